@@ -0,0 +1,1395 @@
+// gRPC API of the AxonOps Schema Registry.
+//
+// The service mirrors the core REST endpoints and is served by the same
+// registry, with the same authentication and RBAC. Credentials are sent as
+// gRPC metadata using the HTTP header names: "authorization" for basic,
+// bearer (JWT, OIDC, share token) and API key credentials, or "x-api-key".
+//
+// Regenerate the Go code with `make proto` after editing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: schemaregistry/v1/schema_registry.proto
+
+package schemaregistryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SchemaType is the format of a schema.
+type SchemaType int32
+
+const (
+	// Unspecified is treated as AVRO, as in the REST API.
+	SchemaType_SCHEMA_TYPE_UNSPECIFIED SchemaType = 0
+	SchemaType_SCHEMA_TYPE_AVRO        SchemaType = 1
+	SchemaType_SCHEMA_TYPE_PROTOBUF    SchemaType = 2
+	SchemaType_SCHEMA_TYPE_JSON        SchemaType = 3
+)
+
+// Enum value maps for SchemaType.
+var (
+	SchemaType_name = map[int32]string{
+		0: "SCHEMA_TYPE_UNSPECIFIED",
+		1: "SCHEMA_TYPE_AVRO",
+		2: "SCHEMA_TYPE_PROTOBUF",
+		3: "SCHEMA_TYPE_JSON",
+	}
+	SchemaType_value = map[string]int32{
+		"SCHEMA_TYPE_UNSPECIFIED": 0,
+		"SCHEMA_TYPE_AVRO":        1,
+		"SCHEMA_TYPE_PROTOBUF":    2,
+		"SCHEMA_TYPE_JSON":        3,
+	}
+)
+
+func (x SchemaType) Enum() *SchemaType {
+	p := new(SchemaType)
+	*p = x
+	return p
+}
+
+func (x SchemaType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SchemaType) Descriptor() protoreflect.EnumDescriptor {
+	return file_schemaregistry_v1_schema_registry_proto_enumTypes[0].Descriptor()
+}
+
+func (SchemaType) Type() protoreflect.EnumType {
+	return &file_schemaregistry_v1_schema_registry_proto_enumTypes[0]
+}
+
+func (x SchemaType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SchemaType.Descriptor instead.
+func (SchemaType) EnumDescriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{0}
+}
+
+// Reference is a reference from a schema to a version of another subject.
+type Reference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Version       int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reference) Reset() {
+	*x = Reference{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reference) ProtoMessage() {}
+
+func (x *Reference) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reference.ProtoReflect.Descriptor instead.
+func (*Reference) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Reference) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Reference) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Reference) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Schema is a registered schema version.
+type Schema struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Subject    string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Version    int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	SchemaType SchemaType             `protobuf:"varint,4,opt,name=schema_type,json=schemaType,proto3,enum=axonops.schemaregistry.v1.SchemaType" json:"schema_type,omitempty"`
+	Schema     string                 `protobuf:"bytes,5,opt,name=schema,proto3" json:"schema,omitempty"`
+	References []*Reference           `protobuf:"bytes,6,rep,name=references,proto3" json:"references,omitempty"`
+	// Set when the version is soft-deleted; only returned when deleted
+	// versions were asked for.
+	Deleted       bool `protobuf:"varint,7,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Schema) Reset() {
+	*x = Schema{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schema) ProtoMessage() {}
+
+func (x *Schema) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schema.ProtoReflect.Descriptor instead.
+func (*Schema) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Schema) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Schema) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Schema) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Schema) GetSchemaType() SchemaType {
+	if x != nil {
+		return x.SchemaType
+	}
+	return SchemaType_SCHEMA_TYPE_UNSPECIFIED
+}
+
+func (x *Schema) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *Schema) GetReferences() []*Reference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *Schema) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type RegisterSchemaRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Context    string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject    string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Schema     string                 `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	SchemaType SchemaType             `protobuf:"varint,4,opt,name=schema_type,json=schemaType,proto3,enum=axonops.schemaregistry.v1.SchemaType" json:"schema_type,omitempty"`
+	References []*Reference           `protobuf:"bytes,5,rep,name=references,proto3" json:"references,omitempty"`
+	// Normalize the schema before registering it, as ?normalize=true does.
+	Normalize     bool `protobuf:"varint,6,opt,name=normalize,proto3" json:"normalize,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterSchemaRequest) Reset() {
+	*x = RegisterSchemaRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterSchemaRequest) ProtoMessage() {}
+
+func (x *RegisterSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterSchemaRequest.ProtoReflect.Descriptor instead.
+func (*RegisterSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisterSchemaRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *RegisterSchemaRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *RegisterSchemaRequest) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *RegisterSchemaRequest) GetSchemaType() SchemaType {
+	if x != nil {
+		return x.SchemaType
+	}
+	return SchemaType_SCHEMA_TYPE_UNSPECIFIED
+}
+
+func (x *RegisterSchemaRequest) GetReferences() []*Reference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *RegisterSchemaRequest) GetNormalize() bool {
+	if x != nil {
+		return x.Normalize
+	}
+	return false
+}
+
+type RegisterSchemaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterSchemaResponse) Reset() {
+	*x = RegisterSchemaResponse{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterSchemaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterSchemaResponse) ProtoMessage() {}
+
+func (x *RegisterSchemaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterSchemaResponse.ProtoReflect.Descriptor instead.
+func (*RegisterSchemaResponse) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RegisterSchemaResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RegisterSchemaResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type LookupSchemaRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Context    string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject    string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Schema     string                 `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	SchemaType SchemaType             `protobuf:"varint,4,opt,name=schema_type,json=schemaType,proto3,enum=axonops.schemaregistry.v1.SchemaType" json:"schema_type,omitempty"`
+	References []*Reference           `protobuf:"bytes,5,rep,name=references,proto3" json:"references,omitempty"`
+	// Also match soft-deleted versions.
+	Deleted       bool `protobuf:"varint,6,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Normalize     bool `protobuf:"varint,7,opt,name=normalize,proto3" json:"normalize,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupSchemaRequest) Reset() {
+	*x = LookupSchemaRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupSchemaRequest) ProtoMessage() {}
+
+func (x *LookupSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupSchemaRequest.ProtoReflect.Descriptor instead.
+func (*LookupSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LookupSchemaRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *LookupSchemaRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *LookupSchemaRequest) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *LookupSchemaRequest) GetSchemaType() SchemaType {
+	if x != nil {
+		return x.SchemaType
+	}
+	return SchemaType_SCHEMA_TYPE_UNSPECIFIED
+}
+
+func (x *LookupSchemaRequest) GetReferences() []*Reference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *LookupSchemaRequest) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *LookupSchemaRequest) GetNormalize() bool {
+	if x != nil {
+		return x.Normalize
+	}
+	return false
+}
+
+type GetSchemaByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSchemaByIdRequest) Reset() {
+	*x = GetSchemaByIdRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSchemaByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSchemaByIdRequest) ProtoMessage() {}
+
+func (x *GetSchemaByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSchemaByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetSchemaByIdRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetSchemaByIdRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *GetSchemaByIdRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetSchemaByVersionRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Context string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// The version to return; 0 or -1 returns the latest version.
+	Version       int32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSchemaByVersionRequest) Reset() {
+	*x = GetSchemaByVersionRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSchemaByVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSchemaByVersionRequest) ProtoMessage() {}
+
+func (x *GetSchemaByVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSchemaByVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetSchemaByVersionRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetSchemaByVersionRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *GetSchemaByVersionRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *GetSchemaByVersionRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ListSubjectsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Context string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	// Only return subjects starting with this prefix.
+	SubjectPrefix string `protobuf:"bytes,2,opt,name=subject_prefix,json=subjectPrefix,proto3" json:"subject_prefix,omitempty"`
+	// Include soft-deleted subjects.
+	Deleted       bool `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubjectsRequest) Reset() {
+	*x = ListSubjectsRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubjectsRequest) ProtoMessage() {}
+
+func (x *ListSubjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubjectsRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSubjectsRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ListSubjectsRequest) GetSubjectPrefix() string {
+	if x != nil {
+		return x.SubjectPrefix
+	}
+	return ""
+}
+
+func (x *ListSubjectsRequest) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type ListSubjectsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subjects      []string               `protobuf:"bytes,1,rep,name=subjects,proto3" json:"subjects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubjectsResponse) Reset() {
+	*x = ListSubjectsResponse{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubjectsResponse) ProtoMessage() {}
+
+func (x *ListSubjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubjectsResponse) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSubjectsResponse) GetSubjects() []string {
+	if x != nil {
+		return x.Subjects
+	}
+	return nil
+}
+
+type ListVersionsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Context string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// Include soft-deleted versions.
+	Deleted       bool `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsRequest) Reset() {
+	*x = ListVersionsRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsRequest) ProtoMessage() {}
+
+func (x *ListVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListVersionsRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ListVersionsRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListVersionsRequest) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type ListVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []int32                `protobuf:"varint,1,rep,packed,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsResponse) Reset() {
+	*x = ListVersionsResponse{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsResponse) ProtoMessage() {}
+
+func (x *ListVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListVersionsResponse) GetVersions() []int32 {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type GetConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigRequest) Reset() {
+	*x = GetConfigRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRequest) ProtoMessage() {}
+
+func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetConfigRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *GetConfigRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+type SetConfigRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Context string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// BACKWARD, BACKWARD_TRANSITIVE, FORWARD, FORWARD_TRANSITIVE, FULL,
+	// FULL_TRANSITIVE or NONE.
+	CompatibilityLevel string `protobuf:"bytes,3,opt,name=compatibility_level,json=compatibilityLevel,proto3" json:"compatibility_level,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SetConfigRequest) Reset() {
+	*x = SetConfigRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetConfigRequest) ProtoMessage() {}
+
+func (x *SetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetConfigRequest.ProtoReflect.Descriptor instead.
+func (*SetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetConfigRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *SetConfigRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SetConfigRequest) GetCompatibilityLevel() string {
+	if x != nil {
+		return x.CompatibilityLevel
+	}
+	return ""
+}
+
+// Config is a compatibility configuration.
+type Config struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CompatibilityLevel string                 `protobuf:"bytes,1,opt,name=compatibility_level,json=compatibilityLevel,proto3" json:"compatibility_level,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Config) GetCompatibilityLevel() string {
+	if x != nil {
+		return x.CompatibilityLevel
+	}
+	return ""
+}
+
+type GetModeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModeRequest) Reset() {
+	*x = GetModeRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModeRequest) ProtoMessage() {}
+
+func (x *GetModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModeRequest.ProtoReflect.Descriptor instead.
+func (*GetModeRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetModeRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *GetModeRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+type SetModeRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Context string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Subject string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// READWRITE, READONLY, READONLY_OVERRIDE or IMPORT.
+	Mode string `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Switch to IMPORT even though the subject or context has schemas.
+	Force         bool `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetModeRequest) Reset() {
+	*x = SetModeRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetModeRequest) ProtoMessage() {}
+
+func (x *SetModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetModeRequest.ProtoReflect.Descriptor instead.
+func (*SetModeRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetModeRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *SetModeRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SetModeRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *SetModeRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+// Mode is a registry mode.
+type Mode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mode) Reset() {
+	*x = Mode{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mode) ProtoMessage() {}
+
+func (x *Mode) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mode.ProtoReflect.Descriptor instead.
+func (*Mode) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Mode) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type ExportSchemasRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Context string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	// Only export subjects starting with this prefix.
+	SubjectPrefix string `protobuf:"bytes,2,opt,name=subject_prefix,json=subjectPrefix,proto3" json:"subject_prefix,omitempty"`
+	// Include soft-deleted versions.
+	Deleted bool `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// Only export the latest version of each subject.
+	LatestOnly    bool `protobuf:"varint,4,opt,name=latest_only,json=latestOnly,proto3" json:"latest_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportSchemasRequest) Reset() {
+	*x = ExportSchemasRequest{}
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportSchemasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSchemasRequest) ProtoMessage() {}
+
+func (x *ExportSchemasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schemaregistry_v1_schema_registry_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSchemasRequest.ProtoReflect.Descriptor instead.
+func (*ExportSchemasRequest) Descriptor() ([]byte, []int) {
+	return file_schemaregistry_v1_schema_registry_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExportSchemasRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ExportSchemasRequest) GetSubjectPrefix() string {
+	if x != nil {
+		return x.SubjectPrefix
+	}
+	return ""
+}
+
+func (x *ExportSchemasRequest) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *ExportSchemasRequest) GetLatestOnly() bool {
+	if x != nil {
+		return x.LatestOnly
+	}
+	return false
+}
+
+var File_schemaregistry_v1_schema_registry_proto protoreflect.FileDescriptor
+
+const file_schemaregistry_v1_schema_registry_proto_rawDesc = "" +
+	"\n" +
+	"'schemaregistry/v1/schema_registry.proto\x12\x19axonops.schemaregistry.v1\"S\n" +
+	"\tReference\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\"\x8c\x02\n" +
+	"\x06Schema\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12F\n" +
+	"\vschema_type\x18\x04 \x01(\x0e2%.axonops.schemaregistry.v1.SchemaTypeR\n" +
+	"schemaType\x12\x16\n" +
+	"\x06schema\x18\x05 \x01(\tR\x06schema\x12D\n" +
+	"\n" +
+	"references\x18\x06 \x03(\v2$.axonops.schemaregistry.v1.ReferenceR\n" +
+	"references\x12\x18\n" +
+	"\adeleted\x18\a \x01(\bR\adeleted\"\x8f\x02\n" +
+	"\x15RegisterSchemaRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x16\n" +
+	"\x06schema\x18\x03 \x01(\tR\x06schema\x12F\n" +
+	"\vschema_type\x18\x04 \x01(\x0e2%.axonops.schemaregistry.v1.SchemaTypeR\n" +
+	"schemaType\x12D\n" +
+	"\n" +
+	"references\x18\x05 \x03(\v2$.axonops.schemaregistry.v1.ReferenceR\n" +
+	"references\x12\x1c\n" +
+	"\tnormalize\x18\x06 \x01(\bR\tnormalize\"B\n" +
+	"\x16RegisterSchemaResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"\xa7\x02\n" +
+	"\x13LookupSchemaRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x16\n" +
+	"\x06schema\x18\x03 \x01(\tR\x06schema\x12F\n" +
+	"\vschema_type\x18\x04 \x01(\x0e2%.axonops.schemaregistry.v1.SchemaTypeR\n" +
+	"schemaType\x12D\n" +
+	"\n" +
+	"references\x18\x05 \x03(\v2$.axonops.schemaregistry.v1.ReferenceR\n" +
+	"references\x12\x18\n" +
+	"\adeleted\x18\x06 \x01(\bR\adeleted\x12\x1c\n" +
+	"\tnormalize\x18\a \x01(\bR\tnormalize\"@\n" +
+	"\x14GetSchemaByIdRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"i\n" +
+	"\x19GetSchemaByVersionRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\"p\n" +
+	"\x13ListSubjectsRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12%\n" +
+	"\x0esubject_prefix\x18\x02 \x01(\tR\rsubjectPrefix\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\bR\adeleted\"2\n" +
+	"\x14ListSubjectsResponse\x12\x1a\n" +
+	"\bsubjects\x18\x01 \x03(\tR\bsubjects\"c\n" +
+	"\x13ListVersionsRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\bR\adeleted\"2\n" +
+	"\x14ListVersionsResponse\x12\x1a\n" +
+	"\bversions\x18\x01 \x03(\x05R\bversions\"F\n" +
+	"\x10GetConfigRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\"w\n" +
+	"\x10SetConfigRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12/\n" +
+	"\x13compatibility_level\x18\x03 \x01(\tR\x12compatibilityLevel\"9\n" +
+	"\x06Config\x12/\n" +
+	"\x13compatibility_level\x18\x01 \x01(\tR\x12compatibilityLevel\"D\n" +
+	"\x0eGetModeRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\"n\n" +
+	"\x0eSetModeRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x14\n" +
+	"\x05force\x18\x04 \x01(\bR\x05force\"\x1a\n" +
+	"\x04Mode\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\"\x92\x01\n" +
+	"\x14ExportSchemasRequest\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12%\n" +
+	"\x0esubject_prefix\x18\x02 \x01(\tR\rsubjectPrefix\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\bR\adeleted\x12\x1f\n" +
+	"\vlatest_only\x18\x04 \x01(\bR\n" +
+	"latestOnly*o\n" +
+	"\n" +
+	"SchemaType\x12\x1b\n" +
+	"\x17SCHEMA_TYPE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10SCHEMA_TYPE_AVRO\x10\x01\x12\x18\n" +
+	"\x14SCHEMA_TYPE_PROTOBUF\x10\x02\x12\x14\n" +
+	"\x10SCHEMA_TYPE_JSON\x10\x032\xf6\b\n" +
+	"\x15SchemaRegistryService\x12u\n" +
+	"\x0eRegisterSchema\x120.axonops.schemaregistry.v1.RegisterSchemaRequest\x1a1.axonops.schemaregistry.v1.RegisterSchemaResponse\x12a\n" +
+	"\fLookupSchema\x12..axonops.schemaregistry.v1.LookupSchemaRequest\x1a!.axonops.schemaregistry.v1.Schema\x12c\n" +
+	"\rGetSchemaById\x12/.axonops.schemaregistry.v1.GetSchemaByIdRequest\x1a!.axonops.schemaregistry.v1.Schema\x12m\n" +
+	"\x12GetSchemaByVersion\x124.axonops.schemaregistry.v1.GetSchemaByVersionRequest\x1a!.axonops.schemaregistry.v1.Schema\x12o\n" +
+	"\fListSubjects\x12..axonops.schemaregistry.v1.ListSubjectsRequest\x1a/.axonops.schemaregistry.v1.ListSubjectsResponse\x12o\n" +
+	"\fListVersions\x12..axonops.schemaregistry.v1.ListVersionsRequest\x1a/.axonops.schemaregistry.v1.ListVersionsResponse\x12[\n" +
+	"\tGetConfig\x12+.axonops.schemaregistry.v1.GetConfigRequest\x1a!.axonops.schemaregistry.v1.Config\x12[\n" +
+	"\tSetConfig\x12+.axonops.schemaregistry.v1.SetConfigRequest\x1a!.axonops.schemaregistry.v1.Config\x12U\n" +
+	"\aGetMode\x12).axonops.schemaregistry.v1.GetModeRequest\x1a\x1f.axonops.schemaregistry.v1.Mode\x12U\n" +
+	"\aSetMode\x12).axonops.schemaregistry.v1.SetModeRequest\x1a\x1f.axonops.schemaregistry.v1.Mode\x12e\n" +
+	"\rExportSchemas\x12/.axonops.schemaregistry.v1.ExportSchemasRequest\x1a!.axonops.schemaregistry.v1.Schema0\x01BXZVgithub.com/axonops/axonops-schema-registry/api/grpc/schemaregistry/v1;schemaregistryv1b\x06proto3"
+
+var (
+	file_schemaregistry_v1_schema_registry_proto_rawDescOnce sync.Once
+	file_schemaregistry_v1_schema_registry_proto_rawDescData []byte
+)
+
+func file_schemaregistry_v1_schema_registry_proto_rawDescGZIP() []byte {
+	file_schemaregistry_v1_schema_registry_proto_rawDescOnce.Do(func() {
+		file_schemaregistry_v1_schema_registry_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_schemaregistry_v1_schema_registry_proto_rawDesc), len(file_schemaregistry_v1_schema_registry_proto_rawDesc)))
+	})
+	return file_schemaregistry_v1_schema_registry_proto_rawDescData
+}
+
+var file_schemaregistry_v1_schema_registry_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_schemaregistry_v1_schema_registry_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_schemaregistry_v1_schema_registry_proto_goTypes = []any{
+	(SchemaType)(0),                   // 0: axonops.schemaregistry.v1.SchemaType
+	(*Reference)(nil),                 // 1: axonops.schemaregistry.v1.Reference
+	(*Schema)(nil),                    // 2: axonops.schemaregistry.v1.Schema
+	(*RegisterSchemaRequest)(nil),     // 3: axonops.schemaregistry.v1.RegisterSchemaRequest
+	(*RegisterSchemaResponse)(nil),    // 4: axonops.schemaregistry.v1.RegisterSchemaResponse
+	(*LookupSchemaRequest)(nil),       // 5: axonops.schemaregistry.v1.LookupSchemaRequest
+	(*GetSchemaByIdRequest)(nil),      // 6: axonops.schemaregistry.v1.GetSchemaByIdRequest
+	(*GetSchemaByVersionRequest)(nil), // 7: axonops.schemaregistry.v1.GetSchemaByVersionRequest
+	(*ListSubjectsRequest)(nil),       // 8: axonops.schemaregistry.v1.ListSubjectsRequest
+	(*ListSubjectsResponse)(nil),      // 9: axonops.schemaregistry.v1.ListSubjectsResponse
+	(*ListVersionsRequest)(nil),       // 10: axonops.schemaregistry.v1.ListVersionsRequest
+	(*ListVersionsResponse)(nil),      // 11: axonops.schemaregistry.v1.ListVersionsResponse
+	(*GetConfigRequest)(nil),          // 12: axonops.schemaregistry.v1.GetConfigRequest
+	(*SetConfigRequest)(nil),          // 13: axonops.schemaregistry.v1.SetConfigRequest
+	(*Config)(nil),                    // 14: axonops.schemaregistry.v1.Config
+	(*GetModeRequest)(nil),            // 15: axonops.schemaregistry.v1.GetModeRequest
+	(*SetModeRequest)(nil),            // 16: axonops.schemaregistry.v1.SetModeRequest
+	(*Mode)(nil),                      // 17: axonops.schemaregistry.v1.Mode
+	(*ExportSchemasRequest)(nil),      // 18: axonops.schemaregistry.v1.ExportSchemasRequest
+}
+var file_schemaregistry_v1_schema_registry_proto_depIdxs = []int32{
+	0,  // 0: axonops.schemaregistry.v1.Schema.schema_type:type_name -> axonops.schemaregistry.v1.SchemaType
+	1,  // 1: axonops.schemaregistry.v1.Schema.references:type_name -> axonops.schemaregistry.v1.Reference
+	0,  // 2: axonops.schemaregistry.v1.RegisterSchemaRequest.schema_type:type_name -> axonops.schemaregistry.v1.SchemaType
+	1,  // 3: axonops.schemaregistry.v1.RegisterSchemaRequest.references:type_name -> axonops.schemaregistry.v1.Reference
+	0,  // 4: axonops.schemaregistry.v1.LookupSchemaRequest.schema_type:type_name -> axonops.schemaregistry.v1.SchemaType
+	1,  // 5: axonops.schemaregistry.v1.LookupSchemaRequest.references:type_name -> axonops.schemaregistry.v1.Reference
+	3,  // 6: axonops.schemaregistry.v1.SchemaRegistryService.RegisterSchema:input_type -> axonops.schemaregistry.v1.RegisterSchemaRequest
+	5,  // 7: axonops.schemaregistry.v1.SchemaRegistryService.LookupSchema:input_type -> axonops.schemaregistry.v1.LookupSchemaRequest
+	6,  // 8: axonops.schemaregistry.v1.SchemaRegistryService.GetSchemaById:input_type -> axonops.schemaregistry.v1.GetSchemaByIdRequest
+	7,  // 9: axonops.schemaregistry.v1.SchemaRegistryService.GetSchemaByVersion:input_type -> axonops.schemaregistry.v1.GetSchemaByVersionRequest
+	8,  // 10: axonops.schemaregistry.v1.SchemaRegistryService.ListSubjects:input_type -> axonops.schemaregistry.v1.ListSubjectsRequest
+	10, // 11: axonops.schemaregistry.v1.SchemaRegistryService.ListVersions:input_type -> axonops.schemaregistry.v1.ListVersionsRequest
+	12, // 12: axonops.schemaregistry.v1.SchemaRegistryService.GetConfig:input_type -> axonops.schemaregistry.v1.GetConfigRequest
+	13, // 13: axonops.schemaregistry.v1.SchemaRegistryService.SetConfig:input_type -> axonops.schemaregistry.v1.SetConfigRequest
+	15, // 14: axonops.schemaregistry.v1.SchemaRegistryService.GetMode:input_type -> axonops.schemaregistry.v1.GetModeRequest
+	16, // 15: axonops.schemaregistry.v1.SchemaRegistryService.SetMode:input_type -> axonops.schemaregistry.v1.SetModeRequest
+	18, // 16: axonops.schemaregistry.v1.SchemaRegistryService.ExportSchemas:input_type -> axonops.schemaregistry.v1.ExportSchemasRequest
+	4,  // 17: axonops.schemaregistry.v1.SchemaRegistryService.RegisterSchema:output_type -> axonops.schemaregistry.v1.RegisterSchemaResponse
+	2,  // 18: axonops.schemaregistry.v1.SchemaRegistryService.LookupSchema:output_type -> axonops.schemaregistry.v1.Schema
+	2,  // 19: axonops.schemaregistry.v1.SchemaRegistryService.GetSchemaById:output_type -> axonops.schemaregistry.v1.Schema
+	2,  // 20: axonops.schemaregistry.v1.SchemaRegistryService.GetSchemaByVersion:output_type -> axonops.schemaregistry.v1.Schema
+	9,  // 21: axonops.schemaregistry.v1.SchemaRegistryService.ListSubjects:output_type -> axonops.schemaregistry.v1.ListSubjectsResponse
+	11, // 22: axonops.schemaregistry.v1.SchemaRegistryService.ListVersions:output_type -> axonops.schemaregistry.v1.ListVersionsResponse
+	14, // 23: axonops.schemaregistry.v1.SchemaRegistryService.GetConfig:output_type -> axonops.schemaregistry.v1.Config
+	14, // 24: axonops.schemaregistry.v1.SchemaRegistryService.SetConfig:output_type -> axonops.schemaregistry.v1.Config
+	17, // 25: axonops.schemaregistry.v1.SchemaRegistryService.GetMode:output_type -> axonops.schemaregistry.v1.Mode
+	17, // 26: axonops.schemaregistry.v1.SchemaRegistryService.SetMode:output_type -> axonops.schemaregistry.v1.Mode
+	2,  // 27: axonops.schemaregistry.v1.SchemaRegistryService.ExportSchemas:output_type -> axonops.schemaregistry.v1.Schema
+	17, // [17:28] is the sub-list for method output_type
+	6,  // [6:17] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_schemaregistry_v1_schema_registry_proto_init() }
+func file_schemaregistry_v1_schema_registry_proto_init() {
+	if File_schemaregistry_v1_schema_registry_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_schemaregistry_v1_schema_registry_proto_rawDesc), len(file_schemaregistry_v1_schema_registry_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_schemaregistry_v1_schema_registry_proto_goTypes,
+		DependencyIndexes: file_schemaregistry_v1_schema_registry_proto_depIdxs,
+		EnumInfos:         file_schemaregistry_v1_schema_registry_proto_enumTypes,
+		MessageInfos:      file_schemaregistry_v1_schema_registry_proto_msgTypes,
+	}.Build()
+	File_schemaregistry_v1_schema_registry_proto = out.File
+	file_schemaregistry_v1_schema_registry_proto_goTypes = nil
+	file_schemaregistry_v1_schema_registry_proto_depIdxs = nil
+}
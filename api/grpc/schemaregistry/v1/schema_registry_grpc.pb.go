@@ -0,0 +1,564 @@
+// gRPC API of the AxonOps Schema Registry.
+//
+// The service mirrors the core REST endpoints and is served by the same
+// registry, with the same authentication and RBAC. Credentials are sent as
+// gRPC metadata using the HTTP header names: "authorization" for basic,
+// bearer (JWT, OIDC, share token) and API key credentials, or "x-api-key".
+//
+// Regenerate the Go code with `make proto` after editing this file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: schemaregistry/v1/schema_registry.proto
+
+package schemaregistryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SchemaRegistryService_RegisterSchema_FullMethodName     = "/axonops.schemaregistry.v1.SchemaRegistryService/RegisterSchema"
+	SchemaRegistryService_LookupSchema_FullMethodName       = "/axonops.schemaregistry.v1.SchemaRegistryService/LookupSchema"
+	SchemaRegistryService_GetSchemaById_FullMethodName      = "/axonops.schemaregistry.v1.SchemaRegistryService/GetSchemaById"
+	SchemaRegistryService_GetSchemaByVersion_FullMethodName = "/axonops.schemaregistry.v1.SchemaRegistryService/GetSchemaByVersion"
+	SchemaRegistryService_ListSubjects_FullMethodName       = "/axonops.schemaregistry.v1.SchemaRegistryService/ListSubjects"
+	SchemaRegistryService_ListVersions_FullMethodName       = "/axonops.schemaregistry.v1.SchemaRegistryService/ListVersions"
+	SchemaRegistryService_GetConfig_FullMethodName          = "/axonops.schemaregistry.v1.SchemaRegistryService/GetConfig"
+	SchemaRegistryService_SetConfig_FullMethodName          = "/axonops.schemaregistry.v1.SchemaRegistryService/SetConfig"
+	SchemaRegistryService_GetMode_FullMethodName            = "/axonops.schemaregistry.v1.SchemaRegistryService/GetMode"
+	SchemaRegistryService_SetMode_FullMethodName            = "/axonops.schemaregistry.v1.SchemaRegistryService/SetMode"
+	SchemaRegistryService_ExportSchemas_FullMethodName      = "/axonops.schemaregistry.v1.SchemaRegistryService/ExportSchemas"
+)
+
+// SchemaRegistryServiceClient is the client API for SchemaRegistryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SchemaRegistryService registers and reads schemas, compatibility levels
+// and modes. Every request names its registry context; an empty context is
+// the default context ".".
+type SchemaRegistryServiceClient interface {
+	// RegisterSchema registers a schema under a subject, as
+	// POST /subjects/{subject}/versions does. Registering a schema that is
+	// already the subject's live version returns its existing ID.
+	RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*RegisterSchemaResponse, error)
+	// LookupSchema returns the version of a subject that has the given
+	// schema, as POST /subjects/{subject} does.
+	LookupSchema(ctx context.Context, in *LookupSchemaRequest, opts ...grpc.CallOption) (*Schema, error)
+	// GetSchemaById returns a schema by its ID, as GET /schemas/ids/{id} does.
+	GetSchemaById(ctx context.Context, in *GetSchemaByIdRequest, opts ...grpc.CallOption) (*Schema, error)
+	// GetSchemaByVersion returns a version of a subject, as
+	// GET /subjects/{subject}/versions/{version} does.
+	GetSchemaByVersion(ctx context.Context, in *GetSchemaByVersionRequest, opts ...grpc.CallOption) (*Schema, error)
+	// ListSubjects returns the subjects of a context, as GET /subjects does.
+	ListSubjects(ctx context.Context, in *ListSubjectsRequest, opts ...grpc.CallOption) (*ListSubjectsResponse, error)
+	// ListVersions returns the versions of a subject, as
+	// GET /subjects/{subject}/versions does.
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	// GetConfig returns the compatibility level in effect for a subject, or
+	// for the context when no subject is given.
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*Config, error)
+	// SetConfig sets the compatibility level of a subject, or of the context
+	// when no subject is given.
+	SetConfig(ctx context.Context, in *SetConfigRequest, opts ...grpc.CallOption) (*Config, error)
+	// GetMode returns the mode in effect for a subject, or for the context
+	// when no subject is given.
+	GetMode(ctx context.Context, in *GetModeRequest, opts ...grpc.CallOption) (*Mode, error)
+	// SetMode sets the mode of a subject, or of the context when no subject
+	// is given.
+	SetMode(ctx context.Context, in *SetModeRequest, opts ...grpc.CallOption) (*Mode, error)
+	// ExportSchemas streams every schema version of a context, ordered by
+	// subject and version, without loading them all into memory.
+	ExportSchemas(ctx context.Context, in *ExportSchemasRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Schema], error)
+}
+
+type schemaRegistryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchemaRegistryServiceClient(cc grpc.ClientConnInterface) SchemaRegistryServiceClient {
+	return &schemaRegistryServiceClient{cc}
+}
+
+func (c *schemaRegistryServiceClient) RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*RegisterSchemaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterSchemaResponse)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_RegisterSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) LookupSchema(ctx context.Context, in *LookupSchemaRequest, opts ...grpc.CallOption) (*Schema, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Schema)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_LookupSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) GetSchemaById(ctx context.Context, in *GetSchemaByIdRequest, opts ...grpc.CallOption) (*Schema, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Schema)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_GetSchemaById_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) GetSchemaByVersion(ctx context.Context, in *GetSchemaByVersionRequest, opts ...grpc.CallOption) (*Schema, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Schema)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_GetSchemaByVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) ListSubjects(ctx context.Context, in *ListSubjectsRequest, opts ...grpc.CallOption) (*ListSubjectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubjectsResponse)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_ListSubjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_ListVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*Config, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Config)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_GetConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) SetConfig(ctx context.Context, in *SetConfigRequest, opts ...grpc.CallOption) (*Config, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Config)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_SetConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) GetMode(ctx context.Context, in *GetModeRequest, opts ...grpc.CallOption) (*Mode, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Mode)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_GetMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) SetMode(ctx context.Context, in *SetModeRequest, opts ...grpc.CallOption) (*Mode, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Mode)
+	err := c.cc.Invoke(ctx, SchemaRegistryService_SetMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaRegistryServiceClient) ExportSchemas(ctx context.Context, in *ExportSchemasRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Schema], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SchemaRegistryService_ServiceDesc.Streams[0], SchemaRegistryService_ExportSchemas_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportSchemasRequest, Schema]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SchemaRegistryService_ExportSchemasClient = grpc.ServerStreamingClient[Schema]
+
+// SchemaRegistryServiceServer is the server API for SchemaRegistryService service.
+// All implementations must embed UnimplementedSchemaRegistryServiceServer
+// for forward compatibility.
+//
+// SchemaRegistryService registers and reads schemas, compatibility levels
+// and modes. Every request names its registry context; an empty context is
+// the default context ".".
+type SchemaRegistryServiceServer interface {
+	// RegisterSchema registers a schema under a subject, as
+	// POST /subjects/{subject}/versions does. Registering a schema that is
+	// already the subject's live version returns its existing ID.
+	RegisterSchema(context.Context, *RegisterSchemaRequest) (*RegisterSchemaResponse, error)
+	// LookupSchema returns the version of a subject that has the given
+	// schema, as POST /subjects/{subject} does.
+	LookupSchema(context.Context, *LookupSchemaRequest) (*Schema, error)
+	// GetSchemaById returns a schema by its ID, as GET /schemas/ids/{id} does.
+	GetSchemaById(context.Context, *GetSchemaByIdRequest) (*Schema, error)
+	// GetSchemaByVersion returns a version of a subject, as
+	// GET /subjects/{subject}/versions/{version} does.
+	GetSchemaByVersion(context.Context, *GetSchemaByVersionRequest) (*Schema, error)
+	// ListSubjects returns the subjects of a context, as GET /subjects does.
+	ListSubjects(context.Context, *ListSubjectsRequest) (*ListSubjectsResponse, error)
+	// ListVersions returns the versions of a subject, as
+	// GET /subjects/{subject}/versions does.
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	// GetConfig returns the compatibility level in effect for a subject, or
+	// for the context when no subject is given.
+	GetConfig(context.Context, *GetConfigRequest) (*Config, error)
+	// SetConfig sets the compatibility level of a subject, or of the context
+	// when no subject is given.
+	SetConfig(context.Context, *SetConfigRequest) (*Config, error)
+	// GetMode returns the mode in effect for a subject, or for the context
+	// when no subject is given.
+	GetMode(context.Context, *GetModeRequest) (*Mode, error)
+	// SetMode sets the mode of a subject, or of the context when no subject
+	// is given.
+	SetMode(context.Context, *SetModeRequest) (*Mode, error)
+	// ExportSchemas streams every schema version of a context, ordered by
+	// subject and version, without loading them all into memory.
+	ExportSchemas(*ExportSchemasRequest, grpc.ServerStreamingServer[Schema]) error
+	mustEmbedUnimplementedSchemaRegistryServiceServer()
+}
+
+// UnimplementedSchemaRegistryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSchemaRegistryServiceServer struct{}
+
+func (UnimplementedSchemaRegistryServiceServer) RegisterSchema(context.Context, *RegisterSchemaRequest) (*RegisterSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSchema not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) LookupSchema(context.Context, *LookupSchemaRequest) (*Schema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupSchema not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) GetSchemaById(context.Context, *GetSchemaByIdRequest) (*Schema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchemaById not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) GetSchemaByVersion(context.Context, *GetSchemaByVersionRequest) (*Schema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchemaByVersion not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) ListSubjects(context.Context, *ListSubjectsRequest) (*ListSubjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubjects not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) GetConfig(context.Context, *GetConfigRequest) (*Config, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) SetConfig(context.Context, *SetConfigRequest) (*Config, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetConfig not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) GetMode(context.Context, *GetModeRequest) (*Mode, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMode not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) SetMode(context.Context, *SetModeRequest) (*Mode, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMode not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) ExportSchemas(*ExportSchemasRequest, grpc.ServerStreamingServer[Schema]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportSchemas not implemented")
+}
+func (UnimplementedSchemaRegistryServiceServer) mustEmbedUnimplementedSchemaRegistryServiceServer() {}
+func (UnimplementedSchemaRegistryServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeSchemaRegistryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SchemaRegistryServiceServer will
+// result in compilation errors.
+type UnsafeSchemaRegistryServiceServer interface {
+	mustEmbedUnimplementedSchemaRegistryServiceServer()
+}
+
+func RegisterSchemaRegistryServiceServer(s grpc.ServiceRegistrar, srv SchemaRegistryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSchemaRegistryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SchemaRegistryService_ServiceDesc, srv)
+}
+
+func _SchemaRegistryService_RegisterSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).RegisterSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_RegisterSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).RegisterSchema(ctx, req.(*RegisterSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_LookupSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).LookupSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_LookupSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).LookupSchema(ctx, req.(*LookupSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_GetSchemaById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).GetSchemaById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_GetSchemaById_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).GetSchemaById(ctx, req.(*GetSchemaByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_GetSchemaByVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaByVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).GetSchemaByVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_GetSchemaByVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).GetSchemaByVersion(ctx, req.(*GetSchemaByVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_ListSubjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).ListSubjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_ListSubjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).ListSubjects(ctx, req.(*ListSubjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_ListVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_GetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_SetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).SetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_SetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).SetConfig(ctx, req.(*SetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_GetMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).GetMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_GetMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).GetMode(ctx, req.(*GetModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_SetMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchemaRegistryServiceServer).SetMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchemaRegistryService_SetMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchemaRegistryServiceServer).SetMode(ctx, req.(*SetModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchemaRegistryService_ExportSchemas_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportSchemasRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SchemaRegistryServiceServer).ExportSchemas(m, &grpc.GenericServerStream[ExportSchemasRequest, Schema]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SchemaRegistryService_ExportSchemasServer = grpc.ServerStreamingServer[Schema]
+
+// SchemaRegistryService_ServiceDesc is the grpc.ServiceDesc for SchemaRegistryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SchemaRegistryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "axonops.schemaregistry.v1.SchemaRegistryService",
+	HandlerType: (*SchemaRegistryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterSchema",
+			Handler:    _SchemaRegistryService_RegisterSchema_Handler,
+		},
+		{
+			MethodName: "LookupSchema",
+			Handler:    _SchemaRegistryService_LookupSchema_Handler,
+		},
+		{
+			MethodName: "GetSchemaById",
+			Handler:    _SchemaRegistryService_GetSchemaById_Handler,
+		},
+		{
+			MethodName: "GetSchemaByVersion",
+			Handler:    _SchemaRegistryService_GetSchemaByVersion_Handler,
+		},
+		{
+			MethodName: "ListSubjects",
+			Handler:    _SchemaRegistryService_ListSubjects_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _SchemaRegistryService_ListVersions_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _SchemaRegistryService_GetConfig_Handler,
+		},
+		{
+			MethodName: "SetConfig",
+			Handler:    _SchemaRegistryService_SetConfig_Handler,
+		},
+		{
+			MethodName: "GetMode",
+			Handler:    _SchemaRegistryService_GetMode_Handler,
+		},
+		{
+			MethodName: "SetMode",
+			Handler:    _SchemaRegistryService_SetMode_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportSchemas",
+			Handler:       _SchemaRegistryService_ExportSchemas_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "schemaregistry/v1/schema_registry.proto",
+}
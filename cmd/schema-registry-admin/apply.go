@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newApplyCmd() *cobra.Command {
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Reconcile the registry with a desired-state file",
+		Long: `Reconcile the registry given by --server with the desired state in a YAML
+file: contexts, their subjects with the schemas they must contain, and
+compatibility levels and modes. For example:
+
+  contexts:
+    - name: .team-a
+      compatibility: BACKWARD
+      subjects:
+        - name: orders-value
+          compatibility: FULL
+          schemas:
+            - file: schemas/order-v1.avsc
+            - file: schemas/order-v2.avsc
+        - name: payments-value
+          schemas:
+            - type: PROTOBUF
+              file: schemas/payment.proto
+              references:
+                - name: common.proto
+                  subject: common
+                  version: 1
+
+A context without a name is the default context. Schema files are read
+relative to the directory of the YAML file; a schema may also be given
+inline with "schema". A subject's schemas are listed oldest first and those
+it does not contain yet are registered in order.
+
+Only the changes needed are made, and contexts, subjects and settings the
+file does not mention are left alone. The plan is printed with the outcome
+of each change; with --dry-run nothing is changed. Applying requires config
+write permissions. The command exits non-zero when a change fails.`,
+		Example: `  schema-registry-admin apply -f registry.yaml --dry-run
+  schema-registry-admin apply -f registry.yaml
+  schema-registry-admin -o json apply -f registry.yaml > plan.json`,
+		RunE: applyDesiredState,
+	}
+	applyCmd.Flags().StringP("file", "f", "", "Desired-state YAML file (required)")
+	applyCmd.Flags().Bool("dry-run", false, "Print the plan without changing anything")
+	_ = applyCmd.MarkFlagRequired("file")
+	return applyCmd
+}
+
+// desiredState is the desired-state file. It is sent to POST /apply with
+// the schema files read into Schema.
+type desiredState struct {
+	Contexts []desiredContext `yaml:"contexts" json:"contexts"`
+}
+
+type desiredContext struct {
+	Name          string           `yaml:"name" json:"name,omitempty"`
+	Description   string           `yaml:"description" json:"description,omitempty"`
+	Compatibility string           `yaml:"compatibility" json:"compatibility,omitempty"`
+	Mode          string           `yaml:"mode" json:"mode,omitempty"`
+	Subjects      []desiredSubject `yaml:"subjects" json:"subjects,omitempty"`
+}
+
+type desiredSubject struct {
+	Name          string          `yaml:"name" json:"name"`
+	Compatibility string          `yaml:"compatibility" json:"compatibility,omitempty"`
+	Mode          string          `yaml:"mode" json:"mode,omitempty"`
+	Schemas       []desiredSchema `yaml:"schemas" json:"schemas,omitempty"`
+}
+
+type desiredSchema struct {
+	File       string             `yaml:"file" json:"-"`
+	Schema     string             `yaml:"schema" json:"schema"`
+	SchemaType string             `yaml:"type" json:"schemaType,omitempty"`
+	References []desiredReference `yaml:"references" json:"references,omitempty"`
+}
+
+type desiredReference struct {
+	Name    string `yaml:"name" json:"name"`
+	Subject string `yaml:"subject" json:"subject"`
+	Version int    `yaml:"version" json:"version"`
+}
+
+// applyResponse is the response of POST /apply.
+type applyResponse struct {
+	DryRun  bool          `json:"dry_run"`
+	Failed  bool          `json:"failed"`
+	Changes []applyChange `json:"changes"`
+}
+
+type applyChange struct {
+	Action  string `json:"action"`
+	Context string `json:"context"`
+	Subject string `json:"subject"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Version int    `json:"version"`
+	ID      int64  `json:"id"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error"`
+}
+
+func applyDesiredState(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	state, err := loadDesiredState(file)
+	if err != nil {
+		return err
+	}
+
+	path := "/apply"
+	if dryRun {
+		path += "?dry_run=true"
+	}
+	var resp applyResponse
+	if err := doRequestInto("POST", path, state, &resp); err != nil {
+		return fmt.Errorf("failed to apply desired state: %w", err)
+	}
+
+	if output == "json" {
+		if err := printJSON(resp); err != nil {
+			return err
+		}
+	} else if err := printApplyChanges(resp); err != nil {
+		return err
+	}
+
+	if resp.Failed {
+		cmd.SilenceUsage = true
+		if dryRun {
+			return fmt.Errorf("the plan contains changes that would fail")
+		}
+		return fmt.Errorf("apply stopped at a change that failed")
+	}
+	return nil
+}
+
+// loadDesiredState reads a desired-state file and the schema files it
+// names, which are relative to its directory.
+func loadDesiredState(file string) (*desiredState, error) {
+	data, err := os.ReadFile(file) // #nosec G304 -- admin CLI tool; path is from user-provided --file flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read desired state: %w", err)
+	}
+	var state desiredState
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse desired state %s: %w", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	for i := range state.Contexts {
+		for j := range state.Contexts[i].Subjects {
+			subject := &state.Contexts[i].Subjects[j]
+			for k := range subject.Schemas {
+				schema := &subject.Schemas[k]
+				if schema.File == "" {
+					continue
+				}
+				if schema.Schema != "" {
+					return nil, fmt.Errorf("schema %d of subject %s sets both file and schema", k+1, subject.Name)
+				}
+				path := schema.File
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(dir, path)
+				}
+				content, err := os.ReadFile(path) // #nosec G304 -- admin CLI tool; path is from the user-provided desired-state file
+				if err != nil {
+					return nil, fmt.Errorf("failed to read schema of subject %s: %w", subject.Name, err)
+				}
+				schema.Schema = string(content)
+			}
+		}
+	}
+	return &state, nil
+}
+
+func printApplyChanges(resp applyResponse) error {
+	if len(resp.Changes) == 0 {
+		fmt.Println("No changes. The registry matches the desired state.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tCONTEXT\tSUBJECT\tCHANGE\tSTATUS")
+	for _, c := range resp.Changes {
+		var change string
+		switch c.Action {
+		case "register_schema":
+			change = "-"
+			if c.Version > 0 {
+				change = "version " + strconv.Itoa(c.Version) + ", id " + strconv.FormatInt(c.ID, 10)
+			}
+		case "create_context":
+			change = "-"
+		default:
+			change = orDash(c.From) + " -> " + c.To
+		}
+		status := "planned"
+		switch {
+		case c.Error != "":
+			status = "failed: " + c.Error
+		case c.Applied:
+			status = "applied"
+		case !resp.DryRun:
+			status = "skipped"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Action, c.Context, orDash(c.Subject), change, status)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,547 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// Finding levels, from harmless to migration-blocking.
+const (
+	levelOK      = "OK"
+	levelWarn    = "WARN"
+	levelBlocker = "BLOCKER"
+)
+
+// supportedSchemaTypes are the schema types the import API accepts.
+var supportedSchemaTypes = map[string]bool{"AVRO": true, "JSON": true, "PROTOBUF": true}
+
+func newAssessCmd() *cobra.Command {
+	assessCmd := &cobra.Command{
+		Use:   "assess",
+		Short: "Inventory a source registry and report what will migrate cleanly",
+		Long: `Read-only inventory of a Confluent-compatible source registry ahead of a
+migration. The report counts subjects, versions, schema types, references and
+schema IDs, and lists every finding that needs attention before cutover:
+
+  OK       migrates as-is
+  WARN     migrates, but something must be re-applied or recreated by hand
+  BLOCKER  will fail to import until fixed
+
+With --check-target the registry given by --server is also checked for
+supported schema types, IMPORT mode and schema ID collisions.
+
+The command exits non-zero when the report contains blockers.`,
+		Example: `  schema-registry-admin assess --source http://confluent-sr:8081
+  schema-registry-admin assess --source https://psrc-xxxx.confluent.cloud --source-username KEY --source-password SECRET
+  schema-registry-admin -s http://axonops-sr:8082 -k sr_live_abc123 assess --source http://confluent-sr:8081 --check-target
+  schema-registry-admin -o json assess --source http://confluent-sr:8081 > assessment.json`,
+		RunE: assessSource,
+	}
+	assessCmd.Flags().String("source", "", "Source registry URL (required)")
+	assessCmd.Flags().String("source-username", "", "Basic auth username for the source registry")
+	assessCmd.Flags().String("source-password", "", "Basic auth password for the source registry")
+	assessCmd.Flags().String("source-api-key", "", "API key for the source registry (sent as X-API-Key)")
+	assessCmd.Flags().Bool("check-target", false, "Also check the registry given by --server as the migration target")
+	_ = assessCmd.MarkFlagRequired("source")
+	return assessCmd
+}
+
+// sourceSchema is a schema version as returned by GET /schemas.
+type sourceSchema struct {
+	Subject    string                 `json:"subject"`
+	Version    int                    `json:"version"`
+	ID         int64                  `json:"id"`
+	SchemaType string                 `json:"schemaType"`
+	Schema     string                 `json:"schema"`
+	References []sourceReference      `json:"references"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	RuleSet    *sourceRuleSet         `json:"ruleSet"`
+}
+
+type sourceReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+type sourceRuleSet struct {
+	MigrationRules []sourceRule `json:"migrationRules"`
+	DomainRules    []sourceRule `json:"domainRules"`
+	EncodingRules  []sourceRule `json:"encodingRules"`
+}
+
+type sourceRule struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Type string `json:"type"`
+}
+
+// assessment is the pre-migration report.
+type assessment struct {
+	Source              string            `json:"source"`
+	Subjects            int               `json:"subjects"`
+	DeletedSubjects     int               `json:"deleted_subjects"`
+	Versions            int               `json:"versions"`
+	DeletedVersions     int               `json:"deleted_versions"`
+	SchemaIDs           int               `json:"schema_ids"`
+	MinID               int64             `json:"min_id"`
+	MaxID               int64             `json:"max_id"`
+	MaxVersion          int               `json:"max_version"`
+	SchemaTypes         map[string]int    `json:"schema_types"`
+	Contexts            map[string]int    `json:"contexts"`
+	References          int               `json:"references"`
+	VersionsWithMeta    int               `json:"versions_with_metadata"`
+	VersionsWithRuleSet int               `json:"versions_with_rule_set"`
+	RuleTypes           map[string]int    `json:"rule_types"`
+	GlobalCompatibility string            `json:"global_compatibility,omitempty"`
+	GlobalMode          string            `json:"global_mode,omitempty"`
+	SubjectConfigs      map[string]string `json:"subject_configs"`
+	SubjectModes        map[string]string `json:"subject_modes"`
+	Exporters           int               `json:"exporters"`
+	KEKs                int               `json:"keks"`
+	Findings            []finding         `json:"findings"`
+}
+
+type finding struct {
+	Level   string `json:"level"`
+	Area    string `json:"area"`
+	Message string `json:"message"`
+}
+
+func (a *assessment) add(level, area, format string, args ...interface{}) {
+	a.Findings = append(a.Findings, finding{Level: level, Area: area, Message: fmt.Sprintf(format, args...)})
+}
+
+func (a *assessment) blockers() int {
+	n := 0
+	for _, f := range a.Findings {
+		if f.Level == levelBlocker {
+			n++
+		}
+	}
+	return n
+}
+
+func assessSource(cmd *cobra.Command, args []string) error {
+	src := registryClient{}
+	src.baseURL, _ = cmd.Flags().GetString("source")
+	src.username, _ = cmd.Flags().GetString("source-username")
+	src.password, _ = cmd.Flags().GetString("source-password")
+	src.apiKey, _ = cmd.Flags().GetString("source-api-key")
+	checkTarget, _ := cmd.Flags().GetBool("check-target")
+
+	a := &assessment{
+		Source:         src.baseURL,
+		SchemaTypes:    map[string]int{},
+		Contexts:       map[string]int{},
+		RuleTypes:      map[string]int{},
+		SubjectConfigs: map[string]string{},
+		SubjectModes:   map[string]string{},
+	}
+
+	var subjects, allSubjects []string
+	if err := src.do("GET", "/subjects", nil, &subjects); err != nil {
+		return fmt.Errorf("failed to list source subjects: %w", err)
+	}
+	if err := src.do("GET", "/subjects?deleted=true", nil, &allSubjects); err != nil {
+		return fmt.Errorf("failed to list soft-deleted source subjects: %w", err)
+	}
+	a.Subjects = len(subjects)
+	a.DeletedSubjects = len(allSubjects) - len(subjects)
+
+	active, all, err := fetchSourceSchemas(src, allSubjects)
+	if err != nil {
+		return err
+	}
+	inventorySchemas(a, active, all)
+	inventorySettings(a, src, subjects)
+	inventoryEncryption(a, src)
+
+	if checkTarget {
+		target := registryClient{baseURL: serverURL, username: username, password: password, apiKey: apiKey}
+		assessTarget(a, target, all)
+	}
+
+	if output == "json" {
+		if err := printJSON(a); err != nil {
+			return err
+		}
+	} else if err := printAssessment(a); err != nil {
+		return err
+	}
+
+	if n := a.blockers(); n > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("assessment found %d blocker(s)", n)
+	}
+	return nil
+}
+
+// fetchSourceSchemas returns the active schema versions and all versions
+// including soft-deleted ones. GET /schemas returns everything in one call;
+// registries that do not support it are walked subject by subject.
+func fetchSourceSchemas(src registryClient, subjects []string) (active, all []sourceSchema, err error) {
+	if src.do("GET", "/schemas", nil, &active) == nil && src.do("GET", "/schemas?deleted=true", nil, &all) == nil {
+		return active, all, nil
+	}
+	active, all = nil, nil
+
+	for _, subject := range subjects {
+		base := "/subjects/" + url.PathEscape(subject) + "/versions"
+		var live, versions []int
+		if err := src.do("GET", base, nil, &live); err != nil && !isNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to list versions of %s: %w", subject, err)
+		}
+		if err := src.do("GET", base+"?deleted=true", nil, &versions); err != nil {
+			return nil, nil, fmt.Errorf("failed to list versions of %s: %w", subject, err)
+		}
+		isLive := make(map[int]bool, len(live))
+		for _, v := range live {
+			isLive[v] = true
+		}
+		for _, v := range versions {
+			var s sourceSchema
+			if err := src.do("GET", base+"/"+strconv.Itoa(v)+"?deleted=true", nil, &s); err != nil {
+				return nil, nil, fmt.Errorf("failed to get %s version %d: %w", subject, v, err)
+			}
+			all = append(all, s)
+			if isLive[v] {
+				active = append(active, s)
+			}
+		}
+	}
+	return active, all, nil
+}
+
+// inventorySchemas counts schema versions, types, IDs, references and the
+// metadata and rule sets attached to them.
+func inventorySchemas(a *assessment, active, all []sourceSchema) {
+	type key struct {
+		subject string
+		version int
+	}
+	isActive := make(map[key]bool, len(active))
+	for _, s := range active {
+		isActive[key{s.Subject, s.Version}] = true
+	}
+	exists := make(map[key]bool, len(all))
+	latest := map[string]int{}
+	for _, s := range all {
+		exists[key{s.Subject, s.Version}] = true
+		if s.Version > latest[s.Subject] {
+			latest[s.Subject] = s.Version
+		}
+	}
+
+	ids := map[int64]string{}
+	var conflictingIDs []int64
+	var unsupported, dangling, deletedRefs []string
+	for _, s := range all {
+		if !isActive[key{s.Subject, s.Version}] {
+			a.DeletedVersions++
+		}
+		schemaType := s.SchemaType
+		if schemaType == "" {
+			schemaType = "AVRO"
+		}
+		a.SchemaTypes[schemaType]++
+		if !supportedSchemaTypes[schemaType] {
+			unsupported = append(unsupported, fmt.Sprintf("%s v%d (%s)", s.Subject, s.Version, schemaType))
+		}
+		a.Contexts[subjectContext(s.Subject)]++
+
+		if prev, ok := ids[s.ID]; ok && prev != s.Schema {
+			conflictingIDs = append(conflictingIDs, s.ID)
+		}
+		ids[s.ID] = s.Schema
+		if a.MinID == 0 || s.ID < a.MinID {
+			a.MinID = s.ID
+		}
+		if s.ID > a.MaxID {
+			a.MaxID = s.ID
+		}
+		if s.Version > a.MaxVersion {
+			a.MaxVersion = s.Version
+		}
+
+		for _, ref := range s.References {
+			a.References++
+			refSubject := qualifySubject(ref.Subject, subjectContext(s.Subject))
+			refVersion := ref.Version
+			if refVersion <= 0 {
+				refVersion = latest[refSubject]
+			}
+			from := fmt.Sprintf("%s v%d -> %s v%d", s.Subject, s.Version, ref.Subject, ref.Version)
+			switch {
+			case !exists[key{refSubject, refVersion}]:
+				dangling = append(dangling, from)
+			case isActive[key{s.Subject, s.Version}] && !isActive[key{refSubject, refVersion}]:
+				deletedRefs = append(deletedRefs, from)
+			}
+		}
+
+		if len(s.Metadata) > 0 {
+			a.VersionsWithMeta++
+		}
+		if s.RuleSet != nil {
+			rules := append(append(append([]sourceRule{}, s.RuleSet.DomainRules...), s.RuleSet.MigrationRules...), s.RuleSet.EncodingRules...)
+			if len(rules) > 0 {
+				a.VersionsWithRuleSet++
+			}
+			for _, r := range rules {
+				a.RuleTypes[r.Type]++
+			}
+		}
+	}
+	a.Versions = len(all) - a.DeletedVersions
+	a.SchemaIDs = len(ids)
+
+	if len(all) == 0 {
+		a.add(levelWarn, "inventory", "source registry has no schemas; there is nothing to migrate")
+		return
+	}
+	a.add(levelOK, "ids", "%d schema IDs in range %d-%d are preserved by the import API; new registrations on the target start at %d", a.SchemaIDs, a.MinID, a.MaxID, a.MaxID+1)
+	if len(unsupported) > 0 {
+		a.add(levelBlocker, "schema-types", "%d version(s) use a schema type the target does not support: %s", len(unsupported), sample(unsupported))
+	}
+	if len(conflictingIDs) > 0 {
+		a.add(levelBlocker, "ids", "%d schema ID(s) map to different schema text on different subjects and will be rejected on import: %v", len(conflictingIDs), conflictingIDs)
+	}
+	if len(dangling) > 0 {
+		a.add(levelBlocker, "references", "%d reference(s) point at a subject version that does not exist on the source: %s", len(dangling), sample(dangling))
+	}
+	if len(deletedRefs) > 0 {
+		a.add(levelBlocker, "references", "%d active version(s) reference a soft-deleted version, which the migration script does not export; import the referenced version explicitly: %s", len(deletedRefs), sample(deletedRefs))
+	}
+	if a.References > 0 && len(dangling) == 0 && len(deletedRefs) == 0 {
+		a.add(levelOK, "references", "all %d reference(s) resolve; the migration script imports in ID order so referenced schemas land first", a.References)
+	}
+	if a.DeletedVersions > 0 {
+		a.add(levelWarn, "soft-deletes", "%d soft-deleted version(s) in %d soft-deleted subject(s) are not exported by the migration script; their IDs stay unused on the target", a.DeletedVersions, a.DeletedSubjects)
+	}
+	if a.VersionsWithMeta > 0 {
+		a.add(levelWarn, "metadata", "%d version(s) carry metadata, which the import API does not carry over; re-register with metadata or set it through config defaults after import", a.VersionsWithMeta)
+	}
+	if a.VersionsWithRuleSet > 0 {
+		a.add(levelWarn, "rule-sets", "%d version(s) carry rule sets (%s), which the import API does not carry over; re-apply them after import", a.VersionsWithRuleSet, formatCounts(a.RuleTypes))
+	}
+	if len(a.Contexts) > 1 || a.Contexts["."] == 0 {
+		a.add(levelWarn, "contexts", "schemas span %d context(s) (%s); import each non-default context through /contexts/{context}/import/schemas", len(a.Contexts), formatCounts(a.Contexts))
+	}
+}
+
+// inventorySettings records the global and per-subject compatibility and
+// mode settings, none of which are part of the schema import.
+func inventorySettings(a *assessment, src registryClient, subjects []string) {
+	var cfg struct {
+		CompatibilityLevel string `json:"compatibilityLevel"`
+	}
+	if src.do("GET", "/config", nil, &cfg) == nil {
+		a.GlobalCompatibility = cfg.CompatibilityLevel
+	}
+	var mode struct {
+		Mode string `json:"mode"`
+	}
+	if src.do("GET", "/mode", nil, &mode) == nil {
+		a.GlobalMode = mode.Mode
+	}
+
+	for _, subject := range subjects {
+		cfg.CompatibilityLevel = ""
+		if src.do("GET", "/config/"+url.PathEscape(subject), nil, &cfg) == nil && cfg.CompatibilityLevel != "" {
+			a.SubjectConfigs[subject] = cfg.CompatibilityLevel
+		}
+		mode.Mode = ""
+		if src.do("GET", "/mode/"+url.PathEscape(subject), nil, &mode) == nil && mode.Mode != "" && mode.Mode != a.GlobalMode {
+			a.SubjectModes[subject] = mode.Mode
+		}
+	}
+
+	if a.GlobalCompatibility != "" && a.GlobalCompatibility != "BACKWARD" {
+		a.add(levelWarn, "config", "global compatibility is %s; set it on the target with PUT /config after import", a.GlobalCompatibility)
+	}
+	if len(a.SubjectConfigs) > 0 {
+		a.add(levelWarn, "config", "%d subject(s) override compatibility and must be re-applied with PUT /config/{subject}: %s", len(a.SubjectConfigs), sample(sortedKeys(a.SubjectConfigs)))
+	}
+	if len(a.SubjectModes) > 0 {
+		a.add(levelWarn, "mode", "%d subject(s) override the mode and must be re-applied with PUT /mode/{subject}: %s", len(a.SubjectModes), sample(sortedKeys(a.SubjectModes)))
+	}
+	if a.GlobalMode != "" && a.GlobalMode != "READWRITE" {
+		a.add(levelWarn, "mode", "source is in %s mode; confirm no producers register schemas during the cutover window", a.GlobalMode)
+	}
+}
+
+// inventoryEncryption counts exporters and KEKs. Both endpoints are optional
+// on Confluent, so a 404 just means the feature is not in use.
+func inventoryEncryption(a *assessment, src registryClient) {
+	var exporters []string
+	if src.do("GET", "/exporters", nil, &exporters) == nil {
+		a.Exporters = len(exporters)
+	}
+	var keks []string
+	if src.do("GET", "/dek-registry/v1/keks", nil, &keks) == nil {
+		a.KEKs = len(keks)
+	}
+	if a.Exporters > 0 {
+		a.add(levelWarn, "exporters", "%d exporter(s) must be recreated on the target with POST /exporters; their credentials are not readable from the source", a.Exporters)
+	}
+	if a.KEKs > 0 {
+		a.add(levelWarn, "encryption", "%d KEK(s) must be recreated on the target, and the target needs access to the same KMS keys to decrypt existing DEKs", a.KEKs)
+	}
+}
+
+// assessTarget checks the target for supported schema types, IMPORT mode
+// and schema IDs that are already taken by different schema text.
+func assessTarget(a *assessment, target registryClient, all []sourceSchema) {
+	var caps struct {
+		SchemaTypes []string `json:"schemaTypes"`
+	}
+	if err := target.do("GET", "/capabilities", nil, &caps); err != nil {
+		a.add(levelBlocker, "target", "could not read target capabilities: %v", err)
+		return
+	}
+	supported := map[string]bool{}
+	for _, t := range caps.SchemaTypes {
+		supported[t] = true
+	}
+	for t := range a.SchemaTypes {
+		if !supported[t] {
+			a.add(levelBlocker, "target", "target does not have schema type %s enabled", t)
+		}
+	}
+
+	var mode struct {
+		Mode string `json:"mode"`
+	}
+	if target.do("GET", "/mode", nil, &mode) == nil && mode.Mode != "IMPORT" {
+		a.add(levelWarn, "target", "target is in %s mode; switch it to IMPORT before importing", mode.Mode)
+	}
+
+	var existing []sourceSchema
+	if err := target.do("GET", "/schemas?deleted=true", nil, &existing); err != nil {
+		a.add(levelWarn, "target", "could not list target schemas to check for ID collisions: %v", err)
+		return
+	}
+	if len(existing) == 0 {
+		a.add(levelOK, "target", "target is empty")
+		return
+	}
+	taken := make(map[int64]string, len(existing))
+	for _, s := range existing {
+		taken[s.ID] = s.Schema
+	}
+	var collisions []int64
+	seen := map[int64]bool{}
+	for _, s := range all {
+		if text, ok := taken[s.ID]; ok && text != s.Schema && !seen[s.ID] {
+			collisions = append(collisions, s.ID)
+			seen[s.ID] = true
+		}
+	}
+	if len(collisions) > 0 {
+		a.add(levelBlocker, "target", "%d source schema ID(s) are already used on the target by different schemas: %v", len(collisions), collisions)
+	} else {
+		a.add(levelWarn, "target", "target already holds %d schema version(s); none of their IDs collide with the source", len(existing))
+	}
+}
+
+func printAssessment(a *assessment) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "SOURCE\t%s\n", a.Source)
+	fmt.Fprintf(w, "SUBJECTS\t%d (+%d soft-deleted)\n", a.Subjects, a.DeletedSubjects)
+	fmt.Fprintf(w, "VERSIONS\t%d (+%d soft-deleted)\n", a.Versions, a.DeletedVersions)
+	fmt.Fprintf(w, "SCHEMA IDS\t%d (range %d-%d)\n", a.SchemaIDs, a.MinID, a.MaxID)
+	fmt.Fprintf(w, "MAX VERSION\t%d\n", a.MaxVersion)
+	fmt.Fprintf(w, "SCHEMA TYPES\t%s\n", formatCounts(a.SchemaTypes))
+	fmt.Fprintf(w, "CONTEXTS\t%s\n", formatCounts(a.Contexts))
+	fmt.Fprintf(w, "REFERENCES\t%d\n", a.References)
+	fmt.Fprintf(w, "METADATA\t%d version(s)\n", a.VersionsWithMeta)
+	fmt.Fprintf(w, "RULE SETS\t%d version(s)\n", a.VersionsWithRuleSet)
+	fmt.Fprintf(w, "COMPATIBILITY\t%s (%d subject override(s))\n", orDash(a.GlobalCompatibility), len(a.SubjectConfigs))
+	fmt.Fprintf(w, "MODE\t%s (%d subject override(s))\n", orDash(a.GlobalMode), len(a.SubjectModes))
+	fmt.Fprintf(w, "EXPORTERS\t%d\n", a.Exporters)
+	fmt.Fprintf(w, "KEKS\t%d\n", a.KEKs)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LEVEL\tAREA\tFINDING")
+	for _, f := range a.Findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Level, f.Area, f.Message)
+	}
+	return w.Flush()
+}
+
+// subjectContext returns the context of a qualified subject name such as
+// ":.staging:orders-value", or "." for the default context.
+func subjectContext(subject string) string {
+	if !strings.HasPrefix(subject, ":.") {
+		return "."
+	}
+	if end := strings.Index(subject[1:], ":"); end > 0 {
+		return subject[1 : end+1]
+	}
+	return "."
+}
+
+// qualifySubject resolves a reference subject relative to the context of the
+// referencing schema.
+func qualifySubject(subject, context string) string {
+	if context == "." || strings.HasPrefix(subject, ":.") {
+		return subject
+	}
+	return ":" + context + ":" + subject
+}
+
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// sample lists the first few items, which keeps findings readable on
+// registries with thousands of subjects.
+func sample(items []string) string {
+	const limit = 5
+	if len(items) <= limit {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:limit], ", "), len(items)-limit)
+}
+
+func formatCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(counts))
+	for _, k := range sortedKeys(counts) {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
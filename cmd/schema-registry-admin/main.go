@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -40,7 +42,7 @@ func main() {
 	rootCmd := &cobra.Command{
 		Use:   "schema-registry-admin",
 		Short: "Admin CLI for AxonOps Schema Registry",
-		Long:  `A command-line tool for managing users, API keys, and roles in the AxonOps Schema Registry.`,
+		Long:  `A command-line tool for managing schemas, users, API keys, and roles in the AxonOps Schema Registry.`,
 	}
 
 	// Global flags
@@ -188,6 +190,41 @@ func main() {
 
 	roleCmd.AddCommand(roleListCmd)
 
+	// Audit commands
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query audit history",
+		Long: `Query the audit events retained in memory by the server.
+
+Requires audit.history.enabled on the server. History is per instance and
+only covers events since the instance started.`,
+	}
+
+	auditListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recent audit events",
+		RunE:  listAuditEvents,
+	}
+	auditListCmd.Flags().String("since", "", "Only events newer than this (duration like 24h, or RFC3339 timestamp)")
+	auditListCmd.Flags().Int("limit", 100, "Maximum number of events to return")
+
+	auditSearchCmd := &cobra.Command{
+		Use:   "search",
+		Short: "Search audit events",
+		Example: `  schema-registry-admin audit search --principal alice --subject orders-value --since 24h
+  schema-registry-admin audit search --event-type subject_delete_permanent --outcome success -o json`,
+		RunE: listAuditEvents,
+	}
+	auditSearchCmd.Flags().String("principal", "", "Filter by actor (username, API key name or MCP principal)")
+	auditSearchCmd.Flags().String("subject", "", "Filter by subject")
+	auditSearchCmd.Flags().String("event-type", "", "Filter by event type (e.g., schema_register)")
+	auditSearchCmd.Flags().String("outcome", "", "Filter by outcome: success, failure")
+	auditSearchCmd.Flags().String("since", "", "Only events newer than this (duration like 24h, or RFC3339 timestamp)")
+	auditSearchCmd.Flags().String("until", "", "Only events older than this (duration like 1h, or RFC3339 timestamp)")
+	auditSearchCmd.Flags().Int("limit", 100, "Maximum number of events to return")
+
+	auditCmd.AddCommand(auditListCmd, auditSearchCmd)
+
 	// Version command
 	versionCmd := &cobra.Command{
 		Use:   "version",
@@ -260,7 +297,7 @@ Environment variables can also be used:
 	initCmd.Flags().String("admin-email", getEnvOrDefault("SCHEMA_REGISTRY_BOOTSTRAP_EMAIL", ""), "Admin email (optional)")
 	_ = initCmd.MarkFlagRequired("admin-password")
 
-	rootCmd.AddCommand(userCmd, apikeyCmd, roleCmd, versionCmd, initCmd)
+	rootCmd.AddCommand(newSchemaCmd(), newAssessCmd(), newVerifyCmd(), newReferencesCmd(), newApplyCmd(), newReencryptCmd(), userCmd, apikeyCmd, roleCmd, auditCmd, versionCmd, initCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -269,7 +306,44 @@ Environment variables can also be used:
 
 // HTTP client helper
 func doRequest(method, path string, body interface{}) (map[string]interface{}, error) {
-	url := strings.TrimSuffix(serverURL, "/") + path
+	var result map[string]interface{}
+	if err := doRequestInto(method, path, body, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// doRequestInto sends a request to the server and decodes the JSON response
+// into out. It is used directly for endpoints that do not return an object,
+// such as subject and version lists.
+func doRequestInto(method, path string, body interface{}, out interface{}) error {
+	target := registryClient{baseURL: serverURL, username: username, password: password, apiKey: apiKey}
+	return target.do(method, path, body, out)
+}
+
+// registryClient is a schema registry endpoint and the credentials used to
+// reach it. The global flags describe the registry being administered; the
+// assess command builds a second client for the source registry.
+type registryClient struct {
+	baseURL  string
+	username string
+	password string
+	apiKey   string
+}
+
+// apiError is a non-2xx response from a registry.
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// do sends a request to the registry and decodes the JSON response into out.
+func (c registryClient) do(method, path string, body interface{}, out interface{}) error {
+	url := strings.TrimSuffix(c.baseURL, "/") + path
 
 	var req *http.Request
 	var err error
@@ -277,48 +351,57 @@ func doRequest(method, path string, body interface{}) (map[string]interface{}, e
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request: %w", err)
+			return fmt.Errorf("failed to marshal request: %w", err)
 		}
 		req, err = http.NewRequest(method, url, strings.NewReader(string(jsonBody)))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+			return fmt.Errorf("failed to create request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
 	} else {
 		req, err = http.NewRequest(method, url, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+			return fmt.Errorf("failed to create request: %w", err)
 		}
 	}
 
 	// Authentication
-	if apiKey != "" {
-		req.Header.Set("X-API-Key", apiKey)
-	} else if username != "" && password != "" {
-		req.SetBasicAuth(username, password)
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	} else if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
 	}
 
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req) // #nosec G704 -- admin CLI tool; URL is from user-provided --server flag
+	resp, err := client.Do(req) // #nosec G704 -- admin CLI tool; URL is from user-provided --server or --source flag
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode != http.StatusNoContent {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
 		msg := "unknown error"
-		if m, ok := result["message"].(string); ok {
-			msg = m
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			msg = apiErr.Message
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, msg)
+		return &apiError{StatusCode: resp.StatusCode, Message: msg}
 	}
 
-	return result, nil
+	if resp.StatusCode == http.StatusNoContent || out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
 }
 
 // User commands
@@ -717,6 +800,69 @@ func listRoles(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Audit commands
+
+// auditQueryFlags maps CLI flags to /admin/audit query parameters. Flags that
+// a command does not define are skipped.
+var auditQueryFlags = map[string]string{
+	"principal":  "principal",
+	"subject":    "subject",
+	"event-type": "event_type",
+	"outcome":    "outcome",
+	"since":      "since",
+	"until":      "until",
+}
+
+func listAuditEvents(cmd *cobra.Command, args []string) error {
+	params := url.Values{}
+	for flag, param := range auditQueryFlags {
+		if cmd.Flags().Lookup(flag) == nil {
+			continue
+		}
+		if v, _ := cmd.Flags().GetString(flag); v != "" {
+			params.Set(param, v)
+		}
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	params.Set("limit", strconv.Itoa(limit))
+
+	result, err := doRequest("GET", "/admin/audit?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	events, ok := result["events"].([]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected response format")
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tEVENT\tOUTCOME\tACTOR\tTARGET\tSTATUS\tPATH")
+	for _, e := range events {
+		event := e.(map[string]interface{})
+		target := "-"
+		if t, _ := event["target_type"].(string); t != "" {
+			target = fmt.Sprintf("%s/%v", t, event["target_id"])
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			formatTime(event["timestamp"]),
+			event["event_type"],
+			event["outcome"],
+			event["actor_id"],
+			target,
+			event["status_code"],
+			event["path"],
+		)
+	}
+	return w.Flush()
+}
+
 // Helpers
 func formatTime(t interface{}) string {
 	if t == nil {
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newReferencesCmd() *cobra.Command {
+	referencesCmd := &cobra.Command{
+		Use:   "references",
+		Short: "Inspect schema references",
+	}
+
+	orphansCmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "List references that no longer resolve",
+		Long: `List the references of every subject version, including soft-deleted ones,
+that no longer resolve to the schema they were registered against:
+
+  missing       the referenced subject version does not exist
+  soft_deleted  the referenced subject version is soft-deleted
+  replaced      the referenced subject version was stored again after the
+                referencing version, e.g. by a hard delete and a re-import,
+                so its content may have changed
+
+With --fix, each live version with a missing or soft-deleted reference is
+soft-deleted, since it cannot be parsed any more. A version that other
+schemas still reference cannot be deleted and is reported as failed.
+Replaced references are only reported: whether the new content is right is
+left to an operator.
+
+Listing requires admin read permissions; --fix also requires schema delete
+permissions. The command exits non-zero when orphaned references remain.`,
+		Example: `  schema-registry-admin references orphans
+  schema-registry-admin references orphans --context .team-a --fix
+  schema-registry-admin -o json references orphans > orphans.json`,
+		RunE: listOrphanedReferences,
+	}
+	orphansCmd.Flags().StringVar(&schemaContext, "context", "", "Registry context (default: every context)")
+	orphansCmd.Flags().Bool("fix", false, "Soft-delete live versions with missing or soft-deleted references")
+
+	referencesCmd.AddCommand(orphansCmd)
+	return referencesCmd
+}
+
+// orphanedReference is an entry of GET /admin/references/orphans, with the
+// outcome of --fix.
+type orphanedReference struct {
+	Context   string `json:"context"`
+	Subject   string `json:"subject"`
+	Version   int    `json:"version"`
+	ID        int64  `json:"id"`
+	Deleted   bool   `json:"deleted"`
+	Reference struct {
+		Name    string `json:"name"`
+		Subject string `json:"subject"`
+		Version int    `json:"version"`
+	} `json:"reference"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+
+	Fixed    bool   `json:"fixed,omitempty"`
+	FixError string `json:"fix_error,omitempty"`
+}
+
+func listOrphanedReferences(cmd *cobra.Command, args []string) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	path := "/admin/references/orphans"
+	if schemaContext != "" {
+		path += "?context=" + url.QueryEscape(schemaContext)
+	}
+	var resp struct {
+		Orphans []orphanedReference `json:"orphans"`
+	}
+	if err := doRequestInto("GET", path, nil, &resp); err != nil {
+		return fmt.Errorf("failed to list orphaned references: %w", err)
+	}
+	orphans := resp.Orphans
+
+	if fix {
+		fixOrphanedReferences(orphans)
+	}
+
+	if output == "json" {
+		if err := printJSON(orphans); err != nil {
+			return err
+		}
+	} else if err := printOrphanedReferences(orphans, fix); err != nil {
+		return err
+	}
+
+	remaining := 0
+	for _, o := range orphans {
+		if !o.Fixed {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d orphaned reference(s) remain", remaining)
+	}
+	return nil
+}
+
+// fixOrphanedReferences soft-deletes each live version with a missing or
+// soft-deleted reference, once, and records the outcome on its entries.
+func fixOrphanedReferences(orphans []orphanedReference) {
+	type versionKey struct {
+		context, subject string
+		version          int
+	}
+	outcome := make(map[versionKey]error)
+	for i := range orphans {
+		o := &orphans[i]
+		if o.Deleted || (o.Kind != "missing" && o.Kind != "soft_deleted") {
+			continue
+		}
+		key := versionKey{o.Context, o.Subject, o.Version}
+		err, done := outcome[key]
+		if !done {
+			path := contextPath(o.Context, "/subjects/"+url.PathEscape(o.Subject)+"/versions/"+strconv.Itoa(o.Version))
+			var deleted interface{}
+			err = doRequestInto("DELETE", path, nil, &deleted)
+			outcome[key] = err
+		}
+		if err != nil {
+			o.FixError = err.Error()
+			continue
+		}
+		o.Fixed = true
+	}
+}
+
+func printOrphanedReferences(orphans []orphanedReference, fix bool) error {
+	if len(orphans) == 0 {
+		fmt.Println("No orphaned references.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "CONTEXT\tSUBJECT\tVERSION\tREFERENCE\tKIND\tMESSAGE"
+	if fix {
+		header += "\tFIX"
+	}
+	fmt.Fprintln(w, header)
+	for _, o := range orphans {
+		version := strconv.Itoa(o.Version)
+		if o.Deleted {
+			version += " (deleted)"
+		}
+		ref := fmt.Sprintf("%s -> %s/%d", o.Reference.Name, o.Reference.Subject, o.Reference.Version)
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s", o.Context, o.Subject, version, ref, o.Kind, o.Message)
+		if fix {
+			status := "-"
+			switch {
+			case o.FixError != "":
+				status = "failed: " + o.FixError
+			case o.Fixed:
+				status = "version soft-deleted"
+			}
+			line += "\t" + status
+		}
+		fmt.Fprintln(w, line)
+	}
+	return w.Flush()
+}
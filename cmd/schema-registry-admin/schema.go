@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// schemaContext is the registry context targeted by schema commands. Empty
+// means the default context.
+var schemaContext string
+
+func newSchemaCmd() *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Manage schemas and subjects",
+		Long: `Register, inspect, compare and delete schemas through the REST API.
+
+Use --context to target a registry context other than the default one.`,
+	}
+	schemaCmd.PersistentFlags().StringVar(&schemaContext, "context", "", "Registry context (default: the default context)")
+
+	schemaRegisterCmd := &cobra.Command{
+		Use:   "register <subject>",
+		Short: "Register a schema under a subject",
+		Example: `  schema-registry-admin schema register orders-value --file order.avsc
+  schema-registry-admin schema register orders-value --file order.proto --type PROTOBUF --context .team-a
+  cat order.json | schema-registry-admin schema register orders-value --file - --type JSON`,
+		Args: cobra.ExactArgs(1),
+		RunE: registerSchema,
+	}
+	schemaRegisterCmd.Flags().StringP("file", "f", "", "Schema file to register, or - for stdin (required)")
+	schemaRegisterCmd.Flags().String("type", "AVRO", "Schema type: AVRO, PROTOBUF, JSON")
+	schemaRegisterCmd.Flags().Bool("normalize", false, "Normalize the schema before registering")
+	_ = schemaRegisterCmd.MarkFlagRequired("file")
+
+	schemaGetCmd := &cobra.Command{
+		Use:   "get <subject> [version]",
+		Short: "Get a schema version (default: latest)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  getSchema,
+	}
+
+	schemaListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List subjects",
+		RunE:  listSubjects,
+	}
+	schemaListCmd.Flags().String("prefix", "", "Only subjects starting with this prefix")
+	schemaListCmd.Flags().Bool("deleted", false, "Include soft-deleted subjects")
+
+	schemaVersionsCmd := &cobra.Command{
+		Use:   "versions <subject>",
+		Short: "List the versions of a subject",
+		Args:  cobra.ExactArgs(1),
+		RunE:  listSchemaVersions,
+	}
+	schemaVersionsCmd.Flags().Bool("deleted", false, "Include soft-deleted versions")
+
+	schemaDeleteCmd := &cobra.Command{
+		Use:   "delete <subject> [version]",
+		Short: "Delete a subject, or a single version of it",
+		Long: `Soft-delete a subject or one of its versions. Use --permanent to remove a
+subject or version that has already been soft-deleted.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: deleteSchema,
+	}
+	schemaDeleteCmd.Flags().Bool("permanent", false, "Permanently delete (requires a prior soft delete)")
+
+	schemaDiffCmd := &cobra.Command{
+		Use:   "diff <subject> <v1> <v2>",
+		Short: "Show the fields added, removed or changed between two versions",
+		Args:  cobra.ExactArgs(3),
+		RunE:  diffSchemas,
+	}
+
+	schemaCmd.AddCommand(schemaRegisterCmd, schemaGetCmd, schemaListCmd, schemaVersionsCmd, schemaDeleteCmd, schemaDiffCmd)
+	return schemaCmd
+}
+
+// registryPath returns the REST path for a registry resource in the
+// context selected with --context.
+func registryPath(path string) string {
+	if schemaContext == "" {
+		return path
+	}
+	return "/contexts/" + url.PathEscape(schemaContext) + path
+}
+
+// subjectPath returns the REST path for a subject resource.
+func subjectPath(subject string, rest string) string {
+	return registryPath("/subjects/" + url.PathEscape(subject) + rest)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func registerSchema(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	schemaType, _ := cmd.Flags().GetString("type")
+	normalize, _ := cmd.Flags().GetBool("normalize")
+
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file) // #nosec G304 -- admin CLI tool; path is from user-provided --file flag
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"schema":     string(data),
+		"schemaType": strings.ToUpper(schemaType),
+	}
+	path := subjectPath(args[0], "/versions")
+	if normalize {
+		path += "?normalize=true"
+	}
+
+	result, err := doRequest("POST", path, body)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		return printJSON(result)
+	}
+	fmt.Printf("Registered schema under subject %s with ID %v\n", args[0], int64(result["id"].(float64)))
+	return nil
+}
+
+func getSchema(cmd *cobra.Command, args []string) error {
+	version := "latest"
+	if len(args) == 2 {
+		version = args[1]
+	}
+
+	result, err := doRequest("GET", subjectPath(args[0], "/versions/"+url.PathEscape(version)), nil)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		return printJSON(result)
+	}
+
+	schemaType := result["schemaType"]
+	if schemaType == nil || schemaType == "" {
+		schemaType = "AVRO"
+	}
+	fmt.Printf("Subject: %v\n", result["subject"])
+	fmt.Printf("Version: %v\n", result["version"])
+	fmt.Printf("ID:      %v\n", int64(result["id"].(float64)))
+	fmt.Printf("Type:    %v\n", schemaType)
+	fmt.Println()
+	fmt.Println(result["schema"])
+	return nil
+}
+
+func listSubjects(cmd *cobra.Command, args []string) error {
+	prefix, _ := cmd.Flags().GetString("prefix")
+	deleted, _ := cmd.Flags().GetBool("deleted")
+
+	params := url.Values{}
+	if prefix != "" {
+		params.Set("subjectPrefix", prefix)
+	}
+	if deleted {
+		params.Set("deleted", "true")
+	}
+	path := registryPath("/subjects")
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var subjects []string
+	if err := doRequestInto("GET", path, nil, &subjects); err != nil {
+		return err
+	}
+
+	if output == "json" {
+		return printJSON(subjects)
+	}
+	for _, s := range subjects {
+		fmt.Println(s)
+	}
+	return nil
+}
+
+func listSchemaVersions(cmd *cobra.Command, args []string) error {
+	deleted, _ := cmd.Flags().GetBool("deleted")
+
+	path := subjectPath(args[0], "/versions")
+	if deleted {
+		path += "?deleted=true"
+	}
+
+	var versions []int
+	if err := doRequestInto("GET", path, nil, &versions); err != nil {
+		return err
+	}
+
+	if output == "json" {
+		return printJSON(versions)
+	}
+	for _, v := range versions {
+		fmt.Println(v)
+	}
+	return nil
+}
+
+func deleteSchema(cmd *cobra.Command, args []string) error {
+	permanent, _ := cmd.Flags().GetBool("permanent")
+
+	path := subjectPath(args[0], "")
+	if len(args) == 2 {
+		path = subjectPath(args[0], "/versions/"+url.PathEscape(args[1]))
+	}
+	if permanent {
+		path += "?permanent=true"
+	}
+
+	var deleted interface{}
+	if err := doRequestInto("DELETE", path, nil, &deleted); err != nil {
+		return err
+	}
+
+	if output == "json" {
+		return printJSON(deleted)
+	}
+	kind := "Soft-deleted"
+	if permanent {
+		kind = "Permanently deleted"
+	}
+	if len(args) == 2 {
+		fmt.Printf("%s version %v of subject %s\n", kind, deleted, args[0])
+		return nil
+	}
+	fmt.Printf("%s subject %s (versions: %v)\n", kind, args[0], deleted)
+	return nil
+}
+
+func diffSchemas(cmd *cobra.Command, args []string) error {
+	v1, err := strconv.Atoi(args[1])
+	if err != nil || v1 <= 0 {
+		return fmt.Errorf("invalid version %q", args[1])
+	}
+	v2, err := strconv.Atoi(args[2])
+	if err != nil || v2 <= 0 {
+		return fmt.Errorf("invalid version %q", args[2])
+	}
+
+	result, err := doRequest("POST", subjectPath(args[0], "/diff"), map[string]int{
+		"version1": v1,
+		"version2": v2,
+	})
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		return printJSON(result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tFIELD\tTYPE")
+	rows := 0
+	for _, change := range []string{"added", "removed", "changed"} {
+		entries, _ := result[change].([]interface{})
+		for _, e := range entries {
+			entry := e.(map[string]interface{})
+			fieldType := entry["type"]
+			if change == "changed" {
+				fieldType = fmt.Sprintf("%v -> %v", entry["old_type"], entry["new_type"])
+			}
+			fmt.Fprintf(w, "%s\t%v\t%v\n", change, entry["field"], fieldType)
+			rows++
+		}
+	}
+	if rows == 0 {
+		fmt.Fprintf(w, "-\t-\tno field differences between versions %d and %d\n", v1, v2)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check registry storage for invariant violations",
+		Long: `Run a consistency check on the registry given by --server. Every subject
+version, including soft-deleted ones, is checked for:
+
+  orphaned_reference    a reference to a subject version that does not exist
+  fingerprint_mismatch  a stored fingerprint that differs from the schema's
+  duplicate_version     a subject version stored more than once
+  id_sequence_behind    a next schema ID that is not past the highest ID in use
+
+With --repair, an ID sequence that is behind is advanced past the highest ID.
+The other findings are only reported.
+
+The check runs as an async job on the server and requires admin write
+permissions. The command exits non-zero when findings remain unrepaired.`,
+		Example: `  schema-registry-admin verify
+  schema-registry-admin verify --context .team-a --repair
+  schema-registry-admin -o json verify --all-contexts > consistency.json`,
+		RunE: verifyRegistry,
+	}
+	verifyCmd.Flags().StringVar(&schemaContext, "context", "", "Registry context (default: the default context)")
+	verifyCmd.Flags().Bool("all-contexts", false, "Check every context")
+	verifyCmd.Flags().Bool("repair", false, "Advance ID sequences that are behind the highest schema ID")
+	verifyCmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait for each check to finish")
+	return verifyCmd
+}
+
+// consistencyReport is the result of a consistency check job.
+type consistencyReport struct {
+	Context         string               `json:"context"`
+	SubjectsChecked int                  `json:"subjects_checked"`
+	VersionsChecked int                  `json:"versions_checked"`
+	Findings        []consistencyFinding `json:"findings"`
+}
+
+type consistencyFinding struct {
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject"`
+	Version  int    `json:"version"`
+	SchemaID int64  `json:"schema_id"`
+	Message  string `json:"message"`
+	Repaired bool   `json:"repaired"`
+}
+
+// jobStatus is the part of a job resource the command follows.
+type jobStatus struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+	Error string `json:"error"`
+}
+
+func verifyRegistry(cmd *cobra.Command, args []string) error {
+	allContexts, _ := cmd.Flags().GetBool("all-contexts")
+	repair, _ := cmd.Flags().GetBool("repair")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	contexts := []string{schemaContext}
+	if allContexts {
+		if err := doRequestInto("GET", "/contexts", nil, &contexts); err != nil {
+			return fmt.Errorf("failed to list contexts: %w", err)
+		}
+	}
+
+	var reports []consistencyReport
+	for _, name := range contexts {
+		report, err := runConsistencyCheck(name, repair, timeout)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, *report)
+	}
+
+	if output == "json" {
+		if err := printJSON(reports); err != nil {
+			return err
+		}
+	} else if err := printConsistencyReports(reports); err != nil {
+		return err
+	}
+
+	unrepaired := 0
+	for _, r := range reports {
+		for _, f := range r.Findings {
+			if !f.Repaired {
+				unrepaired++
+			}
+		}
+	}
+	if unrepaired > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("consistency check found %d unrepaired problem(s)", unrepaired)
+	}
+	return nil
+}
+
+// runConsistencyCheck submits a consistency check job for a context, waits
+// for it to finish and returns its report. An empty context is the default
+// context.
+func runConsistencyCheck(registryCtx string, repair bool, timeout time.Duration) (*consistencyReport, error) {
+	var report consistencyReport
+	if err := runJob("consistency check", contextPath(registryCtx, "/consistency/verify"), map[string]bool{"repair": repair}, timeout, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// contextPath prefixes path with the context, unless it is the default.
+func contextPath(registryCtx, path string) string {
+	if registryCtx != "" && registryCtx != "." {
+		return "/contexts/" + url.PathEscape(registryCtx) + path
+	}
+	return path
+}
+
+// runJob starts an async job with a POST of params to path, waits for it to
+// finish and decodes its result into result. what names the job in errors.
+func runJob(what, path string, params any, timeout time.Duration, result any) error {
+	var job jobStatus
+	if err := doRequestInto("POST", path, params, &job); err != nil {
+		return fmt.Errorf("failed to start %s: %w", what, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for job.State == "PENDING" || job.State == "RUNNING" {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s job %s did not finish within %s", what, job.ID, timeout)
+		}
+		time.Sleep(time.Second)
+		if err := doRequestInto("GET", "/jobs/"+url.PathEscape(job.ID), nil, &job); err != nil {
+			return fmt.Errorf("failed to get job %s: %w", job.ID, err)
+		}
+	}
+	if job.State != "SUCCEEDED" {
+		return fmt.Errorf("%s job %s %s: %s", what, job.ID, job.State, job.Error)
+	}
+
+	if err := doRequestInto("GET", "/jobs/"+url.PathEscape(job.ID)+"/result", nil, result); err != nil {
+		return fmt.Errorf("failed to get result of job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func printConsistencyReports(reports []consistencyReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tSUBJECTS\tVERSIONS\tFINDINGS")
+	for _, r := range reports {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", r.Context, r.SubjectsChecked, r.VersionsChecked, len(r.Findings))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tKIND\tSUBJECT\tVERSION\tSCHEMA ID\tREPAIRED\tMESSAGE")
+	for _, r := range reports {
+		for _, f := range r.Findings {
+			version := "-"
+			if f.Version > 0 {
+				version = strconv.Itoa(f.Version)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%t\t%s\n",
+				r.Context, f.Kind, orDash(f.Subject), version, f.SchemaID, f.Repaired, f.Message)
+		}
+	}
+	return w.Flush()
+}
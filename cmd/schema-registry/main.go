@@ -3,39 +3,56 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/axonops/axonops-schema-registry/internal/api"
+	"github.com/axonops/axonops-schema-registry/internal/atrest"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
 	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
 	jsoncompat "github.com/axonops/axonops-schema-registry/internal/compatibility/jsonschema"
 	protocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/protobuf"
 	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/consistency"
+	"github.com/axonops/axonops-schema-registry/internal/exporter"
+	"github.com/axonops/axonops-schema-registry/internal/grpcapi"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
 	"github.com/axonops/axonops-schema-registry/internal/kms"
 	openbaokms "github.com/axonops/axonops-schema-registry/internal/kms/openbao"
 	vaultkms "github.com/axonops/axonops-schema-registry/internal/kms/vault"
+	"github.com/axonops/axonops-schema-registry/internal/lint"
 	mcpkg "github.com/axonops/axonops-schema-registry/internal/mcp"
 	"github.com/axonops/axonops-schema-registry/internal/metrics"
+	"github.com/axonops/axonops-schema-registry/internal/reencryption"
 	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/retention"
+	"github.com/axonops/axonops-schema-registry/internal/revalidation"
 	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
 	"github.com/axonops/axonops-schema-registry/internal/schema/jsonschema"
 	"github.com/axonops/axonops-schema-registry/internal/schema/protobuf"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 	"github.com/axonops/axonops-schema-registry/internal/storage/cassandra"
+	"github.com/axonops/axonops-schema-registry/internal/storage/fallback"
 	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
 	"github.com/axonops/axonops-schema-registry/internal/storage/mysql"
 	"github.com/axonops/axonops-schema-registry/internal/storage/postgres"
+	"github.com/axonops/axonops-schema-registry/internal/storage/rdsiam"
 	"github.com/axonops/axonops-schema-registry/internal/storage/vault"
+	"github.com/axonops/axonops-schema-registry/internal/storage/writegate"
+	"github.com/axonops/axonops-schema-registry/internal/telemetry"
+	"github.com/axonops/axonops-schema-registry/internal/tenant"
+	"github.com/axonops/axonops-schema-registry/internal/usage"
 )
 
 var (
@@ -72,22 +89,15 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Reconfigure logger from config (format + level).
-	switch cfg.Logging.Level {
-	case "debug":
-		logLevel = slog.LevelDebug
-	case "warn", "warning":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	default:
-		logLevel = slog.LevelInfo
-	}
+	// Reconfigure logger from config (format + level). The level can be
+	// changed by a configuration reload.
+	configLogLevel := new(slog.LevelVar)
+	configLogLevel.Set(parseLogLevel(cfg.Logging.Level))
 	var logHandler slog.Handler
 	if cfg.Logging.Format == "text" {
-		logHandler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})
+		logHandler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: configLogLevel})
 	} else {
-		logHandler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})
+		logHandler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: configLogLevel})
 	}
 	logger = slog.New(logHandler)
 	slog.SetDefault(logger)
@@ -98,6 +108,19 @@ func main() {
 		slog.String("address", cfg.Address()),
 	)
 
+	// Continue callers' traces and, if enabled, export spans
+	shutdownTracing, err := telemetry.Setup(cfg.Tracing, version)
+	if err != nil {
+		logger.Error("failed to set up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if cfg.Tracing.Enabled {
+		logger.Info("tracing enabled",
+			slog.String("endpoint", cfg.Tracing.Endpoint),
+			slog.Float64("sample_ratio", cfg.Tracing.SampleRatio),
+		)
+	}
+
 	// Create metrics early so we can wrap storage with instrumentation
 	m := metrics.New()
 
@@ -108,9 +131,108 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Optionally refuse writes while the backend's health signals say they
+	// could be lost in a failover. The signals are measured on the backend
+	// itself, before any wrapping.
+	var writeGate *writegate.Gate
+	writeGateStop := make(chan struct{})
+	if cfg.Storage.WriteGate.Enabled {
+		checkInterval, _ := config.ParseDuration(cfg.Storage.WriteGate.CheckInterval)      // validated by config.Load
+		checkTimeout, _ := config.ParseDuration(cfg.Storage.WriteGate.CheckTimeout)        // validated by config.Load
+		maxLag, _ := config.ParseDuration(cfg.Storage.WriteGate.MaxReplicationLag)         // validated by config.Load
+		maxLatency, _ := config.ParseDuration(cfg.Storage.WriteGate.MaxCoordinatorLatency) // validated by config.Load
+		writeGate = writegate.New(checkTimeout, logger)
+		switch backend := store.(type) {
+		case *postgres.Store:
+			if maxLag > 0 {
+				writeGate.Register("postgresql_replication_lag", maxLag, backend.ReplicationLag)
+			}
+		case *cassandra.Store:
+			if maxLatency > 0 {
+				writeGate.Register("cassandra_coordinator_latency", maxLatency, backend.CoordinatorLatency)
+			}
+		}
+		if signals := writeGate.Signals(); len(signals) > 0 {
+			writeGate.Start(checkInterval, writeGateStop)
+			logger.Info("storage write gate enabled",
+				slog.Any("signals", signals),
+				slog.Duration("check_interval", checkInterval),
+			)
+		} else {
+			logger.Warn("storage write gate enabled but the storage backend has no health signals to measure",
+				slog.String("storage", cfg.Storage.Type),
+			)
+		}
+	}
+
+	// Optionally keep a local snapshot of the backend and serve reads from it,
+	// read-only, while the backend is down. It sits below encryption so the
+	// snapshot holds schemas as they are stored.
+	var fallbackStore *fallback.Store
+	fallbackStop := make(chan struct{})
+	if cfg.Storage.Fallback.Enabled {
+		refreshInterval, _ := config.ParseDuration(cfg.Storage.Fallback.RefreshInterval) // validated by config.Load
+		checkInterval, _ := config.ParseDuration(cfg.Storage.Fallback.CheckInterval)     // validated by config.Load
+		fallbackStore = fallback.New(store, cfg.Storage.Fallback.Path, logger)
+		fallbackStore.Start(refreshInterval, checkInterval, fallbackStop)
+		store = fallbackStore
+		logger.Info("storage fallback snapshot enabled",
+			slog.String("path", cfg.Storage.Fallback.Path),
+			slog.Duration("refresh_interval", refreshInterval),
+			slog.Duration("check_interval", checkInterval),
+		)
+	}
+
+	// With multi-tenancy, seal each tenant's schemas with its own key before
+	// they reach the backend.
+	var keyring *tenant.Keyring
+	var schemaKeyring storage.SchemaKeyring
+	if cfg.Tenancy.Enabled {
+		masterKey, _ := tenant.DecodeMasterKey(cfg.Tenancy.MasterKey) // validated by config.Load
+		keyring, err = tenant.NewKeyring(store, masterKey)
+		if err != nil {
+			logger.Error("failed to create tenant keyring", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		schemaKeyring = keyring
+		logger.Info("multi-tenancy enabled, tenant schemas are encrypted at rest")
+	}
+
+	// With storage encryption, seal every other schema with this instance's
+	// data key. Keys stay configured after encryption is turned off so that
+	// schemas sealed earlier can still be read and re-encrypted in plaintext.
+	if enc := cfg.Storage.Encryption; enc.Enabled || len(enc.Keys) > 0 || enc.Vault.KeyName != "" || enc.Vault.Address != "" {
+		instanceKeyring, err := createAtRestKeyring(cfg.Storage.Encryption, schemaKeyring)
+		if err != nil {
+			logger.Error("failed to create storage encryption keyring", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		schemaKeyring = instanceKeyring
+		if instanceKeyring.Encrypting() {
+			logger.Info("storage encryption enabled, schemas are encrypted at rest")
+		} else {
+			logger.Warn("storage encryption disabled, new schemas are stored in plaintext and encrypted schemas are only decrypted")
+		}
+	}
+	if schemaKeyring != nil {
+		store = storage.NewEncryptedStorage(store, schemaKeyring)
+	}
+
 	// Wrap storage with instrumentation to record operation metrics
 	instrumentedStore := storage.NewInstrumentedStorage(store, cfg.Storage.Type, m)
 
+	// Optionally cache hot reads in front of the (instrumented) backend, so
+	// storage metrics only count the reads that miss the cache.
+	var registryStore storage.Storage = instrumentedStore
+	if cfg.Storage.Cache.Enabled {
+		cacheTTL, _ := config.ParseDuration(cfg.Storage.Cache.TTL) // validated by config.Load
+		registryStore = storage.NewCachedStorage(instrumentedStore, cfg.Storage.Cache.MaxEntries, cacheTTL, m)
+		logger.Info("storage read cache enabled",
+			slog.Int("max_entries", cfg.Storage.Cache.MaxEntries),
+			slog.Duration("ttl", cacheTTL),
+		)
+	}
+
 	// Create schema parser registry
 	schemaRegistry := schema.NewRegistry()
 	schemaRegistry.Register(avro.NewParser())
@@ -119,12 +241,16 @@ func main() {
 
 	// Create compatibility checker
 	compatChecker := compatibility.NewChecker()
-	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewCheckerWithConfig(avrocompat.Config{
+		LogicalTypes: avrocompat.LogicalTypeMode(cfg.Compatibility.Avro.LogicalTypes),
+	}))
 	compatChecker.Register(storage.SchemaTypeProtobuf, protocompat.NewChecker())
-	compatChecker.Register(storage.SchemaTypeJSON, jsoncompat.NewChecker())
+	compatChecker.Register(storage.SchemaTypeJSON, jsoncompat.NewCheckerWithConfig(jsoncompat.Config{
+		Strictness: jsoncompat.Strictness(cfg.Compatibility.JSONSchema.Strictness),
+	}))
 
 	// Create the registry service (uses instrumented storage for metrics)
-	reg := registry.New(instrumentedStore, schemaRegistry, compatChecker, cfg.Compatibility.DefaultLevel)
+	reg := registry.New(registryStore, schemaRegistry, compatChecker, cfg.Compatibility.DefaultLevel)
 
 	// Wire KMS provider registry for server-side DEK encryption.
 	// Providers are only registered when their connection env vars are present.
@@ -133,10 +259,124 @@ func main() {
 		reg.SetKMSRegistry(kmsReg)
 	}
 
+	reg.SetMaxReferenceDepth(cfg.References.MaxDepth)
+	if cfg.IDs.Scope == config.IDScopeGlobal {
+		reg.SetGlobalIDs(true)
+		logger.Info("schema IDs are global across contexts")
+	}
+	reg.SetAliasWritePolicy(cfg.SubjectAliases.WritePolicy)
+
+	if keyring != nil {
+		reg.SetTenantKeyGenerator(keyring)
+	}
+	if cfg.Tenancy.Enabled && cfg.Tenancy.ContextMode == "principal" {
+		reg.SetTenantHomeContexts(true)
+	}
+
+	// Wire normalization profiles applied when normalize is enabled.
+	if len(cfg.Normalization.Profiles) > 0 {
+		defaultProfile, contextProfiles := buildNormalizationProfiles(&cfg.Normalization)
+		reg.SetNormalizationProfiles(defaultProfile, contextProfiles)
+		logger.Info("normalization profiles configured",
+			slog.String("default_profile", defaultProfile.Name),
+			slog.Int("context_mappings", len(contextProfiles)),
+		)
+	}
+
+	// Wire subject naming strategies enforced at registration.
+	if cfg.SubjectNaming.DefaultStrategy != "" || len(cfg.SubjectNaming.Contexts) > 0 {
+		reg.SetSubjectNameStrategies(cfg.SubjectNaming.DefaultStrategy, cfg.SubjectNaming.Contexts)
+		logger.Info("subject naming strategies configured",
+			slog.String("default_strategy", cfg.SubjectNaming.DefaultStrategy),
+			slog.Int("context_mappings", len(cfg.SubjectNaming.Contexts)),
+		)
+	}
+
+	// Wire lint policies enforced at registration.
+	if len(cfg.Linting.Rules) > 0 || len(cfg.Linting.Contexts) > 0 || len(cfg.Linting.Subjects) > 0 {
+		contextPolicies := make(map[string]lint.Policy, len(cfg.Linting.Contexts))
+		for name, policy := range cfg.Linting.Contexts {
+			contextPolicies[name] = policy
+		}
+		subjectPolicies := make(map[string]lint.Policy, len(cfg.Linting.Subjects))
+		for subject, policy := range cfg.Linting.Subjects {
+			subjectPolicies[subject] = policy
+		}
+		reg.SetLintPolicies(cfg.Linting.Rules, contextPolicies, subjectPolicies)
+		logger.Info("lint policies configured",
+			slog.Int("rules", len(cfg.Linting.Rules)),
+			slog.Int("context_policies", len(contextPolicies)),
+			slog.Int("subject_policies", len(subjectPolicies)),
+		)
+	}
+
+	// Wire the contexts whose developer registrations need approval.
+	if len(cfg.Approval.Contexts) > 0 {
+		reg.SetApprovalContexts(cfg.Approval.Contexts)
+		logger.Info("schema approval required",
+			slog.Any("contexts", cfg.Approval.Contexts),
+		)
+	}
+	if len(cfg.Approval.Reviewers) > 0 {
+		reg.SetApprovalReviewers(cfg.Approval.Reviewers)
+		logger.Info("schema approval reviewers configured",
+			slog.Int("contexts", len(cfg.Approval.Reviewers)),
+		)
+	}
+
+	// Wire whether writes create the contexts they name.
+	if cfg.Contexts.AutoCreate != nil && !*cfg.Contexts.AutoCreate {
+		reg.SetContextAutoCreate(false, cfg.Contexts.Allowed)
+		logger.Info("contexts are not created on write",
+			slog.Any("allowed", cfg.Contexts.Allowed),
+		)
+	}
+
+	// Wire two-phase permanent deletes.
+	if cfg.Deletes.RequireConfirmation {
+		ttl := registry.DefaultDeleteConfirmationTTL
+		if cfg.Deletes.ConfirmationTTL != "" {
+			ttl, _ = config.ParseDuration(cfg.Deletes.ConfirmationTTL) // validated by config.Load
+		}
+		reg.SetDeleteConfirmation(ttl)
+		logger.Info("permanent deletes require confirmation",
+			slog.Duration("ttl", ttl),
+		)
+	}
+
+	// Wire registration quotas.
+	if len(cfg.Quotas.Rules) > 0 {
+		rules := make([]registry.QuotaRule, len(cfg.Quotas.Rules))
+		for i, rule := range cfg.Quotas.Rules {
+			rules[i] = registry.QuotaRule{
+				Name:                  rule.Name,
+				Principals:            rule.Principals,
+				Roles:                 rule.Roles,
+				Contexts:              rule.Contexts,
+				MaxVersionsPerDay:     rule.MaxVersionsPerDay,
+				MaxSubjectsPerContext: rule.MaxSubjectsPerContext,
+			}
+		}
+		reg.SetQuotas(rules)
+		logger.Info("registration quotas configured",
+			slog.Int("rules", len(rules)),
+		)
+	}
+
+	// Refuse writes while the write gate is closed.
+	if writeGate != nil {
+		reg.SetWriteGate(writeGate)
+	}
+
 	// Create server options
 	var serverOpts []api.ServerOption
+	var grpcOpts []grpcapi.Option
+	var mcpOpts []mcpkg.Option
 	serverOpts = append(serverOpts, api.WithBuildInfo(version, commit))
 	serverOpts = append(serverOpts, api.WithMetrics(m))
+	if kmsReg != nil {
+		serverOpts = append(serverOpts, api.WithHealthProbe("kms", kmsReg.HealthCheck))
+	}
 
 	// Create audit logger if enabled
 	var auditLogger *auth.AuditLogger
@@ -168,6 +408,7 @@ func main() {
 			os.Exit(1)
 		}
 		serverOpts = append(serverOpts, api.WithTLS(tlsConfig, tlsManager))
+		grpcOpts = append(grpcOpts, grpcapi.WithTLSConfig(tlsConfig))
 		logger.Info("TLS enabled",
 			slog.String("min_version", cfg.Security.TLS.MinVersion),
 			slog.String("client_auth", cfg.Security.TLS.ClientAuth),
@@ -217,15 +458,18 @@ func main() {
 		}
 	}
 
+	var authenticator *auth.Authenticator
 	var authService *auth.Service
+	var jwtProvider *auth.JWTProvider
 	var vaultStore *vault.Store
+	authMirrorStop := make(chan struct{})
 
 	// Setup authentication if enabled
 	if cfg.Security.Auth.Enabled {
 		logger.Info("authentication enabled", slog.Any("methods", cfg.Security.Auth.Methods))
 
 		// Create authenticator and authorizer
-		authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+		authenticator = auth.NewAuthenticator(cfg.Security.Auth)
 		authorizer := auth.NewAuthorizer(cfg.Security.Auth.RBAC)
 
 		// Determine which auth storage backend to use
@@ -261,19 +505,66 @@ func main() {
 				os.Exit(1)
 			}
 			authStorage = vaultStore
+
+			// Mirror users and API keys, without credentials, into the
+			// database that holds the schemas.
+			if cfg.Storage.Vault.DualWrite {
+				mirror := storage.NewDualWriteAuthStorage(vaultStore, store, logger)
+				mirror.RunOnce(context.Background())
+				if cfg.Storage.Vault.ConsistencyCheckInterval != "" {
+					interval, _ := config.ParseDuration(cfg.Storage.Vault.ConsistencyCheckInterval) // validated by config.Load
+					mirror.Start(interval, authMirrorStop)
+				}
+				logger.Info("auth dual-write to the database enabled",
+					slog.String("consistency_check_interval", cfg.Storage.Vault.ConsistencyCheckInterval),
+				)
+				authStorage = mirror
+			}
 		default:
 			// Use the main storage backend for auth (database-backed)
 			authStorage = store
 		}
 
+		// Login sessions exchange a password or API key for short-lived
+		// access tokens signed by the registry.
+		var sessionSigner *auth.SessionSigner
+		if cfg.Security.Auth.Sessions.Enabled {
+			var err error
+			sessionSigner, err = auth.NewSessionSigner(cfg.Security.Auth.Sessions)
+			if err != nil {
+				logger.Error("failed to configure login sessions", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+			logger.Info("login sessions enabled",
+				slog.String("algorithm", cfg.Security.Auth.Sessions.Algorithm),
+				slog.String("access_ttl", cfg.Security.Auth.Sessions.AccessTTL),
+				slog.String("refresh_ttl", cfg.Security.Auth.Sessions.RefreshTTL),
+			)
+		}
+
 		// Create auth service with secure API key configuration.
 		// UserCacheTTL defaults to 60s to reduce database load for frequently
-		// authenticating users. CacheRefreshInterval ensures cluster consistency.
+		// authenticating users. CacheRefreshInterval ensures cluster consistency,
+		// and the consistency check reports any drift the refresh missed.
 		authService = auth.NewServiceWithConfig(authStorage, auth.ServiceConfig{
-			APIKeySecret:         cfg.Security.Auth.APIKey.Secret,
-			APIKeyPrefix:         cfg.Security.Auth.APIKey.KeyPrefix,
-			CacheRefreshInterval: time.Duration(cfg.Security.Auth.APIKey.CacheRefreshSeconds) * time.Second,
-			UserCacheTTL:         auth.DefaultUserCacheTTL,
+			APIKeySecret:             cfg.Security.Auth.APIKey.Secret,
+			APIKeyPrefix:             cfg.Security.Auth.APIKey.KeyPrefix,
+			CacheRefreshInterval:     time.Duration(cfg.Security.Auth.APIKey.CacheRefreshSeconds) * time.Second,
+			UserCacheTTL:             auth.DefaultUserCacheTTL,
+			ConsistencyCheckInterval: time.Duration(cfg.Security.Auth.APIKey.ConsistencyCheckSeconds) * time.Second,
+			APIKeyUsageFlushInterval: time.Duration(cfg.Security.Auth.APIKey.UsageFlushSeconds) * time.Second,
+			PasswordPolicy: auth.PasswordPolicy{
+				MinLength:        cfg.Security.Auth.PasswordPolicy.MinLength,
+				RequireUppercase: cfg.Security.Auth.PasswordPolicy.RequireUppercase,
+				RequireLowercase: cfg.Security.Auth.PasswordPolicy.RequireLowercase,
+				RequireDigit:     cfg.Security.Auth.PasswordPolicy.RequireDigit,
+				RequireSymbol:    cfg.Security.Auth.PasswordPolicy.RequireSymbol,
+				BannedPasswords:  cfg.Security.Auth.PasswordPolicy.BannedPasswords,
+				MaxAge:           time.Duration(cfg.Security.Auth.PasswordPolicy.ExpiryDays) * 24 * time.Hour,
+				HistorySize:      cfg.Security.Auth.PasswordPolicy.HistorySize,
+			},
+			BootstrapPasswordChange: cfg.Security.Auth.Bootstrap.RequirePasswordChange,
+			Sessions:                sessionSigner,
 		})
 
 		// Wire metrics to auth service for cache metrics
@@ -282,6 +573,20 @@ func main() {
 		// Wire the service to the authenticator for database-backed auth
 		authenticator.SetService(authService)
 
+		// Enforce scoped role grants stored alongside users and API keys
+		authorizer.SetGrantProvider(authService)
+
+		// Delegate authorization decisions to an external policy endpoint
+		if cfg.Security.Auth.RBAC.Policy.URL != "" {
+			policy, err := auth.NewHTTPPolicy(cfg.Security.Auth.RBAC.Policy)
+			if err != nil {
+				logger.Error("failed to configure authorization policy", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+			authorizer.SetPolicy(policy)
+			logger.Info("external authorization policy enabled", slog.String("url", cfg.Security.Auth.RBAC.Policy.URL))
+		}
+
 		// Bootstrap initial admin user if enabled
 		if cfg.Security.Auth.Bootstrap.Enabled {
 			logger.Info("bootstrap enabled, checking for initial admin user")
@@ -319,6 +624,7 @@ func main() {
 				os.Exit(1)
 			}
 			authenticator.SetLDAPProvider(ldapProvider)
+			serverOpts = append(serverOpts, api.WithHealthProbe("ldap", authenticator.PingLDAP))
 			if auditLogger != nil {
 				authenticator.SetAuditLogger(auditLogger)
 			}
@@ -362,15 +668,18 @@ func main() {
 				os.Exit(1)
 			}
 			authenticator.SetOIDCProvider(oidcProvider)
+			serverOpts = append(serverOpts, api.WithHealthProbe("oidc", authenticator.PingOIDC))
 		}
 
 		// Setup JWT provider if configured
-		if cfg.Security.Auth.JWT.PublicKeyFile != "" || cfg.Security.Auth.JWT.JWKSURL != "" {
-			logger.Info("JWT authentication enabled",
-				slog.String("algorithm", cfg.Security.Auth.JWT.Algorithm),
-				slog.String("issuer", cfg.Security.Auth.JWT.Issuer),
-			)
-			jwtProvider, err := auth.NewJWTProvider(cfg.Security.Auth.JWT)
+		if issuers := cfg.Security.Auth.JWT.TrustedIssuers(); len(issuers) > 0 {
+			names := make([]string, len(issuers))
+			for i, iss := range issuers {
+				names[i] = iss.Issuer
+			}
+			logger.Info("JWT authentication enabled", slog.Any("issuers", names))
+			var err error
+			jwtProvider, err = auth.NewJWTProvider(cfg.Security.Auth.JWT)
 			if err != nil {
 				logger.Error("failed to create JWT provider", slog.String("error", err.Error()))
 				os.Exit(1)
@@ -378,6 +687,20 @@ func main() {
 			authenticator.SetJWTProvider(jwtProvider)
 		}
 
+		// Setup client certificate identity mapping if mtls is an auth method
+		if slices.Contains(cfg.Security.Auth.Methods, "mtls") {
+			mtlsProvider, err := auth.NewMTLSProvider(cfg.Security.Auth.MTLS)
+			if err != nil {
+				logger.Error("failed to create mTLS provider", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+			authenticator.SetMTLSProvider(mtlsProvider)
+			logger.Info("mTLS client certificate authentication enabled",
+				slog.Int("user_mappings", len(cfg.Security.Auth.MTLS.UserMapping)),
+				slog.Int("role_rules", len(cfg.Security.Auth.MTLS.RoleRules)),
+			)
+		}
+
 		// Load config-defined API keys if storage_type is "memory"
 		if strings.EqualFold(cfg.Security.Auth.APIKey.StorageType, "memory") {
 			memAPIKeys, err := auth.NewMemoryAPIKeyStore(cfg.Security.Auth.APIKey.Keys)
@@ -402,19 +725,63 @@ func main() {
 
 		// Add auth option
 		serverOpts = append(serverOpts, api.WithAuth(authenticator, authorizer, authService))
+		grpcOpts = append(grpcOpts, grpcapi.WithAuth(authenticator, authorizer))
+		if cfg.MCP.RegistryAuth {
+			mcpOpts = append(mcpOpts, mcpkg.WithAuth(authenticator, authorizer))
+		}
 	}
 
-	// Create rate limiter if enabled
-	var rateLimiter *auth.RateLimiter
+	// Create the rate limiter. It is installed even when disabled, so that a
+	// configuration reload can enable it.
+	rateLimiter := auth.NewRateLimiter(cfg.Security.RateLimiting)
+	serverOpts = append(serverOpts, api.WithRateLimiter(rateLimiter))
 	if cfg.Security.RateLimiting.Enabled {
-		rateLimiter = auth.NewRateLimiter(cfg.Security.RateLimiting)
-		serverOpts = append(serverOpts, api.WithRateLimiter(rateLimiter))
 		logger.Info("rate limiting enabled",
 			slog.Int("requests_per_second", cfg.Security.RateLimiting.RequestsPerSecond),
 			slog.Int("burst_size", cfg.Security.RateLimiting.BurstSize),
 		)
 	}
 
+	// Create the async job manager. Operations that run in the background
+	// register their job types on it; it is started below with the other
+	// background workers.
+	jobRetention, _ := config.ParseDuration(cfg.Jobs.Retention) // validated by config.Load
+	jobManager := jobs.NewManager(registryStore, logger,
+		jobs.WithWorkers(cfg.Jobs.Workers),
+		jobs.WithQueueSize(cfg.Jobs.QueueSize),
+		jobs.WithRetention(jobRetention),
+	)
+	revalidation.Register(jobManager, reg)
+	consistency.Register(jobManager, reg)
+	reencryption.Register(jobManager, reg)
+	serverOpts = append(serverOpts, api.WithJobManager(jobManager))
+
+	// Count schema fetches if usage analytics are enabled.
+	var usageTracker *usage.Tracker
+	if cfg.Usage.Enabled {
+		usageTracker = usage.NewTracker(registryStore, logger)
+		serverOpts = append(serverOpts, api.WithUsageTracker(usageTracker))
+	}
+
+	// Count requests per client library if client analytics are enabled.
+	if cfg.Usage.TrackClients {
+		serverOpts = append(serverOpts, api.WithClientTracker(usage.NewClientTracker(usage.DefaultMaxClientEntries)))
+		logger.Info("client tracking enabled")
+	}
+
+	// Reload the configuration file on SIGHUP and POST /admin/config/reload.
+	reloader := &configReloader{
+		path:          *configPath,
+		logger:        logger,
+		logLevel:      configLogLevel,
+		registry:      reg,
+		rateLimiter:   rateLimiter,
+		authenticator: authenticator,
+		cfg:           cfg,
+		jwtProvider:   jwtProvider,
+	}
+	serverOpts = append(serverOpts, api.WithConfigReloader(reloader))
+
 	// Create and start the HTTP server
 	server := api.NewServer(cfg, reg, logger, serverOpts...)
 
@@ -432,11 +799,88 @@ func main() {
 	gaugeStop := make(chan struct{})
 	m.StartGaugeRefresh(reg, gaugeRefreshInterval, gaugeStop)
 	logger.Info("gauge metrics refresh started", slog.Duration("interval", gaugeRefreshInterval))
+	m.SetMaintenanceSource(reg.MaintenanceActive)
+	if fallbackStore != nil {
+		m.SetStorageDegradedSource(fallbackStore.Degraded)
+	}
+	if writeGate != nil {
+		m.SetWriteGateSource(writeGate.Closed)
+	}
+
+	// Start the async job workers.
+	jobsStop := make(chan struct{})
+	jobManager.Start(jobsStop)
+	logger.Info("async job workers started", slog.Int("workers", cfg.Jobs.Workers))
+
+	// Start flushing schema usage counts to storage.
+	usageStop := make(chan struct{})
+	if usageTracker != nil {
+		flushInterval := time.Minute
+		if cfg.Usage.FlushInterval != "" {
+			flushInterval, _ = config.ParseDuration(cfg.Usage.FlushInterval) // validated by config.Load
+		}
+		usageTracker.Start(flushInterval, usageStop)
+		logger.Info("schema usage tracking enabled", slog.Duration("flush_interval", flushInterval))
+	}
+
+	// Start the exporter replication worker (schema linking) if enabled.
+	replicatorStop := make(chan struct{})
+	if cfg.Exporters.Enabled {
+		pollInterval := time.Duration(cfg.Exporters.PollInterval) * time.Second
+		if pollInterval <= 0 {
+			pollInterval = 10 * time.Second
+		}
+		requestTimeout := time.Duration(cfg.Exporters.RequestTimeout) * time.Second
+		if requestTimeout <= 0 {
+			requestTimeout = 30 * time.Second
+		}
+		replicator := exporter.NewReplicator(registryStore, logger,
+			exporter.WithMetrics(m),
+			exporter.WithClusterID(cfg.Server.ClusterID),
+			exporter.WithHTTPClient(&http.Client{Timeout: requestTimeout}),
+		)
+		replicator.Start(pollInterval, replicatorStop)
+		logger.Info("exporter replication enabled", slog.Duration("poll_interval", pollInterval))
+	}
+
+	// Start the soft-delete purge worker if a retention window is configured.
+	purgerStop := make(chan struct{})
+	if cfg.Storage.SoftDeleteRetention != "" {
+		retentionWindow, _ := config.ParseDuration(cfg.Storage.SoftDeleteRetention) // validated by config.Load
+		purgeInterval := time.Hour
+		if cfg.Storage.SoftDeletePurgeInterval != "" {
+			purgeInterval, _ = config.ParseDuration(cfg.Storage.SoftDeletePurgeInterval)
+		}
+		var purgerOpts []retention.PurgerOption
+		if auditLogger != nil {
+			purgerOpts = append(purgerOpts, retention.WithAuditLogger(auditLogger))
+		}
+		purger := retention.NewPurger(reg, retentionWindow, logger, purgerOpts...)
+		purger.Start(purgeInterval, purgerStop)
+		logger.Info("soft-delete purge enabled",
+			slog.Duration("retention", retentionWindow),
+			slog.Duration("interval", purgeInterval),
+		)
+	}
+
+	// Start the periodic storage consistency check if an interval is configured.
+	consistencyStop := make(chan struct{})
+	if cfg.Consistency.CheckInterval != "" {
+		checkInterval, _ := config.ParseDuration(cfg.Consistency.CheckInterval) // validated by config.Load
+		consistency.NewChecker(reg, cfg.Consistency.Repair, logger).Start(checkInterval, consistencyStop)
+		logger.Info("storage consistency check enabled",
+			slog.Duration("interval", checkInterval),
+			slog.Bool("repair", cfg.Consistency.Repair),
+		)
+	}
 
 	// Create and start the MCP server if enabled
 	var mcpServer *mcpkg.Server
 	if cfg.MCP.Enabled {
-		var mcpOpts []mcpkg.Option
+		if cfg.MCP.RegistryAuth && !cfg.Security.Auth.Enabled {
+			logger.Error("mcp.registry_auth requires security.auth.enabled")
+			os.Exit(1)
+		}
 		if authService != nil {
 			mcpOpts = append(mcpOpts, mcpkg.WithAuthService(authService))
 		}
@@ -451,6 +895,17 @@ func main() {
 		mcpServer = mcpkg.New(&cfg.MCP, reg, logger, version, mcpOpts...)
 	}
 
+	// Create the gRPC server if enabled. It shares the registry, credentials
+	// and TLS certificates of the HTTP server.
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		grpcOpts = append(grpcOpts, grpcapi.WithMetrics(server.Metrics()))
+		if cfg.Tenancy.Enabled && cfg.Tenancy.ContextMode == "principal" {
+			grpcOpts = append(grpcOpts, grpcapi.WithTenantContexts(cfg.Tenancy.OverrideHeader))
+		}
+		grpcServer = grpcapi.New(&cfg.GRPC, reg, logger, grpcOpts...)
+	}
+
 	// Handle shutdown and reload signals
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
@@ -458,7 +913,7 @@ func main() {
 	reload := make(chan os.Signal, 1)
 	signal.Notify(reload, syscall.SIGHUP)
 
-	// Handle SIGHUP for TLS certificate reload
+	// Handle SIGHUP for TLS certificate and configuration reload
 	go func() {
 		for range reload {
 			logger.Info("received SIGHUP, reloading TLS certificates")
@@ -467,6 +922,35 @@ func main() {
 			} else {
 				logger.Info("TLS certificates reloaded successfully")
 			}
+
+			logger.Info("received SIGHUP, reloading configuration", slog.String("path", *configPath))
+			applied, restartRequired, err := reloader.ReloadConfig(context.Background())
+			if err != nil {
+				logger.Error("configuration reload failed, keeping the running configuration",
+					slog.String("error", err.Error()))
+			}
+			if auditLogger != nil {
+				event := &auth.AuditEvent{
+					EventType:  auth.AuditEventConfigReload,
+					Timestamp:  time.Now(),
+					Method:     "SIGHUP",
+					ActorID:    "system",
+					ActorType:  "system",
+					Outcome:    "success",
+					TargetType: "server",
+					TargetID:   "config",
+					Metadata: map[string]string{
+						"applied":          strings.Join(applied, ","),
+						"restart_required": strings.Join(restartRequired, ","),
+					},
+				}
+				if err != nil {
+					event.Outcome = "failure"
+					event.Reason = "invalid_config"
+					event.Metadata = map[string]string{"error": err.Error()}
+				}
+				auditLogger.Log(event)
+			}
 		}
 	}()
 
@@ -487,8 +971,8 @@ func main() {
 		})
 	}
 
-	// Start servers in goroutines — both feed into the same error channel.
-	serverErr := make(chan error, 2)
+	// Start servers in goroutines — all feed into the same error channel.
+	serverErr := make(chan error, 3)
 	go func() {
 		serverErr <- server.Start()
 	}()
@@ -499,6 +983,13 @@ func main() {
 			}
 		}()
 	}
+	if grpcServer != nil {
+		go func() {
+			if err := grpcServer.Start(); err != nil {
+				serverErr <- fmt.Errorf("gRPC server: %w", err)
+			}
+		}()
+	}
 
 	// Wait for shutdown signal or error
 	select {
@@ -518,11 +1009,24 @@ func main() {
 		defer cancel()
 
 		close(gaugeStop)
+		close(replicatorStop)
+		close(purgerStop)
+		close(consistencyStop)
+		close(jobsStop)
+		close(authMirrorStop)
+		close(fallbackStop)
+		close(writeGateStop)
 
 		if err := server.Shutdown(ctx); err != nil {
 			logger.Error("shutdown error", slog.String("error", err.Error()))
 		}
 
+		// Flush the last schema usage counts once no more requests arrive.
+		close(usageStop)
+		if usageTracker != nil {
+			usageTracker.Flush(ctx)
+		}
+
 		// Stop MCP server
 		if mcpServer != nil {
 			if err := mcpServer.Shutdown(ctx); err != nil {
@@ -530,6 +1034,18 @@ func main() {
 			}
 		}
 
+		// Stop gRPC server
+		if grpcServer != nil {
+			if err := grpcServer.Shutdown(ctx); err != nil {
+				logger.Error("gRPC shutdown error", slog.String("error", err.Error()))
+			}
+		}
+
+		// Export the spans of the last requests
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("tracing shutdown error", slog.String("error", err.Error()))
+		}
+
 		// Emit server shutdown audit event before closing the audit logger
 		if auditLogger != nil {
 			auditLogger.Log(&auth.AuditEvent{
@@ -556,6 +1072,9 @@ func main() {
 			authService.Close()
 		}
 
+		// Stop JWKS refresh
+		reloader.close()
+
 		// Close KMS providers
 		if kmsReg != nil {
 			if err := kmsReg.Close(); err != nil {
@@ -580,10 +1099,15 @@ func main() {
 
 // createStorage creates the appropriate storage backend based on configuration.
 func createStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, error) {
+	globalIDs := cfg.IDs.Scope == config.IDScopeGlobal
 	switch cfg.Storage.Type {
 	case "memory":
 		logger.Info("using in-memory storage")
-		return memory.NewStore(), nil
+		var memOpts []memory.Option
+		if globalIDs {
+			memOpts = append(memOpts, memory.WithGlobalIDs())
+		}
+		return memory.NewStore(memOpts...), nil
 
 	case "postgresql", "postgres":
 		logger.Info("connecting to PostgreSQL",
@@ -604,6 +1128,10 @@ func createStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, er
 			ConnectTimeout:     time.Duration(cfg.Storage.PostgreSQL.ConnectTimeout) * time.Second,
 			HealthCheckTimeout: time.Duration(cfg.Storage.PostgreSQL.HealthCheckTimeout) * time.Second,
 			SchemaMaxRetries:   cfg.Storage.PostgreSQL.SchemaMaxRetries,
+
+			DeltaEncoding:         cfg.Storage.PostgreSQL.DeltaEncoding,
+			DeltaSnapshotInterval: cfg.Storage.PostgreSQL.DeltaSnapshotInterval,
+			GlobalIDs:             globalIDs,
 		}
 		if pgCfg.Host == "" {
 			pgCfg.Host = "localhost"
@@ -641,12 +1169,20 @@ func createStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, er
 			Username:           cfg.Storage.MySQL.User,
 			Password:           cfg.Storage.MySQL.Password,
 			TLS:                cfg.Storage.MySQL.TLS,
+			TLSCAFile:          cfg.Storage.MySQL.TLSCAFile,
+			TLSCertFile:        cfg.Storage.MySQL.TLSCertFile,
+			TLSKeyFile:         cfg.Storage.MySQL.TLSKeyFile,
+			TLSServerName:      cfg.Storage.MySQL.TLSServerName,
 			MaxOpenConns:       cfg.Storage.MySQL.MaxOpenConns,
 			MaxIdleConns:       cfg.Storage.MySQL.MaxIdleConns,
 			ConnMaxLifetime:    time.Duration(cfg.Storage.MySQL.ConnMaxLifetime) * time.Second,
 			ConnectTimeout:     time.Duration(cfg.Storage.MySQL.ConnectTimeout) * time.Second,
 			HealthCheckTimeout: time.Duration(cfg.Storage.MySQL.HealthCheckTimeout) * time.Second,
 			SchemaMaxRetries:   cfg.Storage.MySQL.SchemaMaxRetries,
+
+			DeltaEncoding:         cfg.Storage.MySQL.DeltaEncoding,
+			DeltaSnapshotInterval: cfg.Storage.MySQL.DeltaSnapshotInterval,
+			GlobalIDs:             globalIDs,
 		}
 		if mysqlCfg.Host == "" {
 			mysqlCfg.Host = "localhost"
@@ -669,6 +1205,15 @@ func createStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, er
 		if mysqlCfg.ConnMaxLifetime == 0 {
 			mysqlCfg.ConnMaxLifetime = 5 * time.Minute
 		}
+		if cfg.Storage.MySQL.IAMAuth {
+			tokens, err := rdsiam.NewTokenProvider(context.Background(), mysqlCfg.Host, mysqlCfg.Port,
+				mysqlCfg.Username, cfg.Storage.MySQL.IAMRegion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set up MySQL IAM authentication: %w", err)
+			}
+			mysqlCfg.PasswordProvider = tokens.Token
+			logger.Info("using AWS RDS IAM authentication for MySQL")
+		}
 		return mysql.NewStore(mysqlCfg)
 
 	case "cassandra":
@@ -690,7 +1235,26 @@ func createStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, er
 			SerialConsistency: cfg.Storage.Cassandra.SerialConsistency,
 			MaxRetries:        cfg.Storage.Cassandra.MaxRetries,
 			IDBlockSize:       cfg.Storage.Cassandra.IDBlockSize,
-			Migrate:           true,
+			GlobalIDs:         globalIDs,
+			Replication: cassandra.Replication{
+				Strategy:    cfg.Storage.Cassandra.Replication.Strategy,
+				Factor:      cfg.Storage.Cassandra.Replication.Factor,
+				Datacenters: cfg.Storage.Cassandra.Replication.Datacenters,
+			},
+			HostSelectionPolicy: cfg.Storage.Cassandra.HostSelectionPolicy,
+			TLS: cassandra.TLS{
+				Enabled:            cfg.Storage.Cassandra.TLSEnabled,
+				CAFile:             cfg.Storage.Cassandra.TLSCAFile,
+				CertFile:           cfg.Storage.Cassandra.TLSCertFile,
+				KeyFile:            cfg.Storage.Cassandra.TLSKeyFile,
+				ServerName:         cfg.Storage.Cassandra.TLSServerName,
+				InsecureSkipVerify: cfg.Storage.Cassandra.TLSSkipVerify,
+			},
+			Retry: cassandra.Retry{
+				Policy:     cfg.Storage.Cassandra.RetryPolicy,
+				NumRetries: cfg.Storage.Cassandra.QueryRetries,
+			},
+			Migrate: true,
 		}
 		if cfg.Storage.Cassandra.Timeout != "" {
 			d, err := time.ParseDuration(cfg.Storage.Cassandra.Timeout)
@@ -706,6 +1270,20 @@ func createStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, er
 			}
 			cassCfg.ConnectTimeout = d
 		}
+		if cfg.Storage.Cassandra.RetryMinBackoff != "" {
+			d, err := time.ParseDuration(cfg.Storage.Cassandra.RetryMinBackoff)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cassandra retry_min_backoff %q: %w", cfg.Storage.Cassandra.RetryMinBackoff, err)
+			}
+			cassCfg.Retry.MinBackoff = d
+		}
+		if cfg.Storage.Cassandra.RetryMaxBackoff != "" {
+			d, err := time.ParseDuration(cfg.Storage.Cassandra.RetryMaxBackoff)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cassandra retry_max_backoff %q: %w", cfg.Storage.Cassandra.RetryMaxBackoff, err)
+			}
+			cassCfg.Retry.MaxBackoff = d
+		}
 		if len(cassCfg.Hosts) == 0 {
 			cassCfg.Hosts = []string{"localhost"}
 		}
@@ -722,10 +1300,63 @@ func createStorage(cfg *config.Config, logger *slog.Logger) (storage.Storage, er
 	}
 }
 
+// buildNormalizationProfiles converts the normalization configuration into the
+// default profile and the per-context profiles used by the registry.
+func buildNormalizationProfiles(cfg *config.NormalizationConfig) (schema.NormalizationProfile, map[string]schema.NormalizationProfile) {
+	toProfile := func(name string) schema.NormalizationProfile {
+		p := cfg.Profiles[name]
+		return schema.NormalizationProfile{
+			Name:                 name,
+			JSONStripAnnotations: p.JSONStripAnnotations,
+			ProtobufKeepComments: p.ProtobufKeepComments,
+		}
+	}
+
+	defaultProfile := schema.DefaultNormalizationProfile
+	if cfg.DefaultProfile != "" {
+		defaultProfile = toProfile(cfg.DefaultProfile)
+	}
+	contextProfiles := make(map[string]schema.NormalizationProfile, len(cfg.Contexts))
+	for ctxName, profileName := range cfg.Contexts {
+		contextProfiles[ctxName] = toProfile(profileName)
+	}
+	return defaultProfile, contextProfiles
+}
+
 // initKMSRegistry creates a KMS provider registry with available providers.
 // Providers are only registered when their connection environment variables
 // (e.g., VAULT_ADDR/VAULT_TOKEN, BAO_ADDR/BAO_TOKEN) are set.
 // Returns nil if no providers are available.
+// createAtRestKeyring creates the keyring that encrypts schemas at rest with
+// the key encryption key from cfg. tenants, if not nil, keeps sealing the
+// contexts owned by a tenant with the tenant's key.
+func createAtRestKeyring(cfg config.StorageEncryptionConfig, tenants storage.SchemaKeyring) (*atrest.Keyring, error) {
+	atCfg := atrest.Config{Keys: make(map[string][]byte, len(cfg.Keys))}
+	for name, encoded := range cfg.Keys {
+		atCfg.Keys[name], _ = base64.StdEncoding.DecodeString(encoded) // validated by config.Load
+	}
+	if cfg.Vault.KeyName != "" || cfg.Vault.Address != "" {
+		provider, err := vaultkms.NewProvider(vaultkms.Config{
+			Address:      cfg.Vault.Address,
+			Token:        cfg.Vault.Token,
+			Namespace:    cfg.Vault.Namespace,
+			TransitMount: cfg.Vault.TransitMount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		atCfg.Transit = provider
+	}
+	if cfg.Enabled {
+		atCfg.ActiveKey = cfg.ActiveKey
+		atCfg.TransitKey = cfg.Vault.KeyName
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return atrest.NewKeyring(ctx, atCfg, tenants)
+}
+
 func initKMSRegistry(logger *slog.Logger) *kms.Registry {
 	reg := kms.NewRegistry()
 	registered := 0
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// configReloader re-reads the configuration file on SIGHUP or
+// POST /admin/config/reload and applies what can change without a restart:
+// the log level, the default compatibility level, rate limits and, when
+// authentication is enabled, the LDAP, OIDC and JWT providers, the htpasswd
+// file and config-defined API keys. Providers are only replaced when their
+// settings changed, except the htpasswd file, which is always read again.
+// The file is loaded and validated, and the new providers created, before
+// anything is applied, so a bad file or an unreachable provider leaves the
+// server as it was.
+type configReloader struct {
+	path          string
+	logger        *slog.Logger
+	logLevel      *slog.LevelVar
+	registry      *registry.Registry
+	rateLimiter   *auth.RateLimiter
+	authenticator *auth.Authenticator // nil when authentication is disabled
+
+	mu          sync.Mutex
+	cfg         *config.Config    // the configuration in effect
+	jwtProvider *auth.JWTProvider // closed when replaced
+}
+
+// authProviders are the authentication providers built from a reloaded
+// configuration, for the settings listed in changed. A nil provider is not
+// configured.
+type authProviders struct {
+	changed       []string
+	ldap          *auth.LDAPProvider
+	oidc          *auth.OIDCProvider
+	jwt           *auth.JWTProvider
+	memoryAPIKeys *auth.MemoryAPIKeyStore
+	htpasswd      *auth.HTPasswdStore
+}
+
+func (p *authProviders) replaces(key string) bool {
+	return slices.Contains(p.changed, key)
+}
+
+// ReloadConfig reloads the configuration file. It implements
+// handlers.ConfigReloader.
+func (r *configReloader) ReloadConfig(ctx context.Context) (applied, restartRequired []string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := config.Load(r.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	reloaded, applied, restartRequired := r.cfg.Reload(next)
+
+	var providers *authProviders
+	if r.authenticator != nil {
+		if providers, err = newAuthProviders(ctx, reloaded.Security.Auth, applied); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	r.logLevel.Set(parseLogLevel(reloaded.Logging.Level))
+	r.registry.SetDefaultCompatibility(reloaded.Compatibility.DefaultLevel)
+	r.rateLimiter.Update(reloaded.Security.RateLimiting)
+	if providers != nil {
+		if providers.replaces("security.auth.ldap") {
+			r.authenticator.SetLDAPProvider(providers.ldap)
+		}
+		if providers.replaces("security.auth.oidc") {
+			r.authenticator.SetOIDCProvider(providers.oidc)
+		}
+		if providers.replaces("security.auth.jwt") {
+			r.authenticator.SetJWTProvider(providers.jwt)
+			if r.jwtProvider != nil {
+				r.jwtProvider.Close()
+			}
+			r.jwtProvider = providers.jwt
+		}
+		if providers.replaces("security.auth.api_key.keys") {
+			r.authenticator.SetMemoryAPIKeyStore(providers.memoryAPIKeys)
+		}
+		r.authenticator.SetHTPasswdStore(providers.htpasswd)
+	}
+	r.cfg = reloaded
+
+	r.logger.Info("configuration reloaded",
+		slog.String("path", r.path),
+		slog.Any("applied", applied),
+		slog.Any("restart_required", restartRequired),
+	)
+	if len(restartRequired) > 0 {
+		r.logger.Warn("configuration changes need a restart to take effect",
+			slog.Any("sections", restartRequired),
+		)
+	}
+	return applied, restartRequired, nil
+}
+
+// close stops the background work of the current providers.
+func (r *configReloader) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.jwtProvider != nil {
+		r.jwtProvider.Close()
+		r.jwtProvider = nil
+	}
+}
+
+// newAuthProviders creates the providers configured in cfg whose settings
+// are listed in changed, and reads the htpasswd file.
+func newAuthProviders(ctx context.Context, cfg config.AuthConfig, changed []string) (*authProviders, error) {
+	p := &authProviders{changed: changed}
+	var err error
+	if cfg.LDAP.Enabled && p.replaces("security.auth.ldap") {
+		if p.ldap, err = auth.NewLDAPProvider(cfg.LDAP); err != nil {
+			return nil, fmt.Errorf("failed to create LDAP provider: %w", err)
+		}
+	}
+	if cfg.OIDC.Enabled && p.replaces("security.auth.oidc") {
+		if p.oidc, err = auth.NewOIDCProvider(ctx, cfg.OIDC); err != nil {
+			return nil, fmt.Errorf("failed to create OIDC provider: %w", err)
+		}
+	}
+	if strings.EqualFold(cfg.APIKey.StorageType, "memory") && p.replaces("security.auth.api_key.keys") {
+		if p.memoryAPIKeys, err = auth.NewMemoryAPIKeyStore(cfg.APIKey.Keys); err != nil {
+			return nil, fmt.Errorf("failed to load config-defined API keys: %w", err)
+		}
+	}
+	if cfg.Basic.HTPasswd != "" {
+		if p.htpasswd, err = auth.LoadHTPasswdFile(cfg.Basic.HTPasswd); err != nil {
+			return nil, fmt.Errorf("failed to load htpasswd file: %w", err)
+		}
+	}
+	// Created last: it starts refreshing its keys, which would have to be
+	// stopped if a later step failed.
+	if len(cfg.JWT.TrustedIssuers()) > 0 && p.replaces("security.auth.jwt") {
+		if p.jwt, err = auth.NewJWTProvider(cfg.JWT); err != nil {
+			return nil, fmt.Errorf("failed to create JWT provider: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// parseLogLevel returns the slog level for logging.level. Unknown levels
+// are info.
+func parseLogLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import "sort"
+
+// Field change kinds reported by DiffFields.
+const (
+	ChangeAdded           = "added"
+	ChangeRemoved         = "removed"
+	ChangeTypeChanged     = "type_changed"
+	ChangeDocChanged      = "doc_changed"
+	ChangeRequiredChanged = "required_changed"
+	ChangeDefaultChanged  = "default_changed"
+)
+
+// FieldChange describes one difference between two versions of a field.
+// Old* values come from the first schema, New* values from the second.
+type FieldChange struct {
+	Field       string `json:"field"`
+	Change      string `json:"change"`
+	OldType     string `json:"old_type,omitempty"`
+	NewType     string `json:"new_type,omitempty"`
+	OldDoc      string `json:"old_doc,omitempty"`
+	NewDoc      string `json:"new_doc,omitempty"`
+	OldRequired *bool  `json:"old_required,omitempty"`
+	NewRequired *bool  `json:"new_required,omitempty"`
+}
+
+// DiffSummary counts the changes in a diff by kind.
+type DiffSummary struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+// SchemaDiff is a structural diff between two schemas.
+type SchemaDiff struct {
+	Changes []FieldChange `json:"changes"`
+	Summary DiffSummary   `json:"summary"`
+}
+
+// DiffFields compares the fields extracted from two schemas, matching them by
+// path. A field present in both schemas can produce several changes (for
+// example a type change and a doc change). Changes are sorted by field path,
+// then by kind.
+func DiffFields(from, to []FieldInfo) *SchemaDiff {
+	fromMap := make(map[string]FieldInfo, len(from))
+	for _, f := range from {
+		fromMap[f.Path] = f
+	}
+	toMap := make(map[string]FieldInfo, len(to))
+	for _, f := range to {
+		toMap[f.Path] = f
+	}
+
+	diff := &SchemaDiff{Changes: []FieldChange{}}
+	for path, oldField := range fromMap {
+		newField, ok := toMap[path]
+		if !ok {
+			diff.Changes = append(diff.Changes, FieldChange{Field: path, Change: ChangeRemoved, OldType: oldField.Type, OldDoc: oldField.Doc})
+			diff.Summary.Removed++
+			continue
+		}
+		changed := false
+		if oldField.Type != newField.Type {
+			diff.Changes = append(diff.Changes, FieldChange{Field: path, Change: ChangeTypeChanged, OldType: oldField.Type, NewType: newField.Type})
+			changed = true
+		}
+		if oldField.Doc != newField.Doc {
+			diff.Changes = append(diff.Changes, FieldChange{Field: path, Change: ChangeDocChanged, OldDoc: oldField.Doc, NewDoc: newField.Doc})
+			changed = true
+		}
+		if oldField.Required != newField.Required {
+			oldRequired, newRequired := oldField.Required, newField.Required
+			diff.Changes = append(diff.Changes, FieldChange{Field: path, Change: ChangeRequiredChanged, OldRequired: &oldRequired, NewRequired: &newRequired})
+			changed = true
+		}
+		if oldField.HasDefault != newField.HasDefault {
+			diff.Changes = append(diff.Changes, FieldChange{Field: path, Change: ChangeDefaultChanged})
+			changed = true
+		}
+		if changed {
+			diff.Summary.Changed++
+		}
+	}
+	for path, newField := range toMap {
+		if _, ok := fromMap[path]; !ok {
+			diff.Changes = append(diff.Changes, FieldChange{Field: path, Change: ChangeAdded, NewType: newField.Type, NewDoc: newField.Doc})
+			diff.Summary.Added++
+		}
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		if diff.Changes[i].Field != diff.Changes[j].Field {
+			return diff.Changes[i].Field < diff.Changes[j].Field
+		}
+		return diff.Changes[i].Change < diff.Changes[j].Change
+	})
+	return diff
+}
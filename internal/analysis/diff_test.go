@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func TestDiffFields_Avro(t *testing.T) {
+	v1 := `{"type":"record","name":"Order","fields":[
+		{"name":"id","type":"int"},
+		{"name":"note","type":"string","doc":"Free text"},
+		{"name":"legacy","type":"string"}
+	]}`
+	v2 := `{"type":"record","name":"Order","fields":[
+		{"name":"id","type":"long"},
+		{"name":"note","type":["null","string"],"default":null,"doc":"Customer note"},
+		{"name":"status","type":"string"}
+	]}`
+
+	diff := DiffFields(ExtractFields(v1, storage.SchemaTypeAvro), ExtractFields(v2, storage.SchemaTypeAvro))
+
+	want := []struct{ field, change string }{
+		{"id", ChangeTypeChanged},
+		{"legacy", ChangeRemoved},
+		{"note", ChangeDefaultChanged},
+		{"note", ChangeDocChanged},
+		{"note", ChangeRequiredChanged},
+		{"note", ChangeTypeChanged},
+		{"status", ChangeAdded},
+	}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), diff.Changes)
+	}
+	for i, w := range want {
+		if diff.Changes[i].Field != w.field || diff.Changes[i].Change != w.change {
+			t.Errorf("change %d: expected %s %s, got %s %s", i, w.field, w.change, diff.Changes[i].Field, diff.Changes[i].Change)
+		}
+	}
+	if diff.Changes[0].OldType != "int" || diff.Changes[0].NewType != "long" {
+		t.Errorf("expected int -> long, got %s -> %s", diff.Changes[0].OldType, diff.Changes[0].NewType)
+	}
+	if diff.Summary != (DiffSummary{Added: 1, Removed: 1, Changed: 2}) {
+		t.Errorf("unexpected summary %+v", diff.Summary)
+	}
+}
+
+func TestDiffFields_JSONSchemaDescription(t *testing.T) {
+	v1 := `{"type":"object","properties":{"name":{"type":"string","description":"Full name"}}}`
+	v2 := `{"type":"object","properties":{"name":{"type":"string","description":"Display name"}}}`
+
+	diff := DiffFields(ExtractFields(v1, storage.SchemaTypeJSON), ExtractFields(v2, storage.SchemaTypeJSON))
+	if len(diff.Changes) != 1 || diff.Changes[0].Change != ChangeDocChanged {
+		t.Fatalf("expected a single doc change, got %+v", diff.Changes)
+	}
+	if diff.Changes[0].OldDoc != "Full name" || diff.Changes[0].NewDoc != "Display name" {
+		t.Errorf("unexpected docs %q -> %q", diff.Changes[0].OldDoc, diff.Changes[0].NewDoc)
+	}
+}
+
+func TestDiffFields_Identical(t *testing.T) {
+	schema := `syntax = "proto3";
+message User {
+  string name = 1;
+  int32 age = 2;
+}`
+	fields := ExtractFields(schema, storage.SchemaTypeProtobuf)
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %+v", fields)
+	}
+	diff := DiffFields(fields, fields)
+	if len(diff.Changes) != 0 {
+		t.Errorf("expected no changes, got %+v", diff.Changes)
+	}
+}
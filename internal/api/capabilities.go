@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+)
+
+// capabilities describes what this server supports, for GET /capabilities.
+// It only depends on configuration and the wired-in components, so it is
+// built once when the router is set up.
+func (s *Server) capabilities() types.CapabilitiesResponse {
+	authCfg := s.config.Security.Auth
+	methods := []string{}
+	if authCfg.Enabled {
+		methods = append(methods, authCfg.Methods...)
+	}
+
+	profiles := make([]string, 0, len(s.config.Normalization.Profiles))
+	for name := range s.config.Normalization.Profiles {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+
+	return types.CapabilitiesResponse{
+		Version:     s.version,
+		SchemaTypes: s.registry.GetSchemaTypes(),
+		Features: types.CapabilityFeatures{
+			Contexts:       true,
+			ImportMode:     true,
+			Metadata:       true,
+			RuleSet:        true,
+			Normalization:  true,
+			DataEncryption: true,
+			Exporters:      true,
+			Jobs:           s.jobs != nil,
+			UsageTracking:  s.usage != nil,
+			Tenancy:        s.config.Tenancy.Enabled,
+			FieldSelection: true,
+			SchemaAnalysis: true,
+		},
+		Auth: types.AuthCapabilities{
+			Enabled: authCfg.Enabled,
+			Methods: methods,
+			RBAC:    authCfg.Enabled && authCfg.RBAC.Enabled,
+		},
+		Normalization: types.NormalizationCapability{
+			Profiles:       profiles,
+			DefaultProfile: s.config.Normalization.DefaultProfile,
+		},
+		Pagination: types.PaginationCapability{
+			OffsetLimit:         true,
+			MaxVersionsPageSize: s.config.Server.MaxVersionsPageSize,
+			MaxListPageSize:     s.config.Server.MaxListPageSize,
+			LinkHeader:          true,
+		},
+	}
+}
+
+// capabilitiesHandler serves GET /capabilities. It is public so that clients
+// can discover the supported authentication methods before authenticating.
+func capabilitiesHandler(caps types.CapabilitiesResponse) http.HandlerFunc {
+	body, err := json.Marshal(caps)
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if err != nil {
+			http.Error(w, "failed to encode capabilities", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		w.Write(body) //nolint:errcheck
+	}
+}
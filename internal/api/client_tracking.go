@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/usage"
+)
+
+// clientTrackingMiddleware counts each request by the client library in its
+// User-Agent, the matched route and the authenticated principal. It must run
+// after the authentication middleware so the principal is known.
+func clientTrackingMiddleware(t *usage.ClientTracker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			// The route pattern is only complete once routing has finished.
+			endpoint := r.URL.Path
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					endpoint = pattern
+				}
+			}
+			principal := ""
+			if user := auth.GetUser(r.Context()); user != nil {
+				principal = user.Username
+			}
+			t.Record(r.UserAgent(), r.Method, endpoint, principal)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+	"github.com/axonops/axonops-schema-registry/internal/usage"
+)
+
+func TestServer_ClientTracking(t *testing.T) {
+	cfg := config.DefaultConfig()
+	reg := registry.New(memory.NewStore(), schema.NewRegistry(), compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	tracker := usage.NewClientTracker(0)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithClientTracker(tracker))
+
+	for _, path := range []string{"/subjects", "/subjects/orders/versions", "/contexts/.team/subjects/orders/versions", "/"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("User-Agent", "confluent-kafka-go/2.3.0")
+		server.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	got := endpoints(tracker.Query("", time.Time{}))
+	want := []string{
+		"/contexts/{context}/subjects/{subject}/versions",
+		"/subjects",
+		"/subjects/{subject}/versions",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("endpoints = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("endpoints = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+// endpoints returns the endpoints called by confluent-kafka-go 2.3.0.
+func endpoints(entries []usage.ClientUsage) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Client != "confluent-kafka-go" || e.Version != "2.3.0" {
+			continue
+		}
+		out = append(out, e.Endpoint)
+	}
+	return out
+}
@@ -1,7 +1,12 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"sync"
+
+	"gopkg.in/yaml.v3"
 
 	openapispec "github.com/axonops/axonops-schema-registry/api"
 )
@@ -51,3 +56,50 @@ func handleOpenAPISpec(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Cache-Control", "public, max-age=3600")
 	w.Write(openapispec.OpenAPISpec) //nolint:errcheck
 }
+
+// openAPIJSON is the embedded OpenAPI specification converted to JSON on
+// first use, for code generators that do not read YAML.
+var openAPIJSON = sync.OnceValues(func() ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(openapispec.OpenAPISpec, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonCompatible(doc))
+})
+
+// handleOpenAPIJSON serves the embedded OpenAPI specification as JSON.
+func handleOpenAPIJSON(w http.ResponseWriter, _ *http.Request) {
+	spec, err := openAPIJSON()
+	if err != nil {
+		http.Error(w, "failed to convert OpenAPI specification", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write(spec) //nolint:errcheck
+}
+
+// jsonCompatible converts YAML mappings with non-string keys, such as
+// unquoted response codes, into maps that encoding/json can marshal.
+func jsonCompatible(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = jsonCompatible(val)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = jsonCompatible(val)
+		}
+		return m
+	case []any:
+		for i, val := range v {
+			v[i] = jsonCompatible(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
@@ -15,6 +19,7 @@ import (
 // AccountHandler provides HTTP handlers for self-service account operations.
 type AccountHandler struct {
 	authService *auth.Service
+	authorizer  *auth.Authorizer
 }
 
 // NewAccountHandler creates a new AccountHandler.
@@ -24,6 +29,12 @@ func NewAccountHandler(authService *auth.Service) *AccountHandler {
 	}
 }
 
+// SetAuthorizer makes the account endpoints resolve permissions with the
+// authorizer, so that configured super admins and the default role count.
+func (h *AccountHandler) SetAuthorizer(a *auth.Authorizer) {
+	h.authorizer = a
+}
+
 // GetCurrentUser handles GET /me
 func (h *AccountHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	user := auth.GetUser(r.Context())
@@ -33,7 +44,7 @@ func (h *AccountHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get full user record from database
-	fullUser, err := h.authService.GetUserByID(r.Context(), user.ID)
+	fullUser, err := h.authService.GetUserByID(r.Context(), user.UserID)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
 			writeAccountError(w, http.StatusNotFound, types.ErrorCodeUserNotFound, "User not found")
@@ -55,7 +66,7 @@ func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if user.ID <= 0 {
+	if user.UserID <= 0 {
 		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Cannot change password: user not in database")
 		return
 	}
@@ -75,7 +86,7 @@ func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.authService.ChangePassword(r.Context(), user.ID, req.OldPassword, req.NewPassword)
+	err := h.authService.ChangePassword(r.Context(), user.UserID, req.OldPassword, req.NewPassword)
 	if err != nil {
 		if errors.Is(err, storage.ErrPermissionDenied) {
 			writeAccountError(w, http.StatusForbidden, types.ErrorCodeForbidden, "Current password is incorrect")
@@ -85,6 +96,10 @@ func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 			writeAccountError(w, http.StatusNotFound, types.ErrorCodeUserNotFound, "User not found")
 			return
 		}
+		if errors.Is(err, auth.ErrPasswordPolicy) {
+			writeAccountError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidPassword, err.Error())
+			return
+		}
 		slog.Error("internal server error", "error", err)
 		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
 		return
@@ -98,6 +113,237 @@ func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetPermissions handles GET /me/permissions
+func (h *AccountHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeAccountError(w, http.StatusUnauthorized, types.ErrorCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	// Super admins hold every permission, so their list is the full set.
+	rolePerms := auth.GetRolePermissions(auth.Role(user.Role))
+	perms := []string{}
+	for _, p := range auth.GetRolePermissions(auth.RoleSuperAdmin) {
+		held := slices.Contains(rolePerms, p)
+		if h.authorizer != nil {
+			held = h.authorizer.HasPermission(user, p)
+		}
+		if held {
+			perms = append(perms, string(p))
+		}
+	}
+
+	grants := []types.GrantResponse{}
+	for _, g := range h.authService.GrantsFor(r.Context(), user) {
+		grants = append(grants, grantToResponse(g))
+	}
+
+	writeAccountJSON(w, http.StatusOK, types.AccountPermissionsResponse{
+		Username:    user.Username,
+		Role:        user.Role,
+		Method:      user.Method,
+		Tenant:      user.Tenant,
+		SuperAdmin:  h.authorizer != nil && h.authorizer.IsSuperAdmin(user.Username),
+		Permissions: perms,
+		Grants:      grants,
+		Scopes:      user.Scopes,
+	})
+}
+
+// ListAPIKeys handles GET /me/apikeys
+func (h *AccountHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	owner, ok := h.keyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.authService.ListAPIKeysByUserID(r.Context(), owner.ID)
+	if err != nil {
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := types.APIKeysListResponse{
+		APIKeys: make([]types.APIKeyResponse, 0, len(keys)),
+	}
+	for _, k := range keys {
+		resp.APIKeys = append(resp.APIKeys, apiKeyRecordToResponse(k, owner.Username))
+	}
+	writeAccountJSON(w, http.StatusOK, resp)
+}
+
+// CreateAPIKey handles POST /me/apikeys
+func (h *AccountHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	owner, ok := h.keyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	var req types.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+
+	if req.ForUserID != nil {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "for_user_id is not allowed: keys are created for the caller")
+		return
+	}
+	if req.Name == "" {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Name is required")
+		return
+	}
+	if req.Role == "" {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidRole, "Role is required")
+		return
+	}
+	if !auth.ValidRole(req.Role) {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidRole, "Invalid role: "+req.Role)
+		return
+	}
+	if req.ExpiresIn <= 0 {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "expires_in is required and must be positive (duration in seconds)")
+		return
+	}
+
+	// A key may not do more than its owner.
+	superAdmin := h.authorizer != nil && h.authorizer.IsSuperAdmin(owner.Username)
+	if !superAdmin && !auth.RoleIncludes(auth.Role(owner.Role), auth.Role(req.Role)) {
+		writeAccountError(w, http.StatusForbidden, types.ErrorCodeForbidden, "Cannot create an API key with a role above your own")
+		return
+	}
+
+	result, err := h.authService.CreateAPIKey(r.Context(), auth.CreateAPIKeyRequest{
+		UserID:    owner.ID,
+		Name:      req.Name,
+		Role:      req.Role,
+		ExpiresAt: time.Now().UTC().Add(time.Duration(req.ExpiresIn) * time.Second),
+		Scopes:    req.Scopes,
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKeyScope) {
+			writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, err.Error())
+			return
+		}
+		if errors.Is(err, storage.ErrAPIKeyNameExists) {
+			writeAccountError(w, http.StatusConflict, types.ErrorCodeAPIKeyExists, "API key name already exists for this user")
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "apikey"
+		hints.TargetID = req.Name
+		hints.AfterHash = hashAPIKey(&storage.APIKeyRecord{
+			Name:      result.Name,
+			Role:      result.Role,
+			UserID:    result.UserID,
+			Enabled:   result.Enabled,
+			KeyPrefix: result.KeyPrefix,
+			Scopes:    result.Scopes,
+		})
+	}
+
+	writeAccountJSON(w, http.StatusCreated, types.CreateAPIKeyResponse{
+		ID:        result.ID,
+		Key:       result.Key,
+		KeyPrefix: result.KeyPrefix,
+		Name:      result.Name,
+		Role:      result.Role,
+		UserID:    result.UserID,
+		Username:  owner.Username,
+		Enabled:   result.Enabled,
+		CreatedAt: result.CreatedAt.Format(time.RFC3339),
+		ExpiresAt: result.ExpiresAt.Format(time.RFC3339),
+		Scopes:    result.Scopes,
+	})
+}
+
+// RevokeAPIKey handles POST /me/apikeys/{id}/revoke
+func (h *AccountHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	owner, ok := h.keyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := parseAPIKeyID(r)
+	if err != nil {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid API key ID")
+		return
+	}
+
+	// Another user's key is reported as missing rather than forbidden, so
+	// that key IDs cannot be probed.
+	existingKey, err := h.authService.GetAPIKeyByID(r.Context(), id)
+	if errors.Is(err, storage.ErrAPIKeyNotFound) || (err == nil && existingKey.UserID != owner.ID) {
+		writeAccountError(w, http.StatusNotFound, types.ErrorCodeAPIKeyNotFound, "API key not found")
+		return
+	}
+	if err != nil {
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(r.Context(), id); err != nil {
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+	key, err := h.authService.GetAPIKeyByID(r.Context(), id)
+	if err != nil {
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "apikey"
+		hints.TargetID = chi.URLParam(r, "id")
+		hints.BeforeHash = hashAPIKey(existingKey)
+		hints.AfterHash = hashAPIKey(key)
+	}
+
+	writeAccountJSON(w, http.StatusOK, apiKeyRecordToResponse(key, owner.Username))
+}
+
+// keyOwner returns the database user whose own API keys are managed. Keys
+// can only be managed by a user signed in as themselves, not with an API
+// key or a session started with one, so that a leaked key cannot be used to
+// mint others.
+func (h *AccountHandler) keyOwner(w http.ResponseWriter, r *http.Request) (*storage.UserRecord, bool) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeAccountError(w, http.StatusUnauthorized, types.ErrorCodeUnauthorized, "Authentication required")
+		return nil, false
+	}
+	if user.Method == "api_key" || user.APIKeyID != 0 {
+		writeAccountError(w, http.StatusForbidden, types.ErrorCodeForbidden, "API keys cannot manage API keys: sign in as the user")
+		return nil, false
+	}
+	if user.UserID <= 0 {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Cannot manage API keys: user not in database")
+		return nil, false
+	}
+
+	owner, err := h.authService.GetUserByID(r.Context(), user.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			writeAccountError(w, http.StatusNotFound, types.ErrorCodeUserNotFound, "User not found")
+			return nil, false
+		}
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return nil, false
+	}
+	return owner, true
+}
+
 func writeAccountJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/config"
 	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
 )
 
@@ -44,7 +48,7 @@ func TestGetCurrentUser_Success(t *testing.T) {
 	r.Get("/me", h.GetCurrentUser)
 
 	req := httptest.NewRequest("GET", "/me", nil)
-	req = withUser(req, &auth.User{ID: user.ID, Username: "alice", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: user.ID, Username: "alice", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -82,7 +86,7 @@ func TestGetCurrentUser_UserNotInDB(t *testing.T) {
 
 	// User with ID that doesn't exist in DB
 	req := httptest.NewRequest("GET", "/me", nil)
-	req = withUser(req, &auth.User{ID: 999, Username: "ghost", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: 999, Username: "ghost", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -113,7 +117,7 @@ func TestChangePassword_Success(t *testing.T) {
 	bodyBytes, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/me/password", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: user.ID, Username: "alice", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: user.ID, Username: "alice", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -160,7 +164,7 @@ func TestChangePassword_WrongOldPassword(t *testing.T) {
 	bodyBytes, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/me/password", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: user.ID, Username: "alice", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: user.ID, Username: "alice", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -179,7 +183,7 @@ func TestChangePassword_MissingOldPassword(t *testing.T) {
 	bodyBytes, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/me/password", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: 1, Username: "alice", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: 1, Username: "alice", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -198,7 +202,7 @@ func TestChangePassword_MissingNewPassword(t *testing.T) {
 	bodyBytes, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/me/password", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: 1, Username: "alice", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: 1, Username: "alice", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -215,7 +219,7 @@ func TestChangePassword_InvalidBody(t *testing.T) {
 
 	req := httptest.NewRequest("POST", "/me/password", strings.NewReader("{invalid"))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: 1, Username: "alice", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: 1, Username: "alice", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -235,7 +239,7 @@ func TestChangePassword_UserNotInDB(t *testing.T) {
 	bodyBytes, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/me/password", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: 0, Username: "config-user", Role: "admin"})
+	req = withUser(req, &auth.User{UserID: 0, Username: "config-user", Role: "admin"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -243,3 +247,170 @@ func TestChangePassword_UserNotInDB(t *testing.T) {
 		t.Errorf("expected 400, got %d", w.Code)
 	}
 }
+
+// --- Own API keys ---
+
+func TestAccountAPIKeys_CreateListRevoke(t *testing.T) {
+	h, svc := setupTestAccountHandler(t)
+	ctx := context.Background()
+
+	alice, err := svc.CreateUser(ctx, auth.CreateUserRequest{Username: "alice", Password: "pass123", Role: "developer", Enabled: true})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	bob, err := svc.CreateUser(ctx, auth.CreateUserRequest{Username: "bob", Password: "pass123", Role: "developer", Enabled: true})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/me/apikeys", h.ListAPIKeys)
+	r.Post("/me/apikeys", h.CreateAPIKey)
+	r.Post("/me/apikeys/{id}/revoke", h.RevokeAPIKey)
+	do := func(user *auth.User, method, path, body string) *httptest.ResponseRecorder {
+		req := withUser(httptest.NewRequest(method, path, strings.NewReader(body)), user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	asAlice := &auth.User{UserID: alice.ID, Username: "alice", Role: "developer", Method: "basic"}
+
+	if w := do(asAlice, "POST", "/me/apikeys", `{"name":"ci","role":"admin","expires_in":3600}`); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a role above the user's, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do(asAlice, "POST", "/me/apikeys", `{"name":"ci","role":"readonly","expires_in":3600,"for_user_id":1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for for_user_id, got %d", w.Code)
+	}
+
+	w := do(asAlice, "POST", "/me/apikeys", `{"name":"ci","role":"readonly","expires_in":3600}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created types.CreateAPIKeyResponse
+	json.NewDecoder(w.Body).Decode(&created)
+	if created.Key == "" || created.UserID != alice.ID || created.Role != "readonly" {
+		t.Errorf("unexpected created key: %+v", created)
+	}
+
+	// A key of another user is not listed, and cannot be revoked.
+	other, err := svc.CreateAPIKey(ctx, auth.CreateAPIKeyRequest{UserID: bob.ID, Name: "bob-ci", Role: "readonly", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("failed to create API key: %v", err)
+	}
+	w = do(asAlice, "GET", "/me/apikeys", "")
+	var list types.APIKeysListResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	if len(list.APIKeys) != 1 || list.APIKeys[0].ID != created.ID {
+		t.Errorf("expected only alice's key, got %+v", list.APIKeys)
+	}
+	if w := do(asAlice, "POST", fmt.Sprintf("/me/apikeys/%d/revoke", other.ID), ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 revoking another user's key, got %d", w.Code)
+	}
+
+	w = do(asAlice, "POST", fmt.Sprintf("/me/apikeys/%d/revoke", created.ID), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var revoked types.APIKeyResponse
+	json.NewDecoder(w.Body).Decode(&revoked)
+	if revoked.Enabled {
+		t.Error("expected the key to be disabled")
+	}
+
+	// Keys cannot be managed with an API key.
+	asKey := &auth.User{UserID: alice.ID, APIKeyID: created.ID, Username: "alice", Role: "readonly", Method: "api_key"}
+	if w := do(asKey, "POST", "/me/apikeys", `{"name":"more","role":"readonly","expires_in":3600}`); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 with an API key, got %d", w.Code)
+	}
+}
+
+func TestAccountAPIKeys_KeySession(t *testing.T) {
+	signer, err := auth.NewSessionSigner(config.SessionConfig{Enabled: true, Secret: "0123456789abcdef0123456789abcdef"})
+	if err != nil {
+		t.Fatalf("NewSessionSigner: %v", err)
+	}
+	svc := auth.NewServiceWithConfig(memory.NewStore(), auth.ServiceConfig{Sessions: signer})
+	t.Cleanup(func() { svc.Close() })
+	ctx := context.Background()
+
+	alice, err := svc.CreateUser(ctx, auth.CreateUserRequest{Username: "alice", Password: "pass123", Role: "developer", Enabled: true})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if _, err := svc.CreateUser(ctx, auth.CreateUserRequest{Username: "bob", Password: "pass123", Role: "admin", Enabled: true}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	// Give alice's key the ID of bob's account.
+	var key *auth.CreateAPIKeyResponse
+	for i := 0; key == nil || key.ID < 2; i++ {
+		if key, err = svc.CreateAPIKey(ctx, auth.CreateAPIKeyRequest{UserID: alice.ID, Name: fmt.Sprintf("ci-%d", i), Role: "readonly", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("failed to create API key: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	login := withUser(httptest.NewRequest("POST", "/auth/login", nil),
+		&auth.User{UserID: alice.ID, APIKeyID: key.ID, Username: "alice", Role: "readonly", Method: "api_key"})
+	NewSessionHandler(svc).Login(w, login)
+	var tokens types.LoginResponse
+	if err := json.NewDecoder(w.Body).Decode(&tokens); err != nil || tokens.AccessToken == "" {
+		t.Fatalf("failed to start a session with the key: %d %s", w.Code, w.Body.String())
+	}
+
+	authenticator := auth.NewAuthenticator(config.AuthConfig{Enabled: true, Methods: []string{"api_key"}})
+	authenticator.SetService(svc)
+	h := NewAccountHandler(svc)
+	r := chi.NewRouter()
+	r.Use(authenticator.Middleware)
+	r.Get("/me/apikeys", h.ListAPIKeys)
+	r.Post("/me/apikeys", h.CreateAPIKey)
+	for _, tt := range []struct{ method, body string }{
+		{"GET", ""},
+		{"POST", `{"name":"more","role":"readonly","expires_in":3600}`},
+	} {
+		req := httptest.NewRequest(tt.method, "/me/apikeys", strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s /me/apikeys with a key session: expected 403, got %d: %s", tt.method, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestAccountPermissions(t *testing.T) {
+	h, svc := setupTestAccountHandler(t)
+	h.SetAuthorizer(auth.NewAuthorizer(config.RBACConfig{Enabled: true, SuperAdmins: []string{"root"}}))
+	ctx := context.Background()
+
+	if _, err := svc.CreateGrant(ctx, auth.CreateGrantRequest{Username: "alice", Role: "developer", Context: ".team"}); err != nil {
+		t.Fatalf("failed to create grant: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/me/permissions", h.GetPermissions)
+	get := func(user *auth.User) types.AccountPermissionsResponse {
+		req := withUser(httptest.NewRequest("GET", "/me/permissions", nil), user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp types.AccountPermissionsResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		return resp
+	}
+
+	alice := get(&auth.User{Username: "alice", Role: "readonly", Method: "basic"})
+	if slices.Contains(alice.Permissions, "schema:write") || !slices.Contains(alice.Permissions, "schema:read") {
+		t.Errorf("unexpected readonly permissions: %v", alice.Permissions)
+	}
+	if len(alice.Grants) != 1 || alice.Grants[0].Context != ".team" {
+		t.Errorf("expected alice's grant, got %+v", alice.Grants)
+	}
+
+	root := get(&auth.User{Username: "root", Role: "readonly", Method: "basic"})
+	if !root.SuperAdmin || !slices.Contains(root.Permissions, "admin:write") {
+		t.Errorf("expected a configured super admin to hold every permission, got %+v", root)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -14,13 +15,26 @@ import (
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/usage"
 )
 
 // AdminHandler provides HTTP handlers for admin operations.
 type AdminHandler struct {
-	authService *auth.Service
-	authorizer  *auth.Authorizer
+	authService  *auth.Service
+	authorizer   *auth.Authorizer
+	auditHistory *auth.AuditHistory
+	usage        *usage.Tracker
+	clients      *usage.ClientTracker
+	tenants      TenantService
+	maintenance  MaintenanceService
+
+	pendingSchemas PendingSchemaService
+	quotas         QuotaService
+	references     ReferenceService
+	configReloader ConfigReloader
 }
 
 // NewAdminHandler creates a new AdminHandler.
@@ -31,6 +45,25 @@ func NewAdminHandler(authService *auth.Service, authorizer *auth.Authorizer) *Ad
 	}
 }
 
+// SetAuditHistory sets the audit history served by GET /admin/audit.
+// A nil history leaves the endpoint reporting that history is not enabled.
+func (h *AdminHandler) SetAuditHistory(history *auth.AuditHistory) {
+	h.auditHistory = history
+}
+
+// SetSchemaUsage sets the schema usage tracker queried by
+// GET /admin/usage/schemas. Without it the endpoint reports that usage
+// tracking is disabled.
+func (h *AdminHandler) SetSchemaUsage(t *usage.Tracker) {
+	h.usage = t
+}
+
+// SetClientUsage sets the client tracker queried by GET /admin/usage/clients.
+// Without it the endpoint reports that client tracking is disabled.
+func (h *AdminHandler) SetClientUsage(t *usage.ClientTracker) {
+	h.clients = t
+}
+
 // ListUsers handles GET /admin/users
 func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	if !h.requireAdminRead(w, r) {
@@ -47,7 +80,11 @@ func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	resp := types.UsersListResponse{
 		Users: make([]types.UserResponse, 0, len(users)),
 	}
+	tenant := callerTenant(r)
 	for _, u := range users {
+		if tenant != "" && u.Tenant != tenant {
+			continue
+		}
 		resp.Users = append(resp.Users, userToResponse(u))
 	}
 
@@ -84,12 +121,30 @@ func (h *AdminHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		enabled = *req.Enabled
 	}
 
+	// Tenant admins can only add users to their own tenant.
+	tenant := req.Tenant
+	if caller := callerTenant(r); caller != "" {
+		tenant = caller
+	} else if tenant != "" {
+		if h.tenants == nil {
+			writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidTenant, "Multi-tenancy is not enabled")
+			return
+		}
+		if _, err := h.tenants.GetTenant(r.Context(), tenant); err != nil {
+			writeTenantError(w, err)
+			return
+		}
+	}
+
 	user, err := h.authService.CreateUser(r.Context(), auth.CreateUserRequest{
 		Username: req.Username,
 		Email:    req.Email,
 		Password: req.Password,
 		Role:     req.Role,
 		Enabled:  enabled,
+		Tenant:   tenant,
+
+		MustChangePassword: req.MustChangePassword,
 	})
 	if err != nil {
 		if errors.Is(err, storage.ErrUserExists) {
@@ -100,6 +155,10 @@ func (h *AdminHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidRole, err.Error())
 			return
 		}
+		if errors.Is(err, auth.ErrPasswordPolicy) {
+			writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidPassword, err.Error())
+			return
+		}
 		slog.Error("internal server error", "error", err)
 		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
 		return
@@ -125,6 +184,9 @@ func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid user ID")
 		return
 	}
+	if !h.requireUserInTenant(w, r, id) {
+		return
+	}
 
 	user, err := h.authService.GetUserByID(r.Context(), id)
 	if err != nil {
@@ -151,6 +213,9 @@ func (h *AdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid user ID")
 		return
 	}
+	if !h.requireUserInTenant(w, r, id) {
+		return
+	}
 
 	var req types.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -171,6 +236,9 @@ func (h *AdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	if req.Enabled != nil {
 		updates["enabled"] = *req.Enabled
 	}
+	if req.MustChangePassword != nil {
+		updates["must_change_password"] = *req.MustChangePassword
+	}
 
 	// Capture user state before update for audit trail.
 	existingUser, _ := h.authService.GetUserByID(r.Context(), id)
@@ -185,6 +253,10 @@ func (h *AdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidRole, err.Error())
 			return
 		}
+		if errors.Is(err, auth.ErrPasswordPolicy) {
+			writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidPassword, err.Error())
+			return
+		}
 		slog.Error("internal server error", "error", err)
 		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
 		return
@@ -213,6 +285,9 @@ func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid user ID")
 		return
 	}
+	if !h.requireUserInTenant(w, r, id) {
+		return
+	}
 
 	// Capture user state before deletion for audit trail.
 	existingUser, _ := h.authService.GetUserByID(r.Context(), id)
@@ -269,13 +344,82 @@ func (h *AdminHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
 	resp := types.APIKeysListResponse{
 		APIKeys: make([]types.APIKeyResponse, 0, len(keys)),
 	}
+	tenant := callerTenant(r)
 	for _, k := range keys {
+		if tenant != "" && h.apiKeyTenant(r.Context(), k) != tenant {
+			continue
+		}
 		resp.APIKeys = append(resp.APIKeys, h.apiKeyToResponse(r.Context(), k))
 	}
 
 	writeAdminJSON(w, http.StatusOK, resp)
 }
 
+// GetAPIKeyUsage handles GET /admin/apikeys/usage
+func (h *AdminHandler) GetAPIKeyUsage(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	since, err := parseUsageSince(q.Get("since"), time.Now())
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid since: "+err.Error())
+		return
+	}
+	unused := q.Get("unused") == "true"
+
+	keys, err := h.authService.ListAPIKeys(r.Context())
+	if err != nil {
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+	usages, err := h.authService.APIKeyUsage(r.Context(), since)
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeStorageError, "Failed to query API key usage")
+		return
+	}
+	byKey := make(map[int64]auth.APIKeyUsage, len(usages))
+	for _, u := range usages {
+		byKey[u.APIKeyID] = u
+	}
+
+	resp := types.APIKeyUsageResponse{APIKeys: make([]types.APIKeyUsageEntry, 0, len(keys))}
+	if !since.IsZero() {
+		y, m, d := since.UTC().Date()
+		resp.Since = time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	tenant := callerTenant(r)
+	for _, k := range keys {
+		if tenant != "" && h.apiKeyTenant(r.Context(), k) != tenant {
+			continue
+		}
+		u := byKey[k.ID]
+		if unused && u.Requests > 0 {
+			continue
+		}
+		key := h.apiKeyToResponse(r.Context(), k)
+		entry := types.APIKeyUsageEntry{
+			ID:        key.ID,
+			KeyPrefix: key.KeyPrefix,
+			Name:      key.Name,
+			UserID:    key.UserID,
+			Username:  key.Username,
+			Enabled:   key.Enabled,
+			CreatedAt: key.CreatedAt,
+			LastUsed:  key.LastUsed,
+			Requests:  u.Requests,
+			Days:      make([]types.APIKeyUsageDaily, 0, len(u.Days)),
+		}
+		for _, d := range u.Days {
+			entry.Days = append(entry.Days, types.APIKeyUsageDaily{Day: d.Day.Format("2006-01-02"), Requests: d.Requests})
+		}
+		resp.APIKeys = append(resp.APIKeys, entry)
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
 // CreateAPIKey handles POST /admin/apikeys
 func (h *AdminHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	if !h.requireAdminWrite(w, r) {
@@ -320,7 +464,7 @@ func (h *AdminHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		}
 		// Verify the target user exists
 		targetUser, err := h.authService.GetUserByID(r.Context(), *req.ForUserID)
-		if err != nil {
+		if err != nil || (currentUser.Tenant != "" && targetUser.Tenant != currentUser.Tenant) {
 			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeUserNotFound, "Target user not found")
 			return
 		}
@@ -328,7 +472,7 @@ func (h *AdminHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		ownerUsername = targetUser.Username
 	} else {
 		// Creating for self - use the authenticated user's ID
-		ownerUserID = currentUser.ID
+		ownerUserID = currentUser.UserID
 		ownerUsername = currentUser.Username
 
 		// If the user ID is 0 (e.g., authenticated via config-based auth), we can't create API keys
@@ -345,12 +489,17 @@ func (h *AdminHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		Name:      req.Name,
 		Role:      req.Role,
 		ExpiresAt: expiresAt,
+		Scopes:    req.Scopes,
 	})
 	if err != nil {
 		if errors.Is(err, storage.ErrInvalidRole) {
 			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidRole, err.Error())
 			return
 		}
+		if errors.Is(err, auth.ErrInvalidAPIKeyScope) {
+			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, err.Error())
+			return
+		}
 		if errors.Is(err, storage.ErrAPIKeyNameExists) {
 			writeAdminError(w, http.StatusConflict, types.ErrorCodeAPIKeyExists, "API key name already exists for this user")
 			return
@@ -371,6 +520,7 @@ func (h *AdminHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		Enabled:   result.Enabled,
 		CreatedAt: result.CreatedAt.Format(time.RFC3339),
 		ExpiresAt: result.ExpiresAt.Format(time.RFC3339),
+		Scopes:    result.Scopes,
 	}
 
 	if hints := auth.GetAuditHints(r.Context()); hints != nil {
@@ -382,6 +532,7 @@ func (h *AdminHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 			UserID:    result.UserID,
 			Enabled:   result.Enabled,
 			KeyPrefix: result.KeyPrefix,
+			Scopes:    result.Scopes,
 		})
 	}
 
@@ -399,6 +550,9 @@ func (h *AdminHandler) GetAPIKey(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid API key ID")
 		return
 	}
+	if !h.requireAPIKeyInTenant(w, r, id) {
+		return
+	}
 
 	key, err := h.authService.GetAPIKeyByID(r.Context(), id)
 	if err != nil {
@@ -425,6 +579,9 @@ func (h *AdminHandler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid API key ID")
 		return
 	}
+	if !h.requireAPIKeyInTenant(w, r, id) {
+		return
+	}
 
 	var req types.UpdateAPIKeyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -484,6 +641,9 @@ func (h *AdminHandler) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid API key ID")
 		return
 	}
+	if !h.requireAPIKeyInTenant(w, r, id) {
+		return
+	}
 
 	// Capture API key state before deletion for audit trail.
 	existingKey, _ := h.authService.GetAPIKeyByID(r.Context(), id)
@@ -520,6 +680,9 @@ func (h *AdminHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid API key ID")
 		return
 	}
+	if !h.requireAPIKeyInTenant(w, r, id) {
+		return
+	}
 
 	// Capture API key state before revocation for audit trail.
 	existingKey, _ := h.authService.GetAPIKeyByID(r.Context(), id)
@@ -556,6 +719,10 @@ func (h *AdminHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
 // RotateAPIKeyRequest is the request body for rotating an API key.
 type RotateAPIKeyRequest struct {
 	ExpiresIn int64 `json:"expires_in"` // Required: expiry duration in seconds for the new key
+
+	// Scopes replaces the old key's scopes on the new key when present; an
+	// empty list removes them.
+	Scopes []storage.APIKeyScope `json:"scopes"`
 }
 
 // RotateAPIKey handles POST /admin/apikeys/{id}/rotate
@@ -569,6 +736,9 @@ func (h *AdminHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
 		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid API key ID")
 		return
 	}
+	if !h.requireAPIKeyInTenant(w, r, id) {
+		return
+	}
 
 	var req RotateAPIKeyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -586,12 +756,16 @@ func (h *AdminHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
 	// Capture API key state before rotation for audit trail.
 	existingKey, _ := h.authService.GetAPIKeyByID(r.Context(), id)
 
-	result, err := h.authService.RotateAPIKey(r.Context(), id, newExpiresAt)
+	result, err := h.authService.RotateAPIKey(r.Context(), id, newExpiresAt, req.Scopes)
 	if err != nil {
 		if errors.Is(err, storage.ErrAPIKeyNotFound) {
 			writeAdminError(w, http.StatusNotFound, types.ErrorCodeAPIKeyNotFound, "API key not found")
 			return
 		}
+		if errors.Is(err, auth.ErrInvalidAPIKeyScope) {
+			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, err.Error())
+			return
+		}
 		slog.Error("internal server error", "error", err)
 		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
 		return
@@ -614,6 +788,7 @@ func (h *AdminHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
 		Enabled:   result.Enabled,
 		CreatedAt: result.CreatedAt.Format(time.RFC3339),
 		ExpiresAt: result.ExpiresAt.Format(time.RFC3339),
+		Scopes:    result.Scopes,
 	}
 
 	resp := types.RotateAPIKeyResponse{
@@ -633,12 +808,306 @@ func (h *AdminHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
 			UserID:    result.UserID,
 			Enabled:   result.Enabled,
 			KeyPrefix: result.KeyPrefix,
+			Scopes:    result.Scopes,
 		})
 	}
 
 	writeAdminJSON(w, http.StatusOK, resp)
 }
 
+// ListGrants handles GET /admin/grants
+func (h *AdminHandler) ListGrants(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+
+	grants, err := h.authService.ListGrants(r.Context())
+	if err != nil {
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	// Optional filter by bound username
+	username := r.URL.Query().Get("username")
+
+	resp := types.GrantsListResponse{
+		Grants: make([]types.GrantResponse, 0, len(grants)),
+	}
+	for _, g := range grants {
+		if username != "" && g.Username != username {
+			continue
+		}
+		resp.Grants = append(resp.Grants, grantToResponse(g))
+	}
+
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// CreateGrant handles POST /admin/grants
+func (h *AdminHandler) CreateGrant(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminWrite(w, r) {
+		return
+	}
+
+	var req types.CreateGrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+
+	if (req.Username == "") == (req.APIKeyID == 0) {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Exactly one of username or api_key_id is required")
+		return
+	}
+	if req.Role == "" {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidRole, "Role is required")
+		return
+	}
+	if req.Context != "" {
+		req.Context = registrycontext.NormalizeContextName(req.Context)
+		if !registrycontext.IsValidContextName(req.Context) {
+			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidContext, "Invalid context name")
+			return
+		}
+	}
+	if req.APIKeyID != 0 {
+		if _, err := h.authService.GetAPIKeyByID(r.Context(), req.APIKeyID); err != nil {
+			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeAPIKeyNotFound, "Target API key not found")
+			return
+		}
+	}
+
+	var createdBy string
+	if user := auth.GetUser(r.Context()); user != nil {
+		createdBy = user.Username
+	}
+
+	grant, err := h.authService.CreateGrant(r.Context(), auth.CreateGrantRequest{
+		Username:      req.Username,
+		APIKeyID:      req.APIKeyID,
+		Role:          req.Role,
+		Context:       req.Context,
+		SubjectPrefix: req.SubjectPrefix,
+		CreatedBy:     createdBy,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidRole) {
+			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidRole, err.Error())
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "grant"
+		hints.TargetID = strconv.FormatInt(grant.ID, 10)
+		hints.AfterHash = hashGrant(grant)
+	}
+
+	writeAdminJSON(w, http.StatusCreated, grantToResponse(grant))
+}
+
+// GetGrant handles GET /admin/grants/{id}
+func (h *AdminHandler) GetGrant(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+
+	id, err := parseGrantID(r)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid grant ID")
+		return
+	}
+
+	grant, err := h.authService.GetGrant(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrGrantNotFound) {
+			writeAdminError(w, http.StatusNotFound, types.ErrorCodeGrantNotFound, "Grant not found")
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	writeAdminJSON(w, http.StatusOK, grantToResponse(grant))
+}
+
+// DeleteGrant handles DELETE /admin/grants/{id}
+func (h *AdminHandler) DeleteGrant(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminWrite(w, r) {
+		return
+	}
+
+	id, err := parseGrantID(r)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid grant ID")
+		return
+	}
+
+	// Capture grant state before deletion for audit trail.
+	existingGrant, _ := h.authService.GetGrant(r.Context(), id)
+
+	if err := h.authService.DeleteGrant(r.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrGrantNotFound) {
+			writeAdminError(w, http.StatusNotFound, types.ErrorCodeGrantNotFound, "Grant not found")
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "grant"
+		hints.TargetID = chi.URLParam(r, "id")
+		if existingGrant != nil {
+			hints.BeforeHash = hashGrant(existingGrant)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListShareTokens handles GET /admin/share-tokens
+func (h *AdminHandler) ListShareTokens(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+
+	tokens, err := h.authService.ListShareTokens(r.Context())
+	if err != nil {
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := types.ShareTokensListResponse{
+		ShareTokens: make([]types.ShareTokenResponse, 0, len(tokens)),
+	}
+	for _, t := range tokens {
+		resp.ShareTokens = append(resp.ShareTokens, shareTokenToResponse(t))
+	}
+
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// CreateShareToken handles POST /admin/share-tokens
+func (h *AdminHandler) CreateShareToken(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminWrite(w, r) {
+		return
+	}
+
+	var req types.CreateShareTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	if req.ExpiresIn <= 0 {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "expires_in is required and must be positive (duration in seconds)")
+		return
+	}
+
+	var createdBy string
+	if user := auth.GetUser(r.Context()); user != nil {
+		createdBy = user.Username
+	}
+
+	record, token, err := h.authService.CreateShareToken(r.Context(), auth.CreateShareTokenRequest{
+		Name:      req.Name,
+		Context:   req.Context,
+		Subject:   req.Subject,
+		ExpiresAt: time.Now().UTC().Add(time.Duration(req.ExpiresIn) * time.Second),
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidShareToken) {
+			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, err.Error())
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "share_token"
+		hints.TargetID = strconv.FormatInt(record.ID, 10)
+	}
+
+	writeAdminJSON(w, http.StatusCreated, types.CreateShareTokenResponse{
+		ID:          record.ID,
+		Token:       token,
+		TokenPrefix: record.TokenPrefix,
+		Name:        record.Name,
+		Context:     record.Context,
+		Subject:     record.Subject,
+		CreatedBy:   record.CreatedBy,
+		CreatedAt:   record.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:   record.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// GetShareToken handles GET /admin/share-tokens/{id}
+func (h *AdminHandler) GetShareToken(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid share token ID")
+		return
+	}
+
+	record, err := h.authService.GetShareToken(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrShareTokenNotFound) {
+			writeAdminError(w, http.StatusNotFound, types.ErrorCodeShareTokenNotFound, "Share token not found")
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	writeAdminJSON(w, http.StatusOK, shareTokenToResponse(record))
+}
+
+// DeleteShareToken handles DELETE /admin/share-tokens/{id}. Deleting a share
+// token revokes it immediately.
+func (h *AdminHandler) DeleteShareToken(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminWrite(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid share token ID")
+		return
+	}
+
+	if err := h.authService.DeleteShareToken(r.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrShareTokenNotFound) {
+			writeAdminError(w, http.StatusNotFound, types.ErrorCodeShareTokenNotFound, "Share token not found")
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "share_token"
+		hints.TargetID = chi.URLParam(r, "id")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // ListRoles handles GET /admin/roles
 func (h *AdminHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
 	if !h.requireAdminRead(w, r) {
@@ -673,6 +1142,176 @@ func (h *AdminHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
 
 // Helper functions
 
+// QueryAuditEvents handles GET /admin/audit
+func (h *AdminHandler) QueryAuditEvents(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+	if h.auditHistory == nil {
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeAuditHistoryDisabled, "Audit history is not enabled")
+		return
+	}
+
+	q := r.URL.Query()
+	query := auth.AuditQuery{
+		Principal: q.Get("principal"),
+		Subject:   q.Get("subject"),
+		EventType: auth.AuditEventType(q.Get("event_type")),
+		Outcome:   q.Get("outcome"),
+		Limit:     defaultAuditQueryLimit,
+	}
+
+	now := time.Now()
+	var err error
+	if query.Since, err = parseAuditTime(q.Get("since"), now); err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid since: "+err.Error())
+		return
+	}
+	if query.Until, err = parseAuditTime(q.Get("until"), now); err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid until: "+err.Error())
+		return
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid limit: must be a positive integer")
+			return
+		}
+		query.Limit = limit
+	}
+
+	writeAdminJSON(w, http.StatusOK, types.AuditEventsResponse{
+		Events: h.auditHistory.Query(query),
+	})
+}
+
+// GetSchemaUsage handles GET /admin/usage/schemas
+func (h *AdminHandler) GetSchemaUsage(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+	if h.usage == nil {
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeUsageTrackingDisabled, "Schema usage tracking is not enabled")
+		return
+	}
+
+	q := r.URL.Query()
+	since, err := parseUsageSince(q.Get("since"), time.Now())
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid since: "+err.Error())
+		return
+	}
+
+	var entries []usage.SchemaUsage
+	if q.Get("unused") == "true" {
+		entries, err = h.usage.Unused(r.Context(), since)
+	} else {
+		entries, err = h.usage.Query(r.Context(), since)
+	}
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeStorageError, "Failed to query schema usage")
+		return
+	}
+
+	resp := types.SchemaUsageResponse{Schemas: make([]types.SchemaUsageEntry, 0, len(entries))}
+	if !since.IsZero() {
+		y, m, d := since.UTC().Date()
+		resp.Since = time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	for _, u := range entries {
+		entry := types.SchemaUsageEntry{
+			Context: u.Context,
+			ID:      u.SchemaID,
+			Subject: u.Subject,
+			Version: u.Version,
+			Fetches: u.Fetches,
+		}
+		if !u.LastFetched.IsZero() {
+			entry.LastFetched = u.LastFetched.Format("2006-01-02")
+		}
+		resp.Schemas = append(resp.Schemas, entry)
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// GetClientUsage handles GET /admin/usage/clients
+func (h *AdminHandler) GetClientUsage(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdminRead(w, r) {
+		return
+	}
+	if h.clients == nil {
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeClientTrackingDisabled, "Client tracking is not enabled")
+		return
+	}
+
+	q := r.URL.Query()
+	since, err := parseUsageSince(q.Get("since"), time.Now())
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid since: "+err.Error())
+		return
+	}
+
+	entries := h.clients.Query(q.Get("client"), since)
+	resp := types.ClientUsageResponse{Clients: make([]types.ClientUsageEntry, 0, len(entries))}
+	for _, u := range entries {
+		resp.Clients = append(resp.Clients, types.ClientUsageEntry{
+			Client:        u.Client,
+			ClientVersion: u.Version,
+			Method:        u.Method,
+			Endpoint:      u.Endpoint,
+			Principal:     u.Principal,
+			Requests:      u.Requests,
+			FirstSeen:     u.FirstSeen.UTC().Format(time.RFC3339),
+			LastSeen:      u.LastSeen.UTC().Format(time.RFC3339),
+		})
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// GetAuthCacheReport handles GET /admin/auth-cache
+func (h *AdminHandler) GetAuthCacheReport(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	report := h.authService.LastCacheReport()
+	if report == nil {
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeAuthCacheNotChecked, "No auth cache consistency check has run yet")
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, cacheReportToResponse(report))
+}
+
+// RefreshAuthCache handles POST /admin/auth-cache/refresh. It reconciles the
+// caches with storage immediately instead of waiting for the next check.
+func (h *AdminHandler) RefreshAuthCache(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+
+	report, err := h.authService.ReconcileCache(r.Context(), auth.CacheCheckForced)
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeStorageError, "Failed to refresh auth cache")
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, cacheReportToResponse(report))
+}
+
+// requireInstanceAdmin rejects tenant users, whose view of the registry
+// does not extend to instance-wide caches.
+func (h *AdminHandler) requireInstanceAdmin(w http.ResponseWriter, r *http.Request, perm auth.Permission) bool {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeAdminError(w, http.StatusUnauthorized, types.ErrorCodeUnauthorized, "Authentication required")
+		return false
+	}
+	if user.Tenant != "" || !h.authorizer.HasPermission(user, perm) {
+		writeAdminError(w, http.StatusForbidden, types.ErrorCodeForbidden, "Instance admin permission required")
+		return false
+	}
+	return true
+}
+
 func (h *AdminHandler) requireAdminRead(w http.ResponseWriter, r *http.Request) bool {
 	user := auth.GetUser(r.Context())
 	if user == nil {
@@ -686,6 +1325,57 @@ func (h *AdminHandler) requireAdminRead(w http.ResponseWriter, r *http.Request)
 	return true
 }
 
+// callerTenant returns the tenant of the authenticated user, or "" for
+// instance-wide users.
+func callerTenant(r *http.Request) string {
+	if user := auth.GetUser(r.Context()); user != nil {
+		return user.Tenant
+	}
+	return ""
+}
+
+// requireUserInTenant answers 404 for users outside the caller's tenant, so
+// tenant admins cannot see that other tenants' users exist.
+func (h *AdminHandler) requireUserInTenant(w http.ResponseWriter, r *http.Request, id int64) bool {
+	tenant := callerTenant(r)
+	if tenant == "" {
+		return true
+	}
+	user, err := h.authService.GetUserByID(r.Context(), id)
+	if err != nil || user.Tenant != tenant {
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeUserNotFound, "User not found")
+		return false
+	}
+	return true
+}
+
+// requireAPIKeyInTenant answers 404 for API keys whose owner is outside the
+// caller's tenant.
+func (h *AdminHandler) requireAPIKeyInTenant(w http.ResponseWriter, r *http.Request, id int64) bool {
+	tenant := callerTenant(r)
+	if tenant == "" {
+		return true
+	}
+	key, err := h.authService.GetAPIKeyByID(r.Context(), id)
+	if err != nil || h.apiKeyTenant(r.Context(), key) != tenant {
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeAPIKeyNotFound, "API key not found")
+		return false
+	}
+	return true
+}
+
+// apiKeyTenant returns the tenant of an API key's owner.
+func (h *AdminHandler) apiKeyTenant(ctx context.Context, k *storage.APIKeyRecord) string {
+	if k.UserID <= 0 {
+		return ""
+	}
+	user, err := h.authService.GetUserByID(ctx, k.UserID)
+	if err != nil {
+		return ""
+	}
+	return user.Tenant
+}
+
 func (h *AdminHandler) requireAdminWrite(w http.ResponseWriter, r *http.Request) bool {
 	user := auth.GetUser(r.Context())
 	if user == nil {
@@ -699,6 +1389,53 @@ func (h *AdminHandler) requireAdminWrite(w http.ResponseWriter, r *http.Request)
 	return true
 }
 
+// defaultAuditQueryLimit is the number of events returned by GET /admin/audit
+// when no limit is given.
+const defaultAuditQueryLimit = 100
+
+// parseAuditTime parses a since/until filter, which is either an RFC 3339
+// timestamp or a Go duration measured back from now (e.g. "24h"). An empty
+// value yields the zero time, which disables the bound.
+func parseAuditTime(v string, now time.Time) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration must not be negative")
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or a duration such as 24h")
+	}
+	return t, nil
+}
+
+// parseUsageSince parses the since parameter of the usage endpoint: an
+// RFC 3339 timestamp, a date (YYYY-MM-DD), or a duration measured back from
+// now that may use days (e.g. 30d). An empty value means all history.
+func parseUsageSince(v string, now time.Time) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if d, err := config.ParseDuration(v); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration must not be negative")
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp, a date such as 2026-01-31, or a duration such as 30d")
+	}
+	return t, nil
+}
+
 func parseUserID(r *http.Request) (int64, error) {
 	idStr := chi.URLParam(r, "id")
 	return strconv.ParseInt(idStr, 10, 64)
@@ -709,16 +1446,54 @@ func parseAPIKeyID(r *http.Request) (int64, error) {
 	return strconv.ParseInt(idStr, 10, 64)
 }
 
+func parseGrantID(r *http.Request) (int64, error) {
+	idStr := chi.URLParam(r, "id")
+	return strconv.ParseInt(idStr, 10, 64)
+}
+
+func grantToResponse(g *storage.GrantRecord) types.GrantResponse {
+	return types.GrantResponse{
+		ID:            g.ID,
+		Username:      g.Username,
+		APIKeyID:      g.APIKeyID,
+		Role:          g.Role,
+		Context:       g.Context,
+		SubjectPrefix: g.SubjectPrefix,
+		CreatedBy:     g.CreatedBy,
+		CreatedAt:     g.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func shareTokenToResponse(t *storage.ShareTokenRecord) types.ShareTokenResponse {
+	return types.ShareTokenResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		TokenPrefix: t.TokenPrefix,
+		Context:     t.Context,
+		Subject:     t.Subject,
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:   t.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
 func userToResponse(u *storage.UserRecord) types.UserResponse {
-	return types.UserResponse{
+	resp := types.UserResponse{
 		ID:        u.ID,
 		Username:  u.Username,
 		Email:     u.Email,
 		Role:      u.Role,
 		Enabled:   u.Enabled,
+		Tenant:    u.Tenant,
 		CreatedAt: u.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+
+		MustChangePassword: u.MustChangePassword,
 	}
+	if !u.PasswordChangedAt.IsZero() {
+		resp.PasswordChangedAt = u.PasswordChangedAt.Format(time.RFC3339)
+	}
+	return resp
 }
 
 func (h *AdminHandler) apiKeyToResponse(ctx context.Context, k *storage.APIKeyRecord) types.APIKeyResponse {
@@ -727,7 +1502,10 @@ func (h *AdminHandler) apiKeyToResponse(ctx context.Context, k *storage.APIKeyRe
 	if user, err := h.authService.GetUserByID(ctx, k.UserID); err == nil {
 		username = user.Username
 	}
+	return apiKeyRecordToResponse(k, username)
+}
 
+func apiKeyRecordToResponse(k *storage.APIKeyRecord, username string) types.APIKeyResponse {
 	resp := types.APIKeyResponse{
 		ID:        k.ID,
 		KeyPrefix: k.KeyPrefix,
@@ -738,6 +1516,7 @@ func (h *AdminHandler) apiKeyToResponse(ctx context.Context, k *storage.APIKeyRe
 		Enabled:   k.Enabled,
 		CreatedAt: k.CreatedAt.Format(time.RFC3339),
 		ExpiresAt: k.ExpiresAt.Format(time.RFC3339),
+		Scopes:    k.Scopes,
 	}
 	if k.LastUsed != nil {
 		lastUsed := k.LastUsed.Format(time.RFC3339)
@@ -746,6 +1525,23 @@ func (h *AdminHandler) apiKeyToResponse(ctx context.Context, k *storage.APIKeyRe
 	return resp
 }
 
+func cacheReportToResponse(r *auth.CacheReport) types.AuthCacheReportResponse {
+	return types.AuthCacheReportResponse{
+		Trigger:         r.Trigger,
+		CheckedAt:       r.CheckedAt.Format(time.RFC3339),
+		DurationMs:      r.Duration.Milliseconds(),
+		Diverged:        r.Diverged(),
+		APIKeys:         r.APIKeys,
+		StaleAPIKeys:    r.StaleAPIKeys,
+		OrphanedAPIKeys: r.OrphanedAPIKeys,
+		MissingAPIKeys:  r.MissingAPIKeys,
+		CachedUsers:     r.CachedUsers,
+		StaleUsers:      r.StaleUsers,
+		Grants:          r.Grants,
+		StaleGrants:     r.StaleGrants,
+	}
+}
+
 func permissionsToStrings(perms []auth.Permission) []string {
 	result := make([]string, len(perms))
 	for i, p := range perms {
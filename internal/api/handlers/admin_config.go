@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+)
+
+// ConfigReloader reloads the configuration file of the running server. It
+// returns the changed settings it applied and the top-level sections with
+// changes that need a restart. When the new configuration is invalid, it
+// returns an error and leaves the running configuration unchanged.
+type ConfigReloader interface {
+	ReloadConfig(ctx context.Context) (applied, restartRequired []string, err error)
+}
+
+// SetConfigReloader sets the reloader behind POST /admin/config/reload.
+func (h *AdminHandler) SetConfigReloader(r ConfigReloader) {
+	h.configReloader = r
+}
+
+// ReloadConfig handles POST /admin/config/reload. It re-reads the
+// configuration file, as SIGHUP does, and applies the log level, the
+// default compatibility level, rate limits and authentication providers
+// without a restart.
+func (h *AdminHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+
+	applied, restartRequired, err := h.configReloader.ReloadConfig(r.Context())
+	if err != nil {
+		writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeConfigReloadFailed,
+			"Configuration not reloaded: "+err.Error())
+		return
+	}
+	if applied == nil {
+		applied = []string{}
+	}
+	if restartRequired == nil {
+		restartRequired = []string{}
+	}
+	writeAdminJSON(w, http.StatusOK, types.ConfigReloadResponse{Applied: applied, RestartRequired: restartRequired})
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// MaintenanceService schedules maintenance windows. It is implemented by
+// *registry.Registry.
+type MaintenanceService interface {
+	ScheduleMaintenance(ctx context.Context, startsAt time.Time, duration time.Duration, reason, createdBy string) (*storage.MaintenanceWindowRecord, error)
+	GetMaintenanceWindow(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error)
+	ListMaintenanceWindows(ctx context.Context) ([]*storage.MaintenanceWindowRecord, error)
+	CancelMaintenance(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error)
+}
+
+// SetMaintenance sets the service behind /admin/maintenance.
+func (h *AdminHandler) SetMaintenance(m MaintenanceService) {
+	h.maintenance = m
+}
+
+// ListMaintenanceWindows handles GET /admin/maintenance
+func (h *AdminHandler) ListMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	windows, err := h.maintenance.ListMaintenanceWindows(r.Context())
+	if err != nil {
+		writeMaintenanceError(w, err)
+		return
+	}
+
+	now := time.Now()
+	resp := types.MaintenanceListResponse{Windows: make([]types.MaintenanceResponse, 0, len(windows))}
+	for _, mw := range windows {
+		resp.Windows = append(resp.Windows, maintenanceToResponse(mw, now))
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// ScheduleMaintenance handles POST /admin/maintenance. While the window is
+// active the registry is in READONLY_OVERRIDE; it reverts when the window
+// ends.
+func (h *AdminHandler) ScheduleMaintenance(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+
+	var req types.MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidMaintenance, "Invalid request body")
+		return
+	}
+	var startsAt time.Time
+	if req.StartsAt != "" {
+		t, err := time.Parse(time.RFC3339, req.StartsAt)
+		if err != nil {
+			writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidMaintenance,
+				fmt.Sprintf("starts_at must be an RFC 3339 timestamp: %v", err))
+			return
+		}
+		startsAt = t
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidMaintenance,
+			fmt.Sprintf("duration must be a duration such as 30m or 2h: %v", err))
+		return
+	}
+
+	createdBy := ""
+	if user := auth.GetUser(r.Context()); user != nil {
+		createdBy = user.Username
+	}
+	window, err := h.maintenance.ScheduleMaintenance(r.Context(), startsAt, duration, req.Reason, createdBy)
+	if err != nil {
+		writeMaintenanceError(w, err)
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "maintenance"
+		hints.TargetID = window.ID
+	}
+	writeAdminJSON(w, http.StatusCreated, maintenanceToResponse(window, time.Now()))
+}
+
+// GetMaintenanceWindow handles GET /admin/maintenance/{id}
+func (h *AdminHandler) GetMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	window, err := h.maintenance.GetMaintenanceWindow(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeMaintenanceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, maintenanceToResponse(window, time.Now()))
+}
+
+// CancelMaintenance handles DELETE /admin/maintenance/{id}. A scheduled
+// window is cancelled; an active one ends at once.
+func (h *AdminHandler) CancelMaintenance(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+
+	window, err := h.maintenance.CancelMaintenance(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeMaintenanceError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, maintenanceToResponse(window, time.Now()))
+}
+
+func writeMaintenanceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage.ErrMaintenanceNotFound):
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeMaintenanceNotFound, "Maintenance window not found")
+	case errors.Is(err, registry.ErrInvalidMaintenance):
+		writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidMaintenance, err.Error())
+	case errors.Is(err, registry.ErrMaintenanceEnded):
+		writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeMaintenanceEnded, err.Error())
+	default:
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+	}
+}
+
+func maintenanceToResponse(mw *storage.MaintenanceWindowRecord, now time.Time) types.MaintenanceResponse {
+	resp := types.MaintenanceResponse{
+		ID:        mw.ID,
+		State:     registry.MaintenanceState(mw, now),
+		StartsAt:  mw.StartsAt.UTC().Format(time.RFC3339),
+		EndsAt:    mw.EndsAt.UTC().Format(time.RFC3339),
+		Reason:    mw.Reason,
+		CreatedBy: mw.CreatedBy,
+	}
+	if !mw.CreatedAt.IsZero() {
+		resp.CreatedAt = mw.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// PendingSchemaService reviews registrations held for approval. It is
+// implemented by *registry.Registry.
+type PendingSchemaService interface {
+	GetPendingSchema(ctx context.Context, id string) (*storage.PendingSchemaRecord, error)
+	ListPendingSchemas(ctx context.Context, registryCtx string, state string) ([]*storage.PendingSchemaRecord, error)
+	ApprovePendingSchema(ctx context.Context, id string, reviewer registry.Reviewer, reason string) (*storage.PendingSchemaRecord, *storage.SchemaRecord, error)
+	RejectPendingSchema(ctx context.Context, id string, reviewer registry.Reviewer, reason string) (*storage.PendingSchemaRecord, error)
+	GetMode(ctx context.Context, registryCtx string, subject string) (string, error)
+}
+
+// SetPendingSchemas sets the service behind /admin/pending-schemas.
+func (h *AdminHandler) SetPendingSchemas(s PendingSchemaService) {
+	h.pendingSchemas = s
+}
+
+// ListPendingSchemas handles GET /admin/pending-schemas. The context and
+// state query parameters filter the list; without state, only schemas
+// awaiting review are listed.
+func (h *AdminHandler) ListPendingSchemas(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	state := strings.ToUpper(r.URL.Query().Get("state"))
+	switch state {
+	case "":
+		state = storage.PendingSchemaPending
+	case "ALL":
+		state = ""
+	case storage.PendingSchemaPending, storage.PendingSchemaApproved, storage.PendingSchemaRejected:
+	default:
+		writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidReview,
+			fmt.Sprintf("Invalid state '%s'. Accepted states are PENDING, APPROVED, REJECTED and ALL", state))
+		return
+	}
+
+	pending, err := h.pendingSchemas.ListPendingSchemas(r.Context(), r.URL.Query().Get("context"), state)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	resp := types.PendingSchemasListResponse{PendingSchemas: make([]types.PendingSchemaResponse, 0, len(pending))}
+	for _, p := range pending {
+		resp.PendingSchemas = append(resp.PendingSchemas, pendingSchemaToResponse(p))
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// GetPendingSchema handles GET /admin/pending-schemas/{id}
+func (h *AdminHandler) GetPendingSchema(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	pending, err := h.pendingSchemas.GetPendingSchema(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, pendingSchemaToResponse(pending))
+}
+
+// ApprovePendingSchema handles POST /admin/pending-schemas/{id}/approve. The
+// schema is registered, getting its version and ID, as if its submitter had
+// registered it now, so it is checked against the subject's current
+// versions and mode.
+func (h *AdminHandler) ApprovePendingSchema(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionSchemaApprove) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	req, ok := decodeReviewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "pending_schema"
+		hints.TargetID = id
+	}
+
+	pending, err := h.pendingSchemas.GetPendingSchema(r.Context(), id)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	mode, err := h.pendingSchemas.GetMode(r.Context(), pending.Context, pending.Subject)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if mode == "READONLY" || mode == "READONLY_OVERRIDE" || mode == "IMPORT" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
+			fmt.Sprintf("Subject '%s' is in %s mode", pending.Subject, mode))
+		return
+	}
+
+	pending, schema, err := h.pendingSchemas.ApprovePendingSchema(r.Context(), id, reviewerOf(r), req.Reason)
+	if err != nil {
+		writeRegisterError(w, r, err)
+		return
+	}
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.Context = pending.Context
+		hints.SchemaType = string(schema.SchemaType)
+		hints.SchemaID = schema.ID
+		hints.Version = schema.Version
+	}
+	writeAdminJSON(w, http.StatusOK, pendingSchemaToResponse(pending))
+}
+
+// RejectPendingSchema handles POST /admin/pending-schemas/{id}/reject. A
+// reason is required.
+func (h *AdminHandler) RejectPendingSchema(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionSchemaApprove) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	req, ok := decodeReviewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "pending_schema"
+		hints.TargetID = id
+	}
+
+	pending, err := h.pendingSchemas.RejectPendingSchema(r.Context(), id, reviewerOf(r), req.Reason)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.Context = pending.Context
+	}
+	writeAdminJSON(w, http.StatusOK, pendingSchemaToResponse(pending))
+}
+
+// reviewerOf returns the user reviewing a pending schema.
+func reviewerOf(r *http.Request) registry.Reviewer {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		return registry.Reviewer{}
+	}
+	return registry.Reviewer{Username: user.Username, Role: user.Role}
+}
+
+// decodeReviewRequest decodes the optional body of an approval or rejection.
+func decodeReviewRequest(w http.ResponseWriter, r *http.Request) (*types.ReviewPendingSchemaRequest, bool) {
+	var req types.ReviewPendingSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidReview, "Invalid request body")
+		return nil, false
+	}
+	return &req, true
+}
+
+func pendingSchemaToResponse(p *storage.PendingSchemaRecord) types.PendingSchemaResponse {
+	resp := types.PendingSchemaResponse{
+		ID:          p.ID,
+		Context:     p.Context,
+		Subject:     p.Subject,
+		State:       p.State,
+		SubmittedBy: p.SubmittedBy,
+		Reviewers:   p.Reviewers,
+		ReviewedBy:  p.ReviewedBy,
+		Reason:      p.Reason,
+		SchemaID:    p.SchemaID,
+		Version:     p.Version,
+		CreatedAt:   p.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   p.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if req, err := registry.PendingSchemaRequestOf(p); err == nil {
+		resp.Schema = req.Schema
+		resp.SchemaType = string(req.SchemaType)
+		resp.References = req.References
+		resp.Metadata = req.Metadata
+		resp.RuleSet = req.RuleSet
+		resp.Normalize = req.Normalize
+	}
+	return resp
+}
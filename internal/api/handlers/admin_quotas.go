@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// QuotaService reports and resets registration quota usage. It is
+// implemented by *registry.Registry.
+type QuotaService interface {
+	QuotaUsage() []registry.QuotaUsage
+	ResetQuotaUsage(rule, principal, registryCtx string) error
+}
+
+// SetQuotas sets the service behind /admin/quotas.
+func (h *AdminHandler) SetQuotas(s QuotaService) {
+	h.quotas = s
+}
+
+// ListQuotas handles GET /admin/quotas. Every configured quota rule is
+// listed with the versions each principal registered today and the
+// subjects created in each context. Counts are this instance's.
+func (h *AdminHandler) ListQuotas(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	usage := h.quotas.QuotaUsage()
+	resp := types.QuotasListResponse{Quotas: make([]types.QuotaResponse, 0, len(usage))}
+	for _, u := range usage {
+		resp.Quotas = append(resp.Quotas, types.QuotaResponse{
+			Name:                  u.Rule.Name,
+			Principals:            u.Rule.Principals,
+			Roles:                 u.Rule.Roles,
+			Contexts:              u.Rule.Contexts,
+			MaxVersionsPerDay:     u.Rule.MaxVersionsPerDay,
+			MaxSubjectsPerContext: u.Rule.MaxSubjectsPerContext,
+			Day:                   u.Day,
+			VersionsToday:         u.VersionsToday,
+			SubjectsByContext:     u.SubjectsByContext,
+		})
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// ResetQuota handles POST /admin/quotas/{name}/reset. The principal query
+// parameter resets only that principal's version count and the context
+// parameter only that context's subject count; without either, every
+// counter of the rule is reset.
+func (h *AdminHandler) ResetQuota(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+	name := chi.URLParam(r, "name")
+	principal := r.URL.Query().Get("principal")
+	registryCtx := r.URL.Query().Get("context")
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "quota"
+		hints.TargetID = name
+	}
+
+	if err := h.quotas.ResetQuotaUsage(name, principal, registryCtx); err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
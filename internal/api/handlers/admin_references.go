@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// ReferenceService finds dangling schema references. It is implemented by
+// *registry.Registry.
+type ReferenceService interface {
+	OrphanedReferences(ctx context.Context, registryCtx string) ([]registry.OrphanedReference, error)
+}
+
+// SetReferences sets the service behind /admin/references.
+func (h *AdminHandler) SetReferences(s ReferenceService) {
+	h.references = s
+}
+
+// ListOrphanedReferences handles GET /admin/references/orphans. It lists
+// the references that point to missing or soft-deleted subject versions,
+// or to versions stored again after the referencing one. The context query
+// parameter limits the scan to one context; without it every context is
+// scanned.
+func (h *AdminHandler) ListOrphanedReferences(w http.ResponseWriter, r *http.Request) {
+	if !h.requireInstanceAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	orphans, err := h.references.OrphanedReferences(r.Context(), r.URL.Query().Get("context"))
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	resp := types.OrphanedReferencesResponse{Orphans: make([]types.OrphanedReferenceResponse, 0, len(orphans))}
+	for _, o := range orphans {
+		resp.Orphans = append(resp.Orphans, types.OrphanedReferenceResponse{
+			Context:   o.Context,
+			Subject:   o.Subject,
+			Version:   o.Version,
+			ID:        o.ID,
+			Deleted:   o.Deleted,
+			Reference: o.Reference,
+			Kind:      o.Kind,
+			Message:   o.Message,
+		})
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// TenantService manages tenants. It is implemented by *registry.Registry.
+type TenantService interface {
+	CreateTenant(ctx context.Context, record *storage.TenantRecord) error
+	GetTenant(ctx context.Context, name string) (*storage.TenantRecord, error)
+	ListTenants(ctx context.Context) ([]*storage.TenantRecord, error)
+	UpdateTenant(ctx context.Context, record *storage.TenantRecord) error
+	DeleteTenant(ctx context.Context, name string) error
+	TenantContexts(ctx context.Context, tenant string) ([]string, error)
+}
+
+// SetTenants sets the tenant service behind /admin/tenants and used to
+// validate the tenant of new users. Without it users cannot be assigned to
+// a tenant.
+func (h *AdminHandler) SetTenants(t TenantService) {
+	h.tenants = t
+}
+
+// ListTenants handles GET /admin/tenants
+func (h *AdminHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	if !h.requireTenantAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	tenants, err := h.tenants.ListTenants(r.Context())
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	resp := types.TenantsListResponse{Tenants: make([]types.TenantResponse, 0, len(tenants))}
+	for _, t := range tenants {
+		tr, err := h.tenantToResponse(r.Context(), t)
+		if err != nil {
+			writeTenantError(w, err)
+			return
+		}
+		resp.Tenants = append(resp.Tenants, tr)
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// CreateTenant handles POST /admin/tenants
+func (h *AdminHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.requireTenantAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+
+	var req types.TenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidTenant, "Invalid request body")
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "tenant"
+		hints.TargetID = req.Name
+	}
+
+	tenant := &storage.TenantRecord{Name: req.Name, Description: req.Description}
+	if err := h.tenants.CreateTenant(r.Context(), tenant); err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	resp, err := h.tenantToResponse(r.Context(), tenant)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, resp)
+}
+
+// GetTenant handles GET /admin/tenants/{name}
+func (h *AdminHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.requireTenantAdmin(w, r, auth.PermissionAdminRead) {
+		return
+	}
+
+	tenant, err := h.tenants.GetTenant(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	resp, err := h.tenantToResponse(r.Context(), tenant)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// UpdateTenant handles PUT /admin/tenants/{name}
+func (h *AdminHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.requireTenantAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+
+	var req types.TenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, types.ErrorCodeInvalidTenant, "Invalid request body")
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "tenant"
+		hints.TargetID = name
+	}
+
+	tenant := &storage.TenantRecord{Name: name, Description: req.Description}
+	if err := h.tenants.UpdateTenant(r.Context(), tenant); err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	resp, err := h.tenantToResponse(r.Context(), tenant)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, resp)
+}
+
+// DeleteTenant handles DELETE /admin/tenants/{name}. A tenant that still
+// owns contexts is refused with 422.
+func (h *AdminHandler) DeleteTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.requireTenantAdmin(w, r, auth.PermissionAdminWrite) {
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "tenant"
+		hints.TargetID = name
+	}
+
+	if err := h.tenants.DeleteTenant(r.Context(), name); err != nil {
+		writeTenantError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireTenantAdmin checks that the caller may manage tenants: tenants are
+// an instance-wide resource, so only super admins outside any tenant can.
+func (h *AdminHandler) requireTenantAdmin(w http.ResponseWriter, r *http.Request, perm auth.Permission) bool {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeAdminError(w, http.StatusUnauthorized, types.ErrorCodeUnauthorized, "Authentication required")
+		return false
+	}
+	superAdmin := user.Role == string(auth.RoleSuperAdmin) || h.authorizer.IsSuperAdmin(user.Username)
+	if user.Tenant != "" || !superAdmin || !h.authorizer.HasPermission(user, perm) {
+		writeAdminError(w, http.StatusForbidden, types.ErrorCodeForbidden, "Tenant management requires a super admin outside any tenant")
+		return false
+	}
+	return true
+}
+
+func (h *AdminHandler) tenantToResponse(ctx context.Context, t *storage.TenantRecord) (types.TenantResponse, error) {
+	contexts, err := h.tenants.TenantContexts(ctx, t.Name)
+	if err != nil {
+		return types.TenantResponse{}, err
+	}
+	return types.TenantResponse{
+		Name:        t.Name,
+		Description: t.Description,
+		Encrypted:   t.EncryptedKey != "",
+		Contexts:    contexts,
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   t.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// writeTenantError writes the admin error response for a tenant operation.
+func writeTenantError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage.ErrTenantNotFound):
+		writeAdminError(w, http.StatusNotFound, types.ErrorCodeTenantNotFound, "Tenant not found")
+	case errors.Is(err, storage.ErrTenantExists):
+		writeAdminError(w, http.StatusConflict, types.ErrorCodeTenantExists, "Tenant already exists")
+	case errors.Is(err, registry.ErrInvalidTenant):
+		writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidTenant, err.Error())
+	case errors.Is(err, registry.ErrTenantNotEmpty):
+		writeAdminError(w, http.StatusUnprocessableEntity, types.ErrorCodeTenantNotEmpty, err.Error())
+	default:
+		slog.Error("internal server error", "error", err)
+		writeAdminError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+	}
+}
@@ -5,17 +5,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
 	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
 	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+	"github.com/axonops/axonops-schema-registry/internal/usage"
 )
 
 func setupTestAdminHandler(t *testing.T) (*AdminHandler, *auth.Service) {
@@ -36,19 +42,19 @@ func withUser(req *http.Request, user *auth.User) *http.Request {
 }
 
 func superAdmin() *auth.User {
-	return &auth.User{ID: 1, Username: "admin", Role: "super_admin", Method: "basic"}
+	return &auth.User{UserID: 1, Username: "admin", Role: "super_admin", Method: "basic"}
 }
 
 func adminUser() *auth.User {
-	return &auth.User{ID: 2, Username: "admin2", Role: "admin", Method: "basic"}
+	return &auth.User{UserID: 2, Username: "admin2", Role: "admin", Method: "basic"}
 }
 
 func readonlyUser() *auth.User {
-	return &auth.User{ID: 3, Username: "reader", Role: "readonly", Method: "basic"}
+	return &auth.User{UserID: 3, Username: "reader", Role: "readonly", Method: "basic"}
 }
 
 func developerUser() *auth.User {
-	return &auth.User{ID: 4, Username: "dev", Role: "developer", Method: "basic"}
+	return &auth.User{UserID: 4, Username: "dev", Role: "developer", Method: "basic"}
 }
 
 func createTestUser(t *testing.T, h *AdminHandler, username, role string) int64 {
@@ -538,6 +544,68 @@ func TestListAPIKeys_InvalidUserID(t *testing.T) {
 	}
 }
 
+func TestGetAPIKeyUsage(t *testing.T) {
+	h, svc := setupTestAdminHandler(t)
+	ctx := context.Background()
+	userID := createTestUser(t, h, "alice", "admin")
+
+	used, err := svc.CreateAPIKey(ctx, auth.CreateAPIKeyRequest{UserID: userID, Name: "used", Role: "readonly", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("failed to create API key: %v", err)
+	}
+	if _, err := svc.CreateAPIKey(ctx, auth.CreateAPIKeyRequest{UserID: userID, Name: "idle", Role: "readonly", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("failed to create API key: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := svc.ValidateAPIKey(ctx, used.Key); err != nil {
+			t.Fatalf("ValidateAPIKey: %v", err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Get("/admin/apikeys/usage", h.GetAPIKeyUsage)
+
+	req := withUser(httptest.NewRequest("GET", "/admin/apikeys/usage?since=30d", nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.APIKeyUsageResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Since == "" || len(resp.APIKeys) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	for _, k := range resp.APIKeys {
+		switch k.Name {
+		case "used":
+			if k.Requests != 3 || len(k.Days) != 1 || k.Days[0].Requests != 3 || k.LastUsed == nil || k.Username != "alice" {
+				t.Errorf("unexpected usage of the used key: %+v", k)
+			}
+		case "idle":
+			if k.Requests != 0 || len(k.Days) != 0 || k.LastUsed != nil {
+				t.Errorf("unexpected usage of the idle key: %+v", k)
+			}
+		}
+	}
+
+	req = withUser(httptest.NewRequest("GET", "/admin/apikeys/usage?unused=true", nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp = types.APIKeyUsageResponse{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.APIKeys) != 1 || resp.APIKeys[0].Name != "idle" {
+		t.Errorf("expected only the idle key, got %+v", resp.APIKeys)
+	}
+
+	req = withUser(httptest.NewRequest("GET", "/admin/apikeys/usage?since=lately", nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid since, got %d", w.Code)
+	}
+}
+
 // --- CreateAPIKey ---
 
 func TestCreateAPIKey_Success(t *testing.T) {
@@ -551,7 +619,7 @@ func TestCreateAPIKey_Success(t *testing.T) {
 	bodyBytes, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/admin/apikeys", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: userID, Username: "alice", Role: "super_admin", Method: "basic"})
+	req = withUser(req, &auth.User{UserID: userID, Username: "alice", Role: "super_admin", Method: "basic"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -655,7 +723,7 @@ func TestCreateAPIKey_UserNotInDB(t *testing.T) {
 	bodyBytes, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/admin/apikeys", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
-	req = withUser(req, &auth.User{ID: 0, Username: "config-user", Role: "super_admin", Method: "basic"})
+	req = withUser(req, &auth.User{UserID: 0, Username: "config-user", Role: "super_admin", Method: "basic"})
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -924,6 +992,491 @@ func TestListRoles(t *testing.T) {
 	}
 }
 
+// --- Audit history ---
+
+func TestQueryAuditEvents(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	history := auth.NewAuditHistory(10)
+	h.SetAuditHistory(history)
+
+	now := time.Now()
+	history.Add(&auth.AuditEvent{Timestamp: now.Add(-48 * time.Hour), EventType: auth.AuditEventSubjectDeleteSoft, Outcome: "success", ActorID: "alice", TargetType: "subject", TargetID: "orders-value"})
+	history.Add(&auth.AuditEvent{Timestamp: now.Add(-time.Hour), EventType: auth.AuditEventSubjectDeletePermanent, Outcome: "success", ActorID: "alice", TargetType: "subject", TargetID: "orders-value"})
+	history.Add(&auth.AuditEvent{Timestamp: now.Add(-time.Minute), EventType: auth.AuditEventSchemaRegister, Outcome: "success", ActorID: "bob", TargetType: "subject", TargetID: "users-value"})
+
+	r := chi.NewRouter()
+	r.Get("/admin/audit", h.QueryAuditEvents)
+
+	tests := []struct {
+		query string
+		want  []auth.AuditEventType
+	}{
+		{"", []auth.AuditEventType{auth.AuditEventSchemaRegister, auth.AuditEventSubjectDeletePermanent, auth.AuditEventSubjectDeleteSoft}},
+		{"?principal=alice&subject=orders-value&since=24h", []auth.AuditEventType{auth.AuditEventSubjectDeletePermanent}},
+		{"?event_type=schema_register", []auth.AuditEventType{auth.AuditEventSchemaRegister}},
+		{"?limit=1", []auth.AuditEventType{auth.AuditEventSchemaRegister}},
+		{"?until=" + now.Add(-24*time.Hour).UTC().Format(time.RFC3339), []auth.AuditEventType{auth.AuditEventSubjectDeleteSoft}},
+	}
+	for _, tt := range tests {
+		req := withUser(httptest.NewRequest("GET", "/admin/audit"+tt.query, nil), adminUser())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", tt.query, w.Code, w.Body.String())
+		}
+		var resp types.AuditEventsResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("%q: decode: %v", tt.query, err)
+		}
+		if len(resp.Events) != len(tt.want) {
+			t.Fatalf("%q: expected %d events, got %d", tt.query, len(tt.want), len(resp.Events))
+		}
+		for i, want := range tt.want {
+			if resp.Events[i].EventType != want {
+				t.Errorf("%q: event %d: expected %s, got %s", tt.query, i, want, resp.Events[i].EventType)
+			}
+		}
+	}
+}
+
+func TestQueryAuditEvents_InvalidParams(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	h.SetAuditHistory(auth.NewAuditHistory(10))
+
+	r := chi.NewRouter()
+	r.Get("/admin/audit", h.QueryAuditEvents)
+
+	for _, query := range []string{"?since=yesterday", "?until=-1h", "?limit=0", "?limit=abc"} {
+		req := withUser(httptest.NewRequest("GET", "/admin/audit"+query, nil), adminUser())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestQueryAuditEvents_HistoryDisabled(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Get("/admin/audit", h.QueryAuditEvents)
+
+	req := withUser(httptest.NewRequest("GET", "/admin/audit", nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	var resp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.ErrorCode != types.ErrorCodeAuditHistoryDisabled {
+		t.Errorf("expected error_code %d, got %d", types.ErrorCodeAuditHistoryDisabled, resp.ErrorCode)
+	}
+}
+
+func TestQueryAuditEvents_RequiresAdminRead(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	h.SetAuditHistory(auth.NewAuditHistory(10))
+
+	r := chi.NewRouter()
+	r.Get("/admin/audit", h.QueryAuditEvents)
+
+	req := withUser(httptest.NewRequest("GET", "/admin/audit", nil), developerUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+// --- Schema usage ---
+
+func TestGetSchemaUsage(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	store := memory.NewStore()
+	tracker := usage.NewTracker(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	h.SetSchemaUsage(tracker)
+
+	ctx := context.Background()
+	for _, rec := range []*storage.SchemaRecord{
+		{Subject: "orders-value", SchemaType: storage.SchemaTypeAvro, Schema: `"string"`, Fingerprint: "a"},
+		{Subject: "legacy-value", SchemaType: storage.SchemaTypeAvro, Schema: `"int"`, Fingerprint: "b"},
+	} {
+		if err := store.CreateSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+	}
+	tracker.RecordID(".", 1)
+	tracker.RecordID(".", 1)
+	tracker.RecordVersion(".", "orders-value", 1, 1)
+
+	r := chi.NewRouter()
+	r.Get("/admin/usage/schemas", h.GetSchemaUsage)
+
+	get := func(query string) types.SchemaUsageResponse {
+		t.Helper()
+		req := withUser(httptest.NewRequest("GET", "/admin/usage/schemas"+query, nil), adminUser())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", query, w.Code, w.Body.String())
+		}
+		var resp types.SchemaUsageResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("%q: decode: %v", query, err)
+		}
+		return resp
+	}
+
+	resp := get("?since=30d")
+	if resp.Since == "" {
+		t.Error("expected since to be set")
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	want := []types.SchemaUsageEntry{
+		{Context: ".", ID: 1, Fetches: 2, LastFetched: today},
+		{Context: ".", ID: 1, Subject: "orders-value", Version: 1, Fetches: 1, LastFetched: today},
+	}
+	if len(resp.Schemas) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), resp.Schemas)
+	}
+	for i := range want {
+		if resp.Schemas[i] != want[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, want[i], resp.Schemas[i])
+		}
+	}
+
+	unused := get("?unused=true")
+	if unused.Since != "" {
+		t.Errorf("expected no since, got %q", unused.Since)
+	}
+	if len(unused.Schemas) != 1 || unused.Schemas[0].Subject != "legacy-value" || unused.Schemas[0].Fetches != 0 {
+		t.Errorf("expected only legacy-value to be unused, got %+v", unused.Schemas)
+	}
+}
+
+func TestGetSchemaUsage_InvalidSince(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	h.SetSchemaUsage(usage.NewTracker(memory.NewStore(), slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	r := chi.NewRouter()
+	r.Get("/admin/usage/schemas", h.GetSchemaUsage)
+
+	for _, query := range []string{"?since=lately", "?since=-1d"} {
+		req := withUser(httptest.NewRequest("GET", "/admin/usage/schemas"+query, nil), adminUser())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetSchemaUsage_Disabled(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Get("/admin/usage/schemas", h.GetSchemaUsage)
+
+	req := withUser(httptest.NewRequest("GET", "/admin/usage/schemas", nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	var resp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.ErrorCode != types.ErrorCodeUsageTrackingDisabled {
+		t.Errorf("expected error_code %d, got %d", types.ErrorCodeUsageTrackingDisabled, resp.ErrorCode)
+	}
+}
+
+func TestGetClientUsage(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	tracker := usage.NewClientTracker(0)
+	h.SetClientUsage(tracker)
+	tracker.Record("confluent-kafka-python/2.3.0", "GET", "/schemas/ids/{id}", "orders-svc")
+	tracker.Record("confluent-kafka-python/2.3.0", "GET", "/schemas/ids/{id}", "orders-svc")
+	tracker.Record("Java/11.0.1", "POST", "/subjects/{subject}/versions", "legacy-app")
+
+	r := chi.NewRouter()
+	r.Get("/admin/usage/clients", h.GetClientUsage)
+
+	req := withUser(httptest.NewRequest("GET", "/admin/usage/clients?client=confluent-kafka-python&since=1h", nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.ClientUsageResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Clients) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", resp.Clients)
+	}
+	got := resp.Clients[0]
+	if got.ClientVersion != "2.3.0" || got.Endpoint != "/schemas/ids/{id}" || got.Principal != "orders-svc" || got.Requests != 2 {
+		t.Errorf("unexpected entry %+v", got)
+	}
+
+	req = withUser(httptest.NewRequest("GET", "/admin/usage/clients?since=lately", nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid since: expected 400, got %d", w.Code)
+	}
+}
+
+func TestGetClientUsage_Disabled(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Get("/admin/usage/clients", h.GetClientUsage)
+
+	req := withUser(httptest.NewRequest("GET", "/admin/usage/clients", nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	var resp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.ErrorCode != types.ErrorCodeClientTrackingDisabled {
+		t.Errorf("expected error_code %d, got %d", types.ErrorCodeClientTrackingDisabled, resp.ErrorCode)
+	}
+}
+
+func TestAuthCache_RefreshAndReport(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Get("/admin/auth-cache", h.GetAuthCacheReport)
+	r.Post("/admin/auth-cache/refresh", h.RefreshAuthCache)
+
+	// No check has run yet
+	req := withUser(httptest.NewRequest("GET", "/admin/auth-cache", nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before any check, got %d: %s", w.Code, w.Body.String())
+	}
+	var errResp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&errResp)
+	if errResp.ErrorCode != types.ErrorCodeAuthCacheNotChecked {
+		t.Errorf("expected error_code %d, got %d", types.ErrorCodeAuthCacheNotChecked, errResp.ErrorCode)
+	}
+
+	req = withUser(httptest.NewRequest("POST", "/admin/auth-cache/refresh", nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 from refresh, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.AuthCacheReportResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Trigger != auth.CacheCheckForced || resp.Diverged {
+		t.Errorf("unexpected report %+v", resp)
+	}
+
+	req = withUser(httptest.NewRequest("GET", "/admin/auth-cache", nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 after refresh, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAuthCache_RequiresInstanceAdmin(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Post("/admin/auth-cache/refresh", h.RefreshAuthCache)
+
+	tenantAdmin := adminUser()
+	tenantAdmin.Tenant = "acme"
+	for _, user := range []*auth.User{readonlyUser(), tenantAdmin} {
+		req := withUser(httptest.NewRequest("POST", "/admin/auth-cache/refresh", nil), user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", user.Username, w.Code)
+		}
+	}
+}
+
+// --- Grants ---
+
+func createTestGrant(t *testing.T, h *AdminHandler, body string) types.GrantResponse {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Post("/admin/grants", h.CreateGrant)
+
+	req := withUser(httptest.NewRequest("POST", "/admin/grants", strings.NewReader(body)), superAdmin())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.GrantResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	return resp
+}
+
+func TestCreateGrant_Success(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	grant := createTestGrant(t, h, `{"username":"dev-user","role":"developer","context":"dev"}`)
+	if grant.ID == 0 {
+		t.Error("expected non-zero grant ID")
+	}
+	if grant.Context != ".dev" {
+		t.Errorf("expected normalized context .dev, got %q", grant.Context)
+	}
+	if grant.CreatedBy != "admin" {
+		t.Errorf("expected created_by admin, got %q", grant.CreatedBy)
+	}
+}
+
+func TestCreateGrant_Validation(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Post("/admin/grants", h.CreateGrant)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"no principal", `{"role":"developer"}`, types.ErrorCodeInvalidSchema},
+		{"both principals", `{"username":"a","api_key_id":1,"role":"developer"}`, types.ErrorCodeInvalidSchema},
+		{"missing role", `{"username":"a"}`, types.ErrorCodeInvalidRole},
+		{"super_admin", `{"username":"a","role":"super_admin"}`, types.ErrorCodeInvalidRole},
+		{"invalid context", `{"username":"a","role":"developer","context":".bad ctx"}`, types.ErrorCodeInvalidContext},
+		{"unknown api key", `{"api_key_id":42,"role":"developer"}`, types.ErrorCodeAPIKeyNotFound},
+	}
+	for _, tt := range tests {
+		req := withUser(httptest.NewRequest("POST", "/admin/grants", strings.NewReader(tt.body)), superAdmin())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", tt.name, w.Code)
+			continue
+		}
+		var resp types.ErrorResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.ErrorCode != tt.code {
+			t.Errorf("%s: expected error_code %d, got %d", tt.name, tt.code, resp.ErrorCode)
+		}
+	}
+}
+
+func TestCreateGrant_RequiresAdminWrite(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Post("/admin/grants", h.CreateGrant)
+
+	body := `{"username":"dev-user","role":"admin"}`
+	req := withUser(httptest.NewRequest("POST", "/admin/grants", strings.NewReader(body)), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestListGrants_FilterByUsername(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	createTestGrant(t, h, `{"username":"alice","role":"developer","context":".dev"}`)
+	createTestGrant(t, h, `{"username":"bob","role":"readonly","subject_prefix":"orders-"}`)
+
+	r := chi.NewRouter()
+	r.Get("/admin/grants", h.ListGrants)
+
+	req := withUser(httptest.NewRequest("GET", "/admin/grants?username=bob", nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp types.GrantsListResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Grants) != 1 || resp.Grants[0].Username != "bob" || resp.Grants[0].SubjectPrefix != "orders-" {
+		t.Errorf("expected only bob's grant, got %+v", resp.Grants)
+	}
+}
+
+func TestGetAndDeleteGrant(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+	grant := createTestGrant(t, h, `{"username":"alice","role":"developer"}`)
+
+	r := chi.NewRouter()
+	r.Get("/admin/grants/{id}", h.GetGrant)
+	r.Delete("/admin/grants/{id}", h.DeleteGrant)
+	path := fmt.Sprintf("/admin/grants/%d", grant.ID)
+
+	req := withUser(httptest.NewRequest("GET", path, nil), adminUser())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	req = withUser(httptest.NewRequest("DELETE", path, nil), superAdmin())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+
+	req = withUser(httptest.NewRequest("GET", path, nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", w.Code)
+	}
+	var resp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.ErrorCode != types.ErrorCodeGrantNotFound {
+		t.Errorf("expected error_code %d, got %d", types.ErrorCodeGrantNotFound, resp.ErrorCode)
+	}
+}
+
+func TestDeleteUser_RemovesGrants(t *testing.T) {
+	h, svc := setupTestAdminHandler(t)
+	userID := createTestUser(t, h, "alice", "readonly")
+	createTestGrant(t, h, `{"username":"alice","role":"developer","context":".dev"}`)
+	createTestGrant(t, h, `{"username":"bob","role":"developer"}`)
+
+	if err := svc.DeleteUser(context.Background(), userID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	grants, err := svc.ListGrants(context.Background())
+	if err != nil {
+		t.Fatalf("ListGrants: %v", err)
+	}
+	if len(grants) != 1 || grants[0].Username != "bob" {
+		t.Errorf("expected only bob's grant to remain, got %+v", grants)
+	}
+}
+
 // --- Admin content type ---
 
 func TestAdmin_ContentType(t *testing.T) {
@@ -942,3 +1495,91 @@ func TestAdmin_ContentType(t *testing.T) {
 		t.Errorf("expected Content-Type application/json, got %s", ct)
 	}
 }
+
+func TestShareTokens_Lifecycle(t *testing.T) {
+	h, svc := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Post("/admin/share-tokens", h.CreateShareToken)
+	r.Get("/admin/share-tokens", h.ListShareTokens)
+	r.Get("/admin/share-tokens/{id}", h.GetShareToken)
+	r.Delete("/admin/share-tokens/{id}", h.DeleteShareToken)
+
+	body := `{"name":"acme","context":"partners","subject":"orders-value","expires_in":86400}`
+	req := withUser(httptest.NewRequest("POST", "/admin/share-tokens", strings.NewReader(body)), superAdmin())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created types.CreateShareTokenResponse
+	json.NewDecoder(w.Body).Decode(&created)
+	if !strings.HasPrefix(created.Token, auth.ShareTokenPrefix) {
+		t.Errorf("expected token with prefix %q, got %q", auth.ShareTokenPrefix, created.Token)
+	}
+	if created.Context != ".partners" || created.Subject != "orders-value" || created.CreatedBy != "admin" {
+		t.Errorf("unexpected share token: %+v", created)
+	}
+	if _, err := svc.ValidateShareToken(context.Background(), created.Token); err != nil {
+		t.Errorf("expected created token to validate: %v", err)
+	}
+
+	req = withUser(httptest.NewRequest("GET", "/admin/share-tokens", nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var list types.ShareTokensListResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	if len(list.ShareTokens) != 1 || list.ShareTokens[0].ID != created.ID {
+		t.Fatalf("expected the created share token, got %+v", list.ShareTokens)
+	}
+	if strings.Contains(w.Body.String(), created.Token) {
+		t.Error("listing must not include the raw token")
+	}
+
+	id := strconv.FormatInt(created.ID, 10)
+	req = withUser(httptest.NewRequest("DELETE", "/admin/share-tokens/"+id, nil), superAdmin())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if _, err := svc.ValidateShareToken(context.Background(), created.Token); err == nil {
+		t.Error("expected deleted token to be rejected")
+	}
+
+	req = withUser(httptest.NewRequest("GET", "/admin/share-tokens/"+id, nil), adminUser())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var errResp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&errResp)
+	if w.Code != http.StatusNotFound || errResp.ErrorCode != types.ErrorCodeShareTokenNotFound {
+		t.Errorf("expected 404 with %d, got %d %+v", types.ErrorCodeShareTokenNotFound, w.Code, errResp)
+	}
+}
+
+func TestCreateShareToken_Validation(t *testing.T) {
+	h, _ := setupTestAdminHandler(t)
+
+	r := chi.NewRouter()
+	r.Post("/admin/share-tokens", h.CreateShareToken)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing name", `{"context":"partners","expires_in":60}`},
+		{"missing context", `{"name":"acme","expires_in":60}`},
+		{"global context", `{"name":"acme","context":".__GLOBAL","expires_in":60}`},
+		{"qualified subject", `{"name":"acme","context":"partners","subject":":.other:orders","expires_in":60}`},
+		{"missing expiry", `{"name":"acme","context":"partners"}`},
+		{"expiry too long", `{"name":"acme","context":"partners","expires_in":63072000}`},
+	}
+	for _, tt := range tests {
+		req := withUser(httptest.NewRequest("POST", "/admin/share-tokens", strings.NewReader(tt.body)), superAdmin())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", tt.name, w.Code, w.Body.String())
+		}
+	}
+}
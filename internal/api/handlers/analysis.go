@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/axonops/axonops-schema-registry/internal/analysis"
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
@@ -39,7 +41,7 @@ func (h *Handler) ValidateSchema(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	result, err := h.registry.ValidateSchema(r.Context(), registryCtx, req.Schema, st, nil)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{
@@ -52,8 +54,9 @@ func (h *Handler) ValidateSchema(w http.ResponseWriter, r *http.Request) {
 // NormalizeSchema handles POST /schemas/normalize
 func (h *Handler) NormalizeSchema(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Schema     string `json:"schema"`
-		SchemaType string `json:"schemaType"`
+		Schema     string              `json:"schema"`
+		SchemaType string              `json:"schemaType"`
+		References []storage.Reference `json:"references"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
@@ -71,15 +74,56 @@ func (h *Handler) NormalizeSchema(w http.ResponseWriter, r *http.Request) {
 	}
 
 	registryCtx := getRegistryContext(r)
-	result, err := h.registry.NormalizeSchema(r.Context(), registryCtx, req.Schema, st, nil)
+	result, err := h.registry.NormalizeSchema(r.Context(), registryCtx, req.Schema, st, req.References)
 	if err != nil {
-		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
+		writeRegistryError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"schema_type": string(st),
-		"canonical":   result.Normalized,
-		"fingerprint": result.Fingerprint,
+		"schema_type":              string(st),
+		"canonical":                result.Normalized,
+		"fingerprint":              result.Fingerprint,
+		"unnormalized_fingerprint": result.UnnormalizedFingerprint,
+		"changed":                  result.Changed,
+	})
+}
+
+// ConvertSchema handles POST /schemas/convert
+func (h *Handler) ConvertSchema(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Schema     string              `json:"schema"`
+		SchemaType string              `json:"schemaType"`
+		References []storage.Reference `json:"references"`
+		Target     string              `json:"target"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	if req.Schema == "" {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Schema is required")
+		return
+	}
+	if req.Target == "" {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeUnsupportedConversion, "Target is required")
+		return
+	}
+	st, ok := storage.ParseSchemaType(strings.ToUpper(req.SchemaType))
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema,
+			fmt.Sprintf("Invalid schema type '%s'. Accepted types are AVRO, PROTOBUF, and JSON", req.SchemaType))
+		return
+	}
+
+	registryCtx := getRegistryContext(r)
+	result, err := h.registry.ConvertSchema(r.Context(), registryCtx, req.Schema, st, req.References, req.Target)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schemaType": string(result.SchemaType),
+		"schema":     result.Schema,
 	})
 }
 
@@ -108,7 +152,7 @@ func (h *Handler) SearchSchemas(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -188,7 +232,7 @@ func (h *Handler) FindSchemasByField(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -288,7 +332,7 @@ func (h *Handler) FindSchemasByType(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -381,7 +425,7 @@ func (h *Handler) FindSimilarSchemas(w http.ResponseWriter, r *http.Request) {
 
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -640,7 +684,7 @@ func (h *Handler) MatchSubjects(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -871,6 +915,59 @@ func (h *Handler) DiffSchemas(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// DiffVersions handles GET /subjects/{subject}/versions/{version}/diff/{version2}
+func (h *Handler) DiffVersions(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+
+	var records [2]*storage.SchemaRecord
+	var schemaTypes [2]storage.SchemaType
+	for i, param := range []string{"version", "version2"} {
+		versionStr := chi.URLParam(r, param)
+		version, err := registry.ParseVersion(versionStr)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion,
+				fmt.Sprintf("The specified version '%s' is not a valid version id. Allowed values are between [1, 2^31-1] and the string \"latest\"", versionStr))
+			return
+		}
+		record, err := h.registry.GetSchemaBySubjectVersion(r.Context(), registryCtx, subject, version)
+		if err != nil {
+			if errors.Is(err, storage.ErrSubjectNotFound) {
+				writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Subject not found")
+				return
+			}
+			if errors.Is(err, storage.ErrVersionNotFound) {
+				writeError(w, http.StatusNotFound, types.ErrorCodeVersionNotFound, fmt.Sprintf("Version %s not found", versionStr))
+				return
+			}
+			writeRegistryError(w, err)
+			return
+		}
+		records[i] = record
+		schemaTypes[i] = record.SchemaType
+		if schemaTypes[i] == "" {
+			schemaTypes[i] = storage.SchemaTypeAvro
+		}
+	}
+
+	diff := analysis.DiffFields(
+		analysis.ExtractFields(records[0].Schema, schemaTypes[0]),
+		analysis.ExtractFields(records[1].Schema, schemaTypes[1]),
+	)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"subject":      subject,
+		"version1":     records[0].Version,
+		"version2":     records[1].Version,
+		"schema_type1": schemaTypes[0],
+		"schema_type2": schemaTypes[1],
+		"changes":      diff.Changes,
+		"summary":      diff.Summary,
+	})
+}
+
 // SuggestSchemaEvolution handles POST /subjects/{subject}/evolve
 func (h *Handler) SuggestSchemaEvolution(w http.ResponseWriter, r *http.Request) {
 	registryCtx, subject := resolveSubjectAndContext(r)
@@ -1254,7 +1351,7 @@ func (h *Handler) GetRegistryStatistics(w http.ResponseWriter, r *http.Request)
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1289,7 +1386,7 @@ func (h *Handler) CheckFieldConsistency(w http.ResponseWriter, r *http.Request)
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1339,7 +1436,7 @@ func (h *Handler) DetectSchemaPatterns(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1382,7 +1479,7 @@ func (h *Handler) CountSubjects(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{
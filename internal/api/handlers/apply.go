@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Apply handles POST /apply. It reconciles the registry with the desired
+// state in the body, or only plans the changes when dry_run=true.
+func (h *Handler) Apply(w http.ResponseWriter, r *http.Request) {
+	var req types.ApplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidDesiredState, "Invalid request body")
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "registry"
+	}
+
+	state := &registry.DesiredState{Contexts: make([]registry.DesiredContext, len(req.Contexts))}
+	for i, c := range req.Contexts {
+		dc := registry.DesiredContext{
+			Name:          c.Name,
+			Description:   c.Description,
+			Compatibility: c.Compatibility,
+			Mode:          c.Mode,
+			Subjects:      make([]registry.DesiredSubject, len(c.Subjects)),
+		}
+		for j, s := range c.Subjects {
+			ds := registry.DesiredSubject{
+				Name:          s.Name,
+				Compatibility: s.Compatibility,
+				Mode:          s.Mode,
+				Schemas:       make([]registry.DesiredSchema, len(s.Schemas)),
+			}
+			for k, schema := range s.Schemas {
+				ds.Schemas[k] = registry.DesiredSchema{
+					Schema:     schema.Schema,
+					SchemaType: storage.SchemaType(schema.SchemaType),
+					References: schema.References,
+				}
+			}
+			dc.Subjects[j] = ds
+		}
+		state.Contexts[i] = dc
+	}
+
+	result, err := h.registry.Apply(r.Context(), state, dryRun)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+
+	resp := types.ApplyResponse{
+		DryRun:  result.DryRun,
+		Failed:  result.Failed(),
+		Changes: make([]types.ApplyChangeResponse, len(result.Changes)),
+	}
+	applied := 0
+	for i, c := range result.Changes {
+		if c.Applied {
+			applied++
+		}
+		resp.Changes[i] = types.ApplyChangeResponse{
+			Action:  c.Action,
+			Context: c.Context,
+			Subject: c.Subject,
+			From:    c.From,
+			To:      c.To,
+			Version: c.Version,
+			ID:      c.ID,
+			Applied: c.Applied,
+			Error:   c.Error,
+		}
+	}
+	// As for imports: nothing applied is a failure, some applied a partial one.
+	if hints := auth.GetAuditHints(r.Context()); hints != nil && resp.Failed && !dryRun {
+		hints.Outcome = "failure"
+		if applied > 0 {
+			hints.Outcome = "partial_failure"
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
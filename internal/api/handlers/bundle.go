@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// bundleNameUnsafe matches characters that are not safe in a download file name.
+var bundleNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// GetSchemaBundle handles GET /subjects/{subject}/bundle
+// Returns the schema version and all of its transitive references as an
+// archive. ?version= selects the version (default latest) and ?format=
+// selects zip (default) or tar.
+func (h *Handler) GetSchemaBundle(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+
+	versionStr := r.URL.Query().Get("version")
+	if versionStr == "" {
+		versionStr = "latest"
+	}
+	version, err := registry.ParseVersion(versionStr)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion,
+			fmt.Sprintf("The specified version '%s' is not a valid version id. Allowed values are between [1, 2^31-1] and the string \"latest\"", versionStr))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "tar" {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema,
+			fmt.Sprintf("Invalid format '%s': must be zip or tar", format))
+		return
+	}
+
+	bundle, err := h.registry.GetSchemaBundle(r.Context(), registryCtx, subject, version)
+	if err != nil {
+		if errors.Is(err, registry.ErrReferenceCycle) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeReferenceCycle, err.Error())
+			return
+		}
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, fmt.Sprintf("Subject '%s' not found.", subject))
+			return
+		}
+		if errors.Is(err, storage.ErrVersionNotFound) || errors.Is(err, storage.ErrSchemaNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeVersionNotFound, "Version not found")
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	// Build the archive in memory so errors can still be reported as JSON.
+	var buf bytes.Buffer
+	if format == "tar" {
+		err = writeTarBundle(&buf, bundle)
+	} else {
+		err = writeZipBundle(&buf, bundle)
+	}
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+
+	name := fmt.Sprintf("%s-v%d.%s", bundleNameUnsafe.ReplaceAllString(subject, "_"), bundle.Version, format)
+	if format == "tar" {
+		w.Header().Set("Content-Type", "application/x-tar")
+	} else {
+		w.Header().Set("Content-Type", "application/zip")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// writeZipBundle writes the bundle files as a zip archive.
+func writeZipBundle(buf *bytes.Buffer, bundle *registry.SchemaBundle) error {
+	zw := zip.NewWriter(buf)
+	for _, f := range bundle.Files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.Path,
+			Method:   zip.Deflate,
+			Modified: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write([]byte(f.Content)); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeTarBundle writes the bundle files as an uncompressed tar archive.
+func writeTarBundle(buf *bytes.Buffer, bundle *registry.SchemaBundle) error {
+	tw := tar.NewWriter(buf)
+	for _, f := range bundle.Files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    f.Path,
+			Mode:    0o644,
+			Size:    int64(len(f.Content)),
+			ModTime: time.Now().UTC(),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write([]byte(f.Content)); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// bundleManifestFile is the manifest file name at the root of an imported bundle.
+const bundleManifestFile = "manifest.json"
+
+// ImportBundle handles POST /import/bundle
+// Imports a zip or tar archive of schema files described by a manifest.json
+// at the archive root. The bundle is validated as a whole and imported only
+// if every schema in it is valid.
+func (h *Handler) ImportBundle(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "subject"
+		hints.Context = registryCtx
+	}
+
+	// Bulk import requires IMPORT mode, as for POST /import/schemas.
+	mode, modeErr := h.registry.GetMode(r.Context(), registryCtx, "")
+	if modeErr != nil {
+		writeError(w, http.StatusInternalServerError, types.ErrorCodeStorageError, "Failed to check mode")
+		return
+	}
+	if mode != "IMPORT" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
+			"Import is not permitted. The registry must be in IMPORT mode to import schemas.")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	files, err := readBundleArchive(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, fmt.Sprintf("Invalid bundle: %v", err))
+		return
+	}
+
+	manifestData, ok := files[bundleManifestFile]
+	if !ok {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid bundle: missing "+bundleManifestFile)
+		return
+	}
+	var manifest types.BundleManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, fmt.Sprintf("Invalid bundle: malformed %s: %v", bundleManifestFile, err))
+		return
+	}
+	if len(manifest.Schemas) == 0 {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "No schemas provided")
+		return
+	}
+
+	importReqs := make([]registry.ImportSchemaRequest, len(manifest.Schemas))
+	for i, entry := range manifest.Schemas {
+		content, ok := files[registry.CleanBundlePath(entry.File)]
+		if !ok {
+			writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema,
+				fmt.Sprintf("Invalid bundle: file '%s' listed in %s not found", entry.File, bundleManifestFile))
+			return
+		}
+		schemaType := storage.SchemaType(strings.ToUpper(entry.SchemaType))
+		if schemaType == "" {
+			schemaType = storage.SchemaTypeAvro
+		}
+		importReqs[i] = registry.ImportSchemaRequest{
+			ID:         entry.ID,
+			Subject:    entry.Subject,
+			Version:    entry.Version,
+			SchemaType: schemaType,
+			Schema:     string(content),
+			References: entry.References,
+		}
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.SchemaID = importReqs[0].ID
+	}
+
+	start := time.Now()
+	result, err := h.registry.ImportBundle(r.Context(), registryCtx, importReqs)
+	if err != nil {
+		if result != nil {
+			w.Header().Set("X-Warning", err.Error())
+			h.emitPerSchemaAuditEvents(r, registryCtx, importReqs, result, start)
+			writeJSON(w, importStatusCode(result), importResultToResponse(result))
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	h.emitPerSchemaAuditEvents(r, registryCtx, importReqs, result, start)
+	writeJSON(w, importStatusCode(result), importResultToResponse(result))
+}
+
+// readBundleArchive extracts the regular files of a zip or tar archive,
+// keyed by their cleaned path. The format is detected from the content.
+func readBundleArchive(data []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			files[registry.CleanBundlePath(f.Name)] = content
+		}
+	case len(data) > 262 && string(data[257:262]) == "ustar":
+		tr := tar.NewReader(bytes.NewReader(data))
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			files[registry.CleanBundlePath(hdr.Name)] = content
+		}
+	default:
+		return nil, errors.New("unrecognized archive format, expected zip or tar")
+	}
+	return files, nil
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/consistency"
+)
+
+// VerifyConsistency handles POST /consistency/verify. It starts a background
+// job that scans the context's storage for invariant violations and reports
+// them; with repair set, ID sequences behind the highest schema ID are
+// advanced as well.
+func (h *Handler) VerifyConsistency(w http.ResponseWriter, r *http.Request) {
+	var params consistency.Params
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	h.submitJob(w, r, consistency.JobType, params)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// CreateContext handles POST /contexts
+func (h *Handler) CreateContext(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateContextRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidContext, "Invalid request body")
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidContext, "Context name is required")
+		return
+	}
+
+	rec := &storage.ContextRecord{
+		Name:          req.Name,
+		Description:   req.Description,
+		Owner:         req.Owner,
+		MaxSubjects:   req.MaxSubjects,
+		MaxSchemaSize: req.MaxSchemaSize,
+		Tenant:        req.Tenant,
+	}
+	// A tenant user's contexts always belong to their own tenant.
+	if user := auth.GetUser(r.Context()); user != nil && user.Tenant != "" {
+		rec.Tenant = user.Tenant
+	}
+
+	// Set audit hints early so target_id is captured even on failure.
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "context"
+		hints.TargetID = req.Name
+	}
+
+	if err := h.registry.CreateContext(r.Context(), rec); err != nil {
+		if errors.Is(err, storage.ErrContextExists) {
+			writeError(w, http.StatusConflict, types.ErrorCodeContextExists, "Context already exists: "+rec.Name)
+			return
+		}
+		if errors.Is(err, registry.ErrInvalidContext) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidContext, err.Error())
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetID = rec.Name
+		hints.Context = rec.Name
+		hints.AfterHash = hashContext(rec)
+	}
+
+	writeJSON(w, http.StatusOK, contextResponse(rec))
+}
+
+// GetContextSettings handles GET /contexts/{context}/settings
+func (h *Handler) GetContextSettings(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+
+	rec, err := h.registry.GetContext(r.Context(), registryCtx)
+	if err != nil {
+		if errors.Is(err, storage.ErrContextNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeContextNotFound, "Context not found: "+registryCtx)
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contextResponse(rec))
+}
+
+// UpdateContextSettings handles PUT /contexts/{context}/settings
+func (h *Handler) UpdateContextSettings(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+
+	var req types.ContextSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidContext, "Invalid request body")
+		return
+	}
+
+	// Fetch existing for before_hash.
+	existing, _ := h.registry.GetContext(r.Context(), registryCtx)
+
+	rec := &storage.ContextRecord{
+		Name:          registryCtx,
+		Description:   req.Description,
+		Owner:         req.Owner,
+		MaxSubjects:   req.MaxSubjects,
+		MaxSchemaSize: req.MaxSchemaSize,
+	}
+
+	if err := h.registry.UpdateContextSettings(r.Context(), rec); err != nil {
+		if errors.Is(err, storage.ErrContextNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeContextNotFound, "Context not found: "+registryCtx)
+			return
+		}
+		if errors.Is(err, registry.ErrInvalidContext) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidContext, err.Error())
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "context"
+		hints.TargetID = registryCtx
+		hints.Context = registryCtx
+		if existing != nil {
+			hints.BeforeHash = hashContext(existing)
+		}
+		hints.AfterHash = hashContext(rec)
+	}
+
+	writeJSON(w, http.StatusOK, contextResponse(rec))
+}
+
+// DeleteContext handles DELETE /contexts/{context}
+//
+// Query parameters:
+//   - cascade: if "true", permanently delete all subjects in the context first.
+//     Without cascade the context must be empty.
+func (h *Handler) DeleteContext(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	cascade := r.URL.Query().Get("cascade") == "true"
+
+	// Fetch before deletion for audit before_hash.
+	existing, _ := h.registry.GetContext(r.Context(), registryCtx)
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "context"
+		hints.TargetID = registryCtx
+		hints.Context = registryCtx
+		if existing != nil {
+			hints.BeforeHash = hashContext(existing)
+		}
+	}
+
+	// A cascade permanently deletes every subject in the context.
+	if cascade && h.registry.RequiresDeleteConfirmation() && !h.confirmPermanentDelete(w, r, registryCtx, "", 0) {
+		return
+	}
+
+	deleted, err := h.registry.DeleteContext(r.Context(), registryCtx, cascade)
+	if err != nil {
+		if errors.Is(err, storage.ErrContextNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeContextNotFound, "Context not found: "+registryCtx)
+			return
+		}
+		if errors.Is(err, registry.ErrContextProtected) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted, err.Error())
+			return
+		}
+		if errors.Is(err, registry.ErrContextNotEmpty) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeContextNotEmpty,
+				err.Error()+"; use cascade=true to delete its subjects")
+			return
+		}
+		if errors.Is(err, registry.ErrReferenceExists) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeReferenceExists, err.Error())
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	if deleted == nil {
+		deleted = []string{}
+	}
+	writeJSON(w, http.StatusOK, types.DeleteContextResponse{Name: registryCtx, DeletedSubjects: deleted})
+}
+
+// contextResponse converts a context record to its API representation.
+func contextResponse(rec *storage.ContextRecord) types.ContextResponse {
+	return types.ContextResponse{
+		Name:          rec.Name,
+		Description:   rec.Description,
+		Owner:         rec.Owner,
+		MaxSubjects:   rec.MaxSubjects,
+		MaxSchemaSize: rec.MaxSchemaSize,
+		Tenant:        rec.Tenant,
+	}
+}
@@ -19,7 +19,7 @@ func (h *Handler) ListKEKs(w http.ResponseWriter, r *http.Request) {
 
 	keks, err := h.registry.ListKEKs(r.Context(), includeDeleted)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -78,7 +78,7 @@ func (h *Handler) GetKEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -102,7 +102,7 @@ func (h *Handler) UpdateKEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -120,7 +120,7 @@ func (h *Handler) UpdateKEK(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.registry.UpdateKEK(r.Context(), existing); err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -147,7 +147,7 @@ func (h *Handler) DeleteKEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -174,7 +174,7 @@ func (h *Handler) UndeleteKEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -204,7 +204,7 @@ func (h *Handler) ListDEKs(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found: "+kekName)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -272,7 +272,7 @@ func (h *Handler) GetDEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeDEKNotFound, "Data encryption key not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -292,7 +292,7 @@ func (h *Handler) ListDEKVersions(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found: "+kekName)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -304,6 +304,8 @@ func (h *Handler) ListDEKVersions(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetDEKVersion handles GET /dek-registry/v1/keks/{name}/deks/{subject}/versions/{version}
+// The version "latest", or -1, returns the latest version, which is how
+// Confluent's serializers look up the DEK to encrypt with.
 func (h *Handler) GetDEKVersion(w http.ResponseWriter, r *http.Request) {
 	kekName := chi.URLParam(r, "name")
 	subject := chi.URLParam(r, "subject")
@@ -311,14 +313,14 @@ func (h *Handler) GetDEKVersion(w http.ResponseWriter, r *http.Request) {
 	algorithm := r.URL.Query().Get("algorithm")
 	includeDeleted := r.URL.Query().Get("deleted") == "true"
 
-	version, err := strconv.Atoi(versionStr)
-	if err != nil {
-		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion, "Invalid version: must be a positive integer")
-		return
-	}
-	if version <= 0 {
-		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion, "Invalid version: must be a positive integer")
-		return
+	version := -1
+	if versionStr != "latest" {
+		var err error
+		version, err = strconv.Atoi(versionStr)
+		if err != nil || (version <= 0 && version != -1) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion, "Invalid version: must be a positive integer, -1 or latest")
+			return
+		}
 	}
 
 	dek, err := h.registry.GetDEK(r.Context(), kekName, subject, version, algorithm, includeDeleted)
@@ -331,7 +333,7 @@ func (h *Handler) GetDEKVersion(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeDEKNotFound, "Data encryption key not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -357,7 +359,7 @@ func (h *Handler) DeleteDEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeDEKNotFound, "Data encryption key not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -390,7 +392,7 @@ func (h *Handler) UndeleteDEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeDEKNotFound, "Data encryption key not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -438,7 +440,7 @@ func (h *Handler) DeleteDEKVersion(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeDEKNotFound, "Data encryption key not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -483,7 +485,7 @@ func (h *Handler) UndeleteDEKVersion(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeDEKNotFound, "Data encryption key not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -585,7 +587,7 @@ func (h *Handler) TestKEK(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
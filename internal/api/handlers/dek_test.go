@@ -619,7 +619,7 @@ func TestGetDEKVersion_Negative(t *testing.T) {
 	r := chi.NewRouter()
 	r.Get("/dek-registry/v1/keks/{name}/deks/{subject}/versions/{version}", h.GetDEKVersion)
 
-	req := httptest.NewRequest("GET", "/dek-registry/v1/keks/verneg-kek/deks/verneg-subject/versions/-1", nil)
+	req := httptest.NewRequest("GET", "/dek-registry/v1/keks/verneg-kek/deks/verneg-subject/versions/-2", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -632,6 +632,40 @@ func TestGetDEKVersion_Negative(t *testing.T) {
 	}
 }
 
+func TestGetDEKVersion_Latest(t *testing.T) {
+	h := setupTestHandler(t)
+	createKEK(t, h, "latest-kek", "aws-kms", "key-1")
+	for _, version := range []int{1, 2} {
+		body, _ := json.Marshal(types.CreateDEKRequest{Subject: "latest-subject", Version: version, EncryptedKeyMaterial: "encrypted-data"})
+		r := chi.NewRouter()
+		r.Post("/dek-registry/v1/keks/{name}/deks", h.CreateDEK)
+		req := httptest.NewRequest("POST", "/dek-registry/v1/keks/latest-kek/deks", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("create version %d: %d %s", version, w.Code, w.Body.String())
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Get("/dek-registry/v1/keks/{name}/deks/{subject}/versions/{version}", h.GetDEKVersion)
+
+	// Confluent's clients ask for the latest version as "latest" or -1.
+	for _, version := range []string{"latest", "-1"} {
+		req := httptest.NewRequest("GET", "/dek-registry/v1/keks/latest-kek/deks/latest-subject/versions/"+version, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("version %s: expected 200, got %d: %s", version, w.Code, w.Body.String())
+		}
+		var resp types.DEKResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.Version != 2 {
+			t.Errorf("version %s: expected version 2, got %d", version, resp.Version)
+		}
+	}
+}
+
 func TestGetDEKVersion_GetDoesNotStripKeyMaterial(t *testing.T) {
 	h := setupTestHandler(t)
 	createKEK(t, h, "nokey-kek", "aws-kms", "key-1")
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+)
+
+// confirmPermanentDelete runs the two-phase check of a permanent delete of a
+// subject, or of one of its versions when version is not 0, or of a whole
+// context and its subjects when subject is empty. Without a
+// confirmation_token it responds 202 with a new token; with one it requires a
+// super admin and consumes the token. It returns true when the delete may
+// proceed and has written a response otherwise.
+func (h *Handler) confirmPermanentDelete(w http.ResponseWriter, r *http.Request, registryCtx string, subject string, version int) bool {
+	token := r.URL.Query().Get("confirmation_token")
+	if token == "" {
+		confirmation, err := h.registry.IssueDeleteConfirmation(r.Context(), registryCtx, subject, version)
+		if err != nil {
+			writeRegistryError(w, err)
+			return false
+		}
+		writeJSON(w, http.StatusAccepted, types.DeleteConfirmationResponse{
+			ConfirmationToken: confirmation.Token,
+			ExpiresAt:         confirmation.ExpiresAt.UTC().Format(time.RFC3339),
+			Message:           "Permanent deletes must be confirmed. Repeat the request with confirmation_token set to this token as a super admin before it expires.",
+		})
+		return false
+	}
+	if !h.isSuperAdmin(r) {
+		writeError(w, http.StatusForbidden, types.ErrorCodeForbidden, "Confirming a permanent delete requires the super_admin role")
+		return false
+	}
+	if err := h.registry.ConfirmDelete(r.Context(), registryCtx, subject, version, token); err != nil {
+		writeRegistryError(w, err)
+		return false
+	}
+	return true
+}
+
+// isSuperAdmin reports whether the caller has the super_admin role or is a
+// configured super admin. Without authentication every caller counts as one.
+func (h *Handler) isSuperAdmin(r *http.Request) bool {
+	if h.authorizer == nil {
+		return true
+	}
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		return false
+	}
+	return user.Role == string(auth.RoleSuperAdmin) || h.authorizer.IsSuperAdmin(user.Username)
+}
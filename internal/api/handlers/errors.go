@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// errorMapping maps a registry, storage or job error to an HTTP status and
+// Confluent-compatible error code. An empty message means the error's own
+// text is returned, for errors whose text carries detail the client needs
+// (such as why a schema failed to parse).
+type errorMapping struct {
+	err     error
+	status  int
+	code    int
+	message string
+}
+
+// errorMappings is checked in order with errors.Is. Registry errors come
+// first because they may wrap the storage error that caused them; for
+// example a failed reference lookup wraps storage.ErrSubjectNotFound but
+// must be reported as an invalid schema, as Confluent does.
+var errorMappings = []errorMapping{
+	{registry.ErrIncompatibleSchema, http.StatusConflict, types.ErrorCodeIncompatibleSchema, ""},
+	{registry.ErrReferenceCycle, http.StatusUnprocessableEntity, types.ErrorCodeReferenceCycle, ""},
+	{registry.ErrReferenceDepthExceeded, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, ""},
+	{registry.ErrInvalidSchema, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, ""},
+	{registry.ErrUnsupportedSchemaType, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, ""},
+	{registry.ErrInvalidRuleSet, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, ""},
+	{registry.ErrFailedResolveReferences, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, ""},
+	{registry.ErrFingerprintMismatch, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, ""},
+	{registry.ErrInvalidCompatibility, http.StatusUnprocessableEntity, types.ErrorCodeInvalidCompatibilityLevel, ""},
+	{registry.ErrInvalidMode, http.StatusUnprocessableEntity, types.ErrorCodeInvalidMode, ""},
+	{registry.ErrReferenceExists, http.StatusUnprocessableEntity, types.ErrorCodeReferenceExists, ""},
+	{registry.ErrSubjectNameStrategy, http.StatusUnprocessableEntity, types.ErrorCodeSubjectNameStrategy, ""},
+	{registry.ErrLintFailed, http.StatusUnprocessableEntity, types.ErrorCodeLintFailed, ""},
+	{registry.ErrInvalidContext, http.StatusUnprocessableEntity, types.ErrorCodeInvalidContext, ""},
+	{registry.ErrContextNotCreated, http.StatusNotFound, types.ErrorCodeContextNotFound, ""},
+	{registry.ErrContextNotEmpty, http.StatusUnprocessableEntity, types.ErrorCodeContextNotEmpty, ""},
+	{registry.ErrContextQuotaExceeded, http.StatusUnprocessableEntity, types.ErrorCodeContextQuotaExceeded, ""},
+	{registry.ErrInvalidTenant, http.StatusUnprocessableEntity, types.ErrorCodeInvalidTenant, ""},
+	{registry.ErrTenantNotEmpty, http.StatusUnprocessableEntity, types.ErrorCodeTenantNotEmpty, ""},
+	{registry.ErrInvalidTags, http.StatusUnprocessableEntity, types.ErrorCodeInvalidTags, ""},
+	{registry.ErrInvalidSubjectMeta, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSubjectMeta, ""},
+	{registry.ErrInvalidImportSession, http.StatusUnprocessableEntity, types.ErrorCodeInvalidImportSession, ""},
+	{registry.ErrImportSessionClosed, http.StatusUnprocessableEntity, types.ErrorCodeImportSessionClosed, ""},
+	{registry.ErrImportSessionConflict, http.StatusConflict, types.ErrorCodeImportSessionConflict, ""},
+	{registry.ErrInvalidIDAlias, http.StatusUnprocessableEntity, types.ErrorCodeInvalidIDAlias, ""},
+	{registry.ErrVersionFrozen, http.StatusUnprocessableEntity, types.ErrorCodeVersionFrozen, ""},
+	{registry.ErrInvalidSubjectAlias, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSubjectAlias, ""},
+	{registry.ErrSubjectAliased, http.StatusUnprocessableEntity, types.ErrorCodeSubjectAliased, ""},
+	{registry.ErrInvalidReview, http.StatusUnprocessableEntity, types.ErrorCodeInvalidReview, ""},
+	{registry.ErrPendingSchemaReviewed, http.StatusUnprocessableEntity, types.ErrorCodePendingSchemaReviewed, ""},
+	{registry.ErrSelfApproval, http.StatusForbidden, types.ErrorCodeSelfApproval, ""},
+	{registry.ErrNotAssignedReviewer, http.StatusForbidden, types.ErrorCodeNotAssignedReviewer, ""},
+	{registry.ErrInvalidReader, http.StatusUnprocessableEntity, types.ErrorCodeInvalidReader, ""},
+	{registry.ErrUnsupportedConversion, http.StatusUnprocessableEntity, types.ErrorCodeUnsupportedConversion, ""},
+	{registry.ErrInvalidDeleteConfirmation, http.StatusUnprocessableEntity, types.ErrorCodeInvalidDeleteConfirmation, ""},
+	{registry.ErrWritesSuspended, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted, ""},
+	{registry.ErrInvalidDesiredState, http.StatusUnprocessableEntity, types.ErrorCodeInvalidDesiredState, ""},
+	{registry.ErrQuotaExceeded, http.StatusUnprocessableEntity, types.ErrorCodeQuotaExceeded, ""},
+	{registry.ErrQuotaRuleNotFound, http.StatusNotFound, types.ErrorCodeQuotaRuleNotFound, "Quota not found"},
+	{registry.ErrUnsupportedFingerprint, http.StatusUnprocessableEntity, types.ErrorCodeUnsupportedFingerprint, ""},
+
+	{storage.ErrSubjectNotFound, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Subject not found"},
+	{storage.ErrVersionNotFound, http.StatusNotFound, types.ErrorCodeVersionNotFound, "Version not found"},
+	{storage.ErrSchemaNotFound, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found"},
+	{storage.ErrSubjectDeleted, http.StatusNotFound, types.ErrorCodeSubjectSoftDeleted,
+		"Subject was soft deleted. Set permanent=true to delete permanently"},
+	{storage.ErrSubjectNotSoftDeleted, http.StatusNotFound, types.ErrorCodeSubjectNotSoftDeleted,
+		"Subject was not deleted first before being permanently deleted"},
+	{storage.ErrVersionNotSoftDeleted, http.StatusNotFound, types.ErrorCodeVersionNotSoftDeleted,
+		"Version was not deleted first before being permanently deleted"},
+	{storage.ErrInvalidVersion, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion, "Invalid version"},
+	{storage.ErrOperationNotPermitted, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted, ""},
+	{storage.ErrContextNotFound, http.StatusNotFound, types.ErrorCodeContextNotFound, "Context not found"},
+	{storage.ErrContextExists, http.StatusConflict, types.ErrorCodeContextExists, "Context already exists"},
+	{storage.ErrExporterNotFound, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found"},
+	{storage.ErrExporterExists, http.StatusConflict, types.ErrorCodeExporterExists, "Exporter already exists"},
+	{storage.ErrKEKNotFound, http.StatusNotFound, types.ErrorCodeKEKNotFound, "Key encryption key not found"},
+	{storage.ErrKEKExists, http.StatusConflict, types.ErrorCodeKEKExists, "Key encryption key already exists"},
+	{storage.ErrDEKNotFound, http.StatusNotFound, types.ErrorCodeDEKNotFound, "Data encryption key not found"},
+	{storage.ErrDEKExists, http.StatusConflict, types.ErrorCodeDEKExists, "Data encryption key already exists"},
+	{storage.ErrJobNotFound, http.StatusNotFound, types.ErrorCodeJobNotFound, "Job not found"},
+	{storage.ErrTenantNotFound, http.StatusNotFound, types.ErrorCodeTenantNotFound, "Tenant not found"},
+	{storage.ErrTenantExists, http.StatusConflict, types.ErrorCodeTenantExists, "Tenant already exists"},
+	{storage.ErrImportSessionNotFound, http.StatusNotFound, types.ErrorCodeImportSessionNotFound, "Import session not found"},
+	{storage.ErrIDAliasNotFound, http.StatusNotFound, types.ErrorCodeIDAliasNotFound, "Schema ID alias not found"},
+	{storage.ErrFrozenVersionNotFound, http.StatusNotFound, types.ErrorCodeVersionNotFrozen, "Version is not frozen"},
+	{storage.ErrPendingSchemaNotFound, http.StatusNotFound, types.ErrorCodePendingSchemaNotFound, "Pending schema not found"},
+	{storage.ErrReaderAssertionNotFound, http.StatusNotFound, types.ErrorCodeReaderNotFound, "Reader assertion not found"},
+
+	{jobs.ErrJobFinished, http.StatusUnprocessableEntity, types.ErrorCodeJobFinished, "Job has already finished"},
+	{jobs.ErrQueueFull, http.StatusServiceUnavailable, types.ErrorCodeJobQueueFull, "Too many jobs queued, try again later"},
+	{jobs.ErrStopped, http.StatusServiceUnavailable, types.ErrorCodeJobQueueFull, "Server is shutting down"},
+}
+
+// lookupErrorMapping returns the status, error code and message for a known
+// registry or storage error. ok is false for errors with no mapping.
+func lookupErrorMapping(err error) (status, code int, message string, ok bool) {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			message = m.message
+			if message == "" {
+				message = err.Error()
+			}
+			return m.status, m.code, message, true
+		}
+	}
+	return 0, 0, "", false
+}
+
+// writeRegistryError writes the Confluent-compatible error response for a
+// registry or storage error. Handlers that can add detail to a specific
+// error (such as the subject name) should check for it first and fall back
+// to this; unknown errors become a generic 500 via writeInternalError.
+func writeRegistryError(w http.ResponseWriter, err error) {
+	if status, code, message, ok := lookupErrorMapping(err); ok {
+		writeError(w, status, code, message)
+		return
+	}
+	writeInternalError(w, err)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func TestWriteRegistryError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    int
+		message string
+	}{
+		{"subject not found", storage.ErrSubjectNotFound, http.StatusNotFound, 40401, "Subject not found"},
+		{"wrapped version not found", fmt.Errorf("lookup: %w", storage.ErrVersionNotFound), http.StatusNotFound, 40402, "Version not found"},
+		{"schema not found", storage.ErrSchemaNotFound, http.StatusNotFound, 40403, "Schema not found"},
+		{"incompatible", fmt.Errorf("%w: field removed", registry.ErrIncompatibleSchema), http.StatusConflict, 409, "incompatible schema: field removed"},
+		{"invalid schema", fmt.Errorf("%w: bad json", registry.ErrInvalidSchema), http.StatusUnprocessableEntity, 42201, "invalid schema: bad json"},
+		{"invalid version", storage.ErrInvalidVersion, http.StatusUnprocessableEntity, 42202, "Invalid version"},
+		{"invalid compatibility", registry.ErrInvalidCompatibility, http.StatusUnprocessableEntity, 42203, "invalid compatibility level"},
+		{"operation not permitted", storage.ErrOperationNotPermitted, http.StatusUnprocessableEntity, 42205, storage.ErrOperationNotPermitted.Error()},
+		{"registry error wins over wrapped storage error",
+			fmt.Errorf("%w: %w", registry.ErrFailedResolveReferences, storage.ErrSubjectNotFound),
+			http.StatusUnprocessableEntity, 42201, "failed to resolve references: subject not found"},
+		{"unknown error is internal", errors.New("connection refused to db:5432"), http.StatusInternalServerError, 50001, "Internal server error"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		writeRegistryError(w, tt.err)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, w.Code)
+		}
+		var resp types.ErrorResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: decode: %v", tt.name, err)
+		}
+		if resp.ErrorCode != tt.code || resp.Message != tt.message {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.name, tt.code, tt.message, resp.ErrorCode, resp.Message)
+		}
+	}
+}
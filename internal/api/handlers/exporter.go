@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
@@ -16,7 +18,7 @@ import (
 func (h *Handler) ListExporters(w http.ResponseWriter, r *http.Request) {
 	names, err := h.registry.ListExporters(r.Context())
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 	if names == nil {
@@ -38,11 +40,17 @@ func (h *Handler) CreateExporter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	registryCtx := getRegistryContext(r)
+	subjects := req.Subjects
+	if len(subjects) == 0 && registryCtx != registrycontext.DefaultContext {
+		// An exporter of a context exports the whole context by default.
+		subjects = []string{"*"}
+	}
 	exporter := &storage.ExporterRecord{
 		Name:                req.Name,
 		ContextType:         req.ContextType,
 		Context:             req.Context,
-		Subjects:            req.Subjects,
+		Subjects:            scopeExporterSubjects(registryCtx, subjects),
 		SubjectRenameFormat: req.SubjectRenameFormat,
 		Config:              req.Config,
 	}
@@ -79,7 +87,7 @@ func (h *Handler) GetExporter(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -110,7 +118,7 @@ func (h *Handler) UpdateExporter(w http.ResponseWriter, r *http.Request) {
 		Name:                name,
 		ContextType:         req.ContextType,
 		Context:             req.Context,
-		Subjects:            req.Subjects,
+		Subjects:            scopeExporterSubjects(getRegistryContext(r), req.Subjects),
 		SubjectRenameFormat: req.SubjectRenameFormat,
 		Config:              req.Config,
 	}
@@ -148,7 +156,7 @@ func (h *Handler) DeleteExporter(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -175,7 +183,7 @@ func (h *Handler) PauseExporter(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -205,7 +213,7 @@ func (h *Handler) ResumeExporter(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -235,7 +243,7 @@ func (h *Handler) ResetExporter(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -263,7 +271,7 @@ func (h *Handler) GetExporterStatus(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -286,7 +294,7 @@ func (h *Handler) GetExporterConfig(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -314,7 +322,7 @@ func (h *Handler) UpdateExporterConfig(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeExporterNotFound, "Exporter not found: "+name)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -332,3 +340,23 @@ func (h *Handler) UpdateExporterConfig(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, types.ExporterNameResponse{Name: name})
 }
+
+// scopeExporterSubjects qualifies the subject filters of an exporter managed
+// under /contexts/{context}/exporters with that context, so that unqualified
+// filters select subjects of the context rather than of the default one.
+// Filters that already name a context, including ":*:" for every context,
+// are kept.
+func scopeExporterSubjects(registryCtx string, subjects []string) []string {
+	if subjects == nil || registryCtx == registrycontext.DefaultContext {
+		return subjects
+	}
+	scoped := make([]string, len(subjects))
+	for i, s := range subjects {
+		if strings.HasPrefix(s, ":") {
+			scoped[i] = s
+			continue
+		}
+		scoped[i] = registrycontext.FormatSubject(registryCtx, s)
+	}
+	return scoped
+}
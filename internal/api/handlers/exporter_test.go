@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
 )
 
 // --- Exporter Test Helpers ---
@@ -141,6 +143,41 @@ func TestCreateExporter_Duplicate(t *testing.T) {
 	}
 }
 
+func TestCreateExporter_ContextScoped(t *testing.T) {
+	h := setupTestHandler(t)
+
+	r := chi.NewRouter()
+	r.Post("/exporters", h.CreateExporter)
+	r.Get("/exporters/{name}", h.GetExporter)
+
+	tests := []struct {
+		name     string
+		subjects []string
+		want     []string
+	}{
+		{"ctx-all", nil, []string{":.production:*"}},
+		{"ctx-some", []string{"orders-*", ":.staging:users", ":*:audit-*"}, []string{":.production:orders-*", ":.staging:users", ":*:audit-*"}},
+	}
+	for _, tt := range tests {
+		body, _ := json.Marshal(types.CreateExporterRequest{Name: tt.name, Subjects: tt.subjects})
+		req := httptest.NewRequest("POST", "/exporters", bytes.NewReader(body))
+		req = req.WithContext(registrycontext.WithRegistryContext(req.Context(), ".production"))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", tt.name, w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/exporters/"+tt.name, nil))
+		var resp types.ExporterResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		if !slices.Equal(resp.Subjects, tt.want) {
+			t.Errorf("%s: expected subjects %v, got %v", tt.name, tt.want, resp.Subjects)
+		}
+	}
+}
+
 func TestGetExporter_Success(t *testing.T) {
 	h := setupTestHandler(t)
 	createExporter(t, h, "get-exporter", map[string]string{"key": "value"})
@@ -217,6 +254,33 @@ func TestUpdateExporter_Success(t *testing.T) {
 	}
 }
 
+func TestUpdateExporter_KeepsOmittedFields(t *testing.T) {
+	h := setupTestHandler(t)
+	createExporter(t, h, "partial-exporter", map[string]string{"schema.registry.url": "http://dr:8081"})
+
+	r := chi.NewRouter()
+	r.Put("/exporters/{name}", h.UpdateExporter)
+	r.Get("/exporters/{name}", h.GetExporter)
+
+	req := httptest.NewRequest("PUT", "/exporters/partial-exporter", bytes.NewReader([]byte(`{"subjects":["orders-*"]}`)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/exporters/partial-exporter", nil))
+	var resp types.ExporterResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Subjects) != 1 || resp.Subjects[0] != "orders-*" {
+		t.Errorf("expected subjects [orders-*], got %v", resp.Subjects)
+	}
+	if resp.Config["schema.registry.url"] != "http://dr:8081" || resp.ContextType != "AUTO" {
+		t.Errorf("expected config and context type to be kept, got %v %q", resp.Config, resp.ContextType)
+	}
+}
+
 func TestUpdateExporter_NotFound(t *testing.T) {
 	h := setupTestHandler(t)
 
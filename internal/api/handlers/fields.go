@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// requestedFields returns the field names selected with the ?fields= query
+// parameter. Names may be comma-separated or given as repeated parameters.
+// Returns nil when no fields were requested.
+func requestedFields(r *http.Request) map[string]bool {
+	var fields map[string]bool
+	for _, param := range r.URL.Query()["fields"] {
+		for _, name := range strings.Split(param, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if fields == nil {
+				fields = make(map[string]bool)
+			}
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// writeJSONFields writes a JSON response, keeping only the top-level fields
+// selected with ?fields= (for example ?fields=id,version,schemaType). It
+// applies to a JSON object or to each object in a JSON array. Unknown field
+// names are ignored. Without ?fields= it behaves like writeJSON.
+func writeJSONFields(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	fields := requestedFields(r)
+	if fields == nil {
+		writeJSON(w, status, data)
+		return
+	}
+	writeJSON(w, status, selectFields(data, fields))
+}
+
+// selectFields returns data reduced to the given top-level fields. Values that
+// do not encode to an object or an array of objects are returned unchanged.
+func selectFields(data interface{}, fields map[string]bool) interface{} {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return filterObject(obj, fields)
+	}
+
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err != nil {
+		return data
+	}
+	result := make([]interface{}, len(arr))
+	for i, elem := range arr {
+		var elemObj map[string]json.RawMessage
+		if err := json.Unmarshal(elem, &elemObj); err != nil {
+			result[i] = elem
+			continue
+		}
+		result[i] = filterObject(elemObj, fields)
+	}
+	return result
+}
+
+func filterObject(obj map[string]json.RawMessage, fields map[string]bool) map[string]json.RawMessage {
+	filtered := make(map[string]json.RawMessage, len(fields))
+	for name, value := range obj {
+		if fields[name] {
+			filtered[name] = value
+		}
+	}
+	return filtered
+}
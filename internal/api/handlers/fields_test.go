@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRequestedFields(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?fields=id,+version&fields=schemaType,", nil)
+	fields := requestedFields(r)
+	if len(fields) != 3 || !fields["id"] || !fields["version"] || !fields["schemaType"] {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+
+	if fields := requestedFields(httptest.NewRequest("GET", "/", nil)); fields != nil {
+		t.Errorf("expected nil without ?fields=, got %v", fields)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	fields := map[string]bool{"id": true, "version": true, "missing": true}
+
+	obj := selectFields(map[string]interface{}{"id": 1, "version": 2, "schema": "{}"}, fields)
+	raw, _ := json.Marshal(obj)
+	if string(raw) != `{"id":1,"version":2}` {
+		t.Errorf("unexpected object: %s", raw)
+	}
+
+	arr := selectFields([]map[string]interface{}{{"id": 1, "schema": "{}"}, {"id": 2, "schema": "{}"}}, fields)
+	raw, _ = json.Marshal(arr)
+	if string(raw) != `[{"id":1},{"id":2}]` {
+		t.Errorf("unexpected array: %s", raw)
+	}
+
+	ints := selectFields([]int{1, 2}, fields)
+	raw, _ = json.Marshal(ints)
+	if string(raw) != `[1,2]` {
+		t.Errorf("expected non-object values unchanged, got %s", raw)
+	}
+}
+
+func TestGetVersion_FieldsParam(t *testing.T) {
+	h := setupTestHandler(t)
+	registerSchema(t, h, "fields-test", `{"type":"record","name":"F","fields":[{"name":"a","type":"int"}]}`)
+
+	r := chi.NewRouter()
+	r.Get("/subjects/{subject}/versions/{version}", h.GetVersion)
+
+	req := httptest.NewRequest("GET", "/subjects/fields-test/versions/1?fields=id,version", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp) != 2 || resp["id"] == nil || resp["version"] == nil {
+		t.Errorf("expected only id and version, got %v", resp)
+	}
+}
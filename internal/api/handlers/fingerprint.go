@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// GetSchemaFingerprintByID handles GET /schemas/ids/{id}/fingerprint. The
+// algorithm query parameter selects md5, sha256 or crc64-avro; it defaults
+// to the configured response fingerprint, or sha256.
+func (h *Handler) GetSchemaFingerprintByID(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+
+	id, ok := h.schemaIDParam(w, r, registryCtx)
+	if !ok {
+		return
+	}
+	registryCtx, err := h.registry.SchemaIDContext(r.Context(), registryCtx, id)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	schema, err := h.registry.GetSchemaByID(r.Context(), registryCtx, id)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	h.usage.RecordID(registryCtx, id)
+
+	algorithm := strings.ToLower(r.URL.Query().Get("algorithm"))
+	if algorithm == "" {
+		algorithm = h.fingerprint
+	}
+	if algorithm == "" {
+		algorithm = registry.FingerprintSHA256
+	}
+	fingerprint, err := h.registry.SchemaFingerprint(r.Context(), registryCtx, schema, algorithm)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, types.SchemaFingerprintResponse{
+		ID:          id,
+		Algorithm:   algorithm,
+		Fingerprint: fingerprint,
+	})
+}
+
+// responseFingerprint returns the fingerprint added to a schema response,
+// or "" when none is configured or it cannot be computed for the schema,
+// such as crc64-avro for a Protobuf schema.
+func (h *Handler) responseFingerprint(ctx context.Context, registryCtx string, schema *storage.SchemaRecord) string {
+	if h.fingerprint == "" {
+		return ""
+	}
+	fingerprint, err := h.registry.SchemaFingerprint(ctx, registryCtx, schema, h.fingerprint)
+	if err != nil {
+		return ""
+	}
+	return fingerprint
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// freezeTarget resolves the subject and version a freeze request addresses.
+func freezeTarget(w http.ResponseWriter, r *http.Request) (registryCtx, subject string, version int, ok bool) {
+	registryCtx, subject = resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return "", "", 0, false
+	}
+	versionStr := chi.URLParam(r, "version")
+	version, err := registry.ParseVersion(versionStr)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion,
+			fmt.Sprintf("The specified version '%s' is not a valid version id. Allowed values are between [1, 2^31-1] and the string \"latest\"", versionStr))
+		return "", "", 0, false
+	}
+	return registryCtx, subject, version, true
+}
+
+func frozenVersionResponse(rec *storage.FrozenVersionRecord) types.FrozenVersionResponse {
+	return types.FrozenVersionResponse{
+		Subject:  rec.Subject,
+		Version:  rec.Version,
+		FrozenBy: rec.FrozenBy,
+		Reason:   rec.Reason,
+		FrozenAt: rec.FrozenAt.Format(time.RFC3339),
+	}
+}
+
+// setFreezeHints records the frozen version as the audit target.
+func setFreezeHints(r *http.Request, registryCtx, subject string, version int) {
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "subject"
+		hints.TargetID = subject
+		hints.Context = registryCtx
+		hints.Version = version
+	}
+}
+
+// GetFrozenVersion handles GET /subjects/{subject}/versions/{version}/freeze.
+func (h *Handler) GetFrozenVersion(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject, version, ok := freezeTarget(w, r)
+	if !ok {
+		return
+	}
+	rec, err := h.registry.GetFrozenVersion(r.Context(), registryCtx, subject, version)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, frozenVersionResponse(rec))
+}
+
+// FreezeVersion handles PUT /subjects/{subject}/versions/{version}/freeze.
+// A frozen version cannot be soft- or permanently deleted, and neither can
+// its subject, until it is unfrozen. The body, with an optional reason, may
+// be omitted.
+func (h *Handler) FreezeVersion(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject, version, ok := freezeTarget(w, r)
+	if !ok {
+		return
+	}
+	var req types.FreezeVersionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidRequest, "Invalid request body")
+		return
+	}
+	frozenBy := ""
+	if user := auth.GetUser(r.Context()); user != nil {
+		frozenBy = user.Username
+	}
+	rec, err := h.registry.FreezeVersion(r.Context(), registryCtx, subject, version, frozenBy, req.Reason)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	setFreezeHints(r, registryCtx, subject, rec.Version)
+	writeJSON(w, http.StatusOK, frozenVersionResponse(rec))
+}
+
+// UnfreezeVersion handles DELETE /subjects/{subject}/versions/{version}/freeze.
+func (h *Handler) UnfreezeVersion(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject, version, ok := freezeTarget(w, r)
+	if !ok {
+		return
+	}
+	unfrozen, err := h.registry.UnfreezeVersion(r.Context(), registryCtx, subject, version)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	setFreezeHints(r, registryCtx, subject, unfrozen)
+	w.WriteHeader(http.StatusNoContent)
+}
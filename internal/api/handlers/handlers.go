@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,9 +20,13 @@ import (
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/health"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/lint"
 	"github.com/axonops/axonops-schema-registry/internal/metrics"
 	"github.com/axonops/axonops-schema-registry/internal/registry"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/usage"
 )
 
 // schemaTypeForResponse returns the schema type string for API responses.
@@ -37,10 +43,25 @@ type Handler struct {
 	registry    *registry.Registry
 	metrics     *metrics.Metrics
 	auditLogger *auth.AuditLogger
+	jobs        *jobs.Manager
+	usage       *usage.Tracker
 	clusterID   string
 	version     string
 	commit      string
 	buildTime   string
+
+	maxVersionsPageSize int
+	maxListPageSize     int
+
+	// Algorithm of the fingerprint added to schema responses; empty for none.
+	fingerprint string
+
+	health           *health.Checker
+	showHealthErrors bool
+
+	// Resolves configured super admins for confirming permanent deletes;
+	// nil when authentication is disabled.
+	authorizer *auth.Authorizer
 }
 
 // Config holds handler configuration.
@@ -49,6 +70,18 @@ type Config struct {
 	Version   string
 	Commit    string
 	BuildTime string
+
+	// MaxVersionsPageSize caps the number of versions returned by a single
+	// GET /subjects/{subject}/versions request. 0 means unlimited.
+	MaxVersionsPageSize int
+
+	// MaxListPageSize caps the number of entries returned by a single
+	// GET /subjects or GET /schemas request. 0 means unlimited.
+	MaxListPageSize int
+
+	// Fingerprint is the algorithm of the fingerprint added to schema
+	// responses: md5, sha256 or crc64-avro. Empty adds none.
+	Fingerprint string
 }
 
 // New creates a new Handler.
@@ -57,6 +90,7 @@ func New(reg *registry.Registry) *Handler {
 		registry:  reg,
 		clusterID: "default-cluster",
 		version:   "1.0.0",
+		health:    NewHealthChecker(reg, 0, nil),
 	}
 }
 
@@ -68,14 +102,52 @@ func NewWithConfig(reg *registry.Registry, cfg Config) *Handler {
 		version:   cfg.Version,
 		commit:    cfg.Commit,
 		buildTime: cfg.BuildTime,
+
+		maxVersionsPageSize: cfg.MaxVersionsPageSize,
+		maxListPageSize:     cfg.MaxListPageSize,
+		fingerprint:         cfg.Fingerprint,
+
+		health: NewHealthChecker(reg, 0, nil),
 	}
 }
 
+// NewHealthChecker creates the readiness checker with the storage check,
+// which is always critical. Other dependencies are registered by the caller.
+func NewHealthChecker(reg *registry.Registry, timeout time.Duration, logger *slog.Logger) *health.Checker {
+	checker := health.NewChecker(timeout, logger)
+	checker.Register("storage", true, func(ctx context.Context) error {
+		if !reg.IsHealthy(ctx) {
+			return errors.New("storage backend unavailable")
+		}
+		return nil
+	})
+	return checker
+}
+
+// SetHealthChecker sets the checker behind GET /health/ready. Dependency
+// error messages are only included in the response when showErrors is set.
+func (h *Handler) SetHealthChecker(c *health.Checker, showErrors bool) {
+	h.health = c
+	h.showHealthErrors = showErrors
+}
+
 // SetMetrics sets the metrics instance for recording schema operation metrics.
 func (h *Handler) SetMetrics(m *metrics.Metrics) {
 	h.metrics = m
 }
 
+// SetUsageTracker sets the tracker that counts schema fetches. A nil
+// tracker disables usage tracking.
+func (h *Handler) SetUsageTracker(t *usage.Tracker) {
+	h.usage = t
+}
+
+// SetAuthorizer sets the authorizer that decides who is a super admin when
+// confirming permanent deletes. Without one, anyone may confirm.
+func (h *Handler) SetAuthorizer(a *auth.Authorizer) {
+	h.authorizer = a
+}
+
 // SetAuditLogger sets the audit logger for direct event emission (e.g., per-schema import events).
 func (h *Handler) SetAuditLogger(al *auth.AuditLogger) {
 	h.auditLogger = al
@@ -134,17 +206,33 @@ func (h *Handler) LivenessCheck(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "UP"})
 }
 
+// readinessResponse is the body of GET /health/ready.
+type readinessResponse struct {
+	health.Report
+	Reason string `json:"reason,omitempty"`
+}
+
 // ReadinessCheck handles GET /health/ready
-// Returns 200 when storage is healthy, 503 when not.
+// Reports the status and latency of each dependency. Returns 200 when every
+// critical dependency is up, 503 when not.
 func (h *Handler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
-	if h.registry.IsHealthy(r.Context()) {
-		writeJSON(w, http.StatusOK, map[string]string{"status": "UP"})
+	resp := readinessResponse{Report: h.health.Run(r.Context())}
+	if !h.showHealthErrors {
+		for name, check := range resp.Checks {
+			check.Error = ""
+			resp.Checks[name] = check
+		}
+	}
+	if resp.Status == health.StatusUp {
+		writeJSON(w, http.StatusOK, resp)
 		return
 	}
-	writeJSON(w, http.StatusServiceUnavailable, map[string]string{
-		"status": "DOWN",
-		"reason": "storage backend unavailable",
-	})
+	if slices.Contains(resp.Failed, "storage") {
+		resp.Reason = "storage backend unavailable"
+	} else {
+		resp.Reason = strings.Join(resp.Failed, ", ") + " unavailable"
+	}
+	writeJSON(w, http.StatusServiceUnavailable, resp)
 }
 
 // StartupCheck handles GET /health/startup
@@ -173,10 +261,15 @@ func (h *Handler) GetSchemaByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, ok := h.schemaIDParam(w, r, registryCtx)
+	if !ok {
+		return
+	}
+	// With global IDs the schema may be in another context, whose subjects
+	// and references the response is built from.
+	registryCtx, err := h.registry.SchemaIDContext(r.Context(), registryCtx, id)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid schema ID")
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -186,9 +279,10 @@ func (h *Handler) GetSchemaByID(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
+	h.usage.RecordID(registryCtx, id)
 
 	// When ?subject= is provided, enrich the response with per-subject metadata
 	// and ruleSet. The global schema record (by ID) doesn't carry these because
@@ -226,12 +320,15 @@ func (h *Handler) GetSchemaByID(w http.ResponseWriter, r *http.Request) {
 		if schema.RuleSet != nil {
 			resp["ruleSet"] = schema.RuleSet
 		}
+		if fp := h.responseFingerprint(r.Context(), registryCtx, schema); fp != "" {
+			resp["fingerprint"] = fp
+		}
 		if r.URL.Query().Get("fetchMaxId") == "true" {
 			if maxID, err := h.registry.GetMaxSchemaID(r.Context(), registryCtx); err == nil {
 				resp["maxId"] = maxID
 			}
 		}
-		writeJSON(w, http.StatusOK, resp)
+		writeJSONFields(w, r, http.StatusOK, resp)
 		return
 	}
 
@@ -241,6 +338,8 @@ func (h *Handler) GetSchemaByID(w http.ResponseWriter, r *http.Request) {
 		References: schema.References,
 		Metadata:   schema.Metadata,
 		RuleSet:    schema.RuleSet,
+
+		Fingerprint: h.responseFingerprint(r.Context(), registryCtx, schema),
 	}
 
 	if r.URL.Query().Get("fetchMaxId") == "true" {
@@ -250,84 +349,126 @@ func (h *Handler) GetSchemaByID(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	writeJSONFields(w, r, http.StatusOK, resp)
 }
 
-// ListSubjects handles GET /subjects
+// ListSubjects handles GET /subjects. The filters, offset and limit are
+// pushed down to storage; a limit above the configured maximum page size is
+// reduced to it, and a Link header points at the next page of a truncated
+// list.
 func (h *Handler) ListSubjects(w http.ResponseWriter, r *http.Request) {
 	registryCtx := getRegistryContext(r)
 	if rejectGlobalContext(w, registryCtx) {
 		return
 	}
-	deleted := r.URL.Query().Get("deleted") == "true"
-	deletedOnly := r.URL.Query().Get("deletedOnly") == "true"
-	subjectPrefix := r.URL.Query().Get("subjectPrefix")
-
-	// deletedOnly implies including deleted subjects
-	includeDeleted := deleted || deletedOnly
+	q := r.URL.Query()
+	offset := pageOffset(r)
+	limit := pageLimit(r, h.maxListPageSize)
+	params := &storage.ListSubjectsParams{
+		Prefix:      q.Get("subjectPrefix"),
+		Deleted:     q.Get("deleted") == "true",
+		DeletedOnly: q.Get("deletedOnly") == "true",
+		Offset:      offset,
+	}
+	if limit > 0 {
+		// One extra subject tells whether there is a next page.
+		params.Limit = limit + 1
+	}
 
-	subjects, err := h.registry.ListSubjects(r.Context(), registryCtx, includeDeleted)
+	subjects, err := h.registry.ListSubjectsPage(r.Context(), registryCtx, params)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
+	if limit > 0 && len(subjects) > limit {
+		subjects = subjects[:limit]
+		setNextPageLink(w, r, offset, limit)
+	}
 
-	// For deletedOnly, filter to only deleted subjects by diffing with active set
-	if deletedOnly {
-		activeSubjects, err := h.registry.ListSubjects(r.Context(), registryCtx, false)
-		if err != nil {
-			writeInternalError(w, err)
-			return
-		}
-		activeSet := make(map[string]bool, len(activeSubjects))
-		for _, s := range activeSubjects {
-			activeSet[s] = true
-		}
-		var deletedSubjects []string
-		for _, s := range subjects {
-			if !activeSet[s] {
-				deletedSubjects = append(deletedSubjects, s)
-			}
-		}
-		if deletedSubjects == nil {
-			deletedSubjects = []string{}
-		}
-		subjects = deletedSubjects
+	writeJSON(w, http.StatusOK, subjects)
+}
+
+// ListSubjectCatalog handles GET /catalog/subjects. It supports the subject
+// filters and pagination of ListSubjects, and include selects which of
+// latestVersion, config, mode and meta are returned for each subject
+// (default: all).
+func (h *Handler) ListSubjectCatalog(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	q := r.URL.Query()
+	opts := registry.SubjectCatalogOptions{
+		Prefix:  q.Get("subjectPrefix"),
+		Deleted: q.Get("deleted") == "true",
 	}
+	opts.Offset, _ = strconv.Atoi(q.Get("offset"))
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+	opts.Limit, _ = strconv.Atoi(q.Get("limit"))
 
-	// Filter by subject prefix if specified
-	if subjectPrefix != "" {
-		filtered := make([]string, 0)
-		for _, s := range subjects {
-			if strings.HasPrefix(s, subjectPrefix) {
-				filtered = append(filtered, s)
+	if include := q.Get("include"); include == "" {
+		opts.IncludeLatest, opts.IncludeConfig, opts.IncludeMode, opts.IncludeMeta = true, true, true, true
+	} else {
+		for _, field := range strings.Split(include, ",") {
+			switch strings.TrimSpace(field) {
+			case "latestVersion":
+				opts.IncludeLatest = true
+			case "config":
+				opts.IncludeConfig = true
+			case "mode":
+				opts.IncludeMode = true
+			case "meta":
+				opts.IncludeMeta = true
+			default:
+				writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema,
+					fmt.Sprintf("Query parameter 'include' has unknown field '%s'; allowed fields are latestVersion, config, mode and meta", field))
+				return
 			}
 		}
-		subjects = filtered
 	}
 
-	// Apply pagination (offset/limit)
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	if offset < 0 {
-		offset = 0
-	}
-	limit := -1 // default: unlimited
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		limit, _ = strconv.Atoi(limitStr)
+	catalog, err := h.registry.ListSubjectCatalog(r.Context(), registryCtx, opts)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
 	}
 
-	if offset > 0 {
-		if offset >= len(subjects) {
-			subjects = []string{}
-		} else {
-			subjects = subjects[offset:]
-		}
+	resp := types.SubjectCatalogResponse{
+		Subjects: make([]types.SubjectCatalogEntry, 0, len(catalog.Subjects)),
+		Total:    catalog.Total,
+		Offset:   opts.Offset,
 	}
-	if limit > 0 && limit < len(subjects) {
-		subjects = subjects[:limit]
+	if opts.Limit > 0 {
+		resp.Limit = opts.Limit
 	}
-
-	writeJSON(w, http.StatusOK, subjects)
+	for _, entry := range catalog.Subjects {
+		item := types.SubjectCatalogEntry{
+			Subject:            entry.Subject,
+			CompatibilityLevel: entry.CompatibilityLevel,
+			Mode:               entry.Mode,
+		}
+		if entry.Meta != nil {
+			item.Meta = subjectMetaResponse(entry.Meta)
+		}
+		if schema := entry.Latest; schema != nil {
+			item.LatestVersion = &types.SubjectVersionResponse{
+				Subject:    schema.Subject,
+				ID:         schema.ID,
+				Version:    schema.Version,
+				SchemaType: schemaTypeForResponse(schema.SchemaType),
+				Schema:     schema.Schema,
+				Metadata:   withConfluentVersion(schema.Metadata, schema.Version),
+				RuleSet:    schema.RuleSet,
+			}
+			if len(schema.References) > 0 {
+				item.LatestVersion.References = schema.References
+			}
+		}
+		resp.Subjects = append(resp.Subjects, item)
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 // GetVersions handles GET /subjects/{subject}/versions
@@ -340,45 +481,158 @@ func (h *Handler) GetVersions(w http.ResponseWriter, r *http.Request) {
 	deleted := r.URL.Query().Get("deleted") == "true"
 	deletedOnly := r.URL.Query().Get("deletedOnly") == "true"
 
-	// deletedOnly takes precedence: if set, we include deleted and filter to only deleted
-	includeDeleted := deleted || deletedOnly
-	versions, err := h.registry.GetVersions(r.Context(), registryCtx, subject, includeDeleted)
+	if !deletedOnly {
+		h.listVersionsPage(w, r, registryCtx, subject, deleted)
+		return
+	}
+
+	// deletedOnly: include deleted and filter to only deleted
+	versions, err := h.registry.GetVersions(r.Context(), registryCtx, subject, true)
 	if err != nil {
 		if errors.Is(err, storage.ErrSubjectNotFound) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Subject not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
-	if deletedOnly {
-		// Filter to only deleted versions by getting all versions and non-deleted, then diffing
-		activeVersions, err := h.registry.GetVersions(r.Context(), registryCtx, subject, false)
-		if err != nil && !errors.Is(err, storage.ErrSubjectNotFound) {
-			writeInternalError(w, err)
-			return
+	// Filter to only deleted versions by getting all versions and non-deleted, then diffing
+	activeVersions, err := h.registry.GetVersions(r.Context(), registryCtx, subject, false)
+	if err != nil && !errors.Is(err, storage.ErrSubjectNotFound) {
+		writeRegistryError(w, err)
+		return
+	}
+	activeSet := make(map[int]bool, len(activeVersions))
+	for _, v := range activeVersions {
+		activeSet[v] = true
+	}
+	deletedVersions := []int{}
+	for _, v := range versions {
+		if !activeSet[v] {
+			deletedVersions = append(deletedVersions, v)
 		}
-		activeSet := make(map[int]bool, len(activeVersions))
-		for _, v := range activeVersions {
-			activeSet[v] = true
+	}
+
+	start, end := parsePagination(r, len(deletedVersions))
+	if h.maxVersionsPageSize > 0 && end-start > h.maxVersionsPageSize {
+		end = start + h.maxVersionsPageSize
+	}
+	if end < len(deletedVersions) && end > start {
+		setNextPageLink(w, r, start, end-start)
+	}
+	writeJSON(w, http.StatusOK, deletedVersions[start:end])
+}
+
+// listVersionsPage serves GET /subjects/{subject}/versions with offset, limit
+// and latestOnly pushed down to storage, so large subjects are not scanned in
+// full. A limit above the configured maximum page size is reduced to it.
+func (h *Handler) listVersionsPage(w http.ResponseWriter, r *http.Request, registryCtx, subject string, deleted bool) {
+	params := &storage.ListVersionsParams{
+		Deleted:    deleted,
+		LatestOnly: r.URL.Query().Get("latestOnly") == "true",
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			params.Offset = o
 		}
-		var deletedVersions []int
-		for _, v := range versions {
-			if !activeSet[v] {
-				deletedVersions = append(deletedVersions, v)
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l >= 0 {
+			if l == 0 {
+				// An explicit limit of 0 yields an empty page, as before.
+				writeJSON(w, http.StatusOK, []int{})
+				return
 			}
+			params.Limit = l
 		}
-		if deletedVersions == nil {
-			deletedVersions = []int{}
+	}
+	if h.maxVersionsPageSize > 0 && (params.Limit == 0 || params.Limit > h.maxVersionsPageSize) {
+		params.Limit = h.maxVersionsPageSize
+	}
+	limit := params.Limit
+	if limit > 0 && !params.LatestOnly {
+		// One extra version tells whether there is a next page.
+		params.Limit++
+	}
+
+	versions, err := h.registry.ListVersions(r.Context(), registryCtx, subject, params)
+	if err != nil {
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Subject not found")
+			return
 		}
-		writeJSON(w, http.StatusOK, deletedVersions)
+		writeRegistryError(w, err)
 		return
 	}
+	if limit > 0 && len(versions) > limit {
+		versions = versions[:limit]
+		setNextPageLink(w, r, params.Offset, limit)
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
 
-	// Apply pagination
-	start, end := parsePagination(r, len(versions))
-	writeJSON(w, http.StatusOK, versions[start:end])
+// GetCompatibleVersions handles GET /subjects/{subject}/versions/compatible-with
+// It returns the versions a reader schema (given by ?schemaId=) can consume.
+func (h *Handler) GetCompatibleVersions(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+
+	schemaID, err := strconv.ParseInt(r.URL.Query().Get("schemaId"), 10, 64)
+	if err != nil || schemaID <= 0 {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Query parameter 'schemaId' must be a positive schema ID")
+		return
+	}
+
+	versions, level, err := h.registry.GetCompatibleVersions(r.Context(), registryCtx, subject, schemaID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSchemaNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
+			return
+		}
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, fmt.Sprintf("Subject '%s' not found.", subject))
+			return
+		}
+		if errors.Is(err, registry.ErrReferenceCycle) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeReferenceCycle, err.Error())
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, types.CompatibleVersionsResponse{
+		Subject:            subject,
+		SchemaID:           schemaID,
+		CompatibilityLevel: level,
+		Versions:           versions,
+	})
+}
+
+// GetTopicSubjects handles GET /topics/{topic}/subjects
+func (h *Handler) GetTopicSubjects(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	topic := chi.URLParam(r, "topic")
+
+	subjects, err := h.registry.GetTopicSubjects(r.Context(), registryCtx, topic)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, types.TopicSubjectsResponse{
+		Topic:   subjects.Topic,
+		Key:     subjects.Key,
+		Value:   subjects.Value,
+		Records: subjects.Records,
+	})
 }
 
 // GetVersion handles GET /subjects/{subject}/versions/{version}
@@ -427,10 +681,11 @@ func (h *Handler) GetVersion(w http.ResponseWriter, r *http.Request) {
 				writeError(w, http.StatusNotFound, types.ErrorCodeVersionNotFound, "Version not found")
 				return
 			}
-			writeInternalError(w, err)
+			writeRegistryError(w, err)
 			return
 		}
 	}
+	h.usage.RecordVersion(registryCtx, schema.Subject, schema.Version, schema.ID)
 
 	schemaStr := schema.Schema
 	if format := r.URL.Query().Get("format"); format != "" {
@@ -455,7 +710,10 @@ func (h *Handler) GetVersion(w http.ResponseWriter, r *http.Request) {
 		if schema.RuleSet != nil {
 			resp["ruleSet"] = schema.RuleSet
 		}
-		writeJSON(w, http.StatusOK, resp)
+		if fp := h.responseFingerprint(r.Context(), registryCtx, schema); fp != "" {
+			resp["fingerprint"] = fp
+		}
+		writeJSONFields(w, r, http.StatusOK, resp)
 		return
 	}
 
@@ -467,12 +725,14 @@ func (h *Handler) GetVersion(w http.ResponseWriter, r *http.Request) {
 		Schema:     schemaStr,
 		Metadata:   withConfluentVersion(schema.Metadata, schema.Version),
 		RuleSet:    schema.RuleSet,
+
+		Fingerprint: h.responseFingerprint(r.Context(), registryCtx, schema),
 	}
 	if len(schema.References) > 0 {
 		resp.References = schema.References
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	writeJSONFields(w, r, http.StatusOK, resp)
 }
 
 // withConfluentVersion returns a copy of the metadata with confluent:version set
@@ -567,7 +827,11 @@ func (h *Handler) RegisterSchema(w http.ResponseWriter, r *http.Request) {
 	if rejectGlobalContext(w, registryCtx) {
 		return
 	}
-	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+	subject, err := h.registry.ResolveWriteAlias(r.Context(), registryCtx, subject)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
 
 	// Parse request body and set audit hints BEFORE mode enforcement so that
 	// all exit paths (including READONLY/IMPORT blocks) have full audit context.
@@ -603,7 +867,7 @@ func (h *Handler) RegisterSchema(w http.ResponseWriter, r *http.Request) {
 
 	// Check mode enforcement
 	if mode, modeErr := h.registry.CheckModeForWrite(r.Context(), registryCtx, subject); modeErr != nil {
-		writeInternalError(w, modeErr)
+		writeRegistryError(w, modeErr)
 		return
 	} else if mode != "" {
 		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
@@ -618,7 +882,6 @@ func (h *Handler) RegisterSchema(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var schema *storage.SchemaRecord
-	var err error
 
 	// Confluent behavior: IMPORT mode requires explicit ID, READWRITE mode requires no ID.
 	// These are mutually exclusive operational modes.
@@ -656,47 +919,31 @@ func (h *Handler) RegisterSchema(w http.ResponseWriter, r *http.Request) {
 				"Subject is in import mode. Normal registration (without explicit ID) is not permitted in IMPORT mode.")
 			return
 		}
-		schema, err = h.registry.RegisterSchema(r.Context(), registryCtx, subject, req.Schema, schemaType, req.References, registry.RegisterOpts{
+		if h.requiresApproval(r, registryCtx) {
+			h.submitPendingSchema(w, r, registryCtx, subject, &req, schemaType, normalizeSchema)
+			return
+		}
+		opts := registry.RegisterOpts{
 			Normalize: normalizeSchema,
 			Metadata:  req.Metadata,
 			RuleSet:   req.RuleSet,
-		})
-	}
-	if err != nil {
-		if errors.Is(err, registry.ErrInvalidRuleSet) {
-			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
-			return
-		}
-		if errors.Is(err, registry.ErrInvalidSchema) {
-			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
-			return
 		}
-		if errors.Is(err, registry.ErrUnsupportedSchemaType) {
-			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
-			return
-		}
-		if errors.Is(err, registry.ErrFailedResolveReferences) {
-			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
-			return
-		}
-		if errors.Is(err, registry.ErrIncompatibleSchema) {
-			if hints := auth.GetAuditHints(r.Context()); hints != nil {
-				hints.Reason = "incompatible"
-			}
-			writeError(w, http.StatusConflict, types.ErrorCodeIncompatibleSchema, err.Error())
-			return
+		if user := auth.GetUser(r.Context()); user != nil {
+			opts.Principal, opts.Role = user.Username, user.Role
 		}
+		schema, err = h.registry.RegisterSchema(r.Context(), registryCtx, subject, req.Schema, schemaType, req.References, opts)
+	}
+	if err != nil {
 		if errors.Is(err, registry.ErrImportIDConflict) {
 			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
 				fmt.Sprintf("Overwrite new schema with id %d is not permitted.", req.ID))
 			return
 		}
-		writeInternalError(w, err)
+		writeRegisterError(w, r, err)
 		return
 	}
 
 	if h.metrics != nil {
-		h.metrics.RecordSchemaRegistration(string(schema.SchemaType), true)
 		h.metrics.SchemaVersions.WithLabelValues(subject).Set(float64(schema.Version))
 	}
 
@@ -716,11 +963,204 @@ func (h *Handler) RegisterSchema(w http.ResponseWriter, r *http.Request) {
 		hints.Context = registryCtx
 	}
 
-	writeJSON(w, http.StatusOK, types.RegisterSchemaResponse{
-		ID: schema.ID,
+	// Imports are not linted
+	resp := types.RegisterSchemaResponse{ID: schema.ID}
+	if req.ID == 0 {
+		resp.Warnings = h.lintWarnings(r, registryCtx, subject, &req, schemaType)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// RegisterSchemaIfAbsent handles PUT /subjects/{subject}/versions/{fingerprint}.
+// It registers the schema unless a live version with the same fingerprint
+// already exists, so the request is safe to retry: it responds 201 when a new
+// version is created and 200 with the existing schema ID otherwise.
+func (h *Handler) RegisterSchemaIfAbsent(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject, err := h.registry.ResolveWriteAlias(r.Context(), registryCtx, subject)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	fingerprint := chi.URLParam(r, "fingerprint")
+
+	var req types.RegisterSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	if req.Schema == "" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, "Empty schema")
+		return
+	}
+	if req.ID > 0 {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
+			"Explicit schema IDs are not supported by idempotent registration. Use POST /subjects/{subject}/versions in IMPORT mode.")
+		return
+	}
+
+	schemaType, ok := storage.ParseSchemaType(req.SchemaType)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema,
+			fmt.Sprintf("Invalid schema type '%s'. Accepted types are AVRO, PROTOBUF, and JSON", req.SchemaType))
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "subject"
+		hints.TargetID = chi.URLParam(r, "subject")
+		hints.SchemaType = string(schemaType)
+		hints.Context = registryCtx
+	}
+
+	// Same mode enforcement as normal registration without an explicit ID.
+	if mode, modeErr := h.registry.CheckModeForWrite(r.Context(), registryCtx, subject); modeErr != nil {
+		writeRegistryError(w, modeErr)
+		return
+	} else if mode != "" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
+			fmt.Sprintf("Subject '%s' is in %s mode", subject, mode))
+		return
+	}
+	mode, modeErr := h.registry.GetMode(r.Context(), registryCtx, subject)
+	if modeErr != nil {
+		writeInternalError(w, modeErr)
+		return
+	}
+	if mode == "READONLY" || mode == "READONLY_OVERRIDE" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
+			fmt.Sprintf("Subject '%s' is in read-only mode", subject))
+		return
+	}
+	if mode == "IMPORT" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
+			"Subject is in import mode. Normal registration (without explicit ID) is not permitted in IMPORT mode.")
+		return
+	}
+	if h.requiresApproval(r, registryCtx) {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
+			fmt.Sprintf("Registrations in context '%s' require approval. Use POST /subjects/{subject}/versions to submit the schema for review.", registryCtx))
+		return
+	}
+
+	var prevFingerprint string
+	if prev, _ := h.registry.GetLatestSchema(r.Context(), registryCtx, subject); prev != nil {
+		prevFingerprint = prev.Fingerprint
+	}
+
+	opts := registry.RegisterOpts{
+		Normalize: r.URL.Query().Get("normalize") == "true",
+		Metadata:  req.Metadata,
+		RuleSet:   req.RuleSet,
+	}
+	if user := auth.GetUser(r.Context()); user != nil {
+		opts.Principal, opts.Role = user.Username, user.Role
+	}
+	schema, created, err := h.registry.RegisterSchemaIfAbsent(r.Context(), registryCtx, subject, fingerprint, req.Schema, schemaType, req.References, opts)
+	if err != nil {
+		writeRegisterError(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		if h.metrics != nil {
+			h.metrics.SchemaVersions.WithLabelValues(subject).Set(float64(schema.Version))
+		}
+		if hints := auth.GetAuditHints(r.Context()); hints != nil {
+			if prevFingerprint != "" {
+				hints.BeforeHash = "sha256:" + prevFingerprint
+			}
+			if schema.Fingerprint != "" {
+				hints.AfterHash = "sha256:" + schema.Fingerprint
+			}
+			hints.SchemaType = string(schema.SchemaType)
+			hints.SchemaID = schema.ID
+			hints.Version = schema.Version
+		}
+	}
+
+	writeJSON(w, status, types.RegisterSchemaResponse{
+		ID:       schema.ID,
+		Warnings: h.lintWarnings(r, registryCtx, subject, &req, schemaType),
 	})
 }
 
+// lintWarnings returns the warn-severity lint violations of a registered
+// schema for the response. The schema already passed the error-severity
+// rules during registration.
+func (h *Handler) lintWarnings(r *http.Request, registryCtx, subject string, req *types.RegisterSchemaRequest, schemaType storage.SchemaType) []string {
+	violations, err := h.registry.LintSchema(r.Context(), registryCtx, subject, req.Schema, schemaType, req.References)
+	if err != nil {
+		return nil
+	}
+	var warnings []string
+	for _, v := range lint.Warnings(violations) {
+		warnings = append(warnings, v.String())
+	}
+	return warnings
+}
+
+// requiresApproval reports whether a registration by the caller is held for
+// approval, which is the case for developers in approval contexts.
+func (h *Handler) requiresApproval(r *http.Request, registryCtx string) bool {
+	user := auth.GetUser(r.Context())
+	return user != nil && auth.Role(user.Role) == auth.RoleDeveloper && h.registry.RequiresApproval(registryCtx)
+}
+
+// submitPendingSchema holds a registration for approval and responds 202
+// with the pending schema. A schema that is already registered is not held;
+// its ID is returned as for a normal registration.
+func (h *Handler) submitPendingSchema(w http.ResponseWriter, r *http.Request, registryCtx, subject string, req *types.RegisterSchemaRequest, schemaType storage.SchemaType, normalize bool) {
+	pending, existing, err := h.registry.SubmitPendingSchema(r.Context(), registryCtx, subject, registry.PendingSchemaRequest{
+		Schema:     req.Schema,
+		SchemaType: schemaType,
+		References: req.References,
+		Metadata:   req.Metadata,
+		RuleSet:    req.RuleSet,
+		Normalize:  normalize,
+	}, auth.GetUser(r.Context()).Username)
+	if err != nil {
+		writeRegisterError(w, r, err)
+		return
+	}
+	if existing != nil {
+		if hints := auth.GetAuditHints(r.Context()); hints != nil {
+			hints.SchemaID = existing.ID
+			hints.Version = existing.Version
+		}
+		writeJSON(w, http.StatusOK, types.RegisterSchemaResponse{ID: existing.ID})
+		return
+	}
+	// The audit event of the submission tells the assigned reviewers, through
+	// the audit webhook, that a schema awaits them.
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "subject"
+		hints.TargetID = subject
+		hints.Context = registryCtx
+		hints.SchemaType = string(schemaType)
+		hints.Metadata = map[string]string{"pending_schema_id": pending.ID}
+		if len(pending.Reviewers) > 0 {
+			hints.Metadata["reviewers"] = strings.Join(pending.Reviewers, ",")
+		}
+	}
+	writeJSON(w, http.StatusAccepted, pendingSchemaToResponse(pending))
+}
+
+// writeRegisterError maps a schema registration error to its API error response.
+func writeRegisterError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, registry.ErrIncompatibleSchema) {
+		if hints := auth.GetAuditHints(r.Context()); hints != nil {
+			hints.Reason = "incompatible"
+		}
+	}
+	writeRegistryError(w, err)
+}
+
 // LookupSchema handles POST /subjects/{subject}
 func (h *Handler) LookupSchema(w http.ResponseWriter, r *http.Request) {
 	registryCtx, subject := resolveSubjectAndContext(r)
@@ -760,6 +1200,10 @@ func (h *Handler) LookupSchema(w http.ResponseWriter, r *http.Request) {
 	normalizeSchema := r.URL.Query().Get("normalize") == "true"
 	schema, err := h.registry.LookupSchema(r.Context(), registryCtx, subject, req.Schema, schemaType, req.References, deleted, normalizeSchema)
 	if err != nil {
+		if errors.Is(err, registry.ErrReferenceCycle) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeReferenceCycle, err.Error())
+			return
+		}
 		if errors.Is(err, storage.ErrSubjectNotFound) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, fmt.Sprintf("Subject '%s' not found.", subject))
 			return
@@ -772,7 +1216,7 @@ func (h *Handler) LookupSchema(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -781,6 +1225,7 @@ func (h *Handler) LookupSchema(w http.ResponseWriter, r *http.Request) {
 		hints.SchemaID = schema.ID
 		hints.Version = schema.Version
 	}
+	h.usage.RecordVersion(registryCtx, schema.Subject, schema.Version, schema.ID)
 
 	resp := types.LookupSchemaResponse{
 		Subject:    schema.Subject,
@@ -795,7 +1240,7 @@ func (h *Handler) LookupSchema(w http.ResponseWriter, r *http.Request) {
 		resp.References = schema.References
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	writeJSONFields(w, r, http.StatusOK, resp)
 }
 
 // DeleteSubject handles DELETE /subjects/{subject}
@@ -804,12 +1249,16 @@ func (h *Handler) DeleteSubject(w http.ResponseWriter, r *http.Request) {
 	if rejectGlobalContext(w, registryCtx) {
 		return
 	}
-	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+	subject, err := h.registry.ResolveWriteAlias(r.Context(), registryCtx, subject)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
 	permanent := r.URL.Query().Get("permanent") == "true"
 
 	// Check mode enforcement
 	if mode, modeErr := h.registry.CheckModeForWrite(r.Context(), registryCtx, subject); modeErr != nil {
-		writeInternalError(w, modeErr)
+		writeRegistryError(w, modeErr)
 		return
 	} else if mode != "" {
 		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
@@ -817,6 +1266,10 @@ func (h *Handler) DeleteSubject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if permanent && h.registry.RequiresDeleteConfirmation() && !h.confirmPermanentDelete(w, r, registryCtx, subject, 0) {
+		return
+	}
+
 	// Capture schema type and fingerprint before deletion for metrics and audit.
 	var deletionSchemaType string
 	var beforeFingerprint string
@@ -854,7 +1307,7 @@ func (h *Handler) DeleteSubject(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeReferenceExists, err.Error())
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -883,13 +1336,17 @@ func (h *Handler) DeleteVersion(w http.ResponseWriter, r *http.Request) {
 	if rejectGlobalContext(w, registryCtx) {
 		return
 	}
-	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+	subject, err := h.registry.ResolveWriteAlias(r.Context(), registryCtx, subject)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
 	versionStr := chi.URLParam(r, "version")
 	permanent := r.URL.Query().Get("permanent") == "true"
 
 	// Check mode enforcement
 	if mode, modeErr := h.registry.CheckModeForWrite(r.Context(), registryCtx, subject); modeErr != nil {
-		writeInternalError(w, modeErr)
+		writeRegistryError(w, modeErr)
 		return
 	} else if mode != "" {
 		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
@@ -904,6 +1361,10 @@ func (h *Handler) DeleteVersion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if permanent && h.registry.RequiresDeleteConfirmation() && !h.confirmPermanentDelete(w, r, registryCtx, subject, version) {
+		return
+	}
+
 	// Capture schema details before deletion for metrics and audit.
 	var deletionSchemaType string
 	var beforeFingerprint string
@@ -951,7 +1412,7 @@ func (h *Handler) DeleteVersion(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeReferenceExists, err.Error())
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -992,7 +1453,7 @@ func (h *Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
 					fmt.Sprintf("Subject '%s' does not have subject-level compatibility configured", subject))
 				return
 			}
-			writeInternalError(w, err)
+			writeRegistryError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusOK, configToResponse(config))
@@ -1010,7 +1471,7 @@ func (h *Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
 		config, err = h.registry.GetConfigFull(r.Context(), registryCtx, subject)
 	}
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1023,7 +1484,7 @@ func (h *Handler) SetConfig(w http.ResponseWriter, r *http.Request) {
 
 	// Check mode enforcement — Confluent blocks config writes in READONLY mode
 	if mode, modeErr := h.registry.CheckModeForWrite(r.Context(), registryCtx, subject); modeErr != nil {
-		writeInternalError(w, modeErr)
+		writeRegistryError(w, modeErr)
 		return
 	} else if mode != "" {
 		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
@@ -1041,7 +1502,7 @@ func (h *Handler) SetConfig(w http.ResponseWriter, r *http.Request) {
 	if req.Compatibility == "" {
 		level, err := h.registry.GetConfig(r.Context(), registryCtx, subject)
 		if err != nil {
-			writeInternalError(w, err)
+			writeRegistryError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusOK, types.ConfigRequest{Compatibility: level})
@@ -1074,7 +1535,7 @@ func (h *Handler) SetConfig(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1111,7 +1572,7 @@ func (h *Handler) DeleteConfig(w http.ResponseWriter, r *http.Request) {
 
 	// Check mode enforcement — Confluent blocks config writes in READONLY mode
 	if mode, modeErr := h.registry.CheckModeForWrite(r.Context(), registryCtx, subject); modeErr != nil {
-		writeInternalError(w, modeErr)
+		writeRegistryError(w, modeErr)
 		return
 	} else if mode != "" {
 		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
@@ -1125,7 +1586,7 @@ func (h *Handler) DeleteConfig(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Config not found for subject")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1173,6 +1634,10 @@ func (h *Handler) CheckCompatibility(w http.ResponseWriter, r *http.Request) {
 	normalizeSchema := r.URL.Query().Get("normalize") == "true"
 	result, err := h.registry.CheckCompatibility(r.Context(), registryCtx, subject, req.Schema, schemaType, req.References, versionStr, normalizeSchema)
 	if err != nil {
+		if errors.Is(err, registry.ErrReferenceCycle) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeReferenceCycle, err.Error())
+			return
+		}
 		if errors.Is(err, registry.ErrInvalidSchema) {
 			if h.metrics != nil {
 				h.metrics.RecordCompatibilityError(string(schemaType), "")
@@ -1202,7 +1667,7 @@ func (h *Handler) CheckCompatibility(w http.ResponseWriter, r *http.Request) {
 		if h.metrics != nil {
 			h.metrics.RecordCompatibilityError(string(schemaType), "")
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1222,6 +1687,8 @@ func (h *Handler) CheckCompatibility(w http.ResponseWriter, r *http.Request) {
 	}
 	if verbose {
 		resp.Messages = result.Messages
+		resp.Warnings = result.Warnings
+		resp.Incompatibilities = result.Incompatibilities
 	}
 	writeJSON(w, http.StatusOK, resp)
 }
@@ -1253,13 +1720,13 @@ func (h *Handler) GetReferencedBy(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeVersionNotFound, "Version not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
 	refs, err := h.registry.GetReferencedBy(r.Context(), registryCtx, subject, version)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1274,7 +1741,8 @@ func (h *Handler) GetReferencedBy(w http.ResponseWriter, r *http.Request) {
 		result = append(result, int(schema.ID))
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	start, end := parsePagination(r, len(result))
+	writeJSON(w, http.StatusOK, result[start:end])
 }
 
 // GetMode handles GET /mode and GET /mode/{subject}
@@ -1282,6 +1750,22 @@ func (h *Handler) GetMode(w http.ResponseWriter, r *http.Request) {
 	registryCtx, subject := resolveSubjectAndContext(r)
 	defaultToGlobal := r.URL.Query().Get("defaultToGlobal") == "true"
 
+	// An active maintenance window overrides every mode, as READONLY_OVERRIDE
+	// does, so it is reported whatever was asked for.
+	active, err := h.registry.ActiveMaintenance(r.Context())
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	if active != nil {
+		resp := maintenanceToResponse(active, time.Now())
+		writeJSON(w, http.StatusOK, types.ModeResponse{
+			Mode:        "READONLY_OVERRIDE",
+			Maintenance: &resp,
+		})
+		return
+	}
+
 	if subject != "" && !defaultToGlobal {
 		// Subject-specific mode only, no fallback to global
 		mode, err := h.registry.GetSubjectMode(r.Context(), registryCtx, subject)
@@ -1291,7 +1775,7 @@ func (h *Handler) GetMode(w http.ResponseWriter, r *http.Request) {
 					fmt.Sprintf("Subject '%s' does not have subject-level mode configured", subject))
 				return
 			}
-			writeInternalError(w, err)
+			writeRegistryError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusOK, types.ModeResponse{
@@ -1303,14 +1787,13 @@ func (h *Handler) GetMode(w http.ResponseWriter, r *http.Request) {
 	// When subject is empty and defaultToGlobal is false, return context's direct
 	// global mode without the __GLOBAL fallback (Confluent-compatible).
 	var mode string
-	var err error
 	if subject == "" && !defaultToGlobal {
 		mode, err = h.registry.GetGlobalModeDirect(r.Context(), registryCtx)
 	} else {
 		mode, err = h.registry.GetMode(r.Context(), registryCtx, subject)
 	}
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1338,12 +1821,12 @@ func (h *Handler) SetMode(w http.ResponseWriter, r *http.Request) {
 		prevMode := h.getPreviousSubjectMode(r.Context(), registryCtx, subject)
 		if subject != "" {
 			if _, err := h.registry.DeleteMode(r.Context(), registryCtx, subject); err != nil && !errors.Is(err, storage.ErrNotFound) {
-				writeInternalError(w, err)
+				writeRegistryError(w, err)
 				return
 			}
 		} else {
 			if _, err := h.registry.DeleteGlobalMode(r.Context(), registryCtx); err != nil && !errors.Is(err, storage.ErrNotFound) {
-				writeInternalError(w, err)
+				writeRegistryError(w, err)
 				return
 			}
 		}
@@ -1376,7 +1859,7 @@ func (h *Handler) SetMode(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted, err.Error())
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1396,6 +1879,41 @@ func (h *Handler) SetMode(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// pageOffset returns the offset query parameter, or 0 when it is missing or
+// invalid.
+func pageOffset(r *http.Request) int {
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		return 0
+	}
+	return offset
+}
+
+// pageLimit returns the limit query parameter reduced to maxPageSize, or
+// maxPageSize when the limit is missing or not positive (Confluent clients
+// send -1 for no limit). 0 means no limit.
+func pageLimit(r *http.Request, maxPageSize int) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+	if maxPageSize > 0 && (limit == 0 || limit > maxPageSize) {
+		limit = maxPageSize
+	}
+	return limit
+}
+
+// setNextPageLink sets a Link header (RFC 8288) pointing at the page after
+// the one of limit entries starting at offset, keeping the other query
+// parameters of the request.
+func setNextPageLink(w http.ResponseWriter, r *http.Request, offset, limit int) {
+	q := r.URL.Query()
+	q.Set("offset", strconv.Itoa(offset+limit))
+	q.Set("limit", strconv.Itoa(limit))
+	next := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+	w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+}
+
 // parsePagination extracts offset and limit query params and applies them to a slice length.
 // Returns the start and end indices for slicing.
 func parsePagination(r *http.Request, total int) (start, end int) {
@@ -1470,15 +1988,24 @@ func writeInternalError(w http.ResponseWriter, err error) {
 
 // GetRawSchemaByID handles GET /schemas/ids/{id}/schema
 func (h *Handler) GetRawSchemaByID(w http.ResponseWriter, r *http.Request) {
+	mediaType, ok := negotiateRawSchemaType(r)
+	if !ok {
+		writeNotAcceptable(w)
+		return
+	}
+
 	registryCtx := getRegistryContext(r)
 	if rejectGlobalContext(w, registryCtx) {
 		return
 	}
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, ok := h.schemaIDParam(w, r, registryCtx)
+	if !ok {
+		return
+	}
+	registryCtx, err := h.registry.SchemaIDContext(r.Context(), registryCtx, id)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid schema ID")
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1488,19 +2015,17 @@ func (h *Handler) GetRawSchemaByID(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
+	h.usage.RecordID(registryCtx, id)
 
 	result := schemaRecord.Schema
 	if format := r.URL.Query().Get("format"); format != "" {
 		result = h.registry.FormatSchema(r.Context(), registryCtx, schemaRecord, format)
 	}
 
-	// Return raw schema as plain text
-	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(result)) // #nosec G705 -- schema content from storage, not user input
+	writeRawSchema(w, mediaType, result)
 }
 
 // GetSubjectsBySchemaID handles GET /schemas/ids/{id}/subjects
@@ -1510,10 +2035,8 @@ func (h *Handler) GetSubjectsBySchemaID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid schema ID")
+	id, ok := h.schemaIDParam(w, r, registryCtx)
+	if !ok {
 		return
 	}
 
@@ -1526,7 +2049,7 @@ func (h *Handler) GetSubjectsBySchemaID(w http.ResponseWriter, r *http.Request)
 			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1556,10 +2079,8 @@ func (h *Handler) GetVersionsBySchemaID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid schema ID")
+	id, ok := h.schemaIDParam(w, r, registryCtx)
+	if !ok {
 		return
 	}
 
@@ -1572,7 +2093,7 @@ func (h *Handler) GetVersionsBySchemaID(w http.ResponseWriter, r *http.Request)
 			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1590,7 +2111,89 @@ func (h *Handler) GetVersionsBySchemaID(w http.ResponseWriter, r *http.Request)
 
 	// Apply pagination
 	start, end := parsePagination(r, len(result))
-	writeJSON(w, http.StatusOK, result[start:end])
+	writeJSONFields(w, r, http.StatusOK, result[start:end])
+}
+
+// fingerprintMatchResponse converts a fingerprint match to its response.
+func fingerprintMatchResponse(match *registry.FingerprintMatch) types.SchemaFingerprintMatch {
+	resp := types.SchemaFingerprintMatch{
+		Fingerprint: match.Fingerprint,
+		ID:          match.Schema.ID,
+		SchemaType:  schemaTypeForResponse(match.Schema.SchemaType),
+		Versions:    make([]types.SubjectVersionPair, 0, len(match.Versions)),
+	}
+	for _, sv := range match.Versions {
+		resp.Versions = append(resp.Versions, types.SubjectVersionPair{Subject: sv.Subject, Version: sv.Version})
+	}
+	return resp
+}
+
+// GetSchemaByFingerprint handles GET /schemas/fingerprint/{fingerprint}. It
+// returns the schema with the fingerprint and every subject version using
+// it, whatever the subject.
+func (h *Handler) GetSchemaByFingerprint(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+
+	deleted := r.URL.Query().Get("deleted") == "true"
+	match, err := h.registry.FindSchemaByFingerprint(r.Context(), registryCtx, chi.URLParam(r, "fingerprint"), deleted)
+	if err != nil {
+		if errors.Is(err, storage.ErrSchemaNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, fingerprintMatchResponse(match))
+}
+
+// LookupSchemaGlobal handles POST /schemas/lookup-global. It finds where a
+// schema is registered in the context without knowing the subject, for
+// instance from the schema text of a message dump.
+func (h *Handler) LookupSchemaGlobal(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	deleted := r.URL.Query().Get("deleted") == "true"
+
+	var req types.LookupSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	if req.Schema == "" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, "Empty schema")
+		return
+	}
+	schemaType, ok := storage.ParseSchemaType(req.SchemaType)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema,
+			fmt.Sprintf("Invalid schema type '%s'. Accepted types are AVRO, PROTOBUF, and JSON", req.SchemaType))
+		return
+	}
+
+	matches, err := h.registry.LookupSchemaGlobal(r.Context(), registryCtx, req.Schema, schemaType, req.References, deleted)
+	if err != nil {
+		if errors.Is(err, storage.ErrSchemaNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
+			return
+		}
+		if errors.Is(err, registry.ErrInvalidSchema) {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, err.Error())
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+	resp := make([]types.SchemaFingerprintMatch, 0, len(matches))
+	for _, match := range matches {
+		resp = append(resp, fingerprintMatchResponse(match))
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 // ListSchemas handles GET /schemas
@@ -1604,25 +2207,25 @@ func (h *Handler) ListSchemas(w http.ResponseWriter, r *http.Request) {
 		SubjectPrefix: r.URL.Query().Get("subjectPrefix"),
 		Deleted:       r.URL.Query().Get("deleted") == "true",
 		LatestOnly:    r.URL.Query().Get("latestOnly") == "true",
+		Aliases:       r.URL.Query().Get("aliases") == "true",
 	}
 
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			params.Offset = offset
-		}
-	}
-
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			params.Limit = limit
-		}
+	params.Offset = pageOffset(r)
+	limit := pageLimit(r, h.maxListPageSize)
+	if limit > 0 {
+		// One extra schema tells whether there is a next page.
+		params.Limit = limit + 1
 	}
 
 	schemas, err := h.registry.ListSchemas(r.Context(), registryCtx, params)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
+	if limit > 0 && len(schemas) > limit {
+		schemas = schemas[:limit]
+		setNextPageLink(w, r, params.Offset, limit)
+	}
 
 	// Convert to response format
 	result := make([]types.SchemaListItem, 0, len(schemas))
@@ -1637,7 +2240,7 @@ func (h *Handler) ListSchemas(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	writeJSONFields(w, r, http.StatusOK, result)
 }
 
 // ImportSchemas handles POST /import/schemas
@@ -1696,22 +2299,7 @@ func (h *Handler) ImportSchemas(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Convert API types to registry types
-	importReqs := make([]registry.ImportSchemaRequest, len(req.Schemas))
-	for i, s := range req.Schemas {
-		schemaType := storage.SchemaType(strings.ToUpper(s.SchemaType))
-		if schemaType == "" {
-			schemaType = storage.SchemaTypeAvro
-		}
-		importReqs[i] = registry.ImportSchemaRequest{
-			ID:         s.ID,
-			Subject:    s.Subject,
-			Version:    s.Version,
-			SchemaType: schemaType,
-			Schema:     s.Schema,
-			References: s.References,
-		}
-	}
+	importReqs := importRequestsFromAPI(req.Schemas)
 
 	// Set schema_type hint from the converted import requests (after defaulting).
 	if hints := auth.GetAuditHints(r.Context()); hints != nil {
@@ -1738,7 +2326,7 @@ func (h *Handler) ImportSchemas(w http.ResponseWriter, r *http.Request) {
 			writeJSON(w, statusCode, resp)
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1749,6 +2337,27 @@ func (h *Handler) ImportSchemas(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, statusCode, resp)
 }
 
+// importRequestsFromAPI converts API import requests to registry types,
+// defaulting the schema type to Avro.
+func importRequestsFromAPI(schemas []types.ImportSchemaRequest) []registry.ImportSchemaRequest {
+	importReqs := make([]registry.ImportSchemaRequest, len(schemas))
+	for i, s := range schemas {
+		schemaType := storage.SchemaType(strings.ToUpper(s.SchemaType))
+		if schemaType == "" {
+			schemaType = storage.SchemaTypeAvro
+		}
+		importReqs[i] = registry.ImportSchemaRequest{
+			ID:         s.ID,
+			Subject:    s.Subject,
+			Version:    s.Version,
+			SchemaType: schemaType,
+			Schema:     s.Schema,
+			References: s.References,
+		}
+	}
+	return importReqs
+}
+
 // importResultToResponse converts a registry.ImportResult to an API response type.
 func importResultToResponse(result *registry.ImportResult) types.ImportSchemasResponse {
 	resp := types.ImportSchemasResponse{
@@ -1892,6 +2501,12 @@ func hashSchemaContent(schema string) string {
 
 // GetRawSchemaByVersion handles GET /subjects/{subject}/versions/{version}/schema
 func (h *Handler) GetRawSchemaByVersion(w http.ResponseWriter, r *http.Request) {
+	mediaType, ok := negotiateRawSchemaType(r)
+	if !ok {
+		writeNotAcceptable(w)
+		return
+	}
+
 	registryCtx, subject := resolveSubjectAndContext(r)
 	if rejectGlobalContext(w, registryCtx) {
 		return
@@ -1927,19 +2542,17 @@ func (h *Handler) GetRawSchemaByVersion(w http.ResponseWriter, r *http.Request)
 			writeError(w, http.StatusNotFound, types.ErrorCodeVersionNotFound, "Version not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
+	h.usage.RecordVersion(registryCtx, schemaRecord.Subject, schemaRecord.Version, schemaRecord.ID)
 
 	result := schemaRecord.Schema
 	if format := r.URL.Query().Get("format"); format != "" {
 		result = h.registry.FormatSchema(r.Context(), registryCtx, schemaRecord, format)
 	}
 
-	// Return raw schema as plain text
-	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(result)) // #nosec G705 -- schema content from storage, not user input
+	writeRawSchema(w, mediaType, result)
 }
 
 // DeleteGlobalConfig handles DELETE /config
@@ -1948,7 +2561,7 @@ func (h *Handler) DeleteGlobalConfig(w http.ResponseWriter, r *http.Request) {
 
 	// Check mode enforcement — Confluent blocks config writes in READONLY mode
 	if mode, modeErr := h.registry.CheckModeForWrite(r.Context(), registryCtx, ""); modeErr != nil {
-		writeInternalError(w, modeErr)
+		writeRegistryError(w, modeErr)
 		return
 	} else if mode != "" {
 		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeOperationNotPermitted,
@@ -1958,7 +2571,7 @@ func (h *Handler) DeleteGlobalConfig(w http.ResponseWriter, r *http.Request) {
 
 	level, err := h.registry.DeleteGlobalConfig(r.Context(), registryCtx)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -1986,7 +2599,7 @@ func (h *Handler) DeleteMode(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Mode not found for subject")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -2010,7 +2623,7 @@ func (h *Handler) DeleteGlobalMode(w http.ResponseWriter, r *http.Request) {
 
 	mode, err := h.registry.DeleteGlobalMode(r.Context(), registryCtx)
 	if err != nil {
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -2037,6 +2650,17 @@ func (h *Handler) GetContexts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Tenant users only see the contexts of their own tenant.
+	if user := auth.GetUser(r.Context()); user != nil && user.Tenant != "" {
+		owned := make([]string, 0, len(contexts))
+		for _, name := range contexts {
+			if owner, err := h.registry.ContextTenant(r.Context(), name); err == nil && owner == user.Tenant {
+				owned = append(owned, name)
+			}
+		}
+		contexts = owned
+	}
+
 	writeJSON(w, http.StatusOK, contexts)
 }
 
@@ -2096,7 +2720,7 @@ func (h *Handler) GetSubjectMetadata(w http.ResponseWriter, r *http.Request) {
 				writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Subject not found")
 				return
 			}
-			writeInternalError(w, err)
+			writeRegistryError(w, err)
 			return
 		}
 
@@ -2150,7 +2774,7 @@ func (h *Handler) GetSubjectMetadata(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, types.ErrorCodeSubjectNotFound, "Subject not found")
 			return
 		}
-		writeInternalError(w, err)
+		writeRegistryError(w, err)
 		return
 	}
 
@@ -2248,6 +2872,25 @@ func hashExporter(exp *storage.ExporterRecord) string {
 	return hashString(string(data))
 }
 
+// hashContext returns a sha256 hash of the context metadata and limits.
+func hashContext(rec *storage.ContextRecord) string {
+	obj := struct {
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		Owner         string `json:"owner"`
+		MaxSubjects   int    `json:"maxSubjects"`
+		MaxSchemaSize int    `json:"maxSchemaSize"`
+	}{
+		Name:          rec.Name,
+		Description:   rec.Description,
+		Owner:         rec.Owner,
+		MaxSubjects:   rec.MaxSubjects,
+		MaxSchemaSize: rec.MaxSchemaSize,
+	}
+	data, _ := json.Marshal(obj)
+	return hashString(string(data))
+}
+
 // hashExporterStatus returns a sha256 hash of the exporter status state.
 func hashExporterStatus(status *storage.ExporterStatusRecord) string {
 	obj := struct {
@@ -2283,17 +2926,38 @@ func hashUser(user *storage.UserRecord) string {
 // Excludes KeyHash and full key material which MUST NOT appear in audit logs.
 func hashAPIKey(key *storage.APIKeyRecord) string {
 	obj := struct {
-		Name      string `json:"name"`
-		Role      string `json:"role"`
-		UserID    int64  `json:"userId"`
-		Enabled   bool   `json:"enabled"`
-		KeyPrefix string `json:"keyPrefix"`
+		Name      string                `json:"name"`
+		Role      string                `json:"role"`
+		UserID    int64                 `json:"userId"`
+		Enabled   bool                  `json:"enabled"`
+		KeyPrefix string                `json:"keyPrefix"`
+		Scopes    []storage.APIKeyScope `json:"scopes,omitempty"`
 	}{
 		Name:      key.Name,
 		Role:      key.Role,
 		UserID:    key.UserID,
 		Enabled:   key.Enabled,
 		KeyPrefix: key.KeyPrefix,
+		Scopes:    key.Scopes,
+	}
+	data, _ := json.Marshal(obj)
+	return hashString(string(data))
+}
+
+// hashGrant returns a sha256 hash of a role grant's binding and scope.
+func hashGrant(grant *storage.GrantRecord) string {
+	obj := struct {
+		Username      string `json:"username"`
+		APIKeyID      int64  `json:"apiKeyId"`
+		Role          string `json:"role"`
+		Context       string `json:"context"`
+		SubjectPrefix string `json:"subjectPrefix"`
+	}{
+		Username:      grant.Username,
+		APIKeyID:      grant.APIKeyID,
+		Role:          grant.Role,
+		Context:       grant.Context,
+		SubjectPrefix: grant.SubjectPrefix,
 	}
 	data, _ := json.Marshal(obj)
 	return hashString(string(data))
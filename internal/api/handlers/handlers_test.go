@@ -9,17 +9,23 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
 	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/lint"
 	"github.com/axonops/axonops-schema-registry/internal/registry"
 	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+	"github.com/axonops/axonops-schema-registry/internal/storage/writegate"
 )
 
 func setupTestHandler(t *testing.T) *Handler {
@@ -235,6 +241,53 @@ func TestGetRawSchemaByID_InvalidID(t *testing.T) {
 	}
 }
 
+func TestGetRawSchemaByID_ContentNegotiation(t *testing.T) {
+	h := setupTestHandler(t)
+	schemaStr := `{"type":"string"}`
+	id := registerSchema(t, h, "test", schemaStr)
+
+	r := chi.NewRouter()
+	r.Get("/schemas/ids/{id}/schema", h.GetRawSchemaByID)
+
+	tests := []struct {
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"", http.StatusOK, "application/vnd.schemaregistry.v1+json"},
+		{"*/*", http.StatusOK, "application/vnd.schemaregistry.v1+json"},
+		{"application/json", http.StatusOK, "application/json"},
+		{"application/vnd.schemaregistry+json", http.StatusOK, "application/vnd.schemaregistry+json"},
+		{"text/plain", http.StatusOK, "text/plain; charset=utf-8"},
+		{"application/octet-stream", http.StatusOK, "application/octet-stream"},
+		{"application/json;q=0.5, text/plain", http.StatusOK, "text/plain; charset=utf-8"},
+		{"text/*, application/json", http.StatusOK, "application/json"},
+		{"text/html, */*;q=0.1", http.StatusOK, "application/vnd.schemaregistry.v1+json"},
+		{"text/html", http.StatusNotAcceptable, "application/vnd.schemaregistry.v1+json"},
+		{"application/json;q=0", http.StatusNotAcceptable, "application/vnd.schemaregistry.v1+json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/schemas/ids/%d/schema", id), nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.contentType, ct)
+			}
+			if tt.status == http.StatusOK && w.Body.String() != schemaStr {
+				t.Errorf("expected the unescaped schema, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
 // --- GetSubjectsBySchemaID ---
 
 func TestGetSubjectsBySchemaID_Found(t *testing.T) {
@@ -289,6 +342,86 @@ func TestGetSubjectsBySchemaID_InvalidID(t *testing.T) {
 	}
 }
 
+// --- GetSchemaFingerprintByID ---
+
+func TestGetSchemaFingerprintByID(t *testing.T) {
+	h := setupTestHandler(t)
+	id := registerSchema(t, h, "test-fp", `"null"`)
+
+	r := chi.NewRouter()
+	r.Get("/schemas/ids/{id}/fingerprint", h.GetSchemaFingerprintByID)
+
+	tests := []struct {
+		query     string
+		status    int
+		algorithm string
+		want      string
+	}{
+		{"", http.StatusOK, "sha256", ""},
+		{"?algorithm=md5", http.StatusOK, "md5", "9b41ef67651c18488a8b08bb67c75699"},
+		{"?algorithm=CRC64-AVRO", http.StatusOK, "crc64-avro", "63dd24e7cc258f8a"},
+		{"?algorithm=sha1", http.StatusUnprocessableEntity, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/schemas/ids/%d/fingerprint%s", id, tt.query), nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%q: expected %d, got %d: %s", tt.query, tt.status, w.Code, w.Body.String())
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var resp types.SchemaFingerprintResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.ID != id || resp.Algorithm != tt.algorithm || resp.Fingerprint == "" || (tt.want != "" && resp.Fingerprint != tt.want) {
+			t.Errorf("%q: unexpected response %+v", tt.query, resp)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/schemas/ids/999/fingerprint", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown ID, got %d", w.Code)
+	}
+}
+
+func TestGetSchemaByID_ResponseFingerprint(t *testing.T) {
+	h := setupTestHandler(t)
+	id := registerSchema(t, h, "test-fp", `"null"`)
+
+	r := chi.NewRouter()
+	r.Get("/schemas/ids/{id}", h.GetSchemaByID)
+	r.Get("/subjects/{subject}/versions/{version}", h.GetVersion)
+
+	get := func(path string) map[string]interface{} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected 200, got %d", path, w.Code)
+		}
+		var resp map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&resp)
+		return resp
+	}
+
+	// Responses match Confluent's unless an algorithm is configured.
+	if _, ok := get(fmt.Sprintf("/schemas/ids/%d", id))["fingerprint"]; ok {
+		t.Error("expected no fingerprint without a configured algorithm")
+	}
+
+	h.fingerprint = "crc64-avro"
+	for _, path := range []string{fmt.Sprintf("/schemas/ids/%d", id), "/subjects/test-fp/versions/1"} {
+		if fp := get(path)["fingerprint"]; fp != "63dd24e7cc258f8a" {
+			t.Errorf("GET %s: expected the crc64-avro fingerprint, got %v", path, fp)
+		}
+	}
+}
+
 // --- GetVersionsBySchemaID ---
 
 func TestGetVersionsBySchemaID_Found(t *testing.T) {
@@ -385,6 +518,44 @@ func TestListSubjects_WithSubjects(t *testing.T) {
 	}
 }
 
+func TestListSubjects_PaginationAndLink(t *testing.T) {
+	h := setupTestHandler(t)
+	h.maxListPageSize = 2
+	for _, subject := range []string{"orders-c", "orders-a", "payments", "orders-b"} {
+		registerSchema(t, h, subject, `{"type":"string"}`)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/subjects", h.ListSubjects)
+
+	tests := []struct {
+		query string
+		want  string
+		link  string
+	}{
+		{"", `["orders-a","orders-b"]`, `</subjects?limit=2&offset=2>; rel="next"`}, // capped by the soft limit
+		{"?limit=-1", `["orders-a","orders-b"]`, `</subjects?limit=2&offset=2>; rel="next"`},
+		{"?offset=2", `["orders-c","payments"]`, ""},
+		{"?subjectPrefix=orders-&limit=1&offset=1", `["orders-b"]`, `</subjects?limit=1&offset=2&subjectPrefix=orders->; rel="next"`},
+		{"?subjectPrefix=orders-&offset=1", `["orders-b","orders-c"]`, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/subjects"+tt.query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", tt.query, w.Code, w.Body.String())
+		}
+		if got := strings.TrimSpace(w.Body.String()); got != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.query, tt.want, got)
+		}
+		if got := w.Header().Get("Link"); got != tt.link {
+			t.Errorf("%q: expected Link %q, got %q", tt.query, tt.link, got)
+		}
+	}
+}
+
 // --- GetVersions ---
 
 func TestGetVersions_Found(t *testing.T) {
@@ -410,6 +581,46 @@ func TestGetVersions_Found(t *testing.T) {
 	}
 }
 
+func TestGetVersions_PaginationAndLatestOnly(t *testing.T) {
+	h := setupTestHandler(t)
+	h.maxVersionsPageSize = 2
+	registerSchema(t, h, "test", `{"type":"record","name":"U","fields":[{"name":"id","type":"long"}]}`)
+	registerSchema(t, h, "test", `{"type":"record","name":"U","fields":[{"name":"id","type":"long"},{"name":"a","type":"string","default":""}]}`)
+	registerSchema(t, h, "test", `{"type":"record","name":"U","fields":[{"name":"id","type":"long"},{"name":"a","type":"string","default":""},{"name":"b","type":"string","default":""}]}`)
+
+	r := chi.NewRouter()
+	r.Get("/subjects/{subject}/versions", h.GetVersions)
+
+	tests := []struct {
+		query string
+		want  string
+		link  string
+	}{
+		{"", "[1,2]", `</subjects/test/versions?limit=2&offset=2>; rel="next"`},          // capped by the soft limit
+		{"?limit=10", "[1,2]", `</subjects/test/versions?limit=2&offset=2>; rel="next"`}, // larger limits are reduced to it
+		{"?offset=2", "[3]", ""}, // remaining page
+		{"?offset=1&limit=1", "[2]", `</subjects/test/versions?limit=1&offset=2>; rel="next"`},
+		{"?limit=0", "[]", ""},
+		{"?latestOnly=true", "[3]", ""},
+		{"?offset=5", "[]", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/subjects/test/versions"+tt.query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", tt.query, w.Code, w.Body.String())
+		}
+		if got := strings.TrimSpace(w.Body.String()); got != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.query, tt.want, got)
+		}
+		if got := w.Header().Get("Link"); got != tt.link {
+			t.Errorf("%q: expected Link %q, got %q", tt.query, tt.link, got)
+		}
+	}
+}
+
 func TestGetVersions_SubjectNotFound(t *testing.T) {
 	h := setupTestHandler(t)
 
@@ -543,6 +754,29 @@ func TestGetRawSchemaByVersion_Found(t *testing.T) {
 	}
 }
 
+func TestGetRawSchemaByVersion_PlainText(t *testing.T) {
+	h := setupTestHandler(t)
+	registerSchema(t, h, "test", `{"type":"string"}`)
+
+	r := chi.NewRouter()
+	r.Get("/subjects/{subject}/versions/{version}/schema", h.GetRawSchemaByVersion)
+
+	req := httptest.NewRequest("GET", "/subjects/test/versions/latest/schema", nil)
+	req.Header.Set("Accept", "text/plain")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("expected text/plain, got %q", ct)
+	}
+	if w.Body.String() != `{"type":"string"}` {
+		t.Errorf("expected the unescaped schema, got %s", w.Body.String())
+	}
+}
+
 func TestGetRawSchemaByVersion_SubjectNotFound(t *testing.T) {
 	h := setupTestHandler(t)
 
@@ -616,6 +850,42 @@ func TestRegisterSchema_Success(t *testing.T) {
 	}
 }
 
+func TestRegisterSchema_LintPolicy(t *testing.T) {
+	h := setupTestHandler(t)
+	h.registry.SetLintPolicies(lint.Policy{lint.RuleFieldSnakeCase: lint.SeverityError, lint.RuleFieldDoc: lint.SeverityWarn}, nil, nil)
+
+	r := chi.NewRouter()
+	r.Post("/subjects/{subject}/versions", h.RegisterSchema)
+	register := func(schemaStr string) *httptest.ResponseRecorder {
+		bodyBytes, _ := json.Marshal(types.RegisterSchemaRequest{Schema: schemaStr})
+		req := httptest.NewRequest("POST", "/subjects/orders-value/versions", bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := register(`{"type":"record","name":"Order","fields":[{"name":"orderId","type":"int","doc":"ID"}]}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	var errResp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&errResp)
+	if errResp.ErrorCode != types.ErrorCodeLintFailed {
+		t.Errorf("expected error code %d, got %d", types.ErrorCodeLintFailed, errResp.ErrorCode)
+	}
+
+	w = register(`{"type":"record","name":"Order","fields":[{"name":"order_id","type":"int"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.RegisterSchemaResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Warnings) != 1 || !strings.HasPrefix(resp.Warnings[0], lint.RuleFieldDoc) {
+		t.Errorf("expected one field_doc warning, got %v", resp.Warnings)
+	}
+}
+
 func TestRegisterSchema_EmptySchema(t *testing.T) {
 	h := setupTestHandler(t)
 
@@ -1316,6 +1586,13 @@ func TestCheckCompatibility_Incompatible(t *testing.T) {
 	if len(resp.Messages) == 0 {
 		t.Error("expected error messages")
 	}
+	if len(resp.Incompatibilities) != len(resp.Messages) {
+		t.Fatalf("expected one incompatibility per message, got %d for %d", len(resp.Incompatibilities), len(resp.Messages))
+	}
+	inc := resp.Incompatibilities[0]
+	if inc.Code != compatibility.CodeReaderFieldMissingDefault || inc.Path != "name" || inc.Direction != "BACKWARD" || inc.Version != 1 {
+		t.Errorf("unexpected incompatibility: %+v", inc)
+	}
 }
 
 func TestCheckCompatibility_SubjectNotFound_Latest(t *testing.T) {
@@ -1405,6 +1682,43 @@ func TestListSchemas_WithSchemas(t *testing.T) {
 	}
 }
 
+func TestListSchemas_Filters(t *testing.T) {
+	h := setupTestHandler(t)
+	registerSchema(t, h, "orders-value", `{"type":"string"}`)
+	registerSchema(t, h, "orders-value", `{"type":"int"}`)
+	registerSchema(t, h, "users-value", `{"type":"long"}`)
+	if err := h.registry.SetConfig(context.Background(), ".", "legacy-orders", "NONE", nil, registry.SetConfigOpts{Alias: "orders-value"}); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/schemas", h.ListSchemas)
+	list := func(query string) []types.SchemaListItem {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/schemas?"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d", query, w.Code)
+		}
+		var schemas []types.SchemaListItem
+		json.NewDecoder(w.Body).Decode(&schemas)
+		return schemas
+	}
+
+	if got := list("subjectPrefix=orders&latestOnly=true"); len(got) != 1 || got[0].Version != 2 {
+		t.Errorf("expected the latest orders-value version, got %+v", got)
+	}
+	if got := list("subjectPrefix=legacy"); len(got) != 0 {
+		t.Errorf("expected no schemas under the alias without aliases=true, got %+v", got)
+	}
+	if got := list("subjectPrefix=legacy&aliases=true&latestOnly=true"); len(got) != 1 || got[0].Subject != "legacy-orders" || got[0].Version != 2 {
+		t.Errorf("expected the latest orders-value version under legacy-orders, got %+v", got)
+	}
+	if got := list("aliases=true&offset=1&limit=2"); len(got) != 2 {
+		t.Errorf("expected a page of 2 schemas, got %+v", got)
+	}
+}
+
 // --- ImportSchemas ---
 
 func TestImportSchemas_Success(t *testing.T) {
@@ -1810,6 +2124,41 @@ func TestSetConfig_BlockedByReadOnlyOverrideMode(t *testing.T) {
 	}
 }
 
+func TestRegisterSchema_BlockedByWriteGate(t *testing.T) {
+	h := setupTestHandler(t)
+	gate := writegate.New(time.Second, nil)
+	lag := 12 * time.Second
+	gate.Register("postgresql_replication_lag", 5*time.Second, func(context.Context) (time.Duration, error) {
+		return lag, nil
+	})
+	gate.Check(context.Background())
+	h.registry.SetWriteGate(gate)
+
+	body, _ := json.Marshal(types.RegisterSchemaRequest{Schema: `{"type":"string"}`})
+	r := chi.NewRouter()
+	r.Post("/subjects/{subject}/versions", h.RegisterSchema)
+	req := httptest.NewRequest("POST", "/subjects/gated-value/versions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeErrorResponse(t, w)
+	if resp.ErrorCode != types.ErrorCodeOperationNotPermitted {
+		t.Errorf("expected error code %d, got %d", types.ErrorCodeOperationNotPermitted, resp.ErrorCode)
+	}
+	if !strings.Contains(resp.Message, "postgresql_replication_lag is 12s, above the 5s threshold") {
+		t.Errorf("expected the lag in the message, got %q", resp.Message)
+	}
+
+	// Writes are accepted again once the lag recovers.
+	lag = time.Second
+	gate.Check(context.Background())
+	registerSchema(t, h, "gated-value", `{"type":"string"}`)
+}
+
 func TestWriteInternalErrorDoesNotLeakDetails(t *testing.T) {
 	w := httptest.NewRecorder()
 	sensitiveErr := fmt.Errorf("connection refused: postgres://user:password@db:5432/registry")
@@ -2080,3 +2429,374 @@ func TestImportStatusCode(t *testing.T) {
 		})
 	}
 }
+
+// --- Reference cycles ---
+
+func TestRegisterSchema_ReferenceCycle_Returns42213(t *testing.T) {
+	store := memory.NewStore()
+	schemaReg := schema.NewRegistry()
+	schemaReg.Register(avro.NewParser())
+	compatChecker := compatibility.NewChecker()
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	h := New(registry.New(store, schemaReg, compatChecker, "NONE"))
+
+	// Seed a stored cycle a:1 -> b:1 -> a:1.
+	for _, rec := range []*storage.SchemaRecord{
+		{Subject: "a", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"A","fields":[]}`, Fingerprint: "fa",
+			References: []storage.Reference{{Name: "B", Subject: "b", Version: 1}}},
+		{Subject: "b", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"B","fields":[]}`, Fingerprint: "fb",
+			References: []storage.Reference{{Name: "A", Subject: "a", Version: 1}}},
+	} {
+		if err := store.CreateSchema(context.Background(), ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+	}
+
+	body := types.RegisterSchemaRequest{
+		Schema:     `{"type":"record","name":"C","fields":[{"name":"a","type":"A"}]}`,
+		References: []storage.Reference{{Name: "A", Subject: "a", Version: 1}},
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	r := chi.NewRouter()
+	r.Post("/subjects/{subject}/versions", h.RegisterSchema)
+
+	req := httptest.NewRequest("POST", "/subjects/c/versions", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeErrorResponse(t, w)
+	if resp.ErrorCode != types.ErrorCodeReferenceCycle {
+		t.Errorf("expected error code %d, got %d", types.ErrorCodeReferenceCycle, resp.ErrorCode)
+	}
+	if !strings.Contains(resp.Message, "a:1 -> b:1 -> a:1") {
+		t.Errorf("expected cycle path in message, got %q", resp.Message)
+	}
+}
+
+// --- Compatible versions ---
+
+func TestGetCompatibleVersions_ReturnsVersions(t *testing.T) {
+	h := setupTestHandler(t)
+	id := registerSchema(t, h, "cw-test", `{"type":"record","name":"CW","fields":[{"name":"a","type":"int"}]}`)
+
+	r := chi.NewRouter()
+	r.Get("/subjects/{subject}/versions/compatible-with", h.GetCompatibleVersions)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/subjects/cw-test/versions/compatible-with?schemaId=%d", id), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.CompatibleVersionsResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.SchemaID != id || resp.CompatibilityLevel != "BACKWARD" || len(resp.Versions) != 1 || resp.Versions[0] != 1 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestGetCompatibleVersions_InvalidSchemaID(t *testing.T) {
+	h := setupTestHandler(t)
+
+	r := chi.NewRouter()
+	r.Get("/subjects/{subject}/versions/compatible-with", h.GetCompatibleVersions)
+
+	req := httptest.NewRequest("GET", "/subjects/cw-test/versions/compatible-with?schemaId=abc", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// --- Subject naming and topics ---
+
+func TestRegisterSchema_SubjectNameStrategyViolation(t *testing.T) {
+	h := setupTestHandler(t)
+	h.registry.SetSubjectNameStrategies("topic_name", nil)
+
+	body, _ := json.Marshal(types.RegisterSchemaRequest{Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`})
+
+	r := chi.NewRouter()
+	r.Post("/subjects/{subject}/versions", h.RegisterSchema)
+
+	req := httptest.NewRequest("POST", "/subjects/orders/versions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeErrorResponse(t, w)
+	if resp.ErrorCode != types.ErrorCodeSubjectNameStrategy {
+		t.Errorf("expected error code %d, got %d", types.ErrorCodeSubjectNameStrategy, resp.ErrorCode)
+	}
+}
+
+func TestListSubjectCatalog(t *testing.T) {
+	h := setupTestHandler(t)
+	registerSchema(t, h, "orders-value", `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`)
+	registerSchema(t, h, "payments-value", `{"type":"record","name":"Payment","fields":[{"name":"id","type":"int"}]}`)
+
+	r := chi.NewRouter()
+	r.Get("/catalog/subjects", h.ListSubjectCatalog)
+
+	req := httptest.NewRequest("GET", "/catalog/subjects?limit=1&include=latestVersion,mode", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.SubjectCatalogResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Total != 2 || resp.Limit != 1 || len(resp.Subjects) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	entry := resp.Subjects[0]
+	if entry.Subject != "orders-value" || entry.LatestVersion == nil || entry.LatestVersion.Version != 1 ||
+		entry.Mode != "READWRITE" || entry.CompatibilityLevel != "" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	req = httptest.NewRequest("GET", "/catalog/subjects?include=owner", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown include field, got %d", w.Code)
+	}
+}
+
+func TestGetTopicSubjects(t *testing.T) {
+	h := setupTestHandler(t)
+	registerSchema(t, h, "orders-key", `{"type":"record","name":"OrderKey","fields":[{"name":"id","type":"int"}]}`)
+	registerSchema(t, h, "orders-value", `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`)
+	registerSchema(t, h, "orders-Refund", `{"type":"record","name":"Refund","fields":[{"name":"id","type":"int"}]}`)
+
+	r := chi.NewRouter()
+	r.Get("/topics/{topic}/subjects", h.GetTopicSubjects)
+
+	req := httptest.NewRequest("GET", "/topics/orders/subjects", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.TopicSubjectsResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Topic != "orders" || resp.Key != "orders-key" || resp.Value != "orders-value" ||
+		len(resp.Records) != 1 || resp.Records[0] != "orders-Refund" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestSubjectMeta(t *testing.T) {
+	h := setupTestHandler(t)
+	registerSchema(t, h, "orders-value", `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`)
+	registerSchema(t, h, "payments-value", `{"type":"record","name":"Payment","fields":[{"name":"id","type":"int"}]}`)
+
+	r := chi.NewRouter()
+	r.Get("/subjects/{subject}/meta", h.GetSubjectMeta)
+	r.Put("/subjects/{subject}/meta", h.SetSubjectMeta)
+	r.Delete("/subjects/{subject}/meta", h.DeleteSubjectMeta)
+	r.Get("/catalog/subjects", h.ListSubjectCatalog)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("PUT", "/subjects/orders-value/meta", `{"owner":" payments ","description":"Web shop orders","contact":"#payments","links":[{"name":"runbook","url":"https://wiki.example.com/orders"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var meta types.SubjectMetaResponse
+	json.NewDecoder(w.Body).Decode(&meta)
+	if meta.Subject != "orders-value" || meta.Owner != "payments" || len(meta.Links) != 1 || meta.UpdatedAt == "" {
+		t.Errorf("unexpected meta: %+v", meta)
+	}
+
+	if w := do("PUT", "/subjects/orders-value/meta", `{"links":[{"name":"docs","url":"wiki/orders"}]}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for a relative link, got %d", w.Code)
+	}
+	if w := do("PUT", "/subjects/missing-value/meta", `{"owner":"payments"}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown subject, got %d", w.Code)
+	}
+
+	w = do("GET", "/catalog/subjects?include=meta", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var catalog types.SubjectCatalogResponse
+	json.NewDecoder(w.Body).Decode(&catalog)
+	if len(catalog.Subjects) != 2 || catalog.Subjects[0].Meta == nil || catalog.Subjects[0].Meta.Owner != "payments" ||
+		catalog.Subjects[1].Meta != nil || catalog.Subjects[0].LatestVersion != nil {
+		t.Errorf("unexpected catalog: %+v", catalog.Subjects)
+	}
+
+	if w := do("DELETE", "/subjects/orders-value/meta", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	w = do("GET", "/subjects/orders-value/meta", "")
+	meta = types.SubjectMetaResponse{}
+	json.NewDecoder(w.Body).Decode(&meta)
+	if w.Code != http.StatusOK || meta.Owner != "" || meta.Links == nil {
+		t.Errorf("expected empty meta after delete, got %d %+v", w.Code, meta)
+	}
+}
+
+func TestPermanentDeleteConfirmation(t *testing.T) {
+	h := setupTestHandler(t)
+	h.registry.SetDeleteConfirmation(0)
+	h.SetAuthorizer(auth.NewAuthorizer(config.RBACConfig{Enabled: true, SuperAdmins: []string{"root"}}))
+	registerSchema(t, h, "orders-value", `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`)
+	registerSchema(t, h, "payments-value", `{"type":"record","name":"Payment","fields":[{"name":"id","type":"int"}]}`)
+
+	r := chi.NewRouter()
+	r.Delete("/subjects/{subject}", h.DeleteSubject)
+	r.Delete("/subjects/{subject}/versions/{version}", h.DeleteVersion)
+	r.Delete("/contexts/{context}", func(w http.ResponseWriter, req *http.Request) {
+		ctx := registrycontext.WithRegistryContext(req.Context(), chi.URLParam(req, "context"))
+		h.DeleteContext(w, req.WithContext(ctx))
+	})
+	admin := &auth.User{Username: "alice", Role: "admin"}
+	root := &auth.User{Username: "root", Role: "admin"}
+	do := func(user *auth.User, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, withUser(httptest.NewRequest("DELETE", path, nil), user))
+		return w
+	}
+	issue := func(path string) string {
+		t.Helper()
+		w := do(admin, path)
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("expected 202 from %s, got %d: %s", path, w.Code, w.Body.String())
+		}
+		var resp types.DeleteConfirmationResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.ConfirmationToken == "" || resp.ExpiresAt == "" {
+			t.Fatalf("unexpected confirmation: %+v", resp)
+		}
+		return resp.ConfirmationToken
+	}
+
+	// Soft deletes are not two-phase, and no token is issued for a subject
+	// that is not soft-deleted.
+	if w := do(admin, "/subjects/payments-value?permanent=true"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 before soft delete, got %d", w.Code)
+	}
+	if w := do(admin, "/subjects/orders-value"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a soft delete, got %d: %s", w.Code, w.Body.String())
+	}
+
+	token := issue("/subjects/orders-value?permanent=true")
+	if w := do(admin, "/subjects/orders-value?permanent=true&confirmation_token="+token); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 when an admin confirms, got %d", w.Code)
+	}
+	if w := do(root, "/subjects/orders-value?permanent=true&confirmation_token="+token); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 when a super admin confirms, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := h.registry.GetSchemasBySubject(context.Background(), ".", "orders-value", true); err == nil {
+		t.Error("expected the subject to be permanently deleted")
+	}
+	if w := do(root, "/subjects/orders-value?permanent=true&confirmation_token="+token); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 when the token is reused, got %d", w.Code)
+	}
+
+	// A token only confirms the delete it was issued for.
+	if w := do(admin, "/subjects/payments-value/versions/1"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a soft delete, got %d: %s", w.Code, w.Body.String())
+	}
+	token = issue("/subjects/payments-value/versions/latest?permanent=true")
+	if w := do(root, "/subjects/payments-value?permanent=true&confirmation_token="+token); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for a token issued for a version, got %d", w.Code)
+	}
+	token = issue("/subjects/payments-value/versions/latest?permanent=true")
+	if w := do(root, "/subjects/payments-value/versions/1?permanent=true&confirmation_token="+token); w.Code != http.StatusOK {
+		t.Errorf("expected 200 when a super admin confirms, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Deleting a context with cascade permanently deletes its subjects.
+	if err := h.registry.CreateContext(context.Background(), &storage.ContextRecord{Name: ".team"}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	token = issue("/contexts/.team?cascade=true")
+	if _, err := h.registry.GetContext(context.Background(), ".team"); err != nil {
+		t.Fatalf("expected the context to survive until confirmed: %v", err)
+	}
+	if w := do(admin, "/contexts/.team?cascade=true&confirmation_token="+token); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 when an admin confirms, got %d", w.Code)
+	}
+	if w := do(root, "/contexts/.team?cascade=true&confirmation_token="+token); w.Code != http.StatusOK {
+		t.Errorf("expected 200 when a super admin confirms, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTagsAndFindSchemas(t *testing.T) {
+	h := setupTestHandler(t)
+	registerSchema(t, h, "orders-value", `{"type":"record","name":"Order","fields":[{"name":"email","type":"string"}]}`)
+	registerSchema(t, h, "payments-value", `{"type":"record","name":"Payment","fields":[{"name":"id","type":"int"}]}`)
+
+	r := chi.NewRouter()
+	r.Put("/subjects/{subject}/tags", h.SetTags)
+	r.Get("/subjects/{subject}/versions/{version}/tags", h.GetTags)
+	r.Get("/search/schemas", h.FindSchemas)
+
+	req := httptest.NewRequest("PUT", "/subjects/orders-value/tags", strings.NewReader(`{"tags":["pii"," pii","gdpr"],"labels":{"owner":"sales"}}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var tags types.TagsResponse
+	json.NewDecoder(w.Body).Decode(&tags)
+	if len(tags.Tags) != 2 || tags.Tags[0] != "gdpr" || tags.Labels["owner"] != "sales" {
+		t.Errorf("unexpected tags: %+v", tags)
+	}
+
+	req = httptest.NewRequest("PUT", "/subjects/missing-value/tags", strings.NewReader(`{"tags":["pii"]}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown subject, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/subjects/orders-value/versions/latest/tags", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	tags = types.TagsResponse{}
+	json.NewDecoder(w.Body).Decode(&tags)
+	if tags.Version != 1 || len(tags.Tags) != 0 {
+		t.Errorf("expected empty version tags, got %+v", tags)
+	}
+
+	req = httptest.NewRequest("GET", "/search/schemas?tag=PII&type=avro&field=email", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.SchemaSearchResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Count != 1 || resp.Results[0].Subject != "orders-value" || len(resp.Results[0].MatchedFields) != 1 {
+		t.Errorf("unexpected search response: %+v", resp)
+	}
+
+	req = httptest.NewRequest("GET", "/search/schemas?type=XML", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for an unknown schema type, got %d", w.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SchemaSourceHeader names the source registry whose schema IDs a client
+// uses, for clients that cannot add the ?source= query parameter.
+const SchemaSourceHeader = "X-Registry-Source"
+
+// SetIDAliases handles POST /import/id-aliases. It maps schema IDs of a
+// source registry to the IDs of the schemas imported from it, so that
+// messages serialized with the source IDs can still be read after IDs
+// collided during migration.
+func (h *Handler) SetIDAliases(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	var req types.IDAliasesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidIDAlias, "Invalid request body")
+		return
+	}
+	aliases := make([]*storage.SchemaIDAliasRecord, 0, len(req.Aliases))
+	for _, a := range req.Aliases {
+		source := a.Source
+		if source == "" {
+			source = req.Source
+		}
+		aliases = append(aliases, &storage.SchemaIDAliasRecord{Source: source, SourceID: a.SourceID, SchemaID: a.ID})
+	}
+	if err := h.registry.SetSchemaIDAliases(r.Context(), registryCtx, aliases); err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, idAliasesToResponse(aliases))
+}
+
+// ListIDAliases handles GET /import/id-aliases. ?source= filters by source
+// registry.
+func (h *Handler) ListIDAliases(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	aliases, err := h.registry.ListSchemaIDAliases(r.Context(), registryCtx, r.URL.Query().Get("source"))
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, idAliasesToResponse(aliases))
+}
+
+// DeleteIDAlias handles DELETE /import/id-aliases/{source}/{sourceId}
+func (h *Handler) DeleteIDAlias(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	sourceID, err := strconv.ParseInt(chi.URLParam(r, "sourceId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidIDAlias, "Invalid source schema ID")
+		return
+	}
+	if err := h.registry.DeleteSchemaIDAlias(r.Context(), registryCtx, chi.URLParam(r, "source"), sourceID); err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// schemaIDParam returns the schema ID in the {id} URL parameter. When the
+// request names a source registry with ?source= or the X-Registry-Source
+// header, the ID is one of that registry's and is resolved through its
+// alias. It writes the error response and returns false when the ID is
+// invalid or has no alias.
+func (h *Handler) schemaIDParam(w http.ResponseWriter, r *http.Request, registryCtx string) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid schema ID")
+		return 0, false
+	}
+	source := r.URL.Query().Get("source")
+	if source == "" {
+		source = r.Header.Get(SchemaSourceHeader)
+	}
+	resolved, err := h.registry.ResolveSchemaID(r.Context(), registryCtx, source, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrIDAliasNotFound) {
+			writeError(w, http.StatusNotFound, types.ErrorCodeSchemaNotFound, "Schema not found")
+			return 0, false
+		}
+		writeRegistryError(w, err)
+		return 0, false
+	}
+	return resolved, true
+}
+
+func idAliasesToResponse(aliases []*storage.SchemaIDAliasRecord) types.IDAliasesResponse {
+	resp := types.IDAliasesResponse{Aliases: make([]types.IDAlias, 0, len(aliases))}
+	for _, a := range aliases {
+		resp.Aliases = append(resp.Aliases, types.IDAlias{Source: a.Source, SourceID: a.SourceID, ID: a.SchemaID})
+	}
+	return resp
+}
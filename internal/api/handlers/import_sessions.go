@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// OpenImportSession handles POST /import/sessions. The session's subjects
+// are put into IMPORT mode until it is committed or aborted.
+func (h *Handler) OpenImportSession(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	var req types.ImportSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidImportSession, "Invalid request body")
+		return
+	}
+	createdBy := ""
+	if user := auth.GetUser(r.Context()); user != nil {
+		createdBy = user.Username
+	}
+	session, err := h.registry.OpenImportSession(r.Context(), registryCtx, req.Subjects, createdBy)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	setImportSessionHints(r, registryCtx, session.ID)
+	writeJSON(w, http.StatusOK, importSessionToResponse(session))
+}
+
+// ListImportSessions handles GET /import/sessions. ?state= filters by state.
+func (h *Handler) ListImportSessions(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	state := strings.ToUpper(r.URL.Query().Get("state"))
+	sessions, err := h.registry.ListImportSessions(r.Context(), registryCtx, state)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	resp := types.ImportSessionsListResponse{Sessions: make([]types.ImportSessionResponse, 0, len(sessions))}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, importSessionToResponse(session))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetImportSession handles GET /import/sessions/{id}
+func (h *Handler) GetImportSession(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	session, err := h.registry.GetImportSession(r.Context(), registryCtx, chi.URLParam(r, "id"))
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, importSessionToResponse(session))
+}
+
+// StageImportSchemas handles POST /import/sessions/{id}/schemas. The body has
+// the same shape as POST /import/schemas; the schemas are only stored in the
+// session until it is committed.
+func (h *Handler) StageImportSchemas(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	setImportSessionHints(r, registryCtx, id)
+
+	var req types.ImportSchemasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	if len(req.Schemas) == 0 {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "No schemas provided")
+		return
+	}
+	session, err := h.registry.StageImportSchemas(r.Context(), registryCtx, id, importRequestsFromAPI(req.Schemas))
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, importSessionToResponse(session))
+}
+
+// CommitImportSession handles PUT /import/sessions/{id}/commit. The staged
+// schemas are imported all or nothing; if any is invalid, nothing is
+// imported, the response lists the failures with HTTP 422 and the session
+// stays open.
+func (h *Handler) CommitImportSession(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	setImportSessionHints(r, registryCtx, id)
+
+	// The staged schemas are read first so the per-schema audit events can
+	// record their content; committing clears them from the session.
+	var staged []registry.ImportSchemaRequest
+	if session, err := h.registry.GetImportSession(r.Context(), registryCtx, id); err == nil {
+		staged, _ = registry.StagedImportSchemas(session)
+	}
+
+	start := time.Now()
+	session, result, err := h.registry.CommitImportSession(r.Context(), registryCtx, id)
+	if err != nil {
+		if result != nil {
+			w.Header().Set("X-Warning", err.Error())
+			h.emitPerSchemaAuditEvents(r, registryCtx, staged, result, start)
+			writeJSON(w, importStatusCode(result), importResultToResponse(result))
+			return
+		}
+		writeRegistryError(w, err)
+		return
+	}
+
+	h.emitPerSchemaAuditEvents(r, registryCtx, staged, result, start)
+	writeJSON(w, importStatusCode(result), types.ImportSessionCommitResponse{
+		Session:               importSessionToResponse(session),
+		ImportSchemasResponse: importResultToResponse(result),
+	})
+}
+
+// AbortImportSession handles PUT /import/sessions/{id}/abort. The staged
+// schemas are discarded and the subjects' modes restored.
+func (h *Handler) AbortImportSession(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	setImportSessionHints(r, registryCtx, id)
+
+	session, err := h.registry.AbortImportSession(r.Context(), registryCtx, id)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, importSessionToResponse(session))
+}
+
+func setImportSessionHints(r *http.Request, registryCtx, id string) {
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "import_session"
+		hints.TargetID = id
+		hints.Context = registryCtx
+	}
+}
+
+func importSessionToResponse(s *storage.ImportSessionRecord) types.ImportSessionResponse {
+	return types.ImportSessionResponse{
+		ID:        s.ID,
+		Context:   s.Context,
+		Subjects:  s.Subjects,
+		State:     s.State,
+		Staged:    s.Staged,
+		CreatedBy: s.CreatedBy,
+		CreatedAt: s.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: s.UpdatedAt.Format(time.RFC3339),
+	}
+}
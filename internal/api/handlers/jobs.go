@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SetJobManager sets the async job manager behind the /jobs endpoints and
+// used by operations that run in the background.
+func (h *Handler) SetJobManager(m *jobs.Manager) {
+	h.jobs = m
+}
+
+// ListJobs handles GET /jobs
+func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	resp := types.JobsListResponse{Jobs: []types.JobResponse{}}
+	if h.jobs == nil {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	state := strings.ToUpper(r.URL.Query().Get("state"))
+	list, err := h.jobs.List(r.Context(), r.URL.Query().Get("type"), state)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	for _, job := range list {
+		resp.Jobs = append(resp.Jobs, jobToResponse(job))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetJob handles GET /jobs/{id}
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.lookupJob(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, jobToResponse(job))
+}
+
+// GetJobResult handles GET /jobs/{id}/result. The result is the JSON
+// document produced by the job, available once the job has succeeded.
+func (h *Handler) GetJobResult(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.lookupJob(w, r)
+	if !ok {
+		return
+	}
+	if job.State != storage.JobStateSucceeded {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeJobResultAbsent,
+			"Job result is not available: job is "+job.State)
+		return
+	}
+	result := job.Result
+	if result == "" {
+		result = "null"
+	}
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(result))
+}
+
+// CancelJob handles POST /jobs/{id}/cancel
+func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "job"
+		hints.TargetID = id
+	}
+	if h.jobs == nil {
+		writeError(w, http.StatusNotFound, types.ErrorCodeJobNotFound, "Job not found")
+		return
+	}
+
+	job, err := h.jobs.Cancel(r.Context(), id)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, jobToResponse(job))
+}
+
+// submitJob submits a job for the request's registry context and writes the
+// 202 response pointing at it.
+func (h *Handler) submitJob(w http.ResponseWriter, r *http.Request, jobType string, params any) {
+	if h.jobs == nil {
+		writeError(w, http.StatusServiceUnavailable, types.ErrorCodeJobQueueFull, "Async jobs are not enabled")
+		return
+	}
+	createdBy := ""
+	if user := auth.GetUser(r.Context()); user != nil {
+		createdBy = user.Username
+	}
+	job, err := h.jobs.Submit(r.Context(), jobType, getRegistryContext(r), params, createdBy)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "job"
+		hints.TargetID = job.ID
+	}
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, jobToResponse(job))
+}
+
+// lookupJob loads the job named in the URL, writing the error response if
+// it cannot be found.
+func (h *Handler) lookupJob(w http.ResponseWriter, r *http.Request) (*storage.JobRecord, bool) {
+	if h.jobs == nil {
+		writeError(w, http.StatusNotFound, types.ErrorCodeJobNotFound, "Job not found")
+		return nil, false
+	}
+	job, err := h.jobs.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeRegistryError(w, err)
+		return nil, false
+	}
+	return job, true
+}
+
+func jobToResponse(j *storage.JobRecord) types.JobResponse {
+	resp := types.JobResponse{
+		ID:              j.ID,
+		Type:            j.Type,
+		State:           j.State,
+		Context:         j.Context,
+		Done:            j.Done,
+		Total:           j.Total,
+		Message:         j.Message,
+		Error:           j.Error,
+		CancelRequested: j.CancelRequested,
+		CreatedBy:       j.CreatedBy,
+		CreatedAt:       j.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       j.UpdatedAt.Format(time.RFC3339),
+	}
+	if !j.StartedAt.IsZero() {
+		resp.StartedAt = j.StartedAt.Format(time.RFC3339)
+	}
+	if !j.FinishedAt.IsZero() {
+		resp.FinishedAt = j.FinishedAt.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+)
+
+// rawSchemaMediaTypes are the media types a raw schema can be returned as,
+// in order of preference. The body is the schema text in every case; only
+// the Content-Type differs.
+var rawSchemaMediaTypes = []string{
+	"application/vnd.schemaregistry.v1+json",
+	"application/vnd.schemaregistry+json",
+	"application/json",
+	"text/plain",
+	"application/octet-stream",
+}
+
+// negotiateRawSchemaType picks the media type to return a raw schema as from
+// the Accept header, as Confluent does. The type with the highest quality
+// wins, ties going to the earlier of rawSchemaMediaTypes; wildcards match the
+// first type they cover. Without an Accept header the first type is used. It
+// returns false when none of the types is acceptable.
+func negotiateRawSchemaType(r *http.Request) (string, bool) {
+	accept := r.Header.Values("Accept")
+	if len(accept) == 0 {
+		return rawSchemaMediaTypes[0], true
+	}
+
+	best, bestQ := "", 0.0
+	for _, header := range accept {
+		for _, part := range strings.Split(header, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			mediaType, params, err := mime.ParseMediaType(part)
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			if q <= 0 {
+				continue
+			}
+			for _, candidate := range rawSchemaMediaTypes {
+				if !mediaTypeMatches(mediaType, candidate) {
+					continue
+				}
+				if q > bestQ || (q == bestQ && preferredRawSchemaType(candidate, best)) {
+					best, bestQ = candidate, q
+				}
+				break
+			}
+		}
+	}
+	return best, best != ""
+}
+
+// mediaTypeMatches reports whether an Accept media range covers mediaType.
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(mediaRange, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+// preferredRawSchemaType reports whether a comes before b in
+// rawSchemaMediaTypes. Any type comes before "".
+func preferredRawSchemaType(a, b string) bool {
+	if b == "" {
+		return true
+	}
+	for _, t := range rawSchemaMediaTypes {
+		switch t {
+		case a:
+			return true
+		case b:
+			return false
+		}
+	}
+	return false
+}
+
+// writeRawSchema writes a schema as the response body, unescaped, with the
+// Content-Type negotiated by negotiateRawSchemaType.
+func writeRawSchema(w http.ResponseWriter, mediaType, schema string) {
+	if mediaType == "text/plain" {
+		mediaType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(schema)) // #nosec G705 -- schema content from storage, not user input
+}
+
+// writeNotAcceptable responds 406 when no raw schema media type is
+// acceptable to the client.
+func writeNotAcceptable(w http.ResponseWriter) {
+	writeError(w, http.StatusNotAcceptable, types.ErrorCodeNotAcceptable,
+		"HTTP 406 Not Acceptable. Supported types are "+strings.Join(rawSchemaMediaTypes, ", "))
+}
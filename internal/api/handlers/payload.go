@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// ValidatePayload handles POST /subjects/{subject}/versions/{version}/validate-payload.
+// The body is the JSON document to validate against the version's schema,
+// which must be a JSON Schema. A document that fails validation is not an
+// error: the response lists every problem found, with 200 OK.
+func (h *Handler) ValidatePayload(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+	versionStr := chi.URLParam(r, "version")
+	version, err := registry.ParseVersion(versionStr)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion,
+			fmt.Sprintf("The specified version '%s' is not a valid version id. Allowed values are between [1, 2^31-1] and the string \"latest\"", versionStr))
+		return
+	}
+
+	// Numbers are kept as written so that large integers and multipleOf are
+	// checked exactly.
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidRequest, "Invalid request body: the payload must be a JSON document")
+		return
+	}
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidRequest, "Invalid request body: the payload must be a single JSON document")
+		return
+	}
+
+	record, problems, err := h.registry.ValidatePayload(r.Context(), registryCtx, subject, version, doc)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	resp := types.ValidatePayloadResponse{
+		Subject: record.Subject,
+		Version: record.Version,
+		ID:      record.ID,
+		Valid:   len(problems) == 0,
+		Errors:  make([]types.PayloadValidationError, 0, len(problems)),
+	}
+	for _, p := range problems {
+		resp.Errors = append(resp.Errors, types.PayloadValidationError{
+			InstanceLocation: p.InstanceLocation,
+			KeywordLocation:  p.KeywordLocation,
+			Message:          p.Message,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
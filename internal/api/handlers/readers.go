@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func readerResponse(rec *storage.ReaderAssertionRecord) types.ReaderResponse {
+	return types.ReaderResponse{
+		Subject:       rec.Subject,
+		Consumer:      rec.Consumer,
+		ReaderSubject: rec.ReaderSubject,
+		ReaderVersion: rec.ReaderVersion,
+		UpdatedAt:     rec.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// setReaderHints records the subject of a reader assertion as the audit target.
+func setReaderHints(r *http.Request, registryCtx, subject string) {
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "subject"
+		hints.TargetID = subject
+		hints.Context = registryCtx
+	}
+}
+
+// ListReaders handles GET /readers and GET /subjects/{subject}/readers.
+// ?consumer= limits the list to one consumer's reader assertions.
+func (h *Handler) ListReaders(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	if subject != "" {
+		subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+	}
+	records, err := h.registry.ListReaderAssertions(r.Context(), registryCtx, subject, r.URL.Query().Get("consumer"))
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	resp := types.ReadersResponse{Readers: make([]types.ReaderResponse, 0, len(records))}
+	for _, rec := range records {
+		resp.Readers = append(resp.Readers, readerResponse(rec))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// SetReader handles PUT /subjects/{subject}/readers/{consumer}. It records
+// that the consumer reads the subject with a version of reader_subject, the
+// subject itself by default, replacing the consumer's previous assertion.
+// Proposed schemas for the subject can then be checked against the reader
+// with POST /compatibility/subjects/{subject}/readers.
+func (h *Handler) SetReader(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+	var req types.SetReaderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidRequest, "Invalid request body")
+		return
+	}
+	rec, err := h.registry.SetReaderAssertion(r.Context(), registryCtx, subject, chi.URLParam(r, "consumer"), req.ReaderSubject, req.ReaderVersion)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	setReaderHints(r, registryCtx, subject)
+	writeJSON(w, http.StatusOK, readerResponse(rec))
+}
+
+// DeleteReader handles DELETE /subjects/{subject}/readers/{consumer}.
+func (h *Handler) DeleteReader(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+	if err := h.registry.DeleteReaderAssertion(r.Context(), registryCtx, subject, chi.URLParam(r, "consumer")); err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	setReaderHints(r, registryCtx, subject)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CheckReaders handles POST /compatibility/subjects/{subject}/readers. It
+// checks a proposed schema against the subject's versions, as registering
+// it would, and against the reader schema of every consumer of the subject.
+// The top-level messages describe the check against the subject's versions;
+// each reader's result has its own.
+func (h *Handler) CheckReaders(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	subject = h.registry.ResolveAlias(r.Context(), registryCtx, subject)
+
+	var req types.CompatibilityCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	if req.Schema == "" {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema, "Empty schema")
+		return
+	}
+	schemaType, ok := storage.ParseSchemaType(req.SchemaType)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema,
+			fmt.Sprintf("Invalid schema type: %s", req.SchemaType))
+		return
+	}
+
+	normalizeSchema := r.URL.Query().Get("normalize") == "true"
+	result, err := h.registry.CheckReaders(r.Context(), registryCtx, subject, req.Schema, schemaType, req.References, normalizeSchema)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+
+	if hints := auth.GetAuditHints(r.Context()); hints != nil {
+		hints.TargetType = "subject"
+		hints.TargetID = chi.URLParam(r, "subject")
+		hints.Context = registryCtx
+	}
+
+	resp := types.ReadersCompatibilityResponse{
+		IsCompatible:      result.IsCompatible,
+		Messages:          result.Subject.Messages,
+		Incompatibilities: result.Subject.Incompatibilities,
+		Readers:           make([]types.ReaderCompatibility, 0, len(result.Readers)),
+	}
+	for _, check := range result.Readers {
+		resp.Readers = append(resp.Readers, types.ReaderCompatibility{
+			Consumer:          check.Reader.Consumer,
+			ReaderSubject:     check.Reader.ReaderSubject,
+			ReaderVersion:     check.Reader.ReaderVersion,
+			IsCompatible:      check.Result.IsCompatible,
+			Messages:          check.Result.Messages,
+			Incompatibilities: check.Result.Incompatibilities,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
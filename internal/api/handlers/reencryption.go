@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/reencryption"
+)
+
+// ReencryptSchemas handles POST /encryption/reencrypt. It starts a background
+// job that rewrites every schema of the context, so that each is sealed with
+// the current encryption-at-rest key.
+func (h *Handler) ReencryptSchemas(w http.ResponseWriter, r *http.Request) {
+	h.submitJob(w, r, reencryption.JobType, nil)
+}
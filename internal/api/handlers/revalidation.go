@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/revalidation"
+)
+
+// RevalidateCompatibility handles POST /compatibility/revalidate. It starts
+// a background job that re-checks adjacent versions of the context's
+// subjects with the current compatibility rules and reports, without
+// rejecting anything, the pairs that would now fail.
+func (h *Handler) RevalidateCompatibility(w http.ResponseWriter, r *http.Request) {
+	var params revalidation.Params
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "Invalid request body")
+		return
+	}
+	if params.PairsPerSecond < 0 {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSchema, "pairs_per_second must not be negative")
+		return
+	}
+	h.submitJob(w, r, revalidation.JobType, params)
+}
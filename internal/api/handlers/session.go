@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SessionHandler provides HTTP handlers for login sessions.
+type SessionHandler struct {
+	authService *auth.Service
+}
+
+// NewSessionHandler creates a new SessionHandler.
+func NewSessionHandler(authService *auth.Service) *SessionHandler {
+	return &SessionHandler{
+		authService: authService,
+	}
+}
+
+// Login handles POST /auth/login. The caller authenticates with a password
+// or an API key and receives a short-lived access token and a refresh token.
+func (h *SessionHandler) Login(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		writeAccountError(w, http.StatusUnauthorized, types.ErrorCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	session, tokens, err := h.authService.StartSession(r.Context(), user)
+	if err != nil {
+		if errors.Is(err, auth.ErrSessionLogin) {
+			writeAccountError(w, http.StatusForbidden, types.ErrorCodeForbidden, err.Error())
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	setSessionAuditHints(r, session)
+	writeAccountJSON(w, http.StatusOK, loginResponse(tokens))
+}
+
+// Refresh handles POST /auth/refresh. The refresh token is rotated: the one
+// in the request can no longer be used.
+func (h *SessionHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req types.RefreshSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidRequest, "refresh_token is required")
+		return
+	}
+
+	session, tokens, err := h.authService.RefreshSession(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidSession) {
+			writeAccountError(w, http.StatusUnauthorized, types.ErrorCodeUnauthorized, "Invalid or expired refresh token")
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	setSessionAuditHints(r, session)
+	writeAccountJSON(w, http.StatusOK, loginResponse(tokens))
+}
+
+// Revoke handles POST /auth/revoke. It ends the session, so its access
+// tokens are rejected from then on.
+func (h *SessionHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	var req types.RefreshSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeAccountError(w, http.StatusBadRequest, types.ErrorCodeInvalidRequest, "refresh_token is required")
+		return
+	}
+
+	session, err := h.authService.RevokeSession(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidSession) {
+			writeAccountError(w, http.StatusUnauthorized, types.ErrorCodeUnauthorized, "Invalid or expired refresh token")
+			return
+		}
+		slog.Error("internal server error", "error", err)
+		writeAccountError(w, http.StatusInternalServerError, types.ErrorCodeInternalServerError, "Internal server error")
+		return
+	}
+
+	setSessionAuditHints(r, session)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setSessionAuditHints records the session and its principal in the audit
+// event. Refresh and revoke are unauthenticated, so the actor comes from
+// the session.
+func setSessionAuditHints(r *http.Request, session *storage.SessionRecord) {
+	hints := auth.GetAuditHints(r.Context())
+	if hints == nil {
+		return
+	}
+	hints.TargetType = "session"
+	hints.TargetID = session.ID
+	if auth.GetUser(r.Context()) == nil {
+		hints.ActorID = session.Username
+		hints.ActorType = "user"
+		hints.Role = session.Role
+		hints.AuthMethod = auth.AuthMethodSession
+	}
+}
+
+func loginResponse(tokens *auth.SessionTokens) types.LoginResponse {
+	now := time.Now()
+	return types.LoginResponse{
+		AccessToken:      tokens.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(tokens.AccessExpiresAt.Sub(now).Round(time.Second) / time.Second),
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresIn: int64(tokens.RefreshExpiresAt.Sub(now).Round(time.Second) / time.Second),
+	}
+}
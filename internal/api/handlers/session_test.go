@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func setupTestSessionHandler(t *testing.T) (*chi.Mux, *auth.Service) {
+	t.Helper()
+	signer, err := auth.NewSessionSigner(config.SessionConfig{Enabled: true, Secret: "0123456789abcdef0123456789abcdef"})
+	if err != nil {
+		t.Fatalf("NewSessionSigner: %v", err)
+	}
+	svc := auth.NewServiceWithConfig(memory.NewStore(), auth.ServiceConfig{Sessions: signer})
+	t.Cleanup(func() { svc.Close() })
+
+	h := NewSessionHandler(svc)
+	r := chi.NewRouter()
+	r.Post("/auth/login", h.Login)
+	r.Post("/auth/refresh", h.Refresh)
+	r.Post("/auth/revoke", h.Revoke)
+	return r, svc
+}
+
+func TestSessionHandler_LoginRefreshRevoke(t *testing.T) {
+	r, svc := setupTestSessionHandler(t)
+	user, err := svc.CreateUser(context.Background(), auth.CreateUserRequest{
+		Username: "alice",
+		Password: "pass123",
+		Role:     "developer",
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/auth/login", nil)
+	req = withUser(req, &auth.User{UserID: user.ID, Username: "alice", Role: "developer", Method: "basic"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var login types.LoginResponse
+	json.NewDecoder(w.Body).Decode(&login)
+	if login.AccessToken == "" || login.RefreshToken == "" || login.TokenType != "Bearer" || login.ExpiresIn <= 0 {
+		t.Fatalf("unexpected login response: %+v", login)
+	}
+
+	body := `{"refresh_token":"` + login.RefreshToken + `"}`
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/auth/refresh", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var refreshed types.LoginResponse
+	json.NewDecoder(w.Body).Decode(&refreshed)
+	if refreshed.RefreshToken == login.RefreshToken {
+		t.Error("expected the refresh token to rotate")
+	}
+
+	// The rotated-out refresh token can no longer be used.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/auth/revoke", strings.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body = `{"refresh_token":"` + refreshed.RefreshToken + `"}`
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/auth/revoke", strings.NewReader(body)))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSessionHandler_LoginWithToken(t *testing.T) {
+	r, _ := setupTestSessionHandler(t)
+
+	req := httptest.NewRequest("POST", "/auth/login", nil)
+	req = withUser(req, &auth.User{Username: "bob", Role: "readonly", Method: "oidc"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSessionHandler_RefreshMissingToken(t *testing.T) {
+	r, _ := setupTestSessionHandler(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/auth/refresh", strings.NewReader(`{}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func subjectMetaResponse(rec *storage.SubjectMetaRecord) *types.SubjectMetaResponse {
+	resp := &types.SubjectMetaResponse{
+		Subject:     rec.Subject,
+		Owner:       rec.Owner,
+		Description: rec.Description,
+		Contact:     rec.Contact,
+		Links:       rec.Links,
+		Reviewers:   rec.Reviewers,
+	}
+	if resp.Links == nil {
+		resp.Links = []storage.SubjectLink{}
+	}
+	if !rec.UpdatedAt.IsZero() {
+		resp.UpdatedAt = rec.UpdatedAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// GetSubjectMeta handles GET /subjects/{subject}/meta
+func (h *Handler) GetSubjectMeta(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	rec, err := h.registry.GetSubjectMeta(r.Context(), registryCtx, subject)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subjectMetaResponse(rec))
+}
+
+// SetSubjectMeta handles PUT /subjects/{subject}/meta. The body replaces
+// the stored meta.
+func (h *Handler) SetSubjectMeta(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	var req types.SubjectMetaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidSubjectMeta, "Invalid request body")
+		return
+	}
+	rec, err := h.registry.SetSubjectMeta(r.Context(), registryCtx, &storage.SubjectMetaRecord{
+		Subject:     subject,
+		Owner:       req.Owner,
+		Description: req.Description,
+		Contact:     req.Contact,
+		Links:       req.Links,
+		Reviewers:   req.Reviewers,
+	})
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subjectMetaResponse(rec))
+}
+
+// DeleteSubjectMeta handles DELETE /subjects/{subject}/meta
+func (h *Handler) DeleteSubjectMeta(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject := resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	if err := h.registry.DeleteSubjectMeta(r.Context(), registryCtx, subject); err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Default and maximum number of results returned by GET /search/schemas.
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+// tagTarget resolves the subject, and the version when the route has one,
+// that a tags request addresses. Version 0 means the subject itself.
+func tagTarget(w http.ResponseWriter, r *http.Request) (registryCtx, subject string, version int, ok bool) {
+	registryCtx, subject = resolveSubjectAndContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return "", "", 0, false
+	}
+	versionStr := chi.URLParam(r, "version")
+	if versionStr == "" {
+		return registryCtx, subject, 0, true
+	}
+	version, err := registry.ParseVersion(versionStr)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidVersion,
+			fmt.Sprintf("The specified version '%s' is not a valid version id. Allowed values are between [1, 2^31-1] and the string \"latest\"", versionStr))
+		return "", "", 0, false
+	}
+	return registryCtx, subject, version, true
+}
+
+func tagsResponse(rec *storage.TagsRecord) types.TagsResponse {
+	return types.TagsResponse{Subject: rec.Subject, Version: rec.Version, Tags: rec.Tags, Labels: rec.Labels}
+}
+
+// GetTags handles GET /subjects/{subject}/tags and
+// GET /subjects/{subject}/versions/{version}/tags.
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject, version, ok := tagTarget(w, r)
+	if !ok {
+		return
+	}
+	rec, err := h.registry.GetTags(r.Context(), registryCtx, subject, version)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tagsResponse(rec))
+}
+
+// SetTags handles PUT /subjects/{subject}/tags and
+// PUT /subjects/{subject}/versions/{version}/tags. The body replaces the
+// stored tags and labels.
+func (h *Handler) SetTags(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject, version, ok := tagTarget(w, r)
+	if !ok {
+		return
+	}
+	var req types.TagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, types.ErrorCodeInvalidTags, "Invalid request body")
+		return
+	}
+	rec, err := h.registry.SetTags(r.Context(), registryCtx, subject, version, req.Tags, req.Labels)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tagsResponse(rec))
+}
+
+// DeleteTags handles DELETE /subjects/{subject}/tags and
+// DELETE /subjects/{subject}/versions/{version}/tags.
+func (h *Handler) DeleteTags(w http.ResponseWriter, r *http.Request) {
+	registryCtx, subject, version, ok := tagTarget(w, r)
+	if !ok {
+		return
+	}
+	if err := h.registry.DeleteTags(r.Context(), registryCtx, subject, version); err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FindSchemas handles GET /search/schemas. Every filter given must match:
+// tag (repeatable), label (repeatable, key or key=value), type,
+// subjectPrefix and field. Only the latest version of each subject is
+// searched unless latestOnly=false.
+func (h *Handler) FindSchemas(w http.ResponseWriter, r *http.Request) {
+	registryCtx := getRegistryContext(r)
+	if rejectGlobalContext(w, registryCtx) {
+		return
+	}
+	q := r.URL.Query()
+	opts := registry.SchemaSearchOptions{
+		SchemaType:    q.Get("type"),
+		SubjectPrefix: q.Get("subjectPrefix"),
+		Field:         q.Get("field"),
+		AllVersions:   q.Get("latestOnly") == "false",
+		Limit:         defaultSearchLimit,
+	}
+	if opts.SchemaType != "" {
+		if _, ok := storage.ParseSchemaType(strings.ToUpper(opts.SchemaType)); !ok {
+			writeError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidSchema,
+				fmt.Sprintf("Invalid schema type '%s'. Accepted types are AVRO, PROTOBUF, and JSON", opts.SchemaType))
+			return
+		}
+	}
+	for _, tag := range q["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			opts.Tags = append(opts.Tags, tag)
+		}
+	}
+	if labels := q["label"]; len(labels) > 0 {
+		opts.Labels = make(map[string]string, len(labels))
+		for _, label := range labels {
+			key, value, _ := strings.Cut(label, "=")
+			opts.Labels[strings.TrimSpace(key)] = value
+		}
+	}
+	if limit, err := strconv.Atoi(q.Get("limit")); err == nil && limit > 0 {
+		opts.Limit = min(limit, maxSearchLimit)
+	}
+
+	matches, err := h.registry.SearchSchemas(r.Context(), registryCtx, opts)
+	if err != nil {
+		writeRegistryError(w, err)
+		return
+	}
+
+	resp := types.SchemaSearchResponse{Results: make([]types.SchemaSearchResult, 0, len(matches))}
+	for _, m := range matches {
+		resp.Results = append(resp.Results, types.SchemaSearchResult{
+			Subject:       m.Schema.Subject,
+			Version:       m.Schema.Version,
+			ID:            m.Schema.ID,
+			SchemaType:    schemaTypeForResponse(m.Schema.SchemaType),
+			Tags:          m.Tags,
+			Labels:        m.Labels,
+			MatchedFields: m.Fields,
+		})
+	}
+	resp.Count = len(resp.Results)
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+)
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header.
+const maxIdempotencyKeyLength = 255
+
+// idempotencyCache remembers the responses to mutating requests sent with an
+// Idempotency-Key header, so that a client retrying after a timeout gets the
+// first response instead of repeating the change. Keys are scoped to the
+// authenticated principal. Responses are kept in memory by this instance,
+// for ttl, and the oldest are dropped first when maxEntries is reached.
+type idempotencyCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	entries    map[string]*idempotencyEntry
+	order      *list.List // keys, oldest first
+}
+
+// idempotencyEntry is a request seen with a key. Until done is set the
+// request is still being handled.
+type idempotencyEntry struct {
+	fingerprint string
+	expires     time.Time
+	elem        *list.Element
+
+	done   bool
+	status int
+	header http.Header
+	body   []byte
+}
+
+// buildIdempotencyCache returns the cache for server.idempotency, or nil
+// when deduplication is off.
+func (s *Server) buildIdempotencyCache() *idempotencyCache {
+	cfg := s.config.Server.Idempotency
+	if !cfg.Enabled {
+		return nil
+	}
+	ttl, err := config.ParseDuration(cfg.TTL)
+	if err != nil || ttl <= 0 {
+		s.logger.Error("idempotency keys disabled", slog.String("error", fmt.Sprintf("invalid ttl %q", cfg.TTL)))
+		return nil
+	}
+	return newIdempotencyCache(ttl, cfg.MaxEntries)
+}
+
+func newIdempotencyCache(ttl time.Duration, maxEntries int) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*idempotencyEntry),
+		order:      list.New(),
+	}
+}
+
+// middleware deduplicates POST, PUT, PATCH and DELETE requests that carry an
+// Idempotency-Key header. The first request with a key is handled and its
+// response remembered; a retry with the same key, method, path and body
+// gets the remembered response with an Idempotent-Replayed header. Server
+// errors and rate limiting are not remembered, so those requests can be
+// retried. It must run after the authentication middleware.
+func (c *idempotencyCache) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" || !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeIdempotencyError(w, http.StatusUnprocessableEntity, types.ErrorCodeInvalidRequest,
+				fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			// Let the handler report the unreadable body.
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal := ""
+		if user := auth.GetUser(r.Context()); user != nil {
+			principal = user.Username
+		}
+		id := principal + "\x00" + key
+		h := sha256.New()
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", r.Method, r.URL.Path, r.URL.RawQuery)
+		h.Write(body)
+		fingerprint := string(h.Sum(nil))
+
+		entry, existing := c.begin(id, fingerprint)
+		if existing != nil {
+			switch {
+			case existing.fingerprint != fingerprint:
+				writeIdempotencyError(w, http.StatusUnprocessableEntity, types.ErrorCodeIdempotencyKeyReused,
+					"Idempotency-Key was already used for a different request")
+			case !existing.done:
+				writeIdempotencyError(w, http.StatusConflict, types.ErrorCodeIdempotencyKeyInUse,
+					"A request with this Idempotency-Key is still being processed")
+			default:
+				for name, values := range existing.header {
+					w.Header()[name] = values
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(existing.status)
+				_, _ = w.Write(existing.body)
+			}
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		finished := false
+		defer func() {
+			if !finished {
+				// The handler panicked: forget the key so it can be retried.
+				c.finish(id, entry, nil)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+		finished = true
+		c.finish(id, entry, rec)
+	})
+}
+
+// begin records a request with id and returns its new entry, or returns
+// the entry of an earlier request with id that has not expired.
+func (c *idempotencyCache) begin(id, fingerprint string) (entry, existing *idempotencyEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+
+	if e, ok := c.entries[id]; ok {
+		if now.Before(e.expires) {
+			return nil, e
+		}
+		c.remove(id, e)
+	}
+	for c.order.Len() > 0 {
+		oldest := c.order.Front()
+		e := c.entries[oldest.Value.(string)]
+		if c.order.Len() < c.maxEntries && now.Before(e.expires) {
+			break
+		}
+		c.remove(oldest.Value.(string), e)
+	}
+
+	entry = &idempotencyEntry{fingerprint: fingerprint, expires: now.Add(c.ttl)}
+	entry.elem = c.order.PushBack(id)
+	c.entries[id] = entry
+	return entry, nil
+}
+
+// finish remembers the response recorded for a request, or forgets the
+// request when the response must not be replayed.
+func (c *idempotencyCache) finish(id string, entry *idempotencyEntry, rec *responseRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[id] != entry {
+		// Dropped to make room while the request was handled.
+		return
+	}
+	if rec == nil || rec.status >= http.StatusInternalServerError || rec.status == http.StatusTooManyRequests {
+		c.remove(id, entry)
+		return
+	}
+	entry.done = true
+	entry.status = rec.status
+	entry.header = rec.header
+	if entry.header == nil {
+		entry.header = rec.Header().Clone()
+	}
+	entry.body = rec.body.Bytes()
+}
+
+func (c *idempotencyCache) remove(id string, e *idempotencyEntry) {
+	delete(c.entries, id)
+	c.order.Remove(e.elem)
+}
+
+// responseRecorder passes a response through while keeping a copy.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		r.status = status
+		r.header = r.Header().Clone()
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func writeIdempotencyError(w http.ResponseWriter, status, code int, message string) {
+	body, _ := json.Marshal(types.ErrorResponse{ErrorCode: code, Message: message})
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
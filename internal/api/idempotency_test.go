@@ -0,0 +1,156 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+)
+
+// countingHandler answers each request with the number of requests it has
+// handled, with the given status.
+func countingHandler(status int, calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id":` + strconv.Itoa(*calls) + `}`))
+	})
+}
+
+func idempotentRequest(h http.Handler, method, path, key, body, user string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		r.Header.Set("Idempotency-Key", key)
+	}
+	if user != "" {
+		r = r.WithContext(auth.WithUser(r.Context(), &auth.User{Username: user}))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestIdempotencyCache_Replay(t *testing.T) {
+	calls := 0
+	h := newIdempotencyCache(time.Hour, 100).middleware(countingHandler(http.StatusOK, &calls))
+	body := `{"schema":"\"string\""}`
+
+	first := idempotentRequest(h, "POST", "/subjects/orders-value/versions", "k1", body, "alice")
+	retry := idempotentRequest(h, "POST", "/subjects/orders-value/versions", "k1", body, "alice")
+	if calls != 1 {
+		t.Fatalf("expected the retry to be replayed, handler called %d times", calls)
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() {
+		t.Errorf("expected replay of %d %s, got %d %s", first.Code, first.Body, retry.Code, retry.Body)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("expected only the replay to carry Idempotent-Replayed")
+	}
+	if retry.Header().Get("Content-Type") != "application/vnd.schemaregistry.v1+json" {
+		t.Errorf("expected the content type to be replayed, got %q", retry.Header().Get("Content-Type"))
+	}
+
+	// Keys are scoped to the principal, and requests without a key or that
+	// do not change anything are not deduplicated.
+	idempotentRequest(h, "POST", "/subjects/orders-value/versions", "k1", body, "bob")
+	idempotentRequest(h, "POST", "/subjects/orders-value/versions", "", body, "alice")
+	idempotentRequest(h, "GET", "/subjects", "k2", "", "alice")
+	idempotentRequest(h, "GET", "/subjects", "k2", "", "alice")
+	if calls != 5 {
+		t.Errorf("expected 5 handled requests, got %d", calls)
+	}
+}
+
+func TestIdempotencyCache_KeyReusedForDifferentRequest(t *testing.T) {
+	calls := 0
+	h := newIdempotencyCache(time.Hour, 100).middleware(countingHandler(http.StatusOK, &calls))
+
+	idempotentRequest(h, "POST", "/subjects/a/versions", "k1", `{"schema":"\"string\""}`, "")
+	for _, tt := range []struct{ method, path, body string }{
+		{"POST", "/subjects/a/versions", `{"schema":"\"int\""}`},
+		{"POST", "/subjects/b/versions", `{"schema":"\"string\""}`},
+		{"DELETE", "/subjects/a/versions", `{"schema":"\"string\""}`},
+	} {
+		w := idempotentRequest(h, tt.method, tt.path, "k1", tt.body, "")
+		if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), strconv.Itoa(types.ErrorCodeIdempotencyKeyReused)) {
+			t.Errorf("%s %s: expected 422 %d, got %d %s", tt.method, tt.path, types.ErrorCodeIdempotencyKeyReused, w.Code, w.Body)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected only the first request to be handled, got %d", calls)
+	}
+}
+
+func TestIdempotencyCache_ServerErrorsNotRemembered(t *testing.T) {
+	calls := 0
+	h := newIdempotencyCache(time.Hour, 100).middleware(countingHandler(http.StatusInternalServerError, &calls))
+	idempotentRequest(h, "PUT", "/config", "k1", `{"compatibility":"FULL"}`, "")
+	idempotentRequest(h, "PUT", "/config", "k1", `{"compatibility":"FULL"}`, "")
+	if calls != 2 {
+		t.Errorf("expected a failed request to be retried, handler called %d times", calls)
+	}
+
+	// Client errors are remembered like successes.
+	calls = 0
+	h = newIdempotencyCache(time.Hour, 100).middleware(countingHandler(http.StatusConflict, &calls))
+	idempotentRequest(h, "PUT", "/config", "k1", `{"compatibility":"FULL"}`, "")
+	w := idempotentRequest(h, "PUT", "/config", "k1", `{"compatibility":"FULL"}`, "")
+	if calls != 1 || w.Code != http.StatusConflict {
+		t.Errorf("expected the 409 to be replayed, handler called %d times, got %d", calls, w.Code)
+	}
+}
+
+func TestIdempotencyCache_InProgress(t *testing.T) {
+	c := newIdempotencyCache(time.Hour, 100)
+	var inner *httptest.ResponseRecorder
+	var h http.Handler
+	h = c.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A retry arriving while the first request is still handled.
+		inner = idempotentRequest(h, "DELETE", "/subjects/a", "k1", "", "")
+		w.WriteHeader(http.StatusOK)
+	}))
+	idempotentRequest(h, "DELETE", "/subjects/a", "k1", "", "")
+	if inner.Code != http.StatusConflict || !strings.Contains(inner.Body.String(), strconv.Itoa(types.ErrorCodeIdempotencyKeyInUse)) {
+		t.Errorf("expected 409 %d, got %d %s", types.ErrorCodeIdempotencyKeyInUse, inner.Code, inner.Body)
+	}
+}
+
+func TestIdempotencyCache_ExpiryAndEviction(t *testing.T) {
+	calls := 0
+	c := newIdempotencyCache(time.Minute, 2)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	h := c.middleware(countingHandler(http.StatusOK, &calls))
+
+	idempotentRequest(h, "POST", "/subjects/a/versions", "k1", "{}", "")
+	now = now.Add(2 * time.Minute)
+	idempotentRequest(h, "POST", "/subjects/a/versions", "k1", "{}", "")
+	if calls != 2 {
+		t.Fatalf("expected an expired key to be handled again, handler called %d times", calls)
+	}
+
+	idempotentRequest(h, "POST", "/subjects/a/versions", "k2", "{}", "")
+	idempotentRequest(h, "POST", "/subjects/a/versions", "k3", "{}", "")
+	if len(c.entries) != 2 {
+		t.Errorf("expected at most 2 remembered responses, got %d", len(c.entries))
+	}
+	idempotentRequest(h, "POST", "/subjects/a/versions", "k1", "{}", "")
+	if calls != 5 {
+		t.Errorf("expected the oldest key to have been dropped, handler called %d times", calls)
+	}
+}
+
+func TestIdempotencyCache_KeyTooLong(t *testing.T) {
+	calls := 0
+	h := newIdempotencyCache(time.Hour, 100).middleware(countingHandler(http.StatusOK, &calls))
+	w := idempotentRequest(h, "POST", "/subjects/a/versions", strings.Repeat("k", maxIdempotencyKeyLength+1), "{}", "")
+	if w.Code != http.StatusUnprocessableEntity || calls != 0 {
+		t.Errorf("expected 422 without handling, got %d (handler called %d times)", w.Code, calls)
+	}
+}
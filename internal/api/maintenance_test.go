@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestServer_Maintenance(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"basic"}
+	cfg.Security.Auth.RBAC.Enabled = true
+
+	store := memory.NewStore()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	svc := auth.NewService(store)
+	authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+	authenticator.SetService(svc)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.Security.Auth.RBAC), svc))
+
+	for _, u := range []auth.CreateUserRequest{
+		{Username: "root", Password: "root-pass", Role: "admin", Enabled: true},
+		{Username: "dev", Password: "dev-pass", Role: "developer", Enabled: true},
+	} {
+		if _, err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser(%s): %v", u.Username, err)
+		}
+	}
+
+	do := func(user, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth(user, user+"-pass")
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := do("dev", http.MethodPost, "/admin/maintenance", `{"duration":"1h","reason":"upgrade"}`); rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a developer, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("root", http.MethodPost, "/admin/maintenance", `{"duration":"soon","reason":"upgrade"}`); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for an invalid duration, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr := do("root", http.MethodPost, "/admin/maintenance", `{"duration":"1h","reason":"storage upgrade"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("schedule: expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var window types.MaintenanceResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &window); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if window.State != registry.MaintenanceActive || window.CreatedBy != "root" {
+		t.Errorf("expected an ACTIVE window created by root, got %+v", window)
+	}
+
+	rr = do("dev", http.MethodGet, "/mode", "")
+	var mode types.ModeResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &mode); err != nil {
+		t.Fatalf("decode mode: %v", err)
+	}
+	if mode.Mode != "READONLY_OVERRIDE" || mode.Maintenance == nil || mode.Maintenance.ID != window.ID {
+		t.Errorf("expected READONLY_OVERRIDE with the maintenance window, got %s", rr.Body.String())
+	}
+	if rr := do("dev", http.MethodPost, "/subjects/orders-value/versions", `{"schema":"\"string\""}`); rr.Code == http.StatusOK {
+		t.Error("expected registration to be rejected during maintenance")
+	}
+
+	if rr := do("root", http.MethodDelete, "/admin/maintenance/"+window.ID, ""); rr.Code != http.StatusOK {
+		t.Fatalf("cancel: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("root", http.MethodDelete, "/admin/maintenance/"+window.ID, ""); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("cancel twice: expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("root", http.MethodGet, "/admin/maintenance/missing", ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown window, got %d", rr.Code)
+	}
+
+	rr = do("dev", http.MethodGet, "/mode", "")
+	mode = types.ModeResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &mode); err != nil {
+		t.Fatalf("decode mode: %v", err)
+	}
+	if mode.Mode != "READWRITE" || mode.Maintenance != nil {
+		t.Errorf("expected READWRITE once cancelled, got %s", rr.Body.String())
+	}
+
+	rr = do("root", http.MethodGet, "/admin/maintenance", "")
+	var list types.MaintenanceListResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Windows) != 1 || list.Windows[0].State != registry.MaintenanceCancelled {
+		t.Errorf("expected one CANCELLED window, got %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+)
+
+type peerAddrKey struct{}
+
+// peerAddrMiddleware records the connecting peer address before RealIP
+// replaces RemoteAddr with a value taken from forwarding headers.
+func peerAddrMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), peerAddrKey{}, r.RemoteAddr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// peerIP returns the IP of the connecting peer recorded by peerAddrMiddleware.
+func peerIP(r *http.Request) net.IP {
+	addr, _ := r.Context().Value(peerAddrKey{}).(string)
+	if addr == "" {
+		addr = r.RemoteAddr
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(addr)
+}
+
+// metricsAccess guards /metrics with a scrape token and/or a network
+// allow-list, independently of the main auth stack. A request is allowed if
+// it satisfies either check. With neither configured, /metrics is public.
+type metricsAccess struct {
+	token string
+	nets  []*net.IPNet
+}
+
+// newMetricsAccess builds the /metrics guard from config. CIDRs are checked
+// by Config.Validate, so invalid entries are skipped here.
+func newMetricsAccess(cfg config.SecurityMetrics) *metricsAccess {
+	m := &metricsAccess{token: cfg.ScrapeToken}
+	for _, cidr := range cfg.AllowedCIDRs {
+		if _, n, err := net.ParseCIDR(cidr); err == nil {
+			m.nets = append(m.nets, n)
+		}
+	}
+	return m
+}
+
+// enabled reports whether any restriction is configured.
+func (m *metricsAccess) enabled() bool {
+	return m.token != "" || len(m.nets) > 0
+}
+
+// allowed reports whether the request may read /metrics.
+func (m *metricsAccess) allowed(r *http.Request) bool {
+	if !m.enabled() {
+		return true
+	}
+	if m.token != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok &&
+			subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) == 1 {
+			return true
+		}
+	}
+	if ip := peerIP(r); ip != nil {
+		for _, n := range m.nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// middleware rejects /metrics requests that fail the access checks.
+func (m *metricsAccess) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.allowed(r) {
+			if m.token != "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			}
+			w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_code":40101,"message":"Unauthorized"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestServer_MetricsAccess(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Security.Metrics.ScrapeToken = "scrape-secret"
+	cfg.Security.Metrics.AllowedCIDRs = []string{"10.1.0.0/16"}
+	reg := registry.New(memory.NewStore(), schema.NewRegistry(), compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       int
+	}{
+		{"no credentials", "203.0.113.5:4000", nil, http.StatusUnauthorized},
+		{"wrong token", "203.0.113.5:4000", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"scrape token", "203.0.113.5:4000", map[string]string{"Authorization": "Bearer scrape-secret"}, http.StatusOK},
+		{"allowed network", "10.1.2.3:4000", nil, http.StatusOK},
+		{"forwarded header is not trusted", "203.0.113.5:4000", map[string]string{"X-Forwarded-For": "10.1.2.3"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.RemoteAddr = tt.remoteAddr
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, w.Code)
+		}
+	}
+
+	// Other public endpoints are unaffected.
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("health check: expected 200, got %d", w.Code)
+	}
+}
+
+func TestServer_MetricsPublicByDefault(t *testing.T) {
+	server := setupTestServer(t)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+}
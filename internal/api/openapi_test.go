@@ -1,8 +1,10 @@
 package api
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"sort"
 	"strings"
@@ -25,7 +27,7 @@ import (
 
 // openAPIDocument is a minimal representation of the OpenAPI spec for path extraction.
 type openAPIDocument struct {
-	Paths map[string]map[string]interface{} `yaml:"paths"`
+	Paths map[string]map[string]interface{} `yaml:"paths" json:"paths"`
 }
 
 // setupFullServer creates a server with all routes registered (including auth-conditional
@@ -35,6 +37,7 @@ func setupFullServer(t *testing.T) *Server {
 
 	cfg := config.DefaultConfig()
 	cfg.Server.DocsEnabled = true
+	cfg.Tenancy.Enabled = true
 
 	store := memory.NewStore()
 
@@ -46,8 +49,13 @@ func setupFullServer(t *testing.T) *Server {
 
 	reg := registry.New(store, schemaRegistry, compatChecker, cfg.Compatibility.DefaultLevel)
 
-	// Create auth service and authorizer so admin/account routes are registered.
-	authService := auth.NewService(store)
+	// Create auth service and authorizer so admin/account routes are registered,
+	// with login sessions enabled so the /auth routes are too.
+	signer, err := auth.NewSessionSigner(config.SessionConfig{Enabled: true, Secret: "openapi-test-session-secret-0123456789"})
+	if err != nil {
+		t.Fatalf("NewSessionSigner: %v", err)
+	}
+	authService := auth.NewServiceWithConfig(store, auth.ServiceConfig{Sessions: signer})
 	t.Cleanup(func() { authService.Close() })
 	authorizer := auth.NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
 
@@ -234,3 +242,35 @@ func TestOpenAPISpecHasSecuritySchemes(t *testing.T) {
 		}
 	}
 }
+
+// TestOpenAPISpecJSON validates that /openapi.json serves the same document as
+// /openapi.yaml.
+func TestOpenAPISpecJSON(t *testing.T) {
+	server := setupFullServer(t)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var fromJSON openAPIDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &fromJSON); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	var fromYAML openAPIDocument
+	if err := yaml.Unmarshal(openapispec.OpenAPISpec, &fromYAML); err != nil {
+		t.Fatalf("Failed to parse OpenAPI spec: %v", err)
+	}
+	if len(fromJSON.Paths) != len(fromYAML.Paths) {
+		t.Errorf("JSON spec has %d paths, YAML spec has %d", len(fromJSON.Paths), len(fromYAML.Paths))
+	}
+	for p, ops := range fromYAML.Paths {
+		if len(fromJSON.Paths[p]) != len(ops) {
+			t.Errorf("%s: JSON spec has %d operations, YAML spec has %d", p, len(fromJSON.Paths[p]), len(ops))
+		}
+	}
+}
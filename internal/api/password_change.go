@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+)
+
+// passwordChangeMiddleware refuses every request of a user who must change
+// their password, except those to their own account endpoints, where they
+// can change it. It must run after the authentication middleware.
+func passwordChangeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := auth.GetUser(r.Context())
+		if user == nil || !user.PasswordChangeRequired {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if path := strings.TrimSuffix(r.URL.Path, "/"); path == "/me" || path == "/me/password" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"Password change required: change your password with POST /me/password"}`)
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestServer_PasswordChangeRequired(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"basic"}
+	cfg.Security.Auth.RBAC.Enabled = true
+
+	store := memory.NewStore()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	svc := auth.NewServiceWithConfig(store, auth.ServiceConfig{BootstrapPasswordChange: true})
+	defer svc.Close()
+	authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+	authenticator.SetService(svc)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.Security.Auth.RBAC), svc))
+
+	if _, err := svc.BootstrapAdmin(context.Background(), "admin", "initial-secret", ""); err != nil {
+		t.Fatalf("BootstrapAdmin: %v", err)
+	}
+
+	do := func(method, path, password, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth("admin", password)
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := do(http.MethodGet, "/subjects", "initial-secret", ""); code != http.StatusForbidden {
+		t.Errorf("expected the admin to be refused before changing the password, got %d", code)
+	}
+	if code := do(http.MethodGet, "/me", "initial-secret", ""); code != http.StatusOK {
+		t.Errorf("expected GET /me to be allowed, got %d", code)
+	}
+	if code := do(http.MethodPost, "/me/password", "initial-secret", `{"old_password":"initial-secret","new_password":"rotated-secret"}`); code != http.StatusNoContent {
+		t.Fatalf("expected the password change to succeed, got %d", code)
+	}
+	if code := do(http.MethodGet, "/subjects", "rotated-secret", ""); code != http.StatusOK {
+		t.Errorf("expected the admin to be allowed after changing the password, got %d", code)
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestServer_PendingSchemas(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"basic"}
+	cfg.Security.Auth.RBAC.Enabled = true
+
+	store := memory.NewStore()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	reg.SetApprovalContexts([]string{"."})
+	svc := auth.NewService(store)
+	authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+	authenticator.SetService(svc)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.Security.Auth.RBAC), svc))
+
+	for _, u := range []auth.CreateUserRequest{
+		{Username: "root", Password: "root-pass", Role: "admin", Enabled: true},
+		{Username: "dev", Password: "dev-pass", Role: "developer", Enabled: true},
+		{Username: "lead", Password: "lead-pass", Role: "admin", Enabled: true},
+	} {
+		if _, err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser(%s): %v", u.Username, err)
+		}
+	}
+
+	do := func(user, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth(user, user+"-pass")
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// A developer's registration is held for approval.
+	rr := do("dev", http.MethodPost, "/subjects/orders-value/versions", `{"schema":"\"string\""}`)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("register: expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var pending types.PendingSchemaResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &pending); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if pending.State != storage.PendingSchemaPending || pending.SubmittedBy != "dev" || pending.Schema != `"string"` {
+		t.Errorf("expected a PENDING schema submitted by dev, got %+v", pending)
+	}
+	if rr := do("dev", http.MethodGet, "/subjects/orders-value/versions", ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expected no versions before approval, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("dev", http.MethodPut, "/subjects/orders-value/versions/abc", `{"schema":"\"string\""}`); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected idempotent registration to be rejected, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if rr := do("dev", http.MethodGet, "/admin/pending-schemas", ""); rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 listing as a developer, got %d", rr.Code)
+	}
+	if rr := do("dev", http.MethodPost, "/admin/pending-schemas/"+pending.ID+"/approve", ""); rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 approving as a developer, got %d", rr.Code)
+	}
+	rr = do("root", http.MethodGet, "/admin/pending-schemas", "")
+	var list types.PendingSchemasListResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.PendingSchemas) != 1 || list.PendingSchemas[0].ID != pending.ID {
+		t.Errorf("expected the pending schema to be listed, got %s", rr.Body.String())
+	}
+	if rr := do("root", http.MethodPost, "/admin/pending-schemas/"+pending.ID+"/reject", `{}`); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 rejecting without a reason, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = do("root", http.MethodPost, "/admin/pending-schemas/"+pending.ID+"/approve", `{"reason":"reviewed"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("approve: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	pending = types.PendingSchemaResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &pending); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if pending.State != storage.PendingSchemaApproved || pending.ReviewedBy != "root" || pending.Version != 1 || pending.SchemaID == 0 {
+		t.Errorf("expected an APPROVED schema registered as version 1, got %+v", pending)
+	}
+	if rr := do("root", http.MethodPost, "/admin/pending-schemas/"+pending.ID+"/approve", ""); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("approve twice: expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("root", http.MethodGet, "/admin/pending-schemas/missing", ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown pending schema, got %d", rr.Code)
+	}
+
+	// Registering the approved schema again returns its ID.
+	rr = do("dev", http.MethodPost, "/subjects/orders-value/versions", `{"schema":"\"string\""}`)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"id"`) {
+		t.Errorf("expected the registered schema's ID, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Only the reviewers the subject declares may review its pending schemas.
+	if rr := do("root", http.MethodPut, "/config/orders-value", `{"compatibility":"NONE"}`); rr.Code != http.StatusOK {
+		t.Fatalf("set config: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("root", http.MethodPut, "/subjects/orders-value/meta", `{"reviewers":["lead"]}`); rr.Code != http.StatusOK {
+		t.Fatalf("set meta: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = do("dev", http.MethodPost, "/subjects/orders-value/versions", `{"schema":"\"int\""}`)
+	pending = types.PendingSchemaResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &pending); err != nil || rr.Code != http.StatusAccepted {
+		t.Fatalf("register: expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(pending.Reviewers) != 1 || pending.Reviewers[0] != "lead" {
+		t.Errorf("expected the pending schema to be assigned to lead, got %v", pending.Reviewers)
+	}
+	rr = do("root", http.MethodPost, "/admin/pending-schemas/"+pending.ID+"/approve", "")
+	if rr.Code != http.StatusForbidden || !strings.Contains(rr.Body.String(), "40303") {
+		t.Errorf("expected 403 with 40303 approving as another admin, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("lead", http.MethodPost, "/admin/pending-schemas/"+pending.ID+"/approve", ""); rr.Code != http.StatusOK {
+		t.Errorf("approve as lead: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
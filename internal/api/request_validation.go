@@ -0,0 +1,466 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	openapispec "github.com/axonops/axonops-schema-registry/api"
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+)
+
+// maxValidationErrors caps the problems listed in one rejection; a body that
+// is wrong in more places than this is reported as such.
+const maxValidationErrors = 10
+
+// requestValidator rejects JSON request bodies that do not match the request
+// schemas in the OpenAPI specification, before they reach a handler. Without
+// it a mistyped field such as "schemaTyp" is silently ignored by the JSON
+// decoder. In strict mode, fields the specification does not declare are
+// rejected as well; otherwise only wrong types, missing required fields and
+// out-of-range values are.
+//
+// Only the subset of the schema language the specification uses is
+// interpreted: type, properties, required, additionalProperties, items,
+// enum, minimum, pattern and component $refs.
+type requestValidator struct {
+	strict     bool
+	components map[string]any
+	operations []validatedOperation
+	patterns   map[string]*regexp.Regexp
+}
+
+// validatedOperation is an operation whose request body has a JSON schema.
+type validatedOperation struct {
+	method   string
+	segments []string // path template split on "/", with "{param}" segments kept as is
+	schema   map[string]any
+}
+
+// newRequestValidator builds a validator from an OpenAPI document.
+func newRequestValidator(spec []byte, strict bool) (*requestValidator, error) {
+	var raw any
+	if err := yaml.Unmarshal(spec, &raw); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI specification: %w", err)
+	}
+	doc, _ := jsonCompatible(raw).(map[string]any)
+	if doc == nil {
+		return nil, fmt.Errorf("OpenAPI specification is not a mapping")
+	}
+
+	v := &requestValidator{
+		strict:   strict,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	if components, ok := doc["components"].(map[string]any); ok {
+		v.components, _ = components["schemas"].(map[string]any)
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	for path, item := range paths {
+		methods, _ := item.(map[string]any)
+		for method, op := range methods {
+			schema := requestBodySchema(op)
+			if schema == nil {
+				continue
+			}
+			v.operations = append(v.operations, validatedOperation{
+				method:   strings.ToUpper(method),
+				segments: strings.Split(strings.Trim(path, "/"), "/"),
+				schema:   schema,
+			})
+		}
+	}
+
+	// Compile every pattern up front so a bad one fails startup, not a request
+	for _, op := range v.operations {
+		if err := v.compilePatterns(op.schema, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// buildRequestValidator returns the validator for server.request_validation,
+// or nil when validation is off.
+func (s *Server) buildRequestValidator() *requestValidator {
+	mode := s.config.Server.RequestValidation
+	if mode != "on" && mode != "strict" {
+		return nil
+	}
+	v, err := newRequestValidator(openapispec.OpenAPISpec, mode == "strict")
+	if err != nil {
+		s.logger.Error("request validation disabled", slog.String("error", err.Error()))
+		return nil
+	}
+	return v
+}
+
+// requestBodySchema returns the JSON schema of an operation's request body,
+// or nil if it does not take JSON.
+func requestBodySchema(op any) map[string]any {
+	operation, _ := op.(map[string]any)
+	body, _ := operation["requestBody"].(map[string]any)
+	content, _ := body["content"].(map[string]any)
+	for _, mediaType := range []string{"application/vnd.schemaregistry.v1+json", "application/json"} {
+		if media, ok := content[mediaType].(map[string]any); ok {
+			if schema, ok := media["schema"].(map[string]any); ok {
+				return schema
+			}
+		}
+	}
+	return nil
+}
+
+func (v *requestValidator) compilePatterns(schema map[string]any, seen map[string]bool) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		if target := v.resolve(schema); target != nil {
+			return v.compilePatterns(target, seen)
+		}
+		return nil
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if _, exists := v.patterns[pattern]; !exists {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("OpenAPI pattern %q: %w", pattern, err)
+			}
+			v.patterns[pattern] = re
+		}
+	}
+	var children []map[string]any
+	if props, ok := schema["properties"].(map[string]any); ok {
+		for _, p := range props {
+			if m, ok := p.(map[string]any); ok {
+				children = append(children, m)
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties"} {
+		if m, ok := schema[key].(map[string]any); ok {
+			children = append(children, m)
+		}
+	}
+	for _, child := range children {
+		if err := v.compilePatterns(child, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// middleware validates the body of requests to operations that take JSON.
+// Bodies that are empty or not JSON at all are passed through, so that the
+// handlers keep reporting those the way they always have.
+func (v *requestValidator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody || !isJSONContentType(r.Header.Get("Content-Type")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		schema := v.match(r.Method, r.URL.EscapedPath())
+		if schema == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil || len(bytes.TrimSpace(body)) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var problems []string
+		v.validate(value, schema, "", &problems)
+		if len(problems) > 0 {
+			writeValidationError(w, problems)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isJSONContentType reports whether a request body is declared as JSON.
+// Clients that send no content type are assumed to send JSON, as the
+// handlers do.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.HasSuffix(strings.TrimSpace(strings.ToLower(mediaType)), "json")
+}
+
+// match returns the request schema of the operation a request is for. A
+// literal path segment is preferred over a parameter at the same position,
+// so /subjects/match is not taken for /subjects/{subject}.
+func (v *requestValidator) match(method, path string) map[string]any {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var best map[string]any
+	bestScore := -1
+	for _, op := range v.operations {
+		if op.method != method || len(op.segments) != len(segments) {
+			continue
+		}
+		score, ok := 0, true
+		for i, seg := range op.segments {
+			if strings.HasPrefix(seg, "{") {
+				continue
+			}
+			if seg != segments[i] {
+				ok = false
+				break
+			}
+			// Earlier literals weigh more than later ones
+			score += 1 << (len(segments) - i)
+		}
+		if ok && score > bestScore {
+			best, bestScore = op.schema, score
+		}
+	}
+	return best
+}
+
+// resolve follows a component $ref, returning nil if it does not point at a
+// known component schema.
+func (v *requestValidator) resolve(schema map[string]any) map[string]any {
+	ref, _ := schema["$ref"].(string)
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		return nil
+	}
+	target, _ := v.components[name].(map[string]any)
+	return target
+}
+
+// validate checks value against schema, appending a problem for each
+// mismatch. path locates value in the body, e.g. "references[0].version".
+func (v *requestValidator) validate(value any, schema map[string]any, path string, problems *[]string) {
+	if len(*problems) >= maxValidationErrors {
+		return
+	}
+	if _, isRef := schema["$ref"]; isRef {
+		if target := v.resolve(schema); target != nil {
+			v.validate(value, target, path, problems)
+		}
+		return
+	}
+	report := func(format string, args ...any) {
+		if len(*problems) < maxValidationErrors {
+			*problems = append(*problems, fieldPath(path)+": "+fmt.Sprintf(format, args...))
+		}
+	}
+
+	typ, _ := schema["type"].(string)
+	if typ == "" {
+		if _, ok := schema["properties"]; ok {
+			typ = "object"
+		}
+	}
+	if typ != "" && !hasJSONType(value, typ) {
+		report("expected %s, got %s", typ, jsonTypeName(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !enumContains(enum, value) {
+		report("must be one of %s", enumList(enum))
+	}
+	if minimum, ok := schema["minimum"]; ok {
+		if n, isNum := value.(json.Number); isNum {
+			if min, err := strconv.ParseFloat(fmt.Sprint(minimum), 64); err == nil {
+				if f, err := n.Float64(); err == nil && f < min {
+					report("must be at least %v", minimum)
+				}
+			}
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if s, isStr := value.(string); isStr {
+			if re := v.patterns[pattern]; re != nil && !re.MatchString(s) {
+				report("must match pattern %q", pattern)
+			}
+		}
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(val, schema, path, problems)
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				v.validate(item, items, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+func (v *requestValidator) validateObject(obj map[string]any, schema map[string]any, path string, problems *[]string) {
+	props, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if val, present := obj[name]; !present || val == nil {
+				if len(*problems) < maxValidationErrors {
+					*problems = append(*problems, fieldPath(joinPath(path, name))+": is required")
+				}
+			}
+		}
+	}
+
+	// Walk fields in a stable order so the reported problems are too
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		val := obj[name]
+		loc := joinPath(path, name)
+		if prop, ok := props[name].(map[string]any); ok {
+			// JSON null is how many clients omit an optional field
+			if val != nil {
+				v.validate(val, prop, loc, problems)
+			}
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case map[string]any:
+			if val != nil {
+				v.validate(val, extra, loc, problems)
+			}
+		case bool:
+			if !extra && len(*problems) < maxValidationErrors {
+				*problems = append(*problems, loc+": unknown field")
+			}
+		default:
+			if v.strict && props != nil && len(*problems) < maxValidationErrors {
+				*problems = append(*problems, loc+": unknown field")
+			}
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// fieldPath names the location of a problem, using "body" for the whole body.
+func fieldPath(path string) string {
+	if path == "" {
+		return "body"
+	}
+	return path
+}
+
+func hasJSONType(value any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := strconv.ParseInt(n.String(), 10, 64)
+		return err == nil
+	}
+	return true
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// enumContains matches strings case-insensitively, because the handlers
+// accept enumerated values such as compatibility levels in any case.
+func enumContains(enum []any, value any) bool {
+	for _, e := range enum {
+		if s, ok := value.(string); ok {
+			if es, ok := e.(string); ok && strings.EqualFold(s, es) {
+				return true
+			}
+			continue
+		}
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, ", ")
+}
+
+// writeValidationError rejects a request with the problems found in its body.
+func writeValidationError(w http.ResponseWriter, problems []string) {
+	message := "Request validation failed: " + strings.Join(problems, "; ")
+	if len(problems) >= maxValidationErrors {
+		message += "; (further problems not listed)"
+	}
+	body, _ := json.Marshal(types.ErrorResponse{
+		ErrorCode: types.ErrorCodeInvalidRequest,
+		Message:   message,
+	})
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	openapispec "github.com/axonops/axonops-schema-registry/api"
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+)
+
+func validateRequest(t *testing.T, strict bool, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	v, err := newRequestValidator(openapispec.OpenAPISpec, strict)
+	if err != nil {
+		t.Fatalf("newRequestValidator: %v", err)
+	}
+	h := v.middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestRequestValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  bool
+		method  string
+		path    string
+		body    string
+		wantErr string // empty when the request should pass
+	}{
+		{"valid register", false, "POST", "/subjects/orders-value/versions", `{"schema":"{}","schemaType":"AVRO"}`, ""},
+		{"typoed field ignored when not strict", false, "POST", "/subjects/orders-value/versions", `{"schema":"{}","schemaTyp":"AVRO"}`, ""},
+		{"typoed field rejected when strict", true, "POST", "/subjects/orders-value/versions", `{"schema":"{}","schemaTyp":"AVRO"}`, "schemaTyp: unknown field"},
+		{"wrong type in nested field", false, "POST", "/subjects/orders-value/versions", `{"schema":"{}","references":[{"name":"a","subject":"b","version":"1"}]}`, "references[0].version: expected integer, got string"},
+		{"missing required field", false, "POST", "/subjects/orders-value", `{"schemaType":"AVRO"}`, "schema: is required"},
+		{"null optional field", true, "POST", "/subjects/orders-value/versions", `{"schema":"{}","metadata":null}`, ""},
+		{"enum in any case", false, "PUT", "/config", `{"compatibility":"backward"}`, ""},
+		{"enum value unknown", false, "PUT", "/config", `{"compatibility":"SIDEWAYS"}`, "compatibility: must be one of"},
+		{"context-scoped route", false, "POST", "/contexts/.staging/subjects/orders-value/versions", `{"schema":5}`, "schema: expected string, got number"},
+		{"literal segment preferred over parameter", true, "POST", "/subjects/match", `{"pattern":"orders-*","mode":"glob"}`, ""},
+		{"malformed JSON left to the handler", false, "POST", "/subjects/orders-value/versions", `{"schema":`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := validateRequest(t, tt.strict, tt.method, tt.path, tt.body)
+			if tt.wantErr == "" {
+				if w.Code != http.StatusOK {
+					t.Fatalf("expected request to pass, got %d: %s", w.Code, w.Body.String())
+				}
+				return
+			}
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp types.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode error: %v", err)
+			}
+			if resp.ErrorCode != types.ErrorCodeInvalidRequest {
+				t.Errorf("expected error code %d, got %d", types.ErrorCodeInvalidRequest, resp.ErrorCode)
+			}
+			if !strings.Contains(resp.Message, tt.wantErr) {
+				t.Errorf("expected message to contain %q, got %q", tt.wantErr, resp.Message)
+			}
+		})
+	}
+}
+
+func TestServer_RequestValidation(t *testing.T) {
+	body := `{"schema":"{\"type\":\"string\"}","schemaTyp":"AVRO"}`
+
+	server := setupTestServer(t)
+	req := httptest.NewRequest("POST", "/subjects/orders-value/versions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected validation to be off by default, got %d: %s", w.Code, w.Body.String())
+	}
+
+	server = setupTestServer(t)
+	server.config.Server.RequestValidation = "strict"
+	server.setupRouter()
+	req = httptest.NewRequest("POST", "/subjects/orders-value/versions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected strict validation to reject the typoed field, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -9,17 +9,22 @@ import (
 	"log/slog"
 	"net/http"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/handlers"
 	"github.com/axonops/axonops-schema-registry/internal/auth"
 	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/health"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
 	"github.com/axonops/axonops-schema-registry/internal/metrics"
 	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/usage"
 )
 
 // Server represents the HTTP server.
@@ -35,8 +40,13 @@ type Server struct {
 	authService   *auth.Service
 	rateLimiter   *auth.RateLimiter
 	auditLogger   *auth.AuditLogger
+	jobs          *jobs.Manager
+	usage         *usage.Tracker
+	clients       *usage.ClientTracker
 	tlsConfig     *tls.Config      // pre-built TLS config (nil = no TLS)
 	tlsManager    *auth.TLSManager // for certificate reloading
+	healthProbes  []healthProbe
+	reloader      handlers.ConfigReloader
 	version       string
 	commit        string
 }
@@ -93,6 +103,51 @@ func WithMetrics(m *metrics.Metrics) ServerOption {
 	}
 }
 
+// WithJobManager sets the async job manager served under /jobs.
+func WithJobManager(m *jobs.Manager) ServerOption {
+	return func(s *Server) {
+		s.jobs = m
+	}
+}
+
+// WithUsageTracker enables schema usage tracking: schema fetches are counted
+// by the tracker and reported by GET /admin/usage/schemas.
+func WithUsageTracker(t *usage.Tracker) ServerOption {
+	return func(s *Server) {
+		s.usage = t
+	}
+}
+
+// WithClientTracker enables client analytics: requests are counted by client
+// library, endpoint and principal and reported by GET /admin/usage/clients.
+func WithClientTracker(t *usage.ClientTracker) ServerOption {
+	return func(s *Server) {
+		s.clients = t
+	}
+}
+
+// WithConfigReloader enables POST /admin/config/reload, which reloads the
+// configuration file like SIGHUP.
+func WithConfigReloader(r handlers.ConfigReloader) ServerOption {
+	return func(s *Server) {
+		s.reloader = r
+	}
+}
+
+// healthProbe is a dependency check added with WithHealthProbe.
+type healthProbe struct {
+	name  string
+	probe health.Probe
+}
+
+// WithHealthProbe adds a dependency to GET /health/ready. The dependency
+// only decides readiness when named in server.health.critical.
+func WithHealthProbe(name string, probe health.Probe) ServerOption {
+	return func(s *Server) {
+		s.healthProbes = append(s.healthProbes, healthProbe{name, probe})
+	}
+}
+
 // NewServer creates a new HTTP server.
 func NewServer(cfg *config.Config, reg *registry.Registry, logger *slog.Logger, opts ...ServerOption) *Server {
 	s := &Server{
@@ -111,7 +166,9 @@ func NewServer(cfg *config.Config, reg *registry.Registry, logger *slog.Logger,
 		s.metrics = metrics.New()
 	}
 
-	// Wire metrics to auth components so they can record Prometheus metrics.
+	// Wire metrics to the registry and auth components so they can record
+	// Prometheus metrics.
+	reg.SetMetrics(s.metrics)
 	if s.authenticator != nil {
 		s.authenticator.SetMetrics(s.metrics)
 	}
@@ -126,6 +183,17 @@ func NewServer(cfg *config.Config, reg *registry.Registry, logger *slog.Logger,
 	return s
 }
 
+// buildHealthChecker creates the readiness checker: storage plus the
+// dependencies added with WithHealthProbe.
+func (s *Server) buildHealthChecker() *health.Checker {
+	timeout := time.Duration(s.config.Server.Health.Timeout) * time.Second
+	checker := handlers.NewHealthChecker(s.registry, timeout, s.logger)
+	for _, p := range s.healthProbes {
+		checker.Register(p.name, slices.Contains(s.config.Server.Health.Critical, p.name), p.probe)
+	}
+	return checker
+}
+
 // Metrics returns the metrics instance for recording custom metrics.
 func (s *Server) Metrics() *metrics.Metrics {
 	return s.metrics
@@ -140,8 +208,10 @@ func (s *Server) setupRouter() {
 	r.NotFound(notFoundHandler)
 
 	// Common middleware for all routes
+	r.Use(peerAddrMiddleware)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	r.Use(tracingMiddleware)
 	r.Use(s.loggingMiddleware)
 	if s.auditLogger != nil {
 		r.Use(s.auditLogger.Middleware)
@@ -182,21 +252,47 @@ func (s *Server) setupRouter() {
 		ClusterID: s.config.Server.ClusterID,
 		Version:   s.version,
 		Commit:    s.commit,
+
+		MaxVersionsPageSize: s.config.Server.MaxVersionsPageSize,
+		MaxListPageSize:     s.config.Server.MaxListPageSize,
+
+		Fingerprint: s.config.Fingerprints.Algorithm,
 	})
 	h.SetMetrics(s.metrics)
 	h.SetAuditLogger(s.auditLogger)
+	if s.authorizer != nil {
+		h.SetAuthorizer(s.authorizer)
+	}
+	h.SetJobManager(s.jobs)
+	h.SetUsageTracker(s.usage)
+	h.SetHealthChecker(s.buildHealthChecker(), s.config.Server.Health.ShowErrors)
+
+	validator := s.buildRequestValidator()
+	idempotency := s.buildIdempotencyCache()
 
-	// Public endpoints (no auth required) - health checks, metrics, and documentation
+	// Public endpoints (no auth required) - health checks, capabilities, metrics, and documentation
 	r.Get("/", h.HealthCheck)
 	r.Get("/health/live", h.LivenessCheck)
 	r.Get("/health/ready", h.ReadinessCheck)
 	r.Get("/health/startup", h.StartupCheck)
-	r.Get("/metrics", func(w http.ResponseWriter, r *http.Request) {
+	r.Get("/capabilities", capabilitiesHandler(s.capabilities()))
+	// /metrics has its own optional scrape token and network allow-list.
+	metricsGuard := newMetricsAccess(s.config.Security.Metrics)
+	r.With(metricsGuard.middleware).Get("/metrics", func(w http.ResponseWriter, r *http.Request) {
 		s.metrics.Handler().ServeHTTP(w, r)
 	})
 	if s.config.Server.DocsEnabled {
 		r.Get("/docs", handleSwaggerUI)
 		r.Get("/openapi.yaml", handleOpenAPISpec)
+		r.Get("/openapi.json", handleOpenAPIJSON)
+	}
+
+	// Login session refresh and revocation authenticate with the refresh
+	// token in the request body.
+	if s.authService != nil && s.authService.SessionsEnabled() {
+		sessionHandler := handlers.NewSessionHandler(s.authService)
+		r.Post("/auth/refresh", sessionHandler.Refresh)
+		r.Post("/auth/revoke", sessionHandler.Revoke)
 	}
 
 	// Protected routes group (auth required when configured)
@@ -204,6 +300,30 @@ func (s *Server) setupRouter() {
 		// Add auth middleware if configured
 		if s.authenticator != nil {
 			r.Use(s.authenticator.Middleware)
+			r.Use(principalMiddleware)
+		}
+
+		// Count requests per client library once the principal is known
+		if s.clients != nil {
+			r.Use(clientTrackingMiddleware(s.clients))
+		}
+
+		// Confine share tokens to the context or subject they were issued for
+		if s.authenticator != nil {
+			r.Use(shareScopeMiddleware(s.registry))
+		}
+
+		// Confine users who must change their password to their account
+		if s.authenticator != nil {
+			r.Use(passwordChangeMiddleware)
+		}
+
+		// Confine tenant users to the contexts their tenant owns
+		if s.authenticator != nil && s.config.Tenancy.Enabled {
+			if s.config.Tenancy.ContextMode == "principal" {
+				r.Use(tenantContextMiddleware(s.registry, s.config.Tenancy.OverrideHeader, s.logger))
+			}
+			r.Use(tenantScopeMiddleware(s.registry))
 		}
 
 		// Add authorization middleware if configured
@@ -216,48 +336,80 @@ func (s *Server) setupRouter() {
 			r.Use(s.rateLimiter.Middleware)
 		}
 
+		// Reject malformed JSON bodies before they reach a handler
+		if validator != nil {
+			r.Use(validator.middleware)
+		}
+
+		// Replay the first response to retried requests with an Idempotency-Key
+		if idempotency != nil {
+			r.Use(idempotency.middleware)
+		}
+
 		// Mount all schema registry routes at root level (default context)
 		s.mountRegistryRoutes(r, h)
 
-		// DEK Registry routes (Confluent CSFLE compatible).
-		// KEK/DEK endpoints are intentionally global (not mounted under /contexts/{context})
-		// because encryption keys are shared resources across all contexts, matching
-		// Confluent's behavior.
-		r.Route("/dek-registry/v1", func(r chi.Router) {
-			// KEK endpoints
-			r.Get("/keks", h.ListKEKs)
-			r.Post("/keks", h.CreateKEK)
-			r.Get("/keks/{name}", h.GetKEK)
-			r.Put("/keks/{name}", h.UpdateKEK)
-			r.Delete("/keks/{name}", h.DeleteKEK)
-			r.Post("/keks/{name}/undelete", h.UndeleteKEK)
-			r.Post("/keks/{name}/test", h.TestKEK)
-
-			// DEK endpoints
-			r.Get("/keks/{name}/deks", h.ListDEKs)
-			r.Post("/keks/{name}/deks", h.CreateDEK)
-			r.Get("/keks/{name}/deks/{subject}", h.GetDEK)
-			r.Post("/keks/{name}/deks/{subject}", h.CreateDEKWithSubject)
-			r.Delete("/keks/{name}/deks/{subject}", h.DeleteDEK)
-			r.Post("/keks/{name}/deks/{subject}/undelete", h.UndeleteDEK)
-			r.Get("/keks/{name}/deks/{subject}/versions", h.ListDEKVersions)
-			r.Get("/keks/{name}/deks/{subject}/versions/{version}", h.GetDEKVersion)
-			r.Delete("/keks/{name}/deks/{subject}/versions/{version}", h.DeleteDEKVersion)
-			r.Post("/keks/{name}/deks/{subject}/versions/{version}/undelete", h.UndeleteDEKVersion)
-		})
+		// Context management. Context-specific settings and deletion are
+		// registered on the /contexts/{context} subrouter below.
+		r.Post("/contexts", h.CreateContext)
+
+		// Declarative apply. The desired state spans contexts, so it is not
+		// mounted under /contexts/{context}.
+		r.Post("/apply", h.Apply)
+
+		// DEK Registry routes (Confluent CSFLE compatible)
+		mountDEKRoutes(r, h)
+
+		// Async jobs. Jobs are global: a job records the context it works
+		// on, but the endpoints are not mounted under /contexts/{context}.
+		r.Get("/jobs", h.ListJobs)
+		r.Get("/jobs/{id}", h.GetJob)
+		r.Get("/jobs/{id}/result", h.GetJobResult)
+		r.Post("/jobs/{id}/cancel", h.CancelJob)
 
 		// Account endpoints (self-service, requires auth)
 		if s.authService != nil {
 			accountHandler := handlers.NewAccountHandler(s.authService)
+			if s.authorizer != nil {
+				accountHandler.SetAuthorizer(s.authorizer)
+			}
 			r.Route("/me", func(r chi.Router) {
 				r.Get("/", accountHandler.GetCurrentUser)
 				r.Post("/password", accountHandler.ChangePassword)
+				r.Get("/permissions", accountHandler.GetPermissions)
+				r.Get("/apikeys", accountHandler.ListAPIKeys)
+				r.Post("/apikeys", accountHandler.CreateAPIKey)
+				r.Post("/apikeys/{id}/revoke", accountHandler.RevokeAPIKey)
 			})
 		}
 
+		// Login sessions (exchange a password or API key for tokens)
+		if s.authService != nil && s.authService.SessionsEnabled() {
+			r.Post("/auth/login", handlers.NewSessionHandler(s.authService).Login)
+		}
+
 		// Admin endpoints (requires auth)
 		if s.authService != nil && s.authorizer != nil {
 			adminHandler := handlers.NewAdminHandler(s.authService, s.authorizer)
+			if s.auditLogger != nil {
+				adminHandler.SetAuditHistory(s.auditLogger.History())
+			}
+			if s.usage != nil {
+				adminHandler.SetSchemaUsage(s.usage)
+			}
+			if s.clients != nil {
+				adminHandler.SetClientUsage(s.clients)
+			}
+			if s.config.Tenancy.Enabled {
+				adminHandler.SetTenants(s.registry)
+			}
+			adminHandler.SetMaintenance(s.registry)
+			adminHandler.SetPendingSchemas(s.registry)
+			adminHandler.SetQuotas(s.registry)
+			adminHandler.SetReferences(s.registry)
+			if s.reloader != nil {
+				adminHandler.SetConfigReloader(s.reloader)
+			}
 			r.Route("/admin", func(r chi.Router) {
 				// User management
 				r.Get("/users", adminHandler.ListUsers)
@@ -269,14 +421,71 @@ func (s *Server) setupRouter() {
 				// API Key management
 				r.Get("/apikeys", adminHandler.ListAPIKeys)
 				r.Post("/apikeys", adminHandler.CreateAPIKey)
+				r.Get("/apikeys/usage", adminHandler.GetAPIKeyUsage)
 				r.Get("/apikeys/{id}", adminHandler.GetAPIKey)
 				r.Put("/apikeys/{id}", adminHandler.UpdateAPIKey)
 				r.Delete("/apikeys/{id}", adminHandler.DeleteAPIKey)
 				r.Post("/apikeys/{id}/revoke", adminHandler.RevokeAPIKey)
 				r.Post("/apikeys/{id}/rotate", adminHandler.RotateAPIKey)
 
+				// Role grants (scoped RBAC bindings)
+				r.Get("/grants", adminHandler.ListGrants)
+				r.Post("/grants", adminHandler.CreateGrant)
+				r.Get("/grants/{id}", adminHandler.GetGrant)
+				r.Delete("/grants/{id}", adminHandler.DeleteGrant)
+
+				// Read-only share tokens for external partners
+				r.Get("/share-tokens", adminHandler.ListShareTokens)
+				r.Post("/share-tokens", adminHandler.CreateShareToken)
+				r.Get("/share-tokens/{id}", adminHandler.GetShareToken)
+				r.Delete("/share-tokens/{id}", adminHandler.DeleteShareToken)
+
 				// Roles
 				r.Get("/roles", adminHandler.ListRoles)
+
+				// Audit history
+				r.Get("/audit", adminHandler.QueryAuditEvents)
+
+				// Schema usage analytics
+				r.Get("/usage/schemas", adminHandler.GetSchemaUsage)
+				r.Get("/usage/clients", adminHandler.GetClientUsage)
+
+				// Auth cache consistency
+				r.Get("/auth-cache", adminHandler.GetAuthCacheReport)
+				r.Post("/auth-cache/refresh", adminHandler.RefreshAuthCache)
+
+				// Scheduled maintenance windows (READONLY_OVERRIDE)
+				r.Get("/maintenance", adminHandler.ListMaintenanceWindows)
+				r.Post("/maintenance", adminHandler.ScheduleMaintenance)
+				r.Get("/maintenance/{id}", adminHandler.GetMaintenanceWindow)
+				r.Delete("/maintenance/{id}", adminHandler.CancelMaintenance)
+
+				// Registrations held for approval
+				r.Get("/pending-schemas", adminHandler.ListPendingSchemas)
+				r.Get("/pending-schemas/{id}", adminHandler.GetPendingSchema)
+				r.Post("/pending-schemas/{id}/approve", adminHandler.ApprovePendingSchema)
+				r.Post("/pending-schemas/{id}/reject", adminHandler.RejectPendingSchema)
+
+				// Registration quotas
+				r.Get("/quotas", adminHandler.ListQuotas)
+				r.Post("/quotas/{name}/reset", adminHandler.ResetQuota)
+
+				// Dangling schema references
+				r.Get("/references/orphans", adminHandler.ListOrphanedReferences)
+
+				// Configuration reload
+				if s.reloader != nil {
+					r.Post("/config/reload", adminHandler.ReloadConfig)
+				}
+
+				// Tenants (hard multi-tenancy)
+				if s.config.Tenancy.Enabled {
+					r.Get("/tenants", adminHandler.ListTenants)
+					r.Post("/tenants", adminHandler.CreateTenant)
+					r.Get("/tenants/{name}", adminHandler.GetTenant)
+					r.Put("/tenants/{name}", adminHandler.UpdateTenant)
+					r.Delete("/tenants/{name}", adminHandler.DeleteTenant)
+				}
 			})
 		}
 	})
@@ -289,6 +498,30 @@ func (s *Server) setupRouter() {
 		// Add auth middleware if configured
 		if s.authenticator != nil {
 			r.Use(s.authenticator.Middleware)
+			r.Use(principalMiddleware)
+		}
+
+		// Count requests per client library once the principal is known
+		if s.clients != nil {
+			r.Use(clientTrackingMiddleware(s.clients))
+		}
+
+		// Confine share tokens to the context or subject they were issued for
+		if s.authenticator != nil {
+			r.Use(shareScopeMiddleware(s.registry))
+		}
+
+		// Confine users who must change their password to their account
+		if s.authenticator != nil {
+			r.Use(passwordChangeMiddleware)
+		}
+
+		// Confine tenant users to the contexts their tenant owns
+		if s.authenticator != nil && s.config.Tenancy.Enabled {
+			if s.config.Tenancy.ContextMode == "principal" {
+				r.Use(tenantContextMiddleware(s.registry, s.config.Tenancy.OverrideHeader, s.logger))
+			}
+			r.Use(tenantScopeMiddleware(s.registry))
 		}
 
 		// Add authorization middleware if configured
@@ -301,13 +534,61 @@ func (s *Server) setupRouter() {
 			r.Use(s.rateLimiter.Middleware)
 		}
 
+		// Reject malformed JSON bodies before they reach a handler
+		if validator != nil {
+			r.Use(validator.middleware)
+		}
+
+		// Replay the first response to retried requests with an Idempotency-Key
+		if idempotency != nil {
+			r.Use(idempotency.middleware)
+		}
+
+		// Context management (these routes only exist under the context prefix)
+		r.Delete("/", h.DeleteContext)
+		r.Get("/settings", h.GetContextSettings)
+		r.Put("/settings", h.UpdateContextSettings)
+
 		// Mount schema registry routes under context prefix
 		s.mountRegistryRoutes(r, h)
+
+		// Serializers configured with a context URL reach the DEK
+		// Registry through the prefix as well
+		mountDEKRoutes(r, h)
 	})
 
 	s.router = r
 }
 
+// mountDEKRoutes registers the DEK Registry routes on the given router. This
+// is called at root level and under /contexts/{context}, but keys are
+// intentionally global: encryption keys are shared resources across all
+// contexts, matching Confluent's behavior, so the context prefix is ignored.
+func mountDEKRoutes(r chi.Router, h *handlers.Handler) {
+	r.Route("/dek-registry/v1", func(r chi.Router) {
+		// KEK endpoints
+		r.Get("/keks", h.ListKEKs)
+		r.Post("/keks", h.CreateKEK)
+		r.Get("/keks/{name}", h.GetKEK)
+		r.Put("/keks/{name}", h.UpdateKEK)
+		r.Delete("/keks/{name}", h.DeleteKEK)
+		r.Post("/keks/{name}/undelete", h.UndeleteKEK)
+		r.Post("/keks/{name}/test", h.TestKEK)
+
+		// DEK endpoints
+		r.Get("/keks/{name}/deks", h.ListDEKs)
+		r.Post("/keks/{name}/deks", h.CreateDEK)
+		r.Get("/keks/{name}/deks/{subject}", h.GetDEK)
+		r.Post("/keks/{name}/deks/{subject}", h.CreateDEKWithSubject)
+		r.Delete("/keks/{name}/deks/{subject}", h.DeleteDEK)
+		r.Post("/keks/{name}/deks/{subject}/undelete", h.UndeleteDEK)
+		r.Get("/keks/{name}/deks/{subject}/versions", h.ListDEKVersions)
+		r.Get("/keks/{name}/deks/{subject}/versions/{version}", h.GetDEKVersion)
+		r.Delete("/keks/{name}/deks/{subject}/versions/{version}", h.DeleteDEKVersion)
+		r.Post("/keks/{name}/deks/{subject}/versions/{version}/undelete", h.UndeleteDEKVersion)
+	})
+}
+
 // mountRegistryRoutes registers all schema registry API routes on the given router.
 // This is called twice: once at root level (default context) and once under /contexts/{context}.
 func (s *Server) mountRegistryRoutes(r chi.Router, h *handlers.Handler) {
@@ -322,18 +603,58 @@ func (s *Server) mountRegistryRoutes(r chi.Router, h *handlers.Handler) {
 	r.Get("/schemas/ids/{id}/schema", h.GetRawSchemaByID)
 	r.Get("/schemas/ids/{id}/subjects", h.GetSubjectsBySchemaID)
 	r.Get("/schemas/ids/{id}/versions", h.GetVersionsBySchemaID)
+	r.Get("/schemas/ids/{id}/fingerprint", h.GetSchemaFingerprintByID)
+
+	// Schemas by fingerprint, across subjects
+	r.Get("/schemas/fingerprint/{fingerprint}", h.GetSchemaByFingerprint)
+	r.Post("/schemas/lookup-global", h.LookupSchemaGlobal)
 
 	// Subjects
 	r.Get("/subjects", h.ListSubjects)
 	r.Get("/subjects/{subject}/versions", h.GetVersions)
+	r.Get("/subjects/{subject}/versions/compatible-with", h.GetCompatibleVersions)
 	r.Get("/subjects/{subject}/versions/{version}", h.GetVersion)
 	r.Get("/subjects/{subject}/versions/{version}/schema", h.GetRawSchemaByVersion)
 	r.Get("/subjects/{subject}/versions/{version}/referencedby", h.GetReferencedBy)
+	r.Post("/subjects/{subject}/versions/{version}/validate-payload", h.ValidatePayload)
 	r.Post("/subjects/{subject}/versions", h.RegisterSchema)
+	r.Put("/subjects/{subject}/versions/{fingerprint}", h.RegisterSchemaIfAbsent)
 	r.Post("/subjects/{subject}", h.LookupSchema)
 	r.Delete("/subjects/{subject}", h.DeleteSubject)
 	r.Delete("/subjects/{subject}/versions/{version}", h.DeleteVersion)
 	r.Get("/subjects/{subject}/metadata", h.GetSubjectMetadata)
+	r.Get("/subjects/{subject}/bundle", h.GetSchemaBundle)
+
+	// Topics
+	r.Get("/topics/{topic}/subjects", h.GetTopicSubjects)
+
+	// Subject catalog (subjects with their latest schema, config and mode)
+	r.Get("/catalog/subjects", h.ListSubjectCatalog)
+
+	// Tags and labels on subjects and versions, and search over them
+	r.Get("/subjects/{subject}/tags", h.GetTags)
+	r.Put("/subjects/{subject}/tags", h.SetTags)
+	r.Delete("/subjects/{subject}/tags", h.DeleteTags)
+	r.Get("/subjects/{subject}/versions/{version}/tags", h.GetTags)
+	r.Put("/subjects/{subject}/versions/{version}/tags", h.SetTags)
+	r.Delete("/subjects/{subject}/versions/{version}/tags", h.DeleteTags)
+	r.Get("/search/schemas", h.FindSchemas)
+
+	// Owner, description, contact and documentation links of subjects
+	r.Get("/subjects/{subject}/meta", h.GetSubjectMeta)
+	r.Put("/subjects/{subject}/meta", h.SetSubjectMeta)
+	r.Delete("/subjects/{subject}/meta", h.DeleteSubjectMeta)
+
+	// Frozen versions, protected from deletion
+	r.Get("/subjects/{subject}/versions/{version}/freeze", h.GetFrozenVersion)
+	r.Put("/subjects/{subject}/versions/{version}/freeze", h.FreezeVersion)
+	r.Delete("/subjects/{subject}/versions/{version}/freeze", h.UnfreezeVersion)
+
+	// Reader schemas consumers declare for the subjects they read
+	r.Get("/readers", h.ListReaders)
+	r.Get("/subjects/{subject}/readers", h.ListReaders)
+	r.Put("/subjects/{subject}/readers/{consumer}", h.SetReader)
+	r.Delete("/subjects/{subject}/readers/{consumer}", h.DeleteReader)
 
 	// Config
 	r.Get("/config", h.GetConfig)
@@ -353,10 +674,28 @@ func (s *Server) mountRegistryRoutes(r chi.Router, h *handlers.Handler) {
 
 	// Import (for migration from other schema registries)
 	r.Post("/import/schemas", h.ImportSchemas)
+	r.Post("/import/bundle", h.ImportBundle)
+	r.Get("/import/sessions", h.ListImportSessions)
+	r.Post("/import/sessions", h.OpenImportSession)
+	r.Get("/import/sessions/{id}", h.GetImportSession)
+	r.Post("/import/sessions/{id}/schemas", h.StageImportSchemas)
+	r.Put("/import/sessions/{id}/commit", h.CommitImportSession)
+	r.Put("/import/sessions/{id}/abort", h.AbortImportSession)
+	r.Get("/import/id-aliases", h.ListIDAliases)
+	r.Post("/import/id-aliases", h.SetIDAliases)
+	r.Delete("/import/id-aliases/{source}/{sourceId}", h.DeleteIDAlias)
 
 	// Compatibility
 	r.Post("/compatibility/subjects/{subject}/versions/{version}", h.CheckCompatibility)
 	r.Post("/compatibility/subjects/{subject}/versions", h.CheckCompatibility)
+	r.Post("/compatibility/subjects/{subject}/readers", h.CheckReaders)
+	r.Post("/compatibility/revalidate", h.RevalidateCompatibility)
+
+	// Storage consistency
+	r.Post("/consistency/verify", h.VerifyConsistency)
+
+	// Encryption at rest
+	r.Post("/encryption/reencrypt", h.ReencryptSchemas)
 
 	// Contexts
 	r.Get("/contexts", h.GetContexts)
@@ -381,6 +720,7 @@ func (s *Server) mountRegistryRoutes(r chi.Router, h *handlers.Handler) {
 	// Analysis & Intelligence endpoints (mirror MCP tools)
 	r.Post("/schemas/validate", h.ValidateSchema)
 	r.Post("/schemas/normalize", h.NormalizeSchema)
+	r.Post("/schemas/convert", h.ConvertSchema)
 	r.Post("/schemas/search", h.SearchSchemas)
 	r.Post("/schemas/search/field", h.FindSchemasByField)
 	r.Post("/schemas/search/type", h.FindSchemasByType)
@@ -394,6 +734,7 @@ func (s *Server) mountRegistryRoutes(r chi.Router, h *handlers.Handler) {
 	r.Get("/subjects/{subject}/versions/{version}/dependencies", h.GetDependencyGraph)
 	r.Get("/subjects/{subject}/versions/{version}/export", h.ExportSchema)
 	r.Post("/subjects/{subject}/diff", h.DiffSchemas)
+	r.Get("/subjects/{subject}/versions/{version}/diff/{version2}", h.DiffVersions)
 	r.Post("/subjects/{subject}/evolve", h.SuggestSchemaEvolution)
 	r.Post("/subjects/{subject}/migrate", h.PlanMigrationPath)
 	r.Get("/subjects/{subject}/export", h.ExportSubject)
@@ -407,24 +748,43 @@ func (s *Server) mountRegistryRoutes(r chi.Router, h *handlers.Handler) {
 	r.Get("/statistics/patterns", h.DetectSchemaPatterns)
 }
 
-// loggingMiddleware logs HTTP requests.
+// loggingMiddleware logs HTTP requests. Besides the request line, status
+// and duration, the log records the authenticated principal, the subject
+// the request addressed and the trace and span IDs, so that a slow request
+// can be found in the trace backend.
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ctx, rl := withRequestLog(r.Context())
 
 		defer func() {
-			s.logger.Info("request",
+			attrs := []slog.Attr{
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.Int("status", ww.Status()),
 				slog.Duration("duration", time.Since(start)),
 				slog.String("remote", r.RemoteAddr),
-				slog.String("request_id", middleware.GetReqID(r.Context())),
-			)
+				slog.String("request_id", middleware.GetReqID(ctx)),
+			}
+			if rl.principal != "" {
+				attrs = append(attrs, slog.String("principal", rl.principal))
+			}
+			if rctx := chi.RouteContext(ctx); rctx != nil {
+				if subject := rctx.URLParam("subject"); subject != "" {
+					attrs = append(attrs, slog.String("subject", subject))
+				}
+			}
+			if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+				attrs = append(attrs,
+					slog.String("trace_id", sc.TraceID().String()),
+					slog.String("span_id", sc.SpanID().String()),
+				)
+			}
+			s.logger.LogAttrs(ctx, slog.LevelInfo, "request", attrs...)
 		}()
 
-		next.ServeHTTP(ww, r)
+		next.ServeHTTP(ww, r.WithContext(ctx))
 	})
 }
 
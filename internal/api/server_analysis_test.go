@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
@@ -160,6 +161,19 @@ func TestAnalysis_NormalizeSchema(t *testing.T) {
 		if result["fingerprint"] == nil || result["fingerprint"] == "" {
 			t.Error("Expected non-empty fingerprint")
 		}
+		if result["unnormalized_fingerprint"] != result["fingerprint"] || result["changed"] != false {
+			t.Errorf("Expected the default profile to leave the fingerprint unchanged, got %v", result)
+		}
+	})
+
+	t.Run("unresolved reference", func(t *testing.T) {
+		w := doAnalysisRequest(t, server, "POST", "/schemas/normalize", map[string]interface{}{
+			"schema":     `{"type":"record","name":"Ref","fields":[{"name":"base","type":"test.Base"}]}`,
+			"references": []map[string]interface{}{{"name": "test.Base", "subject": "no-such-subject", "version": 1}},
+		})
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422, got %d: %s", w.Code, w.Body.String())
+		}
 	})
 
 	t.Run("invalid schema", func(t *testing.T) {
@@ -692,6 +706,60 @@ func TestAnalysis_DiffSchemas(t *testing.T) {
 	})
 }
 
+func TestAnalysis_DiffVersions(t *testing.T) {
+	server := setupAnalysisTestServer(t)
+	setAnalysisConfig(t, server, "NONE")
+
+	registerTestSchema(t, server, "vdiff-test", `{"type":"record","name":"Diff","fields":[{"name":"id","type":"int"},{"name":"old_field","type":"string"}]}`)
+	registerTestSchema(t, server, "vdiff-test", `{"type":"record","name":"Diff","fields":[{"name":"id","type":"long","doc":"Primary key"},{"name":"new_field","type":"string"}]}`)
+
+	t.Run("structured changes", func(t *testing.T) {
+		w := doAnalysisRequest(t, server, "GET", "/subjects/vdiff-test/versions/1/diff/latest", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		result := parseAnalysisResponse(t, w)
+		if result["version1"] != float64(1) || result["version2"] != float64(2) {
+			t.Errorf("Expected versions 1 and 2, got %v and %v", result["version1"], result["version2"])
+		}
+		summary := result["summary"].(map[string]interface{})
+		if summary["added"] != float64(1) || summary["removed"] != float64(1) || summary["changed"] != float64(1) {
+			t.Errorf("Unexpected summary %v", summary)
+		}
+		kinds := map[string]bool{}
+		for _, c := range result["changes"].([]interface{}) {
+			change := c.(map[string]interface{})
+			kinds[change["field"].(string)+":"+change["change"].(string)] = true
+		}
+		for _, want := range []string{"id:type_changed", "id:doc_changed", "old_field:removed", "new_field:added"} {
+			if !kinds[want] {
+				t.Errorf("Expected change %s in %v", want, kinds)
+			}
+		}
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		w := doAnalysisRequest(t, server, "GET", "/subjects/vdiff-test/versions/1/diff/abc", nil)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422, got %d", w.Code)
+		}
+	})
+
+	t.Run("version not found", func(t *testing.T) {
+		w := doAnalysisRequest(t, server, "GET", "/subjects/vdiff-test/versions/1/diff/9", nil)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("subject not found", func(t *testing.T) {
+		w := doAnalysisRequest(t, server, "GET", "/subjects/nonexistent/versions/1/diff/2", nil)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", w.Code)
+		}
+	})
+}
+
 func TestAnalysis_SuggestSchemaEvolution(t *testing.T) {
 	server := setupAnalysisTestServer(t)
 
@@ -1089,3 +1157,45 @@ func setAnalysisSubjectConfig(t *testing.T, server *Server, subject, level strin
 		t.Fatalf("Failed to set subject config to %s: %d %s", level, w.Code, w.Body.String())
 	}
 }
+
+func TestServer_ValidatePayload(t *testing.T) {
+	server := setupAnalysisTestServer(t)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	schemaStr := `{"type":"object","properties":{"id":{"type":"integer"}},"required":["id"]}`
+	body, _ := json.Marshal(map[string]string{"schemaType": "JSON", "schema": schemaStr})
+	if w := do("POST", "/subjects/orders-value/versions", string(body)); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 registering, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := do("POST", "/subjects/orders-value/versions/latest/validate-payload", `{"id":12345678901234567890}`)
+	var resp types.ValidatePayloadResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || !resp.Valid || resp.Version != 1 || len(resp.Errors) != 0 {
+		t.Errorf("Expected a valid payload, got %d: %+v", w.Code, resp)
+	}
+
+	w = do("POST", "/subjects/orders-value/versions/1/validate-payload", `{"id":"x"}`)
+	resp = types.ValidatePayloadResponse{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].InstanceLocation != "/id" {
+		t.Errorf("Expected one problem at /id, got %d: %+v", w.Code, resp)
+	}
+
+	if w := do("POST", "/subjects/orders-value/versions/1/validate-payload", `{"id":1} {}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for more than one document, got %d", w.Code)
+	}
+	if w := do("POST", "/subjects/orders-value/versions/2/validate-payload", `{}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing version, got %d", w.Code)
+	}
+	if w := do("POST", "/subjects/avro-value/versions", `{"schema":"\"string\""}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 registering, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/subjects/avro-value/versions/1/validate-payload", `"x"`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an Avro subject, got %d", w.Code)
+	}
+}
@@ -1,19 +1,26 @@
 package api
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/axonops/axonops-schema-registry/internal/api/types"
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
 	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
 	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
 	"github.com/axonops/axonops-schema-registry/internal/registry"
 	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
@@ -52,6 +59,36 @@ func TestServer_HealthCheck(t *testing.T) {
 	}
 }
 
+func TestServer_GetCapabilities(t *testing.T) {
+	server := setupTestServer(t)
+
+	req := httptest.NewRequest("GET", "/capabilities", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var caps types.CapabilitiesResponse
+	if err := json.NewDecoder(w.Body).Decode(&caps); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(caps.SchemaTypes) != 1 || caps.SchemaTypes[0] != "AVRO" {
+		t.Errorf("Expected schema types [AVRO], got %v", caps.SchemaTypes)
+	}
+	if !caps.Features.Contexts || !caps.Features.ImportMode || !caps.Pagination.OffsetLimit {
+		t.Errorf("Expected contexts, import mode and pagination to be reported, got %+v", caps)
+	}
+	if caps.Features.Jobs || caps.Features.Tenancy {
+		t.Errorf("Expected jobs and tenancy to be off, got %+v", caps.Features)
+	}
+	if caps.Auth.Enabled || len(caps.Auth.Methods) != 0 {
+		t.Errorf("Expected auth to be off, got %+v", caps.Auth)
+	}
+}
+
 func TestServer_GetSchemaTypes(t *testing.T) {
 	server := setupTestServer(t)
 
@@ -121,6 +158,494 @@ func TestServer_RegisterAndGetSchema(t *testing.T) {
 	}
 }
 
+func TestServer_SchemaBundle(t *testing.T) {
+	server := setupTestServer(t)
+
+	register := func(subject, schema string, refs []storage.Reference) {
+		t.Helper()
+		bodyBytes, _ := json.Marshal(types.RegisterSchemaRequest{Schema: schema, References: refs})
+		req := httptest.NewRequest("POST", "/subjects/"+subject+"/versions", bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("register %s: expected 200, got %d: %s", subject, w.Code, w.Body.String())
+		}
+	}
+	register("address", `{"type":"record","name":"Address","namespace":"geo","fields":[{"name":"city","type":"string"}]}`, nil)
+	register("user-value", `{"type":"record","name":"User","namespace":"app","fields":[{"name":"home","type":"geo.Address"}]}`,
+		[]storage.Reference{{Name: "geo.Address", Subject: "address", Version: 1}})
+
+	t.Run("zip", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("GET", "/subjects/user-value/bundle?version=latest", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+			t.Errorf("Expected application/zip, got %s", ct)
+		}
+		if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="user-value-v1.zip"` {
+			t.Errorf("Unexpected Content-Disposition %q", cd)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		if err != nil {
+			t.Fatalf("Failed to read zip: %v", err)
+		}
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		if len(names) != 2 || names[0] != "app.User.avsc" || names[1] != "geo.Address.avsc" {
+			t.Errorf("Unexpected zip entries %v", names)
+		}
+	})
+
+	t.Run("tar", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("GET", "/subjects/address/bundle?format=tar", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		tr := tar.NewReader(bytes.NewReader(w.Body.Bytes()))
+		hdr, err := tr.Next()
+		if err != nil {
+			t.Fatalf("Failed to read tar: %v", err)
+		}
+		content, _ := io.ReadAll(tr)
+		if hdr.Name != "geo.Address.avsc" || !bytes.Contains(content, []byte(`"Address"`)) {
+			t.Errorf("Unexpected tar entry %s: %s", hdr.Name, content)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			path string
+			code int
+		}{
+			{"/subjects/missing/bundle", http.StatusNotFound},
+			{"/subjects/user-value/bundle?version=9", http.StatusNotFound},
+			{"/subjects/user-value/bundle?version=abc", http.StatusUnprocessableEntity},
+			{"/subjects/user-value/bundle?format=rar", http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.code {
+				t.Errorf("%s: expected %d, got %d", tt.path, tt.code, w.Code)
+			}
+		}
+	})
+}
+
+func TestServer_ImportSession(t *testing.T) {
+	server := setupTestServer(t)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do("POST", "/import/sessions", `{"subjects":["orders-value"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var session types.ImportSessionResponse
+	json.NewDecoder(w.Body).Decode(&session)
+	if session.ID == "" || session.State != "OPEN" {
+		t.Fatalf("Unexpected session: %+v", session)
+	}
+	base := "/import/sessions/" + session.ID
+
+	// Ordinary registration is blocked while the subject is being imported.
+	if w := do("POST", "/subjects/orders-value/versions", `{"schema":"\"string\""}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for registration during the session, got %d", w.Code)
+	}
+	if w := do("POST", "/import/sessions", `{"subjects":["orders-value"]}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a second session on the subject, got %d", w.Code)
+	}
+
+	w = do("POST", base+"/schemas", `{"schemas":[{"id":7,"subject":"orders-value","version":1,"schema":"\"string\""}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 staging schemas, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("GET", "/subjects/orders-value/versions/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected staged schema to be invisible before commit, got %d", w.Code)
+	}
+
+	w = do("PUT", base+"/commit", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 committing, got %d: %s", w.Code, w.Body.String())
+	}
+	var commit types.ImportSessionCommitResponse
+	json.NewDecoder(w.Body).Decode(&commit)
+	if commit.Imported != 1 || commit.Session.State != "COMMITTED" {
+		t.Errorf("Unexpected commit response: %+v", commit)
+	}
+	if w := do("GET", "/subjects/orders-value/versions/1", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected committed schema, got %d", w.Code)
+	}
+	if w := do("GET", "/mode/orders-value", ""); strings.Contains(w.Body.String(), "IMPORT") {
+		t.Errorf("Expected IMPORT mode to be lifted after commit, got %s", w.Body.String())
+	}
+	if w := do("PUT", base+"/abort", ""); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 aborting a committed session, got %d", w.Code)
+	}
+	if w := do("GET", "/import/sessions/unknown", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown session, got %d", w.Code)
+	}
+}
+
+func TestServer_IDAliases(t *testing.T) {
+	server := setupTestServer(t)
+	do := func(method, path, body string, header ...string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	setGlobalMode(t, server, "IMPORT")
+	w := do("POST", "/import/schemas", `{"schemas":[{"id":101,"subject":"orders-value","version":1,"schema":"\"string\""}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 importing, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do("POST", "/import/id-aliases", `{"source":"us-east","aliases":[{"source_id":1,"id":101}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 setting aliases, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/import/id-aliases", `{"source":"us-east","aliases":[{"source_id":2,"id":999}]}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an alias to an unknown schema, got %d", w.Code)
+	}
+
+	for _, w := range []*httptest.ResponseRecorder{
+		do("GET", "/schemas/ids/1?source=us-east", ""),
+		do("GET", "/schemas/ids/1", "", "X-Registry-Source", "us-east"),
+	} {
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "string") {
+			t.Errorf("Expected the aliased schema, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	if w := do("GET", "/schemas/ids/1/subjects?source=us-east", ""); !strings.Contains(w.Body.String(), "orders-value") {
+		t.Errorf("Expected subjects of the aliased schema, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("GET", "/schemas/ids/2?source=us-east", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an ID without an alias, got %d", w.Code)
+	}
+
+	w = do("GET", "/import/id-aliases?source=us-east", "")
+	var list types.IDAliasesResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	if len(list.Aliases) != 1 || list.Aliases[0].ID != 101 {
+		t.Errorf("Unexpected aliases: %+v", list)
+	}
+
+	if w := do("DELETE", "/import/id-aliases/us-east/1", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 deleting the alias, got %d", w.Code)
+	}
+	if w := do("DELETE", "/import/id-aliases/us-east/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 deleting a missing alias, got %d", w.Code)
+	}
+}
+
+func TestServer_FreezeVersion(t *testing.T) {
+	server := setupTestServer(t)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	if w := do("POST", "/subjects/orders-value/versions", `{"schema":"\"string\""}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 registering, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("GET", "/subjects/orders-value/versions/1/freeze", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a version that is not frozen, got %d", w.Code)
+	}
+
+	w := do("PUT", "/subjects/orders-value/versions/latest/freeze", `{"reason":"production"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 freezing, got %d: %s", w.Code, w.Body.String())
+	}
+	var frozen types.FrozenVersionResponse
+	json.NewDecoder(w.Body).Decode(&frozen)
+	if frozen.Version != 1 || frozen.Reason != "production" {
+		t.Errorf("Unexpected frozen version: %+v", frozen)
+	}
+	if w := do("PUT", "/subjects/orders-value/versions/2/freeze", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 freezing a missing version, got %d", w.Code)
+	}
+
+	for _, path := range []string{"/subjects/orders-value/versions/1", "/subjects/orders-value"} {
+		w := do("DELETE", path, "")
+		var errResp types.ErrorResponse
+		json.NewDecoder(w.Body).Decode(&errResp)
+		if w.Code != http.StatusUnprocessableEntity || errResp.ErrorCode != types.ErrorCodeVersionFrozen {
+			t.Errorf("DELETE %s: expected 422 with error code %d, got %d: %+v", path, types.ErrorCodeVersionFrozen, w.Code, errResp)
+		}
+	}
+
+	if w := do("DELETE", "/subjects/orders-value/versions/1/freeze", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 unfreezing, got %d", w.Code)
+	}
+	if w := do("DELETE", "/subjects/orders-value/versions/1", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 deleting the unfrozen version, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestServer_Readers(t *testing.T) {
+	server := setupTestServer(t)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	order := `{\"type\":\"record\",\"name\":\"Order\",\"fields\":[{\"name\":\"a\",\"type\":\"int\"}]}`
+	if w := do("POST", "/subjects/orders-value/versions", `{"schema":"`+order+`"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 registering, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := do("PUT", "/subjects/orders-value/readers/billing", `{"reader_version":2}`)
+	var errResp types.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&errResp)
+	if w.Code != http.StatusUnprocessableEntity || errResp.ErrorCode != types.ErrorCodeInvalidReader {
+		t.Errorf("Expected 422 with error code %d for a missing reader version, got %d: %+v", types.ErrorCodeInvalidReader, w.Code, errResp)
+	}
+
+	w = do("PUT", "/subjects/orders-value/readers/billing", `{"reader_version":-1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 setting a reader, got %d: %s", w.Code, w.Body.String())
+	}
+	var reader types.ReaderResponse
+	json.NewDecoder(w.Body).Decode(&reader)
+	if reader.Consumer != "billing" || reader.ReaderSubject != "orders-value" || reader.ReaderVersion != 1 {
+		t.Errorf("Unexpected reader: %+v", reader)
+	}
+
+	w = do("GET", "/readers?consumer=billing", "")
+	var readers types.ReadersResponse
+	json.NewDecoder(w.Body).Decode(&readers)
+	if w.Code != http.StatusOK || len(readers.Readers) != 1 || readers.Readers[0].Subject != "orders-value" {
+		t.Errorf("Expected the billing reader, got %d: %+v", w.Code, readers)
+	}
+
+	renamed := `{\"type\":\"record\",\"name\":\"Order\",\"fields\":[{\"name\":\"c\",\"type\":\"int\"}]}`
+	w = do("POST", "/compatibility/subjects/orders-value/readers", `{"schema":"`+renamed+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 checking readers, got %d: %s", w.Code, w.Body.String())
+	}
+	var check types.ReadersCompatibilityResponse
+	json.NewDecoder(w.Body).Decode(&check)
+	if check.IsCompatible || len(check.Readers) != 1 || check.Readers[0].IsCompatible || len(check.Readers[0].Messages) == 0 {
+		t.Errorf("Expected the billing reader to reject the schema, got %+v", check)
+	}
+
+	if w := do("DELETE", "/subjects/orders-value/readers/billing", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 deleting the reader, got %d", w.Code)
+	}
+	w = do("DELETE", "/subjects/orders-value/readers/billing", "")
+	json.NewDecoder(w.Body).Decode(&errResp)
+	if w.Code != http.StatusNotFound || errResp.ErrorCode != types.ErrorCodeReaderNotFound {
+		t.Errorf("Expected 404 with error code %d, got %d: %+v", types.ErrorCodeReaderNotFound, w.Code, errResp)
+	}
+}
+
+func TestServer_ImportBundle(t *testing.T) {
+	server := setupTestServer(t)
+
+	manifest := `{"schemas":[
+		{"file":"app.User.avsc","id":20,"subject":"user-value","version":1,
+		 "references":[{"name":"geo.Address","subject":"address","version":1}]},
+		{"file":"./geo/Address.avsc","id":10,"subject":"address","version":1}]}`
+	files := map[string]string{
+		"manifest.json":    manifest,
+		"app.User.avsc":    `{"type":"record","name":"User","namespace":"app","fields":[{"name":"home","type":"geo.Address"}]}`,
+		"geo/Address.avsc": `{"type":"record","name":"Address","namespace":"geo","fields":[{"name":"city","type":"string"}]}`,
+	}
+	zipOf := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			fw, _ := zw.Create(name)
+			fw.Write([]byte(content))
+		}
+		zw.Close()
+		return buf.Bytes()
+	}
+	post := func(path string, body []byte) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("POST", path, bytes.NewReader(body)))
+		return w
+	}
+
+	if w := post("/import/bundle", zipOf(files)); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422 outside IMPORT mode, got %d: %s", w.Code, w.Body.String())
+	}
+	setGlobalMode(t, server, "IMPORT")
+
+	t.Run("errors", func(t *testing.T) {
+		noManifest := map[string]string{"app.User.avsc": files["app.User.avsc"]}
+		missingFile := map[string]string{"manifest.json": manifest, "app.User.avsc": files["app.User.avsc"]}
+		for name, body := range map[string][]byte{
+			"not an archive":   []byte(`{"schemas":[]}`),
+			"missing manifest": zipOf(noManifest),
+			"missing file":     zipOf(missingFile),
+		} {
+			if w := post("/import/bundle", body); w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d: %s", name, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		w := post("/import/bundle", zipOf(files))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp types.ImportSchemasResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.Imported != 2 || resp.Errors != 0 {
+			t.Errorf("Expected 2 imported, got %+v", resp)
+		}
+
+		// Importing the same bundle again fails as a whole.
+		if w := post("/import/bundle", zipOf(files)); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 on re-import, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("tar in context", func(t *testing.T) {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for name, content := range files {
+			tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))})
+			tw.Write([]byte(content))
+		}
+		tw.Close()
+
+		req := httptest.NewRequest("PUT", "/contexts/.staging/mode?force=true", bytes.NewReader([]byte(`{"mode":"IMPORT"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Failed to set context mode: %d %s", w.Code, w.Body.String())
+		}
+
+		w = post("/contexts/.staging/import/bundle", buf.Bytes())
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("GET", "/contexts/.staging/subjects/user-value/versions/1", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected imported subject in context, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestServer_Jobs(t *testing.T) {
+	cfg := config.DefaultConfig()
+	store := memory.NewStore()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	manager := jobs.NewManager(store, logger)
+	release := make(chan struct{})
+	manager.Register("echo", func(ctx context.Context, job *jobs.Job) (any, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var params map[string]string
+		if err := job.DecodeParams(&params); err != nil {
+			return nil, err
+		}
+		return params, nil
+	})
+	stop := make(chan struct{})
+	defer close(stop)
+	manager.Start(stop)
+	server := NewServer(cfg, reg, logger, WithJobManager(manager))
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+	getJob := func(id string) types.JobResponse {
+		t.Helper()
+		w := do("GET", "/jobs/"+id)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /jobs/%s: %d %s", id, w.Code, w.Body.String())
+		}
+		var job types.JobResponse
+		json.Unmarshal(w.Body.Bytes(), &job)
+		return job
+	}
+	waitFor := func(id, state string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for getJob(id).State != state {
+			if time.Now().After(deadline) {
+				t.Fatalf("job %s did not reach %s", id, state)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	done, err := manager.Submit(context.Background(), "echo", ".", map[string]string{"hello": "world"}, "alice")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	cancelled, err := manager.Submit(context.Background(), "echo", ".", nil, "bob")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	if w := do("GET", "/jobs/"+done.ID+"/result"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("result of unfinished job: %d, want 422", w.Code)
+	}
+	if w := do("POST", "/jobs/"+cancelled.ID+"/cancel"); w.Code != http.StatusAccepted {
+		t.Fatalf("cancel: %d %s", w.Code, w.Body.String())
+	}
+	waitFor(cancelled.ID, storage.JobStateCancelled)
+	close(release)
+	waitFor(done.ID, storage.JobStateSucceeded)
+
+	w := do("GET", "/jobs/"+done.ID+"/result")
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"hello":"world"}` {
+		t.Errorf("result: %d %s", w.Code, w.Body.String())
+	}
+	if job := getJob(done.ID); job.CreatedBy != "alice" || job.FinishedAt == "" {
+		t.Errorf("job = %+v", job)
+	}
+
+	var list types.JobsListResponse
+	w = do("GET", "/jobs?state=cancelled")
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Jobs) != 1 || list.Jobs[0].ID != cancelled.ID {
+		t.Errorf("GET /jobs?state=cancelled = %s", w.Body.String())
+	}
+
+	if w := do("POST", "/jobs/"+done.ID+"/cancel"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("cancel finished job: %d, want 422", w.Code)
+	}
+	w = do("GET", "/jobs/missing")
+	var errResp types.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &errResp)
+	if w.Code != http.StatusNotFound || errResp.ErrorCode != types.ErrorCodeJobNotFound {
+		t.Errorf("missing job: %d %s", w.Code, w.Body.String())
+	}
+}
+
 func TestServer_ListSubjects(t *testing.T) {
 	server := setupTestServer(t)
 
@@ -256,6 +781,59 @@ func TestServer_GetVersion(t *testing.T) {
 	}
 }
 
+// TestServer_EncryptionRules covers what a Confluent serializer with an
+// ENCRYPT rule needs when its URL names a context: the rule with its params
+// on the version it reads, and the DEK Registry under the same prefix.
+func TestServer_EncryptionRules(t *testing.T) {
+	server := setupTestServer(t)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/contexts/.team/subjects/payments-value/versions", `{
+		"schema": "{\"type\":\"record\",\"name\":\"Payment\",\"fields\":[{\"name\":\"card\",\"type\":\"string\"}]}",
+		"ruleSet": {"domainRules": [{
+			"name": "encrypt-pii", "kind": "TRANSFORM", "type": "ENCRYPT", "mode": "WRITEREAD", "tags": ["PII"],
+			"params": {"encrypt.kek.name": "payments-kek", "encrypt.kms.type": "local-kms", "encrypt.kms.key.id": "key-1"}
+		}]}
+	}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("register: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do("GET", "/contexts/.team/subjects/payments-value/versions/latest", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get version: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp types.SubjectVersionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.RuleSet == nil || len(resp.RuleSet.DomainRules) != 1 {
+		t.Fatalf("expected one domain rule, got %s", w.Body.String())
+	}
+	rule := resp.RuleSet.DomainRules[0]
+	if rule.Type != "ENCRYPT" || rule.Mode != "WRITEREAD" || rule.Params["encrypt.kek.name"] != "payments-kek" {
+		t.Errorf("rule not returned as registered: %+v", rule)
+	}
+
+	w = do("POST", "/contexts/.team/dek-registry/v1/keks", `{"name":"payments-kek","kmsType":"local-kms","kmsKeyId":"key-1"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("create KEK under the context prefix: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// Keys are global: the one created under the prefix is the root one.
+	if w := do("GET", "/dek-registry/v1/keks/payments-kek", ""); w.Code != http.StatusOK {
+		t.Errorf("get KEK: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("GET", "/contexts/.other/dek-registry/v1/keks/payments-kek", ""); w.Code != http.StatusOK {
+		t.Errorf("get KEK under another context: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestServer_LookupSchema(t *testing.T) {
 	server := setupTestServer(t)
 
@@ -1066,12 +1644,12 @@ func TestServer_ReadinessCheck_Healthy(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 
-	var resp map[string]string
+	var resp map[string]any
 	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
 	if resp["status"] != "UP" {
-		t.Errorf("Expected status UP, got %q", resp["status"])
+		t.Errorf("Expected status UP, got %v", resp["status"])
 	}
 }
 
@@ -1086,18 +1664,73 @@ func TestServer_ReadinessCheck_Unhealthy(t *testing.T) {
 		t.Errorf("Expected status 503, got %d", w.Code)
 	}
 
-	var resp map[string]string
+	var resp map[string]any
 	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
 	if resp["status"] != "DOWN" {
-		t.Errorf("Expected status DOWN, got %q", resp["status"])
+		t.Errorf("Expected status DOWN, got %v", resp["status"])
 	}
 	if resp["reason"] == "" {
 		t.Error("Expected non-empty reason field")
 	}
 }
 
+func TestServer_ReadinessCheck_DependencyProbes(t *testing.T) {
+	newServer := func(critical ...string) *Server {
+		cfg := config.DefaultConfig()
+		cfg.Server.Health.Critical = critical
+		store := memory.NewStore()
+		schemaRegistry := schema.NewRegistry()
+		schemaRegistry.Register(avro.NewParser())
+		compatChecker := compatibility.NewChecker()
+		compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+		reg := registry.New(store, schemaRegistry, compatChecker, cfg.Compatibility.DefaultLevel)
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+		return NewServer(cfg, reg, logger,
+			WithHealthProbe("ldap", func(context.Context) error { return errors.New("connection refused") }))
+	}
+
+	type checkStatus struct {
+		Status   string `json:"status"`
+		Critical bool   `json:"critical"`
+		Error    string `json:"error"`
+	}
+	type readiness struct {
+		Status string                 `json:"status"`
+		Reason string                 `json:"reason"`
+		Checks map[string]checkStatus `json:"checks"`
+	}
+	check := func(server *Server, wantCode int) readiness {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", nil))
+		if w.Code != wantCode {
+			t.Fatalf("Expected status %d, got %d: %s", wantCode, w.Code, w.Body.String())
+		}
+		var resp readiness
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	// A failing optional dependency is reported but does not affect readiness.
+	resp := check(newServer(), http.StatusOK)
+	if resp.Status != "UP" || resp.Checks["storage"].Status != "UP" {
+		t.Errorf("Expected UP with storage UP, got %+v", resp)
+	}
+	if ldap := resp.Checks["ldap"]; ldap.Status != "DOWN" || ldap.Critical || ldap.Error != "" {
+		t.Errorf("Expected ldap DOWN, not critical, without error details, got %+v", ldap)
+	}
+
+	// Once marked critical, it does.
+	resp = check(newServer("ldap"), http.StatusServiceUnavailable)
+	if resp.Status != "DOWN" || resp.Reason != "ldap unavailable" || !resp.Checks["ldap"].Critical {
+		t.Errorf("Expected DOWN because of ldap, got %+v", resp)
+	}
+}
+
 func TestServer_StartupCheck_Healthy(t *testing.T) {
 	server := setupTestServer(t)
 
@@ -1201,3 +1834,58 @@ func TestServer_MethodNotAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_RegisterSchemaIfAbsent(t *testing.T) {
+	server := setupTestServer(t)
+
+	schemaStr := `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`
+	parsed, err := avro.NewParser().Parse(schemaStr, nil)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	fingerprint := parsed.Fingerprint()
+
+	put := func(fp string, body types.RegisterSchemaRequest) *httptest.ResponseRecorder {
+		bodyBytes, _ := json.Marshal(body)
+		req := httptest.NewRequest("PUT", "/subjects/orders-value/versions/"+fp, bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := put(fingerprint, types.RegisterSchemaRequest{Schema: schemaStr})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created types.RegisterSchemaResponse
+	json.NewDecoder(w.Body).Decode(&created)
+
+	// A retry with different metadata returns the existing version instead of creating one.
+	w = put(fingerprint, types.RegisterSchemaRequest{
+		Schema:   schemaStr,
+		Metadata: &storage.Metadata{Properties: map[string]string{"owner": "team-a"}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 on retry, got %d: %s", w.Code, w.Body.String())
+	}
+	var retried types.RegisterSchemaResponse
+	json.NewDecoder(w.Body).Decode(&retried)
+	if retried.ID != created.ID {
+		t.Errorf("Expected ID %d on retry, got %d", created.ID, retried.ID)
+	}
+
+	req := httptest.NewRequest("GET", "/subjects/orders-value/versions", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var versions []int
+	json.NewDecoder(w.Body).Decode(&versions)
+	if len(versions) != 1 {
+		t.Errorf("Expected a single version, got %v", versions)
+	}
+
+	w = put("0000", types.RegisterSchemaRequest{Schema: schemaStr})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for fingerprint mismatch, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// shareScopeMiddleware refuses requests made with a share token that fall
+// outside its scope. It must run after the authentication middleware.
+func shareScopeMiddleware(reg *registry.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := auth.GetUser(r.Context())
+			if user == nil || user.Share == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, check := user.Share.Permits(r.Method, r.URL)
+			if !allowed {
+				writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"Share token does not grant access to this resource"}`)
+				return
+			}
+			if check != nil {
+				// Answer as if the schema did not exist, so a subject-scoped
+				// token cannot probe which IDs other subjects use.
+				subjects, err := reg.GetSubjectsBySchemaID(r.Context(), check.Context, check.ID, false)
+				if err != nil || !slices.Contains(subjects, user.Share.Subject) {
+					writeShareScopeError(w, http.StatusNotFound, `{"error_code":40403,"message":"Schema not found"}`)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeShareScopeError(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
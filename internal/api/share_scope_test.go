@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestServer_ShareTokenScope(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"basic"}
+	cfg.Security.Auth.RBAC.Enabled = true
+
+	store := memory.NewStore()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	svc := auth.NewService(store)
+	authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+	authenticator.SetService(svc)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.Security.Auth.RBAC), svc))
+
+	orders, err := reg.RegisterSchema(ctx, ".partners", "orders-value", `"string"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	payments, err := reg.RegisterSchema(ctx, ".partners", "payments-value", `"int"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	_, token, err := svc.CreateShareToken(ctx, auth.CreateShareTokenRequest{
+		Name:      "acme",
+		Context:   "partners",
+		Subject:   "orders-value",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateShareToken: %v", err)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"GET", "/contexts/.partners/subjects/orders-value/versions/latest", http.StatusOK},
+		{"GET", "/subjects/:.partners:orders-value/versions", http.StatusOK},
+		{"GET", "/contexts/.partners/schemas/ids/" + strconv.FormatInt(orders.ID, 10), http.StatusOK},
+		{"GET", "/contexts/.partners/schemas/ids/" + strconv.FormatInt(payments.ID, 10), http.StatusNotFound},
+		{"GET", "/contexts/.partners/subjects/payments-value/versions/latest", http.StatusForbidden},
+		{"GET", "/contexts/.partners/subjects", http.StatusForbidden},
+		{"GET", "/subjects", http.StatusForbidden},
+		{"POST", "/contexts/.partners/subjects/orders-value/versions", http.StatusForbidden},
+		{"GET", "/admin/users", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.path, tt.want, rr.Code, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// tenantDefaultRoutes are the routes outside any tenant context that tenant
+// users may call: their own account and login sessions, context listing and
+// creation (the handlers confine both to the caller's tenant), the user and
+// API key administration of their tenant, and static metadata.
+var tenantDefaultRoutes = []struct {
+	method string // empty matches every method
+	prefix string
+	exact  bool
+}{
+	{"", "/me", false},
+	{http.MethodPost, "/auth/login", true},
+	{http.MethodGet, "/schemas/types", true},
+	{http.MethodGet, "/contexts", true},
+	{http.MethodPost, "/contexts", true},
+	{"", "/admin/users", false},
+	{"", "/admin/apikeys", false},
+	{http.MethodGet, "/admin/roles", true},
+}
+
+// tenantContextRoutes are the routes whose context is derived from the
+// user's tenant in principal tenancy: the registry routes that are also
+// served under /contexts/{context}, except exporters.
+var tenantContextRoutes = []string{
+	"/subjects", "/schemas", "/config", "/mode", "/compatibility",
+	"/import", "/topics", "/catalog", "/search",
+}
+
+// tenantContextMiddleware implements principal tenancy: the registry
+// context of a tenant user's request is the tenant's home context, whatever
+// the URL says. Registry routes without a context run in the home context,
+// and a request naming any other context, in its prefix or a qualified
+// subject, is refused. A super admin without a tenant may act for a tenant
+// by naming it in overrideHeader. It must run after the authentication
+// middleware and before tenantScopeMiddleware, which then checks the home
+// context is owned by the tenant.
+func tenantContextMiddleware(reg *registry.Registry, overrideHeader string, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := auth.GetUser(r.Context())
+			if user == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if override := r.Header.Get(overrideHeader); override != "" {
+				acting, ok := auth.ActingForTenant(user, override)
+				if !ok {
+					writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"Only super admins without a tenant may act for a tenant"}`)
+					return
+				}
+				if _, err := reg.GetTenant(r.Context(), override); err != nil {
+					writeShareScopeError(w, http.StatusNotFound, `{"error_code":40493,"message":"Tenant not found"}`)
+					return
+				}
+				logger.Info("acting for tenant",
+					slog.String("user", user.Username),
+					slog.String("tenant", override),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+				)
+				user = acting
+				r = r.WithContext(auth.WithUser(r.Context(), user))
+			}
+			if user.Tenant == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			home := registry.TenantHomeContext(user.Tenant)
+			contexts, path := tenantRequestContexts(r)
+			for _, name := range contexts {
+				if name != home {
+					writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"Tenant users may only access their tenant's context"}`)
+					return
+				}
+			}
+			if len(contexts) == 0 && tenantContextRoute(path) {
+				r = r.WithContext(registrycontext.WithRegistryContext(r.Context(), home))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tenantContextRoute reports whether path is a registry route that runs in
+// the tenant's home context in principal tenancy.
+func tenantContextRoute(path string) bool {
+	for _, prefix := range tenantContextRoutes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantScopeMiddleware confines users that belong to a tenant to the
+// contexts their tenant owns. A request is attributed to the context in its
+// /contexts/{context} prefix or to the context of a qualified subject in its
+// path; every qualified subject in the query must be owned as well. Requests
+// on the default context and instance-wide resources are refused unless
+// listed in tenantDefaultRoutes. It must run after the authentication
+// middleware.
+func tenantScopeMiddleware(reg *registry.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := auth.GetUser(r.Context())
+			if user == nil || user.Tenant == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			contexts, path := tenantRequestContexts(r)
+			if len(contexts) == 0 {
+				if !tenantDefaultRouteAllowed(r.Method, path) {
+					writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"Tenant users may only access contexts owned by their tenant"}`)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if strings.HasPrefix(path, "/exporters") {
+				writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"Exporters are not available to tenant users"}`)
+				return
+			}
+			// Keys are global, whatever the context prefix says.
+			if strings.HasPrefix(path, "/dek-registry") {
+				writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"The DEK Registry is not available to tenant users"}`)
+				return
+			}
+			for _, name := range contexts {
+				owner, err := reg.ContextTenant(r.Context(), name)
+				if err != nil || owner != user.Tenant {
+					writeShareScopeError(w, http.StatusForbidden, `{"error_code":40301,"message":"Context is not owned by your tenant"}`)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tenantRequestContexts returns the non-default contexts a request refers
+// to and its path with any /contexts/{context} prefix removed. The context
+// already set on the request, from its prefix or derived from the user's
+// tenant, counts as well.
+func tenantRequestContexts(r *http.Request) ([]string, string) {
+	var contexts []string
+	add := func(name string) {
+		if name != registrycontext.DefaultContext {
+			contexts = append(contexts, name)
+		}
+	}
+	add(registrycontext.RegistryContextFromRequest(r.Context()))
+
+	path := r.URL.Path
+	const prefix = "/contexts/"
+	if strings.HasPrefix(path, prefix) {
+		rest := path[len(prefix):]
+		name, tail := rest, "/"
+		if idx := strings.Index(rest, "/"); idx >= 0 {
+			name, tail = rest[:idx], rest[idx:]
+		}
+		add(registrycontext.NormalizeContextName(name))
+		path = tail
+	}
+
+	// Qualified subjects in the path take precedence over the URL prefix in
+	// the handlers, so both have to be owned.
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	subjectIdx := -1
+	switch {
+	case len(segments) > 1 && (segments[0] == "subjects" || segments[0] == "config" || segments[0] == "mode"):
+		subjectIdx = 1
+	case len(segments) > 2 && segments[0] == "compatibility" && segments[1] == "subjects":
+		subjectIdx = 2
+	}
+	if subjectIdx >= 0 {
+		if name, _ := registrycontext.ResolveSubject(segments[subjectIdx]); name != registrycontext.DefaultContext {
+			add(name)
+		}
+	}
+
+	// Qualified subjects in the query never widen access, but must not
+	// point outside the tenant either.
+	if len(contexts) > 0 {
+		for _, values := range r.URL.Query() {
+			for _, v := range values {
+				if name, _ := registrycontext.ResolveSubject(v); name != registrycontext.DefaultContext {
+					add(name)
+				}
+			}
+		}
+	}
+	return contexts, path
+}
+
+// tenantDefaultRouteAllowed reports whether a tenant user may call a route
+// outside any tenant context.
+func tenantDefaultRouteAllowed(method, path string) bool {
+	path = strings.TrimSuffix(path, "/")
+	for _, route := range tenantDefaultRoutes {
+		if route.method != "" && route.method != method {
+			continue
+		}
+		if path == route.prefix || (!route.exact && strings.HasPrefix(path, route.prefix+"/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/api/types"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+	"github.com/axonops/axonops-schema-registry/internal/tenant"
+)
+
+func TestServer_TenantScope(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"basic"}
+	cfg.Security.Auth.RBAC.Enabled = true
+	cfg.Tenancy.Enabled = true
+
+	raw := memory.NewStore()
+	keyring, err := tenant.NewKeyring(raw, bytes.Repeat([]byte{7}, tenant.MasterKeySize))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	store := storage.NewEncryptedStorage(raw, keyring)
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	reg.SetTenantKeyGenerator(keyring)
+	svc := auth.NewService(store)
+	authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+	authenticator.SetService(svc)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.Security.Auth.RBAC), svc))
+
+	for _, name := range []string{"acme", "globex"} {
+		if err := reg.CreateTenant(ctx, &storage.TenantRecord{Name: name}); err != nil {
+			t.Fatalf("CreateTenant(%s): %v", name, err)
+		}
+		if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: name + "-orders", Tenant: name}); err != nil {
+			t.Fatalf("CreateContext(%s): %v", name, err)
+		}
+		if _, err := reg.RegisterSchema(ctx, "."+name+"-orders", "orders-value", `"string"`, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema: %v", err)
+		}
+	}
+	for _, u := range []auth.CreateUserRequest{
+		{Username: "alice", Password: "alice-pass", Role: "super_admin", Enabled: true, Tenant: "acme"},
+		{Username: "gary", Password: "gary-pass", Role: "super_admin", Enabled: true, Tenant: "globex"},
+		{Username: "root", Password: "root-pass", Role: "super_admin", Enabled: true},
+	} {
+		if _, err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser(%s): %v", u.Username, err)
+		}
+	}
+
+	do := func(user, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth(user, user+"-pass")
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("AccessConfinedToTenantContexts", func(t *testing.T) {
+		tests := []struct {
+			method string
+			path   string
+			want   int
+		}{
+			{"GET", "/contexts/.acme-orders/subjects", http.StatusOK},
+			{"GET", "/contexts/.acme-orders/subjects/orders-value/versions/1", http.StatusOK},
+			{"GET", "/subjects/:.acme-orders:orders-value/versions", http.StatusOK},
+			{"GET", "/contexts/.globex-orders/subjects", http.StatusForbidden},
+			{"GET", "/subjects/:.globex-orders:orders-value/versions", http.StatusForbidden},
+			{"GET", "/contexts/.acme-orders/subjects/:.globex-orders:orders-value/versions", http.StatusForbidden},
+			{"GET", "/contexts/.acme-orders/schemas?subjectPrefix=:.globex-orders:", http.StatusForbidden},
+			{"GET", "/contexts/.unowned/subjects", http.StatusForbidden},
+			{"GET", "/contexts/.acme-orders/exporters", http.StatusForbidden},
+			{"GET", "/contexts/.acme-orders/dek-registry/v1/keks", http.StatusForbidden},
+			{"GET", "/subjects", http.StatusForbidden},
+			{"GET", "/config", http.StatusForbidden},
+			{"GET", "/dek-registry/v1/keks", http.StatusForbidden},
+			{"GET", "/admin/grants", http.StatusForbidden},
+			{"GET", "/admin/tenants", http.StatusForbidden},
+			{"GET", "/schemas/types", http.StatusOK},
+			{"GET", "/admin/users", http.StatusOK},
+		}
+		for _, tt := range tests {
+			rr := do("alice", tt.method, tt.path, "")
+			if rr.Code != tt.want {
+				t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.path, tt.want, rr.Code, rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("ContextListingAndCreation", func(t *testing.T) {
+		rr := do("alice", "POST", "/contexts", `{"name":"acme-billing","tenant":"globex"}`)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("create context: expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var created types.ContextResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &created)
+		if created.Tenant != "acme" {
+			t.Errorf("expected new context to belong to acme, got %q", created.Tenant)
+		}
+
+		rr = do("alice", "GET", "/contexts", "")
+		var contexts []string
+		_ = json.Unmarshal(rr.Body.Bytes(), &contexts)
+		if strings.Join(contexts, ",") != ".acme-billing,.acme-orders" {
+			t.Errorf("expected only acme contexts, got %v", contexts)
+		}
+	})
+
+	t.Run("AdminConfinedToTenantUsers", func(t *testing.T) {
+		rr := do("alice", "GET", "/admin/users", "")
+		var users types.UsersListResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &users)
+		if len(users.Users) != 1 || users.Users[0].Username != "alice" {
+			t.Errorf("expected only alice, got %+v", users.Users)
+		}
+
+		gary, err := svc.GetUserByUsername(ctx, "gary")
+		if err != nil {
+			t.Fatalf("GetUserByUsername: %v", err)
+		}
+		if rr := do("alice", "GET", "/admin/users/"+jsonNumber(gary.ID), ""); rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for another tenant's user, got %d", rr.Code)
+		}
+
+		rr = do("alice", "POST", "/admin/users", `{"username":"alice2","password":"secret-pass","role":"developer"}`)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create user: expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var created types.UserResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &created)
+		if created.Tenant != "acme" {
+			t.Errorf("expected new user to join acme, got %q", created.Tenant)
+		}
+	})
+
+	t.Run("SuperAdminManagesTenants", func(t *testing.T) {
+		rr := do("root", "GET", "/admin/tenants/acme", "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("get tenant: expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var got types.TenantResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &got)
+		if !got.Encrypted || len(got.Contexts) == 0 {
+			t.Errorf("unexpected tenant response: %+v", got)
+		}
+		if rr := do("root", "DELETE", "/admin/tenants/acme", ""); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("delete tenant with contexts: expected 422, got %d", rr.Code)
+		}
+		if rr := do("root", "POST", "/admin/tenants", `{"name":"initech"}`); rr.Code != http.StatusCreated {
+			t.Errorf("create tenant: expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr := do("root", "POST", "/admin/tenants", `{"name":"bad name"}`); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("invalid tenant name: expected 422, got %d", rr.Code)
+		}
+	})
+
+	t.Run("SchemasEncryptedWithTenantKey", func(t *testing.T) {
+		rec, err := raw.GetSchemaBySubjectVersion(ctx, ".acme-orders", "orders-value", 1)
+		if err != nil {
+			t.Fatalf("GetSchemaBySubjectVersion: %v", err)
+		}
+		if !strings.HasPrefix(rec.Schema, "enc:v1:acme:") {
+			t.Errorf("expected schema sealed with the acme key, got %q", rec.Schema)
+		}
+	})
+}
+
+func TestServer_TenantPrincipalContexts(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"basic"}
+	cfg.Security.Auth.RBAC.Enabled = true
+	cfg.Tenancy.Enabled = true
+	cfg.Tenancy.ContextMode = "principal"
+
+	store := memory.NewStore()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	reg.SetTenantHomeContexts(true)
+	svc := auth.NewService(store)
+	authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+	authenticator.SetService(svc)
+	server := NewServer(cfg, reg, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})),
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.Security.Auth.RBAC), svc))
+
+	for _, name := range []string{"acme", "globex"} {
+		if err := reg.CreateTenant(ctx, &storage.TenantRecord{Name: name}); err != nil {
+			t.Fatalf("CreateTenant(%s): %v", name, err)
+		}
+	}
+	if _, err := reg.RegisterSchema(ctx, ".globex", "orders-value", `"string"`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	for _, u := range []auth.CreateUserRequest{
+		{Username: "alice", Password: "alice-pass", Role: "super_admin", Enabled: true, Tenant: "acme"},
+		{Username: "dave", Password: "dave-pass", Role: "developer", Enabled: true},
+		{Username: "root", Password: "root-pass", Role: "super_admin", Enabled: true},
+	} {
+		if _, err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser(%s): %v", u.Username, err)
+		}
+	}
+
+	do := func(user, method, path, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth(user, user+"-pass")
+		req.Header.Set("Content-Type", "application/json")
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("RequestsRunInHomeContext", func(t *testing.T) {
+		rr := do("alice", "POST", "/subjects/payments-value/versions", `{"schema":"\"string\""}`)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("register: expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if _, err := reg.GetSchemaBySubjectVersion(ctx, ".acme", "payments-value", 1); err != nil {
+			t.Errorf("expected the schema in .acme: %v", err)
+		}
+
+		rr = do("alice", "GET", "/subjects", "")
+		var subjects []string
+		_ = json.Unmarshal(rr.Body.Bytes(), &subjects)
+		if strings.Join(subjects, ",") != "payments-value" {
+			t.Errorf("expected only acme subjects, got %v", subjects)
+		}
+	})
+
+	t.Run("OtherContextsRefused", func(t *testing.T) {
+		tests := []struct {
+			method string
+			path   string
+			want   int
+		}{
+			{"GET", "/contexts/.acme/subjects", http.StatusOK},
+			{"GET", "/config?defaultToGlobal=true", http.StatusOK},
+			{"GET", "/contexts/.globex/subjects", http.StatusForbidden},
+			{"GET", "/subjects/:.globex:orders-value/versions", http.StatusForbidden},
+			{"GET", "/contexts/.__GLOBAL/config", http.StatusForbidden},
+			{"GET", "/exporters", http.StatusForbidden},
+			{"GET", "/admin/grants", http.StatusForbidden},
+		}
+		for _, tt := range tests {
+			rr := do("alice", tt.method, tt.path, "")
+			if rr.Code != tt.want {
+				t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.path, tt.want, rr.Code, rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("SuperAdminOverride", func(t *testing.T) {
+		rr := do("root", "GET", "/subjects", "", "X-Registry-Tenant", "globex")
+		var subjects []string
+		_ = json.Unmarshal(rr.Body.Bytes(), &subjects)
+		if rr.Code != http.StatusOK || strings.Join(subjects, ",") != "orders-value" {
+			t.Errorf("expected globex subjects, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr := do("root", "GET", "/subjects", "", "X-Registry-Tenant", "initech"); rr.Code != http.StatusNotFound {
+			t.Errorf("unknown tenant: expected 404, got %d", rr.Code)
+		}
+		if rr := do("alice", "GET", "/subjects", "", "X-Registry-Tenant", "globex"); rr.Code != http.StatusForbidden {
+			t.Errorf("tenant super admin override: expected 403, got %d", rr.Code)
+		}
+		if rr := do("dave", "GET", "/subjects", "", "X-Registry-Tenant", "globex"); rr.Code != http.StatusForbidden {
+			t.Errorf("developer override: expected 403, got %d", rr.Code)
+		}
+
+		// Without the header, instance-wide users keep using the URL.
+		rr = do("root", "GET", "/contexts/.globex/subjects", "")
+		if rr.Code != http.StatusOK {
+			t.Errorf("instance-wide user: expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+}
+
+func jsonNumber(n int64) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
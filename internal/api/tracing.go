@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+)
+
+// tracerName names the instrumentation scope of HTTP server spans.
+const tracerName = "github.com/axonops/axonops-schema-registry/internal/api"
+
+// tracingMiddleware starts a server span for each request, continuing the
+// trace of a caller that sent a W3C traceparent header. Registry and
+// storage spans for the request are its children. Health probes and
+// metrics scrapes are not traced.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" || strings.HasPrefix(r.URL.Path, "/health/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(tracerName).Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("user_agent.original", r.UserAgent()),
+			),
+		)
+		defer span.End()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		// The route pattern is only complete once routing has finished.
+		if rctx := chi.RouteContext(ctx); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(attribute.String("http.route", pattern))
+			}
+			if subject := rctx.URLParam("subject"); subject != "" {
+				span.SetAttributes(attribute.String("schema_registry.subject", subject))
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// requestLogKey is the context key of the *requestLog for a request.
+type requestLogKey struct{}
+
+// requestLog holds what is learned about a request after loggingMiddleware
+// has passed it on, such as the authenticated principal.
+type requestLog struct {
+	principal string
+}
+
+// withRequestLog returns a context carrying a new requestLog.
+func withRequestLog(ctx context.Context) (context.Context, *requestLog) {
+	rl := &requestLog{}
+	return context.WithValue(ctx, requestLogKey{}, rl), rl
+}
+
+// principalMiddleware records the authenticated principal in the request
+// log and on the request's span.
+func principalMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := auth.GetUser(r.Context()); user != nil {
+			if rl, ok := r.Context().Value(requestLogKey{}).(*requestLog); ok {
+				rl.principal = user.Username
+			}
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("enduser.id", user.Username))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
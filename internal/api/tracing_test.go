@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestServer_TracingAndRequestLog(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"basic"}
+	store := memory.NewStore()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	reg := registry.New(store, schemaRegistry, compatibility.NewChecker(), cfg.Compatibility.DefaultLevel)
+	svc := auth.NewService(store)
+	authenticator := auth.NewAuthenticator(cfg.Security.Auth)
+	authenticator.SetService(svc)
+	var logs bytes.Buffer
+	server := NewServer(cfg, reg, slog.New(slog.NewJSONHandler(&logs, nil)),
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.Security.Auth.RBAC), svc))
+	if _, err := svc.CreateUser(context.Background(), auth.CreateUserRequest{
+		Username: "dev", Password: "dev-pass", Role: "developer", Enabled: true,
+	}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/subjects/orders-value/versions", strings.NewReader(`{"schema":"\"string\""}`))
+	req.SetBasicAuth("dev", "dev-pass")
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	req.Header.Set("Traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rr := httptest.NewRecorder()
+	server.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("register: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var serverSpan, registrySpan sdktrace.ReadOnlySpan
+	for _, s := range spans.Ended() {
+		switch s.Name() {
+		case "POST /subjects/{subject}/versions":
+			serverSpan = s
+		case "registry.RegisterSchema":
+			registrySpan = s
+		}
+	}
+	if serverSpan == nil || registrySpan == nil {
+		t.Fatalf("expected server and registry spans, got %d spans", len(spans.Ended()))
+	}
+	if serverSpan.SpanContext().TraceID().String() != traceID || serverSpan.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Error("expected the server span to continue the caller's trace")
+	}
+	if registrySpan.Parent().SpanID() != serverSpan.SpanContext().SpanID() {
+		t.Error("expected the registry span to be a child of the server span")
+	}
+
+	var entry map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if err := json.Unmarshal([]byte(line), &entry); err == nil && entry["msg"] == "request" {
+			break
+		}
+	}
+	if entry["principal"] != "dev" || entry["subject"] != "orders-value" || entry["trace_id"] != traceID ||
+		entry["span_id"] != serverSpan.SpanContext().SpanID().String() || entry["status"] != float64(http.StatusOK) {
+		t.Errorf("unexpected request log: %v", entry)
+	}
+}
@@ -1,7 +1,11 @@
 // Package types provides API request and response types.
 package types
 
-import "github.com/axonops/axonops-schema-registry/internal/storage"
+import (
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
 
 // RegisterSchemaRequest is the request body for registering a schema.
 type RegisterSchemaRequest struct {
@@ -16,7 +20,8 @@ type RegisterSchemaRequest struct {
 
 // RegisterSchemaResponse is the response for registering a schema.
 type RegisterSchemaResponse struct {
-	ID int64 `json:"id"`
+	ID       int64    `json:"id"`
+	Warnings []string `json:"warnings,omitempty"` // Warn-severity lint violations of the registered schema
 }
 
 // SchemaResponse is the response for getting a schema.
@@ -34,6 +39,8 @@ type SchemaByIDResponse struct {
 	Metadata   *storage.Metadata   `json:"metadata,omitempty"`
 	RuleSet    *storage.RuleSet    `json:"ruleSet,omitempty"`
 	MaxId      *int64              `json:"maxId,omitempty"`
+	// Fingerprint is set when fingerprints.algorithm is configured.
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // SubjectVersionResponse is the response for getting a subject version.
@@ -46,6 +53,8 @@ type SubjectVersionResponse struct {
 	References []storage.Reference `json:"references,omitempty"`
 	Metadata   *storage.Metadata   `json:"metadata,omitempty"`
 	RuleSet    *storage.RuleSet    `json:"ruleSet,omitempty"`
+	// Fingerprint is set when fingerprints.algorithm is configured.
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // LookupSchemaRequest is the request body for looking up a schema.
@@ -99,7 +108,8 @@ type ConfigRequest struct {
 
 // ModeResponse is the response for getting mode.
 type ModeResponse struct {
-	Mode string `json:"mode"`
+	Mode        string               `json:"mode"`
+	Maintenance *MaintenanceResponse `json:"maintenance,omitempty"` // Set while a maintenance window is active
 }
 
 // ModeRequest is the request body for setting mode.
@@ -116,8 +126,166 @@ type CompatibilityCheckRequest struct {
 
 // CompatibilityCheckResponse is the response for checking compatibility.
 type CompatibilityCheckResponse struct {
-	IsCompatible bool     `json:"is_compatible"`
-	Messages     []string `json:"messages,omitempty"`
+	IsCompatible      bool                            `json:"is_compatible"`
+	Messages          []string                        `json:"messages,omitempty"`
+	Warnings          []string                        `json:"warnings,omitempty"`
+	Incompatibilities []compatibility.Incompatibility `json:"incompatibilities,omitempty"`
+}
+
+// CompatibleVersionsResponse is the response for listing the versions of a
+// subject that a reader schema can consume.
+type CompatibleVersionsResponse struct {
+	Subject            string `json:"subject"`
+	SchemaID           int64  `json:"schemaId"`
+	CompatibilityLevel string `json:"compatibilityLevel"`
+	Versions           []int  `json:"versions"`
+}
+
+// TopicSubjectsResponse maps a Kafka topic to its registered subjects.
+type TopicSubjectsResponse struct {
+	Topic   string   `json:"topic"`
+	Key     string   `json:"key,omitempty"`
+	Value   string   `json:"value,omitempty"`
+	Records []string `json:"records"`
+}
+
+// SubjectCatalogResponse is one page of GET /catalog/subjects.
+type SubjectCatalogResponse struct {
+	Subjects []SubjectCatalogEntry `json:"subjects"`
+	Total    int                   `json:"total"`
+	Offset   int                   `json:"offset"`
+	Limit    int                   `json:"limit,omitempty"`
+}
+
+// SubjectCatalogEntry is a subject with its latest schema, compatibility
+// level and mode, each present when requested with the include parameter.
+type SubjectCatalogEntry struct {
+	Subject            string                  `json:"subject"`
+	LatestVersion      *SubjectVersionResponse `json:"latestVersion,omitempty"`
+	CompatibilityLevel string                  `json:"compatibilityLevel,omitempty"`
+	Mode               string                  `json:"mode,omitempty"`
+	Meta               *SubjectMetaResponse    `json:"meta,omitempty"`
+}
+
+// SubjectMetaRequest is the request body for PUT /subjects/{subject}/meta.
+// It replaces the stored meta.
+type SubjectMetaRequest struct {
+	Owner       string                `json:"owner,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Contact     string                `json:"contact,omitempty"`
+	Links       []storage.SubjectLink `json:"links,omitempty"`
+	Reviewers   []string              `json:"reviewers,omitempty"`
+}
+
+// SubjectMetaResponse is the owner, description, contact, documentation
+// links and reviewers of a subject.
+type SubjectMetaResponse struct {
+	Subject     string                `json:"subject"`
+	Owner       string                `json:"owner,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Contact     string                `json:"contact,omitempty"`
+	Links       []storage.SubjectLink `json:"links"`
+	Reviewers   []string              `json:"reviewers,omitempty"`
+	UpdatedAt   string                `json:"updated_at,omitempty"`
+}
+
+// ApplyRequest is the request body for POST /apply: the desired state of
+// the registry. Contexts, subjects and settings it does not mention are left
+// alone.
+type ApplyRequest struct {
+	Contexts []ApplyContext `json:"contexts"`
+}
+
+// ApplyContext is the desired state of one context. An empty name is the
+// default context.
+type ApplyContext struct {
+	Name          string         `json:"name,omitempty"`
+	Description   string         `json:"description,omitempty"` // Only used when the context is created
+	Compatibility string         `json:"compatibility,omitempty"`
+	Mode          string         `json:"mode,omitempty"`
+	Subjects      []ApplySubject `json:"subjects,omitempty"`
+}
+
+// ApplySubject is the desired state of one subject. Schemas are the
+// versions it must contain, oldest first.
+type ApplySubject struct {
+	Name          string        `json:"name"`
+	Compatibility string        `json:"compatibility,omitempty"`
+	Mode          string        `json:"mode,omitempty"`
+	Schemas       []ApplySchema `json:"schemas,omitempty"`
+}
+
+// ApplySchema is one schema a subject must contain.
+type ApplySchema struct {
+	Schema     string              `json:"schema"`
+	SchemaType string              `json:"schemaType,omitempty"`
+	References []storage.Reference `json:"references,omitempty"`
+}
+
+// ApplyResponse is the plan of POST /apply and, unless it was a dry run,
+// the outcome of each change.
+type ApplyResponse struct {
+	DryRun  bool                  `json:"dry_run"`
+	Failed  bool                  `json:"failed"`
+	Changes []ApplyChangeResponse `json:"changes"`
+}
+
+// ApplyChangeResponse is one change between the registry and the desired
+// state.
+type ApplyChangeResponse struct {
+	Action  string `json:"action"` // create_context, set_compatibility, set_mode or register_schema
+	Context string `json:"context"`
+	Subject string `json:"subject,omitempty"`
+	From    string `json:"from,omitempty"` // Omitted when the setting is inherited
+	To      string `json:"to,omitempty"`
+	Version int    `json:"version,omitempty"`
+	ID      int64  `json:"id,omitempty"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DeleteConfirmationResponse is the 202 response to a permanent delete when
+// deletes are two-phase. The delete happens when it is repeated with
+// confirmation_token set to ConfirmationToken before ExpiresAt.
+type DeleteConfirmationResponse struct {
+	ConfirmationToken string `json:"confirmation_token"`
+	ExpiresAt         string `json:"expires_at"`
+	Message           string `json:"message"`
+}
+
+// TagsRequest is the request body for PUT /subjects/{subject}/tags and
+// PUT /subjects/{subject}/versions/{version}/tags. It replaces the stored
+// tags and labels.
+type TagsRequest struct {
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// TagsResponse is the tags and labels of a subject, or of a version when
+// Version is set.
+type TagsResponse struct {
+	Subject string            `json:"subject"`
+	Version int               `json:"version,omitempty"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// SchemaSearchResponse is the response for GET /search/schemas.
+type SchemaSearchResponse struct {
+	Count   int                  `json:"count"`
+	Results []SchemaSearchResult `json:"results"`
+}
+
+// SchemaSearchResult is a schema version matching a search, with the
+// effective tags and labels of the version and its subject.
+type SchemaSearchResult struct {
+	Subject       string            `json:"subject"`
+	Version       int               `json:"version"`
+	ID            int64             `json:"id"`
+	SchemaType    string            `json:"schemaType"`
+	Tags          []string          `json:"tags"`
+	Labels        map[string]string `json:"labels"`
+	MatchedFields []string          `json:"matchedFields,omitempty"`
 }
 
 // ErrorResponse is the error response format.
@@ -132,6 +300,23 @@ type SubjectVersionPair struct {
 	Version int    `json:"version"`
 }
 
+// SchemaFingerprintMatch is a schema found by its fingerprint, with every
+// subject version that uses it.
+type SchemaFingerprintMatch struct {
+	Fingerprint string               `json:"fingerprint"`
+	ID          int64                `json:"id"`
+	SchemaType  string               `json:"schemaType"`
+	Versions    []SubjectVersionPair `json:"versions"`
+}
+
+// SchemaFingerprintResponse is the response for getting the fingerprint of
+// a schema by ID.
+type SchemaFingerprintResponse struct {
+	ID          int64  `json:"id"`
+	Algorithm   string `json:"algorithm"`
+	Fingerprint string `json:"fingerprint"`
+}
+
 // SchemaListItem is a schema in the list response.
 type SchemaListItem struct {
 	Subject    string              `json:"subject"`
@@ -156,6 +341,58 @@ type ServerVersionResponse struct {
 	BuildTime string `json:"build_time,omitempty"`
 }
 
+// CapabilitiesResponse is the response for GET /capabilities. It describes
+// what this server supports so that clients can adapt without probing
+// endpoints. Fields are only ever added, never renamed or removed.
+type CapabilitiesResponse struct {
+	Version       string                  `json:"version"`
+	SchemaTypes   []string                `json:"schema_types"`
+	Features      CapabilityFeatures      `json:"features"`
+	Auth          AuthCapabilities        `json:"auth"`
+	Normalization NormalizationCapability `json:"normalization"`
+	Pagination    PaginationCapability    `json:"pagination"`
+}
+
+// CapabilityFeatures reports which optional features are available.
+type CapabilityFeatures struct {
+	Contexts       bool `json:"contexts"`
+	ImportMode     bool `json:"import_mode"`
+	Metadata       bool `json:"metadata"`
+	RuleSet        bool `json:"rule_set"`
+	Normalization  bool `json:"normalization"`
+	DataEncryption bool `json:"data_encryption"`
+	Exporters      bool `json:"exporters"`
+	Jobs           bool `json:"jobs"`
+	UsageTracking  bool `json:"usage_tracking"`
+	Tenancy        bool `json:"tenancy"`
+	FieldSelection bool `json:"field_selection"`
+	SchemaAnalysis bool `json:"schema_analysis"`
+}
+
+// AuthCapabilities reports how clients can authenticate.
+type AuthCapabilities struct {
+	Enabled bool     `json:"enabled"`
+	Methods []string `json:"methods"`
+	RBAC    bool     `json:"rbac"`
+}
+
+// NormalizationCapability lists the configured normalization profiles.
+type NormalizationCapability struct {
+	Profiles       []string `json:"profiles"`
+	DefaultProfile string   `json:"default_profile,omitempty"`
+}
+
+// PaginationCapability describes list pagination. Lists accept offset and
+// limit query parameters; version lists are capped at MaxVersionsPageSize
+// and subject and schema lists at MaxListPageSize per request when they are
+// non-zero. A Link header points at the next page of a truncated list.
+type PaginationCapability struct {
+	OffsetLimit         bool `json:"offset_limit"`
+	MaxVersionsPageSize int  `json:"max_versions_page_size"`
+	MaxListPageSize     int  `json:"max_list_page_size"`
+	LinkHeader          bool `json:"link_header"`
+}
+
 // ResolvedReference is a schema reference with its schema content included.
 // Used when referenceFormat=RESOLVED is requested on schema GET endpoints.
 type ResolvedReference struct {
@@ -176,6 +413,7 @@ const (
 	ErrorCodeVersionNotSoftDeleted     = 40407
 	ErrorCodeSubjectCompatNotFound     = 40408
 	ErrorCodeSubjectModeNotFound       = 40409
+	ErrorCodeNotAcceptable             = 406
 	ErrorCodeIncompatibleSchema        = 409
 	ErrorCodeInvalidSchema             = 42201
 	ErrorCodeInvalidVersion            = 42202
@@ -183,9 +421,18 @@ const (
 	ErrorCodeInvalidMode               = 42204
 	ErrorCodeOperationNotPermitted     = 42205
 	ErrorCodeReferenceExists           = 42206
+	ErrorCodeSubjectNameStrategy       = 42209
+	ErrorCodeInvalidContext            = 42210
+	ErrorCodeReferenceCycle            = 42213
 	ErrorCodeInternalServerError       = 50001
 	ErrorCodeStorageError              = 50002
 
+	// Context error codes
+	ErrorCodeContextNotFound      = 40460
+	ErrorCodeContextExists        = 40960
+	ErrorCodeContextNotEmpty      = 42211
+	ErrorCodeContextQuotaExceeded = 42212
+
 	// Exporter error codes
 	ErrorCodeExporterNotFound = 40450
 	ErrorCodeExporterExists   = 40950
@@ -208,6 +455,103 @@ const (
 	ErrorCodeAPIKeyExpired   = 40103
 	ErrorCodeAPIKeyDisabled  = 40104
 	ErrorCodeUserDisabled    = 40105
+
+	// Audit error codes
+	ErrorCodeAuditHistoryDisabled = 40480
+
+	// Usage error codes
+	ErrorCodeUsageTrackingDisabled  = 40481
+	ErrorCodeClientTrackingDisabled = 40482
+
+	// Auth cache error codes
+	ErrorCodeAuthCacheNotChecked = 40483
+
+	// Grant error codes
+	ErrorCodeGrantNotFound = 40490
+
+	// Share token error codes
+	ErrorCodeShareTokenNotFound = 40492
+
+	// Job error codes
+	ErrorCodeJobNotFound     = 40491
+	ErrorCodeJobFinished     = 42214
+	ErrorCodeJobResultAbsent = 42215
+	ErrorCodeJobQueueFull    = 50003
+
+	// Tenant error codes
+	ErrorCodeTenantNotFound = 40493
+	ErrorCodeTenantExists   = 40903
+	ErrorCodeInvalidTenant  = 42216
+	ErrorCodeTenantNotEmpty = 42217
+
+	// Request validation error codes
+	ErrorCodeInvalidRequest = 42218
+
+	// Tag error codes
+	ErrorCodeInvalidTags = 42219
+
+	// Import session error codes
+	ErrorCodeImportSessionNotFound = 40494
+	ErrorCodeImportSessionConflict = 40904
+	ErrorCodeInvalidImportSession  = 42220
+	ErrorCodeImportSessionClosed   = 42221
+
+	// Schema ID alias error codes
+	ErrorCodeIDAliasNotFound = 40496
+	ErrorCodeInvalidIDAlias  = 42225
+
+	// Frozen version error codes
+	ErrorCodeVersionNotFrozen = 40497
+	ErrorCodeVersionFrozen    = 42226
+
+	// Subject alias error codes
+	ErrorCodeInvalidSubjectAlias = 42227
+	ErrorCodeSubjectAliased      = 42228
+
+	// Maintenance window error codes
+	ErrorCodeMaintenanceNotFound = 40495
+	ErrorCodeInvalidMaintenance  = 42222
+	ErrorCodeMaintenanceEnded    = 42223
+
+	// Lint error codes
+	ErrorCodeLintFailed = 42224
+
+	// Pending schema error codes
+	ErrorCodeSelfApproval          = 40302
+	ErrorCodeNotAssignedReviewer   = 40303
+	ErrorCodePendingSchemaNotFound = 40498
+	ErrorCodeInvalidReview         = 42229
+	ErrorCodePendingSchemaReviewed = 42230
+
+	// Reader assertion error codes
+	ErrorCodeReaderNotFound = 40499
+	ErrorCodeInvalidReader  = 42231
+
+	// Schema conversion error codes
+	ErrorCodeUnsupportedConversion = 42232
+
+	// Subject meta error codes
+	ErrorCodeInvalidSubjectMeta = 42233
+
+	// Permanent delete confirmation error codes
+	ErrorCodeInvalidDeleteConfirmation = 42234
+
+	// Declarative apply error codes
+	ErrorCodeInvalidDesiredState = 42235
+
+	// Quota error codes
+	ErrorCodeQuotaRuleNotFound = 40484
+	ErrorCodeQuotaExceeded     = 42236
+
+	// Idempotency key error codes
+	ErrorCodeIdempotencyKeyInUse  = 40905
+	ErrorCodeIdempotencyKeyReused = 42237
+
+	// Fingerprint error codes
+	ErrorCodeUnsupportedFingerprint = 42238
+
+	// Configuration reload error codes
+	ErrorCodeConfigReloadFailed = 42239
 )
 
 // CreateUserRequest is the request body for creating a user.
@@ -217,6 +561,9 @@ type CreateUserRequest struct {
 	Password string `json:"password"`
 	Role     string `json:"role"`
 	Enabled  *bool  `json:"enabled,omitempty"`
+	Tenant   string `json:"tenant,omitempty"` // Ignored for tenant admins, whose users join their own tenant
+	// MustChangePassword requires the user to change the password on first login.
+	MustChangePassword bool `json:"must_change_password,omitempty"`
 }
 
 // UpdateUserRequest is the request body for updating a user.
@@ -225,6 +572,9 @@ type UpdateUserRequest struct {
 	Password *string `json:"password,omitempty"`
 	Role     *string `json:"role,omitempty"`
 	Enabled  *bool   `json:"enabled,omitempty"`
+	// MustChangePassword requires the user to change the password before
+	// doing anything else.
+	MustChangePassword *bool `json:"must_change_password,omitempty"`
 }
 
 // UserResponse is the response for user operations.
@@ -234,8 +584,12 @@ type UserResponse struct {
 	Email     string `json:"email,omitempty"`
 	Role      string `json:"role"`
 	Enabled   bool   `json:"enabled"`
+	Tenant    string `json:"tenant,omitempty"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+
+	PasswordChangedAt  string `json:"password_changed_at,omitempty"`
+	MustChangePassword bool   `json:"must_change_password,omitempty"`
 }
 
 // UsersListResponse is the response for listing users.
@@ -249,12 +603,31 @@ type ChangePasswordRequest struct {
 	NewPassword string `json:"new_password"`
 }
 
+// LoginResponse is the response body for starting or refreshing a login
+// session.
+type LoginResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+}
+
+// RefreshSessionRequest is the request body for refreshing or revoking a
+// login session.
+type RefreshSessionRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 // CreateAPIKeyRequest is the request body for creating an API key.
 type CreateAPIKeyRequest struct {
 	Name      string `json:"name"`                  // Required, must be unique per user
 	Role      string `json:"role"`                  // Required: super_admin, admin, developer, readonly
 	ExpiresIn int64  `json:"expires_in"`            // Required, duration in seconds (e.g., 2592000 for 30 days)
 	ForUserID *int64 `json:"for_user_id,omitempty"` // Optional: super_admin can create keys for other users
+
+	// Scopes optionally restricts the key to contexts and subject prefixes.
+	Scopes []storage.APIKeyScope `json:"scopes,omitempty"`
 }
 
 // UpdateAPIKeyRequest is the request body for updating an API key.
@@ -276,6 +649,35 @@ type APIKeyResponse struct {
 	CreatedAt string  `json:"created_at"`
 	ExpiresAt string  `json:"expires_at"`
 	LastUsed  *string `json:"last_used,omitempty"`
+
+	Scopes []storage.APIKeyScope `json:"scopes,omitempty"`
+}
+
+// APIKeyUsageResponse is the response for querying API key usage.
+type APIKeyUsageResponse struct {
+	Since   string             `json:"since,omitempty"` // Start of the first UTC day counted; omitted when counting all history
+	APIKeys []APIKeyUsageEntry `json:"api_keys"`
+}
+
+// APIKeyUsageEntry is the number of requests one API key authenticated.
+type APIKeyUsageEntry struct {
+	ID        int64              `json:"id"`
+	KeyPrefix string             `json:"key_prefix"`
+	Name      string             `json:"name"`
+	UserID    int64              `json:"user_id"`
+	Username  string             `json:"username"`
+	Enabled   bool               `json:"enabled"`
+	CreatedAt string             `json:"created_at"`
+	LastUsed  *string            `json:"last_used,omitempty"`
+	Requests  int64              `json:"requests"`
+	Days      []APIKeyUsageDaily `json:"days"` // Oldest first; days without requests are left out
+}
+
+// APIKeyUsageDaily is the number of requests an API key authenticated on
+// one UTC day.
+type APIKeyUsageDaily struct {
+	Day      string `json:"day"` // YYYY-MM-DD
+	Requests int64  `json:"requests"`
 }
 
 // CreateAPIKeyResponse is the response for creating an API key (includes raw key).
@@ -290,6 +692,8 @@ type CreateAPIKeyResponse struct {
 	Enabled   bool   `json:"enabled"`
 	CreatedAt string `json:"created_at"`
 	ExpiresAt string `json:"expires_at"`
+
+	Scopes []storage.APIKeyScope `json:"scopes,omitempty"`
 }
 
 // APIKeysListResponse is the response for listing API keys.
@@ -303,6 +707,108 @@ type RotateAPIKeyResponse struct {
 	RevokedID int64                `json:"revoked_id"`
 }
 
+// CreateGrantRequest is the request body for creating a role grant.
+// Exactly one of Username and APIKeyID must be set.
+type CreateGrantRequest struct {
+	Username      string `json:"username,omitempty"`
+	APIKeyID      int64  `json:"api_key_id,omitempty"`
+	Role          string `json:"role"`                     // Required: admin, developer, readonly
+	Context       string `json:"context,omitempty"`        // Optional: empty applies to every context
+	SubjectPrefix string `json:"subject_prefix,omitempty"` // Optional: empty applies to every subject
+}
+
+// GrantResponse is the response for role grant operations.
+type GrantResponse struct {
+	ID            int64  `json:"id"`
+	Username      string `json:"username,omitempty"`
+	APIKeyID      int64  `json:"api_key_id,omitempty"`
+	Role          string `json:"role"`
+	Context       string `json:"context,omitempty"`
+	SubjectPrefix string `json:"subject_prefix,omitempty"`
+	CreatedBy     string `json:"created_by,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// AccountPermissionsResponse is the response for GET /me/permissions: what
+// the caller may do, through their role and through role grants.
+type AccountPermissionsResponse struct {
+	Username    string                `json:"username"`
+	Role        string                `json:"role"`
+	Method      string                `json:"method"`
+	Tenant      string                `json:"tenant,omitempty"`
+	SuperAdmin  bool                  `json:"super_admin,omitempty"`
+	Permissions []string              `json:"permissions"`
+	Grants      []GrantResponse       `json:"grants"`
+	Scopes      []storage.APIKeyScope `json:"scopes,omitempty"`
+}
+
+// GrantsListResponse is the response for listing role grants.
+type GrantsListResponse struct {
+	Grants []GrantResponse `json:"grants"`
+}
+
+// CreateShareTokenRequest is the request body for creating a share token.
+type CreateShareTokenRequest struct {
+	Name      string `json:"name"`              // Required: who or what the token is for
+	Context   string `json:"context"`           // Required: registry context the token can read
+	Subject   string `json:"subject,omitempty"` // Optional: restricts the token to one subject
+	ExpiresIn int64  `json:"expires_in"`        // Required, duration in seconds (at most 365 days)
+}
+
+// ShareTokenResponse is the response for share token operations.
+type ShareTokenResponse struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	TokenPrefix string `json:"token_prefix"`
+	Context     string `json:"context"`
+	Subject     string `json:"subject,omitempty"`
+	CreatedBy   string `json:"created_by,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// CreateShareTokenResponse is the response for creating a share token.
+type CreateShareTokenResponse struct {
+	ID          int64  `json:"id"`
+	Token       string `json:"token"` // Raw token, only shown once
+	TokenPrefix string `json:"token_prefix"`
+	Name        string `json:"name"`
+	Context     string `json:"context"`
+	Subject     string `json:"subject,omitempty"`
+	CreatedBy   string `json:"created_by,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// ShareTokensListResponse is the response for listing share tokens.
+type ShareTokensListResponse struct {
+	ShareTokens []ShareTokenResponse `json:"share_tokens"`
+}
+
+// JobResponse describes an async job. Timestamps are omitted until the job
+// reaches the corresponding stage.
+type JobResponse struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	State           string `json:"state"`
+	Context         string `json:"context,omitempty"`
+	Done            int64  `json:"done"`
+	Total           int64  `json:"total"`
+	Message         string `json:"message,omitempty"`
+	Error           string `json:"error,omitempty"`
+	CancelRequested bool   `json:"cancel_requested"`
+	CreatedBy       string `json:"created_by,omitempty"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+	StartedAt       string `json:"started_at,omitempty"`
+	FinishedAt      string `json:"finished_at,omitempty"`
+}
+
+// JobsListResponse is the response for listing async jobs.
+type JobsListResponse struct {
+	Jobs []JobResponse `json:"jobs"`
+}
+
 // RolesListResponse is the response for listing available roles.
 type RolesListResponse struct {
 	Roles []RoleInfo `json:"roles"`
@@ -315,6 +821,97 @@ type RoleInfo struct {
 	Permissions []string `json:"permissions"`
 }
 
+// AuditEventsResponse is the response for querying the audit history.
+type AuditEventsResponse struct {
+	Events []*auth.AuditEvent `json:"events"`
+}
+
+// SchemaUsageResponse is the response for querying schema usage.
+type SchemaUsageResponse struct {
+	Since   string             `json:"since,omitempty"` // Start of the first UTC day counted; omitted when counting all history
+	Schemas []SchemaUsageEntry `json:"schemas"`
+}
+
+// SchemaUsageEntry is the number of fetches of one schema. Entries without
+// a subject count fetches by schema ID alone.
+type SchemaUsageEntry struct {
+	Context     string `json:"context"`
+	ID          int64  `json:"id"`
+	Subject     string `json:"subject,omitempty"`
+	Version     int    `json:"version,omitempty"`
+	Fetches     int64  `json:"fetches"`
+	LastFetched string `json:"last_fetched,omitempty"` // UTC day of the most recent fetch (YYYY-MM-DD)
+}
+
+// ClientUsageResponse is the response for querying client analytics.
+type ClientUsageResponse struct {
+	Clients []ClientUsageEntry `json:"clients"`
+}
+
+// ClientUsageEntry is the number of requests one client library version made
+// to an endpoint as a principal since the instance started.
+type ClientUsageEntry struct {
+	Client        string `json:"client"`
+	ClientVersion string `json:"client_version,omitempty"`
+	Method        string `json:"method"`
+	Endpoint      string `json:"endpoint"`
+	Principal     string `json:"principal,omitempty"`
+	Requests      int64  `json:"requests"`
+	FirstSeen     string `json:"first_seen"` // RFC 3339
+	LastSeen      string `json:"last_seen"`  // RFC 3339
+}
+
+// AuthCacheReportResponse is the result of a consistency check between the
+// auth caches and storage. Divergent entries were corrected by the check.
+type AuthCacheReportResponse struct {
+	Trigger         string `json:"trigger"`    // "scheduled" or "forced"
+	CheckedAt       string `json:"checked_at"` // RFC 3339
+	DurationMs      int64  `json:"duration_ms"`
+	Diverged        bool   `json:"diverged"`
+	APIKeys         int    `json:"api_keys"`
+	StaleAPIKeys    int    `json:"stale_api_keys"`
+	OrphanedAPIKeys int    `json:"orphaned_api_keys"`
+	MissingAPIKeys  int    `json:"missing_api_keys"`
+	CachedUsers     int    `json:"cached_users"`
+	StaleUsers      int    `json:"stale_users"`
+	Grants          int    `json:"grants"`
+	StaleGrants     bool   `json:"stale_grants"`
+}
+
+// CreateContextRequest is the request body for creating a context.
+type CreateContextRequest struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	Owner         string `json:"owner,omitempty"`
+	MaxSubjects   int    `json:"maxSubjects,omitempty"`
+	MaxSchemaSize int    `json:"maxSchemaSize,omitempty"`
+	Tenant        string `json:"tenant,omitempty"` // Ignored for tenant users, whose contexts join their own tenant
+}
+
+// ContextSettingsRequest is the request body for updating context settings.
+type ContextSettingsRequest struct {
+	Description   string `json:"description,omitempty"`
+	Owner         string `json:"owner,omitempty"`
+	MaxSubjects   int    `json:"maxSubjects,omitempty"`
+	MaxSchemaSize int    `json:"maxSchemaSize,omitempty"`
+}
+
+// ContextResponse is the response for context create and settings operations.
+type ContextResponse struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	Owner         string `json:"owner,omitempty"`
+	MaxSubjects   int    `json:"maxSubjects"`
+	MaxSchemaSize int    `json:"maxSchemaSize"`
+	Tenant        string `json:"tenant,omitempty"`
+}
+
+// DeleteContextResponse is the response for deleting a context.
+type DeleteContextResponse struct {
+	Name            string   `json:"name"`
+	DeletedSubjects []string `json:"deletedSubjects"`
+}
+
 // CreateExporterRequest is the request body for creating an exporter.
 type CreateExporterRequest struct {
 	Name                string            `json:"name"`
@@ -447,3 +1044,265 @@ type ImportSchemasResponse struct {
 	Errors   int                  `json:"errors"`
 	Results  []ImportSchemaResult `json:"results"`
 }
+
+// ImportSessionRequest is the request for opening an import session.
+type ImportSessionRequest struct {
+	Subjects []string `json:"subjects"`
+}
+
+// ImportSessionResponse describes an import session.
+type ImportSessionResponse struct {
+	ID        string   `json:"id"`
+	Context   string   `json:"context"`
+	Subjects  []string `json:"subjects"`
+	State     string   `json:"state"`
+	Staged    int      `json:"staged"`
+	CreatedBy string   `json:"created_by,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// ImportSessionsListResponse is the response for listing import sessions.
+type ImportSessionsListResponse struct {
+	Sessions []ImportSessionResponse `json:"sessions"`
+}
+
+// ImportSessionCommitResponse is the response for committing an import
+// session: the session and the result of importing its staged schemas.
+type ImportSessionCommitResponse struct {
+	Session ImportSessionResponse `json:"session"`
+	ImportSchemasResponse
+}
+
+// IDAliasesRequest is the request for mapping the schema IDs of a source
+// registry to the IDs of the schemas imported from it.
+type IDAliasesRequest struct {
+	Source  string    `json:"source"`
+	Aliases []IDAlias `json:"aliases"`
+}
+
+// IDAlias maps a schema ID of a source registry to a local schema ID.
+type IDAlias struct {
+	Source   string `json:"source,omitempty"`
+	SourceID int64  `json:"source_id"`
+	ID       int64  `json:"id"`
+}
+
+// IDAliasesResponse is the response for setting and listing schema ID aliases.
+type IDAliasesResponse struct {
+	Aliases []IDAlias `json:"aliases"`
+}
+
+// FreezeVersionRequest is the optional request body for
+// PUT /subjects/{subject}/versions/{version}/freeze.
+type FreezeVersionRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// FrozenVersionResponse describes a frozen version.
+type FrozenVersionResponse struct {
+	Subject  string `json:"subject"`
+	Version  int    `json:"version"`
+	FrozenBy string `json:"frozen_by,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	FrozenAt string `json:"frozen_at"`
+}
+
+// SetReaderRequest is the request body for
+// PUT /subjects/{subject}/readers/{consumer}.
+type SetReaderRequest struct {
+	ReaderSubject string `json:"reader_subject,omitempty"` // Defaults to the subject
+	ReaderVersion int    `json:"reader_version"`           // -1 for the latest version
+}
+
+// ReaderResponse describes the reader schema a consumer reads a subject with.
+type ReaderResponse struct {
+	Subject       string `json:"subject"`
+	Consumer      string `json:"consumer"`
+	ReaderSubject string `json:"reader_subject"`
+	ReaderVersion int    `json:"reader_version"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// ReadersResponse is the response for listing reader assertions.
+type ReadersResponse struct {
+	Readers []ReaderResponse `json:"readers"`
+}
+
+// ReaderCompatibility is the result of checking a proposed schema against
+// one consumer's reader schema.
+type ReaderCompatibility struct {
+	Consumer          string                          `json:"consumer"`
+	ReaderSubject     string                          `json:"reader_subject"`
+	ReaderVersion     int                             `json:"reader_version"`
+	IsCompatible      bool                            `json:"is_compatible"`
+	Messages          []string                        `json:"messages,omitempty"`
+	Incompatibilities []compatibility.Incompatibility `json:"incompatibilities,omitempty"`
+}
+
+// ReadersCompatibilityResponse is the response for checking a proposed
+// schema against a subject's versions and its consumers' reader schemas.
+// The top-level messages describe the check against the subject's versions.
+type ReadersCompatibilityResponse struct {
+	IsCompatible      bool                            `json:"is_compatible"`
+	Messages          []string                        `json:"messages,omitempty"`
+	Incompatibilities []compatibility.Incompatibility `json:"incompatibilities,omitempty"`
+	Readers           []ReaderCompatibility           `json:"readers"`
+}
+
+// ValidatePayloadResponse is the result of validating a JSON document
+// against a subject version's schema.
+type ValidatePayloadResponse struct {
+	Subject string                   `json:"subject"`
+	Version int                      `json:"version"`
+	ID      int64                    `json:"id"`
+	Valid   bool                     `json:"valid"`
+	Errors  []PayloadValidationError `json:"errors"`
+}
+
+// PayloadValidationError is a problem found in a validated document. The
+// locations are JSON pointers, as in the JSON Schema output format.
+type PayloadValidationError struct {
+	InstanceLocation string `json:"instanceLocation"`
+	KeywordLocation  string `json:"keywordLocation"`
+	Message          string `json:"message"`
+}
+
+// BundleManifest is the manifest.json at the root of a schema bundle import.
+// Each entry names a file in the archive and the subject, version and ID to
+// import it under.
+type BundleManifest struct {
+	Schemas []BundleManifestEntry `json:"schemas"`
+}
+
+// BundleManifestEntry describes a single schema file in a bundle import.
+type BundleManifestEntry struct {
+	File       string              `json:"file"`
+	ID         int64               `json:"id"`
+	Subject    string              `json:"subject"`
+	Version    int                 `json:"version"`
+	SchemaType string              `json:"schemaType,omitempty"`
+	References []storage.Reference `json:"references,omitempty"`
+}
+
+// TenantRequest is the request body for creating or updating a tenant.
+type TenantRequest struct {
+	Name        string `json:"name,omitempty"` // Required on create; ignored on update
+	Description string `json:"description,omitempty"`
+}
+
+// TenantResponse is the response for tenant operations.
+type TenantResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Encrypted   bool     `json:"encrypted"`
+	Contexts    []string `json:"contexts"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// TenantsListResponse is the response for listing tenants.
+type TenantsListResponse struct {
+	Tenants []TenantResponse `json:"tenants"`
+}
+
+// MaintenanceRequest is the request body for scheduling a maintenance window.
+type MaintenanceRequest struct {
+	StartsAt string `json:"starts_at,omitempty"` // RFC 3339; empty starts the window now
+	Duration string `json:"duration"`            // Go duration, e.g. "30m" or "2h"
+	Reason   string `json:"reason"`
+}
+
+// MaintenanceResponse describes a maintenance window.
+type MaintenanceResponse struct {
+	ID        string `json:"id"`
+	State     string `json:"state"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at"`
+	Reason    string `json:"reason"`
+	CreatedBy string `json:"created_by,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// MaintenanceListResponse is the response for listing maintenance windows.
+type MaintenanceListResponse struct {
+	Windows []MaintenanceResponse `json:"windows"`
+}
+
+// PendingSchemaResponse describes a registration held for approval.
+type PendingSchemaResponse struct {
+	ID          string              `json:"id"`
+	Context     string              `json:"context"`
+	Subject     string              `json:"subject"`
+	State       string              `json:"state"`
+	Schema      string              `json:"schema"`
+	SchemaType  string              `json:"schemaType,omitempty"`
+	References  []storage.Reference `json:"references,omitempty"`
+	Metadata    *storage.Metadata   `json:"metadata,omitempty"`
+	RuleSet     *storage.RuleSet    `json:"ruleSet,omitempty"`
+	Normalize   bool                `json:"normalize,omitempty"`
+	SubmittedBy string              `json:"submitted_by,omitempty"`
+	Reviewers   []string            `json:"reviewers,omitempty"`
+	ReviewedBy  string              `json:"reviewed_by,omitempty"`
+	Reason      string              `json:"reason,omitempty"`
+	SchemaID    int64               `json:"schema_id,omitempty"` // Set once approved
+	Version     int                 `json:"version,omitempty"`   // Set once approved
+	CreatedAt   string              `json:"created_at"`
+	UpdatedAt   string              `json:"updated_at"`
+}
+
+// PendingSchemasListResponse is the response for listing pending schemas.
+type PendingSchemasListResponse struct {
+	PendingSchemas []PendingSchemaResponse `json:"pending_schemas"`
+}
+
+// QuotaResponse describes a registration quota rule and its usage.
+type QuotaResponse struct {
+	Name                  string         `json:"name"`
+	Principals            []string       `json:"principals,omitempty"`
+	Roles                 []string       `json:"roles,omitempty"`
+	Contexts              []string       `json:"contexts,omitempty"`
+	MaxVersionsPerDay     int            `json:"max_versions_per_day,omitempty"`
+	MaxSubjectsPerContext int            `json:"max_subjects_per_context,omitempty"`
+	Day                   string         `json:"day"`
+	VersionsToday         map[string]int `json:"versions_today"`
+	SubjectsByContext     map[string]int `json:"subjects_by_context"`
+}
+
+// QuotasListResponse is the response for listing registration quotas.
+type QuotasListResponse struct {
+	Quotas []QuotaResponse `json:"quotas"`
+}
+
+// OrphanedReferenceResponse describes a reference of a subject version
+// that no longer resolves to the schema it was registered against.
+type OrphanedReferenceResponse struct {
+	Context   string            `json:"context"`
+	Subject   string            `json:"subject"`
+	Version   int               `json:"version"`
+	ID        int64             `json:"id"`
+	Deleted   bool              `json:"deleted"`
+	Reference storage.Reference `json:"reference"`
+	Kind      string            `json:"kind"`
+	Message   string            `json:"message"`
+}
+
+// OrphanedReferencesResponse is the response for listing orphaned
+// references.
+type OrphanedReferencesResponse struct {
+	Orphans []OrphanedReferenceResponse `json:"orphans"`
+}
+
+// ConfigReloadResponse is the result of reloading the configuration file.
+// Applied lists the changed settings now in effect; RestartRequired lists
+// the top-level sections with changes that need a restart.
+type ConfigReloadResponse struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// ReviewPendingSchemaRequest is the request body for approving or rejecting
+// a pending schema. A reason is required to reject.
+type ReviewPendingSchemaRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
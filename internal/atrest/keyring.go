@@ -0,0 +1,242 @@
+// Package atrest provides the instance data keys that encrypt schema text at
+// rest. Each instance generates a data key when it starts and wraps it with a
+// key encryption key (KEK): a static key from configuration or a key of a
+// Vault Transit engine. The wrapped data key is the name of the key, so every
+// sealed schema carries what is needed to unwrap its key, and rotating the
+// KEK only needs the old KEK to stay available until schemas are re-sealed.
+package atrest
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/axonops/axonops-schema-registry/internal/kms"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Key names of this keyring start with keyPrefix, which tenant names cannot,
+// followed by how the data key is wrapped:
+//
+//	@static.<kek name>.<base64url(nonce || wrapped key)>
+//	@transit.<transit key name>.<base64url(transit ciphertext)>
+const (
+	keyPrefix     = "@"
+	kindStatic    = "static"
+	kindTransit   = "transit"
+	dataKeySize   = 32
+	staticKEKSize = 32
+)
+
+// Config selects the KEK that wraps the data key of this instance. Exactly
+// one of ActiveKey and TransitKey may be set; with neither, schemas are
+// written in plaintext and the keyring only opens schemas sealed earlier,
+// which lets encryption be turned off.
+type Config struct {
+	// Keys are the static KEKs by name. Keys other than ActiveKey are only
+	// used to open schemas sealed under them.
+	Keys map[string][]byte
+	// ActiveKey names the static KEK that wraps the data key.
+	ActiveKey string
+	// Transit wraps data keys with Vault Transit. It is required for
+	// TransitKey, and to open schemas sealed under a transit key.
+	Transit kms.Provider
+	// TransitKey names the transit key that wraps the data key.
+	TransitKey string
+}
+
+// Keyring implements storage.SchemaKeyring with the data key of this
+// instance. With a tenant keyring, contexts owned by a tenant keep using the
+// tenant's key and every other context uses the instance key.
+type Keyring struct {
+	keks       map[string]cipher.AEAD
+	transit    kms.Provider
+	tenants    storage.SchemaKeyring
+	activeName string
+	active     cipher.AEAD
+
+	mu   sync.RWMutex
+	keys map[string]cipher.AEAD // key name -> unwrapped data key
+}
+
+// NewKeyring generates the data key of this instance and wraps it with the
+// configured KEK. tenants may be nil.
+func NewKeyring(ctx context.Context, cfg Config, tenants storage.SchemaKeyring) (*Keyring, error) {
+	k := &Keyring{
+		keks:    make(map[string]cipher.AEAD, len(cfg.Keys)),
+		transit: cfg.Transit,
+		tenants: tenants,
+		keys:    make(map[string]cipher.AEAD),
+	}
+	for name, key := range cfg.Keys {
+		if len(key) != staticKEKSize {
+			return nil, fmt.Errorf("key %s must be %d bytes, got %d", name, staticKEKSize, len(key))
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keks[name] = aead
+	}
+	if cfg.ActiveKey == "" && cfg.TransitKey == "" {
+		return k, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	var name string
+	var err error
+	if cfg.TransitKey != "" {
+		name, err = k.wrapTransit(ctx, cfg.TransitKey, dataKey)
+	} else {
+		name, err = k.wrapStatic(cfg.ActiveKey, dataKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if k.active, err = newAEAD(dataKey); err != nil {
+		return nil, err
+	}
+	k.activeName = name
+	k.keys[name] = k.active
+	return k, nil
+}
+
+// wrapStatic returns the name of dataKey wrapped with the named static KEK.
+func (k *Keyring) wrapStatic(kek string, dataKey []byte) (string, error) {
+	aead, ok := k.keks[kek]
+	if !ok {
+		return "", fmt.Errorf("unknown key %s", kek)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	wrapped := aead.Seal(nonce, nonce, dataKey, []byte(kek))
+	return keyPrefix + kindStatic + "." + kek + "." + base64.RawURLEncoding.EncodeToString(wrapped), nil
+}
+
+// wrapTransit returns the name of dataKey wrapped with the named transit key.
+func (k *Keyring) wrapTransit(ctx context.Context, transitKey string, dataKey []byte) (string, error) {
+	if k.transit == nil {
+		return "", fmt.Errorf("transit key %s configured without a transit provider", transitKey)
+	}
+	wrapped, err := k.transit.Wrap(ctx, transitKey, dataKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key with transit key %s: %w", transitKey, err)
+	}
+	return keyPrefix + kindTransit + "." + transitKey + "." + base64.RawURLEncoding.EncodeToString(wrapped), nil
+}
+
+// Encrypting reports whether new schemas are sealed with the instance key.
+func (k *Keyring) Encrypting() bool {
+	return k.active != nil
+}
+
+// KeyFor returns the tenant key of contexts owned by a tenant, and the
+// instance key, or a nil AEAD when encryption is off, for every other
+// context.
+func (k *Keyring) KeyFor(ctx context.Context, registryCtx string) (string, cipher.AEAD, error) {
+	if k.tenants != nil {
+		name, aead, err := k.tenants.KeyFor(ctx, registryCtx)
+		if err != nil || aead != nil {
+			return name, aead, err
+		}
+	}
+	return k.activeName, k.active, nil
+}
+
+// Key returns the AEAD of the named key, unwrapping instance data keys with
+// their KEK and passing other names to the tenant keyring.
+func (k *Keyring) Key(ctx context.Context, name string) (cipher.AEAD, error) {
+	if !strings.HasPrefix(name, keyPrefix) {
+		if k.tenants == nil {
+			return nil, fmt.Errorf("tenant keys are not configured: %w", storage.ErrSchemaKeyUnavailable)
+		}
+		return k.tenants.Key(ctx, name)
+	}
+
+	k.mu.RLock()
+	aead, ok := k.keys[name]
+	k.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	dataKey, err := k.unwrap(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if aead, err = newAEAD(dataKey); err != nil {
+		return nil, err
+	}
+	k.mu.Lock()
+	k.keys[name] = aead
+	k.mu.Unlock()
+	return aead, nil
+}
+
+// unwrap returns the data key of a key name.
+func (k *Keyring) unwrap(ctx context.Context, name string) ([]byte, error) {
+	kind, rest, _ := strings.Cut(strings.TrimPrefix(name, keyPrefix), ".")
+	kek, encoded, ok := strings.Cut(rest, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed data key name: %w", storage.ErrSchemaKeyUnavailable)
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed data key name: %w", storage.ErrSchemaKeyUnavailable)
+	}
+
+	switch kind {
+	case kindStatic:
+		aead, ok := k.keks[kek]
+		if !ok {
+			return nil, fmt.Errorf("key %s is not configured: %w", kek, storage.ErrSchemaKeyUnavailable)
+		}
+		if len(wrapped) < aead.NonceSize() {
+			return nil, fmt.Errorf("malformed data key name: %w", storage.ErrSchemaKeyUnavailable)
+		}
+		dataKey, err := aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(kek))
+		if err != nil {
+			return nil, fmt.Errorf("data key cannot be unwrapped with key %s: %w", kek, storage.ErrSchemaKeyUnavailable)
+		}
+		return dataKey, nil
+	case kindTransit:
+		if k.transit == nil {
+			return nil, fmt.Errorf("transit key %s: no transit provider is configured: %w", kek, storage.ErrSchemaKeyUnavailable)
+		}
+		dataKey, err := k.transit.Unwrap(ctx, kek, wrapped, nil)
+		if err != nil {
+			// Vault being unreachable is not a missing key; leave the
+			// error unwrapped so it is reported as a storage failure.
+			return nil, fmt.Errorf("failed to unwrap data key with transit key %s: %w", kek, err)
+		}
+		return dataKey, nil
+	default:
+		return nil, fmt.Errorf("unknown data key kind %q: %w", kind, storage.ErrSchemaKeyUnavailable)
+	}
+}
+
+// Forget drops anything the tenant keyring cached about registryCtx.
+func (k *Keyring) Forget(registryCtx string) {
+	if k.tenants != nil {
+		k.tenants.Forget(registryCtx)
+	}
+}
+
+// newAEAD returns an AES-GCM AEAD for key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
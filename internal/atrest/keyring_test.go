@@ -0,0 +1,223 @@
+package atrest
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+// fakeTransit wraps keys by XOR with a per-key byte, standing in for Vault.
+type fakeTransit struct {
+	keys map[string]byte
+	err  error
+}
+
+func (f *fakeTransit) xor(keyID string, in []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	b, ok := f.keys[keyID]
+	if !ok {
+		return nil, errors.New("no such transit key")
+	}
+	out := make([]byte, len(in))
+	for i := range in {
+		out[i] = in[i] ^ b
+	}
+	return out, nil
+}
+
+func (f *fakeTransit) Wrap(_ context.Context, keyID string, plaintext []byte, _ map[string]string) ([]byte, error) {
+	return f.xor(keyID, plaintext)
+}
+
+func (f *fakeTransit) Unwrap(_ context.Context, keyID string, ciphertext []byte, _ map[string]string) ([]byte, error) {
+	return f.xor(keyID, ciphertext)
+}
+
+func (f *fakeTransit) GenerateDataKey(context.Context, string, string, map[string]string) ([]byte, []byte, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (f *fakeTransit) Type() string { return "fake" }
+func (f *fakeTransit) Close() error { return nil }
+
+func staticKeys(names ...string) map[string][]byte {
+	keys := make(map[string][]byte, len(names))
+	for i, name := range names {
+		keys[name] = bytes.Repeat([]byte{byte(i + 1)}, staticKEKSize)
+	}
+	return keys
+}
+
+func newTestKeyring(t *testing.T, cfg Config, tenants storage.SchemaKeyring) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(context.Background(), cfg, tenants)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return k
+}
+
+// registerSchema stores schema in subject s of registryCtx and returns its ID.
+func registerSchema(t *testing.T, store storage.Storage, registryCtx, schema string) int64 {
+	t.Helper()
+	rec := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: schema, Fingerprint: "fp-" + schema}
+	if err := store.CreateSchema(context.Background(), registryCtx, rec); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+	return rec.ID
+}
+
+func readSchema(store storage.Storage, id int64) (string, error) {
+	rec, err := store.GetSchemaByID(context.Background(), ".", id)
+	if err != nil {
+		return "", err
+	}
+	return rec.Schema, nil
+}
+
+func TestKeyring_StaticKeyRotation(t *testing.T) {
+	backend := memory.NewStore()
+	keys := staticKeys("k1", "k2")
+
+	// Schemas written under k1...
+	store := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: keys, ActiveKey: "k1"}, nil))
+	id := registerSchema(t, store, ".", `"string"`)
+	raw, _ := readSchema(backend, id)
+	if !strings.Contains(raw, "@static.k1.") || strings.Contains(raw, "string") {
+		t.Fatalf("expected the schema to be sealed under k1, got %q", raw)
+	}
+
+	// ...are still read by an instance that wraps its key with k2.
+	rotated := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: keys, ActiveKey: "k2"}, nil))
+	if got, err := readSchema(rotated, id); err != nil || got != `"string"` {
+		t.Fatalf("expected the schema under k1 to be readable after rotation, got %q, %v", got, err)
+	}
+
+	// Rewriting re-seals it under k2, after which k1 can be removed.
+	if err := rotated.RewriteSchemaText(context.Background(), ".", id, `"string"`); err != nil {
+		t.Fatalf("RewriteSchemaText: %v", err)
+	}
+	raw, _ = readSchema(backend, id)
+	if !strings.Contains(raw, "@static.k2.") {
+		t.Fatalf("expected the schema to be sealed under k2, got %q", raw)
+	}
+	withoutK1 := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: map[string][]byte{"k2": keys["k2"]}, ActiveKey: "k2"}, nil))
+	if got, err := readSchema(withoutK1, id); err != nil || got != `"string"` {
+		t.Errorf("expected the re-sealed schema to be readable without k1, got %q, %v", got, err)
+	}
+}
+
+func TestKeyring_MissingKey(t *testing.T) {
+	backend := memory.NewStore()
+	store := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: staticKeys("k1"), ActiveKey: "k1"}, nil))
+	id := registerSchema(t, store, ".", `"string"`)
+
+	other := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: staticKeys("k2"), ActiveKey: "k2"}, nil))
+	if _, err := readSchema(other, id); !errors.Is(err, storage.ErrSchemaKeyUnavailable) {
+		t.Errorf("expected ErrSchemaKeyUnavailable without k1, got %v", err)
+	}
+}
+
+func TestKeyring_DecryptOnly(t *testing.T) {
+	backend := memory.NewStore()
+	keys := staticKeys("k1")
+	store := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: keys, ActiveKey: "k1"}, nil))
+	id := registerSchema(t, store, ".", `"string"`)
+
+	// Without an active key, new schemas are written in plaintext and
+	// rewriting decrypts the old ones.
+	k := newTestKeyring(t, Config{Keys: keys}, nil)
+	if k.Encrypting() {
+		t.Error("expected a keyring without an active key not to encrypt")
+	}
+	decrypting := storage.NewEncryptedStorage(backend, k)
+	if err := decrypting.RewriteSchemaText(context.Background(), ".", id, `"string"`); err != nil {
+		t.Fatalf("RewriteSchemaText: %v", err)
+	}
+	if raw, _ := readSchema(backend, id); raw != `"string"` {
+		t.Errorf("expected the schema to be stored in plaintext, got %q", raw)
+	}
+}
+
+func TestKeyring_Transit(t *testing.T) {
+	backend := memory.NewStore()
+	transit := &fakeTransit{keys: map[string]byte{"schemas": 0x5a}}
+	store := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Transit: transit, TransitKey: "schemas"}, nil))
+	id := registerSchema(t, store, ".", `"string"`)
+	if raw, _ := readSchema(backend, id); !strings.Contains(raw, "@transit.schemas.") {
+		t.Fatalf("expected the schema to be sealed under the transit key, got %q", raw)
+	}
+
+	// Another instance unwraps the data key through the transit engine.
+	other := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Transit: transit, TransitKey: "schemas"}, nil))
+	if got, err := readSchema(other, id); err != nil || got != `"string"` {
+		t.Fatalf("expected the schema to be readable by another instance, got %q, %v", got, err)
+	}
+
+	// An unreachable transit engine is an error, not a missing key.
+	transit.err = errors.New("connection refused")
+	third := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: staticKeys("k1"), ActiveKey: "k1", Transit: transit}, nil))
+	if _, err := readSchema(third, id); err == nil || errors.Is(err, storage.ErrSchemaKeyUnavailable) {
+		t.Errorf("expected a transit failure, got %v", err)
+	}
+
+	if _, err := NewKeyring(context.Background(), Config{TransitKey: "schemas"}, nil); err == nil {
+		t.Error("expected an error for a transit key without a provider")
+	}
+}
+
+// tenantKeys seals the .acme context with a key named "acme".
+type tenantKeys struct {
+	aead cipher.AEAD
+}
+
+func (t tenantKeys) KeyFor(_ context.Context, registryCtx string) (string, cipher.AEAD, error) {
+	if registryCtx == ".acme" {
+		return "acme", t.aead, nil
+	}
+	return "", nil, nil
+}
+
+func (t tenantKeys) Key(_ context.Context, name string) (cipher.AEAD, error) {
+	if name == "acme" {
+		return t.aead, nil
+	}
+	return nil, storage.ErrSchemaKeyUnavailable
+}
+
+func (tenantKeys) Forget(string) {}
+
+func TestKeyring_Tenants(t *testing.T) {
+	aead, err := newAEAD(bytes.Repeat([]byte{9}, dataKeySize))
+	if err != nil {
+		t.Fatalf("newAEAD: %v", err)
+	}
+	backend := memory.NewStore()
+	store := storage.NewEncryptedStorage(backend, newTestKeyring(t, Config{Keys: staticKeys("k1"), ActiveKey: "k1"}, tenantKeys{aead}))
+
+	tenantID := registerSchema(t, store, ".acme", `"string"`)
+	sharedID := registerSchema(t, store, ".", `"string"`)
+
+	rec, err := backend.GetSchemaByID(context.Background(), ".acme", tenantID)
+	if err != nil {
+		t.Fatalf("GetSchemaByID: %v", err)
+	}
+	if !strings.HasPrefix(rec.Schema, "enc:v1:acme:") {
+		t.Errorf("expected the tenant context to keep the tenant key, got %q", rec.Schema)
+	}
+	if raw, _ := readSchema(backend, sharedID); !strings.Contains(raw, "@static.k1.") {
+		t.Errorf("expected other contexts to use the instance key, got %q", raw)
+	}
+	rec, err = store.GetSchemaByID(context.Background(), ".acme", tenantID)
+	if err != nil || rec.Schema != `"string"` {
+		t.Errorf("expected the tenant schema to be readable, got %v", err)
+	}
+}
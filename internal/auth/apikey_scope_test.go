@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestAuthorizeEndpoint_APIKeyScopes(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	handler := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	// An admin key that CI may only use to register and read "orders-"
+	// subjects in the .ci context.
+	user := &User{APIKeyID: 10, Username: "ci", Role: "admin", Method: "api_key", Scopes: []storage.APIKeyScope{
+		{Context: ".ci", SubjectPrefix: "orders-", Operations: []string{"schema:read", "schema:write"}},
+	}}
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"POST", "/contexts/.ci/subjects/orders-value/versions", http.StatusOK},
+		{"POST", "/subjects/:.ci:orders-value/versions", http.StatusOK},
+		{"GET", "/contexts/.ci/subjects/orders-value/versions/latest", http.StatusOK},
+		{"GET", "/schemas/ids/1", http.StatusOK},
+		{"DELETE", "/contexts/.ci/subjects/orders-value", http.StatusForbidden},
+		{"POST", "/contexts/.ci/subjects/payments-value/versions", http.StatusForbidden},
+		{"POST", "/subjects/orders-value/versions", http.StatusForbidden},
+		{"PUT", "/contexts/.ci/config/orders-value", http.StatusForbidden},
+		{"GET", "/contexts/.ci/subjects", http.StatusForbidden},
+		{"GET", "/admin/users", http.StatusForbidden},
+		{"POST", "/import/schemas", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req = req.WithContext(setUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestHasScopedPermission_APIKeyScopes(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	authorizer.SetGrantProvider(staticGrants{
+		{ID: 1, APIKeyID: 10, Role: "admin", Context: ".prod"},
+	})
+	ctx := context.Background()
+	user := &User{APIKeyID: 10, Username: "ci", Role: "developer", Method: "api_key", Scopes: []storage.APIKeyScope{{Context: ".ci"}}}
+
+	if !authorizer.HasScopedPermission(ctx, user, PermissionSchemaWrite, ResourceScope{Context: ".ci", Subject: "x"}) {
+		t.Error("expected the role to apply within the scope")
+	}
+	if authorizer.HasScopedPermission(ctx, user, PermissionSchemaDelete, ResourceScope{Context: ".ci", Subject: "x"}) {
+		t.Error("expected a scope not to grant what the role lacks")
+	}
+	if authorizer.HasScopedPermission(ctx, user, PermissionSchemaWrite, ResourceScope{Context: ".prod", Subject: "x"}) {
+		t.Error("expected a grant outside the scopes to be refused")
+	}
+}
+
+func TestService_APIKeyScopes(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	svc := NewService(store)
+	defer svc.Close()
+
+	owner, err := svc.CreateUser(ctx, CreateUserRequest{Username: "ci-owner", Password: "password123", Role: "admin", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	_, err = svc.CreateAPIKey(ctx, CreateAPIKeyRequest{
+		UserID: owner.ID, Name: "bad", Role: "developer", ExpiresAt: time.Now().Add(time.Hour),
+		Scopes: []storage.APIKeyScope{{Context: ".ci", Operations: []string{"admin:write"}}},
+	})
+	if !errors.Is(err, ErrInvalidAPIKeyScope) {
+		t.Errorf("expected ErrInvalidAPIKeyScope for an instance-wide operation, got %v", err)
+	}
+
+	created, err := svc.CreateAPIKey(ctx, CreateAPIKeyRequest{
+		UserID: owner.ID, Name: "ci", Role: "developer", ExpiresAt: time.Now().Add(time.Hour),
+		Scopes: []storage.APIKeyScope{{Context: "ci", SubjectPrefix: "orders-"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if len(created.Scopes) != 1 || created.Scopes[0].Context != ".ci" {
+		t.Errorf("expected the scope's context to be normalized, got %+v", created.Scopes)
+	}
+
+	record, err := svc.ValidateAPIKey(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("ValidateAPIKey: %v", err)
+	}
+	if !storage.EqualAPIKeyScopes(record.Scopes, created.Scopes) {
+		t.Errorf("expected stored scopes %+v, got %+v", created.Scopes, record.Scopes)
+	}
+
+	rotated, err := svc.RotateAPIKey(ctx, created.ID, time.Now().Add(time.Hour), nil)
+	if err != nil {
+		t.Fatalf("RotateAPIKey: %v", err)
+	}
+	if !storage.EqualAPIKeyScopes(rotated.Scopes, created.Scopes) {
+		t.Errorf("expected rotation to keep the scopes, got %+v", rotated.Scopes)
+	}
+	rotated, err = svc.RotateAPIKey(ctx, rotated.ID, time.Now().Add(time.Hour), []storage.APIKeyScope{})
+	if err != nil {
+		t.Fatalf("RotateAPIKey: %v", err)
+	}
+	if len(rotated.Scopes) != 0 {
+		t.Errorf("expected an empty list to remove the scopes, got %+v", rotated.Scopes)
+	}
+}
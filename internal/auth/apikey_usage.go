@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// DefaultAPIKeyUsageFlushInterval is the default interval for writing
+// buffered API key usage to storage.
+const DefaultAPIKeyUsageFlushInterval = 30 * time.Second
+
+// apiKeyUsageBucket identifies the requests of one API key on one UTC day.
+type apiKeyUsageBucket struct {
+	id  int64
+	day time.Time
+}
+
+// pendingAPIKeyUsage is the usage of a bucket not yet written to storage.
+type pendingAPIKeyUsage struct {
+	requests int64
+	lastUsed time.Time
+}
+
+// APIKeyUsage is the number of requests an API key authenticated since a
+// point in time, in total and per UTC day.
+type APIKeyUsage struct {
+	APIKeyID int64
+	Requests int64
+	Days     []APIKeyUsageDay // Oldest first; days without requests are left out
+}
+
+// APIKeyUsageDay is the number of requests an API key authenticated on one
+// UTC day.
+type APIKeyUsageDay struct {
+	Day      time.Time
+	Requests int64
+}
+
+// recordAPIKeyUse counts a request authenticated by an API key. It only
+// touches memory; the count is written to storage by the next flush.
+func (s *Service) recordAPIKeyUse(id int64, at time.Time) {
+	b := apiKeyUsageBucket{id, usageDay(at)}
+	s.apiKeyUsageMu.Lock()
+	defer s.apiKeyUsageMu.Unlock()
+	u := s.apiKeyUsage[b]
+	u.requests++
+	if at.After(u.lastUsed) {
+		u.lastUsed = at
+	}
+	s.apiKeyUsage[b] = u
+}
+
+// runUsageFlush writes buffered API key usage to storage every flush
+// interval, and once more when the service is closed.
+func (s *Service) runUsageFlush() {
+	defer close(s.usageFlushDone)
+
+	ticker := time.NewTicker(s.usageFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := s.backgroundContext()
+			s.FlushAPIKeyUsage(ctx)
+			cancel()
+		case <-s.stopCacheRefresh:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			s.FlushAPIKeyUsage(ctx)
+			cancel()
+			return
+		}
+	}
+}
+
+// FlushAPIKeyUsage writes the API key usage buffered since the last flush
+// to storage, which also moves the keys' last-used times forward. If the
+// write fails, the usage is kept and retried on the next flush.
+func (s *Service) FlushAPIKeyUsage(ctx context.Context) {
+	s.apiKeyUsageMu.Lock()
+	pending := s.apiKeyUsage
+	s.apiKeyUsage = make(map[apiKeyUsageBucket]pendingAPIKeyUsage)
+	s.apiKeyUsageMu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	records := make([]*storage.APIKeyUsageRecord, 0, len(pending))
+	for b, u := range pending {
+		records = append(records, &storage.APIKeyUsageRecord{
+			APIKeyID: b.id,
+			Day:      b.day,
+			Requests: u.requests,
+			LastUsed: u.lastUsed,
+		})
+	}
+	if err := s.storage.AddAPIKeyUsage(ctx, records); err != nil {
+		slog.Error("failed to flush API key usage", "buckets", len(records), "error", err)
+		s.apiKeyUsageMu.Lock()
+		for b, u := range pending {
+			merged := s.apiKeyUsage[b]
+			merged.requests += u.requests
+			if u.lastUsed.After(merged.lastUsed) {
+				merged.lastUsed = u.lastUsed
+			}
+			s.apiKeyUsage[b] = merged
+		}
+		s.apiKeyUsageMu.Unlock()
+	}
+}
+
+// APIKeyUsage returns the requests each API key authenticated since the
+// given time, including those not yet flushed, ordered by key ID. Counts
+// are kept per day, so since is rounded down to the start of its UTC day.
+// Keys without requests in the period are left out.
+func (s *Service) APIKeyUsage(ctx context.Context, since time.Time) ([]APIKeyUsage, error) {
+	stored, err := s.storage.ListAPIKeyUsage(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	since = usageDay(since)
+	days := make(map[apiKeyUsageBucket]int64)
+	for _, rec := range stored {
+		days[apiKeyUsageBucket{rec.APIKeyID, usageDay(rec.Day)}] += rec.Requests
+	}
+	s.apiKeyUsageMu.Lock()
+	for b, u := range s.apiKeyUsage {
+		days[b] += u.requests
+	}
+	s.apiKeyUsageMu.Unlock()
+
+	totals := make(map[int64]*APIKeyUsage)
+	for b, requests := range days {
+		if b.day.Before(since) || requests == 0 {
+			continue
+		}
+		u := totals[b.id]
+		if u == nil {
+			u = &APIKeyUsage{APIKeyID: b.id}
+			totals[b.id] = u
+		}
+		u.Requests += requests
+		u.Days = append(u.Days, APIKeyUsageDay{Day: b.day, Requests: requests})
+	}
+
+	out := make([]APIKeyUsage, 0, len(totals))
+	for _, u := range totals {
+		sort.Slice(u.Days, func(i, j int) bool { return u.Days[i].Day.Before(u.Days[j].Day) })
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].APIKeyID < out[j].APIKeyID })
+	return out, nil
+}
+
+// withPendingLastUsed returns the keys with their last-used times moved
+// forward to requests not yet flushed. Keys that change are copied, so
+// records held by storage or the cache are never modified.
+func (s *Service) withPendingLastUsed(keys ...*storage.APIKeyRecord) []*storage.APIKeyRecord {
+	s.apiKeyUsageMu.Lock()
+	defer s.apiKeyUsageMu.Unlock()
+	if len(s.apiKeyUsage) == 0 {
+		return keys
+	}
+	lastUsed := make(map[int64]time.Time)
+	for b, u := range s.apiKeyUsage {
+		if u.lastUsed.After(lastUsed[b.id]) {
+			lastUsed[b.id] = u.lastUsed
+		}
+	}
+	out := make([]*storage.APIKeyRecord, len(keys))
+	for i, key := range keys {
+		out[i] = key
+		t, ok := lastUsed[key.ID]
+		if !ok || (key.LastUsed != nil && !t.After(*key.LastUsed)) {
+			continue
+		}
+		updated := *key
+		updated.LastUsed = &t
+		out[i] = &updated
+	}
+	return out
+}
+
+// usageDay returns midnight UTC of the day containing t.
+func usageDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
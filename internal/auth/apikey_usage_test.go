@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestAPIKeyUsage_BufferedUntilFlush(t *testing.T) {
+	store := memory.NewStore()
+	svc := NewServiceWithConfig(store, ServiceConfig{APIKeyUsageFlushInterval: time.Hour})
+	defer svc.Close()
+	ctx := context.Background()
+
+	user := &storage.UserRecord{Username: "ci", PasswordHash: "h", Role: "developer", Enabled: true}
+	if err := store.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	key, err := svc.CreateAPIKey(ctx, CreateAPIKeyRequest{UserID: user.ID, Name: "ci", Role: "readonly", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := svc.ValidateAPIKey(ctx, key.Key); err != nil {
+			t.Fatalf("ValidateAPIKey: %v", err)
+		}
+	}
+
+	// Nothing is written before the flush, but the service already reports
+	// the requests and the last-used time.
+	if stored, _ := store.ListAPIKeyUsage(ctx, time.Time{}); len(stored) != 0 {
+		t.Errorf("expected no stored usage before the flush, got %d records", len(stored))
+	}
+	if rec, _ := store.GetAPIKeyByID(ctx, key.ID); rec.LastUsed != nil {
+		t.Error("expected the stored last-used time to be unset before the flush")
+	}
+	if rec, err := svc.GetAPIKeyByID(ctx, key.ID); err != nil || rec.LastUsed == nil {
+		t.Errorf("expected the pending last-used time to be reported, got %v (err %v)", rec, err)
+	}
+	assertAPIKeyRequests(t, svc, key.ID, 4)
+
+	svc.FlushAPIKeyUsage(ctx)
+	if rec, _ := store.GetAPIKeyByID(ctx, key.ID); rec.LastUsed == nil {
+		t.Error("expected the stored last-used time to be set after the flush")
+	}
+	assertAPIKeyRequests(t, svc, key.ID, 4)
+
+	if _, err := svc.ValidateAPIKey(ctx, key.Key); err != nil {
+		t.Fatalf("ValidateAPIKey: %v", err)
+	}
+	assertAPIKeyRequests(t, svc, key.ID, 5)
+
+	tomorrow := time.Now().UTC().Add(24 * time.Hour)
+	if usage, _ := svc.APIKeyUsage(ctx, tomorrow); len(usage) != 0 {
+		t.Errorf("expected no usage since tomorrow, got %+v", usage)
+	}
+}
+
+func TestAPIKeyUsage_FlushedOnClose(t *testing.T) {
+	store := memory.NewStore()
+	svc := NewServiceWithConfig(store, ServiceConfig{APIKeyUsageFlushInterval: time.Hour})
+	ctx := context.Background()
+
+	user := &storage.UserRecord{Username: "ci", PasswordHash: "h", Role: "developer", Enabled: true}
+	if err := store.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	key, err := svc.CreateAPIKey(ctx, CreateAPIKeyRequest{UserID: user.ID, Name: "ci", Role: "readonly", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if _, err := svc.ValidateAPIKey(ctx, key.Key); err != nil {
+		t.Fatalf("ValidateAPIKey: %v", err)
+	}
+
+	svc.Close()
+	stored, err := store.ListAPIKeyUsage(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("ListAPIKeyUsage: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Requests != 1 {
+		t.Errorf("expected the request to be flushed on close, got %d records", len(stored))
+	}
+}
+
+func assertAPIKeyRequests(t *testing.T, svc *Service, id int64, want int64) {
+	t.Helper()
+	usage, err := svc.APIKeyUsage(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("APIKeyUsage: %v", err)
+	}
+	if len(usage) != 1 || usage[0].APIKeyID != id || usage[0].Requests != want {
+		t.Errorf("expected %d requests for key %d, got %+v", want, id, usage)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -86,6 +87,10 @@ const (
 	AuditEventSchemaGet             AuditEventType = "schema_get"
 	AuditEventSchemaLookup          AuditEventType = "schema_lookup"
 	AuditEventSchemaImport          AuditEventType = "schema_import"
+	AuditEventVersionFreeze         AuditEventType = "version_freeze"
+	AuditEventVersionUnfreeze       AuditEventType = "version_unfreeze"
+	AuditEventReaderSet             AuditEventType = "reader_set"
+	AuditEventReaderDelete          AuditEventType = "reader_delete"
 
 	// Config events
 	AuditEventConfigGet    AuditEventType = "config_get"
@@ -98,25 +103,42 @@ const (
 	AuditEventModeDelete AuditEventType = "mode_delete"
 
 	// Auth events
-	AuditEventAuthSuccess   AuditEventType = "auth_success"
-	AuditEventAuthFailure   AuditEventType = "auth_failure"
-	AuditEventAuthForbidden AuditEventType = "auth_forbidden"
+	AuditEventAuthSuccess    AuditEventType = "auth_success"
+	AuditEventAuthFailure    AuditEventType = "auth_failure"
+	AuditEventAuthForbidden  AuditEventType = "auth_forbidden"
+	AuditEventSessionCreate  AuditEventType = "session_create"
+	AuditEventSessionRefresh AuditEventType = "session_refresh"
+	AuditEventSessionRevoke  AuditEventType = "session_revoke"
 
 	// Subject events
 	AuditEventSubjectDeleteSoft      AuditEventType = "subject_delete_soft"
 	AuditEventSubjectDeletePermanent AuditEventType = "subject_delete_permanent"
 	AuditEventSubjectList            AuditEventType = "subject_list"
+	AuditEventSubjectMetaSet         AuditEventType = "subject_meta_set"
+	AuditEventSubjectMetaDelete      AuditEventType = "subject_meta_delete"
+
+	// A permanent delete that awaits confirmation
+	AuditEventDeleteConfirmationIssued AuditEventType = "delete_confirmation_issued"
 
 	// Admin events
-	AuditEventUserCreate     AuditEventType = "user_create"
-	AuditEventUserUpdate     AuditEventType = "user_update"
-	AuditEventUserDelete     AuditEventType = "user_delete"
-	AuditEventPasswordChange AuditEventType = "password_change"
-	AuditEventAPIKeyCreate   AuditEventType = "apikey_create"
-	AuditEventAPIKeyUpdate   AuditEventType = "apikey_update"
-	AuditEventAPIKeyDelete   AuditEventType = "apikey_delete"
-	AuditEventAPIKeyRevoke   AuditEventType = "apikey_revoke"
-	AuditEventAPIKeyRotate   AuditEventType = "apikey_rotate"
+	AuditEventUserCreate           AuditEventType = "user_create"
+	AuditEventUserUpdate           AuditEventType = "user_update"
+	AuditEventUserDelete           AuditEventType = "user_delete"
+	AuditEventPasswordChange       AuditEventType = "password_change"
+	AuditEventAPIKeyCreate         AuditEventType = "apikey_create"
+	AuditEventAPIKeyUpdate         AuditEventType = "apikey_update"
+	AuditEventAPIKeyDelete         AuditEventType = "apikey_delete"
+	AuditEventAPIKeyRevoke         AuditEventType = "apikey_revoke"
+	AuditEventAPIKeyRotate         AuditEventType = "apikey_rotate"
+	AuditEventGrantCreate          AuditEventType = "grant_create"
+	AuditEventGrantDelete          AuditEventType = "grant_delete"
+	AuditEventShareTokenCreate     AuditEventType = "share_token_create"
+	AuditEventShareTokenDelete     AuditEventType = "share_token_delete"
+	AuditEventMaintenanceSchedule  AuditEventType = "maintenance_schedule"
+	AuditEventMaintenanceCancel    AuditEventType = "maintenance_cancel"
+	AuditEventPendingSchemaSubmit  AuditEventType = "pending_schema_submit"
+	AuditEventPendingSchemaApprove AuditEventType = "pending_schema_approve"
+	AuditEventPendingSchemaReject  AuditEventType = "pending_schema_reject"
 
 	// Encryption events (KEK/DEK)
 	AuditEventKEKCreate          AuditEventType = "kek_create"
@@ -142,9 +164,15 @@ const (
 	AuditEventExporterReset        AuditEventType = "exporter_reset"
 	AuditEventExporterConfigUpdate AuditEventType = "exporter_config_update"
 
+	// Context management events
+	AuditEventContextCreate AuditEventType = "context_create"
+	AuditEventContextUpdate AuditEventType = "context_update"
+	AuditEventContextDelete AuditEventType = "context_delete"
+
 	// Server lifecycle events
 	AuditEventServerStartup  AuditEventType = "server_startup"
 	AuditEventServerShutdown AuditEventType = "server_shutdown"
+	AuditEventConfigReload   AuditEventType = "config_reload"
 
 	// MCP events
 	AuditEventMCPToolCall    AuditEventType = "mcp_tool_call"
@@ -225,6 +253,7 @@ type AuditLogger struct {
 	metrics       AuditMetrics
 	mu            sync.Mutex
 	enabledEvents map[AuditEventType]bool
+	history       *AuditHistory // nil unless audit.history.enabled
 
 	// Async delivery fields. When ch is non-nil, Log() enqueues events
 	// for a background goroutine instead of writing synchronously.
@@ -250,6 +279,10 @@ func NewAuditLogger(cfg config.AuditConfig) (*AuditLogger, error) {
 		}
 	}
 
+	if cfg.History.Enabled {
+		al.history = NewAuditHistory(cfg.History.MaxEvents)
+	}
+
 	// Determine which outputs to configure.
 	// Priority: new outputs config > legacy log_file > default (stdout).
 	hasExplicitOutputs := cfg.Outputs.Stdout.Enabled || cfg.Outputs.File.Enabled ||
@@ -361,6 +394,10 @@ func NewAuditLoggerWithWriter(cfg config.AuditConfig, w io.Writer) *AuditLogger
 		}
 	}
 
+	if cfg.History.Enabled {
+		al.history = NewAuditHistory(cfg.History.MaxEvents)
+	}
+
 	al.outputs = append(al.outputs, formattedOutput{
 		output:     &WriterOutput{w: w, name: "writer"},
 		formatType: "json",
@@ -368,6 +405,11 @@ func NewAuditLoggerWithWriter(cfg config.AuditConfig, w io.Writer) *AuditLogger
 	return al
 }
 
+// History returns the in-memory audit history, or nil if it is not enabled.
+func (al *AuditLogger) History() *AuditHistory {
+	return al.history
+}
+
 // Close drains any pending async events and closes all audit outputs.
 // If the drain does not complete within 5 seconds, it proceeds to close outputs.
 // Close is safe to call multiple times.
@@ -410,6 +452,10 @@ func setDefaultEnabledEvents(m map[AuditEventType]bool) {
 	m[AuditEventSchemaDeleteSoft] = true
 	m[AuditEventSchemaDeletePermanent] = true
 	m[AuditEventSchemaImport] = true
+	m[AuditEventVersionFreeze] = true
+	m[AuditEventVersionUnfreeze] = true
+	m[AuditEventReaderSet] = true
+	m[AuditEventReaderDelete] = true
 	m[AuditEventSchemaLookup] = true
 
 	// Compatibility check
@@ -424,10 +470,16 @@ func setDefaultEnabledEvents(m map[AuditEventType]bool) {
 	// Auth events
 	m[AuditEventAuthFailure] = true
 	m[AuditEventAuthForbidden] = true
+	m[AuditEventSessionCreate] = true
+	m[AuditEventSessionRefresh] = true
+	m[AuditEventSessionRevoke] = true
 
 	// Subject events
 	m[AuditEventSubjectDeleteSoft] = true
 	m[AuditEventSubjectDeletePermanent] = true
+	m[AuditEventSubjectMetaSet] = true
+	m[AuditEventSubjectMetaDelete] = true
+	m[AuditEventDeleteConfirmationIssued] = true
 
 	// Admin events
 	m[AuditEventUserCreate] = true
@@ -439,6 +491,15 @@ func setDefaultEnabledEvents(m map[AuditEventType]bool) {
 	m[AuditEventAPIKeyDelete] = true
 	m[AuditEventAPIKeyRevoke] = true
 	m[AuditEventAPIKeyRotate] = true
+	m[AuditEventGrantCreate] = true
+	m[AuditEventGrantDelete] = true
+	m[AuditEventShareTokenCreate] = true
+	m[AuditEventShareTokenDelete] = true
+	m[AuditEventMaintenanceSchedule] = true
+	m[AuditEventMaintenanceCancel] = true
+	m[AuditEventPendingSchemaSubmit] = true
+	m[AuditEventPendingSchemaApprove] = true
+	m[AuditEventPendingSchemaReject] = true
 
 	// Encryption events
 	m[AuditEventKEKCreate] = true
@@ -461,9 +522,15 @@ func setDefaultEnabledEvents(m map[AuditEventType]bool) {
 	m[AuditEventExporterReset] = true
 	m[AuditEventExporterConfigUpdate] = true
 
+	// Context management events
+	m[AuditEventContextCreate] = true
+	m[AuditEventContextUpdate] = true
+	m[AuditEventContextDelete] = true
+
 	// Server lifecycle events
 	m[AuditEventServerStartup] = true
 	m[AuditEventServerShutdown] = true
+	m[AuditEventConfigReload] = true
 
 	// MCP events
 	m[AuditEventMCPToolCall] = true
@@ -515,6 +582,10 @@ func (al *AuditLogger) Log(event *AuditEvent) {
 // writeEvent serializes the event and fans out to all configured outputs.
 // It MUST be called from a single goroutine (the drain loop) or under al.mu.
 func (al *AuditLogger) writeEvent(event *AuditEvent) {
+	if al.history != nil {
+		al.history.Add(event)
+	}
+
 	// Lazy-serialize: only compute each format if at least one output needs it.
 	var jsonData, cefData []byte
 	var jsonErr error
@@ -603,9 +674,13 @@ type AuditHints struct {
 	// Target fields — populated by handlers when the URL path alone is not
 	// sufficient to determine the target (e.g., bulk import where subjects
 	// are in the request body, not the URL).
-	TargetType string // subject, schema, config, mode, kek, dek, exporter, user, apikey
+	TargetType string // subject, schema, config, mode, kek, dek, exporter, user, apikey, session
 	TargetID   string // subject name, KEK name, exporter name, etc.
 
+	// Metadata — event-specific extras copied to the event, such as the
+	// reviewers a pending schema was assigned to.
+	Metadata map[string]string
+
 	// Actor fields — populated by the auth middleware so the audit middleware
 	// can read them even though the auth middleware runs after the audit
 	// middleware in the chi middleware chain. Using a shared mutable pointer
@@ -749,6 +824,7 @@ func (al *AuditLogger) Middleware(next http.Handler) http.Handler {
 			Reason:            reason,
 			RequestBody:       requestBody,
 			RequestID:         middleware.GetReqID(r.Context()),
+			Metadata:          hints.Metadata,
 		}
 
 		// Skip middleware event if the handler emitted per-item events directly.
@@ -770,8 +846,16 @@ func (al *AuditLogger) determineEventType(r *http.Request, statusCode int) Audit
 		return AuditEventAuthForbidden
 	}
 
-	// Import operations
-	if contains(path, "/import/") && r.Method == "POST" {
+	// A permanent delete, or a cascading context delete, answered with a
+	// confirmation token has not deleted anything yet
+	if statusCode == http.StatusAccepted && r.Method == "DELETE" &&
+		(r.URL.Query().Get("permanent") == "true" || r.URL.Query().Get("cascade") == "true") {
+		return AuditEventDeleteConfirmationIssued
+	}
+
+	// Import operations, including committing and aborting import sessions
+	// and changing schema ID aliases
+	if contains(path, "/import/") && (r.Method == "POST" || r.Method == "PUT" || r.Method == "DELETE") {
 		return AuditEventSchemaImport
 	}
 
@@ -780,10 +864,44 @@ func (al *AuditLogger) determineEventType(r *http.Request, statusCode int) Audit
 		return AuditEventCompatibilityCheck
 	}
 
+	// Freezing and unfreezing a version
+	if contains(path, "/subjects/") && strings.HasSuffix(path, "/freeze") {
+		switch r.Method {
+		case "PUT":
+			return AuditEventVersionFreeze
+		case "DELETE":
+			return AuditEventVersionUnfreeze
+		}
+	}
+
+	// Setting and deleting a consumer's reader assertion
+	if contains(path, "/subjects/") && contains(path, "/readers/") {
+		switch r.Method {
+		case "PUT":
+			return AuditEventReaderSet
+		case "DELETE":
+			return AuditEventReaderDelete
+		}
+	}
+
+	// Setting and deleting a subject's owner, description, contact and links
+	if contains(path, "/subjects/") && strings.HasSuffix(path, "/meta") {
+		switch r.Method {
+		case "PUT":
+			return AuditEventSubjectMetaSet
+		case "DELETE":
+			return AuditEventSubjectMetaDelete
+		}
+	}
+
 	// Schema operations — registration, deletion, retrieval via versioned paths
 	if contains(path, "/subjects/") && contains(path, "/versions") {
 		switch r.Method {
-		case "POST":
+		case "POST", "PUT":
+			// A registration held for approval has not registered anything yet
+			if r.Method == "POST" && statusCode == http.StatusAccepted {
+				return AuditEventPendingSchemaSubmit
+			}
 			return AuditEventSchemaRegister
 		case "DELETE":
 			if r.URL.Query().Get("permanent") == "true" {
@@ -830,13 +948,25 @@ func (al *AuditLogger) determineEventType(r *http.Request, statusCode int) Audit
 		}
 	}
 
+	// Login sessions
+	if r.Method == "POST" {
+		switch path {
+		case "/auth/login":
+			return AuditEventSessionCreate
+		case "/auth/refresh":
+			return AuditEventSessionRefresh
+		case "/auth/revoke":
+			return AuditEventSessionRevoke
+		}
+	}
+
 	// Account self-service — password change
 	if contains(path, "/me/password") && r.Method == "POST" {
 		return AuditEventPasswordChange
 	}
 
-	// Admin operations — API key management
-	if contains(path, "/admin/apikeys") {
+	// API key management, by admins or for the caller's own keys
+	if contains(path, "/admin/apikeys") || contains(path, "/me/apikeys") {
 		if contains(path, "/revoke") && r.Method == "POST" {
 			return AuditEventAPIKeyRevoke
 		}
@@ -853,6 +983,51 @@ func (al *AuditLogger) determineEventType(r *http.Request, statusCode int) Audit
 		}
 	}
 
+	// Admin operations — role grants
+	if contains(path, "/admin/grants") {
+		switch r.Method {
+		case "POST":
+			return AuditEventGrantCreate
+		case "DELETE":
+			return AuditEventGrantDelete
+		}
+	}
+
+	// Admin operations — share tokens
+	if contains(path, "/admin/share-tokens") {
+		switch r.Method {
+		case "POST":
+			return AuditEventShareTokenCreate
+		case "DELETE":
+			return AuditEventShareTokenDelete
+		}
+	}
+
+	// Admin operations — maintenance windows
+	if contains(path, "/admin/maintenance") {
+		switch r.Method {
+		case "POST":
+			return AuditEventMaintenanceSchedule
+		case "DELETE":
+			return AuditEventMaintenanceCancel
+		}
+	}
+
+	// Admin operations — configuration reload
+	if path == "/admin/config/reload" && r.Method == "POST" {
+		return AuditEventConfigReload
+	}
+
+	// Admin operations — pending schema reviews
+	if contains(path, "/admin/pending-schemas/") && r.Method == "POST" {
+		switch {
+		case strings.HasSuffix(path, "/approve"):
+			return AuditEventPendingSchemaApprove
+		case strings.HasSuffix(path, "/reject"):
+			return AuditEventPendingSchemaReject
+		}
+	}
+
 	// KEK operations
 	if contains(path, "/dek-registry/v1/keks") {
 		// DEK operations (path includes /deks/)
@@ -920,6 +1095,20 @@ func (al *AuditLogger) determineEventType(r *http.Request, statusCode int) Audit
 		}
 	}
 
+	// Context management operations
+	if path == "/contexts" && r.Method == "POST" {
+		return AuditEventContextCreate
+	}
+	if strings.HasPrefix(path, "/contexts/") {
+		rest := strings.TrimSuffix(path[len("/contexts/"):], "/")
+		if !strings.Contains(rest, "/") && r.Method == "DELETE" {
+			return AuditEventContextDelete
+		}
+		if strings.HasSuffix(rest, "/settings") && strings.Count(rest, "/") == 1 && r.Method == "PUT" {
+			return AuditEventContextUpdate
+		}
+	}
+
 	// Config operations
 	if contains(path, "/config") {
 		switch r.Method {
@@ -1018,7 +1207,9 @@ func actorTypeFromAuthMethod(method string) string {
 	switch method {
 	case "api_key":
 		return "api_key"
-	case "basic", "jwt", "oidc", "ldap", "ldap_fallback":
+	case AuthMethodShareToken:
+		return "share_token"
+	case "basic", "jwt", "oidc", "ldap", "ldap_fallback", "mtls", AuthMethodSession:
 		return "user"
 	default:
 		return "anonymous"
@@ -1098,6 +1289,12 @@ func toLower(s string) string {
 // extractTarget derives the target_type and target_id from the URL path and event type.
 func extractTarget(path string, eventType AuditEventType) (targetType, targetID string) {
 	switch {
+	// Server configuration reload
+	case eventType == AuditEventConfigReload:
+		return "server", "config"
+	// Context management operations
+	case eventType == AuditEventContextCreate || eventType == AuditEventContextUpdate || eventType == AuditEventContextDelete:
+		return extractContextTarget(path)
 	// Subject/schema operations
 	case contains(path, "/subjects/"):
 		subject := extractSubject(path)
@@ -1140,6 +1337,18 @@ func extractTarget(path string, eventType AuditEventType) (targetType, targetID
 	// Admin API key operations
 	case contains(path, "/admin/apikeys"):
 		return extractAdminTarget(path, "/admin/apikeys/", "apikey")
+	// Admin role grant operations
+	case contains(path, "/admin/grants"):
+		return extractAdminTarget(path, "/admin/grants/", "grant")
+	// Admin share token operations
+	case contains(path, "/admin/share-tokens"):
+		return extractAdminTarget(path, "/admin/share-tokens/", "share_token")
+	// Admin maintenance window operations
+	case contains(path, "/admin/maintenance"):
+		return extractAdminTarget(path, "/admin/maintenance/", "maintenance")
+	// Admin pending schema reviews
+	case contains(path, "/admin/pending-schemas"):
+		return extractAdminTarget(path, "/admin/pending-schemas/", "pending_schema")
 	// Import
 	case contains(path, "/import/"):
 		return "schema", ""
@@ -1184,6 +1393,20 @@ func extractExporterTarget(path string) (string, string) {
 	return "exporter", rest[:end]
 }
 
+// extractContextTarget extracts the context name from /contexts/{context} paths.
+func extractContextTarget(path string) (string, string) {
+	prefix := "/contexts/"
+	if !contains(path, prefix) || len(path) <= len(prefix) {
+		return "context", ""
+	}
+	rest := path[len(prefix):]
+	end := 0
+	for end < len(rest) && rest[end] != '/' {
+		end++
+	}
+	return "context", rest[:end]
+}
+
 // extractAdminTarget extracts the target ID from admin paths.
 func extractAdminTarget(path, prefix, targetType string) (string, string) {
 	if !contains(path, prefix) || len(path) <= len(prefix) {
@@ -111,16 +111,26 @@ func cefSeverity(event *AuditEvent) int {
 	switch event.EventType {
 	case AuditEventAuthFailure, AuditEventAuthForbidden:
 		return 8
+	case AuditEventSessionCreate, AuditEventSessionRefresh, AuditEventSessionRevoke:
+		return 3
 	case AuditEventSchemaRegister,
 		AuditEventSchemaDeleteSoft, AuditEventSchemaDeletePermanent,
 		AuditEventSubjectDeleteSoft, AuditEventSubjectDeletePermanent,
 		AuditEventConfigUpdate, AuditEventConfigDelete,
 		AuditEventModeUpdate, AuditEventModeDelete,
 		AuditEventSchemaImport, AuditEventCompatibilityCheck,
+		AuditEventVersionFreeze, AuditEventVersionUnfreeze,
+		AuditEventReaderSet, AuditEventReaderDelete,
+		AuditEventSubjectMetaSet, AuditEventSubjectMetaDelete,
+		AuditEventDeleteConfirmationIssued,
 		AuditEventUserCreate, AuditEventUserUpdate, AuditEventUserDelete,
 		AuditEventPasswordChange,
 		AuditEventAPIKeyCreate, AuditEventAPIKeyUpdate, AuditEventAPIKeyDelete,
 		AuditEventAPIKeyRevoke, AuditEventAPIKeyRotate,
+		AuditEventGrantCreate, AuditEventGrantDelete,
+		AuditEventShareTokenCreate, AuditEventShareTokenDelete,
+		AuditEventMaintenanceSchedule, AuditEventMaintenanceCancel,
+		AuditEventPendingSchemaApprove, AuditEventPendingSchemaReject,
 		AuditEventKEKCreate, AuditEventKEKUpdate,
 		AuditEventKEKDeleteSoft, AuditEventKEKDeletePermanent,
 		AuditEventKEKUndelete, AuditEventKEKTest,
@@ -130,7 +140,8 @@ func cefSeverity(event *AuditEvent) int {
 		AuditEventExporterCreate, AuditEventExporterUpdate, AuditEventExporterDelete,
 		AuditEventExporterPause, AuditEventExporterResume, AuditEventExporterReset,
 		AuditEventExporterConfigUpdate,
-		AuditEventServerStartup, AuditEventServerShutdown:
+		AuditEventContextCreate, AuditEventContextUpdate, AuditEventContextDelete,
+		AuditEventServerStartup, AuditEventServerShutdown, AuditEventConfigReload:
 		return 5
 	case AuditEventMCPToolCall, AuditEventMCPToolError, AuditEventMCPAdminAction,
 		AuditEventMCPConfirmIssued, AuditEventMCPConfirmRejected, AuditEventMCPConfirmed:
@@ -176,12 +187,32 @@ func cefDescription(event *AuditEvent) string {
 		return "Authentication failed"
 	case AuditEventAuthForbidden:
 		return "Access forbidden"
+	case AuditEventSessionCreate:
+		return "Login session started"
+	case AuditEventSessionRefresh:
+		return "Login session refreshed"
+	case AuditEventSessionRevoke:
+		return "Login session revoked"
+	case AuditEventVersionFreeze:
+		return "Version frozen"
+	case AuditEventVersionUnfreeze:
+		return "Version unfrozen"
+	case AuditEventReaderSet:
+		return "Reader assertion set"
+	case AuditEventReaderDelete:
+		return "Reader assertion deleted"
 	case AuditEventSubjectDeleteSoft:
 		return "Subject soft-deleted"
 	case AuditEventSubjectDeletePermanent:
 		return "Subject permanently deleted"
 	case AuditEventSubjectList:
 		return "Subjects listed"
+	case AuditEventSubjectMetaSet:
+		return "Subject meta set"
+	case AuditEventSubjectMetaDelete:
+		return "Subject meta deleted"
+	case AuditEventDeleteConfirmationIssued:
+		return "Permanent delete confirmation issued"
 	case AuditEventUserCreate:
 		return "User created"
 	case AuditEventUserUpdate:
@@ -200,6 +231,22 @@ func cefDescription(event *AuditEvent) string {
 		return "API key revoked"
 	case AuditEventAPIKeyRotate:
 		return "API key rotated"
+	case AuditEventGrantCreate:
+		return "Role grant created"
+	case AuditEventGrantDelete:
+		return "Role grant deleted"
+	case AuditEventShareTokenCreate:
+		return "Share token created"
+	case AuditEventShareTokenDelete:
+		return "Share token deleted"
+	case AuditEventMaintenanceSchedule:
+		return "Maintenance window scheduled"
+	case AuditEventMaintenanceCancel:
+		return "Maintenance window cancelled"
+	case AuditEventPendingSchemaApprove:
+		return "Pending schema approved"
+	case AuditEventPendingSchemaReject:
+		return "Pending schema rejected"
 	case AuditEventKEKCreate:
 		return "KEK created"
 	case AuditEventKEKUpdate:
@@ -234,12 +281,20 @@ func cefDescription(event *AuditEvent) string {
 		return "Exporter reset"
 	case AuditEventExporterConfigUpdate:
 		return "Exporter config updated"
+	case AuditEventContextCreate:
+		return "Context created"
+	case AuditEventContextUpdate:
+		return "Context settings updated"
+	case AuditEventContextDelete:
+		return "Context deleted"
 	case AuditEventCompatibilityCheck:
 		return "Compatibility check"
 	case AuditEventServerStartup:
 		return "Server started"
 	case AuditEventServerShutdown:
 		return "Server stopped"
+	case AuditEventConfigReload:
+		return "Server configuration reloaded"
 	case AuditEventMCPToolCall:
 		return "MCP tool call"
 	case AuditEventMCPToolError:
@@ -205,15 +205,24 @@ func TestCEFDescription_AllEventTypes(t *testing.T) {
 		AuditEventSchemaDeleteSoft, AuditEventSchemaDeletePermanent,
 		AuditEventSchemaGet, AuditEventSchemaLookup, AuditEventSchemaImport,
 		AuditEventCompatibilityCheck,
+		AuditEventVersionFreeze, AuditEventVersionUnfreeze,
+		AuditEventReaderSet, AuditEventReaderDelete,
 		AuditEventConfigGet, AuditEventConfigUpdate, AuditEventConfigDelete,
 		AuditEventModeGet, AuditEventModeUpdate, AuditEventModeDelete,
 		AuditEventAuthSuccess, AuditEventAuthFailure, AuditEventAuthForbidden,
+		AuditEventSessionCreate, AuditEventSessionRefresh, AuditEventSessionRevoke,
 		AuditEventSubjectDeleteSoft, AuditEventSubjectDeletePermanent,
-		AuditEventSubjectList,
+		AuditEventSubjectList, AuditEventSubjectMetaSet, AuditEventSubjectMetaDelete,
+		AuditEventDeleteConfirmationIssued,
 		AuditEventUserCreate, AuditEventUserUpdate, AuditEventUserDelete,
 		AuditEventPasswordChange,
 		AuditEventAPIKeyCreate, AuditEventAPIKeyUpdate, AuditEventAPIKeyDelete,
 		AuditEventAPIKeyRevoke, AuditEventAPIKeyRotate,
+		AuditEventGrantCreate, AuditEventGrantDelete,
+		AuditEventShareTokenCreate, AuditEventShareTokenDelete,
+		AuditEventMaintenanceSchedule, AuditEventMaintenanceCancel,
+		AuditEventPendingSchemaSubmit,
+		AuditEventPendingSchemaApprove, AuditEventPendingSchemaReject,
 		AuditEventKEKCreate, AuditEventKEKUpdate,
 		AuditEventKEKDeleteSoft, AuditEventKEKDeletePermanent,
 		AuditEventKEKUndelete, AuditEventKEKTest,
@@ -223,9 +232,10 @@ func TestCEFDescription_AllEventTypes(t *testing.T) {
 		AuditEventExporterCreate, AuditEventExporterUpdate, AuditEventExporterDelete,
 		AuditEventExporterPause, AuditEventExporterResume, AuditEventExporterReset,
 		AuditEventExporterConfigUpdate,
+		AuditEventContextCreate, AuditEventContextUpdate, AuditEventContextDelete,
 		AuditEventMCPToolCall, AuditEventMCPToolError, AuditEventMCPAdminAction,
 		AuditEventMCPConfirmIssued, AuditEventMCPConfirmRejected, AuditEventMCPConfirmed,
-		AuditEventServerStartup, AuditEventServerShutdown,
+		AuditEventServerStartup, AuditEventServerShutdown, AuditEventConfigReload,
 	}
 
 	for _, et := range eventTypes {
@@ -0,0 +1,111 @@
+// Package auth provides authentication and authorization for the schema registry.
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultAuditHistorySize is the number of events retained when
+// AuditHistoryConfig.MaxEvents is not set.
+const defaultAuditHistorySize = 10000
+
+// AuditHistory retains the most recent audit events in memory so they can be
+// queried through the admin API. It is a fixed-size ring buffer: once full,
+// each new event overwrites the oldest one. History is per instance and is
+// lost on restart; durable retention belongs to the file, syslog or webhook
+// outputs.
+type AuditHistory struct {
+	mu     sync.RWMutex
+	events []*AuditEvent
+	next   int  // index of the slot the next event is written to
+	full   bool // whether the buffer has wrapped at least once
+}
+
+// NewAuditHistory creates an AuditHistory that retains up to maxEvents events.
+// A non-positive maxEvents uses the default of 10,000.
+func NewAuditHistory(maxEvents int) *AuditHistory {
+	if maxEvents <= 0 {
+		maxEvents = defaultAuditHistorySize
+	}
+	return &AuditHistory{events: make([]*AuditEvent, maxEvents)}
+}
+
+// Add records an event, evicting the oldest event if the history is full.
+func (h *AuditHistory) Add(event *AuditEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events[h.next] = event
+	h.next++
+	if h.next == len(h.events) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// AuditQuery filters audit history. Zero-valued fields match everything.
+type AuditQuery struct {
+	Principal string         // Matches ActorID exactly
+	Subject   string         // Matches subject, config and mode targets with this ID
+	EventType AuditEventType // Matches EventType exactly
+	Outcome   string         // "success" or "failure"
+	Since     time.Time      // Inclusive lower bound on Timestamp
+	Until     time.Time      // Exclusive upper bound on Timestamp
+	Limit     int            // Maximum number of events returned; 0 means no limit
+}
+
+// Matches reports whether the event satisfies every filter set on q.
+func (q *AuditQuery) Matches(event *AuditEvent) bool {
+	if q.Principal != "" && event.ActorID != q.Principal {
+		return false
+	}
+	if q.Subject != "" {
+		switch event.TargetType {
+		case "subject", "config", "mode":
+		default:
+			return false
+		}
+		if event.TargetID != q.Subject {
+			return false
+		}
+	}
+	if q.EventType != "" && event.EventType != q.EventType {
+		return false
+	}
+	if q.Outcome != "" && event.Outcome != q.Outcome {
+		return false
+	}
+	if !q.Since.IsZero() && event.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !event.Timestamp.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Query returns the events matching q, newest first.
+func (h *AuditHistory) Query(q AuditQuery) []*AuditEvent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := h.next
+	if h.full {
+		count = len(h.events)
+	}
+
+	result := []*AuditEvent{}
+	for i := 1; i <= count; i++ {
+		idx := (h.next - i + len(h.events)) % len(h.events)
+		event := h.events[idx]
+		if !q.Matches(event) {
+			continue
+		}
+		result = append(result, event)
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+)
+
+func TestAuditHistory_EvictsOldestAndReturnsNewestFirst(t *testing.T) {
+	h := NewAuditHistory(3)
+	base := time.Date(2026, 3, 12, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		h.Add(&AuditEvent{Timestamp: base.Add(time.Duration(i) * time.Minute), TargetID: fmt.Sprintf("s%d", i)})
+	}
+
+	events := h.Query(AuditQuery{})
+	if len(events) != 3 {
+		t.Fatalf("expected 3 retained events, got %d", len(events))
+	}
+	for i, want := range []string{"s4", "s3", "s2"} {
+		if events[i].TargetID != want {
+			t.Errorf("event %d: expected %s, got %s", i, want, events[i].TargetID)
+		}
+	}
+
+	if got := h.Query(AuditQuery{Limit: 1}); len(got) != 1 || got[0].TargetID != "s4" {
+		t.Errorf("expected only the newest event with limit 1, got %+v", got)
+	}
+}
+
+func TestAuditQuery_Matches(t *testing.T) {
+	now := time.Date(2026, 3, 12, 10, 0, 0, 0, time.UTC)
+	event := &AuditEvent{
+		Timestamp:  now,
+		EventType:  AuditEventSubjectDeletePermanent,
+		Outcome:    "success",
+		ActorID:    "alice",
+		TargetType: "subject",
+		TargetID:   "orders-value",
+	}
+
+	tests := []struct {
+		name  string
+		query AuditQuery
+		want  bool
+	}{
+		{"empty query", AuditQuery{}, true},
+		{"principal", AuditQuery{Principal: "alice"}, true},
+		{"other principal", AuditQuery{Principal: "bob"}, false},
+		{"subject", AuditQuery{Subject: "orders-value"}, true},
+		{"other subject", AuditQuery{Subject: "users-value"}, false},
+		{"event type", AuditQuery{EventType: AuditEventSubjectDeletePermanent}, true},
+		{"other event type", AuditQuery{EventType: AuditEventSchemaRegister}, false},
+		{"outcome", AuditQuery{Outcome: "failure"}, false},
+		{"since inclusive", AuditQuery{Since: now}, true},
+		{"since after", AuditQuery{Since: now.Add(time.Second)}, false},
+		{"until exclusive", AuditQuery{Until: now}, false},
+		{"until after", AuditQuery{Until: now.Add(time.Second)}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.query.Matches(event); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Subject filters only apply to subject-scoped targets.
+	kek := &AuditEvent{TargetType: "kek", TargetID: "orders-value"}
+	if (&AuditQuery{Subject: "orders-value"}).Matches(kek) {
+		t.Error("expected subject filter not to match a KEK target with the same name")
+	}
+}
+
+func TestAuditLogger_RecordsHistory(t *testing.T) {
+	var buf bytes.Buffer
+	al := NewAuditLoggerWithWriter(config.AuditConfig{
+		Enabled: true,
+		History: config.AuditHistoryConfig{Enabled: true, MaxEvents: 10},
+	}, &buf)
+
+	al.Log(&AuditEvent{EventType: AuditEventSchemaRegister, ActorID: "alice", TargetType: "subject", TargetID: "orders-value"})
+	al.Log(&AuditEvent{EventType: AuditEventSchemaGet, ActorID: "alice"}) // not enabled by default
+
+	if al.History() == nil {
+		t.Fatal("expected history to be enabled")
+	}
+	events := al.History().Query(AuditQuery{Principal: "alice"})
+	if len(events) != 1 || events[0].EventType != AuditEventSchemaRegister {
+		t.Errorf("expected only the enabled event in history, got %+v", events)
+	}
+}
+
+func TestAuditLogger_HistoryDisabledByDefault(t *testing.T) {
+	al := NewAuditLoggerWithWriter(config.AuditConfig{Enabled: true}, &bytes.Buffer{})
+	if al.History() != nil {
+		t.Error("expected no history when audit.history.enabled is false")
+	}
+}
@@ -203,6 +203,23 @@ func TestDetermineEventType_AuthForbidden(t *testing.T) {
 	}
 }
 
+func TestDetermineEventType_DeleteConfirmationIssued(t *testing.T) {
+	al, _ := NewAuditLogger(config.AuditConfig{Enabled: true})
+	defer al.Close()
+
+	for _, path := range []string{"/subjects/test?permanent=true", "/subjects/test/versions/1?permanent=true", "/contexts/.team?cascade=true"} {
+		r := httptest.NewRequest("DELETE", path, nil)
+		if got := al.determineEventType(r, http.StatusAccepted); got != AuditEventDeleteConfirmationIssued {
+			t.Errorf("DELETE %s with 202: expected delete_confirmation_issued, got %s", path, got)
+		}
+	}
+
+	r := httptest.NewRequest("DELETE", "/subjects/test?permanent=true&confirmation_token=abc", nil)
+	if got := al.determineEventType(r, http.StatusOK); got != AuditEventSubjectDeletePermanent {
+		t.Errorf("expected subject_delete_permanent for a confirmed delete, got %s", got)
+	}
+}
+
 func TestDetermineEventType_SchemaOps(t *testing.T) {
 	al, _ := NewAuditLogger(config.AuditConfig{Enabled: true})
 	defer al.Close()
@@ -222,8 +239,17 @@ func TestDetermineEventType_SchemaOps(t *testing.T) {
 		{"DELETE", "/subjects/test", AuditEventSubjectDeleteSoft},
 		{"DELETE", "/subjects/test?permanent=true", AuditEventSubjectDeletePermanent},
 		{"GET", "/subjects", AuditEventSubjectList},
+		{"PUT", "/subjects/test/versions/1/freeze", AuditEventVersionFreeze},
+		{"DELETE", "/subjects/test/versions/latest/freeze", AuditEventVersionUnfreeze},
+		{"GET", "/subjects/test/versions/1/freeze", AuditEventSchemaGet},
+		{"PUT", "/subjects/test/readers/billing", AuditEventReaderSet},
+		{"DELETE", "/subjects/test/readers/billing", AuditEventReaderDelete},
+		{"PUT", "/subjects/test/meta", AuditEventSubjectMetaSet},
+		{"DELETE", "/subjects/test/meta", AuditEventSubjectMetaDelete},
 		// Import
 		{"POST", "/import/schemas", AuditEventSchemaImport},
+		{"PUT", "/import/sessions/abc/commit", AuditEventSchemaImport},
+		{"DELETE", "/import/id-aliases/us-east/7", AuditEventSchemaImport},
 		// Compatibility check
 		{"POST", "/compatibility/subjects/test/versions/1", AuditEventCompatibilityCheck},
 		{"POST", "/compatibility/subjects/test/versions", AuditEventCompatibilityCheck},
@@ -246,8 +272,23 @@ func TestDetermineEventType_SchemaOps(t *testing.T) {
 		{"DELETE", "/admin/apikeys/1", AuditEventAPIKeyDelete},
 		{"POST", "/admin/apikeys/1/revoke", AuditEventAPIKeyRevoke},
 		{"POST", "/admin/apikeys/1/rotate", AuditEventAPIKeyRotate},
+		{"POST", "/admin/grants", AuditEventGrantCreate},
+		{"DELETE", "/admin/grants/3", AuditEventGrantDelete},
+		{"POST", "/admin/share-tokens", AuditEventShareTokenCreate},
+		{"DELETE", "/admin/share-tokens/5", AuditEventShareTokenDelete},
+		{"POST", "/admin/maintenance", AuditEventMaintenanceSchedule},
+		{"DELETE", "/admin/maintenance/ab12", AuditEventMaintenanceCancel},
+		{"POST", "/admin/pending-schemas/ab12/approve", AuditEventPendingSchemaApprove},
+		{"POST", "/admin/pending-schemas/ab12/reject", AuditEventPendingSchemaReject},
+		{"POST", "/admin/config/reload", AuditEventConfigReload},
 		// Account self-service
 		{"POST", "/me/password", AuditEventPasswordChange},
+		{"POST", "/me/apikeys", AuditEventAPIKeyCreate},
+		{"POST", "/me/apikeys/4/revoke", AuditEventAPIKeyRevoke},
+		// Login sessions
+		{"POST", "/auth/login", AuditEventSessionCreate},
+		{"POST", "/auth/refresh", AuditEventSessionRefresh},
+		{"POST", "/auth/revoke", AuditEventSessionRevoke},
 		// KEK operations
 		{"POST", "/dek-registry/v1/keks", AuditEventKEKCreate},
 		{"PUT", "/dek-registry/v1/keks/my-kek", AuditEventKEKUpdate},
@@ -273,6 +314,12 @@ func TestDetermineEventType_SchemaOps(t *testing.T) {
 		{"PUT", "/exporters/my-export/resume", AuditEventExporterResume},
 		{"PUT", "/exporters/my-export/reset", AuditEventExporterReset},
 		{"PUT", "/exporters/my-export/config", AuditEventExporterConfigUpdate},
+		// Context management operations
+		{"POST", "/contexts", AuditEventContextCreate},
+		{"PUT", "/contexts/.team-a/settings", AuditEventContextUpdate},
+		{"DELETE", "/contexts/.team-a", AuditEventContextDelete},
+		{"DELETE", "/contexts/.team-a?cascade=true", AuditEventContextDelete},
+		{"PUT", "/contexts/.team-a/config", AuditEventConfigUpdate},
 	}
 
 	for _, tt := range tests {
@@ -1074,6 +1121,10 @@ func TestExtractTarget(t *testing.T) {
 		{"/exporters", AuditEventExporterCreate, "exporter", ""},
 		{"/exporters/my-export", AuditEventExporterUpdate, "exporter", "my-export"},
 		{"/exporters/my-export/pause", AuditEventExporterPause, "exporter", "my-export"},
+		// Context operations
+		{"/contexts", AuditEventContextCreate, "context", ""},
+		{"/contexts/.team-a/settings", AuditEventContextUpdate, "context", ".team-a"},
+		{"/contexts/.team-a", AuditEventContextDelete, "context", ".team-a"},
 		// Admin users
 		{"/admin/users", AuditEventUserCreate, "user", ""},
 		{"/admin/users/42", AuditEventUserUpdate, "user", "42"},
@@ -1081,6 +1132,13 @@ func TestExtractTarget(t *testing.T) {
 		{"/admin/apikeys", AuditEventAPIKeyCreate, "apikey", ""},
 		{"/admin/apikeys/99", AuditEventAPIKeyDelete, "apikey", "99"},
 		{"/admin/apikeys/1/revoke", AuditEventAPIKeyRevoke, "apikey", "1"},
+		// Admin role grants
+		{"/admin/grants/3", AuditEventGrantDelete, "grant", "3"},
+		// Admin share tokens
+		{"/admin/share-tokens/5", AuditEventShareTokenDelete, "share_token", "5"},
+		{"/admin/maintenance/ab12", AuditEventMaintenanceCancel, "maintenance", "ab12"},
+		{"/admin/pending-schemas/ab12/approve", AuditEventPendingSchemaApprove, "pending_schema", "ab12"},
+		{"/admin/config/reload", AuditEventConfigReload, "server", "config"},
 		// Import
 		{"/import/schemas", AuditEventSchemaImport, "schema", ""},
 		// Unknown
@@ -1558,6 +1616,32 @@ func TestAuditLogger_Middleware_PropagatesHints(t *testing.T) {
 	}
 }
 
+func TestAuditLogger_Middleware_PropagatesMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	al := NewAuditLoggerWithWriter(config.AuditConfig{Enabled: true}, &buf)
+	defer al.Close()
+
+	handler := al.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Handler records the reviewers of a registration held for approval.
+		if hints := GetAuditHints(r.Context()); hints != nil {
+			hints.Metadata = map[string]string{"pending_schema_id": "ab12", "reviewers": "alice,role:admin"}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	r := httptest.NewRequest("POST", "/subjects/test/versions", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	content := buf.String()
+	if !strings.Contains(content, `"event_type":"pending_schema_submit"`) {
+		t.Errorf("expected pending_schema_submit in audit output, got: %s", content)
+	}
+	if !strings.Contains(content, `"reviewers":"alice,role:admin"`) {
+		t.Errorf("expected reviewers metadata in audit output, got: %s", content)
+	}
+}
+
 func TestAuditLogger_Middleware_OutcomeOverride(t *testing.T) {
 	var buf bytes.Buffer
 	al := NewAuditLoggerWithWriter(config.AuditConfig{Enabled: true}, &buf)
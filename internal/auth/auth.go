@@ -9,12 +9,14 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/axonops/axonops-schema-registry/internal/config"
 	"github.com/axonops/axonops-schema-registry/internal/metrics"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
 // ContextKey is used for storing auth info in context.
@@ -31,19 +33,43 @@ const (
 
 // User represents an authenticated user.
 type User struct {
-	ID       int64
+	// UserID is the database ID of the user the principal is, or acts for
+	// when it authenticated with one of their API keys. 0 for principals
+	// without a database user.
+	UserID int64
+	// APIKeyID is the database ID of the API key the principal
+	// authenticated with, directly or by starting a session with it. 0 for
+	// every other principal.
+	APIKeyID int64
 	Username string
 	Role     string
-	Method   string // basic, api_key, jwt, oidc
+	Method   string      // basic, api_key, jwt, oidc, share_token, session
+	Share    *ShareScope // Set for share tokens, which can only read within the scope
+	Tenant   string      // Tenant of a database user or from a token's tenant claim; empty for instance-wide users
+
+	// Scopes restricts a database API key to the listed resources, on top
+	// of what its role and grants allow. Empty for unrestricted principals.
+	Scopes []storage.APIKeyScope
+
+	// PasswordChangeRequired is set for a database user who logged in with
+	// a password that must be changed, because it has expired or they were
+	// told to change it. Until then they may only use their account
+	// endpoints.
+	PasswordChangeRequired bool
 }
 
 // Authenticator handles authentication.
 type Authenticator struct {
-	config        config.AuthConfig
-	service       *Service           // Database-backed auth service
+	config  config.AuthConfig
+	service *Service // Database-backed auth service
+
+	// mu guards the providers that a configuration reload replaces: LDAP,
+	// OIDC, JWT, config-defined API keys and the htpasswd file.
+	mu            sync.RWMutex
 	ldapProvider  *LDAPProvider      // LDAP authentication provider
 	oidcProvider  *OIDCProvider      // OIDC authentication provider
 	jwtProvider   *JWTProvider       // JWT authentication provider
+	mtlsProvider  *MTLSProvider      // Client certificate identity mapping
 	apiKeys       map[string]*APIKey // key -> APIKey (for legacy/config-based auth)
 	memoryAPIKeys *MemoryAPIKeyStore // config-defined API keys (memory storage_type)
 	htpasswdStore *HTPasswdStore     // htpasswd file entries (optional)
@@ -75,19 +101,30 @@ func (a *Authenticator) SetService(svc *Service) {
 
 // SetLDAPProvider sets the LDAP authentication provider.
 func (a *Authenticator) SetLDAPProvider(p *LDAPProvider) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.ldapProvider = p
 }
 
 // SetOIDCProvider sets the OIDC authentication provider.
 func (a *Authenticator) SetOIDCProvider(p *OIDCProvider) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.oidcProvider = p
 }
 
 // SetJWTProvider sets the JWT authentication provider.
 func (a *Authenticator) SetJWTProvider(p *JWTProvider) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.jwtProvider = p
 }
 
+// SetMTLSProvider sets the client certificate identity provider.
+func (a *Authenticator) SetMTLSProvider(p *MTLSProvider) {
+	a.mtlsProvider = p
+}
+
 // SetMetrics sets the Prometheus metrics instance for recording auth metrics.
 func (a *Authenticator) SetMetrics(m *metrics.Metrics) {
 	a.metrics = m
@@ -95,14 +132,42 @@ func (a *Authenticator) SetMetrics(m *metrics.Metrics) {
 
 // SetHTPasswdStore sets the htpasswd file store for Basic authentication.
 func (a *Authenticator) SetHTPasswdStore(store *HTPasswdStore) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.htpasswdStore = store
 }
 
 // SetMemoryAPIKeyStore sets the config-defined API key store.
 func (a *Authenticator) SetMemoryAPIKeyStore(store *MemoryAPIKeyStore) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.memoryAPIKeys = store
 }
 
+// PingLDAP checks that the current LDAP provider can bind to its server.
+// It succeeds when no LDAP provider is set.
+func (a *Authenticator) PingLDAP(ctx context.Context) error {
+	a.mu.RLock()
+	p := a.ldapProvider
+	a.mu.RUnlock()
+	if p == nil {
+		return nil
+	}
+	return p.Ping(ctx)
+}
+
+// PingOIDC checks that the current OIDC provider's issuer is reachable. It
+// succeeds when no OIDC provider is set.
+func (a *Authenticator) PingOIDC(ctx context.Context) error {
+	a.mu.RLock()
+	p := a.oidcProvider
+	a.mu.RUnlock()
+	if p == nil {
+		return nil
+	}
+	return p.Ping(ctx)
+}
+
 // SetAuditLogger sets the audit logger for recording auth fallback events.
 func (a *Authenticator) SetAuditLogger(al *AuditLogger) {
 	a.auditLogger = al
@@ -122,54 +187,111 @@ func (a *Authenticator) Middleware(next http.Handler) http.Handler {
 		}
 
 		start := time.Now()
+		user, reason := a.Authenticate(r)
+		if user == nil {
+			if a.metrics != nil {
+				a.metrics.RecordAuthAttempt(reasonMethod(reason), false, reason, time.Since(start))
+			}
+			a.unauthorized(w, r)
+			return
+		}
+		a.serveAuthenticated(w, r, next, user, start)
+	})
+}
 
-		// Try each enabled authentication method
-		for _, method := range a.config.Methods {
-			user, ok := a.authenticate(r, method)
-			if ok {
-				if a.metrics != nil {
-					a.metrics.RecordAuthAttempt(user.Method, true, "", time.Since(start))
-				}
-				// Store user in context
-				ctx := context.WithValue(r.Context(), UserContextKey, user)
-				ctx = context.WithValue(ctx, RoleContextKey, user.Role)
-				if user.ID > 0 {
-					ctx = context.WithValue(ctx, UserIDContextKey, user.ID)
-				}
+// Authenticate identifies the user of a request with the configured methods,
+// as Middleware does, without writing a response. It is used by the API
+// servers that do not go through the HTTP middleware chain. When no user is
+// found, the returned reason is "invalid_share_token",
+// "invalid_session_token" or "no_valid_credentials".
+func (a *Authenticator) Authenticate(r *http.Request) (*User, string) {
+	// Share tokens and session access tokens are accepted whatever methods
+	// are configured. A presented share token, or a bearer token signed for
+	// a session, is never passed on to the other methods.
+	if user, handled := a.authenticateShareToken(r); handled {
+		if user != nil {
+			return user, ""
+		}
+		return nil, "invalid_share_token"
+	}
+	if user, handled := a.authenticateSession(r); handled {
+		if user != nil {
+			return user, ""
+		}
+		return nil, "invalid_session_token"
+	}
 
-				// Propagate actor info to the audit middleware via the shared
-				// AuditHints pointer (audit middleware runs before auth in the
-				// chi middleware chain, so context-based communication is
-				// one-directional — audit injects the pointer, auth fills it).
-				if hints := GetAuditHints(r.Context()); hints != nil {
-					hints.ActorID = user.Username
-					hints.Role = user.Role
-					hints.AuthMethod = user.Method
-					hints.ActorType = actorTypeFromAuthMethod(user.Method)
-				}
+	// Try each enabled authentication method
+	for _, method := range a.config.Methods {
+		if user, ok := a.authenticate(r, method); ok {
+			return user, ""
+		}
+	}
+	return nil, "no_valid_credentials"
+}
 
-				// Record per-principal HTTP request metrics.
-				if a.metrics != nil {
-					rw := &principalResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
-					wrappedNext := http.HandlerFunc(func(w2 http.ResponseWriter, r2 *http.Request) {
-						next.ServeHTTP(w2, r2)
-					})
-					wrappedNext.ServeHTTP(rw, r.WithContext(ctx))
-					a.metrics.RecordPrincipalRequest(user.Username, r.Method, normalizePrincipalPath(r.URL.Path), http.StatusText(rw.statusCode))
-					return
-				}
+// reasonMethod returns the method label recorded for a failed attempt.
+func reasonMethod(reason string) string {
+	switch reason {
+	case "invalid_share_token":
+		return AuthMethodShareToken
+	case "invalid_session_token":
+		return AuthMethodSession
+	}
+	return "unknown"
+}
 
-				next.ServeHTTP(w, r.WithContext(ctx))
-				return
-			}
-		}
+// WithUser returns a copy of ctx carrying the authenticated user, as
+// Middleware stores it.
+func WithUser(ctx context.Context, user *User) context.Context {
+	ctx = context.WithValue(ctx, UserContextKey, user)
+	ctx = context.WithValue(ctx, RoleContextKey, user.Role)
+	if user.UserID > 0 {
+		ctx = context.WithValue(ctx, UserIDContextKey, user.UserID)
+	}
+	return ctx
+}
 
-		// No authentication succeeded
-		if a.metrics != nil {
-			a.metrics.RecordAuthAttempt("unknown", false, "no_valid_credentials", time.Since(start))
-		}
-		a.unauthorized(w, r)
-	})
+// ActingForTenant returns a copy of user that acts for tenant, as the tenant
+// override header of principal tenancy requests. Only super admins that
+// belong to no tenant may act for one; for anyone else it returns false.
+func ActingForTenant(user *User, tenant string) (*User, bool) {
+	if user == nil || user.Share != nil || user.Tenant != "" || user.Role != string(RoleSuperAdmin) {
+		return nil, false
+	}
+	acting := *user
+	acting.Tenant = tenant
+	return &acting, true
+}
+
+// serveAuthenticated passes an authenticated request on to next with the
+// user stored in its context.
+func (a *Authenticator) serveAuthenticated(w http.ResponseWriter, r *http.Request, next http.Handler, user *User, start time.Time) {
+	if a.metrics != nil {
+		a.metrics.RecordAuthAttempt(user.Method, true, "", time.Since(start))
+	}
+	ctx := WithUser(r.Context(), user)
+
+	// Propagate actor info to the audit middleware via the shared
+	// AuditHints pointer (audit middleware runs before auth in the
+	// chi middleware chain, so context-based communication is
+	// one-directional — audit injects the pointer, auth fills it).
+	if hints := GetAuditHints(r.Context()); hints != nil {
+		hints.ActorID = user.Username
+		hints.Role = user.Role
+		hints.AuthMethod = user.Method
+		hints.ActorType = actorTypeFromAuthMethod(user.Method)
+	}
+
+	// Record per-principal HTTP request metrics.
+	if a.metrics != nil {
+		rw := &principalResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+		a.metrics.RecordPrincipalRequest(user.Username, r.Method, normalizePrincipalPath(r.URL.Path), http.StatusText(rw.statusCode))
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(ctx))
 }
 
 // authenticate attempts authentication with a specific method.
@@ -183,6 +305,8 @@ func (a *Authenticator) authenticate(r *http.Request, method string) (*User, boo
 		return a.authenticateJWT(r)
 	case "oidc":
 		return a.authenticateOIDC(r)
+	case "mtls":
+		return a.authenticateMTLS(r)
 	default:
 		return nil, false
 	}
@@ -226,9 +350,13 @@ func (a *Authenticator) authenticateBasic(r *http.Request) (*User, bool) {
 	}
 
 	// Try LDAP authentication first if enabled
+	a.mu.RLock()
+	ldap, htpasswd := a.ldapProvider, a.htpasswdStore
+	a.mu.RUnlock()
+
 	ldapFallback := false
-	if a.ldapProvider != nil {
-		user, err := a.ldapProvider.Authenticate(r.Context(), username, password)
+	if ldap != nil {
+		user, err := ldap.Authenticate(r.Context(), username, password)
 		if err == nil && user != nil {
 			return user, true
 		}
@@ -241,7 +369,7 @@ func (a *Authenticator) authenticateBasic(r *http.Request) (*User, bool) {
 		// password policies (complexity, expiry, lockout, MFA).
 		//
 		// If allow_fallback is explicitly false, never fall back regardless of error.
-		if a.config.LDAP.AllowFallback != nil && !*a.config.LDAP.AllowFallback {
+		if ldap.config.AllowFallback != nil && !*ldap.config.AllowFallback {
 			return nil, false
 		}
 
@@ -295,10 +423,13 @@ func (a *Authenticator) authenticateBasic(r *http.Request) (*User, bool) {
 		user, err := a.service.ValidateCredentials(r.Context(), username, password)
 		if err == nil && user != nil {
 			return &User{
-				ID:       user.ID,
+				UserID:   user.ID,
 				Username: user.Username,
 				Role:     user.Role,
 				Method:   authMethod,
+				Tenant:   user.Tenant,
+
+				PasswordChangeRequired: a.service.PasswordChangeRequired(user),
 			}, true
 		}
 	}
@@ -315,7 +446,7 @@ func (a *Authenticator) authenticateBasic(r *http.Request) (*User, bool) {
 	}
 
 	// Fallback to htpasswd file if configured
-	if a.htpasswdStore != nil && a.htpasswdStore.Verify(username, password) {
+	if htpasswd != nil && htpasswd.Verify(username, password) {
 		return &User{
 			Username: username,
 			Role:     a.config.RBAC.DefaultRole,
@@ -354,26 +485,37 @@ func (a *Authenticator) authenticateAPIKeyValue(r *http.Request, key string) (*U
 	if a.service != nil {
 		apiKey, err := a.service.ValidateAPIKey(r.Context(), key)
 		if err == nil && apiKey != nil {
-			// If API key is associated with a user, get the username
+			// If API key is associated with a user, get the username and
+			// tenant. A key whose owner cannot be loaded is rejected rather
+			// than escaping the owner's tenant.
 			username := apiKey.Name
+			tenant := ""
 			if apiKey.UserID > 0 {
 				user, err := a.service.GetUserByID(r.Context(), apiKey.UserID)
-				if err == nil && user != nil {
-					username = user.Username
+				if err != nil || user == nil {
+					return nil, false
 				}
+				username = user.Username
+				tenant = user.Tenant
 			}
 			return &User{
-				ID:       apiKey.ID,
+				UserID:   apiKey.UserID,
+				APIKeyID: apiKey.ID,
 				Username: username,
 				Role:     apiKey.Role,
 				Method:   "api_key",
+				Tenant:   tenant,
+				Scopes:   apiKey.Scopes,
 			}, true
 		}
 	}
 
 	// Try config-defined API keys (memory storage_type)
-	if a.memoryAPIKeys != nil {
-		if name, role, ok := a.memoryAPIKeys.Validate(key); ok {
+	a.mu.RLock()
+	memoryAPIKeys := a.memoryAPIKeys
+	a.mu.RUnlock()
+	if memoryAPIKeys != nil {
+		if name, role, ok := memoryAPIKeys.Validate(key); ok {
 			return &User{
 				Username: name,
 				Role:     role,
@@ -396,7 +538,10 @@ func (a *Authenticator) authenticateAPIKeyValue(r *http.Request, key string) (*U
 
 // authenticateJWT handles JWT authentication.
 func (a *Authenticator) authenticateJWT(r *http.Request) (*User, bool) {
-	if a.jwtProvider == nil {
+	a.mu.RLock()
+	jwt := a.jwtProvider
+	a.mu.RUnlock()
+	if jwt == nil {
 		return nil, false
 	}
 
@@ -410,7 +555,7 @@ func (a *Authenticator) authenticateJWT(r *http.Request) (*User, bool) {
 		return nil, false
 	}
 
-	return a.jwtProvider.VerifyToken(r.Context(), token)
+	return jwt.VerifyToken(r.Context(), token)
 }
 
 // authenticateOIDC handles OpenID Connect authentication.
@@ -425,11 +570,51 @@ func (a *Authenticator) authenticateOIDC(r *http.Request) (*User, bool) {
 		return nil, false
 	}
 
-	if a.oidcProvider == nil {
+	a.mu.RLock()
+	oidc := a.oidcProvider
+	a.mu.RUnlock()
+	if oidc == nil {
 		return nil, false
 	}
 
-	return a.oidcProvider.VerifyToken(r.Context(), token)
+	return oidc.VerifyToken(r.Context(), token)
+}
+
+// authenticateMTLS identifies the client by its TLS certificate. Only
+// certificates verified against the configured CA are accepted.
+func (a *Authenticator) authenticateMTLS(r *http.Request) (*User, bool) {
+	if a.mtlsProvider == nil || r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+
+	// A mapped DN authenticates as the database user, with its role.
+	if username, ok := a.mtlsProvider.MappedUsername(cert); ok {
+		if a.service == nil {
+			return nil, false
+		}
+		user, err := a.service.GetUserByUsername(r.Context(), username)
+		if err != nil || !user.Enabled {
+			return nil, false
+		}
+		return &User{
+			UserID:   user.ID,
+			Username: user.Username,
+			Role:     user.Role,
+			Method:   "mtls",
+			Tenant:   user.Tenant,
+		}, true
+	}
+
+	role, ok := a.mtlsProvider.Role(cert)
+	if !ok {
+		return nil, false
+	}
+	return &User{
+		Username: certificateUsername(cert),
+		Role:     role,
+		Method:   "mtls",
+	}, true
 }
 
 // unauthorized sends an authentication challenge.
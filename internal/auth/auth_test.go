@@ -550,3 +550,25 @@ func TestAuthenticator_SetProviders(t *testing.T) {
 		t.Error("Expected API key to be added")
 	}
 }
+
+func TestActingForTenant(t *testing.T) {
+	admin := &User{Username: "root", Role: string(RoleSuperAdmin)}
+	acting, ok := ActingForTenant(admin, "acme")
+	if !ok || acting.Tenant != "acme" || acting.Username != "root" {
+		t.Fatalf("ActingForTenant(super admin) = %+v, %v", acting, ok)
+	}
+	if admin.Tenant != "" {
+		t.Error("ActingForTenant modified the original user")
+	}
+
+	for _, user := range []*User{
+		nil,
+		{Username: "dev", Role: string(RoleDeveloper)},
+		{Username: "tenant-admin", Role: string(RoleSuperAdmin), Tenant: "globex"},
+		{Username: "share", Role: string(RoleSuperAdmin), Share: &ShareScope{Context: ".acme"}},
+	} {
+		if _, ok := ActingForTenant(user, "acme"); ok {
+			t.Errorf("ActingForTenant(%+v) should be refused", user)
+		}
+	}
+}
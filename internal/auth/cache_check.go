@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Triggers of a cache reconciliation.
+const (
+	CacheCheckScheduled = "scheduled" // The periodic consistency check
+	CacheCheckForced    = "forced"    // Requested through the admin API
+)
+
+// Reasons an API key is dropped from the cache.
+const (
+	InvalidationUpdated      = "updated"
+	InvalidationRevoked      = "revoked"
+	InvalidationDeleted      = "deleted"
+	InvalidationOwnerDeleted = "owner_deleted"
+)
+
+// CacheReport describes one reconciliation of the auth caches with storage.
+// Divergent entries have already been corrected when the report is returned.
+type CacheReport struct {
+	Trigger   string
+	CheckedAt time.Time
+	Duration  time.Duration
+
+	APIKeys         int // API keys in storage
+	StaleAPIKeys    int // Cached keys whose role, status, owner or expiry differed from storage
+	OrphanedAPIKeys int // Cached keys no longer in storage
+	MissingAPIKeys  int // Stored keys that were not cached
+
+	CachedUsers int // Users with cached credentials
+	StaleUsers  int // Cached users deleted, disabled or changed in storage
+
+	Grants      int  // Grants in storage
+	StaleGrants bool // Whether the cached grants differed from storage
+}
+
+// Diverged reports whether any cache differed from storage.
+func (r *CacheReport) Diverged() bool {
+	return r.StaleAPIKeys+r.OrphanedAPIKeys+r.MissingAPIKeys+r.StaleUsers > 0 || r.StaleGrants
+}
+
+// LastCacheReport returns the result of the most recent reconciliation, or
+// nil if none has completed.
+func (s *Service) LastCacheReport() *CacheReport {
+	s.lastCacheReportMu.RLock()
+	defer s.lastCacheReportMu.RUnlock()
+	return s.lastCacheReport
+}
+
+// ReconcileCache compares the cached API keys, user credentials and grants
+// with storage, replaces whatever diverged, and records the divergence in
+// metrics. It does nothing to caches that are disabled.
+func (s *Service) ReconcileCache(ctx context.Context, trigger string) (*CacheReport, error) {
+	start := time.Now()
+	report := &CacheReport{Trigger: trigger, CheckedAt: start.UTC()}
+
+	err := s.reconcileCache(ctx, report)
+	report.Duration = time.Since(start)
+	if s.metrics != nil {
+		s.metrics.RecordAuthCacheReconciliation(trigger, err)
+	}
+	if err != nil {
+		slog.Warn("auth cache consistency check failed", "trigger", trigger, "error", err)
+		return nil, err
+	}
+
+	if s.metrics != nil {
+		s.metrics.RecordAuthCacheDivergence("api_key", "stale", report.StaleAPIKeys)
+		s.metrics.RecordAuthCacheDivergence("api_key", "orphaned", report.OrphanedAPIKeys)
+		s.metrics.RecordAuthCacheDivergence("api_key", "missing", report.MissingAPIKeys)
+		s.metrics.RecordAuthCacheDivergence("user_credentials", "stale", report.StaleUsers)
+		if report.StaleGrants {
+			s.metrics.RecordAuthCacheDivergence("grant", "stale", 1)
+		}
+	}
+	if report.Diverged() {
+		slog.Warn("auth cache diverged from storage",
+			"trigger", trigger,
+			"stale_api_keys", report.StaleAPIKeys,
+			"orphaned_api_keys", report.OrphanedAPIKeys,
+			"missing_api_keys", report.MissingAPIKeys,
+			"stale_users", report.StaleUsers,
+			"stale_grants", report.StaleGrants)
+	}
+
+	s.lastCacheReportMu.Lock()
+	s.lastCacheReport = report
+	s.lastCacheReportMu.Unlock()
+	return report, nil
+}
+
+func (s *Service) reconcileCache(ctx context.Context, report *CacheReport) error {
+	if s.cacheRefreshInterval > 0 {
+		diff, err := s.syncAPIKeyCache(ctx)
+		if err != nil {
+			return err
+		}
+		report.APIKeys = diff.stored
+		report.StaleAPIKeys = diff.stale
+		report.OrphanedAPIKeys = diff.orphaned
+		report.MissingAPIKeys = diff.missing
+
+		grants, err := s.storage.ListGrants(ctx)
+		if err != nil {
+			return err
+		}
+		report.Grants = len(grants)
+		s.grantCacheMu.Lock()
+		report.StaleGrants = !sameGrants(s.grantCache, grants)
+		s.grantCache = grants
+		s.grantCacheMu.Unlock()
+	}
+
+	if s.userCacheTTL > 0 {
+		checked, stale, err := s.reconcileUserCredCache(ctx)
+		if err != nil {
+			return err
+		}
+		report.CachedUsers = checked
+		report.StaleUsers = stale
+	}
+	return nil
+}
+
+// apiKeyCacheDiff counts how the cached API keys differed from storage.
+type apiKeyCacheDiff struct {
+	stored, stale, orphaned, missing int
+}
+
+// syncAPIKeyCache replaces the cached API keys with those in storage. If a
+// key is invalidated while storage is being read, the cache is left alone
+// rather than filled with records that may predate the invalidation; the
+// next refresh picks the change up.
+func (s *Service) syncAPIKeyCache(ctx context.Context) (apiKeyCacheDiff, error) {
+	s.apiKeyCacheMu.Lock()
+	gen := s.apiKeyCacheGen
+	s.apiKeyCacheMu.Unlock()
+
+	keys, err := s.storage.ListAPIKeys(ctx)
+	if err != nil {
+		return apiKeyCacheDiff{}, err
+	}
+
+	newKeys := make(map[string]*storage.APIKeyRecord, len(keys))
+	for _, key := range keys {
+		newKeys[key.KeyHash] = key
+	}
+	diff := apiKeyCacheDiff{stored: len(newKeys)}
+
+	s.apiKeyCacheMu.Lock()
+	defer s.apiKeyCacheMu.Unlock()
+	if s.apiKeyCacheGen != gen {
+		return diff, nil
+	}
+
+	cached := 0
+	s.apiKeyCache.Range(func(k, v interface{}) bool {
+		hash := k.(string)
+		stored, exists := newKeys[hash]
+		switch {
+		case !exists:
+			diff.orphaned++
+			s.apiKeyCache.Delete(hash)
+			return true
+		case apiKeyDiffers(v.(*storage.APIKeyRecord), stored):
+			diff.stale++
+		}
+		cached++
+		return true
+	})
+	diff.missing = len(newKeys) - cached
+
+	for hash, key := range newKeys {
+		s.apiKeyCache.Store(hash, key)
+	}
+
+	if s.metrics != nil {
+		s.metrics.UpdateCacheSize("api_key", float64(len(newKeys)))
+	}
+	return diff, nil
+}
+
+// apiKeyDiffers reports whether a cached API key would authenticate
+// differently from its stored record.
+func apiKeyDiffers(cached, stored *storage.APIKeyRecord) bool {
+	return cached.ID != stored.ID ||
+		cached.UserID != stored.UserID ||
+		cached.Role != stored.Role ||
+		cached.Enabled != stored.Enabled ||
+		!cached.ExpiresAt.Equal(stored.ExpiresAt) ||
+		!storage.EqualAPIKeyScopes(cached.Scopes, stored.Scopes)
+}
+
+// invalidateAPIKeys drops every cached API key matching match. Revocations,
+// deletions and updates call it as soon as storage has been written, so they
+// take effect on this instance immediately instead of at the next refresh.
+func (s *Service) invalidateAPIKeys(reason string, match func(*storage.APIKeyRecord) bool) {
+	s.apiKeyCacheMu.Lock()
+	defer s.apiKeyCacheMu.Unlock()
+
+	s.apiKeyCacheGen++
+	dropped := 0
+	s.apiKeyCache.Range(func(key, value interface{}) bool {
+		if record, ok := value.(*storage.APIKeyRecord); ok && match(record) {
+			s.apiKeyCache.Delete(key)
+			dropped++
+		}
+		return true
+	})
+	if s.metrics != nil && dropped > 0 {
+		s.metrics.RecordAuthCacheInvalidation(reason, dropped)
+	}
+}
+
+// reconcileUserCredCache drops cached credentials of users that were
+// deleted, disabled or changed in storage. It returns the number of distinct
+// users checked and the number found stale.
+func (s *Service) reconcileUserCredCache(ctx context.Context) (int, int, error) {
+	cached := make(map[int64]*storage.UserRecord)
+	s.userCredCache.Range(func(_, value interface{}) bool {
+		if entry, ok := value.(*userCacheEntry); ok {
+			cached[entry.user.ID] = entry.user
+		}
+		return true
+	})
+
+	stale := 0
+	for id, user := range cached {
+		stored, err := s.storage.GetUserByID(ctx, id)
+		if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+			return 0, 0, err
+		}
+		if stored != nil && stored.Enabled && stored.PasswordHash == user.PasswordHash &&
+			stored.Role == user.Role && stored.Tenant == user.Tenant &&
+			stored.MustChangePassword == user.MustChangePassword {
+			continue
+		}
+		stale++
+		s.invalidateUserCredCacheByID(id)
+	}
+	return len(cached), stale, nil
+}
+
+// sameGrants reports whether two grant lists hold the same grants.
+func sameGrants(a, b []*storage.GrantRecord) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byID := make(map[int64]*storage.GrantRecord, len(a))
+	for _, g := range a {
+		byID[g.ID] = g
+	}
+	for _, g := range b {
+		cached, ok := byID[g.ID]
+		if !ok || cached.Username != g.Username || cached.APIKeyID != g.APIKeyID || cached.Role != g.Role ||
+			cached.Context != g.Context || cached.SubjectPrefix != g.SubjectPrefix {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func cachedAPIKeys(svc *Service) int {
+	n := 0
+	svc.apiKeyCache.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestService_RevokeAPIKey_InvalidatesCacheImmediately(t *testing.T) {
+	store := newMockAuthStorage()
+	store.users["testuser"] = &storage.UserRecord{ID: 1, Username: "testuser", Role: "admin", Enabled: true}
+
+	// A long refresh interval, so only invalidation can drop the key
+	svc := NewServiceWithConfig(store, ServiceConfig{CacheRefreshInterval: time.Hour})
+	defer svc.Close()
+
+	ctx := context.Background()
+	created, err := svc.CreateAPIKey(ctx, CreateAPIKeyRequest{
+		Name:      "revoked",
+		UserID:    1,
+		Role:      "admin",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if _, err := svc.ValidateAPIKey(ctx, created.Key); err != nil {
+		t.Fatalf("ValidateAPIKey before revoke: %v", err)
+	}
+
+	// Storage hands out a copy, as the database backends do, so the cached
+	// record is not revoked along with the stored one
+	for hash, k := range store.apiKeys {
+		clone := *k
+		store.apiKeys[hash] = &clone
+	}
+
+	if err := svc.RevokeAPIKey(ctx, created.ID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if n := cachedAPIKeys(svc); n != 0 {
+		t.Errorf("expected revoked key to be dropped from the cache, %d cached", n)
+	}
+	if _, err := svc.ValidateAPIKey(ctx, created.Key); !errors.Is(err, storage.ErrAPIKeyDisabled) {
+		t.Errorf("expected ErrAPIKeyDisabled after revoke, got %v", err)
+	}
+}
+
+func TestService_DeleteUser_InvalidatesOwnedAPIKeys(t *testing.T) {
+	store := newMockAuthStorage()
+	store.users["owner"] = &storage.UserRecord{ID: 1, Username: "owner", Role: "admin", Enabled: true}
+	store.users["other"] = &storage.UserRecord{ID: 2, Username: "other", Role: "admin", Enabled: true}
+
+	svc := NewServiceWithConfig(store, ServiceConfig{CacheRefreshInterval: time.Hour})
+	defer svc.Close()
+
+	ctx := context.Background()
+	for _, userID := range []int64{1, 2} {
+		if _, err := svc.CreateAPIKey(ctx, CreateAPIKeyRequest{
+			Name:      "key",
+			UserID:    userID,
+			Role:      "admin",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}); err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+	}
+
+	if err := svc.DeleteUser(ctx, 1); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	svc.apiKeyCache.Range(func(_, v interface{}) bool {
+		if v.(*storage.APIKeyRecord).UserID == 1 {
+			t.Error("expected the deleted user's API key to be dropped from the cache")
+		}
+		return true
+	})
+	if n := cachedAPIKeys(svc); n != 1 {
+		t.Errorf("expected the other user's key to stay cached, %d cached", n)
+	}
+}
+
+func TestService_ReconcileCache_APIKeys(t *testing.T) {
+	store := newMockAuthStorage()
+	svc := NewServiceWithConfig(store, ServiceConfig{CacheRefreshInterval: time.Hour})
+	defer svc.Close()
+
+	expires := time.Now().Add(time.Hour)
+	unchanged := &storage.APIKeyRecord{ID: 1, KeyHash: "unchanged", Role: "admin", Enabled: true, ExpiresAt: expires}
+	stale := &storage.APIKeyRecord{ID: 2, KeyHash: "stale", Role: "admin", Enabled: true, ExpiresAt: expires}
+	orphaned := &storage.APIKeyRecord{ID: 3, KeyHash: "orphaned", Role: "admin", Enabled: true, ExpiresAt: expires}
+	missing := &storage.APIKeyRecord{ID: 4, KeyHash: "missing", Role: "readonly", Enabled: true, ExpiresAt: expires}
+
+	// The cache as a run of failed refreshes might have left it
+	svc.apiKeyCache.Store(unchanged.KeyHash, unchanged)
+	svc.apiKeyCache.Store(stale.KeyHash, stale)
+	svc.apiKeyCache.Store(orphaned.KeyHash, orphaned)
+
+	restricted := *stale
+	restricted.Enabled = false
+	store.apiKeys[unchanged.KeyHash] = unchanged
+	store.apiKeys[stale.KeyHash] = &restricted
+	store.apiKeys[missing.KeyHash] = missing
+
+	report, err := svc.ReconcileCache(context.Background(), CacheCheckForced)
+	if err != nil {
+		t.Fatalf("ReconcileCache: %v", err)
+	}
+	if report.Trigger != CacheCheckForced {
+		t.Errorf("expected trigger %q, got %q", CacheCheckForced, report.Trigger)
+	}
+	if report.APIKeys != 3 || report.StaleAPIKeys != 1 || report.OrphanedAPIKeys != 1 || report.MissingAPIKeys != 1 {
+		t.Errorf("unexpected API key counts: %+v", report)
+	}
+	if !report.Diverged() {
+		t.Error("expected the report to show divergence")
+	}
+
+	if _, ok := svc.apiKeyCache.Load(orphaned.KeyHash); ok {
+		t.Error("expected orphaned key to be dropped from the cache")
+	}
+	if v, ok := svc.apiKeyCache.Load(stale.KeyHash); !ok || v.(*storage.APIKeyRecord).Enabled {
+		t.Error("expected stale key to be replaced by the stored record")
+	}
+	if _, ok := svc.apiKeyCache.Load(missing.KeyHash); !ok {
+		t.Error("expected missing key to be cached")
+	}
+	if svc.LastCacheReport() != report {
+		t.Error("expected LastCacheReport to return the latest report")
+	}
+
+	// A second pass finds nothing left to correct
+	report, err = svc.ReconcileCache(context.Background(), CacheCheckScheduled)
+	if err != nil {
+		t.Fatalf("ReconcileCache: %v", err)
+	}
+	if report.Diverged() {
+		t.Errorf("expected no divergence after correction, got %+v", report)
+	}
+}
+
+func TestService_ReconcileCache_UserCredentials(t *testing.T) {
+	store := newMockAuthStorage()
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	store.users["kept"] = &storage.UserRecord{ID: 1, Username: "kept", PasswordHash: string(hash), Role: "admin", Enabled: true}
+	store.users["demoted"] = &storage.UserRecord{ID: 2, Username: "demoted", PasswordHash: string(hash), Role: "admin", Enabled: true}
+
+	svc := NewServiceWithConfig(store, ServiceConfig{UserCacheTTL: time.Hour})
+	defer svc.Close()
+
+	ctx := context.Background()
+	for _, username := range []string{"kept", "demoted"} {
+		if _, err := svc.ValidateCredentials(ctx, username, "password"); err != nil {
+			t.Fatalf("ValidateCredentials(%s): %v", username, err)
+		}
+	}
+
+	// Changed directly in storage, bypassing the service
+	demoted := *store.users["demoted"]
+	demoted.Role = "readonly"
+	store.users["demoted"] = &demoted
+
+	report, err := svc.ReconcileCache(ctx, CacheCheckScheduled)
+	if err != nil {
+		t.Fatalf("ReconcileCache: %v", err)
+	}
+	if report.CachedUsers != 2 || report.StaleUsers != 1 {
+		t.Errorf("expected 1 of 2 cached users stale, got %+v", report)
+	}
+
+	user, err := svc.ValidateCredentials(ctx, "demoted", "password")
+	if err != nil {
+		t.Fatalf("ValidateCredentials after reconcile: %v", err)
+	}
+	if user.Role != "readonly" {
+		t.Errorf("expected stored role readonly after reconcile, got %s", user.Role)
+	}
+}
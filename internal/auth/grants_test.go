@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+type staticGrants []*storage.GrantRecord
+
+func (g staticGrants) ActiveGrants(ctx context.Context) []*storage.GrantRecord {
+	return g
+}
+
+func TestRequestScope(t *testing.T) {
+	tests := []struct {
+		path    string
+		ok      bool
+		context string
+		subject string
+	}{
+		{"/subjects/orders-value/versions", true, ".", "orders-value"},
+		{"/subjects", true, ".", ""},
+		{"/config/orders-value", true, ".", "orders-value"},
+		{"/mode", true, ".", ""},
+		{"/contexts/.dev/subjects/orders-value/versions/1", true, ".dev", "orders-value"},
+		{"/contexts/dev/config", true, ".dev", ""},
+		{"/subjects/:.dev:orders-value/versions", true, ".dev", "orders-value"},
+		{"/contexts/.prod/subjects/:.dev:orders-value", true, ".dev", "orders-value"},
+		{"/schemas/ids/1", false, "", ""},
+		{"/admin/users", false, "", ""},
+		{"/contexts/.dev", false, "", ""},
+		{"/contexts/.dev/settings", false, "", ""},
+	}
+	for _, tt := range tests {
+		scope, ok := RequestScope(tt.path)
+		if ok != tt.ok {
+			t.Errorf("RequestScope(%q) ok = %v, want %v", tt.path, ok, tt.ok)
+			continue
+		}
+		if ok && (scope.Context != tt.context || scope.Subject != tt.subject) {
+			t.Errorf("RequestScope(%q) = %+v, want context %q subject %q", tt.path, scope, tt.context, tt.subject)
+		}
+	}
+}
+
+func TestHasScopedPermission(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	authorizer.SetGrantProvider(staticGrants{
+		{ID: 1, Username: "dev-user", Role: "developer", Context: ".dev"},
+		{ID: 2, Username: "dev-user", Role: "admin", SubjectPrefix: "sandbox-"},
+		{ID: 3, APIKeyID: 10, Role: "developer", Context: ".ci"},
+	})
+	ctx := context.Background()
+	user := &User{UserID: 5, Username: "dev-user", Role: "readonly", Method: "basic"}
+
+	tests := []struct {
+		name  string
+		user  *User
+		perm  Permission
+		scope ResourceScope
+		want  bool
+	}{
+		{"base role still applies", user, PermissionSchemaRead, ResourceScope{Context: ".prod", Subject: "a"}, true},
+		{"context grant", user, PermissionSchemaWrite, ResourceScope{Context: ".dev", Subject: "orders"}, true},
+		{"context grant covers context-level operations", user, PermissionSchemaWrite, ResourceScope{Context: ".dev"}, true},
+		{"context grant role limits", user, PermissionSchemaDelete, ResourceScope{Context: ".dev", Subject: "orders"}, false},
+		{"other context", user, PermissionSchemaWrite, ResourceScope{Context: ".prod", Subject: "orders"}, false},
+		{"prefix grant in any context", user, PermissionSchemaDelete, ResourceScope{Context: ".prod", Subject: "sandbox-x"}, true},
+		{"prefix grant needs a subject", user, PermissionConfigWrite, ResourceScope{Context: "."}, false},
+		{"grants never confer admin", user, PermissionAdminRead, ResourceScope{Context: ".", Subject: "sandbox-x"}, false},
+		{"other user", &User{Username: "someone", Role: "readonly"}, PermissionSchemaWrite, ResourceScope{Context: ".dev"}, false},
+		{"api key by ID", &User{APIKeyID: 10, Username: "owner", Role: "readonly", Method: "api_key"}, PermissionSchemaWrite, ResourceScope{Context: ".ci", Subject: "x"}, true},
+		{"api key does not inherit owner grants", &User{APIKeyID: 11, Username: "dev-user", Role: "readonly", Method: "api_key"}, PermissionSchemaWrite, ResourceScope{Context: ".dev", Subject: "x"}, false},
+		{"api key session by key ID", &User{UserID: 5, APIKeyID: 10, Username: "dev-user", Role: "readonly", Method: AuthMethodSession}, PermissionSchemaWrite, ResourceScope{Context: ".ci", Subject: "x"}, true},
+		{"api key session does not inherit owner grants", &User{UserID: 5, APIKeyID: 11, Username: "dev-user", Role: "readonly", Method: AuthMethodSession}, PermissionSchemaWrite, ResourceScope{Context: ".dev", Subject: "x"}, false},
+	}
+	for _, tt := range tests {
+		if got := authorizer.HasScopedPermission(ctx, tt.user, tt.perm, tt.scope); got != tt.want {
+			t.Errorf("%s: HasScopedPermission = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorizeEndpoint_Grants(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	authorizer.SetGrantProvider(staticGrants{
+		{ID: 1, Username: "dev-user", Role: "developer", Context: ".dev"},
+	})
+	handler := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	user := &User{UserID: 5, Username: "dev-user", Role: "readonly", Method: "basic"}
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"POST", "/contexts/.dev/subjects/orders-value/versions", http.StatusOK},
+		{"POST", "/subjects/:.dev:orders-value/versions", http.StatusOK},
+		{"POST", "/subjects/orders-value/versions", http.StatusForbidden},
+		{"DELETE", "/contexts/.dev/subjects/orders-value", http.StatusForbidden},
+		{"POST", "/import/schemas", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req = req.WithContext(setUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, rr.Code)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math/big"
 	"net/http"
 	"os"
@@ -25,20 +26,34 @@ import (
 	"github.com/axonops/axonops-schema-registry/internal/config"
 )
 
-// JWTProvider handles JWT authentication.
+// jwksMinRefreshInterval bounds how often a token signed with a key that is
+// not in the cached JWKS makes the provider fetch the key set again.
+const jwksMinRefreshInterval = 30 * time.Second
+
+// JWTProvider handles JWT authentication. It trusts one or more issuers,
+// each with its own keys, audience and claims mapping, and verifies a token
+// with the issuer its iss claim names. JWKS key sets are refreshed in the
+// background; call Close to stop the refresh.
 type JWTProvider struct {
-	config    config.JWTConfig
-	publicKey any // *rsa.PublicKey, *ecdsa.PublicKey, or []byte for HMAC
-	mu        sync.RWMutex
-
-	// JWKS support
-	jwksKeys      map[string]any // kid -> public key
-	jwksLastFetch time.Time
-	jwksCacheTTL  time.Duration
-	httpClient    *http.Client
+	issuers      []*jwtIssuer
+	jwksCacheTTL time.Duration
+	stopCh       chan struct{}
+}
+
+// jwtIssuer verifies the tokens of one trusted issuer.
+type jwtIssuer struct {
+	config     config.JWTIssuerConfig
+	publicKey  any // *rsa.PublicKey, *ecdsa.PublicKey, or []byte for HMAC
+	httpClient *http.Client
+
+	mu              sync.RWMutex
+	jwksKeys        map[string]any // kid -> public key
+	jwksLastFetch   time.Time
+	jwksLastAttempt time.Time // last refresh for an unknown kid
 }
 
-// NewJWTProvider creates a new JWT authentication provider.
+// NewJWTProvider creates a new JWT authentication provider for the issuers
+// returned by cfg.TrustedIssuers.
 func NewJWTProvider(cfg config.JWTConfig) (*JWTProvider, error) {
 	jwksCacheTTL := 5 * time.Minute
 	if cfg.JWKSCacheTTL > 0 {
@@ -48,35 +63,83 @@ func NewJWTProvider(cfg config.JWTConfig) (*JWTProvider, error) {
 	if cfg.HTTPTimeout > 0 {
 		httpTimeout = time.Duration(cfg.HTTPTimeout) * time.Second
 	}
+	httpClient := &http.Client{
+		Timeout: httpTimeout,
+	}
 
 	p := &JWTProvider{
-		config:       cfg,
-		jwksKeys:     make(map[string]any),
 		jwksCacheTTL: jwksCacheTTL,
-		httpClient: &http.Client{
-			Timeout: httpTimeout,
-		},
-	}
+		stopCh:       make(chan struct{}),
+	}
+	usesJWKS := false
+	for _, issCfg := range cfg.TrustedIssuers() {
+		iss := &jwtIssuer{
+			config:     issCfg,
+			httpClient: httpClient,
+			jwksKeys:   make(map[string]any),
+		}
 
-	// Load the public key if specified
-	if cfg.PublicKeyFile != "" {
-		if err := p.loadPublicKey(cfg.PublicKeyFile, cfg.Algorithm); err != nil {
-			return nil, fmt.Errorf("failed to load public key: %w", err)
+		// Load the public key if specified
+		if issCfg.PublicKeyFile != "" {
+			if err := iss.loadPublicKey(issCfg.PublicKeyFile, issCfg.Algorithm); err != nil {
+				return nil, fmt.Errorf("issuer %q: failed to load public key: %w", issCfg.Issuer, err)
+			}
 		}
-	}
 
-	// Load JWKS if URL is specified
-	if cfg.JWKSURL != "" {
-		if err := p.refreshJWKS(); err != nil {
-			return nil, fmt.Errorf("failed to load JWKS: %w", err)
+		// Load JWKS if URL is specified
+		if issCfg.JWKSURL != "" {
+			if err := iss.refreshJWKS(); err != nil {
+				return nil, fmt.Errorf("issuer %q: failed to load JWKS: %w", issCfg.Issuer, err)
+			}
+			usesJWKS = true
 		}
+		p.issuers = append(p.issuers, iss)
 	}
 
+	if usesJWKS {
+		go p.refreshLoop()
+	}
 	return p, nil
 }
 
+// Close stops the background JWKS refresh.
+func (p *JWTProvider) Close() {
+	select {
+	case <-p.stopCh:
+		// already closed
+	default:
+		close(p.stopCh)
+	}
+}
+
+// refreshLoop fetches the JWKS of every issuer once per cache TTL. A failed
+// fetch keeps the previous key set, so an identity provider outage does not
+// reject tokens signed with keys already known.
+func (p *JWTProvider) refreshLoop() {
+	ticker := time.NewTicker(p.jwksCacheTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, iss := range p.issuers {
+				if iss.config.JWKSURL == "" {
+					continue
+				}
+				if err := iss.refreshJWKS(); err != nil {
+					slog.Warn("JWKS refresh failed, keeping the previous keys",
+						slog.String("issuer", iss.config.Issuer),
+						slog.String("error", err.Error()),
+					)
+				}
+			}
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
 // loadPublicKey loads a public key from a PEM file.
-func (p *JWTProvider) loadPublicKey(keyFile, algorithm string) error {
+func (i *jwtIssuer) loadPublicKey(keyFile, algorithm string) error {
 	keyData, err := os.ReadFile(keyFile) // #nosec G304 -- keyFile is from trusted server configuration
 	if err != nil {
 		return fmt.Errorf("failed to read key file: %w", err)
@@ -102,7 +165,7 @@ func (p *JWTProvider) loadPublicKey(keyFile, algorithm string) error {
 		if !ok {
 			return errors.New("key is not an RSA public key")
 		}
-		p.publicKey = rsaKey
+		i.publicKey = rsaKey
 
 	case strings.HasPrefix(algorithm, "ES"):
 		// ECDSA public key
@@ -110,11 +173,11 @@ func (p *JWTProvider) loadPublicKey(keyFile, algorithm string) error {
 		if err != nil {
 			return fmt.Errorf("failed to parse ECDSA public key: %w", err)
 		}
-		p.publicKey = pub
+		i.publicKey = pub
 
 	case strings.HasPrefix(algorithm, "HS"):
 		// HMAC secret (raw bytes)
-		p.publicKey = keyData
+		i.publicKey = keyData
 
 	default:
 		return fmt.Errorf("unsupported algorithm: %s", algorithm)
@@ -142,8 +205,8 @@ type jwkKey struct {
 }
 
 // refreshJWKS fetches and parses the JWKS from the configured URL.
-func (p *JWTProvider) refreshJWKS() error {
-	resp, err := p.httpClient.Get(p.config.JWKSURL)
+func (i *jwtIssuer) refreshJWKS() error {
+	resp, err := i.httpClient.Get(i.config.JWKSURL)
 	if err != nil {
 		return fmt.Errorf("failed to fetch JWKS: %w", err)
 	}
@@ -170,7 +233,7 @@ func (p *JWTProvider) refreshJWKS() error {
 			continue // Skip non-signature keys
 		}
 
-		pubKey, err := p.parseJWK(key)
+		pubKey, err := parseJWK(key)
 		if err != nil {
 			continue // Skip keys we can't parse
 		}
@@ -183,27 +246,27 @@ func (p *JWTProvider) refreshJWKS() error {
 	}
 
 	// Atomically replace the key set
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	p.jwksKeys = newKeys
-	p.jwksLastFetch = time.Now()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.jwksKeys = newKeys
+	i.jwksLastFetch = time.Now()
 	return nil
 }
 
 // parseJWK parses a JWK into a Go public key.
-func (p *JWTProvider) parseJWK(key jwkKey) (any, error) {
+func parseJWK(key jwkKey) (any, error) {
 	switch key.Kty {
 	case "RSA":
-		return p.parseRSAJWK(key)
+		return parseRSAJWK(key)
 	case "EC":
-		return p.parseECJWK(key)
+		return parseECJWK(key)
 	default:
 		return nil, fmt.Errorf("unsupported key type: %s", key.Kty)
 	}
 }
 
 // parseRSAJWK parses an RSA JWK into an *rsa.PublicKey.
-func (p *JWTProvider) parseRSAJWK(key jwkKey) (*rsa.PublicKey, error) {
+func parseRSAJWK(key jwkKey) (*rsa.PublicKey, error) {
 	if key.N == "" || key.E == "" {
 		return nil, errors.New("missing RSA key components")
 	}
@@ -229,7 +292,7 @@ func (p *JWTProvider) parseRSAJWK(key jwkKey) (*rsa.PublicKey, error) {
 }
 
 // parseECJWK parses an EC JWK into an *ecdsa.PublicKey.
-func (p *JWTProvider) parseECJWK(key jwkKey) (*ecdsa.PublicKey, error) {
+func parseECJWK(key jwkKey) (*ecdsa.PublicKey, error) {
 	if key.X == "" || key.Y == "" || key.Crv == "" {
 		return nil, errors.New("missing EC key components")
 	}
@@ -270,55 +333,104 @@ func (p *JWTProvider) parseECJWK(key jwkKey) (*ecdsa.PublicKey, error) {
 
 // getKeyForToken returns the appropriate key for verifying a token.
 // It uses the kid header if present, or falls back to the static key.
-func (p *JWTProvider) getKeyForToken(token *jwt.Token) (any, error) {
+func (i *jwtIssuer) getKeyForToken(token *jwt.Token) (any, error) {
 	// If we have a static public key, use it
-	if p.publicKey != nil {
-		return p.publicKey, nil
+	if i.publicKey != nil {
+		return i.publicKey, nil
 	}
 
 	// Check if JWKS is configured
-	if p.config.JWKSURL == "" {
+	if i.config.JWKSURL == "" {
 		return nil, errors.New("no key configured for JWT validation")
 	}
 
-	// Check if we need to refresh JWKS
-	if time.Since(p.jwksLastFetch) > p.jwksCacheTTL {
-		// Refresh in the background, but don't block if it fails
-		go func() {
-			p.refreshJWKS() //nolint:errcheck
-		}()
-	}
-
 	// Get the key ID from the token header
 	kid, ok := token.Header["kid"].(string)
 	if !ok || kid == "" {
 		kid = "default"
 	}
 
-	// Look up the key
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	if key, ok := i.jwksKey(kid); ok {
+		return key, nil
+	}
 
-	key, ok := p.jwksKeys[kid]
-	if !ok {
-		// If no matching key found and there's only one key, use it
-		if len(p.jwksKeys) == 1 {
-			for _, k := range p.jwksKeys {
-				return k, nil
-			}
+	// An unknown kid may be a key the issuer has just rotated in, so fetch
+	// the key set again before giving up.
+	if i.allowKeyRefresh() {
+		if err := i.refreshJWKS(); err != nil {
+			slog.Warn("JWKS refresh for unknown key failed",
+				slog.String("issuer", i.config.Issuer),
+				slog.String("kid", kid),
+				slog.String("error", err.Error()),
+			)
+		} else if key, ok := i.jwksKey(kid); ok {
+			return key, nil
 		}
-		return nil, fmt.Errorf("key %q not found in JWKS", kid)
 	}
 
-	return key, nil
+	// If no matching key found and there's only one key, use it
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if len(i.jwksKeys) == 1 {
+		for _, k := range i.jwksKeys {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("key %q not found in JWKS", kid)
 }
 
-// VerifyToken verifies a JWT token and returns the authenticated user.
+// jwksKey looks up a key of the cached JWKS by kid.
+func (i *jwtIssuer) jwksKey(kid string) (any, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	key, ok := i.jwksKeys[kid]
+	return key, ok
+}
+
+// allowKeyRefresh reports whether the JWKS may be fetched for an unknown
+// kid, which is at most once per jwksMinRefreshInterval so that tokens with
+// made-up key IDs cannot flood the identity provider.
+func (i *jwtIssuer) allowKeyRefresh() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if time.Since(i.jwksLastAttempt) < jwksMinRefreshInterval || time.Since(i.jwksLastFetch) < jwksMinRefreshInterval {
+		return false
+	}
+	i.jwksLastAttempt = time.Now()
+	return true
+}
+
+// VerifyToken verifies a JWT token and returns the authenticated user. The
+// token is verified by the trusted issuer its iss claim names, or by the
+// issuer configured without a name, which accepts any iss.
 func (p *JWTProvider) VerifyToken(ctx context.Context, rawToken string) (*User, bool) {
+	unverified := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(rawToken, unverified); err != nil {
+		return nil, false
+	}
+	iss, _ := unverified.GetIssuer()
+
+	var fallback *jwtIssuer
+	for _, i := range p.issuers {
+		if i.config.Issuer == iss {
+			return i.verifyToken(rawToken)
+		}
+		if i.config.Issuer == "" && fallback == nil {
+			fallback = i
+		}
+	}
+	if fallback == nil {
+		return nil, false
+	}
+	return fallback.verifyToken(rawToken)
+}
+
+// verifyToken verifies a token against the issuer's keys and claims.
+func (i *jwtIssuer) verifyToken(rawToken string) (*User, bool) {
 	// Build the key function that handles both static keys and JWKS
 	keyFunc := func(token *jwt.Token) (any, error) {
 		// Validate the signing method based on algorithm config
-		alg := p.config.Algorithm
+		alg := i.config.Algorithm
 		if alg != "" {
 			switch {
 			case strings.HasPrefix(alg, "RS"):
@@ -335,13 +447,13 @@ func (p *JWTProvider) VerifyToken(ctx context.Context, rawToken string) (*User,
 				}
 			}
 		}
-		return p.getKeyForToken(token)
+		return i.getKeyForToken(token)
 	}
 
 	// Build parse options
 	var parseOpts []jwt.ParserOption
-	if p.config.Algorithm != "" {
-		parseOpts = append(parseOpts, jwt.WithValidMethods([]string{p.config.Algorithm}))
+	if i.config.Algorithm != "" {
+		parseOpts = append(parseOpts, jwt.WithValidMethods([]string{i.config.Algorithm}))
 	}
 
 	// Parse and validate the token
@@ -360,19 +472,19 @@ func (p *JWTProvider) VerifyToken(ctx context.Context, rawToken string) (*User,
 	}
 
 	// Validate issuer if configured
-	if p.config.Issuer != "" {
+	if i.config.Issuer != "" {
 		iss, _ := claims.GetIssuer()
-		if iss != p.config.Issuer {
+		if iss != i.config.Issuer {
 			return nil, false
 		}
 	}
 
 	// Validate audience if configured
-	if p.config.Audience != "" {
+	if i.config.Audience != "" {
 		aud, _ := claims.GetAudience()
 		found := false
 		for _, a := range aud {
-			if a == p.config.Audience {
+			if a == i.config.Audience {
 				found = true
 				break
 			}
@@ -391,31 +503,37 @@ func (p *JWTProvider) VerifyToken(ctx context.Context, rawToken string) (*User,
 	}
 
 	// Extract user information from claims
-	username := p.extractClaim(claims, "sub")
+	username := i.extractClaim(claims, "sub")
 	if username == "" {
-		username = p.extractClaim(claims, "preferred_username")
+		username = i.extractClaim(claims, "preferred_username")
 	}
 	if username == "" {
-		username = p.extractClaim(claims, "email")
+		username = i.extractClaim(claims, "email")
 	}
 	if username == "" {
 		return nil, false
 	}
 
 	// Extract role from claims
-	role := p.determineRole(claims)
+	role := i.determineRole(claims)
+
+	var tenant string
+	if i.config.TenantClaim != "" {
+		tenant, _ = claims[i.config.TenantClaim].(string)
+	}
 
 	return &User{
 		Username: username,
 		Role:     role,
 		Method:   "jwt",
+		Tenant:   tenant,
 	}, true
 }
 
 // extractClaim extracts a string claim from the token.
-func (p *JWTProvider) extractClaim(claims jwt.MapClaims, key string) string {
+func (i *jwtIssuer) extractClaim(claims jwt.MapClaims, key string) string {
 	// Check if there's a mapping for this claim
-	if mappedKey, ok := p.config.ClaimsMapping[key]; ok {
+	if mappedKey, ok := i.config.ClaimsMapping[key]; ok {
 		key = mappedKey
 	}
 
@@ -428,10 +546,10 @@ func (p *JWTProvider) extractClaim(claims jwt.MapClaims, key string) string {
 }
 
 // determineRole extracts the role from JWT claims.
-func (p *JWTProvider) determineRole(claims jwt.MapClaims) string {
+func (i *jwtIssuer) determineRole(claims jwt.MapClaims) string {
 	// Check claims mapping for role
 	roleKey := "role"
-	if mappedKey, ok := p.config.ClaimsMapping["role"]; ok {
+	if mappedKey, ok := i.config.ClaimsMapping["role"]; ok {
 		roleKey = mappedKey
 	}
 
@@ -444,7 +562,7 @@ func (p *JWTProvider) determineRole(claims jwt.MapClaims) string {
 
 	// Try roles array
 	rolesKey := "roles"
-	if mappedKey, ok := p.config.ClaimsMapping["roles"]; ok {
+	if mappedKey, ok := i.config.ClaimsMapping["roles"]; ok {
 		rolesKey = mappedKey
 	}
 
@@ -457,8 +575,8 @@ func (p *JWTProvider) determineRole(claims jwt.MapClaims) string {
 	}
 
 	// Default role
-	if p.config.DefaultRole != "" {
-		return p.config.DefaultRole
+	if i.config.DefaultRole != "" {
+		return i.config.DefaultRole
 	}
 	return "readonly"
 }
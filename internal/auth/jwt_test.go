@@ -13,6 +13,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -290,6 +291,48 @@ func TestJWTProvider_VerifyToken_ClaimsMapping(t *testing.T) {
 	}
 }
 
+func TestJWTProvider_VerifyToken_TenantClaim(t *testing.T) {
+	key := generateTestRSAKey(t)
+
+	tmpDir := t.TempDir()
+	keyFile := filepath.Join(tmpDir, "public.pem")
+	if err := writePublicKey(keyFile, &key.PublicKey); err != nil {
+		t.Fatalf("failed to write public key: %v", err)
+	}
+
+	claims := jwt.MapClaims{
+		"sub":    "alice",
+		"tenant": "acme",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}
+	token := createTestToken(t, key, claims, jwt.SigningMethodRS256)
+
+	for _, tt := range []struct {
+		claim string
+		want  string
+	}{
+		{"", ""},
+		{"tenant", "acme"},
+		{"org", ""},
+	} {
+		provider, err := NewJWTProvider(config.JWTConfig{
+			Algorithm:     "RS256",
+			PublicKeyFile: keyFile,
+			TenantClaim:   tt.claim,
+		})
+		if err != nil {
+			t.Fatalf("failed to create provider: %v", err)
+		}
+		user, ok := provider.VerifyToken(context.Background(), token)
+		if !ok {
+			t.Fatal("expected token to be valid")
+		}
+		if user.Tenant != tt.want {
+			t.Errorf("tenant_claim %q: expected tenant %q, got %q", tt.claim, tt.want, user.Tenant)
+		}
+	}
+}
+
 func TestJWTProvider_VerifyToken_InvalidSignature(t *testing.T) {
 	key := generateTestRSAKey(t)
 	wrongKey := generateTestRSAKey(t)
@@ -406,6 +449,131 @@ func TestJWTProvider_JWKS_InvalidKid(t *testing.T) {
 	}
 }
 
+func TestJWTProvider_MultipleIssuers(t *testing.T) {
+	corpKey := generateTestRSAKey(t)
+	ciKey := generateTestRSAKey(t)
+
+	jwks := createTestJWKS(t, &corpKey.PublicKey, "corp-kid")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	ciKeyFile := filepath.Join(t.TempDir(), "ci.pem")
+	if err := writePublicKey(ciKeyFile, &ciKey.PublicKey); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	provider, err := NewJWTProvider(config.JWTConfig{
+		Issuers: []config.JWTIssuerConfig{
+			{Issuer: "https://corp.example.com", JWKSURL: server.URL, Algorithm: "RS256", Audience: "registry"},
+			{
+				Issuer:        "https://ci.example.com",
+				PublicKeyFile: ciKeyFile,
+				Algorithm:     "RS256",
+				ClaimsMapping: map[string]string{"role": "ci_role"},
+				DefaultRole:   "developer",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	sign := func(key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		tokenStr, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return tokenStr
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantOK   bool
+		wantRole string
+	}{
+		{"corp token", sign(corpKey, "corp-kid", jwt.MapClaims{"sub": "alice", "iss": "https://corp.example.com", "aud": "registry", "role": "admin"}), true, "admin"},
+		{"ci token with its own claims mapping", sign(ciKey, "", jwt.MapClaims{"sub": "pipeline", "iss": "https://ci.example.com", "ci_role": "developer"}), true, "developer"},
+		{"corp token without the corp audience", sign(corpKey, "corp-kid", jwt.MapClaims{"sub": "alice", "iss": "https://corp.example.com"}), false, ""},
+		{"ci token claiming the corp issuer", sign(ciKey, "corp-kid", jwt.MapClaims{"sub": "pipeline", "iss": "https://corp.example.com", "aud": "registry"}), false, ""},
+		{"corp token claiming the ci issuer", sign(corpKey, "", jwt.MapClaims{"sub": "alice", "iss": "https://ci.example.com"}), false, ""},
+		{"untrusted issuer", sign(corpKey, "corp-kid", jwt.MapClaims{"sub": "alice", "iss": "https://evil.example.com", "aud": "registry"}), false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, ok := provider.VerifyToken(context.Background(), tt.token)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if ok && user.Role != tt.wantRole {
+				t.Errorf("expected role %q, got %q", tt.wantRole, user.Role)
+			}
+		})
+	}
+}
+
+func TestJWTProvider_JWKS_KeyRotation(t *testing.T) {
+	oldKey := generateTestRSAKey(t)
+	newKey := generateTestRSAKey(t)
+
+	var mu sync.Mutex
+	jwks := createTestJWKS(t, &oldKey.PublicKey, "old-kid")
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	provider, err := NewJWTProvider(config.JWTConfig{Algorithm: "RS256", JWKSURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	// The identity provider rotates to a new key after the JWKS was cached.
+	mu.Lock()
+	jwks = createTestJWKS(t, &newKey.PublicKey, "new-kid")
+	mu.Unlock()
+	provider.issuers[0].mu.Lock()
+	provider.issuers[0].jwksLastFetch = time.Now().Add(-time.Hour)
+	provider.issuers[0].mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = "new-kid"
+	tokenStr, err := token.SignedString(newKey)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	if _, ok := provider.VerifyToken(context.Background(), tokenStr); !ok {
+		t.Fatal("expected a token signed with a rotated-in key to be accepted")
+	}
+
+	// Unknown key IDs do not refetch the key set on every request.
+	token.Header["kid"] = "made-up-kid"
+	tokenStr, _ = token.SignedString(newKey)
+	for range 3 {
+		provider.VerifyToken(context.Background(), tokenStr)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fetches != 2 {
+		t.Errorf("expected 2 JWKS fetches, got %d", fetches)
+	}
+}
+
 // createTestJWKS creates a JWKS JSON response for testing.
 func createTestJWKS(t *testing.T, key *rsa.PublicKey, kid string) []byte {
 	t.Helper()
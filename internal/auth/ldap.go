@@ -183,6 +183,20 @@ func (p *LDAPProvider) connect() (*ldap.Conn, error) {
 	return conn, nil
 }
 
+// Ping checks that the LDAP server is reachable and accepts the service
+// account credentials. It is used by the readiness check.
+func (p *LDAPProvider) Ping(ctx context.Context) error {
+	conn, err := p.connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to LDAP: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.Bind(p.config.BindDN, p.config.BindPassword); err != nil {
+		return fmt.Errorf("failed to bind with service account: %w", err)
+	}
+	return nil
+}
+
 // IsSecure returns true if the LDAP connection uses TLS (LDAPS or StartTLS).
 func (p *LDAPProvider) IsSecure() bool {
 	return strings.HasPrefix(p.config.URL, "ldaps://") || p.config.StartTLS
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"regexp"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+)
+
+// mtlsRoleRule is a compiled config.MTLSRoleRule.
+type mtlsRoleRule struct {
+	match *regexp.Regexp
+	role  string
+}
+
+// MTLSProvider maps verified client certificates to registry principals, so
+// service accounts connecting over mTLS need no additional credential.
+type MTLSProvider struct {
+	userMapping map[string]string
+	rules       []mtlsRoleRule
+	defaultRole string
+}
+
+// NewMTLSProvider creates a new mTLS identity provider.
+func NewMTLSProvider(cfg config.MTLSConfig) (*MTLSProvider, error) {
+	if len(cfg.UserMapping) == 0 && len(cfg.RoleRules) == 0 && cfg.DefaultRole == "" {
+		return nil, fmt.Errorf("mTLS authentication requires user_mapping, role_rules or default_role")
+	}
+	p := &MTLSProvider{
+		userMapping: cfg.UserMapping,
+		defaultRole: cfg.DefaultRole,
+	}
+	for i, rule := range cfg.RoleRules {
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mTLS role rule %d: %w", i, err)
+		}
+		if !ValidRole(rule.Role) {
+			return nil, fmt.Errorf("invalid mTLS role rule %d: unknown role %q", i, rule.Role)
+		}
+		p.rules = append(p.rules, mtlsRoleRule{match: re, role: rule.Role})
+	}
+	if p.defaultRole != "" && !ValidRole(p.defaultRole) {
+		return nil, fmt.Errorf("invalid mTLS default role %q", p.defaultRole)
+	}
+	return p, nil
+}
+
+// MappedUsername returns the database username a certificate's subject DN is
+// mapped to, if any.
+func (p *MTLSProvider) MappedUsername(cert *x509.Certificate) (string, bool) {
+	username, ok := p.userMapping[cert.Subject.String()]
+	return username, ok
+}
+
+// Role returns the role of the first rule matching the certificate's subject
+// DN or one of its subject alternative names, or the default role. ok is
+// false if neither applies.
+func (p *MTLSProvider) Role(cert *x509.Certificate) (role string, ok bool) {
+	identities := certificateIdentities(cert)
+	for _, rule := range p.rules {
+		for _, id := range identities {
+			if rule.match.MatchString(id) {
+				return rule.role, true
+			}
+		}
+	}
+	return p.defaultRole, p.defaultRole != ""
+}
+
+// certificateIdentities returns the subject DN followed by the subject
+// alternative names of a certificate.
+func certificateIdentities(cert *x509.Certificate) []string {
+	ids := []string{cert.Subject.String()}
+	ids = append(ids, cert.DNSNames...)
+	ids = append(ids, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		ids = append(ids, u.String())
+	}
+	return ids
+}
+
+// certificateUsername is the principal name of a certificate matched by a role
+// rule: its common name, else its first subject alternative name, else its
+// subject DN.
+func certificateUsername(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if ids := certificateIdentities(cert); len(ids) > 1 {
+		return ids[1]
+	}
+	return cert.Subject.String()
+}
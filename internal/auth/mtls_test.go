@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func testClientCert(cn, ou string, uris ...string) *x509.Certificate {
+	cert := &x509.Certificate{
+		Subject: pkix.Name{CommonName: cn, OrganizationalUnit: []string{ou}, Organization: []string{"Acme"}},
+	}
+	for _, raw := range uris {
+		u, _ := url.Parse(raw)
+		cert.URIs = append(cert.URIs, u)
+	}
+	return cert
+}
+
+func TestNewMTLSProvider_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.MTLSConfig
+	}{
+		{"empty", config.MTLSConfig{}},
+		{"bad regex", config.MTLSConfig{RoleRules: []config.MTLSRoleRule{{Match: "(", Role: "readonly"}}}},
+		{"unknown role", config.MTLSConfig{RoleRules: []config.MTLSRoleRule{{Match: ".*", Role: "owner"}}}},
+		{"unknown default role", config.MTLSConfig{DefaultRole: "owner"}},
+	}
+	for _, tt := range tests {
+		if _, err := NewMTLSProvider(tt.cfg); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestMTLSProvider_Role(t *testing.T) {
+	p, err := NewMTLSProvider(config.MTLSConfig{
+		RoleRules: []config.MTLSRoleRule{
+			{Match: "^spiffe://acme.example/ns/platform/", Role: "developer"},
+			{Match: "^CN=[^,]+,OU=Services,O=Acme$", Role: "readonly"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewMTLSProvider: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		role string
+		ok   bool
+	}{
+		{"uri san", testClientCert("orders", "Apps", "spiffe://acme.example/ns/platform/sa/orders"), "developer", true},
+		{"subject dn", testClientCert("billing", "Services"), "readonly", true},
+		{"no match", testClientCert("billing", "Contractors"), "", false},
+	}
+	for _, tt := range tests {
+		role, ok := p.Role(tt.cert)
+		if role != tt.role || ok != tt.ok {
+			t.Errorf("%s: Role = (%q, %v), want (%q, %v)", tt.name, role, ok, tt.role, tt.ok)
+		}
+	}
+}
+
+func TestAuthenticator_MTLS(t *testing.T) {
+	store := memory.NewStore()
+	svc := NewService(store)
+	if err := store.CreateUser(context.Background(), &storage.UserRecord{Username: "ci-deployer", Role: "admin", Enabled: true}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	p, err := NewMTLSProvider(config.MTLSConfig{
+		UserMapping: map[string]string{
+			"CN=ci-deployer,OU=Platform,O=Acme": "ci-deployer",
+			"CN=retired,OU=Platform,O=Acme":     "retired",
+		},
+		RoleRules: []config.MTLSRoleRule{{Match: "^CN=[^,]+,OU=Services,O=Acme$", Role: "readonly"}},
+	})
+	if err != nil {
+		t.Fatalf("NewMTLSProvider: %v", err)
+	}
+	a := NewAuthenticator(config.AuthConfig{Enabled: true, Methods: []string{"mtls"}})
+	a.SetService(svc)
+	a.SetMTLSProvider(p)
+
+	verified := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		state    *tls.ConnectionState
+		ok       bool
+		username string
+		role     string
+	}{
+		{"mapped user", verified(testClientCert("ci-deployer", "Platform")), true, "ci-deployer", "admin"},
+		{"mapped missing user", verified(testClientCert("retired", "Platform")), false, "", ""},
+		{"role rule", verified(testClientCert("billing", "Services")), true, "billing", "readonly"},
+		{"no match", verified(testClientCert("billing", "Contractors")), false, "", ""},
+		{"unverified", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{testClientCert("billing", "Services")}}, false, "", ""},
+		{"no tls", nil, false, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/subjects", nil)
+		req.TLS = tt.state
+		user, ok := a.authenticate(req, "mtls")
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if ok && (user.Username != tt.username || user.Role != tt.role || user.Method != "mtls") {
+			t.Errorf("%s: got %+v, want %s with role %s", tt.name, user, tt.username, tt.role)
+		}
+	}
+}
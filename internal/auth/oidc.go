@@ -62,6 +62,15 @@ func NewOIDCProvider(ctx context.Context, cfg config.OIDCConfig) (*OIDCProvider,
 	}, nil
 }
 
+// Ping checks that the issuer's discovery document can be fetched. It is used
+// by the readiness check.
+func (p *OIDCProvider) Ping(ctx context.Context) error {
+	if _, err := oidc.NewProvider(ctx, p.config.IssuerURL); err != nil {
+		return fmt.Errorf("OIDC issuer unreachable: %w", err)
+	}
+	return nil
+}
+
 // VerifyToken validates an OIDC/JWT token and returns the user if valid.
 func (p *OIDCProvider) VerifyToken(ctx context.Context, rawToken string) (*User, bool) {
 	if rawToken == "" {
@@ -97,10 +106,16 @@ func (p *OIDCProvider) VerifyToken(ctx context.Context, rawToken string) (*User,
 	// Extract roles and determine role
 	role := p.determineRole(claims)
 
+	var tenant string
+	if p.config.TenantClaim != "" {
+		tenant = p.extractStringClaim(claims, p.config.TenantClaim)
+	}
+
 	return &User{
 		Username: username,
 		Role:     role,
 		Method:   "oidc",
+		Tenant:   tenant,
 	}, true
 }
 
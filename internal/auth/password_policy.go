@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// ErrPasswordPolicy is returned when a password does not satisfy the
+// password policy. The wrapping error lists what the password lacks.
+var ErrPasswordPolicy = errors.New("password does not satisfy the password policy")
+
+// PasswordPolicy is the policy the passwords of database users must satisfy
+// when they are set. The zero value accepts any password.
+type PasswordPolicy struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// BannedPasswords are refused regardless of case.
+	BannedPasswords []string
+	// MaxAge is how long a password stays valid before it must be changed.
+	// 0 means passwords do not expire.
+	MaxAge time.Duration
+	// HistorySize is how many of the most recent passwords, including the
+	// current one, may not be reused. 0 disables the check.
+	HistorySize int
+}
+
+// Validate returns an error wrapping ErrPasswordPolicy that lists every rule
+// the password breaks, or nil when it satisfies the policy.
+func (p PasswordPolicy) Validate(password string) error {
+	var problems []string
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		problems = append(problems, fmt.Sprintf("be at least %d characters long", p.MinLength))
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if p.RequireUppercase && !upper {
+		problems = append(problems, "contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		problems = append(problems, "contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, "contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, "contain a symbol")
+	}
+	for _, banned := range p.BannedPasswords {
+		if strings.EqualFold(password, banned) {
+			problems = append(problems, "not be a banned password")
+			break
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: the password must %s", ErrPasswordPolicy, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// Expired reports whether the user's password is older than MaxAge. A
+// password set before its change was recorded is dated from the user's
+// creation.
+func (p PasswordPolicy) Expired(user *storage.UserRecord, now time.Time) bool {
+	if p.MaxAge <= 0 {
+		return false
+	}
+	changedAt := user.PasswordChangedAt
+	if changedAt.IsZero() {
+		changedAt = user.CreatedAt
+	}
+	return now.Sub(changedAt) > p.MaxAge
+}
+
+// checkReuse returns an error wrapping ErrPasswordPolicy when the password
+// is the user's current password or one of the previous ones the policy
+// remembers.
+func (p PasswordPolicy) checkReuse(user *storage.UserRecord, password string) error {
+	if p.HistorySize <= 0 {
+		return nil
+	}
+	hashes := append([]string{user.PasswordHash}, user.PasswordHistory...)
+	if len(hashes) > p.HistorySize {
+		hashes = hashes[:p.HistorySize]
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return fmt.Errorf("%w: the password must not be one of the last %d passwords", ErrPasswordPolicy, p.HistorySize)
+		}
+	}
+	return nil
+}
+
+// setPassword replaces the user's password hash, moving the current hash
+// into the history the policy remembers, and records when it was changed.
+func (p PasswordPolicy) setPassword(user *storage.UserRecord, hash string, now time.Time) {
+	if p.HistorySize > 1 && user.PasswordHash != "" {
+		history := append([]string{user.PasswordHash}, user.PasswordHistory...)
+		if len(history) > p.HistorySize-1 {
+			history = history[:p.HistorySize-1]
+		}
+		user.PasswordHistory = history
+	} else {
+		user.PasswordHistory = nil
+	}
+	user.PasswordHash = hash
+	user.PasswordChangedAt = now
+}
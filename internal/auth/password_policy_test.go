@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:        10,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		BannedPasswords:  []string{"Password123!"},
+	}
+
+	tests := []struct {
+		password string
+		problem  string
+	}{
+		{"Corr3ct-Horse", ""},
+		{"x", "at least 10 characters"},
+		{"correct-horse-1", "uppercase"},
+		{"CORRECT-HORSE-1", "lowercase"},
+		{"Correct-Horse", "digit"},
+		{"CorrectHorse1", "symbol"},
+		{"password123!", "banned"},
+	}
+	for _, tt := range tests {
+		err := policy.Validate(tt.password)
+		if tt.problem == "" {
+			if err != nil {
+				t.Errorf("Validate(%q) = %v, want nil", tt.password, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrPasswordPolicy) || !strings.Contains(err.Error(), tt.problem) {
+			t.Errorf("Validate(%q) = %v, want a policy error mentioning %q", tt.password, err, tt.problem)
+		}
+	}
+
+	if err := (PasswordPolicy{}).Validate("x"); err != nil {
+		t.Errorf("expected the zero policy to accept any password, got %v", err)
+	}
+}
+
+func TestPasswordPolicy_Expired(t *testing.T) {
+	now := time.Now()
+	policy := PasswordPolicy{MaxAge: 90 * 24 * time.Hour}
+
+	if policy.Expired(&storage.UserRecord{PasswordChangedAt: now.Add(-24 * time.Hour)}, now) {
+		t.Error("expected a recent password not to be expired")
+	}
+	if !policy.Expired(&storage.UserRecord{PasswordChangedAt: now.Add(-91 * 24 * time.Hour)}, now) {
+		t.Error("expected an old password to be expired")
+	}
+	// Without a recorded change, the password is as old as the user.
+	if !policy.Expired(&storage.UserRecord{CreatedAt: now.Add(-100 * 24 * time.Hour)}, now) {
+		t.Error("expected the password of an old user to be expired")
+	}
+	if (PasswordPolicy{}).Expired(&storage.UserRecord{}, now) {
+		t.Error("expected passwords not to expire without a maximum age")
+	}
+}
+
+func TestService_PasswordPolicy(t *testing.T) {
+	store := newMockAuthStorage()
+	svc := NewServiceWithConfig(store, ServiceConfig{
+		PasswordPolicy: PasswordPolicy{MinLength: 8, HistorySize: 3},
+	})
+	defer svc.Close()
+	ctx := context.Background()
+
+	if _, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "a", Role: "readonly", Enabled: true}); !errors.Is(err, ErrPasswordPolicy) {
+		t.Fatalf("expected a one-character password to be refused, got %v", err)
+	}
+	user, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "password-1", Role: "readonly", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.PasswordChangedAt.IsZero() {
+		t.Error("expected the password change to be recorded")
+	}
+
+	if err := svc.ChangePassword(ctx, user.ID, "password-1", "short"); !errors.Is(err, ErrPasswordPolicy) {
+		t.Errorf("expected a short new password to be refused, got %v", err)
+	}
+	if err := svc.ChangePassword(ctx, user.ID, "password-1", "password-1"); !errors.Is(err, ErrPasswordPolicy) {
+		t.Errorf("expected the current password to be refused, got %v", err)
+	}
+	if err := svc.ChangePassword(ctx, user.ID, "password-1", "password-2"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if err := svc.ChangePassword(ctx, user.ID, "password-2", "password-3"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	// The last three passwords are remembered.
+	if _, err := svc.UpdateUser(ctx, user.ID, map[string]interface{}{"password": "password-1"}); !errors.Is(err, ErrPasswordPolicy) {
+		t.Errorf("expected a recent password to be refused, got %v", err)
+	}
+	if err := svc.ChangePassword(ctx, user.ID, "password-3", "password-4"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := svc.UpdateUser(ctx, user.ID, map[string]interface{}{"password": "password-1"}); err != nil {
+		t.Errorf("expected a password older than the history to be accepted, got %v", err)
+	}
+}
+
+func TestService_BootstrapPasswordChange(t *testing.T) {
+	store := newMockAuthStorage()
+	svc := NewServiceWithConfig(store, ServiceConfig{BootstrapPasswordChange: true})
+	defer svc.Close()
+	ctx := context.Background()
+
+	if _, err := svc.BootstrapAdmin(ctx, "admin", "initial-secret", ""); err != nil {
+		t.Fatalf("BootstrapAdmin: %v", err)
+	}
+	admin, err := svc.ValidateCredentials(ctx, "admin", "initial-secret")
+	if err != nil {
+		t.Fatalf("ValidateCredentials: %v", err)
+	}
+	if !svc.PasswordChangeRequired(admin) {
+		t.Fatal("expected the bootstrap admin to have to change the password")
+	}
+
+	if err := svc.ChangePassword(ctx, admin.ID, "initial-secret", "rotated-secret"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	admin, err = svc.ValidateCredentials(ctx, "admin", "rotated-secret")
+	if err != nil {
+		t.Fatalf("ValidateCredentials: %v", err)
+	}
+	if svc.PasswordChangeRequired(admin) {
+		t.Error("expected the requirement to be lifted by the change")
+	}
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+)
+
+// Policy decides authorization requests in place of the built-in roles and
+// grants.
+type Policy interface {
+	Decide(ctx context.Context, input PolicyInput) (bool, error)
+}
+
+// PolicyInput describes a request to be authorized. Context and Subject are
+// empty for requests that do not target a context or subject, and Method and
+// Path are empty for requests that did not come through the REST API.
+type PolicyInput struct {
+	Principal PolicyPrincipal `json:"principal"`
+	Action    string          `json:"action"` // The permission required, e.g. "schema:write"
+	Method    string          `json:"method,omitempty"`
+	Path      string          `json:"path,omitempty"`
+	Context   string          `json:"context,omitempty"`
+	Subject   string          `json:"subject,omitempty"`
+}
+
+// PolicyPrincipal identifies who is making a request.
+type PolicyPrincipal struct {
+	Username   string `json:"username"`
+	Role       string `json:"role,omitempty"`
+	Method     string `json:"method,omitempty"` // How the principal authenticated, e.g. "jwt" or "api_key"
+	SuperAdmin bool   `json:"superAdmin,omitempty"`
+}
+
+// policyInput builds the policy input for a user and resource.
+func (a *Authorizer) policyInput(user *User, perm Permission, scope ResourceScope) PolicyInput {
+	return PolicyInput{
+		Principal: PolicyPrincipal{
+			Username:   user.Username,
+			Role:       user.Role,
+			Method:     user.Method,
+			SuperAdmin: a.superAdmins[user.Username],
+		},
+		Action:  string(perm),
+		Context: scope.Context,
+		Subject: scope.Subject,
+	}
+}
+
+// SetPolicy delegates authorization decisions to an external policy. The
+// policy's decision replaces the user's role and grants; API key scopes
+// still confine the keys they are set on.
+func (a *Authorizer) SetPolicy(p Policy) {
+	a.policy = p
+}
+
+// HTTPPolicy asks a policy decision point over HTTP. Each decision is a POST
+// of {"input": PolicyInput}; the response must be a JSON object whose
+// "result" is either a boolean or an object with a boolean "allow" field, as
+// OPA returns for a rule or a package. A missing result denies the request.
+type HTTPPolicy struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPPolicy creates a policy client from config. Defaults: timeout=2s.
+func NewHTTPPolicy(cfg config.PolicyConfig) (*HTTPPolicy, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid policy URL %q", cfg.URL)
+	}
+	timeout, err := parseDurationDefault(cfg.Timeout, 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy timeout: %w", err)
+	}
+	return &HTTPPolicy{
+		url:     cfg.URL,
+		headers: cfg.Headers,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Decide asks the policy endpoint whether the request is allowed.
+func (p *HTTPPolicy) Decide(ctx context.Context, input PolicyInput) (bool, error) {
+	body, err := json.Marshal(struct {
+		Input PolicyInput `json:"input"`
+	}{input})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("policy request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, fmt.Errorf("policy request failed: endpoint returned %s", resp.Status)
+	}
+
+	var decision struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&decision); err != nil {
+		return false, fmt.Errorf("invalid policy response: %w", err)
+	}
+	if len(decision.Result) == 0 || string(decision.Result) == "null" {
+		return false, nil
+	}
+	var allow bool
+	if err := json.Unmarshal(decision.Result, &allow); err == nil {
+		return allow, nil
+	}
+	var result struct {
+		Allow bool `json:"allow"`
+	}
+	if err := json.Unmarshal(decision.Result, &result); err != nil {
+		return false, fmt.Errorf("invalid policy response: result must be a boolean or an object with an allow field")
+	}
+	return result.Allow, nil
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func TestNewHTTPPolicy_InvalidConfig(t *testing.T) {
+	if _, err := NewHTTPPolicy(config.PolicyConfig{URL: "localhost:8181"}); err == nil {
+		t.Error("expected error for a URL without a scheme")
+	}
+	if _, err := NewHTTPPolicy(config.PolicyConfig{URL: "http://localhost:8181", Timeout: "soon"}); err == nil {
+		t.Error("expected error for an invalid timeout")
+	}
+}
+
+func TestHTTPPolicy_Decide(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    bool
+		wantErr bool
+	}{
+		{"rule allows", http.StatusOK, `{"result": true}`, true, false},
+		{"rule denies", http.StatusOK, `{"result": false}`, false, false},
+		{"package allows", http.StatusOK, `{"result": {"allow": true}}`, true, false},
+		{"undefined result denies", http.StatusOK, `{}`, false, false},
+		{"unexpected result", http.StatusOK, `{"result": "yes"}`, false, true},
+		{"server error", http.StatusInternalServerError, `{}`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				Input PolicyInput `json:"input"`
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					t.Errorf("expected the configured header, got %q", r.Header.Get("Authorization"))
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decode input: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			policy, err := NewHTTPPolicy(config.PolicyConfig{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer token"}})
+			if err != nil {
+				t.Fatalf("NewHTTPPolicy: %v", err)
+			}
+			input := PolicyInput{Principal: PolicyPrincipal{Username: "alice"}, Action: "schema:write", Context: ".", Subject: "orders"}
+			allowed, err := policy.Decide(context.Background(), input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if allowed != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, allowed)
+			}
+			if got.Input != input {
+				t.Errorf("expected input %+v, got %+v", input, got.Input)
+			}
+		})
+	}
+}
+
+// funcPolicy adapts a function to the Policy interface.
+type funcPolicy func(PolicyInput) (bool, error)
+
+func (f funcPolicy) Decide(_ context.Context, input PolicyInput) (bool, error) {
+	return f(input)
+}
+
+func TestAuthorizeEndpoint_Policy(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	// Only subjects in the .team context may be written, by anyone, and
+	// nobody may delete.
+	authorizer.SetPolicy(funcPolicy(func(in PolicyInput) (bool, error) {
+		switch in.Action {
+		case string(PermissionSchemaWrite):
+			return in.Context == ".team", nil
+		case string(PermissionSchemaDelete):
+			return false, nil
+		}
+		return true, nil
+	}))
+	handler := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		method string
+		path   string
+		user   *User
+		want   int
+	}{
+		// The policy allows what the readonly role does not...
+		{"POST", "/contexts/.team/subjects/orders/versions", &User{Username: "bob", Role: "readonly"}, http.StatusOK},
+		// ...and denies what the admin role allows.
+		{"POST", "/subjects/orders/versions", &User{Username: "carol", Role: "admin"}, http.StatusForbidden},
+		{"DELETE", "/subjects/orders", &User{Username: "carol", Role: "admin"}, http.StatusForbidden},
+		{"GET", "/subjects", &User{Username: "bob", Role: "readonly"}, http.StatusOK},
+		// API key scopes still confine the key.
+		{"POST", "/contexts/.team/subjects/orders/versions", &User{APIKeyID: 3, Username: "ci", Role: "admin", Method: "api_key",
+			Scopes: []storage.APIKeyScope{{Context: ".ci"}}}, http.StatusForbidden},
+		{"GET", "/subjects", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.user != nil {
+			req = req.WithContext(setUser(req.Context(), tt.user))
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestAuthorizeEndpoint_PolicyUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	policy, err := NewHTTPPolicy(config.PolicyConfig{URL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewHTTPPolicy: %v", err)
+	}
+
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, SuperAdmins: []string{"admin"}})
+	authorizer.SetPolicy(policy)
+	handler := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/subjects", nil)
+	req = req.WithContext(setUser(req.Context(), &User{Username: "admin", Role: "super_admin"}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 when the policy fails, got %d", rr.Code)
+	}
+
+	if authorizer.HasScopedPermission(context.Background(), &User{Username: "admin", Role: "super_admin"}, PermissionSchemaRead, ResourceScope{Context: "."}) {
+		t.Error("expected a failing policy to deny scoped permission checks")
+	}
+}
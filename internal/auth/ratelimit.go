@@ -20,6 +20,7 @@ type RateLimiter struct {
 	endpoints map[string]*tokenBucket
 	metrics   *metrics.Metrics // Prometheus metrics (optional)
 	stopCh    chan struct{}    // signals cleanup goroutine to stop
+	cleaning  bool             // whether the cleanup goroutine was started
 }
 
 // tokenBucket implements the token bucket algorithm.
@@ -37,22 +38,39 @@ type tokenBucket struct {
 // Call Close() to stop the cleanup goroutine.
 func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
 	rl := &RateLimiter{
-		config:    cfg,
-		clients:   make(map[string]*tokenBucket),
-		endpoints: make(map[string]*tokenBucket),
-		stopCh:    make(chan struct{}),
-	}
-
-	if cfg.Enabled {
-		rl.global = newTokenBucket(float64(cfg.BurstSize), float64(cfg.RequestsPerSecond))
-		if cfg.PerClient {
-			go rl.cleanupLoop()
-		}
+		stopCh: make(chan struct{}),
 	}
 
+	rl.apply(cfg)
 	return rl
 }
 
+// Update replaces the limits of a running rate limiter, for a configuration
+// reload. Every bucket is reset, so each client starts again with a full
+// burst under the new limits.
+func (rl *RateLimiter) Update(cfg config.RateLimitConfig) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.apply(cfg)
+}
+
+// apply installs cfg and fresh buckets. The caller holds rl.mu, or has not
+// shared rl yet.
+func (rl *RateLimiter) apply(cfg config.RateLimitConfig) {
+	rl.config = cfg
+	rl.global = nil
+	rl.clients = make(map[string]*tokenBucket)
+	rl.endpoints = make(map[string]*tokenBucket)
+	if !cfg.Enabled {
+		return
+	}
+	rl.global = newTokenBucket(float64(cfg.BurstSize), float64(cfg.RequestsPerSecond))
+	if cfg.PerClient && !rl.cleaning {
+		rl.cleaning = true
+		go rl.cleanupLoop()
+	}
+}
+
 // Close stops the cleanup goroutine.
 func (rl *RateLimiter) Close() {
 	select {
@@ -125,7 +143,10 @@ func (rl *RateLimiter) SetMetrics(m *metrics.Metrics) {
 // Middleware returns HTTP middleware for rate limiting.
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !rl.config.Enabled {
+		rl.mu.Lock()
+		cfg, global := rl.config, rl.global
+		rl.mu.Unlock()
+		if !cfg.Enabled {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -134,18 +155,18 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 		var key string
 
 		// Determine which bucket to use
-		if rl.config.PerClient {
+		if cfg.PerClient {
 			key = GetClientIP(r)
 			bucket = rl.getClientBucket(key)
-		} else if rl.config.PerEndpoint {
+		} else if cfg.PerEndpoint {
 			key = r.Method + ":" + r.URL.Path
 			bucket = rl.getEndpointBucket(key)
 		} else {
-			bucket = rl.global
+			bucket = global
 		}
 
 		// Set rate limit headers
-		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.config.RequestsPerSecond))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(cfg.RequestsPerSecond))
 		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(bucket.remaining()))
 
 		if !bucket.allow() {
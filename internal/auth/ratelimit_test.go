@@ -143,6 +143,44 @@ func TestRateLimiter_Disabled(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_Update(t *testing.T) {
+	rl := NewRateLimiter(config.RateLimitConfig{Enabled: false})
+	defer rl.Close()
+
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() int {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+		return rr.Code
+	}
+
+	// Enabling limits applies to a middleware that is already installed.
+	rl.Update(config.RateLimitConfig{Enabled: true, RequestsPerSecond: 1, BurstSize: 1, PerClient: true})
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("expected the first request to pass, got %d", code)
+	}
+	if code := serve(); code != http.StatusTooManyRequests {
+		t.Fatalf("expected the second request to be limited, got %d", code)
+	}
+
+	// Raising the limits resets the buckets.
+	rl.Update(config.RateLimitConfig{Enabled: true, RequestsPerSecond: 10, BurstSize: 3, PerClient: true})
+	for i := 0; i < 3; i++ {
+		if code := serve(); code != http.StatusOK {
+			t.Errorf("request %d: expected status 200 under the new burst, got %d", i+1, code)
+		}
+	}
+
+	rl.Update(config.RateLimitConfig{Enabled: false})
+	for i := 0; i < 10; i++ {
+		if code := serve(); code != http.StatusOK {
+			t.Fatalf("expected requests to pass once disabled, got %d", code)
+		}
+	}
+}
+
 func TestRateLimiter_RateLimitHeaders(t *testing.T) {
 	rl := NewRateLimiter(config.RateLimitConfig{
 		Enabled:           true,
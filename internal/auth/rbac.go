@@ -2,6 +2,7 @@
 package auth
 
 import (
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -30,6 +31,8 @@ const (
 	PermissionSchemaRead   Permission = "schema:read"
 	PermissionSchemaWrite  Permission = "schema:write"
 	PermissionSchemaDelete Permission = "schema:delete"
+	// Reviewing registrations held for approval
+	PermissionSchemaApprove Permission = "schema:approve"
 
 	// Config permissions
 	PermissionConfigRead  Permission = "config:read"
@@ -58,7 +61,7 @@ const (
 // rolePermissions defines permissions for each role.
 var rolePermissions = map[Role][]Permission{
 	RoleSuperAdmin: {
-		PermissionSchemaRead, PermissionSchemaWrite, PermissionSchemaDelete,
+		PermissionSchemaRead, PermissionSchemaWrite, PermissionSchemaDelete, PermissionSchemaApprove,
 		PermissionConfigRead, PermissionConfigWrite,
 		PermissionModeRead, PermissionModeWrite,
 		PermissionImport,
@@ -67,7 +70,7 @@ var rolePermissions = map[Role][]Permission{
 		PermissionExporterRead, PermissionExporterWrite,
 	},
 	RoleAdmin: {
-		PermissionSchemaRead, PermissionSchemaWrite, PermissionSchemaDelete,
+		PermissionSchemaRead, PermissionSchemaWrite, PermissionSchemaDelete, PermissionSchemaApprove,
 		PermissionConfigRead, PermissionConfigWrite,
 		PermissionModeRead, PermissionModeWrite,
 		PermissionImport,
@@ -95,6 +98,8 @@ var rolePermissions = map[Role][]Permission{
 type Authorizer struct {
 	config      config.RBACConfig
 	superAdmins map[string]bool
+	grants      GrantProvider
+	policy      Policy
 }
 
 // NewAuthorizer creates a new authorizer.
@@ -166,9 +171,13 @@ func (a *Authorizer) RequirePermission(perm Permission) func(http.Handler) http.
 }
 
 // EndpointPermission maps HTTP methods and paths to required permissions.
+// A path matches if it starts with PathPrefix and, when PathSuffix is set,
+// ends with PathSuffix. The suffix is matched against the escaped path, so
+// that a subject name containing an encoded "/" cannot forge it.
 type EndpointPermission struct {
 	Method     string
 	PathPrefix string
+	PathSuffix string
 	Permission Permission
 }
 
@@ -178,6 +187,10 @@ func DefaultEndpointPermissions() []EndpointPermission {
 		// Schema read operations
 		{Method: "GET", PathPrefix: "/subjects", Permission: PermissionSchemaRead},
 		{Method: "GET", PathPrefix: "/schemas", Permission: PermissionSchemaRead},
+		{Method: "GET", PathPrefix: "/topics", Permission: PermissionSchemaRead},
+		{Method: "GET", PathPrefix: "/catalog", Permission: PermissionSchemaRead},
+		{Method: "GET", PathPrefix: "/search", Permission: PermissionSchemaRead},
+		{Method: "GET", PathPrefix: "/readers", Permission: PermissionSchemaRead},
 
 		// Analysis endpoints (read-only POST operations) — must precede
 		// the generic POST /subjects entry so that prefix matching picks
@@ -185,9 +198,14 @@ func DefaultEndpointPermissions() []EndpointPermission {
 		{Method: "POST", PathPrefix: "/schemas", Permission: PermissionSchemaRead},
 		{Method: "POST", PathPrefix: "/subjects/validate", Permission: PermissionSchemaRead},
 		{Method: "POST", PathPrefix: "/subjects/match", Permission: PermissionSchemaRead},
+		{Method: "POST", PathPrefix: "/subjects/", PathSuffix: "/validate-payload", Permission: PermissionSchemaRead},
 
 		// Schema write operations
 		{Method: "POST", PathPrefix: "/subjects", Permission: PermissionSchemaWrite},
+		{Method: "PUT", PathPrefix: "/subjects", Permission: PermissionSchemaWrite},
+		// Re-validating every stored version is an operator action; other
+		// compatibility checks are read-only.
+		{Method: "POST", PathPrefix: "/compatibility/revalidate", Permission: PermissionConfigWrite},
 		{Method: "POST", PathPrefix: "/compatibility", Permission: PermissionSchemaRead},
 
 		// Schema delete operations
@@ -203,7 +221,10 @@ func DefaultEndpointPermissions() []EndpointPermission {
 		{Method: "PUT", PathPrefix: "/mode", Permission: PermissionModeWrite},
 
 		// Import operations (migration)
+		{Method: "GET", PathPrefix: "/import", Permission: PermissionImport},
 		{Method: "POST", PathPrefix: "/import", Permission: PermissionImport},
+		{Method: "PUT", PathPrefix: "/import", Permission: PermissionImport},
+		{Method: "DELETE", PathPrefix: "/import", Permission: PermissionImport},
 
 		// DEK Registry (encryption key management)
 		{Method: "GET", PathPrefix: "/dek-registry", Permission: PermissionEncryptionRead},
@@ -220,6 +241,9 @@ func DefaultEndpointPermissions() []EndpointPermission {
 		// Mode delete operations
 		{Method: "DELETE", PathPrefix: "/mode", Permission: PermissionModeWrite},
 
+		// Pending schema reviews
+		{Method: "POST", PathPrefix: "/admin/pending-schemas", Permission: PermissionSchemaApprove},
+
 		// Admin operations (user management, API keys, roles)
 		{Method: "GET", PathPrefix: "/admin", Permission: PermissionAdminRead},
 		{Method: "POST", PathPrefix: "/admin", Permission: PermissionAdminWrite},
@@ -230,10 +254,37 @@ func DefaultEndpointPermissions() []EndpointPermission {
 		{Method: "GET", PathPrefix: "/me", Permission: PermissionSchemaRead},
 		{Method: "POST", PathPrefix: "/me", Permission: PermissionSchemaRead},
 
+		// Login sessions (any authenticated user; refresh and revoke are public)
+		{Method: "POST", PathPrefix: "/auth/login", Permission: PermissionSchemaRead},
+
+		// Context settings (per-context metadata and limits)
+		{Method: "GET", PathPrefix: "/settings", Permission: PermissionConfigRead},
+		{Method: "PUT", PathPrefix: "/settings", Permission: PermissionConfigWrite},
+
+		// Context management (create and delete contexts)
+		{Method: "POST", PathPrefix: "/contexts", Permission: PermissionConfigWrite},
+		{Method: "DELETE", PathPrefix: "/contexts", Permission: PermissionConfigWrite},
+
 		// Contexts and metadata (read-only, any authenticated user)
 		{Method: "GET", PathPrefix: "/contexts", Permission: PermissionSchemaRead},
 		{Method: "GET", PathPrefix: "/v1/metadata", Permission: PermissionSchemaRead},
 
+		// Async jobs (status is readable by any authenticated user;
+		// cancelling is an operator action)
+		{Method: "GET", PathPrefix: "/jobs", Permission: PermissionSchemaRead},
+		{Method: "POST", PathPrefix: "/jobs", Permission: PermissionConfigWrite},
+
+		// Storage consistency checks can repair ID sequences
+		{Method: "POST", PathPrefix: "/consistency", Permission: PermissionAdminWrite},
+
+		// Re-encrypting schemas rewrites every stored schema
+		{Method: "POST", PathPrefix: "/encryption", Permission: PermissionAdminWrite},
+
+		// Declarative apply creates contexts, sets configs and modes and
+		// registers schemas across contexts, an operator action like
+		// creating a context
+		{Method: "POST", PathPrefix: "/apply", Permission: PermissionConfigWrite},
+
 		// Statistics (read-only)
 		{Method: "GET", PathPrefix: "/statistics", Permission: PermissionSchemaRead},
 	}
@@ -241,17 +292,20 @@ func DefaultEndpointPermissions() []EndpointPermission {
 
 // normalizePathForRBAC strips the /contexts/{context} prefix from a URL path
 // so that context-scoped routes match the same RBAC permissions as root routes.
-// For example, /contexts/.TestContext/subjects/foo → /subjects/foo.
+// For example, /contexts/.TestContext/subjects/foo → /subjects/foo. A bare
+// /contexts/{context} path maps to /contexts so that context management
+// operations are governed by the /contexts permission entries.
 func normalizePathForRBAC(path string) string {
 	const prefix = "/contexts/"
 	if strings.HasPrefix(path, prefix) {
 		// Find the end of the context name (next slash after /contexts/)
 		rest := path[len(prefix):]
 		idx := strings.Index(rest, "/")
-		if idx >= 0 {
+		if idx >= 0 && rest[idx:] != "/" {
 			return rest[idx:] // Return everything after /contexts/{context}
 		}
-		return "/" // Context path with no sub-path
+		// The context resource itself (e.g. DELETE /contexts/.TestContext)
+		return "/contexts"
 	}
 	return path
 }
@@ -274,14 +328,31 @@ func (a *Authorizer) AuthorizeEndpoint(permissions []EndpointPermission) func(ht
 			// Find matching permission
 			matched := false
 			for _, ep := range permissions {
-				if r.Method == ep.Method && strings.HasPrefix(normalizedPath, ep.PathPrefix) {
+				if r.Method == ep.Method && strings.HasPrefix(normalizedPath, ep.PathPrefix) && strings.HasSuffix(r.URL.EscapedPath(), ep.PathSuffix) {
 					matched = true
 					if user == nil {
 						http.Error(w, "Unauthorized", http.StatusUnauthorized)
 						return
 					}
 
-					if !a.HasPermission(user, ep.Permission) {
+					if a.policy != nil {
+						allowed, err := a.decideRequest(r, user, ep.Permission)
+						if err != nil {
+							slog.Warn("authorization policy failed", slog.String("error", err.Error()))
+							http.Error(w, "Authorization policy unavailable", http.StatusServiceUnavailable)
+							return
+						}
+						if !allowed {
+							http.Error(w, "Forbidden", http.StatusForbidden)
+							return
+						}
+					} else if !a.HasPermission(user, ep.Permission) && !a.hasGrantedPermission(r, user, ep.Permission) {
+						http.Error(w, "Forbidden", http.StatusForbidden)
+						return
+					}
+					// A scoped API key is confined to its scopes even
+					// where its role would allow more.
+					if scope, ok := RequestScope(r.URL.Path); !apiKeyScopesPermit(user, ep.Permission, scope, ok) {
 						http.Error(w, "Forbidden", http.StatusForbidden)
 						return
 					}
@@ -302,6 +373,33 @@ func (a *Authorizer) AuthorizeEndpoint(permissions []EndpointPermission) func(ht
 	}
 }
 
+// decideRequest asks the external policy whether a request is allowed.
+func (a *Authorizer) decideRequest(r *http.Request, user *User, perm Permission) (bool, error) {
+	scope, _ := RequestScope(r.URL.Path)
+	input := a.policyInput(user, perm, scope)
+	input.Method = r.Method
+	input.Path = r.URL.Path
+	return a.policy.Decide(r.Context(), input)
+}
+
+// hasGrantedPermission checks whether a role grant scoped to the request's
+// context or subject confers the permission.
+func (a *Authorizer) hasGrantedPermission(r *http.Request, user *User, perm Permission) bool {
+	if a.grants == nil {
+		return false
+	}
+	scope, ok := RequestScope(r.URL.Path)
+	if !ok {
+		return false
+	}
+	return a.HasScopedPermission(r.Context(), user, perm, scope)
+}
+
+// Enabled reports whether RBAC is enforced.
+func (a *Authorizer) Enabled() bool {
+	return a.config.Enabled
+}
+
 // IsSuperAdmin checks if a user is a super admin.
 func (a *Authorizer) IsSuperAdmin(username string) bool {
 	return a.superAdmins[username]
@@ -312,6 +410,21 @@ func GetRolePermissions(role Role) []Permission {
 	return rolePermissions[role]
 }
 
+// RoleIncludes reports whether holder has every permission of role, so that
+// a principal with holder may hand out credentials with role.
+func RoleIncludes(holder, role Role) bool {
+	held := make(map[Permission]bool, len(rolePermissions[holder]))
+	for _, p := range rolePermissions[holder] {
+		held[p] = true
+	}
+	for _, p := range rolePermissions[role] {
+		if !held[p] {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidRole checks if a role is valid.
 func ValidRole(role string) bool {
 	switch Role(role) {
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
 func TestAuthorizer_HasPermission(t *testing.T) {
@@ -214,6 +215,26 @@ func TestGetRolePermissions(t *testing.T) {
 	}
 }
 
+func TestRoleIncludes(t *testing.T) {
+	tests := []struct {
+		holder, role Role
+		want         bool
+	}{
+		{RoleSuperAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleDeveloper, true},
+		{RoleDeveloper, RoleReadOnly, true},
+		{RoleDeveloper, RoleDeveloper, true},
+		{RoleDeveloper, RoleAdmin, false},
+		{RoleReadOnly, RoleDeveloper, false},
+		{RoleAdmin, RoleSuperAdmin, false},
+	}
+	for _, tt := range tests {
+		if got := RoleIncludes(tt.holder, tt.role); got != tt.want {
+			t.Errorf("RoleIncludes(%s, %s) = %v, want %v", tt.holder, tt.role, got, tt.want)
+		}
+	}
+}
+
 func TestValidRole(t *testing.T) {
 	if !ValidRole("super_admin") {
 		t.Error("super_admin should be valid")
@@ -285,11 +306,13 @@ func TestNormalizePathForRBAC(t *testing.T) {
 		{"/contexts/.TestContext/mode/my-topic", "/mode/my-topic"},
 		{"/contexts/.TestContext/compatibility/subjects/my-topic/versions/1", "/compatibility/subjects/my-topic/versions/1"},
 		{"/contexts/.TestContext/import/schemas", "/import/schemas"},
+		{"/contexts/.TestContext/dek-registry/v1/keks", "/dek-registry/v1/keks"},
 		{"/contexts/.production/subjects", "/subjects"},
 		{"/contexts/:.:/subjects", "/subjects"},
 
 		// Edge cases
-		{"/contexts/.TestContext", "/"},
+		{"/contexts/.TestContext", "/contexts"},
+		{"/contexts/.TestContext/settings", "/settings"},
 	}
 
 	for _, tt := range tests {
@@ -413,6 +436,34 @@ func TestDefaultEndpointPermissionsIncludesDEKRegistry(t *testing.T) {
 	}
 }
 
+func TestAuthorizeEndpoint_ValidatePayloadIsRead(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	handler := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	user := &User{Username: "reader", Role: "readonly"}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/subjects/orders-value/versions/latest/validate-payload", http.StatusOK},
+		{"/contexts/.ci/subjects/orders-value/versions/2/validate-payload", http.StatusOK},
+		// Registering to a subject whose name ends in the suffix is a write.
+		{"/subjects/orders%2Fvalidate-payload", http.StatusForbidden},
+		{"/subjects/orders-value/versions", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, nil)
+		req = req.WithContext(setUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("POST %s: expected %d, got %d", tt.path, tt.want, rr.Code)
+		}
+	}
+}
+
 func TestDefaultEndpointPermissionsIncludesExporters(t *testing.T) {
 	perms := DefaultEndpointPermissions()
 
@@ -446,6 +497,40 @@ func TestDefaultEndpointPermissionsIncludesExporters(t *testing.T) {
 	}
 }
 
+func TestDefaultEndpointPermissionsIncludesContextManagement(t *testing.T) {
+	perms := DefaultEndpointPermissions()
+
+	tests := []struct {
+		method     string
+		pathPrefix string
+		wantPerm   Permission
+	}{
+		{"GET", "/contexts", PermissionSchemaRead},
+		{"POST", "/contexts", PermissionConfigWrite},
+		{"DELETE", "/contexts", PermissionConfigWrite},
+		{"GET", "/settings", PermissionConfigRead},
+		{"PUT", "/settings", PermissionConfigWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.pathPrefix, func(t *testing.T) {
+			found := false
+			for _, ep := range perms {
+				if ep.Method == tt.method && ep.PathPrefix == tt.pathPrefix {
+					found = true
+					if ep.Permission != tt.wantPerm {
+						t.Errorf("%s %s: want permission %s, got %s", tt.method, tt.pathPrefix, tt.wantPerm, ep.Permission)
+					}
+					break
+				}
+			}
+			if !found {
+				t.Errorf("no endpoint permission found for %s %s", tt.method, tt.pathPrefix)
+			}
+		})
+	}
+}
+
 func TestEncryptionPermissionsInRoles(t *testing.T) {
 	hasPermission := func(perms []Permission, target Permission) bool {
 		for _, p := range perms {
@@ -555,6 +640,7 @@ func TestAnalysisEndpointsAccessibleToReadonlyUsers(t *testing.T) {
 	}{
 		{"POST", "/schemas/validate"},
 		{"POST", "/schemas/normalize"},
+		{"POST", "/schemas/convert"},
 		{"POST", "/schemas/search"},
 		{"POST", "/schemas/quality"},
 		{"POST", "/schemas/complexity"},
@@ -578,6 +664,147 @@ func TestAnalysisEndpointsAccessibleToReadonlyUsers(t *testing.T) {
 	}
 }
 
+func TestRevalidationRequiresConfigWrite(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	wrapped := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		role string
+		path string
+		want int
+	}{
+		{string(RoleReadOnly), "/compatibility/revalidate", http.StatusForbidden},
+		{string(RoleReadOnly), "/contexts/.staging/compatibility/revalidate", http.StatusForbidden},
+		{string(RoleReadOnly), "/compatibility/subjects/orders-value/versions", http.StatusOK},
+		{string(RoleAdmin), "/compatibility/revalidate", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, nil)
+		req = req.WithContext(setUser(req.Context(), &User{Username: "u", Role: tt.role}))
+		rr := httptest.NewRecorder()
+		wrapped.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s POST %s: expected %d, got %d", tt.role, tt.path, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestConsistencyVerifyRequiresAdminWrite(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	wrapped := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		role string
+		path string
+		want int
+	}{
+		{string(RoleAdmin), "/consistency/verify", http.StatusForbidden},
+		{string(RoleAdmin), "/contexts/.staging/consistency/verify", http.StatusForbidden},
+		{string(RoleSuperAdmin), "/consistency/verify", http.StatusOK},
+		{string(RoleSuperAdmin), "/contexts/.staging/consistency/verify", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, nil)
+		req = req.WithContext(setUser(req.Context(), &User{Username: "u", Role: tt.role}))
+		rr := httptest.NewRecorder()
+		wrapped.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s POST %s: expected %d, got %d", tt.role, tt.path, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestReencryptRequiresAdminWrite(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	wrapped := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		role string
+		path string
+		want int
+	}{
+		{string(RoleAdmin), "/encryption/reencrypt", http.StatusForbidden},
+		{string(RoleAdmin), "/contexts/.staging/encryption/reencrypt", http.StatusForbidden},
+		{string(RoleSuperAdmin), "/encryption/reencrypt", http.StatusOK},
+		{string(RoleSuperAdmin), "/contexts/.staging/encryption/reencrypt", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, nil)
+		req = req.WithContext(setUser(req.Context(), &User{Username: "u", Role: tt.role}))
+		rr := httptest.NewRecorder()
+		wrapped.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s POST %s: expected %d, got %d", tt.role, tt.path, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestApplyRequiresConfigWrite(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	wrapped := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name string
+		user *User
+		want int
+	}{
+		{"developer", &User{Username: "u", Role: string(RoleDeveloper)}, http.StatusForbidden},
+		{"admin", &User{Username: "u", Role: string(RoleAdmin)}, http.StatusOK},
+		// The desired state spans contexts, so a key scoped to one may not apply it.
+		{"scoped admin key", &User{Username: "ci", Role: string(RoleAdmin), Method: "api_key", Scopes: []storage.APIKeyScope{
+			{Context: ".ci", Operations: []string{"config:write", "mode:write", "schema:write"}},
+		}}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/apply", nil)
+		req = req.WithContext(setUser(req.Context(), tt.user))
+		rr := httptest.NewRecorder()
+		wrapped.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s POST /apply: expected %d, got %d", tt.name, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestPendingSchemaReviewRequiresApprovePermission(t *testing.T) {
+	authorizer := NewAuthorizer(config.RBACConfig{Enabled: true, DefaultRole: "readonly"})
+	wrapped := authorizer.AuthorizeEndpoint(DefaultEndpointPermissions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		role   string
+		method string
+		path   string
+		want   int
+	}{
+		{string(RoleDeveloper), "POST", "/admin/pending-schemas/abc/approve", http.StatusForbidden},
+		{string(RoleDeveloper), "GET", "/admin/pending-schemas", http.StatusForbidden},
+		{string(RoleAdmin), "POST", "/admin/pending-schemas/abc/approve", http.StatusOK},
+		{string(RoleAdmin), "POST", "/admin/pending-schemas/abc/reject", http.StatusOK},
+		{string(RoleAdmin), "GET", "/admin/pending-schemas", http.StatusOK},
+		// The rule must not open other admin writes to admins.
+		{string(RoleAdmin), "POST", "/admin/users", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req = req.WithContext(setUser(req.Context(), &User{Username: "u", Role: tt.role}))
+		rr := httptest.NewRecorder()
+		wrapped.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s %s %s: expected %d, got %d", tt.role, tt.method, tt.path, tt.want, rr.Code)
+		}
+	}
+}
+
 func TestAuthorizeEndpointDenyByDefault(t *testing.T) {
 	cfg := config.RBACConfig{
 		Enabled:     true,
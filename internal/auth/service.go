@@ -7,6 +7,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -35,6 +36,12 @@ type Service struct {
 	// Map: keyHash (string) -> *storage.APIKeyRecord
 	apiKeyCache sync.Map
 
+	// apiKeyCacheMu serializes writes to apiKeyCache. apiKeyCacheGen is
+	// bumped by every invalidation so that a record read from the database
+	// before a key was revoked is never cached after it.
+	apiKeyCacheMu  sync.Mutex
+	apiKeyCacheGen uint64
+
 	// userCredCache caches validated user credentials in memory for performance.
 	// Entries include a TTL to ensure password changes are eventually reflected.
 	// Map: cacheKey (string) -> *userCacheEntry
@@ -46,14 +53,52 @@ type Service struct {
 	// cacheRefreshInterval is how often the background process refreshes cached keys.
 	cacheRefreshInterval time.Duration
 
+	// consistencyCheckInterval is how often the caches are reconciled with storage.
+	consistencyCheckInterval time.Duration
+
+	// lastCacheReport is the result of the most recent reconciliation.
+	lastCacheReport   *CacheReport
+	lastCacheReportMu sync.RWMutex
+
 	// stopCacheRefresh signals the background refresh goroutine to stop.
 	stopCacheRefresh chan struct{}
 
 	// cacheRefreshDone signals that the background refresh goroutine has stopped.
 	cacheRefreshDone chan struct{}
 
+	// grantCache holds the role grants consulted by the authorizer. It is
+	// loaded on startup, refreshed with the API key cache, and reloaded
+	// whenever a grant is created or deleted on this node.
+	grantCache   []*storage.GrantRecord
+	grantCacheMu sync.RWMutex
+
 	// metrics is the optional Prometheus metrics instance for recording cache metrics.
 	metrics *metrics.Metrics
+
+	// passwordPolicy is enforced whenever a user's password is set.
+	passwordPolicy PasswordPolicy
+
+	// bootstrapPasswordChange makes the bootstrap admin change the password
+	// on first login.
+	bootstrapPasswordChange bool
+
+	// sessions signs the access tokens of login sessions; nil when they are
+	// disabled.
+	sessions *SessionSigner
+
+	// lastSessionPrune is when expired sessions were last deleted.
+	lastSessionPrune time.Time
+	sessionPruneMu   sync.Mutex
+
+	// apiKeyUsage buffers the requests authenticated by API keys until
+	// they are flushed to storage every usageFlushInterval.
+	apiKeyUsage        map[apiKeyUsageBucket]pendingAPIKeyUsage
+	apiKeyUsageMu      sync.Mutex
+	usageFlushInterval time.Duration
+
+	// usageFlushDone signals that the background usage flush goroutine has
+	// stopped.
+	usageFlushDone chan struct{}
 }
 
 // ServiceConfig contains configuration for the auth service.
@@ -76,6 +121,23 @@ type ServiceConfig struct {
 	// This reduces database load for frequently authenticating users.
 	// Set to 0 to disable user credential caching. Default is 60 seconds.
 	UserCacheTTL time.Duration
+	// ConsistencyCheckInterval is how often the cached API keys, user
+	// credentials and grants are compared with the database, reporting and
+	// correcting any divergence. Set to 0 to disable the check.
+	ConsistencyCheckInterval time.Duration
+	// PasswordPolicy is enforced when users are created and their passwords
+	// are changed or reset. The zero value accepts any password.
+	PasswordPolicy PasswordPolicy
+	// BootstrapPasswordChange requires the admin created by BootstrapAdmin
+	// to change the password on first login.
+	BootstrapPasswordChange bool
+	// Sessions signs the access tokens of login sessions started with
+	// StartSession. Nil disables sessions.
+	Sessions *SessionSigner
+	// APIKeyUsageFlushInterval is how often the requests counted per API
+	// key, and their last-used times, are written to storage. 0 uses
+	// DefaultAPIKeyUsageFlushInterval.
+	APIKeyUsageFlushInterval time.Duration
 }
 
 // DefaultCacheRefreshInterval is the default interval for refreshing the API key cache.
@@ -84,11 +146,16 @@ const DefaultCacheRefreshInterval = 1 * time.Minute
 // DefaultUserCacheTTL is the default TTL for cached user credentials.
 const DefaultUserCacheTTL = 60 * time.Second
 
+// DefaultConsistencyCheckInterval is the default interval for reconciling the
+// auth caches with the database.
+const DefaultConsistencyCheckInterval = 24 * time.Hour
+
 // NewService creates a new auth service with default configuration.
 func NewService(store storage.AuthStorage) *Service {
 	return NewServiceWithConfig(store, ServiceConfig{
-		CacheRefreshInterval: DefaultCacheRefreshInterval,
-		UserCacheTTL:         DefaultUserCacheTTL,
+		CacheRefreshInterval:     DefaultCacheRefreshInterval,
+		UserCacheTTL:             DefaultUserCacheTTL,
+		ConsistencyCheckInterval: DefaultConsistencyCheckInterval,
 	})
 }
 
@@ -97,12 +164,22 @@ func NewService(store storage.AuthStorage) *Service {
 // Use DefaultCacheRefreshInterval and DefaultUserCacheTTL for default behavior.
 func NewServiceWithConfig(store storage.AuthStorage, cfg ServiceConfig) *Service {
 	s := &Service{
-		storage:              store,
-		keyPrefix:            cfg.APIKeyPrefix,
-		userCacheTTL:         cfg.UserCacheTTL,         // 0 means disabled
-		cacheRefreshInterval: cfg.CacheRefreshInterval, // 0 means disabled
-		stopCacheRefresh:     make(chan struct{}),
-		cacheRefreshDone:     make(chan struct{}),
+		storage:                  store,
+		keyPrefix:                cfg.APIKeyPrefix,
+		userCacheTTL:             cfg.UserCacheTTL,             // 0 means disabled
+		cacheRefreshInterval:     cfg.CacheRefreshInterval,     // 0 means disabled
+		consistencyCheckInterval: cfg.ConsistencyCheckInterval, // 0 means disabled
+		passwordPolicy:           cfg.PasswordPolicy,
+		bootstrapPasswordChange:  cfg.BootstrapPasswordChange,
+		sessions:                 cfg.Sessions,
+		stopCacheRefresh:         make(chan struct{}),
+		cacheRefreshDone:         make(chan struct{}),
+		apiKeyUsage:              make(map[apiKeyUsageBucket]pendingAPIKeyUsage),
+		usageFlushInterval:       cfg.APIKeyUsageFlushInterval,
+		usageFlushDone:           make(chan struct{}),
+	}
+	if s.usageFlushInterval <= 0 {
+		s.usageFlushInterval = DefaultAPIKeyUsageFlushInterval
 	}
 
 	// Decode hex secret if provided
@@ -119,10 +196,12 @@ func NewServiceWithConfig(store storage.AuthStorage, cfg ServiceConfig) *Service
 	// Load all API keys into cache on startup (only if caching is enabled)
 	if s.cacheRefreshInterval > 0 {
 		s.refreshAPIKeyCache()
+		s.refreshGrantCache()
 	}
 
-	// Start background refresh goroutine
+	// Start background refresh and usage flush goroutines
 	go s.runCacheRefresh()
+	go s.runUsageFlush()
 
 	return s
 }
@@ -132,14 +211,16 @@ func (s *Service) SetMetrics(m *metrics.Metrics) {
 	s.metrics = m
 }
 
-// Close stops the background cache refresh goroutine.
-// Should be called when shutting down the server.
+// Close stops the background cache refresh goroutine and flushes buffered
+// API key usage. Should be called when shutting down the server.
 func (s *Service) Close() {
 	close(s.stopCacheRefresh)
 	<-s.cacheRefreshDone
+	<-s.usageFlushDone
 }
 
-// runCacheRefresh periodically refreshes the API key cache from the database.
+// runCacheRefresh periodically refreshes the API key cache from the database
+// and reconciles the caches with it at the consistency check interval.
 func (s *Service) runCacheRefresh() {
 	defer close(s.cacheRefreshDone)
 
@@ -152,19 +233,31 @@ func (s *Service) runCacheRefresh() {
 	ticker := time.NewTicker(s.cacheRefreshInterval)
 	defer ticker.Stop()
 
+	var check <-chan time.Time
+	if s.consistencyCheckInterval > 0 {
+		checkTicker := time.NewTicker(s.consistencyCheckInterval)
+		defer checkTicker.Stop()
+		check = checkTicker.C
+	}
+
 	for {
 		select {
 		case <-s.stopCacheRefresh:
 			return
 		case <-ticker.C:
 			s.refreshAPIKeyCache()
+			s.refreshGrantCache()
+		case <-check:
+			ctx, cancel := s.backgroundContext()
+			_, _ = s.ReconcileCache(ctx, CacheCheckScheduled)
+			cancel()
 		}
 	}
 }
 
-// refreshAPIKeyCache loads all API keys from the database into the cache.
-// The stop channel context ensures cache refresh is canceled on shutdown.
-func (s *Service) refreshAPIKeyCache() {
+// backgroundContext returns a context for background cache work. It times
+// out after 30 seconds and is canceled when the service is closed.
+func (s *Service) backgroundContext() (context.Context, context.CancelFunc) {
 	bgCtx, bgCancel := context.WithCancel(context.Background())
 	go func() {
 		select {
@@ -174,37 +267,24 @@ func (s *Service) refreshAPIKeyCache() {
 		}
 	}()
 	ctx, cancel := context.WithTimeout(bgCtx, 30*time.Second)
-	defer cancel()
-	defer bgCancel()
-
-	keys, err := s.storage.ListAPIKeys(ctx)
-	if err != nil {
-		// Log error but don't crash - keep using existing cache
-		return
+	return ctx, func() {
+		cancel()
+		bgCancel()
 	}
+}
 
-	// Build new cache map
-	newKeys := make(map[string]*storage.APIKeyRecord, len(keys))
-	for _, key := range keys {
-		newKeys[key.KeyHash] = key
-	}
+// refreshAPIKeyCache loads all API keys from the database into the cache.
+// The stop channel context ensures cache refresh is canceled on shutdown.
+func (s *Service) refreshAPIKeyCache() {
+	ctx, cancel := s.backgroundContext()
+	defer cancel()
 
-	// Clear old entries not in database and update existing ones
-	s.apiKeyCache.Range(func(k, v interface{}) bool {
-		hash := k.(string)
-		if _, exists := newKeys[hash]; !exists {
-			s.apiKeyCache.Delete(hash)
+	if _, err := s.syncAPIKeyCache(ctx); err != nil {
+		// Keep using the existing cache; the failure is counted so that
+		// drift from storage can be alerted on.
+		if s.metrics != nil {
+			s.metrics.RecordAuthCacheRefreshFailure("api_key")
 		}
-		return true
-	})
-
-	// Add/update all keys from database
-	for hash, key := range newKeys {
-		s.apiKeyCache.Store(hash, key)
-	}
-
-	if s.metrics != nil {
-		s.metrics.UpdateCacheSize("api_key", float64(len(newKeys)))
 	}
 }
 
@@ -215,6 +295,9 @@ type CreateUserRequest struct {
 	Password string
 	Role     string
 	Enabled  bool
+	Tenant   string // Tenant the user belongs to; empty for instance-wide users
+	// MustChangePassword requires the user to change the password on first login.
+	MustChangePassword bool
 }
 
 // CreateAPIKeyRequest contains the data needed to create an API key.
@@ -223,6 +306,10 @@ type CreateAPIKeyRequest struct {
 	Name      string    // Required: must be unique per user
 	Role      string    // Required: role for this API key
 	ExpiresAt time.Time // Required: when the key expires
+
+	// Scopes restricts the key to the listed resources; empty for a key
+	// that can do whatever its role allows.
+	Scopes []storage.APIKeyScope
 }
 
 // CreateAPIKeyResponse contains the created API key details including the raw key.
@@ -236,6 +323,8 @@ type CreateAPIKeyResponse struct {
 	Enabled   bool      `json:"enabled"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
+
+	Scopes []storage.APIKeyScope `json:"scopes,omitempty"`
 }
 
 // CreateUser creates a new user with the given details.
@@ -245,6 +334,10 @@ func (s *Service) CreateUser(ctx context.Context, req CreateUserRequest) (*stora
 		return nil, fmt.Errorf("%w: %s", storage.ErrInvalidRole, req.Role)
 	}
 
+	if err := s.passwordPolicy.Validate(req.Password); err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -253,13 +346,16 @@ func (s *Service) CreateUser(ctx context.Context, req CreateUserRequest) (*stora
 
 	now := time.Now().UTC()
 	user := &storage.UserRecord{
-		Username:     req.Username,
-		Email:        req.Email,
-		PasswordHash: string(hash),
-		Role:         req.Role,
-		Enabled:      req.Enabled,
-		CreatedAt:    now,
-		UpdatedAt:    now,
+		Username:           req.Username,
+		Email:              req.Email,
+		PasswordHash:       string(hash),
+		Role:               req.Role,
+		Enabled:            req.Enabled,
+		Tenant:             req.Tenant,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		PasswordChangedAt:  now,
+		MustChangePassword: req.MustChangePassword,
 	}
 
 	if err := s.storage.CreateUser(ctx, user); err != nil {
@@ -286,7 +382,9 @@ func (s *Service) UpdateUser(ctx context.Context, id int64, updates map[string]i
 		return nil, err
 	}
 
-	// Apply updates
+	// Apply updates. Changing the password or role, or disabling the user,
+	// ends their sessions.
+	revokeSessions := false
 	for key, value := range updates {
 		switch key {
 		case "email":
@@ -295,21 +393,34 @@ func (s *Service) UpdateUser(ctx context.Context, id int64, updates map[string]i
 			}
 		case "password":
 			if password, ok := value.(string); ok {
+				if err := s.passwordPolicy.Validate(password); err != nil {
+					return nil, err
+				}
+				if err := s.passwordPolicy.checkReuse(user, password); err != nil {
+					return nil, err
+				}
 				hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 				if err != nil {
 					return nil, fmt.Errorf("failed to hash password: %w", err)
 				}
-				user.PasswordHash = string(hash)
+				s.passwordPolicy.setPassword(user, string(hash), time.Now().UTC())
+				revokeSessions = true
+			}
+		case "must_change_password":
+			if mustChange, ok := value.(bool); ok {
+				user.MustChangePassword = mustChange
 			}
 		case "role":
 			if role, ok := value.(string); ok {
 				if !ValidRole(role) {
 					return nil, fmt.Errorf("%w: %s", storage.ErrInvalidRole, role)
 				}
+				revokeSessions = revokeSessions || role != user.Role
 				user.Role = role
 			}
 		case "enabled":
 			if enabled, ok := value.(bool); ok {
+				revokeSessions = revokeSessions || !enabled
 				user.Enabled = enabled
 			}
 		}
@@ -324,15 +435,39 @@ func (s *Service) UpdateUser(ctx context.Context, id int64, updates map[string]i
 	// Invalidate credential cache for this user
 	s.invalidateUserCredCacheByID(id)
 
+	if revokeSessions {
+		if err := s.revokeUserSessions(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
 	return user, nil
 }
 
-// DeleteUser deletes a user by ID.
+// DeleteUser deletes a user by ID, along with the grants bound to them and
+// their sessions.
 func (s *Service) DeleteUser(ctx context.Context, id int64) error {
+	user, err := s.storage.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// Invalidate credential cache before delete
 	s.invalidateUserCredCacheByID(id)
 
-	return s.storage.DeleteUser(ctx, id)
+	if err := s.storage.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	// The user's API keys and sessions go with them
+	s.invalidateAPIKeys(InvalidationOwnerDeleted, func(record *storage.APIKeyRecord) bool {
+		return record.UserID == id
+	})
+	if err := s.revokeUserSessions(ctx, id); err != nil {
+		return err
+	}
+	return s.deleteGrantsWhere(ctx, func(g *storage.GrantRecord) bool {
+		return g.Username == user.Username
+	})
 }
 
 // ListUsers returns all users.
@@ -340,7 +475,8 @@ func (s *Service) ListUsers(ctx context.Context) ([]*storage.UserRecord, error)
 	return s.storage.ListUsers(ctx)
 }
 
-// ChangePassword changes a user's password.
+// ChangePassword changes a user's password, which must satisfy the password
+// policy, lifts any requirement to change it and ends the user's sessions.
 func (s *Service) ChangePassword(ctx context.Context, id int64, oldPassword, newPassword string) error {
 	user, err := s.storage.GetUserByID(ctx, id)
 	if err != nil {
@@ -352,14 +488,23 @@ func (s *Service) ChangePassword(ctx context.Context, id int64, oldPassword, new
 		return storage.ErrPermissionDenied
 	}
 
+	if err := s.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+	if err := s.passwordPolicy.checkReuse(user, newPassword); err != nil {
+		return err
+	}
+
 	// Hash new password
 	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	user.PasswordHash = string(hash)
-	user.UpdatedAt = time.Now().UTC()
+	now := time.Now().UTC()
+	s.passwordPolicy.setPassword(user, string(hash), now)
+	user.MustChangePassword = false
+	user.UpdatedAt = now
 
 	if err := s.storage.UpdateUser(ctx, user); err != nil {
 		return err
@@ -368,7 +513,14 @@ func (s *Service) ChangePassword(ctx context.Context, id int64, oldPassword, new
 	// Invalidate credential cache for this user
 	s.invalidateUserCredCacheByID(id)
 
-	return nil
+	return s.revokeUserSessions(ctx, id)
+}
+
+// PasswordChangeRequired reports whether the user must change their password
+// before doing anything else: an admin or the bootstrap required it, or the
+// password has expired.
+func (s *Service) PasswordChangeRequired(user *storage.UserRecord) bool {
+	return user.MustChangePassword || s.passwordPolicy.Expired(user, time.Now())
 }
 
 // userCredCacheKey generates a cache key for user credentials.
@@ -482,6 +634,11 @@ func (s *Service) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*C
 		return nil, fmt.Errorf("expiry time must be in the future")
 	}
 
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if API key name already exists for this user
 	existing, err := s.storage.GetAPIKeyByUserAndName(ctx, req.UserID, req.Name)
 	if err == nil && existing != nil {
@@ -518,6 +675,7 @@ func (s *Service) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*C
 		Enabled:   true,
 		CreatedAt: now,
 		ExpiresAt: req.ExpiresAt,
+		Scopes:    scopes,
 	}
 
 	if err := s.storage.CreateAPIKey(ctx, record); err != nil {
@@ -526,7 +684,9 @@ func (s *Service) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*C
 
 	// Add to cache immediately so the key can be used right away (only if caching is enabled)
 	if s.cacheRefreshInterval > 0 {
+		s.apiKeyCacheMu.Lock()
 		s.apiKeyCache.Store(keyHashStr, record)
+		s.apiKeyCacheMu.Unlock()
 	}
 
 	return &CreateAPIKeyResponse{
@@ -539,6 +699,7 @@ func (s *Service) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*C
 		Enabled:   true,
 		CreatedAt: now,
 		ExpiresAt: req.ExpiresAt,
+		Scopes:    scopes,
 	}, nil
 }
 
@@ -566,14 +727,23 @@ func (s *Service) ValidateAPIKey(ctx context.Context, rawKey string) (*storage.A
 		if s.metrics != nil && s.cacheRefreshInterval > 0 {
 			s.metrics.RecordCacheAccess("api_key", false)
 		}
+		s.apiKeyCacheMu.Lock()
+		gen := s.apiKeyCacheGen
+		s.apiKeyCacheMu.Unlock()
+
 		var err error
 		record, err = s.storage.GetAPIKeyByHash(ctx, keyHashStr)
 		if err != nil {
 			return nil, storage.ErrAPIKeyNotFound
 		}
-		// Cache the result for future lookups (only if caching is enabled)
+		// Cache the result for future lookups (only if caching is enabled),
+		// unless a key was invalidated while it was being read
 		if s.cacheRefreshInterval > 0 {
-			s.apiKeyCache.Store(keyHashStr, record)
+			s.apiKeyCacheMu.Lock()
+			if s.apiKeyCacheGen == gen {
+				s.apiKeyCache.Store(keyHashStr, record)
+			}
+			s.apiKeyCacheMu.Unlock()
 		}
 	}
 
@@ -587,29 +757,27 @@ func (s *Service) ValidateAPIKey(ctx context.Context, rawKey string) (*storage.A
 		return nil, storage.ErrAPIKeyExpired
 	}
 
-	// Update last used time (non-blocking). Use WithoutCancel so the update
-	// completes even after the HTTP request context is canceled.
-	go func(ctx context.Context) {
-		_ = s.storage.UpdateAPIKeyLastUsed(ctx, record.ID)
-	}(context.WithoutCancel(ctx))
+	// Count the request and its time; both reach storage with the next
+	// usage flush rather than with a write per request.
+	s.recordAPIKeyUse(record.ID, time.Now().UTC())
 
 	return record, nil
 }
 
-// GetAPIKeyByID retrieves an API key by ID.
+// GetAPIKeyByID retrieves an API key by ID. Its last-used time includes
+// requests not yet flushed to storage.
 func (s *Service) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
-	return s.storage.GetAPIKeyByID(ctx, id)
+	key, err := s.storage.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPendingLastUsed(key)[0], nil
 }
 
 // invalidateAPIKeyCache removes an API key from the cache by its ID.
-// This iterates through the cache since we index by hash, not ID.
-func (s *Service) invalidateAPIKeyCache(id int64) {
-	s.apiKeyCache.Range(func(key, value interface{}) bool {
-		if record, ok := value.(*storage.APIKeyRecord); ok && record.ID == id {
-			s.apiKeyCache.Delete(key)
-			return false // Stop iteration, found the key
-		}
-		return true // Continue iteration
+func (s *Service) invalidateAPIKeyCache(id int64, reason string) {
+	s.invalidateAPIKeys(reason, func(record *storage.APIKeyRecord) bool {
+		return record.ID == id
 	})
 }
 
@@ -660,27 +828,44 @@ func (s *Service) UpdateAPIKey(ctx context.Context, id int64, updates map[string
 	}
 
 	// Invalidate cache so next validation fetches fresh data
-	s.invalidateAPIKeyCache(id)
+	reason := InvalidationUpdated
+	if !record.Enabled {
+		reason = InvalidationRevoked
+	}
+	s.invalidateAPIKeyCache(id, reason)
 
 	return record, nil
 }
 
-// DeleteAPIKey deletes an API key by ID.
+// DeleteAPIKey deletes an API key by ID, along with the grants bound to it.
 func (s *Service) DeleteAPIKey(ctx context.Context, id int64) error {
-	// Invalidate cache before delete
-	s.invalidateAPIKeyCache(id)
-
-	return s.storage.DeleteAPIKey(ctx, id)
+	if err := s.storage.DeleteAPIKey(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateAPIKeyCache(id, InvalidationDeleted)
+	return s.deleteGrantsWhere(ctx, func(g *storage.GrantRecord) bool {
+		return g.APIKeyID == id
+	})
 }
 
-// ListAPIKeys returns all API keys.
+// ListAPIKeys returns all API keys. Their last-used times include requests
+// not yet flushed to storage.
 func (s *Service) ListAPIKeys(ctx context.Context) ([]*storage.APIKeyRecord, error) {
-	return s.storage.ListAPIKeys(ctx)
+	keys, err := s.storage.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPendingLastUsed(keys...), nil
 }
 
-// ListAPIKeysByUserID returns all API keys for a specific user.
+// ListAPIKeysByUserID returns all API keys for a specific user. Their
+// last-used times include requests not yet flushed to storage.
 func (s *Service) ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*storage.APIKeyRecord, error) {
-	return s.storage.ListAPIKeysByUserID(ctx, userID)
+	keys, err := s.storage.ListAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPendingLastUsed(keys...), nil
 }
 
 // RevokeAPIKey disables an API key.
@@ -696,14 +881,16 @@ func (s *Service) RevokeAPIKey(ctx context.Context, id int64) error {
 	}
 
 	// Invalidate cache so the revoked key is rejected immediately
-	s.invalidateAPIKeyCache(id)
+	s.invalidateAPIKeyCache(id, InvalidationRevoked)
 
 	return nil
 }
 
 // RotateAPIKey creates a new API key with same settings and revokes the old one.
 // The new key will have a fresh expiry based on the remaining duration of the old key.
-func (s *Service) RotateAPIKey(ctx context.Context, id int64, newExpiresAt time.Time) (*CreateAPIKeyResponse, error) {
+// It keeps the old key's scopes unless scopes is non-nil, in which case the
+// new key is restricted to scopes instead; an empty slice removes them.
+func (s *Service) RotateAPIKey(ctx context.Context, id int64, newExpiresAt time.Time, scopes []storage.APIKeyScope) (*CreateAPIKeyResponse, error) {
 	oldKey, err := s.storage.GetAPIKeyByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -718,17 +905,27 @@ func (s *Service) RotateAPIKey(ctx context.Context, id int64, newExpiresAt time.
 		return nil, fmt.Errorf("expiry time must be in the future")
 	}
 
+	if scopes == nil {
+		scopes = oldKey.Scopes
+	}
+
 	// Create new key with same settings but new expiry
 	newKey, err := s.CreateAPIKey(ctx, CreateAPIKeyRequest{
 		UserID:    oldKey.UserID,
 		Name:      newName,
 		Role:      oldKey.Role,
 		ExpiresAt: newExpiresAt,
+		Scopes:    scopes,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Carry the old key's grants over to the new key
+	if err := s.copyAPIKeyGrants(ctx, id, newKey.ID); err != nil {
+		return nil, err
+	}
+
 	// Revoke old key
 	if err := s.RevokeAPIKey(ctx, id); err != nil {
 		// Log but don't fail - new key was created
@@ -738,6 +935,162 @@ func (s *Service) RotateAPIKey(ctx context.Context, id int64, newExpiresAt time.
 	return newKey, nil
 }
 
+// CreateGrantRequest contains the data needed to create a role grant.
+type CreateGrantRequest struct {
+	Username      string // Username to bind; mutually exclusive with APIKeyID
+	APIKeyID      int64  // Database API key to bind; mutually exclusive with Username
+	Role          string // Required: role conferred within the scope
+	Context       string // Context name, or empty for every context
+	SubjectPrefix string // Subject prefix, or empty for the whole context
+	CreatedBy     string
+}
+
+// CreateGrant creates a role grant. Grants cannot confer super_admin, which
+// is only assigned through configuration.
+func (s *Service) CreateGrant(ctx context.Context, req CreateGrantRequest) (*storage.GrantRecord, error) {
+	if !ValidRole(req.Role) || Role(req.Role) == RoleSuperAdmin {
+		return nil, fmt.Errorf("%w: %s", storage.ErrInvalidRole, req.Role)
+	}
+
+	grant := &storage.GrantRecord{
+		Username:      req.Username,
+		APIKeyID:      req.APIKeyID,
+		Role:          req.Role,
+		Context:       req.Context,
+		SubjectPrefix: req.SubjectPrefix,
+		CreatedBy:     req.CreatedBy,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := s.storage.CreateGrant(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	s.refreshGrantCache()
+	return grant, nil
+}
+
+// GetGrant retrieves a role grant by ID.
+func (s *Service) GetGrant(ctx context.Context, id int64) (*storage.GrantRecord, error) {
+	return s.storage.GetGrantByID(ctx, id)
+}
+
+// DeleteGrant deletes a role grant by ID.
+func (s *Service) DeleteGrant(ctx context.Context, id int64) error {
+	if err := s.storage.DeleteGrant(ctx, id); err != nil {
+		return err
+	}
+
+	s.refreshGrantCache()
+	return nil
+}
+
+// ListGrants returns all role grants.
+func (s *Service) ListGrants(ctx context.Context) ([]*storage.GrantRecord, error) {
+	return s.storage.ListGrants(ctx)
+}
+
+// ActiveGrants returns the grants the authorizer should enforce. It serves
+// the cached set when caching is enabled and reads storage otherwise. A
+// storage error yields no grants, so requests fall back to base roles.
+func (s *Service) ActiveGrants(ctx context.Context) []*storage.GrantRecord {
+	if s.cacheRefreshInterval == 0 {
+		grants, err := s.storage.ListGrants(ctx)
+		if err != nil {
+			return nil
+		}
+		return grants
+	}
+
+	s.grantCacheMu.RLock()
+	defer s.grantCacheMu.RUnlock()
+	return s.grantCache
+}
+
+// GrantsFor returns the active grants bound to a user, whatever the
+// resources they cover.
+func (s *Service) GrantsFor(ctx context.Context, user *User) []*storage.GrantRecord {
+	var held []*storage.GrantRecord
+	for _, g := range s.ActiveGrants(ctx) {
+		if grantHeldBy(g, user) {
+			held = append(held, g)
+		}
+	}
+	return held
+}
+
+// refreshGrantCache reloads the grant cache from the database. It is a no-op
+// when caching is disabled.
+func (s *Service) refreshGrantCache() {
+	if s.cacheRefreshInterval == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	grants, err := s.storage.ListGrants(ctx)
+	if err != nil {
+		// Keep using the existing cache
+		return
+	}
+
+	s.grantCacheMu.Lock()
+	s.grantCache = grants
+	s.grantCacheMu.Unlock()
+}
+
+// deleteGrantsWhere deletes every grant matching the predicate.
+func (s *Service) deleteGrantsWhere(ctx context.Context, match func(*storage.GrantRecord) bool) error {
+	grants, err := s.storage.ListGrants(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted := false
+	for _, g := range grants {
+		if !match(g) {
+			continue
+		}
+		if err := s.storage.DeleteGrant(ctx, g.ID); err != nil && !errors.Is(err, storage.ErrGrantNotFound) {
+			return err
+		}
+		deleted = true
+	}
+
+	if deleted {
+		s.refreshGrantCache()
+	}
+	return nil
+}
+
+// copyAPIKeyGrants binds a copy of every grant held by one API key to another.
+func (s *Service) copyAPIKeyGrants(ctx context.Context, fromID, toID int64) error {
+	grants, err := s.storage.ListGrants(ctx)
+	if err != nil {
+		return err
+	}
+
+	copied := false
+	for _, g := range grants {
+		if g.APIKeyID != fromID {
+			continue
+		}
+		dup := *g
+		dup.ID = 0
+		dup.APIKeyID = toID
+		dup.CreatedAt = time.Now().UTC()
+		if err := s.storage.CreateGrant(ctx, &dup); err != nil {
+			return err
+		}
+		copied = true
+	}
+
+	if copied {
+		s.refreshGrantCache()
+	}
+	return nil
+}
+
 // hashAPIKey returns a secure hash of an API key for storage.
 // If an API secret (pepper) is configured, it uses HMAC-SHA256 for defense-in-depth.
 // Otherwise, it falls back to plain SHA-256 for backward compatibility.
@@ -809,6 +1162,8 @@ func (s *Service) BootstrapAdmin(ctx context.Context, username, password, email
 		Password: password,
 		Role:     "super_admin",
 		Enabled:  true,
+
+		MustChangePassword: s.bootstrapPasswordChange,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bootstrap admin: %w", err)
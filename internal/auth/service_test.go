@@ -140,6 +140,74 @@ func (m *mockAuthStorage) UpdateAPIKeyLastUsed(ctx context.Context, id int64) er
 	return nil
 }
 
+func (m *mockAuthStorage) AddAPIKeyUsage(ctx context.Context, records []*storage.APIKeyUsageRecord) error {
+	return nil
+}
+
+func (m *mockAuthStorage) ListAPIKeyUsage(ctx context.Context, since time.Time) ([]*storage.APIKeyUsageRecord, error) {
+	return nil, nil
+}
+
+func (m *mockAuthStorage) CreateGrant(ctx context.Context, grant *storage.GrantRecord) error {
+	return nil
+}
+
+func (m *mockAuthStorage) GetGrantByID(ctx context.Context, id int64) (*storage.GrantRecord, error) {
+	return nil, storage.ErrGrantNotFound
+}
+
+func (m *mockAuthStorage) DeleteGrant(ctx context.Context, id int64) error {
+	return storage.ErrGrantNotFound
+}
+
+func (m *mockAuthStorage) ListGrants(ctx context.Context) ([]*storage.GrantRecord, error) {
+	return nil, nil
+}
+
+func (m *mockAuthStorage) CreateShareToken(ctx context.Context, token *storage.ShareTokenRecord) error {
+	return nil
+}
+
+func (m *mockAuthStorage) GetShareTokenByID(ctx context.Context, id int64) (*storage.ShareTokenRecord, error) {
+	return nil, storage.ErrShareTokenNotFound
+}
+
+func (m *mockAuthStorage) GetShareTokenByHash(ctx context.Context, tokenHash string) (*storage.ShareTokenRecord, error) {
+	return nil, storage.ErrShareTokenNotFound
+}
+
+func (m *mockAuthStorage) DeleteShareToken(ctx context.Context, id int64) error {
+	return storage.ErrShareTokenNotFound
+}
+
+func (m *mockAuthStorage) ListShareTokens(ctx context.Context) ([]*storage.ShareTokenRecord, error) {
+	return nil, nil
+}
+
+func (m *mockAuthStorage) CreateSession(ctx context.Context, session *storage.SessionRecord) error {
+	return nil
+}
+
+func (m *mockAuthStorage) GetSession(ctx context.Context, id string) (*storage.SessionRecord, error) {
+	return nil, storage.ErrSessionNotFound
+}
+
+func (m *mockAuthStorage) RefreshSession(ctx context.Context, session *storage.SessionRecord, oldRefreshHash string) error {
+	return storage.ErrSessionNotFound
+}
+
+func (m *mockAuthStorage) DeleteSession(ctx context.Context, id string) error {
+	return storage.ErrSessionNotFound
+}
+
+func (m *mockAuthStorage) DeleteUserSessions(ctx context.Context, userID int64) error {
+	return nil
+}
+
+func (m *mockAuthStorage) DeleteExpiredSessions(ctx context.Context, before time.Time) error {
+	return nil
+}
+
 func TestService_CacheDisabled_UserCredentials(t *testing.T) {
 	store := newMockAuthStorage()
 
@@ -0,0 +1,476 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SessionRefreshTokenPrefix starts every session refresh token.
+const SessionRefreshTokenPrefix = "srsess_"
+
+// AuthMethodSession is the User.Method of principals authenticated with the
+// access token of a login session.
+const AuthMethodSession = "session"
+
+// Session defaults, used when the configuration leaves them unset.
+const (
+	DefaultSessionIssuer     = "axonops-schema-registry"
+	DefaultSessionAccessTTL  = 15 * time.Minute
+	DefaultSessionRefreshTTL = 24 * time.Hour
+)
+
+// sessionPruneInterval is how often logins delete expired sessions.
+const sessionPruneInterval = time.Hour
+
+var (
+	// ErrInvalidSession is returned when a refresh or access token does not
+	// belong to a live session.
+	ErrInvalidSession = errors.New("invalid or expired session")
+	// ErrSessionLogin is returned when the principal did not authenticate
+	// with a method that can start a session.
+	ErrSessionLogin = errors.New("sessions can only be started with a password or an API key")
+	// ErrSessionsDisabled is returned when login sessions are not enabled.
+	ErrSessionsDisabled = errors.New("login sessions are not enabled")
+
+	// errNotSessionToken is returned for bearer tokens that were not signed
+	// by the session signer, which the other methods may accept.
+	errNotSessionToken = errors.New("not a session token")
+)
+
+// sessionLoginMethods are the authentication methods that can start a
+// session: the ones that present a password or an API key.
+var sessionLoginMethods = map[string]bool{
+	"basic":         true,
+	"ldap":          true,
+	"ldap_fallback": true,
+	"api_key":       true,
+}
+
+// SessionSigner signs and verifies the JWT access tokens of login sessions.
+type SessionSigner struct {
+	method     jwt.SigningMethod
+	signKey    any
+	verifyKey  any
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewSessionSigner creates a session signer from the session configuration.
+func NewSessionSigner(cfg config.SessionConfig) (*SessionSigner, error) {
+	s := &SessionSigner{
+		issuer:     cfg.Issuer,
+		accessTTL:  DefaultSessionAccessTTL,
+		refreshTTL: DefaultSessionRefreshTTL,
+	}
+	if s.issuer == "" {
+		s.issuer = DefaultSessionIssuer
+	}
+	if cfg.AccessTTL != "" {
+		d, err := config.ParseDuration(cfg.AccessTTL)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid access TTL: %q", cfg.AccessTTL)
+		}
+		s.accessTTL = d
+	}
+	if cfg.RefreshTTL != "" {
+		d, err := config.ParseDuration(cfg.RefreshTTL)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid refresh TTL: %q", cfg.RefreshTTL)
+		}
+		s.refreshTTL = d
+	}
+
+	switch strings.ToUpper(cfg.Algorithm) {
+	case "", "HS256":
+		if len(cfg.Secret) < 32 {
+			return nil, errors.New("HS256 secret must be at least 32 bytes")
+		}
+		s.method = jwt.SigningMethodHS256
+		s.signKey = []byte(cfg.Secret)
+		s.verifyKey = []byte(cfg.Secret)
+	case "RS256":
+		key, err := loadRSAPrivateKey(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.method = jwt.SigningMethodRS256
+		s.signKey = key
+		s.verifyKey = &key.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", cfg.Algorithm)
+	}
+	return s, nil
+}
+
+// loadRSAPrivateKey loads a PKCS#1 or PKCS#8 RSA private key from a PEM file.
+func loadRSAPrivateKey(keyFile string) (*rsa.PrivateKey, error) {
+	keyData, err := os.ReadFile(keyFile) // #nosec G304 -- keyFile is from trusted server configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+	block, _ := pem.Decode(keyData)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not an RSA private key")
+	}
+	return key, nil
+}
+
+// AccessTTL returns how long the access tokens are valid.
+func (s *SessionSigner) AccessTTL() time.Duration {
+	return s.accessTTL
+}
+
+// sessionClaims are the claims of a session access token. The JWT ID is the
+// session ID; the role and tenant are informational, as the session record
+// is authoritative.
+type sessionClaims struct {
+	Role   string `json:"role"`
+	Tenant string `json:"tenant,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// sign returns a signed access token for the session and its expiry time.
+func (s *SessionSigner) sign(session *storage.SessionRecord, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.accessTTL)
+	if expiresAt.After(session.ExpiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+	claims := sessionClaims{
+		Role:   session.Role,
+		Tenant: session.Tenant,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   session.Username,
+			ID:        session.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(s.method, claims).SignedString(s.signKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// verify returns the claims of an access token. It returns
+// errNotSessionToken if the token is not a JWT signed by this signer, and
+// ErrInvalidSession if it is but has expired or its claims are invalid.
+func (s *SessionSigner) verify(tokenString string) (*sessionClaims, error) {
+	claims := &sessionClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) {
+		return s.verifyKey, nil
+	}, jwt.WithValidMethods([]string{s.method.Alg()}), jwt.WithIssuer(s.issuer), jwt.WithExpirationRequired())
+	if errors.Is(err, jwt.ErrTokenInvalidClaims) {
+		return nil, ErrInvalidSession
+	}
+	if err != nil {
+		return nil, errNotSessionToken
+	}
+	return claims, nil
+}
+
+// SessionTokens are the tokens issued when a session starts or is refreshed.
+type SessionTokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// SessionsEnabled reports whether login sessions are enabled.
+func (s *Service) SessionsEnabled() bool {
+	return s.sessions != nil
+}
+
+// StartSession starts a login session for a user who authenticated with a
+// password or an API key, and returns it with its first tokens.
+func (s *Service) StartSession(ctx context.Context, user *User) (*storage.SessionRecord, *SessionTokens, error) {
+	if s.sessions == nil {
+		return nil, nil, ErrSessionsDisabled
+	}
+	if user == nil || !sessionLoginMethods[user.Method] {
+		return nil, nil, ErrSessionLogin
+	}
+	// Session principals do not carry scopes, so a scoped key's sessions
+	// would escape them.
+	if len(user.Scopes) > 0 {
+		return nil, nil, fmt.Errorf("%w: API keys restricted to scopes cannot start sessions", ErrSessionLogin)
+	}
+	s.pruneSessions(ctx)
+
+	now := time.Now().UTC()
+	session := &storage.SessionRecord{
+		Username:    user.Username,
+		Role:        user.Role,
+		Tenant:      user.Tenant,
+		AuthMethod:  user.Method,
+		CreatedAt:   now,
+		RefreshedAt: now,
+		ExpiresAt:   now.Add(s.sessions.refreshTTL),
+	}
+	// A database API key's sessions are revoked with the key's owner.
+	session.UserID = user.UserID
+	session.APIKeyID = user.APIKeyID
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	session.ID = hex.EncodeToString(idBytes)
+
+	refreshToken, err := s.newRefreshToken(session)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.storage.CreateSession(ctx, session); err != nil {
+		return nil, nil, err
+	}
+	tokens, err := s.sessionTokens(session, refreshToken, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, tokens, nil
+}
+
+// RefreshSession exchanges a refresh token for new access and refresh
+// tokens, extending the session. The refresh token can only be used once.
+// The session takes the current role and tenant of its database user or
+// API key, and ends if they have been disabled or must change password.
+func (s *Service) RefreshSession(ctx context.Context, refreshToken string) (*storage.SessionRecord, *SessionTokens, error) {
+	session, err := s.lookupSession(ctx, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	oldHash := session.RefreshHash
+
+	if err := s.refreshSessionPrincipal(ctx, session); err != nil {
+		_ = s.storage.DeleteSession(ctx, session.ID)
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	session.RefreshedAt = now
+	session.ExpiresAt = now.Add(s.sessions.refreshTTL)
+	newRefreshToken, err := s.newRefreshToken(session)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.storage.RefreshSession(ctx, session, oldHash); err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return nil, nil, ErrInvalidSession
+		}
+		return nil, nil, err
+	}
+	tokens, err := s.sessionTokens(session, newRefreshToken, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, tokens, nil
+}
+
+// RevokeSession ends the session a refresh token belongs to, invalidating
+// its access tokens.
+func (s *Service) RevokeSession(ctx context.Context, refreshToken string) (*storage.SessionRecord, error) {
+	session, err := s.lookupSession(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.DeleteSession(ctx, session.ID); err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// ValidateSessionToken returns the live session an access token belongs
+// to. It returns errNotSessionToken for bearer tokens the session signer
+// did not sign.
+func (s *Service) ValidateSessionToken(ctx context.Context, accessToken string) (*storage.SessionRecord, error) {
+	if s.sessions == nil {
+		return nil, errNotSessionToken
+	}
+	claims, err := s.sessions.verify(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.storage.GetSession(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+	if !session.ExpiresAt.After(time.Now()) || session.Username != claims.Subject {
+		return nil, ErrInvalidSession
+	}
+	return session, nil
+}
+
+// lookupSession returns the live session of a refresh token.
+func (s *Service) lookupSession(ctx context.Context, refreshToken string) (*storage.SessionRecord, error) {
+	if s.sessions == nil {
+		return nil, ErrSessionsDisabled
+	}
+	rest, ok := strings.CutPrefix(refreshToken, SessionRefreshTokenPrefix)
+	if !ok {
+		return nil, ErrInvalidSession
+	}
+	id, _, ok := strings.Cut(rest, ".")
+	if !ok {
+		return nil, ErrInvalidSession
+	}
+	session, err := s.storage.GetSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+	if !hmac.Equal([]byte(session.RefreshHash), []byte(s.hashAPIKey(refreshToken))) {
+		return nil, ErrInvalidSession
+	}
+	if !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidSession
+	}
+	return session, nil
+}
+
+// refreshSessionPrincipal updates the role and tenant of a session from its
+// database user or API key, returning ErrInvalidSession if they can no
+// longer be used. Sessions of config, htpasswd and LDAP users keep the role
+// they logged in with.
+func (s *Service) refreshSessionPrincipal(ctx context.Context, session *storage.SessionRecord) error {
+	if session.APIKeyID > 0 {
+		key, err := s.storage.GetAPIKeyByID(ctx, session.APIKeyID)
+		if err != nil || !key.Enabled || !key.ExpiresAt.After(time.Now()) {
+			return ErrInvalidSession
+		}
+		session.Role = key.Role
+	}
+	if session.UserID > 0 {
+		user, err := s.storage.GetUserByID(ctx, session.UserID)
+		if err != nil || !user.Enabled {
+			return ErrInvalidSession
+		}
+		if session.APIKeyID == 0 {
+			if s.PasswordChangeRequired(user) {
+				return ErrInvalidSession
+			}
+			session.Role = user.Role
+		}
+		session.Tenant = user.Tenant
+	}
+	return nil
+}
+
+// newRefreshToken generates a refresh token for the session and stores its
+// hash in the session record.
+func (s *Service) newRefreshToken(session *storage.SessionRecord) (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	refreshToken := SessionRefreshTokenPrefix + session.ID + "." + hex.EncodeToString(secretBytes)
+	session.RefreshHash = s.hashAPIKey(refreshToken)
+	return refreshToken, nil
+}
+
+// sessionTokens signs an access token for the session.
+func (s *Service) sessionTokens(session *storage.SessionRecord, refreshToken string, now time.Time) (*SessionTokens, error) {
+	accessToken, accessExpiresAt, err := s.sessions.sign(session, now)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionTokens{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// revokeUserSessions ends every session of a database user, so a password
+// change, role change, disabling or deletion takes effect immediately.
+func (s *Service) revokeUserSessions(ctx context.Context, userID int64) error {
+	if s.sessions == nil {
+		return nil
+	}
+	return s.storage.DeleteUserSessions(ctx, userID)
+}
+
+// pruneSessions deletes expired sessions, at most once per
+// sessionPruneInterval. Failures are ignored; the next login retries.
+func (s *Service) pruneSessions(ctx context.Context) {
+	s.sessionPruneMu.Lock()
+	if time.Since(s.lastSessionPrune) < sessionPruneInterval {
+		s.sessionPruneMu.Unlock()
+		return
+	}
+	s.lastSessionPrune = time.Now()
+	s.sessionPruneMu.Unlock()
+
+	_ = s.storage.DeleteExpiredSessions(ctx, time.Now())
+}
+
+// authenticateSession checks for a session access token in the
+// Authorization header. handled is true when the bearer token was signed by
+// the session signer, so an expired or revoked one is rejected rather than
+// passed to the JWT and OIDC methods.
+func (a *Authenticator) authenticateSession(r *http.Request) (user *User, handled bool) {
+	header := r.Header.Get("Authorization")
+	if a.service == nil || !a.service.SessionsEnabled() || !strings.HasPrefix(header, "Bearer ") {
+		return nil, false
+	}
+	session, err := a.service.ValidateSessionToken(r.Context(), header[len("Bearer "):])
+	if errors.Is(err, errNotSessionToken) {
+		return nil, false
+	}
+	if err != nil {
+		return nil, true
+	}
+	return sessionUser(session), true
+}
+
+// sessionUser returns the principal of a session. Like the principal that
+// logged in, an API key session carries the key's ID as well as its owner's.
+func sessionUser(session *storage.SessionRecord) *User {
+	return &User{
+		UserID:   session.UserID,
+		APIKeyID: session.APIKeyID,
+		Username: session.Username,
+		Role:     session.Role,
+		Method:   AuthMethodSession,
+		Tenant:   session.Tenant,
+	}
+}
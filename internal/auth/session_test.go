@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+const testSessionSecret = "0123456789abcdef0123456789abcdef"
+
+func newSessionTestService(t *testing.T) *Service {
+	t.Helper()
+	signer, err := NewSessionSigner(config.SessionConfig{Enabled: true, Secret: testSessionSecret})
+	if err != nil {
+		t.Fatalf("NewSessionSigner: %v", err)
+	}
+	svc := NewServiceWithConfig(memory.NewStore(), ServiceConfig{Sessions: signer})
+	t.Cleanup(func() { svc.Close() })
+	return svc
+}
+
+func TestNewSessionSigner_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.SessionConfig
+	}{
+		{"short secret", config.SessionConfig{Secret: "too-short"}},
+		{"unknown algorithm", config.SessionConfig{Algorithm: "ES256", Secret: testSessionSecret}},
+		{"missing key file", config.SessionConfig{Algorithm: "RS256", PrivateKeyFile: "/nonexistent/key.pem"}},
+		{"bad access TTL", config.SessionConfig{Secret: testSessionSecret, AccessTTL: "soon"}},
+	}
+	for _, tt := range tests {
+		if _, err := NewSessionSigner(tt.cfg); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestService_SessionLifecycle(t *testing.T) {
+	svc := newSessionTestService(t)
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "pass123", Role: "developer", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	principal := &User{UserID: user.ID, Username: "alice", Role: "developer", Method: "basic"}
+
+	session, tokens, err := svc.StartSession(ctx, principal)
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	if session.UserID != user.ID || tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatalf("unexpected session %+v, tokens %+v", session, tokens)
+	}
+
+	got, err := svc.ValidateSessionToken(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateSessionToken: %v", err)
+	}
+	if got.ID != session.ID || got.Role != "developer" {
+		t.Errorf("unexpected session: %+v", got)
+	}
+
+	// Refreshing rotates the refresh token within the session.
+	refreshed, newTokens, err := svc.RefreshSession(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshSession: %v", err)
+	}
+	if refreshed.ID != session.ID || newTokens.RefreshToken == tokens.RefreshToken {
+		t.Errorf("expected the refresh token to rotate within the session")
+	}
+	if _, _, err := svc.RefreshSession(ctx, tokens.RefreshToken); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("expected the old refresh token to be rejected, got %v", err)
+	}
+
+	// Revoking ends the session and its access tokens.
+	if _, err := svc.RevokeSession(ctx, newTokens.RefreshToken); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if _, err := svc.ValidateSessionToken(ctx, newTokens.AccessToken); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("expected the access token of a revoked session to be rejected, got %v", err)
+	}
+	if _, err := svc.RevokeSession(ctx, newTokens.RefreshToken); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("expected a second revoke to fail, got %v", err)
+	}
+}
+
+func TestService_StartSession_RejectsTokenLogins(t *testing.T) {
+	svc := newSessionTestService(t)
+	for _, method := range []string{"jwt", "oidc", AuthMethodShareToken, AuthMethodSession} {
+		_, _, err := svc.StartSession(context.Background(), &User{Username: "bob", Role: "readonly", Method: method})
+		if !errors.Is(err, ErrSessionLogin) {
+			t.Errorf("%s: expected ErrSessionLogin, got %v", method, err)
+		}
+	}
+}
+
+func TestService_PasswordChangeRevokesSessions(t *testing.T) {
+	svc := newSessionTestService(t)
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "pass123", Role: "developer", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	_, tokens, err := svc.StartSession(ctx, &User{UserID: user.ID, Username: "alice", Role: "developer", Method: "basic"})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	if err := svc.ChangePassword(ctx, user.ID, "pass123", "newpass456"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := svc.ValidateSessionToken(ctx, tokens.AccessToken); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("expected the session to be revoked by the password change, got %v", err)
+	}
+	if _, _, err := svc.RefreshSession(ctx, tokens.RefreshToken); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("expected the refresh token to be revoked by the password change, got %v", err)
+	}
+}
+
+func TestAuthenticator_SessionToken(t *testing.T) {
+	svc := newSessionTestService(t)
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "pass123", Role: "developer", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	_, tokens, err := svc.StartSession(ctx, &User{UserID: user.ID, Username: "alice", Role: "developer", Method: "basic"})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+
+	// Session tokens work even when only basic auth is configured.
+	a := NewAuthenticator(config.AuthConfig{Enabled: true, Methods: []string{"basic"}})
+	a.SetService(svc)
+
+	var got *User
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = GetUser(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/subjects", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if got == nil || got.Method != AuthMethodSession || got.Username != "alice" || got.Role != "developer" {
+		t.Errorf("unexpected session user: %+v", got)
+	}
+
+	if _, err := svc.RevokeSession(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a revoked session, got %d", rr.Code)
+	}
+}
+
+func TestAuthenticator_APIKeySession(t *testing.T) {
+	svc := newSessionTestService(t)
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "pass123", Role: "developer", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	key, err := svc.CreateAPIKey(ctx, CreateAPIKeyRequest{UserID: user.ID, Name: "ci", Role: "readonly", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	_, tokens, err := svc.StartSession(ctx, &User{UserID: user.ID, APIKeyID: key.ID, Username: "alice", Role: "readonly", Method: "api_key"})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+
+	a := NewAuthenticator(config.AuthConfig{Enabled: true, Methods: []string{"api_key"}})
+	a.SetService(svc)
+	var got *User
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = GetUser(r.Context())
+	}))
+	req := httptest.NewRequest("GET", "/subjects", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// The session keeps the key and its owner apart.
+	if got == nil || got.Method != AuthMethodSession || got.UserID != user.ID || got.APIKeyID != key.ID {
+		t.Errorf("expected a session of key %d owned by user %d, got %+v", key.ID, user.ID, got)
+	}
+}
@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// ShareTokenPrefix starts every share token, so the authenticator can tell
+// share tokens from JWT and OIDC bearer tokens.
+const ShareTokenPrefix = "srshare_"
+
+// MaxShareTokenLifetime is the longest a share token may be valid for.
+const MaxShareTokenLifetime = 365 * 24 * time.Hour
+
+// AuthMethodShareToken is the User.Method of principals authenticated with a
+// share token.
+const AuthMethodShareToken = "share_token"
+
+// ErrInvalidShareToken is returned when a share token request is invalid.
+var ErrInvalidShareToken = errors.New("invalid share token")
+
+// ShareScope is what a share token grants read access to: one registry
+// context, or one subject within it when Subject is set.
+type ShareScope struct {
+	Context string
+	Subject string
+}
+
+// CreateShareTokenRequest contains the data needed to create a share token.
+type CreateShareTokenRequest struct {
+	Name      string    // Required: who or what the token is for
+	Context   string    // Required: registry context the token can read
+	Subject   string    // Subject within the context, or empty for the whole context
+	ExpiresAt time.Time // Required: must be in the future and within MaxShareTokenLifetime
+	CreatedBy string
+}
+
+// CreateShareToken creates a share token and returns it with the raw token
+// value, which is only available at creation time.
+func (s *Service) CreateShareToken(ctx context.Context, req CreateShareTokenRequest) (*storage.ShareTokenRecord, string, error) {
+	if req.Name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidShareToken)
+	}
+	registryCtx := registrycontext.NormalizeContextName(req.Context)
+	if req.Context == "" || !registrycontext.IsValidContextName(registryCtx) || registrycontext.IsGlobalContext(registryCtx) {
+		return nil, "", fmt.Errorf("%w: invalid context %q", ErrInvalidShareToken, req.Context)
+	}
+	if strings.HasPrefix(req.Subject, ":.") {
+		return nil, "", fmt.Errorf("%w: subject must not be context-qualified", ErrInvalidShareToken)
+	}
+	now := time.Now().UTC()
+	if !req.ExpiresAt.After(now) {
+		return nil, "", fmt.Errorf("%w: expiry time must be in the future", ErrInvalidShareToken)
+	}
+	if req.ExpiresAt.Sub(now) > MaxShareTokenLifetime {
+		return nil, "", fmt.Errorf("%w: expiry time must be within %d days", ErrInvalidShareToken, int(MaxShareTokenLifetime.Hours()/24))
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	tokenHex := hex.EncodeToString(tokenBytes)
+	rawToken := ShareTokenPrefix + tokenHex
+
+	record := &storage.ShareTokenRecord{
+		TokenHash:   s.hashAPIKey(rawToken),
+		TokenPrefix: tokenHex[:8],
+		Name:        req.Name,
+		Context:     registryCtx,
+		Subject:     req.Subject,
+		CreatedBy:   req.CreatedBy,
+		CreatedAt:   now,
+		ExpiresAt:   req.ExpiresAt.UTC(),
+	}
+	if err := s.storage.CreateShareToken(ctx, record); err != nil {
+		return nil, "", err
+	}
+	return record, rawToken, nil
+}
+
+// GetShareToken retrieves a share token by ID.
+func (s *Service) GetShareToken(ctx context.Context, id int64) (*storage.ShareTokenRecord, error) {
+	return s.storage.GetShareTokenByID(ctx, id)
+}
+
+// ListShareTokens returns all share tokens, including expired ones.
+func (s *Service) ListShareTokens(ctx context.Context) ([]*storage.ShareTokenRecord, error) {
+	return s.storage.ListShareTokens(ctx)
+}
+
+// DeleteShareToken revokes a share token by deleting it.
+func (s *Service) DeleteShareToken(ctx context.Context, id int64) error {
+	return s.storage.DeleteShareToken(ctx, id)
+}
+
+// ValidateShareToken returns the share token with the given raw value if it
+// exists and has not expired.
+func (s *Service) ValidateShareToken(ctx context.Context, rawToken string) (*storage.ShareTokenRecord, error) {
+	record, err := s.storage.GetShareTokenByHash(ctx, s.hashAPIKey(rawToken))
+	if err != nil {
+		return nil, storage.ErrShareTokenNotFound
+	}
+	if !record.ExpiresAt.After(time.Now()) {
+		return nil, storage.ErrShareTokenNotFound
+	}
+	return record, nil
+}
+
+// authenticateShareToken checks for a share token in the Authorization
+// header. handled is true whenever a share token was presented, so an
+// invalid one is rejected rather than passed to the JWT and OIDC methods.
+func (a *Authenticator) authenticateShareToken(r *http.Request) (user *User, handled bool) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer "+ShareTokenPrefix) {
+		return nil, false
+	}
+	if a.service == nil {
+		return nil, true
+	}
+	record, err := a.service.ValidateShareToken(r.Context(), header[len("Bearer "):])
+	if err != nil {
+		return nil, true
+	}
+	return &User{
+		Username: "share:" + record.Name,
+		Role:     string(RoleReadOnly),
+		Method:   AuthMethodShareToken,
+		Share:    &ShareScope{Context: record.Context, Subject: record.Subject},
+	}, true
+}
+
+// SharedSchemaID is a schema ID lookup that a subject-scoped share token may
+// only perform if the schema is registered under its subject.
+type SharedSchemaID struct {
+	Context string
+	ID      int64
+}
+
+// Permits reports whether a share token with this scope may make a request.
+// Share tokens can only read subjects, schemas, configs and modes within
+// their scope; every other endpoint is refused. For a subject scope, a
+// lookup by schema ID is returned in check, and is only allowed if the
+// schema belongs to the subject.
+func (s ShareScope) Permits(method string, u *url.URL) (allowed bool, check *SharedSchemaID) {
+	if method != http.MethodGet {
+		return false, nil
+	}
+
+	// Split the escaped path so an encoded slash cannot shift a subject
+	// name into another segment.
+	segments := strings.Split(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	for i, seg := range segments {
+		unescaped, err := url.PathUnescape(seg)
+		if err != nil {
+			return false, nil
+		}
+		segments[i] = unescaped
+	}
+
+	registryCtx := registrycontext.DefaultContext
+	if segments[0] == "contexts" {
+		if len(segments) < 3 {
+			return false, nil
+		}
+		registryCtx = registrycontext.NormalizeContextName(segments[1])
+		segments = segments[2:]
+	}
+
+	// Query parameters may name subjects, for example ?subject= when
+	// fetching a schema by ID. They must stay within the scope too.
+	for _, values := range u.Query() {
+		for _, v := range values {
+			if !s.permitsSubjectRef(v) {
+				return false, nil
+			}
+		}
+	}
+	if subject := u.Query().Get("subject"); s.Subject != "" && subject != "" {
+		if _, resolved := registrycontext.ResolveSubject(subject); resolved != s.Subject {
+			return false, nil
+		}
+	}
+
+	switch segments[0] {
+	case "subjects", "config", "mode":
+		if len(segments) < 2 || segments[1] == "" {
+			// Listing subjects reveals other subjects, so only a context
+			// scope may do it; the context's config and mode are harmless.
+			return registryCtx == s.Context && (s.Subject == "" || segments[0] != "subjects"), nil
+		}
+		// A context-qualified subject overrides the URL context, as in
+		// the handlers.
+		ctxName, subject := registrycontext.ResolveSubject(segments[1])
+		if ctxName == registrycontext.DefaultContext {
+			ctxName = registryCtx
+		}
+		if ctxName != s.Context {
+			return false, nil
+		}
+		if s.Subject == "" {
+			return true, nil
+		}
+		// Schemas referencing the subject belong to other subjects.
+		return subject == s.Subject && segments[len(segments)-1] != "referencedby", nil
+	case "schemas":
+		if len(segments) == 2 && segments[1] == "types" {
+			return true, nil
+		}
+		if registryCtx != s.Context || len(segments) < 3 || segments[1] != "ids" {
+			return false, nil
+		}
+		if s.Subject == "" {
+			return true, nil
+		}
+		// Listing the subjects or versions of a schema would reveal other
+		// subjects, so a subject scope can only fetch the schema itself.
+		if len(segments) > 4 || (len(segments) == 4 && segments[3] != "schema") {
+			return false, nil
+		}
+		id, err := strconv.ParseInt(segments[2], 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		return true, &SharedSchemaID{Context: registryCtx, ID: id}
+	case "catalog":
+		// The catalog lists subjects, like GET /subjects.
+		return len(segments) == 2 && segments[1] == "subjects" && registryCtx == s.Context && s.Subject == "", nil
+	case "search":
+		// Search results span subjects, like the catalog.
+		return len(segments) == 2 && segments[1] == "schemas" && registryCtx == s.Context && s.Subject == "", nil
+	default:
+		return false, nil
+	}
+}
+
+// permitsSubjectRef reports whether a query parameter value stays within the
+// scope. Only context-qualified values (":.ctx:subject") can leave it.
+func (s ShareScope) permitsSubjectRef(v string) bool {
+	if !strings.HasPrefix(v, ":.") {
+		return true
+	}
+	ctxName, subject := registrycontext.ResolveSubject(v)
+	if ctxName != s.Context {
+		return false
+	}
+	return s.Subject == "" || subject == s.Subject
+}
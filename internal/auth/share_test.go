@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestShareScope_Permits(t *testing.T) {
+	contextScope := ShareScope{Context: ".partners"}
+	subjectScope := ShareScope{Context: ".partners", Subject: "orders-value"}
+
+	tests := []struct {
+		name    string
+		scope   ShareScope
+		method  string
+		target  string
+		allowed bool
+		check   bool
+	}{
+		{"context subjects", contextScope, "GET", "/contexts/.partners/subjects", true, false},
+		{"context schema", contextScope, "GET", "/contexts/partners/subjects/payments/versions/latest", true, false},
+		{"context schema by id", contextScope, "GET", "/contexts/.partners/schemas/ids/7/versions", true, false},
+		{"qualified subject", contextScope, "GET", "/subjects/:.partners:payments/versions", true, false},
+		{"context config", contextScope, "GET", "/contexts/.partners/config", true, false},
+		{"schema types", contextScope, "GET", "/schemas/types", true, false},
+		{"other context", contextScope, "GET", "/contexts/.internal/subjects", false, false},
+		{"default context", contextScope, "GET", "/subjects", false, false},
+		{"qualified other context", contextScope, "GET", "/contexts/.partners/subjects/:.internal:payments/versions", false, false},
+		{"write", contextScope, "POST", "/contexts/.partners/subjects/payments/versions", false, false},
+		{"delete", contextScope, "DELETE", "/contexts/.partners/subjects/payments", false, false},
+		{"admin", contextScope, "GET", "/admin/users", false, false},
+		{"contexts list", contextScope, "GET", "/contexts", false, false},
+		{"exporters", contextScope, "GET", "/contexts/.partners/exporters", false, false},
+		{"context catalog", contextScope, "GET", "/contexts/.partners/catalog/subjects", true, false},
+		{"default catalog", contextScope, "GET", "/catalog/subjects", false, false},
+		{"context search", contextScope, "GET", "/contexts/.partners/search/schemas", true, false},
+		{"qualified query", contextScope, "GET", "/contexts/.partners/schemas/ids/7?subject=:.internal:payments", false, false},
+
+		{"subject versions", subjectScope, "GET", "/contexts/.partners/subjects/orders-value/versions/1", true, false},
+		{"subject config", subjectScope, "GET", "/contexts/.partners/config/orders-value", true, false},
+		{"subject catalog", subjectScope, "GET", "/contexts/.partners/catalog/subjects", false, false},
+		{"subject search", subjectScope, "GET", "/contexts/.partners/search/schemas", false, false},
+		{"subject list", subjectScope, "GET", "/contexts/.partners/subjects", false, false},
+		{"other subject", subjectScope, "GET", "/contexts/.partners/subjects/payments/versions", false, false},
+		{"referenced by", subjectScope, "GET", "/contexts/.partners/subjects/orders-value/versions/1/referencedby", false, false},
+		{"encoded slash", subjectScope, "GET", "/contexts/.partners/subjects/orders-value%2F..%2Fpayments/versions", false, false},
+		{"schema by id", subjectScope, "GET", "/contexts/.partners/schemas/ids/7", true, true},
+		{"schema text by id", subjectScope, "GET", "/contexts/.partners/schemas/ids/7/schema", true, true},
+		{"schema subjects by id", subjectScope, "GET", "/contexts/.partners/schemas/ids/7/subjects", false, false},
+		{"schema by id other subject", subjectScope, "GET", "/contexts/.partners/schemas/ids/7?subject=payments", false, false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.target)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		allowed, check := tt.scope.Permits(tt.method, u)
+		if allowed != tt.allowed {
+			t.Errorf("%s: Permits(%s %s) = %v, want %v", tt.name, tt.method, tt.target, allowed, tt.allowed)
+		}
+		if (check != nil) != tt.check {
+			t.Errorf("%s: Permits(%s %s) check = %+v, want check %v", tt.name, tt.method, tt.target, check, tt.check)
+		}
+	}
+}
+
+func TestService_CreateShareToken(t *testing.T) {
+	svc := NewService(memory.NewStore())
+	ctx := context.Background()
+
+	record, token, err := svc.CreateShareToken(ctx, CreateShareTokenRequest{
+		Name:      "acme",
+		Context:   "partners",
+		Subject:   "orders-value",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateShareToken: %v", err)
+	}
+	if record.Context != ".partners" {
+		t.Errorf("expected normalized context .partners, got %q", record.Context)
+	}
+	if record.TokenHash == token {
+		t.Error("expected the token to be stored hashed")
+	}
+
+	got, err := svc.ValidateShareToken(ctx, token)
+	if err != nil {
+		t.Fatalf("ValidateShareToken: %v", err)
+	}
+	if got.ID != record.ID {
+		t.Errorf("expected share token %d, got %d", record.ID, got.ID)
+	}
+	if _, err := svc.ValidateShareToken(ctx, token+"0"); !errors.Is(err, storage.ErrShareTokenNotFound) {
+		t.Errorf("expected ErrShareTokenNotFound for unknown token, got %v", err)
+	}
+
+	invalid := []CreateShareTokenRequest{
+		{Context: "partners", ExpiresAt: time.Now().Add(time.Hour)},
+		{Name: "acme", ExpiresAt: time.Now().Add(time.Hour)},
+		{Name: "acme", Context: ".__GLOBAL", ExpiresAt: time.Now().Add(time.Hour)},
+		{Name: "acme", Context: "partners", Subject: ":.other:orders", ExpiresAt: time.Now().Add(time.Hour)},
+		{Name: "acme", Context: "partners", ExpiresAt: time.Now().Add(-time.Hour)},
+		{Name: "acme", Context: "partners", ExpiresAt: time.Now().Add(2 * MaxShareTokenLifetime)},
+	}
+	for i, req := range invalid {
+		if _, _, err := svc.CreateShareToken(ctx, req); !errors.Is(err, ErrInvalidShareToken) {
+			t.Errorf("request %d: expected ErrInvalidShareToken, got %v", i, err)
+		}
+	}
+}
+
+func TestService_ValidateShareToken_Expired(t *testing.T) {
+	store := memory.NewStore()
+	svc := NewService(store)
+	ctx := context.Background()
+
+	record, token, err := svc.CreateShareToken(ctx, CreateShareTokenRequest{
+		Name:      "acme",
+		Context:   "partners",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateShareToken: %v", err)
+	}
+	stored, _ := store.GetShareTokenByID(ctx, record.ID)
+	stored.ExpiresAt = time.Now().Add(-time.Minute)
+
+	if _, err := svc.ValidateShareToken(ctx, token); !errors.Is(err, storage.ErrShareTokenNotFound) {
+		t.Errorf("expected expired token to be rejected, got %v", err)
+	}
+}
+
+func TestAuthenticator_ShareToken(t *testing.T) {
+	svc := NewService(memory.NewStore())
+	_, token, err := svc.CreateShareToken(context.Background(), CreateShareTokenRequest{
+		Name:      "acme",
+		Context:   "partners",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateShareToken: %v", err)
+	}
+
+	// Share tokens work even when only basic auth is configured.
+	a := NewAuthenticator(config.AuthConfig{Enabled: true, Methods: []string{"basic"}})
+	a.SetService(svc)
+
+	var got *User
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = GetUser(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/contexts/.partners/subjects", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if got == nil || got.Method != AuthMethodShareToken || got.Role != string(RoleReadOnly) || got.Share == nil || got.Share.Context != ".partners" {
+		t.Errorf("unexpected share token user: %+v", got)
+	}
+
+	req = httptest.NewRequest("GET", "/contexts/.partners/subjects", nil)
+	req.Header.Set("Authorization", "Bearer "+ShareTokenPrefix+"invalid")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an invalid share token, got %d", rr.Code)
+	}
+}
@@ -9,12 +9,45 @@ import (
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
 )
 
+// LogicalTypeMode controls how changes of logical types are reported. The
+// Avro specification resolves schemas by their underlying types only, so
+// a decimal whose scale changed or a timestamp whose unit changed passes
+// the structural check although consumers read wrong values.
+type LogicalTypeMode string
+
+const (
+	// LogicalTypesOff ignores logical types.
+	LogicalTypesOff LogicalTypeMode = "off"
+	// LogicalTypesWarning reports logical type changes as warnings.
+	LogicalTypesWarning LogicalTypeMode = "warning"
+	// LogicalTypesError reports logical type changes as incompatibilities.
+	LogicalTypesError LogicalTypeMode = "error"
+)
+
+// Config configures a Checker.
+type Config struct {
+	// LogicalTypes defaults to LogicalTypesOff when empty.
+	LogicalTypes LogicalTypeMode
+}
+
 // Checker implements Avro schema compatibility checking.
-type Checker struct{}
+type Checker struct {
+	logicalTypes LogicalTypeMode
+}
 
-// NewChecker creates a new Avro compatibility checker.
+// NewChecker creates a new Avro compatibility checker that ignores logical
+// types.
 func NewChecker() *Checker {
-	return &Checker{}
+	return NewCheckerWithConfig(Config{})
+}
+
+// NewCheckerWithConfig creates a new Avro compatibility checker.
+func NewCheckerWithConfig(cfg Config) *Checker {
+	mode := cfg.LogicalTypes
+	if mode == "" {
+		mode = LogicalTypesOff
+	}
+	return &Checker{logicalTypes: mode}
 }
 
 // Check checks compatibility between reader and writer schemas.
@@ -23,15 +56,22 @@ func NewChecker() *Checker {
 func (c *Checker) Check(reader, writer compatibility.SchemaWithRefs) *compatibility.Result {
 	readerSchema, err := c.parseSchema(reader)
 	if err != nil {
-		return compatibility.NewIncompatibleResult(fmt.Sprintf("invalid reader schema: %v", err))
+		return invalidSchema("invalid reader schema: %v", err)
 	}
 
 	writerSchema, err := c.parseSchema(writer)
 	if err != nil {
-		return compatibility.NewIncompatibleResult(fmt.Sprintf("invalid writer schema: %v", err))
+		return invalidSchema("invalid writer schema: %v", err)
 	}
 
-	return c.checkSchemas(readerSchema, writerSchema, "")
+	return c.checkSchemas(readerSchema, writerSchema, "", make(map[namePair]bool))
+}
+
+// namePair identifies a reader and a writer record being compared. Records
+// that refer to themselves are compared once per pair; a pair met again
+// while it is being compared is assumed compatible.
+type namePair struct {
+	reader, writer string
 }
 
 // parseSchema parses a schema string with optional reference resolution.
@@ -51,8 +91,11 @@ func (c *Checker) parseSchema(s compatibility.SchemaWithRefs) (avro.Schema, erro
 }
 
 // checkSchemas recursively checks compatibility between two schemas.
-func (c *Checker) checkSchemas(reader, writer avro.Schema, path string) *compatibility.Result {
+// References to named types are resolved to the types they name.
+func (c *Checker) checkSchemas(reader, writer avro.Schema, path string, seen map[namePair]bool) *compatibility.Result {
+	reader, writer = resolveRef(reader), resolveRef(writer)
 	result := compatibility.NewCompatibleResult()
+	c.checkLogicalTypes(reader, writer, path, result)
 
 	// Handle schema promotion (widening)
 	if c.isPromotable(writer, reader) {
@@ -65,13 +108,13 @@ func (c *Checker) checkSchemas(reader, writer avro.Schema, path string) *compati
 		if !c.canPromote(writer, reader) {
 			// Check union compatibility
 			if reader.Type() == avro.Union {
-				return c.checkReaderUnion(reader, writer, path)
+				return c.checkReaderUnion(reader, writer, path, seen)
 			}
 			if writer.Type() == avro.Union {
-				return c.checkWriterUnion(reader, writer, path)
+				return c.checkWriterUnion(reader, writer, path, seen)
 			}
-			result.AddMessage("%s: type mismatch: reader has %s, writer has %s",
-				pathOrRoot(path), reader.Type(), writer.Type())
+			result.AddIncompatibility(compatibility.CodeTypeMismatch, pathOrRoot(path), reader.String(), writer.String(),
+				"%s: type mismatch: reader has %s, writer has %s", pathOrRoot(path), reader.Type(), writer.Type())
 			return result
 		}
 	}
@@ -79,17 +122,18 @@ func (c *Checker) checkSchemas(reader, writer avro.Schema, path string) *compati
 	// Type-specific compatibility checks
 	switch reader.Type() {
 	case avro.Record:
-		return c.checkRecord(reader.(*avro.RecordSchema), writer.(*avro.RecordSchema), path)
+		return c.checkRecord(reader.(*avro.RecordSchema), writer.(*avro.RecordSchema), path, seen)
 	case avro.Enum:
 		return c.checkEnum(reader.(*avro.EnumSchema), writer.(*avro.EnumSchema), path)
 	case avro.Array:
-		return c.checkArray(reader.(*avro.ArraySchema), writer.(*avro.ArraySchema), path)
+		return c.checkArray(reader.(*avro.ArraySchema), writer.(*avro.ArraySchema), path, seen)
 	case avro.Map:
-		return c.checkMap(reader.(*avro.MapSchema), writer.(*avro.MapSchema), path)
+		return c.checkMap(reader.(*avro.MapSchema), writer.(*avro.MapSchema), path, seen)
 	case avro.Union:
-		return c.checkUnion(reader.(*avro.UnionSchema), writer.(*avro.UnionSchema), path)
+		return c.checkUnion(reader.(*avro.UnionSchema), writer.(*avro.UnionSchema), path, seen)
 	case avro.Fixed:
-		return c.checkFixed(reader.(*avro.FixedSchema), writer.(*avro.FixedSchema), path)
+		result.Merge(c.checkFixed(reader.(*avro.FixedSchema), writer.(*avro.FixedSchema), path))
+		return result
 	case avro.String, avro.Bytes, avro.Int, avro.Long, avro.Float, avro.Double, avro.Boolean, avro.Null:
 		// Primitive types are compatible if types match (already checked above)
 		return result
@@ -99,16 +143,23 @@ func (c *Checker) checkSchemas(reader, writer avro.Schema, path string) *compati
 }
 
 // checkRecord checks compatibility between two record schemas.
-func (c *Checker) checkRecord(reader, writer *avro.RecordSchema, path string) *compatibility.Result {
+func (c *Checker) checkRecord(reader, writer *avro.RecordSchema, path string, seen map[namePair]bool) *compatibility.Result {
 	result := compatibility.NewCompatibleResult()
 
 	// Check that names match (considering aliases)
-	if !c.recordNamesMatch(reader, writer) {
-		result.AddMessage("%s: record name mismatch: reader has %s, writer has %s",
-			pathOrRoot(path), reader.FullName(), writer.FullName())
+	if !c.namesMatch(reader, writer) {
+		result.AddIncompatibility(compatibility.CodeNameMismatch, pathOrRoot(path), reader.FullName(), writer.FullName(),
+			"%s: record name mismatch: reader has %s, writer has %s", pathOrRoot(path), reader.FullName(), writer.FullName())
 		return result
 	}
 
+	pair := namePair{reader: reader.FullName(), writer: writer.FullName()}
+	if seen[pair] {
+		return result
+	}
+	seen[pair] = true
+	defer delete(seen, pair)
+
 	// Build maps of writer fields by name and aliases
 	writerFields := make(map[string]*avro.Field)
 	for _, f := range writer.Fields() {
@@ -128,23 +179,24 @@ func (c *Checker) checkRecord(reader, writer *avro.RecordSchema, path string) *c
 		if wf == nil {
 			// Field doesn't exist in writer - reader must have a default
 			if !rf.HasDefault() {
-				result.AddMessage("%s: reader field '%s' has no default and is missing from writer",
-					pathOrRoot(path), rf.Name())
+				result.AddIncompatibility(compatibility.CodeReaderFieldMissingDefault, fieldPath, rf.String(), "",
+					"%s: reader field '%s' has no default and is missing from writer", pathOrRoot(path), rf.Name())
 			}
 			continue
 		}
 
 		// Check field type compatibility
-		fieldResult := c.checkSchemas(rf.Type(), wf.Type(), fieldPath)
+		fieldResult := c.checkSchemas(rf.Type(), wf.Type(), fieldPath, seen)
 		result.Merge(fieldResult)
 	}
 
 	return result
 }
 
-// recordNamesMatch checks if reader and writer record names match,
-// considering aliases on both sides per the Avro specification.
-func (c *Checker) recordNamesMatch(reader, writer *avro.RecordSchema) bool {
+// namesMatch checks if the names of reader and writer named types (record,
+// enum, fixed) match, considering aliases on both sides, so that a type
+// renamed with an alias for its old name stays compatible.
+func (c *Checker) namesMatch(reader, writer avro.NamedSchema) bool {
 	if reader.FullName() == writer.FullName() {
 		return true
 	}
@@ -183,15 +235,15 @@ func (c *Checker) findWriterField(readerField *avro.Field, writerFields map[stri
 func (c *Checker) checkEnum(reader, writer *avro.EnumSchema, path string) *compatibility.Result {
 	result := compatibility.NewCompatibleResult()
 
-	// Check that names match
-	if reader.FullName() != writer.FullName() {
-		result.AddMessage("%s: enum name mismatch: reader has %s, writer has %s",
-			pathOrRoot(path), reader.FullName(), writer.FullName())
+	// Check that names match (considering aliases)
+	if !c.namesMatch(reader, writer) {
+		result.AddIncompatibility(compatibility.CodeNameMismatch, pathOrRoot(path), reader.FullName(), writer.FullName(),
+			"%s: enum name mismatch: reader has %s, writer has %s", pathOrRoot(path), reader.FullName(), writer.FullName())
 		return result
 	}
 
-	// Check that all writer symbols are in reader
-	// (reader can have additional symbols - those will use default if set)
+	// Check that all writer symbols are in reader. A writer symbol the
+	// reader does not have is read as the reader's default symbol.
 	readerSymbols := make(map[string]bool)
 	for _, s := range reader.Symbols() {
 		readerSymbols[s] = true
@@ -202,8 +254,8 @@ func (c *Checker) checkEnum(reader, writer *avro.EnumSchema, path string) *compa
 			// Writer has a symbol that reader doesn't have
 			// This is only compatible if reader has a default
 			if reader.Default() == "" {
-				result.AddMessage("%s: writer enum symbol '%s' not found in reader and no default set",
-					pathOrRoot(path), ws)
+				result.AddIncompatibility(compatibility.CodeEnumSymbolRemoved, pathOrRoot(path), reader.String(), ws,
+					"%s: writer enum symbol '%s' not found in reader and no default set", pathOrRoot(path), ws)
 			}
 		}
 	}
@@ -212,31 +264,27 @@ func (c *Checker) checkEnum(reader, writer *avro.EnumSchema, path string) *compa
 }
 
 // checkArray checks compatibility between two array schemas.
-func (c *Checker) checkArray(reader, writer *avro.ArraySchema, path string) *compatibility.Result {
-	return c.checkSchemas(reader.Items(), writer.Items(), appendPath(path, "[]"))
+func (c *Checker) checkArray(reader, writer *avro.ArraySchema, path string, seen map[namePair]bool) *compatibility.Result {
+	return c.checkSchemas(reader.Items(), writer.Items(), appendPath(path, "[]"), seen)
 }
 
 // checkMap checks compatibility between two map schemas.
-func (c *Checker) checkMap(reader, writer *avro.MapSchema, path string) *compatibility.Result {
-	return c.checkSchemas(reader.Values(), writer.Values(), appendPath(path, "{}"))
+func (c *Checker) checkMap(reader, writer *avro.MapSchema, path string, seen map[namePair]bool) *compatibility.Result {
+	return c.checkSchemas(reader.Values(), writer.Values(), appendPath(path, "{}"), seen)
 }
 
-// checkUnion checks compatibility between two union schemas.
-func (c *Checker) checkUnion(reader, writer *avro.UnionSchema, path string) *compatibility.Result {
+// checkUnion checks compatibility between two union schemas. Branches are
+// matched by type, not position, so reordering a union is compatible.
+func (c *Checker) checkUnion(reader, writer *avro.UnionSchema, path string, seen map[namePair]bool) *compatibility.Result {
 	result := compatibility.NewCompatibleResult()
 
 	// Each writer type must be compatible with at least one reader type
 	for _, wt := range writer.Types() {
-		found := false
-		for _, rt := range reader.Types() {
-			if c.checkSchemas(rt, wt, path).IsCompatible {
-				found = true
-				break
-			}
-		}
-		if !found {
-			result.AddMessage("%s: writer union type %s is not compatible with any reader union type",
-				pathOrRoot(path), wt.Type())
+		if match := c.matchBranch(reader.Types(), wt, path, seen); match != nil {
+			result.Merge(match)
+		} else {
+			result.AddIncompatibility(compatibility.CodeUnionBranchMissing, pathOrRoot(path), reader.String(), wt.String(),
+				"%s: writer union type %s is not compatible with any reader union type", pathOrRoot(path), wt.Type())
 		}
 	}
 
@@ -244,50 +292,158 @@ func (c *Checker) checkUnion(reader, writer *avro.UnionSchema, path string) *com
 }
 
 // checkReaderUnion handles the case where reader is a union but writer is not.
-func (c *Checker) checkReaderUnion(reader, writer avro.Schema, path string) *compatibility.Result {
+func (c *Checker) checkReaderUnion(reader, writer avro.Schema, path string, seen map[namePair]bool) *compatibility.Result {
 	union := reader.(*avro.UnionSchema)
 
 	// Writer type must be compatible with at least one type in the reader union
-	for _, rt := range union.Types() {
-		if c.checkSchemas(rt, writer, path).IsCompatible {
-			return compatibility.NewCompatibleResult()
-		}
+	if match := c.matchBranch(union.Types(), writer, path, seen); match != nil {
+		return match
 	}
 
-	return compatibility.NewIncompatibleResult(
-		fmt.Sprintf("%s: writer type %s is not compatible with any type in reader union",
-			pathOrRoot(path), writer.Type()))
+	result := compatibility.NewCompatibleResult()
+	result.AddIncompatibility(compatibility.CodeUnionBranchMissing, pathOrRoot(path), reader.String(), writer.String(),
+		"%s: writer type %s is not compatible with any type in reader union", pathOrRoot(path), writer.Type())
+	return result
 }
 
 // checkWriterUnion handles the case where writer is a union but reader is not.
-func (c *Checker) checkWriterUnion(reader, writer avro.Schema, path string) *compatibility.Result {
+func (c *Checker) checkWriterUnion(reader, writer avro.Schema, path string, seen map[namePair]bool) *compatibility.Result {
 	union := writer.(*avro.UnionSchema)
 
 	// All writer union types must be compatible with the reader type
+	merged := compatibility.NewCompatibleResult()
 	for _, wt := range union.Types() {
-		result := c.checkSchemas(reader, wt, path)
-		if !result.IsCompatible {
-			return compatibility.NewIncompatibleResult(
-				fmt.Sprintf("%s: reader type %s cannot read writer union type %s",
-					pathOrRoot(path), reader.Type(), wt.Type()))
+		result := c.checkSchemas(reader, wt, path, seen)
+		if !result.IsCompatible && !onlyLogicalTypeChanges(result) {
+			result := compatibility.NewCompatibleResult()
+			result.AddIncompatibility(compatibility.CodeUnionBranchMissing, pathOrRoot(path), reader.String(), wt.String(),
+				"%s: reader type %s cannot read writer union type %s", pathOrRoot(path), reader.Type(), wt.Type())
+			return result
+		}
+		merged.Merge(result)
+	}
+
+	return merged
+}
+
+// matchBranch returns the result of checking writer against the first
+// reader union branch that can read it. When no branch can, but one could
+// if not for a logical type change, that branch's result is returned so the
+// change is reported rather than a missing branch; otherwise it returns nil.
+func (c *Checker) matchBranch(branches []avro.Schema, writer avro.Schema, path string, seen map[namePair]bool) *compatibility.Result {
+	var logical *compatibility.Result
+	for _, rt := range branches {
+		result := c.checkSchemas(rt, writer, path, seen)
+		if result.IsCompatible {
+			return result
+		}
+		if logical == nil && onlyLogicalTypeChanges(result) {
+			logical = result
+		}
+	}
+	return logical
+}
+
+// onlyLogicalTypeChanges reports whether every incompatibility of an
+// incompatible result is a logical type change.
+func onlyLogicalTypeChanges(result *compatibility.Result) bool {
+	if len(result.Incompatibilities) == 0 {
+		return false
+	}
+	for _, inc := range result.Incompatibilities {
+		if inc.Code != compatibility.CodeLogicalTypeChanged {
+			return false
 		}
 	}
+	return true
+}
+
+// timeUnitFamilies groups the logical types that differ only in unit.
+var timeUnitFamilies = map[avro.LogicalType]string{
+	avro.TimestampMillis:      "timestamp",
+	avro.TimestampMicros:      "timestamp",
+	avro.LocalTimestampMillis: "local-timestamp",
+	avro.LocalTimestampMicros: "local-timestamp",
+	avro.TimeMillis:           "time",
+	avro.TimeMicros:           "time",
+}
+
+// checkLogicalTypes reports logical type changes that the structural check
+// accepts but that change how values are read: a decimal whose scale
+// changed or whose precision was narrowed, a timestamp or time whose unit
+// changed, and a string that gained or lost the uuid logical type.
+func (c *Checker) checkLogicalTypes(reader, writer avro.Schema, path string, result *compatibility.Result) {
+	if c.logicalTypes == LogicalTypesOff {
+		return
+	}
+	rl, wl := logicalType(reader), logicalType(writer)
+	if rl == nil && wl == nil {
+		return
+	}
+
+	var msg string
+	switch {
+	case rl != nil && wl != nil && rl.Type() == avro.Decimal && wl.Type() == avro.Decimal:
+		rd, rok := rl.(*avro.DecimalLogicalSchema)
+		wd, wok := wl.(*avro.DecimalLogicalSchema)
+		switch {
+		case !rok || !wok:
+		case rd.Scale() != wd.Scale():
+			msg = fmt.Sprintf("decimal scale changed: reader has %d, writer has %d", rd.Scale(), wd.Scale())
+		case rd.Precision() < wd.Precision():
+			msg = fmt.Sprintf("decimal precision narrowed: reader has %d, writer has %d", rd.Precision(), wd.Precision())
+		}
+	case rl != nil && wl != nil && rl.Type() != wl.Type() &&
+		timeUnitFamilies[rl.Type()] != "" && timeUnitFamilies[rl.Type()] == timeUnitFamilies[wl.Type()]:
+		msg = fmt.Sprintf("time unit changed: reader has %s, writer has %s", rl.Type(), wl.Type())
+	case reader.Type() == avro.String && writer.Type() == avro.String && isUUID(rl) != isUUID(wl):
+		msg = fmt.Sprintf("uuid logical type changed: reader has %s, writer has %s", describeString(rl), describeString(wl))
+	}
+	if msg == "" {
+		return
+	}
+
+	if c.logicalTypes == LogicalTypesError {
+		result.AddIncompatibility(compatibility.CodeLogicalTypeChanged, pathOrRoot(path), reader.String(), writer.String(),
+			"%s: %s", pathOrRoot(path), msg)
+		return
+	}
+	result.AddWarning("%s: %s", pathOrRoot(path), msg)
+}
+
+// logicalType returns the logical type of a primitive or fixed schema, or
+// nil if it has none.
+func logicalType(schema avro.Schema) avro.LogicalSchema {
+	if s, ok := schema.(avro.LogicalTypeSchema); ok {
+		return s.Logical()
+	}
+	return nil
+}
+
+func isUUID(l avro.LogicalSchema) bool {
+	return l != nil && l.Type() == avro.UUID
+}
 
-	return compatibility.NewCompatibleResult()
+// describeString names a string schema for uuid change messages.
+func describeString(l avro.LogicalSchema) string {
+	if isUUID(l) {
+		return "uuid"
+	}
+	return "string"
 }
 
 // checkFixed checks compatibility between two fixed schemas.
 func (c *Checker) checkFixed(reader, writer *avro.FixedSchema, path string) *compatibility.Result {
 	result := compatibility.NewCompatibleResult()
 
-	if reader.FullName() != writer.FullName() {
-		result.AddMessage("%s: fixed name mismatch: reader has %s, writer has %s",
-			pathOrRoot(path), reader.FullName(), writer.FullName())
+	if !c.namesMatch(reader, writer) {
+		result.AddIncompatibility(compatibility.CodeNameMismatch, pathOrRoot(path), reader.FullName(), writer.FullName(),
+			"%s: fixed name mismatch: reader has %s, writer has %s", pathOrRoot(path), reader.FullName(), writer.FullName())
 	}
 
 	if reader.Size() != writer.Size() {
-		result.AddMessage("%s: fixed size mismatch: reader has %d, writer has %d",
-			pathOrRoot(path), reader.Size(), writer.Size())
+		result.AddIncompatibility(compatibility.CodeFixedSizeMismatch, pathOrRoot(path), reader.String(), writer.String(),
+			"%s: fixed size mismatch: reader has %d, writer has %d", pathOrRoot(path), reader.Size(), writer.Size())
 	}
 
 	return result
@@ -320,6 +476,22 @@ func (c *Checker) canPromote(writer, reader avro.Schema) bool {
 	return false
 }
 
+// resolveRef returns the named type a reference refers to, or the schema
+// itself when it is not a reference.
+func resolveRef(schema avro.Schema) avro.Schema {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		return ref.Schema()
+	}
+	return schema
+}
+
+// invalidSchema returns an incompatible result for a schema that failed to parse.
+func invalidSchema(format string, args ...interface{}) *compatibility.Result {
+	result := compatibility.NewCompatibleResult()
+	result.AddIncompatibility(compatibility.CodeSchemaInvalid, "", "", "", format, args...)
+	return result
+}
+
 // pathOrRoot returns the path or "root" if empty.
 func pathOrRoot(path string) string {
 	if path == "" {
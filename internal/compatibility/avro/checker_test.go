@@ -1,6 +1,7 @@
 package avro
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
@@ -413,3 +414,254 @@ func TestChecker_PrimitiveTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestChecker_NamedTypeAliases(t *testing.T) {
+	checker := NewChecker()
+
+	tests := []struct {
+		name   string
+		reader string
+		writer string
+	}{
+		{
+			"record renamed across namespaces",
+			`{"type": "record", "name": "Customer", "namespace": "com.new", "aliases": ["com.old.User"], "fields": [{"name": "id", "type": "long"}]}`,
+			`{"type": "record", "name": "User", "namespace": "com.old", "fields": [{"name": "id", "type": "long"}]}`,
+		},
+		{
+			"field renamed",
+			`{"type": "record", "name": "User", "fields": [{"name": "user_id", "type": "long", "aliases": ["id"]}]}`,
+			`{"type": "record", "name": "User", "fields": [{"name": "id", "type": "long"}]}`,
+		},
+		{
+			"enum renamed",
+			`{"type": "enum", "name": "State", "aliases": ["Status"], "symbols": ["ACTIVE", "INACTIVE"]}`,
+			`{"type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE"]}`,
+		},
+		{
+			"fixed renamed",
+			`{"type": "fixed", "name": "Digest", "aliases": ["Hash"], "size": 16}`,
+			`{"type": "fixed", "name": "Hash", "size": 16}`,
+		},
+		{
+			"renamed record referenced by name",
+			`{"type": "record", "name": "Order", "fields": [
+				{"name": "billing", "type": {"type": "record", "name": "Location", "aliases": ["Address"], "fields": [{"name": "city", "type": "string"}]}},
+				{"name": "shipping", "type": ["null", "Location"]}
+			]}`,
+			`{"type": "record", "name": "Order", "fields": [
+				{"name": "billing", "type": {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}]}},
+				{"name": "shipping", "type": ["null", "Address"]}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		result := checker.Check(s(tt.reader), s(tt.writer))
+		if !result.IsCompatible {
+			t.Errorf("%s: expected compatible, got incompatible: %v", tt.name, result.Messages)
+		}
+	}
+
+	// Without the alias, a renamed enum is incompatible.
+	result := checker.Check(
+		s(`{"type": "enum", "name": "State", "symbols": ["ACTIVE"]}`),
+		s(`{"type": "enum", "name": "Status", "symbols": ["ACTIVE"]}`))
+	if result.IsCompatible {
+		t.Error("Expected incompatible (enum renamed without alias), got compatible")
+	}
+}
+
+func TestChecker_EnumCompatibility_RemoveSymbolWithDefault(t *testing.T) {
+	checker := NewChecker()
+
+	writerSchema := `{
+		"type": "enum",
+		"name": "Status",
+		"symbols": ["ACTIVE", "INACTIVE", "PENDING", "UNKNOWN"]
+	}`
+
+	// PENDING is read as the reader's default symbol
+	readerSchema := `{
+		"type": "enum",
+		"name": "Status",
+		"symbols": ["ACTIVE", "INACTIVE", "UNKNOWN"],
+		"default": "UNKNOWN"
+	}`
+
+	result := checker.Check(s(readerSchema), s(writerSchema))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible (reader enum has a default), got incompatible: %v", result.Messages)
+	}
+}
+
+func TestChecker_UnionCompatibility_Reorder(t *testing.T) {
+	checker := NewChecker()
+
+	writerSchema := `{
+		"type": "record",
+		"name": "Event",
+		"fields": [
+			{"name": "payload", "type": [
+				"null",
+				{"type": "record", "name": "Created", "fields": [{"name": "id", "type": "long"}]},
+				{"type": "record", "name": "Deleted", "fields": [{"name": "id", "type": "long"}]}
+			]}
+		]
+	}`
+
+	readerSchema := `{
+		"type": "record",
+		"name": "Event",
+		"fields": [
+			{"name": "payload", "type": [
+				{"type": "record", "name": "Deleted", "fields": [{"name": "id", "type": "long"}]},
+				{"type": "record", "name": "Created", "fields": [{"name": "id", "type": "long"}]},
+				"null"
+			]}
+		]
+	}`
+
+	result := checker.Check(s(readerSchema), s(writerSchema))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible (reordered union), got incompatible: %v", result.Messages)
+	}
+}
+
+func TestChecker_NamedTypeReferences(t *testing.T) {
+	checker := NewChecker()
+
+	// Address is defined in the first field that uses it; later uses are
+	// references by name. Moving the definition is compatible.
+	writerSchema := `{
+		"type": "record",
+		"name": "Order",
+		"fields": [
+			{"name": "shipping", "type": ["null", {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}]}]},
+			{"name": "billing", "type": "Address"}
+		]
+	}`
+
+	readerSchema := `{
+		"type": "record",
+		"name": "Order",
+		"fields": [
+			{"name": "billing", "type": {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}]}},
+			{"name": "shipping", "type": ["null", "Address"]}
+		]
+	}`
+
+	result := checker.Check(s(readerSchema), s(writerSchema))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible (moved named type definition), got incompatible: %v", result.Messages)
+	}
+
+	// A reference to a different named type is incompatible.
+	readerSchema = `{
+		"type": "record",
+		"name": "Order",
+		"fields": [
+			{"name": "billing", "type": {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}]}},
+			{"name": "pickup", "type": ["null", {"type": "record", "name": "Store", "fields": [{"name": "code", "type": "string"}]}], "default": null},
+			{"name": "shipping", "type": ["null", "Store"]}
+		]
+	}`
+
+	result = checker.Check(s(readerSchema), s(writerSchema))
+	if result.IsCompatible {
+		t.Error("Expected incompatible (reference to a different named type), got compatible")
+	}
+}
+
+func TestChecker_RecursiveRecord(t *testing.T) {
+	checker := NewChecker()
+
+	writerSchema := `{
+		"type": "record",
+		"name": "Node",
+		"fields": [
+			{"name": "value", "type": "int"},
+			{"name": "next", "type": ["null", "Node"]}
+		]
+	}`
+
+	readerSchema := `{
+		"type": "record",
+		"name": "Node",
+		"fields": [
+			{"name": "value", "type": "long"},
+			{"name": "label", "type": "string", "default": ""},
+			{"name": "next", "type": ["null", "Node"]}
+		]
+	}`
+
+	result := checker.Check(s(readerSchema), s(writerSchema))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible (recursive record), got incompatible: %v", result.Messages)
+	}
+
+	result = checker.Check(s(writerSchema), s(readerSchema))
+	if result.IsCompatible {
+		t.Error("Expected incompatible (long narrowed to int in recursive record), got compatible")
+	}
+}
+
+func TestChecker_LogicalTypes(t *testing.T) {
+	field := func(typ string) string {
+		return `{"type": "record", "name": "Payment", "fields": [{"name": "value", "type": ` + typ + `}]}`
+	}
+	decimal := func(precision, scale int) string {
+		return fmt.Sprintf(`{"type": "bytes", "logicalType": "decimal", "precision": %d, "scale": %d}`, precision, scale)
+	}
+
+	tests := []struct {
+		name           string
+		reader, writer string
+		changed        bool
+	}{
+		{"decimal precision widened", decimal(12, 2), decimal(10, 2), false},
+		{"decimal precision narrowed", decimal(8, 2), decimal(10, 2), true},
+		{"decimal scale changed", decimal(10, 3), decimal(10, 2), true},
+		{"timestamp millis to micros", `{"type": "long", "logicalType": "timestamp-micros"}`, `{"type": "long", "logicalType": "timestamp-millis"}`, true},
+		{"local timestamp micros to millis", `{"type": "long", "logicalType": "local-timestamp-millis"}`, `{"type": "long", "logicalType": "local-timestamp-micros"}`, true},
+		{"time millis to micros", `{"type": "long", "logicalType": "time-micros"}`, `{"type": "int", "logicalType": "time-millis"}`, true},
+		{"timestamp unchanged", `{"type": "long", "logicalType": "timestamp-millis"}`, `{"type": "long", "logicalType": "timestamp-millis"}`, false},
+		{"string to uuid", `{"type": "string", "logicalType": "uuid"}`, `"string"`, true},
+		{"uuid to string", `"string"`, `{"type": "string", "logicalType": "uuid"}`, true},
+		{"optional decimal scale changed", `["null", ` + decimal(10, 3) + `]`, `["null", ` + decimal(10, 2) + `]`, true},
+		{"decimal scale changed into union", `["null", ` + decimal(10, 3) + `]`, decimal(10, 2), true},
+		{"fixed decimal scale changed",
+			`{"type": "fixed", "name": "Amount", "size": 8, "logicalType": "decimal", "precision": 10, "scale": 3}`,
+			`{"type": "fixed", "name": "Amount", "size": 8, "logicalType": "decimal", "precision": 10, "scale": 2}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, writer := s(field(tt.reader)), s(field(tt.writer))
+
+			result := NewChecker().Check(reader, writer)
+			if !result.IsCompatible || len(result.Warnings) != 0 {
+				t.Errorf("expected logical types to be ignored by default, got %v %v", result.Messages, result.Warnings)
+			}
+
+			result = NewCheckerWithConfig(Config{LogicalTypes: LogicalTypesWarning}).Check(reader, writer)
+			if !result.IsCompatible {
+				t.Errorf("expected compatible with warnings, got incompatible: %v", result.Messages)
+			}
+			if got := len(result.Warnings) != 0; got != tt.changed {
+				t.Errorf("expected warning %v, got %v", tt.changed, result.Warnings)
+			}
+
+			result = NewCheckerWithConfig(Config{LogicalTypes: LogicalTypesError}).Check(reader, writer)
+			if result.IsCompatible == tt.changed {
+				t.Errorf("expected compatible %v, got %v: %v", !tt.changed, result.IsCompatible, result.Messages)
+			}
+			if tt.changed {
+				if len(result.Incompatibilities) != 1 || result.Incompatibilities[0].Code != compatibility.CodeLogicalTypeChanged {
+					t.Errorf("expected a single %s incompatibility, got %+v", compatibility.CodeLogicalTypeChanged, result.Incompatibilities)
+				} else if result.Incompatibilities[0].Path != "value" {
+					t.Errorf("expected path value, got %s", result.Incompatibilities[0].Path)
+				}
+			}
+		})
+	}
+}
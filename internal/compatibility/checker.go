@@ -16,6 +16,15 @@ type SchemaChecker interface {
 	Check(reader, writer SchemaWithRefs) *Result
 }
 
+// HistoryChecker is implemented by type-specific checkers with rules that
+// span a subject's whole history rather than one pair of schemas, such as
+// Protobuf field numbers that must never be reused. existingSchemas are
+// ordered from oldest to newest. Incompatibilities report the new schema's
+// fragment as Reader and the existing schema's as Writer.
+type HistoryChecker interface {
+	CheckHistory(newSchema SchemaWithRefs, existingSchemas []SchemaWithRefs) *Result
+}
+
 // Checker orchestrates compatibility checking across schema types.
 type Checker struct {
 	checkers map[storage.SchemaType]SchemaChecker
@@ -65,33 +74,70 @@ func (c *Checker) Check(mode Mode, schemaType storage.SchemaType, newSchema Sche
 	}
 
 	for i, existingSchema := range schemasToCheck {
-		var checkResult *Result
-
-		if mode.RequiresBackward() {
-			// BACKWARD: new schema (reader) can read data from old schema (writer)
-			checkResult = checker.Check(newSchema, existingSchema)
-			if !checkResult.IsCompatible {
-				for _, msg := range checkResult.Messages {
-					result.AddMessage("BACKWARD compatibility check failed against version %d: %s", i+1, msg)
-				}
-			}
+		c.checkAgainst(mode, checker, newSchema, existingSchema, i+1, result)
+	}
+
+	// History rules apply to every existing schema whatever the mode, since
+	// they protect data written by any earlier version.
+	if hc, ok := checker.(HistoryChecker); ok {
+		for _, inc := range hc.CheckHistory(newSchema, existingSchemas).Incompatibilities {
+			inc.Old, inc.New = inc.Writer, inc.Reader
+			result.add(inc)
 		}
+	}
 
-		if mode.RequiresForward() {
-			// FORWARD: old schema (reader) can read data from new schema (writer)
-			checkResult = checker.Check(existingSchema, newSchema)
-			if !checkResult.IsCompatible {
-				for _, msg := range checkResult.Messages {
-					result.AddMessage("FORWARD compatibility check failed against version %d: %s", i+1, msg)
-				}
+	return result
+}
+
+// checkAgainst checks newSchema against one existing schema in the
+// directions required by mode, adding failures to result.
+func (c *Checker) checkAgainst(mode Mode, checker SchemaChecker, newSchema, existingSchema SchemaWithRefs, version int, result *Result) {
+	if mode.RequiresBackward() {
+		// BACKWARD: new schema (reader) can read data from old schema (writer)
+		checkResult := checker.Check(newSchema, existingSchema)
+		if !checkResult.IsCompatible {
+			for _, inc := range checkResult.Incompatibilities {
+				inc.Old, inc.New = inc.Writer, inc.Reader
+				result.addAgainst("BACKWARD", version, inc)
 			}
 		}
+		for _, msg := range checkResult.Warnings {
+			result.AddWarning("BACKWARD compatibility warning against version %d: %s", version, msg)
+		}
 	}
 
-	return result
+	if mode.RequiresForward() {
+		// FORWARD: old schema (reader) can read data from new schema (writer)
+		checkResult := checker.Check(existingSchema, newSchema)
+		if !checkResult.IsCompatible {
+			for _, inc := range checkResult.Incompatibilities {
+				inc.Old, inc.New = inc.Reader, inc.Writer
+				result.addAgainst("FORWARD", version, inc)
+			}
+		}
+		for _, msg := range checkResult.Warnings {
+			result.AddWarning("FORWARD compatibility warning against version %d: %s", version, msg)
+		}
+	}
 }
 
 // CheckPair checks compatibility between two specific schemas.
 func (c *Checker) CheckPair(mode Mode, schemaType storage.SchemaType, newSchema, existingSchema SchemaWithRefs) *Result {
 	return c.Check(mode, schemaType, newSchema, []SchemaWithRefs{existingSchema})
 }
+
+// CheckVersion checks a new schema against one existing version of a subject
+// in the directions required by mode, ignoring whether mode is transitive.
+// Failure messages name existingVersion.
+func (c *Checker) CheckVersion(mode Mode, schemaType storage.SchemaType, newSchema, existingSchema SchemaWithRefs, existingVersion int) *Result {
+	if mode == ModeNone {
+		return NewCompatibleResult()
+	}
+	checker, ok := c.checkers[schemaType]
+	if !ok {
+		return NewIncompatibleResult("no compatibility checker for schema type: " + string(schemaType))
+	}
+	result := NewCompatibleResult()
+	c.checkAgainst(mode, checker, newSchema, existingSchema, existingVersion, result)
+	return result
+}
@@ -334,6 +334,32 @@ func TestChecker_FullTransitive_Protobuf(t *testing.T) {
 	}
 }
 
+func TestChecker_Backward_Protobuf_ChecksFieldReuseAcrossHistory(t *testing.T) {
+	c := newCheckerWithAll()
+
+	v1 := `syntax = "proto3"; message User { string id = 1; string email = 2; }`
+	// v2 removes email without reserving its number
+	v2 := `syntax = "proto3"; message User { string id = 1; }`
+	// v3 reuses number 2 for a different field
+	v3 := `syntax = "proto3"; message User { string id = 1; int64 created_at = 2; }`
+
+	// BACKWARD only compares v3 with v2, but number reuse is checked
+	// against the whole history.
+	result := c.Check(compatibility.ModeBackward, storage.SchemaTypeProtobuf, s(v3), ss(v1, v2))
+	if result.IsCompatible {
+		t.Fatal("expected reuse of a removed field number to be incompatible")
+	}
+	inc := result.Incompatibilities[0]
+	if inc.Code != compatibility.CodeFieldNumberReused || inc.New != "optional int64 created_at = 2" || inc.Old != "optional string email = 2" {
+		t.Errorf("unexpected incompatibility: %+v", inc)
+	}
+
+	result = c.Check(compatibility.ModeNone, storage.SchemaTypeProtobuf, s(v3), ss(v1, v2))
+	if !result.IsCompatible {
+		t.Errorf("NONE should pass: %v", result.Messages)
+	}
+}
+
 // --- JSON Schema transitive tests ---
 
 func TestChecker_BackwardTransitive_JSONSchema(t *testing.T) {
@@ -522,3 +548,115 @@ func TestChecker_JSONSchema_FourVersionEvolution_BackwardTransitive(t *testing.T
 		t.Errorf("4-version JSON Schema evolution should pass BACKWARD_TRANSITIVE: %v", result.Messages)
 	}
 }
+
+func TestChecker_JSONSchema_LenientPatternChange_Warns(t *testing.T) {
+	c := compatibility.NewChecker()
+	c.Register(storage.SchemaTypeJSON, jscompat.NewCheckerWithConfig(jscompat.Config{Strictness: jscompat.StrictnessLenient}))
+
+	v1 := `{"type":"object","properties":{"code":{"type":"string","pattern":"^[A-Z]{3}$"}}}`
+	v2 := `{"type":"object","properties":{"code":{"type":"string","pattern":"^[A-Z]{2,3}$"}}}`
+
+	result := c.Check(compatibility.ModeBackward, storage.SchemaTypeJSON, s(v2), ss(v1))
+	if !result.IsCompatible {
+		t.Errorf("expected lenient pattern change to be compatible, got %v", result.Messages)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("expected 1 warning, got %v", result.Warnings)
+	}
+}
+
+// --- Structured incompatibilities ---
+
+func TestChecker_Incompatibilities(t *testing.T) {
+	c := newCheckerWithAll()
+
+	tests := []struct {
+		name       string
+		mode       compatibility.Mode
+		schemaType storage.SchemaType
+		oldSchema  string
+		newSchema  string
+		code       string
+		path       string
+		direction  string
+		old, new   string
+	}{
+		{
+			name:       "avro field without default, backward",
+			mode:       compatibility.ModeBackward,
+			schemaType: storage.SchemaTypeAvro,
+			oldSchema:  `{"type":"record","name":"User","fields":[{"name":"id","type":"long"}]}`,
+			newSchema:  `{"type":"record","name":"User","fields":[{"name":"id","type":"long"},{"name":"email","type":"string"}]}`,
+			code:       compatibility.CodeReaderFieldMissingDefault,
+			path:       "email",
+			direction:  "BACKWARD",
+			new:        `{"name":"email","type":"string"}`,
+		},
+		{
+			name:       "avro field without default, forward",
+			mode:       compatibility.ModeForward,
+			schemaType: storage.SchemaTypeAvro,
+			oldSchema:  `{"type":"record","name":"User","fields":[{"name":"id","type":"long"},{"name":"email","type":"string"}]}`,
+			newSchema:  `{"type":"record","name":"User","fields":[{"name":"id","type":"long"}]}`,
+			code:       compatibility.CodeReaderFieldMissingDefault,
+			path:       "email",
+			direction:  "FORWARD",
+			old:        `{"name":"email","type":"string"}`,
+		},
+		{
+			name:       "protobuf field type changed",
+			mode:       compatibility.ModeBackward,
+			schemaType: storage.SchemaTypeProtobuf,
+			oldSchema:  `syntax = "proto3"; message User { string id = 1; }`,
+			newSchema:  `syntax = "proto3"; message User { int32 id = 1; }`,
+			code:       compatibility.CodeTypeMismatch,
+			path:       "User.id",
+			direction:  "BACKWARD",
+			old:        "optional string id = 1",
+			new:        "optional int32 id = 1",
+		},
+		{
+			name:       "json schema type option removed",
+			mode:       compatibility.ModeBackward,
+			schemaType: storage.SchemaTypeJSON,
+			oldSchema:  `{"oneOf":[{"type":"string"},{"type":"integer"}]}`,
+			newSchema:  `{"oneOf":[{"type":"string"}]}`,
+			code:       compatibility.CodeTypeNarrowed,
+			path:       "root",
+			direction:  "BACKWARD",
+			old:        `{"type":"integer"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(tt.mode, tt.schemaType, s(tt.newSchema), ss(tt.oldSchema))
+			if result.IsCompatible {
+				t.Fatal("expected incompatible")
+			}
+			if len(result.Incompatibilities) != len(result.Messages) {
+				t.Fatalf("got %d incompatibilities for %d messages", len(result.Incompatibilities), len(result.Messages))
+			}
+			for i, inc := range result.Incompatibilities {
+				if inc.Message != result.Messages[i] {
+					t.Errorf("incompatibility %d message %q, want %q", i, inc.Message, result.Messages[i])
+				}
+			}
+			inc := result.Incompatibilities[0]
+			if inc.Code != tt.code || inc.Path != tt.path || inc.Direction != tt.direction || inc.Version != 1 {
+				t.Errorf("got %s at %s (%s v%d), want %s at %s (%s v1)", inc.Code, inc.Path, inc.Direction, inc.Version, tt.code, tt.path, tt.direction)
+			}
+			if inc.Old != tt.old || inc.New != tt.new {
+				t.Errorf("got old %q new %q, want old %q new %q", inc.Old, inc.New, tt.old, tt.new)
+			}
+		})
+	}
+}
+
+func TestChecker_Incompatibilities_InvalidSchema(t *testing.T) {
+	c := newCheckerWithAll()
+	result := c.Check(compatibility.ModeBackward, storage.SchemaTypeAvro, s(`{"type":`), ss(`{"type":"string"}`))
+	if len(result.Incompatibilities) != 1 || result.Incompatibilities[0].Code != compatibility.CodeSchemaInvalid {
+		t.Errorf("got %+v, want one SCHEMA_INVALID", result.Incompatibilities)
+	}
+}
@@ -0,0 +1,118 @@
+package compatibility
+
+// Reason codes for Incompatibility. A code names the kind of change, so the
+// same code is used by every schema type that can make that change.
+const (
+	// CodeIncompatibleChange is used for changes without a more specific code.
+	CodeIncompatibleChange = "INCOMPATIBLE_CHANGE"
+	// CodeSchemaInvalid means a schema could not be parsed.
+	CodeSchemaInvalid = "SCHEMA_INVALID"
+
+	// CodeTypeMismatch means a type changed to one that cannot be read as the other.
+	CodeTypeMismatch = "TYPE_MISMATCH"
+	// CodeTypeNarrowed means a type accepts fewer values than before, such as
+	// a removed type option or a forbidden additionalProperties schema.
+	CodeTypeNarrowed = "TYPE_NARROWED"
+	// CodeNameMismatch means a named type (record, enum, fixed) was renamed
+	// without an alias.
+	CodeNameMismatch = "NAME_MISMATCH"
+	// CodeFixedSizeMismatch means the size of an Avro fixed type changed.
+	CodeFixedSizeMismatch = "FIXED_SIZE_MISMATCH"
+	// CodeReaderFieldMissingDefault means the reader has a field without a
+	// default that the writer does not have.
+	CodeReaderFieldMissingDefault = "READER_FIELD_MISSING_DEFAULT"
+	// CodeEnumSymbolRemoved means the writer can produce an enum value the
+	// reader does not have.
+	CodeEnumSymbolRemoved = "ENUM_SYMBOL_REMOVED"
+	// CodeUnionBranchMissing means a writer type matches no reader union branch.
+	CodeUnionBranchMissing = "UNION_BRANCH_MISSING"
+	// CodeLogicalTypeChanged means an Avro logical type changed in a way the
+	// encoding allows but that changes the meaning of values, such as a
+	// narrowed decimal precision or timestamp-millis becoming timestamp-micros.
+	CodeLogicalTypeChanged = "LOGICAL_TYPE_CHANGED"
+
+	// CodePackageChanged means the Protobuf package changed.
+	CodePackageChanged = "PACKAGE_CHANGED"
+	// CodeMessageRemoved means a Protobuf message was removed.
+	CodeMessageRemoved = "MESSAGE_REMOVED"
+	// CodeRequiredFieldAdded means a required field or property was added,
+	// or an optional one became required.
+	CodeRequiredFieldAdded = "REQUIRED_FIELD_ADDED"
+	// CodeRequiredFieldRemoved means a required Protobuf field was removed.
+	CodeRequiredFieldRemoved = "REQUIRED_FIELD_REMOVED"
+	// CodeFieldLabelChanged means a Protobuf field changed between optional,
+	// required and repeated.
+	CodeFieldLabelChanged = "FIELD_LABEL_CHANGED"
+	// CodeOneofChanged means Protobuf fields moved into, out of or were
+	// removed from a oneof.
+	CodeOneofChanged = "ONEOF_CHANGED"
+	// CodeReservedFieldReused means a Protobuf field uses a number or name
+	// that an earlier version reserved.
+	CodeReservedFieldReused = "RESERVED_FIELD_REUSED"
+	// CodeFieldNumberReused means a Protobuf field reuses the number of a
+	// field that an earlier version removed.
+	CodeFieldNumberReused = "FIELD_NUMBER_REUSED"
+	// CodeServiceRemoved means a Protobuf service was removed.
+	CodeServiceRemoved = "SERVICE_REMOVED"
+	// CodeMethodRemoved means a Protobuf service method was removed.
+	CodeMethodRemoved = "METHOD_REMOVED"
+	// CodeMethodChanged means a Protobuf method's types or streaming changed.
+	CodeMethodChanged = "METHOD_CHANGED"
+
+	// CodePropertyRemoved means a JSON Schema property was removed.
+	CodePropertyRemoved = "PROPERTY_REMOVED"
+	// CodePropertyAdded means a JSON Schema property was added to an open
+	// content model.
+	CodePropertyAdded = "PROPERTY_ADDED"
+	// CodeItemAdded means a JSON Schema tuple item was added.
+	CodeItemAdded = "ITEM_ADDED"
+	// CodeItemRemoved means a JSON Schema tuple item was removed.
+	CodeItemRemoved = "ITEM_REMOVED"
+	// CodeConstraintAdded means a JSON Schema constraint was added.
+	CodeConstraintAdded = "CONSTRAINT_ADDED"
+	// CodeConstraintRemoved means a JSON Schema constraint was removed.
+	CodeConstraintRemoved = "CONSTRAINT_REMOVED"
+	// CodeConstraintChanged means a JSON Schema constraint changed value.
+	CodeConstraintChanged = "CONSTRAINT_CHANGED"
+	// CodeConstraintTightened means a JSON Schema bound was tightened.
+	CodeConstraintTightened = "CONSTRAINT_TIGHTENED"
+	// CodeCompositionChanged means an allOf, anyOf or oneOf element changed.
+	CodeCompositionChanged = "COMPOSITION_CHANGED"
+)
+
+// Reason categories group reason codes into a few coarse kinds of change,
+// for use as a metric label.
+const (
+	CategoryTypeChanged       = "type_changed"
+	CategoryFieldAdded        = "field_added"
+	CategoryFieldRemoved      = "field_removed"
+	CategoryFieldChanged      = "field_changed"
+	CategoryConstraintChanged = "constraint_changed"
+	CategoryServiceChanged    = "service_changed"
+	CategoryInvalidSchema     = "invalid_schema"
+	CategoryOther             = "other"
+)
+
+// ReasonCategory returns the category of a reason code. Unknown codes and
+// CodeIncompatibleChange are CategoryOther.
+func ReasonCategory(code string) string {
+	switch code {
+	case CodeTypeMismatch, CodeTypeNarrowed, CodeNameMismatch, CodeFixedSizeMismatch,
+		CodeUnionBranchMissing, CodeLogicalTypeChanged, CodePackageChanged, CodeCompositionChanged:
+		return CategoryTypeChanged
+	case CodeReaderFieldMissingDefault, CodeRequiredFieldAdded, CodePropertyAdded, CodeItemAdded:
+		return CategoryFieldAdded
+	case CodeRequiredFieldRemoved, CodePropertyRemoved, CodeItemRemoved, CodeEnumSymbolRemoved, CodeMessageRemoved:
+		return CategoryFieldRemoved
+	case CodeFieldLabelChanged, CodeOneofChanged, CodeReservedFieldReused, CodeFieldNumberReused:
+		return CategoryFieldChanged
+	case CodeConstraintAdded, CodeConstraintRemoved, CodeConstraintChanged, CodeConstraintTightened:
+		return CategoryConstraintChanged
+	case CodeServiceRemoved, CodeMethodRemoved, CodeMethodChanged:
+		return CategoryServiceChanged
+	case CodeSchemaInvalid:
+		return CategoryInvalidSchema
+	default:
+		return CategoryOther
+	}
+}
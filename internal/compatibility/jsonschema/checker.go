@@ -7,18 +7,43 @@ import (
 	"math"
 	"reflect"
 	"sort"
-	"strings"
 
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
-	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
+// Strictness controls how changes that may or may not narrow the set of
+// valid documents are reported.
+type Strictness string
+
+const (
+	// StrictnessStrict reports every change that could reject previously
+	// valid data as an incompatibility. This is the default.
+	StrictnessStrict Strictness = "strict"
+	// StrictnessLenient reports changes whose effect cannot be decided
+	// statically, such as a changed pattern, as warnings instead.
+	StrictnessLenient Strictness = "lenient"
+)
+
+// Config configures a Checker.
+type Config struct {
+	// Strictness defaults to StrictnessStrict when empty.
+	Strictness Strictness
+}
+
 // Checker implements compatibility.SchemaChecker for JSON Schema.
-type Checker struct{}
+type Checker struct {
+	lenient bool
+}
 
-// NewChecker creates a new JSON Schema compatibility checker.
+// NewChecker creates a new JSON Schema compatibility checker with strict
+// checking.
 func NewChecker() *Checker {
-	return &Checker{}
+	return NewCheckerWithConfig(Config{})
+}
+
+// NewCheckerWithConfig creates a new JSON Schema compatibility checker.
+func NewCheckerWithConfig(cfg Config) *Checker {
+	return &Checker{lenient: cfg.Strictness == StrictnessLenient}
 }
 
 // Check checks compatibility between reader (new) and writer (old) JSON schemas.
@@ -26,20 +51,17 @@ func (c *Checker) Check(reader, writer compatibility.SchemaWithRefs) *compatibil
 	var newSchema, oldSchema map[string]interface{}
 
 	if err := json.Unmarshal([]byte(reader.Schema), &newSchema); err != nil {
-		return compatibility.NewIncompatibleResult("failed to parse new schema: " + err.Error())
+		return invalidSchema("failed to parse new schema: " + err.Error())
 	}
 
 	if err := json.Unmarshal([]byte(writer.Schema), &oldSchema); err != nil {
-		return compatibility.NewIncompatibleResult("failed to parse old schema: " + err.Error())
+		return invalidSchema("failed to parse old schema: " + err.Error())
 	}
 
-	// Build external reference maps from resolved references
-	newExtRefs := buildExternalRefMap(reader.References)
-	oldExtRefs := buildExternalRefMap(writer.References)
-
-	// Resolve $ref references within each schema (local + external)
-	resolveAllRefs(newSchema, newExtRefs)
-	resolveAllRefs(oldSchema, oldExtRefs)
+	// Resolve $ref and $dynamicRef within each schema against its own
+	// definitions and registered references
+	newRefResolver(newSchema, reader.References).resolve(newSchema)
+	newRefResolver(oldSchema, writer.References).resolve(oldSchema)
 
 	result := compatibility.NewCompatibleResult()
 	c.checkCompatibility(newSchema, oldSchema, "", result)
@@ -67,7 +89,7 @@ func (c *Checker) checkCompatibility(newSchema, oldSchema map[string]interface{}
 	oldType := getType(oldSchema)
 
 	if !c.areTypesCompatible(newType, oldType) {
-		result.AddMessage("Type changed at %s from '%v' to '%v'", pathOrRoot(path), oldType, newType)
+		result.AddIncompatibility(compatibility.CodeTypeMismatch, pathOrRoot(path), fragment(newType), fragment(oldType), "Type changed at %s from '%v' to '%v'", pathOrRoot(path), oldType, newType)
 	}
 
 	// Check based on schema type — detect implicit types via keywords
@@ -124,126 +146,6 @@ func (c *Checker) checkCompatibility(newSchema, oldSchema map[string]interface{}
 	c.checkItemsBoolean(newSchema, oldSchema, path, result)
 }
 
-// ==========================================================================
-// $REF RESOLUTION
-// ==========================================================================
-
-// buildExternalRefMap builds a map of reference name → parsed schema from resolved
-// external references. This allows $ref resolution for cross-subject references.
-func buildExternalRefMap(refs []storage.Reference) map[string]map[string]interface{} {
-	if len(refs) == 0 {
-		return nil
-	}
-	result := make(map[string]map[string]interface{}, len(refs))
-	for _, ref := range refs {
-		if ref.Schema == "" {
-			continue
-		}
-		var parsed map[string]interface{}
-		if err := json.Unmarshal([]byte(ref.Schema), &parsed); err == nil {
-			result[ref.Name] = parsed
-		}
-	}
-	if len(result) == 0 {
-		return nil
-	}
-	return result
-}
-
-// resolveAllRefs resolves all $ref references within a schema using both
-// local definitions and external references from other subjects.
-func resolveAllRefs(schema map[string]interface{}, extRefs map[string]map[string]interface{}) {
-	defs := getDefinitions(schema)
-	resolveRefsInMap(schema, defs, extRefs)
-}
-
-// getDefinitions returns the definitions map from a schema.
-func getDefinitions(schema map[string]interface{}) map[string]interface{} {
-	if defs, ok := schema["definitions"].(map[string]interface{}); ok {
-		return defs
-	}
-	if defs, ok := schema["$defs"].(map[string]interface{}); ok {
-		return defs
-	}
-	return nil
-}
-
-// resolveRefsInMap recursively replaces $ref with the referenced definition content.
-// Resolves both local ($ref: "#/definitions/...") and external ($ref: "RefName") references.
-func resolveRefsInMap(schema map[string]interface{}, defs map[string]interface{}, extRefs map[string]map[string]interface{}) {
-	for key, val := range schema {
-		if key == "definitions" || key == "$defs" {
-			continue
-		}
-		switch v := val.(type) {
-		case map[string]interface{}:
-			if ref, ok := v["$ref"].(string); ok {
-				if resolved := resolveRef(ref, defs, extRefs); resolved != nil {
-					schema[key] = resolved
-				}
-			} else {
-				resolveRefsInMap(v, defs, extRefs)
-			}
-		case []interface{}:
-			for i, item := range v {
-				if m, ok := item.(map[string]interface{}); ok {
-					if ref, ok := m["$ref"].(string); ok {
-						if resolved := resolveRef(ref, defs, extRefs); resolved != nil {
-							v[i] = resolved
-						}
-					} else {
-						resolveRefsInMap(m, defs, extRefs)
-					}
-				}
-			}
-		}
-	}
-}
-
-// resolveRef resolves a $ref string, trying local definitions first, then external references.
-func resolveRef(ref string, defs map[string]interface{}, extRefs map[string]map[string]interface{}) map[string]interface{} {
-	// Try local $ref first (e.g., "#/definitions/someRef")
-	if resolved := resolveLocalRef(ref, defs); resolved != nil {
-		return resolved
-	}
-	// Try external references (e.g., "Address" or "com.example.Address")
-	if extRefs != nil {
-		if resolved, ok := extRefs[ref]; ok {
-			// Return a copy to avoid mutation
-			result := make(map[string]interface{}, len(resolved))
-			for k, v := range resolved {
-				result[k] = v
-			}
-			return result
-		}
-	}
-	return nil
-}
-
-// resolveLocalRef resolves a local $ref string to its definition.
-func resolveLocalRef(ref string, defs map[string]interface{}) map[string]interface{} {
-	if defs == nil {
-		return nil
-	}
-	// Handle "#/definitions/name" and "#/$defs/name" patterns
-	for _, prefix := range []string{"#/definitions/", "#/$defs/"} {
-		if strings.HasPrefix(ref, prefix) {
-			name := ref[len(prefix):]
-			if def, ok := defs[name]; ok {
-				if defMap, ok := def.(map[string]interface{}); ok {
-					// Return a copy to avoid mutation
-					result := make(map[string]interface{}, len(defMap))
-					for k, v := range defMap {
-						result[k] = v
-					}
-					return result
-				}
-			}
-		}
-	}
-	return nil
-}
-
 // ==========================================================================
 // IMPLICIT TYPE DETECTION
 // ==========================================================================
@@ -316,11 +218,11 @@ func (c *Checker) checkObjectCompatibility(newSchema, oldSchema map[string]inter
 						localResult := compatibility.NewCompatibleResult()
 						c.checkCompatibility(readerAPSchema, oldPropMap, propPath, localResult)
 						if !localResult.IsCompatible {
-							result.AddMessage("Property '%s' removed but not covered by additionalProperties", propPath)
+							result.AddIncompatibility(compatibility.CodePropertyRemoved, propPath, fragment(readerAPSchema), fragment(oldProps[propName]), "Property '%s' removed but not covered by additionalProperties", propPath)
 						}
 					}
 				} else {
-					result.AddMessage("Property '%s' was removed", propPath)
+					result.AddIncompatibility(compatibility.CodePropertyRemoved, propPath, "", fragment(oldProps[propName]), "Property '%s' was removed", propPath)
 				}
 			}
 		}
@@ -339,11 +241,11 @@ func (c *Checker) checkObjectCompatibility(newSchema, oldSchema map[string]inter
 			}
 			if isRequired {
 				// New required property added — always incompatible for backward compat
-				result.AddMessage("New required property '%s' was added", propPath)
+				result.AddIncompatibility(compatibility.CodeRequiredFieldAdded, propPath, fragment(newProps[propName]), "", "New required property '%s' was added", propPath)
 			} else if hasOpenContentModel(oldSchema) {
 				// Open content model: old writer could have used this property name
 				// with any type, conflicting with the new typed constraint
-				result.AddMessage("Property '%s' was added to open content model", propPath)
+				result.AddIncompatibility(compatibility.CodePropertyAdded, propPath, fragment(newProps[propName]), "", "Property '%s' was added to open content model", propPath)
 			} else if getAdditionalPropertiesSchema(oldSchema) != nil {
 				// Partially open: check if new property type matches the AP schema
 				newPropMap, newOk := newProps[propName].(map[string]interface{})
@@ -352,14 +254,14 @@ func (c *Checker) checkObjectCompatibility(newSchema, oldSchema map[string]inter
 					localResult := compatibility.NewCompatibleResult()
 					c.checkCompatibility(newPropMap, apSchema, propPath, localResult)
 					if !localResult.IsCompatible {
-						result.AddMessage("Property '%s' added with type incompatible with additionalProperties", propPath)
+						result.AddIncompatibility(compatibility.CodePropertyAdded, propPath, fragment(newPropMap), fragment(apSchema), "Property '%s' added with type incompatible with additionalProperties", propPath)
 					}
 				}
 			}
 			// Closed model (additionalProperties:false) + non-required → compatible
 			// (old writer couldn't produce this property)
 		} else if !oldRequired[propName] && isRequired {
-			result.AddMessage("Property '%s' changed from optional to required", propPath)
+			result.AddIncompatibility(compatibility.CodeRequiredFieldAdded, propPath, fragment(newProps[propName]), fragment(oldProps[propName]), "Property '%s' changed from optional to required", propPath)
 		}
 	}
 
@@ -416,7 +318,7 @@ func (c *Checker) checkArrayCompatibility(newSchema, oldSchema map[string]interf
 		_, oldHasItems := oldSchema["items"]
 		if !oldHasItems {
 			// Adding items constraint to unconstrained array — more restrictive
-			result.AddMessage("items schema added at '%s'", pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "items"), fragment(newItems), "", "items schema added at '%s'", pathOrRoot(path))
 		}
 	}
 
@@ -468,7 +370,7 @@ func (c *Checker) checkTupleItems(newSchema, oldSchema map[string]interface{}, p
 				localResult := compatibility.NewCompatibleResult()
 				c.checkCompatibility(newItem, oldAISchema, joinPath(path, fmt.Sprintf("items/%d", i)), localResult)
 				if !localResult.IsCompatible {
-					result.AddMessage("Item added at position %d not covered by additionalItems", i)
+					result.AddIncompatibility(compatibility.CodeItemAdded, joinPath(path, fmt.Sprintf("items/%d", i)), fragment(newItem), fragment(oldAISchema), "Item added at position %d not covered by additionalItems", i)
 				}
 			}
 		}
@@ -486,7 +388,7 @@ func (c *Checker) checkTupleItems(newSchema, oldSchema map[string]interface{}, p
 				localResult := compatibility.NewCompatibleResult()
 				c.checkCompatibility(newAISchema, oldItem, joinPath(path, fmt.Sprintf("items/%d", i)), localResult)
 				if !localResult.IsCompatible {
-					result.AddMessage("Item removed at position %d not covered by additionalItems", i)
+					result.AddIncompatibility(compatibility.CodeItemRemoved, joinPath(path, fmt.Sprintf("items/%d", i)), fragment(newAISchema), fragment(oldItem), "Item removed at position %d not covered by additionalItems", i)
 				}
 			}
 		}
@@ -535,7 +437,7 @@ func (c *Checker) checkEnumCompatibility(newSchema, oldSchema map[string]interfa
 
 	if oldEnum == nil && newEnum != nil {
 		// Enum constraint added — more restrictive
-		result.AddMessage("Enum constraint added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, pathOrRoot(path), fragment(newEnum), "", "Enum constraint added at '%s'", pathOrRoot(path))
 		return
 	}
 
@@ -561,7 +463,7 @@ func (c *Checker) checkEnumCompatibility(newSchema, oldSchema map[string]interfa
 
 	for oldVal := range oldEnumSet {
 		if !newEnumSet[oldVal] {
-			result.AddMessage("Enum value '%s' was removed at '%s'", oldVal, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeEnumSymbolRemoved, pathOrRoot(path), fragment(newEnum), oldVal, "Enum value '%s' was removed at '%s'", oldVal, pathOrRoot(path))
 		}
 	}
 }
@@ -587,13 +489,13 @@ func (c *Checker) checkConstCompatibility(newSchema, oldSchema map[string]interf
 
 	if !oldHas && newHas {
 		// Adding const constraint — more restrictive
-		result.AddMessage("const constraint added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, pathOrRoot(path), fragment(newConst), "", "const constraint added at '%s'", pathOrRoot(path))
 		return
 	}
 
 	// Both have const — check if values differ
 	if !reflect.DeepEqual(oldConst, newConst) {
-		result.AddMessage("const value changed at '%s' from '%v' to '%v'", pathOrRoot(path), oldConst, newConst)
+		result.AddIncompatibility(compatibility.CodeConstraintChanged, pathOrRoot(path), fragment(newConst), fragment(oldConst), "const value changed at '%s' from '%v' to '%v'", pathOrRoot(path), oldConst, newConst)
 	}
 }
 
@@ -608,7 +510,7 @@ func (c *Checker) checkAdditionalPropertiesCompatibility(newSchema, oldSchema ma
 
 	// If old schema allowed additional properties and new doesn't
 	if (!oldHasAP || oldAP == true) && newHasAP && newAP == false {
-		result.AddMessage("additionalProperties changed from allowed to forbidden at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeTypeNarrowed, joinPath(path, "additionalProperties"), fragment(newAP), fragment(oldAP), "additionalProperties changed from allowed to forbidden at '%s'", pathOrRoot(path))
 	}
 
 	// If old allowed additional properties schema and new narrows it
@@ -617,7 +519,7 @@ func (c *Checker) checkAdditionalPropertiesCompatibility(newSchema, oldSchema ma
 			c.checkCompatibility(newAPSchema, oldAPSchema, joinPath(path, "additionalProperties"), result)
 		} else if !oldHasAP || oldAP == true {
 			// Old was unrestricted, new has schema constraint — narrowing
-			result.AddMessage("additionalProperties narrowed at '%s'", pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeTypeNarrowed, joinPath(path, "additionalProperties"), fragment(newAPSchema), fragment(oldAP), "additionalProperties narrowed at '%s'", pathOrRoot(path))
 		}
 	}
 }
@@ -663,7 +565,7 @@ func (c *Checker) checkCompositionSubschemas(newSchema, oldSchema map[string]int
 				localResult := compatibility.NewCompatibleResult()
 				c.checkCompatibility(newElem, oldElem, path, localResult)
 				if !localResult.IsCompatible {
-					result.AddMessage("Composed schema element changed at '%s'", pathOrRoot(path))
+					result.AddIncompatibility(compatibility.CodeCompositionChanged, pathOrRoot(path), fragment(newElem), fragment(oldElem), "Composed schema element changed at '%s'", pathOrRoot(path))
 					return
 				}
 			}
@@ -691,7 +593,7 @@ func (c *Checker) checkSumTypeCompatibility(newSchema, oldSchema map[string]inte
 			if oldType == "" {
 				oldType = "schema"
 			}
-			result.AddMessage("Type option '%s' removed at '%s'", oldType, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeTypeNarrowed, pathOrRoot(path), "", fragment(oldOpt), "Type option '%s' removed at '%s'", oldType, pathOrRoot(path))
 		}
 	}
 }
@@ -792,7 +694,8 @@ func (c *Checker) checkAllOfCompatibility(newSchema, oldSchema map[string]interf
 				}
 			}
 			if !schemaSubsumedBy(newElem, oldSchema) {
-				result.AddMessage("New constraint added to allOf at '%s'", pathOrRoot(path))
+				result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "allOf"), fragment(newElem), "",
+					"New constraint added to allOf at '%s'", pathOrRoot(path))
 				return
 			}
 		}
@@ -829,9 +732,7 @@ func (c *Checker) checkAllOfCompatibility(newSchema, oldSchema map[string]interf
 					localResult := compatibility.NewCompatibleResult()
 					c.checkEnumCompatibility(newElem, oldElem, path, localResult)
 					if !localResult.IsCompatible {
-						for _, msg := range localResult.Messages {
-							result.AddMessage("%s", msg)
-						}
+						result.Merge(localResult)
 					}
 					found = true
 					break
@@ -845,7 +746,8 @@ func (c *Checker) checkAllOfCompatibility(newSchema, oldSchema map[string]interf
 		if c.hasMatchingElement(newElem, oldDeduped) {
 			continue
 		}
-		result.AddMessage("New constraint added to allOf at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "allOf"), fragment(newElem), "",
+			"New constraint added to allOf at '%s'", pathOrRoot(path))
 	}
 
 	// Check type changes within matching allOf elements
@@ -862,7 +764,7 @@ func (c *Checker) checkAllOfCompatibility(newSchema, oldSchema map[string]interf
 					_, oldHasType := oldElem["type"]
 					_, newHasType := newElem["type"]
 					if oldHasType && newHasType && len(oldElem) == 1 && len(newElem) == 1 {
-						result.AddMessage("Type changed in allOf at '%s' from '%s' to '%s'", pathOrRoot(path), oldType, newType)
+						result.AddIncompatibility(compatibility.CodeTypeMismatch, joinPath(path, "allOf"), fragment(newElem), fragment(oldElem), "Type changed in allOf at '%s' from '%s' to '%s'", pathOrRoot(path), oldType, newType)
 					}
 				}
 			}
@@ -885,10 +787,19 @@ func (c *Checker) checkStringConstraints(newSchema, oldSchema map[string]interfa
 	oldPattern, oldHas := oldSchema["pattern"]
 	newPattern, newHas := newSchema["pattern"]
 
+	// Whether one regular expression accepts everything another does cannot
+	// be decided in general, so lenient checking only warns about it
+	report := func(code, format string, args ...interface{}) {
+		if c.lenient {
+			result.AddWarning(format, args...)
+			return
+		}
+		result.AddIncompatibility(code, joinPath(path, "pattern"), fragment(newPattern), fragment(oldPattern), format, args...)
+	}
 	if oldHas && newHas && oldPattern != newPattern {
-		result.AddMessage("pattern changed at '%s' from '%v' to '%v'", pathOrRoot(path), oldPattern, newPattern)
+		report(compatibility.CodeConstraintChanged, "pattern changed at '%s' from '%v' to '%v'", pathOrRoot(path), oldPattern, newPattern)
 	} else if !oldHas && newHas {
-		result.AddMessage("pattern constraint added at '%s'", pathOrRoot(path))
+		report(compatibility.CodeConstraintAdded, "pattern constraint added at '%s'", pathOrRoot(path))
 	}
 	// Removing pattern is compatible (less restrictive)
 }
@@ -918,11 +829,11 @@ func (c *Checker) checkNumericConstraints(newSchema, oldSchema map[string]interf
 		if oldVal != 0 && newVal != 0 {
 			ratio := oldVal / newVal
 			if math.Abs(ratio-math.Round(ratio)) > 1e-9 {
-				result.AddMessage("multipleOf changed at '%s' from %v to %v", pathOrRoot(path), oldMul, newMul)
+				result.AddIncompatibility(compatibility.CodeConstraintChanged, joinPath(path, "multipleOf"), fragment(newMul), fragment(oldMul), "multipleOf changed at '%s' from %v to %v", pathOrRoot(path), oldMul, newMul)
 			}
 		}
 	} else if !oldHas && newHas {
-		result.AddMessage("multipleOf constraint added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "multipleOf"), fragment(newMul), "", "multipleOf constraint added at '%s'", pathOrRoot(path))
 	}
 }
 
@@ -950,7 +861,7 @@ func (c *Checker) checkNotSchema(newSchema, oldSchema map[string]interface{}, pa
 	}
 
 	if !oldHas && newHas {
-		result.AddMessage("'not' constraint added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "not"), fragment(newNot), "", "'not' constraint added at '%s'", pathOrRoot(path))
 		return
 	}
 
@@ -967,13 +878,13 @@ func (c *Checker) checkNotSchema(newSchema, oldSchema map[string]interface{}, pa
 
 		if oldNotType != "" && newNotType != "" && oldNotType != newNotType {
 			if !isTypePromotion(newNotType, oldNotType) {
-				result.AddMessage("'not' schema changed at '%s' from '%s' to '%s'", pathOrRoot(path), oldNotType, newNotType)
+				result.AddIncompatibility(compatibility.CodeConstraintChanged, joinPath(path, "not"), fragment(newNotMap), fragment(oldNotMap), "'not' schema changed at '%s' from '%s' to '%s'", pathOrRoot(path), oldNotType, newNotType)
 			}
 		}
 
 		if !reflect.DeepEqual(oldNotMap, newNotMap) && oldNotType == newNotType {
 			if len(newNotMap) < len(oldNotMap) {
-				result.AddMessage("'not' schema broadened at '%s'", pathOrRoot(path))
+				result.AddIncompatibility(compatibility.CodeTypeNarrowed, joinPath(path, "not"), fragment(newNotMap), fragment(oldNotMap), "'not' schema broadened at '%s'", pathOrRoot(path))
 			}
 		}
 	}
@@ -993,7 +904,7 @@ func (c *Checker) checkDependencies(newSchema, oldSchema map[string]interface{},
 	}
 
 	if !oldHas && newHas {
-		result.AddMessage("dependencies added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "dependencies"), fragment(newDeps), "", "dependencies added at '%s'", pathOrRoot(path))
 		return
 	}
 
@@ -1010,7 +921,7 @@ func (c *Checker) checkDependencies(newSchema, oldSchema map[string]interface{},
 	// Check for added dependencies
 	for propName := range newDepsMap {
 		if _, exists := oldDepsMap[propName]; !exists {
-			result.AddMessage("dependency added for property '%s' at '%s'", propName, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "dependencies/"+propName), fragment(newDepsMap[propName]), "", "dependency added for property '%s' at '%s'", propName, pathOrRoot(path))
 		}
 	}
 
@@ -1021,7 +932,7 @@ func (c *Checker) checkDependencies(newSchema, oldSchema map[string]interface{},
 			if _, isSchema := oldDep.(map[string]interface{}); isSchema {
 				continue // Schema dependency removed — compatible
 			}
-			result.AddMessage("dependency removed for property '%s' at '%s'", propName, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintRemoved, joinPath(path, "dependencies/"+propName), "", fragment(oldDep), "dependency removed for property '%s' at '%s'", propName, pathOrRoot(path))
 			continue
 		}
 
@@ -1031,7 +942,7 @@ func (c *Checker) checkDependencies(newSchema, oldSchema map[string]interface{},
 		if oldIsSchema && newIsSchema {
 			c.checkCompatibility(newDepSchema, oldDepSchema, joinPath(path, "dependencies/"+propName), result)
 		} else if !reflect.DeepEqual(oldDep, newDep) {
-			result.AddMessage("dependency changed for property '%s' at '%s'", propName, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintChanged, joinPath(path, "dependencies/"+propName), fragment(newDep), fragment(oldDep), "dependency changed for property '%s' at '%s'", propName, pathOrRoot(path))
 		}
 	}
 }
@@ -1050,7 +961,7 @@ func (c *Checker) checkDependentRequired(newSchema, oldSchema map[string]interfa
 	}
 
 	if !oldHas && newHas {
-		result.AddMessage("dependentRequired added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "dependentRequired"), fragment(newDeps), "", "dependentRequired added at '%s'", pathOrRoot(path))
 		return
 	}
 
@@ -1067,14 +978,14 @@ func (c *Checker) checkDependentRequired(newSchema, oldSchema map[string]interfa
 	// Check for added dependency keys
 	for propName := range newDepsMap {
 		if _, exists := oldDepsMap[propName]; !exists {
-			result.AddMessage("dependentRequired added for property '%s' at '%s'", propName, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "dependentRequired/"+propName), fragment(newDepsMap[propName]), "", "dependentRequired added for property '%s' at '%s'", propName, pathOrRoot(path))
 		}
 	}
 
 	// Check for removed dependency keys
 	for propName := range oldDepsMap {
 		if _, exists := newDepsMap[propName]; !exists {
-			result.AddMessage("dependentRequired removed for property '%s' at '%s'", propName, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintRemoved, joinPath(path, "dependentRequired/"+propName), "", fragment(oldDepsMap[propName]), "dependentRequired removed for property '%s' at '%s'", propName, pathOrRoot(path))
 		}
 	}
 
@@ -1085,7 +996,7 @@ func (c *Checker) checkDependentRequired(newSchema, oldSchema map[string]interfa
 			continue // Already handled above
 		}
 		if !reflect.DeepEqual(oldDep, newDep) {
-			result.AddMessage("dependentRequired changed for property '%s' at '%s'", propName, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintChanged, joinPath(path, "dependentRequired/"+propName), fragment(newDep), fragment(oldDep), "dependentRequired changed for property '%s' at '%s'", propName, pathOrRoot(path))
 		}
 	}
 }
@@ -1104,7 +1015,7 @@ func (c *Checker) checkDependentSchemas(newSchema, oldSchema map[string]interfac
 	}
 
 	if !oldHas && newHas {
-		result.AddMessage("dependentSchemas added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "dependentSchemas"), fragment(newDeps), "", "dependentSchemas added at '%s'", pathOrRoot(path))
 		return
 	}
 
@@ -1121,7 +1032,7 @@ func (c *Checker) checkDependentSchemas(newSchema, oldSchema map[string]interfac
 	// Check for added dependency keys
 	for propName := range newDepsMap {
 		if _, exists := oldDepsMap[propName]; !exists {
-			result.AddMessage("dependentSchema added for property '%s' at '%s'", propName, pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "dependentSchemas/"+propName), fragment(newDepsMap[propName]), "", "dependentSchema added for property '%s' at '%s'", propName, pathOrRoot(path))
 		}
 	}
 
@@ -1160,7 +1071,7 @@ func (c *Checker) checkUniqueItems(newSchema, oldSchema map[string]interface{},
 		return
 	}
 	if newHas && newVal == true && (!oldHas || oldVal != true) {
-		result.AddMessage("uniqueItems constraint added at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeConstraintAdded, joinPath(path, "uniqueItems"), fragment(newVal), fragment(oldVal), "uniqueItems constraint added at '%s'", pathOrRoot(path))
 	}
 }
 
@@ -1174,7 +1085,7 @@ func (c *Checker) checkAdditionalItems(newSchema, oldSchema map[string]interface
 	oldAI, oldHasAI := oldSchema["additionalItems"]
 
 	if (!oldHasAI || oldAI == true) && newHasAI && newAI == false {
-		result.AddMessage("additionalItems changed from allowed to forbidden at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeTypeNarrowed, joinPath(path, "additionalItems"), fragment(newAI), fragment(oldAI), "additionalItems changed from allowed to forbidden at '%s'", pathOrRoot(path))
 	}
 
 	if newAISchema, newOk := newAI.(map[string]interface{}); newOk {
@@ -1201,14 +1112,14 @@ func (c *Checker) checkItemsBoolean(newSchema, oldSchema map[string]interface{},
 	if oldHas && newHas && oldIsBool && newIsBool {
 		if oldBool && !newBool {
 			// items: true → items: false = closing the model = incompatible
-			result.AddMessage("items changed from allowed to forbidden at '%s'", pathOrRoot(path))
+			result.AddIncompatibility(compatibility.CodeTypeNarrowed, joinPath(path, "items"), fragment(newItems), fragment(oldItems), "items changed from allowed to forbidden at '%s'", pathOrRoot(path))
 		}
 	} else if oldHas && newHas && oldIsBool && oldBool && !newIsBool {
 		// items: true → items: {schema} = narrowing = could be incompatible
 		// but we handle this in checkArrayCompatibility via getItems
 	} else if oldHas && newHas && !oldIsBool && newIsBool && !newBool {
 		// items: {schema} → items: false = closing the model
-		result.AddMessage("items changed from schema to forbidden at '%s'", pathOrRoot(path))
+		result.AddIncompatibility(compatibility.CodeTypeNarrowed, joinPath(path, "items"), fragment(newItems), fragment(oldItems), "items changed from schema to forbidden at '%s'", pathOrRoot(path))
 	}
 }
 
@@ -1230,11 +1141,11 @@ func (c *Checker) checkConstraintChange(newSchema, oldSchema map[string]interfac
 
 	if isMinConstraint {
 		if newHas && (!oldHas || newNum > oldNum) {
-			result.AddMessage("'%s' constraint tightened at '%s' (was %v, now %v)", constraint, pathOrRoot(path), oldVal, newVal)
+			result.AddIncompatibility(compatibility.CodeConstraintTightened, joinPath(path, constraint), fragment(newVal), fragment(oldVal), "'%s' constraint tightened at '%s' (was %v, now %v)", constraint, pathOrRoot(path), oldVal, newVal)
 		}
 	} else {
 		if newHas && (!oldHas || newNum < oldNum) {
-			result.AddMessage("'%s' constraint tightened at '%s' (was %v, now %v)", constraint, pathOrRoot(path), oldVal, newVal)
+			result.AddIncompatibility(compatibility.CodeConstraintTightened, joinPath(path, constraint), fragment(newVal), fragment(oldVal), "'%s' constraint tightened at '%s' (was %v, now %v)", constraint, pathOrRoot(path), oldVal, newVal)
 		}
 	}
 }
@@ -1475,6 +1386,29 @@ func joinPath(base, prop string) string {
 	return base + "." + prop
 }
 
+// fragment renders a schema fragment or keyword value as JSON for an
+// incompatibility report, or "" if there is none.
+func fragment(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// invalidSchema returns an incompatible result for a schema that failed to parse.
+func invalidSchema(msg string) *compatibility.Result {
+	result := compatibility.NewCompatibleResult()
+	result.AddIncompatibility(compatibility.CodeSchemaInvalid, "", "", "", "%s", msg)
+	return result
+}
+
 func pathOrRoot(path string) string {
 	if path == "" {
 		return "root"
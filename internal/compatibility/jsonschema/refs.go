@@ -0,0 +1,365 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// maxRefExpansions bounds the number of references inlined into one schema,
+// so that a schema whose definitions reference each other many times over
+// cannot make a compatibility check expand without limit.
+const maxRefExpansions = 10000
+
+// refResolver inlines $ref and $dynamicRef targets into a schema so that
+// referenced definitions are compared like inline ones. Targets are found in
+// the schema itself (JSON pointers, embedded $id resources, $anchor and
+// $dynamicAnchor) and in the registered references of the schema, which are
+// known by their reference name and by their own $id. Nothing is ever
+// fetched: a reference that matches none of these is left in place.
+type refResolver struct {
+	// resources maps the URI of each schema resource (the root, every
+	// registered reference, and every subschema with an $id) to its schema.
+	resources map[string]map[string]interface{}
+	// anchors maps "<resource URI>#<name>" to the subschema declaring the
+	// $anchor or $dynamicAnchor name.
+	anchors map[string]map[string]interface{}
+	// dynamicAnchors maps a $dynamicAnchor name to its outermost declaration:
+	// the checked schema's own declaration wins over a reference's.
+	dynamicAnchors map[string]map[string]interface{}
+	// bases maps each indexed subschema to the URI of its resource.
+	bases map[uintptr]string
+
+	expansions int
+}
+
+// newRefResolver indexes a schema and its registered references. The
+// resolver keeps its own copies, so resolving references in root does not
+// change what they resolve to.
+func newRefResolver(root map[string]interface{}, refs []storage.Reference) *refResolver {
+	r := &refResolver{
+		resources:      make(map[string]map[string]interface{}),
+		anchors:        make(map[string]map[string]interface{}),
+		dynamicAnchors: make(map[string]map[string]interface{}),
+		bases:          make(map[uintptr]string),
+	}
+	// The checked schema is indexed first so that its dynamic anchors are
+	// the outermost ones.
+	rootCopy := deepCopy(root).(map[string]interface{})
+	r.resources[""] = rootCopy
+	r.index(rootCopy, "")
+
+	for _, ref := range refs {
+		if ref.Schema == "" {
+			continue
+		}
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(ref.Schema), &parsed); err != nil {
+			continue
+		}
+		if _, exists := r.resources[ref.Name]; !exists {
+			r.resources[ref.Name] = parsed
+		}
+		r.index(parsed, ref.Name)
+	}
+	return r
+}
+
+// index records the resources and anchors declared in schema, whose
+// resource URI is base.
+func (r *refResolver) index(schema map[string]interface{}, base string) {
+	if id, ok := schema["$id"].(string); ok {
+		if anchor, isAnchor := strings.CutPrefix(id, "#"); isAnchor {
+			// Draft-07 plain-name fragment: "$id": "#address"
+			r.addAnchor(base, anchor, schema)
+		} else if uri, ok := resolveURI(base, id); ok {
+			base = stripFragment(uri)
+			if _, exists := r.resources[base]; !exists {
+				r.resources[base] = schema
+			}
+		}
+	}
+	r.bases[mapID(schema)] = base
+
+	if anchor, ok := schema["$anchor"].(string); ok {
+		r.addAnchor(base, anchor, schema)
+	}
+	if anchor, ok := schema["$dynamicAnchor"].(string); ok {
+		r.addAnchor(base, anchor, schema)
+		if _, exists := r.dynamicAnchors[anchor]; !exists {
+			r.dynamicAnchors[anchor] = schema
+		}
+	}
+
+	for key, val := range schema {
+		if isValueKeyword(key) {
+			continue
+		}
+		r.indexValue(val, base)
+	}
+}
+
+func (r *refResolver) indexValue(val interface{}, base string) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		r.index(v, base)
+	case []interface{}:
+		for _, item := range v {
+			r.indexValue(item, base)
+		}
+	}
+}
+
+func (r *refResolver) addAnchor(base, name string, schema map[string]interface{}) {
+	key := base + "#" + name
+	if _, exists := r.anchors[key]; !exists {
+		r.anchors[key] = schema
+	}
+}
+
+// resolve replaces every resolvable $ref and $dynamicRef in schema with a copy
+// of its target. Definitions are not compared themselves, so they are left
+// as they are. A reference back into a definition that is already being
+// inlined is left in place, which keeps recursive schemas finite.
+func (r *refResolver) resolve(schema map[string]interface{}) {
+	resolved := r.resolveSchema(schema, "", nil)
+	if mapID(resolved) == mapID(schema) {
+		return
+	}
+	// The root itself was a reference
+	for key := range schema {
+		delete(schema, key)
+	}
+	for key, val := range resolved {
+		schema[key] = val
+	}
+}
+
+func (r *refResolver) resolveIn(schema map[string]interface{}, base string, chain []uintptr) {
+	base = schemaBase(schema, base)
+	for key, val := range schema {
+		if key == "definitions" || key == "$defs" || isValueKeyword(key) {
+			continue
+		}
+		switch v := val.(type) {
+		case map[string]interface{}:
+			schema[key] = r.resolveSchema(v, base, chain)
+		case []interface{}:
+			for i, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					v[i] = r.resolveSchema(m, base, chain)
+				}
+			}
+		}
+	}
+}
+
+// resolveSchema returns the schema to compare in place of schema: a resolved
+// copy of its reference target, or schema itself with its own references
+// resolved.
+func (r *refResolver) resolveSchema(schema map[string]interface{}, base string, chain []uintptr) map[string]interface{} {
+	target, targetBase := r.lookup(schema, schemaBase(schema, base))
+	if target == nil {
+		r.resolveIn(schema, base, chain)
+		return schema
+	}
+	id := mapID(target)
+	for _, seen := range chain {
+		if seen == id {
+			return schema
+		}
+	}
+	if r.expansions >= maxRefExpansions {
+		return schema
+	}
+	r.expansions++
+
+	resolved := deepCopy(target).(map[string]interface{})
+	r.resolveIn(resolved, targetBase, append(chain, id))
+
+	// Keywords next to the reference apply alongside it, as in draft 2019-09
+	// and later; they are kept over the target's own.
+	r.resolveIn(schema, base, chain)
+	for key, val := range schema {
+		if key != "$ref" && key != "$dynamicRef" {
+			resolved[key] = val
+		}
+	}
+	return resolved
+}
+
+// lookup finds the target of the $ref or $dynamicRef in schema, and the URI
+// of the resource it belongs to. It returns nil if schema has neither or the
+// target is unknown.
+func (r *refResolver) lookup(schema map[string]interface{}, base string) (map[string]interface{}, string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		return r.lookupRef(ref, base)
+	}
+	ref, ok := schema["$dynamicRef"].(string)
+	if !ok {
+		return nil, ""
+	}
+
+	// A $dynamicRef resolves like a $ref unless it lands on a matching
+	// $dynamicAnchor, in which case the outermost schema declaring that
+	// anchor wins. Only the schema being checked and its references are in
+	// scope, so the outermost declaration is known up front.
+	name := ""
+	if i := strings.LastIndexByte(ref, '#'); i >= 0 && !strings.HasPrefix(ref[i+1:], "/") {
+		name = ref[i+1:]
+	}
+	target, targetBase := r.lookupRef(ref, base)
+	if name == "" {
+		return target, targetBase
+	}
+	if target != nil {
+		if anchor, _ := target["$dynamicAnchor"].(string); anchor != name {
+			return target, targetBase
+		}
+	}
+	if outermost, ok := r.dynamicAnchors[name]; ok {
+		return outermost, r.bases[mapID(outermost)]
+	}
+	return target, targetBase
+}
+
+// lookupRef resolves a reference against base.
+func (r *refResolver) lookupRef(ref, base string) (map[string]interface{}, string) {
+	// A registered reference may be named by something that is not a URI
+	// relative to base, such as "com.example.Address".
+	if doc, ok := r.resources[ref]; ok && !strings.HasPrefix(ref, "#") {
+		return doc, r.bases[mapID(doc)]
+	}
+
+	uri, ok := resolveURI(base, ref)
+	if !ok {
+		return nil, ""
+	}
+	docURI, fragment, _ := strings.Cut(uri, "#")
+	doc, ok := r.resources[docURI]
+	if !ok {
+		// A relative name of a registered reference, e.g. "address.json"
+		// from a schema whose $id is https://example.com/order.json.
+		name, _, _ := strings.Cut(ref, "#")
+		if doc, ok = r.resources[name]; !ok {
+			return nil, ""
+		}
+		docURI = r.bases[mapID(doc)]
+	}
+
+	switch {
+	case fragment == "":
+		return doc, r.bases[mapID(doc)]
+	case strings.HasPrefix(fragment, "/"):
+		target := evalPointer(doc, fragment)
+		if target == nil {
+			return nil, ""
+		}
+		if b, ok := r.bases[mapID(target)]; ok {
+			return target, b
+		}
+		return target, docURI
+	default:
+		target, ok := r.anchors[docURI+"#"+fragment]
+		if !ok {
+			return nil, ""
+		}
+		return target, r.bases[mapID(target)]
+	}
+}
+
+// schemaBase returns the URI that references in schema resolve against: its
+// own $id, or otherwise base.
+func schemaBase(schema map[string]interface{}, base string) string {
+	if id, ok := schema["$id"].(string); ok && !strings.HasPrefix(id, "#") {
+		if uri, ok := resolveURI(base, id); ok {
+			return stripFragment(uri)
+		}
+	}
+	return base
+}
+
+// resolveURI resolves ref against base. Both may be relative.
+func resolveURI(base, ref string) (string, bool) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", false
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", false
+	}
+	return baseURL.ResolveReference(refURL).String(), true
+}
+
+func stripFragment(uri string) string {
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		return uri[:i]
+	}
+	return uri
+}
+
+// evalPointer evaluates a JSON pointer (RFC 6901) taken from a URI fragment
+// against doc, returning nil unless it points at a schema object.
+func evalPointer(doc map[string]interface{}, pointer string) map[string]interface{} {
+	var cur interface{} = doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			cur = v[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			cur = v[i]
+		default:
+			return nil
+		}
+	}
+	m, _ := cur.(map[string]interface{})
+	return m
+}
+
+// isValueKeyword reports whether a keyword holds data rather than subschemas,
+// so that e.g. an enum value that happens to contain "$ref" is left alone.
+func isValueKeyword(key string) bool {
+	switch key {
+	case "enum", "const", "default", "examples":
+		return true
+	}
+	return false
+}
+
+// mapID identifies a schema object by its address.
+func mapID(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+// deepCopy copies a value decoded from JSON.
+func deepCopy(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = deepCopy(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = deepCopy(item)
+		}
+		return s
+	default:
+		return val
+	}
+}
@@ -0,0 +1,204 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func TestChecker_LocalRef_ComparesDefinition(t *testing.T) {
+	checker := NewChecker()
+
+	oldSchema := `{
+		"type": "object",
+		"properties": {"address": {"$ref": "#/$defs/address"}},
+		"$defs": {"address": {"type": "object", "properties": {"zip": {"type": "string"}}}}
+	}`
+	newSchema := `{
+		"type": "object",
+		"properties": {"address": {"$ref": "#/$defs/address"}},
+		"$defs": {"address": {"type": "object", "properties": {"zip": {"type": "integer"}}}}
+	}`
+
+	result := checker.Check(s(newSchema), s(oldSchema))
+	if result.IsCompatible {
+		t.Error("Expected incompatible: type of referenced property changed")
+	}
+}
+
+func TestChecker_IDRef_EmbeddedResource(t *testing.T) {
+	checker := NewChecker()
+
+	// The $ref is relative to the root $id and names an embedded resource by its own $id
+	schema := func(zipType string) string {
+		return `{
+			"$id": "https://example.com/schemas/order.json",
+			"type": "object",
+			"properties": {"address": {"$ref": "address.json"}},
+			"$defs": {
+				"address": {
+					"$id": "https://example.com/schemas/address.json",
+					"type": "object",
+					"properties": {"zip": {"$ref": "#/$defs/zip"}},
+					"$defs": {"zip": {"type": "` + zipType + `"}}
+				}
+			}
+		}`
+	}
+
+	result := checker.Check(s(schema("string")), s(schema("string")))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible, got messages: %v", result.Messages)
+	}
+
+	result = checker.Check(s(schema("integer")), s(schema("string")))
+	if result.IsCompatible {
+		t.Error("Expected incompatible: the pointer must resolve within the embedded resource")
+	}
+}
+
+func TestChecker_IDRef_Anchor(t *testing.T) {
+	checker := NewChecker()
+
+	schema := func(zipType string) string {
+		return `{
+			"type": "object",
+			"properties": {"zip": {"$ref": "#zip"}},
+			"$defs": {"zip": {"$anchor": "zip", "type": "` + zipType + `"}}
+		}`
+	}
+
+	result := checker.Check(s(schema("integer")), s(schema("string")))
+	if result.IsCompatible {
+		t.Error("Expected incompatible: type of anchored definition changed")
+	}
+}
+
+func TestChecker_ExternalRef_MatchedByID(t *testing.T) {
+	checker := NewChecker()
+
+	// The reference is registered as "address.json" but referred to by its $id
+	reference := func(zipType string) storage.Reference {
+		return storage.Reference{
+			Name: "address.json",
+			Schema: `{
+				"$id": "https://example.com/schemas/address.json",
+				"type": "object",
+				"properties": {"zip": {"type": "` + zipType + `"}}
+			}`,
+		}
+	}
+	root := `{
+		"$id": "https://example.com/schemas/order.json",
+		"type": "object",
+		"properties": {"address": {"$ref": "https://example.com/schemas/address.json"}}
+	}`
+
+	result := checker.Check(
+		compatibility.SchemaWithRefs{Schema: root, References: []storage.Reference{reference("integer")}},
+		compatibility.SchemaWithRefs{Schema: root, References: []storage.Reference{reference("string")}},
+	)
+	if result.IsCompatible {
+		t.Error("Expected incompatible: type changed in reference resolved by $id")
+	}
+}
+
+func TestChecker_ExternalRef_UnknownIsNotFetched(t *testing.T) {
+	checker := NewChecker()
+
+	schema := `{
+		"type": "object",
+		"properties": {"address": {"$ref": "https://example.com/schemas/address.json"}}
+	}`
+
+	result := checker.Check(s(schema), s(schema))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible, got messages: %v", result.Messages)
+	}
+}
+
+func TestChecker_DynamicRef_ResolvesToOutermostAnchor(t *testing.T) {
+	checker := NewChecker()
+
+	// A generic list whose item type is bound by the referencing schema
+	list := storage.Reference{
+		Name: "list.json",
+		Schema: `{
+			"$id": "https://example.com/schemas/list.json",
+			"type": "array",
+			"items": {"$dynamicRef": "#item"},
+			"$defs": {"item": {"$dynamicAnchor": "item", "not": true}}
+		}`,
+	}
+	schema := func(itemType string) string {
+		return `{
+			"$id": "https://example.com/schemas/strings.json",
+			"$ref": "list.json",
+			"$defs": {"item": {"$dynamicAnchor": "item", "type": "` + itemType + `"}}
+		}`
+	}
+
+	result := checker.Check(
+		compatibility.SchemaWithRefs{Schema: schema("string"), References: []storage.Reference{list}},
+		compatibility.SchemaWithRefs{Schema: schema("string"), References: []storage.Reference{list}},
+	)
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible, got messages: %v", result.Messages)
+	}
+
+	result = checker.Check(
+		compatibility.SchemaWithRefs{Schema: schema("integer"), References: []storage.Reference{list}},
+		compatibility.SchemaWithRefs{Schema: schema("string"), References: []storage.Reference{list}},
+	)
+	if result.IsCompatible {
+		t.Error("Expected incompatible: the dynamically bound item type changed")
+	}
+}
+
+func TestChecker_RecursiveRef_Terminates(t *testing.T) {
+	checker := NewChecker()
+
+	schema := `{
+		"$ref": "#/$defs/node",
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {
+					"value": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			}
+		}
+	}`
+
+	result := checker.Check(s(schema), s(schema))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible, got messages: %v", result.Messages)
+	}
+}
+
+func TestChecker_PatternChange_Strictness(t *testing.T) {
+	oldSchema := `{"type": "string", "pattern": "^[a-z]+$"}`
+	newSchema := `{"type": "string", "pattern": "^[a-z0-9]+$"}`
+
+	result := NewChecker().Check(s(newSchema), s(oldSchema))
+	if result.IsCompatible {
+		t.Error("Expected incompatible under strict checking")
+	}
+
+	result = NewCheckerWithConfig(Config{Strictness: StrictnessLenient}).Check(s(newSchema), s(oldSchema))
+	if !result.IsCompatible {
+		t.Errorf("Expected compatible under lenient checking, got messages: %v", result.Messages)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Expected 1 warning, got %v", result.Warnings)
+	}
+
+	// Lenient checking still rejects changes it can decide
+	tightened := `{"type": "string", "pattern": "^[a-z0-9]+$", "minLength": 3}`
+	result = NewCheckerWithConfig(Config{Strictness: StrictnessLenient}).Check(s(tightened), s(oldSchema))
+	if result.IsCompatible {
+		t.Error("Expected incompatible: minLength added")
+	}
+}
@@ -12,9 +12,13 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	protoschema "github.com/axonops/axonops-schema-registry/internal/schema/protobuf"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
+// schemaFileName is the virtual file name the schema under check is compiled as.
+const schemaFileName = "schema.proto"
+
 // Checker implements compatibility.SchemaChecker for Protobuf schemas.
 type Checker struct{}
 
@@ -30,23 +34,27 @@ func (c *Checker) Check(reader, writer compatibility.SchemaWithRefs) *compatibil
 	// Parse both schemas
 	readerFD, err := parseSchemaWithRefs(reader)
 	if err != nil {
-		return compatibility.NewIncompatibleResult("failed to parse new schema: " + err.Error())
+		return invalidSchema("failed to parse new schema: " + err.Error())
 	}
 
 	writerFD, err := parseSchemaWithRefs(writer)
 	if err != nil {
-		return compatibility.NewIncompatibleResult("failed to parse old schema: " + err.Error())
+		return invalidSchema("failed to parse old schema: " + err.Error())
 	}
 
 	result := compatibility.NewCompatibleResult()
 
 	// Check package compatibility
 	if readerFD.Package() != writerFD.Package() {
-		result.AddMessage("Package changed from '%s' to '%s'", writerFD.Package(), readerFD.Package())
+		result.AddIncompatibility(compatibility.CodePackageChanged, "", string(readerFD.Package()), string(writerFD.Package()),
+			"Package changed from '%s' to '%s'", writerFD.Package(), readerFD.Package())
 	}
 
-	// Syntax keyword is a source-level annotation only; proto2 optional and proto3
-	// fields produce identical wire bytes. Confluent ignores syntax changes.
+	// Syntax keyword is a source-level annotation only; proto2 optional, proto3
+	// and editions fields produce identical wire bytes. Confluent ignores syntax
+	// changes. What editions express as features is checked on the resolved
+	// descriptors: LEGACY_REQUIRED presence is a required cardinality and
+	// DELIMITED message encoding is a group.
 
 	// Check messages
 	c.checkMessages(readerFD, writerFD, result)
@@ -54,28 +62,125 @@ func (c *Checker) Check(reader, writer compatibility.SchemaWithRefs) *compatibil
 	// Check enums
 	c.checkEnums(readerFD, writerFD, result)
 
+	// Check extensions (proto2 and editions)
+	c.checkExtensions(readerFD, writerFD, result)
+
 	// Services are gRPC metadata with zero wire-format impact.
 	// Confluent ignores service definitions entirely.
 
 	return result
 }
 
+// CheckHistory reports fields of the new schema that reuse a number or name
+// an earlier version reserved, or the number of a field an earlier version
+// removed. Data written before the removal would be decoded as the new
+// field, so every existing version is checked whatever the mode. Fields kept
+// from the latest version that declares their message are not reported
+// again, and a removed field may be restored with its original declaration.
+func (c *Checker) CheckHistory(newSchema compatibility.SchemaWithRefs, existingSchemas []compatibility.SchemaWithRefs) *compatibility.Result {
+	result := compatibility.NewCompatibleResult()
+	newFD, err := parseSchemaWithRefs(newSchema)
+	if err != nil {
+		// Reported by Check.
+		return result
+	}
+
+	history := make(map[protoreflect.FullName][]versionedMessage)
+	for i, s := range existingSchemas {
+		fd, err := parseSchemaWithRefs(s)
+		if err != nil {
+			continue
+		}
+		walkMessages(fd.Messages(), func(md protoreflect.MessageDescriptor) {
+			history[md.FullName()] = append(history[md.FullName()], versionedMessage{version: i + 1, msg: md})
+		})
+	}
+
+	walkMessages(newFD.Messages(), func(md protoreflect.MessageDescriptor) {
+		if versions := history[md.FullName()]; len(versions) > 0 {
+			c.checkFieldReuse(md, versions, result)
+		}
+	})
+	return result
+}
+
+// versionedMessage is a message as declared by one existing version.
+type versionedMessage struct {
+	version int
+	msg     protoreflect.MessageDescriptor
+}
+
+// walkMessages calls fn for each message and nested message, skipping map
+// entries.
+func walkMessages(msgs protoreflect.MessageDescriptors, fn func(protoreflect.MessageDescriptor)) {
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		if md.IsMapEntry() {
+			continue
+		}
+		fn(md)
+		walkMessages(md.Messages(), fn)
+	}
+}
+
+// checkFieldReuse checks the fields of newMsg against the versions of the
+// message, ordered from oldest to newest. The most recent offending version
+// is reported.
+func (c *Checker) checkFieldReuse(newMsg protoreflect.MessageDescriptor, versions []versionedMessage, result *compatibility.Result) {
+	msgName := string(newMsg.FullName())
+	latest := versions[len(versions)-1].msg
+
+	for i := 0; i < newMsg.Fields().Len(); i++ {
+		f := newMsg.Fields().Get(i)
+		num := f.Number()
+		numberKept := latest.Fields().ByNumber(num) != nil
+		nameKept := latest.Fields().ByName(f.Name()) != nil
+		if numberKept && nameKept {
+			continue
+		}
+
+		for j := len(versions) - 1; j >= 0; j-- {
+			v := versions[j]
+			if !numberKept && v.msg.ReservedRanges().Has(num) {
+				result.AddIncompatibility(compatibility.CodeReservedFieldReused, fieldPath(msgName, f), fieldFragment(f), fmt.Sprintf("reserved %d", num),
+					"Message '%s': field '%s' uses number %d, which version %d reserved", msgName, fieldPath(msgName, f), num, v.version)
+				break
+			}
+			if !nameKept && v.msg.ReservedNames().Has(f.Name()) {
+				result.AddIncompatibility(compatibility.CodeReservedFieldReused, fieldPath(msgName, f), fieldFragment(f), fmt.Sprintf("reserved %q", f.Name()),
+					"Message '%s': field '%s' uses a name that version %d reserved", msgName, fieldPath(msgName, f), v.version)
+				break
+			}
+			if old := v.msg.Fields().ByNumber(num); !numberKept && old != nil && fieldFragment(old) != fieldFragment(f) {
+				result.AddIncompatibility(compatibility.CodeFieldNumberReused, fieldPath(msgName, f), fieldFragment(f), fieldFragment(old),
+					"Message '%s': field '%s' reuses number %d of field '%s', which was removed after version %d", msgName, fieldPath(msgName, f), num, fieldPath(msgName, old), v.version)
+				break
+			}
+		}
+	}
+}
+
 // parseSchemaWithRefs parses a Protobuf schema string with optional references.
 func parseSchemaWithRefs(s compatibility.SchemaWithRefs) (protoreflect.FileDescriptor, error) {
 	handler := reporter.NewHandler(nil)
-	_, err := parser.Parse("schema.proto", strings.NewReader(s.Schema), handler)
+	_, err := parser.Parse(schemaFileName, strings.NewReader(s.Schema), handler)
 	if err != nil {
 		return nil, err
 	}
 
 	resolver := newCheckerResolver(s.Schema, s.References)
 
+	// Well-known types (google/protobuf, google/type, confluent) are bundled
+	// so they resolve the same way they do when the schema is registered.
 	compiler := protocompile.Compiler{
-		Resolver: protocompile.WithStandardImports(resolver),
+		Resolver: protocompile.CompositeResolver{
+			protoschema.BundledImports(),
+			resolver,
+		},
 	}
 
 	ctx := context.Background()
-	files, err := compiler.Compile(ctx, "schema.proto")
+	files, err := compiler.Compile(ctx, schemaFileName)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +213,7 @@ func newCheckerResolver(content string, refs []storage.Reference) *checkerResolv
 }
 
 func (r *checkerResolver) FindFileByPath(path string) (protocompile.SearchResult, error) {
-	if path == "schema.proto" {
+	if path == schemaFileName {
 		return protocompile.SearchResult{
 			Source: strings.NewReader(r.content),
 		}, nil
@@ -148,7 +253,7 @@ func (c *Checker) checkMessages(reader, writer protoreflect.FileDescriptor, resu
 
 	// Messages removed from new schema
 	for name := range oldMessages {
-		result.AddMessage("Message '%s' was removed", name)
+		result.AddIncompatibility(compatibility.CodeMessageRemoved, name, "", name, "Message '%s' was removed", name)
 	}
 }
 
@@ -178,8 +283,8 @@ func (c *Checker) checkMessageCompatibility(newMsg, oldMsg protoreflect.MessageD
 			// New field added
 			// For backward compatibility, new required fields are problematic
 			if newField.Cardinality() == protoreflect.Required {
-				result.AddMessage("Message '%s': new required field '%s' (number %d) added",
-					msgName, newField.Name(), num)
+				result.AddIncompatibility(compatibility.CodeRequiredFieldAdded, fieldPath(msgName, newField), fieldFragment(newField), "",
+					"Message '%s': new required field '%s' (number %d) added", msgName, fieldPath(msgName, newField), num)
 			}
 			continue
 		}
@@ -214,8 +319,8 @@ func (c *Checker) checkMessageCompatibility(newMsg, oldMsg protoreflect.MessageD
 	for oneofName, movedFields := range fieldsMovedToOneof {
 		if len(movedFields) > 1 {
 			// Multiple independent fields moved into same oneof
-			result.AddMessage("Message '%s': multiple fields moved into oneof '%s', creating mutual exclusion",
-				msgName, oneofName)
+			result.AddIncompatibility(compatibility.CodeOneofChanged, msgName+"."+oneofName, strings.Join(movedFields, ", "), "",
+				"Message '%s': multiple fields moved into oneof '%s', creating mutual exclusion", msgName, oneofName)
 			continue
 		}
 		// Check if the target oneof has other members that already existed in
@@ -238,8 +343,8 @@ func (c *Checker) checkMessageCompatibility(newMsg, oldMsg protoreflect.MessageD
 			}
 		}
 		if otherPreExistingMember {
-			result.AddMessage("Message '%s': field '%s' moved into existing oneof '%s'",
-				msgName, movedFieldName, oneofName)
+			result.AddIncompatibility(compatibility.CodeOneofChanged, msgName+"."+movedFieldName, oneofName, "",
+				"Message '%s': field '%s.%s' moved into existing oneof '%s'", msgName, msgName, movedFieldName, oneofName)
 		}
 	}
 
@@ -249,11 +354,11 @@ func (c *Checker) checkMessageCompatibility(newMsg, oldMsg protoreflect.MessageD
 	// 3. In proto3, non-oneof field removal is wire-safe (readers ignore unknown fields)
 	for num, oldField := range oldFields {
 		if oldField.Cardinality() == protoreflect.Required {
-			result.AddMessage("Message '%s': required field '%s' (number %d) was removed",
-				msgName, oldField.Name(), num)
+			result.AddIncompatibility(compatibility.CodeRequiredFieldRemoved, fieldPath(msgName, oldField), "", fieldFragment(oldField),
+				"Message '%s': required field '%s' (number %d) was removed", msgName, fieldPath(msgName, oldField), num)
 		} else if oldField.ContainingOneof() != nil && !oldField.ContainingOneof().IsSynthetic() {
-			result.AddMessage("Message '%s': field '%s' (number %d) was removed from oneof",
-				msgName, oldField.Name(), num)
+			result.AddIncompatibility(compatibility.CodeOneofChanged, fieldPath(msgName, oldField), "", fieldFragment(oldField),
+				"Message '%s': field '%s' (number %d) was removed from oneof '%s'", msgName, fieldPath(msgName, oldField), num, oldField.ContainingOneof().Name())
 		}
 	}
 
@@ -266,7 +371,7 @@ func (c *Checker) checkMessageCompatibility(newMsg, oldMsg protoreflect.MessageD
 
 // checkFieldCompatibility checks compatibility between two field descriptors.
 func (c *Checker) checkFieldCompatibility(newField, oldField protoreflect.FieldDescriptor, msgName string, result *compatibility.Result) {
-	fieldName := string(newField.Name())
+	fieldName := fieldPath(msgName, newField)
 	fieldNum := newField.Number()
 
 	// Check name change (allowed but worth noting)
@@ -275,10 +380,14 @@ func (c *Checker) checkFieldCompatibility(newField, oldField protoreflect.FieldD
 		// But it's worth noting for documentation
 	}
 
-	// Check type compatibility
-	if !c.areTypesCompatible(newField, oldField) {
-		result.AddMessage("Message '%s': field %d type changed from '%s' to '%s'",
-			msgName, fieldNum, protoTypeName(oldField), protoTypeName(newField))
+	// Check type compatibility. Message-typed fields, and groups, are diffed
+	// field by field so that a change deep inside the referenced type is
+	// reported at its path.
+	if newField.Kind() == oldField.Kind() && isMessageKind(newField.Kind()) {
+		c.checkMessageTypeCompatibility(newField.Message(), oldField.Message(), msgName, fieldName, nil, result)
+	} else if !c.areTypesCompatible(newField, oldField) {
+		result.AddIncompatibility(compatibility.CodeTypeMismatch, fieldName, fieldFragment(newField), fieldFragment(oldField),
+			"Message '%s': field '%s' (number %d) type changed from '%s' to '%s'", msgName, fieldName, fieldNum, protoTypeName(oldField), protoTypeName(newField))
 	}
 
 	// Check cardinality changes
@@ -291,25 +400,23 @@ func (c *Checker) checkFieldCompatibility(newField, oldField protoreflect.FieldD
 			// Per protobuf spec: "For string, bytes, and message fields, optional is
 			// compatible with repeated." These use length-delimited encoding which
 			// is the same wire format for both singular and repeated.
-			kind := oldField.Kind()
-			if kind != protoreflect.StringKind && kind != protoreflect.BytesKind && kind != protoreflect.MessageKind {
-				result.AddMessage("Message '%s': field '%s' changed from optional to repeated",
-					msgName, fieldName)
+			if !isLengthDelimited(oldField.Kind()) {
+				result.AddIncompatibility(compatibility.CodeFieldLabelChanged, fieldName, fieldFragment(newField), fieldFragment(oldField),
+					"Message '%s': field '%s' changed from optional to repeated", msgName, fieldName)
 			}
 		} else if oldCard == protoreflect.Required && newCard != protoreflect.Required {
 			// Required to optional/repeated - compatible
 		} else if newCard == protoreflect.Required && oldCard != protoreflect.Required {
 			// Non-required to required - breaking
-			result.AddMessage("Message '%s': field '%s' changed from optional to required",
-				msgName, fieldName)
+			result.AddIncompatibility(compatibility.CodeFieldLabelChanged, fieldName, fieldFragment(newField), fieldFragment(oldField),
+				"Message '%s': field '%s' changed from optional to required", msgName, fieldName)
 		} else if oldCard == protoreflect.Repeated && newCard != protoreflect.Repeated {
 			// Per protobuf spec: "For string, bytes, and message fields, singular is
 			// compatible with repeated." These use length-delimited encoding which
 			// is the same wire format for both singular and repeated.
-			kind := newField.Kind()
-			if kind != protoreflect.StringKind && kind != protoreflect.BytesKind && kind != protoreflect.MessageKind {
-				result.AddMessage("Message '%s': field '%s' changed from repeated to singular",
-					msgName, fieldName)
+			if !isLengthDelimited(newField.Kind()) {
+				result.AddIncompatibility(compatibility.CodeFieldLabelChanged, fieldName, fieldFragment(newField), fieldFragment(oldField),
+					"Message '%s': field '%s' changed from repeated to singular", msgName, fieldName)
 			}
 		}
 	}
@@ -326,28 +433,22 @@ func (c *Checker) checkFieldCompatibility(newField, oldField protoreflect.FieldD
 	if oldIsRealOneof != newIsRealOneof {
 		if oldIsRealOneof && !newIsRealOneof {
 			// Moving OUT of a real oneof — incompatible (changes oneof semantics)
-			result.AddMessage("Message '%s': field '%s' oneof membership changed",
-				msgName, fieldName)
+			result.AddIncompatibility(compatibility.CodeOneofChanged, fieldName, fieldFragment(newField), string(oldOneof.Name()),
+				"Message '%s': field '%s' moved out of oneof '%s'", msgName, fieldName, oldOneof.Name())
 		}
 		// Moving INTO a real oneof from non-oneof or synthetic oneof is compatible
 		// because the field number and wire format are preserved.
 	}
 }
 
-// areTypesCompatible checks if two field types are compatible.
+// areTypesCompatible checks if two field types are compatible. Fields that are
+// message-typed on both sides are compared by checkMessageTypeCompatibility.
 func (c *Checker) areTypesCompatible(newField, oldField protoreflect.FieldDescriptor) bool {
 	newKind := newField.Kind()
 	oldKind := oldField.Kind()
 
 	if newKind == oldKind {
-		// Same kind - check message/enum types
-		if newKind == protoreflect.MessageKind {
-			// Compare message types structurally (by field numbers and types) rather
-			// than by fully-qualified name. Protobuf wire format encodes field numbers
-			// and wire types, not type names. This handles cross-import compatibility
-			// where the same message structure appears under different package names.
-			return c.areMessagesStructurallyCompatible(newField.Message(), oldField.Message(), nil)
-		}
+		// Same kind - check enum types
 		if newKind == protoreflect.EnumKind {
 			return newField.Enum().FullName() == oldField.Enum().FullName()
 		}
@@ -415,15 +516,20 @@ func (c *Checker) areKindsWireCompatible(newKind, oldKind protoreflect.Kind) boo
 	return false
 }
 
-// areMessagesStructurallyCompatible checks if two message descriptors have
-// compatible field structures. For each field in the old message, the new
-// message must have a field with the same number and wire-compatible type.
+// checkMessageTypeCompatibility compares the message types of a field whose
+// type is a message in both schemas. Types are compared structurally (by field
+// numbers and wire types) rather than by fully-qualified name, because the
+// protobuf wire format encodes field numbers and wire types, not type names.
 // This handles cross-import compatibility where the same message structure
-// may appear under different package names (Confluent behavior).
-func (c *Checker) areMessagesStructurallyCompatible(newMsg, oldMsg protoreflect.MessageDescriptor, visited map[messagePair]bool) bool {
-	// Fast path: same message descriptor
-	if newMsg.FullName() == oldMsg.FullName() && newMsg.ParentFile().Path() == oldMsg.ParentFile().Path() {
-		return true
+// appears under different package names (Confluent behavior), and evolution
+// of types declared in referenced files. Each incompatibility is reported at
+// its full field path, starting from path.
+func (c *Checker) checkMessageTypeCompatibility(newMsg, oldMsg protoreflect.MessageDescriptor, msgName, path string, visited map[messagePair]bool, result *compatibility.Result) {
+	// Fast path: the same message declared in the schema itself is checked on
+	// its own by checkMessages, so its changes must not be reported twice.
+	if newMsg.FullName() == oldMsg.FullName() &&
+		newMsg.ParentFile().Path() == schemaFileName && oldMsg.ParentFile().Path() == schemaFileName {
+		return
 	}
 
 	// Recursion guard for self-referencing message types
@@ -432,7 +538,7 @@ func (c *Checker) areMessagesStructurallyCompatible(newMsg, oldMsg protoreflect.
 		visited = make(map[messagePair]bool)
 	}
 	if visited[key] {
-		return true // Assume compatible for recursive types to break cycle
+		return // Assume compatible for recursive types to break cycle
 	}
 	visited[key] = true
 
@@ -445,26 +551,42 @@ func (c *Checker) areMessagesStructurallyCompatible(newMsg, oldMsg protoreflect.
 			continue
 		}
 
+		nestedPath := path + "." + string(newField.Name())
 		oldKind := oldField.Kind()
 		newKind := newField.Kind()
 
 		if oldKind != newKind {
 			if !c.areKindsWireCompatible(newKind, oldKind) {
-				return false
+				result.AddIncompatibility(compatibility.CodeTypeMismatch, nestedPath, fieldFragment(newField), fieldFragment(oldField),
+					"Message '%s': field '%s' (number %d) type changed from '%s' to '%s'", msgName, nestedPath, newField.Number(), protoTypeName(oldField), protoTypeName(newField))
 			}
 			continue
 		}
 
-		// Same kind — check deeper for message/enum types
-		if oldKind == protoreflect.MessageKind {
-			if !c.areMessagesStructurallyCompatible(newField.Message(), oldField.Message(), visited) {
-				return false
-			}
+		// Same kind — check deeper for message types and groups
+		if isMessageKind(oldKind) {
+			c.checkMessageTypeCompatibility(newField.Message(), oldField.Message(), msgName, nestedPath, visited, result)
 		}
 		// For enums with same kind, wire format is always varint — compatible
 	}
+}
 
-	return true
+// isMessageKind reports whether fields of the kind hold a message: a
+// length-prefixed message, or a group (delimited message). The two kinds are
+// not wire-compatible with each other.
+func isMessageKind(k protoreflect.Kind) bool {
+	return k == protoreflect.MessageKind || k == protoreflect.GroupKind
+}
+
+// isLengthDelimited reports whether singular and repeated fields of the kind
+// share a wire encoding, so that switching between them is compatible. Groups
+// are included: each element is a delimited message either way.
+func isLengthDelimited(k protoreflect.Kind) bool {
+	switch k {
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		return true
+	}
+	return false
 }
 
 // messagePair is a key for tracking visited message pairs during structural comparison.
@@ -497,7 +619,8 @@ func (c *Checker) checkNestedMessages(newMsg, oldMsg protoreflect.MessageDescrip
 	}
 
 	for name := range oldNested {
-		result.AddMessage("Nested message '%s.%s' was removed", oldMsg.FullName(), name)
+		nested := string(oldMsg.FullName()) + "." + name
+		result.AddIncompatibility(compatibility.CodeMessageRemoved, nested, "", nested, "Nested message '%s' was removed", nested)
 	}
 }
 
@@ -545,6 +668,50 @@ func (c *Checker) checkEnums(reader, writer protoreflect.FileDescriptor, result
 	// wire, removing the type definition doesn't affect wire format.
 }
 
+// checkExtensions checks compatibility of extension fields, declared at the
+// top level or inside messages. Extensions are matched by extended message
+// and field number. Removing one is wire-safe: readers keep the value as an
+// unknown field.
+func (c *Checker) checkExtensions(reader, writer protoreflect.FileDescriptor, result *compatibility.Result) {
+	oldExts := collectExtensions(writer)
+	for key, newExt := range collectExtensions(reader) {
+		oldExt, exists := oldExts[key]
+		if !exists {
+			continue
+		}
+		extendee := string(newExt.ContainingMessage().FullName())
+		c.checkFieldCompatibility(newExt, oldExt, extendee, result)
+	}
+}
+
+// extensionKey identifies an extension by the message it extends and its
+// field number.
+type extensionKey struct {
+	extendee protoreflect.FullName
+	number   protoreflect.FieldNumber
+}
+
+// collectExtensions returns every extension declared in the file.
+func collectExtensions(fd protoreflect.FileDescriptor) map[extensionKey]protoreflect.FieldDescriptor {
+	exts := make(map[extensionKey]protoreflect.FieldDescriptor)
+	add := func(xs protoreflect.ExtensionDescriptors) {
+		for i := 0; i < xs.Len(); i++ {
+			x := xs.Get(i)
+			exts[extensionKey{x.ContainingMessage().FullName(), x.Number()}] = x
+		}
+	}
+	var walk func(msgs protoreflect.MessageDescriptors)
+	walk = func(msgs protoreflect.MessageDescriptors) {
+		for i := 0; i < msgs.Len(); i++ {
+			add(msgs.Get(i).Extensions())
+			walk(msgs.Get(i).Messages())
+		}
+	}
+	add(fd.Extensions())
+	walk(fd.Messages())
+	return exts
+}
+
 // checkEnumCompatibility checks compatibility between two enum descriptors.
 func (c *Checker) checkEnumCompatibility(newEnum, oldEnum protoreflect.EnumDescriptor, result *compatibility.Result) {
 	// Build map of old values by number
@@ -593,7 +760,7 @@ func (c *Checker) checkServices(reader, writer protoreflect.FileDescriptor, resu
 	}
 
 	for name := range oldServices {
-		result.AddMessage("Service '%s' was removed", name)
+		result.AddIncompatibility(compatibility.CodeServiceRemoved, name, "", name, "Service '%s' was removed", name)
 	}
 }
 
@@ -614,30 +781,49 @@ func (c *Checker) checkServiceCompatibility(newSvc, oldSvc protoreflect.ServiceD
 		if oldMethod, exists := oldMethods[name]; exists {
 			// Check method compatibility
 			if newMethod.Input().FullName() != oldMethod.Input().FullName() {
-				result.AddMessage("Service '%s': method '%s' input type changed from '%s' to '%s'",
-					svcName, name, oldMethod.Input().FullName(), newMethod.Input().FullName())
+				result.AddIncompatibility(compatibility.CodeMethodChanged, svcName+"."+name, string(newMethod.Input().FullName()), string(oldMethod.Input().FullName()),
+					"Service '%s': method '%s' input type changed from '%s' to '%s'", svcName, name, oldMethod.Input().FullName(), newMethod.Input().FullName())
 			}
 			if newMethod.Output().FullName() != oldMethod.Output().FullName() {
-				result.AddMessage("Service '%s': method '%s' output type changed from '%s' to '%s'",
-					svcName, name, oldMethod.Output().FullName(), newMethod.Output().FullName())
+				result.AddIncompatibility(compatibility.CodeMethodChanged, svcName+"."+name, string(newMethod.Output().FullName()), string(oldMethod.Output().FullName()),
+					"Service '%s': method '%s' output type changed from '%s' to '%s'", svcName, name, oldMethod.Output().FullName(), newMethod.Output().FullName())
 			}
 			if newMethod.IsStreamingClient() != oldMethod.IsStreamingClient() {
-				result.AddMessage("Service '%s': method '%s' client streaming changed",
-					svcName, name)
+				result.AddIncompatibility(compatibility.CodeMethodChanged, svcName+"."+name, "", "",
+					"Service '%s': method '%s' client streaming changed", svcName, name)
 			}
 			if newMethod.IsStreamingServer() != oldMethod.IsStreamingServer() {
-				result.AddMessage("Service '%s': method '%s' server streaming changed",
-					svcName, name)
+				result.AddIncompatibility(compatibility.CodeMethodChanged, svcName+"."+name, "", "",
+					"Service '%s': method '%s' server streaming changed", svcName, name)
 			}
 			delete(oldMethods, name)
 		}
 	}
 
 	for name := range oldMethods {
-		result.AddMessage("Service '%s': method '%s' was removed", svcName, name)
+		result.AddIncompatibility(compatibility.CodeMethodRemoved, svcName+"."+name, "", name,
+			"Service '%s': method '%s' was removed", svcName, name)
 	}
 }
 
+// fieldFragment describes a field as it is declared, such as
+// "optional int32 id = 1".
+func fieldFragment(f protoreflect.FieldDescriptor) string {
+	return fmt.Sprintf("%s %s %s = %d", f.Cardinality(), protoTypeName(f), f.Name(), f.Number())
+}
+
+// invalidSchema returns an incompatible result for a schema that failed to parse.
+func invalidSchema(msg string) *compatibility.Result {
+	result := compatibility.NewCompatibleResult()
+	result.AddIncompatibility(compatibility.CodeSchemaInvalid, "", "", "", "%s", msg)
+	return result
+}
+
+// fieldPath returns the dotted path of a field within the message msgName.
+func fieldPath(msgName string, f protoreflect.FieldDescriptor) string {
+	return msgName + "." + string(f.Name())
+}
+
 // protoTypeName returns a human-readable type name for a field.
 func protoTypeName(f protoreflect.FieldDescriptor) string {
 	switch f.Kind() {
@@ -676,11 +862,15 @@ func protoTypeName(f protoreflect.FieldDescriptor) string {
 	case protoreflect.EnumKind:
 		return string(f.Enum().FullName())
 	case protoreflect.GroupKind:
-		return "group"
+		return "group " + string(f.Message().FullName())
 	default:
 		return "unknown"
 	}
 }
 
-// Ensure Checker implements compatibility.SchemaChecker
-var _ compatibility.SchemaChecker = (*Checker)(nil)
+// Ensure Checker implements compatibility.SchemaChecker and
+// compatibility.HistoryChecker
+var (
+	_ compatibility.SchemaChecker  = (*Checker)(nil)
+	_ compatibility.HistoryChecker = (*Checker)(nil)
+)
@@ -1,9 +1,11 @@
 package protobuf
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
 // s creates a SchemaWithRefs with no references for convenience.
@@ -1217,3 +1219,448 @@ message TestMessage {
 		t.Error("Optional to repeated for int32 should be incompatible (packed encoding differs)")
 	}
 }
+
+// hasMessageContaining reports whether any result message contains substr.
+func hasMessageContaining(result *compatibility.Result, substr string) bool {
+	for _, msg := range result.Messages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestChecker_WellKnownTypes_Compatible(t *testing.T) {
+	checker := NewChecker()
+	oldSchema := `
+syntax = "proto3";
+import "google/protobuf/timestamp.proto";
+import "google/type/date.proto";
+import "google/type/money.proto";
+import "confluent/meta.proto";
+message Order {
+  google.protobuf.Timestamp created_at = 1;
+  google.type.Date ship_date = 2;
+  google.type.Money total = 3 [(confluent.field_meta).doc = "Order total"];
+}
+`
+	newSchema := `
+syntax = "proto3";
+import "google/protobuf/timestamp.proto";
+import "google/type/date.proto";
+import "google/type/money.proto";
+import "confluent/meta.proto";
+message Order {
+  google.protobuf.Timestamp created_at = 1;
+  google.type.Date ship_date = 2;
+  google.type.Money total = 3 [(confluent.field_meta).doc = "Order total"];
+  string note = 4;
+}
+`
+	result := checker.Check(s(newSchema), s(oldSchema))
+	if !result.IsCompatible {
+		t.Errorf("Schemas importing well-known types should resolve and be compatible: %v", result.Messages)
+	}
+}
+
+func TestChecker_WellKnownTypeChanged_ReportsPath(t *testing.T) {
+	checker := NewChecker()
+	oldSchema := `
+syntax = "proto3";
+import "google/type/date.proto";
+message Order {
+  google.type.Date ship_date = 1;
+}
+`
+	newSchema := `
+syntax = "proto3";
+message Order {
+  Date ship_date = 1;
+  message Date {
+    string year = 1;
+  }
+}
+`
+	result := checker.Check(s(newSchema), s(oldSchema))
+	if result.IsCompatible {
+		t.Fatal("Changing a well-known type to a wire-incompatible message should be incompatible")
+	}
+	if !hasMessageContaining(result, "'Order.ship_date.year'") {
+		t.Errorf("Expected precise field path Order.ship_date.year, got: %v", result.Messages)
+	}
+}
+
+func TestChecker_DeeplyNestedMessage_ReportsPath(t *testing.T) {
+	checker := NewChecker()
+	oldSchema := `
+syntax = "proto3";
+message Order {
+  message Line {
+    message Item {
+      int32 sku = 1;
+    }
+    Item item = 1;
+  }
+  Line line = 1;
+}
+`
+	newSchema := `
+syntax = "proto3";
+message Order {
+  message Line {
+    message Item {
+      string sku = 1;
+    }
+    Item item = 1;
+  }
+  Line line = 1;
+}
+`
+	result := checker.Check(s(newSchema), s(oldSchema))
+	if result.IsCompatible {
+		t.Fatal("Type change in a deeply nested message should be incompatible")
+	}
+	if !hasMessageContaining(result, "'Order.Line.Item.sku'") {
+		t.Errorf("Expected precise field path Order.Line.Item.sku, got: %v", result.Messages)
+	}
+	if len(result.Messages) != 1 {
+		t.Errorf("Expected the change to be reported once, got: %v", result.Messages)
+	}
+}
+
+func TestChecker_NestedOneofFieldRemoved_ReportsPath(t *testing.T) {
+	checker := NewChecker()
+	oldSchema := `
+syntax = "proto3";
+message Order {
+  message Payment {
+    oneof method {
+      string card = 1;
+      string iban = 2;
+    }
+  }
+  Payment payment = 1;
+}
+`
+	newSchema := `
+syntax = "proto3";
+message Order {
+  message Payment {
+    oneof method {
+      string card = 1;
+    }
+  }
+  Payment payment = 1;
+}
+`
+	result := checker.Check(s(newSchema), s(oldSchema))
+	if result.IsCompatible {
+		t.Fatal("Removing a field from a nested oneof should be incompatible")
+	}
+	if !hasMessageContaining(result, "'Order.Payment.iban'") || !hasMessageContaining(result, "oneof 'method'") {
+		t.Errorf("Expected field path and oneof name in message, got: %v", result.Messages)
+	}
+}
+
+func TestChecker_ReferencedMessageEvolution_ReportsPath(t *testing.T) {
+	checker := NewChecker()
+	schema := `
+syntax = "proto3";
+import "common.proto";
+message Order {
+  common.Address address = 1;
+}
+`
+	oldRef := `
+syntax = "proto3";
+package common;
+message Address {
+  message Geo {
+    double lat = 1;
+  }
+  Geo geo = 1;
+}
+`
+	newRef := `
+syntax = "proto3";
+package common;
+message Address {
+  message Geo {
+    string lat = 1;
+  }
+  Geo geo = 1;
+}
+`
+	oldSchema := compatibility.SchemaWithRefs{
+		Schema:     schema,
+		References: []storage.Reference{{Name: "common.proto", Subject: "common", Version: 1, Schema: oldRef}},
+	}
+	newSchema := compatibility.SchemaWithRefs{
+		Schema:     schema,
+		References: []storage.Reference{{Name: "common.proto", Subject: "common", Version: 2, Schema: newRef}},
+	}
+	result := checker.Check(newSchema, oldSchema)
+	if result.IsCompatible {
+		t.Fatal("Incompatible change inside a referenced message should be detected")
+	}
+	if !hasMessageContaining(result, "'Order.address.geo.lat'") {
+		t.Errorf("Expected precise field path Order.address.geo.lat, got: %v", result.Messages)
+	}
+}
+
+func TestChecker_Proto2RequiredToOptional(t *testing.T) {
+	checker := NewChecker()
+
+	required := `
+syntax = "proto2";
+
+message User {
+  required string name = 1;
+}
+`
+	optional := `
+syntax = "proto2";
+
+message User {
+  optional string name = 1;
+}
+`
+
+	// New readers tolerate the field being absent from old data.
+	if result := checker.Check(s(optional), s(required)); !result.IsCompatible {
+		t.Errorf("Relaxing required to optional should be compatible, got: %v", result.Messages)
+	}
+	// Old readers reject data written without the required field.
+	if result := checker.Check(s(required), s(optional)); result.IsCompatible {
+		t.Error("Tightening optional to required should be incompatible")
+	}
+}
+
+func TestChecker_Proto2Groups(t *testing.T) {
+	checker := NewChecker()
+
+	oldSchema := `
+syntax = "proto2";
+
+message Order {
+  repeated group Item = 1 {
+    optional string sku = 1;
+  }
+}
+`
+
+	compatibleSchema := `
+syntax = "proto2";
+
+message Order {
+  repeated group Item = 1 {
+    optional string sku = 1;
+    optional int32 quantity = 2;
+  }
+}
+`
+	if result := checker.Check(s(compatibleSchema), s(oldSchema)); !result.IsCompatible {
+		t.Errorf("Adding a field to a group should be compatible, got: %v", result.Messages)
+	}
+
+	changedSchema := `
+syntax = "proto2";
+
+message Order {
+  repeated group Item = 1 {
+    optional double sku = 1;
+  }
+}
+`
+	if result := checker.Check(s(changedSchema), s(oldSchema)); result.IsCompatible {
+		t.Error("Changing a group field's type should be incompatible")
+	}
+
+	messageSchema := `
+syntax = "proto2";
+
+message Order {
+  message Item {
+    optional string sku = 1;
+  }
+  repeated Item item = 1;
+}
+`
+	if result := checker.Check(s(messageSchema), s(oldSchema)); result.IsCompatible {
+		t.Error("Replacing a group with a message field should be incompatible")
+	}
+}
+
+func TestChecker_Proto2Extensions(t *testing.T) {
+	checker := NewChecker()
+
+	oldSchema := `
+syntax = "proto2";
+
+message Order {
+  extensions 100 to 199;
+}
+
+extend Order {
+  optional string channel = 100;
+}
+`
+
+	newSchema := `
+syntax = "proto2";
+
+message Order {
+  extensions 100 to 199;
+}
+
+extend Order {
+  optional double channel = 100;
+}
+`
+	if result := checker.Check(s(newSchema), s(oldSchema)); result.IsCompatible {
+		t.Error("Changing an extension's type should be incompatible")
+	}
+
+	removedSchema := `
+syntax = "proto2";
+
+message Order {
+  extensions 100 to 199;
+}
+`
+	if result := checker.Check(s(removedSchema), s(oldSchema)); !result.IsCompatible {
+		t.Errorf("Removing an extension should be compatible, got: %v", result.Messages)
+	}
+}
+
+func TestChecker_Editions(t *testing.T) {
+	checker := NewChecker()
+
+	proto2Schema := `
+syntax = "proto2";
+
+message User {
+  optional string name = 1;
+  optional int32 age = 2;
+}
+`
+
+	editionsSchema := `
+edition = "2023";
+
+message User {
+  string name = 1;
+  int32 age = 2 [features.field_presence = IMPLICIT];
+}
+`
+	if result := checker.Check(s(editionsSchema), s(proto2Schema)); !result.IsCompatible {
+		t.Errorf("Migrating proto2 to editions should be compatible, got: %v", result.Messages)
+	}
+
+	requiredSchema := `
+edition = "2023";
+
+message User {
+  string name = 1 [features.field_presence = LEGACY_REQUIRED];
+  int32 age = 2;
+}
+`
+	if result := checker.Check(s(requiredSchema), s(editionsSchema)); result.IsCompatible {
+		t.Error("Making a field LEGACY_REQUIRED should be incompatible")
+	}
+}
+
+func TestChecker_CheckHistory_FieldReuse(t *testing.T) {
+	checker := NewChecker()
+
+	tests := []struct {
+		name     string
+		history  []string
+		schema   string
+		wantCode string
+	}{
+		{
+			name:     "reserved number reused",
+			history:  []string{`syntax = "proto3"; message User { reserved 2; string id = 1; }`},
+			schema:   `syntax = "proto3"; message User { string id = 1; string email = 2; }`,
+			wantCode: compatibility.CodeReservedFieldReused,
+		},
+		{
+			name:     "reserved range reused",
+			history:  []string{`syntax = "proto3"; message User { reserved 5 to 10; string id = 1; }`},
+			schema:   `syntax = "proto3"; message User { string id = 1; string email = 7; }`,
+			wantCode: compatibility.CodeReservedFieldReused,
+		},
+		{
+			name:     "reserved name reused",
+			history:  []string{`syntax = "proto3"; message User { reserved "email"; string id = 1; }`},
+			schema:   `syntax = "proto3"; message User { string id = 1; string email = 3; }`,
+			wantCode: compatibility.CodeReservedFieldReused,
+		},
+		{
+			name: "reservation dropped by a later version",
+			history: []string{
+				`syntax = "proto3"; message User { reserved 2; string id = 1; }`,
+				`syntax = "proto3"; message User { string id = 1; }`,
+			},
+			schema:   `syntax = "proto3"; message User { string id = 1; bool active = 2; }`,
+			wantCode: compatibility.CodeReservedFieldReused,
+		},
+		{
+			name: "removed field number reused",
+			history: []string{
+				`syntax = "proto3"; message User { string id = 1; string email = 2; }`,
+				`syntax = "proto3"; message User { string id = 1; }`,
+			},
+			schema:   `syntax = "proto3"; message User { string id = 1; int64 created_at = 2; }`,
+			wantCode: compatibility.CodeFieldNumberReused,
+		},
+		{
+			name: "nested message",
+			history: []string{
+				`syntax = "proto3"; message Order { message Line { reserved 3; string sku = 1; } Line line = 1; }`,
+			},
+			schema:   `syntax = "proto3"; message Order { message Line { string sku = 1; int32 qty = 3; } Line line = 1; }`,
+			wantCode: compatibility.CodeReservedFieldReused,
+		},
+		{
+			name: "removed field restored unchanged",
+			history: []string{
+				`syntax = "proto3"; message User { string id = 1; string email = 2; }`,
+				`syntax = "proto3"; message User { string id = 1; }`,
+			},
+			schema: `syntax = "proto3"; message User { string id = 1; string email = 2; }`,
+		},
+		{
+			name:    "field kept from the latest version",
+			history: []string{`syntax = "proto3"; message User { string id = 1; string email = 2; }`},
+			schema:  `syntax = "proto3"; message User { string id = 1; string contact = 2; }`,
+		},
+		{
+			name:    "removed field reserved",
+			history: []string{`syntax = "proto3"; message User { string id = 1; string email = 2; }`},
+			schema:  `syntax = "proto3"; message User { reserved 2; reserved "email"; string id = 1; string name = 3; }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := make([]compatibility.SchemaWithRefs, len(tt.history))
+			for i, h := range tt.history {
+				history[i] = s(h)
+			}
+			result := checker.CheckHistory(s(tt.schema), history)
+			if tt.wantCode == "" {
+				if !result.IsCompatible {
+					t.Errorf("expected no incompatibilities, got %v", result.Messages)
+				}
+				return
+			}
+			if result.IsCompatible || len(result.Incompatibilities) != 1 {
+				t.Fatalf("expected one incompatibility, got %v", result.Messages)
+			}
+			if result.Incompatibilities[0].Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s (%s)", tt.wantCode, result.Incompatibilities[0].Code, result.Messages[0])
+			}
+		})
+	}
+}
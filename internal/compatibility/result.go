@@ -1,11 +1,41 @@
 package compatibility
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
-// Result represents the result of a compatibility check.
+// Result represents the result of a compatibility check. Warnings describe
+// changes a checker was configured to tolerate; they do not affect
+// IsCompatible.
 type Result struct {
-	IsCompatible bool     `json:"is_compatible"`
-	Messages     []string `json:"messages,omitempty"`
+	IsCompatible      bool              `json:"is_compatible"`
+	Messages          []string          `json:"messages,omitempty"`
+	Warnings          []string          `json:"warnings,omitempty"`
+	Incompatibilities []Incompatibility `json:"incompatibilities,omitempty"`
+}
+
+// Incompatibility is the structured form of one incompatibility message:
+// Messages[i] and Incompatibilities[i] describe the same change.
+type Incompatibility struct {
+	// Code is a machine-readable reason such as READER_FIELD_MISSING_DEFAULT.
+	Code string `json:"code"`
+	// Path locates the change within the schema, if it has a location.
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+	// Direction (BACKWARD or FORWARD) and Version identify the existing
+	// version the check failed against. They are set by Checker.
+	Direction string `json:"direction,omitempty"`
+	Version   int    `json:"version,omitempty"`
+	// Old and New are the offending fragments of the existing and the new
+	// schema. They are set by Checker from Reader and Writer.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+
+	// Reader and Writer are the offending fragments of the reader and the
+	// writer schema, as reported by a SchemaChecker.
+	Reader string `json:"-"`
+	Writer string `json:"-"`
 }
 
 // NewCompatibleResult creates a result indicating compatibility.
@@ -17,22 +47,65 @@ func NewCompatibleResult() *Result {
 
 // NewIncompatibleResult creates a result indicating incompatibility.
 func NewIncompatibleResult(messages ...string) *Result {
-	return &Result{
-		IsCompatible: false,
-		Messages:     messages,
+	r := &Result{IsCompatible: false}
+	for _, msg := range messages {
+		r.AddMessage("%s", msg)
 	}
+	return r
 }
 
-// AddMessage adds an incompatibility message.
+// AddMessage adds an incompatibility message with the generic
+// CodeIncompatibleChange code.
 func (r *Result) AddMessage(format string, args ...interface{}) {
-	r.Messages = append(r.Messages, fmt.Sprintf(format, args...))
+	r.AddIncompatibility(CodeIncompatibleChange, "", "", "", format, args...)
+}
+
+// AddIncompatibility adds an incompatibility message with its reason code,
+// path and the offending reader and writer fragments.
+func (r *Result) AddIncompatibility(code, path, reader, writer, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	r.add(Incompatibility{Code: code, Path: path, Message: msg, Reader: reader, Writer: writer})
+}
+
+// add records inc and its message.
+func (r *Result) add(inc Incompatibility) {
+	r.Messages = append(r.Messages, inc.Message)
+	r.Incompatibilities = append(r.Incompatibilities, inc)
 	r.IsCompatible = false
 }
 
+// ReasonCategories returns the distinct categories of a result's
+// incompatibilities, in the order they first occur.
+func (r *Result) ReasonCategories() []string {
+	var categories []string
+	for _, inc := range r.Incompatibilities {
+		category := ReasonCategory(inc.Code)
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// addAgainst records inc, found checking in direction against version.
+func (r *Result) addAgainst(direction string, version int, inc Incompatibility) {
+	inc.Direction = direction
+	inc.Version = version
+	inc.Message = fmt.Sprintf("%s compatibility check failed against version %d: %s", direction, version, inc.Message)
+	r.add(inc)
+}
+
+// AddWarning adds a warning without marking the result incompatible.
+func (r *Result) AddWarning(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
 // Merge merges another result into this one.
 func (r *Result) Merge(other *Result) {
 	if !other.IsCompatible {
 		r.IsCompatible = false
 		r.Messages = append(r.Messages, other.Messages...)
+		r.Incompatibilities = append(r.Incompatibilities, other.Incompatibilities...)
 	}
+	r.Warnings = append(r.Warnings, other.Warnings...)
 }
@@ -65,6 +65,36 @@ func TestAddMessage(t *testing.T) {
 	}
 }
 
+func TestAddWarning(t *testing.T) {
+	r := NewCompatibleResult()
+	r.AddWarning("pattern changed at '%s'", "#/properties/zip")
+
+	if !r.IsCompatible {
+		t.Error("expected a warning to leave the result compatible")
+	}
+	if len(r.Messages) != 0 {
+		t.Errorf("expected no messages, got %d", len(r.Messages))
+	}
+	if len(r.Warnings) != 1 || r.Warnings[0] != "pattern changed at '#/properties/zip'" {
+		t.Errorf("unexpected warnings: %v", r.Warnings)
+	}
+}
+
+func TestMerge_Warnings(t *testing.T) {
+	r := NewCompatibleResult()
+	r.AddWarning("first")
+	other := NewCompatibleResult()
+	other.AddWarning("second")
+
+	r.Merge(other)
+	if !r.IsCompatible {
+		t.Error("expected merged result to stay compatible")
+	}
+	if len(r.Warnings) != 2 {
+		t.Errorf("expected 2 warnings, got %v", r.Warnings)
+	}
+}
+
 func TestAddMessage_Multiple(t *testing.T) {
 	r := NewCompatibleResult()
 	r.AddMessage("issue 1")
@@ -134,3 +164,80 @@ func TestMerge_MultipleMessages(t *testing.T) {
 		t.Errorf("expected 3 messages, got %d", len(r.Messages))
 	}
 }
+
+func TestAddIncompatibility(t *testing.T) {
+	r := NewCompatibleResult()
+	r.AddIncompatibility(CodeTypeMismatch, "user.age", `"string"`, `"int"`, "type of %s changed", "age")
+
+	if r.IsCompatible {
+		t.Error("expected incompatible after AddIncompatibility")
+	}
+	if len(r.Messages) != 1 || r.Messages[0] != "type of age changed" {
+		t.Errorf("unexpected messages: %v", r.Messages)
+	}
+	if len(r.Incompatibilities) != 1 {
+		t.Fatalf("expected 1 incompatibility, got %d", len(r.Incompatibilities))
+	}
+	inc := r.Incompatibilities[0]
+	if inc.Code != CodeTypeMismatch || inc.Path != "user.age" || inc.Reader != `"string"` || inc.Writer != `"int"` || inc.Message != "type of age changed" {
+		t.Errorf("unexpected incompatibility: %+v", inc)
+	}
+}
+
+func TestAddMessage_GenericCode(t *testing.T) {
+	r := NewIncompatibleResult("breaking change")
+	r.AddMessage("another")
+	if len(r.Incompatibilities) != 2 {
+		t.Fatalf("expected 2 incompatibilities, got %d", len(r.Incompatibilities))
+	}
+	for _, inc := range r.Incompatibilities {
+		if inc.Code != CodeIncompatibleChange {
+			t.Errorf("expected %s, got %s", CodeIncompatibleChange, inc.Code)
+		}
+	}
+}
+
+func TestMerge_Incompatibilities(t *testing.T) {
+	r := NewCompatibleResult()
+	other := NewCompatibleResult()
+	other.AddIncompatibility(CodeMessageRemoved, "pkg.User", "", "pkg.User", "Message '%s' was removed", "pkg.User")
+	r.Merge(other)
+	if len(r.Incompatibilities) != 1 || r.Incompatibilities[0].Code != CodeMessageRemoved {
+		t.Errorf("unexpected incompatibilities after merge: %+v", r.Incompatibilities)
+	}
+}
+
+func TestReasonCategory(t *testing.T) {
+	tests := map[string]string{
+		CodeTypeMismatch:              CategoryTypeChanged,
+		CodeLogicalTypeChanged:        CategoryTypeChanged,
+		CodeReaderFieldMissingDefault: CategoryFieldAdded,
+		CodePropertyRemoved:           CategoryFieldRemoved,
+		CodeEnumSymbolRemoved:         CategoryFieldRemoved,
+		CodeFieldNumberReused:         CategoryFieldChanged,
+		CodeConstraintTightened:       CategoryConstraintChanged,
+		CodeMethodChanged:             CategoryServiceChanged,
+		CodeSchemaInvalid:             CategoryInvalidSchema,
+		CodeIncompatibleChange:        CategoryOther,
+		"SOMETHING_NEW":               CategoryOther,
+	}
+	for code, want := range tests {
+		if got := ReasonCategory(code); got != want {
+			t.Errorf("ReasonCategory(%s) = %s, want %s", code, got, want)
+		}
+	}
+}
+
+func TestResult_ReasonCategories(t *testing.T) {
+	r := NewCompatibleResult()
+	if got := r.ReasonCategories(); len(got) != 0 {
+		t.Errorf("expected no categories, got %v", got)
+	}
+	r.AddIncompatibility(CodePropertyRemoved, "/properties/a", "", "", "property a removed")
+	r.AddIncompatibility(CodeTypeMismatch, "/properties/b", "", "", "type of b changed")
+	r.AddIncompatibility(CodeItemRemoved, "/items/1", "", "", "item 1 removed")
+	got := r.ReasonCategories()
+	if len(got) != 2 || got[0] != CategoryFieldRemoved || got[1] != CategoryTypeChanged {
+		t.Errorf("expected [field_removed type_changed], got %v", got)
+	}
+}
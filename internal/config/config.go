@@ -2,24 +2,222 @@
 package config
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/url"
 	"os"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the schema registry configuration.
 type Config struct {
-	Server        ServerConfig        `yaml:"server"`
-	Storage       StorageConfig       `yaml:"storage"`
-	Compatibility CompatibilityConfig `yaml:"compatibility"`
-	Logging       LoggingConfig       `yaml:"logging"`
-	Security      SecurityConfig      `yaml:"security"`
-	MCP           MCPConfig           `yaml:"mcp"`
+	Server         ServerConfig         `yaml:"server"`
+	Storage        StorageConfig        `yaml:"storage"`
+	Compatibility  CompatibilityConfig  `yaml:"compatibility"`
+	Logging        LoggingConfig        `yaml:"logging"`
+	Tracing        TracingConfig        `yaml:"tracing"`
+	Security       SecurityConfig       `yaml:"security"`
+	MCP            MCPConfig            `yaml:"mcp"`
+	GRPC           GRPCConfig           `yaml:"grpc"`
+	Exporters      ExportersConfig      `yaml:"exporters"`
+	Jobs           JobsConfig           `yaml:"jobs"`
+	Usage          UsageConfig          `yaml:"usage"`
+	Normalization  NormalizationConfig  `yaml:"normalization"`
+	SubjectNaming  SubjectNamingConfig  `yaml:"subject_naming"`
+	Linting        LintingConfig        `yaml:"linting"`
+	References     ReferencesConfig     `yaml:"references"`
+	Fingerprints   FingerprintsConfig   `yaml:"fingerprints"`
+	Tenancy        TenancyConfig        `yaml:"tenancy"`
+	IDs            IDsConfig            `yaml:"ids"`
+	SubjectAliases SubjectAliasesConfig `yaml:"subject_aliases"`
+	Consistency    ConsistencyConfig    `yaml:"consistency"`
+	Approval       ApprovalConfig       `yaml:"approval"`
+	Contexts       ContextsConfig       `yaml:"contexts"`
+	Deletes        DeletesConfig        `yaml:"deletes"`
+	Quotas         QuotasConfig         `yaml:"quotas"`
+}
+
+// SubjectAliasesConfig represents how writes to an aliased subject, one
+// whose config sets an alias, are handled. Reads always resolve the alias.
+// With the "follow" write policy (default), as in Confluent Schema Registry,
+// registrations and deletes apply to the alias target; with "ignore" they
+// apply to the aliased subject itself; with "reject" they are refused.
+type SubjectAliasesConfig struct {
+	WritePolicy string `yaml:"write_policy"` // follow (default), ignore or reject
+}
+
+// Subject alias write policies.
+const (
+	AliasWriteFollow = "follow"
+	AliasWriteIgnore = "ignore"
+	AliasWriteReject = "reject"
+)
+
+// IDsConfig represents how schema IDs are allocated. In "context" scope
+// (default) every context has its own ID sequence, so the same ID can name
+// different schemas in different contexts. In "global" scope, as in
+// Confluent Schema Registry, all contexts share one sequence, and an ID that
+// is not found in the requested context is resolved in the other contexts.
+type IDsConfig struct {
+	Scope string `yaml:"scope"` // context (default) or global
+}
+
+// Schema ID scopes.
+const (
+	IDScopeContext = "context"
+	IDScopeGlobal  = "global"
+)
+
+// TenancyConfig represents hard multi-tenancy. When enabled, tenants own
+// contexts and users, tenant users are confined to their tenant's contexts,
+// and each tenant's schemas are encrypted with its own key. Tenant keys are
+// wrapped with the master key, so losing it makes tenant schemas unreadable.
+//
+// ContextMode decides which contexts tenant users reach. In "owned" mode
+// (default) they name any context their tenant owns in the URL. In
+// "principal" mode every tenant has a single home context, created with the
+// tenant, and the context of a tenant user's request is derived from the
+// user's tenant instead of the URL; super admins without a tenant can act
+// for a tenant by sending OverrideHeader.
+type TenancyConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	MasterKey      string `yaml:"master_key"`      // Base64-encoded 32-byte key that wraps tenant keys (required when enabled)
+	ContextMode    string `yaml:"context_mode"`    // owned (default) or principal
+	OverrideHeader string `yaml:"override_header"` // Header super admins send to act for a tenant in principal mode (default: X-Registry-Tenant)
+}
+
+// ReferencesConfig represents schema reference resolution limits.
+type ReferencesConfig struct {
+	MaxDepth int `yaml:"max_depth"` // Longest reference chain a schema may have (default: 32, 0 uses the default)
+}
+
+// FingerprintsConfig represents the schema fingerprint included in schema
+// responses, as a "fingerprint" field, and returned by default by
+// GET /schemas/ids/{id}/fingerprint. With no algorithm, responses are left
+// as Confluent returns them and the endpoint defaults to sha256.
+type FingerprintsConfig struct {
+	Algorithm string `yaml:"algorithm"` // md5, sha256 or crc64-avro (default: none)
+}
+
+// SubjectNamingConfig represents the subject naming policy enforced at
+// registration time. Strategies are "none", "topic_name", "record_name" and
+// "topic_record_name".
+type SubjectNamingConfig struct {
+	DefaultStrategy string            `yaml:"default_strategy"` // Strategy for contexts without a mapping (default: none)
+	Contexts        map[string]string `yaml:"contexts"`         // Context name -> strategy
+}
+
+// LintingConfig represents the schema linting policy enforced at registration
+// time. A policy maps a rule ("field_doc", "field_snake_case" or
+// "timestamp_logical_type") to a severity: "error" rejects the schema, "warn"
+// accepts it and reports the violation, and "off" disables the rule. Context
+// policies are applied on top of Rules, and subject policies on top of their
+// context's.
+type LintingConfig struct {
+	Rules    map[string]string            `yaml:"rules"`    // Rule -> severity in every context
+	Contexts map[string]map[string]string `yaml:"contexts"` // Context name -> rule -> severity
+	Subjects map[string]map[string]string `yaml:"subjects"` // Subject, optionally context-qualified (":.ctx:subject") -> rule -> severity
+}
+
+// NormalizationConfig represents schema normalization profile configuration.
+// Profiles only take effect when normalization is enabled for a request,
+// subject, or context.
+type NormalizationConfig struct {
+	DefaultProfile string                                `yaml:"default_profile"` // Profile used by contexts without a mapping (default: built-in)
+	Contexts       map[string]string                     `yaml:"contexts"`        // Context name -> profile name
+	Profiles       map[string]NormalizationProfileConfig `yaml:"profiles"`
+}
+
+// NormalizationProfileConfig represents a single named normalization profile.
+type NormalizationProfileConfig struct {
+	JSONStripAnnotations bool `yaml:"json_strip_annotations"` // Strip title, description, examples and $comment from JSON Schemas
+	ProtobufKeepComments bool `yaml:"protobuf_keep_comments"` // Keep leading comments in normalized Protobuf schemas
+}
+
+// ExportersConfig represents the schema exporter (schema linking) replication worker configuration.
+type ExportersConfig struct {
+	Enabled        bool `yaml:"enabled"`         // Run the replication worker for RUNNING exporters
+	PollInterval   int  `yaml:"poll_interval"`   // Seconds between replication passes (default: 10)
+	RequestTimeout int  `yaml:"request_timeout"` // Timeout in seconds for requests to the remote registry (default: 30)
+}
+
+// JobsConfig represents the async job worker configuration.
+type JobsConfig struct {
+	Workers   int    `yaml:"workers"`    // Jobs run at the same time on this instance (default: 4)
+	QueueSize int    `yaml:"queue_size"` // Jobs that may wait for a free worker (default: 100)
+	Retention string `yaml:"retention"`  // How long finished jobs are kept, e.g. "7d" (default: "7d")
+}
+
+// ConsistencyConfig represents the periodic storage consistency check.
+type ConsistencyConfig struct {
+	CheckInterval string `yaml:"check_interval"` // How often storage is checked for invariant violations, e.g. "24h" (default: disabled)
+	Repair        bool   `yaml:"repair"`         // Repair what can be repaired safely (ID sequences behind the highest ID) instead of only reporting
+}
+
+// ApprovalConfig represents the schema approval workflow. In the listed
+// contexts, new schemas registered by users with the developer role are held
+// as pending schemas until an admin other than the submitter approves or
+// rejects them under /admin/pending-schemas. A pending schema is assigned
+// the reviewers its subject declares in its meta, or else those listed for
+// its context in Reviewers; only assigned reviewers may review it.
+type ApprovalConfig struct {
+	Contexts  []string            `yaml:"contexts"`  // Contexts whose developer registrations need approval (default: none)
+	Reviewers map[string][]string `yaml:"reviewers"` // Context name -> usernames and role:<role> entries reviewing its pending schemas
+}
+
+// ContextsConfig represents whether writes create the contexts they name. By
+// default, as in Confluent Schema Registry, the first write to a context
+// creates it. With AutoCreate false, writes to a context that was not created
+// with POST /contexts are refused, except in the default context and the
+// Allowed contexts.
+type ContextsConfig struct {
+	AutoCreate *bool    `yaml:"auto_create"` // Create a context on the first write to it (default: true)
+	Allowed    []string `yaml:"allowed"`     // Contexts still created on write when auto_create is false (default: none)
+}
+
+// DeletesConfig represents the safety mode for permanent deletes. With
+// RequireConfirmation, DELETE ...?permanent=true only returns a confirmation
+// token, and the delete happens when a super admin repeats it with the token
+// within ConfirmationTTL.
+type DeletesConfig struct {
+	RequireConfirmation bool   `yaml:"require_confirmation"` // Make permanent deletes two-phase (default: false)
+	ConfirmationTTL     string `yaml:"confirmation_ttl"`     // How long a confirmation token is valid, e.g. "5m" (default: "5m")
+}
+
+// QuotasConfig represents registration quotas. Each rule applies to the
+// principals and roles it names, optionally only in some contexts, and a
+// registration must be within every rule that applies to it. Registrations
+// that return an existing version are not counted. Counters are kept in
+// memory by each instance and can be inspected and reset under /admin/quotas.
+type QuotasConfig struct {
+	Rules []QuotaRuleConfig `yaml:"rules"`
+}
+
+// QuotaRuleConfig represents one registration quota. A zero limit is
+// unlimited.
+type QuotaRuleConfig struct {
+	Name                  string   `yaml:"name"`                     // Identifies the rule in errors and under /admin/quotas (required, unique)
+	Principals            []string `yaml:"principals"`               // Usernames the rule applies to
+	Roles                 []string `yaml:"roles"`                    // Roles whose users the rule applies to
+	Contexts              []string `yaml:"contexts"`                 // Contexts the rule applies in (default: all)
+	MaxVersionsPerDay     int      `yaml:"max_versions_per_day"`     // New schema versions each principal may register per UTC day
+	MaxSubjectsPerContext int      `yaml:"max_subjects_per_context"` // New subjects the principals together may create in each context
+}
+
+// UsageConfig represents schema usage analytics configuration.
+type UsageConfig struct {
+	Enabled       bool   `yaml:"enabled"`        // Count schema fetches per schema ID and subject version
+	FlushInterval string `yaml:"flush_interval"` // How often counts are written to storage, e.g. "1m" (default: "1m")
+	TrackClients  bool   `yaml:"track_clients"`  // Count requests per client library and version from the User-Agent header
 }
 
 // MCPConfig represents MCP (Model Context Protocol) server configuration.
@@ -28,6 +226,7 @@ type MCPConfig struct {
 	Host                 string   `yaml:"host"`
 	Port                 int      `yaml:"port"`
 	AuthToken            string   `yaml:"auth_token"`            // Bearer token for v1 auth
+	RegistryAuth         bool     `yaml:"registry_auth"`         // Authenticate clients with registry credentials and enforce their RBAC role instead of auth_token
 	ReadOnly             bool     `yaml:"read_only"`             // Restrict to read-only tools
 	ToolPolicy           string   `yaml:"tool_policy"`           // "allow_all" (default), "deny_list", "allow_list"
 	AllowedTools         []string `yaml:"allowed_tools"`         // Tools to allow (for allow_list mode)
@@ -41,17 +240,55 @@ type MCPConfig struct {
 	ReadHeaderTimeout    int      `yaml:"read_header_timeout"`   // HTTP ReadHeaderTimeout in seconds (default: 10)
 }
 
+// GRPCConfig represents the gRPC API server configuration. The gRPC API
+// shares the registry, authentication and TLS settings of the HTTP server.
+type GRPCConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	Host           string `yaml:"host"`
+	Port           int    `yaml:"port"`
+	MaxMessageSize int    `yaml:"max_message_size"` // Maximum request and response size in bytes (default: 0, gRPC default of 4 MiB)
+	Reflection     bool   `yaml:"reflection"`       // Register the gRPC server reflection service (default: false)
+}
+
 // ServerConfig represents HTTP server configuration.
 type ServerConfig struct {
-	Host                   string `yaml:"host"`
-	Port                   int    `yaml:"port"`
-	ReadTimeout            int    `yaml:"read_timeout"`
-	WriteTimeout           int    `yaml:"write_timeout"`
-	ShutdownTimeout        int    `yaml:"shutdown_timeout"` // Graceful shutdown timeout in seconds (default: 30)
-	DocsEnabled            bool   `yaml:"docs_enabled"`
-	ClusterID              string `yaml:"cluster_id"`
-	MaxRequestBodySize     int64  `yaml:"max_request_body_size"`
-	MetricsRefreshInterval int    `yaml:"metrics_refresh_interval"` // Gauge metrics refresh interval in seconds (default: 300)
+	Host                   string       `yaml:"host"`
+	Port                   int          `yaml:"port"`
+	ReadTimeout            int          `yaml:"read_timeout"`
+	WriteTimeout           int          `yaml:"write_timeout"`
+	ShutdownTimeout        int          `yaml:"shutdown_timeout"` // Graceful shutdown timeout in seconds (default: 30)
+	DocsEnabled            bool         `yaml:"docs_enabled"`
+	ClusterID              string       `yaml:"cluster_id"`
+	MaxRequestBodySize     int64        `yaml:"max_request_body_size"`
+	MetricsRefreshInterval int          `yaml:"metrics_refresh_interval"` // Gauge metrics refresh interval in seconds (default: 300)
+	MaxVersionsPageSize    int          `yaml:"max_versions_page_size"`   // Soft cap on versions returned per /versions request (default: 0, unlimited)
+	MaxListPageSize        int          `yaml:"max_list_page_size"`       // Soft cap on entries returned per /subjects or /schemas request (default: 0, unlimited)
+	RequestValidation      string       `yaml:"request_validation"`       // Validate JSON request bodies against the OpenAPI spec: off, on, strict (default: off)
+	Health                 HealthConfig `yaml:"health"`
+
+	// Idempotency replays the first response to a mutating request sent
+	// with an Idempotency-Key header when the request is retried.
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+}
+
+// IdempotencyConfig represents the Idempotency-Key request deduplication.
+// Responses are remembered by the instance that answered the request.
+type IdempotencyConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	TTL        string `yaml:"ttl"`         // How long a response is replayed for, e.g. "24h" (default: "24h")
+	MaxEntries int    `yaml:"max_entries"` // Maximum remembered responses; the oldest are dropped first (default: 10000)
+}
+
+// HealthDependencies are the dependencies GET /health/ready reports on.
+// Storage always decides readiness; the others only do when listed in
+// HealthConfig.Critical.
+var HealthDependencies = []string{"storage", "ldap", "oidc", "kms"}
+
+// HealthConfig represents the readiness check configuration.
+type HealthConfig struct {
+	Timeout    int      `yaml:"timeout"`     // Seconds each dependency check may take (default: 2)
+	Critical   []string `yaml:"critical"`    // Dependencies besides storage whose failure makes the registry not ready: ldap, oidc, kms (default: none)
+	ShowErrors bool     `yaml:"show_errors"` // Include dependency error messages in the readiness response (default: false)
 }
 
 // StorageConfig represents storage backend configuration.
@@ -62,6 +299,86 @@ type StorageConfig struct {
 	MySQL      MySQLConfig      `yaml:"mysql"`
 	Cassandra  CassandraConfig  `yaml:"cassandra"`
 	Vault      VaultConfig      `yaml:"vault"`
+
+	// SoftDeleteRetention is how long soft-deleted versions are kept before
+	// the purge worker permanently deletes them, e.g. "30d" or "720h".
+	// Empty disables purging.
+	SoftDeleteRetention string `yaml:"soft_delete_retention"`
+	// SoftDeletePurgeInterval is how often the purge worker runs (default: "1h").
+	SoftDeletePurgeInterval string `yaml:"soft_delete_purge_interval"`
+
+	// Cache is the optional in-process read-through cache in front of the
+	// storage backend.
+	Cache StorageCacheConfig `yaml:"cache"`
+
+	// Encryption optionally encrypts schema text at rest.
+	Encryption StorageEncryptionConfig `yaml:"encryption"`
+
+	// Fallback optionally serves reads from a local snapshot while the
+	// storage backend is unavailable.
+	Fallback StorageFallbackConfig `yaml:"fallback"`
+
+	// WriteGate optionally refuses writes while the backend's health
+	// signals say they could be lost in a failover.
+	WriteGate StorageWriteGateConfig `yaml:"write_gate"`
+}
+
+// StorageWriteGateConfig represents the write gate, which measures the
+// storage backend's health signals every CheckInterval and refuses writes,
+// as if the registry were READONLY, while any of them is above its
+// threshold. Writes are accepted again once every signal is back under its
+// threshold. A threshold of "0" turns its signal off; signals of other
+// backends are ignored.
+type StorageWriteGateConfig struct {
+	Enabled               bool   `yaml:"enabled"`
+	CheckInterval         string `yaml:"check_interval"`          // How often the signals are measured, e.g. "10s" (default: "10s")
+	CheckTimeout          string `yaml:"check_timeout"`           // How long one measurement may take (default: "5s")
+	MaxReplicationLag     string `yaml:"max_replication_lag"`     // PostgreSQL: highest replay lag of a standby (default: "30s")
+	MaxCoordinatorLatency string `yaml:"max_coordinator_latency"` // Cassandra: round trip of a query to the coordinator (default: "1s")
+}
+
+// StorageFallbackConfig represents the local snapshot of schemas, configs
+// and modes that reads are served from while the storage backend is down.
+// The snapshot is refreshed from the backend every RefreshInterval and kept
+// in the file at Path, so an instance restarted during an outage can serve
+// it too.
+type StorageFallbackConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Path            string `yaml:"path"`             // Snapshot file (default: "schema-registry-snapshot.json")
+	RefreshInterval string `yaml:"refresh_interval"` // How often the snapshot is refreshed, e.g. "5m" (default: "5m")
+	CheckInterval   string `yaml:"check_interval"`   // How often the backend is checked, e.g. "10s" (default: "10s")
+}
+
+// StorageEncryptionConfig represents envelope encryption of schema text at
+// rest. Each instance seals schemas with a data key of its own, stored
+// wrapped with a key encryption key (KEK) next to every schema it sealed.
+// The KEK is either one of Keys, named by ActiveKey, or a key of a Vault
+// Transit engine, named by Vault.KeyName. Keys that are no longer active
+// stay in Keys until every schema has been re-encrypted, so that schemas
+// sealed under them can still be read.
+type StorageEncryptionConfig struct {
+	Enabled   bool                         `yaml:"enabled"`
+	Keys      map[string]string            `yaml:"keys"`       // KEK name -> base64-encoded 32-byte key
+	ActiveKey string                       `yaml:"active_key"` // Name of the key in Keys that wraps new data keys
+	Vault     StorageEncryptionVaultConfig `yaml:"vault"`
+}
+
+// StorageEncryptionVaultConfig represents a Vault Transit key that wraps the
+// data keys schemas are encrypted with.
+type StorageEncryptionVaultConfig struct {
+	Address      string `yaml:"address"`       // Vault server address (default: VAULT_ADDR env)
+	Token        string `yaml:"token"`         // Vault token (default: VAULT_TOKEN env)
+	Namespace    string `yaml:"namespace"`     // Vault namespace (enterprise feature)
+	TransitMount string `yaml:"transit_mount"` // Transit engine mount path (default: "transit")
+	KeyName      string `yaml:"key_name"`      // Transit key that wraps new data keys
+}
+
+// StorageCacheConfig represents the read-through cache for schemas by ID,
+// schemas by subject version, and subject configs.
+type StorageCacheConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	MaxEntries int    `yaml:"max_entries"` // Maximum cached reads (default: 10000)
+	TTL        string `yaml:"ttl"`         // How long a read is served from the cache, e.g. "1m" (default: "1m")
 }
 
 // PostgreSQLConfig represents PostgreSQL connection configuration.
@@ -78,6 +395,11 @@ type PostgreSQLConfig struct {
 	ConnectTimeout     int    `yaml:"connect_timeout"`      // Initial connection ping timeout in seconds (default: 5)
 	HealthCheckTimeout int    `yaml:"health_check_timeout"` // Health check timeout in seconds (default: 2)
 	SchemaMaxRetries   int    `yaml:"schema_max_retries"`   // Max retries for schema creation (default: 15)
+
+	// DeltaEncoding stores new schema versions as deltas against the
+	// previous version of their subject, with periodic full snapshots.
+	DeltaEncoding         bool `yaml:"delta_encoding"`
+	DeltaSnapshotInterval int  `yaml:"delta_snapshot_interval"` // Store every Nth version of a chain in full (default: 10)
 }
 
 // MySQLConfig represents MySQL connection configuration.
@@ -87,13 +409,50 @@ type MySQLConfig struct {
 	Database           string `yaml:"database"`
 	User               string `yaml:"user"`
 	Password           string `yaml:"password"`
-	TLS                string `yaml:"tls"` // true, false, skip-verify, preferred
+	TLS                string `yaml:"tls"`             // true, false, skip-verify, preferred
+	TLSCAFile          string `yaml:"tls_ca_file"`     // CA bundle to verify the server certificate with (default: system roots)
+	TLSCertFile        string `yaml:"tls_cert_file"`   // Client certificate, for accounts requiring X509
+	TLSKeyFile         string `yaml:"tls_key_file"`    // Client certificate key
+	TLSServerName      string `yaml:"tls_server_name"` // Name to verify the server certificate against (default: host)
 	MaxOpenConns       int    `yaml:"max_open_conns"`
 	MaxIdleConns       int    `yaml:"max_idle_conns"`
 	ConnMaxLifetime    int    `yaml:"conn_max_lifetime"`    // seconds
 	ConnectTimeout     int    `yaml:"connect_timeout"`      // Initial connection ping timeout in seconds (default: 5)
 	HealthCheckTimeout int    `yaml:"health_check_timeout"` // Health check timeout in seconds (default: 2)
 	SchemaMaxRetries   int    `yaml:"schema_max_retries"`   // Max retries for schema creation (default: 15)
+
+	// DeltaEncoding stores new schema versions as deltas against the
+	// previous version of their subject, with periodic full snapshots.
+	DeltaEncoding         bool `yaml:"delta_encoding"`
+	DeltaSnapshotInterval int  `yaml:"delta_snapshot_interval"` // Store every Nth version of a chain in full (default: 10)
+
+	// IAMAuth authenticates with AWS RDS IAM tokens generated from the AWS
+	// credentials of the process instead of Password. It requires TLS.
+	IAMAuth   bool   `yaml:"iam_auth"`
+	IAMRegion string `yaml:"iam_region"` // AWS region of the database (default: AWS_REGION)
+}
+
+// validate checks the TLS and IAM authentication settings.
+func (m MySQLConfig) validate() error {
+	if m.TLSCAFile != "" || m.TLSCertFile != "" || m.TLSKeyFile != "" || m.TLSServerName != "" {
+		switch m.TLS {
+		case "true", "skip-verify", "preferred":
+		default:
+			return fmt.Errorf("storage.mysql.tls_* settings require storage.mysql.tls true, skip-verify or preferred")
+		}
+	}
+	if (m.TLSCertFile == "") != (m.TLSKeyFile == "") {
+		return fmt.Errorf("storage.mysql.tls_cert_file and storage.mysql.tls_key_file must be set together")
+	}
+	if m.IAMAuth {
+		if m.TLS != "true" && m.TLS != "skip-verify" {
+			return fmt.Errorf("storage.mysql.iam_auth requires storage.mysql.tls true or skip-verify")
+		}
+		if m.Password != "" {
+			return fmt.Errorf("storage.mysql.password cannot be set with storage.mysql.iam_auth")
+		}
+	}
+	return nil
 }
 
 // CassandraConfig represents Cassandra connection configuration.
@@ -112,6 +471,90 @@ type CassandraConfig struct {
 	ConnectTimeout    string   `yaml:"connect_timeout"` // Connection timeout (e.g., "10s")
 	MaxRetries        int      `yaml:"max_retries"`     // Max retries for CAS operations
 	IDBlockSize       int      `yaml:"id_block_size"`   // IDs reserved per LWT call
+
+	// Replication is used when the registry creates the keyspace. An
+	// existing keyspace is not altered.
+	Replication CassandraReplicationConfig `yaml:"replication"`
+
+	HostSelectionPolicy string `yaml:"host_selection_policy"` // token_aware, dc_aware or round_robin (default: dc_aware with local_dc, else round_robin)
+
+	TLSEnabled    bool   `yaml:"tls_enabled"`     // Encrypt connections to the nodes (implied by any TLS file)
+	TLSCAFile     string `yaml:"tls_ca_file"`     // CA certificate verifying the nodes (default: system roots)
+	TLSCertFile   string `yaml:"tls_cert_file"`   // Client certificate, for client authentication
+	TLSKeyFile    string `yaml:"tls_key_file"`    // Client key, for client authentication
+	TLSServerName string `yaml:"tls_server_name"` // Name the nodes' certificates are verified against
+	TLSSkipVerify bool   `yaml:"tls_skip_verify"` // Skip TLS verification (not recommended)
+
+	RetryPolicy     string `yaml:"retry_policy"`      // simple or exponential (default: no query retries)
+	QueryRetries    int    `yaml:"query_retries"`     // Retries of a failed query (default: 3)
+	RetryMinBackoff string `yaml:"retry_min_backoff"` // First backoff of the exponential policy (default: "100ms")
+	RetryMaxBackoff string `yaml:"retry_max_backoff"` // Largest backoff of the exponential policy (default: "10s")
+}
+
+// validateDriver checks the host selection, TLS and retry settings.
+func (c CassandraConfig) validateDriver() error {
+	switch c.HostSelectionPolicy {
+	case "", "token_aware", "round_robin":
+	case "dc_aware":
+		if c.LocalDC == "" {
+			return fmt.Errorf("storage.cassandra.host_selection_policy dc_aware requires storage.cassandra.local_dc")
+		}
+	default:
+		return fmt.Errorf("invalid storage.cassandra.host_selection_policy: %q (must be token_aware, dc_aware or round_robin)", c.HostSelectionPolicy)
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("storage.cassandra.tls_cert_file and tls_key_file must be set together")
+	}
+	switch c.RetryPolicy {
+	case "", "simple", "exponential":
+	default:
+		return fmt.Errorf("invalid storage.cassandra.retry_policy: %q (must be simple or exponential)", c.RetryPolicy)
+	}
+	if c.QueryRetries < 0 {
+		return fmt.Errorf("invalid storage.cassandra.query_retries: %d", c.QueryRetries)
+	}
+	for key, v := range map[string]string{"retry_min_backoff": c.RetryMinBackoff, "retry_max_backoff": c.RetryMaxBackoff} {
+		if v == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.cassandra.%s: %q", key, v)
+		}
+	}
+	return nil
+}
+
+// CassandraReplicationConfig is the replication of the Cassandra keyspace.
+type CassandraReplicationConfig struct {
+	Strategy    string         `yaml:"strategy"`    // SimpleStrategy (default) or NetworkTopologyStrategy
+	Factor      int            `yaml:"factor"`      // SimpleStrategy replication factor (default: 1)
+	Datacenters map[string]int `yaml:"datacenters"` // NetworkTopologyStrategy replicas per datacenter
+}
+
+// validate checks the replication settings. Datacenter names are checked by
+// the Cassandra backend.
+func (r CassandraReplicationConfig) validate() error {
+	switch r.Strategy {
+	case "", "SimpleStrategy":
+		if r.Factor < 0 {
+			return fmt.Errorf("invalid storage.cassandra.replication.factor: %d", r.Factor)
+		}
+		if len(r.Datacenters) > 0 {
+			return fmt.Errorf("storage.cassandra.replication.datacenters requires strategy NetworkTopologyStrategy")
+		}
+	case "NetworkTopologyStrategy":
+		if len(r.Datacenters) == 0 {
+			return fmt.Errorf("storage.cassandra.replication.datacenters is required with NetworkTopologyStrategy")
+		}
+		for dc, n := range r.Datacenters {
+			if n <= 0 {
+				return fmt.Errorf("invalid storage.cassandra.replication.datacenters.%s: %d (must be positive)", dc, n)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid storage.cassandra.replication.strategy: %q (must be SimpleStrategy or NetworkTopologyStrategy)", r.Strategy)
+	}
+	return nil
 }
 
 // VaultConfig represents HashiCorp Vault connection configuration.
@@ -125,11 +568,38 @@ type VaultConfig struct {
 	TLSKeyFile    string `yaml:"tls_key_file"`    // Path to client key for TLS auth
 	TLSCAFile     string `yaml:"tls_ca_file"`     // Path to CA certificate
 	TLSSkipVerify bool   `yaml:"tls_skip_verify"` // Skip TLS verification (not recommended)
+
+	// DualWrite mirrors users and API keys, without their password and key
+	// hashes, into the main database when auth_type is vault, so that SQL
+	// tooling and reports still see them. Credentials stay in Vault only.
+	DualWrite bool `yaml:"dual_write"`
+	// ConsistencyCheckInterval is how often the mirror is reconciled with
+	// Vault when DualWrite is enabled (e.g., "1h"). Empty disables it.
+	ConsistencyCheckInterval string `yaml:"consistency_check_interval"`
 }
 
 // CompatibilityConfig represents compatibility checking configuration.
 type CompatibilityConfig struct {
-	DefaultLevel string `yaml:"default_level"`
+	DefaultLevel string                 `yaml:"default_level"`
+	JSONSchema   JSONSchemaCompatConfig `yaml:"json_schema"`
+	Avro         AvroCompatConfig       `yaml:"avro"`
+}
+
+// AvroCompatConfig configures Avro compatibility checking.
+type AvroCompatConfig struct {
+	// LogicalTypes is how changes of logical types that the structural check
+	// accepts are reported, such as a narrowed decimal precision or
+	// timestamp-millis becoming timestamp-micros: "error", "warning"
+	// (default) or "off".
+	LogicalTypes string `yaml:"logical_types"`
+}
+
+// JSONSchemaCompatConfig configures JSON Schema compatibility checking.
+type JSONSchemaCompatConfig struct {
+	// Strictness is "strict" (default) or "lenient". Lenient checking reports
+	// changes it cannot decide, such as a changed pattern, as warnings
+	// instead of incompatibilities.
+	Strictness string `yaml:"strictness"`
 }
 
 // LoggingConfig represents logging configuration.
@@ -138,6 +608,18 @@ type LoggingConfig struct {
 	Format string `yaml:"format"` // json, text
 }
 
+// TracingConfig represents OpenTelemetry tracing. Spans for HTTP requests,
+// registry operations and storage calls are sent to an OTLP/HTTP collector.
+// Incoming W3C traceparent headers are honoured whether or not tracing is
+// enabled, so request logs carry the caller's trace ID.
+type TracingConfig struct {
+	Enabled     bool              `yaml:"enabled"`      // Record and export spans
+	Endpoint    string            `yaml:"endpoint"`     // OTLP/HTTP collector URL, e.g. "http://localhost:4318"; spans are posted to /v1/traces
+	Headers     map[string]string `yaml:"headers"`      // Headers sent with every export, e.g. a collector API key
+	ServiceName string            `yaml:"service_name"` // service.name resource attribute (default: "axonops-schema-registry")
+	SampleRatio float64           `yaml:"sample_ratio"` // Fraction of new traces recorded, 0 to 1 (default: 1). Traces started by a caller follow its sampling decision
+}
+
 // SecurityConfig represents security configuration.
 type SecurityConfig struct {
 	TLS          TLSConfig       `yaml:"tls"`
@@ -154,6 +636,15 @@ type SecurityMetrics struct {
 	// This adds a `principal` label to metrics, which MAY increase cardinality.
 	// Default: true.
 	PerPrincipalMetrics *bool `yaml:"per_principal_metrics"`
+
+	// ScrapeToken, when set, protects /metrics with a static bearer token
+	// that is independent of the main auth stack, for Prometheus scrapers
+	// that cannot use the configured auth methods.
+	ScrapeToken string `yaml:"scrape_token"`
+	// AllowedCIDRs, when set, lets clients from these networks read /metrics
+	// without a token. The connecting peer address is checked, not
+	// X-Forwarded-For, so the list cannot be bypassed with headers.
+	AllowedCIDRs []string `yaml:"allowed_cidrs"`
 }
 
 // TLSConfig represents TLS configuration.
@@ -172,14 +663,21 @@ type TLSConfig struct {
 // AuthConfig represents authentication configuration.
 type AuthConfig struct {
 	Enabled   bool            `yaml:"enabled"`
-	Methods   []string        `yaml:"methods"` // basic, api_key, jwt, oidc
+	Methods   []string        `yaml:"methods"` // basic, api_key, jwt, oidc, mtls
 	Bootstrap BootstrapConfig `yaml:"bootstrap"`
-	Basic     BasicAuthConfig `yaml:"basic"`
-	LDAP      LDAPConfig      `yaml:"ldap"`
-	OIDC      OIDCConfig      `yaml:"oidc"`
-	APIKey    APIKeyConfig    `yaml:"api_key"`
-	JWT       JWTConfig       `yaml:"jwt"`
-	RBAC      RBACConfig      `yaml:"rbac"`
+	// PasswordPolicy applies to the passwords of users stored in the
+	// registry's database.
+	PasswordPolicy PasswordPolicyConfig `yaml:"password_policy"`
+	// Sessions lets clients exchange credentials for short-lived access
+	// tokens with POST /auth/login.
+	Sessions SessionConfig   `yaml:"sessions"`
+	Basic    BasicAuthConfig `yaml:"basic"`
+	LDAP     LDAPConfig      `yaml:"ldap"`
+	OIDC     OIDCConfig      `yaml:"oidc"`
+	APIKey   APIKeyConfig    `yaml:"api_key"`
+	JWT      JWTConfig       `yaml:"jwt"`
+	MTLS     MTLSConfig      `yaml:"mtls"`
+	RBAC     RBACConfig      `yaml:"rbac"`
 }
 
 // BootstrapConfig represents initial admin user bootstrap configuration.
@@ -199,6 +697,54 @@ type BootstrapConfig struct {
 	Password string `yaml:"password"`
 	// Email for the bootstrap admin user (optional).
 	Email string `yaml:"email"`
+	// RequirePasswordChange forces the bootstrap admin to change the
+	// password on first login before doing anything else.
+	RequirePasswordChange bool `yaml:"require_password_change"`
+}
+
+// PasswordPolicyConfig represents the policy that passwords of database
+// users must satisfy when they are set. Passwords set before a policy is
+// tightened keep working until they are changed or expire.
+type PasswordPolicyConfig struct {
+	// MinLength is the minimum number of characters. Default 8.
+	MinLength int `yaml:"min_length"`
+	// RequireUppercase, RequireLowercase, RequireDigit and RequireSymbol
+	// require at least one character of the class.
+	RequireUppercase bool `yaml:"require_uppercase"`
+	RequireLowercase bool `yaml:"require_lowercase"`
+	RequireDigit     bool `yaml:"require_digit"`
+	RequireSymbol    bool `yaml:"require_symbol"`
+	// BannedPasswords are refused regardless of case.
+	BannedPasswords []string `yaml:"banned_passwords"`
+	// ExpiryDays is how many days a password stays valid before it must be
+	// changed. 0 means passwords do not expire.
+	ExpiryDays int `yaml:"expiry_days"`
+	// HistorySize is how many previous passwords may not be reused.
+	// 0 only refuses the current password when changing it.
+	HistorySize int `yaml:"history_size"`
+}
+
+// SessionConfig represents login session configuration. A login exchanges
+// basic credentials or an API key for a signed JWT access token, accepted
+// by every API until it expires, and a refresh token that obtains new
+// access tokens until the session is revoked or expires.
+type SessionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Algorithm signs the access tokens: HS256 (default) or RS256.
+	Algorithm string `yaml:"algorithm"`
+	// Secret is the HS256 signing key, at least 32 bytes. Set it the same
+	// on every instance, preferably via SCHEMA_REGISTRY_SESSION_SECRET.
+	Secret string `yaml:"secret"`
+	// PrivateKeyFile is the PEM RSA private key that signs RS256 tokens.
+	PrivateKeyFile string `yaml:"private_key_file"`
+	// Issuer is the iss claim of the access tokens. Default
+	// "axonops-schema-registry".
+	Issuer string `yaml:"issuer"`
+	// AccessTTL is how long an access token is valid. Default 15m.
+	AccessTTL string `yaml:"access_ttl"`
+	// RefreshTTL is how long a session lasts without being refreshed.
+	// Default 24h.
+	RefreshTTL string `yaml:"refresh_ttl"`
 }
 
 // BasicAuthConfig represents basic authentication configuration.
@@ -245,6 +791,7 @@ type OIDCConfig struct {
 	RoleMapping       map[string]string `yaml:"role_mapping"`       // OIDC role -> registry role
 	DefaultRole       string            `yaml:"default_role"`       // Role if no mapping
 	RequiredAudience  string            `yaml:"required_audience"`  // aud claim validation
+	TenantClaim       string            `yaml:"tenant_claim"`       // Claim naming the user's tenant (e.g., tenant, org.id)
 	AllowedAlgorithms []string          `yaml:"allowed_algorithms"` // RS256, ES256
 	SkipIssuerCheck   bool              `yaml:"skip_issuer_check"`  // For testing only
 	SkipExpiryCheck   bool              `yaml:"skip_expiry_check"`  // For testing only
@@ -267,6 +814,14 @@ type APIKeyConfig struct {
 	// CacheRefreshSeconds is how often (in seconds) the API key cache is refreshed
 	// from the database. This ensures cluster consistency. Default is 60 seconds.
 	CacheRefreshSeconds int `yaml:"cache_refresh_seconds"`
+	// ConsistencyCheckSeconds is how often (in seconds) the cached API keys,
+	// user credentials and grants are compared with the database, reporting
+	// and correcting any divergence. Default is 86400 (daily); 0 disables it.
+	ConsistencyCheckSeconds int `yaml:"consistency_check_seconds"`
+	// UsageFlushSeconds is how often (in seconds) the requests counted per
+	// API key, and their last-used times, are written to the database.
+	// Default is 30; 0 uses the default.
+	UsageFlushSeconds int `yaml:"usage_flush_seconds"`
 	// Keys defines API keys in config (used when storage_type is "memory").
 	Keys []ConfigAPIKey `yaml:"keys"`
 }
@@ -289,13 +844,103 @@ type JWTConfig struct {
 	DefaultRole   string            `yaml:"default_role"`   // Fallback role when no claim matches (default: "readonly")
 	JWKSCacheTTL  int               `yaml:"jwks_cache_ttl"` // JWKS cache TTL in seconds (default: 300)
 	HTTPTimeout   int               `yaml:"http_timeout"`   // JWKS HTTP client timeout in seconds (default: 10)
+	TenantClaim   string            `yaml:"tenant_claim"`   // Claim naming the user's tenant (e.g., tenant)
+
+	// Issuers lists further trusted token issuers, each with its own keys,
+	// audience and claims mapping. A token is verified by the issuer its
+	// iss claim names; JWKSCacheTTL and HTTPTimeout apply to all of them.
+	Issuers []JWTIssuerConfig `yaml:"issuers"`
+}
+
+// JWTIssuerConfig configures one trusted JWT issuer.
+type JWTIssuerConfig struct {
+	Issuer        string            `yaml:"issuer"` // Required; matched against the iss claim
+	Audience      string            `yaml:"audience"`
+	JWKSURL       string            `yaml:"jwks_url"`
+	PublicKeyFile string            `yaml:"public_key_file"`
+	Algorithm     string            `yaml:"algorithm"` // RS256, ES256
+	ClaimsMapping map[string]string `yaml:"claims_mapping"`
+	DefaultRole   string            `yaml:"default_role"` // Fallback role when no claim matches (default: "readonly")
+	TenantClaim   string            `yaml:"tenant_claim"`
+}
+
+// TrustedIssuers returns the issuers JWT authentication accepts: the one
+// configured by the top-level fields, when it has a JWKS URL or public key
+// file, followed by Issuers.
+func (c JWTConfig) TrustedIssuers() []JWTIssuerConfig {
+	var issuers []JWTIssuerConfig
+	if c.JWKSURL != "" || c.PublicKeyFile != "" {
+		issuers = append(issuers, JWTIssuerConfig{
+			Issuer:        c.Issuer,
+			Audience:      c.Audience,
+			JWKSURL:       c.JWKSURL,
+			PublicKeyFile: c.PublicKeyFile,
+			Algorithm:     c.Algorithm,
+			ClaimsMapping: c.ClaimsMapping,
+			DefaultRole:   c.DefaultRole,
+			TenantClaim:   c.TenantClaim,
+		})
+	}
+	return append(issuers, c.Issuers...)
+}
+
+// validate checks that each listed issuer has a unique issuer name and a
+// source of keys.
+func (c JWTConfig) validate() error {
+	seen := make(map[string]bool)
+	if c.JWKSURL != "" || c.PublicKeyFile != "" {
+		seen[c.Issuer] = true
+	}
+	for i, iss := range c.Issuers {
+		if iss.Issuer == "" {
+			return fmt.Errorf("security.auth.jwt.issuers[%d].issuer is required", i)
+		}
+		if seen[iss.Issuer] {
+			return fmt.Errorf("security.auth.jwt.issuers[%d]: issuer %q is configured more than once", i, iss.Issuer)
+		}
+		seen[iss.Issuer] = true
+		if iss.JWKSURL == "" && iss.PublicKeyFile == "" {
+			return fmt.Errorf("security.auth.jwt.issuers[%d] (%s): jwks_url or public_key_file is required", i, iss.Issuer)
+		}
+		if iss.PublicKeyFile != "" && iss.Algorithm == "" {
+			return fmt.Errorf("security.auth.jwt.issuers[%d] (%s): algorithm is required with public_key_file", i, iss.Issuer)
+		}
+	}
+	return nil
+}
+
+// MTLSConfig maps verified client certificates to registry principals for the
+// "mtls" authentication method. Certificate subject DNs are written as Go
+// formats them, e.g. "CN=orders-service,OU=Platform,O=Acme".
+type MTLSConfig struct {
+	UserMapping map[string]string `yaml:"user_mapping"` // Subject DN -> database username, whose role is used
+	RoleRules   []MTLSRoleRule    `yaml:"role_rules"`   // Checked in order for DNs not in user_mapping
+	DefaultRole string            `yaml:"default_role"` // Role if no rule matches; empty rejects the certificate
+}
+
+// MTLSRoleRule assigns a role to client certificates whose subject DN or any
+// subject alternative name matches a regular expression.
+type MTLSRoleRule struct {
+	Match string `yaml:"match"` // e.g. ^CN=.*,OU=Platform,O=Acme$ or ^spiffe://acme/ns/payments/
+	Role  string `yaml:"role"`
 }
 
 // RBACConfig represents RBAC configuration.
 type RBACConfig struct {
-	Enabled     bool     `yaml:"enabled"`
-	DefaultRole string   `yaml:"default_role"`
-	SuperAdmins []string `yaml:"super_admins"` // Users with full access
+	Enabled     bool         `yaml:"enabled"`
+	DefaultRole string       `yaml:"default_role"`
+	SuperAdmins []string     `yaml:"super_admins"` // Users with full access
+	Policy      PolicyConfig `yaml:"policy"`
+}
+
+// PolicyConfig configures an external policy decision point. When a URL is
+// set, the decision it returns for each authorized request replaces the
+// built-in role check. Requests use the OPA data API format, so an OPA
+// server loaded with a Rego policy can answer them directly.
+type PolicyConfig struct {
+	URL     string            `yaml:"url"`     // Decision endpoint, e.g. "http://localhost:8181/v1/data/schema_registry/allow"
+	Headers map[string]string `yaml:"headers"` // Headers sent with every decision request, e.g. a bearer token
+	Timeout string            `yaml:"timeout"` // Time each decision may take (default: "2s"); requests are denied when it is exceeded
 }
 
 // RateLimitConfig represents rate limiting configuration.
@@ -315,6 +960,14 @@ type AuditConfig struct {
 	IncludeBody bool               `yaml:"include_body"`
 	BufferSize  int                `yaml:"buffer_size"` // Async channel buffer size (default: 10000, 0 = sync)
 	Outputs     AuditOutputsConfig `yaml:"outputs"`
+	History     AuditHistoryConfig `yaml:"history"`
+}
+
+// AuditHistoryConfig configures the in-memory history of recent audit events
+// that backs the GET /admin/audit query API.
+type AuditHistoryConfig struct {
+	Enabled   bool `yaml:"enabled"`
+	MaxEvents int  `yaml:"max_events"` // Most recent events retained per instance (default: 10000)
 }
 
 // AuditOutputsConfig represents the multi-output audit configuration.
@@ -378,21 +1031,64 @@ func DefaultConfig() *Config {
 			ReadTimeout:     30,
 			WriteTimeout:    30,
 			ShutdownTimeout: 30,
+			Idempotency: IdempotencyConfig{
+				TTL:        "24h",
+				MaxEntries: 10000,
+			},
 		},
 		Storage: StorageConfig{
 			Type: "memory",
+			Cache: StorageCacheConfig{
+				MaxEntries: 10000,
+				TTL:        "1m",
+			},
+			Fallback: StorageFallbackConfig{
+				Path:            "schema-registry-snapshot.json",
+				RefreshInterval: "5m",
+				CheckInterval:   "10s",
+			},
+			WriteGate: StorageWriteGateConfig{
+				CheckInterval:         "10s",
+				CheckTimeout:          "5s",
+				MaxReplicationLag:     "30s",
+				MaxCoordinatorLatency: "1s",
+			},
+			Vault: VaultConfig{
+				ConsistencyCheckInterval: "1h",
+			},
 		},
 		Compatibility: CompatibilityConfig{
 			DefaultLevel: "BACKWARD",
+			JSONSchema: JSONSchemaCompatConfig{
+				Strictness: "strict",
+			},
+			Avro: AvroCompatConfig{
+				LogicalTypes: "warning",
+			},
 		},
 		Logging: LoggingConfig{
 			Level:  "info",
 			Format: "json",
 		},
+		Tracing: TracingConfig{
+			ServiceName: "axonops-schema-registry",
+			SampleRatio: 1,
+		},
 		Security: SecurityConfig{
 			Auth: AuthConfig{
+				PasswordPolicy: PasswordPolicyConfig{
+					MinLength: 8,
+				},
+				Sessions: SessionConfig{
+					Algorithm:  "HS256",
+					Issuer:     "axonops-schema-registry",
+					AccessTTL:  "15m",
+					RefreshTTL: "24h",
+				},
 				APIKey: APIKeyConfig{
-					CacheRefreshSeconds: 60, // Default to 60 seconds, 0 means disabled
+					CacheRefreshSeconds:     60,    // Default to 60 seconds, 0 means disabled
+					ConsistencyCheckSeconds: 86400, // Daily, 0 means disabled
+					UsageFlushSeconds:       30,
 				},
 			},
 		},
@@ -405,6 +1101,35 @@ func DefaultConfig() *Config {
 				"vscode-webview://*",
 			},
 		},
+		GRPC: GRPCConfig{
+			Host: "0.0.0.0",
+			Port: 9090,
+		},
+		Exporters: ExportersConfig{
+			PollInterval:   10,
+			RequestTimeout: 30,
+		},
+		Jobs: JobsConfig{
+			Workers:   4,
+			QueueSize: 100,
+			Retention: "7d",
+		},
+		Usage: UsageConfig{
+			FlushInterval: "1m",
+		},
+		References: ReferencesConfig{
+			MaxDepth: 32,
+		},
+		Tenancy: TenancyConfig{
+			ContextMode:    "owned",
+			OverrideHeader: "X-Registry-Tenant",
+		},
+		IDs: IDsConfig{
+			Scope: IDScopeContext,
+		},
+		SubjectAliases: SubjectAliasesConfig{
+			WritePolicy: AliasWriteFollow,
+		},
 	}
 }
 
@@ -440,6 +1165,57 @@ func Load(path string) (*Config, error) {
 	return cfg, nil
 }
 
+// Reload returns c, the configuration the server runs with, updated with
+// the settings of next, a newly loaded configuration, that a reload puts
+// into effect: logging.level, compatibility.default_level,
+// security.rate_limiting and, when authentication is enabled in both, the
+// LDAP, OIDC and JWT settings, security.auth.basic.htpasswd_file and
+// security.auth.api_key.keys. Turning LDAP or OIDC on or off is not
+// reloaded. applied lists the reloaded settings that changed;
+// restartRequired lists the top-level sections with other changes, which
+// only take effect after a restart.
+func (c *Config) Reload(next *Config) (reloaded *Config, applied, restartRequired []string) {
+	r := *c
+	changed := func(key string, differs bool) {
+		if differs {
+			applied = append(applied, key)
+		}
+	}
+
+	changed("logging.level", c.Logging.Level != next.Logging.Level)
+	r.Logging.Level = next.Logging.Level
+	changed("compatibility.default_level", c.Compatibility.DefaultLevel != next.Compatibility.DefaultLevel)
+	r.Compatibility.DefaultLevel = next.Compatibility.DefaultLevel
+	changed("security.rate_limiting", c.Security.RateLimiting != next.Security.RateLimiting)
+	r.Security.RateLimiting = next.Security.RateLimiting
+
+	if cur, nxt := c.Security.Auth, next.Security.Auth; cur.Enabled && nxt.Enabled {
+		if cur.LDAP.Enabled == nxt.LDAP.Enabled {
+			changed("security.auth.ldap", !reflect.DeepEqual(cur.LDAP, nxt.LDAP))
+			r.Security.Auth.LDAP = nxt.LDAP
+		}
+		if cur.OIDC.Enabled == nxt.OIDC.Enabled {
+			changed("security.auth.oidc", !reflect.DeepEqual(cur.OIDC, nxt.OIDC))
+			r.Security.Auth.OIDC = nxt.OIDC
+		}
+		changed("security.auth.jwt", !reflect.DeepEqual(cur.JWT, nxt.JWT))
+		r.Security.Auth.JWT = nxt.JWT
+		changed("security.auth.basic.htpasswd_file", cur.Basic.HTPasswd != nxt.Basic.HTPasswd)
+		r.Security.Auth.Basic.HTPasswd = nxt.Basic.HTPasswd
+		changed("security.auth.api_key.keys", !reflect.DeepEqual(cur.APIKey.Keys, nxt.APIKey.Keys))
+		r.Security.Auth.APIKey.Keys = nxt.APIKey.Keys
+	}
+
+	// What still differs from next needs a restart.
+	have, want := reflect.ValueOf(r), reflect.ValueOf(*next)
+	for i := 0; i < have.NumField(); i++ {
+		if !reflect.DeepEqual(have.Field(i).Interface(), want.Field(i).Interface()) {
+			restartRequired = append(restartRequired, strings.Split(have.Type().Field(i).Tag.Get("yaml"), ",")[0])
+		}
+	}
+	return &r, applied, restartRequired
+}
+
 // envInt parses an integer from an env var value, logging a warning on failure.
 func envInt(envVar, value string) (int, bool) {
 	n, err := strconv.Atoi(value)
@@ -468,6 +1244,20 @@ func envInt64(envVar, value string) (int64, bool) {
 	return n, true
 }
 
+// envFloat parses a float64 from an env var value, logging a warning on failure.
+func envFloat(envVar, value string) (float64, bool) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Warn("ignoring invalid env var value",
+			slog.String("var", envVar),
+			slog.String("value", value),
+			slog.String("error", err.Error()),
+		)
+		return 0, false
+	}
+	return f, true
+}
+
 // envJSON parses a JSON-encoded map[string]string from an env var, logging a warning on failure.
 func envJSON(envVar, value string) (map[string]string, bool) {
 	var m map[string]string
@@ -520,12 +1310,126 @@ func (c *Config) applyEnvOverrides() {
 			c.Server.MaxRequestBodySize = n
 		}
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MAX_VERSIONS_PAGE_SIZE"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_MAX_VERSIONS_PAGE_SIZE", v); ok {
+			c.Server.MaxVersionsPageSize = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MAX_LIST_PAGE_SIZE"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_MAX_LIST_PAGE_SIZE", v); ok {
+			c.Server.MaxListPageSize = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_REQUEST_VALIDATION"); v != "" {
+		c.Server.RequestValidation = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_IDEMPOTENCY_ENABLED"); v != "" {
+		c.Server.Idempotency.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_IDEMPOTENCY_TTL"); v != "" {
+		c.Server.Idempotency.TTL = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_IDEMPOTENCY_MAX_ENTRIES"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_IDEMPOTENCY_MAX_ENTRIES", v); ok {
+			c.Server.Idempotency.MaxEntries = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_HEALTH_TIMEOUT"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_HEALTH_TIMEOUT", v); ok {
+			c.Server.Health.Timeout = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_HEALTH_CRITICAL"); v != "" {
+		var deps []string
+		for _, dep := range strings.Split(v, ",") {
+			if dep = strings.TrimSpace(dep); dep != "" {
+				deps = append(deps, dep)
+			}
+		}
+		c.Server.Health.Critical = deps
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_TYPE"); v != "" {
 		c.Storage.Type = v
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SOFT_DELETE_RETENTION"); v != "" {
+		c.Storage.SoftDeleteRetention = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SOFT_DELETE_PURGE_INTERVAL"); v != "" {
+		c.Storage.SoftDeletePurgeInterval = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_CACHE_ENABLED"); v != "" {
+		c.Storage.Cache.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_CACHE_MAX_ENTRIES"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_STORAGE_CACHE_MAX_ENTRIES", v); ok {
+			c.Storage.Cache.MaxEntries = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_CACHE_TTL"); v != "" {
+		c.Storage.Cache.TTL = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_FALLBACK_ENABLED"); v != "" {
+		c.Storage.Fallback.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_FALLBACK_PATH"); v != "" {
+		c.Storage.Fallback.Path = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_FALLBACK_REFRESH_INTERVAL"); v != "" {
+		c.Storage.Fallback.RefreshInterval = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_FALLBACK_CHECK_INTERVAL"); v != "" {
+		c.Storage.Fallback.CheckInterval = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_ENABLED"); v != "" {
+		c.Storage.WriteGate.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_CHECK_INTERVAL"); v != "" {
+		c.Storage.WriteGate.CheckInterval = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_CHECK_TIMEOUT"); v != "" {
+		c.Storage.WriteGate.CheckTimeout = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_MAX_REPLICATION_LAG"); v != "" {
+		c.Storage.WriteGate.MaxReplicationLag = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_MAX_COORDINATOR_LATENCY"); v != "" {
+		c.Storage.WriteGate.MaxCoordinatorLatency = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_ENABLED"); v != "" {
+		c.Storage.Encryption.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_KEYS"); v != "" {
+		if m, ok := envJSON("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_KEYS", v); ok {
+			c.Storage.Encryption.Keys = m
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_ACTIVE_KEY"); v != "" {
+		c.Storage.Encryption.ActiveKey = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_VAULT_ADDRESS"); v != "" {
+		c.Storage.Encryption.Vault.Address = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_VAULT_TOKEN"); v != "" {
+		c.Storage.Encryption.Vault.Token = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_VAULT_NAMESPACE"); v != "" {
+		c.Storage.Encryption.Vault.Namespace = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_VAULT_TRANSIT_MOUNT"); v != "" {
+		c.Storage.Encryption.Vault.TransitMount = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_VAULT_KEY_NAME"); v != "" {
+		c.Storage.Encryption.Vault.KeyName = v
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_COMPATIBILITY_LEVEL"); v != "" {
 		c.Compatibility.DefaultLevel = v
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_JSON_SCHEMA_STRICTNESS"); v != "" {
+		c.Compatibility.JSONSchema.Strictness = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_AVRO_LOGICAL_TYPES"); v != "" {
+		c.Compatibility.Avro.LogicalTypes = v
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_LOG_LEVEL"); v != "" {
 		c.Logging.Level = v
 	}
@@ -533,6 +1437,27 @@ func (c *Config) applyEnvOverrides() {
 		c.Logging.Format = v
 	}
 
+	// Tracing overrides
+	if v := os.Getenv("SCHEMA_REGISTRY_TRACING_ENABLED"); v != "" {
+		c.Tracing.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_TRACING_ENDPOINT"); v != "" {
+		c.Tracing.Endpoint = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_TRACING_HEADERS"); v != "" {
+		if m, ok := envJSON("SCHEMA_REGISTRY_TRACING_HEADERS", v); ok {
+			c.Tracing.Headers = m
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_TRACING_SERVICE_NAME"); v != "" {
+		c.Tracing.ServiceName = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_TRACING_SAMPLE_RATIO"); v != "" {
+		if f, ok := envFloat("SCHEMA_REGISTRY_TRACING_SAMPLE_RATIO", v); ok {
+			c.Tracing.SampleRatio = f
+		}
+	}
+
 	// PostgreSQL overrides
 	if v := os.Getenv("SCHEMA_REGISTRY_PG_HOST"); v != "" {
 		c.Storage.PostgreSQL.Host = v
@@ -569,6 +1494,14 @@ func (c *Config) applyEnvOverrides() {
 			c.Storage.PostgreSQL.SchemaMaxRetries = n
 		}
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PG_DELTA_ENCODING"); v != "" {
+		c.Storage.PostgreSQL.DeltaEncoding = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PG_DELTA_SNAPSHOT_INTERVAL"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_PG_DELTA_SNAPSHOT_INTERVAL", v); ok {
+			c.Storage.PostgreSQL.DeltaSnapshotInterval = n
+		}
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_PG_MAX_OPEN_CONNS"); v != "" {
 		if n, ok := envInt("SCHEMA_REGISTRY_PG_MAX_OPEN_CONNS", v); ok {
 			c.Storage.PostgreSQL.MaxOpenConns = n
@@ -606,6 +1539,24 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_TLS"); v != "" {
 		c.Storage.MySQL.TLS = v
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_TLS_CA_FILE"); v != "" {
+		c.Storage.MySQL.TLSCAFile = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_TLS_CERT_FILE"); v != "" {
+		c.Storage.MySQL.TLSCertFile = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_TLS_KEY_FILE"); v != "" {
+		c.Storage.MySQL.TLSKeyFile = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_TLS_SERVER_NAME"); v != "" {
+		c.Storage.MySQL.TLSServerName = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_IAM_AUTH"); v != "" {
+		c.Storage.MySQL.IAMAuth = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_IAM_REGION"); v != "" {
+		c.Storage.MySQL.IAMRegion = v
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_CONNECT_TIMEOUT"); v != "" {
 		if n, ok := envInt("SCHEMA_REGISTRY_MYSQL_CONNECT_TIMEOUT", v); ok {
 			c.Storage.MySQL.ConnectTimeout = n
@@ -621,6 +1572,14 @@ func (c *Config) applyEnvOverrides() {
 			c.Storage.MySQL.SchemaMaxRetries = n
 		}
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_DELTA_ENCODING"); v != "" {
+		c.Storage.MySQL.DeltaEncoding = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_DELTA_SNAPSHOT_INTERVAL"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_MYSQL_DELTA_SNAPSHOT_INTERVAL", v); ok {
+			c.Storage.MySQL.DeltaSnapshotInterval = n
+		}
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_MYSQL_MAX_OPEN_CONNS"); v != "" {
 		if n, ok := envInt("SCHEMA_REGISTRY_MYSQL_MAX_OPEN_CONNS", v); ok {
 			c.Storage.MySQL.MaxOpenConns = n
@@ -690,6 +1649,61 @@ func (c *Config) applyEnvOverrides() {
 			c.Storage.Cassandra.IDBlockSize = n
 		}
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_REPLICATION_STRATEGY"); v != "" {
+		c.Storage.Cassandra.Replication.Strategy = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_REPLICATION_FACTOR"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_CASSANDRA_REPLICATION_FACTOR", v); ok {
+			c.Storage.Cassandra.Replication.Factor = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_REPLICATION_DATACENTERS"); v != "" {
+		var dcs map[string]int
+		if err := json.Unmarshal([]byte(v), &dcs); err != nil {
+			slog.Warn("ignoring invalid env var value (expected JSON object)",
+				slog.String("var", "SCHEMA_REGISTRY_CASSANDRA_REPLICATION_DATACENTERS"),
+				slog.String("value", v),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			c.Storage.Cassandra.Replication.Datacenters = dcs
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_HOST_SELECTION_POLICY"); v != "" {
+		c.Storage.Cassandra.HostSelectionPolicy = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_TLS_ENABLED"); v != "" {
+		c.Storage.Cassandra.TLSEnabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_TLS_CA_FILE"); v != "" {
+		c.Storage.Cassandra.TLSCAFile = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_TLS_CERT_FILE"); v != "" {
+		c.Storage.Cassandra.TLSCertFile = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_TLS_KEY_FILE"); v != "" {
+		c.Storage.Cassandra.TLSKeyFile = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_TLS_SERVER_NAME"); v != "" {
+		c.Storage.Cassandra.TLSServerName = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_TLS_SKIP_VERIFY"); v != "" {
+		c.Storage.Cassandra.TLSSkipVerify = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_RETRY_POLICY"); v != "" {
+		c.Storage.Cassandra.RetryPolicy = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_QUERY_RETRIES"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_CASSANDRA_QUERY_RETRIES", v); ok {
+			c.Storage.Cassandra.QueryRetries = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_RETRY_MIN_BACKOFF"); v != "" {
+		c.Storage.Cassandra.RetryMinBackoff = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CASSANDRA_RETRY_MAX_BACKOFF"); v != "" {
+		c.Storage.Cassandra.RetryMaxBackoff = v
+	}
 
 	// MCP overrides
 	if v := os.Getenv("SCHEMA_REGISTRY_MCP_ENABLED"); v != "" {
@@ -706,6 +1720,9 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("SCHEMA_REGISTRY_MCP_AUTH_TOKEN"); v != "" {
 		c.MCP.AuthToken = v
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_MCP_REGISTRY_AUTH"); v != "" {
+		c.MCP.RegistryAuth = strings.ToLower(v) == "true" || v == "1"
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_MCP_READ_ONLY"); v != "" {
 		c.MCP.ReadOnly = strings.ToLower(v) == "true" || v == "1"
 	}
@@ -760,11 +1777,157 @@ func (c *Config) applyEnvOverrides() {
 		}
 	}
 
+	// gRPC overrides
+	if v := os.Getenv("SCHEMA_REGISTRY_GRPC_ENABLED"); v != "" {
+		c.GRPC.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_GRPC_HOST"); v != "" {
+		c.GRPC.Host = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_GRPC_PORT"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_GRPC_PORT", v); ok {
+			c.GRPC.Port = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_GRPC_MAX_MESSAGE_SIZE"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_GRPC_MAX_MESSAGE_SIZE", v); ok {
+			c.GRPC.MaxMessageSize = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_GRPC_REFLECTION"); v != "" {
+		c.GRPC.Reflection = strings.ToLower(v) == "true" || v == "1"
+	}
+
 	// Docs enabled override
 	if v := os.Getenv("SCHEMA_REGISTRY_DOCS_ENABLED"); v != "" {
 		c.Server.DocsEnabled = strings.ToLower(v) == "true" || v == "1"
 	}
 
+	// Exporter replication worker
+	if v := os.Getenv("SCHEMA_REGISTRY_EXPORTERS_ENABLED"); v != "" {
+		c.Exporters.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_EXPORTERS_POLL_INTERVAL"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_EXPORTERS_POLL_INTERVAL", v); ok {
+			c.Exporters.PollInterval = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_EXPORTERS_REQUEST_TIMEOUT"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_EXPORTERS_REQUEST_TIMEOUT", v); ok {
+			c.Exporters.RequestTimeout = n
+		}
+	}
+
+	// Async job workers
+	if v := os.Getenv("SCHEMA_REGISTRY_JOBS_WORKERS"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_JOBS_WORKERS", v); ok {
+			c.Jobs.Workers = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_JOBS_QUEUE_SIZE"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_JOBS_QUEUE_SIZE", v); ok {
+			c.Jobs.QueueSize = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_JOBS_RETENTION"); v != "" {
+		c.Jobs.Retention = v
+	}
+
+	// Storage consistency check
+	if v := os.Getenv("SCHEMA_REGISTRY_CONSISTENCY_CHECK_INTERVAL"); v != "" {
+		c.Consistency.CheckInterval = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CONSISTENCY_REPAIR"); v != "" {
+		c.Consistency.Repair = strings.ToLower(v) == "true" || v == "1"
+	}
+
+	// Schema approval workflow
+	if v := os.Getenv("SCHEMA_REGISTRY_APPROVAL_CONTEXTS"); v != "" {
+		contexts := strings.Split(v, ",")
+		for i := range contexts {
+			contexts[i] = strings.TrimSpace(contexts[i])
+		}
+		c.Approval.Contexts = contexts
+	}
+
+	// Context auto-creation
+	if v := os.Getenv("SCHEMA_REGISTRY_CONTEXTS_AUTO_CREATE"); v != "" {
+		b := strings.ToLower(v) == "true" || v == "1"
+		c.Contexts.AutoCreate = &b
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_CONTEXTS_ALLOWED"); v != "" {
+		contexts := strings.Split(v, ",")
+		for i := range contexts {
+			contexts[i] = strings.TrimSpace(contexts[i])
+		}
+		c.Contexts.Allowed = contexts
+	}
+
+	// Two-phase permanent deletes
+	if v := os.Getenv("SCHEMA_REGISTRY_DELETES_REQUIRE_CONFIRMATION"); v != "" {
+		c.Deletes.RequireConfirmation = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_DELETES_CONFIRMATION_TTL"); v != "" {
+		c.Deletes.ConfirmationTTL = v
+	}
+
+	// Schema usage analytics
+	if v := os.Getenv("SCHEMA_REGISTRY_USAGE_ENABLED"); v != "" {
+		c.Usage.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_USAGE_FLUSH_INTERVAL"); v != "" {
+		c.Usage.FlushInterval = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_USAGE_TRACK_CLIENTS"); v != "" {
+		c.Usage.TrackClients = strings.ToLower(v) == "true" || v == "1"
+	}
+
+	// Normalization profile override
+	if v := os.Getenv("SCHEMA_REGISTRY_NORMALIZATION_DEFAULT_PROFILE"); v != "" {
+		c.Normalization.DefaultProfile = v
+	}
+
+	// Subject naming strategy override
+	if v := os.Getenv("SCHEMA_REGISTRY_SUBJECT_NAMING_STRATEGY"); v != "" {
+		c.SubjectNaming.DefaultStrategy = v
+	}
+
+	// Reference resolution limit override
+	if v := os.Getenv("SCHEMA_REGISTRY_REFERENCES_MAX_DEPTH"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_REFERENCES_MAX_DEPTH", v); ok {
+			c.References.MaxDepth = n
+		}
+	}
+
+	// Response fingerprint override
+	if v := os.Getenv("SCHEMA_REGISTRY_FINGERPRINT_ALGORITHM"); v != "" {
+		c.Fingerprints.Algorithm = v
+	}
+
+	// Schema ID scope override
+	if v := os.Getenv("SCHEMA_REGISTRY_IDS_SCOPE"); v != "" {
+		c.IDs.Scope = v
+	}
+
+	// Subject alias write policy override
+	if v := os.Getenv("SCHEMA_REGISTRY_SUBJECT_ALIAS_WRITE_POLICY"); v != "" {
+		c.SubjectAliases.WritePolicy = v
+	}
+
+	// Multi-tenancy overrides
+	if v := os.Getenv("SCHEMA_REGISTRY_TENANCY_ENABLED"); v != "" {
+		c.Tenancy.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_TENANCY_MASTER_KEY"); v != "" {
+		c.Tenancy.MasterKey = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_TENANCY_CONTEXT_MODE"); v != "" {
+		c.Tenancy.ContextMode = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_TENANCY_OVERRIDE_HEADER"); v != "" {
+		c.Tenancy.OverrideHeader = v
+	}
+
 	// Auth type override
 	if v := os.Getenv("SCHEMA_REGISTRY_AUTH_TYPE"); v != "" {
 		c.Storage.AuthType = v
@@ -783,6 +1946,70 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("SCHEMA_REGISTRY_BOOTSTRAP_EMAIL"); v != "" {
 		c.Security.Auth.Bootstrap.Email = v
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_BOOTSTRAP_REQUIRE_PASSWORD_CHANGE"); v != "" {
+		c.Security.Auth.Bootstrap.RequirePasswordChange = strings.ToLower(v) == "true" || v == "1"
+	}
+
+	// Password policy overrides
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_MIN_LENGTH"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_PASSWORD_MIN_LENGTH", v); ok {
+			c.Security.Auth.PasswordPolicy.MinLength = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_REQUIRE_UPPERCASE"); v != "" {
+		c.Security.Auth.PasswordPolicy.RequireUppercase = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_REQUIRE_LOWERCASE"); v != "" {
+		c.Security.Auth.PasswordPolicy.RequireLowercase = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_REQUIRE_DIGIT"); v != "" {
+		c.Security.Auth.PasswordPolicy.RequireDigit = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_REQUIRE_SYMBOL"); v != "" {
+		c.Security.Auth.PasswordPolicy.RequireSymbol = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_BANNED"); v != "" {
+		var banned []string
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				banned = append(banned, p)
+			}
+		}
+		c.Security.Auth.PasswordPolicy.BannedPasswords = banned
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_EXPIRY_DAYS"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_PASSWORD_EXPIRY_DAYS", v); ok {
+			c.Security.Auth.PasswordPolicy.ExpiryDays = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_PASSWORD_HISTORY_SIZE"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_PASSWORD_HISTORY_SIZE", v); ok {
+			c.Security.Auth.PasswordPolicy.HistorySize = n
+		}
+	}
+
+	// Login session overrides
+	if v := os.Getenv("SCHEMA_REGISTRY_SESSION_ENABLED"); v != "" {
+		c.Security.Auth.Sessions.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SESSION_ALGORITHM"); v != "" {
+		c.Security.Auth.Sessions.Algorithm = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SESSION_SECRET"); v != "" {
+		c.Security.Auth.Sessions.Secret = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SESSION_PRIVATE_KEY_FILE"); v != "" {
+		c.Security.Auth.Sessions.PrivateKeyFile = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SESSION_ISSUER"); v != "" {
+		c.Security.Auth.Sessions.Issuer = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SESSION_ACCESS_TTL"); v != "" {
+		c.Security.Auth.Sessions.AccessTTL = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_SESSION_REFRESH_TTL"); v != "" {
+		c.Security.Auth.Sessions.RefreshTTL = v
+	}
 
 	// Vault overrides
 	if v := os.Getenv("SCHEMA_REGISTRY_VAULT_ADDRESS"); v != "" {
@@ -820,6 +2047,12 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("SCHEMA_REGISTRY_VAULT_TLS_SKIP_VERIFY"); v != "" {
 		c.Storage.Vault.TLSSkipVerify = strings.ToLower(v) == "true" || v == "1"
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_VAULT_DUAL_WRITE"); v != "" {
+		c.Storage.Vault.DualWrite = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_VAULT_CONSISTENCY_CHECK_INTERVAL"); v != "" {
+		c.Storage.Vault.ConsistencyCheckInterval = v
+	}
 
 	// JWT overrides
 	if v := os.Getenv("SCHEMA_REGISTRY_JWT_ISSUER"); v != "" {
@@ -837,6 +2070,9 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("SCHEMA_REGISTRY_JWT_ALGORITHM"); v != "" {
 		c.Security.Auth.JWT.Algorithm = v
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_JWT_TENANT_CLAIM"); v != "" {
+		c.Security.Auth.JWT.TenantClaim = v
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_JWT_DEFAULT_ROLE"); v != "" {
 		c.Security.Auth.JWT.DefaultRole = v
 	}
@@ -954,6 +2190,9 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("SCHEMA_REGISTRY_OIDC_USERNAME_CLAIM"); v != "" {
 		c.Security.Auth.OIDC.UsernameClaim = v
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_OIDC_TENANT_CLAIM"); v != "" {
+		c.Security.Auth.OIDC.TenantClaim = v
+	}
 	if v := os.Getenv("SCHEMA_REGISTRY_OIDC_ROLES_CLAIM"); v != "" {
 		c.Security.Auth.OIDC.RolesClaim = v
 	}
@@ -1003,6 +2242,16 @@ func (c *Config) applyEnvOverrides() {
 			c.Security.Auth.APIKey.CacheRefreshSeconds = n
 		}
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_API_KEY_CONSISTENCY_CHECK"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_API_KEY_CONSISTENCY_CHECK", v); ok {
+			c.Security.Auth.APIKey.ConsistencyCheckSeconds = n
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_API_KEY_USAGE_FLUSH"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_API_KEY_USAGE_FLUSH", v); ok {
+			c.Security.Auth.APIKey.UsageFlushSeconds = n
+		}
+	}
 
 	// Basic auth overrides
 	if v := os.Getenv("SCHEMA_REGISTRY_BASIC_REALM"); v != "" {
@@ -1038,6 +2287,17 @@ func (c *Config) applyEnvOverrides() {
 		}
 		c.Security.Auth.RBAC.SuperAdmins = admins
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_RBAC_POLICY_URL"); v != "" {
+		c.Security.Auth.RBAC.Policy.URL = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_RBAC_POLICY_HEADERS"); v != "" {
+		if m, ok := envJSON("SCHEMA_REGISTRY_RBAC_POLICY_HEADERS", v); ok {
+			c.Security.Auth.RBAC.Policy.Headers = m
+		}
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_RBAC_POLICY_TIMEOUT"); v != "" {
+		c.Security.Auth.RBAC.Policy.Timeout = v
+	}
 
 	// TLS overrides
 	if v := os.Getenv("SCHEMA_REGISTRY_TLS_ENABLED"); v != "" {
@@ -1116,6 +2376,16 @@ func (c *Config) applyEnvOverrides() {
 		c.Security.Audit.Events = events
 	}
 
+	// Audit history overrides
+	if v := os.Getenv("SCHEMA_REGISTRY_AUDIT_HISTORY_ENABLED"); v != "" {
+		c.Security.Audit.History.Enabled = strings.ToLower(v) == "true" || v == "1"
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_AUDIT_HISTORY_MAX_EVENTS"); v != "" {
+		if n, ok := envInt("SCHEMA_REGISTRY_AUDIT_HISTORY_MAX_EVENTS", v); ok {
+			c.Security.Audit.History.MaxEvents = n
+		}
+	}
+
 	// Audit stdout output overrides
 	if v := os.Getenv("SCHEMA_REGISTRY_AUDIT_STDOUT_ENABLED"); v != "" {
 		c.Security.Audit.Outputs.Stdout.Enabled = strings.ToLower(v) == "true" || v == "1"
@@ -1228,6 +2498,16 @@ func (c *Config) applyEnvOverrides() {
 		b := strings.ToLower(v) == "true" || v == "1"
 		c.Security.Metrics.PerPrincipalMetrics = &b
 	}
+	if v := os.Getenv("SCHEMA_REGISTRY_METRICS_SCRAPE_TOKEN"); v != "" {
+		c.Security.Metrics.ScrapeToken = v
+	}
+	if v := os.Getenv("SCHEMA_REGISTRY_METRICS_ALLOWED_CIDRS"); v != "" {
+		cidrs := strings.Split(v, ",")
+		for i := range cidrs {
+			cidrs[i] = strings.TrimSpace(cidrs[i])
+		}
+		c.Security.Metrics.AllowedCIDRs = cidrs
+	}
 }
 
 // Validate validates the configuration.
@@ -1235,6 +2515,44 @@ func (c *Config) Validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
+	if c.GRPC.Enabled {
+		if c.GRPC.Port < 1 || c.GRPC.Port > 65535 {
+			return fmt.Errorf("invalid grpc port: %d", c.GRPC.Port)
+		}
+		if c.GRPC.Port == c.Server.Port && c.GRPC.Host == c.Server.Host {
+			return fmt.Errorf("grpc.port must differ from server.port: %d", c.GRPC.Port)
+		}
+	}
+	if c.GRPC.MaxMessageSize < 0 {
+		return fmt.Errorf("invalid grpc.max_message_size: %d (must be >= 0)", c.GRPC.MaxMessageSize)
+	}
+	if c.Server.MaxVersionsPageSize < 0 {
+		return fmt.Errorf("invalid server.max_versions_page_size: %d (must be >= 0)", c.Server.MaxVersionsPageSize)
+	}
+	if c.Server.MaxListPageSize < 0 {
+		return fmt.Errorf("invalid server.max_list_page_size: %d (must be >= 0)", c.Server.MaxListPageSize)
+	}
+	switch c.Server.RequestValidation {
+	case "", "off", "on", "strict":
+	default:
+		return fmt.Errorf("invalid server.request_validation: %q (must be \"off\", \"on\" or \"strict\")", c.Server.RequestValidation)
+	}
+	if c.Server.Idempotency.Enabled {
+		if d, err := ParseDuration(c.Server.Idempotency.TTL); err != nil || d <= 0 {
+			return fmt.Errorf("invalid server.idempotency.ttl: %q (must be a positive duration)", c.Server.Idempotency.TTL)
+		}
+		if c.Server.Idempotency.MaxEntries <= 0 {
+			return fmt.Errorf("invalid server.idempotency.max_entries: %d (must be positive)", c.Server.Idempotency.MaxEntries)
+		}
+	}
+	if c.Server.Health.Timeout < 0 {
+		return fmt.Errorf("invalid server.health.timeout: %d (must be >= 0)", c.Server.Health.Timeout)
+	}
+	for _, dep := range c.Server.Health.Critical {
+		if !slices.Contains(HealthDependencies, dep) {
+			return fmt.Errorf("invalid server.health.critical entry: %q (must be one of %s)", dep, strings.Join(HealthDependencies, ", "))
+		}
+	}
 
 	validStorageTypes := map[string]bool{
 		"memory":     true,
@@ -1246,6 +2564,21 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid storage type: %s", c.Storage.Type)
 	}
 
+	if c.Storage.Type == "mysql" || c.Storage.AuthType == "mysql" {
+		if err := c.Storage.MySQL.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Storage.Type == "cassandra" {
+		if err := c.Storage.Cassandra.Replication.validate(); err != nil {
+			return err
+		}
+		if err := c.Storage.Cassandra.validateDriver(); err != nil {
+			return err
+		}
+	}
+
 	// Validate auth_type if set
 	if c.Storage.AuthType != "" {
 		validAuthTypes := map[string]bool{
@@ -1260,11 +2593,188 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Storage.SoftDeleteRetention != "" {
+		if d, err := ParseDuration(c.Storage.SoftDeleteRetention); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.soft_delete_retention: %q (must be a positive duration such as \"30d\" or \"720h\")", c.Storage.SoftDeleteRetention)
+		}
+	}
+	if c.Storage.SoftDeletePurgeInterval != "" {
+		if d, err := ParseDuration(c.Storage.SoftDeletePurgeInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.soft_delete_purge_interval: %q (must be a positive duration)", c.Storage.SoftDeletePurgeInterval)
+		}
+	}
+	if c.Storage.Cache.Enabled {
+		if c.Storage.Cache.MaxEntries <= 0 {
+			return fmt.Errorf("invalid storage.cache.max_entries: %d (must be positive)", c.Storage.Cache.MaxEntries)
+		}
+		if d, err := ParseDuration(c.Storage.Cache.TTL); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.cache.ttl: %q (must be a positive duration)", c.Storage.Cache.TTL)
+		}
+	}
+	if err := c.Storage.Encryption.validate(); err != nil {
+		return err
+	}
+	if c.Storage.Fallback.Enabled {
+		if c.Storage.Fallback.Path == "" {
+			return fmt.Errorf("storage.fallback.path is required when the fallback snapshot is enabled")
+		}
+		if d, err := ParseDuration(c.Storage.Fallback.RefreshInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.fallback.refresh_interval: %q (must be a positive duration)", c.Storage.Fallback.RefreshInterval)
+		}
+		if d, err := ParseDuration(c.Storage.Fallback.CheckInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.fallback.check_interval: %q (must be a positive duration)", c.Storage.Fallback.CheckInterval)
+		}
+	}
+	if c.Storage.WriteGate.Enabled {
+		if d, err := ParseDuration(c.Storage.WriteGate.CheckInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.write_gate.check_interval: %q (must be a positive duration)", c.Storage.WriteGate.CheckInterval)
+		}
+		if d, err := ParseDuration(c.Storage.WriteGate.CheckTimeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid storage.write_gate.check_timeout: %q (must be a positive duration)", c.Storage.WriteGate.CheckTimeout)
+		}
+		if d, err := ParseDuration(c.Storage.WriteGate.MaxReplicationLag); err != nil || d < 0 {
+			return fmt.Errorf("invalid storage.write_gate.max_replication_lag: %q (must be a duration, 0 to disable)", c.Storage.WriteGate.MaxReplicationLag)
+		}
+		if d, err := ParseDuration(c.Storage.WriteGate.MaxCoordinatorLatency); err != nil || d < 0 {
+			return fmt.Errorf("invalid storage.write_gate.max_coordinator_latency: %q (must be a duration, 0 to disable)", c.Storage.WriteGate.MaxCoordinatorLatency)
+		}
+	}
+
+	if c.Jobs.Workers < 0 {
+		return fmt.Errorf("invalid jobs.workers: %d (must not be negative)", c.Jobs.Workers)
+	}
+	if c.Jobs.QueueSize < 0 {
+		return fmt.Errorf("invalid jobs.queue_size: %d (must not be negative)", c.Jobs.QueueSize)
+	}
+	if c.Jobs.Retention != "" {
+		if d, err := ParseDuration(c.Jobs.Retention); err != nil || d <= 0 {
+			return fmt.Errorf("invalid jobs.retention: %q (must be a positive duration such as \"7d\" or \"168h\")", c.Jobs.Retention)
+		}
+	}
+	if c.Consistency.CheckInterval != "" {
+		if d, err := ParseDuration(c.Consistency.CheckInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid consistency.check_interval: %q (must be a positive duration such as \"24h\")", c.Consistency.CheckInterval)
+		}
+	}
+	if c.Tracing.Enabled {
+		u, err := url.Parse(c.Tracing.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid tracing.endpoint: %q (must be an http or https URL when tracing is enabled)", c.Tracing.Endpoint)
+		}
+	}
+	if policy := c.Security.Auth.RBAC.Policy; policy.URL != "" {
+		u, err := url.Parse(policy.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid security.auth.rbac.policy.url: %q (must be an http or https URL)", policy.URL)
+		}
+		if policy.Timeout != "" {
+			if d, err := time.ParseDuration(policy.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("invalid security.auth.rbac.policy.timeout: %q (must be a positive duration such as \"2s\")", policy.Timeout)
+			}
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("invalid tracing.sample_ratio: %v (must be between 0 and 1)", c.Tracing.SampleRatio)
+	}
+	for _, name := range c.Approval.Contexts {
+		if name == "" {
+			return fmt.Errorf("invalid approval.contexts: context names must not be empty")
+		}
+	}
+	for name, reviewers := range c.Approval.Reviewers {
+		if name == "" {
+			return fmt.Errorf("invalid approval.reviewers: context names must not be empty")
+		}
+		for _, reviewer := range reviewers {
+			if strings.TrimSpace(strings.TrimPrefix(reviewer, "role:")) == "" {
+				return fmt.Errorf("invalid approval.reviewers for context %s: reviewers must name a user or a role", name)
+			}
+		}
+	}
+	for _, name := range c.Contexts.Allowed {
+		if name == "" {
+			return fmt.Errorf("invalid contexts.allowed: context names must not be empty")
+		}
+	}
+	if c.Deletes.ConfirmationTTL != "" {
+		if d, err := ParseDuration(c.Deletes.ConfirmationTTL); err != nil || d <= 0 {
+			return fmt.Errorf("invalid deletes.confirmation_ttl: %q (must be a positive duration such as \"5m\")", c.Deletes.ConfirmationTTL)
+		}
+	}
+	quotaNames := make(map[string]bool, len(c.Quotas.Rules))
+	for _, rule := range c.Quotas.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("invalid quotas.rules: every rule needs a name")
+		}
+		if quotaNames[rule.Name] {
+			return fmt.Errorf("invalid quotas.rules: duplicate rule name %q", rule.Name)
+		}
+		quotaNames[rule.Name] = true
+		if len(rule.Principals) == 0 && len(rule.Roles) == 0 {
+			return fmt.Errorf("invalid quotas rule %q: principals or roles are required", rule.Name)
+		}
+		if rule.MaxVersionsPerDay < 0 || rule.MaxSubjectsPerContext < 0 {
+			return fmt.Errorf("invalid quotas rule %q: limits must not be negative", rule.Name)
+		}
+	}
+	if c.Usage.FlushInterval != "" {
+		if d, err := ParseDuration(c.Usage.FlushInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid usage.flush_interval: %q (must be a positive duration)", c.Usage.FlushInterval)
+		}
+	}
+
+	// mTLS identities are only trusted from certificates verified against ca_file
+	if c.Security.Auth.Enabled && slices.Contains(c.Security.Auth.Methods, "mtls") &&
+		(!c.Security.TLS.Enabled || c.Security.TLS.ClientAuth != "verify") {
+		return fmt.Errorf("auth method mtls requires security.tls.enabled and security.tls.client_auth: verify")
+	}
+
+	if err := c.Security.Auth.JWT.validate(); err != nil {
+		return err
+	}
+
+	if p := c.Security.Auth.PasswordPolicy; p.MinLength < 0 || p.ExpiryDays < 0 || p.HistorySize < 0 {
+		return fmt.Errorf("security.auth.password_policy min_length, expiry_days and history_size must not be negative")
+	}
+
+	if sc := c.Security.Auth.Sessions; sc.Enabled {
+		switch strings.ToUpper(sc.Algorithm) {
+		case "", "HS256":
+			if len(sc.Secret) < 32 {
+				return fmt.Errorf("security.auth.sessions.secret must be at least 32 bytes for HS256")
+			}
+		case "RS256":
+			if sc.PrivateKeyFile == "" {
+				return fmt.Errorf("security.auth.sessions.private_key_file is required for RS256")
+			}
+		default:
+			return fmt.Errorf("invalid security.auth.sessions.algorithm: %q (must be HS256 or RS256)", sc.Algorithm)
+		}
+		if d, err := ParseDuration(sc.AccessTTL); sc.AccessTTL != "" && (err != nil || d <= 0) {
+			return fmt.Errorf("invalid security.auth.sessions.access_ttl: %q (must be a positive duration)", sc.AccessTTL)
+		}
+		if d, err := ParseDuration(sc.RefreshTTL); sc.RefreshTTL != "" && (err != nil || d <= 0) {
+			return fmt.Errorf("invalid security.auth.sessions.refresh_ttl: %q (must be a positive duration)", sc.RefreshTTL)
+		}
+	}
+
 	// Validate Vault config if auth_type is vault
 	if c.Storage.AuthType == "vault" {
 		if c.Storage.Vault.Address == "" {
 			return fmt.Errorf("vault address is required when auth_type is vault")
 		}
+		if c.Storage.Vault.DualWrite {
+			if c.Storage.Type == "memory" {
+				return fmt.Errorf("storage.vault.dual_write requires a database storage type")
+			}
+			if c.Storage.Vault.ConsistencyCheckInterval != "" {
+				if d, err := ParseDuration(c.Storage.Vault.ConsistencyCheckInterval); err != nil || d <= 0 {
+					return fmt.Errorf("invalid storage.vault.consistency_check_interval: %q (must be a positive duration)", c.Storage.Vault.ConsistencyCheckInterval)
+				}
+			}
+		}
+	} else if c.Storage.Vault.DualWrite {
+		return fmt.Errorf("storage.vault.dual_write requires auth_type: vault")
 	}
 
 	validCompatibility := map[string]bool{
@@ -1280,12 +2790,154 @@ func (c *Config) Validate() error {
 	if !validCompatibility[level] {
 		return fmt.Errorf("invalid compatibility level: %s", c.Compatibility.DefaultLevel)
 	}
+	switch c.Compatibility.JSONSchema.Strictness {
+	case "", "strict", "lenient":
+	default:
+		return fmt.Errorf("invalid compatibility.json_schema.strictness: %q (must be \"strict\" or \"lenient\")", c.Compatibility.JSONSchema.Strictness)
+	}
+	switch c.Compatibility.Avro.LogicalTypes {
+	case "", "error", "warning", "off":
+	default:
+		return fmt.Errorf("invalid compatibility.avro.logical_types: %q (must be \"error\", \"warning\" or \"off\")", c.Compatibility.Avro.LogicalTypes)
+	}
 
 	// Validate audit config
 	if err := c.validateAuditConfig(); err != nil {
 		return err
 	}
 
+	// Validate normalization profile references
+	if err := c.validateNormalizationConfig(); err != nil {
+		return err
+	}
+
+	// Validate subject naming strategies
+	if err := c.validateSubjectNamingConfig(); err != nil {
+		return err
+	}
+
+	// Validate lint policies
+	if err := c.validateLintingConfig(); err != nil {
+		return err
+	}
+
+	if c.References.MaxDepth < 0 {
+		return fmt.Errorf("references.max_depth must not be negative, got %d", c.References.MaxDepth)
+	}
+
+	switch c.Fingerprints.Algorithm {
+	case "", "md5", "sha256", "crc64-avro":
+	default:
+		return fmt.Errorf("invalid fingerprints.algorithm: %q (must be md5, sha256 or crc64-avro)", c.Fingerprints.Algorithm)
+	}
+
+	switch c.IDs.Scope {
+	case "", IDScopeContext, IDScopeGlobal:
+	default:
+		return fmt.Errorf("invalid ids.scope: %q (must be context or global)", c.IDs.Scope)
+	}
+
+	switch c.SubjectAliases.WritePolicy {
+	case "", AliasWriteFollow, AliasWriteIgnore, AliasWriteReject:
+	default:
+		return fmt.Errorf("invalid subject_aliases.write_policy: %q (must be follow, ignore or reject)", c.SubjectAliases.WritePolicy)
+	}
+
+	if c.Tenancy.Enabled {
+		key, err := base64.StdEncoding.DecodeString(c.Tenancy.MasterKey)
+		if c.Tenancy.MasterKey == "" || err != nil || len(key) != 32 {
+			return fmt.Errorf("tenancy.master_key must be a base64-encoded 32-byte key when tenancy is enabled")
+		}
+	}
+	switch c.Tenancy.ContextMode {
+	case "", "owned":
+	case "principal":
+		if c.Tenancy.OverrideHeader == "" {
+			return fmt.Errorf("tenancy.override_header is required when tenancy.context_mode is principal")
+		}
+	default:
+		return fmt.Errorf("invalid tenancy.context_mode: %q (must be owned or principal)", c.Tenancy.ContextMode)
+	}
+
+	for _, cidr := range c.Security.Metrics.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid security.metrics.allowed_cidrs entry %q: %w", cidr, err)
+		}
+	}
+
+	return nil
+}
+
+// validateSubjectNamingConfig checks that every configured subject naming strategy is known.
+func (c *Config) validateSubjectNamingConfig() error {
+	validStrategies := map[string]bool{
+		"":                  true,
+		"none":              true,
+		"topic_name":        true,
+		"record_name":       true,
+		"topic_record_name": true,
+	}
+	naming := &c.SubjectNaming
+	if !validStrategies[naming.DefaultStrategy] {
+		return fmt.Errorf("invalid subject_naming default_strategy %q (must be none, topic_name, record_name or topic_record_name)", naming.DefaultStrategy)
+	}
+	for ctxName, strategy := range naming.Contexts {
+		if !validStrategies[strategy] {
+			return fmt.Errorf("invalid subject_naming strategy %q for context %q (must be none, topic_name, record_name or topic_record_name)", strategy, ctxName)
+		}
+	}
+	return nil
+}
+
+// validateLintingConfig checks that every lint policy names known rules and severities.
+func (c *Config) validateLintingConfig() error {
+	validRules := map[string]bool{
+		"field_doc":              true,
+		"field_snake_case":       true,
+		"timestamp_logical_type": true,
+	}
+	validSeverities := map[string]bool{"off": true, "warn": true, "error": true}
+	check := func(where string, policy map[string]string) error {
+		for rule, severity := range policy {
+			if !validRules[rule] {
+				return fmt.Errorf("unknown linting rule %q in %s (must be field_doc, field_snake_case or timestamp_logical_type)", rule, where)
+			}
+			if !validSeverities[severity] {
+				return fmt.Errorf("invalid linting severity %q for rule %q in %s (must be off, warn or error)", severity, rule, where)
+			}
+		}
+		return nil
+	}
+	linting := &c.Linting
+	if err := check("linting.rules", linting.Rules); err != nil {
+		return err
+	}
+	for ctxName, policy := range linting.Contexts {
+		if err := check(fmt.Sprintf("linting.contexts[%q]", ctxName), policy); err != nil {
+			return err
+		}
+	}
+	for subject, policy := range linting.Subjects {
+		if err := check(fmt.Sprintf("linting.subjects[%q]", subject), policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNormalizationConfig checks that every referenced normalization profile is defined.
+func (c *Config) validateNormalizationConfig() error {
+	norm := &c.Normalization
+	if norm.DefaultProfile != "" {
+		if _, ok := norm.Profiles[norm.DefaultProfile]; !ok {
+			return fmt.Errorf("normalization default_profile %q is not defined in normalization.profiles", norm.DefaultProfile)
+		}
+	}
+	for ctxName, profile := range norm.Contexts {
+		if _, ok := norm.Profiles[profile]; !ok {
+			return fmt.Errorf("normalization profile %q for context %q is not defined in normalization.profiles", profile, ctxName)
+		}
+	}
 	return nil
 }
 
@@ -1323,15 +2975,73 @@ func (c *Config) validateAuditConfig() error {
 		return fmt.Errorf("audit webhook output enabled but no URL specified")
 	}
 
+	if audit.History.MaxEvents < 0 {
+		return fmt.Errorf("invalid audit history max_events: %d (must be >= 0)", audit.History.MaxEvents)
+	}
+
 	return nil
 }
 
+// ParseDuration parses a Go duration string, additionally accepting a whole
+// number of days with a "d" suffix (e.g. "30d").
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
 // Address returns the server address string.
 func (c *Config) Address() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
 }
 
+// GRPCAddress returns the gRPC server address string.
+func (c *Config) GRPCAddress() string {
+	return fmt.Sprintf("%s:%d", c.GRPC.Host, c.GRPC.Port)
+}
+
 // MCPAddress returns the MCP server address string.
 func (c *Config) MCPAddress() string {
 	return fmt.Sprintf("%s:%d", c.MCP.Host, c.MCP.Port)
 }
+
+// validate checks the encryption keys. Key names may only contain letters,
+// digits, '_' and '-', because they are recorded in every schema a data key
+// wrapped with them sealed.
+func (e *StorageEncryptionConfig) validate() error {
+	validName := func(name string) bool {
+		return name != "" && len(name) <= 64 && strings.IndexFunc(name, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-')
+		}) < 0
+	}
+	for name, encoded := range e.Keys {
+		if !validName(name) {
+			return fmt.Errorf("invalid storage.encryption.keys name %q (must be 1-64 letters, digits, '_' or '-')", name)
+		}
+		if key, err := base64.StdEncoding.DecodeString(encoded); err != nil || len(key) != 32 {
+			return fmt.Errorf("storage.encryption.keys.%s must be a base64-encoded 32-byte key", name)
+		}
+	}
+	if e.Vault.KeyName != "" && !validName(e.Vault.KeyName) {
+		return fmt.Errorf("invalid storage.encryption.vault.key_name %q (must be 1-64 letters, digits, '_' or '-')", e.Vault.KeyName)
+	}
+	if !e.Enabled {
+		return nil
+	}
+	switch {
+	case e.ActiveKey != "" && e.Vault.KeyName != "":
+		return fmt.Errorf("storage.encryption.active_key and storage.encryption.vault.key_name are mutually exclusive")
+	case e.ActiveKey != "":
+		if _, ok := e.Keys[e.ActiveKey]; !ok {
+			return fmt.Errorf("storage.encryption.active_key %q is not in storage.encryption.keys", e.ActiveKey)
+		}
+	case e.Vault.KeyName == "":
+		return fmt.Errorf("storage.encryption.active_key or storage.encryption.vault.key_name is required when storage encryption is enabled")
+	}
+	return nil
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -40,6 +43,14 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.MCP.LogSchemas {
 		t.Error("Expected LogSchemas to be false by default")
 	}
+
+	// gRPC defaults
+	if cfg.GRPC.Enabled {
+		t.Error("Expected gRPC to be disabled by default")
+	}
+	if cfg.GRPCAddress() != "0.0.0.0:9090" {
+		t.Errorf("Expected gRPC address 0.0.0.0:9090, got %s", cfg.GRPCAddress())
+	}
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -328,20 +339,22 @@ func TestConfig_EnvOverrides_MySQL(t *testing.T) {
 
 func TestConfig_EnvOverrides_Cassandra(t *testing.T) {
 	envVars := map[string]string{
-		"SCHEMA_REGISTRY_CASSANDRA_HOSTS":              "node1, node2, node3",
-		"SCHEMA_REGISTRY_CASSANDRA_PORT":               "9043",
-		"SCHEMA_REGISTRY_CASSANDRA_KEYSPACE":           "my_keyspace",
-		"SCHEMA_REGISTRY_CASSANDRA_LOCAL_DC":           "dc1",
-		"SCHEMA_REGISTRY_CASSANDRA_CONSISTENCY":        "QUORUM",
-		"SCHEMA_REGISTRY_CASSANDRA_READ_CONSISTENCY":   "LOCAL_ONE",
-		"SCHEMA_REGISTRY_CASSANDRA_WRITE_CONSISTENCY":  "LOCAL_QUORUM",
-		"SCHEMA_REGISTRY_CASSANDRA_SERIAL_CONSISTENCY": "LOCAL_SERIAL",
-		"SCHEMA_REGISTRY_CASSANDRA_USERNAME":           "cassuser",
-		"SCHEMA_REGISTRY_CASSANDRA_PASSWORD":           "casspass",
-		"SCHEMA_REGISTRY_CASSANDRA_TIMEOUT":            "15s",
-		"SCHEMA_REGISTRY_CASSANDRA_CONNECT_TIMEOUT":    "20s",
-		"SCHEMA_REGISTRY_CASSANDRA_MAX_RETRIES":        "100",
-		"SCHEMA_REGISTRY_CASSANDRA_ID_BLOCK_SIZE":      "200",
+		"SCHEMA_REGISTRY_CASSANDRA_HOSTS":                   "node1, node2, node3",
+		"SCHEMA_REGISTRY_CASSANDRA_PORT":                    "9043",
+		"SCHEMA_REGISTRY_CASSANDRA_KEYSPACE":                "my_keyspace",
+		"SCHEMA_REGISTRY_CASSANDRA_LOCAL_DC":                "dc1",
+		"SCHEMA_REGISTRY_CASSANDRA_CONSISTENCY":             "QUORUM",
+		"SCHEMA_REGISTRY_CASSANDRA_READ_CONSISTENCY":        "LOCAL_ONE",
+		"SCHEMA_REGISTRY_CASSANDRA_WRITE_CONSISTENCY":       "LOCAL_QUORUM",
+		"SCHEMA_REGISTRY_CASSANDRA_SERIAL_CONSISTENCY":      "LOCAL_SERIAL",
+		"SCHEMA_REGISTRY_CASSANDRA_USERNAME":                "cassuser",
+		"SCHEMA_REGISTRY_CASSANDRA_PASSWORD":                "casspass",
+		"SCHEMA_REGISTRY_CASSANDRA_TIMEOUT":                 "15s",
+		"SCHEMA_REGISTRY_CASSANDRA_CONNECT_TIMEOUT":         "20s",
+		"SCHEMA_REGISTRY_CASSANDRA_MAX_RETRIES":             "100",
+		"SCHEMA_REGISTRY_CASSANDRA_ID_BLOCK_SIZE":           "200",
+		"SCHEMA_REGISTRY_CASSANDRA_REPLICATION_STRATEGY":    "NetworkTopologyStrategy",
+		"SCHEMA_REGISTRY_CASSANDRA_REPLICATION_DATACENTERS": `{"dc1":3,"dc2":2}`,
 	}
 	for k, v := range envVars {
 		os.Setenv(k, v)
@@ -406,6 +419,96 @@ func TestConfig_EnvOverrides_Cassandra(t *testing.T) {
 	if cfg.Storage.Cassandra.IDBlockSize != 200 {
 		t.Errorf("Expected 200, got %d", cfg.Storage.Cassandra.IDBlockSize)
 	}
+	if r := cfg.Storage.Cassandra.Replication; r.Strategy != "NetworkTopologyStrategy" || r.Datacenters["dc2"] != 2 {
+		t.Errorf("Expected NetworkTopologyStrategy with dc2: 2, got %+v", r)
+	}
+}
+
+func TestValidate_CassandraReplication(t *testing.T) {
+	tests := []struct {
+		name        string
+		replication CassandraReplicationConfig
+		wantErr     bool
+	}{
+		{"default", CassandraReplicationConfig{}, false},
+		{"simple", CassandraReplicationConfig{Strategy: "SimpleStrategy", Factor: 3}, false},
+		{"network topology", CassandraReplicationConfig{Strategy: "NetworkTopologyStrategy", Datacenters: map[string]int{"dc1": 3}}, false},
+		{"unknown strategy", CassandraReplicationConfig{Strategy: "EverywhereStrategy"}, true},
+		{"no datacenters", CassandraReplicationConfig{Strategy: "NetworkTopologyStrategy"}, true},
+		{"zero replicas", CassandraReplicationConfig{Strategy: "NetworkTopologyStrategy", Datacenters: map[string]int{"dc1": 0}}, true},
+		{"datacenters with SimpleStrategy", CassandraReplicationConfig{Datacenters: map[string]int{"dc1": 3}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.Type = "cassandra"
+			cfg.Storage.Cassandra.Replication = tt.replication
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_CassandraDriver(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*CassandraConfig)
+		wantErr bool
+	}{
+		{"default", func(c *CassandraConfig) {}, false},
+		{"token aware", func(c *CassandraConfig) { c.HostSelectionPolicy = "token_aware" }, false},
+		{"dc aware", func(c *CassandraConfig) { c.HostSelectionPolicy, c.LocalDC = "dc_aware", "dc1" }, false},
+		{"dc aware without local dc", func(c *CassandraConfig) { c.HostSelectionPolicy = "dc_aware" }, true},
+		{"unknown policy", func(c *CassandraConfig) { c.HostSelectionPolicy = "nearest" }, true},
+		{"client cert without key", func(c *CassandraConfig) { c.TLSCertFile = "client.pem" }, true},
+		{"exponential retry", func(c *CassandraConfig) { c.RetryPolicy, c.RetryMaxBackoff = "exponential", "5s" }, false},
+		{"unknown retry policy", func(c *CassandraConfig) { c.RetryPolicy = "forever" }, true},
+		{"negative retries", func(c *CassandraConfig) { c.QueryRetries = -1 }, true},
+		{"invalid backoff", func(c *CassandraConfig) { c.RetryMinBackoff = "soon" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.Type = "cassandra"
+			tt.mutate(&cfg.Storage.Cassandra)
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_EnvOverrides_CassandraDriver(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_HOST_SELECTION_POLICY", "token_aware")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_TLS_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_TLS_CA_FILE", "/etc/ssl/cassandra-ca.pem")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_TLS_CERT_FILE", "/etc/ssl/client.pem")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_TLS_KEY_FILE", "/etc/ssl/client-key.pem")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_TLS_SERVER_NAME", "cassandra.internal")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_RETRY_POLICY", "exponential")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_QUERY_RETRIES", "5")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_RETRY_MIN_BACKOFF", "50ms")
+	t.Setenv("SCHEMA_REGISTRY_CASSANDRA_RETRY_MAX_BACKOFF", "2s")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	c := cfg.Storage.Cassandra
+	if c.HostSelectionPolicy != "token_aware" {
+		t.Errorf("HostSelectionPolicy = %q, want token_aware", c.HostSelectionPolicy)
+	}
+	if !c.TLSEnabled || c.TLSCAFile != "/etc/ssl/cassandra-ca.pem" || c.TLSCertFile != "/etc/ssl/client.pem" ||
+		c.TLSKeyFile != "/etc/ssl/client-key.pem" || c.TLSServerName != "cassandra.internal" {
+		t.Errorf("unexpected TLS settings: %+v", c)
+	}
+	if c.RetryPolicy != "exponential" || c.QueryRetries != 5 || c.RetryMinBackoff != "50ms" || c.RetryMaxBackoff != "2s" {
+		t.Errorf("unexpected retry settings: %+v", c)
+	}
 }
 
 func TestConfig_EnvOverrides_Bootstrap(t *testing.T) {
@@ -548,32 +651,686 @@ func TestConfig_Validate_VaultRequiresAddress(t *testing.T) {
 	cfg.Storage.AuthType = "vault"
 	cfg.Storage.Vault.Address = ""
 
-	err := cfg.Validate()
-	if err == nil {
-		t.Error("Expected error when vault auth_type has no address")
+	err := cfg.Validate()
+	if err == nil {
+		t.Error("Expected error when vault auth_type has no address")
+	}
+}
+
+func TestConfig_Validate_VaultDualWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		storageType string
+		authType    string
+		interval    string
+		wantErr     bool
+	}{
+		{"postgres with vault auth", "postgresql", "vault", "1h", false},
+		{"check disabled", "mysql", "vault", "", false},
+		{"memory storage", "memory", "vault", "1h", true},
+		{"auth not in vault", "postgresql", "", "1h", true},
+		{"invalid interval", "postgresql", "vault", "soon", true},
+		{"zero interval", "postgresql", "vault", "0s", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Storage.Type = tt.storageType
+			cfg.Storage.AuthType = tt.authType
+			cfg.Storage.Vault.Address = "http://localhost:8200"
+			cfg.Storage.Vault.DualWrite = true
+			cfg.Storage.Vault.ConsistencyCheckInterval = tt.interval
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_EnvOverrides_VaultDualWrite(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_TYPE", "postgresql")
+	t.Setenv("SCHEMA_REGISTRY_AUTH_TYPE", "vault")
+	t.Setenv("SCHEMA_REGISTRY_VAULT_ADDRESS", "http://localhost:8200")
+	t.Setenv("SCHEMA_REGISTRY_VAULT_DUAL_WRITE", "true")
+	t.Setenv("SCHEMA_REGISTRY_VAULT_CONSISTENCY_CHECK_INTERVAL", "15m")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Storage.Vault.DualWrite {
+		t.Error("Expected dual_write to be enabled")
+	}
+	if cfg.Storage.Vault.ConsistencyCheckInterval != "15m" {
+		t.Errorf("Expected 15m, got %s", cfg.Storage.Vault.ConsistencyCheckInterval)
+	}
+}
+
+func TestConfig_Validate_SoftDeleteRetention(t *testing.T) {
+	tests := []struct {
+		retention string
+		interval  string
+		wantErr   bool
+	}{
+		{"", "", false},
+		{"30d", "", false},
+		{"720h", "15m", false},
+		{"thirty days", "", true},
+		{"0d", "", true},
+		{"-1h", "", true},
+		{"30d", "0s", true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Storage.SoftDeleteRetention = tt.retention
+		cfg.Storage.SoftDeletePurgeInterval = tt.interval
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("retention=%q interval=%q: Validate() error = %v, wantErr %v", tt.retention, tt.interval, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_Jobs(t *testing.T) {
+	tests := []struct {
+		workers, queueSize int
+		retention          string
+		wantErr            bool
+	}{
+		{4, 100, "7d", false},
+		{0, 0, "", false},
+		{-1, 100, "7d", true},
+		{4, -1, "7d", true},
+		{4, 100, "a week", true},
+		{4, 100, "0h", true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Jobs = JobsConfig{Workers: tt.workers, QueueSize: tt.queueSize, Retention: tt.retention}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("jobs=%+v: Validate() error = %v, wantErr %v", cfg.Jobs, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_Quotas(t *testing.T) {
+	tests := []struct {
+		rules   []QuotaRuleConfig
+		wantErr bool
+	}{
+		{[]QuotaRuleConfig{{Name: "codegen", Principals: []string{"ci-bot"}, MaxVersionsPerDay: 100}}, false},
+		{[]QuotaRuleConfig{{Name: "devs", Roles: []string{"developer"}, Contexts: []string{".team-a"}, MaxSubjectsPerContext: 50}}, false},
+		{[]QuotaRuleConfig{{Principals: []string{"ci-bot"}, MaxVersionsPerDay: 100}}, true},
+		{[]QuotaRuleConfig{{Name: "codegen", MaxVersionsPerDay: 100}}, true},
+		{[]QuotaRuleConfig{{Name: "codegen", Principals: []string{"ci-bot"}, MaxVersionsPerDay: -1}}, true},
+		{[]QuotaRuleConfig{{Name: "a", Roles: []string{"developer"}}, {Name: "a", Roles: []string{"admin"}}}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Quotas.Rules = tt.rules
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("rules=%+v: Validate() error = %v, wantErr %v", tt.rules, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_MySQL(t *testing.T) {
+	tests := []struct {
+		mysql   MySQLConfig
+		wantErr bool
+	}{
+		{MySQLConfig{TLS: "true", TLSCAFile: "/etc/ssl/rds-ca.pem", TLSServerName: "db.internal"}, false},
+		{MySQLConfig{TLS: "true", TLSCertFile: "client.pem", TLSKeyFile: "client-key.pem"}, false},
+		{MySQLConfig{TLS: "true", IAMAuth: true, IAMRegion: "eu-west-1"}, false},
+		{MySQLConfig{TLS: "false", TLSCAFile: "/etc/ssl/rds-ca.pem"}, true},
+		{MySQLConfig{TLS: "true", TLSCertFile: "client.pem"}, true},
+		{MySQLConfig{TLS: "preferred", IAMAuth: true}, true},
+		{MySQLConfig{IAMAuth: true}, true},
+		{MySQLConfig{TLS: "true", IAMAuth: true, Password: "secret"}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Storage.Type = "mysql"
+		cfg.Storage.MySQL = tt.mysql
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("mysql=%+v: Validate() error = %v, wantErr %v", tt.mysql, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_Idempotency(t *testing.T) {
+	tests := []struct {
+		idempotency IdempotencyConfig
+		wantErr     bool
+	}{
+		{IdempotencyConfig{Enabled: true, TTL: "24h", MaxEntries: 10000}, false},
+		{IdempotencyConfig{Enabled: false}, false},
+		{IdempotencyConfig{Enabled: true, TTL: "", MaxEntries: 100}, true},
+		{IdempotencyConfig{Enabled: true, TTL: "1d", MaxEntries: 0}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Server.Idempotency = tt.idempotency
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("idempotency=%+v: Validate() error = %v, wantErr %v", tt.idempotency, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_JWTIssuers(t *testing.T) {
+	corp := JWTIssuerConfig{Issuer: "https://corp.example.com", JWKSURL: "https://corp.example.com/jwks"}
+	tests := []struct {
+		jwt     JWTConfig
+		wantErr bool
+	}{
+		{JWTConfig{Issuers: []JWTIssuerConfig{corp, {Issuer: "ci", PublicKeyFile: "ci.pem", Algorithm: "ES256"}}}, false},
+		{JWTConfig{Issuer: "legacy", JWKSURL: "https://legacy/jwks", Issuers: []JWTIssuerConfig{corp}}, false},
+		{JWTConfig{Issuers: []JWTIssuerConfig{{JWKSURL: "https://corp.example.com/jwks"}}}, true},
+		{JWTConfig{Issuers: []JWTIssuerConfig{corp, corp}}, true},
+		{JWTConfig{Issuer: corp.Issuer, JWKSURL: "https://legacy/jwks", Issuers: []JWTIssuerConfig{corp}}, true},
+		{JWTConfig{Issuers: []JWTIssuerConfig{{Issuer: "ci"}}}, true},
+		{JWTConfig{Issuers: []JWTIssuerConfig{{Issuer: "ci", PublicKeyFile: "ci.pem"}}}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Security.Auth.JWT = tt.jwt
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("jwt=%+v: Validate() error = %v, wantErr %v", tt.jwt, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_GRPC(t *testing.T) {
+	tests := []struct {
+		grpc    GRPCConfig
+		wantErr bool
+	}{
+		{GRPCConfig{Enabled: true, Host: "0.0.0.0", Port: 9090}, false},
+		{GRPCConfig{Enabled: false, Port: 0}, false},
+		{GRPCConfig{Enabled: true, Host: "0.0.0.0", Port: 0}, true},
+		{GRPCConfig{Enabled: true, Host: "0.0.0.0", Port: 70000}, true},
+		{GRPCConfig{Enabled: true, Host: "0.0.0.0", Port: 8081}, true},
+		{GRPCConfig{Enabled: true, Host: "0.0.0.0", Port: 9090, MaxMessageSize: -1}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.GRPC = tt.grpc
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("grpc=%+v: Validate() error = %v, wantErr %v", tt.grpc, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_StorageCache(t *testing.T) {
+	tests := []struct {
+		cache   StorageCacheConfig
+		wantErr bool
+	}{
+		{StorageCacheConfig{Enabled: true, MaxEntries: 10000, TTL: "1m"}, false},
+		{StorageCacheConfig{Enabled: false, MaxEntries: 0, TTL: ""}, false},
+		{StorageCacheConfig{Enabled: true, MaxEntries: 0, TTL: "1m"}, true},
+		{StorageCacheConfig{Enabled: true, MaxEntries: 100, TTL: ""}, true},
+		{StorageCacheConfig{Enabled: true, MaxEntries: 100, TTL: "forever"}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Storage.Cache = tt.cache
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("cache=%+v: Validate() error = %v, wantErr %v", tt.cache, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_StorageFallback(t *testing.T) {
+	tests := []struct {
+		fallback StorageFallbackConfig
+		wantErr  bool
+	}{
+		{StorageFallbackConfig{Enabled: true, Path: "snapshot.json", RefreshInterval: "5m", CheckInterval: "10s"}, false},
+		{StorageFallbackConfig{Enabled: false}, false},
+		{StorageFallbackConfig{Enabled: true, Path: "", RefreshInterval: "5m", CheckInterval: "10s"}, true},
+		{StorageFallbackConfig{Enabled: true, Path: "snapshot.json", RefreshInterval: "", CheckInterval: "10s"}, true},
+		{StorageFallbackConfig{Enabled: true, Path: "snapshot.json", RefreshInterval: "5m", CheckInterval: "often"}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Storage.Fallback = tt.fallback
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("fallback=%+v: Validate() error = %v, wantErr %v", tt.fallback, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_StorageEncryption(t *testing.T) {
+	key := "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+	tests := []struct {
+		name       string
+		encryption StorageEncryptionConfig
+		wantErr    bool
+	}{
+		{"disabled", StorageEncryptionConfig{}, false},
+		{"static key", StorageEncryptionConfig{Enabled: true, Keys: map[string]string{"k1": key, "k2": key}, ActiveKey: "k2"}, false},
+		{"vault with old static keys", StorageEncryptionConfig{Enabled: true, Keys: map[string]string{"k1": key}, Vault: StorageEncryptionVaultConfig{KeyName: "schemas"}}, false},
+		{"no key", StorageEncryptionConfig{Enabled: true}, true},
+		{"unknown active key", StorageEncryptionConfig{Enabled: true, Keys: map[string]string{"k1": key}, ActiveKey: "k2"}, true},
+		{"both keys", StorageEncryptionConfig{Enabled: true, Keys: map[string]string{"k1": key}, ActiveKey: "k1", Vault: StorageEncryptionVaultConfig{KeyName: "schemas"}}, true},
+		{"short key", StorageEncryptionConfig{Enabled: true, Keys: map[string]string{"k1": "c2hvcnQ="}, ActiveKey: "k1"}, true},
+		{"invalid key name", StorageEncryptionConfig{Enabled: true, Keys: map[string]string{"k:1": key}, ActiveKey: "k:1"}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Storage.Encryption = tt.encryption
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_StorageEncryptionEnv(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_KEYS", `{"2026-10": "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}`)
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_ENCRYPTION_ACTIVE_KEY", "2026-10")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Storage.Encryption.Enabled || cfg.Storage.Encryption.ActiveKey != "2026-10" || len(cfg.Storage.Encryption.Keys) != 1 {
+		t.Errorf("unexpected encryption config: %+v", cfg.Storage.Encryption)
+	}
+}
+
+func TestConfig_Validate_Usage(t *testing.T) {
+	tests := []struct {
+		flushInterval string
+		wantErr       bool
+	}{
+		{"1m", false},
+		{"", false},
+		{"30s", false},
+		{"0s", true},
+		{"often", true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Usage = UsageConfig{Enabled: true, FlushInterval: tt.flushInterval}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("flush_interval=%q: Validate() error = %v, wantErr %v", tt.flushInterval, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"1d", 24 * time.Hour},
+		{"90m", 90 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ParseDuration("1.5d"); err == nil {
+		t.Error("expected error for fractional days")
+	}
+}
+
+func TestConfig_Validate_NormalizationProfiles(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Normalization.Profiles = map[string]NormalizationProfileConfig{
+		"relaxed": {JSONStripAnnotations: true},
+	}
+	cfg.Normalization.DefaultProfile = "relaxed"
+	cfg.Normalization.Contexts = map[string]string{".team-a": "relaxed"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid normalization config, got %v", err)
+	}
+
+	cfg.Normalization.Contexts[".team-b"] = "missing"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for context mapped to undefined profile")
+	}
+
+	cfg.Normalization.Contexts = nil
+	cfg.Normalization.DefaultProfile = "missing"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for undefined default profile")
+	}
+}
+
+func TestConfig_Validate_SubjectNaming(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SubjectNaming.DefaultStrategy = "topic_name"
+	cfg.SubjectNaming.Contexts = map[string]string{".team-a": "record_name", ".legacy": "none"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid subject naming config, got %v", err)
+	}
+
+	cfg.SubjectNaming.Contexts[".team-b"] = "TopicNameStrategy"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for unknown context strategy")
+	}
+
+	cfg.SubjectNaming.Contexts = nil
+	cfg.SubjectNaming.DefaultStrategy = "bogus"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for unknown default strategy")
+	}
+}
+
+func TestConfig_Validate_Linting(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Linting.Rules = map[string]string{"field_doc": "warn", "field_snake_case": "error"}
+	cfg.Linting.Contexts = map[string]map[string]string{".legacy": {"field_doc": "off"}}
+	cfg.Linting.Subjects = map[string]map[string]string{":.payments:orders-value": {"timestamp_logical_type": "error"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected valid linting config, got %v", err)
+	}
+
+	cfg.Linting.Contexts[".legacy"]["field_doc"] = "ignore"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for unknown severity")
+	}
+
+	cfg.Linting.Contexts = nil
+	cfg.Linting.Subjects["orders-key"] = map[string]string{"record_doc": "warn"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for unknown rule")
+	}
+}
+
+func TestConfig_SubjectNamingEnvOverride(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_SUBJECT_NAMING_STRATEGY", "topic_record_name")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.SubjectNaming.DefaultStrategy != "topic_record_name" {
+		t.Errorf("Expected topic_record_name, got %q", cfg.SubjectNaming.DefaultStrategy)
+	}
+}
+
+func TestConfig_ReferencesMaxDepth(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_REFERENCES_MAX_DEPTH", "8")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.References.MaxDepth != 8 {
+		t.Errorf("Expected max depth 8, got %d", cfg.References.MaxDepth)
+	}
+
+	cfg.References.MaxDepth = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative max depth")
+	}
+}
+
+func TestConfig_IDScope(t *testing.T) {
+	if scope := DefaultConfig().IDs.Scope; scope != IDScopeContext {
+		t.Errorf("Expected the context scope by default, got %q", scope)
+	}
+
+	t.Setenv("SCHEMA_REGISTRY_IDS_SCOPE", "global")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.IDs.Scope != IDScopeGlobal {
+		t.Errorf("Expected the global scope, got %q", cfg.IDs.Scope)
+	}
+
+	cfg.IDs.Scope = "subject"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown ID scope")
+	}
+}
+
+func TestConfig_SubjectAliasWritePolicy(t *testing.T) {
+	if policy := DefaultConfig().SubjectAliases.WritePolicy; policy != AliasWriteFollow {
+		t.Errorf("Expected the follow policy by default, got %q", policy)
+	}
+
+	t.Setenv("SCHEMA_REGISTRY_SUBJECT_ALIAS_WRITE_POLICY", "reject")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.SubjectAliases.WritePolicy != AliasWriteReject {
+		t.Errorf("Expected the reject policy, got %q", cfg.SubjectAliases.WritePolicy)
+	}
+
+	cfg.SubjectAliases.WritePolicy = "redirect"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown alias write policy")
+	}
+}
+
+func TestConfig_PasswordPolicy(t *testing.T) {
+	if n := DefaultConfig().Security.Auth.PasswordPolicy.MinLength; n != 8 {
+		t.Errorf("Expected a minimum length of 8 by default, got %d", n)
+	}
+
+	t.Setenv("SCHEMA_REGISTRY_PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("SCHEMA_REGISTRY_PASSWORD_REQUIRE_SYMBOL", "true")
+	t.Setenv("SCHEMA_REGISTRY_PASSWORD_BANNED", "changeme, Password1")
+	t.Setenv("SCHEMA_REGISTRY_PASSWORD_EXPIRY_DAYS", "90")
+	t.Setenv("SCHEMA_REGISTRY_PASSWORD_HISTORY_SIZE", "5")
+	t.Setenv("SCHEMA_REGISTRY_BOOTSTRAP_REQUIRE_PASSWORD_CHANGE", "true")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	policy := cfg.Security.Auth.PasswordPolicy
+	if policy.MinLength != 12 || !policy.RequireSymbol || policy.ExpiryDays != 90 || policy.HistorySize != 5 {
+		t.Errorf("Unexpected password policy: %+v", policy)
+	}
+	if len(policy.BannedPasswords) != 2 || policy.BannedPasswords[1] != "Password1" {
+		t.Errorf("Expected two banned passwords, got %v", policy.BannedPasswords)
+	}
+	if !cfg.Security.Auth.Bootstrap.RequirePasswordChange {
+		t.Error("Expected the bootstrap admin to require a password change")
+	}
+
+	cfg.Security.Auth.PasswordPolicy.HistorySize = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a negative history size")
+	}
+}
+
+func TestConfig_Sessions(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_SESSION_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_SESSION_SECRET", "0123456789abcdef0123456789abcdef")
+	t.Setenv("SCHEMA_REGISTRY_SESSION_ACCESS_TTL", "5m")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	sessions := cfg.Security.Auth.Sessions
+	if !sessions.Enabled || sessions.Algorithm != "HS256" || sessions.AccessTTL != "5m" || sessions.RefreshTTL != "24h" {
+		t.Errorf("Unexpected session config: %+v", sessions)
+	}
+
+	cfg.Security.Auth.Sessions.Secret = "short"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an HS256 secret shorter than 32 bytes")
+	}
+	cfg.Security.Auth.Sessions.Algorithm = "RS256"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for RS256 without a private key file")
+	}
+	cfg.Security.Auth.Sessions.PrivateKeyFile = "/etc/registry/session.pem"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected RS256 with a private key file to be valid: %v", err)
+	}
+	cfg.Security.Auth.Sessions.RefreshTTL = "-1h"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a negative refresh TTL")
+	}
+	cfg.Security.Auth.Sessions.Algorithm = "none"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unsupported algorithm")
+	}
+}
+
+func TestConfig_Tenancy(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_TENANCY_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_TENANCY_MASTER_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Tenancy.Enabled {
+		t.Error("Expected tenancy to be enabled")
+	}
+
+	cfg.Tenancy.MasterKey = "c2hvcnQ="
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a master key that is not 32 bytes")
+	}
+	cfg.Tenancy.MasterKey = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a missing master key")
+	}
+}
+
+func TestConfig_TenancyContextMode(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_TENANCY_CONTEXT_MODE", "principal")
+	t.Setenv("SCHEMA_REGISTRY_TENANCY_OVERRIDE_HEADER", "X-Acting-Tenant")
+	t.Setenv("SCHEMA_REGISTRY_OIDC_TENANT_CLAIM", "org.tenant")
+	t.Setenv("SCHEMA_REGISTRY_JWT_TENANT_CLAIM", "tenant")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Tenancy.ContextMode != "principal" || cfg.Tenancy.OverrideHeader != "X-Acting-Tenant" {
+		t.Errorf("Tenancy = %+v", cfg.Tenancy)
+	}
+	if cfg.Security.Auth.OIDC.TenantClaim != "org.tenant" || cfg.Security.Auth.JWT.TenantClaim != "tenant" {
+		t.Errorf("tenant claims = %q, %q", cfg.Security.Auth.OIDC.TenantClaim, cfg.Security.Auth.JWT.TenantClaim)
+	}
+
+	cfg.Tenancy.OverrideHeader = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for principal mode without an override header")
+	}
+	cfg.Tenancy.ContextMode = "url"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown context mode")
+	}
+}
+
+func TestConfig_Validate_AllCompatibilityLevels(t *testing.T) {
+	levels := []string{
+		"NONE", "BACKWARD", "BACKWARD_TRANSITIVE",
+		"FORWARD", "FORWARD_TRANSITIVE",
+		"FULL", "FULL_TRANSITIVE",
+	}
+	for _, level := range levels {
+		cfg := DefaultConfig()
+		cfg.Compatibility.DefaultLevel = level
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("level %s should be valid: %v", level, err)
+		}
+	}
+}
+
+func TestConfig_Validate_CaseInsensitiveCompatibility(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Compatibility.DefaultLevel = "backward"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("lowercase should be valid: %v", err)
+	}
+}
+
+func TestConfig_Validate_JSONSchemaStrictness(t *testing.T) {
+	for _, strictness := range []string{"", "strict", "lenient"} {
+		cfg := DefaultConfig()
+		cfg.Compatibility.JSONSchema.Strictness = strictness
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("strictness %q should be valid: %v", strictness, err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Compatibility.JSONSchema.Strictness = "loose"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown strictness")
+	}
+}
+
+func TestConfig_Validate_AvroLogicalTypes(t *testing.T) {
+	for _, mode := range []string{"", "error", "warning", "off"} {
+		cfg := DefaultConfig()
+		cfg.Compatibility.Avro.LogicalTypes = mode
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("logical_types %q should be valid: %v", mode, err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Compatibility.Avro.LogicalTypes = "fatal"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown logical_types mode")
+	}
+}
+
+func TestConfig_Validate_FingerprintAlgorithm(t *testing.T) {
+	for _, algorithm := range []string{"", "md5", "sha256", "crc64-avro"} {
+		cfg := DefaultConfig()
+		cfg.Fingerprints.Algorithm = algorithm
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("fingerprint algorithm %q should be valid: %v", algorithm, err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Fingerprints.Algorithm = "sha1"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown fingerprint algorithm")
+	}
+}
+
+func TestConfig_Validate_HealthCritical(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.Health.Critical = []string{"ldap", "kms"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("ldap and kms should be valid critical dependencies: %v", err)
+	}
+
+	cfg.Server.Health.Critical = []string{"redis"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown critical dependency")
+	}
+
+	cfg = DefaultConfig()
+	cfg.Server.Health.Timeout = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a negative health timeout")
 	}
 }
 
-func TestConfig_Validate_AllCompatibilityLevels(t *testing.T) {
-	levels := []string{
-		"NONE", "BACKWARD", "BACKWARD_TRANSITIVE",
-		"FORWARD", "FORWARD_TRANSITIVE",
-		"FULL", "FULL_TRANSITIVE",
-	}
-	for _, level := range levels {
+func TestConfig_Validate_RequestValidation(t *testing.T) {
+	for _, mode := range []string{"", "off", "on", "strict"} {
 		cfg := DefaultConfig()
-		cfg.Compatibility.DefaultLevel = level
+		cfg.Server.RequestValidation = mode
 		if err := cfg.Validate(); err != nil {
-			t.Errorf("level %s should be valid: %v", level, err)
+			t.Errorf("request_validation %q should be valid: %v", mode, err)
 		}
 	}
-}
 
-func TestConfig_Validate_CaseInsensitiveCompatibility(t *testing.T) {
 	cfg := DefaultConfig()
-	cfg.Compatibility.DefaultLevel = "backward"
-	if err := cfg.Validate(); err != nil {
-		t.Errorf("lowercase should be valid: %v", err)
+	cfg.Server.RequestValidation = "lenient"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown request_validation mode")
 	}
 }
 
@@ -681,6 +1438,29 @@ func TestConfig_DefaultConfig_CacheRefreshSeconds(t *testing.T) {
 	}
 }
 
+func TestConfig_DefaultConfig_ConsistencyCheckSeconds(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Security.Auth.APIKey.ConsistencyCheckSeconds != 86400 {
+		t.Errorf("Expected default ConsistencyCheckSeconds 86400, got %d", cfg.Security.Auth.APIKey.ConsistencyCheckSeconds)
+	}
+}
+
+func TestConfig_DefaultConfig_UsageFlushSeconds(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Security.Auth.APIKey.UsageFlushSeconds != 30 {
+		t.Errorf("Expected default UsageFlushSeconds 30, got %d", cfg.Security.Auth.APIKey.UsageFlushSeconds)
+	}
+}
+
+func TestConfig_EnvOverrides_APIKeyUsageFlush(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_API_KEY_USAGE_FLUSH", "5")
+	cfg := DefaultConfig()
+	cfg.applyEnvOverrides()
+	if cfg.Security.Auth.APIKey.UsageFlushSeconds != 5 {
+		t.Errorf("Expected UsageFlushSeconds 5, got %d", cfg.Security.Auth.APIKey.UsageFlushSeconds)
+	}
+}
+
 func TestConfig_LoadEmpty(t *testing.T) {
 	// Loading with empty path and no env overrides should return defaults
 	cfg, err := Load("")
@@ -723,6 +1503,7 @@ func TestConfig_EnvOverrides_MCP(t *testing.T) {
 		"SCHEMA_REGISTRY_MCP_REQUIRE_CONFIRMATIONS": "true",
 		"SCHEMA_REGISTRY_MCP_CONFIRMATION_TTL":      "60",
 		"SCHEMA_REGISTRY_MCP_LOG_SCHEMAS":           "true",
+		"SCHEMA_REGISTRY_MCP_REGISTRY_AUTH":         "true",
 	}
 	for k, v := range envVars {
 		os.Setenv(k, v)
@@ -768,6 +1549,39 @@ func TestConfig_EnvOverrides_MCP(t *testing.T) {
 	if !cfg.MCP.LogSchemas {
 		t.Error("Expected log_schemas true")
 	}
+	if !cfg.MCP.RegistryAuth {
+		t.Error("Expected registry_auth true")
+	}
+}
+
+func TestConfig_EnvOverrides_GRPC(t *testing.T) {
+	envVars := map[string]string{
+		"SCHEMA_REGISTRY_GRPC_ENABLED":          "true",
+		"SCHEMA_REGISTRY_GRPC_HOST":             "127.0.0.1",
+		"SCHEMA_REGISTRY_GRPC_PORT":             "9191",
+		"SCHEMA_REGISTRY_GRPC_MAX_MESSAGE_SIZE": "16777216",
+		"SCHEMA_REGISTRY_GRPC_REFLECTION":       "1",
+	}
+	for k, v := range envVars {
+		t.Setenv(k, v)
+	}
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.GRPC.Enabled {
+		t.Error("Expected gRPC enabled")
+	}
+	if cfg.GRPCAddress() != "127.0.0.1:9191" {
+		t.Errorf("Expected 127.0.0.1:9191, got %s", cfg.GRPCAddress())
+	}
+	if cfg.GRPC.MaxMessageSize != 16777216 {
+		t.Errorf("Expected max message size 16777216, got %d", cfg.GRPC.MaxMessageSize)
+	}
+	if !cfg.GRPC.Reflection {
+		t.Error("Expected reflection enabled")
+	}
 }
 
 func TestConfig_EnvOverrides_Server_Extended(t *testing.T) {
@@ -775,6 +1589,8 @@ func TestConfig_EnvOverrides_Server_Extended(t *testing.T) {
 	t.Setenv("SCHEMA_REGISTRY_WRITE_TIMEOUT", "90")
 	t.Setenv("SCHEMA_REGISTRY_CLUSTER_ID", "my-cluster")
 	t.Setenv("SCHEMA_REGISTRY_MAX_REQUEST_BODY_SIZE", "10485760")
+	t.Setenv("SCHEMA_REGISTRY_MAX_VERSIONS_PAGE_SIZE", "500")
+	t.Setenv("SCHEMA_REGISTRY_MAX_LIST_PAGE_SIZE", "1000")
 
 	cfg, err := Load("")
 	if err != nil {
@@ -793,6 +1609,12 @@ func TestConfig_EnvOverrides_Server_Extended(t *testing.T) {
 	if cfg.Server.MaxRequestBodySize != 10485760 {
 		t.Errorf("Expected MaxRequestBodySize 10485760, got %d", cfg.Server.MaxRequestBodySize)
 	}
+	if cfg.Server.MaxVersionsPageSize != 500 {
+		t.Errorf("Expected MaxVersionsPageSize 500, got %d", cfg.Server.MaxVersionsPageSize)
+	}
+	if cfg.Server.MaxListPageSize != 1000 {
+		t.Errorf("Expected MaxListPageSize 1000, got %d", cfg.Server.MaxListPageSize)
+	}
 }
 
 func TestConfig_EnvOverrides_PostgreSQL_ConnectionPool(t *testing.T) {
@@ -850,6 +1672,27 @@ func TestConfig_EnvOverrides_LogFormat(t *testing.T) {
 	}
 }
 
+func TestConfig_EnvOverrides_Exporters(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_EXPORTERS_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_EXPORTERS_POLL_INTERVAL", "5")
+	t.Setenv("SCHEMA_REGISTRY_EXPORTERS_REQUEST_TIMEOUT", "15")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !cfg.Exporters.Enabled {
+		t.Error("Expected exporters enabled")
+	}
+	if cfg.Exporters.PollInterval != 5 {
+		t.Errorf("Expected poll interval 5, got %d", cfg.Exporters.PollInterval)
+	}
+	if cfg.Exporters.RequestTimeout != 15 {
+		t.Errorf("Expected request timeout 15, got %d", cfg.Exporters.RequestTimeout)
+	}
+}
+
 func TestConfig_EnvOverrides_SecurityMetrics(t *testing.T) {
 	t.Setenv("SCHEMA_REGISTRY_SECURITY_METRICS_PER_PRINCIPAL", "false")
 
@@ -879,6 +1722,52 @@ func TestConfig_EnvOverrides_SecurityMetrics_True(t *testing.T) {
 	}
 }
 
+func TestConfig_EnvOverrides_MetricsAccess(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_METRICS_SCRAPE_TOKEN", "scrape-secret")
+	t.Setenv("SCHEMA_REGISTRY_METRICS_ALLOWED_CIDRS", "10.0.0.0/8, 192.168.1.0/24")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Security.Metrics.ScrapeToken != "scrape-secret" {
+		t.Errorf("Expected scrape token, got %q", cfg.Security.Metrics.ScrapeToken)
+	}
+	cidrs := cfg.Security.Metrics.AllowedCIDRs
+	if len(cidrs) != 2 || cidrs[0] != "10.0.0.0/8" || cidrs[1] != "192.168.1.0/24" {
+		t.Errorf("Unexpected allowed CIDRs: %v", cidrs)
+	}
+}
+
+func TestConfig_Validate_MetricsAllowedCIDRs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Security.Metrics.AllowedCIDRs = []string{"10.0.0.0/8", "not-a-cidr"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "not-a-cidr") {
+		t.Errorf("Expected invalid CIDR error, got %v", err)
+	}
+}
+
+func TestConfig_Validate_MTLSAuthRequiresVerifiedClientCerts(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Security.Auth.Enabled = true
+	cfg.Security.Auth.Methods = []string{"mtls", "basic"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "mtls") {
+		t.Errorf("Expected mtls without TLS to be rejected, got %v", err)
+	}
+
+	cfg.Security.TLS.Enabled = true
+	cfg.Security.TLS.ClientAuth = "request"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected mtls with client_auth request to be rejected")
+	}
+
+	cfg.Security.TLS.ClientAuth = "verify"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected mtls with client_auth verify to be valid, got %v", err)
+	}
+}
+
 func TestConfig_EnvOverrides_TLS_AutoReload(t *testing.T) {
 	t.Setenv("SCHEMA_REGISTRY_TLS_AUTO_RELOAD", "true")
 
@@ -1080,6 +1969,230 @@ func TestConfig_EnvOverrides_Audit_Extended(t *testing.T) {
 	}
 }
 
+func TestConfig_EnvOverrides_AuditHistory(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_AUDIT_HISTORY_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_AUDIT_HISTORY_MAX_EVENTS", "500")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !cfg.Security.Audit.History.Enabled {
+		t.Error("Expected audit history enabled")
+	}
+	if cfg.Security.Audit.History.MaxEvents != 500 {
+		t.Errorf("Expected MaxEvents 500, got %d", cfg.Security.Audit.History.MaxEvents)
+	}
+
+	cfg.Security.Audit.History.MaxEvents = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative audit history max_events")
+	}
+}
+
+func TestConfig_EnvOverrides_SoftDeleteRetention(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_SOFT_DELETE_RETENTION", "30d")
+	t.Setenv("SCHEMA_REGISTRY_SOFT_DELETE_PURGE_INTERVAL", "15m")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Storage.SoftDeleteRetention != "30d" {
+		t.Errorf("Expected soft_delete_retention 30d, got %q", cfg.Storage.SoftDeleteRetention)
+	}
+	if cfg.Storage.SoftDeletePurgeInterval != "15m" {
+		t.Errorf("Expected soft_delete_purge_interval 15m, got %q", cfg.Storage.SoftDeletePurgeInterval)
+	}
+}
+
+func TestConfig_EnvOverrides_StorageCache(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_CACHE_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_CACHE_MAX_ENTRIES", "500")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_CACHE_TTL", "30s")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := StorageCacheConfig{Enabled: true, MaxEntries: 500, TTL: "30s"}
+	if cfg.Storage.Cache != want {
+		t.Errorf("Storage.Cache = %+v, want %+v", cfg.Storage.Cache, want)
+	}
+}
+
+func TestConfig_EnvOverrides_StorageFallback(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_FALLBACK_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_FALLBACK_PATH", "/var/lib/schema-registry/snapshot.json")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_FALLBACK_REFRESH_INTERVAL", "1m")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := StorageFallbackConfig{Enabled: true, Path: "/var/lib/schema-registry/snapshot.json", RefreshInterval: "1m", CheckInterval: "10s"}
+	if cfg.Storage.Fallback != want {
+		t.Errorf("Storage.Fallback = %+v, want %+v", cfg.Storage.Fallback, want)
+	}
+}
+
+func TestConfig_EnvOverrides_StorageWriteGate(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_CHECK_INTERVAL", "5s")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_MAX_REPLICATION_LAG", "10s")
+	t.Setenv("SCHEMA_REGISTRY_STORAGE_WRITE_GATE_MAX_COORDINATOR_LATENCY", "0")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := StorageWriteGateConfig{Enabled: true, CheckInterval: "5s", CheckTimeout: "5s", MaxReplicationLag: "10s", MaxCoordinatorLatency: "0"}
+	if cfg.Storage.WriteGate != want {
+		t.Errorf("Storage.WriteGate = %+v, want %+v", cfg.Storage.WriteGate, want)
+	}
+
+	cfg.Storage.WriteGate.MaxReplicationLag = "-1s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for a negative storage.write_gate.max_replication_lag")
+	}
+}
+
+func TestConfig_EnvOverrides_Jobs(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_JOBS_WORKERS", "8")
+	t.Setenv("SCHEMA_REGISTRY_JOBS_QUEUE_SIZE", "500")
+	t.Setenv("SCHEMA_REGISTRY_JOBS_RETENTION", "48h")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := JobsConfig{Workers: 8, QueueSize: 500, Retention: "48h"}
+	if cfg.Jobs != want {
+		t.Errorf("Jobs = %+v, want %+v", cfg.Jobs, want)
+	}
+}
+
+func TestConfig_EnvOverrides_Consistency(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_CONSISTENCY_CHECK_INTERVAL", "12h")
+	t.Setenv("SCHEMA_REGISTRY_CONSISTENCY_REPAIR", "true")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := ConsistencyConfig{CheckInterval: "12h", Repair: true}
+	if cfg.Consistency != want {
+		t.Errorf("Consistency = %+v, want %+v", cfg.Consistency, want)
+	}
+
+	cfg.Consistency.CheckInterval = "0s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for a non-positive consistency.check_interval")
+	}
+}
+
+func TestConfig_EnvOverrides_Approval(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_APPROVAL_CONTEXTS", ".payments, regulated")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if want := []string{".payments", "regulated"}; !slices.Equal(cfg.Approval.Contexts, want) {
+		t.Errorf("Approval.Contexts = %v, want %v", cfg.Approval.Contexts, want)
+	}
+
+	cfg.Approval.Contexts = []string{".payments", ""}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an empty approval context")
+	}
+}
+
+func TestConfig_Validate_ApprovalReviewers(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Approval.Reviewers = map[string][]string{".payments": {"alice", "role:admin"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	for _, reviewers := range [][]string{{""}, {"role:"}, {" "}} {
+		cfg.Approval.Reviewers = map[string][]string{".payments": reviewers}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected an error for reviewers %q", reviewers)
+		}
+	}
+	cfg.Approval.Reviewers = map[string][]string{"": {"alice"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an empty reviewers context")
+	}
+}
+
+func TestConfig_EnvOverrides_Contexts(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_CONTEXTS_AUTO_CREATE", "false")
+	t.Setenv("SCHEMA_REGISTRY_CONTEXTS_ALLOWED", ".staging, sandbox")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Contexts.AutoCreate == nil || *cfg.Contexts.AutoCreate {
+		t.Errorf("Contexts.AutoCreate = %v, want false", cfg.Contexts.AutoCreate)
+	}
+	if want := []string{".staging", "sandbox"}; !slices.Equal(cfg.Contexts.Allowed, want) {
+		t.Errorf("Contexts.Allowed = %v, want %v", cfg.Contexts.Allowed, want)
+	}
+
+	cfg.Contexts.Allowed = []string{".staging", ""}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an empty allowed context")
+	}
+}
+
+func TestConfig_EnvOverrides_Deletes(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_DELETES_REQUIRE_CONFIRMATION", "true")
+	t.Setenv("SCHEMA_REGISTRY_DELETES_CONFIRMATION_TTL", "10m")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := DeletesConfig{RequireConfirmation: true, ConfirmationTTL: "10m"}
+	if cfg.Deletes != want {
+		t.Errorf("Deletes = %+v, want %+v", cfg.Deletes, want)
+	}
+
+	cfg.Deletes.ConfirmationTTL = "0s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for a non-positive deletes.confirmation_ttl")
+	}
+}
+
+func TestConfig_EnvOverrides_Usage(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_USAGE_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_USAGE_FLUSH_INTERVAL", "5m")
+	t.Setenv("SCHEMA_REGISTRY_USAGE_TRACK_CLIENTS", "true")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := UsageConfig{Enabled: true, FlushInterval: "5m", TrackClients: true}
+	if cfg.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", cfg.Usage, want)
+	}
+}
+
 func TestConfig_EnvOverrides_Webhook_Headers(t *testing.T) {
 	t.Setenv("SCHEMA_REGISTRY_AUDIT_WEBHOOK_HEADERS", `{"Authorization":"Bearer token123","X-Custom":"value"}`)
 
@@ -1215,3 +2328,113 @@ func writeTempFile(t *testing.T, content string) string {
 	f.Close()
 	return f.Name()
 }
+
+func TestConfig_EnvOverrides_Tracing(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_TRACING_ENABLED", "true")
+	t.Setenv("SCHEMA_REGISTRY_TRACING_ENDPOINT", "http://otel-collector:4318")
+	t.Setenv("SCHEMA_REGISTRY_TRACING_HEADERS", `{"x-api-key":"secret"}`)
+	t.Setenv("SCHEMA_REGISTRY_TRACING_SERVICE_NAME", "schema-registry-eu")
+	t.Setenv("SCHEMA_REGISTRY_TRACING_SAMPLE_RATIO", "0.25")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !cfg.Tracing.Enabled || cfg.Tracing.Endpoint != "http://otel-collector:4318" ||
+		cfg.Tracing.Headers["x-api-key"] != "secret" || cfg.Tracing.ServiceName != "schema-registry-eu" ||
+		cfg.Tracing.SampleRatio != 0.25 {
+		t.Errorf("unexpected tracing config: %+v", cfg.Tracing)
+	}
+
+	cfg.Tracing.Endpoint = "otel-collector:4318"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for a tracing endpoint without a scheme")
+	}
+	cfg.Tracing.Endpoint = "http://otel-collector:4318"
+	cfg.Tracing.SampleRatio = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for a sample ratio above 1")
+	}
+}
+
+func TestConfig_EnvOverrides_RBACPolicy(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_RBAC_POLICY_URL", "http://opa:8181/v1/data/schema_registry/allow")
+	t.Setenv("SCHEMA_REGISTRY_RBAC_POLICY_HEADERS", `{"Authorization":"Bearer token"}`)
+	t.Setenv("SCHEMA_REGISTRY_RBAC_POLICY_TIMEOUT", "500ms")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	policy := cfg.Security.Auth.RBAC.Policy
+	if policy.URL != "http://opa:8181/v1/data/schema_registry/allow" ||
+		policy.Headers["Authorization"] != "Bearer token" || policy.Timeout != "500ms" {
+		t.Errorf("unexpected policy config: %+v", policy)
+	}
+
+	cfg.Security.Auth.RBAC.Policy.URL = "opa:8181"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for a policy URL without a scheme")
+	}
+	cfg.Security.Auth.RBAC.Policy.URL = "http://opa:8181"
+	cfg.Security.Auth.RBAC.Policy.Timeout = "0s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for a zero policy timeout")
+	}
+}
+
+func TestConfig_Reload(t *testing.T) {
+	cur := DefaultConfig()
+	cur.Security.Auth.Enabled = true
+	cur.Security.Auth.LDAP.Enabled = true
+	cur.Security.Auth.LDAP.BindPassword = "old"
+
+	next := DefaultConfig()
+	next.Security.Auth.Enabled = true
+	next.Security.Auth.LDAP.Enabled = true
+	next.Security.Auth.LDAP.BindPassword = "new"
+	next.Logging.Level = "debug"
+	next.Compatibility.DefaultLevel = "FULL"
+	next.Security.RateLimiting.Enabled = true
+
+	reloaded, applied, restart := cur.Reload(next)
+	want := []string{"logging.level", "compatibility.default_level", "security.rate_limiting", "security.auth.ldap"}
+	if !slices.Equal(applied, want) {
+		t.Errorf("expected applied %v, got %v", want, applied)
+	}
+	if len(restart) != 0 {
+		t.Errorf("expected no restart, got %v", restart)
+	}
+	if reloaded.Security.Auth.LDAP.BindPassword != "new" || cur.Security.Auth.LDAP.BindPassword != "old" {
+		t.Error("expected the reloaded copy to have the new bind password, and the running config to be unchanged")
+	}
+
+	// Other settings, and turning a provider on or off, need a restart, and
+	// are not reloaded.
+	next.Server.Port = cur.Server.Port + 1
+	next.Logging.Format = "text"
+	next.Security.Auth.OIDC.Enabled = true
+	reloaded, _, restart = cur.Reload(next)
+	if want := []string{"server", "logging", "security"}; !slices.Equal(restart, want) {
+		t.Errorf("expected restart %v, got %v", want, restart)
+	}
+	if reloaded.Server.Port != cur.Server.Port || reloaded.Logging.Format != cur.Logging.Format || reloaded.Security.Auth.OIDC.Enabled {
+		t.Error("expected settings that need a restart to keep their running values")
+	}
+
+	// Nothing is reported once a reload is in effect.
+	if _, applied, restart := reloaded.Reload(reloaded); len(applied) != 0 || len(restart) != 0 {
+		t.Errorf("expected no changes, got applied %v, restart %v", applied, restart)
+	}
+
+	// Auth providers are only reloaded when authentication is enabled.
+	cur.Security.Auth.Enabled = false
+	next = DefaultConfig()
+	next.Security.Auth.JWT.Issuer = "https://issuer.example.com"
+	_, applied, restart = cur.Reload(next)
+	if len(applied) != 0 || !slices.Equal(restart, []string{"security"}) {
+		t.Errorf("expected only a restart of security, got applied %v, restart %v", applied, restart)
+	}
+}
@@ -0,0 +1,115 @@
+// Package consistency checks storage for invariant violations that the
+// registry's own write paths never produce but that a partial migration, a
+// manual database edit or a backend bug can leave behind: orphaned
+// references, fingerprint mismatches, duplicate subject versions and ID
+// sequences behind the highest schema ID. It runs as an async job on demand
+// and, optionally, periodically in the background.
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// JobType is the async job type that runs a consistency check.
+const JobType = "consistency_check"
+
+// Params are the parameters of a consistency check job. The job covers the
+// registry context it was submitted for.
+type Params struct {
+	// Repair advances ID sequences that are behind. Other findings are
+	// only reported.
+	Repair bool `json:"repair,omitempty"`
+}
+
+// Register registers the consistency check job type with the job manager.
+// The job's result is a registry.ConsistencyReport.
+func Register(m *jobs.Manager, reg *registry.Registry) {
+	m.Register(JobType, func(ctx context.Context, job *jobs.Job) (any, error) {
+		var p Params
+		if err := job.DecodeParams(&p); err != nil {
+			return nil, err
+		}
+		return reg.VerifyConsistency(ctx, job.RegistryContext(), p.Repair, func(_ context.Context, done, total int) error {
+			job.SetProgress(int64(done), int64(total), "")
+			return nil
+		})
+	})
+}
+
+// Checker periodically checks every context and logs what it finds.
+type Checker struct {
+	reg    *registry.Registry
+	repair bool
+	logger *slog.Logger
+}
+
+// NewChecker creates a new periodic consistency checker. With repair set,
+// it repairs what VerifyConsistency can repair.
+func NewChecker(reg *registry.Registry, repair bool, logger *slog.Logger) *Checker {
+	return &Checker{
+		reg:    reg,
+		repair: repair,
+		logger: logger,
+	}
+}
+
+// Start starts a background goroutine that runs a check every interval.
+// The goroutine stops when the stop channel is closed.
+func (c *Checker) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.RunOnce(context.Background())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// RunOnce checks every context once and returns the reports of the
+// contexts that could be checked.
+func (c *Checker) RunOnce(ctx context.Context) []*registry.ConsistencyReport {
+	contexts, err := c.reg.ListContexts(ctx)
+	if err != nil {
+		c.logger.Error("consistency check: failed to list contexts", slog.String("error", err.Error()))
+		return nil
+	}
+
+	var reports []*registry.ConsistencyReport
+	for _, registryCtx := range contexts {
+		report, err := c.reg.VerifyConsistency(ctx, registryCtx, c.repair, nil)
+		if err != nil {
+			c.logger.Error("consistency check: failed to check context",
+				slog.String("context", registryCtx),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		reports = append(reports, report)
+		for _, f := range report.Findings {
+			level := slog.LevelWarn
+			if f.Repaired {
+				level = slog.LevelInfo
+			}
+			c.logger.Log(ctx, level, fmt.Sprintf("consistency check: %s", f.Kind),
+				slog.String("context", registryCtx),
+				slog.String("subject", f.Subject),
+				slog.Int("version", f.Version),
+				slog.Int64("schema_id", f.SchemaID),
+				slog.String("message", f.Message),
+				slog.Bool("repaired", f.Repaired),
+			)
+		}
+	}
+	return reports
+}
@@ -0,0 +1,126 @@
+package consistency
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+// setupRegistry returns a registry with one healthy subject in the default
+// context and, in .broken, a schema imported with a high ID and a bogus
+// fingerprint without advancing the ID sequence.
+func setupRegistry(t *testing.T) (*registry.Registry, *memory.Store) {
+	t.Helper()
+	store := memory.NewStore()
+	ctx := context.Background()
+	parsers := schema.NewRegistry()
+	parsers.Register(avro.NewParser())
+	checker := compatibility.NewChecker()
+	checker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := registry.New(store, parsers, checker, "NONE")
+
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", `"string"`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if err := store.ImportSchema(ctx, ".broken", &storage.SchemaRecord{
+		ID: 40, Subject: "users-value", Version: 1, SchemaType: storage.SchemaTypeAvro, Schema: `"int"`, Fingerprint: "bogus",
+	}); err != nil {
+		t.Fatalf("ImportSchema: %v", err)
+	}
+	return reg, store
+}
+
+func TestConsistencyJob(t *testing.T) {
+	reg, store := setupRegistry(t)
+	ctx := context.Background()
+
+	m := jobs.NewManager(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	Register(m, reg)
+	stop := make(chan struct{})
+	defer close(stop)
+	m.Start(stop)
+
+	rec, err := m.Submit(ctx, JobType, ".broken", Params{Repair: true}, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !rec.Finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish: %+v", rec)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if rec, err = m.Get(ctx, rec.ID); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if rec.State != storage.JobStateSucceeded {
+		t.Fatalf("job state %s: %s", rec.State, rec.Error)
+	}
+	if rec.Done != 1 || rec.Total != 1 {
+		t.Errorf("progress = %d/%d, want 1/1", rec.Done, rec.Total)
+	}
+
+	var report registry.ConsistencyReport
+	if err := json.Unmarshal([]byte(rec.Result), &report); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if report.Context != ".broken" || report.SubjectsChecked != 1 || len(report.Findings) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	for _, f := range report.Findings {
+		wantRepaired := f.Kind == registry.FindingIDSequenceBehind
+		if f.Repaired != wantRepaired {
+			t.Errorf("finding %+v: repaired = %v, want %v", f, f.Repaired, wantRepaired)
+		}
+	}
+	if next, err := store.PeekNextID(ctx, ".broken"); err != nil || next != 41 {
+		t.Errorf("PeekNextID = %d, %v; want 41", next, err)
+	}
+}
+
+func TestChecker_RunOnce(t *testing.T) {
+	reg, store := setupRegistry(t)
+	ctx := context.Background()
+
+	// Without repair the sequence is left alone.
+	reports := NewChecker(reg, false, slog.New(slog.NewTextHandler(io.Discard, nil))).RunOnce(ctx)
+	findings := 0
+	for _, r := range reports {
+		if r.Context == "." && len(r.Findings) != 0 {
+			t.Errorf("expected the default context to be clean, got %+v", r.Findings)
+		}
+		findings += r.Unrepaired()
+	}
+	if findings != 2 {
+		t.Errorf("expected 2 unrepaired findings, got %d", findings)
+	}
+	if next, err := store.PeekNextID(ctx, ".broken"); err != nil || next != 1 {
+		t.Errorf("PeekNextID = %d, %v; want 1", next, err)
+	}
+
+	// With repair only the fingerprint mismatch is left.
+	reports = NewChecker(reg, true, slog.New(slog.NewTextHandler(io.Discard, nil))).RunOnce(ctx)
+	findings = 0
+	for _, r := range reports {
+		findings += r.Unrepaired()
+	}
+	if findings != 1 {
+		t.Errorf("expected 1 unrepaired finding after repair, got %d", findings)
+	}
+	if next, err := store.PeekNextID(ctx, ".broken"); err != nil || next != 41 {
+		t.Errorf("PeekNextID = %d, %v; want 41", next, err)
+	}
+}
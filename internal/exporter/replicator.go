@@ -0,0 +1,664 @@
+package exporter
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/metrics"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/pkg/client"
+)
+
+// Exporter states stored in storage.ExporterStatusRecord.State.
+const (
+	StateStarting = "STARTING"
+	StateRunning  = "RUNNING"
+	StatePaused   = "PAUSED"
+	StateError    = "ERROR"
+)
+
+// Exporter config keys understood by the replicator (Confluent-compatible names).
+const (
+	ConfigRemoteURL         = "schema.registry.url"
+	ConfigBasicAuthSource   = "basic.auth.credentials.source"
+	ConfigBasicAuthInfo     = "basic.auth.user.info"
+	ConfigBearerAuthSource  = "bearer.auth.credentials.source"
+	ConfigBearerAuthToken   = "bearer.auth.token"
+	ConfigTruststoreType    = "schema.registry.ssl.truststore.type"
+	ConfigTruststoreFile    = "schema.registry.ssl.truststore.location"
+	ConfigTruststoreCerts   = "schema.registry.ssl.truststore.certificates"
+	ConfigEndpointAlgorithm = "schema.registry.ssl.endpoint.identification.algorithm"
+)
+
+// allContextsPrefix is the subject filter prefix selecting subjects in every
+// context, as in ":*:" or ":*:orders-*".
+const allContextsPrefix = ":*:"
+
+// ReplicatorOption configures a Replicator.
+type ReplicatorOption func(*Replicator)
+
+// WithMetrics sets the Prometheus metrics used to report exporter lag and throughput.
+func WithMetrics(m *metrics.Metrics) ReplicatorOption {
+	return func(r *Replicator) {
+		r.metrics = m
+	}
+}
+
+// WithClusterID sets the local cluster ID used to name the destination
+// context of exporters with contextType AUTO.
+func WithClusterID(id string) ReplicatorOption {
+	return func(r *Replicator) {
+		r.clusterID = id
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to talk to remote registries.
+func WithHTTPClient(c *http.Client) ReplicatorOption {
+	return func(r *Replicator) {
+		r.client = c
+	}
+}
+
+// Replicator runs the exporters managed through the /exporters API. On every
+// pass it copies the schema versions selected by each RUNNING exporter to the
+// remote registry named in the exporter config, preserving schema IDs and
+// version numbers by registering them in IMPORT mode.
+type Replicator struct {
+	store     storage.Storage
+	client    *http.Client
+	clusterID string
+	metrics   *metrics.Metrics
+	logger    *slog.Logger
+
+	mu    sync.Mutex
+	links map[string]*linkState
+}
+
+// linkState is the in-memory replication state of a single exporter.
+type linkState struct {
+	// offset is the status offset last written by the replicator. A stored
+	// offset lower than this means the exporter was reset.
+	offset int64
+	// exported maps a source subject (context-qualified) to the version up
+	// to which every version is known to be present at the destination.
+	exported map[string]int
+	// importMode records destination subjects already switched to IMPORT mode.
+	importMode map[string]bool
+}
+
+func newLinkState(offset int64) *linkState {
+	return &linkState{
+		offset:     offset,
+		exported:   make(map[string]int),
+		importMode: make(map[string]bool),
+	}
+}
+
+// NewReplicator creates a new exporter replicator.
+func NewReplicator(store storage.Storage, logger *slog.Logger, opts ...ReplicatorOption) *Replicator {
+	r := &Replicator{
+		store:  store,
+		client: &http.Client{Timeout: 30 * time.Second},
+		logger: logger,
+		links:  make(map[string]*linkState),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start starts a background goroutine that runs a replication pass every
+// interval. The goroutine stops when the stop channel is closed.
+func (r *Replicator) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.RunOnce(context.Background())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// RunOnce runs a single replication pass over all exporters.
+func (r *Replicator) RunOnce(ctx context.Context) {
+	names, err := r.store.ListExporters(ctx)
+	if err != nil {
+		r.logger.Error("exporter replication: failed to list exporters", slog.String("error", err.Error()))
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Forget state of exporters that were deleted.
+	live := make(map[string]bool, len(names))
+	for _, name := range names {
+		live[name] = true
+	}
+	for name := range r.links {
+		if !live[name] {
+			delete(r.links, name)
+		}
+	}
+
+	for _, name := range names {
+		r.runExporter(ctx, name)
+	}
+}
+
+// runExporter runs a single replication pass for one exporter.
+func (r *Replicator) runExporter(ctx context.Context, name string) {
+	exp, err := r.store.GetExporter(ctx, name)
+	if err != nil {
+		return
+	}
+	status, err := r.store.GetExporterStatus(ctx, name)
+	if err != nil || status == nil || status.State == StatePaused {
+		return
+	}
+
+	link, ok := r.links[name]
+	if !ok || status.Offset < link.offset {
+		// First pass since startup, or the exporter was reset: re-check the
+		// destination for every subject.
+		link = newLinkState(status.Offset)
+		r.links[name] = link
+	}
+
+	exported, pending, syncErr := r.sync(ctx, exp, link)
+	link.offset += int64(exported)
+
+	if r.metrics != nil {
+		r.metrics.RecordExporterSync(name, exported, pending, syncErr)
+	}
+
+	if syncErr != nil {
+		r.logger.Warn("exporter replication failed",
+			slog.String("exporter", name),
+			slog.Int("exported", exported),
+			slog.Int("pending", pending),
+			slog.String("error", syncErr.Error()),
+		)
+		// Re-check the destination on the next pass rather than trusting
+		// versions cached before the failure.
+		link.exported = make(map[string]int)
+	}
+
+	r.updateStatus(ctx, name, link.offset, syncErr)
+}
+
+// updateStatus persists the exporter status after a pass. A pause issued
+// while the pass was running is preserved.
+func (r *Replicator) updateStatus(ctx context.Context, name string, offset int64, syncErr error) {
+	current, err := r.store.GetExporterStatus(ctx, name)
+	if err != nil || current == nil {
+		return
+	}
+
+	next := *current
+	next.Offset = offset
+	switch {
+	case current.State == StatePaused:
+		// Keep PAUSED, only record progress.
+	case syncErr != nil:
+		next.State = StateError
+		next.Trace = syncErr.Error()
+	default:
+		next.State = StateRunning
+		next.Trace = ""
+	}
+
+	if next == *current {
+		return
+	}
+	next.Ts = time.Now().UnixMilli()
+	if err := r.store.SetExporterStatus(ctx, name, &next); err != nil {
+		r.logger.Error("exporter replication: failed to update status",
+			slog.String("exporter", name),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// pendingVersion is a source schema version that has not been replicated yet.
+type pendingVersion struct {
+	key    string // context-qualified source subject
+	record *storage.SchemaRecord
+	srcCtx string
+}
+
+// sync replicates all pending versions of an exporter. It returns the number
+// of versions replicated and the number still pending.
+func (r *Replicator) sync(ctx context.Context, exp *storage.ExporterRecord, link *linkState) (int, int, error) {
+	remote, err := newRemoteClient(r.client, exp.Config)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sources, err := r.selectSubjects(ctx, exp)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var pending []pendingVersion
+	// latestOf and missing record, per subject with pending versions, its
+	// latest version and the versions not yet at the destination.
+	latestOf := make(map[string]int)
+	missing := make(map[string]map[int]bool)
+	for _, src := range sources {
+		key := registrycontext.FormatSubject(src.ctx, src.subject)
+		versions, err := r.store.GetSchemasBySubject(ctx, src.ctx, src.subject, false)
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			// Deleted since the subjects were listed.
+			continue
+		}
+		if err != nil {
+			return 0, len(pending), fmt.Errorf("get versions of %s: %w", key, err)
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		latest := 0
+		for _, v := range versions {
+			if v.Version > latest {
+				latest = v.Version
+			}
+		}
+
+		known, cached := link.exported[key]
+		if cached && known >= latest {
+			continue
+		}
+
+		var present map[int]bool
+		if !cached {
+			destSubject := r.destSubject(exp, src.ctx, src.subject)
+			remoteVersions, err := remote.versions(ctx, destSubject)
+			if err != nil {
+				return 0, len(pending), err
+			}
+			present = make(map[int]bool, len(remoteVersions))
+			for _, v := range remoteVersions {
+				present[v] = true
+			}
+		}
+
+		for _, v := range versions {
+			if cached && v.Version <= known {
+				continue
+			}
+			if present[v.Version] {
+				continue
+			}
+			pending = append(pending, pendingVersion{key: key, record: v, srcCtx: src.ctx})
+			if missing[key] == nil {
+				missing[key] = make(map[int]bool)
+			}
+			missing[key][v.Version] = true
+		}
+		if missing[key] == nil {
+			// Destination already has every version of this subject.
+			link.exported[key] = latest
+		}
+		latestOf[key] = latest
+	}
+
+	// Replicate in schema ID order so referenced schemas reach the
+	// destination before the schemas that reference them.
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].record.ID != pending[j].record.ID {
+			return pending[i].record.ID < pending[j].record.ID
+		}
+		if pending[i].key != pending[j].key {
+			return pending[i].key < pending[j].key
+		}
+		return pending[i].record.Version < pending[j].record.Version
+	})
+
+	for i, p := range pending {
+		destSubject := r.destSubject(exp, p.srcCtx, p.record.Subject)
+		if !link.importMode[destSubject] {
+			if err := remote.setImportMode(ctx, destSubject); err != nil {
+				return i, len(pending) - i, err
+			}
+			link.importMode[destSubject] = true
+		}
+
+		refs := make([]storage.Reference, len(p.record.References))
+		for j, ref := range p.record.References {
+			refs[j] = storage.Reference{
+				Name:    ref.Name,
+				Subject: r.destSubject(exp, p.srcCtx, ref.Subject),
+				Version: ref.Version,
+			}
+		}
+
+		if err := remote.register(ctx, destSubject, p.record, refs); err != nil {
+			return i, len(pending) - i, err
+		}
+		// Versions are replicated in schema ID order, and a later version
+		// can reuse a lower ID, so only advance the mark past versions that
+		// are all at the destination.
+		delete(missing[p.key], p.record.Version)
+		if mark := replicatedUpTo(latestOf[p.key], missing[p.key]); mark > link.exported[p.key] {
+			link.exported[p.key] = mark
+		}
+	}
+
+	return len(pending), 0, nil
+}
+
+// replicatedUpTo returns the version up to which every version of a subject
+// is at the destination, given its latest version and the versions missing.
+func replicatedUpTo(latest int, missing map[int]bool) int {
+	mark := latest
+	for v := range missing {
+		if v-1 < mark {
+			mark = v - 1
+		}
+	}
+	return mark
+}
+
+// sourceSubject is a subject selected for replication.
+type sourceSubject struct {
+	ctx     string
+	subject string
+}
+
+// selectSubjects resolves the exporter's subject filter against the local
+// registry. Filter entries are exact subject names or prefixes ending in "*",
+// optionally context-qualified (":.ctx:orders-*" or ":.ctx:*" for a whole
+// context, ":*:" for every context). An empty filter selects every subject
+// in the default context.
+func (r *Replicator) selectSubjects(ctx context.Context, exp *storage.ExporterRecord) ([]sourceSubject, error) {
+	filters := exp.Subjects
+	if len(filters) == 0 {
+		filters = []string{"*"}
+	}
+
+	// Group patterns by source context so each context is listed once.
+	patterns := make(map[string][]string)
+	var contexts []string
+	addPattern := func(srcCtx, pattern string) {
+		if _, ok := patterns[srcCtx]; !ok {
+			contexts = append(contexts, srcCtx)
+		}
+		patterns[srcCtx] = append(patterns[srcCtx], pattern)
+	}
+	var allContexts []string
+	for _, f := range filters {
+		if pattern, ok := strings.CutPrefix(f, allContextsPrefix); ok {
+			if allContexts == nil {
+				listed, err := r.store.ListContexts(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("list contexts: %w", err)
+				}
+				allContexts = []string{registrycontext.DefaultContext}
+				for _, c := range listed {
+					if c != registrycontext.DefaultContext && !registrycontext.IsGlobalContext(c) {
+						allContexts = append(allContexts, c)
+					}
+				}
+			}
+			for _, c := range allContexts {
+				addPattern(c, pattern)
+			}
+			continue
+		}
+		addPattern(registrycontext.ResolveSubject(f))
+	}
+
+	var result []sourceSubject
+	for _, srcCtx := range contexts {
+		subjects, err := r.store.ListSubjects(ctx, srcCtx, false)
+		if err != nil {
+			return nil, fmt.Errorf("list subjects in context %s: %w", srcCtx, err)
+		}
+		for _, subject := range subjects {
+			if matchesAny(patterns[srcCtx], subject) {
+				result = append(result, sourceSubject{ctx: srcCtx, subject: subject})
+			}
+		}
+	}
+	return result, nil
+}
+
+// matchesAny reports whether subject matches one of the filter patterns.
+func matchesAny(patterns []string, subject string) bool {
+	for _, p := range patterns {
+		if p == "" || p == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(subject, prefix) {
+				return true
+			}
+			continue
+		}
+		if p == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// destSubject returns the context-qualified subject name at the destination.
+func (r *Replicator) destSubject(exp *storage.ExporterRecord, srcCtx, subject string) string {
+	renamed := subject
+	if exp.SubjectRenameFormat != "" {
+		renamed = strings.ReplaceAll(exp.SubjectRenameFormat, "${subject}", subject)
+	}
+
+	switch exp.ContextType {
+	case "NONE":
+		return registrycontext.FormatSubject(srcCtx, renamed)
+	case "CUSTOM":
+		return registrycontext.FormatSubject(registrycontext.NormalizeContextName(exp.Context), renamed)
+	default: // AUTO
+		name := r.clusterID
+		if name == "" {
+			name = exp.Name
+		}
+		return registrycontext.FormatSubject(registrycontext.NormalizeContextName(name), renamed)
+	}
+}
+
+// remoteClient replicates schemas to the remote registry's REST API.
+type remoteClient struct {
+	client *client.Client
+}
+
+func newRemoteClient(httpClient *http.Client, config map[string]string) (*remoteClient, error) {
+	raw := strings.TrimSpace(config[ConfigRemoteURL])
+	if raw == "" {
+		return nil, fmt.Errorf("exporter config %q is required", ConfigRemoteURL)
+	}
+	if idx := strings.Index(raw, ","); idx >= 0 {
+		raw = raw[:idx]
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("exporter config %q: %w", ConfigRemoteURL, err)
+	}
+	urlUser := u.User
+	u.User = nil
+
+	// Failed passes are retried on the next sync, and every version is
+	// sent once, so the client neither retries nor caches.
+	opts := []client.Option{
+		client.WithHTTPClient(httpClient),
+		client.WithRetry(0, 0, 0),
+		client.WithoutCache(),
+	}
+
+	switch source := strings.ToUpper(config[ConfigBasicAuthSource]); source {
+	case "", "USER_INFO":
+		if info := config[ConfigBasicAuthInfo]; info != "" {
+			username, password, _ := strings.Cut(info, ":")
+			opts = append(opts, client.WithBasicAuth(username, password))
+		}
+	case "URL":
+		if urlUser == nil {
+			return nil, fmt.Errorf("exporter config %q is URL but %q has no user info", ConfigBasicAuthSource, ConfigRemoteURL)
+		}
+		password, _ := urlUser.Password()
+		opts = append(opts, client.WithBasicAuth(urlUser.Username(), password))
+	default:
+		return nil, fmt.Errorf("exporter config %q: unsupported source %q (must be USER_INFO or URL)", ConfigBasicAuthSource, source)
+	}
+	if config[ConfigBasicAuthInfo] == "" && !strings.EqualFold(config[ConfigBasicAuthSource], "URL") {
+		switch source := strings.ToUpper(config[ConfigBearerAuthSource]); source {
+		case "", "STATIC_TOKEN":
+			if token := config[ConfigBearerAuthToken]; token != "" {
+				opts = append(opts, client.WithBearerToken(token))
+			}
+		default:
+			return nil, fmt.Errorf("exporter config %q: unsupported source %q (must be STATIC_TOKEN)", ConfigBearerAuthSource, source)
+		}
+	}
+
+	tlsCfg, err := remoteTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, client.WithTLSConfig(tlsCfg))
+	}
+
+	c, err := client.New(u.String(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("exporter config %q: %w", ConfigRemoteURL, err)
+	}
+	return &remoteClient{client: c}, nil
+}
+
+// remoteTLSConfig builds the TLS configuration for the remote registry from
+// a PEM truststore, given as a file or inline certificates, and the endpoint
+// identification algorithm. It returns nil when neither is set. JKS and
+// PKCS12 truststores are not supported.
+func remoteTLSConfig(config map[string]string) (*tls.Config, error) {
+	file, certs := config[ConfigTruststoreFile], config[ConfigTruststoreCerts]
+	algorithm, verifyHost := config[ConfigEndpointAlgorithm]
+	if file == "" && certs == "" && !verifyHost {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if file != "" || certs != "" {
+		if t := config[ConfigTruststoreType]; t != "" && !strings.EqualFold(t, "PEM") {
+			return nil, fmt.Errorf("exporter config %q: unsupported type %q (only PEM truststores are supported)", ConfigTruststoreType, t)
+		}
+		pem := []byte(certs)
+		if file != "" {
+			data, err := os.ReadFile(file) // #nosec G304 -- the truststore path is set by a registry administrator
+			if err != nil {
+				return nil, fmt.Errorf("exporter config %q: %w", ConfigTruststoreFile, err)
+			}
+			pem = append(pem, data...)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("exporter truststore contains no PEM certificates")
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if verifyHost && strings.TrimSpace(algorithm) == "" {
+		// As in Confluent clients, an empty algorithm turns off host name
+		// verification while still checking the certificate chain.
+		tlsCfg.InsecureSkipVerify = true // #nosec G402 -- chain verified in VerifyConnection
+		tlsCfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("remote registry sent no certificate")
+			}
+			opts := x509.VerifyOptions{Roots: tlsCfg.RootCAs, Intermediates: x509.NewCertPool()}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		}
+	}
+	return tlsCfg, nil
+}
+
+// versions returns the versions registered under subject at the remote,
+// or nil if the subject does not exist there.
+func (c *remoteClient) versions(ctx context.Context, subject string) ([]int, error) {
+	versions, err := c.client.ListVersions(ctx, subject, false)
+	if client.IsNotFound(err) {
+		return nil, nil
+	}
+	return versions, err
+}
+
+// setImportMode switches a destination subject to IMPORT mode so that
+// schema IDs and versions can be preserved.
+func (c *remoteClient) setImportMode(ctx context.Context, subject string) error {
+	_, err := c.client.SetMode(ctx, subject, client.ModeImport, true)
+	return err
+}
+
+// register registers a schema version at the remote with its original ID and version.
+func (c *remoteClient) register(ctx context.Context, subject string, rec *storage.SchemaRecord, refs []storage.Reference) error {
+	req := client.RegisterRequest{
+		Schema: client.Schema{
+			Schema:   rec.Schema,
+			Metadata: (*client.Metadata)(rec.Metadata),
+			RuleSet:  clientRuleSet(rec.RuleSet),
+		},
+		ID:      rec.ID,
+		Version: rec.Version,
+	}
+	if rec.SchemaType != "" && rec.SchemaType != storage.SchemaTypeAvro {
+		req.SchemaType = string(rec.SchemaType)
+	}
+	for _, ref := range refs {
+		req.References = append(req.References, client.Reference{Name: ref.Name, Subject: ref.Subject, Version: ref.Version})
+	}
+	if _, err := c.client.RegisterSchema(ctx, subject, req); err != nil {
+		return fmt.Errorf("replicate %s version %d (id %d): %w", subject, rec.Version, rec.ID, err)
+	}
+	return nil
+}
+
+// clientRuleSet converts a stored rule set to its client form.
+func clientRuleSet(rs *storage.RuleSet) *client.RuleSet {
+	if rs == nil {
+		return nil
+	}
+	convert := func(rules []storage.Rule) []client.Rule {
+		if rules == nil {
+			return nil
+		}
+		out := make([]client.Rule, len(rules))
+		for i, rule := range rules {
+			out[i] = client.Rule(rule)
+		}
+		return out
+	}
+	return &client.RuleSet{
+		MigrationRules: convert(rs.MigrationRules),
+		DomainRules:    convert(rs.DomainRules),
+		EncodingRules:  convert(rs.EncodingRules),
+	}
+}
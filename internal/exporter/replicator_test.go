@@ -0,0 +1,448 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+// fakeRemote is a minimal remote registry that records imported versions.
+type fakeRemote struct {
+	mu       sync.Mutex
+	versions map[string]map[int]map[string]interface{} // subject -> version -> request body
+	modes    map[string]string
+	auth     string
+	fail     bool
+	reject   int // version whose registration fails, 0 for none
+}
+
+func newFakeRemote() *fakeRemote {
+	return &fakeRemote{
+		versions: make(map[string]map[int]map[string]interface{}),
+		modes:    make(map[string]string),
+	}
+}
+
+func (f *fakeRemote) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auth = r.Header.Get("Authorization")
+	if f.fail {
+		http.Error(w, `{"error_code":50001,"message":"unavailable"}`, http.StatusInternalServerError)
+		return
+	}
+
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/mode/"):
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		f.modes[strings.TrimPrefix(path, "/mode/")] = body["mode"]
+		w.Write([]byte(`{"mode":"IMPORT"}`))
+	case strings.HasPrefix(path, "/subjects/") && strings.HasSuffix(path, "/versions"):
+		subject := strings.TrimSuffix(strings.TrimPrefix(path, "/subjects/"), "/versions")
+		if r.Method == http.MethodGet {
+			versions, ok := f.versions[subject]
+			if !ok {
+				http.Error(w, `{"error_code":40401,"message":"Subject not found"}`, http.StatusNotFound)
+				return
+			}
+			list := []int{}
+			for v := range versions {
+				list = append(list, v)
+			}
+			json.NewEncoder(w).Encode(list)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		if version := int(body["version"].(float64)); version == f.reject {
+			http.Error(w, `{"error_code":50001,"message":"unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		if f.versions[subject] == nil {
+			f.versions[subject] = make(map[int]map[string]interface{})
+		}
+		f.versions[subject][int(body["version"].(float64))] = body
+		w.Write([]byte(`{"id":1}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeRemote) count(subject string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.versions[subject])
+}
+
+func setupReplicatorTest(t *testing.T, exp *storage.ExporterRecord, state string) (*memory.Store, *fakeRemote, *Replicator) {
+	t.Helper()
+	ctx := context.Background()
+
+	remote := newFakeRemote()
+	srv := httptest.NewServer(remote)
+	t.Cleanup(srv.Close)
+
+	store := memory.NewStore()
+	if exp.Config == nil {
+		exp.Config = map[string]string{}
+	}
+	exp.Config[ConfigRemoteURL] = srv.URL
+	if err := store.CreateExporter(ctx, exp); err != nil {
+		t.Fatalf("CreateExporter: %v", err)
+	}
+	if err := store.SetExporterStatus(ctx, exp.Name, &storage.ExporterStatusRecord{Name: exp.Name, State: state}); err != nil {
+		t.Fatalf("SetExporterStatus: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return store, remote, NewReplicator(store, logger, WithHTTPClient(srv.Client()))
+}
+
+func createTestSchema(t *testing.T, store *memory.Store, registryCtx, subject, schema string) *storage.SchemaRecord {
+	t.Helper()
+	rec := &storage.SchemaRecord{
+		Subject:     subject,
+		SchemaType:  storage.SchemaTypeAvro,
+		Schema:      schema,
+		Fingerprint: schema,
+	}
+	if err := store.CreateSchema(context.Background(), registryCtx, rec); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+	return rec
+}
+
+func TestReplicator_ReplicatesPreservingIDsAndVersions(t *testing.T) {
+	store, remote, rep := setupReplicatorTest(t, &storage.ExporterRecord{
+		Name:        "dr",
+		ContextType: "NONE",
+		Subjects:    []string{"orders-*"},
+	}, StateRunning)
+
+	v1 := createTestSchema(t, store, ".", "orders-value", `"string"`)
+	v2 := createTestSchema(t, store, ".", "orders-value", `"int"`)
+	createTestSchema(t, store, ".", "users-value", `"long"`)
+
+	rep.RunOnce(context.Background())
+
+	if got := remote.count("orders-value"); got != 2 {
+		t.Fatalf("expected 2 replicated versions, got %d", got)
+	}
+	if remote.count("users-value") != 0 {
+		t.Error("expected users-value to be filtered out")
+	}
+	if remote.modes["orders-value"] != "IMPORT" {
+		t.Errorf("expected destination subject in IMPORT mode, got %q", remote.modes["orders-value"])
+	}
+	for _, rec := range []*storage.SchemaRecord{v1, v2} {
+		body := remote.versions["orders-value"][rec.Version]
+		if int64(body["id"].(float64)) != rec.ID {
+			t.Errorf("version %d: expected id %d, got %v", rec.Version, rec.ID, body["id"])
+		}
+	}
+
+	status, _ := store.GetExporterStatus(context.Background(), "dr")
+	if status.State != StateRunning || status.Offset != 2 {
+		t.Errorf("expected RUNNING with offset 2, got %s/%d", status.State, status.Offset)
+	}
+
+	// A second pass with nothing new must not re-send anything.
+	remote.versions["orders-value"] = map[int]map[string]interface{}{1: nil, 2: nil}
+	createTestSchema(t, store, ".", "orders-value", `"double"`)
+	rep.RunOnce(context.Background())
+	if got := remote.count("orders-value"); got != 3 {
+		t.Fatalf("expected 3 versions after incremental pass, got %d", got)
+	}
+	if remote.versions["orders-value"][1] != nil {
+		t.Error("expected already-replicated version 1 not to be re-sent")
+	}
+}
+
+func TestReplicator_SkipsPausedExporter(t *testing.T) {
+	store, remote, rep := setupReplicatorTest(t, &storage.ExporterRecord{
+		Name:        "paused",
+		ContextType: "NONE",
+	}, StatePaused)
+
+	createTestSchema(t, store, ".", "orders-value", `"string"`)
+	rep.RunOnce(context.Background())
+
+	if remote.count("orders-value") != 0 {
+		t.Error("expected paused exporter not to replicate")
+	}
+}
+
+func TestReplicator_ContextMapping(t *testing.T) {
+	store, remote, rep := setupReplicatorTest(t, &storage.ExporterRecord{
+		Name:                "linked",
+		ContextType:         "CUSTOM",
+		Context:             "dr",
+		Subjects:            []string{":.team-a:*"},
+		SubjectRenameFormat: "east.${subject}",
+	}, StateRunning)
+
+	createTestSchema(t, store, ".team-a", "orders-value", `"string"`)
+	createTestSchema(t, store, ".", "orders-value", `"int"`)
+	rep.RunOnce(context.Background())
+
+	if got := remote.count(":.dr:east.orders-value"); got != 1 {
+		t.Fatalf("expected 1 version under :.dr:east.orders-value, got %d", got)
+	}
+	if remote.count("orders-value") != 0 {
+		t.Error("expected default-context subject not to be replicated")
+	}
+}
+
+func TestReplicator_ErrorSetsStatusAndRecovers(t *testing.T) {
+	store, remote, rep := setupReplicatorTest(t, &storage.ExporterRecord{
+		Name:        "flaky",
+		ContextType: "NONE",
+		Config:      map[string]string{ConfigBasicAuthInfo: "user:pass"},
+	}, StateRunning)
+
+	createTestSchema(t, store, ".", "orders-value", `"string"`)
+
+	remote.fail = true
+	rep.RunOnce(context.Background())
+
+	status, _ := store.GetExporterStatus(context.Background(), "flaky")
+	if status.State != StateError || status.Trace == "" {
+		t.Fatalf("expected ERROR with trace, got %s/%q", status.State, status.Trace)
+	}
+	if !strings.HasPrefix(remote.auth, "Basic ") {
+		t.Errorf("expected basic auth header, got %q", remote.auth)
+	}
+
+	remote.fail = false
+	rep.RunOnce(context.Background())
+
+	status, _ = store.GetExporterStatus(context.Background(), "flaky")
+	if status.State != StateRunning || status.Trace != "" {
+		t.Fatalf("expected RUNNING after recovery, got %s/%q", status.State, status.Trace)
+	}
+	if remote.count("orders-value") != 1 {
+		t.Error("expected version to be replicated after recovery")
+	}
+}
+
+func TestReplicator_VersionReusingLowerIDDoesNotSkipEarlierVersion(t *testing.T) {
+	exp := &storage.ExporterRecord{Name: "dr", ContextType: "NONE", Subjects: []string{"orders-value"}}
+	store, remote, rep := setupReplicatorTest(t, exp, StateRunning)
+
+	// Version 2 reuses the lower ID of another subject's schema, so it is
+	// replicated before version 1.
+	createTestSchema(t, store, ".", "users-value", `"int"`)
+	createTestSchema(t, store, ".", "orders-value", `"string"`)
+	createTestSchema(t, store, ".", "orders-value", `"int"`)
+
+	remote.reject = 1
+	link := newLinkState(0)
+	exported, pending, err := rep.sync(context.Background(), exp, link)
+	if err == nil || exported != 1 || pending != 1 {
+		t.Fatalf("sync = %d exported, %d pending, %v; want version 2 exported and version 1 failed", exported, pending, err)
+	}
+	if got := link.exported["orders-value"]; got != 0 {
+		t.Errorf("expected no version to be marked replicated while version 1 is missing, got %d", got)
+	}
+
+	remote.reject = 0
+	if _, _, err := rep.sync(context.Background(), exp, link); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if remote.count("orders-value") != 2 || link.exported["orders-value"] != 2 {
+		t.Errorf("expected both versions replicated, got %d at the destination and mark %d",
+			remote.count("orders-value"), link.exported["orders-value"])
+	}
+}
+
+// failingVersionsStore fails to read the versions of any subject.
+type failingVersionsStore struct {
+	*memory.Store
+}
+
+func (s *failingVersionsStore) GetSchemasBySubject(context.Context, string, string, bool) ([]*storage.SchemaRecord, error) {
+	return nil, errors.New("storage unavailable")
+}
+
+func TestReplicator_VersionReadErrorSetsStatus(t *testing.T) {
+	store, remote, _ := setupReplicatorTest(t, &storage.ExporterRecord{Name: "dr", ContextType: "NONE"}, StateRunning)
+	createTestSchema(t, store, ".", "orders-value", `"string"`)
+
+	rep := NewReplicator(&failingVersionsStore{store}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	rep.RunOnce(context.Background())
+
+	status, _ := store.GetExporterStatus(context.Background(), "dr")
+	if status.State != StateError || !strings.Contains(status.Trace, "storage unavailable") {
+		t.Errorf("expected ERROR with the storage error, got %s/%q", status.State, status.Trace)
+	}
+	if remote.count("orders-value") != 0 {
+		t.Error("expected nothing to be replicated")
+	}
+}
+
+func TestReplicator_ResetResyncs(t *testing.T) {
+	store, remote, rep := setupReplicatorTest(t, &storage.ExporterRecord{
+		Name:        "reset",
+		ContextType: "NONE",
+	}, StateRunning)
+
+	createTestSchema(t, store, ".", "orders-value", `"string"`)
+	rep.RunOnce(context.Background())
+
+	// Destination wiped, then the exporter is reset.
+	remote.versions = make(map[string]map[int]map[string]interface{})
+	store.SetExporterStatus(context.Background(), "reset", &storage.ExporterStatusRecord{Name: "reset", State: StateRunning})
+	rep.RunOnce(context.Background())
+
+	if remote.count("orders-value") != 1 {
+		t.Error("expected reset exporter to re-replicate to the wiped destination")
+	}
+}
+
+func TestReplicator_MissingURL(t *testing.T) {
+	store, _, rep := setupReplicatorTest(t, &storage.ExporterRecord{Name: "nourl"}, StateRunning)
+	store.UpdateExporterConfig(context.Background(), "nourl", map[string]string{})
+
+	rep.RunOnce(context.Background())
+
+	status, _ := store.GetExporterStatus(context.Background(), "nourl")
+	if status.State != StateError || !strings.Contains(status.Trace, ConfigRemoteURL) {
+		t.Errorf("expected ERROR mentioning %s, got %s/%q", ConfigRemoteURL, status.State, status.Trace)
+	}
+}
+
+func TestReplicator_AllContexts(t *testing.T) {
+	store, remote, rep := setupReplicatorTest(t, &storage.ExporterRecord{
+		Name:        "everything",
+		ContextType: "NONE",
+		Subjects:    []string{":*:orders-*"},
+	}, StateRunning)
+
+	createTestSchema(t, store, ".", "orders-value", `"string"`)
+	createTestSchema(t, store, ".team-a", "orders-value", `"int"`)
+	createTestSchema(t, store, ".team-a", "users-value", `"long"`)
+	rep.RunOnce(context.Background())
+
+	if remote.count("orders-value") != 1 || remote.count(":.team-a:orders-value") != 1 {
+		t.Errorf("expected orders-value replicated from both contexts, got %v", remote.versions)
+	}
+	if remote.count(":.team-a:users-value") != 0 {
+		t.Error("expected subjects not matching the pattern to be skipped")
+	}
+}
+
+func TestNewRemoteClient_CredentialSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		wantAuth string
+		wantErr  string
+	}{
+		{"user info", map[string]string{ConfigBasicAuthInfo: "user:pass"}, "Basic dXNlcjpwYXNz", ""},
+		{"url", map[string]string{ConfigBasicAuthSource: "URL"}, "Basic dXNlcjpwYXNz", ""},
+		{"static token", map[string]string{ConfigBearerAuthSource: "STATIC_TOKEN", ConfigBearerAuthToken: "tok"}, "Bearer tok", ""},
+		{"unsupported basic source", map[string]string{ConfigBasicAuthSource: "SASL_INHERIT"}, "", ConfigBasicAuthSource},
+		{"unsupported bearer source", map[string]string{ConfigBearerAuthSource: "OAUTHBEARER"}, "", ConfigBearerAuthSource},
+		{"jks truststore", map[string]string{ConfigTruststoreFile: "/etc/ssl/dr.jks", ConfigTruststoreType: "JKS"}, "", ConfigTruststoreType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newFakeRemote()
+			srv := httptest.NewServer(remote)
+			defer srv.Close()
+
+			tt.config[ConfigRemoteURL] = strings.Replace(srv.URL, "://", "://user:pass@", 1)
+			c, err := newRemoteClient(srv.Client(), tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error mentioning %s, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newRemoteClient: %v", err)
+			}
+			c.versions(context.Background(), "orders-value")
+			if remote.auth != tt.wantAuth {
+				t.Errorf("expected Authorization %q, got %q", tt.wantAuth, remote.auth)
+			}
+		})
+	}
+}
+
+func TestNewRemoteClient_PEMTruststore(t *testing.T) {
+	remote := newFakeRemote()
+	srv := httptest.NewTLSServer(remote)
+	defer srv.Close()
+
+	truststore := filepath.Join(t.TempDir(), "truststore.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(truststore, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, config := range []map[string]string{
+		{ConfigTruststoreFile: truststore, ConfigTruststoreType: "PEM"},
+		{ConfigTruststoreCerts: string(certPEM)},
+		// The test certificate is for 127.0.0.1, so connecting by name only
+		// works without host name verification.
+		{ConfigTruststoreFile: truststore, ConfigEndpointAlgorithm: ""},
+	} {
+		config[ConfigRemoteURL] = srv.URL
+		if _, ok := config[ConfigEndpointAlgorithm]; ok {
+			config[ConfigRemoteURL] = strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+		}
+		c, err := newRemoteClient(&http.Client{}, config)
+		if err != nil {
+			t.Fatalf("newRemoteClient(%v): %v", config, err)
+		}
+		if _, err := c.versions(context.Background(), "orders-value"); err != nil {
+			t.Errorf("versions over TLS with %v: %v", config, err)
+		}
+	}
+
+	c, err := newRemoteClient(&http.Client{}, map[string]string{ConfigRemoteURL: srv.URL})
+	if err != nil {
+		t.Fatalf("newRemoteClient: %v", err)
+	}
+	if _, err := c.versions(context.Background(), "orders-value"); err == nil {
+		t.Error("expected an untrusted certificate to be rejected without a truststore")
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		subject  string
+		want     bool
+	}{
+		{[]string{"*"}, "anything", true},
+		{[]string{"orders-*"}, "orders-value", true},
+		{[]string{"orders-*"}, "users-value", false},
+		{[]string{"orders-value"}, "orders-value", true},
+		{[]string{"orders-value"}, "orders-value-2", false},
+		{[]string{"a", "b-*"}, "b-1", true},
+	}
+	for _, tt := range tests {
+		if got := matchesAny(tt.patterns, tt.subject); got != tt.want {
+			t.Errorf("matchesAny(%v, %q) = %v, want %v", tt.patterns, tt.subject, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+)
+
+// unaryAuthInterceptor authenticates unary calls.
+func (s *Server) unaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor authenticates streaming calls.
+func (s *Server) streamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticatedStream carries the authenticated user in its context.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate identifies the caller with the same methods as the REST API
+// and returns ctx carrying the user. Credentials are read from the call
+// metadata, which the authenticator sees as HTTP headers, and client
+// certificates from the TLS connection.
+func (s *Server) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if s.authenticator == nil {
+		return ctx, nil
+	}
+	start := time.Now()
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, fullMethod, nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid method name")
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			for _, v := range values {
+				r.Header.Add(key, v)
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			r.RemoteAddr = p.Addr.String()
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			r.TLS = &tlsInfo.State
+		}
+	}
+
+	user, reason := s.authenticator.Authenticate(r)
+	if user == nil {
+		if s.metrics != nil {
+			method := "unknown"
+			switch reason {
+			case "invalid_share_token":
+				method = auth.AuthMethodShareToken
+			case "invalid_session_token":
+				method = auth.AuthMethodSession
+			}
+			s.metrics.RecordAuthAttempt(method, false, reason, time.Since(start))
+		}
+		return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	if s.metrics != nil {
+		s.metrics.RecordAuthAttempt(user.Method, true, "", time.Since(start))
+	}
+	if user.PasswordChangeRequired {
+		return nil, status.Error(codes.PermissionDenied, "Password change required: change your password with POST /me/password")
+	}
+
+	if override := r.Header.Get(s.tenantOverrideHeader); s.tenantContexts && override != "" {
+		acting, ok := auth.ActingForTenant(user, override)
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, "Only super admins without a tenant may act for a tenant")
+		}
+		if _, err := s.registry.GetTenant(ctx, override); err != nil {
+			return nil, status.Error(codes.NotFound, "Tenant not found")
+		}
+		s.logger.Info("acting for tenant",
+			slog.String("user", user.Username),
+			slog.String("tenant", override),
+			slog.String("method", fullMethod),
+		)
+		user = acting
+	}
+	return auth.WithUser(ctx, user), nil
+}
+
+// authorize checks that the caller may use perm on a subject of a context,
+// or on the context itself when subject is empty. Share tokens are confined
+// to their scope and tenant users to the contexts their tenant owns, as in
+// the REST API.
+func (s *Server) authorize(ctx context.Context, perm auth.Permission, registryCtx, subject string) error {
+	if s.authenticator == nil {
+		return nil
+	}
+	user := auth.GetUser(ctx)
+	if user == nil {
+		return status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	if user.Share != nil && !sharePermits(user.Share, perm, registryCtx, subject) {
+		return status.Error(codes.PermissionDenied, "Share token does not grant access to this resource")
+	}
+	if user.Tenant != "" {
+		if registryCtx == registrycontext.DefaultContext {
+			return status.Error(codes.PermissionDenied, "Tenant users may only access contexts owned by their tenant")
+		}
+		owner, err := s.registry.ContextTenant(ctx, registryCtx)
+		if err != nil || owner != user.Tenant {
+			return status.Error(codes.PermissionDenied, "Context is not owned by your tenant")
+		}
+	}
+	if s.authorizer != nil && s.authorizer.Enabled() &&
+		!s.authorizer.HasScopedPermission(ctx, user, perm, auth.ResourceScope{Context: registryCtx, Subject: subject}) {
+		return status.Error(codes.PermissionDenied, "Forbidden")
+	}
+	return nil
+}
+
+// authorizeSchemaID checks that the caller may read a schema by ID. A share
+// token scoped to a subject may only read the schemas registered under it.
+func (s *Server) authorizeSchemaID(ctx context.Context, registryCtx string, id int64) error {
+	user := auth.GetUser(ctx)
+	if s.authenticator == nil || user == nil || user.Share == nil || user.Share.Subject == "" {
+		return s.authorize(ctx, auth.PermissionSchemaRead, registryCtx, "")
+	}
+	if registryCtx != user.Share.Context {
+		return status.Error(codes.PermissionDenied, "Share token does not grant access to this resource")
+	}
+	subjects, err := s.registry.GetSubjectsBySchemaID(ctx, registryCtx, id, false)
+	if err != nil || !slices.Contains(subjects, user.Share.Subject) {
+		return status.Error(codes.NotFound, "Schema not found")
+	}
+	return s.authorize(ctx, auth.PermissionSchemaRead, registryCtx, user.Share.Subject)
+}
+
+// sharePermits reports whether a share token may make a call. Share tokens
+// can only read within their context; a subject scope cannot list the
+// context's subjects or schemas, which would reveal other subjects.
+func sharePermits(scope *auth.ShareScope, perm auth.Permission, registryCtx, subject string) bool {
+	switch perm {
+	case auth.PermissionSchemaRead, auth.PermissionConfigRead, auth.PermissionModeRead:
+	default:
+		return false
+	}
+	if registryCtx != scope.Context {
+		return false
+	}
+	if scope.Subject == "" {
+		return true
+	}
+	if subject == "" {
+		return perm != auth.PermissionSchemaRead
+	}
+	return subject == scope.Subject
+}
@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// errorMapping maps a registry or storage error to a gRPC status code. An
+// empty message means the error's own text is returned.
+type errorMapping struct {
+	err     error
+	code    codes.Code
+	message string
+}
+
+// errorMappings is checked in order with errors.Is. As in the REST API,
+// registry errors come first because they may wrap the storage error that
+// caused them.
+var errorMappings = []errorMapping{
+	{registry.ErrIncompatibleSchema, codes.FailedPrecondition, ""},
+	{registry.ErrReferenceCycle, codes.InvalidArgument, ""},
+	{registry.ErrReferenceDepthExceeded, codes.InvalidArgument, ""},
+	{registry.ErrInvalidSchema, codes.InvalidArgument, ""},
+	{registry.ErrUnsupportedSchemaType, codes.InvalidArgument, ""},
+	{registry.ErrInvalidRuleSet, codes.InvalidArgument, ""},
+	{registry.ErrFailedResolveReferences, codes.InvalidArgument, ""},
+	{registry.ErrInvalidCompatibility, codes.InvalidArgument, ""},
+	{registry.ErrInvalidMode, codes.InvalidArgument, ""},
+	{registry.ErrSubjectNameStrategy, codes.InvalidArgument, ""},
+	{registry.ErrLintFailed, codes.InvalidArgument, ""},
+	{registry.ErrInvalidContext, codes.InvalidArgument, ""},
+	{registry.ErrContextNotCreated, codes.NotFound, ""},
+	{registry.ErrContextQuotaExceeded, codes.ResourceExhausted, ""},
+	{registry.ErrQuotaExceeded, codes.ResourceExhausted, ""},
+	{registry.ErrSubjectAliased, codes.FailedPrecondition, ""},
+	{registry.ErrWritesSuspended, codes.FailedPrecondition, ""},
+
+	{storage.ErrSubjectNotFound, codes.NotFound, "Subject not found"},
+	{storage.ErrVersionNotFound, codes.NotFound, "Version not found"},
+	{storage.ErrSchemaNotFound, codes.NotFound, "Schema not found"},
+	{storage.ErrContextNotFound, codes.NotFound, "Context not found"},
+	{storage.ErrSubjectDeleted, codes.NotFound, "Subject was soft deleted"},
+	{storage.ErrInvalidVersion, codes.InvalidArgument, "Invalid version"},
+	{storage.ErrOperationNotPermitted, codes.FailedPrecondition, ""},
+}
+
+// toStatus converts a registry or storage error to a gRPC status error.
+// Unknown errors become Internal without their detail, as the REST API
+// returns a generic 500.
+func toStatus(err error) error {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			message := m.message
+			if message == "" {
+				message = err.Error()
+			}
+			return status.Error(m.code, message)
+		}
+	}
+	slog.Error("internal server error", "error", err)
+	return status.Error(codes.Internal, "Internal server error")
+}
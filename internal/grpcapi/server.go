@@ -0,0 +1,154 @@
+// Package grpcapi provides the gRPC API of the schema registry. It serves
+// axonops.schemaregistry.v1.SchemaRegistryService, which mirrors the core
+// subject, schema, config and mode endpoints of the REST API on the same
+// registry and with the same credentials.
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+
+	schemaregistryv1 "github.com/axonops/axonops-schema-registry/api/grpc/schemaregistry/v1"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/metrics"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// Server is the gRPC API server.
+type Server struct {
+	schemaregistryv1.UnimplementedSchemaRegistryServiceServer
+
+	grpcServer    *grpc.Server
+	registry      *registry.Registry
+	config        *config.GRPCConfig
+	logger        *slog.Logger
+	metrics       *metrics.Metrics
+	authenticator *auth.Authenticator
+	authorizer    *auth.Authorizer
+	tlsConfig     *tls.Config
+
+	// Principal tenancy: tenant users' calls run in their home context.
+	tenantContexts       bool
+	tenantOverrideHeader string
+}
+
+// Option configures a gRPC server.
+type Option func(*Server)
+
+// WithAuth requires every call to be authenticated with the authenticator
+// and authorized with the authorizer, as the REST API does. Without it the
+// API is open.
+func WithAuth(authenticator *auth.Authenticator, authorizer *auth.Authorizer) Option {
+	return func(s *Server) {
+		s.authenticator = authenticator
+		s.authorizer = authorizer
+	}
+}
+
+// WithMetrics sets the Prometheus metrics instance for authentication and
+// registration metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+// WithTenantContexts derives the context of a tenant user's calls from the
+// user's tenant, as principal tenancy does over REST. A super admin without
+// a tenant may act for a tenant by naming it in the overrideHeader metadata.
+func WithTenantContexts(overrideHeader string) Option {
+	return func(s *Server) {
+		s.tenantContexts = true
+		s.tenantOverrideHeader = overrideHeader
+	}
+}
+
+// WithTLSConfig serves the API over TLS. Client certificates are verified
+// as configured in tlsConfig and are available to mTLS authentication.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(s *Server) {
+		s.tlsConfig = tlsConfig
+	}
+}
+
+// New creates a new gRPC server.
+func New(cfg *config.GRPCConfig, reg *registry.Registry, logger *slog.Logger, opts ...Option) *Server {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	s := &Server{
+		registry: reg,
+		config:   cfg,
+		logger:   logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(s.streamAuthInterceptor),
+	}
+	if s.tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	if cfg.MaxMessageSize > 0 {
+		serverOpts = append(serverOpts,
+			grpc.MaxRecvMsgSize(cfg.MaxMessageSize),
+			grpc.MaxSendMsgSize(cfg.MaxMessageSize),
+		)
+	}
+
+	s.grpcServer = grpc.NewServer(serverOpts...)
+	schemaregistryv1.RegisterSchemaRegistryServiceServer(s.grpcServer, s)
+	if cfg.Reflection {
+		reflection.Register(s.grpcServer)
+	}
+	return s
+}
+
+// GRPCServer returns the underlying gRPC server (for testing with an
+// in-memory listener).
+func (s *Server) GRPCServer() *grpc.Server {
+	return s.grpcServer
+}
+
+// Start starts the gRPC server. Blocks until the server stops.
+func (s *Server) Start() error {
+	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("grpc listen: %w", err)
+	}
+	s.logger.Info("gRPC server listening",
+		slog.String("address", addr),
+		slog.Bool("tls", s.tlsConfig != nil),
+	)
+	return s.grpcServer.Serve(ln)
+}
+
+// Shutdown stops the server, letting in-flight calls finish until ctx is
+// done. Calls still running then, such as long exports, are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
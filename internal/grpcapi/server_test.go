@@ -0,0 +1,349 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	schemaregistryv1 "github.com/axonops/axonops-schema-registry/api/grpc/schemaregistry/v1"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+const (
+	userSchemaV1 = `{"type":"record","name":"User","fields":[{"name":"id","type":"long"}]}`
+	userSchemaV2 = `{"type":"record","name":"User","fields":[{"name":"id","type":"long"},{"name":"name","type":"string","default":""}]}`
+)
+
+// newTestClient starts a gRPC server on an in-memory listener and returns
+// a client connected to it.
+func newTestClient(t *testing.T, opts ...Option) schemaregistryv1.SchemaRegistryServiceClient {
+	t.Helper()
+	client, _, _ := startTestServer(t, opts...)
+	return client
+}
+
+// startTestServer is newTestClient that also returns the registry and store
+// behind the server.
+func startTestServer(t *testing.T, opts ...Option) (schemaregistryv1.SchemaRegistryServiceClient, *registry.Registry, *memory.Store) {
+	t.Helper()
+
+	store := memory.NewStore()
+	t.Cleanup(func() { store.Close() })
+
+	schemaReg := schema.NewRegistry()
+	schemaReg.Register(avro.NewParser())
+	compatChecker := compatibility.NewChecker()
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := registry.New(store, schemaReg, compatChecker, "BACKWARD")
+
+	srv := New(&config.GRPCConfig{Enabled: true}, reg, nil, opts...)
+	ln := bufconn.Listen(1 << 20)
+	go srv.GRPCServer().Serve(ln) //nolint:errcheck
+	t.Cleanup(srv.GRPCServer().Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return schemaregistryv1.NewSchemaRegistryServiceClient(conn), reg, store
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected %s, got %s (%v)", want, got, err)
+	}
+}
+
+func TestRegisterAndRead(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	reg, err := client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: "users-value", Schema: userSchemaV1})
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if reg.GetVersion() != 1 {
+		t.Errorf("expected version 1, got %d", reg.GetVersion())
+	}
+
+	byID, err := client.GetSchemaById(ctx, &schemaregistryv1.GetSchemaByIdRequest{Id: reg.GetId()})
+	if err != nil {
+		t.Fatalf("GetSchemaById: %v", err)
+	}
+	if byID.GetSchemaType() != schemaregistryv1.SchemaType_SCHEMA_TYPE_AVRO {
+		t.Errorf("expected AVRO, got %s", byID.GetSchemaType())
+	}
+
+	if _, err := client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: "users-value", Schema: userSchemaV2}); err != nil {
+		t.Fatalf("RegisterSchema v2: %v", err)
+	}
+	latest, err := client.GetSchemaByVersion(ctx, &schemaregistryv1.GetSchemaByVersionRequest{Subject: "users-value"})
+	if err != nil {
+		t.Fatalf("GetSchemaByVersion: %v", err)
+	}
+	if latest.GetVersion() != 2 {
+		t.Errorf("expected latest version 2, got %d", latest.GetVersion())
+	}
+
+	found, err := client.LookupSchema(ctx, &schemaregistryv1.LookupSchemaRequest{Subject: "users-value", Schema: userSchemaV1})
+	if err != nil {
+		t.Fatalf("LookupSchema: %v", err)
+	}
+	if found.GetVersion() != 1 || found.GetId() != reg.GetId() {
+		t.Errorf("expected version 1 with ID %d, got version %d with ID %d", reg.GetId(), found.GetVersion(), found.GetId())
+	}
+
+	versions, err := client.ListVersions(ctx, &schemaregistryv1.ListVersionsRequest{Subject: "users-value"})
+	if err != nil {
+		t.Fatalf("ListVersions: %v", err)
+	}
+	if len(versions.GetVersions()) != 2 {
+		t.Errorf("expected 2 versions, got %v", versions.GetVersions())
+	}
+
+	subjects, err := client.ListSubjects(ctx, &schemaregistryv1.ListSubjectsRequest{})
+	if err != nil {
+		t.Fatalf("ListSubjects: %v", err)
+	}
+	if len(subjects.GetSubjects()) != 1 || subjects.GetSubjects()[0] != "users-value" {
+		t.Errorf("expected [users-value], got %v", subjects.GetSubjects())
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.GetSchemaById(ctx, &schemaregistryv1.GetSchemaByIdRequest{Id: 42})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: "bad", Schema: "{not avro"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Context: ".__GLOBAL", Subject: "s", Schema: userSchemaV1})
+	assertCode(t, err, codes.InvalidArgument)
+
+	if _, err := client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: "users-value", Schema: userSchemaV1}); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	incompatible := `{"type":"record","name":"User","fields":[{"name":"id","type":"string"}]}`
+	_, err = client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: "users-value", Schema: incompatible})
+	assertCode(t, err, codes.FailedPrecondition)
+}
+
+func TestConfigAndMode(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.SetConfig(ctx, &schemaregistryv1.SetConfigRequest{Subject: "users-value", CompatibilityLevel: "full"}); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	cfg, err := client.GetConfig(ctx, &schemaregistryv1.GetConfigRequest{Subject: "users-value"})
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	if cfg.GetCompatibilityLevel() != "FULL" {
+		t.Errorf("expected FULL, got %s", cfg.GetCompatibilityLevel())
+	}
+
+	if _, err := client.SetMode(ctx, &schemaregistryv1.SetModeRequest{Subject: "users-value", Mode: "READONLY"}); err != nil {
+		t.Fatalf("SetMode: %v", err)
+	}
+	mode, err := client.GetMode(ctx, &schemaregistryv1.GetModeRequest{Subject: "users-value"})
+	if err != nil {
+		t.Fatalf("GetMode: %v", err)
+	}
+	if mode.GetMode() != "READONLY" {
+		t.Errorf("expected READONLY, got %s", mode.GetMode())
+	}
+	_, err = client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: "users-value", Schema: userSchemaV1})
+	assertCode(t, err, codes.FailedPrecondition)
+}
+
+func TestExportSchemas(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	for _, subject := range []string{"a-value", "b-value", "c-value"} {
+		if _, err := client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: subject, Schema: userSchemaV1}); err != nil {
+			t.Fatalf("RegisterSchema %s: %v", subject, err)
+		}
+	}
+	if _, err := client.RegisterSchema(ctx, &schemaregistryv1.RegisterSchemaRequest{Subject: "a-value", Schema: userSchemaV2}); err != nil {
+		t.Fatalf("RegisterSchema v2: %v", err)
+	}
+
+	count := func(req *schemaregistryv1.ExportSchemasRequest) int {
+		stream, err := client.ExportSchemas(ctx, req)
+		if err != nil {
+			t.Fatalf("ExportSchemas: %v", err)
+		}
+		n := 0
+		for {
+			_, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return n
+			}
+			if err != nil {
+				t.Fatalf("Recv: %v", err)
+			}
+			n++
+		}
+	}
+	if n := count(&schemaregistryv1.ExportSchemasRequest{}); n != 4 {
+		t.Errorf("expected 4 schemas, got %d", n)
+	}
+	if n := count(&schemaregistryv1.ExportSchemasRequest{LatestOnly: true}); n != 3 {
+		t.Errorf("expected 3 latest schemas, got %d", n)
+	}
+	if n := count(&schemaregistryv1.ExportSchemasRequest{SubjectPrefix: "a-"}); n != 2 {
+		t.Errorf("expected 2 schemas under a-, got %d", n)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	cfg := config.AuthConfig{
+		Enabled: true,
+		Methods: []string{"api_key"},
+		APIKey:  config.APIKeyConfig{Header: "X-API-Key"},
+		RBAC:    config.RBACConfig{Enabled: true},
+	}
+	authenticator := auth.NewAuthenticator(cfg)
+	authenticator.AddAPIKey(&auth.APIKey{Key: "admin-key", Username: "admin", Role: string(auth.RoleAdmin)})
+	authenticator.AddAPIKey(&auth.APIKey{Key: "reader-key", Username: "reader", Role: string(auth.RoleReadOnly)})
+	client := newTestClient(t, WithAuth(authenticator, auth.NewAuthorizer(cfg.RBAC)))
+
+	req := &schemaregistryv1.RegisterSchemaRequest{Subject: "users-value", Schema: userSchemaV1}
+
+	_, err := client.RegisterSchema(context.Background(), req)
+	assertCode(t, err, codes.Unauthenticated)
+
+	readerCtx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "reader-key")
+	_, err = client.RegisterSchema(readerCtx, req)
+	assertCode(t, err, codes.PermissionDenied)
+
+	adminCtx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "admin-key")
+	if _, err := client.RegisterSchema(adminCtx, req); err != nil {
+		t.Fatalf("RegisterSchema as admin: %v", err)
+	}
+	if _, err := client.ListSubjects(readerCtx, &schemaregistryv1.ListSubjectsRequest{}); err != nil {
+		t.Fatalf("ListSubjects as reader: %v", err)
+	}
+
+	stream, err := client.ExportSchemas(context.Background(), &schemaregistryv1.ExportSchemasRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	assertCode(t, err, codes.Unauthenticated)
+}
+
+func TestTenantContexts(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.AuthConfig{
+		Enabled: true,
+		Methods: []string{"basic"},
+		RBAC:    config.RBACConfig{Enabled: true},
+	}
+	authenticator := auth.NewAuthenticator(cfg)
+	client, reg, store := startTestServer(t,
+		WithAuth(authenticator, auth.NewAuthorizer(cfg.RBAC)),
+		WithTenantContexts("X-Registry-Tenant"),
+	)
+	svc := auth.NewService(store)
+	authenticator.SetService(svc)
+	reg.SetTenantHomeContexts(true)
+
+	for _, name := range []string{"acme", "globex"} {
+		if err := reg.CreateTenant(ctx, &storage.TenantRecord{Name: name}); err != nil {
+			t.Fatalf("CreateTenant(%s): %v", name, err)
+		}
+	}
+	for _, u := range []auth.CreateUserRequest{
+		{Username: "alice", Password: "alice-pass", Role: string(auth.RoleDeveloper), Enabled: true, Tenant: "acme"},
+		{Username: "root", Password: "root-pass", Role: string(auth.RoleSuperAdmin), Enabled: true},
+	} {
+		if _, err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatalf("CreateUser(%s): %v", u.Username, err)
+		}
+	}
+	as := func(user string, kv ...string) context.Context {
+		creds := base64.StdEncoding.EncodeToString([]byte(user + ":" + user + "-pass"))
+		return metadata.AppendToOutgoingContext(ctx, append([]string{"authorization", "Basic " + creds}, kv...)...)
+	}
+
+	// A tenant user's calls run in the tenant's home context.
+	req := &schemaregistryv1.RegisterSchemaRequest{Subject: "users-value", Schema: userSchemaV1}
+	if _, err := client.RegisterSchema(as("alice"), req); err != nil {
+		t.Fatalf("RegisterSchema as alice: %v", err)
+	}
+	if _, err := reg.GetSchemaBySubjectVersion(ctx, ".acme", "users-value", 1); err != nil {
+		t.Errorf("expected the schema in .acme: %v", err)
+	}
+	_, err := client.ListSubjects(as("alice"), &schemaregistryv1.ListSubjectsRequest{Context: ".globex"})
+	assertCode(t, err, codes.PermissionDenied)
+	_, err = client.ListSubjects(as("alice", "x-registry-tenant", "globex"), &schemaregistryv1.ListSubjectsRequest{})
+	assertCode(t, err, codes.PermissionDenied)
+
+	// A super admin acts for a tenant with the override header.
+	if _, err := client.RegisterSchema(as("root", "x-registry-tenant", "globex"), req); err != nil {
+		t.Fatalf("RegisterSchema for globex: %v", err)
+	}
+	if _, err := reg.GetSchemaBySubjectVersion(ctx, ".globex", "users-value", 1); err != nil {
+		t.Errorf("expected the schema in .globex: %v", err)
+	}
+	_, err = client.ListSubjects(as("root", "x-registry-tenant", "initech"), &schemaregistryv1.ListSubjectsRequest{})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestSharePermits(t *testing.T) {
+	contextScope := &auth.ShareScope{Context: ".partner"}
+	subjectScope := &auth.ShareScope{Context: ".partner", Subject: "orders-value"}
+
+	tests := []struct {
+		name    string
+		scope   *auth.ShareScope
+		perm    auth.Permission
+		context string
+		subject string
+		want    bool
+	}{
+		{"context scope reads subject", contextScope, auth.PermissionSchemaRead, ".partner", "any", true},
+		{"context scope lists subjects", contextScope, auth.PermissionSchemaRead, ".partner", "", true},
+		{"other context", contextScope, auth.PermissionSchemaRead, ".", "any", false},
+		{"write refused", contextScope, auth.PermissionSchemaWrite, ".partner", "any", false},
+		{"subject scope reads its subject", subjectScope, auth.PermissionSchemaRead, ".partner", "orders-value", true},
+		{"subject scope reads other subject", subjectScope, auth.PermissionSchemaRead, ".partner", "users-value", false},
+		{"subject scope cannot list subjects", subjectScope, auth.PermissionSchemaRead, ".partner", "", false},
+		{"subject scope reads context config", subjectScope, auth.PermissionConfigRead, ".partner", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sharePermits(tt.scope, tt.perm, tt.context, tt.subject); got != tt.want {
+				t.Errorf("sharePermits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	schemaregistryv1 "github.com/axonops/axonops-schema-registry/api/grpc/schemaregistry/v1"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// exportPageSize is how many schemas ExportSchemas reads from storage at a
+// time.
+const exportPageSize = 500
+
+// RegisterSchema implements SchemaRegistryService.RegisterSchema.
+func (s *Server) RegisterSchema(ctx context.Context, req *schemaregistryv1.RegisterSchemaRequest) (*schemaregistryv1.RegisterSchemaResponse, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionSchemaWrite, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	subject, err = s.registry.ResolveWriteAlias(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if req.GetSchema() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Empty schema")
+	}
+
+	// Normal registration is blocked by READONLY, READONLY_OVERRIDE and
+	// IMPORT modes. Registering with explicit IDs is only available over
+	// REST.
+	mode, err := s.registry.GetMode(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	switch mode {
+	case "READONLY", "READONLY_OVERRIDE":
+		return nil, status.Errorf(codes.FailedPrecondition, "Subject '%s' is in read-only mode", subject)
+	case "IMPORT":
+		return nil, status.Error(codes.FailedPrecondition, "Subject is in import mode. Normal registration is not permitted in IMPORT mode.")
+	}
+	// Registrations held for approval are only available over REST.
+	if user := auth.GetUser(ctx); user != nil && auth.Role(user.Role) == auth.RoleDeveloper && s.registry.RequiresApproval(registryCtx) {
+		return nil, status.Errorf(codes.FailedPrecondition, "Registrations in context '%s' require approval. Submit the schema over the REST API.", registryCtx)
+	}
+
+	schemaType := schemaTypeFromProto(req.GetSchemaType())
+	opts := registry.RegisterOpts{Normalize: req.GetNormalize()}
+	if user := auth.GetUser(ctx); user != nil {
+		opts.Principal, opts.Role = user.Username, user.Role
+	}
+	schema, err := s.registry.RegisterSchema(ctx, registryCtx, subject, req.GetSchema(), schemaType, referencesFromProto(req.GetReferences()), opts)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if s.metrics != nil {
+		s.metrics.SchemaVersions.WithLabelValues(subject).Set(float64(schema.Version))
+	}
+	return &schemaregistryv1.RegisterSchemaResponse{Id: schema.ID, Version: int32(schema.Version)}, nil
+}
+
+// LookupSchema implements SchemaRegistryService.LookupSchema.
+func (s *Server) LookupSchema(ctx context.Context, req *schemaregistryv1.LookupSchemaRequest) (*schemaregistryv1.Schema, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionSchemaRead, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	subject = s.registry.ResolveAlias(ctx, registryCtx, subject)
+	if req.GetSchema() == "" {
+		return nil, status.Error(codes.NotFound, "Schema not found")
+	}
+	schema, err := s.registry.LookupSchema(ctx, registryCtx, subject, req.GetSchema(), schemaTypeFromProto(req.GetSchemaType()),
+		referencesFromProto(req.GetReferences()), req.GetDeleted(), req.GetNormalize())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return schemaToProto(schema), nil
+}
+
+// GetSchemaById implements SchemaRegistryService.GetSchemaById.
+func (s *Server) GetSchemaById(ctx context.Context, req *schemaregistryv1.GetSchemaByIdRequest) (*schemaregistryv1.Schema, error) {
+	registryCtx, _, err := s.resolveSubject(ctx, req.GetContext(), "", true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeSchemaID(ctx, registryCtx, req.GetId()); err != nil {
+		return nil, err
+	}
+	schema, err := s.registry.GetSchemaByID(ctx, registryCtx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return schemaToProto(schema), nil
+}
+
+// GetSchemaByVersion implements SchemaRegistryService.GetSchemaByVersion.
+func (s *Server) GetSchemaByVersion(ctx context.Context, req *schemaregistryv1.GetSchemaByVersionRequest) (*schemaregistryv1.Schema, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionSchemaRead, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	subject = s.registry.ResolveAlias(ctx, registryCtx, subject)
+	version := int(req.GetVersion())
+	if version == 0 {
+		version = -1
+	}
+	if version < -1 {
+		return nil, status.Errorf(codes.InvalidArgument, "The specified version '%d' is not a valid version id", version)
+	}
+	schema, err := s.registry.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return schemaToProto(schema), nil
+}
+
+// ListSubjects implements SchemaRegistryService.ListSubjects.
+func (s *Server) ListSubjects(ctx context.Context, req *schemaregistryv1.ListSubjectsRequest) (*schemaregistryv1.ListSubjectsResponse, error) {
+	registryCtx, _, err := s.resolveSubject(ctx, req.GetContext(), "", true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionSchemaRead, registryCtx, ""); err != nil {
+		return nil, err
+	}
+	subjects, err := s.registry.ListSubjectsPage(ctx, registryCtx, &storage.ListSubjectsParams{
+		Prefix:  req.GetSubjectPrefix(),
+		Deleted: req.GetDeleted(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &schemaregistryv1.ListSubjectsResponse{Subjects: subjects}, nil
+}
+
+// ListVersions implements SchemaRegistryService.ListVersions.
+func (s *Server) ListVersions(ctx context.Context, req *schemaregistryv1.ListVersionsRequest) (*schemaregistryv1.ListVersionsResponse, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionSchemaRead, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	subject = s.registry.ResolveAlias(ctx, registryCtx, subject)
+	versions, err := s.registry.GetVersions(ctx, registryCtx, subject, req.GetDeleted())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if len(versions) == 0 {
+		return nil, status.Error(codes.NotFound, "Subject not found")
+	}
+	resp := &schemaregistryv1.ListVersionsResponse{Versions: make([]int32, 0, len(versions))}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, int32(v))
+	}
+	return resp, nil
+}
+
+// GetConfig implements SchemaRegistryService.GetConfig.
+func (s *Server) GetConfig(ctx context.Context, req *schemaregistryv1.GetConfigRequest) (*schemaregistryv1.Config, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionConfigRead, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	level, err := s.registry.GetConfig(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &schemaregistryv1.Config{CompatibilityLevel: level}, nil
+}
+
+// SetConfig implements SchemaRegistryService.SetConfig.
+func (s *Server) SetConfig(ctx context.Context, req *schemaregistryv1.SetConfigRequest) (*schemaregistryv1.Config, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionConfigWrite, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	if req.GetCompatibilityLevel() == "" {
+		return nil, status.Error(codes.InvalidArgument, "compatibility_level is required")
+	}
+	// Config writes are blocked in read-only modes, as over REST.
+	if mode, err := s.registry.CheckModeForWrite(ctx, registryCtx, subject); err != nil {
+		return nil, toStatus(err)
+	} else if mode != "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "Subject '%s' is in %s mode", subject, mode)
+	}
+	if err := s.registry.SetConfig(ctx, registryCtx, subject, req.GetCompatibilityLevel(), nil); err != nil {
+		return nil, toStatus(err)
+	}
+	return &schemaregistryv1.Config{CompatibilityLevel: strings.ToUpper(req.GetCompatibilityLevel())}, nil
+}
+
+// GetMode implements SchemaRegistryService.GetMode.
+func (s *Server) GetMode(ctx context.Context, req *schemaregistryv1.GetModeRequest) (*schemaregistryv1.Mode, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionModeRead, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	mode, err := s.registry.GetMode(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &schemaregistryv1.Mode{Mode: mode}, nil
+}
+
+// SetMode implements SchemaRegistryService.SetMode.
+func (s *Server) SetMode(ctx context.Context, req *schemaregistryv1.SetModeRequest) (*schemaregistryv1.Mode, error) {
+	registryCtx, subject, err := s.resolveSubject(ctx, req.GetContext(), req.GetSubject(), false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, auth.PermissionModeWrite, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	if req.GetMode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "mode is required")
+	}
+	if err := s.registry.SetMode(ctx, registryCtx, subject, req.GetMode(), req.GetForce()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &schemaregistryv1.Mode{Mode: strings.ToUpper(req.GetMode())}, nil
+}
+
+// ExportSchemas implements SchemaRegistryService.ExportSchemas. Schemas are
+// read from storage a page at a time and sent as they are read, so an
+// export of a large registry does not have to fit in one response.
+func (s *Server) ExportSchemas(req *schemaregistryv1.ExportSchemasRequest, stream grpc.ServerStreamingServer[schemaregistryv1.Schema]) error {
+	ctx := stream.Context()
+	registryCtx, _, err := s.resolveSubject(ctx, req.GetContext(), "", true)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, auth.PermissionSchemaRead, registryCtx, ""); err != nil {
+		return err
+	}
+	params := &storage.ListSchemasParams{
+		SubjectPrefix: req.GetSubjectPrefix(),
+		Deleted:       req.GetDeleted(),
+		LatestOnly:    req.GetLatestOnly(),
+		Limit:         exportPageSize,
+	}
+	for {
+		schemas, err := s.registry.ListSchemas(ctx, registryCtx, params)
+		if err != nil {
+			return toStatus(err)
+		}
+		for _, schema := range schemas {
+			if err := stream.Send(schemaToProto(schema)); err != nil {
+				return err
+			}
+		}
+		if len(schemas) < exportPageSize {
+			return nil
+		}
+		params.Offset += len(schemas)
+	}
+}
+
+// resolveSubject returns the registry context and plain subject of a call.
+// A context-qualified subject (":.ctx:subject") takes precedence over the
+// context field, as over REST. With tenant contexts, a tenant user's calls
+// run in the tenant's home context and may not name any other. Subject and
+// schema operations are refused on the global context; config and mode may
+// be read and set on it.
+func (s *Server) resolveSubject(ctx context.Context, contextName, subject string, rejectGlobal bool) (string, string, error) {
+	registryCtx := registrycontext.NormalizeContextName(contextName)
+	if qualifiedCtx, plain := registrycontext.ResolveSubject(subject); qualifiedCtx != registrycontext.DefaultContext {
+		registryCtx, subject = qualifiedCtx, plain
+	}
+	if !registrycontext.IsValidContextName(registryCtx) {
+		return "", "", status.Errorf(codes.InvalidArgument, "invalid context name %q", registryCtx)
+	}
+	if user := auth.GetUser(ctx); s.tenantContexts && user != nil && user.Tenant != "" {
+		home := registry.TenantHomeContext(user.Tenant)
+		if registryCtx != registrycontext.DefaultContext && registryCtx != home {
+			return "", "", status.Error(codes.PermissionDenied, "Tenant users may only access their tenant's context")
+		}
+		registryCtx = home
+	}
+	if rejectGlobal && registrycontext.IsGlobalContext(registryCtx) {
+		return "", "", status.Error(codes.InvalidArgument,
+			fmt.Sprintf("Subject operations are not permitted on the %s context", registrycontext.GlobalContext))
+	}
+	return registryCtx, subject, nil
+}
+
+func schemaTypeFromProto(t schemaregistryv1.SchemaType) storage.SchemaType {
+	switch t {
+	case schemaregistryv1.SchemaType_SCHEMA_TYPE_PROTOBUF:
+		return storage.SchemaTypeProtobuf
+	case schemaregistryv1.SchemaType_SCHEMA_TYPE_JSON:
+		return storage.SchemaTypeJSON
+	default:
+		return storage.SchemaTypeAvro
+	}
+}
+
+func schemaTypeToProto(t storage.SchemaType) schemaregistryv1.SchemaType {
+	switch t {
+	case storage.SchemaTypeProtobuf:
+		return schemaregistryv1.SchemaType_SCHEMA_TYPE_PROTOBUF
+	case storage.SchemaTypeJSON:
+		return schemaregistryv1.SchemaType_SCHEMA_TYPE_JSON
+	default:
+		return schemaregistryv1.SchemaType_SCHEMA_TYPE_AVRO
+	}
+}
+
+func referencesFromProto(refs []*schemaregistryv1.Reference) []storage.Reference {
+	if len(refs) == 0 {
+		return nil
+	}
+	out := make([]storage.Reference, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, storage.Reference{Name: ref.GetName(), Subject: ref.GetSubject(), Version: int(ref.GetVersion())})
+	}
+	return out
+}
+
+func schemaToProto(schema *storage.SchemaRecord) *schemaregistryv1.Schema {
+	out := &schemaregistryv1.Schema{
+		Id:         schema.ID,
+		Subject:    schema.Subject,
+		Version:    int32(schema.Version),
+		SchemaType: schemaTypeToProto(schema.SchemaType),
+		Schema:     schema.Schema,
+		Deleted:    schema.Deleted,
+	}
+	for _, ref := range schema.References {
+		out.References = append(out.References, &schemaregistryv1.Reference{
+			Name:    ref.Name,
+			Subject: ref.Subject,
+			Version: int32(ref.Version),
+		})
+	}
+	return out
+}
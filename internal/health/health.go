@@ -0,0 +1,161 @@
+// Package health runs the dependency checks behind the readiness endpoint.
+// Each dependency is probed concurrently with a timeout; only critical
+// dependencies decide whether the registry is ready, so a flapping optional
+// dependency such as an LDAP server is reported without taking the registry
+// out of service.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status values reported for the registry and for each dependency.
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// DefaultTimeout is how long a dependency check may take by default.
+const DefaultTimeout = 2 * time.Second
+
+// Probe checks one dependency, returning nil when it is reachable.
+type Probe func(ctx context.Context) error
+
+// DependencyStatus is the result of checking one dependency.
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the result of checking every dependency. Status is DOWN when a
+// critical dependency is down.
+type Report struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+
+	// Failed lists the critical dependencies that are down, sorted.
+	Failed []string `json:"-"`
+}
+
+type check struct {
+	name     string
+	critical bool
+	probe    Probe
+}
+
+// Checker runs the registered dependency checks.
+type Checker struct {
+	timeout time.Duration
+	logger  *slog.Logger
+
+	mu     sync.Mutex
+	checks []check
+	last   map[string]string // status of each dependency at the previous run
+}
+
+// NewChecker creates a Checker whose checks each time out after timeout
+// (DefaultTimeout when zero). A nil logger disables logging.
+func NewChecker(timeout time.Duration, logger *slog.Logger) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout, logger: logger, last: make(map[string]string)}
+}
+
+// Register adds a dependency check, replacing any check with the same name.
+func (c *Checker) Register(name string, critical bool, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.checks {
+		if c.checks[i].name == name {
+			c.checks[i] = check{name, critical, probe}
+			return
+		}
+	}
+	c.checks = append(c.checks, check{name, critical, probe})
+}
+
+// Run checks every dependency concurrently and reports their status.
+// Changes in a dependency's status are logged, so failures are visible in
+// the logs even when error details are not exposed by the endpoint.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.Unlock()
+
+	results := make([]DependencyStatus, len(checks))
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			errs[i] = c.runProbe(ctx, chk.probe, &results[i])
+			results[i].Critical = chk.critical
+		}(i, chk)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]DependencyStatus, len(checks))}
+	for i, chk := range checks {
+		report.Checks[chk.name] = results[i]
+		if results[i].Status == StatusDown && chk.critical {
+			report.Status = StatusDown
+			report.Failed = append(report.Failed, chk.name)
+		}
+	}
+	sort.Strings(report.Failed)
+	c.logTransitions(checks, results, errs)
+	return report
+}
+
+// runProbe runs one probe under the check timeout. A probe that does not
+// return in time is reported down without waiting for it.
+func (c *Checker) runProbe(ctx context.Context, probe Probe, result *DependencyStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- probe(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	result.Status = StatusUp
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return err
+}
+
+func (c *Checker) logTransitions(checks []check, results []DependencyStatus, errs []error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, chk := range checks {
+		prev, seen := c.last[chk.name]
+		c.last[chk.name] = results[i].Status
+		if c.logger == nil || prev == results[i].Status || (!seen && results[i].Status == StatusUp) {
+			continue
+		}
+		if results[i].Status == StatusDown {
+			c.logger.Warn("health check failed",
+				slog.String("dependency", chk.name),
+				slog.Bool("critical", chk.critical),
+				slog.String("error", errs[i].Error()))
+		} else {
+			c.logger.Info("health check recovered", slog.String("dependency", chk.name))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecker_Run(t *testing.T) {
+	c := NewChecker(50*time.Millisecond, nil)
+	c.Register("storage", true, func(context.Context) error { return nil })
+	c.Register("ldap", false, func(context.Context) error { return errors.New("connection refused") })
+
+	report := c.Run(context.Background())
+	if report.Status != StatusUp {
+		t.Errorf("a failing non-critical dependency should not make the registry DOWN, got %s", report.Status)
+	}
+	if got := report.Checks["ldap"]; got.Status != StatusDown || got.Critical || got.Error != "connection refused" {
+		t.Errorf("unexpected ldap status: %+v", got)
+	}
+	if got := report.Checks["storage"]; got.Status != StatusUp || !got.Critical {
+		t.Errorf("unexpected storage status: %+v", got)
+	}
+
+	// Registering the same name again replaces the check.
+	c.Register("ldap", true, func(context.Context) error { return errors.New("connection refused") })
+	report = c.Run(context.Background())
+	if report.Status != StatusDown || len(report.Failed) != 1 || report.Failed[0] != "ldap" {
+		t.Errorf("a failing critical dependency should make the registry DOWN, got %+v", report)
+	}
+	if len(report.Checks) != 2 {
+		t.Errorf("expected 2 checks, got %d", len(report.Checks))
+	}
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	c := NewChecker(20*time.Millisecond, nil)
+	block := make(chan struct{})
+	defer close(block)
+	c.Register("oidc", true, func(context.Context) error {
+		<-block // ignores its context, like a stuck client
+		return nil
+	})
+
+	start := time.Now()
+	report := c.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Run should not wait for a stuck probe, took %s", elapsed)
+	}
+	got := report.Checks["oidc"]
+	if got.Status != StatusDown || got.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("expected a timed-out check to be DOWN with an error, got %+v", got)
+	}
+	if got.LatencyMs < 20 {
+		t.Errorf("expected latency of at least the timeout, got %.2fms", got.LatencyMs)
+	}
+}
@@ -0,0 +1,486 @@
+// Package jobs runs long-running operations in the background and records
+// their state, progress and result in storage so they can be followed and
+// cancelled through the API from any instance.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+var (
+	// ErrUnknownJobType is returned by Submit for a type with no registered Func.
+	ErrUnknownJobType = errors.New("unknown job type")
+	// ErrQueueFull is returned by Submit when no more jobs can be queued.
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrJobFinished is returned by Cancel for a job that has already finished.
+	ErrJobFinished = errors.New("job has already finished")
+	// ErrStopped is returned by Submit after the manager has shut down.
+	ErrStopped = errors.New("job manager is stopped")
+)
+
+// Func runs one job. ctx is cancelled when the job is cancelled or the
+// manager shuts down; long-running work should check it regularly. The
+// returned value is stored as the job result, encoded as JSON.
+type Func func(ctx context.Context, job *Job) (any, error)
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithWorkers sets how many jobs run at the same time on this instance.
+func WithWorkers(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.workers = n
+		}
+	}
+}
+
+// WithQueueSize sets how many submitted jobs may wait for a free worker.
+func WithQueueSize(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.queueSize = n
+		}
+	}
+}
+
+// WithRetention sets how long finished jobs are kept before they are deleted.
+func WithRetention(d time.Duration) Option {
+	return func(m *Manager) {
+		if d > 0 {
+			m.retention = d
+		}
+	}
+}
+
+// WithHeartbeat sets how often active jobs are refreshed in storage. A job
+// not refreshed for three heartbeats is considered abandoned by its instance.
+func WithHeartbeat(d time.Duration) Option {
+	return func(m *Manager) {
+		if d > 0 {
+			m.heartbeat = d
+		}
+	}
+}
+
+// Manager queues and runs jobs on a fixed pool of workers.
+//
+// Several instances may share the same storage. Each runs only the jobs
+// submitted to it, but any instance can report on or cancel any job: a
+// cancellation is recorded in storage and picked up by the owning instance
+// on its next heartbeat. Jobs left unfinished by an instance that stopped
+// heartbeating are marked failed by the others.
+type Manager struct {
+	store     storage.Storage
+	logger    *slog.Logger
+	workers   int
+	queueSize int
+	retention time.Duration
+	heartbeat time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	funcs  map[string]Func
+	active map[string]*Job
+	queue  chan *Job
+	base   context.Context
+	stop   context.CancelFunc
+}
+
+// NewManager creates a new job manager. Job types must be registered before
+// Start is called.
+func NewManager(store storage.Storage, logger *slog.Logger, opts ...Option) *Manager {
+	m := &Manager{
+		store:     store,
+		logger:    logger,
+		workers:   4,
+		queueSize: 100,
+		retention: 7 * 24 * time.Hour,
+		heartbeat: 10 * time.Second,
+		now:       time.Now,
+		funcs:     make(map[string]Func),
+		active:    make(map[string]*Job),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.queue = make(chan *Job, m.queueSize)
+	m.base, m.stop = context.WithCancel(context.Background())
+	return m
+}
+
+// Register sets the function that runs jobs of the given type.
+func (m *Manager) Register(jobType string, fn Func) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.funcs[jobType] = fn
+}
+
+// Start starts the workers and the heartbeat loop. When the stop channel is
+// closed, running jobs are cancelled and every job this instance had not
+// finished is marked failed.
+func (m *Manager) Start(stop <-chan struct{}) {
+	var wg sync.WaitGroup
+	for i := 0; i < m.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case job := <-m.queue:
+					m.run(job)
+				case <-m.base.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(m.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.sweep(context.Background())
+			case <-stop:
+				m.stop()
+				wg.Wait()
+				m.failActive("interrupted by shutdown")
+				return
+			}
+		}
+	}()
+}
+
+// Submit stores a new job and queues it to run. params is encoded as JSON
+// and made available to the job through Job.DecodeParams.
+func (m *Manager) Submit(ctx context.Context, jobType, registryCtx string, params any, createdBy string) (*storage.JobRecord, error) {
+	if m.base.Err() != nil {
+		return nil, ErrStopped
+	}
+	m.mu.Lock()
+	_, ok := m.funcs[jobType]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, jobType)
+	}
+
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job parameters: %w", err)
+	}
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+	rec := &storage.JobRecord{
+		ID:        id,
+		Type:      jobType,
+		State:     storage.JobStatePending,
+		Context:   registryCtx,
+		Params:    string(encoded),
+		CreatedBy: createdBy,
+	}
+	if err := m.store.CreateJob(ctx, rec); err != nil {
+		return nil, err
+	}
+
+	job := &Job{m: m, rec: *rec}
+	job.ctx, job.cancel = context.WithCancel(m.base)
+	m.mu.Lock()
+	m.active[id] = job
+	m.mu.Unlock()
+
+	select {
+	case m.queue <- job:
+	default:
+		m.mu.Lock()
+		delete(m.active, id)
+		m.mu.Unlock()
+		job.cancel()
+		_ = m.store.DeleteJob(ctx, id)
+		return nil, ErrQueueFull
+	}
+	out := *rec
+	return &out, nil
+}
+
+// Get returns a job by ID.
+func (m *Manager) Get(ctx context.Context, id string) (*storage.JobRecord, error) {
+	return m.store.GetJob(ctx, id)
+}
+
+// List returns jobs newest first, optionally filtered by type and state.
+func (m *Manager) List(ctx context.Context, jobType, state string) ([]*storage.JobRecord, error) {
+	all, err := m.store.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*storage.JobRecord, 0, len(all))
+	for _, j := range all {
+		if (jobType == "" || j.Type == jobType) && (state == "" || j.State == state) {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs, nil
+}
+
+// Cancel requests cancellation of a job and returns its current record. A
+// job running on this instance is cancelled immediately; one running
+// elsewhere is cancelled on that instance's next heartbeat.
+func (m *Manager) Cancel(ctx context.Context, id string) (*storage.JobRecord, error) {
+	rec, err := m.store.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Finished() {
+		return nil, ErrJobFinished
+	}
+	if err := m.store.RequestJobCancel(ctx, id); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	job := m.active[id]
+	m.mu.Unlock()
+	if job != nil {
+		job.requestCancel()
+	}
+	rec.CancelRequested = true
+	return rec, nil
+}
+
+// run executes one queued job and records its outcome.
+func (m *Manager) run(job *Job) {
+	defer func() {
+		job.cancel()
+		m.mu.Lock()
+		delete(m.active, job.rec.ID)
+		m.mu.Unlock()
+	}()
+
+	if job.ctx.Err() != nil {
+		m.finish(job, nil, job.ctx.Err())
+		return
+	}
+
+	m.mu.Lock()
+	fn := m.funcs[job.rec.Type]
+	m.mu.Unlock()
+
+	job.mu.Lock()
+	job.rec.State = storage.JobStateRunning
+	job.rec.StartedAt = m.now()
+	job.mu.Unlock()
+	job.save()
+
+	result, err := m.call(fn, job)
+	m.finish(job, result, err)
+}
+
+// call runs fn, turning a panic into an error so one faulty job cannot take
+// down the worker pool.
+func (m *Manager) call(fn Func, job *Job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("job panicked",
+				slog.String("job_id", job.rec.ID),
+				slog.String("type", job.rec.Type),
+				slog.Any("panic", r),
+			)
+			result, err = nil, fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return fn(job.ctx, job)
+}
+
+// finish stores a job's terminal state.
+func (m *Manager) finish(job *Job, result any, err error) {
+	job.mu.Lock()
+	switch {
+	case err == nil:
+		job.rec.State = storage.JobStateSucceeded
+		if result != nil {
+			if encoded, encErr := json.Marshal(result); encErr != nil {
+				job.rec.State = storage.JobStateFailed
+				job.rec.Error = fmt.Sprintf("failed to encode job result: %v", encErr)
+			} else {
+				job.rec.Result = string(encoded)
+			}
+		}
+	case job.cancelled:
+		job.rec.State = storage.JobStateCancelled
+	case job.ctx.Err() != nil:
+		job.rec.State = storage.JobStateFailed
+		job.rec.Error = "interrupted by shutdown"
+	default:
+		job.rec.State = storage.JobStateFailed
+		job.rec.Error = err.Error()
+	}
+	job.rec.FinishedAt = m.now()
+	state := job.rec.State
+	job.mu.Unlock()
+	job.save()
+
+	m.logger.Info("job finished",
+		slog.String("job_id", job.rec.ID),
+		slog.String("type", job.rec.Type),
+		slog.String("state", state),
+	)
+}
+
+// sweep refreshes the jobs active on this instance, picks up cancellations
+// requested elsewhere, fails jobs abandoned by other instances and deletes
+// finished jobs older than the retention window.
+func (m *Manager) sweep(ctx context.Context) {
+	m.mu.Lock()
+	active := make(map[string]*Job, len(m.active))
+	for id, job := range m.active {
+		active[id] = job
+	}
+	m.mu.Unlock()
+
+	for id, job := range active {
+		if rec, err := m.store.GetJob(ctx, id); err == nil && rec.CancelRequested {
+			job.requestCancel()
+		}
+		job.save()
+	}
+
+	all, err := m.store.ListJobs(ctx)
+	if err != nil {
+		m.logger.Error("job sweep: failed to list jobs", slog.String("error", err.Error()))
+		return
+	}
+	now := m.now()
+	staleBefore := now.Add(-3 * m.heartbeat)
+	expiredBefore := now.Add(-m.retention)
+	for _, rec := range all {
+		switch {
+		case rec.Finished():
+			if rec.FinishedAt.Before(expiredBefore) {
+				if err := m.store.DeleteJob(ctx, rec.ID); err != nil && !errors.Is(err, storage.ErrJobNotFound) {
+					m.logger.Error("job sweep: failed to delete job",
+						slog.String("job_id", rec.ID),
+						slog.String("error", err.Error()),
+					)
+				}
+			}
+		case active[rec.ID] == nil && rec.UpdatedAt.Before(staleBefore):
+			rec.State = storage.JobStateFailed
+			rec.Error = "abandoned: the instance running this job stopped"
+			rec.FinishedAt = now
+			if err := m.store.UpdateJob(ctx, rec); err != nil && !errors.Is(err, storage.ErrJobNotFound) {
+				m.logger.Error("job sweep: failed to fail abandoned job",
+					slog.String("job_id", rec.ID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
+
+// failActive marks every job this instance has not finished as failed.
+func (m *Manager) failActive(reason string) {
+	m.mu.Lock()
+	active := make([]*Job, 0, len(m.active))
+	for _, job := range m.active {
+		active = append(active, job)
+	}
+	m.active = make(map[string]*Job)
+	m.mu.Unlock()
+
+	for _, job := range active {
+		job.mu.Lock()
+		job.rec.State = storage.JobStateFailed
+		job.rec.Error = reason
+		job.rec.FinishedAt = m.now()
+		job.mu.Unlock()
+		job.save()
+	}
+}
+
+// newJobID returns a random 128-bit job ID.
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Job is the handle a running job uses to read its parameters and report
+// progress.
+type Job struct {
+	m      *Manager
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	rec       storage.JobRecord
+	cancelled bool
+	lastSave  time.Time
+}
+
+// ID returns the job ID.
+func (j *Job) ID() string {
+	return j.rec.ID
+}
+
+// RegistryContext returns the registry context the job was submitted for.
+func (j *Job) RegistryContext() string {
+	return j.rec.Context
+}
+
+// DecodeParams decodes the job parameters into v.
+func (j *Job) DecodeParams(v any) error {
+	return json.Unmarshal([]byte(j.rec.Params), v)
+}
+
+// SetProgress records how much of the job is done. total may be 0 if it is
+// not known. Progress is written to storage at most once a second.
+func (j *Job) SetProgress(done, total int64, message string) {
+	j.mu.Lock()
+	j.rec.Done = done
+	j.rec.Total = total
+	j.rec.Message = message
+	due := j.m.now().Sub(j.lastSave) >= time.Second
+	j.mu.Unlock()
+	if due {
+		j.save()
+	}
+}
+
+// requestCancel cancels the job's context and records that the
+// cancellation was requested rather than caused by shutdown.
+func (j *Job) requestCancel() {
+	j.mu.Lock()
+	j.cancelled = true
+	j.mu.Unlock()
+	j.cancel()
+}
+
+// save writes the job's current state to storage.
+func (j *Job) save() {
+	j.mu.Lock()
+	rec := j.rec
+	j.lastSave = j.m.now()
+	j.mu.Unlock()
+
+	if err := j.m.store.UpdateJob(context.Background(), &rec); err != nil {
+		j.m.logger.Error("failed to update job",
+			slog.String("job_id", rec.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
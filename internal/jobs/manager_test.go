@@ -0,0 +1,298 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func newTestManager(t *testing.T, store storage.Storage, opts ...Option) (*Manager, chan struct{}) {
+	t.Helper()
+	m := NewManager(store, slog.New(slog.NewTextHandler(io.Discard, nil)), opts...)
+	stop := make(chan struct{})
+	return m, stop
+}
+
+// waitForState polls until the job reaches state or the test times out.
+func waitForState(t *testing.T, m *Manager, id, state string) *storage.JobRecord {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec, err := m.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if rec.State == state {
+			return rec
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s: state %s, want %s", id, rec.State, state)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_RunsJob(t *testing.T) {
+	m, stop := newTestManager(t, memory.NewStore())
+	defer close(stop)
+
+	type params struct {
+		N int `json:"n"`
+	}
+	m.Register("count", func(ctx context.Context, job *Job) (any, error) {
+		var p params
+		if err := job.DecodeParams(&p); err != nil {
+			return nil, err
+		}
+		job.SetProgress(int64(p.N), int64(p.N), "counted")
+		return map[string]int{"counted": p.N}, nil
+	})
+	m.Start(stop)
+
+	rec, err := m.Submit(context.Background(), "count", ".staging", params{N: 3}, "alice")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if rec.State != storage.JobStatePending || rec.Context != ".staging" || rec.CreatedBy != "alice" {
+		t.Errorf("submitted record = %+v", rec)
+	}
+
+	done := waitForState(t, m, rec.ID, storage.JobStateSucceeded)
+	if done.Result != `{"counted":3}` {
+		t.Errorf("Result = %q", done.Result)
+	}
+	if done.Done != 3 || done.Total != 3 || done.Message != "counted" {
+		t.Errorf("progress = %d/%d %q", done.Done, done.Total, done.Message)
+	}
+	if done.StartedAt.IsZero() || done.FinishedAt.IsZero() {
+		t.Errorf("timestamps not set: started %v finished %v", done.StartedAt, done.FinishedAt)
+	}
+}
+
+func TestManager_FailedAndPanickingJobs(t *testing.T) {
+	m, stop := newTestManager(t, memory.NewStore())
+	defer close(stop)
+	m.Register("fail", func(ctx context.Context, job *Job) (any, error) {
+		return nil, errors.New("boom")
+	})
+	m.Register("panic", func(ctx context.Context, job *Job) (any, error) {
+		panic("oops")
+	})
+	m.Start(stop)
+
+	failed, err := m.Submit(context.Background(), "fail", "", nil, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if rec := waitForState(t, m, failed.ID, storage.JobStateFailed); rec.Error != "boom" {
+		t.Errorf("Error = %q, want boom", rec.Error)
+	}
+
+	panicked, err := m.Submit(context.Background(), "panic", "", nil, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if rec := waitForState(t, m, panicked.ID, storage.JobStateFailed); rec.Error != "job panicked: oops" {
+		t.Errorf("Error = %q", rec.Error)
+	}
+}
+
+func TestManager_SubmitErrors(t *testing.T) {
+	m, stop := newTestManager(t, memory.NewStore(), WithQueueSize(1))
+	m.Register("noop", func(ctx context.Context, job *Job) (any, error) { return nil, nil })
+
+	if _, err := m.Submit(context.Background(), "missing", "", nil, ""); !errors.Is(err, ErrUnknownJobType) {
+		t.Errorf("unknown type: err = %v, want ErrUnknownJobType", err)
+	}
+
+	// Without Start nothing drains the queue.
+	if _, err := m.Submit(context.Background(), "noop", "", nil, ""); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if _, err := m.Submit(context.Background(), "noop", "", nil, ""); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("full queue: err = %v, want ErrQueueFull", err)
+	}
+	jobs, _ := m.List(context.Background(), "", "")
+	if len(jobs) != 1 {
+		t.Errorf("rejected job was stored: %d jobs", len(jobs))
+	}
+
+	m.Start(stop)
+	close(stop)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := m.Submit(context.Background(), "noop", "", nil, "")
+		if errors.Is(err, ErrStopped) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Submit after stop: err = %v, want ErrStopped", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	m, stop := newTestManager(t, memory.NewStore())
+	defer close(stop)
+	started := make(chan struct{})
+	m.Register("wait", func(ctx context.Context, job *Job) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m.Start(stop)
+
+	rec, err := m.Submit(context.Background(), "wait", "", nil, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+	cancelled, err := m.Cancel(context.Background(), rec.ID)
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if !cancelled.CancelRequested {
+		t.Error("CancelRequested not set")
+	}
+	waitForState(t, m, rec.ID, storage.JobStateCancelled)
+
+	if _, err := m.Cancel(context.Background(), rec.ID); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("cancel finished job: err = %v, want ErrJobFinished", err)
+	}
+	if _, err := m.Cancel(context.Background(), "nope"); !errors.Is(err, storage.ErrJobNotFound) {
+		t.Errorf("cancel missing job: err = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestManager_CancelFromOtherInstance(t *testing.T) {
+	store := memory.NewStore()
+	owner, stop := newTestManager(t, store, WithHeartbeat(10*time.Millisecond))
+	defer close(stop)
+	started := make(chan struct{})
+	owner.Register("wait", func(ctx context.Context, job *Job) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	owner.Start(stop)
+
+	rec, err := owner.Submit(context.Background(), "wait", "", nil, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+
+	other, _ := newTestManager(t, store)
+	if _, err := other.Cancel(context.Background(), rec.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	waitForState(t, owner, rec.ID, storage.JobStateCancelled)
+}
+
+func TestManager_ShutdownFailsUnfinishedJobs(t *testing.T) {
+	m, stop := newTestManager(t, memory.NewStore(), WithWorkers(1))
+	started := make(chan struct{})
+	m.Register("wait", func(ctx context.Context, job *Job) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m.Start(stop)
+
+	running, err := m.Submit(context.Background(), "wait", "", nil, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+	queued, err := m.Submit(context.Background(), "wait", "", nil, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	close(stop)
+
+	for _, id := range []string{running.ID, queued.ID} {
+		rec := waitForState(t, m, id, storage.JobStateFailed)
+		if rec.Error != "interrupted by shutdown" {
+			t.Errorf("job %s: Error = %q", id, rec.Error)
+		}
+	}
+}
+
+func TestManager_Sweep(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+	m, _ := newTestManager(t, store, WithHeartbeat(time.Minute), WithRetention(time.Hour))
+
+	for _, rec := range []*storage.JobRecord{
+		{ID: "abandoned", Type: "t", State: storage.JobStateRunning},
+		{ID: "old", Type: "t", State: storage.JobStateSucceeded},
+		{ID: "recent", Type: "t", State: storage.JobStateSucceeded},
+	} {
+		if err := store.CreateJob(ctx, rec); err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+	}
+	old, _ := store.GetJob(ctx, "old")
+	old.FinishedAt = time.Now().Add(-2 * time.Hour)
+	if err := store.UpdateJob(ctx, old); err != nil {
+		t.Fatalf("UpdateJob: %v", err)
+	}
+	recent, _ := store.GetJob(ctx, "recent")
+	recent.FinishedAt = time.Now()
+	if err := store.UpdateJob(ctx, recent); err != nil {
+		t.Fatalf("UpdateJob: %v", err)
+	}
+
+	// Run the sweep as if the abandoned job had missed its heartbeats.
+	m.now = func() time.Time { return time.Now().Add(5 * time.Minute) }
+	m.sweep(ctx)
+
+	if rec, err := store.GetJob(ctx, "abandoned"); err != nil || rec.State != storage.JobStateFailed {
+		t.Errorf("abandoned job = %+v, %v; want FAILED", rec, err)
+	}
+	if _, err := store.GetJob(ctx, "old"); !errors.Is(err, storage.ErrJobNotFound) {
+		t.Errorf("expired job: err = %v, want ErrJobNotFound", err)
+	}
+	if _, err := store.GetJob(ctx, "recent"); err != nil {
+		t.Errorf("recent job deleted: %v", err)
+	}
+}
+
+func TestManager_List(t *testing.T) {
+	m, stop := newTestManager(t, memory.NewStore())
+	defer close(stop)
+	m.Register("a", func(ctx context.Context, job *Job) (any, error) { return nil, nil })
+	m.Register("b", func(ctx context.Context, job *Job) (any, error) { return nil, errors.New("no") })
+	m.Start(stop)
+
+	a, _ := m.Submit(context.Background(), "a", "", nil, "")
+	b, _ := m.Submit(context.Background(), "b", "", nil, "")
+	waitForState(t, m, a.ID, storage.JobStateSucceeded)
+	waitForState(t, m, b.ID, storage.JobStateFailed)
+
+	tests := []struct {
+		jobType, state string
+		want           int
+	}{
+		{"", "", 2},
+		{"a", "", 1},
+		{"", storage.JobStateFailed, 1},
+		{"a", storage.JobStateFailed, 0},
+	}
+	for _, tt := range tests {
+		jobs, err := m.List(context.Background(), tt.jobType, tt.state)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(jobs) != tt.want {
+			t.Errorf("List(%q, %q) = %d jobs, want %d", tt.jobType, tt.state, len(jobs), tt.want)
+		}
+	}
+}
@@ -66,6 +66,9 @@ func NewProviderFromProps(props map[string]string) (*Provider, error) {
 func (p *Provider) Type() string { return ProviderType }
 func (p *Provider) Close() error { return p.inner.Close() }
 
+// HealthCheck reports whether OpenBao is reachable and unsealed.
+func (p *Provider) HealthCheck(ctx context.Context) error { return p.inner.HealthCheck(ctx) }
+
 func (p *Provider) Wrap(ctx context.Context, kmsKeyID string, plaintext []byte, props map[string]string) ([]byte, error) {
 	return p.inner.Wrap(ctx, kmsKeyID, plaintext, props)
 }
@@ -10,6 +10,7 @@ package kms
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -38,6 +39,12 @@ type Provider interface {
 	Close() error
 }
 
+// HealthChecker is implemented by providers that can report whether their
+// KMS is reachable.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
 // Registry manages available KMS providers, keyed by their type identifier.
 type Registry struct {
 	mu        sync.RWMutex
@@ -95,3 +102,28 @@ func (r *Registry) Close() error {
 	r.providers = make(map[string]Provider)
 	return firstErr
 }
+
+// HealthCheck checks every registered provider that implements
+// HealthChecker, in type order, and returns the first failure.
+func (r *Registry) HealthCheck(ctx context.Context) error {
+	r.mu.RLock()
+	providers := make(map[string]Provider, len(r.providers))
+	types := make([]string, 0, len(r.providers))
+	for t, p := range r.providers {
+		providers[t] = p
+		types = append(types, t)
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(types)
+	for _, t := range types {
+		hc, ok := providers[t].(HealthChecker)
+		if !ok {
+			continue
+		}
+		if err := hc.HealthCheck(ctx); err != nil {
+			return fmt.Errorf("kms provider %q: %w", t, err)
+		}
+	}
+	return nil
+}
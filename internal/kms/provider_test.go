@@ -110,3 +110,25 @@ func (m *errorProvider) Unwrap(_ context.Context, _ string, _ []byte, _ map[stri
 func (m *errorProvider) GenerateDataKey(_ context.Context, _ string, _ string, _ map[string]string) ([]byte, []byte, error) {
 	return nil, nil, nil
 }
+
+// checkedProvider is a mock provider that also reports its health.
+type checkedProvider struct {
+	mockProvider
+	err error
+}
+
+func (c *checkedProvider) HealthCheck(context.Context) error { return c.err }
+
+func TestRegistryHealthCheck(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockProvider{kmsType: "no-health"})
+	reg.Register(&checkedProvider{mockProvider: mockProvider{kmsType: "healthy"}})
+	if err := reg.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+
+	reg.Register(&checkedProvider{mockProvider: mockProvider{kmsType: "sealed"}, err: fmt.Errorf("sealed")})
+	if err := reg.HealthCheck(context.Background()); err == nil {
+		t.Fatal("expected error from unhealthy provider")
+	}
+}
@@ -97,6 +97,18 @@ func (p *Provider) Type() string {
 	return ProviderType
 }
 
+// HealthCheck reports whether Vault is reachable and unsealed.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	resp, err := p.client.Sys().HealthWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("vault: health: %w", err)
+	}
+	if resp.Sealed {
+		return fmt.Errorf("vault: sealed")
+	}
+	return nil
+}
+
 // Wrap encrypts plaintext using Vault Transit's encrypt endpoint.
 func (p *Provider) Wrap(ctx context.Context, kmsKeyID string, plaintext []byte, props map[string]string) ([]byte, error) {
 	path := fmt.Sprintf("%s/encrypt/%s", p.transitMount, kmsKeyID)
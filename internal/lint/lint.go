@@ -0,0 +1,272 @@
+// Package lint checks schemas against style rules, such as requiring field
+// documentation or snake_case field names. Unlike compatibility checking it
+// looks at a single schema, not at how it differs from earlier versions. The
+// registry uses it to enforce a linting policy at registration time.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+
+	"github.com/axonops/axonops-schema-registry/internal/analysis"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Rule identifiers.
+const (
+	// RuleFieldDoc requires every field to be documented: an Avro "doc" or a
+	// JSON Schema "description". Protobuf schemas are not checked.
+	RuleFieldDoc = "field_doc"
+	// RuleFieldSnakeCase requires field names to be snake_case.
+	RuleFieldSnakeCase = "field_snake_case"
+	// RuleTimestampLogicalType rejects Avro long fields whose name suggests a
+	// point in time, such as "created_at" or "event_time", unless they carry
+	// a logicalType such as timestamp-millis.
+	RuleTimestampLogicalType = "timestamp_logical_type"
+)
+
+// Severities a policy can assign to a rule.
+const (
+	// SeverityOff disables the rule.
+	SeverityOff = "off"
+	// SeverityWarn reports violations without rejecting the schema.
+	SeverityWarn = "warn"
+	// SeverityError rejects schemas that violate the rule.
+	SeverityError = "error"
+)
+
+// Rules lists every rule, in the order violations are reported.
+var Rules = []string{RuleFieldDoc, RuleFieldSnakeCase, RuleTimestampLogicalType}
+
+// IsRule reports whether name is a known rule.
+func IsRule(name string) bool {
+	for _, rule := range Rules {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSeverity reports whether s is a known severity.
+func IsSeverity(s string) bool {
+	return s == SeverityOff || s == SeverityWarn || s == SeverityError
+}
+
+// Policy maps a rule to its severity. Rules without an entry are off.
+type Policy map[string]string
+
+// With returns p with the entries of override applied on top, so that a
+// more specific policy can enable, disable or change the severity of rules.
+func (p Policy) With(override Policy) Policy {
+	if len(override) == 0 {
+		return p
+	}
+	merged := make(Policy, len(p)+len(override))
+	for rule, severity := range p {
+		merged[rule] = severity
+	}
+	for rule, severity := range override {
+		merged[rule] = severity
+	}
+	return merged
+}
+
+// Enabled reports whether any rule in p is on.
+func (p Policy) Enabled() bool {
+	for _, severity := range p {
+		if severity == SeverityWarn || severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Violation is one place where a schema breaks a rule.
+type Violation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats the violation for error messages and warnings.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Errors returns the violations with SeverityError.
+func Errors(violations []Violation) []Violation {
+	return filter(violations, SeverityError)
+}
+
+// Warnings returns the violations with SeverityWarn.
+func Warnings(violations []Violation) []Violation {
+	return filter(violations, SeverityWarn)
+}
+
+func filter(violations []Violation, severity string) []Violation {
+	var out []Violation
+	for _, v := range violations {
+		if v.Severity == severity {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// field is a schema field as seen by the rules.
+type field struct {
+	path string
+	name string
+	doc  string
+	// rawLong is set for Avro fields of type long without a logicalType,
+	// the only fields RuleTimestampLogicalType applies to.
+	rawLong bool
+}
+
+// Check lints a schema against the rules policy enables. refs carry the
+// resolved content of the schema's references; types defined by them are not
+// linted, since they were linted when they were registered. A schema that
+// cannot be parsed has no violations: parsing errors are reported by the
+// registry's parser.
+func Check(policy Policy, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference) []Violation {
+	if !policy.Enabled() {
+		return nil
+	}
+
+	var fields []field
+	switch schemaType {
+	case storage.SchemaTypeAvro, "":
+		fields = avroFields(schemaStr, refs)
+	case storage.SchemaTypeJSON, storage.SchemaTypeProtobuf:
+		for _, f := range analysis.ExtractFields(schemaStr, schemaType) {
+			fields = append(fields, field{path: f.Path, name: f.Name, doc: f.Doc})
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].path < fields[j].path })
+
+	var violations []Violation
+	report := func(rule, path, format string, args ...interface{}) {
+		severity := policy[rule]
+		if severity != SeverityWarn && severity != SeverityError {
+			return
+		}
+		violations = append(violations, Violation{
+			Rule:     rule,
+			Severity: severity,
+			Path:     path,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, rule := range Rules {
+		for _, f := range fields {
+			switch rule {
+			case RuleFieldDoc:
+				if f.doc == "" && schemaType != storage.SchemaTypeProtobuf {
+					report(rule, f.path, "field '%s' has no documentation", f.path)
+				}
+			case RuleFieldSnakeCase:
+				if !snakeCase.MatchString(f.name) {
+					report(rule, f.path, "field '%s' is not snake_case", f.path)
+				}
+			case RuleTimestampLogicalType:
+				if f.rawLong && looksLikeTimestamp(f.name) {
+					report(rule, f.path, "field '%s' is a long without a logicalType; use timestamp-millis or timestamp-micros", f.path)
+				}
+			}
+		}
+	}
+	return violations
+}
+
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// looksLikeTimestamp reports whether a field name suggests a point in time.
+func looksLikeTimestamp(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range []string{"_at", "_ts", "time", "timestamp"} {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// avroFields parses an Avro schema and returns the fields of the records it
+// defines, skipping records defined by its references.
+func avroFields(schemaStr string, refs []storage.Reference) []field {
+	cache := &avro.SchemaCache{}
+	referenced := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.Schema == "" {
+			continue
+		}
+		s, err := avro.ParseWithCache(ref.Schema, "", cache)
+		if err != nil {
+			return nil
+		}
+		if named, ok := s.(avro.NamedSchema); ok {
+			referenced[named.FullName()] = true
+		}
+	}
+	s, err := avro.ParseWithCache(schemaStr, "", cache)
+	if err != nil {
+		return nil
+	}
+	var fields []field
+	walkAvro(s, "", referenced, &fields)
+	return fields
+}
+
+// walkAvro appends the fields of the records reachable from s. visited holds
+// the records already walked, or defined by references, so recursive types
+// terminate.
+func walkAvro(s avro.Schema, prefix string, visited map[string]bool, fields *[]field) {
+	switch v := s.(type) {
+	case *avro.RecordSchema:
+		if visited[v.FullName()] {
+			return
+		}
+		visited[v.FullName()] = true
+		for _, f := range v.Fields() {
+			path := f.Name()
+			if prefix != "" {
+				path = prefix + "." + f.Name()
+			}
+			*fields = append(*fields, field{path: path, name: f.Name(), doc: f.Doc(), rawLong: isRawLong(f.Type())})
+			walkAvro(f.Type(), path, visited, fields)
+		}
+	case *avro.ArraySchema:
+		walkAvro(v.Items(), prefix+"[]", visited, fields)
+	case *avro.MapSchema:
+		walkAvro(v.Values(), prefix+"{}", visited, fields)
+	case *avro.UnionSchema:
+		for _, t := range v.Types() {
+			walkAvro(t, prefix, visited, fields)
+		}
+	case *avro.RefSchema:
+		walkAvro(v.Schema(), prefix, visited, fields)
+	}
+}
+
+// isRawLong reports whether s is a long, or a union with a long, that has no
+// logicalType.
+func isRawLong(s avro.Schema) bool {
+	switch v := s.(type) {
+	case *avro.PrimitiveSchema:
+		return v.Type() == avro.Long && v.Logical() == nil
+	case *avro.UnionSchema:
+		for _, t := range v.Types() {
+			if isRawLong(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
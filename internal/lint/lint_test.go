@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func TestCheck_Avro(t *testing.T) {
+	schema := `{"type":"record","name":"Order","fields":[
+		{"name":"order_id","type":"string","doc":"Order identifier"},
+		{"name":"createdAt","type":"long","doc":"Creation time"},
+		{"name":"updated_at","type":{"type":"long","logicalType":"timestamp-millis"}},
+		{"name":"shipped_at","type":["null","long"],"default":null,"doc":"Ship time"}
+	]}`
+	policy := Policy{RuleFieldDoc: SeverityWarn, RuleFieldSnakeCase: SeverityError, RuleTimestampLogicalType: SeverityError}
+
+	got := Check(policy, schema, storage.SchemaTypeAvro, nil)
+	want := []Violation{
+		{Rule: RuleFieldDoc, Severity: SeverityWarn, Path: "updated_at"},
+		{Rule: RuleFieldSnakeCase, Severity: SeverityError, Path: "createdAt"},
+		{Rule: RuleTimestampLogicalType, Severity: SeverityError, Path: "shipped_at"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d violations, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Rule != want[i].Rule || got[i].Severity != want[i].Severity || got[i].Path != want[i].Path {
+			t.Errorf("violation %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(Errors(got)) != 2 || len(Warnings(got)) != 1 {
+		t.Errorf("Errors/Warnings split = %d/%d, want 2/1", len(Errors(got)), len(Warnings(got)))
+	}
+}
+
+func TestCheck_AvroSkipsReferencedTypes(t *testing.T) {
+	refs := []storage.Reference{{Name: "com.example.Address", Schema: `{"type":"record","name":"Address","namespace":"com.example","fields":[{"name":"streetName","type":"string"}]}`}}
+	schema := `{"type":"record","name":"User","namespace":"com.example","fields":[{"name":"home","type":"com.example.Address","doc":"Home address"}]}`
+
+	if got := Check(Policy{RuleFieldSnakeCase: SeverityError, RuleFieldDoc: SeverityError}, schema, storage.SchemaTypeAvro, refs); len(got) != 0 {
+		t.Errorf("expected referenced type to be skipped, got %+v", got)
+	}
+}
+
+func TestCheck_JSONSchemaAndProtobuf(t *testing.T) {
+	policy := Policy{RuleFieldDoc: SeverityError, RuleFieldSnakeCase: SeverityError}
+
+	jsonSchema := `{"type":"object","properties":{"userName":{"type":"string","description":"Name"},"age":{"type":"integer"}}}`
+	got := Check(policy, jsonSchema, storage.SchemaTypeJSON, nil)
+	if len(got) != 2 || got[0].Path != "age" || got[0].Rule != RuleFieldDoc || got[1].Path != "userName" || got[1].Rule != RuleFieldSnakeCase {
+		t.Errorf("JSON Schema violations = %+v", got)
+	}
+
+	// Protobuf fields are not checked for documentation.
+	proto := `syntax = "proto3"; message User {
+  string userName = 1;
+  int32 age = 2;
+}`
+	got = Check(policy, proto, storage.SchemaTypeProtobuf, nil)
+	if len(got) != 1 || got[0].Path != "userName" || got[0].Rule != RuleFieldSnakeCase {
+		t.Errorf("Protobuf violations = %+v", got)
+	}
+}
+
+func TestCheck_Disabled(t *testing.T) {
+	schema := `{"type":"record","name":"R","fields":[{"name":"badName","type":"long"}]}`
+	for _, policy := range []Policy{nil, {RuleFieldSnakeCase: SeverityOff}} {
+		if got := Check(policy, schema, storage.SchemaTypeAvro, nil); got != nil {
+			t.Errorf("Check(%v) = %+v, want nil", policy, got)
+		}
+	}
+}
+
+func TestPolicy_With(t *testing.T) {
+	base := Policy{RuleFieldDoc: SeverityWarn, RuleFieldSnakeCase: SeverityError}
+	merged := base.With(Policy{RuleFieldDoc: SeverityOff, RuleTimestampLogicalType: SeverityWarn})
+
+	want := Policy{RuleFieldDoc: SeverityOff, RuleFieldSnakeCase: SeverityError, RuleTimestampLogicalType: SeverityWarn}
+	for rule, severity := range want {
+		if merged[rule] != severity {
+			t.Errorf("merged[%s] = %q, want %q", rule, merged[rule], severity)
+		}
+	}
+	if base[RuleFieldDoc] != SeverityWarn {
+		t.Error("With modified the receiver")
+	}
+}
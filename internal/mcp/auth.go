@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+)
+
+// scopePermissions maps each permission scope to the RBAC permission it
+// mirrors.
+var scopePermissions = map[string]auth.Permission{
+	ScopeSchemaRead:      auth.PermissionSchemaRead,
+	ScopeSchemaWrite:     auth.PermissionSchemaWrite,
+	ScopeSchemaDelete:    auth.PermissionSchemaDelete,
+	ScopeConfigRead:      auth.PermissionConfigRead,
+	ScopeConfigWrite:     auth.PermissionConfigWrite,
+	ScopeModeRead:        auth.PermissionModeRead,
+	ScopeModeWrite:       auth.PermissionModeWrite,
+	ScopeImport:          auth.PermissionImport,
+	ScopeEncryptionRead:  auth.PermissionEncryptionRead,
+	ScopeEncryptionWrite: auth.PermissionEncryptionWrite,
+	ScopeExporterRead:    auth.PermissionExporterRead,
+	ScopeExporterWrite:   auth.PermissionExporterWrite,
+	ScopeAdminRead:       auth.PermissionAdminRead,
+	ScopeAdminWrite:      auth.PermissionAdminWrite,
+}
+
+var errUnauthorized = errors.New("unauthorized: valid registry credentials are required")
+
+// authorizeCall authenticates the caller of a tool with the same methods as
+// the REST API and checks that its role grants the permission mirrored by
+// the tool's scope, for the context and subject in the call's arguments.
+// Credentials are read from the headers of the HTTP request that carried the
+// call. It returns ctx carrying the user. Without WithAuth every call is
+// allowed.
+func (s *Server) authorizeCall(ctx context.Context, req *gomcp.CallToolRequest, toolName string) (context.Context, error) {
+	if s.authenticator == nil {
+		return ctx, nil
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/mcp", nil)
+	if err != nil {
+		return ctx, err
+	}
+	if req.Extra != nil && req.Extra.Header != nil {
+		r.Header = req.Extra.Header.Clone()
+	}
+	user, _ := s.authenticator.Authenticate(r)
+	if user == nil {
+		return ctx, errUnauthorized
+	}
+	ctx = auth.WithUser(ctx, user)
+
+	// Share tokens are confined to the REST endpoints of their scope; the
+	// MCP tools search and list across subjects.
+	if user.Share != nil {
+		return ctx, errors.New("forbidden: share tokens cannot be used with the MCP server")
+	}
+	if user.PasswordChangeRequired {
+		return ctx, errors.New("forbidden: password change required, change your password with POST /me/password")
+	}
+	perm, ok := scopePermissions[toolPermissionScope[toolName]]
+	if !ok {
+		return ctx, nil
+	}
+	registryCtx := resolveContext(extractContextFromArgs(req.Params.Arguments))
+	if user.Tenant != "" {
+		if registryCtx == registrycontext.DefaultContext {
+			return ctx, errors.New("forbidden: tenant users may only access contexts owned by their tenant")
+		}
+		owner, err := s.registry.ContextTenant(ctx, registryCtx)
+		if err != nil || owner != user.Tenant {
+			return ctx, errors.New("forbidden: context is not owned by your tenant")
+		}
+	}
+	if s.authorizer != nil && s.authorizer.Enabled() {
+		scope := auth.ResourceScope{Context: registryCtx, Subject: extractSubjectFromArgs(req.Params.Arguments)}
+		if !s.authorizer.HasScopedPermission(ctx, user, perm, scope) {
+			return ctx, fmt.Errorf("forbidden: %s requires the %s permission", toolName, perm)
+		}
+	}
+	return ctx, nil
+}
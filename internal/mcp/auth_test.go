@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+// apiKeyTransport adds an API key to every request.
+type apiKeyTransport struct {
+	key string
+}
+
+func (t apiKeyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	if t.key != "" {
+		r.Header.Set("X-API-Key", t.key)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// newRegistryAuthServer starts an MCP server over HTTP that authenticates
+// clients with API keys and enforces their RBAC role.
+func newRegistryAuthServer(t *testing.T) (*httptest.Server, *registry.Registry) {
+	t.Helper()
+
+	store := memory.NewStore()
+	t.Cleanup(func() { store.Close() })
+	schemaReg := schema.NewRegistry()
+	schemaReg.Register(avro.NewParser())
+	compatChecker := compatibility.NewChecker()
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := registry.New(store, schemaReg, compatChecker, "BACKWARD")
+
+	authCfg := config.AuthConfig{
+		Enabled: true,
+		Methods: []string{"api_key"},
+		APIKey:  config.APIKeyConfig{Header: "X-API-Key"},
+		RBAC:    config.RBACConfig{Enabled: true},
+	}
+	authenticator := auth.NewAuthenticator(authCfg)
+	authenticator.AddAPIKey(&auth.APIKey{Key: "dev-key", Username: "dev", Role: string(auth.RoleDeveloper)})
+	authenticator.AddAPIKey(&auth.APIKey{Key: "reader-key", Username: "reader", Role: string(auth.RoleReadOnly)})
+
+	cfg := &config.MCPConfig{Host: "localhost", Port: 0}
+	srv := New(cfg, reg, testLogger(), "test-version", WithAuth(authenticator, auth.NewAuthorizer(authCfg.RBAC)))
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts, reg
+}
+
+func connectWithKey(t *testing.T, url, key string) (*gomcp.ClientSession, error) {
+	t.Helper()
+	client := gomcp.NewClient(&gomcp.Implementation{Name: "test-client", Version: "1.0"}, nil)
+	cs, err := client.Connect(context.Background(), &gomcp.StreamableClientTransport{
+		Endpoint:   url,
+		HTTPClient: &http.Client{Transport: apiKeyTransport{key: key}},
+		MaxRetries: -1,
+	}, nil)
+	if err == nil {
+		t.Cleanup(func() { cs.Close() })
+	}
+	return cs, err
+}
+
+func TestRegistryAuth_EnforcesRole(t *testing.T) {
+	ts, reg := newRegistryAuthServer(t)
+	ctx := context.Background()
+
+	if _, err := connectWithKey(t, ts.URL, ""); err == nil {
+		t.Fatal("expected connecting without credentials to fail")
+	}
+
+	register := &gomcp.CallToolParams{
+		Name:      "register_schema",
+		Arguments: map[string]any{"subject": "orders-value", "schema": `"string"`},
+	}
+
+	reader, err := connectWithKey(t, ts.URL, "reader-key")
+	if err != nil {
+		t.Fatalf("connect as reader: %v", err)
+	}
+	result, err := reader.CallTool(ctx, register)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError || !strings.Contains(resultText(t, result), "schema:write") {
+		t.Fatalf("expected a permission error for the reader, got: %s", resultText(t, result))
+	}
+	if result, err := reader.CallTool(ctx, &gomcp.CallToolParams{Name: "list_subjects"}); err != nil || result.IsError {
+		t.Fatalf("list_subjects as reader: %v, %v", err, result)
+	}
+
+	dev, err := connectWithKey(t, ts.URL, "dev-key")
+	if err != nil {
+		t.Fatalf("connect as developer: %v", err)
+	}
+	result, err = dev.CallTool(ctx, register)
+	if err != nil || result.IsError {
+		t.Fatalf("register_schema as developer: %v, %v", err, result)
+	}
+	if _, err := reg.GetSchemaBySubjectVersion(ctx, ".", "orders-value", 1); err != nil {
+		t.Errorf("expected the schema to be registered: %v", err)
+	}
+
+	// Developers may not delete.
+	result, err = dev.CallTool(ctx, &gomcp.CallToolParams{
+		Name:      "delete_subject",
+		Arguments: map[string]any{"subject": "orders-value"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("expected delete_subject to be refused, got: %s", resultText(t, result))
+	}
+}
+
+func TestMCPActor_UsesCaller(t *testing.T) {
+	srv := &Server{config: &config.MCPConfig{AuthToken: "token"}}
+	ctx := auth.WithUser(context.Background(), &auth.User{Username: "alice", Role: "developer", Method: "api_key"})
+	if id, actorType, method := srv.mcpActor(ctx); id != "alice" || actorType != "mcp_client" || method != "api_key" {
+		t.Errorf("mcpActor = %s, %s, %s", id, actorType, method)
+	}
+	if id, _, method := srv.mcpActor(context.Background()); id != "mcp-authenticated" || method != "bearer_token" {
+		t.Errorf("mcpActor without a user = %s, %s", id, method)
+	}
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
@@ -184,7 +185,7 @@ func stringArg(args map[string]any, key string) string {
 // confirmationCheck evaluates whether a tool call requires two-phase
 // confirmation and returns an appropriate result. Returns nil if the call
 // should proceed normally.
-func (s *Server) confirmationCheck(toolName string, dryRun bool, confirmToken string,
+func (s *Server) confirmationCheck(ctx context.Context, toolName string, dryRun bool, confirmToken string,
 	args map[string]any, preview map[string]any) *gomcp.CallToolResult {
 
 	if s.confirmStore == nil {
@@ -205,7 +206,7 @@ func (s *Server) confirmationCheck(toolName string, dryRun bool, confirmToken st
 			s.metrics.RecordMCPConfirmation("token_issued")
 		}
 		if s.auditLogger != nil {
-			actorID, actorType, authMethod := s.mcpActor()
+			actorID, actorType, authMethod := s.mcpActor(ctx)
 			s.auditLogger.LogMCPConfirmationEvent(auth.AuditEventMCPConfirmIssued, actorID, actorType, authMethod, toolName, registrycontext.DefaultContext, nil)
 		}
 		data, err := json.Marshal(map[string]any{
@@ -234,7 +235,7 @@ func (s *Server) confirmationCheck(toolName string, dryRun bool, confirmToken st
 				s.metrics.RecordMCPConfirmation("token_rejected")
 			}
 			if s.auditLogger != nil {
-				actorID, actorType, authMethod := s.mcpActor()
+				actorID, actorType, authMethod := s.mcpActor(ctx)
 				s.auditLogger.LogMCPConfirmationEvent(auth.AuditEventMCPConfirmRejected, actorID, actorType, authMethod, toolName, registrycontext.DefaultContext, nil)
 			}
 			return &gomcp.CallToolResult{
@@ -248,7 +249,7 @@ func (s *Server) confirmationCheck(toolName string, dryRun bool, confirmToken st
 			s.metrics.RecordMCPConfirmation("confirmed")
 		}
 		if s.auditLogger != nil {
-			actorID, actorType, authMethod := s.mcpActor()
+			actorID, actorType, authMethod := s.mcpActor(ctx)
 			s.auditLogger.LogMCPConfirmationEvent(auth.AuditEventMCPConfirmed, actorID, actorType, authMethod, toolName, registrycontext.DefaultContext, nil)
 		}
 		return nil // proceed with the operation
@@ -8,9 +8,18 @@ import (
 )
 
 // authMiddleware enforces bearer token authentication for MCP HTTP requests.
-// If no auth token is configured, all requests are allowed.
+// With WithAuth, requests must carry registry credentials instead. If
+// neither is configured, all requests are allowed.
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.authenticator != nil {
+			if user, _ := s.authenticator.Authenticate(r); user == nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
 		if s.config.AuthToken == "" {
 			next.ServeHTTP(w, r)
 			return
@@ -115,6 +115,7 @@ var toolPermissionScope = map[string]string{
 	"plan_migration_path":           ScopeSchemaRead,
 	"check_compatibility_multi":     ScopeSchemaRead,
 	"diff_schemas":                  ScopeSchemaRead,
+	"diff_versions":                 ScopeSchemaRead,
 	"compare_subjects":              ScopeSchemaRead,
 	"suggest_compatible_change":     ScopeSchemaRead,
 	"explain_compatibility_failure": ScopeSchemaRead,
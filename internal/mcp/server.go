@@ -24,6 +24,8 @@ type Server struct {
 	httpServer     *http.Server
 	registry       *registry.Registry
 	authService    *auth.Service
+	authenticator  *auth.Authenticator
+	authorizer     *auth.Authorizer
 	config         *config.MCPConfig
 	logger         *slog.Logger
 	metrics        *metrics.Metrics
@@ -46,6 +48,16 @@ func WithAuthService(svc *auth.Service) Option {
 	}
 }
 
+// WithAuth authenticates MCP clients with the registry's credentials instead
+// of the static auth token, and authorizes each tool call with the caller's
+// RBAC role, as the REST API does.
+func WithAuth(authenticator *auth.Authenticator, authorizer *auth.Authorizer) Option {
+	return func(s *Server) {
+		s.authenticator = authenticator
+		s.authorizer = authorizer
+	}
+}
+
 // WithBuildInfo sets commit hash and build time for the server version tool.
 func WithBuildInfo(commit, buildTime string) Option {
 	return func(s *Server) {
@@ -122,17 +134,22 @@ func (s *Server) MCPServer() *gomcp.Server {
 	return s.mcpServer
 }
 
-// Start starts the MCP HTTP server. Blocks until the server stops.
-func (s *Server) Start() error {
-	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
-
+// Handler returns the HTTP handler serving the MCP streamable transport,
+// with origin and authentication checks.
+func (s *Server) Handler() http.Handler {
 	handler := gomcp.NewStreamableHTTPHandler(
 		func(_ *http.Request) *gomcp.Server { return s.mcpServer },
 		nil,
 	)
+	return s.originMiddleware(s.authMiddleware(handler))
+}
+
+// Start starts the MCP HTTP server. Blocks until the server stops.
+func (s *Server) Start() error {
+	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
 
 	mux := http.NewServeMux()
-	mux.Handle("/mcp", s.originMiddleware(s.authMiddleware(handler)))
+	mux.Handle("/mcp", s.Handler())
 
 	readHeaderTimeout := time.Duration(s.config.ReadHeaderTimeout) * time.Second
 	if readHeaderTimeout <= 0 {
@@ -164,8 +181,12 @@ func (s *Server) mcpPrincipal() string {
 }
 
 // mcpActor returns the actor identity fields for audit logging.
-// Returns (actorID, actorType, authMethod).
-func (s *Server) mcpActor() (string, string, string) {
+// Returns (actorID, actorType, authMethod). With WithAuth, the actor is
+// the registry user that made the call.
+func (s *Server) mcpActor(ctx context.Context) (string, string, string) {
+	if user := auth.GetUser(ctx); user != nil {
+		return user.Username, "mcp_client", user.Method
+	}
 	if s.config.AuthToken != "" {
 		return "mcp-authenticated", "mcp_client", "bearer_token"
 	}
@@ -209,6 +230,7 @@ Domain knowledge is available as glossary resources. Read these BEFORE answering
 Critical rules:
 - Always use validate_schema before register_schema to catch syntax errors early.
 - Always use check_compatibility before register_schema to avoid compatibility rejection.
+- register_schema with dry_run=true runs every registration check without writing anything.
 - When debugging errors, read schema://glossary/error-reference for diagnostic guidance.
 - Schema IDs are embedded in Kafka messages. NEVER suggest changing IDs in production.
 - BACKWARD is the default compatibility. Do not change it without explaining consequences.
@@ -587,6 +587,73 @@ func TestRegisterSchemaJSON(t *testing.T) {
 	}
 }
 
+func TestRegisterSchemaDryRun(t *testing.T) {
+	cs, reg := newTestMCPClient(t)
+	registerTestSchema(t, reg, "dry-run", `{"type":"record","name":"R","fields":[{"name":"a","type":"string"}]}`)
+
+	dryRun := func(schema string) registerDryRunResult {
+		t.Helper()
+		result, err := cs.CallTool(context.Background(), &gomcp.CallToolParams{
+			Name:      "register_schema",
+			Arguments: map[string]any{"subject": "dry-run", "schema": schema, "dry_run": true},
+		})
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("expected a structured result, got error: %s", resultText(t, result))
+		}
+		var out registerDryRunResult
+		if err := json.Unmarshal([]byte(resultText(t, result)), &out); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return out
+	}
+
+	out := dryRun(`{"type":"record","name":"R","fields":[{"name":"a","type":"string"},{"name":"b","type":"int","default":0}]}`)
+	if !out.DryRun || !out.WouldSucceed || !out.NewVersion || out.Version != 2 {
+		t.Errorf("unexpected dry run result: %+v", out)
+	}
+	if versions, _ := reg.GetVersions(context.Background(), ".", "dry-run", false); len(versions) != 1 {
+		t.Errorf("dry run registered a version: %v", versions)
+	}
+
+	out = dryRun(`{"type":"record","name":"R","fields":[{"name":"a","type":"int"}]}`)
+	if out.WouldSucceed || !strings.Contains(out.Error, "incompatible") {
+		t.Errorf("expected an incompatible dry run, got: %+v", out)
+	}
+}
+
+func TestDiffVersions(t *testing.T) {
+	cs, reg := newTestMCPClient(t)
+	registerTestSchema(t, reg, "diff-v", `{"type":"record","name":"R","fields":[{"name":"a","type":"string"}]}`)
+	registerTestSchema(t, reg, "diff-v", `{"type":"record","name":"R","fields":[{"name":"a","type":"string"},{"name":"b","type":"int","default":0}]}`)
+
+	result, err := cs.CallTool(context.Background(), &gomcp.CallToolParams{
+		Name:      "diff_versions",
+		Arguments: map[string]any{"subject": "diff-v", "version1": "1", "dry_run": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", resultText(t, result))
+	}
+	var out struct {
+		Version1 int `json:"version1"`
+		Version2 int `json:"version2"`
+		Summary  struct {
+			Added int `json:"added"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.Version1 != 1 || out.Version2 != 2 || out.Summary.Added != 1 {
+		t.Errorf("unexpected diff: %+v", out)
+	}
+}
+
 func TestDeleteSubject(t *testing.T) {
 	cs, reg := newTestMCPClient(t)
 	registerTestSchema(t, reg, "del-subj", `{"type":"string"}`)
@@ -634,6 +701,35 @@ func TestDeleteSubjectPermanent(t *testing.T) {
 	}
 }
 
+func TestDeleteSubjectPermanent_ConfirmationRequired(t *testing.T) {
+	cs, reg := newTestMCPClient(t)
+	reg.SetDeleteConfirmation(0)
+	registerTestSchema(t, reg, "del-confirm", `{"type":"string"}`)
+
+	if _, err := cs.CallTool(context.Background(), &gomcp.CallToolParams{
+		Name:      "delete_subject",
+		Arguments: map[string]any{"subject": "del-confirm"},
+	}); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+
+	for tool, args := range map[string]map[string]any{
+		"delete_subject": {"subject": "del-confirm", "permanent": true},
+		"delete_version": {"subject": "del-confirm", "version": 1, "permanent": true},
+	} {
+		result, err := cs.CallTool(context.Background(), &gomcp.CallToolParams{Name: tool, Arguments: args})
+		if err != nil {
+			t.Fatalf("%s: %v", tool, err)
+		}
+		if !result.IsError {
+			t.Errorf("expected %s to refuse a permanent delete, got: %s", tool, resultText(t, result))
+		}
+	}
+	if _, err := reg.GetSchemasBySubject(context.Background(), ".", "del-confirm", true); err != nil {
+		t.Errorf("expected the subject to survive: %v", err)
+	}
+}
+
 func TestDeleteVersion(t *testing.T) {
 	cs, reg := newTestMCPClient(t)
 	registerTestSchema(t, reg, "del-ver", `{"type":"string"}`)
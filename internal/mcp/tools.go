@@ -242,8 +242,8 @@ func addToolIfAllowed[T any](s *Server, tool *gomcp.Tool, handler gomcp.ToolHand
 	gomcp.AddTool(s.mcpServer, tool, handler)
 }
 
-// instrumentedHandler wraps an MCP tool handler with metrics, audit logging,
-// and structured logging.
+// instrumentedHandler wraps an MCP tool handler with authorization, metrics,
+// audit logging, and structured logging.
 func instrumentedHandler[T any](s *Server, name string, handler gomcp.ToolHandlerFor[T, any]) gomcp.ToolHandlerFor[T, any] {
 	return func(ctx context.Context, req *gomcp.CallToolRequest, input T) (*gomcp.CallToolResult, any, error) {
 		start := time.Now()
@@ -253,7 +253,18 @@ func instrumentedHandler[T any](s *Server, name string, handler gomcp.ToolHandle
 			defer s.metrics.MCPToolCallsActive.Dec()
 		}
 
-		result, output, err := handler(ctx, req, input)
+		var result *gomcp.CallToolResult
+		var output any
+		var err error
+		ctx, authErr := s.authorizeCall(ctx, req, name)
+		if authErr != nil {
+			result = errorResult(authErr)
+			if s.metrics != nil {
+				s.metrics.RecordMCPPermissionDenied(name, toolPermissionScope[name])
+			}
+		} else {
+			result, output, err = handler(ctx, req, input)
+		}
 
 		duration := time.Since(start)
 		status := "success"
@@ -294,6 +305,8 @@ func instrumentedHandler[T any](s *Server, name string, handler gomcp.ToolHandle
 			var auditErr error
 			if err != nil {
 				auditErr = err
+			} else if authErr != nil {
+				auditErr = authErr
 			} else if result != nil && result.IsError {
 				auditErr = fmt.Errorf("tool returned error")
 			}
@@ -314,7 +327,7 @@ func instrumentedHandler[T any](s *Server, name string, handler gomcp.ToolHandle
 				}
 				auditMeta = map[string]string{"schema": truncated}
 			}
-			actorID, actorType, authMethod := s.mcpActor()
+			actorID, actorType, authMethod := s.mcpActor(ctx)
 			s.auditLogger.LogMCPEvent(eventType, actorID, actorType, authMethod, name, status, duration, auditErr, subject, auditCtx, auditMeta)
 		}
 
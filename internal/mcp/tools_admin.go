@@ -373,7 +373,7 @@ func (s *Server) handleRotateAPIKey(ctx context.Context, _ *gomcp.CallToolReques
 		return errorResult(fmt.Errorf("expires_in is required and must be positive (duration in seconds)")), nil, nil
 	}
 	expiresAt := time.Now().UTC().Add(time.Duration(input.ExpiresIn) * time.Second)
-	result, err := s.authService.RotateAPIKey(ctx, input.ID, expiresAt)
+	result, err := s.authService.RotateAPIKey(ctx, input.ID, expiresAt, nil)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
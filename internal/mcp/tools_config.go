@@ -78,7 +78,7 @@ type deleteConfigInput struct {
 }
 
 func (s *Server) handleDeleteConfig(ctx context.Context, _ *gomcp.CallToolRequest, input deleteConfigInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("delete_config", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "delete_config", input.DryRun, input.ConfirmToken,
 		map[string]any{"subject": input.Subject},
 		map[string]any{"action": "delete_config", "subject": input.Subject},
 	); result != nil {
@@ -114,7 +114,7 @@ type setModeInput struct {
 }
 
 func (s *Server) handleSetMode(ctx context.Context, _ *gomcp.CallToolRequest, input setModeInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("set_mode", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "set_mode", input.DryRun, input.ConfirmToken,
 		map[string]any{"subject": input.Subject, "mode": input.Mode},
 		map[string]any{"action": "set_mode", "subject": input.Subject, "mode": input.Mode},
 	); result != nil {
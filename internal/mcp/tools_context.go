@@ -55,7 +55,7 @@ type importSchemasInput struct {
 }
 
 func (s *Server) handleImportSchemas(ctx context.Context, _ *gomcp.CallToolRequest, input importSchemasInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("import_schemas", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "import_schemas", input.DryRun, input.ConfirmToken,
 		map[string]any{"schema_count": len(input.Schemas), "schemas_hash": hashImportSchemas(input.Schemas)},
 		map[string]any{"action": "import_schemas", "schema_count": len(input.Schemas)},
 	); result != nil {
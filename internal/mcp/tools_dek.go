@@ -149,7 +149,7 @@ type deleteKEKInput struct {
 }
 
 func (s *Server) handleDeleteKEK(ctx context.Context, _ *gomcp.CallToolRequest, input deleteKEKInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("delete_kek", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "delete_kek", input.DryRun, input.ConfirmToken,
 		map[string]any{"name": input.Name, "permanent": input.Permanent},
 		map[string]any{"action": "delete_kek", "name": input.Name, "permanent": input.Permanent},
 	); result != nil {
@@ -276,7 +276,7 @@ type deleteDEKInput struct {
 }
 
 func (s *Server) handleDeleteDEK(ctx context.Context, _ *gomcp.CallToolRequest, input deleteDEKInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("delete_dek", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "delete_dek", input.DryRun, input.ConfirmToken,
 		map[string]any{"kek_name": input.KEKName, "subject": input.Subject, "version": input.Version, "algorithm": input.Algorithm, "permanent": input.Permanent},
 		map[string]any{"action": "delete_dek", "kek_name": input.KEKName, "subject": input.Subject, "permanent": input.Permanent},
 	); result != nil {
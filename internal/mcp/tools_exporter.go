@@ -151,7 +151,7 @@ type deleteExporterInput struct {
 }
 
 func (s *Server) handleDeleteExporter(ctx context.Context, _ *gomcp.CallToolRequest, input deleteExporterInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("delete_exporter", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "delete_exporter", input.DryRun, input.ConfirmToken,
 		map[string]any{"name": input.Name},
 		map[string]any{"action": "delete_exporter", "name": input.Name},
 	); result != nil {
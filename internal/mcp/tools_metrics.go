@@ -73,11 +73,13 @@ func (s *Server) handleGetMetricsSummary(_ context.Context, _ *gomcp.CallToolReq
 	writeMatchingMetrics(&sb, lines, "schema_registry_schemas_total")
 	writeMetricValue(&sb, lines, "schema_registry_subjects_total", "Total subjects")
 	writeMatchingMetrics(&sb, lines, "schema_registry_registrations_total")
+	writeMatchingMetrics(&sb, lines, "schema_registry_imports_total")
 	sb.WriteString("\n")
 
 	sb.WriteString("## Compatibility Metrics\n")
 	writeMatchingMetrics(&sb, lines, "schema_registry_compatibility_checks_total")
 	writeMatchingMetrics(&sb, lines, "schema_registry_compatibility_errors_total")
+	writeMatchingMetrics(&sb, lines, "schema_registry_compatibility_rejections_total")
 	sb.WriteString("\n")
 
 	sb.WriteString("## Storage Metrics\n")
@@ -161,7 +161,9 @@ type getSchemaVersionInput struct {
 }
 
 func (s *Server) handleGetSchemaVersion(ctx context.Context, _ *gomcp.CallToolRequest, input getSchemaVersionInput) (*gomcp.CallToolResult, any, error) {
-	record, err := s.registry.GetSchemaBySubjectVersion(ctx, resolveContext(input.Context), input.Subject, input.Version)
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	record, err := s.registry.GetSchemaBySubjectVersion(ctx, registryCtx, subject, input.Version)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
@@ -175,7 +177,9 @@ type getRawSchemaVersionInput struct {
 }
 
 func (s *Server) handleGetRawSchemaVersion(ctx context.Context, _ *gomcp.CallToolRequest, input getRawSchemaVersionInput) (*gomcp.CallToolResult, any, error) {
-	raw, err := s.registry.GetRawSchemaBySubjectVersion(ctx, resolveContext(input.Context), input.Subject, input.Version)
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	raw, err := s.registry.GetRawSchemaBySubjectVersion(ctx, registryCtx, subject, input.Version)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
@@ -188,7 +192,9 @@ type getLatestSchemaInput struct {
 }
 
 func (s *Server) handleGetLatestSchema(ctx context.Context, _ *gomcp.CallToolRequest, input getLatestSchemaInput) (*gomcp.CallToolResult, any, error) {
-	record, err := s.registry.GetLatestSchema(ctx, resolveContext(input.Context), input.Subject)
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	record, err := s.registry.GetLatestSchema(ctx, registryCtx, subject)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
@@ -202,7 +208,9 @@ type listVersionsInput struct {
 }
 
 func (s *Server) handleListVersions(ctx context.Context, _ *gomcp.CallToolRequest, input listVersionsInput) (*gomcp.CallToolResult, any, error) {
-	versions, err := s.registry.GetVersions(ctx, resolveContext(input.Context), input.Subject, input.Deleted)
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	versions, err := s.registry.GetVersions(ctx, registryCtx, subject, input.Deleted)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
@@ -250,7 +258,9 @@ type getReferencedByInput struct {
 }
 
 func (s *Server) handleGetReferencedBy(ctx context.Context, _ *gomcp.CallToolRequest, input getReferencedByInput) (*gomcp.CallToolResult, any, error) {
-	refs, err := s.registry.GetReferencedBy(ctx, resolveContext(input.Context), input.Subject, input.Version)
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	refs, err := s.registry.GetReferencedBy(ctx, registryCtx, subject, input.Version)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
@@ -270,7 +280,9 @@ type lookupSchemaInput struct {
 
 func (s *Server) handleLookupSchema(ctx context.Context, _ *gomcp.CallToolRequest, input lookupSchemaInput) (*gomcp.CallToolResult, any, error) {
 	schemaType := storage.SchemaType(input.SchemaType)
-	record, err := s.registry.LookupSchema(ctx, resolveContext(input.Context), input.Subject, input.Schema, schemaType, nil, input.Deleted)
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	record, err := s.registry.LookupSchema(ctx, registryCtx, subject, input.Schema, schemaType, nil, input.Deleted)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
@@ -288,6 +300,7 @@ type listSchemasInput struct {
 	SubjectPrefix string `json:"subject_prefix,omitempty"`
 	Deleted       bool   `json:"deleted,omitempty"`
 	LatestOnly    bool   `json:"latest_only,omitempty"`
+	Aliases       bool   `json:"aliases,omitempty"`
 	Offset        int    `json:"offset,omitempty"`
 	Limit         int    `json:"limit,omitempty"`
 	Context       string `json:"context,omitempty"`
@@ -309,6 +322,7 @@ func (s *Server) handleListSchemas(ctx context.Context, _ *gomcp.CallToolRequest
 		SubjectPrefix: input.SubjectPrefix,
 		Deleted:       input.Deleted,
 		LatestOnly:    input.LatestOnly,
+		Aliases:       input.Aliases,
 		Offset:        offset,
 		Limit:         limit,
 	}
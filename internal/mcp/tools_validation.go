@@ -20,7 +20,7 @@ func (s *Server) registerValidationTools() {
 
 	addToolIfAllowed(s, &gomcp.Tool{
 		Name:        "normalize_schema",
-		Description: "Parse and normalize a schema, returning the canonical form and fingerprint for deduplication.",
+		Description: "Parse and normalize a schema without registering it, returning the canonical form and fingerprint that registering with normalize=true would store, and whether normalization changes the fingerprint.",
 		Annotations: &gomcp.ToolAnnotations{ReadOnlyHint: true},
 	}, instrumentedHandler(s, "normalize_schema", s.handleNormalizeSchema))
 
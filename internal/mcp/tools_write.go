@@ -2,9 +2,13 @@ package mcp
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/axonops/axonops-schema-registry/internal/analysis"
+	"github.com/axonops/axonops-schema-registry/internal/auth"
 	"github.com/axonops/axonops-schema-registry/internal/registry"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
@@ -12,17 +16,17 @@ import (
 func (s *Server) registerSchemaWriteTools() {
 	addToolIfAllowed(s, &gomcp.Tool{
 		Name:        "register_schema",
-		Description: "Register a new schema version for a subject. If the same schema already exists, returns the existing record.",
+		Description: "Register a new schema version for a subject. If the same schema already exists, returns the existing record. For developers in contexts that require approval, the schema is held as a pending schema until an admin approves it. With dry_run=true, runs every registration check without storing anything and returns the version and ID the schema would get.",
 	}, instrumentedHandler(s, "register_schema", s.handleRegisterSchema))
 
 	addToolIfAllowed(s, &gomcp.Tool{
 		Name:        "delete_subject",
-		Description: "Delete a subject and all its schema versions. Soft-deletes by default; use permanent=true for hard delete.",
+		Description: "Delete a subject and all its schema versions. Soft-deletes by default; use permanent=true for hard delete. Hard deletes are refused when the server requires them to be confirmed over the REST API.",
 	}, instrumentedHandler(s, "delete_subject", s.handleDeleteSubject))
 
 	addToolIfAllowed(s, &gomcp.Tool{
 		Name:        "delete_version",
-		Description: "Delete a specific schema version. Soft-deletes by default; use permanent=true for hard delete (requires prior soft-delete).",
+		Description: "Delete a specific schema version. Soft-deletes by default; use permanent=true for hard delete (requires prior soft-delete). Hard deletes are refused when the server requires them to be confirmed over the REST API.",
 	}, instrumentedHandler(s, "delete_version", s.handleDeleteVersion))
 
 	addToolIfAllowed(s, &gomcp.Tool{
@@ -30,6 +34,12 @@ func (s *Server) registerSchemaWriteTools() {
 		Description: "Check if a schema is compatible with existing versions of a subject according to the configured compatibility level",
 		Annotations: &gomcp.ToolAnnotations{ReadOnlyHint: true},
 	}, instrumentedHandler(s, "check_compatibility", s.handleCheckCompatibility))
+
+	addToolIfAllowed(s, &gomcp.Tool{
+		Name:        "diff_versions",
+		Description: "Structural diff of two versions of a subject: each added, removed, or changed field with its old and new type, doc, and requiredness, plus a summary. Versions are numbers or \"latest\".",
+		Annotations: &gomcp.ToolAnnotations{ReadOnlyHint: true},
+	}, instrumentedHandler(s, "diff_versions", s.handleDiffVersions))
 }
 
 // --- Handler input types and implementations ---
@@ -42,9 +52,24 @@ type registerSchemaInput struct {
 	Normalize  bool                `json:"normalize,omitempty"`
 	Metadata   *storage.Metadata   `json:"metadata,omitempty"`
 	RuleSet    *storage.RuleSet    `json:"rule_set,omitempty"`
+	DryRun     bool                `json:"dry_run,omitempty"`
 	Context    string              `json:"context,omitempty"`
 }
 
+// registerDryRunResult is the outcome of a register_schema dry run. Error
+// holds the reason registration would fail.
+type registerDryRunResult struct {
+	DryRun        bool               `json:"dry_run"`
+	WouldSucceed  bool               `json:"would_succeed"`
+	NewVersion    bool               `json:"new_version"`
+	Subject       string             `json:"subject"`
+	Version       int                `json:"version,omitempty"`
+	ID            int64              `json:"id,omitempty"`
+	SchemaType    storage.SchemaType `json:"schema_type,omitempty"`
+	BlockedByMode string             `json:"blocked_by_mode,omitempty"`
+	Error         string             `json:"error,omitempty"`
+}
+
 func (s *Server) handleRegisterSchema(ctx context.Context, _ *gomcp.CallToolRequest, input registerSchemaInput) (*gomcp.CallToolResult, any, error) {
 	schemaType := storage.SchemaType(input.SchemaType)
 	opts := registry.RegisterOpts{
@@ -52,13 +77,72 @@ func (s *Server) handleRegisterSchema(ctx context.Context, _ *gomcp.CallToolRequ
 		Metadata:  input.Metadata,
 		RuleSet:   input.RuleSet,
 	}
-	record, err := s.registry.RegisterSchema(ctx, resolveContext(input.Context), input.Subject, input.Schema, schemaType, input.References, opts)
+	if user := auth.GetUser(ctx); user != nil {
+		opts.Principal, opts.Role = user.Username, user.Role
+	}
+	registryCtx := resolveContext(input.Context)
+	subject, err := s.registry.ResolveWriteAlias(ctx, registryCtx, input.Subject)
+	if err != nil {
+		return errorResult(err), nil, nil
+	}
+	if input.DryRun {
+		return s.dryRunRegisterSchema(ctx, registryCtx, subject, input, schemaType, opts)
+	}
+	if user := auth.GetUser(ctx); user != nil && auth.Role(user.Role) == auth.RoleDeveloper && s.registry.RequiresApproval(registryCtx) {
+		pending, existing, err := s.registry.SubmitPendingSchema(ctx, registryCtx, subject, registry.PendingSchemaRequest{
+			Schema:     input.Schema,
+			SchemaType: schemaType,
+			References: input.References,
+			Metadata:   input.Metadata,
+			RuleSet:    input.RuleSet,
+			Normalize:  input.Normalize,
+		}, user.Username)
+		if err != nil {
+			return errorResult(err), nil, nil
+		}
+		if existing != nil {
+			return jsonResult(existing)
+		}
+		return jsonResult(pending)
+	}
+	record, err := s.registry.RegisterSchema(ctx, registryCtx, subject, input.Schema, schemaType, input.References, opts)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
 	return jsonResult(record)
 }
 
+// dryRunRegisterSchema reports what registering the schema would do. A
+// failing check is part of the result rather than a tool error.
+func (s *Server) dryRunRegisterSchema(ctx context.Context, registryCtx, subject string, input registerSchemaInput, schemaType storage.SchemaType, opts registry.RegisterOpts) (*gomcp.CallToolResult, any, error) {
+	result := registerDryRunResult{DryRun: true, Subject: subject}
+	blocking, err := s.registry.CheckModeForWrite(ctx, registryCtx, subject)
+	if err != nil {
+		return errorResult(err), nil, nil
+	}
+	if blocking != "" {
+		result.BlockedByMode = blocking
+		result.Error = fmt.Sprintf("subject %s is in %s mode", subject, blocking)
+		return jsonResult(result)
+	}
+	record, created, err := s.registry.PlanRegistration(ctx, registryCtx, subject, input.Schema, schemaType, input.References, opts)
+	if err != nil {
+		result.Error = err.Error()
+		return jsonResult(result)
+	}
+	result.WouldSucceed = true
+	result.NewVersion = created
+	result.Version = record.Version
+	result.ID = record.ID
+	result.SchemaType = record.SchemaType
+	return jsonResult(result)
+}
+
+// errPermanentDeleteNeedsConfirmation is returned for permanent deletes
+// while deletes.require_confirmation is on: they must be confirmed by a super
+// admin over the REST API.
+var errPermanentDeleteNeedsConfirmation = errors.New("permanent deletes must be confirmed by a super admin: use DELETE with permanent=true over the REST API")
+
 type deleteSubjectInput struct {
 	Subject      string `json:"subject"`
 	Permanent    bool   `json:"permanent,omitempty"`
@@ -68,13 +152,21 @@ type deleteSubjectInput struct {
 }
 
 func (s *Server) handleDeleteSubject(ctx context.Context, _ *gomcp.CallToolRequest, input deleteSubjectInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("delete_subject", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "delete_subject", input.DryRun, input.ConfirmToken,
 		map[string]any{"subject": input.Subject, "permanent": input.Permanent},
 		map[string]any{"action": "delete_subject", "subject": input.Subject, "permanent": input.Permanent},
 	); result != nil {
 		return result, nil, nil
 	}
-	versions, err := s.registry.DeleteSubject(ctx, resolveContext(input.Context), input.Subject, input.Permanent)
+	registryCtx := resolveContext(input.Context)
+	subject, err := s.registry.ResolveWriteAlias(ctx, registryCtx, input.Subject)
+	if err != nil {
+		return errorResult(err), nil, nil
+	}
+	if input.Permanent && s.registry.RequiresDeleteConfirmation() {
+		return errorResult(errPermanentDeleteNeedsConfirmation), nil, nil
+	}
+	versions, err := s.registry.DeleteSubject(ctx, registryCtx, subject, input.Permanent)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
@@ -91,25 +183,36 @@ type deleteVersionInput struct {
 }
 
 func (s *Server) handleDeleteVersion(ctx context.Context, _ *gomcp.CallToolRequest, input deleteVersionInput) (*gomcp.CallToolResult, any, error) {
-	if result := s.confirmationCheck("delete_version", input.DryRun, input.ConfirmToken,
+	if result := s.confirmationCheck(ctx, "delete_version", input.DryRun, input.ConfirmToken,
 		map[string]any{"subject": input.Subject, "version": input.Version, "permanent": input.Permanent},
 		map[string]any{"action": "delete_version", "subject": input.Subject, "version": input.Version, "permanent": input.Permanent},
 	); result != nil {
 		return result, nil, nil
 	}
-	ver, err := s.registry.DeleteVersion(ctx, resolveContext(input.Context), input.Subject, input.Version, input.Permanent)
+	registryCtx := resolveContext(input.Context)
+	subject, err := s.registry.ResolveWriteAlias(ctx, registryCtx, input.Subject)
+	if err != nil {
+		return errorResult(err), nil, nil
+	}
+	if input.Permanent && s.registry.RequiresDeleteConfirmation() {
+		return errorResult(errPermanentDeleteNeedsConfirmation), nil, nil
+	}
+	ver, err := s.registry.DeleteVersion(ctx, registryCtx, subject, input.Version, input.Permanent)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
 	return jsonResult(map[string]int{"version": ver})
 }
 
+// checkCompatibilityInput accepts dry_run like the other schema tools;
+// checking never writes, so it has no effect.
 type checkCompatibilityInput struct {
 	Subject    string              `json:"subject"`
 	Schema     string              `json:"schema"`
 	SchemaType string              `json:"schema_type,omitempty"`
 	References []storage.Reference `json:"references,omitempty"`
 	Version    string              `json:"version,omitempty"`
+	DryRun     bool                `json:"dry_run,omitempty"`
 	Context    string              `json:"context,omitempty"`
 }
 
@@ -119,9 +222,60 @@ func (s *Server) handleCheckCompatibility(ctx context.Context, _ *gomcp.CallTool
 		version = "latest"
 	}
 	schemaType := storage.SchemaType(input.SchemaType)
-	result, err := s.registry.CheckCompatibility(ctx, resolveContext(input.Context), input.Subject, input.Schema, schemaType, input.References, version)
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	result, err := s.registry.CheckCompatibility(ctx, registryCtx, subject, input.Schema, schemaType, input.References, version)
 	if err != nil {
 		return errorResult(err), nil, nil
 	}
 	return jsonResult(result)
 }
+
+// diffVersionsInput accepts dry_run like the other schema tools; diffing
+// never writes, so it has no effect.
+type diffVersionsInput struct {
+	Subject  string `json:"subject"`
+	Version1 string `json:"version1"`
+	Version2 string `json:"version2,omitempty"`
+	DryRun   bool   `json:"dry_run,omitempty"`
+	Context  string `json:"context,omitempty"`
+}
+
+func (s *Server) handleDiffVersions(ctx context.Context, _ *gomcp.CallToolRequest, input diffVersionsInput) (*gomcp.CallToolResult, any, error) {
+	registryCtx := resolveContext(input.Context)
+	subject := s.registry.ResolveAlias(ctx, registryCtx, input.Subject)
+	version2 := input.Version2
+	if version2 == "" {
+		version2 = "latest"
+	}
+	var records [2]*storage.SchemaRecord
+	var schemaTypes [2]storage.SchemaType
+	for i, versionStr := range []string{input.Version1, version2} {
+		version, err := registry.ParseVersion(versionStr)
+		if err != nil {
+			return errorResult(fmt.Errorf("invalid version %q: %w", versionStr, err)), nil, nil
+		}
+		record, err := s.registry.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+		if err != nil {
+			return errorResult(fmt.Errorf("version %s: %w", versionStr, err)), nil, nil
+		}
+		records[i] = record
+		schemaTypes[i] = record.SchemaType
+		if schemaTypes[i] == "" {
+			schemaTypes[i] = storage.SchemaTypeAvro
+		}
+	}
+	diff := analysis.DiffFields(
+		analysis.ExtractFields(records[0].Schema, schemaTypes[0]),
+		analysis.ExtractFields(records[1].Schema, schemaTypes[1]),
+	)
+	return jsonResult(map[string]any{
+		"subject":      subject,
+		"version1":     records[0].Version,
+		"version2":     records[1].Version,
+		"schema_type1": schemaTypes[0],
+		"schema_type2": schemaTypes[1],
+		"changes":      diff.Changes,
+		"summary":      diff.Summary,
+	})
+}
@@ -23,14 +23,17 @@ type Metrics struct {
 	SubjectsTotal      prometheus.Gauge
 	SchemaVersions     *prometheus.GaugeVec
 	RegistrationsTotal *prometheus.CounterVec
+	ImportsTotal       *prometheus.CounterVec
 
 	// Compatibility metrics
-	CompatibilityChecks *prometheus.CounterVec
-	CompatibilityErrors *prometheus.CounterVec
+	CompatibilityChecks     *prometheus.CounterVec
+	CompatibilityErrors     *prometheus.CounterVec
+	CompatibilityRejections *prometheus.CounterVec // labels: type, reason
 
 	// Storage metrics
 	StorageOperations *prometheus.CounterVec
 	StorageLatency    *prometheus.HistogramVec
+	StorageDuration   *prometheus.HistogramVec
 	StorageErrors     *prometheus.CounterVec
 
 	// Cache metrics
@@ -44,6 +47,12 @@ type Metrics struct {
 	AuthLatency       *prometheus.HistogramVec
 	AuthLDAPFallbacks *prometheus.CounterVec
 
+	// Auth cache consistency metrics
+	AuthCacheReconciliations *prometheus.CounterVec // labels: trigger, result
+	AuthCacheDivergence      *prometheus.CounterVec // labels: cache, kind
+	AuthCacheInvalidations   *prometheus.CounterVec // labels: reason
+	AuthCacheRefreshFailures *prometheus.CounterVec // labels: cache
+
 	// Rate limit metrics
 	RateLimitHits *prometheus.CounterVec
 
@@ -64,6 +73,12 @@ type Metrics struct {
 	AuditWebhookBatchSize     prometheus.Histogram
 	AuditWebhookFlushDuration prometheus.Histogram
 
+	// Exporter (schema linking) metrics
+	ExporterLag               *prometheus.GaugeVec   // labels: exporter
+	ExporterSchemasExported   *prometheus.CounterVec // labels: exporter
+	ExporterErrorsTotal       *prometheus.CounterVec // labels: exporter
+	ExporterLastSuccessSecond *prometheus.GaugeVec   // labels: exporter
+
 	// Per-principal metrics (optional, may be nil if disabled)
 	PrincipalRequestsTotal *prometheus.CounterVec // labels: principal, method, path, status
 	PrincipalMCPCallsTotal *prometheus.CounterVec // labels: principal, tool, status
@@ -147,6 +162,14 @@ func New() *Metrics {
 		[]string{"type", "status"},
 	)
 
+	m.ImportsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_imports_total",
+			Help: "Total number of schemas imported with preserved IDs",
+		},
+		[]string{"type", "status"},
+	)
+
 	// Compatibility metrics
 	m.CompatibilityChecks = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -164,6 +187,14 @@ func New() *Metrics {
 		[]string{"type", "level"},
 	)
 
+	m.CompatibilityRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_compatibility_rejections_total",
+			Help: "Total number of schema registrations rejected as incompatible, by reason",
+		},
+		[]string{"type", "reason"},
+	)
+
 	// Storage metrics
 	m.StorageOperations = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -182,6 +213,17 @@ func New() *Metrics {
 		[]string{"backend", "operation"},
 	)
 
+	// Finer-grained buckets than StorageLatency so that sub-millisecond
+	// memory lookups and multi-second Cassandra LWT retries are both resolvable.
+	m.StorageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "schema_registry_storage_operation_duration_seconds",
+			Help:    "Duration of storage backend operations in seconds",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		},
+		[]string{"backend", "operation"},
+	)
+
 	m.StorageErrors = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "schema_registry_storage_errors_total",
@@ -249,6 +291,39 @@ func New() *Metrics {
 		[]string{"username"},
 	)
 
+	// Auth cache consistency metrics
+	m.AuthCacheReconciliations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_auth_cache_reconciliations_total",
+			Help: "Total number of auth cache consistency checks against storage",
+		},
+		[]string{"trigger", "result"},
+	)
+
+	m.AuthCacheDivergence = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_auth_cache_divergence_total",
+			Help: "Total number of cached auth entries found to differ from storage",
+		},
+		[]string{"cache", "kind"},
+	)
+
+	m.AuthCacheInvalidations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_auth_cache_invalidations_total",
+			Help: "Total number of API keys dropped from the cache when revoked, updated or deleted",
+		},
+		[]string{"reason"},
+	)
+
+	m.AuthCacheRefreshFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_auth_cache_refresh_failures_total",
+			Help: "Total number of failed background auth cache refreshes",
+		},
+		[]string{"cache"},
+	)
+
 	// Rate limit metrics
 	m.RateLimitHits = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -451,6 +526,39 @@ func New() *Metrics {
 		[]string{"endpoint"},
 	)
 
+	// Exporter (schema linking) metrics
+	m.ExporterLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "schema_registry_exporter_lag",
+			Help: "Number of schema versions waiting to be replicated by an exporter",
+		},
+		[]string{"exporter"},
+	)
+
+	m.ExporterSchemasExported = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_exporter_schemas_exported_total",
+			Help: "Total number of schema versions replicated to the remote registry by an exporter",
+		},
+		[]string{"exporter"},
+	)
+
+	m.ExporterErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "schema_registry_exporter_errors_total",
+			Help: "Total number of failed exporter replication passes",
+		},
+		[]string{"exporter"},
+	)
+
+	m.ExporterLastSuccessSecond = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "schema_registry_exporter_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful exporter replication pass",
+		},
+		[]string{"exporter"},
+	)
+
 	// Register all collectors
 	m.registry.MustRegister(
 		m.RequestsTotal,
@@ -460,10 +568,13 @@ func New() *Metrics {
 		m.SubjectsTotal,
 		m.SchemaVersions,
 		m.RegistrationsTotal,
+		m.ImportsTotal,
 		m.CompatibilityChecks,
 		m.CompatibilityErrors,
+		m.CompatibilityRejections,
 		m.StorageOperations,
 		m.StorageLatency,
+		m.StorageDuration,
 		m.StorageErrors,
 		m.CacheHits,
 		m.CacheMisses,
@@ -472,6 +583,10 @@ func New() *Metrics {
 		m.AuthFailures,
 		m.AuthLatency,
 		m.AuthLDAPFallbacks,
+		m.AuthCacheReconciliations,
+		m.AuthCacheDivergence,
+		m.AuthCacheInvalidations,
+		m.AuthCacheRefreshFailures,
 		m.RateLimitHits,
 		m.MCPToolCallsTotal,
 		m.MCPToolCallDuration,
@@ -486,6 +601,10 @@ func New() *Metrics {
 		m.AuditWebhookDroppedTotal,
 		m.AuditWebhookBatchSize,
 		m.AuditWebhookFlushDuration,
+		m.ExporterLag,
+		m.ExporterSchemasExported,
+		m.ExporterErrorsTotal,
+		m.ExporterLastSuccessSecond,
 		m.ConfluentRegisteredCount,
 		m.ConfluentDeletedCount,
 		m.ConfluentAPISuccessCount,
@@ -724,6 +843,15 @@ func (m *Metrics) RecordSchemaRegistration(schemaType string, success bool) {
 	}
 }
 
+// RecordSchemaImport records the import of one schema with a preserved ID.
+func (m *Metrics) RecordSchemaImport(schemaType string, success bool) {
+	status := "success"
+	if !success {
+		status = "failure"
+	}
+	m.ImportsTotal.WithLabelValues(schemaType, status).Inc()
+}
+
 // RecordSchemaDeletion records a schema or subject deletion.
 func (m *Metrics) RecordSchemaDeletion(schemaType string) {
 	m.ConfluentDeletedCount.Inc()
@@ -759,10 +887,17 @@ func (m *Metrics) RecordCompatibilityError(schemaType, level string) {
 	m.CompatibilityErrors.WithLabelValues(schemaType, level).Inc()
 }
 
+// RecordCompatibilityRejection records a schema registration rejected as
+// incompatible for reason, a compatibility reason category.
+func (m *Metrics) RecordCompatibilityRejection(schemaType, reason string) {
+	m.CompatibilityRejections.WithLabelValues(schemaType, reason).Inc()
+}
+
 // RecordStorageOperation records a storage operation.
 func (m *Metrics) RecordStorageOperation(backend, operation string, duration time.Duration, err error) {
 	m.StorageOperations.WithLabelValues(backend, operation).Inc()
 	m.StorageLatency.WithLabelValues(backend, operation).Observe(duration.Seconds())
+	m.StorageDuration.WithLabelValues(backend, operation).Observe(duration.Seconds())
 	if err != nil {
 		m.StorageErrors.WithLabelValues(backend, operation).Inc()
 	}
@@ -792,6 +927,34 @@ func (m *Metrics) RecordAuthLDAPFallback(username string) {
 	m.AuthLDAPFallbacks.WithLabelValues(username).Inc()
 }
 
+// RecordAuthCacheReconciliation records a consistency check of the auth
+// caches against storage.
+func (m *Metrics) RecordAuthCacheReconciliation(trigger string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.AuthCacheReconciliations.WithLabelValues(trigger, result).Inc()
+}
+
+// RecordAuthCacheDivergence records cached auth entries found to differ from
+// storage during a consistency check.
+func (m *Metrics) RecordAuthCacheDivergence(cache, kind string, count int) {
+	m.AuthCacheDivergence.WithLabelValues(cache, kind).Add(float64(count))
+}
+
+// RecordAuthCacheInvalidation records API keys dropped from the cache when
+// they were revoked, updated or deleted.
+func (m *Metrics) RecordAuthCacheInvalidation(reason string, count int) {
+	m.AuthCacheInvalidations.WithLabelValues(reason).Add(float64(count))
+}
+
+// RecordAuthCacheRefreshFailure records a failed background refresh of an
+// auth cache.
+func (m *Metrics) RecordAuthCacheRefreshFailure(cache string) {
+	m.AuthCacheRefreshFailures.WithLabelValues(cache).Inc()
+}
+
 // RecordRateLimitHit records a rate limit hit.
 func (m *Metrics) RecordRateLimitHit(client string) {
 	m.RateLimitHits.WithLabelValues(client).Inc()
@@ -900,6 +1063,21 @@ func (m *Metrics) RecordAuditWebhookFlush(batchSize int, duration time.Duration)
 	m.AuditWebhookFlushDuration.Observe(duration.Seconds())
 }
 
+// RecordExporterSync records the outcome of one replication pass of an exporter.
+// exported is the number of schema versions replicated during the pass and
+// pending is the number still waiting to be replicated afterwards.
+func (m *Metrics) RecordExporterSync(exporter string, exported, pending int, err error) {
+	if exported > 0 {
+		m.ExporterSchemasExported.WithLabelValues(exporter).Add(float64(exported))
+	}
+	m.ExporterLag.WithLabelValues(exporter).Set(float64(pending))
+	if err != nil {
+		m.ExporterErrorsTotal.WithLabelValues(exporter).Inc()
+		return
+	}
+	m.ExporterLastSuccessSecond.WithLabelValues(exporter).Set(float64(time.Now().Unix()))
+}
+
 // GaugeSource provides the data needed to periodically refresh gauge metrics.
 // This avoids importing the registry or storage packages from the metrics package.
 type GaugeSource interface {
@@ -940,3 +1118,58 @@ func (m *Metrics) refreshGauges(source GaugeSource) {
 		}
 	}
 }
+
+// SetMaintenanceSource registers schema_registry_maintenance_active, which
+// is 1 while a scheduled maintenance window holds the registry in
+// READONLY_OVERRIDE and 0 otherwise. It is evaluated on every scrape, so it
+// follows windows as they start and end. Call it at most once.
+func (m *Metrics) SetMaintenanceSource(active func() bool) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "schema_registry_maintenance_active",
+			Help: "Whether a scheduled maintenance window is active (1) or not (0)",
+		},
+		func() float64 {
+			if active() {
+				return 1
+			}
+			return 0
+		},
+	))
+}
+
+// SetStorageDegradedSource registers schema_registry_storage_degraded, which
+// is 1 while the storage backend is unavailable and reads are served from
+// the fallback snapshot, and 0 otherwise. Call it at most once.
+func (m *Metrics) SetStorageDegradedSource(degraded func() bool) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "schema_registry_storage_degraded",
+			Help: "Whether reads are served from the fallback snapshot because the storage backend is unavailable (1) or not (0)",
+		},
+		func() float64 {
+			if degraded() {
+				return 1
+			}
+			return 0
+		},
+	))
+}
+
+// SetWriteGateSource registers schema_registry_storage_write_gate_closed,
+// which is 1 while the storage write gate refuses writes and 0 otherwise.
+// Call it at most once.
+func (m *Metrics) SetWriteGateSource(closed func() bool) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "schema_registry_storage_write_gate_closed",
+			Help: "Whether writes are refused because a storage health signal is above its threshold (1) or not (0)",
+		},
+		func() float64 {
+			if closed() {
+				return 1
+			}
+			return 0
+		},
+	))
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestNew(t *testing.T) {
@@ -82,6 +84,36 @@ func TestMetrics_RecordSchemaRegistration(t *testing.T) {
 	// Verify metrics are recorded (no panic)
 }
 
+func TestMetrics_RecordCompatibilityRejection(t *testing.T) {
+	m := New()
+
+	m.RecordCompatibilityRejection("AVRO", "field_removed")
+	m.RecordCompatibilityRejection("AVRO", "field_removed")
+	m.RecordCompatibilityRejection("PROTOBUF", "type_changed")
+
+	if v := testutil.ToFloat64(m.CompatibilityRejections.WithLabelValues("AVRO", "field_removed")); v != 2 {
+		t.Errorf("expected 2 rejections, got %v", v)
+	}
+	if n := testutil.CollectAndCount(m.CompatibilityRejections); n != 2 {
+		t.Errorf("expected 2 series, got %d", n)
+	}
+}
+
+func TestMetrics_RecordSchemaImport(t *testing.T) {
+	m := New()
+
+	m.RecordSchemaImport("AVRO", true)
+	m.RecordSchemaImport("AVRO", true)
+	m.RecordSchemaImport("JSON", false)
+
+	if v := testutil.ToFloat64(m.ImportsTotal.WithLabelValues("AVRO", "success")); v != 2 {
+		t.Errorf("expected 2 successful imports, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.ImportsTotal.WithLabelValues("JSON", "failure")); v != 1 {
+		t.Errorf("expected 1 failed import, got %v", v)
+	}
+}
+
 func TestMetrics_RecordCompatibilityCheck(t *testing.T) {
 	m := New()
 
@@ -97,7 +129,15 @@ func TestMetrics_RecordStorageOperation(t *testing.T) {
 	m.RecordStorageOperation("memory", "get", 10*time.Millisecond, nil)
 	m.RecordStorageOperation("cassandra", "put", 50*time.Millisecond, io.EOF)
 
-	// Verify metrics are recorded (no panic)
+	if n := testutil.CollectAndCount(m.StorageDuration, "schema_registry_storage_operation_duration_seconds"); n != 2 {
+		t.Errorf("expected 2 duration series, got %d", n)
+	}
+	if v := testutil.ToFloat64(m.StorageErrors.WithLabelValues("cassandra", "put")); v != 1 {
+		t.Errorf("expected 1 cassandra put error, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.StorageErrors.WithLabelValues("memory", "get")); v != 0 {
+		t.Errorf("expected no memory get errors, got %v", v)
+	}
 }
 
 func TestMetrics_RecordCacheAccess(t *testing.T) {
@@ -606,3 +646,45 @@ func extractMetric(content, name string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+func TestSetMaintenanceSource(t *testing.T) {
+	m := New()
+	active := false
+	m.SetMaintenanceSource(func() bool { return active })
+
+	scrape := func() string {
+		rr := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+		body, _ := io.ReadAll(rr.Body)
+		return string(body)
+	}
+
+	if !strings.Contains(scrape(), "schema_registry_maintenance_active 0") {
+		t.Error("Expected maintenance_active to be 0 without an active window")
+	}
+	active = true
+	if !strings.Contains(scrape(), "schema_registry_maintenance_active 1") {
+		t.Error("Expected maintenance_active to be 1 during a window")
+	}
+}
+
+func TestSetWriteGateSource(t *testing.T) {
+	m := New()
+	closed := false
+	m.SetWriteGateSource(func() bool { return closed })
+
+	scrape := func() string {
+		rr := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+		body, _ := io.ReadAll(rr.Body)
+		return string(body)
+	}
+
+	if !strings.Contains(scrape(), "schema_registry_storage_write_gate_closed 0") {
+		t.Error("Expected storage_write_gate_closed to be 0 while the gate is open")
+	}
+	closed = true
+	if !strings.Contains(scrape(), "schema_registry_storage_write_gate_closed 1") {
+		t.Error("Expected storage_write_gate_closed to be 1 while the gate is closed")
+	}
+}
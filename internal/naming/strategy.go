@@ -0,0 +1,77 @@
+// Package naming implements the Kafka subject name strategies used by
+// serializers to derive a subject from a topic and a schema's record name.
+// The registry uses it to enforce a naming policy at registration time and to
+// map topics back to their subjects.
+package naming
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strategy identifiers, matching the names accepted by POST /subjects/validate.
+const (
+	// StrategyNone disables enforcement.
+	StrategyNone = "none"
+	// StrategyTopicName requires subjects of the form "<topic>-key" or
+	// "<topic>-value" (Confluent TopicNameStrategy).
+	StrategyTopicName = "topic_name"
+	// StrategyRecordName requires the subject to equal the schema's
+	// fully-qualified record name (Confluent RecordNameStrategy).
+	StrategyRecordName = "record_name"
+	// StrategyTopicRecordName requires subjects of the form
+	// "<topic>-<record name>" (Confluent TopicRecordNameStrategy).
+	StrategyTopicRecordName = "topic_record_name"
+)
+
+// Key and value subject suffixes used by TopicNameStrategy.
+const (
+	KeySuffix   = "-key"
+	ValueSuffix = "-value"
+)
+
+// IsValid reports whether s is a known strategy identifier. The empty string
+// is treated as StrategyNone.
+func IsValid(s string) bool {
+	switch s {
+	case "", StrategyNone, StrategyTopicName, StrategyRecordName, StrategyTopicRecordName:
+		return true
+	}
+	return false
+}
+
+// Check reports whether subject conforms to strategy for a schema whose
+// record name is recordName. It returns nil when the subject matches or
+// enforcement is disabled, and a descriptive error otherwise.
+func Check(strategy, subject, recordName string) error {
+	switch strategy {
+	case "", StrategyNone:
+		return nil
+	case StrategyTopicName:
+		for _, suffix := range []string{KeySuffix, ValueSuffix} {
+			if topic, ok := strings.CutSuffix(subject, suffix); ok && topic != "" {
+				return nil
+			}
+		}
+		return fmt.Errorf("subject '%s' does not match TopicNameStrategy: expected '<topic>%s' or '<topic>%s'",
+			subject, KeySuffix, ValueSuffix)
+	case StrategyRecordName:
+		if recordName == "" {
+			return fmt.Errorf("subject '%s' does not match RecordNameStrategy: schema has no record name", subject)
+		}
+		if subject != recordName {
+			return fmt.Errorf("subject '%s' does not match RecordNameStrategy: expected '%s'", subject, recordName)
+		}
+		return nil
+	case StrategyTopicRecordName:
+		if recordName == "" {
+			return fmt.Errorf("subject '%s' does not match TopicRecordNameStrategy: schema has no record name", subject)
+		}
+		if topic, ok := strings.CutSuffix(subject, "-"+recordName); ok && topic != "" {
+			return nil
+		}
+		return fmt.Errorf("subject '%s' does not match TopicRecordNameStrategy: expected '<topic>-%s'", subject, recordName)
+	default:
+		return fmt.Errorf("unknown subject name strategy '%s'", strategy)
+	}
+}
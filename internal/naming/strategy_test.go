@@ -0,0 +1,44 @@
+package naming
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategy   string
+		subject    string
+		recordName string
+		wantErr    bool
+	}{
+		{"none", StrategyNone, "anything", "", false},
+		{"empty strategy", "", "anything", "", false},
+		{"topic value", StrategyTopicName, "orders-value", "com.example.Order", false},
+		{"topic key", StrategyTopicName, "orders-key", "", false},
+		{"topic missing suffix", StrategyTopicName, "orders", "", true},
+		{"topic empty topic", StrategyTopicName, "-value", "", true},
+		{"record match", StrategyRecordName, "com.example.Order", "com.example.Order", false},
+		{"record mismatch", StrategyRecordName, "orders-value", "com.example.Order", true},
+		{"record unnamed schema", StrategyRecordName, "orders-value", "", true},
+		{"topic record match", StrategyTopicRecordName, "orders-com.example.Order", "com.example.Order", false},
+		{"topic record mismatch", StrategyTopicRecordName, "orders-value", "com.example.Order", true},
+		{"topic record empty topic", StrategyTopicRecordName, "-com.example.Order", "com.example.Order", true},
+		{"unknown strategy", "bogus", "orders-value", "", true},
+	}
+	for _, tt := range tests {
+		err := Check(tt.strategy, tt.subject, tt.recordName)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Check(%q, %q, %q) error = %v, wantErr %v", tt.name, tt.strategy, tt.subject, tt.recordName, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	for _, s := range []string{"", StrategyNone, StrategyTopicName, StrategyRecordName, StrategyTopicRecordName} {
+		if !IsValid(s) {
+			t.Errorf("expected %q to be valid", s)
+		}
+	}
+	if IsValid("TopicNameStrategy") {
+		t.Error("expected Java class-style names to be rejected")
+	}
+}
@@ -0,0 +1,28 @@
+// Package reencryption re-seals the schemas stored in a context with the
+// current encryption-at-rest key. It runs as an async job after the key
+// encryption key is rotated, after encryption is enabled on a registry that
+// already holds schemas, or, with encryption turned off but the old keys
+// still configured, to store every schema in plaintext again.
+package reencryption
+
+import (
+	"context"
+
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// JobType is the async job type that re-encrypts a context.
+const JobType = "schema_reencryption"
+
+// Register registers the re-encryption job type with the job manager. The
+// job covers the registry context it was submitted for and its result is a
+// registry.ReencryptReport.
+func Register(m *jobs.Manager, reg *registry.Registry) {
+	m.Register(JobType, func(ctx context.Context, job *jobs.Job) (any, error) {
+		return reg.ReencryptSchemas(ctx, job.RegistryContext(), func(_ context.Context, done, total int) error {
+			job.SetProgress(int64(done), int64(total), "")
+			return nil
+		})
+	})
+}
@@ -0,0 +1,105 @@
+package reencryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/atrest"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestReencryptionJob(t *testing.T) {
+	ctx := context.Background()
+	backend := memory.NewStore()
+	parsers := schema.NewRegistry()
+	parsers.Register(avro.NewParser())
+	checker := compatibility.NewChecker()
+	checker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+
+	// Schemas registered before encryption was enabled, one of them in two
+	// subjects and one soft-deleted.
+	plain := registry.New(backend, parsers, checker, "NONE")
+	for _, r := range []struct{ subject, schema string }{
+		{"orders-value", `"string"`},
+		{"orders-value", `"int"`},
+		{"users-value", `"string"`},
+	} {
+		if _, err := plain.RegisterSchema(ctx, ".", r.subject, r.schema, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema: %v", err)
+		}
+	}
+	if _, err := plain.DeleteVersion(ctx, ".", "orders-value", 2, false); err != nil {
+		t.Fatalf("DeleteVersion: %v", err)
+	}
+
+	keyring, err := atrest.NewKeyring(ctx, atrest.Config{
+		Keys:      map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)},
+		ActiveKey: "k1",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	reg := registry.New(storage.NewEncryptedStorage(backend, keyring), parsers, checker, "NONE")
+
+	m := jobs.NewManager(backend, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	Register(m, reg)
+	stop := make(chan struct{})
+	defer close(stop)
+	m.Start(stop)
+
+	rec, err := m.Submit(ctx, JobType, ".", nil, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !rec.Finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish: %+v", rec)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if rec, err = m.Get(ctx, rec.ID); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if rec.State != storage.JobStateSucceeded {
+		t.Fatalf("job state %s: %s", rec.State, rec.Error)
+	}
+
+	var report registry.ReencryptReport
+	if err := json.Unmarshal([]byte(rec.Result), &report); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if report.SubjectsScanned != 2 || report.SchemasRewritten != 2 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	for _, id := range []int64{1, 2} {
+		raw, err := backend.GetSchemaByID(ctx, ".", id)
+		if err != nil {
+			t.Fatalf("GetSchemaByID: %v", err)
+		}
+		if !strings.HasPrefix(raw.Schema, "enc:v1:@static.k1.") {
+			t.Errorf("schema %d: expected it to be sealed, got %q", id, raw.Schema)
+		}
+		opened, err := reg.GetSchemaByID(ctx, ".", id)
+		if err != nil {
+			t.Fatalf("GetSchemaByID: %v", err)
+		}
+		if strings.HasPrefix(opened.Schema, "enc:") {
+			t.Errorf("schema %d: expected the registry to read plaintext, got %q", id, opened.Schema)
+		}
+	}
+}
@@ -5,11 +5,46 @@ import "errors"
 // Sentinel errors for the registry layer.
 // These allow handlers to check error types with errors.Is() instead of string matching.
 var (
-	ErrInvalidSchema           = errors.New("invalid schema")
-	ErrUnsupportedSchemaType   = errors.New("unsupported schema type")
-	ErrInvalidRuleSet          = errors.New("invalid ruleSet")
-	ErrFailedResolveReferences = errors.New("failed to resolve references")
-	ErrReferenceExists         = errors.New("schema is referenced by other schemas")
-	ErrInvalidCompatibility    = errors.New("invalid compatibility level")
-	ErrInvalidMode             = errors.New("invalid mode")
+	ErrInvalidSchema             = errors.New("invalid schema")
+	ErrUnsupportedSchemaType     = errors.New("unsupported schema type")
+	ErrInvalidRuleSet            = errors.New("invalid ruleSet")
+	ErrFailedResolveReferences   = errors.New("failed to resolve references")
+	ErrReferenceExists           = errors.New("schema is referenced by other schemas")
+	ErrReferenceCycle            = errors.New("schema reference cycle detected")
+	ErrReferenceDepthExceeded    = errors.New("schema reference chain too deep")
+	ErrInvalidCompatibility      = errors.New("invalid compatibility level")
+	ErrInvalidMode               = errors.New("invalid mode")
+	ErrInvalidContext            = errors.New("invalid context")
+	ErrContextNotCreated         = errors.New("context does not exist and is not created on write")
+	ErrContextProtected          = errors.New("context cannot be deleted")
+	ErrContextNotEmpty           = errors.New("context is not empty")
+	ErrContextQuotaExceeded      = errors.New("context quota exceeded")
+	ErrInvalidTenant             = errors.New("invalid tenant")
+	ErrTenantNotEmpty            = errors.New("tenant still owns contexts")
+	ErrSubjectNameStrategy       = errors.New("subject name does not match naming strategy")
+	ErrLintFailed                = errors.New("schema violates lint rules")
+	ErrFingerprintMismatch       = errors.New("fingerprint does not match schema")
+	ErrInvalidTags               = errors.New("invalid tags")
+	ErrInvalidSubjectMeta        = errors.New("invalid subject meta")
+	ErrInvalidImportSession      = errors.New("invalid import session")
+	ErrImportSessionClosed       = errors.New("import session is not open")
+	ErrImportSessionConflict     = errors.New("subject is already in an open import session")
+	ErrInvalidMaintenance        = errors.New("invalid maintenance window")
+	ErrMaintenanceEnded          = errors.New("maintenance window has ended")
+	ErrInvalidIDAlias            = errors.New("invalid schema ID alias")
+	ErrVersionFrozen             = errors.New("version is frozen")
+	ErrInvalidSubjectAlias       = errors.New("invalid subject alias")
+	ErrSubjectAliased            = errors.New("subject is an alias")
+	ErrInvalidReview             = errors.New("invalid review")
+	ErrPendingSchemaReviewed     = errors.New("pending schema has already been reviewed")
+	ErrSelfApproval              = errors.New("submitter cannot approve their own schema")
+	ErrNotAssignedReviewer       = errors.New("not an assigned reviewer of the pending schema")
+	ErrInvalidReader             = errors.New("invalid reader assertion")
+	ErrUnsupportedConversion     = errors.New("unsupported schema conversion")
+	ErrInvalidDeleteConfirmation = errors.New("invalid delete confirmation")
+	ErrWritesSuspended           = errors.New("writes are suspended")
+	ErrInvalidDesiredState       = errors.New("invalid desired state")
+	ErrQuotaExceeded             = errors.New("quota exceeded")
+	ErrQuotaRuleNotFound         = errors.New("quota rule not found")
+	ErrUnsupportedFingerprint    = errors.New("unsupported fingerprint algorithm")
 )
@@ -7,14 +7,22 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
 	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
 	"github.com/axonops/axonops-schema-registry/internal/kms"
+	"github.com/axonops/axonops-schema-registry/internal/lint"
 	"github.com/axonops/axonops-schema-registry/internal/rules"
 	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
@@ -32,18 +40,101 @@ type Registry struct {
 	storage       storage.Storage
 	schemaParser  *schema.Registry
 	compatChecker *compatibility.Checker
-	defaultConfig string
+	defaultConfig atomic.Value // string; see SetDefaultCompatibility
 	kmsRegistry   *kms.Registry
+
+	// Normalization profiles applied when normalize is enabled.
+	defaultProfile  schema.NormalizationProfile
+	contextProfiles map[string]schema.NormalizationProfile
+
+	// Subject naming strategies enforced at registration.
+	defaultNameStrategy   string
+	contextNameStrategies map[string]string
+
+	// Lint policies enforced at registration.
+	defaultLintPolicy   lint.Policy
+	contextLintPolicies map[string]lint.Policy
+	subjectLintPolicies map[string]lint.Policy
+
+	// Longest reference chain resolved before a schema is rejected.
+	maxReferenceDepth int
+
+	// Generates the encryption key of new tenants; nil when tenant
+	// encryption is disabled.
+	tenantKeys TenantKeyGenerator
+
+	// Whether CreateTenant also creates the tenant's home context.
+	tenantHomeContexts bool
+
+	// Whether schema IDs are allocated from one sequence shared by all
+	// contexts, and resolved across them.
+	globalIDs bool
+
+	// What writes to an aliased subject do; see ResolveWriteAlias.
+	aliasWritePolicy string
+
+	// Serializes changes to import sessions made through this instance.
+	importMu sync.Mutex
+
+	// Contexts whose developer registrations are held for approval, and
+	// the reviewers assigned to their pending schemas by default.
+	approvalContexts  map[string]bool
+	approvalReviewers map[string][]string
+
+	// Whether a write to a context that does not exist is refused, unless
+	// the context is in autoCreateContexts; see SetContextAutoCreate.
+	noContextAutoCreate bool
+	autoCreateContexts  map[string]bool
+
+	// Serializes reviews of pending schemas made through this instance.
+	pendingMu sync.Mutex
+
+	// Permanent deletes awaiting confirmation, by token; see
+	// SetDeleteConfirmation. A zero TTL means deletes are not two-phase.
+	deleteConfirmMu  sync.Mutex
+	deleteConfirmTTL time.Duration
+	pendingDeletes   map[string]pendingDelete
+
+	// Refuses writes while storage could lose them; see SetWriteGate.
+	writeGate WriteGate
+
+	// Registration quotas of principals and roles; nil when none are
+	// configured. See SetQuotas.
+	quotas *quotaTracker
+
+	// Records registrations and imports; nil when metrics are off. See
+	// SetMetrics.
+	metrics RegistrationMetrics
 }
 
+// DefaultMaxReferenceDepth is the default limit on how many references deep
+// a schema's transitive references may go.
+const DefaultMaxReferenceDepth = 32
+
 // New creates a new Registry.
 func New(store storage.Storage, parser *schema.Registry, compatChecker *compatibility.Checker, defaultCompatibility string) *Registry {
-	return &Registry{
-		storage:       store,
-		schemaParser:  parser,
-		compatChecker: compatChecker,
-		defaultConfig: defaultCompatibility,
+	r := &Registry{
+		storage:           store,
+		schemaParser:      parser,
+		compatChecker:     compatChecker,
+		defaultProfile:    schema.DefaultNormalizationProfile,
+		maxReferenceDepth: DefaultMaxReferenceDepth,
 	}
+	r.defaultConfig.Store(defaultCompatibility)
+	return r
+}
+
+// SetDefaultCompatibility replaces the compatibility level of subjects and
+// contexts without their own config. It may be called while the registry
+// serves requests.
+func (r *Registry) SetDefaultCompatibility(level string) {
+	r.defaultConfig.Store(level)
+}
+
+// defaultCompatibility returns the compatibility level of subjects and
+// contexts without their own config.
+func (r *Registry) defaultCompatibility() string {
+	return r.defaultConfig.Load().(string)
 }
 
 // SetKMSRegistry sets the KMS provider registry for server-side DEK operations.
@@ -52,48 +143,115 @@ func (r *Registry) SetKMSRegistry(reg *kms.Registry) {
 	r.kmsRegistry = reg
 }
 
+// SetMaxReferenceDepth sets the limit on how many references deep a schema's
+// transitive references may go. A schema's own references are at depth 1.
+// Non-positive values restore DefaultMaxReferenceDepth.
+func (r *Registry) SetMaxReferenceDepth(depth int) {
+	if depth <= 0 {
+		depth = DefaultMaxReferenceDepth
+	}
+	r.maxReferenceDepth = depth
+}
+
+// SetNormalizationProfiles sets the normalization profiles applied when
+// normalization is enabled. contextProfiles maps a context name to its profile;
+// contexts without an entry use defaultProfile.
+func (r *Registry) SetNormalizationProfiles(defaultProfile schema.NormalizationProfile, contextProfiles map[string]schema.NormalizationProfile) {
+	r.defaultProfile = defaultProfile
+	r.contextProfiles = make(map[string]schema.NormalizationProfile, len(contextProfiles))
+	for name, profile := range contextProfiles {
+		r.contextProfiles[registrycontext.NormalizeContextName(name)] = profile
+	}
+}
+
+// NormalizationProfile returns the normalization profile for a context.
+func (r *Registry) NormalizationProfile(registryCtx string) schema.NormalizationProfile {
+	if profile, ok := r.contextProfiles[registryCtx]; ok {
+		return profile
+	}
+	return r.defaultProfile
+}
+
 // RegisterOpts holds optional parameters for schema registration.
 type RegisterOpts struct {
 	Normalize bool
 	Metadata  *storage.Metadata
 	RuleSet   *storage.RuleSet
+
+	// Principal and Role identify who is registering, for quotas. A
+	// registration without a principal is not limited by quotas.
+	Principal string
+	Role      string
 }
 
 // RegisterSchema registers a new schema for a subject.
 func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, opts ...RegisterOpts) (*storage.SchemaRecord, error) {
+	var opt RegisterOpts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	ctx, span := startSpan(ctx, "RegisterSchema", registryCtx, subject)
+	record, _, err := r.registerSchema(ctx, registryCtx, subject, schemaStr, schemaType, refs, opt, false)
+	endSpan(span, record, err)
+	r.recordRegistration(schemaType, err)
+	return record, err
+}
+
+// PlanRegistration runs every check RegisterSchema would run without storing
+// anything. It returns the version the schema is already registered as, or
+// the record that registering it would create, with the version it would
+// likely be given and the ID of the same schema elsewhere in the context, or
+// 0 for a new ID. The boolean result reports whether a new version would be
+// created. A version registered concurrently, or a permanently deleted
+// version, can make the real version higher.
+func (r *Registry) PlanRegistration(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, opt RegisterOpts) (*storage.SchemaRecord, bool, error) {
+	ctx, span := startSpan(ctx, "PlanRegistration", registryCtx, subject)
+	record, created, err := r.registerSchema(ctx, registryCtx, subject, schemaStr, schemaType, refs, opt, true)
+	endSpan(span, record, err)
+	return record, created, err
+}
+
+// registerSchema implements RegisterSchema and, when dryRun is set,
+// PlanRegistration. The boolean result reports whether a new version was, or
+// would be, created.
+func (r *Registry) registerSchema(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, opt RegisterOpts, dryRun bool) (*storage.SchemaRecord, bool, error) {
 	// Default to Avro if not specified
 	if schemaType == "" {
 		schemaType = storage.SchemaTypeAvro
 	}
 
+	if err := r.checkContextCreatable(ctx, registryCtx); err != nil {
+		return nil, false, err
+	}
+
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, false, err
+	}
+
 	// Get the parser for this schema type
 	parser, ok := r.schemaParser.Get(schemaType)
 	if !ok {
-		return nil, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
+		return nil, false, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
 	}
 
 	// Resolve reference content from storage
 	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+		return nil, false, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
 	}
 
 	// Parse the schema
 	parsed, err := parser.Parse(schemaStr, resolvedRefs)
 	if err != nil {
-		return nil, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
-	}
-
-	// Extract options
-	var opt RegisterOpts
-	if len(opts) > 0 {
-		opt = opts[0]
+		return nil, false, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
 	}
+	schemaStr = sourceOf(parsed, schemaStr)
 
 	// Validate ruleSet if provided
 	if opt.RuleSet != nil {
 		if err := rules.ValidateRuleSet(opt.RuleSet); err != nil {
-			return nil, fmt.Errorf("invalid ruleSet: %w", errors.Join(err, ErrInvalidRuleSet))
+			return nil, false, fmt.Errorf("invalid ruleSet: %w", errors.Join(err, ErrInvalidRuleSet))
 		}
 	}
 
@@ -103,7 +261,7 @@ func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subje
 		shouldNormalize = r.isNormalizeEnabled(ctx, registryCtx, subject)
 	}
 	if shouldNormalize {
-		parsed = parsed.Normalize()
+		parsed = parsed.NormalizeWithProfile(r.NormalizationProfile(registryCtx))
 		schemaStr = parsed.CanonicalString()
 	}
 
@@ -123,17 +281,32 @@ func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subje
 	if err == nil && existing != nil {
 		if metadataEqualForDedup(existing.Metadata, opt.Metadata) && ruleSetEqual(existing.RuleSet, opt.RuleSet) {
 			if cvTarget <= 0 || cvTarget == existing.Version {
-				return autoPopulateConfluentVersion(existing), nil
+				return autoPopulateConfluentVersion(existing), false, nil
 			}
 			// confluent:version mismatch — skip dedup, create new version
 		}
 		// Same schema text but different metadata/ruleSet — fall through to create new version
 	}
 
+	// Enforce the context's subject naming strategy
+	if err := r.checkSubjectName(registryCtx, subject, parsed); err != nil {
+		return nil, false, err
+	}
+
+	// Enforce the subject's lint policy
+	if err := r.checkLint(registryCtx, subject, schemaStr, schemaType, resolvedRefs); err != nil {
+		return nil, false, err
+	}
+
+	// Enforce per-context limits (max subjects, max schema size)
+	if err := r.checkContextQuota(ctx, registryCtx, subject, schemaStr); err != nil {
+		return nil, false, err
+	}
+
 	// Get compatibility level for this subject
 	compatLevel, err := r.GetConfig(ctx, registryCtx, subject)
 	if err != nil {
-		compatLevel = r.defaultConfig
+		compatLevel = r.defaultCompatibility()
 	}
 
 	// Check compatibility if not NONE
@@ -142,7 +315,7 @@ func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subje
 		// Get existing schemas for compatibility check
 		existingSchemas, err := r.storage.GetSchemasBySubject(ctx, registryCtx, subject, false)
 		if err != nil && !errors.Is(err, storage.ErrSubjectNotFound) {
-			return nil, fmt.Errorf("failed to get existing schemas: %w", err)
+			return nil, false, fmt.Errorf("failed to get existing schemas: %w", err)
 		}
 
 		// Filter by compatibility group if configured
@@ -154,7 +327,7 @@ func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subje
 			for i, s := range existingSchemas {
 				existingResolvedRefs, resolveErr := r.resolveReferences(ctx, registryCtx, s.References)
 				if resolveErr != nil {
-					return nil, fmt.Errorf("failed to resolve existing schema references: %w", resolveErr)
+					return nil, false, fmt.Errorf("failed to resolve existing schema references: %w", resolveErr)
 				}
 				existingWithRefs[i] = compatibility.SchemaWithRefs{
 					Schema:     s.Schema,
@@ -167,7 +340,10 @@ func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subje
 				compatibility.SchemaWithRefs{Schema: schemaStr, References: resolvedRefs},
 				existingWithRefs)
 			if !result.IsCompatible {
-				return nil, fmt.Errorf("%w: %s", ErrIncompatibleSchema, strings.Join(result.Messages, "; "))
+				if !dryRun {
+					r.recordRejection(schemaType, result.ReasonCategories()...)
+				}
+				return nil, false, fmt.Errorf("%w: %s", ErrIncompatibleSchema, strings.Join(result.Messages, "; "))
 			}
 		}
 	}
@@ -175,7 +351,10 @@ func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subje
 	// Validate reserved fields if enabled
 	if r.isValidateFieldsEnabled(ctx, registryCtx, subject) {
 		if msgs := r.validateReservedFields(ctx, registryCtx, subject, parsed, opt.Metadata); len(msgs) > 0 {
-			return nil, fmt.Errorf("%w: %s", ErrIncompatibleSchema, strings.Join(msgs, "; "))
+			if !dryRun {
+				r.recordRejection(schemaType, RejectedReservedField)
+			}
+			return nil, false, fmt.Errorf("%w: %s", ErrIncompatibleSchema, strings.Join(msgs, "; "))
 		}
 	}
 
@@ -201,22 +380,51 @@ func (r *Registry) RegisterSchema(ctx context.Context, registryCtx string, subje
 		Fingerprint: globalFingerprint,
 	}
 
+	// Enforce the registering principal's quotas
+	reservation, err := r.reserveQuota(ctx, registryCtx, subject, opt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if dryRun {
+		reservation.release()
+		return r.plannedRecord(ctx, registryCtx, record), true, nil
+	}
+
 	// Store the schema
 	if err := r.storage.CreateSchema(ctx, registryCtx, record); err != nil {
+		reservation.release()
 		if errors.Is(err, storage.ErrSchemaExists) {
 			// Storage detected same fingerprint+metadata — return existing,
 			// but only if confluent:version soft CAS also matches.
 			existing, _ := r.storage.GetSchemaByFingerprint(ctx, registryCtx, subject, globalFingerprint, false)
 			if existing != nil && metadataEqual(existing.Metadata, opt.Metadata) && ruleSetEqual(existing.RuleSet, opt.RuleSet) {
 				if cvTarget <= 0 || cvTarget == existing.Version {
-					return autoPopulateConfluentVersion(existing), nil
+					return autoPopulateConfluentVersion(existing), false, nil
 				}
 			}
 		}
-		return nil, fmt.Errorf("failed to store schema: %w", err)
+		return nil, false, fmt.Errorf("failed to store schema: %w", err)
 	}
 
-	return autoPopulateConfluentVersion(record), nil
+	return autoPopulateConfluentVersion(record), true, nil
+}
+
+// plannedRecord fills in the ID and version a new record would likely be
+// stored with. Versions are never reused, so soft-deleted ones count.
+func (r *Registry) plannedRecord(ctx context.Context, registryCtx string, record *storage.SchemaRecord) *storage.SchemaRecord {
+	if existing, err := r.storage.GetSchemaByGlobalFingerprint(ctx, registryCtx, record.Fingerprint); err == nil && existing != nil {
+		record.ID = existing.ID
+	}
+	record.Version = 1
+	if versions, err := r.storage.GetSchemasBySubject(ctx, registryCtx, record.Subject, true); err == nil {
+		for _, v := range versions {
+			if v.Version >= record.Version {
+				record.Version = v.Version + 1
+			}
+		}
+	}
+	return autoPopulateConfluentVersion(record)
 }
 
 // extractConfluentVersionTarget extracts the confluent:version target from metadata.
@@ -238,12 +446,80 @@ func extractConfluentVersionTarget(meta *storage.Metadata) int {
 	return cv
 }
 
+// sourceOf returns the schema string to store for a parsed schema, which is
+// schemaStr unless the parser rewrote it, as Protobuf does with descriptors.
+func sourceOf(parsed schema.ParsedSchema, schemaStr string) string {
+	if src, ok := parsed.(schema.SourceProvider); ok {
+		return src.Raw()
+	}
+	return schemaStr
+}
+
+// RegisterSchemaIfAbsent registers a schema under a subject unless a live
+// version with the given fingerprint already exists, in which case that
+// version is returned unchanged. Unlike RegisterSchema, an existing version is
+// returned even when the request's metadata or ruleSet differ, so a retried
+// request never creates an extra version. fingerprint must equal the
+// fingerprint of the submitted schema after any normalization, as returned by
+// ValidateSchema or NormalizeSchema; otherwise ErrFingerprintMismatch is
+// returned. The boolean result reports whether a new version was created.
+func (r *Registry) RegisterSchemaIfAbsent(ctx context.Context, registryCtx string, subject string, fingerprint string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, opts ...RegisterOpts) (*storage.SchemaRecord, bool, error) {
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
+	}
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, false, err
+	}
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+	}
+	parsed, err := parser.Parse(schemaStr, resolvedRefs)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
+	}
+
+	var opt RegisterOpts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Normalize || r.isNormalizeEnabled(ctx, registryCtx, subject) {
+		parsed = parsed.NormalizeWithProfile(r.NormalizationProfile(registryCtx))
+	}
+	if parsed.Fingerprint() != fingerprint {
+		return nil, false, fmt.Errorf("fingerprint %s does not match the submitted schema (expected %s): %w",
+			fingerprint, parsed.Fingerprint(), ErrFingerprintMismatch)
+	}
+
+	existing, err := r.storage.GetSchemaByFingerprint(ctx, registryCtx, subject, computeGlobalFingerprint(fingerprint, refs), false)
+	if err == nil && existing != nil {
+		return autoPopulateConfluentVersion(existing), false, nil
+	}
+
+	record, err := r.RegisterSchema(ctx, registryCtx, subject, schemaStr, schemaType, refs, opt)
+	if err != nil {
+		return nil, false, err
+	}
+	return record, true, nil
+}
+
 // RegisterSchemaWithID registers a schema with a specific ID (for IMPORT mode).
 // If version > 0, that exact version number is used; otherwise the next sequential version is assigned.
 // Confluent behavior: if the ID already exists with the same schema content, the schema
 // is associated with the new subject (succeeds). If the ID exists with different content,
 // returns error code 42205.
 func (r *Registry) RegisterSchemaWithID(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, id int64, version int) (*storage.SchemaRecord, error) {
+	record, err := r.registerSchemaWithID(ctx, registryCtx, subject, schemaStr, schemaType, refs, id, version)
+	r.recordRegistration(schemaType, err)
+	return record, err
+}
+
+func (r *Registry) registerSchemaWithID(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, id int64, version int) (*storage.SchemaRecord, error) {
 	if schemaType == "" {
 		schemaType = storage.SchemaTypeAvro
 	}
@@ -253,7 +529,16 @@ func (r *Registry) RegisterSchemaWithID(ctx context.Context, registryCtx string,
 		return nil, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
 	}
 
-	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
+	if err := r.checkContextCreatable(ctx, registryCtx); err != nil {
+		return nil, err
+	}
+
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedRefs, err := r.resolveReferencesFrom(ctx, registryCtx, subject, version, refs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
 	}
@@ -262,6 +547,7 @@ func (r *Registry) RegisterSchemaWithID(ctx context.Context, registryCtx string,
 	if err != nil {
 		return nil, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
 	}
+	schemaStr = sourceOf(parsed, schemaStr)
 
 	// Check if schema already exists in this subject with same fingerprint (idempotent)
 	globalFP := computeGlobalFingerprint(parsed.Fingerprint(), refs)
@@ -270,6 +556,11 @@ func (r *Registry) RegisterSchemaWithID(ctx context.Context, registryCtx string,
 		return existing, nil
 	}
 
+	// Enforce per-context limits (max subjects, max schema size)
+	if err := r.checkContextQuota(ctx, registryCtx, subject, schemaStr); err != nil {
+		return nil, err
+	}
+
 	// Determine version: use explicit version if provided, otherwise auto-assign next sequential.
 	// Include soft-deleted versions to avoid version number conflicts
 	// with rows that still physically exist in storage.
@@ -296,7 +587,11 @@ func (r *Registry) RegisterSchemaWithID(ctx context.Context, registryCtx string,
 		Fingerprint: globalFP,
 	}
 
-	if err := r.storage.ImportSchema(ctx, registryCtx, record); err != nil {
+	err = r.checkGlobalIDConflict(ctx, registryCtx, id, globalFP)
+	if err == nil {
+		err = r.storage.ImportSchema(ctx, registryCtx, record)
+	}
+	if err != nil {
 		if errors.Is(err, storage.ErrSchemaIDConflict) {
 			return nil, fmt.Errorf("overwrite schema with id %d: %w", id, ErrImportIDConflict)
 		}
@@ -328,42 +623,25 @@ func (r *Registry) RegisterSchemaWithID(ctx context.Context, registryCtx string,
 
 // CheckCompatibility checks if a schema is compatible with a specific version or all versions.
 func (r *Registry) CheckCompatibility(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, version string, normalize ...bool) (*compatibility.Result, error) {
-	// Default to Avro if not specified
-	if schemaType == "" {
-		schemaType = storage.SchemaTypeAvro
-	}
-
-	// Parse the new schema to validate it
-	parser, ok := r.schemaParser.Get(schemaType)
-	if !ok {
-		return nil, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
-	}
-
-	// Resolve reference content from storage
-	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+	ctx, span := startSpan(ctx, "CheckCompatibility", registryCtx, subject)
+	result, err := r.checkCompatibility(ctx, registryCtx, subject, schemaStr, schemaType, refs, version, normalize...)
+	if result != nil {
+		span.SetAttributes(attribute.Bool("schema_registry.compatible", result.IsCompatible))
 	}
+	endSpan(span, nil, err)
+	return result, err
+}
 
-	parsed, err := parser.Parse(schemaStr, resolvedRefs)
+func (r *Registry) checkCompatibility(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, version string, normalize ...bool) (*compatibility.Result, error) {
+	proposed, schemaType, err := r.parseProposedSchema(ctx, registryCtx, subject, schemaStr, schemaType, refs, normalize...)
 	if err != nil {
-		return nil, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
-	}
-
-	// Apply normalization if requested
-	shouldNormalize := len(normalize) > 0 && normalize[0]
-	if !shouldNormalize {
-		shouldNormalize = r.isNormalizeEnabled(ctx, registryCtx, subject)
-	}
-	if shouldNormalize {
-		parsed = parsed.Normalize()
-		schemaStr = parsed.CanonicalString()
+		return nil, err
 	}
 
 	// Get compatibility level
 	compatLevel, err := r.GetConfig(ctx, registryCtx, subject)
 	if err != nil {
-		compatLevel = r.defaultConfig
+		compatLevel = r.defaultCompatibility()
 	}
 
 	mode := compatibility.Mode(compatLevel)
@@ -433,14 +711,108 @@ func (r *Registry) CheckCompatibility(ctx context.Context, registryCtx string, s
 		return compatibility.NewCompatibleResult(), nil
 	}
 
-	return r.compatChecker.Check(mode, schemaType,
-		compatibility.SchemaWithRefs{Schema: schemaStr, References: resolvedRefs},
-		schemasToCheck), nil
+	return r.compatChecker.Check(mode, schemaType, proposed, schemasToCheck), nil
 }
 
-// GetSchemaByID retrieves a schema by its ID within a context.
+// parseProposedSchema validates a schema proposed for subject and returns it
+// with its references resolved, normalized if requested or configured, along
+// with its schema type (Avro when empty).
+func (r *Registry) parseProposedSchema(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, normalize ...bool) (compatibility.SchemaWithRefs, storage.SchemaType, error) {
+	// Default to Avro if not specified
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+
+	// Parse the new schema to validate it
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return compatibility.SchemaWithRefs{}, "", fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
+	}
+
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return compatibility.SchemaWithRefs{}, "", err
+	}
+
+	// Resolve reference content from storage
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
+	if err != nil {
+		return compatibility.SchemaWithRefs{}, "", fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+	}
+
+	parsed, err := parser.Parse(schemaStr, resolvedRefs)
+	if err != nil {
+		return compatibility.SchemaWithRefs{}, "", fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
+	}
+
+	// Apply normalization if requested
+	shouldNormalize := len(normalize) > 0 && normalize[0]
+	if !shouldNormalize {
+		shouldNormalize = r.isNormalizeEnabled(ctx, registryCtx, subject)
+	}
+	if shouldNormalize {
+		parsed = parsed.NormalizeWithProfile(r.NormalizationProfile(registryCtx))
+		schemaStr = parsed.CanonicalString()
+	}
+	return compatibility.SchemaWithRefs{Schema: schemaStr, References: resolvedRefs}, schemaType, nil
+}
+
+// GetCompatibleVersions returns the live versions of a subject that the schema
+// with the given ID can safely consume, along with the subject's compatibility
+// level. A version qualifies when the schema, as reader, can read data written
+// with that version. When the level also requires forward compatibility (FULL,
+// FULL_TRANSITIVE), the version must in turn be able to read data written with
+// the schema. Versions of a different schema type never qualify.
+func (r *Registry) GetCompatibleVersions(ctx context.Context, registryCtx string, subject string, schemaID int64) ([]int, string, error) {
+	reader, err := r.storage.GetSchemaByID(ctx, registryCtx, schemaID)
+	if err != nil {
+		return nil, "", err
+	}
+	readerRefs, err := r.resolveReferences(ctx, registryCtx, reader.References)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+	}
+
+	compatLevel, err := r.GetConfig(ctx, registryCtx, subject)
+	if err != nil {
+		compatLevel = r.defaultCompatibility()
+	}
+	pairMode := compatibility.ModeBackward
+	if level := compatibility.Mode(compatLevel); level.RequiresBackward() && level.RequiresForward() {
+		pairMode = compatibility.ModeFull
+	}
+
+	versions, err := r.storage.GetSchemasBySubject(ctx, registryCtx, subject, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	readerSchema := compatibility.SchemaWithRefs{Schema: reader.Schema, References: readerRefs}
+	compatible := []int{}
+	for _, v := range versions {
+		if v.SchemaType != reader.SchemaType {
+			continue
+		}
+		writerRefs, err := r.resolveReferences(ctx, registryCtx, v.References)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to resolve existing schema references: %w", err)
+		}
+		writerSchema := compatibility.SchemaWithRefs{Schema: v.Schema, References: writerRefs}
+		if r.compatChecker.CheckPair(pairMode, reader.SchemaType, readerSchema, writerSchema).IsCompatible {
+			compatible = append(compatible, v.Version)
+		}
+	}
+	return compatible, compatLevel, nil
+}
+
+// GetSchemaByID retrieves a schema by its ID within a context, or within
+// the context that holds the ID when IDs are global.
 func (r *Registry) GetSchemaByID(ctx context.Context, registryCtx string, id int64) (*storage.SchemaRecord, error) {
-	return r.storage.GetSchemaByID(ctx, registryCtx, id)
+	holder, err := r.SchemaIDContext(ctx, registryCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.storage.GetSchemaByID(ctx, holder, id)
 }
 
 // GetMaxSchemaID returns the highest schema ID currently assigned in a context.
@@ -465,17 +837,11 @@ func (r *Registry) FormatSchema(ctx context.Context, registryCtx string, record
 		return record.Schema
 	}
 
-	// Resolve references
-	refs := record.References
-	resolvedRefs := make([]storage.Reference, len(refs))
-	copy(resolvedRefs, refs)
-	for i, ref := range resolvedRefs {
-		if ref.Schema == "" {
-			refSchema, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, ref.Subject, ref.Version)
-			if err == nil {
-				resolvedRefs[i].Schema = refSchema.Schema
-			}
-		}
+	// Resolve references, including the references of references, so
+	// formats that inline them see every schema they need.
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, record.References)
+	if err != nil {
+		return record.Schema
 	}
 
 	parsed, err := parser.Parse(record.Schema, resolvedRefs)
@@ -506,6 +872,11 @@ func (r *Registry) ListSubjects(ctx context.Context, registryCtx string, deleted
 	return r.storage.ListSubjects(ctx, registryCtx, deleted)
 }
 
+// ListSubjectsPage returns one page of subject names within a context.
+func (r *Registry) ListSubjectsPage(ctx context.Context, registryCtx string, params *storage.ListSubjectsParams) ([]string, error) {
+	return r.storage.ListSubjectsPage(ctx, registryCtx, params)
+}
+
 // SubjectCount returns the number of active (non-deleted) subjects in the default context.
 // Satisfies metrics.GaugeSource.
 func (r *Registry) SubjectCount() (int, error) {
@@ -549,8 +920,27 @@ func (r *Registry) GetVersions(ctx context.Context, registryCtx string, subject
 	return versions, nil
 }
 
+// ListVersions returns a page of version numbers for a subject within a context
+// without loading schema content.
+func (r *Registry) ListVersions(ctx context.Context, registryCtx string, subject string, params *storage.ListVersionsParams) ([]int, error) {
+	return r.storage.ListVersions(ctx, registryCtx, subject, params)
+}
+
+// ListSoftDeletedVersions returns the versions in a context that were
+// soft-deleted before the cutoff.
+func (r *Registry) ListSoftDeletedVersions(ctx context.Context, registryCtx string, deletedBefore time.Time) ([]storage.SubjectVersion, error) {
+	return r.storage.ListSoftDeletedVersions(ctx, registryCtx, deletedBefore)
+}
+
 // LookupSchema finds a schema in a subject within a context.
 func (r *Registry) LookupSchema(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, deleted bool, normalize ...bool) (*storage.SchemaRecord, error) {
+	ctx, span := startSpan(ctx, "LookupSchema", registryCtx, subject)
+	record, err := r.lookupSchema(ctx, registryCtx, subject, schemaStr, schemaType, refs, deleted, normalize...)
+	endSpan(span, record, err)
+	return record, err
+}
+
+func (r *Registry) lookupSchema(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, deleted bool, normalize ...bool) (*storage.SchemaRecord, error) {
 	// Default to Avro if not specified
 	if schemaType == "" {
 		schemaType = storage.SchemaTypeAvro
@@ -562,6 +952,11 @@ func (r *Registry) LookupSchema(ctx context.Context, registryCtx string, subject
 		return nil, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
 	}
 
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Resolve reference content from storage
 	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
 	if err != nil {
@@ -580,7 +975,7 @@ func (r *Registry) LookupSchema(ctx context.Context, registryCtx string, subject
 		shouldNormalize = r.isNormalizeEnabled(ctx, registryCtx, subject)
 	}
 	if shouldNormalize {
-		parsed = parsed.Normalize()
+		parsed = parsed.NormalizeWithProfile(r.NormalizationProfile(registryCtx))
 	}
 
 	// Look up by fingerprint, including deleted if requested
@@ -589,6 +984,9 @@ func (r *Registry) LookupSchema(ctx context.Context, registryCtx string, subject
 
 // DeleteSubject deletes a subject within a context.
 func (r *Registry) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
+	if err := r.checkSubjectNotFrozen(ctx, registryCtx, subject); err != nil {
+		return nil, err
+	}
 	if !permanent {
 		// For soft-delete, check if any version in this subject is referenced by other schemas.
 		// If the subject doesn't exist or is already deleted, skip the check and let
@@ -644,6 +1042,9 @@ func (r *Registry) DeleteVersion(ctx context.Context, registryCtx string, subjec
 			version = resolved
 		}
 
+		if err := r.checkVersionNotFrozen(ctx, registryCtx, subject, version); err != nil {
+			return 0, err
+		}
 		if err := r.storage.DeleteSchema(ctx, registryCtx, subject, version, permanent); err != nil {
 			return 0, err
 		}
@@ -659,6 +1060,10 @@ func (r *Registry) DeleteVersion(ctx context.Context, registryCtx string, subjec
 	// Use the resolved version (handles "latest" / -1 → actual version number)
 	resolvedVersion := schema.Version
 
+	if err := r.checkVersionNotFrozen(ctx, registryCtx, subject, resolvedVersion); err != nil {
+		return 0, err
+	}
+
 	// Check for references - only block soft-delete when referenced
 	refs, err := r.storage.GetReferencedBy(ctx, registryCtx, subject, resolvedVersion)
 	if err != nil {
@@ -709,7 +1114,7 @@ func (r *Registry) GetConfig(ctx context.Context, registryCtx string, subject st
 	}
 
 	// Step 4: Server hardcoded default
-	return r.defaultConfig, nil
+	return r.defaultCompatibility(), nil
 }
 
 // GetSubjectConfig gets the compatibility configuration for a specific subject only,
@@ -771,7 +1176,7 @@ func (r *Registry) GetConfigFull(ctx context.Context, registryCtx string, subjec
 	}
 
 	// Step 4: Server hardcoded default
-	return &storage.ConfigRecord{CompatibilityLevel: r.defaultConfig}, nil
+	return &storage.ConfigRecord{CompatibilityLevel: r.defaultCompatibility()}, nil
 }
 
 // GetGlobalConfigDirect returns the context's global config without the __GLOBAL fallback.
@@ -781,7 +1186,7 @@ func (r *Registry) GetGlobalConfigDirect(ctx context.Context, registryCtx string
 	if err == nil {
 		return config, nil
 	}
-	return &storage.ConfigRecord{CompatibilityLevel: r.defaultConfig}, nil
+	return &storage.ConfigRecord{CompatibilityLevel: r.defaultCompatibility()}, nil
 }
 
 // SetConfigOpts holds optional fields for configuration updates.
@@ -824,7 +1229,15 @@ func (r *Registry) SetConfig(ctx context.Context, registryCtx string, subject st
 			}
 		}
 
-		config.Alias = opt.Alias
+		if subject != "" {
+			alias, err := validateSubjectAlias(registryCtx, subject, opt.Alias)
+			if err != nil {
+				return err
+			}
+			config.Alias = alias
+		} else {
+			config.Alias = opt.Alias
+		}
 		config.CompatibilityGroup = opt.CompatibilityGroup
 		config.ValidateFields = opt.ValidateFields
 		config.DefaultMetadata = opt.DefaultMetadata
@@ -835,6 +1248,9 @@ func (r *Registry) SetConfig(ctx context.Context, registryCtx string, subject st
 		config.CompatibilityPolicy = opt.CompatibilityPolicy
 	}
 
+	if err := r.checkContextCreatable(ctx, registryCtx); err != nil {
+		return err
+	}
 	if subject == "" {
 		return r.storage.SetGlobalConfig(ctx, registryCtx, config)
 	}
@@ -932,7 +1348,11 @@ func (r *Registry) GetMode(ctx context.Context, registryCtx string, subject stri
 // resolveGlobalMode resolves the "global" mode by walking the default context chain.
 // This is used for the READONLY_OVERRIDE kill switch check (Confluent compatibility).
 // Chain: default context global mode → __GLOBAL context mode → READWRITE
+// An active maintenance window resolves to READONLY_OVERRIDE before the chain.
 func (r *Registry) resolveGlobalMode(ctx context.Context) string {
+	if active, err := r.ActiveMaintenance(ctx); err == nil && active != nil {
+		return "READONLY_OVERRIDE"
+	}
 	// Default context global mode
 	mode, err := r.storage.GetGlobalMode(ctx, registrycontext.DefaultContext)
 	if err == nil {
@@ -985,6 +1405,9 @@ func (r *Registry) SetMode(ctx context.Context, registryCtx string, subject stri
 		Mode: mode,
 	}
 
+	if err := r.checkContextCreatable(ctx, registryCtx); err != nil {
+		return err
+	}
 	if subject == "" {
 		return r.storage.SetGlobalMode(ctx, registryCtx, modeRecord)
 	}
@@ -1058,6 +1481,10 @@ func (r *Registry) ValidateSchema(ctx context.Context, registryCtx string, schem
 	if !ok {
 		return &ValidateResult{Valid: false, SchemaType: string(schemaType), Error: "unsupported schema type"}, nil
 	}
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return &ValidateResult{Valid: false, SchemaType: string(schemaType), Error: err.Error()}, nil
+	}
 	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
 	if err != nil {
 		return &ValidateResult{Valid: false, SchemaType: string(schemaType), Error: fmt.Sprintf("failed to resolve references: %v", err)}, nil
@@ -1075,13 +1502,19 @@ func (r *Registry) ValidateSchema(ctx context.Context, registryCtx string, schem
 }
 
 // NormalizeResult holds the result of a schema normalization.
+// UnnormalizedFingerprint is the fingerprint the schema is registered under
+// when normalize is off; Changed reports whether normalizing alters it.
 type NormalizeResult struct {
-	Normalized  string `json:"normalized"`
-	Fingerprint string `json:"fingerprint"`
-	SchemaType  string `json:"schema_type"`
+	Normalized              string `json:"normalized"`
+	Fingerprint             string `json:"fingerprint"`
+	UnnormalizedFingerprint string `json:"unnormalized_fingerprint"`
+	Changed                 bool   `json:"changed"`
+	SchemaType              string `json:"schema_type"`
 }
 
-// NormalizeSchema parses and normalizes a schema, returning the canonical form.
+// NormalizeSchema parses and normalizes a schema with the context's
+// normalization profile, returning the canonical form that registering it
+// with normalize=true would store. Nothing is registered.
 func (r *Registry) NormalizeSchema(ctx context.Context, registryCtx string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference) (*NormalizeResult, error) {
 	if schemaType == "" {
 		schemaType = storage.SchemaTypeAvro
@@ -1090,19 +1523,25 @@ func (r *Registry) NormalizeSchema(ctx context.Context, registryCtx string, sche
 	if !ok {
 		return nil, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
 	}
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, err
+	}
 	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve references: %w", err)
+		return nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
 	}
 	parsed, err := parser.Parse(schemaStr, resolvedRefs)
 	if err != nil {
 		return nil, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
 	}
-	normalized := parsed.Normalize()
+	normalized := parsed.NormalizeWithProfile(r.NormalizationProfile(registryCtx))
 	return &NormalizeResult{
-		Normalized:  normalized.CanonicalString(),
-		Fingerprint: normalized.Fingerprint(),
-		SchemaType:  string(schemaType),
+		Normalized:              normalized.CanonicalString(),
+		Fingerprint:             normalized.Fingerprint(),
+		UnnormalizedFingerprint: parsed.Fingerprint(),
+		Changed:                 normalized.Fingerprint() != parsed.Fingerprint(),
+		SchemaType:              string(schemaType),
 	}, nil
 }
 
@@ -1113,7 +1552,7 @@ func (r *Registry) GetSchemaTypes() []string {
 
 // GetRawSchemaByID retrieves just the schema string by ID within a context.
 func (r *Registry) GetRawSchemaByID(ctx context.Context, registryCtx string, id int64) (string, error) {
-	schema, err := r.storage.GetSchemaByID(ctx, registryCtx, id)
+	schema, err := r.GetSchemaByID(ctx, registryCtx, id)
 	if err != nil {
 		return "", err
 	}
@@ -1121,13 +1560,39 @@ func (r *Registry) GetRawSchemaByID(ctx context.Context, registryCtx string, id
 }
 
 // GetSubjectsBySchemaID returns all subjects where the given schema ID is registered within a context.
+// When IDs are global and another context holds the ID, its subjects are
+// returned qualified with that context.
 func (r *Registry) GetSubjectsBySchemaID(ctx context.Context, registryCtx string, id int64, includeDeleted bool) ([]string, error) {
-	return r.storage.GetSubjectsBySchemaID(ctx, registryCtx, id, includeDeleted)
+	holder, err := r.SchemaIDContext(ctx, registryCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := r.storage.GetSubjectsBySchemaID(ctx, holder, id, includeDeleted)
+	if err != nil || holder == registryCtx {
+		return subjects, err
+	}
+	for i, subject := range subjects {
+		subjects[i] = registrycontext.FormatSubject(holder, subject)
+	}
+	return subjects, nil
 }
 
 // GetVersionsBySchemaID returns all subject-version pairs for a schema ID within a context.
+// When IDs are global and another context holds the ID, its subjects are
+// returned qualified with that context.
 func (r *Registry) GetVersionsBySchemaID(ctx context.Context, registryCtx string, id int64, includeDeleted bool) ([]storage.SubjectVersion, error) {
-	return r.storage.GetVersionsBySchemaID(ctx, registryCtx, id, includeDeleted)
+	holder, err := r.SchemaIDContext(ctx, registryCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := r.storage.GetVersionsBySchemaID(ctx, holder, id, includeDeleted)
+	if err != nil || holder == registryCtx {
+		return versions, err
+	}
+	for i := range versions {
+		versions[i].Subject = registrycontext.FormatSubject(holder, versions[i].Subject)
+	}
+	return versions, nil
 }
 
 // ListSchemas returns schemas matching the given filters within a context.
@@ -1182,6 +1647,11 @@ type ImportResult struct {
 // It validates each schema, imports it with the specified ID, and adjusts
 // the ID sequence after import to prevent conflicts.
 func (r *Registry) ImportSchemas(ctx context.Context, registryCtx string, schemas []ImportSchemaRequest) (*ImportResult, error) {
+	if err := r.checkContextCreatable(ctx, registryCtx); err != nil {
+		r.recordImports(schemas, nil)
+		return nil, err
+	}
+
 	result := &ImportResult{
 		Results: make([]ImportSchemaResult, len(schemas)),
 	}
@@ -1237,7 +1707,7 @@ func (r *Registry) ImportSchemas(ctx context.Context, registryCtx string, schema
 		}
 
 		// Resolve reference content from storage
-		resolvedRefs, resolveErr := r.resolveReferences(ctx, registryCtx, req.References)
+		resolvedRefs, resolveErr := r.resolveReferencesFrom(ctx, registryCtx, req.Subject, req.Version, req.References)
 		if resolveErr != nil {
 			res.Error = fmt.Sprintf("failed to resolve references: %v", resolveErr)
 			result.Errors++
@@ -1253,6 +1723,13 @@ func (r *Registry) ImportSchemas(ctx context.Context, registryCtx string, schema
 			continue
 		}
 
+		if err := r.checkContextQuota(ctx, registryCtx, req.Subject, req.Schema); err != nil {
+			res.Error = err.Error()
+			result.Errors++
+			result.Results[i] = res
+			continue
+		}
+
 		// Create the schema record
 		record := &storage.SchemaRecord{
 			ID:          req.ID,
@@ -1265,7 +1742,11 @@ func (r *Registry) ImportSchemas(ctx context.Context, registryCtx string, schema
 		}
 
 		// Import the schema
-		if err := r.storage.ImportSchema(ctx, registryCtx, record); err != nil {
+		err = r.checkGlobalIDConflict(ctx, registryCtx, record.ID, record.Fingerprint)
+		if err == nil {
+			err = r.storage.ImportSchema(ctx, registryCtx, record)
+		}
+		if err != nil {
 			if errors.Is(err, storage.ErrSchemaIDConflict) {
 				res.Error = "schema ID already exists"
 			} else if errors.Is(err, storage.ErrSchemaExists) {
@@ -1287,6 +1768,7 @@ func (r *Registry) ImportSchemas(ctx context.Context, registryCtx string, schema
 		result.Imported++
 		result.Results[i] = res
 	}
+	r.recordImports(schemas, result)
 
 	// Adjust the ID sequence to prevent conflicts.
 	// Guard against rewinding: only advance the sequence, never go backward.
@@ -1321,7 +1803,7 @@ func (r *Registry) DeleteGlobalConfig(ctx context.Context, registryCtx string) (
 	config, err := r.storage.GetGlobalConfig(ctx, registryCtx)
 	if err != nil {
 		// If no config, use default
-		config = &storage.ConfigRecord{CompatibilityLevel: r.defaultConfig}
+		config = &storage.ConfigRecord{CompatibilityLevel: r.defaultCompatibility()}
 	}
 	prevLevel := config.CompatibilityLevel
 
@@ -1516,10 +1998,17 @@ func (r *Registry) validateReservedFields(ctx context.Context, registryCtx strin
 // with different references produces different global IDs. For schemas without references,
 // it returns the original fingerprint unchanged for backward compatibility.
 func computeGlobalFingerprint(schemaFingerprint string, refs []storage.Reference) string {
+	return fingerprintWithReferences(sha256.New, schemaFingerprint, refs)
+}
+
+// fingerprintWithReferences combines a schema fingerprint with the schema's
+// references using the hash newHash returns. Without references it is the
+// schema fingerprint.
+func fingerprintWithReferences(newHash func() hash.Hash, schemaFingerprint string, refs []storage.Reference) string {
 	if len(refs) == 0 {
 		return schemaFingerprint
 	}
-	h := sha256.New()
+	h := newHash()
 	h.Write([]byte(schemaFingerprint))
 	// Sort refs for determinism
 	sorted := make([]storage.Reference, len(refs))
@@ -1539,11 +2028,87 @@ func computeGlobalFingerprint(schemaFingerprint string, refs []storage.Reference
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// referencesByID returns refs with each reference given by schema ID
+// replaced by a subject version registered with that ID. A reference may
+// also name the subject, to choose among the subjects sharing the ID;
+// otherwise the first subject in name order is used. Either way the lowest
+// live version with the ID is taken. refs is returned as is when no
+// reference has an ID. Errors wrap ErrFailedResolveReferences.
+func (r *Registry) referencesByID(ctx context.Context, registryCtx string, refs []storage.Reference) ([]storage.Reference, error) {
+	if !slices.ContainsFunc(refs, func(ref storage.Reference) bool { return ref.ID != 0 }) {
+		return refs, nil
+	}
+	out := make([]storage.Reference, len(refs))
+	for i, ref := range refs {
+		out[i] = ref
+		if ref.ID == 0 {
+			continue
+		}
+		sv, err := r.referencedVersion(ctx, registryCtx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+		}
+		out[i].Subject, out[i].Version, out[i].ID = sv.Subject, sv.Version, 0
+	}
+	return out, nil
+}
+
+// referencedVersion returns the subject version a reference by ID stands for.
+func (r *Registry) referencedVersion(ctx context.Context, registryCtx string, ref storage.Reference) (storage.SubjectVersion, error) {
+	if ref.ID < 0 {
+		return storage.SubjectVersion{}, fmt.Errorf("reference %q has invalid id %d", ref.Name, ref.ID)
+	}
+	if ref.Version != 0 {
+		return storage.SubjectVersion{}, fmt.Errorf("reference %q sets both id and version", ref.Name)
+	}
+	versions, err := r.GetVersionsBySchemaID(ctx, registryCtx, ref.ID, false)
+	if err != nil {
+		return storage.SubjectVersion{}, fmt.Errorf("failed to resolve reference %q (id=%d): %w", ref.Name, ref.ID, err)
+	}
+	var best *storage.SubjectVersion
+	for i, sv := range versions {
+		if ref.Subject != "" && sv.Subject != ref.Subject {
+			continue
+		}
+		if best == nil || sv.Subject < best.Subject || (sv.Subject == best.Subject && sv.Version < best.Version) {
+			best = &versions[i]
+		}
+	}
+	if best == nil {
+		if ref.Subject != "" {
+			return storage.SubjectVersion{}, fmt.Errorf("failed to resolve reference %q: schema %d is not registered under subject %s: %w",
+				ref.Name, ref.ID, ref.Subject, storage.ErrVersionNotFound)
+		}
+		return storage.SubjectVersion{}, fmt.Errorf("failed to resolve reference %q (id=%d): %w", ref.Name, ref.ID, storage.ErrSchemaNotFound)
+	}
+	return *best, nil
+}
+
 // resolveReferences looks up the schema content for each reference from storage.
 // It recursively resolves transitive references (e.g., A refs B, B refs C) using
 // depth-first traversal so that transitive dependencies appear before their dependents.
-// A seen map prevents cycles and ensures each subject:version is resolved only once.
+// A seen map ensures each subject:version is resolved only once, and a reference
+// chain that leads back to a schema already on it fails with ErrReferenceCycle,
+// and one longer than the configured maximum depth with
+// ErrReferenceDepthExceeded. Errors name the reference chain that led to them.
 func (r *Registry) resolveReferences(ctx context.Context, registryCtx string, refs []storage.Reference) ([]storage.Reference, error) {
+	return r.resolveReferencesFrom(ctx, registryCtx, "", 0, refs)
+}
+
+// resolveReferencesFrom is resolveReferences for the references of a known
+// subject version, such as a schema being imported with an explicit version.
+// A reference chain that leads back to that subject version is reported as a
+// cycle. An empty subject or non-positive version disables the root check.
+func (r *Registry) resolveReferencesFrom(ctx context.Context, registryCtx string, subject string, version int, refs []storage.Reference) ([]storage.Reference, error) {
+	return r.resolveReferencesWith(ctx, registryCtx, r.storage.GetSchemaBySubjectVersion, subject, version, refs)
+}
+
+// schemaLookup fetches the schema registered under a subject version.
+type schemaLookup func(ctx context.Context, registryCtx string, subject string, version int) (*storage.SchemaRecord, error)
+
+// resolveReferencesWith is resolveReferencesFrom with a custom lookup, so
+// callers can resolve references to schemas that are not yet stored.
+func (r *Registry) resolveReferencesWith(ctx context.Context, registryCtx string, lookup schemaLookup, subject string, version int, refs []storage.Reference) ([]storage.Reference, error) {
 	if len(refs) == 0 {
 		return refs, nil
 	}
@@ -1551,24 +2116,51 @@ func (r *Registry) resolveReferences(ctx context.Context, registryCtx string, re
 	seen := make(map[string]bool)
 	var resolved []storage.Reference
 
+	// path holds the chain of subject versions currently being resolved,
+	// starting with the root when it is known.
+	var path []string
+	onPath := make(map[string]bool)
+	rootDepth := 0
+	if subject != "" && version > 0 {
+		root := referenceKey(subject, version)
+		path = append(path, root)
+		onPath[root] = true
+		rootDepth = 1
+	}
+
 	var resolve func(refs []storage.Reference) error
 	resolve = func(refs []storage.Reference) error {
 		for _, ref := range refs {
-			key := fmt.Sprintf("%s:%d", ref.Subject, ref.Version)
+			key := referenceKey(ref.Subject, ref.Version)
+			if onPath[key] {
+				cycle := append(append([]string{}, path[slices.Index(path, key):]...), key)
+				return fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(cycle, " -> "))
+			}
 			if seen[key] {
 				continue
 			}
 			seen[key] = true
 
-			record, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, ref.Subject, ref.Version)
+			chain := strings.Join(append(append([]string{}, path...), key), " -> ")
+			if depth := len(path) - rootDepth + 1; depth > r.maxReferenceDepth {
+				return fmt.Errorf("%w: %s is %d references deep, the maximum is %d",
+					ErrReferenceDepthExceeded, chain, depth, r.maxReferenceDepth)
+			}
+
+			record, err := lookup(ctx, registryCtx, ref.Subject, ref.Version)
 			if err != nil {
-				return fmt.Errorf("failed to resolve reference %q (subject=%s, version=%d): %w",
-					ref.Name, ref.Subject, ref.Version, err)
+				return fmt.Errorf("failed to resolve reference %q (subject=%s, version=%d, chain %s): %w",
+					ref.Name, ref.Subject, ref.Version, chain, err)
 			}
 
 			// Recursively resolve this record's own references FIRST
 			if len(record.References) > 0 {
-				if err := resolve(record.References); err != nil {
+				path = append(path, key)
+				onPath[key] = true
+				err := resolve(record.References)
+				delete(onPath, key)
+				path = path[:len(path)-1]
+				if err != nil {
 					return err
 				}
 			}
@@ -1589,6 +2181,11 @@ func (r *Registry) resolveReferences(ctx context.Context, registryCtx string, re
 	return resolved, nil
 }
 
+// referenceKey formats a subject version for reference tracking and cycle paths.
+func referenceKey(subject string, version int) string {
+	return fmt.Sprintf("%s:%d", subject, version)
+}
+
 // metadataEqual compares two Metadata pointers for equality.
 // Both nil = equal. One nil, one non-nil = not equal (unless non-nil is empty).
 func metadataEqual(a, b *storage.Metadata) bool {
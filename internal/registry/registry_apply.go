@@ -0,0 +1,370 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Changes Apply can make.
+const (
+	ApplyCreateContext    = "create_context"
+	ApplySetCompatibility = "set_compatibility"
+	ApplySetMode          = "set_mode"
+	ApplyRegisterSchema   = "register_schema"
+)
+
+// DesiredState is the declarative state of a registry that Apply reconciles
+// it with. Contexts, subjects and settings it does not mention are left
+// alone.
+type DesiredState struct {
+	Contexts []DesiredContext
+}
+
+// DesiredContext is the desired state of one context. An empty Name is the
+// default context. Empty settings are left as they are.
+type DesiredContext struct {
+	Name          string
+	Description   string // Only used when the context is created
+	Compatibility string
+	Mode          string
+	Subjects      []DesiredSubject
+}
+
+// DesiredSubject is the desired state of one subject. Schemas are the
+// versions the subject must contain, oldest first; those not yet registered
+// are registered in order.
+type DesiredSubject struct {
+	Name          string
+	Compatibility string
+	Mode          string
+	Schemas       []DesiredSchema
+}
+
+// DesiredSchema is one schema a subject must contain.
+type DesiredSchema struct {
+	Schema     string
+	SchemaType storage.SchemaType
+	References []storage.Reference
+}
+
+// ApplyChange is one change between the registry and a desired state. From
+// and To are the old and new compatibility level or mode; From is empty when
+// the setting is inherited. For a registration, Version and ID are those the
+// schema was given or, in a plan, would likely be given.
+type ApplyChange struct {
+	Action  string
+	Context string
+	Subject string
+	From    string
+	To      string
+	Version int
+	ID      int64
+	Applied bool
+	Error   string
+}
+
+// ApplyResult is the plan of an Apply and, unless it was a dry run, the
+// outcome of each change.
+type ApplyResult struct {
+	DryRun  bool
+	Changes []ApplyChange
+}
+
+// Failed reports whether a change could not be applied or, in a dry run,
+// is expected to fail.
+func (res *ApplyResult) Failed() bool {
+	for _, c := range res.Changes {
+		if c.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyStep is a planned change and how to make it.
+type applyStep struct {
+	change ApplyChange
+	run    func(ctx context.Context, change *ApplyChange) error
+}
+
+// Apply reconciles the registry with a desired state. It compares the state
+// with the registry and plans only the changes needed: contexts to create,
+// compatibility levels and modes to set, and schemas to register. A
+// compatibility level is set before the subject's schemas are registered,
+// and a mode before them when it allows writes or after them otherwise.
+//
+// With dryRun the plan is returned without changing anything; registrations
+// are checked against the registry as it is, so one that depends on an
+// earlier change in the plan may be reported as failing. Otherwise the
+// changes are made in order and Apply stops at the first that fails. The
+// state is validated first and ErrInvalidDesiredState is returned if it is
+// malformed.
+func (r *Registry) Apply(ctx context.Context, state *DesiredState, dryRun bool) (*ApplyResult, error) {
+	if err := normalizeDesiredState(state); err != nil {
+		return nil, err
+	}
+
+	var steps []applyStep
+	for _, dc := range state.Contexts {
+		contextSteps, err := r.planContext(ctx, dc, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, contextSteps...)
+	}
+
+	result := &ApplyResult{DryRun: dryRun, Changes: make([]ApplyChange, len(steps))}
+	for i := range steps {
+		result.Changes[i] = steps[i].change
+	}
+	if dryRun {
+		return result, nil
+	}
+	for i, step := range steps {
+		change := &result.Changes[i]
+		if err := step.run(ctx, change); err != nil {
+			change.Error = err.Error()
+			break
+		}
+		change.Applied = true
+	}
+	return result, nil
+}
+
+// planContext plans the changes to one context and its subjects.
+func (r *Registry) planContext(ctx context.Context, dc DesiredContext, dryRun bool) ([]applyStep, error) {
+	var steps []applyStep
+	name := dc.Name
+
+	if name != registrycontext.DefaultContext {
+		_, err := r.storage.GetContext(ctx, name)
+		switch {
+		case errors.Is(err, storage.ErrContextNotFound):
+			record := &storage.ContextRecord{Name: name, Description: dc.Description}
+			steps = append(steps, applyStep{
+				change: ApplyChange{Action: ApplyCreateContext, Context: name},
+				run: func(ctx context.Context, _ *ApplyChange) error {
+					return r.CreateContext(ctx, record)
+				},
+			})
+		case err != nil:
+			return nil, fmt.Errorf("failed to get context %s: %w", name, err)
+		}
+	}
+
+	compat, err := r.planCompatibility(ctx, name, "", dc.Compatibility)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, compat...)
+	modeBefore, modeAfter, err := r.planMode(ctx, name, "", dc.Mode)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, modeBefore...)
+
+	for _, ds := range dc.Subjects {
+		compat, err := r.planCompatibility(ctx, name, ds.Name, ds.Compatibility)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, compat...)
+		before, after, err := r.planMode(ctx, name, ds.Name, ds.Mode)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, before...)
+		for _, schema := range ds.Schemas {
+			step, ok := r.planRegistration(ctx, name, ds.Name, schema, dryRun)
+			if ok {
+				steps = append(steps, step)
+			}
+		}
+		steps = append(steps, after...)
+	}
+
+	return append(steps, modeAfter...), nil
+}
+
+// planCompatibility plans setting the compatibility level of a subject, or
+// of the context when subject is empty, unless it is already set to level.
+func (r *Registry) planCompatibility(ctx context.Context, registryCtx, subject, level string) ([]applyStep, error) {
+	if level == "" {
+		return nil, nil
+	}
+	var current *storage.ConfigRecord
+	var err error
+	if subject == "" {
+		current, err = r.storage.GetGlobalConfig(ctx, registryCtx)
+	} else {
+		current, err = r.storage.GetConfig(ctx, registryCtx, subject)
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	var from string
+	if current != nil {
+		from = current.CompatibilityLevel
+	}
+	if from == level {
+		return nil, nil
+	}
+	return []applyStep{{
+		change: ApplyChange{Action: ApplySetCompatibility, Context: registryCtx, Subject: subject, From: from, To: level},
+		run: func(ctx context.Context, _ *ApplyChange) error {
+			if current == nil {
+				return r.SetConfig(ctx, registryCtx, subject, level, nil)
+			}
+			// Keep the rest of the config, such as rule sets and metadata.
+			return r.SetConfig(ctx, registryCtx, subject, level, current.Normalize, SetConfigOpts{
+				Alias:               current.Alias,
+				CompatibilityGroup:  current.CompatibilityGroup,
+				ValidateFields:      current.ValidateFields,
+				DefaultMetadata:     current.DefaultMetadata,
+				OverrideMetadata:    current.OverrideMetadata,
+				DefaultRuleSet:      current.DefaultRuleSet,
+				OverrideRuleSet:     current.OverrideRuleSet,
+				AliasForDeks:        current.AliasForDeks,
+				CompatibilityPolicy: current.CompatibilityPolicy,
+			})
+		},
+	}}, nil
+}
+
+// planMode plans setting the mode of a subject, or of the context when
+// subject is empty, unless it is already set to mode. The step is returned
+// as before when the mode allows writes, so that registrations can follow
+// it, and as after otherwise.
+func (r *Registry) planMode(ctx context.Context, registryCtx, subject, mode string) (before, after []applyStep, err error) {
+	if mode == "" {
+		return nil, nil, nil
+	}
+	var current *storage.ModeRecord
+	if subject == "" {
+		current, err = r.storage.GetGlobalMode(ctx, registryCtx)
+	} else {
+		current, err = r.storage.GetMode(ctx, registryCtx, subject)
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("failed to get mode: %w", err)
+	}
+	var from string
+	if current != nil {
+		from = current.Mode
+	}
+	if from == mode {
+		return nil, nil, nil
+	}
+	step := []applyStep{{
+		change: ApplyChange{Action: ApplySetMode, Context: registryCtx, Subject: subject, From: from, To: mode},
+		run: func(ctx context.Context, _ *ApplyChange) error {
+			return r.SetMode(ctx, registryCtx, subject, mode, false)
+		},
+	}}
+	if mode == "READONLY" || mode == "READONLY_OVERRIDE" {
+		return nil, step, nil
+	}
+	return step, nil, nil
+}
+
+// planRegistration plans registering a schema under a subject. It returns
+// false when the subject already contains the schema. A check that fails is
+// only reported in a dry run: when the plan is applied, an earlier change,
+// such as registering a referenced schema, may make it pass.
+func (r *Registry) planRegistration(ctx context.Context, registryCtx, subject string, schema DesiredSchema, dryRun bool) (applyStep, bool) {
+	step := applyStep{
+		change: ApplyChange{Action: ApplyRegisterSchema, Context: registryCtx, Subject: subject},
+		run: func(ctx context.Context, change *ApplyChange) error {
+			blocking, err := r.CheckModeForWrite(ctx, registryCtx, subject)
+			if err != nil {
+				return err
+			}
+			if blocking != "" {
+				return fmt.Errorf("subject %s is in %s mode: %w", subject, blocking, storage.ErrOperationNotPermitted)
+			}
+			record, err := r.RegisterSchema(ctx, registryCtx, subject, schema.Schema, schema.SchemaType, schema.References)
+			if err != nil {
+				return err
+			}
+			change.Version = record.Version
+			change.ID = record.ID
+			return nil
+		},
+	}
+
+	record, created, err := r.PlanRegistration(ctx, registryCtx, subject, schema.Schema, schema.SchemaType, schema.References, RegisterOpts{})
+	switch {
+	case err == nil && !created:
+		return step, false
+	case err == nil:
+		step.change.Version = record.Version
+		step.change.ID = record.ID
+	case dryRun:
+		step.change.Error = err.Error()
+	}
+	return step, true
+}
+
+// normalizeDesiredState checks a desired state and puts its names and
+// settings in canonical form.
+func normalizeDesiredState(state *DesiredState) error {
+	contexts := make(map[string]bool, len(state.Contexts))
+	for i := range state.Contexts {
+		dc := &state.Contexts[i]
+		dc.Name = registrycontext.NormalizeContextName(dc.Name)
+		if dc.Name == "" {
+			dc.Name = registrycontext.DefaultContext
+		}
+		if !registrycontext.IsValidContextName(dc.Name) || registrycontext.IsGlobalContext(dc.Name) {
+			return fmt.Errorf("%w: invalid context name %q", ErrInvalidDesiredState, dc.Name)
+		}
+		if contexts[dc.Name] {
+			return fmt.Errorf("%w: context %s is listed more than once", ErrInvalidDesiredState, dc.Name)
+		}
+		contexts[dc.Name] = true
+		if err := normalizeDesiredSettings(&dc.Compatibility, &dc.Mode, "context "+dc.Name); err != nil {
+			return err
+		}
+
+		subjects := make(map[string]bool, len(dc.Subjects))
+		for j := range dc.Subjects {
+			ds := &dc.Subjects[j]
+			if ds.Name == "" {
+				return fmt.Errorf("%w: subject without a name in context %s", ErrInvalidDesiredState, dc.Name)
+			}
+			if subjects[ds.Name] {
+				return fmt.Errorf("%w: subject %s is listed more than once in context %s", ErrInvalidDesiredState, ds.Name, dc.Name)
+			}
+			subjects[ds.Name] = true
+			if err := normalizeDesiredSettings(&ds.Compatibility, &ds.Mode, "subject "+ds.Name); err != nil {
+				return err
+			}
+			for k := range ds.Schemas {
+				if strings.TrimSpace(ds.Schemas[k].Schema) == "" {
+					return fmt.Errorf("%w: schema %d of subject %s is empty", ErrInvalidDesiredState, k+1, ds.Name)
+				}
+				ds.Schemas[k].SchemaType = schemaTypeOrDefault(storage.SchemaType(strings.ToUpper(string(ds.Schemas[k].SchemaType))))
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeDesiredSettings upper-cases a compatibility level and mode and
+// checks that they are valid.
+func normalizeDesiredSettings(compatibility, mode *string, owner string) error {
+	*compatibility = strings.ToUpper(*compatibility)
+	if *compatibility != "" && !isValidCompatibility(*compatibility) {
+		return fmt.Errorf("%w: invalid compatibility level %q for %s", ErrInvalidDesiredState, *compatibility, owner)
+	}
+	*mode = strings.ToUpper(*mode)
+	if *mode != "" && !isValidMode(*mode) {
+		return fmt.Errorf("%w: invalid mode %q for %s", ErrInvalidDesiredState, *mode, owner)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// BundleFile is one schema in a bundle, stored under Path.
+type BundleFile struct {
+	Path       string
+	Subject    string
+	Version    int
+	ID         int64
+	SchemaType string
+	Content    string
+}
+
+// SchemaBundle is a schema together with all of its transitive references.
+// The requested schema is always the first file.
+type SchemaBundle struct {
+	Subject string
+	Version int
+	Files   []BundleFile
+}
+
+// GetSchemaBundle collects a subject version and every schema it references,
+// directly or transitively, as files named so that the schema toolchain can
+// resolve them from a single directory:
+//
+//   - Protobuf files use their import path (the reference name).
+//   - Avro files are named after the record's full name with an .avsc suffix.
+//   - JSON Schema files use their $ref (the reference name).
+//
+// The requested schema is named after its subject when its type gives it no
+// better name. If two schemas map to the same path, the first one wins.
+func (r *Registry) GetSchemaBundle(ctx context.Context, registryCtx string, subject string, version int) (*SchemaBundle, error) {
+	root, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &SchemaBundle{Subject: subject, Version: root.Version}
+	used := make(map[string]bool)
+	add := func(name string, rec *storage.SchemaRecord, subj string) {
+		p := bundleFilePath(name, rec, subj)
+		if used[p] {
+			return
+		}
+		used[p] = true
+		bundle.Files = append(bundle.Files, BundleFile{
+			Path:       p,
+			Subject:    subj,
+			Version:    rec.Version,
+			ID:         rec.ID,
+			SchemaType: string(schemaTypeOrDefault(rec.SchemaType)),
+			Content:    rec.Schema,
+		})
+	}
+	add("", root, subject)
+
+	seen := map[string]bool{referenceKey(subject, root.Version): true}
+	chain := []string{referenceKey(subject, root.Version)}
+	onPath := map[string]bool{chain[0]: true}
+
+	var walk func(refs []storage.Reference) error
+	walk = func(refs []storage.Reference) error {
+		for _, ref := range refs {
+			key := referenceKey(ref.Subject, ref.Version)
+			if onPath[key] {
+				cycle := append(append([]string{}, chain[slices.Index(chain, key):]...), key)
+				return fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(cycle, " -> "))
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			rec, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, ref.Subject, ref.Version)
+			if err != nil {
+				return fmt.Errorf("failed to resolve reference %q (subject=%s, version=%d): %w",
+					ref.Name, ref.Subject, ref.Version, err)
+			}
+			add(ref.Name, rec, ref.Subject)
+
+			chain = append(chain, key)
+			onPath[key] = true
+			err = walk(rec.References)
+			delete(onPath, key)
+			chain = chain[:len(chain)-1]
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root.References); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// bundleFilePath chooses the file name for a schema in a bundle. refName is
+// the name the schema was referenced by, or empty for the requested schema.
+func bundleFilePath(refName string, rec *storage.SchemaRecord, subject string) string {
+	switch schemaTypeOrDefault(rec.SchemaType) {
+	case storage.SchemaTypeAvro:
+		if name := avroFullName(rec.Schema); name != "" {
+			return safeBundlePath(name+".avsc", subject+".avsc")
+		}
+		return safeBundlePath(refName, subject+".avsc")
+	case storage.SchemaTypeProtobuf:
+		return safeBundlePath(refName, withExtension(subject, ".proto"))
+	default:
+		// Drop the scheme and any fragment from $ref URLs.
+		if u, err := url.Parse(refName); err == nil {
+			refName = u.Host + u.Path
+		}
+		return safeBundlePath(refName, withExtension(subject, ".json"))
+	}
+}
+
+// avroFullName returns the full name of a named Avro schema, or an empty
+// string for unnamed schemas such as unions and primitives.
+func avroFullName(schema string) string {
+	var named struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	}
+	if err := json.Unmarshal([]byte(schema), &named); err != nil || named.Name == "" {
+		return ""
+	}
+	if strings.Contains(named.Name, ".") || named.Namespace == "" {
+		return named.Name
+	}
+	return named.Namespace + "." + named.Name
+}
+
+// safeBundlePath cleans a file path so it stays inside the bundle root,
+// falling back to the given name when nothing usable remains.
+func safeBundlePath(p, fallback string) string {
+	if cleaned := strings.TrimPrefix(path.Clean("/"+p), "/"); cleaned != "" {
+		return cleaned
+	}
+	return strings.TrimPrefix(path.Clean("/"+fallback), "/")
+}
+
+// CleanBundlePath normalizes a file path within a bundle archive, so that
+// "./a.avsc", "/a.avsc" and "a.avsc" all name the same file.
+func CleanBundlePath(p string) string {
+	return safeBundlePath(p, "")
+}
+
+// withExtension appends ext to name unless it already ends with it.
+func withExtension(name, ext string) string {
+	if strings.HasSuffix(name, ext) {
+		return name
+	}
+	return name + ext
+}
+
+// schemaTypeOrDefault returns the schema type, treating an empty type as Avro.
+func schemaTypeOrDefault(t storage.SchemaType) storage.SchemaType {
+	if t == "" {
+		return storage.SchemaTypeAvro
+	}
+	return t
+}
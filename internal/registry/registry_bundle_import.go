@@ -0,0 +1,215 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// ImportBundle imports a set of schemas with preserved IDs as a single unit.
+// Unlike ImportSchemas, every schema is validated before anything is written,
+// and references may point at other schemas in the same bundle regardless of
+// their order. If any schema is invalid, nothing is imported and the result
+// reports the failing entries. If a write fails part way through, the schemas
+// already written are removed again before the error is returned.
+func (r *Registry) ImportBundle(ctx context.Context, registryCtx string, schemas []ImportSchemaRequest) (*ImportResult, error) {
+	result := &ImportResult{
+		Results: make([]ImportSchemaResult, len(schemas)),
+	}
+
+	// Index the bundle so references between bundle entries resolve
+	// before they are stored.
+	pending := make(map[string]*storage.SchemaRecord, len(schemas))
+	byID := make(map[int64]string, len(schemas))
+	for i, req := range schemas {
+		result.Results[i] = ImportSchemaResult{ID: req.ID, Subject: req.Subject, Version: req.Version}
+		key := referenceKey(req.Subject, req.Version)
+		if _, dup := pending[key]; dup {
+			result.Results[i].Error = "duplicate subject/version in bundle"
+			continue
+		}
+		pending[key] = &storage.SchemaRecord{
+			ID:         req.ID,
+			Subject:    req.Subject,
+			Version:    req.Version,
+			SchemaType: schemaTypeOrDefault(req.SchemaType),
+			Schema:     req.Schema,
+			References: req.References,
+		}
+	}
+	lookup := func(ctx context.Context, registryCtx string, subject string, version int) (*storage.SchemaRecord, error) {
+		if rec, ok := pending[referenceKey(subject, version)]; ok {
+			return rec, nil
+		}
+		return r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	}
+
+	records := make([]*storage.SchemaRecord, len(schemas))
+	for i, req := range schemas {
+		res := &result.Results[i]
+		if res.Error == "" {
+			records[i], res.Error = r.validateBundleEntry(ctx, registryCtx, req, lookup)
+		}
+		if res.Error == "" {
+			// The same ID may be shared by identical schemas only.
+			key := referenceKey(req.Subject, req.Version)
+			if other, ok := byID[req.ID]; ok && pending[other].Fingerprint != records[i].Fingerprint {
+				res.Error = "schema ID used for different schemas in bundle"
+			} else if !ok {
+				byID[req.ID] = key
+			}
+			pending[key].Fingerprint = records[i].Fingerprint
+		}
+		if res.Error != "" {
+			result.Errors++
+		}
+	}
+	if err := r.checkBundleQuota(ctx, registryCtx, schemas, result); err != nil {
+		r.recordImports(schemas, nil)
+		return nil, err
+	}
+	if result.Errors > 0 {
+		r.recordImports(schemas, result)
+		return result, nil
+	}
+
+	var maxID int64
+	for i, record := range records {
+		if err := r.storage.ImportSchema(ctx, registryCtx, record); err != nil {
+			r.rollbackBundle(ctx, registryCtx, records[:i])
+			r.recordImports(schemas, nil)
+			return nil, fmt.Errorf("failed to import %s version %d: %w", record.Subject, record.Version, err)
+		}
+		if record.ID > maxID {
+			maxID = record.ID
+		}
+	}
+	for i := range result.Results {
+		result.Results[i].Success = true
+	}
+	result.Imported = len(records)
+	r.recordImports(schemas, result)
+
+	// Adjust the ID sequence to prevent conflicts, never rewinding it.
+	if maxID > 0 {
+		nextID := maxID + 1
+		currentMax, err := r.storage.GetMaxSchemaID(ctx, registryCtx)
+		if err == nil && currentMax+1 > nextID {
+			nextID = currentMax + 1
+		}
+		if err := r.storage.SetNextID(ctx, registryCtx, nextID); err != nil {
+			return result, fmt.Errorf("imported %d schemas but failed to adjust ID sequence: %w", result.Imported, err)
+		}
+	}
+
+	return result, nil
+}
+
+// validateBundleEntry checks a single bundle entry against the bundle and the
+// existing registry contents and builds its schema record. It returns an
+// error message rather than an error so it can be reported per entry.
+func (r *Registry) validateBundleEntry(ctx context.Context, registryCtx string, req ImportSchemaRequest, lookup schemaLookup) (*storage.SchemaRecord, string) {
+	switch {
+	case req.ID <= 0:
+		return nil, "schema ID must be positive"
+	case req.Subject == "":
+		return nil, "subject is required"
+	case req.Version <= 0:
+		return nil, "version must be positive"
+	case req.Schema == "":
+		return nil, "schema is required"
+	}
+
+	schemaType := schemaTypeOrDefault(req.SchemaType)
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return nil, fmt.Sprintf("unsupported schema type: %s", schemaType)
+	}
+	resolvedRefs, err := r.resolveReferencesWith(ctx, registryCtx, lookup, req.Subject, req.Version, req.References)
+	if err != nil {
+		return nil, fmt.Sprintf("failed to resolve references: %v", err)
+	}
+	parsed, err := parser.Parse(req.Schema, resolvedRefs)
+	if err != nil {
+		return nil, fmt.Sprintf("invalid schema: %v", err)
+	}
+	record := &storage.SchemaRecord{
+		ID:          req.ID,
+		Subject:     req.Subject,
+		Version:     req.Version,
+		SchemaType:  schemaType,
+		Schema:      req.Schema,
+		References:  req.References,
+		Fingerprint: computeGlobalFingerprint(parsed.Fingerprint(), req.References),
+	}
+
+	if _, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, req.Subject, req.Version); err == nil {
+		return nil, "subject/version already exists"
+	} else if !errors.Is(err, storage.ErrSubjectNotFound) && !errors.Is(err, storage.ErrVersionNotFound) {
+		return nil, err.Error()
+	}
+	if existing, err := r.storage.GetSchemaByID(ctx, registryCtx, req.ID); err == nil {
+		if existing.Fingerprint != record.Fingerprint {
+			return nil, "schema ID already exists"
+		}
+	} else if !errors.Is(err, storage.ErrSchemaNotFound) {
+		return nil, err.Error()
+	}
+	if err := r.checkGlobalIDConflict(ctx, registryCtx, req.ID, record.Fingerprint); errors.Is(err, storage.ErrSchemaIDConflict) {
+		return nil, "schema ID already exists"
+	} else if err != nil {
+		return nil, err.Error()
+	}
+	return record, ""
+}
+
+// checkBundleQuota enforces the per-context limits on a bundle before any of
+// it is written, reporting the entries that do not fit. New subjects count
+// against the subject limit in bundle order.
+func (r *Registry) checkBundleQuota(ctx context.Context, registryCtx string, schemas []ImportSchemaRequest, result *ImportResult) error {
+	settings, err := r.contextLimits(ctx, registryCtx)
+	if err != nil {
+		return err
+	}
+	added := make(map[string]bool)
+	for i, req := range schemas {
+		res := &result.Results[i]
+		if res.Error != "" {
+			continue
+		}
+		if err := checkContextSchemaSize(registryCtx, settings, req.Schema); err != nil {
+			res.Error = err.Error()
+			result.Errors++
+			continue
+		}
+		if added[req.Subject] {
+			continue
+		}
+		isNew, err := r.checkContextSubjects(ctx, registryCtx, settings, req.Subject, len(added))
+		if errors.Is(err, ErrContextQuotaExceeded) {
+			res.Error = err.Error()
+			result.Errors++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if isNew {
+			added[req.Subject] = true
+		}
+	}
+	return nil
+}
+
+// rollbackBundle removes schemas written by a failed bundle import. Storage
+// only permanently deletes soft-deleted versions, so each is soft-deleted
+// first. Errors are ignored: the import has already failed.
+func (r *Registry) rollbackBundle(ctx context.Context, registryCtx string, records []*storage.SchemaRecord) {
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		_ = r.storage.DeleteSchema(ctx, registryCtx, rec.Subject, rec.Version, false)
+		_ = r.storage.DeleteSchema(ctx, registryCtx, rec.Subject, rec.Version, true)
+	}
+}
@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SubjectCatalogOptions selects and pages the subjects returned by
+// ListSubjectCatalog, and what is loaded for each of them.
+type SubjectCatalogOptions struct {
+	Prefix  string // Only subjects starting with Prefix
+	Deleted bool   // Include soft-deleted subjects
+	Offset  int
+	Limit   int // Non-positive means no limit
+
+	IncludeLatest bool // Load each subject's latest schema
+	IncludeConfig bool // Resolve each subject's compatibility level
+	IncludeMode   bool // Resolve each subject's mode
+	IncludeMeta   bool // Load each subject's owner, description, contact and links
+}
+
+// SubjectCatalogEntry is a subject with the details requested for it.
+type SubjectCatalogEntry struct {
+	Subject            string
+	Latest             *storage.SchemaRecord      // nil when not requested or every version is deleted
+	CompatibilityLevel string                     // Effective level, after fallback to the context and global defaults
+	Mode               string                     // Effective mode, after fallback to the context and global defaults
+	Meta               *storage.SubjectMetaRecord // nil when not requested or none is stored
+}
+
+// SubjectCatalog is one page of the subject catalog. Total counts every
+// subject matching the options, across all pages.
+type SubjectCatalog struct {
+	Total    int
+	Subjects []SubjectCatalogEntry
+}
+
+// ListSubjectCatalog returns a page of a context's subjects together with
+// their latest schema, compatibility level, mode and meta, so a subject list can be
+// rendered without a request per subject. Details are only loaded for the
+// subjects on the requested page.
+func (r *Registry) ListSubjectCatalog(ctx context.Context, registryCtx string, opts SubjectCatalogOptions) (*SubjectCatalog, error) {
+	subjects, err := r.storage.ListSubjects(ctx, registryCtx, opts.Deleted)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Prefix != "" {
+		filtered := make([]string, 0, len(subjects))
+		for _, s := range subjects {
+			if strings.HasPrefix(s, opts.Prefix) {
+				filtered = append(filtered, s)
+			}
+		}
+		subjects = filtered
+	}
+
+	catalog := &SubjectCatalog{Total: len(subjects), Subjects: []SubjectCatalogEntry{}}
+	if opts.Offset > 0 {
+		if opts.Offset >= len(subjects) {
+			return catalog, nil
+		}
+		subjects = subjects[opts.Offset:]
+	}
+	if opts.Limit > 0 && opts.Limit < len(subjects) {
+		subjects = subjects[:opts.Limit]
+	}
+
+	for _, subject := range subjects {
+		entry := SubjectCatalogEntry{Subject: subject}
+		if opts.IncludeLatest {
+			latest, err := r.storage.GetLatestSchema(ctx, registryCtx, subject)
+			switch {
+			case err == nil:
+				entry.Latest = latest
+			case errors.Is(err, storage.ErrSubjectNotFound), errors.Is(err, storage.ErrVersionNotFound):
+				// Soft-deleted subjects have no latest version.
+			default:
+				return nil, err
+			}
+		}
+		if opts.IncludeConfig {
+			if entry.CompatibilityLevel, err = r.GetConfig(ctx, registryCtx, subject); err != nil {
+				return nil, err
+			}
+		}
+		if opts.IncludeMode {
+			if entry.Mode, err = r.GetMode(ctx, registryCtx, subject); err != nil {
+				return nil, err
+			}
+		}
+		if opts.IncludeMeta {
+			meta, err := r.storage.GetSubjectMeta(ctx, registryCtx, subject)
+			switch {
+			case err == nil:
+				entry.Meta = meta
+			case errors.Is(err, storage.ErrSubjectMetaNotFound):
+			default:
+				return nil, err
+			}
+		}
+		catalog.Subjects = append(catalog.Subjects, entry)
+	}
+	return catalog, nil
+}
@@ -0,0 +1,242 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Kinds of storage invariant violations reported by VerifyConsistency.
+const (
+	// FindingOrphanedReference is a schema referencing a subject version
+	// that does not exist, not even soft-deleted.
+	FindingOrphanedReference = "orphaned_reference"
+	// FindingFingerprintMismatch is a schema whose stored fingerprint is
+	// not the one computed from its content and references, so it is not
+	// deduplicated against identical registrations.
+	FindingFingerprintMismatch = "fingerprint_mismatch"
+	// FindingDuplicateVersion is a subject version stored more than once.
+	FindingDuplicateVersion = "duplicate_version"
+	// FindingIDSequenceBehind is an ID sequence whose next ID is not past
+	// the highest schema ID in use, so a registration would reuse an ID.
+	FindingIDSequenceBehind = "id_sequence_behind"
+)
+
+// ConsistencyFinding is one storage invariant violation.
+type ConsistencyFinding struct {
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject,omitempty"`
+	Version  int    `json:"version,omitempty"`
+	SchemaID int64  `json:"schema_id,omitempty"`
+	Message  string `json:"message"`
+	Repaired bool   `json:"repaired"`
+}
+
+// ConsistencyReport is the outcome of checking one context.
+type ConsistencyReport struct {
+	Context         string               `json:"context"`
+	SubjectsChecked int                  `json:"subjects_checked"`
+	VersionsChecked int                  `json:"versions_checked"`
+	Findings        []ConsistencyFinding `json:"findings"`
+}
+
+// Unrepaired returns the number of findings that were not repaired.
+func (c *ConsistencyReport) Unrepaired() int {
+	n := 0
+	for _, f := range c.Findings {
+		if !f.Repaired {
+			n++
+		}
+	}
+	return n
+}
+
+// VerifyConsistency scans every subject version of a context, including
+// soft-deleted ones, for storage invariant violations: references to
+// subject versions that do not exist, stored fingerprints that differ from
+// the recomputed ones, subject versions stored twice, and an ID sequence
+// that is not past the highest schema ID.
+//
+// With repair set, an ID sequence that is behind is advanced past the
+// highest ID. The other findings are only reported: fixing them means
+// choosing which schema content or version is the right one, which is left
+// to an operator.
+//
+// beforeSubject, if not nil, is called before each subject is checked so
+// callers can report progress or throttle the work; an error from it stops
+// the check and is returned.
+func (r *Registry) VerifyConsistency(ctx context.Context, registryCtx string, repair bool, beforeSubject func(ctx context.Context, done, total int) error) (*ConsistencyReport, error) {
+	report := &ConsistencyReport{Context: registryCtx, Findings: []ConsistencyFinding{}}
+
+	subjects, err := r.storage.ListSubjects(ctx, registryCtx, true)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(subjects)
+
+	// Versions of each subject, including soft-deleted ones, so that a
+	// reference to a deleted version is not reported as orphaned.
+	versions := make(map[string]map[int]bool)
+	records := make(map[string][]*storage.SchemaRecord, len(subjects))
+	var maxID int64
+	for _, subject := range subjects {
+		list, err := r.storage.GetSchemasBySubject(ctx, registryCtx, subject, true)
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records[subject] = list
+		versions[subject] = make(map[int]bool, len(list))
+		for _, rec := range list {
+			if versions[subject][rec.Version] {
+				report.Findings = append(report.Findings, ConsistencyFinding{
+					Kind:     FindingDuplicateVersion,
+					Subject:  subject,
+					Version:  rec.Version,
+					SchemaID: rec.ID,
+					Message:  fmt.Sprintf("version %d of subject %s is stored more than once", rec.Version, subject),
+				})
+			}
+			versions[subject][rec.Version] = true
+			if rec.ID > maxID {
+				maxID = rec.ID
+			}
+		}
+	}
+
+	// References are resolved from the scanned records, so that schemas
+	// referencing soft-deleted versions can be fingerprinted too.
+	lookup := func(_ context.Context, _ string, subject string, version int) (*storage.SchemaRecord, error) {
+		for _, rec := range records[subject] {
+			if rec.Version == version {
+				return rec, nil
+			}
+		}
+		return nil, storage.ErrVersionNotFound
+	}
+
+	for i, subject := range subjects {
+		if beforeSubject != nil {
+			if err := beforeSubject(ctx, i, len(subjects)); err != nil {
+				return nil, err
+			}
+		}
+		report.SubjectsChecked++
+		checked := make(map[int]bool)
+		for _, rec := range records[subject] {
+			if checked[rec.Version] {
+				// A duplicate, already reported.
+				continue
+			}
+			checked[rec.Version] = true
+			report.VersionsChecked++
+			orphaned := false
+			for _, ref := range rec.References {
+				if versions[ref.Subject][ref.Version] {
+					continue
+				}
+				orphaned = true
+				report.Findings = append(report.Findings, ConsistencyFinding{
+					Kind:     FindingOrphanedReference,
+					Subject:  subject,
+					Version:  rec.Version,
+					SchemaID: rec.ID,
+					Message:  fmt.Sprintf("reference %q points to subject %s version %d, which does not exist", ref.Name, ref.Subject, ref.Version),
+				})
+			}
+			if orphaned {
+				// The fingerprint cannot be recomputed without the
+				// referenced schemas.
+				continue
+			}
+			if msg := r.checkFingerprint(ctx, registryCtx, rec, lookup); msg != "" {
+				report.Findings = append(report.Findings, ConsistencyFinding{
+					Kind:     FindingFingerprintMismatch,
+					Subject:  subject,
+					Version:  rec.Version,
+					SchemaID: rec.ID,
+					Message:  msg,
+				})
+			}
+		}
+	}
+	if beforeSubject != nil {
+		if err := beforeSubject(ctx, len(subjects), len(subjects)); err != nil {
+			return nil, err
+		}
+	}
+
+	if maxID > 0 {
+		finding, err := r.checkIDSequence(ctx, registryCtx, maxID, repair)
+		if err != nil {
+			return nil, err
+		}
+		if finding != nil {
+			report.Findings = append(report.Findings, *finding)
+		}
+	}
+	return report, nil
+}
+
+// checkFingerprint recomputes the fingerprint of a stored schema and returns
+// why it does not match the stored one, or "" when it does. A schema stored
+// normalized has the fingerprint of its normalized form, so either form is
+// accepted. References are resolved with lookup.
+func (r *Registry) checkFingerprint(ctx context.Context, registryCtx string, rec *storage.SchemaRecord, lookup schemaLookup) string {
+	schemaType := rec.SchemaType
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		// Not a storage problem: this server cannot parse the type.
+		return ""
+	}
+	resolvedRefs, err := r.resolveReferencesWith(ctx, registryCtx, lookup, rec.Subject, rec.Version, rec.References)
+	if err != nil {
+		return fmt.Sprintf("fingerprint cannot be recomputed: failed to resolve references: %v", err)
+	}
+	parsed, err := parser.Parse(rec.Schema, resolvedRefs)
+	if err != nil {
+		return fmt.Sprintf("fingerprint cannot be recomputed: schema no longer parses: %v", err)
+	}
+	if computeGlobalFingerprint(parsed.Fingerprint(), rec.References) == rec.Fingerprint {
+		return ""
+	}
+	normalized := parsed.NormalizeWithProfile(r.NormalizationProfile(registryCtx))
+	if computeGlobalFingerprint(normalized.Fingerprint(), rec.References) == rec.Fingerprint {
+		return ""
+	}
+	return fmt.Sprintf("stored fingerprint %s does not match the fingerprint computed from the schema", rec.Fingerprint)
+}
+
+// checkIDSequence reports the context's ID sequence when its next ID is not
+// past maxID, and advances it when repair is set. With global IDs the
+// sequence is shared, and it must be past the highest ID of every context.
+func (r *Registry) checkIDSequence(ctx context.Context, registryCtx string, maxID int64, repair bool) (*ConsistencyFinding, error) {
+	next, err := r.storage.PeekNextID(ctx, registryCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ID sequence: %w", err)
+	}
+	if next > maxID {
+		return nil, nil
+	}
+	finding := &ConsistencyFinding{
+		Kind:     FindingIDSequenceBehind,
+		SchemaID: maxID,
+		Message:  fmt.Sprintf("next schema ID is %d but schema ID %d is already in use", next, maxID),
+	}
+	if repair {
+		if err := r.storage.SetNextID(ctx, registryCtx, maxID+1); err != nil {
+			return nil, fmt.Errorf("failed to advance ID sequence: %w", err)
+		}
+		finding.Repaired = true
+	}
+	return finding, nil
+}
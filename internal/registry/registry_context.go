@@ -0,0 +1,191 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// CreateContext explicitly creates a registry context with its metadata and limits.
+// The context name is normalized to its dot-prefixed form. A context created for
+// a tenant belongs to it for its whole life, so the tenant must already exist.
+func (r *Registry) CreateContext(ctx context.Context, record *storage.ContextRecord) error {
+	record.Name = registrycontext.NormalizeContextName(record.Name)
+	if !registrycontext.IsValidContextName(record.Name) {
+		return fmt.Errorf("invalid context name %q: %w", record.Name, ErrInvalidContext)
+	}
+	if registrycontext.IsGlobalContext(record.Name) {
+		return fmt.Errorf("context %s is reserved: %w", record.Name, ErrInvalidContext)
+	}
+	if err := validateContextLimits(record); err != nil {
+		return err
+	}
+	if record.Tenant != "" {
+		if _, err := r.storage.GetTenant(ctx, record.Tenant); err != nil {
+			return err
+		}
+	}
+	return r.storage.CreateContext(ctx, record)
+}
+
+// GetContext retrieves a registry context and its settings.
+func (r *Registry) GetContext(ctx context.Context, name string) (*storage.ContextRecord, error) {
+	return r.storage.GetContext(ctx, name)
+}
+
+// UpdateContextSettings replaces the metadata and limits of an existing context.
+func (r *Registry) UpdateContextSettings(ctx context.Context, record *storage.ContextRecord) error {
+	if err := validateContextLimits(record); err != nil {
+		return err
+	}
+	return r.storage.UpdateContext(ctx, record)
+}
+
+// DeleteContext deletes a registry context. The default and global contexts
+// cannot be deleted. Without cascade the context must contain no subjects
+// (including soft-deleted ones); with cascade every subject in the context is
+// permanently deleted first. Returns the subjects that were removed.
+func (r *Registry) DeleteContext(ctx context.Context, name string, cascade bool) ([]string, error) {
+	if name == registrycontext.DefaultContext || registrycontext.IsGlobalContext(name) {
+		return nil, fmt.Errorf("context %s: %w", name, ErrContextProtected)
+	}
+	if _, err := r.storage.GetContext(ctx, name); err != nil {
+		return nil, err
+	}
+
+	subjects, err := r.storage.ListSubjects(ctx, name, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(subjects) > 0 && !cascade {
+		return nil, fmt.Errorf("context %s contains %d subject(s): %w", name, len(subjects), ErrContextNotEmpty)
+	}
+	frozen, err := r.storage.ListFrozenVersions(ctx, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(frozen) > 0 {
+		return nil, fmt.Errorf("context %s has %d frozen version(s): %w", name, len(frozen), ErrVersionFrozen)
+	}
+
+	for _, subject := range subjects {
+		// Soft-delete first so the permanent delete precondition holds; a
+		// subject that is already soft-deleted reports ErrSubjectDeleted.
+		if _, err := r.storage.DeleteSubject(ctx, name, subject, false); err != nil &&
+			!errors.Is(err, storage.ErrSubjectDeleted) && !errors.Is(err, storage.ErrSubjectNotFound) {
+			return nil, fmt.Errorf("failed to delete subject %s: %w", subject, err)
+		}
+		if _, err := r.DeleteSubject(ctx, name, subject, true); err != nil && !errors.Is(err, storage.ErrSubjectNotFound) {
+			return nil, fmt.Errorf("failed to delete subject %s: %w", subject, err)
+		}
+	}
+
+	if err := r.storage.DeleteContext(ctx, name); err != nil {
+		return nil, err
+	}
+	return subjects, nil
+}
+
+// SetContextAutoCreate sets whether a write to a context that does not exist
+// creates it, as in Confluent Schema Registry. When disabled, writes to
+// contexts that were not created with CreateContext fail with
+// ErrContextNotCreated, except in the default context and the allowed
+// contexts, which are still created on write.
+func (r *Registry) SetContextAutoCreate(enabled bool, allowed []string) {
+	r.noContextAutoCreate = !enabled
+	r.autoCreateContexts = make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		r.autoCreateContexts[registrycontext.NormalizeContextName(name)] = true
+	}
+}
+
+// checkContextCreatable returns ErrContextNotCreated if a write to
+// registryCtx would create it and contexts are not created on write.
+func (r *Registry) checkContextCreatable(ctx context.Context, registryCtx string) error {
+	if !r.noContextAutoCreate || registryCtx == registrycontext.DefaultContext ||
+		registrycontext.IsGlobalContext(registryCtx) || r.autoCreateContexts[registryCtx] {
+		return nil
+	}
+	_, err := r.storage.GetContext(ctx, registryCtx)
+	if errors.Is(err, storage.ErrContextNotFound) {
+		return fmt.Errorf("context %s does not exist; create it first: %w", registryCtx, ErrContextNotCreated)
+	}
+	return err
+}
+
+// contextLimits returns the per-context limits configured for registryCtx.
+// A context that does not exist yet has none, so it is unlimited.
+func (r *Registry) contextLimits(ctx context.Context, registryCtx string) (*storage.ContextRecord, error) {
+	settings, err := r.storage.GetContext(ctx, registryCtx)
+	if errors.Is(err, storage.ErrContextNotFound) {
+		return &storage.ContextRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get context %s: %w", registryCtx, err)
+	}
+	return settings, nil
+}
+
+// checkContextQuota enforces the per-context limits configured for registryCtx.
+// Contexts without explicit settings (or that do not exist yet) are unlimited.
+func (r *Registry) checkContextQuota(ctx context.Context, registryCtx, subject, schemaStr string) error {
+	settings, err := r.contextLimits(ctx, registryCtx)
+	if err != nil {
+		return err
+	}
+	if err := checkContextSchemaSize(registryCtx, settings, schemaStr); err != nil {
+		return err
+	}
+	_, err = r.checkContextSubjects(ctx, registryCtx, settings, subject, 0)
+	return err
+}
+
+// checkContextSchemaSize returns ErrContextQuotaExceeded if schemaStr is
+// larger than the context allows.
+func checkContextSchemaSize(registryCtx string, settings *storage.ContextRecord, schemaStr string) error {
+	if settings.MaxSchemaSize > 0 && len(schemaStr) > settings.MaxSchemaSize {
+		return fmt.Errorf("schema size %d bytes exceeds the limit of %d bytes for context %s: %w",
+			len(schemaStr), settings.MaxSchemaSize, registryCtx, ErrContextQuotaExceeded)
+	}
+	return nil
+}
+
+// checkContextSubjects returns ErrContextQuotaExceeded if writing to subject
+// would create a subject the context has no room for, counting added
+// subjects that are about to be created alongside it. It reports whether
+// the subject is a new one.
+func (r *Registry) checkContextSubjects(ctx context.Context, registryCtx string, settings *storage.ContextRecord, subject string, added int) (bool, error) {
+	if settings.MaxSubjects <= 0 {
+		return false, nil
+	}
+	exists, err := r.storage.SubjectExists(ctx, registryCtx, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to check subject: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+	subjects, err := r.storage.ListSubjects(ctx, registryCtx, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to list subjects: %w", err)
+	}
+	if len(subjects)+added >= settings.MaxSubjects {
+		return false, fmt.Errorf("context %s already has the maximum of %d subjects: %w",
+			registryCtx, settings.MaxSubjects, ErrContextQuotaExceeded)
+	}
+	return true, nil
+}
+
+// validateContextLimits rejects negative per-context limits.
+func validateContextLimits(record *storage.ContextRecord) error {
+	if record.MaxSubjects < 0 {
+		return fmt.Errorf("maxSubjects must not be negative: %w", ErrInvalidContext)
+	}
+	if record.MaxSchemaSize < 0 {
+		return fmt.Errorf("maxSchemaSize must not be negative: %w", ErrInvalidContext)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Schema conversion targets.
+const (
+	// ConvertToJSONSchema describes an Avro or Protobuf schema's values as a
+	// JSON Schema. JSON Schemas convert to their canonical form.
+	ConvertToJSONSchema = "json"
+	// ConvertToCanonical returns the schema's canonical form.
+	ConvertToCanonical = "canonical"
+	// ConvertToPretty returns an Avro schema's canonical form, indented.
+	ConvertToPretty = "pretty"
+)
+
+// ConvertResult is a schema converted by ConvertSchema.
+type ConvertResult struct {
+	SchemaType storage.SchemaType
+	Schema     string
+}
+
+// ConvertSchema converts a schema to another representation. The target is
+// one of ConvertToJSONSchema, ConvertToCanonical and ConvertToPretty, in any
+// case. Returns ErrUnsupportedConversion for an unknown target, or one the
+// schema type cannot be converted to.
+func (r *Registry) ConvertSchema(ctx context.Context, registryCtx string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, target string) (*ConvertResult, error) {
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
+	}
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, err
+	}
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+	}
+	parsed, err := parser.Parse(schemaStr, resolvedRefs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
+	}
+
+	switch strings.ToLower(target) {
+	case ConvertToJSONSchema:
+		if schemaType == storage.SchemaTypeJSON {
+			return &ConvertResult{SchemaType: schemaType, Schema: parsed.CanonicalString()}, nil
+		}
+		converter, ok := parsed.(schema.JSONSchemaConverter)
+		if !ok {
+			break
+		}
+		converted, err := converter.ToJSONSchema()
+		if err != nil {
+			return nil, err
+		}
+		return &ConvertResult{SchemaType: storage.SchemaTypeJSON, Schema: converted}, nil
+	case ConvertToCanonical:
+		return &ConvertResult{SchemaType: schemaType, Schema: parsed.CanonicalString()}, nil
+	case ConvertToPretty:
+		if schemaType != storage.SchemaTypeAvro {
+			break
+		}
+		return &ConvertResult{SchemaType: schemaType, Schema: parsed.FormattedString(ConvertToPretty)}, nil
+	default:
+		return nil, fmt.Errorf("unknown conversion target %q: %w", target, ErrUnsupportedConversion)
+	}
+	return nil, fmt.Errorf("%s schemas cannot be converted to %s: %w", schemaType, target, ErrUnsupportedConversion)
+}
@@ -0,0 +1,170 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// DefaultDeleteConfirmationTTL is how long a delete confirmation token is
+// valid when no TTL is configured.
+const DefaultDeleteConfirmationTTL = 5 * time.Minute
+
+// DeleteConfirmation is the token a permanent delete must be repeated with
+// before it takes effect.
+type DeleteConfirmation struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// pendingDelete is the permanent delete a confirmation token was issued for.
+// A version of 0 stands for the whole subject, and an empty subject for the
+// cascading delete of the whole context.
+type pendingDelete struct {
+	registryCtx string
+	subject     string
+	version     int
+	expiresAt   time.Time
+}
+
+// SetDeleteConfirmation makes permanent deletes two-phase: the first request
+// only returns a token, and the delete happens when it is repeated with the
+// token within ttl. A ttl of 0 uses DefaultDeleteConfirmationTTL.
+func (r *Registry) SetDeleteConfirmation(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultDeleteConfirmationTTL
+	}
+	r.deleteConfirmMu.Lock()
+	defer r.deleteConfirmMu.Unlock()
+	r.deleteConfirmTTL = ttl
+	r.pendingDeletes = make(map[string]pendingDelete)
+}
+
+// RequiresDeleteConfirmation reports whether permanent deletes are two-phase.
+func (r *Registry) RequiresDeleteConfirmation() bool {
+	r.deleteConfirmMu.Lock()
+	defer r.deleteConfirmMu.Unlock()
+	return r.deleteConfirmTTL > 0
+}
+
+// IssueDeleteConfirmation checks that a subject, or one of its versions when
+// version is not 0, can be permanently deleted and returns the token that
+// ConfirmDelete needs. Version -1 is the latest soft-deleted version, as in
+// DeleteVersion. An empty subject stands for deleting the context with
+// every subject in it, as DeleteContext does with cascade. Tokens are kept
+// in memory by this instance only, so the confirmation must reach the
+// instance that issued the token.
+func (r *Registry) IssueDeleteConfirmation(ctx context.Context, registryCtx string, subject string, version int) (*DeleteConfirmation, error) {
+	resolved, err := r.resolvePermanentDelete(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, err
+	}
+	token, err := newDeleteConfirmationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	r.deleteConfirmMu.Lock()
+	defer r.deleteConfirmMu.Unlock()
+	now := time.Now()
+	for t, pending := range r.pendingDeletes {
+		if now.After(pending.expiresAt) {
+			delete(r.pendingDeletes, t)
+		}
+	}
+	expiresAt := now.Add(r.deleteConfirmTTL)
+	r.pendingDeletes[token] = pendingDelete{
+		registryCtx: registryCtx,
+		subject:     subject,
+		version:     resolved,
+		expiresAt:   expiresAt,
+	}
+	return &DeleteConfirmation{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// ConfirmDelete consumes a token from IssueDeleteConfirmation. It fails with
+// ErrInvalidDeleteConfirmation unless the token is unused, unexpired and was
+// issued for the same subject and version. A token is consumed by its first
+// use, whether or not that use succeeds.
+func (r *Registry) ConfirmDelete(ctx context.Context, registryCtx string, subject string, version int, token string) error {
+	r.deleteConfirmMu.Lock()
+	pending, ok := r.pendingDeletes[token]
+	delete(r.pendingDeletes, token)
+	r.deleteConfirmMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: unknown or already used token", ErrInvalidDeleteConfirmation)
+	}
+	if time.Now().After(pending.expiresAt) {
+		return fmt.Errorf("%w: token has expired", ErrInvalidDeleteConfirmation)
+	}
+	if version == -1 {
+		resolved, err := r.resolvePermanentDelete(ctx, registryCtx, subject, version)
+		if err != nil {
+			return err
+		}
+		version = resolved
+	}
+	if pending.registryCtx != registryCtx || pending.subject != subject || pending.version != version {
+		return fmt.Errorf("%w: token was issued for a different subject or version", ErrInvalidDeleteConfirmation)
+	}
+	return nil
+}
+
+// resolvePermanentDelete returns the version a permanent delete applies to,
+// or 0 for the whole subject, after checking that it is soft-deleted. For
+// an empty subject it checks that the context exists.
+func (r *Registry) resolvePermanentDelete(ctx context.Context, registryCtx string, subject string, version int) (int, error) {
+	if subject == "" {
+		if _, err := r.GetContext(ctx, registryCtx); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	schemas, err := r.storage.GetSchemasBySubject(ctx, registryCtx, subject, true)
+	if err != nil {
+		return 0, err
+	}
+	if len(schemas) == 0 {
+		return 0, storage.ErrSubjectNotFound
+	}
+	if version == 0 {
+		for _, s := range schemas {
+			if !s.Deleted {
+				return 0, storage.ErrSubjectNotSoftDeleted
+			}
+		}
+		return 0, nil
+	}
+	if version == -1 {
+		for _, s := range schemas {
+			if s.Deleted && s.Version > version {
+				version = s.Version
+			}
+		}
+		if version == -1 {
+			return 0, storage.ErrVersionNotFound
+		}
+	}
+	for _, s := range schemas {
+		if s.Version == version {
+			if !s.Deleted {
+				return 0, storage.ErrVersionNotSoftDeleted
+			}
+			return version, nil
+		}
+	}
+	return 0, storage.ErrVersionNotFound
+}
+
+func newDeleteConfirmationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate delete confirmation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
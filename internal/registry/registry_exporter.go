@@ -41,7 +41,9 @@ func (r *Registry) GetExporter(ctx context.Context, name string) (*storage.Expor
 	return r.storage.GetExporter(ctx, name)
 }
 
-// UpdateExporter updates an existing exporter.
+// UpdateExporter updates an existing exporter. As in Confluent's API, fields
+// left empty keep their current value, so a client can change the subjects
+// without sending the config again.
 func (r *Registry) UpdateExporter(ctx context.Context, exporter *storage.ExporterRecord) error {
 	if strings.TrimSpace(exporter.Name) == "" {
 		return fmt.Errorf("exporter name is required")
@@ -54,6 +56,26 @@ func (r *Registry) UpdateExporter(ctx context.Context, exporter *storage.Exporte
 		}
 	}
 
+	existing, err := r.storage.GetExporter(ctx, exporter.Name)
+	if err != nil {
+		return err
+	}
+	if exporter.ContextType == "" {
+		exporter.ContextType = existing.ContextType
+	}
+	if exporter.Context == "" {
+		exporter.Context = existing.Context
+	}
+	if exporter.Subjects == nil {
+		exporter.Subjects = existing.Subjects
+	}
+	if exporter.SubjectRenameFormat == "" {
+		exporter.SubjectRenameFormat = existing.SubjectRenameFormat
+	}
+	if exporter.Config == nil {
+		exporter.Config = existing.Config
+	}
+
 	return r.storage.UpdateExporter(ctx, exporter)
 }
 
@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"context"
+	"crypto/md5" // #nosec G501 -- MD5 fingerprints are offered for interoperability, not security
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Fingerprint algorithms of SchemaFingerprint.
+const (
+	// FingerprintSHA256 is the fingerprint schemas are stored and found by:
+	// the SHA-256 of the canonical form, combined with the references.
+	FingerprintSHA256 = "sha256"
+	// FingerprintMD5 is the MD5 of the canonical form, combined with the
+	// references.
+	FingerprintMD5 = "md5"
+	// FingerprintCRC64Avro is the CRC-64-AVRO (Rabin) fingerprint of an Avro
+	// schema's Parsing Canonical Form, which Avro single-object encoding
+	// identifies the writer schema by.
+	FingerprintCRC64Avro = "crc64-avro"
+)
+
+// FingerprintMatch is a stored schema found by its fingerprint, with every
+// subject version of the context that uses it.
+type FingerprintMatch struct {
+	Fingerprint string
+	Schema      *storage.SchemaRecord
+	Versions    []storage.SubjectVersion
+}
+
+// FindSchemaByFingerprint returns the schema of a context with the given
+// fingerprint and the subject versions that use it, whatever the subject.
+// Soft-deleted versions are only included with includeDeleted. Returns
+// storage.ErrSchemaNotFound if no schema has the fingerprint, or if every
+// version using it is soft-deleted and includeDeleted is false.
+func (r *Registry) FindSchemaByFingerprint(ctx context.Context, registryCtx, fingerprint string, includeDeleted bool) (*FingerprintMatch, error) {
+	record, err := r.storage.GetSchemaByGlobalFingerprint(ctx, registryCtx, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := r.storage.GetVersionsBySchemaID(ctx, registryCtx, record.ID, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, storage.ErrSchemaNotFound
+	}
+	return &FingerprintMatch{Fingerprint: fingerprint, Schema: record, Versions: versions}, nil
+}
+
+// LookupSchemaGlobal finds where a schema is registered in a context
+// without knowing the subject. The schema is fingerprinted as registered,
+// and normalized, since subjects that normalize store the fingerprint of
+// the normalized schema; each fingerprint that is found is one match.
+// Returns storage.ErrSchemaNotFound if neither is.
+func (r *Registry) LookupSchemaGlobal(ctx context.Context, registryCtx, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, includeDeleted bool) ([]*FingerprintMatch, error) {
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema type: %s: %w", schemaType, ErrUnsupportedSchemaType)
+	}
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, err
+	}
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+	}
+	parsed, err := parser.Parse(schemaStr, resolvedRefs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
+	}
+
+	fingerprints := []string{computeGlobalFingerprint(parsed.Fingerprint(), refs)}
+	normalized := computeGlobalFingerprint(parsed.NormalizeWithProfile(r.NormalizationProfile(registryCtx)).Fingerprint(), refs)
+	if normalized != fingerprints[0] {
+		fingerprints = append(fingerprints, normalized)
+	}
+
+	var matches []*FingerprintMatch
+	for _, fingerprint := range fingerprints {
+		match, err := r.FindSchemaByFingerprint(ctx, registryCtx, fingerprint, includeDeleted)
+		if errors.Is(err, storage.ErrSchemaNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	if len(matches) == 0 {
+		return nil, storage.ErrSchemaNotFound
+	}
+	return matches, nil
+}
+
+// SchemaFingerprint returns the fingerprint of a stored schema computed with
+// algorithm, in lowercase hex. The sha256 fingerprint is the stored one; the
+// others are computed from the schema parsed with its references. The
+// crc64-avro fingerprint is the 64-bit value, most significant byte first;
+// single-object encoding writes it least significant byte first. Returns
+// ErrUnsupportedFingerprint for an unknown algorithm, or for crc64-avro and
+// a schema that is not Avro.
+func (r *Registry) SchemaFingerprint(ctx context.Context, registryCtx string, record *storage.SchemaRecord, algorithm string) (string, error) {
+	schemaType := record.SchemaType
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	switch algorithm {
+	case FingerprintSHA256:
+		if record.Fingerprint != "" {
+			return record.Fingerprint, nil
+		}
+	case FingerprintMD5:
+	case FingerprintCRC64Avro:
+		if schemaType != storage.SchemaTypeAvro {
+			return "", fmt.Errorf("%w: %s applies to Avro schemas only", ErrUnsupportedFingerprint, algorithm)
+		}
+	default:
+		return "", fmt.Errorf("%w: %q, expected %s, %s or %s", ErrUnsupportedFingerprint, algorithm,
+			FingerprintMD5, FingerprintSHA256, FingerprintCRC64Avro)
+	}
+
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedSchemaType, schemaType)
+	}
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, record.References)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := parser.Parse(record.Schema, resolvedRefs)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	switch algorithm {
+	case FingerprintCRC64Avro:
+		fp, ok := parsed.(schema.ParsingFingerprinter)
+		if !ok {
+			return "", fmt.Errorf("%w: %s applies to Avro schemas only", ErrUnsupportedFingerprint, algorithm)
+		}
+		return fmt.Sprintf("%016x", fp.ParsingFingerprint64()), nil
+	case FingerprintMD5:
+		sum := md5.Sum([]byte(parsed.CanonicalString())) // #nosec G401 -- not used for security
+		return fingerprintWithReferences(md5.New, hex.EncodeToString(sum[:]), record.References), nil
+	default:
+		return computeGlobalFingerprint(parsed.Fingerprint(), record.References), nil
+	}
+}
@@ -0,0 +1,96 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// FreezeVersion protects a version of subject from soft and permanent
+// deletion until it is unfrozen. version -1 freezes the latest version. The
+// version must exist and not be soft-deleted; freezing a frozen version
+// replaces its record.
+func (r *Registry) FreezeVersion(ctx context.Context, registryCtx string, subject string, version int, frozenBy, reason string) (*storage.FrozenVersionRecord, error) {
+	schema, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, err
+	}
+	record := &storage.FrozenVersionRecord{
+		Subject:  subject,
+		Version:  schema.Version,
+		FrozenBy: frozenBy,
+		Reason:   reason,
+	}
+	if err := r.storage.FreezeVersion(ctx, registryCtx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetFrozenVersion returns the freeze of a version of subject. version -1
+// means the latest version.
+func (r *Registry) GetFrozenVersion(ctx context.Context, registryCtx string, subject string, version int) (*storage.FrozenVersionRecord, error) {
+	version, err := r.resolveFrozenVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, err
+	}
+	return r.storage.GetFrozenVersion(ctx, registryCtx, subject, version)
+}
+
+// UnfreezeVersion removes the freeze of a version of subject, allowing it to
+// be deleted again. version -1 means the latest version.
+func (r *Registry) UnfreezeVersion(ctx context.Context, registryCtx string, subject string, version int) (int, error) {
+	version, err := r.resolveFrozenVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.storage.UnfreezeVersion(ctx, registryCtx, subject, version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// ListFrozenVersions returns the frozen versions of subject, or of every
+// subject in the context when subject is empty.
+func (r *Registry) ListFrozenVersions(ctx context.Context, registryCtx string, subject string) ([]*storage.FrozenVersionRecord, error) {
+	return r.storage.ListFrozenVersions(ctx, registryCtx, subject)
+}
+
+// resolveFrozenVersion resolves "latest" (-1) to the latest version of subject.
+func (r *Registry) resolveFrozenVersion(ctx context.Context, registryCtx string, subject string, version int) (int, error) {
+	if version != -1 {
+		return version, nil
+	}
+	schema, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return 0, err
+	}
+	return schema.Version, nil
+}
+
+// checkVersionNotFrozen returns ErrVersionFrozen if the version is frozen.
+func (r *Registry) checkVersionNotFrozen(ctx context.Context, registryCtx string, subject string, version int) error {
+	_, err := r.storage.GetFrozenVersion(ctx, registryCtx, subject, version)
+	if err == nil {
+		return fmt.Errorf("version %d of subject %s is frozen and cannot be deleted: %w", version, subject, ErrVersionFrozen)
+	}
+	if errors.Is(err, storage.ErrFrozenVersionNotFound) {
+		return nil
+	}
+	return err
+}
+
+// checkSubjectNotFrozen returns ErrVersionFrozen if any version of subject is
+// frozen.
+func (r *Registry) checkSubjectNotFrozen(ctx context.Context, registryCtx string, subject string) error {
+	frozen, err := r.storage.ListFrozenVersions(ctx, registryCtx, subject)
+	if err != nil {
+		return err
+	}
+	if len(frozen) > 0 {
+		return fmt.Errorf("version %d of subject %s is frozen and cannot be deleted: %w", frozen[0].Version, subject, ErrVersionFrozen)
+	}
+	return nil
+}
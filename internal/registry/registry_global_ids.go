@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SetGlobalIDs sets whether schema IDs are global, i.e. allocated from one
+// sequence shared by all contexts. The storage backend must be configured to
+// allocate them that way; the registry resolves IDs across contexts and
+// rejects imports that would give an ID to two different schemas.
+func (r *Registry) SetGlobalIDs(enabled bool) {
+	r.globalIDs = enabled
+}
+
+// SchemaIDContext returns the context that holds the schema with the given
+// ID when it is looked up from registryCtx. With global IDs, an ID that is
+// not in registryCtx is looked up in the other contexts owned by the same
+// tenant, so that a serialized message can be resolved without knowing the
+// context its schema was registered in. Otherwise, and when no context holds
+// the ID, it returns registryCtx.
+func (r *Registry) SchemaIDContext(ctx context.Context, registryCtx string, id int64) (string, error) {
+	if !r.globalIDs {
+		return registryCtx, nil
+	}
+	if _, err := r.storage.GetSchemaByID(ctx, registryCtx, id); !errors.Is(err, storage.ErrSchemaNotFound) {
+		return registryCtx, err
+	}
+	holder, err := r.findSchemaIDElsewhere(ctx, registryCtx, id)
+	if err != nil || holder == "" {
+		return registryCtx, err
+	}
+	return holder, nil
+}
+
+// findSchemaIDElsewhere returns the first context other than registryCtx,
+// owned by the same tenant, that holds the schema with the given ID. It
+// returns an empty context when none does.
+func (r *Registry) findSchemaIDElsewhere(ctx context.Context, registryCtx string, id int64) (string, error) {
+	owner, err := r.ContextTenant(ctx, registryCtx)
+	if err != nil {
+		return "", err
+	}
+	contexts, err := r.ListContexts(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range contexts {
+		if name == registryCtx {
+			continue
+		}
+		if tenant, err := r.ContextTenant(ctx, name); err != nil || tenant != owner {
+			continue
+		}
+		_, err := r.storage.GetSchemaByID(ctx, name, id)
+		if errors.Is(err, storage.ErrSchemaNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	return "", nil
+}
+
+// checkGlobalIDConflict returns storage.ErrSchemaIDConflict when IDs are
+// global and a context other than registryCtx holds a different schema under
+// the ID being imported. Contexts of every tenant are checked, as they share
+// the sequence.
+func (r *Registry) checkGlobalIDConflict(ctx context.Context, registryCtx string, id int64, fingerprint string) error {
+	if !r.globalIDs {
+		return nil
+	}
+	contexts, err := r.ListContexts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range contexts {
+		if name == registryCtx {
+			continue
+		}
+		record, err := r.storage.GetSchemaByID(ctx, name, id)
+		if errors.Is(err, storage.ErrSchemaNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if record.Fingerprint != fingerprint {
+			return storage.ErrSchemaIDConflict
+		}
+	}
+	return nil
+}
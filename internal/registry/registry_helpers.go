@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"strconv"
 
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
 // CheckModeForWrite checks if the current mode allows write operations.
 // Returns the blocking mode name ("READONLY" or "READONLY_OVERRIDE") or empty
-// string if writes are allowed.
+// string if writes are allowed. While the write gate is closed it fails with
+// ErrWritesSuspended, which says why.
 func (r *Registry) CheckModeForWrite(ctx context.Context, registryCtx, subject string) (string, error) {
 	mode, err := r.GetMode(ctx, registryCtx, subject)
 	if err != nil {
@@ -19,9 +21,29 @@ func (r *Registry) CheckModeForWrite(ctx context.Context, registryCtx, subject s
 	if mode == "READONLY" || mode == "READONLY_OVERRIDE" {
 		return mode, nil
 	}
+	if r.writeGate != nil {
+		if reason := r.writeGate.Reason(); reason != "" {
+			return "", fmt.Errorf("%w while the storage backend could lose them: %s", ErrWritesSuspended, reason)
+		}
+	}
 	return "", nil
 }
 
+// WriteGate decides whether the storage backend is safe to write to.
+type WriteGate interface {
+	// Reason describes why writes must be refused, or returns "" while
+	// they are allowed.
+	Reason() string
+}
+
+// SetWriteGate makes writes that check the mode fail with
+// ErrWritesSuspended while gate refuses them, as if the registry were
+// READONLY. The stored modes are unchanged, so writes resume as soon as the
+// gate allows them again.
+func (r *Registry) SetWriteGate(gate WriteGate) {
+	r.writeGate = gate
+}
+
 // ResolveAlias resolves a subject alias. If the subject has an alias configured,
 // the alias target is returned. Otherwise the original subject is returned.
 // Alias resolution is single-level (no recursive chaining).
@@ -36,6 +58,64 @@ func (r *Registry) ResolveAlias(ctx context.Context, registryCtx, subject string
 	return subject
 }
 
+// Write policies for subject aliases: what registering a schema under, or
+// deleting, a subject that is an alias does.
+const (
+	// AliasWriteFollow applies the write to the alias target, as Confluent
+	// does.
+	AliasWriteFollow = "follow"
+	// AliasWriteIgnore applies the write to the aliased subject itself.
+	AliasWriteIgnore = "ignore"
+	// AliasWriteReject rejects the write with ErrSubjectAliased.
+	AliasWriteReject = "reject"
+)
+
+// SetAliasWritePolicy sets what writes to an aliased subject do. An empty
+// policy means AliasWriteFollow.
+func (r *Registry) SetAliasWritePolicy(policy string) {
+	r.aliasWritePolicy = policy
+}
+
+// ResolveWriteAlias resolves a subject alias for a write: a registration or
+// a delete. Under the follow policy it is ResolveAlias; under ignore it
+// returns the subject unchanged; under reject it returns ErrSubjectAliased
+// when the subject is an alias.
+func (r *Registry) ResolveWriteAlias(ctx context.Context, registryCtx, subject string) (string, error) {
+	switch r.aliasWritePolicy {
+	case AliasWriteIgnore:
+		return subject, nil
+	case AliasWriteReject:
+		if target := r.ResolveAlias(ctx, registryCtx, subject); target != subject {
+			return "", fmt.Errorf("subject %s is an alias for %s, write to %s instead: %w", subject, target, target, ErrSubjectAliased)
+		}
+		return subject, nil
+	default:
+		return r.ResolveAlias(ctx, registryCtx, subject), nil
+	}
+}
+
+// validateSubjectAlias checks the alias of a subject in registryCtx and
+// returns it in the form ResolveAlias returns: unqualified. The alias may
+// be qualified with the subject's own context but not with another one,
+// since permissions and tenant ownership are checked against the context of
+// the subject the request names.
+func validateSubjectAlias(registryCtx, subject, alias string) (string, error) {
+	if alias == "" {
+		return "", nil
+	}
+	aliasCtx, target := registrycontext.ResolveSubject(alias)
+	if aliasCtx != registrycontext.DefaultContext && aliasCtx != registryCtx {
+		return "", fmt.Errorf("alias %s is in context %s, not %s: %w", alias, aliasCtx, registryCtx, ErrInvalidSubjectAlias)
+	}
+	if target == "" {
+		return "", fmt.Errorf("alias %s names no subject: %w", alias, ErrInvalidSubjectAlias)
+	}
+	if target == subject {
+		return "", fmt.Errorf("subject %s cannot be an alias for itself: %w", subject, ErrInvalidSubjectAlias)
+	}
+	return target, nil
+}
+
 // ParseVersion parses a version string. "latest" and "-1" return -1 (sentinel).
 // Valid versions are positive integers >= 1. Returns storage.ErrInvalidVersion on failure.
 func ParseVersion(s string) (int, error) {
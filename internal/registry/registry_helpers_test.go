@@ -88,6 +88,48 @@ func TestCheckModeForWrite(t *testing.T) {
 	}
 }
 
+// stubWriteGate is a write gate that is closed while reason is set.
+type stubWriteGate struct{ reason string }
+
+func (g *stubWriteGate) Reason() string { return g.reason }
+
+func TestCheckModeForWrite_WriteGate(t *testing.T) {
+	ctx := context.Background()
+	reg := setupHelperTestRegistry()
+	gate := &stubWriteGate{}
+	reg.SetWriteGate(gate)
+
+	if mode, err := reg.CheckModeForWrite(ctx, ".", "test-subject"); err != nil || mode != "" {
+		t.Fatalf("expected writes allowed with the gate open, got %q, %v", mode, err)
+	}
+
+	gate.reason = "postgresql_replication_lag is 12s, above the 5s threshold"
+	_, err := reg.CheckModeForWrite(ctx, ".", "test-subject")
+	if !errors.Is(err, ErrWritesSuspended) {
+		t.Fatalf("expected ErrWritesSuspended, got %v", err)
+	}
+	want := "writes are suspended while the storage backend could lose them: postgresql_replication_lag is 12s, above the 5s threshold"
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+
+	// An explicit read-only mode is still reported as such.
+	if err := reg.SetMode(ctx, ".", "", "READONLY", false); err != nil {
+		t.Fatalf("SetMode error: %v", err)
+	}
+	if mode, err := reg.CheckModeForWrite(ctx, ".", "test-subject"); err != nil || mode != "READONLY" {
+		t.Errorf("expected READONLY, got %q, %v", mode, err)
+	}
+	if err := reg.SetMode(ctx, ".", "", "READWRITE", false); err != nil {
+		t.Fatalf("SetMode error: %v", err)
+	}
+
+	gate.reason = ""
+	if mode, err := reg.CheckModeForWrite(ctx, ".", "test-subject"); err != nil || mode != "" {
+		t.Errorf("expected writes allowed once the gate opens, got %q, %v", mode, err)
+	}
+}
+
 func TestResolveAlias(t *testing.T) {
 	ctx := context.Background()
 	reg := setupHelperTestRegistry()
@@ -123,6 +165,75 @@ func TestResolveAlias(t *testing.T) {
 	}
 }
 
+func TestSetConfig_SubjectAliasValidation(t *testing.T) {
+	ctx := context.Background()
+	reg := setupHelperTestRegistry()
+
+	tests := []struct {
+		name        string
+		registryCtx string
+		alias       string
+		want        string
+		wantErr     bool
+	}{
+		{"plain", ".team", "orders", "orders", false},
+		{"same context", ".team", ":.team:orders", "orders", false},
+		{"other context", ".team", ":.other:orders", "", true},
+		{"itself", ".team", "legacy-orders", "", true},
+		{"qualified itself", ".team", ":.team:legacy-orders", "", true},
+		{"empty subject", ".team", ":.team:", "", true},
+	}
+	for _, tt := range tests {
+		err := reg.SetConfig(ctx, tt.registryCtx, "legacy-orders", "BACKWARD", nil, SetConfigOpts{Alias: tt.alias})
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidSubjectAlias) {
+				t.Errorf("%s: expected ErrInvalidSubjectAlias, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: SetConfig error: %v", tt.name, err)
+		}
+		if got := reg.ResolveAlias(ctx, tt.registryCtx, "legacy-orders"); got != tt.want {
+			t.Errorf("%s: expected alias %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestResolveWriteAlias(t *testing.T) {
+	ctx := context.Background()
+	reg := setupHelperTestRegistry()
+	if err := reg.SetConfig(ctx, ".", "legacy-orders", "BACKWARD", nil, SetConfigOpts{Alias: "orders"}); err != nil {
+		t.Fatalf("SetConfig error: %v", err)
+	}
+
+	tests := []struct {
+		policy  string
+		subject string
+		want    string
+		wantErr bool
+	}{
+		{"", "legacy-orders", "orders", false},
+		{AliasWriteFollow, "legacy-orders", "orders", false},
+		{AliasWriteIgnore, "legacy-orders", "legacy-orders", false},
+		{AliasWriteReject, "legacy-orders", "", true},
+		{AliasWriteReject, "orders", "orders", false},
+	}
+	for _, tt := range tests {
+		reg.SetAliasWritePolicy(tt.policy)
+		got, err := reg.ResolveWriteAlias(ctx, ".", tt.subject)
+		if tt.wantErr {
+			if !errors.Is(err, ErrSubjectAliased) {
+				t.Errorf("%q %s: expected ErrSubjectAliased, got %v", tt.policy, tt.subject, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q %s: expected %q, got %q (%v)", tt.policy, tt.subject, tt.want, got, err)
+		}
+	}
+}
+
 func TestParseVersion(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Limits on schema ID aliases.
+const (
+	maxIDAliasesPerRequest = 10000
+	maxIDAliasSourceLength = 255
+)
+
+// SetSchemaIDAliases maps schema IDs of source registries to schemas in this
+// context, replacing any existing alias of the same source ID. Every alias
+// is validated before any is stored, and each must name a schema that exists
+// in the context.
+func (r *Registry) SetSchemaIDAliases(ctx context.Context, registryCtx string, aliases []*storage.SchemaIDAliasRecord) error {
+	if len(aliases) == 0 {
+		return fmt.Errorf("%w: no aliases given", ErrInvalidIDAlias)
+	}
+	if len(aliases) > maxIDAliasesPerRequest {
+		return fmt.Errorf("%w: at most %d aliases are allowed per request", ErrInvalidIDAlias, maxIDAliasesPerRequest)
+	}
+
+	seen := make(map[string]int64, len(aliases))
+	for _, alias := range aliases {
+		alias.Source = strings.TrimSpace(alias.Source)
+		if err := validateIDAliasSource(alias.Source); err != nil {
+			return err
+		}
+		if alias.SourceID <= 0 || alias.SchemaID <= 0 {
+			return fmt.Errorf("%w: source ID and schema ID must be positive", ErrInvalidIDAlias)
+		}
+		key := fmt.Sprintf("%s/%d", alias.Source, alias.SourceID)
+		if id, ok := seen[key]; ok && id != alias.SchemaID {
+			return fmt.Errorf("%w: ID %d of source %q is mapped to both %d and %d", ErrInvalidIDAlias, alias.SourceID, alias.Source, id, alias.SchemaID)
+		}
+		seen[key] = alias.SchemaID
+	}
+
+	checked := make(map[int64]bool)
+	for _, alias := range aliases {
+		if checked[alias.SchemaID] {
+			continue
+		}
+		if _, err := r.storage.GetSchemaByID(ctx, registryCtx, alias.SchemaID); err != nil {
+			if errors.Is(err, storage.ErrSchemaNotFound) {
+				return fmt.Errorf("%w: schema %d does not exist", ErrInvalidIDAlias, alias.SchemaID)
+			}
+			return err
+		}
+		checked[alias.SchemaID] = true
+	}
+
+	for _, alias := range aliases {
+		if err := r.storage.SetSchemaIDAlias(ctx, registryCtx, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSchemaIDAliases returns the aliases in a context from source, or from
+// every source when source is empty.
+func (r *Registry) ListSchemaIDAliases(ctx context.Context, registryCtx string, source string) ([]*storage.SchemaIDAliasRecord, error) {
+	return r.storage.ListSchemaIDAliases(ctx, registryCtx, strings.TrimSpace(source))
+}
+
+// DeleteSchemaIDAlias removes the alias of a source registry's schema ID.
+func (r *Registry) DeleteSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) error {
+	return r.storage.DeleteSchemaIDAlias(ctx, registryCtx, strings.TrimSpace(source), sourceID)
+}
+
+// ResolveSchemaID returns the local schema ID that the ID of a source
+// registry is aliased to, or id itself when source is empty. An ID without
+// an alias returns storage.ErrIDAliasNotFound rather than falling back to
+// the local schema with the same ID, which is a different schema when IDs
+// collided.
+func (r *Registry) ResolveSchemaID(ctx context.Context, registryCtx string, source string, id int64) (int64, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return id, nil
+	}
+	alias, err := r.storage.GetSchemaIDAlias(ctx, registryCtx, source, id)
+	if err != nil {
+		return 0, err
+	}
+	return alias.SchemaID, nil
+}
+
+func validateIDAliasSource(source string) error {
+	if source == "" {
+		return fmt.Errorf("%w: source must not be empty", ErrInvalidIDAlias)
+	}
+	if len(source) > maxIDAliasSourceLength {
+		return fmt.Errorf("%w: source is longer than %d characters", ErrInvalidIDAlias, maxIDAliasSourceLength)
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// maxImportSessionSubjects limits how many subjects one import session may
+// cover.
+const maxImportSessionSubjects = 1000
+
+// OpenImportSession starts an import into the given subjects. Each subject is
+// put into IMPORT mode, so that ordinary registrations are rejected while the
+// import is staged, and its own mode is recorded so that committing or
+// aborting the session restores it. A subject may only be in one open session
+// at a time.
+func (r *Registry) OpenImportSession(ctx context.Context, registryCtx string, subjects []string, createdBy string) (*storage.ImportSessionRecord, error) {
+	subjects, err := normalizeImportSubjects(subjects)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkContextCreatable(ctx, registryCtx); err != nil {
+		return nil, err
+	}
+
+	r.importMu.Lock()
+	defer r.importMu.Unlock()
+
+	open, err := r.ListImportSessions(ctx, registryCtx, storage.ImportSessionOpen)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range open {
+		for _, subject := range subjects {
+			if slices.Contains(other.Subjects, subject) {
+				return nil, fmt.Errorf("subject %q is already in import session %s: %w", subject, other.ID, ErrImportSessionConflict)
+			}
+		}
+	}
+
+	id, err := newImportSessionID()
+	if err != nil {
+		return nil, err
+	}
+	session := &storage.ImportSessionRecord{
+		ID:        id,
+		Context:   registryCtx,
+		Subjects:  subjects,
+		State:     storage.ImportSessionOpen,
+		Modes:     make(map[string]string, len(subjects)),
+		CreatedBy: createdBy,
+	}
+	for _, subject := range subjects {
+		mode, err := r.storage.GetMode(ctx, registryCtx, subject)
+		switch {
+		case err == nil:
+			session.Modes[subject] = mode.Mode
+		case errors.Is(err, storage.ErrNotFound):
+			session.Modes[subject] = ""
+		default:
+			return nil, fmt.Errorf("failed to get mode of %s: %w", subject, err)
+		}
+	}
+
+	// Subjects are switched to IMPORT mode even when they already have
+	// versions: the staged schemas are validated against them on commit.
+	for i, subject := range subjects {
+		if err := r.storage.SetMode(ctx, registryCtx, subject, &storage.ModeRecord{Mode: "IMPORT"}); err != nil {
+			r.restoreImportModes(ctx, registryCtx, subjects[:i], session.Modes)
+			return nil, fmt.Errorf("failed to set IMPORT mode on %s: %w", subject, err)
+		}
+	}
+	if err := r.storage.CreateImportSession(ctx, session); err != nil {
+		r.restoreImportModes(ctx, registryCtx, subjects, session.Modes)
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetImportSession returns an import session of the given context.
+func (r *Registry) GetImportSession(ctx context.Context, registryCtx string, id string) (*storage.ImportSessionRecord, error) {
+	session, err := r.storage.GetImportSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Sessions of other contexts are not visible, as if they did not exist.
+	if session.Context != registryCtx {
+		return nil, storage.ErrImportSessionNotFound
+	}
+	return session, nil
+}
+
+// ListImportSessions returns the import sessions of a context, newest first.
+// An empty state returns sessions in every state.
+func (r *Registry) ListImportSessions(ctx context.Context, registryCtx string, state string) ([]*storage.ImportSessionRecord, error) {
+	all, err := r.storage.ListImportSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*storage.ImportSessionRecord, 0, len(all))
+	for _, session := range all {
+		if session.Context == registryCtx && (state == "" || session.State == state) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+// StageImportSchemas adds schemas to an open import session. Nothing is
+// written to the registry until the session is committed. Every schema must
+// belong to one of the session's subjects.
+func (r *Registry) StageImportSchemas(ctx context.Context, registryCtx string, id string, schemas []ImportSchemaRequest) (*storage.ImportSessionRecord, error) {
+	r.importMu.Lock()
+	defer r.importMu.Unlock()
+
+	session, err := r.openImportSession(ctx, registryCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, schema := range schemas {
+		if !slices.Contains(session.Subjects, schema.Subject) {
+			return nil, fmt.Errorf("subject %q is not part of import session %s: %w", schema.Subject, id, ErrInvalidImportSession)
+		}
+	}
+	staged, err := StagedImportSchemas(session)
+	if err != nil {
+		return nil, err
+	}
+	staged = append(staged, schemas...)
+	data, err := json.Marshal(staged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode staged schemas: %w", err)
+	}
+	session.Schemas = string(data)
+	session.Staged = len(staged)
+	if err := r.storage.UpdateImportSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CommitImportSession imports the staged schemas as a single unit, as
+// ImportBundle does, then restores the subjects' modes and closes the
+// session. If any staged schema is invalid nothing is imported, the result
+// reports the failing entries and the session stays open so that it can be
+// aborted.
+func (r *Registry) CommitImportSession(ctx context.Context, registryCtx string, id string) (*storage.ImportSessionRecord, *ImportResult, error) {
+	r.importMu.Lock()
+	defer r.importMu.Unlock()
+
+	session, err := r.openImportSession(ctx, registryCtx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	staged, err := StagedImportSchemas(session)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := r.ImportBundle(ctx, registryCtx, staged)
+	if err != nil || result.Errors > 0 {
+		return session, result, err
+	}
+	if err := r.closeImportSession(ctx, session, storage.ImportSessionCommitted); err != nil {
+		return nil, result, err
+	}
+	return session, result, nil
+}
+
+// AbortImportSession discards the staged schemas, restores the subjects'
+// modes and closes the session.
+func (r *Registry) AbortImportSession(ctx context.Context, registryCtx string, id string) (*storage.ImportSessionRecord, error) {
+	r.importMu.Lock()
+	defer r.importMu.Unlock()
+
+	session, err := r.openImportSession(ctx, registryCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.closeImportSession(ctx, session, storage.ImportSessionAborted); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// openImportSession returns the session if it is still open.
+func (r *Registry) openImportSession(ctx context.Context, registryCtx string, id string) (*storage.ImportSessionRecord, error) {
+	session, err := r.GetImportSession(ctx, registryCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.State != storage.ImportSessionOpen {
+		return nil, fmt.Errorf("import session %s is %s: %w", id, strings.ToLower(session.State), ErrImportSessionClosed)
+	}
+	return session, nil
+}
+
+// closeImportSession restores the subjects' modes and stores the session in
+// its final state without its staged schemas.
+func (r *Registry) closeImportSession(ctx context.Context, session *storage.ImportSessionRecord, state string) error {
+	if err := r.restoreImportModes(ctx, session.Context, session.Subjects, session.Modes); err != nil {
+		return err
+	}
+	session.State = state
+	session.Schemas = ""
+	return r.storage.UpdateImportSession(ctx, session)
+}
+
+// restoreImportModes puts each subject back into the mode it had before the
+// session, removing the subject-level mode of subjects that had none. Every
+// subject is attempted; the first error is returned.
+func (r *Registry) restoreImportModes(ctx context.Context, registryCtx string, subjects []string, modes map[string]string) error {
+	var firstErr error
+	for _, subject := range subjects {
+		var err error
+		if mode := modes[subject]; mode != "" {
+			err = r.storage.SetMode(ctx, registryCtx, subject, &storage.ModeRecord{Mode: mode})
+		} else if err = r.storage.DeleteMode(ctx, registryCtx, subject); errors.Is(err, storage.ErrNotFound) {
+			err = nil
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to restore mode of %s: %w", subject, err)
+		}
+	}
+	return firstErr
+}
+
+// StagedImportSchemas decodes the schemas staged in a session.
+func StagedImportSchemas(session *storage.ImportSessionRecord) ([]ImportSchemaRequest, error) {
+	if session.Schemas == "" {
+		return nil, nil
+	}
+	var staged []ImportSchemaRequest
+	if err := json.Unmarshal([]byte(session.Schemas), &staged); err != nil {
+		return nil, fmt.Errorf("failed to decode staged schemas: %w", err)
+	}
+	return staged, nil
+}
+
+// normalizeImportSubjects trims and de-duplicates the subjects of a new
+// session, keeping their order.
+func normalizeImportSubjects(subjects []string) ([]string, error) {
+	out := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		subject = strings.TrimSpace(subject)
+		if subject == "" {
+			return nil, fmt.Errorf("subject names must not be empty: %w", ErrInvalidImportSession)
+		}
+		if !slices.Contains(out, subject) {
+			out = append(out, subject)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("at least one subject is required: %w", ErrInvalidImportSession)
+	}
+	if len(out) > maxImportSessionSubjects {
+		return nil, fmt.Errorf("at most %d subjects may be imported in one session: %w", maxImportSessionSubjects, ErrInvalidImportSession)
+	}
+	return out, nil
+}
+
+func newImportSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate import session ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/lint"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SetLintPolicies sets the lint policies enforced when schemas are
+// registered. contextPolicies maps a context name, and subjectPolicies a
+// subject that may be context-qualified (":.staging:orders-value"), to a
+// policy applied on top of the less specific ones.
+func (r *Registry) SetLintPolicies(defaultPolicy lint.Policy, contextPolicies, subjectPolicies map[string]lint.Policy) {
+	r.defaultLintPolicy = defaultPolicy
+	r.contextLintPolicies = make(map[string]lint.Policy, len(contextPolicies))
+	for name, policy := range contextPolicies {
+		r.contextLintPolicies[registrycontext.NormalizeContextName(name)] = policy
+	}
+	r.subjectLintPolicies = make(map[string]lint.Policy, len(subjectPolicies))
+	for qualified, policy := range subjectPolicies {
+		registryCtx, subject := registrycontext.ResolveSubject(qualified)
+		r.subjectLintPolicies[registrycontext.FormatSubject(registryCtx, subject)] = policy
+	}
+}
+
+// LintPolicy returns the lint policy in effect for a subject.
+func (r *Registry) LintPolicy(registryCtx, subject string) lint.Policy {
+	return r.defaultLintPolicy.
+		With(r.contextLintPolicies[registryCtx]).
+		With(r.subjectLintPolicies[registrycontext.FormatSubject(registryCtx, subject)])
+}
+
+// LintSchema checks a schema against the lint policy of a subject and
+// returns every violation, whatever its severity.
+func (r *Registry) LintSchema(ctx context.Context, registryCtx, subject, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference) ([]lint.Violation, error) {
+	policy := r.LintPolicy(registryCtx, subject)
+	if !policy.Enabled() {
+		return nil, nil
+	}
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	refs, err := r.referencesByID(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, err
+	}
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, refs)
+	if err != nil {
+		return nil, err
+	}
+	return lint.Check(policy, schemaStr, schemaType, resolvedRefs), nil
+}
+
+// checkLint rejects a registration whose schema has error-severity lint
+// violations. Like the naming strategy, it runs after re-registrations are
+// deduplicated, so schemas registered before a rule was enabled keep working.
+func (r *Registry) checkLint(registryCtx, subject, schemaStr string, schemaType storage.SchemaType, resolvedRefs []storage.Reference) error {
+	errs := lint.Errors(lint.Check(r.LintPolicy(registryCtx, subject), schemaStr, schemaType, resolvedRefs))
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, v := range errs {
+		msgs[i] = v.String()
+	}
+	return fmt.Errorf("%s: %w", strings.Join(msgs, "; "), ErrLintFailed)
+}
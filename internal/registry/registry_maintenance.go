@@ -0,0 +1,139 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Maintenance window states, derived from a window's times.
+const (
+	MaintenanceScheduled = "SCHEDULED"
+	MaintenanceActive    = "ACTIVE"
+	MaintenanceEnded     = "ENDED"
+	MaintenanceCancelled = "CANCELLED"
+)
+
+// maxMaintenanceDuration limits how long one maintenance window may last, so
+// that a typo cannot leave the registry read-only for months.
+const maxMaintenanceDuration = 7 * 24 * time.Hour
+
+// maxMaintenanceReason limits the length of a maintenance window's reason.
+const maxMaintenanceReason = 1024
+
+// MaintenanceState returns the state of a window at the given time.
+func MaintenanceState(w *storage.MaintenanceWindowRecord, now time.Time) string {
+	switch {
+	case w.Cancelled:
+		return MaintenanceCancelled
+	case now.Before(w.StartsAt):
+		return MaintenanceScheduled
+	case now.Before(w.EndsAt):
+		return MaintenanceActive
+	default:
+		return MaintenanceEnded
+	}
+}
+
+// ScheduleMaintenance schedules a maintenance window. While it is active the
+// registry behaves as if READONLY_OVERRIDE were set as the global mode, and
+// it reverts on its own when the window ends: nothing is written to the
+// stored modes. A zero startsAt starts the window now.
+func (r *Registry) ScheduleMaintenance(ctx context.Context, startsAt time.Time, duration time.Duration, reason, createdBy string) (*storage.MaintenanceWindowRecord, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required: %w", ErrInvalidMaintenance)
+	}
+	if len(reason) > maxMaintenanceReason {
+		return nil, fmt.Errorf("reason must be at most %d characters: %w", maxMaintenanceReason, ErrInvalidMaintenance)
+	}
+	if duration <= 0 || duration > maxMaintenanceDuration {
+		return nil, fmt.Errorf("duration must be positive and at most %s: %w", maxMaintenanceDuration, ErrInvalidMaintenance)
+	}
+	now := time.Now().UTC()
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+	startsAt = startsAt.UTC().Truncate(time.Millisecond)
+	endsAt := startsAt.Add(duration)
+	if !endsAt.After(now) {
+		return nil, fmt.Errorf("the window would end in the past: %w", ErrInvalidMaintenance)
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate maintenance window ID: %w", err)
+	}
+	window := &storage.MaintenanceWindowRecord{
+		ID:        hex.EncodeToString(b),
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Reason:    reason,
+		CreatedBy: createdBy,
+	}
+	if err := r.storage.CreateMaintenanceWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// GetMaintenanceWindow returns a maintenance window.
+func (r *Registry) GetMaintenanceWindow(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error) {
+	return r.storage.GetMaintenanceWindow(ctx, id)
+}
+
+// ListMaintenanceWindows returns all maintenance windows ordered by start
+// time, including ended and cancelled ones.
+func (r *Registry) ListMaintenanceWindows(ctx context.Context) ([]*storage.MaintenanceWindowRecord, error) {
+	return r.storage.ListMaintenanceWindows(ctx)
+}
+
+// CancelMaintenance cancels a scheduled window, or ends an active one at
+// once. Windows that have ended or were cancelled cannot be cancelled.
+func (r *Registry) CancelMaintenance(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error) {
+	window, err := r.storage.GetMaintenanceWindow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	switch MaintenanceState(window, now) {
+	case MaintenanceEnded, MaintenanceCancelled:
+		return nil, fmt.Errorf("maintenance window %s is %s: %w", id, strings.ToLower(MaintenanceState(window, now)), ErrMaintenanceEnded)
+	case MaintenanceActive:
+		window.EndsAt = now
+	}
+	window.Cancelled = true
+	if err := r.storage.UpdateMaintenanceWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// ActiveMaintenance returns the active maintenance window, or nil when there
+// is none. When windows overlap, the one that ends last is returned.
+func (r *Registry) ActiveMaintenance(ctx context.Context) (*storage.MaintenanceWindowRecord, error) {
+	windows, err := r.storage.ListMaintenanceWindows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	now := time.Now()
+	var active *storage.MaintenanceWindowRecord
+	for _, w := range windows {
+		if MaintenanceState(w, now) == MaintenanceActive && (active == nil || w.EndsAt.After(active.EndsAt)) {
+			active = w
+		}
+	}
+	return active, nil
+}
+
+// MaintenanceActive reports whether a maintenance window is active.
+// Satisfies metrics.MaintenanceSource.
+func (r *Registry) MaintenanceActive() bool {
+	active, err := r.ActiveMaintenance(context.Background())
+	return err == nil && active != nil
+}
@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RejectedReservedField is the rejection reason of a schema that removes or
+// changes a reserved field, when field validation is enabled. Other
+// rejections are reported with compatibility.ReasonCategory.
+const RejectedReservedField = "reserved_field"
+
+// RegistrationMetrics records the outcome of schema registrations and
+// imports. It is implemented by *metrics.Metrics.
+type RegistrationMetrics interface {
+	// RecordSchemaRegistration records a registration attempt.
+	RecordSchemaRegistration(schemaType string, success bool)
+	// RecordCompatibilityRejection records a registration refused because
+	// the schema is incompatible with the subject, once for each reason.
+	RecordCompatibilityRejection(schemaType, reason string)
+	// RecordSchemaImport records the import of one schema.
+	RecordSchemaImport(schemaType string, success bool)
+}
+
+// SetMetrics makes the registry record registrations, compatibility
+// rejections and imports in m. Dry runs are not recorded.
+func (r *Registry) SetMetrics(m RegistrationMetrics) {
+	r.metrics = m
+}
+
+// recordRegistration records a registration attempt that failed with err,
+// or succeeded if err is nil.
+func (r *Registry) recordRegistration(schemaType storage.SchemaType, err error) {
+	if r.metrics != nil {
+		r.metrics.RecordSchemaRegistration(string(schemaTypeOrDefault(schemaType)), err == nil)
+	}
+}
+
+// recordRejection records a registration refused for reasons. The subject
+// is left out: it would give the metric one series per subject.
+func (r *Registry) recordRejection(schemaType storage.SchemaType, reasons ...string) {
+	if r.metrics == nil {
+		return
+	}
+	for _, reason := range reasons {
+		r.metrics.RecordCompatibilityRejection(string(schemaType), reason)
+	}
+}
+
+// recordImports records the outcome of each schema of an import.
+func (r *Registry) recordImports(schemas []ImportSchemaRequest, result *ImportResult) {
+	if r.metrics == nil {
+		return
+	}
+	for i, req := range schemas {
+		success := result != nil && result.Results[i].Success
+		r.metrics.RecordSchemaImport(string(schemaTypeOrDefault(req.SchemaType)), success)
+	}
+}
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/naming"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SetSubjectNameStrategies sets the subject naming strategies enforced when
+// schemas are registered. contextStrategies maps a context name to its
+// strategy; contexts without an entry use defaultStrategy. An empty strategy
+// or "none" disables enforcement.
+func (r *Registry) SetSubjectNameStrategies(defaultStrategy string, contextStrategies map[string]string) {
+	r.defaultNameStrategy = defaultStrategy
+	r.contextNameStrategies = make(map[string]string, len(contextStrategies))
+	for name, strategy := range contextStrategies {
+		r.contextNameStrategies[registrycontext.NormalizeContextName(name)] = strategy
+	}
+}
+
+// SubjectNameStrategy returns the subject naming strategy for a context.
+func (r *Registry) SubjectNameStrategy(registryCtx string) string {
+	if strategy, ok := r.contextNameStrategies[registryCtx]; ok {
+		return strategy
+	}
+	return r.defaultNameStrategy
+}
+
+// checkSubjectName rejects a registration whose subject does not match the
+// context's naming strategy. Re-registering an existing schema is deduplicated
+// before this check, so subjects created before a policy was configured keep
+// working.
+func (r *Registry) checkSubjectName(registryCtx, subject string, parsed schema.ParsedSchema) error {
+	if err := naming.Check(r.SubjectNameStrategy(registryCtx), subject, parsed.RecordName()); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrSubjectNameStrategy)
+	}
+	return nil
+}
+
+// TopicSubjects describes the subjects that serializers use for a Kafka topic.
+type TopicSubjects struct {
+	Topic   string
+	Key     string   // "<topic>-key" when registered
+	Value   string   // "<topic>-value" when registered
+	Records []string // "<topic>-<record name>" subjects (TopicRecordNameStrategy)
+}
+
+// GetTopicSubjects maps a Kafka topic to its registered key, value and
+// per-record subjects within a context. A "<topic>-<suffix>" subject is only
+// reported as a record subject when the suffix is the record name of its
+// latest schema, so subjects of other topics sharing the prefix are excluded.
+func (r *Registry) GetTopicSubjects(ctx context.Context, registryCtx string, topic string) (*TopicSubjects, error) {
+	subjects, err := r.storage.ListSubjects(ctx, registryCtx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TopicSubjects{Topic: topic, Records: []string{}}
+	prefix := topic + "-"
+	for _, subject := range subjects {
+		switch subject {
+		case topic + naming.KeySuffix:
+			result.Key = subject
+			continue
+		case topic + naming.ValueSuffix:
+			result.Value = subject
+			continue
+		}
+		suffix, ok := strings.CutPrefix(subject, prefix)
+		if !ok || suffix == "" {
+			continue
+		}
+		latest, err := r.storage.GetLatestSchema(ctx, registryCtx, subject)
+		if err != nil {
+			continue
+		}
+		if r.recordName(ctx, registryCtx, latest) == suffix {
+			result.Records = append(result.Records, subject)
+		}
+	}
+	sort.Strings(result.Records)
+	return result, nil
+}
+
+// recordName parses a stored schema and returns its record name, or "" if the
+// schema cannot be parsed.
+func (r *Registry) recordName(ctx context.Context, registryCtx string, record *storage.SchemaRecord) string {
+	schemaType := record.SchemaType
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return ""
+	}
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, record.References)
+	if err != nil {
+		return ""
+	}
+	parsed, err := parser.Parse(record.Schema, resolvedRefs)
+	if err != nil {
+		return ""
+	}
+	return parsed.RecordName()
+}
@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Kinds of dangling references reported by OrphanedReferences.
+const (
+	// OrphanMissing is a reference to a subject version that does not
+	// exist, not even soft-deleted.
+	OrphanMissing = "missing"
+	// OrphanSoftDeleted is a reference to a soft-deleted subject version.
+	OrphanSoftDeleted = "soft_deleted"
+	// OrphanReplaced is a reference to a subject version that was stored
+	// again after the referencing schema was registered, for example by a
+	// hard delete and a re-import, so its content may not be the one the
+	// schema was registered against.
+	OrphanReplaced = "replaced"
+)
+
+// OrphanedReference is a reference of a stored schema version that no
+// longer resolves to the schema it was registered against.
+type OrphanedReference struct {
+	Context string
+	Subject string
+	Version int
+	ID      int64
+	// Deleted reports that the referencing version is itself soft-deleted.
+	Deleted bool
+	// Reference is the dangling reference.
+	Reference storage.Reference
+	Kind      string
+	Message   string
+}
+
+// OrphanedReferences lists the references of every subject version of a
+// context, including soft-deleted ones, that point to a missing or
+// soft-deleted subject version, or to one stored again after the
+// referencing version. An empty registryCtx lists the references of every
+// context. The list is sorted by context, subject, version and reference
+// name.
+func (r *Registry) OrphanedReferences(ctx context.Context, registryCtx string) ([]OrphanedReference, error) {
+	contexts := []string{registrycontext.NormalizeContextName(registryCtx)}
+	if registryCtx == "" {
+		var err error
+		if contexts, err = r.ListContexts(ctx); err != nil {
+			return nil, err
+		}
+		sort.Strings(contexts)
+	}
+
+	orphans := []OrphanedReference{}
+	for _, c := range contexts {
+		found, err := r.orphanedReferencesIn(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", c, err)
+		}
+		orphans = append(orphans, found...)
+	}
+	return orphans, nil
+}
+
+func (r *Registry) orphanedReferencesIn(ctx context.Context, registryCtx string) ([]OrphanedReference, error) {
+	subjects, err := r.storage.ListSubjects(ctx, registryCtx, true)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(subjects)
+
+	records := make(map[string]map[int]*storage.SchemaRecord, len(subjects))
+	for _, subject := range subjects {
+		list, err := r.storage.GetSchemasBySubject(ctx, registryCtx, subject, true)
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records[subject] = make(map[int]*storage.SchemaRecord, len(list))
+		for _, rec := range list {
+			records[subject][rec.Version] = rec
+		}
+	}
+	lookup := func(_ context.Context, _ string, subject string, version int) (*storage.SchemaRecord, error) {
+		if rec := records[subject][version]; rec != nil {
+			return rec, nil
+		}
+		return nil, storage.ErrVersionNotFound
+	}
+
+	var orphans []OrphanedReference
+	for _, subject := range subjects {
+		versions := make([]int, 0, len(records[subject]))
+		for v := range records[subject] {
+			versions = append(versions, v)
+		}
+		sort.Ints(versions)
+
+		for _, v := range versions {
+			rec := records[subject][v]
+			refs := append([]storage.Reference(nil), rec.References...)
+			sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+
+			for _, ref := range refs {
+				o := OrphanedReference{
+					Context:   registryCtx,
+					Subject:   subject,
+					Version:   v,
+					ID:        rec.ID,
+					Deleted:   rec.Deleted,
+					Reference: storage.Reference{Name: ref.Name, Subject: ref.Subject, Version: ref.Version},
+				}
+				target := records[ref.Subject][ref.Version]
+				switch {
+				case target == nil:
+					o.Kind = OrphanMissing
+					o.Message = fmt.Sprintf("subject %s version %d does not exist", ref.Subject, ref.Version)
+				case target.Deleted:
+					o.Kind = OrphanSoftDeleted
+					o.Message = fmt.Sprintf("subject %s version %d is soft-deleted", ref.Subject, ref.Version)
+				case !target.CreatedAt.IsZero() && !rec.CreatedAt.IsZero() && target.CreatedAt.After(rec.CreatedAt):
+					o.Kind = OrphanReplaced
+					o.Message = fmt.Sprintf("subject %s version %d (ID %d) was stored after this version was registered, so its content may have changed",
+						ref.Subject, ref.Version, target.ID)
+					if err := r.parseWithReferences(ctx, registryCtx, rec, lookup); err != nil {
+						o.Message += "; the schema no longer parses: " + err.Error()
+					}
+				default:
+					continue
+				}
+				orphans = append(orphans, o)
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// parseWithReferences parses a stored schema with its references resolved
+// by lookup. Schema types this server cannot parse are not checked.
+func (r *Registry) parseWithReferences(ctx context.Context, registryCtx string, rec *storage.SchemaRecord, lookup schemaLookup) error {
+	schemaType := rec.SchemaType
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	parser, ok := r.schemaParser.Get(schemaType)
+	if !ok {
+		return nil
+	}
+	resolvedRefs, err := r.resolveReferencesWith(ctx, registryCtx, lookup, rec.Subject, rec.Version, rec.References)
+	if err != nil {
+		return err
+	}
+	_, err = parser.Parse(rec.Schema, resolvedRefs)
+	return err
+}
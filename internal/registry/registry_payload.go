@@ -0,0 +1,44 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// ValidatePayload validates doc, a value decoded from JSON, against the
+// schema of a version of subject, with the schema's references resolved.
+// version -1 means the latest version. It returns the schema validated
+// against and the problems found, which are empty when doc is valid. Only
+// JSON Schema subjects can validate payloads; for other schema types
+// ErrUnsupportedSchemaType is returned.
+func (r *Registry) ValidatePayload(ctx context.Context, registryCtx string, subject string, version int, doc interface{}) (*storage.SchemaRecord, []schema.PayloadError, error) {
+	record, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.SchemaType != storage.SchemaTypeJSON {
+		return nil, nil, fmt.Errorf("payload validation is only supported for JSON schemas, subject %s version %d is %s: %w",
+			subject, record.Version, schemaTypeOrDefault(record.SchemaType), ErrUnsupportedSchemaType)
+	}
+	parser, ok := r.schemaParser.Get(storage.SchemaTypeJSON)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported schema type: %s: %w", storage.SchemaTypeJSON, ErrUnsupportedSchemaType)
+	}
+	resolvedRefs, err := r.resolveReferences(ctx, registryCtx, record.References)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve references: %w", errors.Join(err, ErrFailedResolveReferences))
+	}
+	parsed, err := parser.Parse(record.Schema, resolvedRefs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schema: %w", errors.Join(err, ErrInvalidSchema))
+	}
+	validator, ok := parsed.(schema.PayloadValidator)
+	if !ok {
+		return nil, nil, fmt.Errorf("payload validation is not supported for %s: %w", storage.SchemaTypeJSON, ErrUnsupportedSchemaType)
+	}
+	return record, validator.ValidatePayload(doc), nil
+}
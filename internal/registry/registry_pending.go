@@ -0,0 +1,293 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// maxReviewReason limits the length of a reviewer's reason.
+const maxReviewReason = 1024
+
+// ReviewerRolePrefix marks a reviewer entry that names a role rather than a
+// user, as in role:admin.
+const ReviewerRolePrefix = "role:"
+
+// Reviewer is the user approving or rejecting a pending schema.
+type Reviewer struct {
+	Username string
+	Role     string
+}
+
+// PendingSchemaRequest is a registration held for approval, as stored in
+// PendingSchemaRecord.Request.
+type PendingSchemaRequest struct {
+	Schema     string              `json:"schema"`
+	SchemaType storage.SchemaType  `json:"schemaType,omitempty"`
+	References []storage.Reference `json:"references,omitempty"`
+	Metadata   *storage.Metadata   `json:"metadata,omitempty"`
+	RuleSet    *storage.RuleSet    `json:"ruleSet,omitempty"`
+	Normalize  bool                `json:"normalize,omitempty"`
+}
+
+// SetApprovalContexts sets the contexts in which registrations by developers
+// are held for approval instead of being registered.
+func (r *Registry) SetApprovalContexts(contexts []string) {
+	r.approvalContexts = make(map[string]bool, len(contexts))
+	for _, name := range contexts {
+		r.approvalContexts[registrycontext.NormalizeContextName(name)] = true
+	}
+}
+
+// SetApprovalReviewers sets, by context, the reviewers assigned to pending
+// schemas whose subject declares none in its meta. Reviewers are usernames
+// or roles written as role:<name>.
+func (r *Registry) SetApprovalReviewers(reviewers map[string][]string) {
+	r.approvalReviewers = make(map[string][]string, len(reviewers))
+	for name, list := range reviewers {
+		r.approvalReviewers[registrycontext.NormalizeContextName(name)] = list
+	}
+}
+
+// RequiresApproval reports whether registrations by developers in a context
+// are held for approval.
+func (r *Registry) RequiresApproval(registryCtx string) bool {
+	return r.approvalContexts[registryCtx]
+}
+
+// SubmitPendingSchema holds a registration until it is approved or rejected.
+// The schema is checked as RegisterSchema would check it, so that only
+// registrations that can succeed are queued for review.
+//
+// When the schema is already registered under the subject nothing is queued
+// and the existing version is returned instead, so that clients registering
+// their schemas on startup keep working. Submitting a registration that is
+// already pending returns the pending schema.
+//
+// The pending schema is assigned the reviewers the subject declares in its
+// meta, or else those configured for the context.
+func (r *Registry) SubmitPendingSchema(ctx context.Context, registryCtx string, subject string, req PendingSchemaRequest, submittedBy string) (*storage.PendingSchemaRecord, *storage.SchemaRecord, error) {
+	if req.SchemaType == "" {
+		req.SchemaType = storage.SchemaTypeAvro
+	}
+	existing, created, err := r.PlanRegistration(ctx, registryCtx, subject, req.Schema, req.SchemaType, req.References, RegisterOpts{
+		Normalize: req.Normalize,
+		Metadata:  req.Metadata,
+		RuleSet:   req.RuleSet,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !created {
+		return nil, existing, nil
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode registration: %w", err)
+	}
+	pending, err := r.ListPendingSchemas(ctx, registryCtx, storage.PendingSchemaPending)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range pending {
+		if p.Subject == subject && p.Request == string(data) {
+			return p, nil, nil
+		}
+	}
+
+	reviewers, err := r.subjectReviewers(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := newPendingSchemaID()
+	if err != nil {
+		return nil, nil, err
+	}
+	record := &storage.PendingSchemaRecord{
+		ID:          id,
+		Context:     registryCtx,
+		Subject:     subject,
+		State:       storage.PendingSchemaPending,
+		Request:     string(data),
+		SubmittedBy: submittedBy,
+		Reviewers:   reviewers,
+	}
+	if err := r.storage.CreatePendingSchema(ctx, record); err != nil {
+		return nil, nil, err
+	}
+	return record, nil, nil
+}
+
+// GetPendingSchema returns a pending schema.
+func (r *Registry) GetPendingSchema(ctx context.Context, id string) (*storage.PendingSchemaRecord, error) {
+	return r.storage.GetPendingSchema(ctx, id)
+}
+
+// ListPendingSchemas returns the pending schemas of a context, newest first.
+// An empty context returns those of every context, and an empty state those
+// in every state.
+func (r *Registry) ListPendingSchemas(ctx context.Context, registryCtx string, state string) ([]*storage.PendingSchemaRecord, error) {
+	all, err := r.storage.ListPendingSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*storage.PendingSchemaRecord, 0, len(all))
+	for _, p := range all {
+		if (registryCtx == "" || p.Context == registryCtx) && (state == "" || p.State == state) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// ApprovePendingSchema registers a pending schema, which gives it its
+// version and ID, and records the approval. The reviewer must not be the
+// submitter, and must be one of its assigned reviewers if it has any. If the
+// registration fails, for example because a schema registered since is
+// incompatible with it, the error is returned and the schema stays pending
+// so that it can be rejected.
+func (r *Registry) ApprovePendingSchema(ctx context.Context, id string, reviewer Reviewer, reason string) (*storage.PendingSchemaRecord, *storage.SchemaRecord, error) {
+	reason, err := checkReviewReason(reason, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+
+	pending, err := r.openPendingSchema(ctx, id, reviewer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pending.SubmittedBy != "" && pending.SubmittedBy == reviewer.Username {
+		return nil, nil, fmt.Errorf("%s submitted pending schema %s: %w", reviewer.Username, id, ErrSelfApproval)
+	}
+	req, err := PendingSchemaRequestOf(pending)
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := r.RegisterSchema(ctx, pending.Context, pending.Subject, req.Schema, req.SchemaType, req.References, RegisterOpts{
+		Normalize: req.Normalize,
+		Metadata:  req.Metadata,
+		RuleSet:   req.RuleSet,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pending.State = storage.PendingSchemaApproved
+	pending.ReviewedBy = reviewer.Username
+	pending.Reason = reason
+	pending.SchemaID = record.ID
+	pending.Version = record.Version
+	if err := r.storage.UpdatePendingSchema(ctx, pending); err != nil {
+		return nil, record, err
+	}
+	return pending, record, nil
+}
+
+// RejectPendingSchema discards a pending schema. A reason is required so
+// that the submitter learns what to change. The reviewer must be one of its
+// assigned reviewers if it has any.
+func (r *Registry) RejectPendingSchema(ctx context.Context, id string, reviewer Reviewer, reason string) (*storage.PendingSchemaRecord, error) {
+	reason, err := checkReviewReason(reason, true)
+	if err != nil {
+		return nil, err
+	}
+
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+
+	pending, err := r.openPendingSchema(ctx, id, reviewer)
+	if err != nil {
+		return nil, err
+	}
+	pending.State = storage.PendingSchemaRejected
+	pending.ReviewedBy = reviewer.Username
+	pending.Reason = reason
+	if err := r.storage.UpdatePendingSchema(ctx, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// openPendingSchema returns the pending schema if it has not been reviewed
+// and the reviewer may review it.
+func (r *Registry) openPendingSchema(ctx context.Context, id string, reviewer Reviewer) (*storage.PendingSchemaRecord, error) {
+	pending, err := r.storage.GetPendingSchema(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pending.State != storage.PendingSchemaPending {
+		return nil, fmt.Errorf("pending schema %s is %s: %w", id, strings.ToLower(pending.State), ErrPendingSchemaReviewed)
+	}
+	if !IsAssignedReviewer(pending.Reviewers, reviewer) {
+		return nil, fmt.Errorf("pending schema %s must be reviewed by %s: %w", id, strings.Join(pending.Reviewers, ", "), ErrNotAssignedReviewer)
+	}
+	return pending, nil
+}
+
+// IsAssignedReviewer reports whether reviewer is one of reviewers, by
+// username or by role. Anyone is when there are no reviewers.
+func IsAssignedReviewer(reviewers []string, reviewer Reviewer) bool {
+	if len(reviewers) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(reviewers, func(entry string) bool {
+		if role, ok := strings.CutPrefix(entry, ReviewerRolePrefix); ok {
+			return reviewer.Role != "" && role == reviewer.Role
+		}
+		return reviewer.Username != "" && entry == reviewer.Username
+	})
+}
+
+// subjectReviewers returns the reviewers of schemas submitted to a subject:
+// those it declares in its meta, or else those configured for its context.
+func (r *Registry) subjectReviewers(ctx context.Context, registryCtx string, subject string) ([]string, error) {
+	meta, err := r.storage.GetSubjectMeta(ctx, registryCtx, subject)
+	if err != nil && !errors.Is(err, storage.ErrSubjectMetaNotFound) {
+		return nil, err
+	}
+	if meta != nil && len(meta.Reviewers) > 0 {
+		return meta.Reviewers, nil
+	}
+	return r.approvalReviewers[registryCtx], nil
+}
+
+// PendingSchemaRequestOf decodes the registration held by a pending schema.
+func PendingSchemaRequestOf(pending *storage.PendingSchemaRecord) (*PendingSchemaRequest, error) {
+	var req PendingSchemaRequest
+	if err := json.Unmarshal([]byte(pending.Request), &req); err != nil {
+		return nil, fmt.Errorf("failed to decode pending registration: %w", err)
+	}
+	return &req, nil
+}
+
+// checkReviewReason trims a reviewer's reason and checks its length.
+func checkReviewReason(reason string, required bool) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if required && reason == "" {
+		return "", fmt.Errorf("a reason is required: %w", ErrInvalidReview)
+	}
+	if len(reason) > maxReviewReason {
+		return "", fmt.Errorf("reason must be at most %d characters: %w", maxReviewReason, ErrInvalidReview)
+	}
+	return reason, nil
+}
+
+func newPendingSchemaID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate pending schema ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,262 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+)
+
+// QuotaRule limits how much the principals it matches may register. A rule
+// matches the principals it names and the users with the roles it names,
+// optionally only in some contexts. MaxVersionsPerDay limits the new schema
+// versions each matching principal registers per UTC day;
+// MaxSubjectsPerContext limits the new subjects all matching principals
+// together create in each context. Zero means unlimited.
+type QuotaRule struct {
+	Name                  string
+	Principals            []string
+	Roles                 []string
+	Contexts              []string
+	MaxVersionsPerDay     int
+	MaxSubjectsPerContext int
+}
+
+// QuotaUsage is what the principals matching a quota rule have used.
+type QuotaUsage struct {
+	Rule QuotaRule
+	// Day is the UTC date VersionsToday counts versions for.
+	Day string
+	// VersionsToday counts new versions registered today by principal.
+	VersionsToday map[string]int
+	// SubjectsByContext counts new subjects created by context.
+	SubjectsByContext map[string]int
+}
+
+// quotaKey identifies a counter of a quota rule: a principal for version
+// counts, a context for subject counts.
+type quotaKey struct {
+	rule string
+	name string
+}
+
+// quotaTracker enforces the quota rules. Counters are kept in memory by
+// this instance only and start from zero when it restarts.
+type quotaTracker struct {
+	mu       sync.Mutex
+	rules    []QuotaRule
+	now      func() time.Time
+	day      string
+	versions map[quotaKey]int
+	subjects map[quotaKey]int
+}
+
+// quotaReservation is the usage counted for a registration that has not
+// been stored yet. It is given back with release if the registration does
+// not create a version.
+type quotaReservation struct {
+	tracker  *quotaTracker
+	day      string
+	versions []quotaKey
+	subjects []quotaKey
+}
+
+// SetQuotas sets the quota rules checked when principals register schemas.
+// Rules without limits are ignored. Counters are reset.
+func (r *Registry) SetQuotas(rules []QuotaRule) {
+	t := &quotaTracker{
+		now:      time.Now,
+		versions: make(map[quotaKey]int),
+		subjects: make(map[quotaKey]int),
+	}
+	for _, rule := range rules {
+		if rule.MaxVersionsPerDay <= 0 && rule.MaxSubjectsPerContext <= 0 {
+			continue
+		}
+		rule.Contexts = slices.Clone(rule.Contexts)
+		for i, name := range rule.Contexts {
+			rule.Contexts[i] = registrycontext.NormalizeContextName(name)
+		}
+		t.rules = append(t.rules, rule)
+	}
+	if len(t.rules) == 0 {
+		t = nil
+	}
+	r.quotas = t
+}
+
+// matches reports whether a rule applies to a registration by principal,
+// who has role, in registryCtx.
+func (rule *QuotaRule) matches(principal, role, registryCtx string) bool {
+	if principal == "" {
+		return false
+	}
+	if len(rule.Contexts) > 0 && !slices.Contains(rule.Contexts, registryCtx) {
+		return false
+	}
+	return slices.Contains(rule.Principals, principal) || (role != "" && slices.Contains(rule.Roles, role))
+}
+
+// reserveQuota checks the quota rules matching the registering principal
+// and counts the registration against them. It returns nil when no rule
+// applies. Registrations without a principal are not limited.
+func (r *Registry) reserveQuota(ctx context.Context, registryCtx, subject string, opt RegisterOpts) (*quotaReservation, error) {
+	t := r.quotas
+	if t == nil || opt.Principal == "" {
+		return nil, nil
+	}
+
+	var matched []*QuotaRule
+	limitsSubjects := false
+	for i := range t.rules {
+		if t.rules[i].matches(opt.Principal, opt.Role, registryCtx) {
+			matched = append(matched, &t.rules[i])
+			limitsSubjects = limitsSubjects || t.rules[i].MaxSubjectsPerContext > 0
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	newSubject := false
+	if limitsSubjects {
+		exists, err := r.storage.SubjectExists(ctx, registryCtx, subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check subject: %w", err)
+		}
+		newSubject = !exists
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollDay()
+
+	res := &quotaReservation{tracker: t, day: t.day}
+	for _, rule := range matched {
+		if rule.MaxVersionsPerDay > 0 {
+			key := quotaKey{rule.Name, opt.Principal}
+			if t.versions[key] >= rule.MaxVersionsPerDay {
+				return nil, fmt.Errorf("%s has registered %d new schema versions today, the daily limit of quota %s: %w",
+					opt.Principal, t.versions[key], rule.Name, ErrQuotaExceeded)
+			}
+			res.versions = append(res.versions, key)
+		}
+		if rule.MaxSubjectsPerContext > 0 && newSubject {
+			key := quotaKey{rule.Name, registryCtx}
+			if t.subjects[key] >= rule.MaxSubjectsPerContext {
+				return nil, fmt.Errorf("quota %s has created %d subjects in context %s, the limit: %w",
+					rule.Name, t.subjects[key], registryCtx, ErrQuotaExceeded)
+			}
+			res.subjects = append(res.subjects, key)
+		}
+	}
+	for _, key := range res.versions {
+		t.versions[key]++
+	}
+	for _, key := range res.subjects {
+		t.subjects[key]++
+	}
+	return res, nil
+}
+
+// rollDay clears the version counts when the UTC day has changed. t.mu must
+// be held.
+func (t *quotaTracker) rollDay() {
+	today := t.now().UTC().Format(time.DateOnly)
+	if t.day != today {
+		t.day = today
+		clear(t.versions)
+	}
+}
+
+// release gives back the usage counted by a reservation. It is a no-op on
+// a nil reservation.
+func (res *quotaReservation) release() {
+	if res == nil {
+		return
+	}
+	t := res.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.day == res.day {
+		for _, key := range res.versions {
+			if t.versions[key] > 0 {
+				t.versions[key]--
+			}
+		}
+	}
+	for _, key := range res.subjects {
+		if t.subjects[key] > 0 {
+			t.subjects[key]--
+		}
+	}
+}
+
+// QuotaUsage returns the usage of each quota rule, in the order the rules
+// were configured. It returns nil when no quotas are configured.
+func (r *Registry) QuotaUsage() []QuotaUsage {
+	t := r.quotas
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollDay()
+
+	usage := make([]QuotaUsage, len(t.rules))
+	for i, rule := range t.rules {
+		usage[i] = QuotaUsage{
+			Rule:              rule,
+			Day:               t.day,
+			VersionsToday:     make(map[string]int),
+			SubjectsByContext: make(map[string]int),
+		}
+	}
+	index := make(map[string]int, len(t.rules))
+	for i, rule := range t.rules {
+		index[rule.Name] = i
+	}
+	for key, n := range t.versions {
+		if n > 0 {
+			usage[index[key.rule]].VersionsToday[key.name] = n
+		}
+	}
+	for key, n := range t.subjects {
+		if n > 0 {
+			usage[index[key.rule]].SubjectsByContext[key.name] = n
+		}
+	}
+	return usage
+}
+
+// ResetQuotaUsage resets the counters of a quota rule. A non-empty principal
+// only resets that principal's version count, and a non-empty context only
+// that context's subject count; with neither, every counter of the rule is
+// reset. It returns ErrQuotaRuleNotFound for an unknown rule.
+func (r *Registry) ResetQuotaUsage(rule, principal, registryCtx string) error {
+	t := r.quotas
+	if t == nil || !slices.ContainsFunc(t.rules, func(q QuotaRule) bool { return q.Name == rule }) {
+		return fmt.Errorf("quota %s: %w", rule, ErrQuotaRuleNotFound)
+	}
+	if registryCtx != "" {
+		registryCtx = registrycontext.NormalizeContextName(registryCtx)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	all := principal == "" && registryCtx == ""
+	for key := range t.versions {
+		if key.rule == rule && (all || key.name == principal) {
+			delete(t.versions, key)
+		}
+	}
+	for key := range t.subjects {
+		if key.rule == rule && (all || key.name == registryCtx) {
+			delete(t.subjects, key)
+		}
+	}
+	return nil
+}
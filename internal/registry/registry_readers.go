@@ -0,0 +1,148 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// maxReaderConsumerLength limits the length of a consumer name.
+const maxReaderConsumerLength = 255
+
+// ReaderCheck is the result of checking a proposed schema against one
+// consumer's reader schema.
+type ReaderCheck struct {
+	Reader *storage.ReaderAssertionRecord
+	Result *compatibility.Result
+}
+
+// ReadersCheckResult is the result of checking a proposed schema against the
+// subject's own versions and against the reader schemas of its consumers.
+type ReadersCheckResult struct {
+	IsCompatible bool
+	Subject      *compatibility.Result
+	Readers      []ReaderCheck
+}
+
+// SetReaderAssertion records that consumer reads subject with a version of
+// readerSubject, which defaults to subject itself. The reader version must
+// exist; -1 means its latest version, which is resolved and stored, so the
+// assertion does not move when new versions are registered.
+func (r *Registry) SetReaderAssertion(ctx context.Context, registryCtx string, subject string, consumer string, readerSubject string, readerVersion int) (*storage.ReaderAssertionRecord, error) {
+	consumer = strings.TrimSpace(consumer)
+	if consumer == "" {
+		return nil, fmt.Errorf("%w: consumer must not be empty", ErrInvalidReader)
+	}
+	if len(consumer) > maxReaderConsumerLength {
+		return nil, fmt.Errorf("%w: consumer must be at most %d characters", ErrInvalidReader, maxReaderConsumerLength)
+	}
+	if readerSubject == "" {
+		readerSubject = subject
+	}
+	if readerVersion == 0 || readerVersion < -1 {
+		return nil, fmt.Errorf("%w: version must be a positive version number or -1 for the latest", ErrInvalidReader)
+	}
+	schema, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, readerSubject, readerVersion)
+	if err != nil {
+		if errors.Is(err, storage.ErrSubjectNotFound) || errors.Is(err, storage.ErrVersionNotFound) {
+			return nil, fmt.Errorf("%w: version %d of subject %s does not exist", ErrInvalidReader, readerVersion, readerSubject)
+		}
+		return nil, err
+	}
+
+	record := &storage.ReaderAssertionRecord{
+		Subject:       subject,
+		Consumer:      consumer,
+		ReaderSubject: readerSubject,
+		ReaderVersion: schema.Version,
+	}
+	if err := r.storage.SetReaderAssertion(ctx, registryCtx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// DeleteReaderAssertion removes consumer's reader assertion for subject.
+func (r *Registry) DeleteReaderAssertion(ctx context.Context, registryCtx string, subject string, consumer string) error {
+	return r.storage.DeleteReaderAssertion(ctx, registryCtx, subject, consumer)
+}
+
+// ListReaderAssertions returns the reader assertions for subject, or for
+// every subject in the context when subject is empty, limited to one
+// consumer when consumer is not empty.
+func (r *Registry) ListReaderAssertions(ctx context.Context, registryCtx string, subject string, consumer string) ([]*storage.ReaderAssertionRecord, error) {
+	records, err := r.storage.ListReaderAssertions(ctx, registryCtx, subject)
+	if err != nil || consumer == "" {
+		return records, err
+	}
+	filtered := make([]*storage.ReaderAssertionRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.Consumer == consumer {
+			filtered = append(filtered, rec)
+		}
+	}
+	return filtered, nil
+}
+
+// CheckReaders checks a schema proposed for subject the way registering it
+// would, against the subject's versions under its compatibility level, and
+// also against the reader schema of every consumer asserted for the subject:
+// each reader must be able to read data written with the proposed schema,
+// whatever the subject's compatibility level. A reader whose schema no
+// longer exists or has a different schema type is incompatible.
+func (r *Registry) CheckReaders(ctx context.Context, registryCtx string, subject string, schemaStr string, schemaType storage.SchemaType, refs []storage.Reference, normalize bool) (*ReadersCheckResult, error) {
+	proposed, schemaType, err := r.parseProposedSchema(ctx, registryCtx, subject, schemaStr, schemaType, refs, normalize)
+	if err != nil {
+		return nil, err
+	}
+	subjectResult, err := r.checkCompatibility(ctx, registryCtx, subject, schemaStr, schemaType, refs, "", normalize)
+	if err != nil {
+		return nil, err
+	}
+
+	readers, err := r.storage.ListReaderAssertions(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, err
+	}
+	result := &ReadersCheckResult{
+		IsCompatible: subjectResult.IsCompatible,
+		Subject:      subjectResult,
+		Readers:      make([]ReaderCheck, 0, len(readers)),
+	}
+	for _, reader := range readers {
+		check, err := r.checkReader(ctx, registryCtx, schemaType, proposed, reader)
+		if err != nil {
+			return nil, err
+		}
+		if !check.IsCompatible {
+			result.IsCompatible = false
+		}
+		result.Readers = append(result.Readers, ReaderCheck{Reader: reader, Result: check})
+	}
+	return result, nil
+}
+
+// checkReader checks that reader's schema can read data written with the
+// proposed schema.
+func (r *Registry) checkReader(ctx context.Context, registryCtx string, schemaType storage.SchemaType, proposed compatibility.SchemaWithRefs, reader *storage.ReaderAssertionRecord) (*compatibility.Result, error) {
+	schema, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, reader.ReaderSubject, reader.ReaderVersion)
+	if err != nil {
+		if errors.Is(err, storage.ErrSubjectNotFound) || errors.Is(err, storage.ErrVersionNotFound) {
+			return compatibility.NewIncompatibleResult(fmt.Sprintf("reader schema version %d of subject %s no longer exists", reader.ReaderVersion, reader.ReaderSubject)), nil
+		}
+		return nil, err
+	}
+	if schema.SchemaType != schemaType {
+		return compatibility.NewIncompatibleResult(fmt.Sprintf("reader schema version %d of subject %s is %s, not %s", reader.ReaderVersion, reader.ReaderSubject, schema.SchemaType, schemaType)), nil
+	}
+	readerRefs, err := r.resolveReferences(ctx, registryCtx, schema.References)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve existing schema references: %w", err)
+	}
+	readerSchema := compatibility.SchemaWithRefs{Schema: schema.Schema, References: readerRefs}
+	return r.compatChecker.CheckVersion(compatibility.ModeForward, schemaType, proposed, readerSchema, schema.Version), nil
+}
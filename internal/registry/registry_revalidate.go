@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SubjectRevalidation is the outcome of re-checking a subject's stored
+// versions against its current compatibility level.
+type SubjectRevalidation struct {
+	Subject            string
+	CompatibilityLevel string
+	PairsChecked       int
+	Incompatible       []IncompatiblePair
+}
+
+// IncompatiblePair is a pair of adjacent versions that the current
+// compatibility rules no longer accept.
+type IncompatiblePair struct {
+	PreviousVersion int
+	Version         int
+	Messages        []string
+}
+
+// RevalidateSubject re-checks every pair of adjacent live versions of a
+// subject with the current compatibility checkers and the subject's current
+// compatibility level, and reports the pairs that would now be rejected.
+// Nothing is modified. When the subject has a compatibility group, only
+// versions in the same group are paired, as at registration.
+//
+// beforePair, if not nil, is called before each pair is checked so callers
+// can throttle the work; an error from it stops the check and is returned.
+func (r *Registry) RevalidateSubject(ctx context.Context, registryCtx string, subject string, beforePair func(context.Context) error) (*SubjectRevalidation, error) {
+	level, err := r.GetConfig(ctx, registryCtx, subject)
+	if err != nil {
+		level = r.defaultCompatibility()
+	}
+	result := &SubjectRevalidation{Subject: subject, CompatibilityLevel: level}
+	mode := compatibility.Mode(level)
+	if mode == compatibility.ModeNone {
+		return result, nil
+	}
+
+	versions, err := r.storage.GetSchemasBySubject(ctx, registryCtx, subject, false)
+	if errors.Is(err, storage.ErrSubjectNotFound) {
+		// Deleted since it was listed; nothing left to check.
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Pair each version with the previous one in its compatibility group.
+	groupKey := ""
+	if cfg, err := r.GetSubjectConfigFull(ctx, registryCtx, subject); err == nil && cfg != nil {
+		groupKey = cfg.CompatibilityGroup
+	}
+	previous := make(map[string]*storage.SchemaRecord)
+	for _, v := range versions {
+		group := ""
+		if groupKey != "" && v.Metadata != nil {
+			group = v.Metadata.Properties[groupKey]
+		}
+		prev := previous[group]
+		previous[group] = v
+		if prev == nil {
+			continue
+		}
+
+		if beforePair != nil {
+			if err := beforePair(ctx); err != nil {
+				return nil, err
+			}
+		}
+		messages, err := r.checkVersionPair(ctx, registryCtx, mode, prev, v)
+		if err != nil {
+			return nil, fmt.Errorf("subject %s versions %d and %d: %w", subject, prev.Version, v.Version, err)
+		}
+		result.PairsChecked++
+		if len(messages) > 0 {
+			result.Incompatible = append(result.Incompatible, IncompatiblePair{
+				PreviousVersion: prev.Version,
+				Version:         v.Version,
+				Messages:        messages,
+			})
+		}
+	}
+	return result, nil
+}
+
+// checkVersionPair checks a version against the version before it and
+// returns the reasons it is incompatible, if any.
+func (r *Registry) checkVersionPair(ctx context.Context, registryCtx string, mode compatibility.Mode, prev, next *storage.SchemaRecord) ([]string, error) {
+	if prev.SchemaType != next.SchemaType {
+		return []string{fmt.Sprintf("schema type changed from %s to %s", prev.SchemaType, next.SchemaType)}, nil
+	}
+	prevRefs, err := r.resolveReferences(ctx, registryCtx, prev.References)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve references: %w", err)
+	}
+	nextRefs, err := r.resolveReferences(ctx, registryCtx, next.References)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve references: %w", err)
+	}
+	res := r.compatChecker.CheckVersion(mode, next.SchemaType,
+		compatibility.SchemaWithRefs{Schema: next.Schema, References: nextRefs},
+		compatibility.SchemaWithRefs{Schema: prev.Schema, References: prevRefs},
+		prev.Version)
+	if res.IsCompatible {
+		return nil, nil
+	}
+	return res.Messages, nil
+}
@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Limits on the meta of a single subject.
+const (
+	maxSubjectMetaFieldLen   = 255
+	maxSubjectDescriptionLen = 4096
+	maxSubjectLinks          = 32
+	maxSubjectLinkNameLen    = 128
+	maxSubjectLinkURLLen     = 2048
+	maxSubjectReviewers      = 32
+)
+
+// SetSubjectMeta replaces the owner, description, contact, links and
+// reviewers of a subject. Fields are trimmed; links must be absolute http or
+// https URLs and reviewers usernames or roles written as role:<name>.
+func (r *Registry) SetSubjectMeta(ctx context.Context, registryCtx string, record *storage.SubjectMetaRecord) (*storage.SubjectMetaRecord, error) {
+	if err := r.checkSubjectExists(ctx, registryCtx, record.Subject); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeSubjectMeta(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.storage.SetSubjectMeta(ctx, registryCtx, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// GetSubjectMeta returns the meta of a subject, or an empty record when none
+// is stored.
+func (r *Registry) GetSubjectMeta(ctx context.Context, registryCtx string, subject string) (*storage.SubjectMetaRecord, error) {
+	if err := r.checkSubjectExists(ctx, registryCtx, subject); err != nil {
+		return nil, err
+	}
+	record, err := r.storage.GetSubjectMeta(ctx, registryCtx, subject)
+	if errors.Is(err, storage.ErrSubjectMetaNotFound) {
+		return &storage.SubjectMetaRecord{Subject: subject}, nil
+	}
+	return record, err
+}
+
+// DeleteSubjectMeta removes the meta of a subject. Removing meta that was
+// never set is not an error.
+func (r *Registry) DeleteSubjectMeta(ctx context.Context, registryCtx string, subject string) error {
+	if err := r.checkSubjectExists(ctx, registryCtx, subject); err != nil {
+		return err
+	}
+	err := r.storage.DeleteSubjectMeta(ctx, registryCtx, subject)
+	if errors.Is(err, storage.ErrSubjectMetaNotFound) {
+		return nil
+	}
+	return err
+}
+
+// checkSubjectExists returns storage.ErrSubjectNotFound unless the subject
+// has a version that is not soft-deleted.
+func (r *Registry) checkSubjectExists(ctx context.Context, registryCtx string, subject string) error {
+	exists, err := r.storage.SubjectExists(ctx, registryCtx, subject)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return storage.ErrSubjectNotFound
+	}
+	return nil
+}
+
+func normalizeSubjectMeta(record *storage.SubjectMetaRecord) (*storage.SubjectMetaRecord, error) {
+	out := &storage.SubjectMetaRecord{
+		Subject:     record.Subject,
+		Owner:       strings.TrimSpace(record.Owner),
+		Description: strings.TrimSpace(record.Description),
+		Contact:     strings.TrimSpace(record.Contact),
+	}
+	if len(out.Owner) > maxSubjectMetaFieldLen {
+		return nil, fmt.Errorf("%w: owner is longer than %d characters", ErrInvalidSubjectMeta, maxSubjectMetaFieldLen)
+	}
+	if len(out.Contact) > maxSubjectMetaFieldLen {
+		return nil, fmt.Errorf("%w: contact is longer than %d characters", ErrInvalidSubjectMeta, maxSubjectMetaFieldLen)
+	}
+	if len(out.Description) > maxSubjectDescriptionLen {
+		return nil, fmt.Errorf("%w: description is longer than %d characters", ErrInvalidSubjectMeta, maxSubjectDescriptionLen)
+	}
+	if len(record.Links) > maxSubjectLinks {
+		return nil, fmt.Errorf("%w: at most %d links are allowed", ErrInvalidSubjectMeta, maxSubjectLinks)
+	}
+	for _, link := range record.Links {
+		link.Name = strings.TrimSpace(link.Name)
+		link.URL = strings.TrimSpace(link.URL)
+		if link.Name == "" {
+			return nil, fmt.Errorf("%w: link names must not be empty", ErrInvalidSubjectMeta)
+		}
+		if len(link.Name) > maxSubjectLinkNameLen {
+			return nil, fmt.Errorf("%w: link name %q is longer than %d characters", ErrInvalidSubjectMeta, link.Name, maxSubjectLinkNameLen)
+		}
+		if len(link.URL) > maxSubjectLinkURLLen {
+			return nil, fmt.Errorf("%w: URL of link %q is longer than %d characters", ErrInvalidSubjectMeta, link.Name, maxSubjectLinkURLLen)
+		}
+		u, err := url.Parse(link.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: URL of link %q must be an absolute http or https URL", ErrInvalidSubjectMeta, link.Name)
+		}
+		out.Links = append(out.Links, link)
+	}
+	if len(record.Reviewers) > maxSubjectReviewers {
+		return nil, fmt.Errorf("%w: at most %d reviewers are allowed", ErrInvalidSubjectMeta, maxSubjectReviewers)
+	}
+	for _, reviewer := range record.Reviewers {
+		reviewer = strings.TrimSpace(reviewer)
+		if reviewer == "" || reviewer == ReviewerRolePrefix {
+			return nil, fmt.Errorf("%w: reviewers must name a user or a role", ErrInvalidSubjectMeta)
+		}
+		if len(reviewer) > maxSubjectMetaFieldLen {
+			return nil, fmt.Errorf("%w: reviewer %q is longer than %d characters", ErrInvalidSubjectMeta, reviewer, maxSubjectMetaFieldLen)
+		}
+		out.Reviewers = append(out.Reviewers, reviewer)
+	}
+	return out, nil
+}
@@ -0,0 +1,275 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/axonops/axonops-schema-registry/internal/analysis"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// Limits on the tags and labels of a single subject or version, so that
+// governance metadata cannot grow without bound.
+const (
+	maxTags          = 64
+	maxTagLength     = 128
+	maxLabels        = 64
+	maxLabelKeyLen   = 128
+	maxLabelValueLen = 1024
+)
+
+// SetTags replaces the tags and labels of a subject, or of one of its
+// versions when version is non-zero (-1 means the latest version). Tags are
+// trimmed, de-duplicated case-insensitively and sorted.
+func (r *Registry) SetTags(ctx context.Context, registryCtx string, subject string, version int, tags []string, labels map[string]string) (*storage.TagsRecord, error) {
+	version, err := r.resolveTagTarget(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, err
+	}
+	record := &storage.TagsRecord{Subject: subject, Version: version}
+	if record.Tags, err = normalizeTags(tags); err != nil {
+		return nil, err
+	}
+	if record.Labels, err = normalizeLabels(labels); err != nil {
+		return nil, err
+	}
+	if err := r.storage.SetTags(ctx, registryCtx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetTags returns the tags and labels stored on a subject or one of its
+// versions, or an empty record when none are stored.
+func (r *Registry) GetTags(ctx context.Context, registryCtx string, subject string, version int) (*storage.TagsRecord, error) {
+	version, err := r.resolveTagTarget(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, err
+	}
+	record, err := r.storage.GetTags(ctx, registryCtx, subject, version)
+	if errors.Is(err, storage.ErrTagsNotFound) {
+		return &storage.TagsRecord{Subject: subject, Version: version, Tags: []string{}, Labels: map[string]string{}}, nil
+	}
+	return record, err
+}
+
+// DeleteTags removes the tags and labels of a subject or one of its
+// versions. Removing tags that were never set is not an error.
+func (r *Registry) DeleteTags(ctx context.Context, registryCtx string, subject string, version int) error {
+	version, err := r.resolveTagTarget(ctx, registryCtx, subject, version)
+	if err != nil {
+		return err
+	}
+	err = r.storage.DeleteTags(ctx, registryCtx, subject, version)
+	if errors.Is(err, storage.ErrTagsNotFound) {
+		return nil
+	}
+	return err
+}
+
+// resolveTagTarget checks that the subject, or the version of it, exists
+// and is not soft-deleted, and returns the concrete version (0 for the
+// subject itself).
+func (r *Registry) resolveTagTarget(ctx context.Context, registryCtx string, subject string, version int) (int, error) {
+	if version == 0 {
+		exists, err := r.storage.SubjectExists(ctx, registryCtx, subject)
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			return 0, storage.ErrSubjectNotFound
+		}
+		return 0, nil
+	}
+	record, err := r.storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return 0, err
+	}
+	if record.Deleted {
+		return 0, storage.ErrVersionNotFound
+	}
+	return record.Version, nil
+}
+
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) > maxTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidTags, maxTags)
+	}
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags must not be empty", ErrInvalidTags)
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidTags, tag, maxTagLength)
+		}
+		if seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func normalizeLabels(labels map[string]string) (map[string]string, error) {
+	if len(labels) > maxLabels {
+		return nil, fmt.Errorf("%w: at most %d labels are allowed", ErrInvalidTags, maxLabels)
+	}
+	out := make(map[string]string, len(labels))
+	for key, value := range labels {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("%w: label keys must not be empty", ErrInvalidTags)
+		}
+		if len(key) > maxLabelKeyLen {
+			return nil, fmt.Errorf("%w: label key %q is longer than %d characters", ErrInvalidTags, key, maxLabelKeyLen)
+		}
+		if len(value) > maxLabelValueLen {
+			return nil, fmt.Errorf("%w: value of label %q is longer than %d characters", ErrInvalidTags, key, maxLabelValueLen)
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// SchemaSearchOptions selects the schema versions returned by
+// SearchSchemas. Zero-valued fields match everything.
+type SchemaSearchOptions struct {
+	Tags          []string          // Every tag must be present (case-insensitive)
+	Labels        map[string]string // Every label must match; an empty value only requires the key
+	SchemaType    string            // AVRO, JSON or PROTOBUF
+	SubjectPrefix string
+	Field         string // Field name or dotted path (case-insensitive)
+	AllVersions   bool   // Search every live version instead of only the latest
+	Limit         int    // Non-positive means no limit
+}
+
+// SchemaSearchResult is a schema version matching a search, with its
+// effective tags and labels: those of the version combined with those of
+// its subject, version labels taking precedence.
+type SchemaSearchResult struct {
+	Schema *storage.SchemaRecord
+	Tags   []string
+	Labels map[string]string
+	Fields []string // Paths of the fields matching SchemaSearchOptions.Field
+}
+
+// SearchSchemas finds the schema versions in a context matching every
+// filter in opts, ordered by subject and version.
+func (r *Registry) SearchSchemas(ctx context.Context, registryCtx string, opts SchemaSearchOptions) ([]SchemaSearchResult, error) {
+	records, err := r.storage.ListTags(ctx, registryCtx)
+	if err != nil {
+		return nil, err
+	}
+	tagged := map[string]map[int]*storage.TagsRecord{}
+	for _, rec := range records {
+		if tagged[rec.Subject] == nil {
+			tagged[rec.Subject] = map[int]*storage.TagsRecord{}
+		}
+		tagged[rec.Subject][rec.Version] = rec
+	}
+
+	subjects, err := r.storage.ListSubjects(ctx, registryCtx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	byTag := len(opts.Tags) > 0 || len(opts.Labels) > 0
+	results := []SchemaSearchResult{}
+	for _, subject := range subjects {
+		if !strings.HasPrefix(subject, opts.SubjectPrefix) {
+			continue
+		}
+		// Only subjects with stored tags can match a tag or label filter.
+		if byTag && tagged[subject] == nil {
+			continue
+		}
+
+		var versions []*storage.SchemaRecord
+		if opts.AllVersions {
+			versions, err = r.storage.GetSchemasBySubject(ctx, registryCtx, subject, false)
+		} else {
+			var latest *storage.SchemaRecord
+			latest, err = r.storage.GetLatestSchema(ctx, registryCtx, subject)
+			versions = []*storage.SchemaRecord{latest}
+		}
+		if errors.Is(err, storage.ErrSubjectNotFound) || errors.Is(err, storage.ErrVersionNotFound) {
+			continue // deleted since it was listed
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, schema := range versions {
+			result, ok := matchSchema(schema, tagged[subject], opts)
+			if !ok {
+				continue
+			}
+			results = append(results, result)
+			if opts.Limit > 0 && len(results) >= opts.Limit {
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
+
+// matchSchema applies the search filters to one schema version.
+func matchSchema(schema *storage.SchemaRecord, tags map[int]*storage.TagsRecord, opts SchemaSearchOptions) (SchemaSearchResult, bool) {
+	schemaType := schema.SchemaType
+	if schemaType == "" {
+		schemaType = storage.SchemaTypeAvro
+	}
+	if opts.SchemaType != "" && !strings.EqualFold(string(schemaType), opts.SchemaType) {
+		return SchemaSearchResult{}, false
+	}
+
+	result := SchemaSearchResult{Schema: schema, Tags: []string{}, Labels: map[string]string{}}
+	seen := map[string]bool{}
+	for _, rec := range []*storage.TagsRecord{tags[0], tags[schema.Version]} {
+		if rec == nil {
+			continue
+		}
+		for _, tag := range rec.Tags {
+			if !seen[strings.ToLower(tag)] {
+				seen[strings.ToLower(tag)] = true
+				result.Tags = append(result.Tags, tag)
+			}
+		}
+		for k, v := range rec.Labels {
+			result.Labels[k] = v
+		}
+	}
+	sort.Strings(result.Tags)
+
+	for _, tag := range opts.Tags {
+		if !seen[strings.ToLower(tag)] {
+			return SchemaSearchResult{}, false
+		}
+	}
+	for k, want := range opts.Labels {
+		got, ok := result.Labels[k]
+		if !ok || (want != "" && got != want) {
+			return SchemaSearchResult{}, false
+		}
+	}
+
+	if opts.Field != "" {
+		for _, f := range analysis.ExtractFields(schema.Schema, schemaType) {
+			if strings.EqualFold(f.Name, opts.Field) || strings.EqualFold(f.Path, opts.Field) {
+				result.Fields = append(result.Fields, f.Path)
+			}
+		}
+		if len(result.Fields) == 0 {
+			return SchemaSearchResult{}, false
+		}
+	}
+	return result, true
+}
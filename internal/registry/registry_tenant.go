@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// TenantKeyGenerator generates the wrapped encryption key stored with a new
+// tenant.
+type TenantKeyGenerator interface {
+	NewTenantKey(tenant string) (string, error)
+}
+
+// tenantNamePattern restricts tenant names to characters that are safe in
+// key references and URLs.
+var tenantNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// SetTenantKeyGenerator sets the generator of tenant encryption keys. When
+// unset, tenants are created without a key and their schemas are stored in
+// plaintext.
+func (r *Registry) SetTenantKeyGenerator(gen TenantKeyGenerator) {
+	r.tenantKeys = gen
+}
+
+// TenantHomeContext returns the name of a tenant's home context, the single
+// context its users work in when contexts are derived from the principal.
+func TenantHomeContext(tenant string) string {
+	return "." + tenant
+}
+
+// SetTenantHomeContexts makes CreateTenant create each tenant's home context
+// along with the tenant, for principal-derived tenancy.
+func (r *Registry) SetTenantHomeContexts(enabled bool) {
+	r.tenantHomeContexts = enabled
+}
+
+// CreateTenant creates a tenant and, when tenant encryption is enabled,
+// generates its encryption key. With home contexts enabled it also creates
+// the tenant's home context, and fails if a context of that name exists.
+func (r *Registry) CreateTenant(ctx context.Context, record *storage.TenantRecord) error {
+	if !tenantNamePattern.MatchString(record.Name) {
+		return fmt.Errorf("invalid tenant name %q: %w", record.Name, ErrInvalidTenant)
+	}
+	record.EncryptedKey = ""
+	if r.tenantKeys != nil {
+		key, err := r.tenantKeys.NewTenantKey(record.Name)
+		if err != nil {
+			return err
+		}
+		record.EncryptedKey = key
+	}
+	if r.tenantHomeContexts {
+		home := TenantHomeContext(record.Name)
+		if _, err := r.storage.GetContext(ctx, home); err == nil {
+			return fmt.Errorf("home context %s already exists: %w", home, ErrInvalidTenant)
+		} else if !errors.Is(err, storage.ErrContextNotFound) {
+			return err
+		}
+	}
+	if err := r.storage.CreateTenant(ctx, record); err != nil {
+		return err
+	}
+	if r.tenantHomeContexts {
+		if err := r.CreateContext(ctx, &storage.ContextRecord{Name: TenantHomeContext(record.Name), Tenant: record.Name}); err != nil {
+			return fmt.Errorf("failed to create home context of tenant %s: %w", record.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetTenant retrieves a tenant.
+func (r *Registry) GetTenant(ctx context.Context, name string) (*storage.TenantRecord, error) {
+	return r.storage.GetTenant(ctx, name)
+}
+
+// ListTenants returns all tenants ordered by name.
+func (r *Registry) ListTenants(ctx context.Context) ([]*storage.TenantRecord, error) {
+	return r.storage.ListTenants(ctx)
+}
+
+// UpdateTenant updates the description of a tenant.
+func (r *Registry) UpdateTenant(ctx context.Context, record *storage.TenantRecord) error {
+	return r.storage.UpdateTenant(ctx, record)
+}
+
+// DeleteTenant deletes a tenant. A tenant that still owns contexts cannot be
+// deleted, since its key is needed to read their schemas.
+func (r *Registry) DeleteTenant(ctx context.Context, name string) error {
+	if _, err := r.storage.GetTenant(ctx, name); err != nil {
+		return err
+	}
+	contexts, err := r.TenantContexts(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(contexts) > 0 {
+		return fmt.Errorf("tenant %s owns %d context(s): %w", name, len(contexts), ErrTenantNotEmpty)
+	}
+	return r.storage.DeleteTenant(ctx, name)
+}
+
+// TenantContexts returns the names of the contexts owned by tenant, sorted.
+func (r *Registry) TenantContexts(ctx context.Context, tenant string) ([]string, error) {
+	names, err := r.storage.ListContexts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]string, 0)
+	for _, name := range names {
+		if owner, err := r.ContextTenant(ctx, name); err == nil && owner == tenant {
+			owned = append(owned, name)
+		}
+	}
+	return owned, nil
+}
+
+// ContextTenant returns the tenant that owns a context, or "" for contexts
+// without a tenant and contexts that do not exist.
+func (r *Registry) ContextTenant(ctx context.Context, name string) (string, error) {
+	if name == registrycontext.DefaultContext || registrycontext.IsGlobalContext(name) {
+		return "", nil
+	}
+	record, err := r.storage.GetContext(ctx, name)
+	if err != nil {
+		if errors.Is(err, storage.ErrContextNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return record.Tenant, nil
+}
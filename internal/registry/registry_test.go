@@ -2,15 +2,20 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/axonops/axonops-schema-registry/internal/compatibility"
 	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
 	jsonschemacompat "github.com/axonops/axonops-schema-registry/internal/compatibility/jsonschema"
 	protobufcompat "github.com/axonops/axonops-schema-registry/internal/compatibility/protobuf"
 	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/lint"
 	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
 	"github.com/axonops/axonops-schema-registry/internal/schema/jsonschema"
@@ -177,6 +182,38 @@ func TestRegisterSchema_Protobuf(t *testing.T) {
 	}
 }
 
+func TestRegisterSchema_ProtobufDescriptor(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+
+	source, err := reg.RegisterSchema(ctx, ".", "proto-source", `syntax = "proto3"; message User { string name = 1; }`, storage.SchemaTypeProtobuf, nil)
+	if err != nil {
+		t.Fatalf("failed to register Protobuf schema: %v", err)
+	}
+	descriptor := reg.FormatSchema(ctx, ".", source, "serialized")
+
+	// A descriptor is stored as .proto source and gets the ID of the same
+	// schema registered as source.
+	record, err := reg.RegisterSchema(ctx, ".", "proto-descriptor", descriptor, storage.SchemaTypeProtobuf, nil)
+	if err != nil {
+		t.Fatalf("failed to register descriptor: %v", err)
+	}
+	if record.ID != source.ID {
+		t.Errorf("expected ID %d, got %d", source.ID, record.ID)
+	}
+	if !strings.HasPrefix(record.Schema, `syntax = "proto3";`) || !strings.Contains(record.Schema, "string name = 1;") {
+		t.Errorf("expected .proto source to be stored, got %q", record.Schema)
+	}
+
+	again, err := reg.RegisterSchema(ctx, ".", "proto-source", descriptor, storage.SchemaTypeProtobuf, nil)
+	if err != nil {
+		t.Fatalf("failed to register descriptor: %v", err)
+	}
+	if again.Version != source.Version {
+		t.Errorf("expected the descriptor to match version %d, got %d", source.Version, again.Version)
+	}
+}
+
 // --- GetSchemaByID tests ---
 
 func TestGetSchemaByID(t *testing.T) {
@@ -380,6 +417,33 @@ func TestDeleteVersion_ReferencedBlocked(t *testing.T) {
 	}
 }
 
+func TestFormatSchema_ResolvedNestedReferences(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	street := `{"type":"record","name":"Street","namespace":"test","fields":[{"name":"line","type":"string"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "street", street, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("failed to register street: %v", err)
+	}
+	address := `{"type":"record","name":"Address","namespace":"test","fields":[{"name":"street","type":"test.Street"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "address", address, storage.SchemaTypeAvro,
+		[]storage.Reference{{Name: "test.Street", Subject: "street", Version: 1}}); err != nil {
+		t.Fatalf("failed to register address: %v", err)
+	}
+	order := `{"type":"record","name":"Order","namespace":"test","fields":[{"name":"shipping","type":"test.Address"}]}`
+	record, err := reg.RegisterSchema(ctx, ".", "order", order, storage.SchemaTypeAvro,
+		[]storage.Reference{{Name: "test.Address", Subject: "address", Version: 1}})
+	if err != nil {
+		t.Fatalf("failed to register order: %v", err)
+	}
+
+	// The reference's own reference must be inlined too.
+	resolved := reg.FormatSchema(ctx, ".", record, "resolved")
+	if !strings.Contains(resolved, `"name":"test.Street"`) || !strings.Contains(resolved, `"name":"line"`) {
+		t.Errorf("expected Street to be inlined, got %s", resolved)
+	}
+}
+
 // --- Config tests ---
 
 func TestGetConfig_SubjectFallsBackToGlobal(t *testing.T) {
@@ -409,6 +473,31 @@ func TestGetConfig_GlobalReturnsStoredValue(t *testing.T) {
 	}
 }
 
+func TestSetDefaultCompatibility(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+	if err := reg.storage.DeleteGlobalConfig(ctx, "."); err != nil {
+		t.Fatalf("failed to delete global config: %v", err)
+	}
+
+	reg.SetDefaultCompatibility("FULL_TRANSITIVE")
+	level, err := reg.GetConfig(ctx, ".", "my-subject")
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	if level != "FULL_TRANSITIVE" {
+		t.Errorf("expected the new default FULL_TRANSITIVE, got %s", level)
+	}
+
+	// A stored config still takes precedence over the default.
+	if err := reg.SetConfig(ctx, ".", "my-subject", "NONE", nil); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+	if level, _ := reg.GetConfig(ctx, ".", "my-subject"); level != "NONE" {
+		t.Errorf("expected NONE, got %s", level)
+	}
+}
+
 func TestSetConfig_SubjectLevel(t *testing.T) {
 	reg := setupTestRegistry("BACKWARD")
 	ctx := context.Background()
@@ -1717,6 +1806,142 @@ func TestRegisterSchema_WithReferences_FailsOnMissingRef(t *testing.T) {
 	}
 }
 
+func TestRegisterSchema_ReferenceCycle(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	// Write a cycle directly to storage (a:1 -> b:1 -> a:1); the registry API
+	// itself never produces one, but imported or legacy data can.
+	for _, rec := range []*storage.SchemaRecord{
+		{Subject: "a", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"A","fields":[]}`, Fingerprint: "fa",
+			References: []storage.Reference{{Name: "B", Subject: "b", Version: 1}}},
+		{Subject: "b", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"B","fields":[]}`, Fingerprint: "fb",
+			References: []storage.Reference{{Name: "A", Subject: "a", Version: 1}}},
+	} {
+		if err := reg.storage.CreateSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+	}
+
+	schema := `{"type":"record","name":"C","fields":[{"name":"a","type":"A"}]}`
+	refs := []storage.Reference{{Name: "A", Subject: "a", Version: 1}}
+	_, err := reg.RegisterSchema(ctx, ".", "c", schema, storage.SchemaTypeAvro, refs)
+	if !errors.Is(err, ErrReferenceCycle) {
+		t.Fatalf("expected ErrReferenceCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "a:1 -> b:1 -> a:1") {
+		t.Errorf("expected cycle path in error, got: %v", err)
+	}
+}
+
+func TestRegisterSchema_ProtobufTransitiveReferences(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+
+	// order.proto imports customer.proto, which imports address.proto. Only
+	// the direct import is declared; address.proto must still be resolved.
+	address := `syntax = "proto3";
+package common;
+message Address {
+  string city = 1;
+}`
+	customer := `syntax = "proto3";
+package common;
+import "common/address.proto";
+message Customer {
+  string name = 1;
+  Address address = 2;
+}`
+	order := `syntax = "proto3";
+package orders;
+import "common/customer.proto";
+message Order {
+  common.Customer customer = 1;
+}`
+	if _, err := reg.RegisterSchema(ctx, ".", "common-address", address, storage.SchemaTypeProtobuf, nil); err != nil {
+		t.Fatalf("register address: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "common-customer", customer, storage.SchemaTypeProtobuf,
+		[]storage.Reference{{Name: "common/address.proto", Subject: "common-address", Version: 1}}); err != nil {
+		t.Fatalf("register customer: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", order, storage.SchemaTypeProtobuf,
+		[]storage.Reference{{Name: "common/customer.proto", Subject: "common-customer", Version: 1}}); err != nil {
+		t.Fatalf("register order: %v", err)
+	}
+}
+
+func TestRegisterSchema_ReferenceErrorsNameChain(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	// c:1 -> b:1 -> a:1, where a:1 references a missing subject.
+	for _, rec := range []*storage.SchemaRecord{
+		{Subject: "a", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"A","fields":[]}`, Fingerprint: "fa",
+			References: []storage.Reference{{Name: "Missing", Subject: "missing", Version: 1}}},
+		{Subject: "b", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"B","fields":[]}`, Fingerprint: "fb",
+			References: []storage.Reference{{Name: "A", Subject: "a", Version: 1}}},
+		{Subject: "c", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"C","fields":[]}`, Fingerprint: "fc",
+			References: []storage.Reference{{Name: "B", Subject: "b", Version: 1}}},
+	} {
+		if err := reg.storage.CreateSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+	}
+
+	schema := `{"type":"record","name":"D","fields":[{"name":"c","type":"C"}]}`
+	refs := []storage.Reference{{Name: "C", Subject: "c", Version: 1}}
+	_, err := reg.RegisterSchema(ctx, ".", "d", schema, storage.SchemaTypeAvro, refs)
+	if !errors.Is(err, ErrFailedResolveReferences) {
+		t.Fatalf("expected ErrFailedResolveReferences, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "c:1 -> b:1 -> a:1 -> missing:1") {
+		t.Errorf("expected reference chain in error, got: %v", err)
+	}
+
+	reg.SetMaxReferenceDepth(2)
+	_, err = reg.RegisterSchema(ctx, ".", "d", schema, storage.SchemaTypeAvro, refs)
+	if !errors.Is(err, ErrReferenceDepthExceeded) {
+		t.Fatalf("expected ErrReferenceDepthExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "c:1 -> b:1 -> a:1 is 3 references deep, the maximum is 2") {
+		t.Errorf("expected depth and chain in error, got: %v", err)
+	}
+}
+
+func TestRegisterSchemaWithID_ReferenceCycleToSelf(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	// b:1 references a:1, which is about to be imported with a reference to b:1.
+	if err := reg.storage.CreateSchema(ctx, ".", &storage.SchemaRecord{
+		Subject: "b", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"record","name":"B","fields":[]}`, Fingerprint: "fb",
+		References: []storage.Reference{{Name: "A", Subject: "a", Version: 1}},
+	}); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+
+	schema := `{"type":"record","name":"A","fields":[{"name":"b","type":"B"}]}`
+	refs := []storage.Reference{{Name: "B", Subject: "b", Version: 1}}
+	_, err := reg.RegisterSchemaWithID(ctx, ".", "a", schema, storage.SchemaTypeAvro, refs, 500, 1)
+	if !errors.Is(err, ErrReferenceCycle) {
+		t.Fatalf("expected ErrReferenceCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "a:1 -> b:1 -> a:1") {
+		t.Errorf("expected cycle path in error, got: %v", err)
+	}
+
+	result, err := reg.ImportSchemas(ctx, ".", []ImportSchemaRequest{{
+		ID: 500, Subject: "a", Version: 1, SchemaType: storage.SchemaTypeAvro, Schema: schema, References: refs,
+	}})
+	if err != nil {
+		t.Fatalf("ImportSchemas: %v", err)
+	}
+	if result.Errors != 1 || !strings.Contains(result.Results[0].Error, "reference cycle") {
+		t.Errorf("expected import to fail with reference cycle, got %+v", result.Results)
+	}
+}
+
 func TestRegisterSchema_WithProtobufReferences(t *testing.T) {
 	reg := setupMultiTypeRegistry("NONE")
 	ctx := context.Background()
@@ -3086,6 +3311,58 @@ func TestRegisterSchema_SameTextDifferentMetadata_CreatesNewVersion(t *testing.T
 	}
 }
 
+func TestRegisterSchemaIfAbsent_DifferentMetadataReturnsExisting(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	schemaStr := `{"type":"record","name":"Idem","fields":[{"name":"id","type":"int"}]}`
+	validated, err := reg.ValidateSchema(ctx, ".", schemaStr, storage.SchemaTypeAvro, nil)
+	if err != nil || !validated.Valid {
+		t.Fatalf("validate failed: %v", err)
+	}
+
+	rec1, created, err := reg.RegisterSchemaIfAbsent(ctx, ".", "idem", validated.Fingerprint, schemaStr, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("first register failed: %v", err)
+	}
+	if !created {
+		t.Error("expected first registration to create a version")
+	}
+
+	// A retry with different metadata must not create a new version
+	rec2, created, err := reg.RegisterSchemaIfAbsent(ctx, ".", "idem", validated.Fingerprint, schemaStr, storage.SchemaTypeAvro, nil, RegisterOpts{
+		Metadata: &storage.Metadata{Properties: map[string]string{"build": "42"}},
+	})
+	if err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if created {
+		t.Error("expected retry to return the existing version")
+	}
+	if rec1.ID != rec2.ID || rec1.Version != rec2.Version {
+		t.Errorf("expected id %d version %d, got id %d version %d", rec1.ID, rec1.Version, rec2.ID, rec2.Version)
+	}
+
+	versions, err := reg.GetVersions(ctx, ".", "idem", false)
+	if err != nil {
+		t.Fatalf("get versions failed: %v", err)
+	}
+	if len(versions) != 1 {
+		t.Errorf("expected 1 version, got %v", versions)
+	}
+}
+
+func TestRegisterSchemaIfAbsent_FingerprintMismatch(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	schemaStr := `{"type":"record","name":"Idem","fields":[{"name":"id","type":"int"}]}`
+	_, _, err := reg.RegisterSchemaIfAbsent(ctx, ".", "idem", "0000", schemaStr, storage.SchemaTypeAvro, nil)
+	if !errors.Is(err, ErrFingerprintMismatch) {
+		t.Fatalf("expected ErrFingerprintMismatch, got %v", err)
+	}
+}
+
 func TestRegisterSchema_SameTextSameMetadata_ReturnsSameID(t *testing.T) {
 	reg := setupTestRegistry("NONE")
 	ctx := context.Background()
@@ -3335,3 +3612,2340 @@ func TestRegisterSchema_PreviousSchemaMetadataInheritance(t *testing.T) {
 		t.Errorf("expected owner=team-a inherited from v1, got %s", rec2.Metadata.Properties["owner"])
 	}
 }
+
+func TestGetCompatibleVersions(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	v1 := `{"type":"record","name":"Order","fields":[{"name":"a","type":"int"}]}`
+	v2 := `{"type":"record","name":"Order","fields":[{"name":"a","type":"int"},{"name":"b","type":"string"}]}`
+	v3 := `{"type":"record","name":"Order","fields":[{"name":"c","type":"string"}]}`
+	var ids []int64
+	for _, schemaStr := range []string{v1, v2, v3} {
+		rec, err := reg.RegisterSchema(ctx, ".", "orders-value", schemaStr, storage.SchemaTypeAvro, nil)
+		if err != nil {
+			t.Fatalf("RegisterSchema: %v", err)
+		}
+		ids = append(ids, rec.ID)
+	}
+
+	tests := []struct {
+		name     string
+		level    string
+		readerID int64
+		want     []int
+	}{
+		{"v1 reads v1 and v2", "BACKWARD", ids[0], []int{1, 2}},
+		{"v2 needs field b", "BACKWARD", ids[1], []int{2}},
+		{"FULL requires both directions", "FULL", ids[0], []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := reg.SetConfig(ctx, ".", "orders-value", tt.level, nil); err != nil {
+				t.Fatalf("SetConfig: %v", err)
+			}
+			got, level, err := reg.GetCompatibleVersions(ctx, ".", "orders-value", tt.readerID)
+			if err != nil {
+				t.Fatalf("GetCompatibleVersions: %v", err)
+			}
+			if level != tt.level {
+				t.Errorf("expected level %s, got %s", tt.level, level)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected versions %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, _, err := reg.GetCompatibleVersions(ctx, ".", "orders-value", 9999); !errors.Is(err, storage.ErrSchemaNotFound) {
+		t.Errorf("expected ErrSchemaNotFound for unknown ID, got %v", err)
+	}
+	if _, _, err := reg.GetCompatibleVersions(ctx, ".", "missing", ids[0]); !errors.Is(err, storage.ErrSubjectNotFound) {
+		t.Errorf("expected ErrSubjectNotFound for unknown subject, got %v", err)
+	}
+}
+
+func TestCheckReaders(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	order := `{"type":"record","name":"Order","fields":[{"name":"a","type":"int"}]}`
+	billing := `{"type":"record","name":"Order","fields":[{"name":"a","type":"int"},{"name":"b","type":"string"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", order, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-billing", billing, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	if _, err := reg.SetReaderAssertion(ctx, ".", "orders-value", " ", "", 1); !errors.Is(err, ErrInvalidReader) {
+		t.Errorf("expected ErrInvalidReader for an empty consumer, got %v", err)
+	}
+	if _, err := reg.SetReaderAssertion(ctx, ".", "orders-value", "billing", "orders-billing", 2); !errors.Is(err, ErrInvalidReader) {
+		t.Errorf("expected ErrInvalidReader for a missing reader version, got %v", err)
+	}
+	rec, err := reg.SetReaderAssertion(ctx, ".", "orders-value", "billing", "orders-billing", -1)
+	if err != nil {
+		t.Fatalf("SetReaderAssertion: %v", err)
+	}
+	if rec.ReaderVersion != 1 {
+		t.Errorf("expected the latest version to be resolved to 1, got %d", rec.ReaderVersion)
+	}
+	if rec, err = reg.SetReaderAssertion(ctx, ".", "orders-value", "analytics", "", 1); err != nil || rec.ReaderSubject != "orders-value" {
+		t.Fatalf("expected the reader subject to default to the subject, got %+v, %v", rec, err)
+	}
+
+	readers, err := reg.ListReaderAssertions(ctx, ".", "", "billing")
+	if err != nil || len(readers) != 1 || readers[0].ReaderSubject != "orders-billing" {
+		t.Errorf("expected only the billing reader, got %v, %v", readers, err)
+	}
+
+	// Adding b is safe for both readers, even though billing's reader
+	// schema is registered under another subject.
+	result, err := reg.CheckReaders(ctx, ".", "orders-value", billing, storage.SchemaTypeAvro, nil, false)
+	if err != nil {
+		t.Fatalf("CheckReaders: %v", err)
+	}
+	if !result.IsCompatible || len(result.Readers) != 2 {
+		t.Fatalf("expected both readers to be compatible, got %+v", result)
+	}
+
+	// Renaming a breaks both readers, although the subject's level is NONE.
+	renamed := `{"type":"record","name":"Order","fields":[{"name":"c","type":"int"}]}`
+	result, err = reg.CheckReaders(ctx, ".", "orders-value", renamed, storage.SchemaTypeAvro, nil, false)
+	if err != nil {
+		t.Fatalf("CheckReaders: %v", err)
+	}
+	if result.IsCompatible || !result.Subject.IsCompatible {
+		t.Errorf("expected only the readers to reject the schema, got %+v", result)
+	}
+	for _, check := range result.Readers {
+		if check.Result.IsCompatible {
+			t.Errorf("expected reader %s to be incompatible", check.Reader.Consumer)
+		}
+	}
+
+	if err := reg.DeleteReaderAssertion(ctx, ".", "orders-value", "billing"); err != nil {
+		t.Fatalf("DeleteReaderAssertion: %v", err)
+	}
+	if err := reg.DeleteReaderAssertion(ctx, ".", "orders-value", "billing"); !errors.Is(err, storage.ErrReaderAssertionNotFound) {
+		t.Errorf("expected ErrReaderAssertionNotFound, got %v", err)
+	}
+}
+
+// =============================================================================
+// Context Management Tests
+// =============================================================================
+
+func TestCreateContext_NormalizesName(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	rec := &storage.ContextRecord{Name: "team-a", Owner: "alice"}
+	if err := reg.CreateContext(ctx, rec); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	if rec.Name != ".team-a" {
+		t.Errorf("expected normalized name .team-a, got %s", rec.Name)
+	}
+
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: ".team-a"}); !errors.Is(err, storage.ErrContextExists) {
+		t.Errorf("expected ErrContextExists, got %v", err)
+	}
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: ".bad/name"}); !errors.Is(err, ErrInvalidContext) {
+		t.Errorf("expected ErrInvalidContext, got %v", err)
+	}
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: ".__GLOBAL"}); !errors.Is(err, ErrInvalidContext) {
+		t.Errorf("expected ErrInvalidContext for reserved context, got %v", err)
+	}
+}
+
+func TestRegisterSchema_ContextQuotas(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: ".limited", MaxSubjects: 1, MaxSchemaSize: 80}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+
+	schemaStr := `{"type":"record","name":"A","fields":[{"name":"id","type":"int"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".limited", "first", schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("first subject should be accepted: %v", err)
+	}
+
+	// A new version of an existing subject does not count against maxSubjects
+	schemaV2 := `{"type":"record","name":"A","fields":[{"name":"id","type":"long"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".limited", "first", schemaV2, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("new version of existing subject should be accepted: %v", err)
+	}
+
+	_, err := reg.RegisterSchema(ctx, ".limited", "second", schemaStr, storage.SchemaTypeAvro, nil)
+	if !errors.Is(err, ErrContextQuotaExceeded) {
+		t.Errorf("expected ErrContextQuotaExceeded for second subject, got %v", err)
+	}
+
+	large := `{"type":"record","name":"Large","fields":[{"name":"a_very_long_field_name_that_exceeds_the_limit","type":"string"}]}`
+	_, err = reg.RegisterSchema(ctx, ".limited", "first", large, storage.SchemaTypeAvro, nil)
+	if !errors.Is(err, ErrContextQuotaExceeded) {
+		t.Errorf("expected ErrContextQuotaExceeded for oversized schema, got %v", err)
+	}
+
+	// Other contexts are unaffected
+	if _, err := reg.RegisterSchema(ctx, ".other", "second", large, storage.SchemaTypeAvro, nil); err != nil {
+		t.Errorf("unlimited context should accept schema: %v", err)
+	}
+}
+
+func TestImport_ContextQuotas(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: ".limited", MaxSubjects: 2, MaxSchemaSize: 80}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	schemaN := func(n int) string {
+		return fmt.Sprintf(`{"type":"record","name":"A","fields":[{"name":"f%d","type":"int"}]}`, n)
+	}
+	large := `{"type":"record","name":"Large","fields":[{"name":"a_very_long_field_name_that_exceeds_the_limit","type":"string"}]}`
+
+	if _, err := reg.RegisterSchemaWithID(ctx, ".limited", "first", large, storage.SchemaTypeAvro, nil, 10, 0); !errors.Is(err, ErrContextQuotaExceeded) {
+		t.Errorf("expected ErrContextQuotaExceeded for an oversized schema with an ID, got %v", err)
+	}
+	if _, err := reg.RegisterSchemaWithID(ctx, ".limited", "first", schemaN(1), storage.SchemaTypeAvro, nil, 10, 0); err != nil {
+		t.Fatalf("RegisterSchemaWithID: %v", err)
+	}
+
+	// A bundle is checked as a whole: the second new subject fills the
+	// context, so the third does not fit and nothing is imported.
+	result, err := reg.ImportBundle(ctx, ".limited", []ImportSchemaRequest{
+		{ID: 20, Subject: "second", Version: 1, Schema: schemaN(2)},
+		{ID: 21, Subject: "second", Version: 2, Schema: schemaN(3)},
+		{ID: 22, Subject: "third", Version: 1, Schema: schemaN(4)},
+	})
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if result.Imported != 0 || result.Errors != 1 || !strings.Contains(result.Results[2].Error, "maximum of 2 subjects") {
+		t.Errorf("expected the third subject to be refused, got %+v", result)
+	}
+
+	result, err = reg.ImportSchemas(ctx, ".limited", []ImportSchemaRequest{
+		{ID: 30, Subject: "second", Version: 1, Schema: schemaN(2)},
+		{ID: 31, Subject: "third", Version: 1, Schema: schemaN(4)},
+		{ID: 32, Subject: "second", Version: 2, Schema: large},
+	})
+	if err != nil {
+		t.Fatalf("ImportSchemas: %v", err)
+	}
+	if result.Imported != 1 || result.Errors != 2 || !result.Results[0].Success ||
+		!strings.Contains(result.Results[1].Error, "maximum of 2 subjects") ||
+		!strings.Contains(result.Results[2].Error, "exceeds the limit") {
+		t.Errorf("expected only the first schema to be imported, got %+v", result)
+	}
+}
+
+// failGetContextStore wraps a real memory store but makes GetContext fail.
+type failGetContextStore struct {
+	*memory.Store
+}
+
+func (f *failGetContextStore) GetContext(_ context.Context, _ string) (*storage.ContextRecord, error) {
+	return nil, errors.New("injected GetContext failure")
+}
+
+func TestRegisterSchema_ContextQuotaStorageError(t *testing.T) {
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	compatChecker := compatibility.NewChecker()
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := New(&failGetContextStore{Store: memory.NewStore()}, schemaRegistry, compatChecker, "NONE")
+
+	_, err := reg.RegisterSchema(context.Background(), ".", "orders", `{"type":"string"}`, storage.SchemaTypeAvro, nil)
+	if err == nil || !strings.Contains(err.Error(), "injected GetContext failure") {
+		t.Errorf("expected the storage error, got %v", err)
+	}
+}
+
+func TestRegisterSchema_PrincipalQuotas(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	reg.SetQuotas([]QuotaRule{
+		{Name: "codegen", Principals: []string{"ci-bot"}, MaxVersionsPerDay: 2},
+		{Name: "team-a", Roles: []string{"developer"}, Contexts: []string{"team-a"}, MaxSubjectsPerContext: 1},
+	})
+	now := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	reg.quotas.now = func() time.Time { return now }
+	ctx := context.Background()
+	bot := RegisterOpts{Principal: "ci-bot", Role: "developer"}
+	schemaN := func(n int) string {
+		return fmt.Sprintf(`{"type":"record","name":"A","fields":[{"name":"f%d","type":"int"}]}`, n)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if _, err := reg.RegisterSchema(ctx, ".", "orders", schemaN(i), storage.SchemaTypeAvro, nil, bot); err != nil {
+			t.Fatalf("version %d should be within the quota: %v", i, err)
+		}
+	}
+	// Registering an existing version is not counted.
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", schemaN(1), storage.SchemaTypeAvro, nil, bot); err != nil {
+		t.Fatalf("existing version should be returned: %v", err)
+	}
+	_, err := reg.RegisterSchema(ctx, ".", "orders", schemaN(3), storage.SchemaTypeAvro, nil, bot)
+	if !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "codegen") {
+		t.Errorf("expected ErrQuotaExceeded naming the rule, got %v", err)
+	}
+	// Other principals, and registrations without one, are not limited.
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", schemaN(3), storage.SchemaTypeAvro, nil, RegisterOpts{Principal: "alice"}); err != nil {
+		t.Errorf("another principal should not be limited: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", schemaN(4), storage.SchemaTypeAvro, nil); err != nil {
+		t.Errorf("a registration without a principal should not be limited: %v", err)
+	}
+
+	// The daily count starts over the next UTC day.
+	now = now.Add(2 * time.Hour)
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", schemaN(5), storage.SchemaTypeAvro, nil, bot); err != nil {
+		t.Errorf("the quota should reset the next day: %v", err)
+	}
+
+	// Subjects are counted per context for everyone matching the rule.
+	dev := RegisterOpts{Principal: "bob", Role: "developer"}
+	if _, err := reg.RegisterSchema(ctx, ".team-a", "a1", schemaN(1), storage.SchemaTypeAvro, nil, dev); err != nil {
+		t.Fatalf("first subject should be within the quota: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".team-a", "a1", schemaN(2), storage.SchemaTypeAvro, nil, dev); err != nil {
+		t.Errorf("a new version of an existing subject should not count: %v", err)
+	}
+	carol := RegisterOpts{Principal: "carol", Role: "developer"}
+	if _, err := reg.RegisterSchema(ctx, ".team-a", "a2", schemaN(1), storage.SchemaTypeAvro, nil, carol); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded for a second subject, got %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".team-b", "b1", schemaN(1), storage.SchemaTypeAvro, nil, carol); err != nil {
+		t.Errorf("the rule should not apply in other contexts: %v", err)
+	}
+
+	usage := reg.QuotaUsage()
+	if len(usage) != 2 || usage[0].Day != "2026-03-02" || usage[0].VersionsToday["ci-bot"] != 1 ||
+		usage[1].SubjectsByContext[".team-a"] != 1 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+
+	if err := reg.ResetQuotaUsage("team-a", "", "team-a"); err != nil {
+		t.Fatalf("ResetQuotaUsage: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".team-a", "a2", schemaN(1), storage.SchemaTypeAvro, nil, carol); err != nil {
+		t.Errorf("a reset quota should accept the subject: %v", err)
+	}
+	if err := reg.ResetQuotaUsage("unknown", "", ""); !errors.Is(err, ErrQuotaRuleNotFound) {
+		t.Errorf("expected ErrQuotaRuleNotFound, got %v", err)
+	}
+}
+
+func TestRegisterSchema_QuotaNotCountedOnFailure(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	reg.SetQuotas([]QuotaRule{{Name: "codegen", Principals: []string{"ci-bot"}, MaxVersionsPerDay: 1}})
+	ctx := context.Background()
+	bot := RegisterOpts{Principal: "ci-bot"}
+
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", `{"type":"record","name":"A","fields":[{"name":"id","type":"int"}]}`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatal(err)
+	}
+	// An incompatible schema and a dry run do not use up the quota.
+	incompatible := `{"type":"record","name":"A","fields":[{"name":"id","type":"string"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", incompatible, storage.SchemaTypeAvro, nil, bot); !errors.Is(err, ErrIncompatibleSchema) {
+		t.Fatalf("expected ErrIncompatibleSchema, got %v", err)
+	}
+	compatible := `{"type":"record","name":"A","fields":[{"name":"id","type":"int"},{"name":"n","type":"int","default":0}]}`
+	if _, _, err := reg.PlanRegistration(ctx, ".", "orders", compatible, storage.SchemaTypeAvro, nil, bot); err != nil {
+		t.Fatalf("PlanRegistration: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", compatible, storage.SchemaTypeAvro, nil, bot); err != nil {
+		t.Errorf("the quota should not have been used: %v", err)
+	}
+}
+
+func TestContextAutoCreateDisabled(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	reg.SetContextAutoCreate(false, []string{"staging"})
+	ctx := context.Background()
+	schemaStr := `{"type":"record","name":"A","fields":[{"name":"id","type":"int"}]}`
+
+	if _, err := reg.RegisterSchema(ctx, ".typo", "orders", schemaStr, storage.SchemaTypeAvro, nil); !errors.Is(err, ErrContextNotCreated) {
+		t.Errorf("expected ErrContextNotCreated registering into an unknown context, got %v", err)
+	}
+	if err := reg.SetConfig(ctx, ".typo", "", "FULL", nil); !errors.Is(err, ErrContextNotCreated) {
+		t.Errorf("expected ErrContextNotCreated setting config of an unknown context, got %v", err)
+	}
+	if err := reg.SetMode(ctx, ".typo", "orders", "READONLY", false); !errors.Is(err, ErrContextNotCreated) {
+		t.Errorf("expected ErrContextNotCreated setting mode in an unknown context, got %v", err)
+	}
+	if _, err := reg.GetContext(ctx, ".typo"); !errors.Is(err, storage.ErrContextNotFound) {
+		t.Errorf("expected the refused writes not to create the context, got %v", err)
+	}
+
+	// The default context, allowed contexts and created contexts take writes.
+	for _, registryCtx := range []string{".", ".staging", ".team"} {
+		if registryCtx == ".team" {
+			if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: ".team"}); err != nil {
+				t.Fatalf("CreateContext: %v", err)
+			}
+		}
+		if _, err := reg.RegisterSchema(ctx, registryCtx, "orders", schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+			t.Errorf("expected a registration in %s to succeed, got %v", registryCtx, err)
+		}
+	}
+}
+
+func TestDeleteContext(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	schemaStr := `{"type":"record","name":"A","fields":[{"name":"id","type":"int"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".doomed", "s1", schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	if _, err := reg.DeleteContext(ctx, ".doomed", false); !errors.Is(err, ErrContextNotEmpty) {
+		t.Errorf("expected ErrContextNotEmpty, got %v", err)
+	}
+
+	deleted, err := reg.DeleteContext(ctx, ".doomed", true)
+	if err != nil {
+		t.Fatalf("cascade DeleteContext: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "s1" {
+		t.Errorf("expected [s1] deleted, got %v", deleted)
+	}
+
+	if _, err := reg.GetContext(ctx, ".doomed"); !errors.Is(err, storage.ErrContextNotFound) {
+		t.Errorf("expected ErrContextNotFound after delete, got %v", err)
+	}
+	if _, err := reg.DeleteContext(ctx, ".", true); !errors.Is(err, ErrContextProtected) {
+		t.Errorf("expected ErrContextProtected for default context, got %v", err)
+	}
+}
+
+// =============================================================================
+// Normalization Profile Tests
+// =============================================================================
+
+func TestRegisterSchema_NormalizationProfilePerContext(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+
+	reg.SetNormalizationProfiles(schema.DefaultNormalizationProfile, map[string]schema.NormalizationProfile{
+		"relaxed": {Name: "relaxed", JSONStripAnnotations: true},
+	})
+	if got := reg.NormalizationProfile(".relaxed").Name; got != "relaxed" {
+		t.Fatalf("expected context name to be normalized, got profile %q", got)
+	}
+
+	schema1 := `{"type":"object","title":"User","properties":{"id":{"type":"integer"}}}`
+	schema2 := `{"type":"object","title":"Account","properties":{"id":{"type":"integer"}}}`
+	opts := RegisterOpts{Normalize: true}
+
+	// Context with the stripping profile: schemas differing in annotations are the same schema.
+	rec1, err := reg.RegisterSchema(ctx, ".relaxed", "users-value", schema1, storage.SchemaTypeJSON, nil, opts)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	rec2, err := reg.RegisterSchema(ctx, ".relaxed", "users-value", schema2, storage.SchemaTypeJSON, nil, opts)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if rec1.ID != rec2.ID || rec1.Version != rec2.Version {
+		t.Errorf("expected schema to dedupe under stripping profile, got v%d/id%d and v%d/id%d",
+			rec1.Version, rec1.ID, rec2.Version, rec2.ID)
+	}
+
+	// Default context keeps annotations: a changed title is a new version.
+	rec3, err := reg.RegisterSchema(ctx, registrycontext.DefaultContext, "users-value", schema1, storage.SchemaTypeJSON, nil, opts)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	rec4, err := reg.RegisterSchema(ctx, registrycontext.DefaultContext, "users-value", schema2, storage.SchemaTypeJSON, nil, opts)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if rec3.Version == rec4.Version {
+		t.Error("expected changed annotations to register a new version under the default profile")
+	}
+
+	// No profile reorders Avro fields, whose order is part of the wire format.
+	avro1 := `{"type":"record","name":"User","fields":[{"name":"id","type":"long"},{"name":"email","type":"string"}]}`
+	avro2 := `{"type":"record","name":"User","fields":[{"name":"email","type":"string"},{"name":"id","type":"long"}]}`
+	rec5, err := reg.RegisterSchema(ctx, ".relaxed", "orders-value", avro1, storage.SchemaTypeAvro, nil, opts)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	rec6, err := reg.RegisterSchema(ctx, ".relaxed", "orders-value", avro2, storage.SchemaTypeAvro, nil, opts)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if rec5.ID == rec6.ID {
+		t.Error("expected reordered Avro fields to get a new ID")
+	}
+}
+
+func TestNormalizeSchema_MatchesNormalizedRegistration(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+
+	reg.SetNormalizationProfiles(schema.DefaultNormalizationProfile, map[string]schema.NormalizationProfile{
+		"relaxed": {Name: "relaxed", JSONStripAnnotations: true},
+	})
+	schemaStr := `{"type":"object","title":"User","properties":{"id":{"type":"integer"}}}`
+
+	// The default profile keeps the canonical form as it is
+	result, err := reg.NormalizeSchema(ctx, registrycontext.DefaultContext, schemaStr, storage.SchemaTypeJSON, nil)
+	if err != nil {
+		t.Fatalf("NormalizeSchema: %v", err)
+	}
+	if result.Changed || result.Fingerprint != result.UnnormalizedFingerprint {
+		t.Errorf("expected no change under the default profile, got %+v", result)
+	}
+
+	// The stripping profile removes the title
+	result, err = reg.NormalizeSchema(ctx, ".relaxed", schemaStr, storage.SchemaTypeJSON, nil)
+	if err != nil {
+		t.Fatalf("NormalizeSchema: %v", err)
+	}
+	if !result.Changed || result.Fingerprint == result.UnnormalizedFingerprint {
+		t.Errorf("expected the stripping profile to change the fingerprint, got %+v", result)
+	}
+
+	// The fingerprints are the ones registration uses with and without normalize
+	if _, _, err := reg.RegisterSchemaIfAbsent(ctx, ".relaxed", "users-value", result.Fingerprint, schemaStr,
+		storage.SchemaTypeJSON, nil, RegisterOpts{Normalize: true}); err != nil {
+		t.Errorf("normalized fingerprint rejected by normalized registration: %v", err)
+	}
+	if _, _, err := reg.RegisterSchemaIfAbsent(ctx, ".relaxed", "raw-value", result.UnnormalizedFingerprint, schemaStr,
+		storage.SchemaTypeJSON, nil); err != nil {
+		t.Errorf("unnormalized fingerprint rejected by plain registration: %v", err)
+	}
+}
+
+func TestConvertSchema(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+	avroSchema := `{"type":"record","name":"User","fields":[{"name":"id","type":"long"}]}`
+
+	result, err := reg.ConvertSchema(ctx, ".", avroSchema, storage.SchemaTypeAvro, nil, "JSON")
+	if err != nil {
+		t.Fatalf("ConvertSchema: %v", err)
+	}
+	if result.SchemaType != storage.SchemaTypeJSON {
+		t.Errorf("expected a JSON Schema, got %s", result.SchemaType)
+	}
+	// The conversion is itself a valid JSON Schema.
+	if _, err := reg.ConvertSchema(ctx, ".", result.Schema, storage.SchemaTypeJSON, nil, ConvertToCanonical); err != nil {
+		t.Errorf("expected the converted schema to parse as JSON Schema: %v", err)
+	}
+
+	proto := "syntax = \"proto3\";\nmessage User { int64 id = 1; }\n"
+	if _, err := reg.ConvertSchema(ctx, ".", proto, storage.SchemaTypeProtobuf, nil, ConvertToPretty); !errors.Is(err, ErrUnsupportedConversion) {
+		t.Errorf("expected ErrUnsupportedConversion for pretty Protobuf, got %v", err)
+	}
+	if _, err := reg.ConvertSchema(ctx, ".", avroSchema, storage.SchemaTypeAvro, nil, "xml"); !errors.Is(err, ErrUnsupportedConversion) {
+		t.Errorf("expected ErrUnsupportedConversion for an unknown target, got %v", err)
+	}
+	if _, err := reg.ConvertSchema(ctx, ".", `{"type":`, storage.SchemaTypeAvro, nil, ConvertToCanonical); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+}
+
+func TestNormalizeSchema_References(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	base := `{"type":"record","name":"Base","namespace":"test","fields":[{"name":"id","type":"int"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "base-subject", base, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("failed to register base: %v", err)
+	}
+
+	referencing := `{"type":"record","name":"Ref","namespace":"test","fields":[{"name":"base","type":"test.Base"}]}`
+	refs := []storage.Reference{{Name: "test.Base", Subject: "base-subject", Version: 1}}
+	if _, err := reg.NormalizeSchema(ctx, ".", referencing, storage.SchemaTypeAvro, refs); err != nil {
+		t.Errorf("expected referencing schema to normalize, got %v", err)
+	}
+
+	missing := []storage.Reference{{Name: "test.Base", Subject: "no-such-subject", Version: 1}}
+	if _, err := reg.NormalizeSchema(ctx, ".", referencing, storage.SchemaTypeAvro, missing); !errors.Is(err, ErrFailedResolveReferences) {
+		t.Errorf("expected ErrFailedResolveReferences, got %v", err)
+	}
+}
+
+func TestRegisterSchema_SubjectNameStrategy(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	reg.SetSubjectNameStrategies("topic_name", map[string]string{"team-a": "record_name", ".legacy": "none"})
+	ctx := context.Background()
+
+	schemaStr := `{"type":"record","name":"Order","namespace":"com.example","fields":[{"name":"id","type":"int"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("topic_name subject should be accepted: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders", schemaStr, storage.SchemaTypeAvro, nil); !errors.Is(err, ErrSubjectNameStrategy) {
+		t.Errorf("expected ErrSubjectNameStrategy in default context, got %v", err)
+	}
+
+	// Context names are normalized, so "team-a" applies to ".team-a".
+	if _, err := reg.RegisterSchema(ctx, ".team-a", "com.example.Order", schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("record_name subject should be accepted: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".team-a", "orders-value", schemaStr, storage.SchemaTypeAvro, nil); !errors.Is(err, ErrSubjectNameStrategy) {
+		t.Errorf("expected ErrSubjectNameStrategy in .team-a, got %v", err)
+	}
+
+	if _, err := reg.RegisterSchema(ctx, ".legacy", "anything", schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+		t.Errorf("enforcement should be disabled in .legacy: %v", err)
+	}
+}
+
+func TestRegisterSchema_SubjectNameStrategyAllowsExistingSchema(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	schemaStr := `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`
+	first, err := reg.RegisterSchema(ctx, ".", "orders", schemaStr, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	// Re-registering an existing schema returns it even after a policy is configured.
+	reg.SetSubjectNameStrategies("topic_name", nil)
+	again, err := reg.RegisterSchema(ctx, ".", "orders", schemaStr, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("re-registering an existing schema should succeed: %v", err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("expected ID %d, got %d", first.ID, again.ID)
+	}
+}
+
+func TestRegisterSchema_LintPolicies(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	reg.SetLintPolicies(
+		lint.Policy{lint.RuleFieldSnakeCase: lint.SeverityError, lint.RuleFieldDoc: lint.SeverityWarn},
+		map[string]lint.Policy{"legacy": {lint.RuleFieldSnakeCase: lint.SeverityOff}},
+		map[string]lint.Policy{":.legacy:strict-value": {lint.RuleFieldDoc: lint.SeverityError}},
+	)
+	ctx := context.Background()
+
+	camel := `{"type":"record","name":"Order","fields":[{"name":"orderId","type":"int","doc":"Order ID"}]}`
+	undocumented := `{"type":"record","name":"Order","fields":[{"name":"order_id","type":"int"}]}`
+
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", camel, storage.SchemaTypeAvro, nil); !errors.Is(err, ErrLintFailed) {
+		t.Errorf("expected ErrLintFailed for camelCase field, got %v", err)
+	}
+	// Warnings do not reject the schema, but are reported by LintSchema.
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", undocumented, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("warn-severity violation should be accepted: %v", err)
+	}
+	violations, err := reg.LintSchema(ctx, ".", "orders-value", undocumented, storage.SchemaTypeAvro, nil)
+	if err != nil || len(violations) != 1 || violations[0].Rule != lint.RuleFieldDoc || violations[0].Severity != lint.SeverityWarn {
+		t.Errorf("LintSchema = %+v, %v", violations, err)
+	}
+
+	// Context names are normalized, so "legacy" applies to ".legacy".
+	if _, err := reg.RegisterSchema(ctx, ".legacy", "orders-value", camel, storage.SchemaTypeAvro, nil); err != nil {
+		t.Errorf("snake_case should be disabled in .legacy: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".legacy", "strict-value", undocumented, storage.SchemaTypeAvro, nil); !errors.Is(err, ErrLintFailed) {
+		t.Errorf("expected ErrLintFailed from the subject policy, got %v", err)
+	}
+}
+
+func TestGetTopicSubjects(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	register := func(subject, name string) {
+		t.Helper()
+		schemaStr := fmt.Sprintf(`{"type":"record","name":"%s","fields":[{"name":"id","type":"int"}]}`, name)
+		if _, err := reg.RegisterSchema(ctx, ".", subject, schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema %s: %v", subject, err)
+		}
+	}
+	register("orders-key", "OrderKey")
+	register("orders-value", "Order")
+	register("orders-Refund", "Refund")
+	register("orders-audit-value", "Audit") // belongs to topic "orders-audit"
+	register("payments-value", "Payment")
+
+	got, err := reg.GetTopicSubjects(ctx, ".", "orders")
+	if err != nil {
+		t.Fatalf("GetTopicSubjects: %v", err)
+	}
+	if got.Key != "orders-key" || got.Value != "orders-value" {
+		t.Errorf("expected key and value subjects, got %+v", got)
+	}
+	if len(got.Records) != 1 || got.Records[0] != "orders-Refund" {
+		t.Errorf("expected only orders-Refund as a record subject, got %v", got.Records)
+	}
+
+	empty, err := reg.GetTopicSubjects(ctx, ".", "unknown")
+	if err != nil {
+		t.Fatalf("GetTopicSubjects: %v", err)
+	}
+	if empty.Key != "" || empty.Value != "" || len(empty.Records) != 0 {
+		t.Errorf("expected no subjects for unknown topic, got %+v", empty)
+	}
+}
+
+// --- GetSchemaBundle tests ---
+
+func TestGetSchemaBundle_AvroTransitive(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	country := `{"type":"record","name":"Country","namespace":"geo","fields":[{"name":"code","type":"string"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "country", country, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("register country: %v", err)
+	}
+	address := `{"type":"record","name":"Address","namespace":"geo","fields":[{"name":"country","type":"geo.Country"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "address", address, storage.SchemaTypeAvro,
+		[]storage.Reference{{Name: "geo.Country", Subject: "country", Version: 1}}); err != nil {
+		t.Fatalf("register address: %v", err)
+	}
+	order := `{"type":"record","name":"Order","namespace":"shop","fields":[{"name":"ship","type":"geo.Address"},{"name":"bill","type":"geo.Address"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "order-value", order, storage.SchemaTypeAvro,
+		[]storage.Reference{{Name: "geo.Address", Subject: "address", Version: 1}}); err != nil {
+		t.Fatalf("register order: %v", err)
+	}
+
+	bundle, err := reg.GetSchemaBundle(ctx, ".", "order-value", -1)
+	if err != nil {
+		t.Fatalf("GetSchemaBundle: %v", err)
+	}
+	want := []string{"shop.Order.avsc", "geo.Address.avsc", "geo.Country.avsc"}
+	if len(bundle.Files) != len(want) {
+		t.Fatalf("expected %d files, got %+v", len(want), bundle.Files)
+	}
+	for i, p := range want {
+		if bundle.Files[i].Path != p {
+			t.Errorf("file %d: expected %s, got %s", i, p, bundle.Files[i].Path)
+		}
+	}
+	if bundle.Version != 1 || bundle.Files[2].Content != country {
+		t.Errorf("unexpected bundle contents: %+v", bundle)
+	}
+}
+
+func TestGetSchemaBundle_ProtobufImportPaths(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+
+	common := "syntax = \"proto3\";\npackage common;\nmessage Money {\n  int64 units = 1;\n}\n"
+	if _, err := reg.RegisterSchema(ctx, ".", "money", common, storage.SchemaTypeProtobuf, nil); err != nil {
+		t.Fatalf("register money: %v", err)
+	}
+	order := "syntax = \"proto3\";\nimport \"common/money.proto\";\nmessage Order {\n  common.Money total = 1;\n}\n"
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", order, storage.SchemaTypeProtobuf,
+		[]storage.Reference{{Name: "common/money.proto", Subject: "money", Version: 1}}); err != nil {
+		t.Fatalf("register order: %v", err)
+	}
+
+	bundle, err := reg.GetSchemaBundle(ctx, ".", "orders-value", 1)
+	if err != nil {
+		t.Fatalf("GetSchemaBundle: %v", err)
+	}
+	if len(bundle.Files) != 2 || bundle.Files[0].Path != "orders-value.proto" || bundle.Files[1].Path != "common/money.proto" {
+		t.Errorf("unexpected file paths: %+v", bundle.Files)
+	}
+}
+
+func TestGetSchemaBundle_NotFound(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+
+	if _, err := reg.GetSchemaBundle(context.Background(), ".", "missing", -1); !errors.Is(err, storage.ErrSubjectNotFound) {
+		t.Errorf("expected ErrSubjectNotFound, got %v", err)
+	}
+}
+
+func TestSafeBundlePath(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"common/money.proto", "common/money.proto"},
+		{"../../etc/passwd", "etc/passwd"},
+		{"/abs/path.json", "abs/path.json"},
+		{"", "fallback.json"},
+	}
+	for _, tt := range tests {
+		if got := safeBundlePath(tt.in, "fallback.json"); got != tt.want {
+			t.Errorf("safeBundlePath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestImportBundle_ReferencesInAnyOrder(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	address := `{"type":"record","name":"Address","namespace":"geo","fields":[{"name":"city","type":"string"}]}`
+	user := `{"type":"record","name":"User","namespace":"app","fields":[{"name":"home","type":"geo.Address"}]}`
+	result, err := reg.ImportBundle(ctx, ".", []ImportSchemaRequest{
+		{ID: 20, Subject: "users-value", Version: 1, Schema: user,
+			References: []storage.Reference{{Name: "geo.Address", Subject: "address", Version: 1}}},
+		{ID: 10, Subject: "address", Version: 1, Schema: address},
+	})
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if result.Imported != 2 || result.Errors != 0 {
+		t.Fatalf("expected 2 imported, got %+v", result)
+	}
+
+	got, err := reg.GetSchemaBySubjectVersion(ctx, ".", "users-value", 1)
+	if err != nil || got.ID != 20 {
+		t.Fatalf("expected users-value v1 with ID 20, got %+v, %v", got, err)
+	}
+	// The ID sequence continues after the highest imported ID.
+	rec, err := reg.RegisterSchema(ctx, ".", "other", `{"type":"string"}`, storage.SchemaTypeAvro, nil)
+	if err != nil || rec.ID != 21 {
+		t.Errorf("expected next ID 21, got %+v, %v", rec, err)
+	}
+}
+
+func TestImportBundle_AllOrNothing(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	if _, err := reg.RegisterSchema(ctx, ".", "existing", `{"type":"string"}`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("register existing: %v", err)
+	}
+
+	result, err := reg.ImportBundle(ctx, ".", []ImportSchemaRequest{
+		{ID: 10, Subject: "valid", Version: 1, Schema: `{"type":"int"}`},
+		{ID: 11, Subject: "broken", Version: 1, Schema: `{"type":"record"`},
+		{ID: 12, Subject: "existing", Version: 1, Schema: `{"type":"long"}`},
+		{ID: 10, Subject: "clash", Version: 1, Schema: `{"type":"boolean"}`},
+		{ID: 13, Subject: "valid", Version: 1, Schema: `{"type":"int"}`},
+	})
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if result.Imported != 0 || result.Errors != 4 {
+		t.Fatalf("expected 0 imported and 4 errors, got %+v", result)
+	}
+	wantErrors := []string{"", "invalid schema", "subject/version already exists", "schema ID used for different schemas in bundle", "duplicate subject/version in bundle"}
+	for i, want := range wantErrors {
+		if res := result.Results[i]; res.Success || !strings.Contains(res.Error, want) || (want == "") != (res.Error == "") {
+			t.Errorf("result %d: expected error %q, got %+v", i, want, res)
+		}
+	}
+
+	if _, err := reg.GetSchemaBySubjectVersion(ctx, ".", "valid", 1); !errors.Is(err, storage.ErrSubjectNotFound) {
+		t.Errorf("expected nothing imported, got %v", err)
+	}
+}
+
+func TestCleanBundlePath(t *testing.T) {
+	for _, in := range []string{"a/b.avsc", "./a/b.avsc", "/a/b.avsc", "a//b.avsc"} {
+		if got := CleanBundlePath(in); got != "a/b.avsc" {
+			t.Errorf("CleanBundlePath(%q) = %q, want a/b.avsc", in, got)
+		}
+	}
+}
+
+func TestRevalidateSubject(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	v1 := `{"type":"record","name":"Order","fields":[{"name":"a","type":"int"}]}`
+	v2 := `{"type":"record","name":"Order","fields":[{"name":"a","type":"int"},{"name":"b","type":"string","default":""}]}`
+	v3 := `{"type":"record","name":"Order","fields":[{"name":"c","type":"string"}]}`
+	for _, schemaStr := range []string{v1, v2, v3} {
+		if _, err := reg.RegisterSchema(ctx, ".", "orders-value", schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema: %v", err)
+		}
+	}
+
+	// Under NONE nothing is checked.
+	res, err := reg.RevalidateSubject(ctx, ".", "orders-value", nil)
+	if err != nil {
+		t.Fatalf("RevalidateSubject: %v", err)
+	}
+	if res.PairsChecked != 0 || len(res.Incompatible) != 0 {
+		t.Errorf("NONE: expected nothing checked, got %+v", res)
+	}
+
+	if err := reg.SetConfig(ctx, ".", "orders-value", "BACKWARD", nil); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	waits := 0
+	res, err = reg.RevalidateSubject(ctx, ".", "orders-value", func(context.Context) error {
+		waits++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RevalidateSubject: %v", err)
+	}
+	if res.CompatibilityLevel != "BACKWARD" || res.PairsChecked != 2 || waits != 2 {
+		t.Errorf("expected 2 BACKWARD pairs and 2 waits, got %+v and %d waits", res, waits)
+	}
+	if len(res.Incompatible) != 1 {
+		t.Fatalf("expected 1 incompatible pair, got %+v", res.Incompatible)
+	}
+	pair := res.Incompatible[0]
+	if pair.PreviousVersion != 2 || pair.Version != 3 || len(pair.Messages) == 0 {
+		t.Errorf("unexpected incompatible pair %+v", pair)
+	}
+	if !strings.Contains(pair.Messages[0], "against version 2") {
+		t.Errorf("expected message to name version 2, got %q", pair.Messages[0])
+	}
+
+	// An error from beforePair stops the check.
+	stop := errors.New("stop")
+	if _, err := reg.RevalidateSubject(ctx, ".", "orders-value", func(context.Context) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("expected beforePair error, got %v", err)
+	}
+
+	// A subject deleted after it was listed has nothing to check.
+	res, err = reg.RevalidateSubject(ctx, ".", "missing-value", nil)
+	if err != nil || res.PairsChecked != 0 {
+		t.Errorf("missing subject: got %+v, %v", res, err)
+	}
+}
+
+func TestListSubjectCatalog(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+
+	for _, subject := range []string{"orders-value", "payments-value", "refunds-value"} {
+		if _, err := reg.RegisterSchema(ctx, ".", subject, `"string"`, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema %s: %v", subject, err)
+		}
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "payments-value", `["string","null"]`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if err := reg.SetConfig(ctx, ".", "payments-value", "FULL", nil); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	if err := reg.SetMode(ctx, ".", "payments-value", "READONLY", false); err != nil {
+		t.Fatalf("SetMode: %v", err)
+	}
+	if _, err := reg.DeleteSubject(ctx, ".", "refunds-value", false); err != nil {
+		t.Fatalf("DeleteSubject: %v", err)
+	}
+
+	all := SubjectCatalogOptions{IncludeLatest: true, IncludeConfig: true, IncludeMode: true}
+	catalog, err := reg.ListSubjectCatalog(ctx, ".", all)
+	if err != nil {
+		t.Fatalf("ListSubjectCatalog: %v", err)
+	}
+	if catalog.Total != 2 || len(catalog.Subjects) != 2 {
+		t.Fatalf("expected 2 active subjects, got %+v", catalog)
+	}
+	orders, payments := catalog.Subjects[0], catalog.Subjects[1]
+	if orders.Subject != "orders-value" || orders.Latest == nil || orders.Latest.Version != 1 ||
+		orders.CompatibilityLevel != "BACKWARD" || orders.Mode != "READWRITE" {
+		t.Errorf("unexpected orders entry: %+v", orders)
+	}
+	if payments.Latest == nil || payments.Latest.Version != 2 || payments.CompatibilityLevel != "FULL" || payments.Mode != "READONLY" {
+		t.Errorf("unexpected payments entry: %+v", payments)
+	}
+
+	// Pages only load details for their own subjects; soft-deleted subjects
+	// have no latest version.
+	page, err := reg.ListSubjectCatalog(ctx, ".", SubjectCatalogOptions{Deleted: true, Offset: 2, Limit: 1, IncludeLatest: true})
+	if err != nil {
+		t.Fatalf("ListSubjectCatalog: %v", err)
+	}
+	if page.Total != 3 || len(page.Subjects) != 1 || page.Subjects[0].Subject != "refunds-value" || page.Subjects[0].Latest != nil {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	prefixed, err := reg.ListSubjectCatalog(ctx, ".", SubjectCatalogOptions{Prefix: "pay"})
+	if err != nil {
+		t.Fatalf("ListSubjectCatalog: %v", err)
+	}
+	if prefixed.Total != 1 || prefixed.Subjects[0].Latest != nil || prefixed.Subjects[0].Mode != "" {
+		t.Errorf("expected only payments-value without details, got %+v", prefixed)
+	}
+}
+
+func TestTenants(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+
+	if err := reg.CreateTenant(ctx, &storage.TenantRecord{Name: "bad name"}); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("expected ErrInvalidTenant, got %v", err)
+	}
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: "acme-orders", Tenant: "acme"}); !errors.Is(err, storage.ErrTenantNotFound) {
+		t.Errorf("expected ErrTenantNotFound for an unknown tenant, got %v", err)
+	}
+
+	if err := reg.CreateTenant(ctx, &storage.TenantRecord{Name: "acme"}); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: "acme-orders", Tenant: "acme"}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	if got, err := reg.ContextTenant(ctx, ".acme-orders"); err != nil || got != "acme" {
+		t.Errorf("expected .acme-orders to belong to acme, got %q, %v", got, err)
+	}
+	if got, err := reg.ContextTenant(ctx, "."); err != nil || got != "" {
+		t.Errorf("expected the default context to have no tenant, got %q, %v", got, err)
+	}
+
+	if err := reg.DeleteTenant(ctx, "acme"); !errors.Is(err, ErrTenantNotEmpty) {
+		t.Errorf("expected ErrTenantNotEmpty, got %v", err)
+	}
+	if _, err := reg.DeleteContext(ctx, ".acme-orders", false); err != nil {
+		t.Fatalf("DeleteContext: %v", err)
+	}
+	if err := reg.DeleteTenant(ctx, "acme"); err != nil {
+		t.Errorf("DeleteTenant: %v", err)
+	}
+}
+
+func TestTenantHomeContexts(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	reg.SetTenantHomeContexts(true)
+	ctx := context.Background()
+
+	if err := reg.CreateTenant(ctx, &storage.TenantRecord{Name: "acme"}); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if got, err := reg.ContextTenant(ctx, TenantHomeContext("acme")); err != nil || got != "acme" {
+		t.Errorf("expected .acme to belong to acme, got %q, %v", got, err)
+	}
+
+	// A context already named like the tenant's home context blocks it.
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: "globex"}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	if err := reg.CreateTenant(ctx, &storage.TenantRecord{Name: "globex"}); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("expected ErrInvalidTenant, got %v", err)
+	}
+	if _, err := reg.GetTenant(ctx, "globex"); !errors.Is(err, storage.ErrTenantNotFound) {
+		t.Errorf("expected no tenant globex, got %v", err)
+	}
+}
+
+func TestDeleteConfirmation(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	if reg.RequiresDeleteConfirmation() {
+		t.Fatal("expected deletes not to be two-phase by default")
+	}
+	reg.SetDeleteConfirmation(0)
+	if !reg.RequiresDeleteConfirmation() {
+		t.Fatal("expected deletes to be two-phase")
+	}
+	for _, s := range []string{`{"type":"string"}`, `{"type":"int"}`} {
+		if _, err := reg.RegisterSchema(ctx, ".", "orders-value", s, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema: %v", err)
+		}
+	}
+
+	if _, err := reg.IssueDeleteConfirmation(ctx, ".", "missing-value", 0); !errors.Is(err, storage.ErrSubjectNotFound) {
+		t.Errorf("expected ErrSubjectNotFound, got %v", err)
+	}
+	if _, err := reg.IssueDeleteConfirmation(ctx, ".", "orders-value", 0); !errors.Is(err, storage.ErrSubjectNotSoftDeleted) {
+		t.Errorf("expected ErrSubjectNotSoftDeleted, got %v", err)
+	}
+	if _, err := reg.IssueDeleteConfirmation(ctx, ".", "orders-value", -1); !errors.Is(err, storage.ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound without soft-deleted versions, got %v", err)
+	}
+
+	if _, err := reg.DeleteVersion(ctx, ".", "orders-value", 1, false); err != nil {
+		t.Fatalf("DeleteVersion: %v", err)
+	}
+	if _, err := reg.IssueDeleteConfirmation(ctx, ".", "orders-value", 2); !errors.Is(err, storage.ErrVersionNotSoftDeleted) {
+		t.Errorf("expected ErrVersionNotSoftDeleted, got %v", err)
+	}
+	conf, err := reg.IssueDeleteConfirmation(ctx, ".", "orders-value", -1)
+	if err != nil {
+		t.Fatalf("IssueDeleteConfirmation: %v", err)
+	}
+	if err := reg.ConfirmDelete(ctx, ".", "orders-value", 2, conf.Token); !errors.Is(err, ErrInvalidDeleteConfirmation) {
+		t.Errorf("expected ErrInvalidDeleteConfirmation for another version, got %v", err)
+	}
+	if err := reg.ConfirmDelete(ctx, ".", "orders-value", 1, conf.Token); !errors.Is(err, ErrInvalidDeleteConfirmation) {
+		t.Errorf("expected the token to be consumed by its first use, got %v", err)
+	}
+	conf, err = reg.IssueDeleteConfirmation(ctx, ".", "orders-value", -1)
+	if err != nil {
+		t.Fatalf("IssueDeleteConfirmation: %v", err)
+	}
+	if err := reg.ConfirmDelete(ctx, ".", "orders-value", 1, conf.Token); err != nil {
+		t.Errorf("ConfirmDelete: %v", err)
+	}
+
+	reg.SetDeleteConfirmation(time.Millisecond)
+	conf, err = reg.IssueDeleteConfirmation(ctx, ".", "orders-value", 1)
+	if err != nil {
+		t.Fatalf("IssueDeleteConfirmation: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := reg.ConfirmDelete(ctx, ".", "orders-value", 1, conf.Token); !errors.Is(err, ErrInvalidDeleteConfirmation) {
+		t.Errorf("expected ErrInvalidDeleteConfirmation for an expired token, got %v", err)
+	}
+
+	// An empty subject stands for the cascading delete of a context.
+	reg.SetDeleteConfirmation(0)
+	if _, err := reg.IssueDeleteConfirmation(ctx, ".team", "", 0); !errors.Is(err, storage.ErrContextNotFound) {
+		t.Errorf("expected ErrContextNotFound, got %v", err)
+	}
+	if err := reg.CreateContext(ctx, &storage.ContextRecord{Name: ".team"}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	conf, err = reg.IssueDeleteConfirmation(ctx, ".team", "", 0)
+	if err != nil {
+		t.Fatalf("IssueDeleteConfirmation: %v", err)
+	}
+	if err := reg.ConfirmDelete(ctx, ".", "", 0, conf.Token); !errors.Is(err, ErrInvalidDeleteConfirmation) {
+		t.Errorf("expected ErrInvalidDeleteConfirmation for another context, got %v", err)
+	}
+	conf, err = reg.IssueDeleteConfirmation(ctx, ".team", "", 0)
+	if err != nil {
+		t.Fatalf("IssueDeleteConfirmation: %v", err)
+	}
+	if err := reg.ConfirmDelete(ctx, ".team", "", 0, conf.Token); err != nil {
+		t.Errorf("ConfirmDelete: %v", err)
+	}
+}
+
+func TestSubjectMeta(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", `{"type":"string"}`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	rec, err := reg.SetSubjectMeta(ctx, ".", &storage.SubjectMetaRecord{
+		Subject: "orders-value",
+		Owner:   "  payments ",
+		Links:   []storage.SubjectLink{{Name: " runbook ", URL: " https://wiki.example.com/orders "}},
+	})
+	if err != nil {
+		t.Fatalf("SetSubjectMeta: %v", err)
+	}
+	if rec.Owner != "payments" || rec.Links[0].Name != "runbook" || rec.Links[0].URL != "https://wiki.example.com/orders" {
+		t.Errorf("expected fields to be trimmed, got %+v", rec)
+	}
+
+	for _, links := range [][]storage.SubjectLink{
+		{{Name: "", URL: "https://wiki.example.com"}},
+		{{Name: "docs", URL: "/wiki/orders"}},
+		{{Name: "docs", URL: "ftp://files.example.com/orders"}},
+	} {
+		if _, err := reg.SetSubjectMeta(ctx, ".", &storage.SubjectMetaRecord{Subject: "orders-value", Links: links}); !errors.Is(err, ErrInvalidSubjectMeta) {
+			t.Errorf("expected ErrInvalidSubjectMeta for %+v, got %v", links, err)
+		}
+	}
+	if _, err := reg.SetSubjectMeta(ctx, ".", &storage.SubjectMetaRecord{Subject: "orders-value", Owner: strings.Repeat("x", 256)}); !errors.Is(err, ErrInvalidSubjectMeta) {
+		t.Errorf("expected ErrInvalidSubjectMeta for a long owner, got %v", err)
+	}
+	if _, err := reg.SetSubjectMeta(ctx, ".", &storage.SubjectMetaRecord{Subject: "missing-value"}); !errors.Is(err, storage.ErrSubjectNotFound) {
+		t.Errorf("expected ErrSubjectNotFound, got %v", err)
+	}
+
+	catalog, err := reg.ListSubjectCatalog(ctx, ".", SubjectCatalogOptions{IncludeMeta: true})
+	if err != nil {
+		t.Fatalf("ListSubjectCatalog: %v", err)
+	}
+	if len(catalog.Subjects) != 1 || catalog.Subjects[0].Meta == nil || catalog.Subjects[0].Meta.Owner != "payments" {
+		t.Errorf("expected the catalog to include the meta, got %+v", catalog.Subjects)
+	}
+
+	if err := reg.DeleteSubjectMeta(ctx, ".", "orders-value"); err != nil {
+		t.Fatalf("DeleteSubjectMeta: %v", err)
+	}
+	if err := reg.DeleteSubjectMeta(ctx, ".", "orders-value"); err != nil {
+		t.Errorf("expected deleting missing meta to succeed, got %v", err)
+	}
+	empty, err := reg.GetSubjectMeta(ctx, ".", "orders-value")
+	if err != nil || empty.Owner != "" || len(empty.Links) != 0 {
+		t.Errorf("expected empty meta, got %+v, %v", empty, err)
+	}
+}
+
+func TestTagsAndSearch(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+
+	customer := `{"type":"record","name":"Customer","fields":[{"name":"email","type":"string"}]}`
+	order := `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`
+	for subject, schemaStr := range map[string]string{"customers-value": customer, "orders-value": order} {
+		if _, err := reg.RegisterSchema(ctx, ".", subject, schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema %s: %v", subject, err)
+		}
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "events-value", `{"type":"object","properties":{"email":{"type":"string"}}}`, storage.SchemaTypeJSON, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	rec, err := reg.SetTags(ctx, ".", "customers-value", 0, []string{" pii ", "gdpr", "PII"}, map[string]string{"owner": "crm"})
+	if err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+	if strings.Join(rec.Tags, ",") != "gdpr,pii" {
+		t.Errorf("expected tags to be trimmed, de-duplicated and sorted, got %v", rec.Tags)
+	}
+	if _, err := reg.SetTags(ctx, ".", "customers-value", -1, []string{"golden"}, map[string]string{"owner": "data-platform"}); err != nil {
+		t.Fatalf("SetTags on latest version: %v", err)
+	}
+	if _, err := reg.SetTags(ctx, ".", "events-value", 0, []string{"pii"}, nil); err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+
+	if _, err := reg.SetTags(ctx, ".", "missing-value", 0, []string{"pii"}, nil); !errors.Is(err, storage.ErrSubjectNotFound) {
+		t.Errorf("expected ErrSubjectNotFound, got %v", err)
+	}
+	if _, err := reg.SetTags(ctx, ".", "orders-value", 0, []string{""}, nil); !errors.Is(err, ErrInvalidTags) {
+		t.Errorf("expected ErrInvalidTags, got %v", err)
+	}
+
+	empty, err := reg.GetTags(ctx, ".", "orders-value", 0)
+	if err != nil || len(empty.Tags) != 0 || len(empty.Labels) != 0 {
+		t.Errorf("expected no tags on orders-value, got %+v, %v", empty, err)
+	}
+
+	results, err := reg.SearchSchemas(ctx, ".", SchemaSearchOptions{Tags: []string{"PII"}})
+	if err != nil {
+		t.Fatalf("SearchSchemas: %v", err)
+	}
+	if len(results) != 2 || results[0].Schema.Subject != "customers-value" || results[1].Schema.Subject != "events-value" {
+		t.Fatalf("expected customers-value and events-value, got %+v", results)
+	}
+	if strings.Join(results[0].Tags, ",") != "gdpr,golden,pii" || results[0].Labels["owner"] != "data-platform" {
+		t.Errorf("expected version tags and labels to be combined with the subject's, got %v %v", results[0].Tags, results[0].Labels)
+	}
+
+	results, err = reg.SearchSchemas(ctx, ".", SchemaSearchOptions{Tags: []string{"pii"}, SchemaType: "AVRO", Field: "EMAIL"})
+	if err != nil {
+		t.Fatalf("SearchSchemas: %v", err)
+	}
+	if len(results) != 1 || results[0].Schema.Subject != "customers-value" || len(results[0].Fields) != 1 {
+		t.Errorf("expected only customers-value with one matching field, got %+v", results)
+	}
+
+	results, err = reg.SearchSchemas(ctx, ".", SchemaSearchOptions{Field: "email", SubjectPrefix: "events"})
+	if err != nil {
+		t.Fatalf("SearchSchemas: %v", err)
+	}
+	if len(results) != 1 || results[0].Schema.Subject != "events-value" {
+		t.Errorf("expected only events-value, got %+v", results)
+	}
+
+	results, err = reg.SearchSchemas(ctx, ".", SchemaSearchOptions{Labels: map[string]string{"owner": "crm"}})
+	if err != nil {
+		t.Fatalf("SearchSchemas: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected the version label to override the subject label, got %+v", results)
+	}
+
+	if err := reg.DeleteTags(ctx, ".", "customers-value", 0); err != nil {
+		t.Fatalf("DeleteTags: %v", err)
+	}
+	if err := reg.DeleteTags(ctx, ".", "customers-value", 0); err != nil {
+		t.Errorf("expected deleting absent tags to succeed, got %v", err)
+	}
+	rec, err = reg.GetTags(ctx, ".", "customers-value", 1)
+	if err != nil || strings.Join(rec.Tags, ",") != "golden" {
+		t.Errorf("expected version tags to survive deleting subject tags, got %+v, %v", rec, err)
+	}
+}
+
+func TestImportSession_CommitRestoresModes(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	if err := reg.SetMode(ctx, ".", "orders-value", "READONLY", false); err != nil {
+		t.Fatalf("SetMode: %v", err)
+	}
+
+	session, err := reg.OpenImportSession(ctx, ".", []string{"orders-value", " payments-value ", "orders-value"}, "admin")
+	if err != nil {
+		t.Fatalf("OpenImportSession: %v", err)
+	}
+	if len(session.Subjects) != 2 || session.Subjects[1] != "payments-value" {
+		t.Errorf("expected trimmed, de-duplicated subjects, got %v", session.Subjects)
+	}
+	if mode, _ := reg.GetMode(ctx, ".", "payments-value"); mode != "IMPORT" {
+		t.Errorf("expected IMPORT mode during the session, got %s", mode)
+	}
+	if _, err := reg.OpenImportSession(ctx, ".", []string{"payments-value"}, ""); !errors.Is(err, ErrImportSessionConflict) {
+		t.Errorf("expected ErrImportSessionConflict, got %v", err)
+	}
+
+	if _, err := reg.StageImportSchemas(ctx, ".", session.ID, []ImportSchemaRequest{
+		{ID: 10, Subject: "other-value", Version: 1, Schema: `"string"`},
+	}); !errors.Is(err, ErrInvalidImportSession) {
+		t.Errorf("expected ErrInvalidImportSession for a subject outside the session, got %v", err)
+	}
+	for _, req := range []ImportSchemaRequest{
+		{ID: 10, Subject: "orders-value", Version: 1, Schema: `"string"`},
+		{ID: 11, Subject: "payments-value", Version: 1, Schema: `"int"`},
+	} {
+		if _, err := reg.StageImportSchemas(ctx, ".", session.ID, []ImportSchemaRequest{req}); err != nil {
+			t.Fatalf("StageImportSchemas: %v", err)
+		}
+	}
+	if _, err := reg.GetSchemaBySubjectVersion(ctx, ".", "orders-value", 1); err == nil {
+		t.Error("staged schemas must not be visible before commit")
+	}
+
+	session, result, err := reg.CommitImportSession(ctx, ".", session.ID)
+	if err != nil {
+		t.Fatalf("CommitImportSession: %v", err)
+	}
+	if result.Imported != 2 || session.State != storage.ImportSessionCommitted || session.Staged != 2 {
+		t.Errorf("unexpected commit: %+v, %+v", result, session)
+	}
+	if rec, err := reg.GetSchemaBySubjectVersion(ctx, ".", "payments-value", 1); err != nil || rec.ID != 11 {
+		t.Errorf("expected committed schema with ID 11, got %+v, %v", rec, err)
+	}
+	if mode, _ := reg.GetMode(ctx, ".", "orders-value"); mode != "READONLY" {
+		t.Errorf("expected READONLY restored, got %s", mode)
+	}
+	if mode, _ := reg.GetMode(ctx, ".", "payments-value"); mode != "READWRITE" {
+		t.Errorf("expected subject-level mode removed, got %s", mode)
+	}
+	if _, err := reg.AbortImportSession(ctx, ".", session.ID); !errors.Is(err, ErrImportSessionClosed) {
+		t.Errorf("expected ErrImportSessionClosed after commit, got %v", err)
+	}
+}
+
+func TestImportSession_InvalidCommitStaysOpen(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	session, err := reg.OpenImportSession(ctx, ".", []string{"orders-value"}, "")
+	if err != nil {
+		t.Fatalf("OpenImportSession: %v", err)
+	}
+	if _, err := reg.StageImportSchemas(ctx, ".", session.ID, []ImportSchemaRequest{
+		{ID: 10, Subject: "orders-value", Version: 1, Schema: `"string"`},
+		{ID: 11, Subject: "orders-value", Version: 2, Schema: `{"type":`},
+	}); err != nil {
+		t.Fatalf("StageImportSchemas: %v", err)
+	}
+
+	session, result, err := reg.CommitImportSession(ctx, ".", session.ID)
+	if err != nil {
+		t.Fatalf("CommitImportSession: %v", err)
+	}
+	if result.Imported != 0 || result.Errors != 1 || session.State != storage.ImportSessionOpen {
+		t.Errorf("expected nothing imported and the session open, got %+v, %s", result, session.State)
+	}
+	if _, err := reg.GetSchemaBySubjectVersion(ctx, ".", "orders-value", 1); err == nil {
+		t.Error("no schema may be imported when one is invalid")
+	}
+
+	session, err = reg.AbortImportSession(ctx, ".", session.ID)
+	if err != nil {
+		t.Fatalf("AbortImportSession: %v", err)
+	}
+	if session.State != storage.ImportSessionAborted {
+		t.Errorf("expected ABORTED, got %s", session.State)
+	}
+	if mode, _ := reg.GetMode(ctx, ".", "orders-value"); mode != "READWRITE" {
+		t.Errorf("expected mode restored after abort, got %s", mode)
+	}
+	if _, err := reg.GetImportSession(ctx, ".other", session.ID); !errors.Is(err, storage.ErrImportSessionNotFound) {
+		t.Errorf("sessions must not be visible from other contexts, got %v", err)
+	}
+}
+
+func TestSchemaIDAliases_Resolve(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	rec, err := reg.RegisterSchema(ctx, ".", "orders-value", `"string"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	if err := reg.SetSchemaIDAliases(ctx, ".", []*storage.SchemaIDAliasRecord{
+		{Source: " us-east ", SourceID: 1, SchemaID: rec.ID},
+	}); err != nil {
+		t.Fatalf("SetSchemaIDAliases: %v", err)
+	}
+	if id, err := reg.ResolveSchemaID(ctx, ".", "us-east", 1); err != nil || id != rec.ID {
+		t.Errorf("expected ID 1 of us-east to resolve to %d, got %d, %v", rec.ID, id, err)
+	}
+	if id, err := reg.ResolveSchemaID(ctx, ".", "", 42); err != nil || id != 42 {
+		t.Errorf("expected an ID without a source to be returned as is, got %d, %v", id, err)
+	}
+	if _, err := reg.ResolveSchemaID(ctx, ".", "us-west", 1); !errors.Is(err, storage.ErrIDAliasNotFound) {
+		t.Errorf("expected ErrIDAliasNotFound for another source, got %v", err)
+	}
+
+	invalid := [][]*storage.SchemaIDAliasRecord{
+		nil,
+		{{Source: "", SourceID: 1, SchemaID: rec.ID}},
+		{{Source: "us-west", SourceID: 0, SchemaID: rec.ID}},
+		{{Source: "us-west", SourceID: 2, SchemaID: rec.ID + 100}},
+		{{Source: "us-west", SourceID: 2, SchemaID: rec.ID}, {Source: "us-west", SourceID: 2, SchemaID: rec.ID + 1}},
+	}
+	for i, aliases := range invalid {
+		if err := reg.SetSchemaIDAliases(ctx, ".", aliases); !errors.Is(err, ErrInvalidIDAlias) {
+			t.Errorf("case %d: expected ErrInvalidIDAlias, got %v", i, err)
+		}
+	}
+	if aliases, _ := reg.ListSchemaIDAliases(ctx, ".", "us-west"); len(aliases) != 0 {
+		t.Errorf("expected no alias stored from a rejected request, got %d", len(aliases))
+	}
+
+	if err := reg.DeleteSchemaIDAlias(ctx, ".", "us-east", 1); err != nil {
+		t.Fatalf("DeleteSchemaIDAlias: %v", err)
+	}
+	if _, err := reg.ResolveSchemaID(ctx, ".", "us-east", 1); !errors.Is(err, storage.ErrIDAliasNotFound) {
+		t.Errorf("expected the alias to be deleted, got %v", err)
+	}
+}
+
+func TestFreezeVersion_BlocksDeletion(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	for _, schema := range []string{`"string"`, `"int"`} {
+		if _, err := reg.RegisterSchema(ctx, ".", "orders-value", schema, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema: %v", err)
+		}
+	}
+
+	if _, err := reg.FreezeVersion(ctx, ".", "orders-value", 9, "alice", ""); !errors.Is(err, storage.ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound freezing a missing version, got %v", err)
+	}
+	rec, err := reg.FreezeVersion(ctx, ".", "orders-value", -1, "alice", "production")
+	if err != nil {
+		t.Fatalf("FreezeVersion: %v", err)
+	}
+	if rec.Version != 2 || rec.FrozenBy != "alice" {
+		t.Errorf("expected latest to resolve to version 2, got %+v", rec)
+	}
+
+	if _, err := reg.DeleteVersion(ctx, ".", "orders-value", 2, false); !errors.Is(err, ErrVersionFrozen) {
+		t.Errorf("expected ErrVersionFrozen soft-deleting a frozen version, got %v", err)
+	}
+	if _, err := reg.DeleteVersion(ctx, ".", "orders-value", 2, true); !errors.Is(err, ErrVersionFrozen) {
+		t.Errorf("expected ErrVersionFrozen permanently deleting a frozen version, got %v", err)
+	}
+	if _, err := reg.DeleteSubject(ctx, ".", "orders-value", false); !errors.Is(err, ErrVersionFrozen) {
+		t.Errorf("expected ErrVersionFrozen deleting the subject, got %v", err)
+	}
+	// Other versions can still be deleted.
+	if _, err := reg.DeleteVersion(ctx, ".", "orders-value", 1, false); err != nil {
+		t.Errorf("DeleteVersion of an unfrozen version: %v", err)
+	}
+
+	if _, err := reg.UnfreezeVersion(ctx, ".", "orders-value", 2); err != nil {
+		t.Fatalf("UnfreezeVersion: %v", err)
+	}
+	if _, err := reg.UnfreezeVersion(ctx, ".", "orders-value", 2); !errors.Is(err, storage.ErrFrozenVersionNotFound) {
+		t.Errorf("expected ErrFrozenVersionNotFound unfreezing twice, got %v", err)
+	}
+	if _, err := reg.DeleteSubject(ctx, ".", "orders-value", false); err != nil {
+		t.Errorf("DeleteSubject after unfreezing: %v", err)
+	}
+}
+
+// --- Maintenance window tests ---
+
+func TestMaintenance_ActiveWindowOverridesMode(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	reg.SetMode(ctx, ".myctx", "my-subject", "READWRITE", false)
+
+	// A window that has not started does not change the mode.
+	future, err := reg.ScheduleMaintenance(ctx, time.Now().Add(time.Hour), 30*time.Minute, "storage upgrade", "admin")
+	if err != nil {
+		t.Fatalf("ScheduleMaintenance: %v", err)
+	}
+	if state := MaintenanceState(future, time.Now()); state != MaintenanceScheduled {
+		t.Errorf("expected SCHEDULED, got %s", state)
+	}
+	if mode, _ := reg.GetMode(ctx, ".myctx", "my-subject"); mode != "READWRITE" {
+		t.Errorf("expected READWRITE before the window, got %s", mode)
+	}
+
+	window, err := reg.ScheduleMaintenance(ctx, time.Time{}, time.Hour, "failover drill", "admin")
+	if err != nil {
+		t.Fatalf("ScheduleMaintenance: %v", err)
+	}
+	if mode, _ := reg.GetMode(ctx, ".myctx", "my-subject"); mode != "READONLY_OVERRIDE" {
+		t.Errorf("expected READONLY_OVERRIDE during the window, got %s", mode)
+	}
+	if blocked, _ := reg.CheckModeForWrite(ctx, ".", "orders-value"); blocked != "READONLY_OVERRIDE" {
+		t.Errorf("expected writes to be blocked during maintenance, got %q", blocked)
+	}
+	if !reg.MaintenanceActive() {
+		t.Error("expected MaintenanceActive during the window")
+	}
+
+	// Cancelling an active window ends it at once and the mode reverts.
+	window, err = reg.CancelMaintenance(ctx, window.ID)
+	if err != nil {
+		t.Fatalf("CancelMaintenance: %v", err)
+	}
+	if !window.Cancelled || window.EndsAt.After(time.Now()) {
+		t.Errorf("expected the window cancelled and ended, got %+v", window)
+	}
+	if mode, _ := reg.GetMode(ctx, ".myctx", "my-subject"); mode != "READWRITE" {
+		t.Errorf("expected READWRITE after cancelling, got %s", mode)
+	}
+	if _, err := reg.CancelMaintenance(ctx, window.ID); !errors.Is(err, ErrMaintenanceEnded) {
+		t.Errorf("expected ErrMaintenanceEnded, got %v", err)
+	}
+}
+
+func TestMaintenance_WindowRevertsWhenItEnds(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	if _, err := reg.ScheduleMaintenance(ctx, time.Now().Add(-time.Hour), time.Hour+100*time.Millisecond, "short", ""); err != nil {
+		t.Fatalf("ScheduleMaintenance: %v", err)
+	}
+	if mode, _ := reg.GetMode(ctx, ".", ""); mode != "READONLY_OVERRIDE" {
+		t.Errorf("expected READONLY_OVERRIDE during the window, got %s", mode)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if mode, _ := reg.GetMode(ctx, ".", ""); mode != "READWRITE" {
+		t.Errorf("expected READWRITE once the window ended, got %s", mode)
+	}
+	if reg.MaintenanceActive() {
+		t.Error("expected no active maintenance after the window")
+	}
+}
+
+func TestMaintenance_Validation(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		startsAt time.Time
+		duration time.Duration
+		reason   string
+	}{
+		{"no reason", time.Time{}, time.Hour, "  "},
+		{"zero duration", time.Time{}, 0, "upgrade"},
+		{"too long", time.Time{}, 8 * 24 * time.Hour, "upgrade"},
+		{"in the past", time.Now().Add(-2 * time.Hour), time.Hour, "upgrade"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := reg.ScheduleMaintenance(ctx, tt.startsAt, tt.duration, tt.reason, ""); !errors.Is(err, ErrInvalidMaintenance) {
+				t.Errorf("expected ErrInvalidMaintenance, got %v", err)
+			}
+		})
+	}
+	if _, err := reg.CancelMaintenance(ctx, "missing"); !errors.Is(err, storage.ErrMaintenanceNotFound) {
+		t.Errorf("expected ErrMaintenanceNotFound, got %v", err)
+	}
+}
+
+func TestPlanRegistration(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+	v1 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", v1, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"note","type":["null","string"],"default":null}]}`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, err := reg.DeleteVersion(ctx, ".", "orders-value", 2, false); err != nil {
+		t.Fatalf("DeleteVersion: %v", err)
+	}
+
+	// An existing version is returned as is.
+	rec, created, err := reg.PlanRegistration(ctx, ".", "orders-value", v1, storage.SchemaTypeAvro, nil, RegisterOpts{})
+	if err != nil || created || rec.Version != 1 || rec.ID == 0 {
+		t.Errorf("PlanRegistration of an existing schema = %+v, %v, %v", rec, created, err)
+	}
+
+	// A new schema gets the next version after the soft-deleted one.
+	v3 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"qty","type":"int","default":0}]}`
+	rec, created, err = reg.PlanRegistration(ctx, ".", "orders-value", v3, storage.SchemaTypeAvro, nil, RegisterOpts{})
+	if err != nil || !created || rec.Version != 3 || rec.ID != 0 {
+		t.Errorf("PlanRegistration of a new schema = %+v, %v, %v", rec, created, err)
+	}
+	if versions, _ := reg.GetVersions(ctx, ".", "orders-value", true); len(versions) != 2 {
+		t.Errorf("PlanRegistration stored a version: %v", versions)
+	}
+
+	// The schema registered under another subject keeps its ID.
+	rec, _, err = reg.PlanRegistration(ctx, ".", "payments-value", v1, storage.SchemaTypeAvro, nil, RegisterOpts{})
+	if err != nil || rec.Version != 1 || rec.ID == 0 {
+		t.Errorf("PlanRegistration under a new subject = %+v, %v", rec, err)
+	}
+
+	// Incompatible schemas are rejected as on registration.
+	if _, _, err := reg.PlanRegistration(ctx, ".", "orders-value", `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`, storage.SchemaTypeAvro, nil, RegisterOpts{}); !errors.Is(err, ErrIncompatibleSchema) {
+		t.Errorf("expected ErrIncompatibleSchema, got %v", err)
+	}
+}
+
+func TestGlobalSchemaIDs(t *testing.T) {
+	store := memory.NewStore(memory.WithGlobalIDs())
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	compatChecker := compatibility.NewChecker()
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := New(store, schemaRegistry, compatChecker, "NONE")
+	reg.SetGlobalIDs(true)
+	ctx := context.Background()
+
+	staging, err := reg.RegisterSchema(ctx, ".staging", "orders-value", `"string"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	def, err := reg.RegisterSchema(ctx, ".", "orders-value", `"long"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if def.ID == staging.ID {
+		t.Fatalf("expected distinct IDs across contexts, both got %d", def.ID)
+	}
+
+	// An ID registered in .staging resolves from the default context.
+	got, err := reg.GetSchemaByID(ctx, ".", staging.ID)
+	if err != nil {
+		t.Fatalf("GetSchemaByID: %v", err)
+	}
+	if got.Schema != `"string"` {
+		t.Errorf("expected the .staging schema, got %s", got.Schema)
+	}
+	subjects, err := reg.GetSubjectsBySchemaID(ctx, ".", staging.ID, false)
+	if err != nil || len(subjects) != 1 || subjects[0] != ":.staging:orders-value" {
+		t.Errorf("expected the qualified .staging subject, got %v, %v", subjects, err)
+	}
+	if holder, err := reg.SchemaIDContext(ctx, ".staging", def.ID); err != nil || holder != "." {
+		t.Errorf("SchemaIDContext = %q, %v", holder, err)
+	}
+
+	// Contexts of another tenant are not searched.
+	if err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".acme", Tenant: "acme"}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	acme, err := reg.RegisterSchema(ctx, ".acme", "orders-value", `"int"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, err := reg.GetSchemaByID(ctx, ".", acme.ID); !errors.Is(err, storage.ErrSchemaNotFound) {
+		t.Errorf("expected ErrSchemaNotFound for another tenant's ID, got %v", err)
+	}
+
+	// Importing a different schema under an ID used in another context fails.
+	if _, err := reg.RegisterSchemaWithID(ctx, ".dev", "orders-value", `"boolean"`, storage.SchemaTypeAvro, nil, staging.ID, 1); !errors.Is(err, ErrImportIDConflict) {
+		t.Errorf("expected ErrImportIDConflict, got %v", err)
+	}
+	if _, err := reg.RegisterSchemaWithID(ctx, ".dev", "orders-value", `"string"`, storage.SchemaTypeAvro, nil, staging.ID, 1); err != nil {
+		t.Errorf("expected importing the same schema under its ID to succeed, got %v", err)
+	}
+}
+
+// --- VerifyConsistency tests ---
+
+// duplicateVersionStore wraps a memory store and returns the last version
+// of a subject twice, as a backend with a broken uniqueness constraint would.
+type duplicateVersionStore struct {
+	*memory.Store
+	subject string
+}
+
+func (d *duplicateVersionStore) GetSchemasBySubject(ctx context.Context, registryCtx string, subject string, includeDeleted bool) ([]*storage.SchemaRecord, error) {
+	list, err := d.Store.GetSchemasBySubject(ctx, registryCtx, subject, includeDeleted)
+	if err != nil || subject != d.subject || len(list) == 0 {
+		return list, err
+	}
+	return append(list, list[len(list)-1]), nil
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	underlying := memory.NewStore()
+	ctx := context.Background()
+	underlying.SetGlobalConfig(ctx, ".", &storage.ConfigRecord{CompatibilityLevel: "NONE"})
+	store := &duplicateVersionStore{Store: underlying, subject: "dup-value"}
+
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	compatChecker := compatibility.NewChecker()
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := New(store, schemaRegistry, compatChecker, "NONE")
+
+	// A healthy registry has no findings.
+	base, err := reg.RegisterSchema(ctx, ".", "base-value", `{"type":"record","name":"Base","fields":[{"name":"a","type":"int"}]}`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "ref-value",
+		`{"type":"record","name":"Ref","fields":[{"name":"b","type":"Base"}]}`, storage.SchemaTypeAvro,
+		[]storage.Reference{{Name: "Base", Subject: "base-value", Version: base.Version}}); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "norm-value", `{"type": "string"}`, storage.SchemaTypeAvro, nil, RegisterOpts{Normalize: true}); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	report, err := reg.VerifyConsistency(ctx, ".", false, nil)
+	if err != nil {
+		t.Fatalf("VerifyConsistency: %v", err)
+	}
+	if len(report.Findings) != 0 || report.SubjectsChecked != 3 || report.VersionsChecked != 3 {
+		t.Fatalf("expected a clean report of 3 subjects, got %+v", report)
+	}
+
+	// Break each invariant by writing to storage directly.
+	for _, rec := range []*storage.SchemaRecord{
+		{ID: 50, Subject: "orphan-value", Version: 1, SchemaType: storage.SchemaTypeAvro, Schema: `"int"`,
+			References: []storage.Reference{{Name: "Gone", Subject: "gone-value", Version: 1}}, Fingerprint: "f1"},
+		{ID: 51, Subject: "badfp-value", Version: 1, SchemaType: storage.SchemaTypeAvro, Schema: `"long"`, Fingerprint: "bogus"},
+		{ID: 100, Subject: "dup-value", Version: 1, SchemaType: storage.SchemaTypeAvro, Schema: `"boolean"`, Fingerprint: "f3"},
+	} {
+		if err := underlying.ImportSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("ImportSchema: %v", err)
+		}
+	}
+
+	report, err = reg.VerifyConsistency(ctx, ".", false, nil)
+	if err != nil {
+		t.Fatalf("VerifyConsistency: %v", err)
+	}
+	has := func(kind, subject string) bool {
+		for _, f := range report.Findings {
+			if f.Kind == kind && f.Subject == subject {
+				return true
+			}
+		}
+		return false
+	}
+	for _, want := range []struct{ kind, subject string }{
+		{FindingOrphanedReference, "orphan-value"},
+		{FindingFingerprintMismatch, "badfp-value"},
+		{FindingFingerprintMismatch, "dup-value"},
+		{FindingDuplicateVersion, "dup-value"},
+		{FindingIDSequenceBehind, ""},
+	} {
+		if !has(want.kind, want.subject) {
+			t.Errorf("expected a %s finding for %q, got %+v", want.kind, want.subject, report.Findings)
+		}
+	}
+	if len(report.Findings) != 5 || report.Unrepaired() != 5 {
+		t.Errorf("expected 5 unrepaired findings, got %+v", report.Findings)
+	}
+
+	// Repair advances the ID sequence and leaves the rest to the operator.
+	report, err = reg.VerifyConsistency(ctx, ".", true, nil)
+	if err != nil {
+		t.Fatalf("VerifyConsistency: %v", err)
+	}
+	if report.Unrepaired() != 4 {
+		t.Errorf("expected 4 unrepaired findings after repair, got %+v", report.Findings)
+	}
+	if next, err := underlying.PeekNextID(ctx, "."); err != nil || next != 101 {
+		t.Errorf("PeekNextID = %d, %v; want 101", next, err)
+	}
+	report, err = reg.VerifyConsistency(ctx, ".", false, nil)
+	if err != nil {
+		t.Fatalf("VerifyConsistency: %v", err)
+	}
+	for _, f := range report.Findings {
+		if f.Kind == FindingIDSequenceBehind {
+			t.Errorf("expected the ID sequence to stay repaired, got %+v", f)
+		}
+	}
+}
+
+func TestOrphanedReferences(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+	schemaRegistry := schema.NewRegistry()
+	schemaRegistry.Register(avro.NewParser())
+	compatChecker := compatibility.NewChecker()
+	compatChecker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := New(store, schemaRegistry, compatChecker, "NONE")
+
+	register := func(subject, schemaStr string, refs []storage.Reference) *storage.SchemaRecord {
+		t.Helper()
+		rec, err := reg.RegisterSchema(ctx, ".", subject, schemaStr, storage.SchemaTypeAvro, refs)
+		if err != nil {
+			t.Fatalf("RegisterSchema %s: %v", subject, err)
+		}
+		return rec
+	}
+	register("base-value", `{"type":"record","name":"Base","fields":[{"name":"a","type":"int"}]}`, nil)
+	register("ref-value", `{"type":"record","name":"Ref","fields":[{"name":"b","type":"Base"}]}`,
+		[]storage.Reference{{Name: "Base", Subject: "base-value", Version: 1}})
+	register("old-value", `{"type":"record","name":"Old","fields":[{"name":"a","type":"int"}]}`, nil)
+	register("uses-old-value", `{"type":"record","name":"UsesOld","fields":[{"name":"o","type":"Old"}]}`,
+		[]storage.Reference{{Name: "Old", Subject: "old-value", Version: 1}})
+
+	orphans, err := reg.OrphanedReferences(ctx, "")
+	if err != nil {
+		t.Fatalf("OrphanedReferences: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Fatalf("expected no orphaned references, got %+v", orphans)
+	}
+
+	// Soft-delete a referenced version, re-import another with different
+	// content after a hard delete, and import a schema referencing a
+	// version that never existed, all in storage directly.
+	if err := store.DeleteSchema(ctx, ".", "old-value", 1, false); err != nil {
+		t.Fatalf("DeleteSchema: %v", err)
+	}
+	if err := store.DeleteSchema(ctx, ".", "base-value", 1, false); err != nil {
+		t.Fatalf("DeleteSchema: %v", err)
+	}
+	if err := store.DeleteSchema(ctx, ".", "base-value", 1, true); err != nil {
+		t.Fatalf("DeleteSchema: %v", err)
+	}
+	for _, rec := range []*storage.SchemaRecord{
+		{ID: 50, Subject: "base-value", Version: 1, SchemaType: storage.SchemaTypeAvro,
+			Schema: `{"type":"record","name":"Other","fields":[{"name":"a","type":"int"}]}`, Fingerprint: "f1"},
+		{ID: 51, Subject: "orphan-value", Version: 1, SchemaType: storage.SchemaTypeAvro, Schema: `"int"`,
+			References: []storage.Reference{{Name: "Gone", Subject: "gone-value", Version: 1}}, Fingerprint: "f2"},
+	} {
+		if err := store.ImportSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("ImportSchema: %v", err)
+		}
+	}
+
+	orphans, err = reg.OrphanedReferences(ctx, ".")
+	if err != nil {
+		t.Fatalf("OrphanedReferences: %v", err)
+	}
+	want := []struct{ subject, kind, ref string }{
+		{"orphan-value", OrphanMissing, "gone-value"},
+		{"ref-value", OrphanReplaced, "base-value"},
+		{"uses-old-value", OrphanSoftDeleted, "old-value"},
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("expected %d orphaned references, got %+v", len(want), orphans)
+	}
+	for i, w := range want {
+		o := orphans[i]
+		if o.Subject != w.subject || o.Kind != w.kind || o.Reference.Subject != w.ref || o.Version != 1 {
+			t.Errorf("orphan %d: expected %s referencing %s (%s), got %+v", i, w.subject, w.ref, w.kind, o)
+		}
+	}
+	if !strings.Contains(orphans[1].Message, "no longer parses") {
+		t.Errorf("expected the replaced reference to report the parse failure, got %q", orphans[1].Message)
+	}
+}
+
+func TestPendingSchema_ApproveAndReject(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+	reg.SetApprovalContexts([]string{"regulated"})
+	if !reg.RequiresApproval(".regulated") || reg.RequiresApproval(".") {
+		t.Fatal("expected only .regulated to require approval")
+	}
+
+	v1 := PendingSchemaRequest{Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`}
+	pending, existing, err := reg.SubmitPendingSchema(ctx, ".regulated", "orders-value", v1, "dev")
+	if err != nil || existing != nil {
+		t.Fatalf("SubmitPendingSchema = %+v, %v", existing, err)
+	}
+	if pending.State != storage.PendingSchemaPending || pending.SubmittedBy != "dev" || pending.Context != ".regulated" {
+		t.Errorf("unexpected pending schema %+v", pending)
+	}
+	if _, err := reg.GetLatestSchema(ctx, ".regulated", "orders-value"); err == nil {
+		t.Error("expected nothing to be registered before approval")
+	}
+
+	// Submitting the same registration again returns the pending schema.
+	again, _, err := reg.SubmitPendingSchema(ctx, ".regulated", "orders-value", v1, "dev")
+	if err != nil || again.ID != pending.ID {
+		t.Errorf("resubmitting = %+v, %v; want pending schema %s", again, err, pending.ID)
+	}
+
+	// An invalid schema is rejected at submission.
+	if _, _, err := reg.SubmitPendingSchema(ctx, ".regulated", "orders-value", PendingSchemaRequest{Schema: `{`}, "dev"); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, got %v", err)
+	}
+
+	// Four eyes: the submitter cannot approve their own schema.
+	if _, _, err := reg.ApprovePendingSchema(ctx, pending.ID, Reviewer{Username: "dev"}, ""); !errors.Is(err, ErrSelfApproval) {
+		t.Errorf("expected ErrSelfApproval, got %v", err)
+	}
+	approved, record, err := reg.ApprovePendingSchema(ctx, pending.ID, Reviewer{Username: "admin"}, "reviewed in CHG-42")
+	if err != nil {
+		t.Fatalf("ApprovePendingSchema: %v", err)
+	}
+	if approved.State != storage.PendingSchemaApproved || approved.ReviewedBy != "admin" ||
+		approved.SchemaID != record.ID || approved.Version != 1 || record.ID == 0 {
+		t.Errorf("unexpected approval %+v of %+v", approved, record)
+	}
+	if _, _, err := reg.ApprovePendingSchema(ctx, pending.ID, Reviewer{Username: "admin"}, ""); !errors.Is(err, ErrPendingSchemaReviewed) {
+		t.Errorf("expected ErrPendingSchemaReviewed, got %v", err)
+	}
+
+	// A registered schema is not queued again.
+	queued, existing, err := reg.SubmitPendingSchema(ctx, ".regulated", "orders-value", v1, "dev")
+	if err != nil || queued != nil || existing == nil || existing.ID != record.ID {
+		t.Errorf("resubmitting a registered schema = %+v, %+v, %v", queued, existing, err)
+	}
+
+	v2 := PendingSchemaRequest{Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"note","type":["null","string"],"default":null}]}`}
+	pending, _, err = reg.SubmitPendingSchema(ctx, ".regulated", "orders-value", v2, "dev")
+	if err != nil {
+		t.Fatalf("SubmitPendingSchema: %v", err)
+	}
+	if _, err := reg.RejectPendingSchema(ctx, pending.ID, Reviewer{Username: "admin"}, " "); !errors.Is(err, ErrInvalidReview) {
+		t.Errorf("expected ErrInvalidReview without a reason, got %v", err)
+	}
+	rejected, err := reg.RejectPendingSchema(ctx, pending.ID, Reviewer{Username: "admin"}, "note needs a doc")
+	if err != nil {
+		t.Fatalf("RejectPendingSchema: %v", err)
+	}
+	if rejected.State != storage.PendingSchemaRejected || rejected.Reason != "note needs a doc" {
+		t.Errorf("unexpected rejection %+v", rejected)
+	}
+	if versions, _ := reg.GetVersions(ctx, ".regulated", "orders-value", false); len(versions) != 1 {
+		t.Errorf("expected the rejected schema not to be registered, got versions %v", versions)
+	}
+
+	list, err := reg.ListPendingSchemas(ctx, ".regulated", "")
+	if err != nil || len(list) != 2 || list[0].ID != pending.ID {
+		t.Errorf("ListPendingSchemas = %+v, %v", list, err)
+	}
+	if list, _ := reg.ListPendingSchemas(ctx, "", storage.PendingSchemaPending); len(list) != 0 {
+		t.Errorf("expected nothing left pending, got %+v", list)
+	}
+}
+
+func TestPendingSchema_Reviewers(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+	reg.SetApprovalContexts([]string{"regulated"})
+	reg.SetApprovalReviewers(map[string][]string{"regulated": {"role:admin"}})
+
+	v1 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+	v2 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".regulated", "orders-value", v1, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	// Without reviewers in its meta, a subject gets those of its context.
+	pending, _, err := reg.SubmitPendingSchema(ctx, ".regulated", "orders-value", PendingSchemaRequest{Schema: v2}, "dev")
+	if err != nil {
+		t.Fatalf("SubmitPendingSchema: %v", err)
+	}
+	if !slices.Equal(pending.Reviewers, []string{"role:admin"}) {
+		t.Errorf("expected the context reviewers, got %v", pending.Reviewers)
+	}
+	if _, err := reg.RejectPendingSchema(ctx, pending.ID, Reviewer{Username: "ops", Role: "super_admin"}, "no"); !errors.Is(err, ErrNotAssignedReviewer) {
+		t.Errorf("expected ErrNotAssignedReviewer, got %v", err)
+	}
+	if _, err := reg.RejectPendingSchema(ctx, pending.ID, Reviewer{Username: "bob", Role: "admin"}, "id must stay a string"); err != nil {
+		t.Fatalf("RejectPendingSchema by role: %v", err)
+	}
+
+	// Reviewers in the subject's meta replace those of the context.
+	if _, err := reg.SetSubjectMeta(ctx, ".regulated", &storage.SubjectMetaRecord{Subject: "orders-value", Reviewers: []string{" alice "}}); err != nil {
+		t.Fatalf("SetSubjectMeta: %v", err)
+	}
+	pending, _, err = reg.SubmitPendingSchema(ctx, ".regulated", "orders-value", PendingSchemaRequest{Schema: v2}, "dev")
+	if err != nil {
+		t.Fatalf("SubmitPendingSchema: %v", err)
+	}
+	if !slices.Equal(pending.Reviewers, []string{"alice"}) {
+		t.Errorf("expected the subject reviewers, got %v", pending.Reviewers)
+	}
+	if _, _, err := reg.ApprovePendingSchema(ctx, pending.ID, Reviewer{Username: "bob", Role: "admin"}, ""); !errors.Is(err, ErrNotAssignedReviewer) {
+		t.Errorf("expected ErrNotAssignedReviewer, got %v", err)
+	}
+	if approved, _, err := reg.ApprovePendingSchema(ctx, pending.ID, Reviewer{Username: "alice", Role: "admin"}, ""); err != nil || approved.ReviewedBy != "alice" {
+		t.Errorf("ApprovePendingSchema by alice = %+v, %v", approved, err)
+	}
+
+	for _, reviewers := range [][]string{{""}, {"role:"}} {
+		if _, err := reg.SetSubjectMeta(ctx, ".regulated", &storage.SubjectMetaRecord{Subject: "orders-value", Reviewers: reviewers}); !errors.Is(err, ErrInvalidSubjectMeta) {
+			t.Errorf("reviewers %q: expected ErrInvalidSubjectMeta, got %v", reviewers, err)
+		}
+	}
+}
+
+func TestValidatePayload(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+	if _, err := reg.RegisterSchema(ctx, ".", "address", `{"type":"object","properties":{"zip":{"type":"string"}},"required":["zip"]}`, storage.SchemaTypeJSON, nil); err != nil {
+		t.Fatalf("RegisterSchema address: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value",
+		`{"type":"object","properties":{"id":{"type":"integer"},"address":{"$ref":"address.json"}},"required":["id"]}`,
+		storage.SchemaTypeJSON, []storage.Reference{{Name: "address.json", Subject: "address", Version: 1}}); err != nil {
+		t.Fatalf("RegisterSchema orders-value: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "avro-value", `"string"`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema avro-value: %v", err)
+	}
+
+	record, problems, err := reg.ValidatePayload(ctx, ".", "orders-value", -1, map[string]interface{}{
+		"id": json.Number("7"), "address": map[string]interface{}{"zip": "12345"},
+	})
+	if err != nil {
+		t.Fatalf("ValidatePayload: %v", err)
+	}
+	if record.Version != 1 || len(problems) != 0 {
+		t.Errorf("expected a valid payload for version 1, got version %d and %+v", record.Version, problems)
+	}
+
+	_, problems, err = reg.ValidatePayload(ctx, ".", "orders-value", 1, map[string]interface{}{
+		"id": "seven", "address": map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("ValidatePayload: %v", err)
+	}
+	locations := map[string]bool{}
+	for _, p := range problems {
+		locations[p.InstanceLocation] = true
+	}
+	if len(problems) != 2 || !locations["/id"] || !locations["/address"] {
+		t.Errorf("expected problems at /id and, through the reference, /address; got %+v", problems)
+	}
+
+	if _, _, err := reg.ValidatePayload(ctx, ".", "avro-value", 1, "x"); !errors.Is(err, ErrUnsupportedSchemaType) {
+		t.Errorf("expected ErrUnsupportedSchemaType for an Avro subject, got %v", err)
+	}
+	if _, _, err := reg.ValidatePayload(ctx, ".", "orders-value", 5, nil); !errors.Is(err, storage.ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestReferencesByID(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+	address := `{"type":"record","name":"Address","namespace":"geo","fields":[{"name":"city","type":"string"}]}`
+	addr, err := reg.RegisterSchema(ctx, ".", "address", address, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema address: %v", err)
+	}
+	// The same schema under a second subject shares the ID.
+	if _, err := reg.RegisterSchema(ctx, ".", "address-copy", address, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema address-copy: %v", err)
+	}
+
+	user := `{"type":"record","name":"User","namespace":"app","fields":[{"name":"home","type":"geo.Address"}]}`
+	byID := []storage.Reference{{Name: "geo.Address", ID: addr.ID}}
+	rec, err := reg.RegisterSchema(ctx, ".", "user", user, storage.SchemaTypeAvro, byID)
+	if err != nil {
+		t.Fatalf("RegisterSchema by ID: %v", err)
+	}
+	want := storage.Reference{Name: "geo.Address", Subject: "address", Version: 1}
+	if len(rec.References) != 1 || rec.References[0] != want {
+		t.Errorf("expected the reference to be stored as %+v, got %+v", want, rec.References)
+	}
+	if byID[0].ID != addr.ID || byID[0].Subject != "" {
+		t.Errorf("expected the caller's references to be left unchanged, got %+v", byID)
+	}
+
+	// Registering again by subject and version finds the same version.
+	again, err := reg.RegisterSchema(ctx, ".", "user", user, storage.SchemaTypeAvro, []storage.Reference{want})
+	if err != nil || again.Version != rec.Version {
+		t.Errorf("expected the registration by ID to match one by subject and version, got %+v, %v", again, err)
+	}
+	found, err := reg.LookupSchema(ctx, ".", "user", user, storage.SchemaTypeAvro, byID, false)
+	if err != nil || found.Version != rec.Version {
+		t.Errorf("expected lookup by ID to find version %d, got %+v, %v", rec.Version, found, err)
+	}
+	result, err := reg.CheckCompatibility(ctx, ".", "user", user, storage.SchemaTypeAvro, byID, "latest")
+	if err != nil || !result.IsCompatible {
+		t.Errorf("expected a compatible result checking by ID, got %+v, %v", result, err)
+	}
+
+	// A subject chooses among the subjects sharing the ID.
+	copyRec, err := reg.RegisterSchema(ctx, ".", "user-copy", user, storage.SchemaTypeAvro,
+		[]storage.Reference{{Name: "geo.Address", Subject: "address-copy", ID: addr.ID}})
+	if err != nil {
+		t.Fatalf("RegisterSchema by ID and subject: %v", err)
+	}
+	if copyRec.References[0].Subject != "address-copy" {
+		t.Errorf("expected the reference to use address-copy, got %+v", copyRec.References)
+	}
+
+	for _, refs := range [][]storage.Reference{
+		{{Name: "geo.Address", ID: addr.ID, Version: 1}},
+		{{Name: "geo.Address", ID: 9999}},
+		{{Name: "geo.Address", Subject: "user", ID: addr.ID}},
+	} {
+		if _, err := reg.RegisterSchema(ctx, ".", "user", user, storage.SchemaTypeAvro, refs); !errors.Is(err, ErrFailedResolveReferences) {
+			t.Errorf("%+v: expected ErrFailedResolveReferences, got %v", refs, err)
+		}
+	}
+}
+
+func TestLookupSchemaGlobal(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	ctx := context.Background()
+
+	schema := `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`
+	var id int64
+	for _, subject := range []string{"orders-value", "orders-archive-value"} {
+		rec, err := reg.RegisterSchema(ctx, ".", subject, schema, storage.SchemaTypeAvro, nil)
+		if err != nil {
+			t.Fatalf("RegisterSchema %s: %v", subject, err)
+		}
+		id = rec.ID
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "users-value", `"string"`, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	// The text from a message dump need not match byte for byte.
+	dumped := "{\n  \"type\": \"record\",\n  \"name\": \"Order\",\n  \"fields\": [{\"name\": \"id\", \"type\": \"long\"}]\n}"
+	matches, err := reg.LookupSchemaGlobal(ctx, ".", dumped, storage.SchemaTypeAvro, nil, false)
+	if err != nil {
+		t.Fatalf("LookupSchemaGlobal: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Schema.ID != id || len(matches[0].Versions) != 2 {
+		t.Fatalf("expected schema %d in both subjects, got %+v", id, matches)
+	}
+
+	if _, err := reg.DeleteSubject(ctx, ".", "orders-archive-value", false); err != nil {
+		t.Fatalf("DeleteSubject: %v", err)
+	}
+	match, err := reg.FindSchemaByFingerprint(ctx, ".", matches[0].Fingerprint, false)
+	if err != nil || len(match.Versions) != 1 || match.Versions[0].Subject != "orders-value" {
+		t.Errorf("expected only the live subject, got %+v, %v", match, err)
+	}
+	match, err = reg.FindSchemaByFingerprint(ctx, ".", matches[0].Fingerprint, true)
+	if err != nil || len(match.Versions) != 2 {
+		t.Errorf("expected the soft-deleted subject with deleted, got %+v, %v", match, err)
+	}
+
+	if _, err := reg.FindSchemaByFingerprint(ctx, ".", "unknown", false); !errors.Is(err, storage.ErrSchemaNotFound) {
+		t.Errorf("expected ErrSchemaNotFound for an unknown fingerprint, got %v", err)
+	}
+	if _, err := reg.LookupSchemaGlobal(ctx, ".", `"int"`, storage.SchemaTypeAvro, nil, false); !errors.Is(err, storage.ErrSchemaNotFound) {
+		t.Errorf("expected ErrSchemaNotFound for an unregistered schema, got %v", err)
+	}
+}
+
+func TestSchemaFingerprint(t *testing.T) {
+	reg := setupMultiTypeRegistry("NONE")
+	ctx := context.Background()
+
+	avroRec, err := reg.RegisterSchema(ctx, ".", "users-value", `"null"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	jsonRec, err := reg.RegisterSchema(ctx, ".", "events-value", `{"type":"object"}`, storage.SchemaTypeJSON, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	for _, tt := range []struct {
+		record    *storage.SchemaRecord
+		algorithm string
+		want      string
+	}{
+		{avroRec, FingerprintSHA256, avroRec.Fingerprint},
+		{avroRec, FingerprintMD5, "9b41ef67651c18488a8b08bb67c75699"},
+		{avroRec, FingerprintCRC64Avro, "63dd24e7cc258f8a"},
+		{jsonRec, FingerprintSHA256, jsonRec.Fingerprint},
+	} {
+		got, err := reg.SchemaFingerprint(ctx, ".", tt.record, tt.algorithm)
+		if err != nil || got != tt.want {
+			t.Errorf("%s of %s: expected %s, got %s, %v", tt.algorithm, tt.record.Schema, tt.want, got, err)
+		}
+	}
+
+	// A stored record without a fingerprint gets the one it would be stored with.
+	unstored := *avroRec
+	unstored.Fingerprint = ""
+	if got, _ := reg.SchemaFingerprint(ctx, ".", &unstored, FingerprintSHA256); got != avroRec.Fingerprint {
+		t.Errorf("expected computed sha256 %s, got %s", avroRec.Fingerprint, got)
+	}
+
+	if _, err := reg.SchemaFingerprint(ctx, ".", jsonRec, FingerprintCRC64Avro); !errors.Is(err, ErrUnsupportedFingerprint) {
+		t.Errorf("expected ErrUnsupportedFingerprint for crc64-avro of a JSON schema, got %v", err)
+	}
+	if _, err := reg.SchemaFingerprint(ctx, ".", avroRec, "sha1"); !errors.Is(err, ErrUnsupportedFingerprint) {
+		t.Errorf("expected ErrUnsupportedFingerprint for sha1, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+	v1 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`
+	v2 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"},{"name":"note","type":"string","default":""}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", v1, storage.SchemaTypeAvro, nil); err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+
+	desired := func() *DesiredState {
+		return &DesiredState{Contexts: []DesiredContext{
+			{Subjects: []DesiredSubject{
+				{Name: "orders-value", Compatibility: "full", Schemas: []DesiredSchema{{Schema: v1}, {Schema: v2}}},
+			}},
+			{Name: "team-a", Mode: "READONLY", Subjects: []DesiredSubject{
+				{Name: "payments-value", Schemas: []DesiredSchema{{Schema: `{"type":"string"}`}}},
+			}},
+		}}
+	}
+
+	plan, err := reg.Apply(ctx, desired(), true)
+	if err != nil {
+		t.Fatalf("Apply dry run: %v", err)
+	}
+	var actions []string
+	for _, c := range plan.Changes {
+		actions = append(actions, c.Action+" "+c.Context+" "+c.Subject)
+		if c.Applied || c.Error != "" {
+			t.Errorf("unexpected outcome in a dry run: %+v", c)
+		}
+	}
+	want := []string{
+		"set_compatibility . orders-value",
+		"register_schema . orders-value",
+		"create_context .team-a ",
+		"register_schema .team-a payments-value",
+		"set_mode .team-a ",
+	}
+	if strings.Join(actions, "; ") != strings.Join(want, "; ") {
+		t.Fatalf("plan = %v, want %v", actions, want)
+	}
+	if plan.Changes[1].Version != 2 {
+		t.Errorf("expected v2 to be planned as version 2, got %d", plan.Changes[1].Version)
+	}
+	if versions, _ := reg.GetVersions(ctx, ".", "orders-value", false); len(versions) != 1 {
+		t.Errorf("dry run registered a schema: versions %v", versions)
+	}
+
+	result, err := reg.Apply(ctx, desired(), false)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if result.Failed() || len(result.Changes) != len(want) {
+		t.Fatalf("unexpected result: %+v", result.Changes)
+	}
+	for _, c := range result.Changes {
+		if !c.Applied {
+			t.Errorf("change not applied: %+v", c)
+		}
+	}
+	if level, _ := reg.GetSubjectConfig(ctx, ".", "orders-value"); level != "FULL" {
+		t.Errorf("expected FULL for orders-value, got %q", level)
+	}
+	if mode, _ := reg.GetMode(ctx, ".team-a", ""); mode != "READONLY" {
+		t.Errorf("expected .team-a to be READONLY, got %q", mode)
+	}
+
+	// Applying the same state again changes nothing.
+	again, err := reg.Apply(ctx, desired(), false)
+	if err != nil {
+		t.Fatalf("Apply again: %v", err)
+	}
+	if len(again.Changes) != 0 {
+		t.Errorf("expected no changes, got %+v", again.Changes)
+	}
+}
+
+func TestApply_StopsAtFirstFailure(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+	state := &DesiredState{Contexts: []DesiredContext{{Subjects: []DesiredSubject{
+		{Name: "orders-value", Schemas: []DesiredSchema{{Schema: `{"type":"string"}`}, {Schema: `{"type":"int"}`}}},
+		{Name: "payments-value", Compatibility: "NONE"},
+	}}}}
+
+	result, err := reg.Apply(ctx, state, false)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !result.Failed() || len(result.Changes) != 3 {
+		t.Fatalf("unexpected result: %+v", result.Changes)
+	}
+	if c := result.Changes[0]; !c.Applied || c.Version != 1 {
+		t.Errorf("expected the first schema to be registered as version 1, got %+v", c)
+	}
+	if c := result.Changes[1]; c.Applied || c.Error == "" {
+		t.Errorf("expected the incompatible schema to fail, got %+v", c)
+	}
+	if c := result.Changes[2]; c.Applied || c.Error != "" {
+		t.Errorf("expected the change after the failure to be skipped, got %+v", c)
+	}
+}
+
+func TestApply_InvalidState(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	for name, state := range map[string]*DesiredState{
+		"duplicate context": {Contexts: []DesiredContext{{Name: ".a"}, {Name: "a"}}},
+		"global context":    {Contexts: []DesiredContext{{Name: "__GLOBAL"}}},
+		"unnamed subject":   {Contexts: []DesiredContext{{Subjects: []DesiredSubject{{}}}}},
+		"duplicate subject": {Contexts: []DesiredContext{{Subjects: []DesiredSubject{{Name: "s"}, {Name: "s"}}}}},
+		"bad compatibility": {Contexts: []DesiredContext{{Compatibility: "SOMETIMES"}}},
+		"bad mode":          {Contexts: []DesiredContext{{Subjects: []DesiredSubject{{Name: "s", Mode: "WRITEONLY"}}}}},
+		"empty schema":      {Contexts: []DesiredContext{{Subjects: []DesiredSubject{{Name: "s", Schemas: []DesiredSchema{{Schema: " "}}}}}}},
+	} {
+		if _, err := reg.Apply(context.Background(), state, true); !errors.Is(err, ErrInvalidDesiredState) {
+			t.Errorf("%s: expected ErrInvalidDesiredState, got %v", name, err)
+		}
+	}
+}
+
+// recordingMetrics is a RegistrationMetrics that keeps what it records.
+type recordingMetrics struct {
+	registrations []string
+	rejections    []string
+	imports       []string
+}
+
+func (m *recordingMetrics) RecordSchemaRegistration(schemaType string, success bool) {
+	m.registrations = append(m.registrations, fmt.Sprintf("%s %t", schemaType, success))
+}
+
+func (m *recordingMetrics) RecordCompatibilityRejection(schemaType, reason string) {
+	m.rejections = append(m.rejections, schemaType+" "+reason)
+}
+
+func (m *recordingMetrics) RecordSchemaImport(schemaType string, success bool) {
+	m.imports = append(m.imports, fmt.Sprintf("%s %t", schemaType, success))
+}
+
+func TestRegisterSchema_Metrics(t *testing.T) {
+	reg := setupTestRegistry("BACKWARD")
+	m := &recordingMetrics{}
+	reg.SetMetrics(m)
+	ctx := context.Background()
+
+	v1 := `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", v1, "", nil); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	incompatible := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"qty","type":"int"}]}`
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", incompatible, "", nil); !errors.Is(err, ErrIncompatibleSchema) {
+		t.Fatalf("expected ErrIncompatibleSchema, got %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".team-a", "orders-value", `{"type":"record"`, "", nil); err == nil {
+		t.Fatal("expected an invalid schema to be rejected")
+	}
+	// Dry runs are not counted.
+	if _, _, err := reg.PlanRegistration(ctx, ".", "orders-value", incompatible, "", nil, RegisterOpts{}); !errors.Is(err, ErrIncompatibleSchema) {
+		t.Fatalf("expected ErrIncompatibleSchema, got %v", err)
+	}
+
+	wantRegistrations := []string{"AVRO true", "AVRO false", "AVRO false"}
+	if fmt.Sprint(m.registrations) != fmt.Sprint(wantRegistrations) {
+		t.Errorf("expected registrations %v, got %v", wantRegistrations, m.registrations)
+	}
+	wantRejections := []string{"AVRO type_changed", "AVRO field_added"}
+	if fmt.Sprint(m.rejections) != fmt.Sprint(wantRejections) {
+		t.Errorf("expected rejections %v, got %v", wantRejections, m.rejections)
+	}
+}
+
+func TestImportSchemas_Metrics(t *testing.T) {
+	reg := setupTestRegistry("NONE")
+	m := &recordingMetrics{}
+	reg.SetMetrics(m)
+
+	_, err := reg.ImportSchemas(context.Background(), ".", []ImportSchemaRequest{
+		{ID: 100, Subject: "imported", Version: 1, Schema: `"string"`},
+		{ID: 101, Subject: "imported", Version: 2, SchemaType: storage.SchemaTypeJSON, Schema: `{"type":`},
+	})
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	want := []string{"AVRO true", "JSON false"}
+	if fmt.Sprint(m.imports) != fmt.Sprint(want) {
+		t.Errorf("expected imports %v, got %v", want, m.imports)
+	}
+	if len(m.registrations) != 0 {
+		t.Errorf("expected imports not to count as registrations, got %v", m.registrations)
+	}
+}
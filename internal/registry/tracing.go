@@ -0,0 +1,39 @@
+package registry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// tracerName names the instrumentation scope of registry spans.
+const tracerName = "github.com/axonops/axonops-schema-registry/internal/registry"
+
+// startSpan starts the span for a registry operation on a subject. Storage
+// calls made with the returned context are traced as its children.
+func startSpan(ctx context.Context, operation, registryCtx, subject string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "registry."+operation, trace.WithAttributes(
+		attribute.String("schema_registry.context", registryCtx),
+		attribute.String("schema_registry.subject", subject),
+	))
+}
+
+// endSpan records the outcome of a registry operation, with the schema it
+// returned if any, and ends its span.
+func endSpan(span trace.Span, record *storage.SchemaRecord, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if record != nil {
+		span.SetAttributes(
+			attribute.Int64("schema_registry.schema_id", record.ID),
+			attribute.Int("schema_registry.version", record.Version),
+		)
+	}
+	span.End()
+}
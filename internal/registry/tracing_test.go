@@ -0,0 +1,50 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+func TestRegistry_TracesRegistration(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	reg := setupTestRegistry("BACKWARD")
+	ctx := context.Background()
+	record, err := reg.RegisterSchema(ctx, ".", "orders-value", `"string"`, storage.SchemaTypeAvro, nil)
+	if err != nil {
+		t.Fatalf("RegisterSchema: %v", err)
+	}
+	if _, err := reg.RegisterSchema(ctx, ".", "orders-value", `"int"`, storage.SchemaTypeAvro, nil); err == nil {
+		t.Fatal("expected an incompatible schema to be rejected")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(ended))
+	}
+	ok, failed := ended[0], ended[1]
+	if ok.Name() != "registry.RegisterSchema" {
+		t.Errorf("span name = %q, want registry.RegisterSchema", ok.Name())
+	}
+	attrs := map[string]any{}
+	for _, kv := range ok.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs["schema_registry.subject"] != "orders-value" || attrs["schema_registry.schema_id"] != record.ID {
+		t.Errorf("unexpected span attributes: %v", attrs)
+	}
+	if failed.Status().Code != codes.Error {
+		t.Errorf("expected the rejected registration's span to have an error status, got %+v", failed.Status())
+	}
+}
@@ -0,0 +1,205 @@
+// Package retention permanently removes soft-deleted schema versions once
+// they are older than the configured retention window.
+package retention
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// PurgerOption configures a Purger.
+type PurgerOption func(*Purger)
+
+// WithAuditLogger sets the audit logger that receives one event per purge.
+func WithAuditLogger(l *auth.AuditLogger) PurgerOption {
+	return func(p *Purger) {
+		p.audit = l
+	}
+}
+
+// Purger permanently deletes soft-deleted versions whose deletion time is
+// older than the retention window. A subject whose versions have all expired
+// is deleted as a whole so its subject-level config and mode are removed too.
+//
+// Several instances may run purgers against the same storage; a version
+// already purged by another instance is skipped without an audit event.
+type Purger struct {
+	reg       *registry.Registry
+	retention time.Duration
+	logger    *slog.Logger
+	audit     *auth.AuditLogger
+	now       func() time.Time
+}
+
+// NewPurger creates a new soft-delete purger.
+func NewPurger(reg *registry.Registry, retention time.Duration, logger *slog.Logger, opts ...PurgerOption) *Purger {
+	p := &Purger{
+		reg:       reg,
+		retention: retention,
+		logger:    logger,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Start starts a background goroutine that runs a purge pass every interval.
+// The goroutine stops when the stop channel is closed.
+func (p *Purger) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.RunOnce(context.Background())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// RunOnce runs a single purge pass over all contexts and returns the number
+// of versions permanently deleted.
+func (p *Purger) RunOnce(ctx context.Context) int {
+	contexts, err := p.reg.ListContexts(ctx)
+	if err != nil {
+		p.logger.Error("soft-delete purge: failed to list contexts", slog.String("error", err.Error()))
+		return 0
+	}
+
+	cutoff := p.now().Add(-p.retention)
+	purged := 0
+	for _, registryCtx := range contexts {
+		expired, err := p.reg.ListSoftDeletedVersions(ctx, registryCtx, cutoff)
+		if err != nil {
+			p.logger.Error("soft-delete purge: failed to list soft-deleted versions",
+				slog.String("context", registryCtx),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		// Versions are ordered by subject, so each subject is a contiguous run.
+		for start := 0; start < len(expired); {
+			end := start + 1
+			for end < len(expired) && expired[end].Subject == expired[start].Subject {
+				end++
+			}
+			versions := make([]int, 0, end-start)
+			for _, sv := range expired[start:end] {
+				versions = append(versions, sv.Version)
+			}
+			purged += p.purgeSubject(ctx, registryCtx, expired[start].Subject, versions)
+			start = end
+		}
+	}
+
+	if purged > 0 {
+		p.logger.Info("soft-delete purge completed",
+			slog.Int("versions_purged", purged),
+			slog.Duration("retention", p.retention),
+		)
+	}
+	return purged
+}
+
+// purgeSubject permanently deletes the expired versions of one subject and
+// returns how many were deleted.
+func (p *Purger) purgeSubject(ctx context.Context, registryCtx, subject string, versions []int) int {
+	all, err := p.reg.ListVersions(ctx, registryCtx, subject, &storage.ListVersionsParams{Deleted: true})
+	if err != nil {
+		if !isAlreadyPurged(err) {
+			p.logger.Error("soft-delete purge: failed to list versions",
+				slog.String("context", registryCtx),
+				slog.String("subject", subject),
+				slog.String("error", err.Error()),
+			)
+		}
+		return 0
+	}
+
+	if len(all) == len(versions) {
+		deleted, err := p.reg.DeleteSubject(ctx, registryCtx, subject, true)
+		if isAlreadyPurged(err) {
+			return 0
+		}
+		p.logEvent(auth.AuditEventSubjectDeletePermanent, registryCtx, subject, 0, versions, err)
+		if err != nil {
+			return 0
+		}
+		return len(deleted)
+	}
+
+	purged := 0
+	for _, version := range versions {
+		_, err := p.reg.DeleteVersion(ctx, registryCtx, subject, version, true)
+		if isAlreadyPurged(err) {
+			continue
+		}
+		p.logEvent(auth.AuditEventSchemaDeletePermanent, registryCtx, subject, version, nil, err)
+		if err == nil {
+			purged++
+		}
+	}
+	return purged
+}
+
+// logEvent records a purge in the application log and the audit log.
+func (p *Purger) logEvent(eventType auth.AuditEventType, registryCtx, subject string, version int, versions []int, err error) {
+	if err != nil {
+		p.logger.Error("soft-delete purge: failed to delete",
+			slog.String("context", registryCtx),
+			slog.String("subject", subject),
+			slog.String("error", err.Error()),
+		)
+	}
+	if p.audit == nil {
+		return
+	}
+
+	event := &auth.AuditEvent{
+		EventType:  eventType,
+		Timestamp:  time.Now(),
+		Method:     "PURGE",
+		ActorID:    "system",
+		ActorType:  "system",
+		Outcome:    "success",
+		TargetType: "subject",
+		TargetID:   subject,
+		Version:    version,
+		Context:    registryCtx,
+		Metadata: map[string]string{
+			"retention": p.retention.String(),
+		},
+	}
+	if len(versions) > 0 {
+		parts := make([]string, len(versions))
+		for i, v := range versions {
+			parts[i] = strconv.Itoa(v)
+		}
+		event.Metadata["versions"] = strings.Join(parts, ",")
+	}
+	if err != nil {
+		event.Outcome = "failure"
+		event.Error = err.Error()
+	}
+	p.audit.Log(event)
+}
+
+// isAlreadyPurged reports whether err means the target no longer exists,
+// typically because another instance purged it first.
+func isAlreadyPurged(err error) bool {
+	return errors.Is(err, storage.ErrSubjectNotFound) || errors.Is(err, storage.ErrVersionNotFound)
+}
@@ -0,0 +1,117 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/auth"
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
+	"github.com/axonops/axonops-schema-registry/internal/config"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func setupRegistry(t *testing.T) *registry.Registry {
+	t.Helper()
+	parsers := schema.NewRegistry()
+	parsers.Register(avro.NewParser())
+	checker := compatibility.NewChecker()
+	checker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	return registry.New(memory.NewStore(), parsers, checker, "NONE")
+}
+
+func register(t *testing.T, reg *registry.Registry, subject string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		schemaStr := fmt.Sprintf(`{"type":"record","name":"R","fields":[{"name":"f%d","type":"string"}]}`, i)
+		if _, err := reg.RegisterSchema(context.Background(), ".", subject, schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+			t.Fatalf("RegisterSchema %s: %v", subject, err)
+		}
+	}
+}
+
+func TestPurger_RunOnce(t *testing.T) {
+	reg := setupRegistry(t)
+	ctx := context.Background()
+
+	register(t, reg, "orders-value", 3)
+	register(t, reg, "legacy-value", 2)
+	register(t, reg, "users-value", 1)
+
+	if _, err := reg.DeleteVersion(ctx, ".", "orders-value", 1, false); err != nil {
+		t.Fatalf("DeleteVersion: %v", err)
+	}
+	if _, err := reg.DeleteSubject(ctx, ".", "legacy-value", false); err != nil {
+		t.Fatalf("DeleteSubject: %v", err)
+	}
+	if err := reg.SetConfig(ctx, ".", "legacy-value", "FULL", nil); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+
+	var buf bytes.Buffer
+	auditLogger := auth.NewAuditLoggerWithWriter(config.AuditConfig{Enabled: true}, &buf)
+	p := NewPurger(reg, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)), WithAuditLogger(auditLogger))
+
+	// Nothing has been soft-deleted for longer than the retention yet.
+	if n := p.RunOnce(ctx); n != 0 {
+		t.Fatalf("expected nothing purged before the retention elapses, purged %d", n)
+	}
+
+	p.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if n := p.RunOnce(ctx); n != 3 {
+		t.Fatalf("expected 3 versions purged, got %d", n)
+	}
+	if err := auditLogger.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	versions, err := reg.ListVersions(ctx, ".", "orders-value", &storage.ListVersionsParams{Deleted: true})
+	if err != nil || fmt.Sprint(versions) != "[2 3]" {
+		t.Errorf("orders-value versions = %v, %v; want [2 3]", versions, err)
+	}
+	if _, err := reg.ListVersions(ctx, ".", "legacy-value", &storage.ListVersionsParams{Deleted: true}); err == nil {
+		t.Error("expected legacy-value to be permanently deleted")
+	}
+	if _, err := reg.GetSubjectConfig(ctx, ".", "legacy-value"); err == nil {
+		t.Error("expected legacy-value subject config to be removed with the subject")
+	}
+
+	var events []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("unmarshal audit event %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 audit events, got %d: %s", len(events), buf.String())
+	}
+	if events[0]["event_type"] != "subject_delete_permanent" || events[0]["target_id"] != "legacy-value" {
+		t.Errorf("unexpected first event: %v", events[0])
+	}
+	if events[1]["event_type"] != "schema_delete_permanent" || events[1]["target_id"] != "orders-value" || events[1]["version"] != float64(1) {
+		t.Errorf("unexpected second event: %v", events[1])
+	}
+	for _, e := range events {
+		if e["actor_type"] != "system" || e["outcome"] != "success" {
+			t.Errorf("expected successful system event, got %v", e)
+		}
+	}
+
+	// A second pass finds nothing left to purge.
+	if n := p.RunOnce(ctx); n != 0 {
+		t.Errorf("expected nothing purged on the second pass, purged %d", n)
+	}
+}
@@ -0,0 +1,127 @@
+// Package revalidation re-checks stored schema versions with the current
+// compatibility rules. After an upgrade changes checker logic, it reports
+// the adjacent version pairs that would now be rejected, so operators can
+// review them before the new rules matter for a registration. It never
+// modifies or rejects anything.
+package revalidation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+)
+
+// JobType is the async job type that runs a re-validation.
+const JobType = "compatibility_revalidation"
+
+// DefaultPairsPerSecond is how many version pairs are checked per second
+// when Params.PairsPerSecond is not set.
+const DefaultPairsPerSecond = 20
+
+// Params are the parameters of a re-validation job. The job covers the
+// registry context it was submitted for.
+type Params struct {
+	// SubjectPrefix limits the job to subjects starting with it.
+	SubjectPrefix string `json:"subject_prefix,omitempty"`
+	// PairsPerSecond limits how fast version pairs are checked, so a large
+	// registry is re-validated without competing with client traffic.
+	PairsPerSecond int `json:"pairs_per_second,omitempty"`
+}
+
+// Result is the outcome of a re-validation job.
+type Result struct {
+	SubjectsChecked int       `json:"subjects_checked"`
+	PairsChecked    int       `json:"pairs_checked"`
+	Incompatible    []Finding `json:"incompatible"`
+}
+
+// Finding is a pair of adjacent versions that the current rules reject.
+type Finding struct {
+	Subject            string   `json:"subject"`
+	CompatibilityLevel string   `json:"compatibility_level"`
+	PreviousVersion    int      `json:"previous_version"`
+	Version            int      `json:"version"`
+	Messages           []string `json:"messages"`
+}
+
+// Register registers the re-validation job type with the job manager.
+func Register(m *jobs.Manager, reg *registry.Registry) {
+	m.Register(JobType, func(ctx context.Context, job *jobs.Job) (any, error) {
+		return run(ctx, job, reg)
+	})
+}
+
+func run(ctx context.Context, job *jobs.Job, reg *registry.Registry) (*Result, error) {
+	var p Params
+	if err := job.DecodeParams(&p); err != nil {
+		return nil, err
+	}
+	if p.PairsPerSecond <= 0 {
+		p.PairsPerSecond = DefaultPairsPerSecond
+	}
+
+	registryCtx := job.RegistryContext()
+	subjects, err := reg.ListSubjects(ctx, registryCtx, false)
+	if err != nil {
+		return nil, err
+	}
+	var selected []string
+	for _, s := range subjects {
+		if strings.HasPrefix(s, p.SubjectPrefix) {
+			selected = append(selected, s)
+		}
+	}
+	sort.Strings(selected)
+
+	t := &throttle{interval: time.Second / time.Duration(p.PairsPerSecond)}
+	result := &Result{Incompatible: []Finding{}}
+	total := int64(len(selected))
+	job.SetProgress(0, total, "")
+	for i, subject := range selected {
+		sr, err := reg.RevalidateSubject(ctx, registryCtx, subject, t.wait)
+		if err != nil {
+			return nil, err
+		}
+		result.SubjectsChecked++
+		result.PairsChecked += sr.PairsChecked
+		for _, pair := range sr.Incompatible {
+			result.Incompatible = append(result.Incompatible, Finding{
+				Subject:            subject,
+				CompatibilityLevel: sr.CompatibilityLevel,
+				PreviousVersion:    pair.PreviousVersion,
+				Version:            pair.Version,
+				Messages:           pair.Messages,
+			})
+		}
+		job.SetProgress(int64(i+1), total, fmt.Sprintf("%d version pairs checked, %d incompatible",
+			result.PairsChecked, len(result.Incompatible)))
+	}
+	return result, nil
+}
+
+// throttle spaces calls to wait at least interval apart.
+type throttle struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func (t *throttle) wait(ctx context.Context) error {
+	now := time.Now()
+	if delay := t.next.Sub(now); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		now = t.next
+	}
+	t.next = now.Add(t.interval)
+	return nil
+}
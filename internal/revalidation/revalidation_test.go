@@ -0,0 +1,111 @@
+package revalidation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/compatibility"
+	avrocompat "github.com/axonops/axonops-schema-registry/internal/compatibility/avro"
+	"github.com/axonops/axonops-schema-registry/internal/jobs"
+	"github.com/axonops/axonops-schema-registry/internal/registry"
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+	"github.com/axonops/axonops-schema-registry/internal/schema/avro"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func TestRevalidationJob(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+	store.SetGlobalConfig(ctx, ".", &storage.ConfigRecord{CompatibilityLevel: "NONE"})
+
+	parsers := schema.NewRegistry()
+	parsers.Register(avro.NewParser())
+	checker := compatibility.NewChecker()
+	checker.Register(storage.SchemaTypeAvro, avrocompat.NewChecker())
+	reg := registry.New(store, parsers, checker, "NONE")
+
+	// Register versions that BACKWARD would have rejected, then tighten
+	// the rules for the orders subjects only.
+	for _, subject := range []string{"orders-key", "orders-value", "users-value"} {
+		for _, schemaStr := range []string{
+			`{"type":"record","name":"R","fields":[{"name":"a","type":"int"}]}`,
+			`{"type":"record","name":"R","fields":[{"name":"b","type":"int"}]}`,
+		} {
+			if _, err := reg.RegisterSchema(ctx, ".", subject, schemaStr, storage.SchemaTypeAvro, nil); err != nil {
+				t.Fatalf("RegisterSchema: %v", err)
+			}
+		}
+	}
+	for _, subject := range []string{"orders-value", "users-value"} {
+		if err := reg.SetConfig(ctx, ".", subject, "BACKWARD", nil); err != nil {
+			t.Fatalf("SetConfig: %v", err)
+		}
+	}
+
+	m := jobs.NewManager(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	Register(m, reg)
+	stop := make(chan struct{})
+	defer close(stop)
+	m.Start(stop)
+
+	rec, err := m.Submit(ctx, JobType, ".", Params{SubjectPrefix: "orders-", PairsPerSecond: 1000}, "")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !rec.Finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish: %+v", rec)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if rec, err = m.Get(ctx, rec.ID); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if rec.State != storage.JobStateSucceeded {
+		t.Fatalf("job state %s: %s", rec.State, rec.Error)
+	}
+	if rec.Done != 2 || rec.Total != 2 {
+		t.Errorf("progress = %d/%d, want 2/2", rec.Done, rec.Total)
+	}
+
+	var result Result
+	if err := json.Unmarshal([]byte(rec.Result), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.SubjectsChecked != 2 || result.PairsChecked != 1 {
+		t.Errorf("checked %d subjects and %d pairs, want 2 and 1", result.SubjectsChecked, result.PairsChecked)
+	}
+	if len(result.Incompatible) != 1 {
+		t.Fatalf("incompatible = %+v, want one finding", result.Incompatible)
+	}
+	f := result.Incompatible[0]
+	if f.Subject != "orders-value" || f.CompatibilityLevel != "BACKWARD" || f.PreviousVersion != 1 || f.Version != 2 {
+		t.Errorf("unexpected finding %+v", f)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	th := &throttle{interval: 20 * time.Millisecond}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := th.wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("three waits took %v, want at least 40ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	th.next = time.Now().Add(time.Hour)
+	if err := th.wait(ctx); err != context.Canceled {
+		t.Errorf("wait on cancelled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,145 @@
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hamba/avro/v2"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+)
+
+// ToJSONSchema returns a JSON Schema (draft 2020-12) describing the values of
+// the Avro schema as JSON documents. Records become objects whose fields
+// without a default are required, enums become string enums, maps become
+// objects and unions become anyOf of their branches; union values are
+// described unwrapped, not in the {"type": value} form of the Avro JSON
+// encoding. Named types are emitted once under $defs, except the top-level
+// one, which is the document itself and is referenced as "#".
+func (s *ParsedSchema) ToJSONSchema() (string, error) {
+	c := &jsonSchemaConverter{defs: make(map[string]any), seen: make(map[string]bool)}
+	var doc map[string]any
+	if ns, ok := s.rawSchema.(avro.NamedSchema); ok {
+		c.root = ns.FullName()
+		doc = c.named(ns)
+	} else {
+		doc = c.convert(s.rawSchema)
+	}
+	doc["$schema"] = schema.JSONSchemaDraft
+	if len(c.defs) > 0 {
+		doc["$defs"] = c.defs
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON Schema: %w", err)
+	}
+	return string(out), nil
+}
+
+// jsonSchemaConverter converts an Avro schema to JSON Schema.
+type jsonSchemaConverter struct {
+	root string          // full name of the top-level named type, if any
+	defs map[string]any  // named types, by full name
+	seen map[string]bool // named types in defs, or being converted into it
+}
+
+// convert returns the JSON Schema of an Avro schema. Named types are
+// referenced.
+func (c *jsonSchemaConverter) convert(s avro.Schema) map[string]any {
+	switch t := s.(type) {
+	case *avro.RefSchema:
+		return c.ref(t.Schema())
+	case avro.NamedSchema:
+		return c.ref(t)
+	case *avro.ArraySchema:
+		return map[string]any{"type": "array", "items": c.convert(t.Items())}
+	case *avro.MapSchema:
+		return map[string]any{"type": "object", "additionalProperties": c.convert(t.Values())}
+	case *avro.UnionSchema:
+		branches := make([]any, 0, len(t.Types()))
+		for _, branch := range t.Types() {
+			branches = append(branches, c.convert(branch))
+		}
+		return map[string]any{"anyOf": branches}
+	case *avro.NullSchema:
+		return map[string]any{"type": "null"}
+	case *avro.PrimitiveSchema:
+		return primitiveJSONSchema(t)
+	}
+	return map[string]any{}
+}
+
+// ref returns a reference to a named type, converting it into defs the
+// first time it is referenced.
+func (c *jsonSchemaConverter) ref(ns avro.NamedSchema) map[string]any {
+	name := ns.FullName()
+	if name == c.root {
+		return map[string]any{"$ref": "#"}
+	}
+	if !c.seen[name] {
+		c.seen[name] = true
+		c.defs[name] = c.named(ns)
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// named returns the JSON Schema of a record, enum or fixed schema.
+func (c *jsonSchemaConverter) named(s avro.NamedSchema) map[string]any {
+	switch t := s.(type) {
+	case *avro.RecordSchema:
+		out := map[string]any{"type": "object", "title": t.FullName()}
+		if t.Doc() != "" {
+			out["description"] = t.Doc()
+		}
+		properties := make(map[string]any, len(t.Fields()))
+		required := []string{}
+		for _, f := range t.Fields() {
+			prop := c.convert(f.Type())
+			if f.Doc() != "" {
+				prop["description"] = f.Doc()
+			}
+			if f.HasDefault() {
+				prop["default"] = f.Default()
+			} else {
+				required = append(required, f.Name())
+			}
+			properties[f.Name()] = prop
+		}
+		out["properties"] = properties
+		if len(required) > 0 {
+			out["required"] = required
+		}
+		out["additionalProperties"] = false
+		return out
+	case *avro.EnumSchema:
+		out := map[string]any{"type": "string", "title": t.FullName(), "enum": t.Symbols()}
+		if t.Doc() != "" {
+			out["description"] = t.Doc()
+		}
+		return out
+	case *avro.FixedSchema:
+		return map[string]any{"type": "string", "title": t.FullName()}
+	}
+	return map[string]any{}
+}
+
+// primitiveJSONSchema returns the JSON Schema of an Avro primitive. Logical
+// types are described by their underlying type, except uuid strings.
+func primitiveJSONSchema(s *avro.PrimitiveSchema) map[string]any {
+	switch s.Type() {
+	case avro.Boolean:
+		return map[string]any{"type": "boolean"}
+	case avro.Int, avro.Long:
+		return map[string]any{"type": "integer"}
+	case avro.Float, avro.Double:
+		return map[string]any{"type": "number"}
+	case avro.String:
+		if s.Logical() != nil && s.Logical().Type() == avro.UUID {
+			return map[string]any{"type": "string", "format": "uuid"}
+		}
+		return map[string]any{"type": "string"}
+	case avro.Bytes:
+		return map[string]any{"type": "string"}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,71 @@
+package avro
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+)
+
+func TestParsedSchema_ToJSONSchema(t *testing.T) {
+	parsed, err := NewParser().Parse(`{
+		"type": "record", "name": "Node", "namespace": "com.example", "doc": "A tree node",
+		"fields": [
+			{"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+			{"name": "color", "type": {"type": "enum", "name": "Color", "symbols": ["RED", "GREEN"]}},
+			{"name": "children", "type": {"type": "array", "items": "Node"}, "default": []},
+			{"name": "label", "type": ["null", "string"], "default": null, "doc": "Display label"},
+			{"name": "weights", "type": {"type": "map", "values": "double"}}
+		]
+	}`, nil)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	out, err := parsed.(schema.JSONSchemaConverter).ToJSONSchema()
+	if err != nil {
+		t.Fatalf("ToJSONSchema: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("expected a JSON document, got %q: %v", out, err)
+	}
+
+	want := map[string]any{
+		"$schema":     schema.JSONSchemaDraft,
+		"type":        "object",
+		"title":       "com.example.Node",
+		"description": "A tree node",
+		"properties": map[string]any{
+			"id":       map[string]any{"type": "string", "format": "uuid"},
+			"color":    map[string]any{"$ref": "#/$defs/com.example.Color"},
+			"children": map[string]any{"type": "array", "items": map[string]any{"$ref": "#"}, "default": []any{}},
+			"label": map[string]any{
+				"anyOf":       []any{map[string]any{"type": "null"}, map[string]any{"type": "string"}},
+				"default":     nil,
+				"description": "Display label",
+			},
+			"weights": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "number"}},
+		},
+		"required":             []any{"id", "color", "weights"},
+		"additionalProperties": false,
+		"$defs": map[string]any{
+			"com.example.Color": map[string]any{"type": "string", "title": "com.example.Color", "enum": []any{"RED", "GREEN"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected JSON Schema:\n got %s", out)
+	}
+}
+
+func TestParsedSchema_FormattedString_Pretty(t *testing.T) {
+	parsed, err := NewParser().Parse(`{"type":"record","name":"A","fields":[{"name":"id","type":"int"}]}`, nil)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := "{\n  \"name\": \"A\",\n  \"type\": \"record\",\n  \"fields\": [\n    {\n      \"name\": \"id\",\n      \"type\": \"int\"\n    }\n  ]\n}"
+	if got := parsed.FormattedString("pretty"); got != want {
+		t.Errorf("FormattedString(pretty) = %q, want %q", got, want)
+	}
+}
@@ -2,6 +2,7 @@
 package avro
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/pkg/crc64"
 
 	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
@@ -101,6 +103,13 @@ func (s *ParsedSchema) RawSchema() interface{} {
 
 // Normalize returns a normalized copy of this schema using canonical form.
 func (s *ParsedSchema) Normalize() schema.ParsedSchema {
+	return s.NormalizeWithProfile(schema.DefaultNormalizationProfile)
+}
+
+// NormalizeWithProfile returns a normalized copy of this schema. No profile
+// option applies to Avro: field order is part of the binary encoding, so it
+// is always preserved.
+func (s *ParsedSchema) NormalizeWithProfile(_ schema.NormalizationProfile) schema.ParsedSchema {
 	return &ParsedSchema{
 		schemaType:  s.schemaType,
 		canonical:   s.canonical,
@@ -122,8 +131,26 @@ func (s *ParsedSchema) HasTopLevelField(field string) bool {
 	return false
 }
 
+// ParsingFingerprint64 returns the CRC-64-AVRO fingerprint of the schema's
+// Parsing Canonical Form, with named types from references inlined.
+func (s *ParsedSchema) ParsingFingerprint64() uint64 {
+	h := crc64.New()
+	_, _ = h.Write([]byte(s.rawSchema.String()))
+	return h.Sum64()
+}
+
+// RecordName returns the full name of a named Avro schema (record, enum or
+// fixed). Returns "" for unnamed schemas such as primitives and unions.
+func (s *ParsedSchema) RecordName() string {
+	if ns, ok := s.rawSchema.(avro.NamedSchema); ok {
+		return ns.FullName()
+	}
+	return ""
+}
+
 // FormattedString returns the schema in the requested format.
-// Supported formats: "resolved" (inlines all references), "default" (canonical).
+// Supported formats: "resolved" (inlines all references), "pretty" (canonical,
+// indented), "default" (canonical).
 func (s *ParsedSchema) FormattedString(format string) string {
 	switch strings.ToLower(strings.TrimSpace(format)) {
 	case "resolved":
@@ -131,6 +158,12 @@ func (s *ParsedSchema) FormattedString(format string) string {
 			return s.rawSchema.String()
 		}
 		return s.canonical
+	case "pretty":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(s.canonical), "", "  "); err != nil {
+			return s.canonical
+		}
+		return buf.String()
 	default:
 		return s.canonical
 	}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
@@ -1230,3 +1231,54 @@ func TestCanonicalize_MixedExplicitAndInheritedNamespaces(t *testing.T) {
 		t.Errorf("LineItem should inherit com.example from Order, got: %s", canonical)
 	}
 }
+
+func TestParsedSchema_RecordName(t *testing.T) {
+	parser := NewParser()
+
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"record", `{"type":"record","name":"User","namespace":"com.example","fields":[{"name":"id","type":"long"}]}`, "com.example.User"},
+		{"enum", `{"type":"enum","name":"Color","symbols":["RED"]}`, "Color"},
+		{"primitive", `"string"`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parser.Parse(tt.schema, nil)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got := parsed.RecordName(); got != tt.want {
+				t.Errorf("RecordName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsedSchema_ParsingFingerprint64(t *testing.T) {
+	parser := NewParser()
+
+	tests := []struct {
+		schema string
+		want   uint64
+	}{
+		// Values from the Avro specification's test suite.
+		{`"null"`, 0x63dd24e7cc258f8a},
+		{`"int"`, 0x7275d51a3f395c8f},
+		// Attributes outside the Parsing Canonical Form do not change it.
+		{`{"type": "record", "name": "User", "doc": "A user", "fields": [{"name": "id", "type": "long", "default": 0}]}`, 0xb998935be7041a7e},
+	}
+	for _, tt := range tests {
+		parsed, err := parser.Parse(tt.schema, nil)
+		if err != nil {
+			t.Fatalf("Parse(%s) failed: %v", tt.schema, err)
+		}
+		got := parsed.(schema.ParsingFingerprinter).ParsingFingerprint64()
+		if got != tt.want {
+			t.Errorf("ParsingFingerprint64(%s) = %#x, want %#x", tt.schema, got, tt.want)
+		}
+	}
+}
@@ -131,13 +131,24 @@ func (p *ParsedJSONSchema) RawSchema() interface{} {
 
 // Normalize returns a normalized copy of this schema with deterministic key ordering.
 func (p *ParsedJSONSchema) Normalize() schema.ParsedSchema {
-	return &ParsedJSONSchema{
-		raw:             p.CanonicalString(),
+	return p.NormalizeWithProfile(schema.DefaultNormalizationProfile)
+}
+
+// NormalizeWithProfile returns a normalized copy of this schema with
+// deterministic key ordering. When the profile enables JSONStripAnnotations,
+// annotation keywords are removed from every subschema.
+func (p *ParsedJSONSchema) NormalizeWithProfile(profile schema.NormalizationProfile) schema.ParsedSchema {
+	normalized := &ParsedJSONSchema{
 		schemaMap:       p.schemaMap,
 		compiled:        p.compiled,
 		references:      p.references,
 		isBooleanSchema: p.isBooleanSchema,
 	}
+	if profile.JSONStripAnnotations && !p.isBooleanSchema {
+		normalized.schemaMap, _ = stripAnnotations(p.schemaMap).(map[string]interface{})
+	}
+	normalized.raw = normalized.CanonicalString()
+	return normalized
 }
 
 // HasTopLevelField reports whether the JSON Schema "properties" object
@@ -151,10 +162,26 @@ func (p *ParsedJSONSchema) HasTopLevelField(field string) bool {
 	return exists
 }
 
+// RecordName returns the JSON Schema "title" keyword, which serializers use
+// as the record name. Returns "" when no title is set.
+func (p *ParsedJSONSchema) RecordName() string {
+	title, _ := p.schemaMap["title"].(string)
+	return title
+}
+
 // FormattedString returns the schema in the requested format.
-// JSON Schema does not support special format values; always returns canonical string.
+// Supported formats: "resolved" (inlines $ref targets, including those in
+// referenced schemas), "default" (canonical).
 func (p *ParsedJSONSchema) FormattedString(format string) string {
-	return p.CanonicalString()
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "resolved":
+		if p.isBooleanSchema {
+			return p.CanonicalString()
+		}
+		return canonicalize(p.resolved())
+	default:
+		return p.CanonicalString()
+	}
 }
 
 // Raw returns the original schema string.
@@ -227,6 +254,83 @@ func canonicalizeValue(v interface{}) (string, error) {
 	}
 }
 
+// annotationKeywords are JSON Schema keywords that carry documentation only
+// and never affect validation.
+var annotationKeywords = map[string]bool{
+	"title":       true,
+	"description": true,
+	"examples":    true,
+	"$comment":    true,
+}
+
+// schemaMapKeywords are keywords whose value is an object of subschemas.
+var schemaMapKeywords = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"definitions":       true,
+	"$defs":             true,
+	"dependentSchemas":  true,
+	"dependencies":      true,
+}
+
+// schemaKeywords are keywords whose value is a subschema or an array of subschemas.
+var schemaKeywords = map[string]bool{
+	"items":                 true,
+	"additionalItems":       true,
+	"additionalProperties":  true,
+	"contains":              true,
+	"propertyNames":         true,
+	"not":                   true,
+	"if":                    true,
+	"then":                  true,
+	"else":                  true,
+	"allOf":                 true,
+	"anyOf":                 true,
+	"oneOf":                 true,
+	"prefixItems":           true,
+	"unevaluatedItems":      true,
+	"unevaluatedProperties": true,
+}
+
+// stripAnnotations returns a copy of a (sub)schema with annotation keywords
+// removed. Only schema positions are visited, so a property that happens to
+// be named "description" is kept.
+func stripAnnotations(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = stripAnnotations(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			switch {
+			case annotationKeywords[k]:
+				continue
+			case schemaMapKeywords[k]:
+				if m, ok := child.(map[string]interface{}); ok {
+					sub := make(map[string]interface{}, len(m))
+					for name, s := range m {
+						sub[name] = stripAnnotations(s)
+					}
+					out[k] = sub
+					continue
+				}
+				out[k] = child
+			case schemaKeywords[k]:
+				out[k] = stripAnnotations(child)
+			default:
+				out[k] = child
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
+
 // GetSchemaType extracts the type from a JSON Schema.
 func GetSchemaType(schemaMap map[string]interface{}) string {
 	if t, ok := schemaMap["type"].(string); ok {
@@ -1,9 +1,11 @@
 package jsonschema
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
+	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
@@ -1013,3 +1015,168 @@ func TestParser_Parse_UnusedReferencesGraceful(t *testing.T) {
 		t.Error("Expected non-empty fingerprint")
 	}
 }
+
+func TestParsedSchema_NormalizeWithProfile_StripAnnotations(t *testing.T) {
+	parser := NewParser()
+
+	schemaStr := `{
+		"type": "object",
+		"title": "User",
+		"description": "A user",
+		"properties": {
+			"id": {"type": "integer", "description": "The ID", "examples": [1]},
+			"description": {"type": "string", "$comment": "free text"}
+		}
+	}`
+	plain := `{"type":"object","properties":{"id":{"type":"integer"},"description":{"type":"string"}}}`
+
+	parsed, err := parser.Parse(schemaStr, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	parsedPlain, err := parser.Parse(plain, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if parsed.Normalize().Fingerprint() == parsedPlain.Normalize().Fingerprint() {
+		t.Error("Expected default profile to keep annotations")
+	}
+
+	strip := schema.NormalizationProfile{Name: "strip", JSONStripAnnotations: true}
+	normalized := parsed.NormalizeWithProfile(strip)
+	canonical := normalized.CanonicalString()
+	for _, keyword := range []string{`"title"`, `"examples"`, `"$comment"`, `"A user"`, `"The ID"`} {
+		if strings.Contains(canonical, keyword) {
+			t.Errorf("Expected %s to be stripped, got %s", keyword, canonical)
+		}
+	}
+	// A property named "description" is data, not an annotation.
+	if !strings.Contains(canonical, `"description":{"type":"string"}`) {
+		t.Errorf("Expected description property to be kept, got %s", canonical)
+	}
+	if normalized.Fingerprint() != parsedPlain.NormalizeWithProfile(strip).Fingerprint() {
+		t.Error("Expected same fingerprint once annotations are stripped")
+	}
+}
+
+func TestParsedJSONSchema_RecordName(t *testing.T) {
+	parser := NewParser()
+
+	parsed, err := parser.Parse(`{"title":"com.example.Order","type":"object"}`, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	if got := parsed.RecordName(); got != "com.example.Order" {
+		t.Errorf("RecordName() = %q, want com.example.Order", got)
+	}
+
+	untitled, err := parser.Parse(`{"type":"object"}`, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	if got := untitled.RecordName(); got != "" {
+		t.Errorf("RecordName() = %q, want empty", got)
+	}
+}
+
+func TestParsedJSONSchema_FormattedString_Resolved(t *testing.T) {
+	parser := NewParser()
+	address := `{
+		"$id": "https://example.com/address.json",
+		"type": "object",
+		"properties": {"street": {"$ref": "#/definitions/line"}},
+		"definitions": {"line": {"type": "string", "maxLength": 80}}
+	}`
+	order := `{
+		"$id": "https://example.com/order.json",
+		"type": "object",
+		"properties": {
+			"shipping": {"$ref": "address.json"},
+			"billing": {"$ref": "address.json", "description": "Billing address"},
+			"parent": {"$ref": "#"},
+			"note": {"$ref": "#/definitions/note"}
+		},
+		"definitions": {"note": {"type": "string"}}
+	}`
+	parsed, err := parser.Parse(order, []storage.Reference{
+		{Name: "https://example.com/address.json", Subject: "address-value", Version: 1, Schema: address},
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var resolved map[string]interface{}
+	if err := json.Unmarshal([]byte(parsed.FormattedString("resolved")), &resolved); err != nil {
+		t.Fatalf("resolved schema is not JSON: %v", err)
+	}
+	props := resolved["properties"].(map[string]interface{})
+
+	shipping := props["shipping"].(map[string]interface{})
+	street := shipping["properties"].(map[string]interface{})["street"].(map[string]interface{})
+	if street["maxLength"] != float64(80) {
+		t.Errorf("expected the referenced schema and its own $ref to be inlined, got %v", shipping)
+	}
+	billing := props["billing"].(map[string]interface{})
+	if _, ok := billing["$ref"]; ok || billing["description"] != "Billing address" || len(billing["allOf"].([]interface{})) != 1 {
+		t.Errorf("expected a $ref with siblings to become an allOf entry, got %v", billing)
+	}
+	if props["note"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("expected the local $ref to be inlined, got %v", props["note"])
+	}
+	if props["parent"].(map[string]interface{})["$ref"] != "#" {
+		t.Errorf("expected the recursive $ref to be kept, got %v", props["parent"])
+	}
+
+	if parsed.FormattedString("") != parsed.CanonicalString() {
+		t.Error("expected the default format to be the canonical string")
+	}
+}
+
+func TestParsedJSONSchema_ValidatePayload(t *testing.T) {
+	address := storage.Reference{
+		Name:   "address.json",
+		Schema: `{"type":"object","properties":{"zip":{"type":"string","pattern":"^[0-9]{5}$"}},"required":["zip"]}`,
+	}
+	parsed, err := NewParser().Parse(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer", "minimum": 0},
+			"address": {"$ref": "address.json"}
+		},
+		"required": ["name"]
+	}`, []storage.Reference{address})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	validator := parsed.(schema.PayloadValidator)
+
+	decode := func(s string) interface{} {
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("decode %s: %v", s, err)
+		}
+		return v
+	}
+
+	if problems := validator.ValidatePayload(decode(`{"name":"a","age":3,"address":{"zip":"12345"}}`)); problems != nil {
+		t.Errorf("expected a valid document, got %+v", problems)
+	}
+
+	problems := validator.ValidatePayload(decode(`{"age":-1,"address":{"zip":"abc"}}`))
+	locations := map[string]bool{}
+	for _, p := range problems {
+		if p.Message == "" || p.KeywordLocation == "" {
+			t.Errorf("expected a message and keyword location, got %+v", p)
+		}
+		locations[p.InstanceLocation] = true
+	}
+	for _, want := range []string{"", "/age", "/address/zip"} {
+		if !locations[want] {
+			t.Errorf("expected a problem at %q, got %+v", want, problems)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// rootDocument names the schema being resolved among the documents a
+// resolver knows.
+const rootDocument = "schema.json"
+
+// resolver inlines $ref targets, from the schema itself and from its
+// referenced schemas, to build a self-contained schema.
+type resolver struct {
+	docs  map[string]interface{} // parsed documents by name
+	bases map[string]*url.URL    // base URI of each document
+	// inlining holds the targets being inlined, "document#pointer", so a
+	// recursive schema keeps its $ref instead of expanding forever.
+	inlining map[string]bool
+}
+
+// resolved returns the schema with every $ref it can resolve replaced by
+// its target. A $ref with sibling keywords becomes an allOf entry next to
+// them. A $ref to a target already being inlined, as in recursive schemas,
+// or to a target that cannot be found, is left in place.
+func (p *ParsedJSONSchema) resolved() interface{} {
+	r := &resolver{
+		docs:     map[string]interface{}{rootDocument: p.schemaMap},
+		bases:    map[string]*url.URL{},
+		inlining: map[string]bool{},
+	}
+	for _, ref := range p.references {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(ref.Schema), &doc); err == nil {
+			r.docs[ref.Name] = doc
+		}
+	}
+	for name, doc := range r.docs {
+		r.bases[name] = documentBase(name, doc)
+	}
+	r.inlining[rootDocument+"#"] = true
+	return r.value(p.schemaMap, rootDocument)
+}
+
+// documentBase returns the base URI of a document: its $id, resolved
+// against its name, or its name.
+func documentBase(name string, doc interface{}) *url.URL {
+	base, err := url.Parse(name)
+	if err != nil {
+		base = &url.URL{}
+	}
+	if m, ok := doc.(map[string]interface{}); ok {
+		if id, ok := m["$id"].(string); ok {
+			if u, err := url.Parse(id); err == nil {
+				base = base.ResolveReference(u)
+			}
+		}
+	}
+	base.Fragment = ""
+	return base
+}
+
+// value returns v, from document doc, with its $refs inlined.
+func (r *resolver) value(v interface{}, doc string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ref, isRef := val["$ref"].(string)
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			if isRef && k == "$ref" {
+				continue
+			}
+			out[k] = r.value(child, doc)
+		}
+		if !isRef {
+			return out
+		}
+		target, ok := r.inline(ref, doc)
+		if !ok {
+			out["$ref"] = ref
+			return out
+		}
+		if len(out) == 0 {
+			return target
+		}
+		allOf, _ := out["allOf"].([]interface{})
+		out["allOf"] = append(allOf, target)
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = r.value(child, doc)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// inline resolves ref, found in document doc, and returns its target with
+// the target's own $refs inlined.
+func (r *resolver) inline(ref, doc string) (interface{}, bool) {
+	docPart, pointer, _ := strings.Cut(ref, "#")
+	target := doc
+	if docPart != "" {
+		var ok bool
+		if target, ok = r.document(docPart, doc); !ok {
+			return nil, false
+		}
+	}
+	key := target + "#" + pointer
+	if r.inlining[key] {
+		return nil, false
+	}
+	v, ok := lookupPointer(r.docs[target], pointer)
+	if !ok {
+		return nil, false
+	}
+	r.inlining[key] = true
+	defer delete(r.inlining, key)
+	return r.value(v, target), true
+}
+
+// document finds the document a $ref's URI part names, either by a
+// reference name or $id as written, or by resolving it against the base of
+// the document containing the $ref.
+func (r *resolver) document(uri, from string) (string, bool) {
+	if _, ok := r.docs[uri]; ok {
+		return uri, true
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", false
+	}
+	want := r.bases[from].ResolveReference(u).String()
+	for name, base := range r.bases {
+		if base.String() == want {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// lookupPointer returns the value a JSON pointer fragment selects in doc.
+// Anchors ("#name") are not supported.
+func lookupPointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+	v := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := v.(type) {
+		case map[string]interface{}:
+			child, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
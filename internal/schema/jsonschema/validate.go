@@ -0,0 +1,41 @@
+package jsonschema
+
+import (
+	"errors"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+)
+
+// ValidatePayload validates doc, decoded from JSON, against the schema and
+// its references. Each problem reported is a keyword the document failed;
+// the keywords that only group others, such as allOf, are left out.
+func (p *ParsedJSONSchema) ValidatePayload(doc interface{}) []schema.PayloadError {
+	err := p.compiled.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []schema.PayloadError{{Message: err.Error()}}
+	}
+	var problems []schema.PayloadError
+	collectPayloadErrors(ve, &problems)
+	return problems
+}
+
+// collectPayloadErrors appends the leaves of a validation error tree.
+func collectPayloadErrors(ve *jsonschema.ValidationError, problems *[]schema.PayloadError) {
+	if len(ve.Causes) == 0 {
+		*problems = append(*problems, schema.PayloadError{
+			InstanceLocation: ve.InstanceLocation,
+			KeywordLocation:  ve.KeywordLocation,
+			Message:          ve.Message,
+		})
+		return
+	}
+	for _, cause := range ve.Causes {
+		collectPayloadErrors(cause, problems)
+	}
+}
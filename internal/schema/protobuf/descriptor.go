@@ -0,0 +1,683 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// sourceFromDescriptor turns a schema given as a base64-encoded
+// FileDescriptorSet or FileDescriptorProto, as protoc --descriptor_set_out
+// and buf build write them, into .proto source. The schema of a set is its
+// last file, which protoc writes after the files it imports; the other files
+// are only used to interpret custom options, so imports must still be
+// provided as references. It returns false when the schema is not a
+// descriptor, such as .proto source.
+func sourceFromDescriptor(schemaStr string) (string, bool, error) {
+	set, ok := decodeDescriptor(schemaStr)
+	if !ok {
+		return "", false, nil
+	}
+	source, err := printFileDescriptor(set)
+	if err != nil {
+		return "", true, fmt.Errorf("failed to convert descriptor to .proto source: %w", err)
+	}
+	return source, true, nil
+}
+
+// decodeDescriptor decodes a base64-encoded FileDescriptorSet or
+// FileDescriptorProto. A single FileDescriptorProto is returned as a set of
+// one file.
+func decodeDescriptor(schemaStr string) (*descriptorpb.FileDescriptorSet, bool) {
+	encoded := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, schemaStr)
+	if encoded == "" {
+		return nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+			return nil, false
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err == nil && validDescriptorSet(set) {
+		return set, true
+	}
+	file := &descriptorpb.FileDescriptorProto{}
+	if err := proto.Unmarshal(data, file); err == nil && validDescriptorSet(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}}) {
+		return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}}, true
+	}
+	return nil, false
+}
+
+// validDescriptorSet reports whether a decoded message looks like a
+// FileDescriptorSet rather than other bytes that happen to decode as one:
+// every file is named *.proto and nothing is left unparsed.
+func validDescriptorSet(set *descriptorpb.FileDescriptorSet) bool {
+	if len(set.GetFile()) == 0 || len(set.ProtoReflect().GetUnknown()) > 0 {
+		return false
+	}
+	for _, f := range set.GetFile() {
+		if !strings.HasSuffix(f.GetName(), ".proto") {
+			return false
+		}
+	}
+	return true
+}
+
+// descriptorPrinter writes one file of a FileDescriptorSet as .proto source.
+type descriptorPrinter struct {
+	sb      strings.Builder
+	file    *descriptorpb.FileDescriptorProto
+	types   *dynamicpb.Types // Extensions defined in the set, for custom options
+	options []string         // Options of the declaration being printed
+}
+
+// printFileDescriptor returns the last file of a set as .proto source.
+func printFileDescriptor(set *descriptorpb.FileDescriptorSet) (string, error) {
+	file := set.GetFile()[len(set.GetFile())-1]
+	files, err := protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(set)
+	if err != nil {
+		files = new(protoregistry.Files)
+	}
+	p := &descriptorPrinter{file: file, types: dynamicpb.NewTypes(files)}
+	if err := p.printFile(); err != nil {
+		return "", err
+	}
+	return p.sb.String(), nil
+}
+
+func (p *descriptorPrinter) printFile() error {
+	f := p.file
+	switch f.GetSyntax() {
+	case "proto3":
+		p.sb.WriteString("syntax = \"proto3\";\n")
+	case "editions":
+		p.sb.WriteString(fmt.Sprintf("edition = %q;\n", strings.TrimPrefix(f.GetEdition().String(), "EDITION_")))
+	default:
+		p.sb.WriteString("syntax = \"proto2\";\n")
+	}
+	if f.Package != nil {
+		p.sb.WriteString(fmt.Sprintf("\npackage %s;\n", f.GetPackage()))
+	}
+
+	if len(f.GetDependency()) > 0 {
+		p.sb.WriteString("\n")
+	}
+	public := indexSet(f.GetPublicDependency())
+	weak := indexSet(f.GetWeakDependency())
+	for i, dep := range f.GetDependency() {
+		switch {
+		case public[int32(i)]: // #nosec G115 -- dependency index is always small
+			p.sb.WriteString(fmt.Sprintf("import public %q;\n", dep))
+		case weak[int32(i)]: // #nosec G115 -- dependency index is always small
+			p.sb.WriteString(fmt.Sprintf("import weak %q;\n", dep))
+		default:
+			p.sb.WriteString(fmt.Sprintf("import %q;\n", dep))
+		}
+	}
+
+	if err := p.printOptionStatements(f.GetOptions(), 0); err != nil {
+		return err
+	}
+
+	for _, m := range f.GetMessageType() {
+		p.sb.WriteString("\n")
+		if err := p.printMessage(m, "."+qualify(f.GetPackage(), m.GetName()), 0); err != nil {
+			return err
+		}
+	}
+	for _, e := range f.GetEnumType() {
+		p.sb.WriteString("\n")
+		if err := p.printEnum(e, 0); err != nil {
+			return err
+		}
+	}
+	for _, s := range f.GetService() {
+		p.sb.WriteString("\n")
+		if err := p.printService(s); err != nil {
+			return err
+		}
+	}
+	return p.printExtensions(f.GetExtension(), 0)
+}
+
+// printMessage writes a message. fullName is its fully-qualified name with a
+// leading dot, as field type names refer to it.
+func (p *descriptorPrinter) printMessage(m *descriptorpb.DescriptorProto, fullName string, depth int) error {
+	p.line(depth, "message %s {", m.GetName())
+	if err := p.printMessageBody(m, fullName, depth+1); err != nil {
+		return err
+	}
+	p.line(depth, "}")
+	return nil
+}
+
+// printMessageBody writes the declarations of a message, which a group
+// declares after its field instead of in a message statement.
+func (p *descriptorPrinter) printMessageBody(m *descriptorpb.DescriptorProto, fullName string, depth int) error {
+	if err := p.printOptionStatements(m.GetOptions(), depth); err != nil {
+		return err
+	}
+
+	// Map entries and groups are declared by their fields, not as nested
+	// messages of their own. The compiler adds their types to the nested
+	// messages where the field is declared, so the nested messages before
+	// them are written first to keep the order.
+	nested := make(map[string]*descriptorpb.DescriptorProto, len(m.GetNestedType()))
+	index := make(map[string]int, len(m.GetNestedType()))
+	for i, n := range m.GetNestedType() {
+		nested[fullName+"."+n.GetName()] = n
+		index[fullName+"."+n.GetName()] = i
+	}
+	implicit := make(map[string]bool)
+	next := 0
+	printNestedBefore := func(end int) error {
+		for ; next < end; next++ {
+			n := m.GetNestedType()[next]
+			name := fullName + "." + n.GetName()
+			if n.GetOptions().GetMapEntry() || isGroupType(m, name) {
+				continue
+			}
+			if err := p.printMessage(n, name, depth); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	printed := make(map[int32]bool)
+	for _, field := range m.GetField() {
+		if i, ok := index[field.GetTypeName()]; ok && i >= next {
+			if err := printNestedBefore(i); err != nil {
+				return err
+			}
+		}
+		if field.OneofIndex != nil && !field.GetProto3Optional() {
+			idx := field.GetOneofIndex()
+			if printed[idx] {
+				continue
+			}
+			printed[idx] = true
+			if err := p.printOneof(m, idx, nested, implicit, depth); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.printField(field, nested, implicit, false, depth); err != nil {
+			return err
+		}
+	}
+
+	if err := printNestedBefore(len(m.GetNestedType())); err != nil {
+		return err
+	}
+	for _, e := range m.GetEnumType() {
+		if err := p.printEnum(e, depth); err != nil {
+			return err
+		}
+	}
+	if err := p.printExtensions(m.GetExtension(), depth); err != nil {
+		return err
+	}
+
+	for _, r := range m.GetExtensionRange() {
+		if err := p.collectOptions(r.GetOptions()); err != nil {
+			return err
+		}
+		p.line(depth, "extensions %s%s;", rangeString(r.GetStart(), r.GetEnd()-1, 536870911), p.bracketOptions())
+	}
+	if len(m.GetReservedRange()) > 0 {
+		ranges := make([]string, len(m.GetReservedRange()))
+		for i, r := range m.GetReservedRange() {
+			ranges[i] = rangeString(r.GetStart(), r.GetEnd()-1, 536870911)
+		}
+		p.line(depth, "reserved %s;", strings.Join(ranges, ", "))
+	}
+	if len(m.GetReservedName()) > 0 {
+		p.line(depth, "reserved %s;", p.reservedNames(m.GetReservedName()))
+	}
+	return nil
+}
+
+func (p *descriptorPrinter) printOneof(m *descriptorpb.DescriptorProto, idx int32, nested map[string]*descriptorpb.DescriptorProto, implicit map[string]bool, depth int) error {
+	oneof := m.GetOneofDecl()[idx]
+	p.line(depth, "oneof %s {", oneof.GetName())
+	if err := p.printOptionStatements(oneof.GetOptions(), depth+1); err != nil {
+		return err
+	}
+	for _, field := range m.GetField() {
+		if field.OneofIndex == nil || field.GetOneofIndex() != idx || field.GetProto3Optional() {
+			continue
+		}
+		if err := p.printField(field, nested, implicit, true, depth+1); err != nil {
+			return err
+		}
+	}
+	p.line(depth, "}")
+	return nil
+}
+
+// printField writes a field, or an extension when its extendee is set. A
+// field whose type is a map entry is written as a map and a group as a group
+// with its body; both types are marked implicit so they are not written
+// again as nested messages.
+func (p *descriptorPrinter) printField(field *descriptorpb.FieldDescriptorProto, nested map[string]*descriptorpb.DescriptorProto, implicit map[string]bool, inOneof bool, depth int) error {
+	label := p.label(field, inOneof)
+	typeName := scalarTypeName(field.GetType())
+	if typeName == "" {
+		typeName = field.GetTypeName()
+	}
+
+	entry := nested[field.GetTypeName()]
+	if entry != nil && entry.GetOptions().GetMapEntry() && field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		implicit[field.GetTypeName()] = true
+		var key, value *descriptorpb.FieldDescriptorProto
+		for _, f := range entry.GetField() {
+			switch f.GetNumber() {
+			case 1:
+				key = f
+			case 2:
+				value = f
+			}
+		}
+		if key == nil || value == nil {
+			return fmt.Errorf("map entry %s lacks a key or value field", field.GetTypeName())
+		}
+		label = ""
+		typeName = fmt.Sprintf("map<%s, %s>", fieldTypeName(key), fieldTypeName(value))
+	}
+
+	if err := p.fieldOptions(field); err != nil {
+		return err
+	}
+	if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP && p.file.GetSyntax() != "editions" && entry != nil {
+		implicit[field.GetTypeName()] = true
+		p.line(depth, "%sgroup %s = %d%s {", label, entry.GetName(), field.GetNumber(), p.bracketOptions())
+		if err := p.printMessageBody(entry, field.GetTypeName(), depth+1); err != nil {
+			return err
+		}
+		p.line(depth, "}")
+		return nil
+	}
+	p.line(depth, "%s%s %s = %d%s;", label, typeName, field.GetName(), field.GetNumber(), p.bracketOptions())
+	return nil
+}
+
+// isGroupType reports whether the nested message name is the type of a
+// group field of m.
+func isGroupType(m *descriptorpb.DescriptorProto, name string) bool {
+	for _, f := range m.GetField() {
+		if f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP && f.GetTypeName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// label returns the label a field is declared with, followed by a space, or
+// "" when it has none.
+func (p *descriptorPrinter) label(field *descriptorpb.FieldDescriptorProto, inOneof bool) string {
+	if inOneof {
+		return ""
+	}
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return "repeated "
+	}
+	switch p.file.GetSyntax() {
+	case "proto3":
+		if field.GetProto3Optional() {
+			return "optional "
+		}
+		return ""
+	case "editions":
+		return ""
+	}
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED {
+		return "required "
+	}
+	return "optional "
+}
+
+// fieldOptions collects the options of a field, including its default value
+// and a JSON name that differs from the default one.
+func (p *descriptorPrinter) fieldOptions(field *descriptorpb.FieldDescriptorProto) error {
+	if err := p.collectOptions(field.GetOptions()); err != nil {
+		return err
+	}
+	var pseudo []string
+	if field.DefaultValue != nil {
+		def := field.GetDefaultValue()
+		switch field.GetType() {
+		case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+			def = quoteProtoString(def)
+		case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+			def = `"` + def + `"` // Already C-escaped in the descriptor
+		}
+		pseudo = append(pseudo, "default = "+def)
+	}
+	if field.JsonName != nil && field.Extendee == nil && field.GetJsonName() != jsonName(field.GetName()) {
+		pseudo = append(pseudo, "json_name = "+quoteProtoString(field.GetJsonName()))
+	}
+	p.options = append(pseudo, p.options...)
+	return nil
+}
+
+func (p *descriptorPrinter) printEnum(e *descriptorpb.EnumDescriptorProto, depth int) error {
+	p.line(depth, "enum %s {", e.GetName())
+	if err := p.printOptionStatements(e.GetOptions(), depth+1); err != nil {
+		return err
+	}
+	for _, v := range e.GetValue() {
+		if err := p.collectOptions(v.GetOptions()); err != nil {
+			return err
+		}
+		p.line(depth+1, "%s = %d%s;", v.GetName(), v.GetNumber(), p.bracketOptions())
+	}
+	if len(e.GetReservedRange()) > 0 {
+		ranges := make([]string, len(e.GetReservedRange()))
+		for i, r := range e.GetReservedRange() {
+			ranges[i] = rangeString(r.GetStart(), r.GetEnd(), 2147483647)
+		}
+		p.line(depth+1, "reserved %s;", strings.Join(ranges, ", "))
+	}
+	if len(e.GetReservedName()) > 0 {
+		p.line(depth+1, "reserved %s;", p.reservedNames(e.GetReservedName()))
+	}
+	p.line(depth, "}")
+	return nil
+}
+
+func (p *descriptorPrinter) printService(s *descriptorpb.ServiceDescriptorProto) error {
+	p.line(0, "service %s {", s.GetName())
+	if err := p.printOptionStatements(s.GetOptions(), 1); err != nil {
+		return err
+	}
+	for _, m := range s.GetMethod() {
+		input, output := m.GetInputType(), m.GetOutputType()
+		if m.GetClientStreaming() {
+			input = "stream " + input
+		}
+		if m.GetServerStreaming() {
+			output = "stream " + output
+		}
+		if err := p.collectOptions(m.GetOptions()); err != nil {
+			return err
+		}
+		if len(p.options) == 0 {
+			p.line(1, "rpc %s(%s) returns (%s);", m.GetName(), input, output)
+			continue
+		}
+		p.line(1, "rpc %s(%s) returns (%s) {", m.GetName(), input, output)
+		for _, opt := range p.options {
+			p.line(2, "option %s;", opt)
+		}
+		p.options = nil
+		p.line(1, "}")
+	}
+	p.line(0, "}")
+	return nil
+}
+
+// printExtensions writes extension fields in one extend block per extended
+// message, in the order the messages are first extended.
+func (p *descriptorPrinter) printExtensions(extensions []*descriptorpb.FieldDescriptorProto, depth int) error {
+	var extendees []string
+	byExtendee := make(map[string][]*descriptorpb.FieldDescriptorProto)
+	for _, ext := range extensions {
+		if _, ok := byExtendee[ext.GetExtendee()]; !ok {
+			extendees = append(extendees, ext.GetExtendee())
+		}
+		byExtendee[ext.GetExtendee()] = append(byExtendee[ext.GetExtendee()], ext)
+	}
+	for _, extendee := range extendees {
+		if depth == 0 {
+			p.sb.WriteString("\n")
+		}
+		p.line(depth, "extend %s {", extendee)
+		for _, ext := range byExtendee[extendee] {
+			if err := p.printField(ext, nil, map[string]bool{}, false, depth+1); err != nil {
+				return err
+			}
+		}
+		p.line(depth, "}")
+	}
+	return nil
+}
+
+// printOptionStatements writes the options of a file, message, enum,
+// service or oneof as option statements.
+func (p *descriptorPrinter) printOptionStatements(opts proto.Message, depth int) error {
+	if err := p.collectOptions(opts); err != nil {
+		return err
+	}
+	if len(p.options) > 0 && depth == 0 {
+		p.sb.WriteString("\n")
+	}
+	for _, opt := range p.options {
+		p.line(depth, "option %s;", opt)
+	}
+	p.options = nil
+	return nil
+}
+
+// bracketOptions returns the collected options in the compact form of a
+// field or enum value, or "" when there are none.
+func (p *descriptorPrinter) bracketOptions() string {
+	if len(p.options) == 0 {
+		return ""
+	}
+	s := " [" + strings.Join(p.options, ", ") + "]"
+	p.options = nil
+	return s
+}
+
+// collectOptions renders each option set in an options message as
+// "name = value". Custom options are resolved against the extensions in the
+// descriptor set; one that cannot be resolved is an error, since it would be
+// silently lost.
+func (p *descriptorPrinter) collectOptions(opts proto.Message) error {
+	p.options = nil
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return nil
+	}
+	data, err := proto.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	resolved := opts.ProtoReflect().Type().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: p.types}).Unmarshal(data, resolved); err != nil {
+		return err
+	}
+	if len(resolved.ProtoReflect().GetUnknown()) > 0 {
+		return fmt.Errorf("%s has custom options that are not defined in the descriptor set; include the files that define them, as protoc --include_imports does", opts.ProtoReflect().Descriptor().Name())
+	}
+
+	type option struct {
+		number protoreflect.FieldNumber
+		text   []string
+	}
+	var options []option
+	var rangeErr error
+	resolved.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Name() == "map_entry" || fd.Name() == "uninterpreted_option" {
+			return true
+		}
+		name := string(fd.Name())
+		if fd.IsExtension() {
+			name = "(" + string(fd.FullName()) + ")"
+		}
+		var values []string
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				values = append(values, optionValue(fd, list.Get(i)))
+			}
+		} else if fd.IsMap() {
+			rangeErr = fmt.Errorf("map option %s is not supported", fd.FullName())
+			return false
+		} else {
+			values = []string{optionValue(fd, v)}
+		}
+		opt := option{number: fd.Number()}
+		for _, value := range values {
+			opt.text = append(opt.text, name+" = "+value)
+		}
+		options = append(options, opt)
+		return true
+	})
+	if rangeErr != nil {
+		return rangeErr
+	}
+	sort.SliceStable(options, func(i, j int) bool { return options[i].number < options[j].number })
+	for _, opt := range options {
+		p.options = append(p.options, opt.text...)
+	}
+	return nil
+}
+
+// optionValue renders an option value as it is written in .proto source.
+func optionValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.StringKind:
+		return quoteProtoString(v.String())
+	case protoreflect.BytesKind:
+		return quoteProtoString(string(v.Bytes()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		text := prototext.MarshalOptions{}.Format(v.Message().Interface())
+		return "{ " + text + " }"
+	default:
+		return v.String()
+	}
+}
+
+// reservedNames renders reserved names, which are quoted strings before
+// editions and identifiers in them.
+func (p *descriptorPrinter) reservedNames(names []string) string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		if p.file.GetSyntax() == "editions" {
+			out[i] = n
+		} else {
+			out[i] = strconv.Quote(n)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// line writes one indented line.
+func (p *descriptorPrinter) line(depth int, format string, args ...any) {
+	p.sb.WriteString(strings.Repeat("  ", depth))
+	p.sb.WriteString(fmt.Sprintf(format, args...))
+	p.sb.WriteString("\n")
+}
+
+// rangeString renders an inclusive range of field or enum numbers.
+func rangeString(start, end, maxNumber int32) string {
+	switch {
+	case start == end:
+		return strconv.Itoa(int(start))
+	case end >= maxNumber:
+		return fmt.Sprintf("%d to max", start)
+	default:
+		return fmt.Sprintf("%d to %d", start, end)
+	}
+}
+
+// fieldTypeName returns the type of a map key or value as it is written.
+func fieldTypeName(field *descriptorpb.FieldDescriptorProto) string {
+	if name := scalarTypeName(field.GetType()); name != "" {
+		return name
+	}
+	return field.GetTypeName()
+}
+
+// scalarTypeName returns the keyword of a scalar field type, or "" for
+// messages, groups and enums, which are named by their type name.
+func scalarTypeName(t descriptorpb.FieldDescriptorProto_Type) string {
+	switch t {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptorpb.FieldDescriptorProto_TYPE_GROUP,
+		descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(t.String(), "TYPE_"))
+}
+
+// jsonName returns the JSON name protoc gives a field by default.
+func jsonName(name string) string {
+	var sb strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			sb.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// quoteProtoString quotes a string for .proto source, escaping bytes that
+// are not printable ASCII in octal.
+func quoteProtoString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c >= 0x20 && c < 0x7f:
+			sb.WriteByte(c)
+		default:
+			sb.WriteString(fmt.Sprintf("\\%03o", c))
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// qualify joins a package and a name.
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// indexSet returns the indexes as a set.
+func indexSet(indexes []int32) map[int32]bool {
+	set := make(map[int32]bool, len(indexes))
+	for _, i := range indexes {
+		set[i] = true
+	}
+	return set
+}
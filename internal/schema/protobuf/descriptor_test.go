@@ -0,0 +1,218 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// descriptorSetOf compiles a schema and returns it with its imports as a
+// base64-encoded FileDescriptorSet, as protoc --include_imports writes it.
+func descriptorSetOf(t *testing.T, src string, refs []storage.Reference) (*ParsedProtobuf, *descriptorpb.FileDescriptorSet) {
+	t.Helper()
+	parsed, err := NewParser().Parse(src, refs)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+	pp := parsed.(*ParsedProtobuf)
+	set := &descriptorpb.FileDescriptorSet{}
+	imports := pp.descriptor.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(imports.Get(i).FileDescriptor))
+	}
+	set.File = append(set.File, protodesc.ToFileDescriptorProto(pp.descriptor))
+	return pp, set
+}
+
+func encodeDescriptor(t *testing.T, m proto.Message) string {
+	t.Helper()
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// assertSameDescriptor checks that two parsed schemas compile to the same
+// file descriptor, apart from source locations.
+func assertSameDescriptor(t *testing.T, want, got *ParsedProtobuf) {
+	t.Helper()
+	marshal := func(p *ParsedProtobuf) string {
+		fdp := protodesc.ToFileDescriptorProto(p.descriptor)
+		fdp.SourceCodeInfo = nil
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(fdp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if marshal(want) != marshal(got) {
+		t.Errorf("Descriptor differs after the round trip; reconstructed source:\n%s", got.Raw())
+	}
+	if want.Fingerprint() != got.Fingerprint() {
+		t.Errorf("Fingerprint differs: %s vs %s", want.Fingerprint(), got.Fingerprint())
+	}
+}
+
+func TestParser_Parse_DescriptorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "proto3",
+			src: `syntax = "proto3";
+package acme.v1;
+import "google/protobuf/timestamp.proto";
+option java_package = "com.acme";
+option go_package = "acme/v1;acmev1";
+message Order {
+  string id = 1;
+  repeated Item items = 2 [deprecated = true];
+  map<string, int64> counts = 3;
+  oneof payment { string card = 4; string iban = 5; }
+  google.protobuf.Timestamp created_at = 6;
+  optional int32 quantity = 7;
+  string legacy_name = 8 [json_name = "name"];
+  message Item { string sku = 1; Kind kind = 2; }
+  map<int32, Item> by_id = 9;
+  enum Kind {
+    option allow_alias = true;
+    KIND_UNSPECIFIED = 0;
+    BIG = 1;
+    LARGE = 1 [deprecated = true];
+    reserved 5 to 9, 100 to max;
+  }
+  reserved 10, 12 to 15;
+  reserved "old";
+}
+service Orders {
+  rpc Get(Order) returns (Order);
+  rpc Watch(stream Order) returns (stream Order) { option deprecated = true; }
+}`,
+		},
+		{
+			name: "proto2 with custom options",
+			src: `syntax = "proto2";
+package p2;
+import "google/protobuf/descriptor.proto";
+message Msg {
+  required string a = 1 [default = "h\"i\n"];
+  optional bytes b = 2 [default = "\001\377x"];
+  optional double d = 3 [default = inf];
+  optional E e = 4 [default = TWO];
+  optional group G = 5 { optional int32 x = 1; }
+  repeated int32 packed = 6 [packed = true];
+  extensions 100 to 199, 300 to max;
+}
+enum E { ONE = 1; TWO = 2; }
+extend Msg { optional int32 ext = 100; }
+message Opts { optional string v = 1; optional int32 n = 2; }
+extend google.protobuf.FieldOptions {
+  optional string tag = 50001;
+  optional Opts rich = 50002;
+  repeated int32 many = 50003;
+}
+extend google.protobuf.MessageOptions { optional bool flag = 50004; }
+message Tagged {
+  option (flag) = true;
+  optional string s = 1 [(tag) = "x", (rich) = { v: "a" n: 3 }, (many) = 1, (many) = 2];
+}`,
+		},
+		{
+			name: "editions",
+			src: `edition = "2023";
+package ed;
+option features.field_presence = IMPLICIT;
+message M {
+  string a = 1 [features.field_presence = EXPLICIT];
+  repeated int32 r = 2 [features.repeated_field_encoding = EXPANDED];
+  M child = 3 [features.message_encoding = DELIMITED];
+  reserved foo, bar;
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, set := descriptorSetOf(t, tt.src, nil)
+
+			parsed, err := NewParser().Parse(encodeDescriptor(t, set), nil)
+			if err != nil {
+				t.Fatalf("Failed to parse descriptor set: %v", err)
+			}
+			got := parsed.(*ParsedProtobuf)
+			assertSameDescriptor(t, want, got)
+			if !strings.HasPrefix(got.Raw(), "syntax = ") && !strings.HasPrefix(got.Raw(), "edition = ") {
+				t.Errorf("Expected .proto source, got:\n%s", got.Raw())
+			}
+		})
+	}
+}
+
+func TestParser_Parse_SingleFileDescriptorProto(t *testing.T) {
+	want, set := descriptorSetOf(t, `syntax = "proto3";
+package acme;
+message User { string name = 1; }`, nil)
+
+	// Line-wrapped base64, as some tools write it.
+	encoded := encodeDescriptor(t, set.File[len(set.File)-1])
+	wrapped := encoded[:8] + "\n" + encoded[8:]
+
+	parsed, err := NewParser().Parse(wrapped, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse FileDescriptorProto: %v", err)
+	}
+	assertSameDescriptor(t, want, parsed.(*ParsedProtobuf))
+}
+
+func TestParser_Parse_DescriptorWithReferences(t *testing.T) {
+	refs := []storage.Reference{{
+		Name:    "opts.proto",
+		Subject: "opts",
+		Version: 1,
+		Schema: `syntax = "proto3";
+package opts;
+import "google/protobuf/descriptor.proto";
+extend google.protobuf.FieldOptions { string pii = 50100; }`,
+	}}
+	want, set := descriptorSetOf(t, `syntax = "proto3";
+import "opts.proto";
+message User { string email = 1 [(opts.pii) = "high"]; }`, refs)
+
+	parsed, err := NewParser().Parse(encodeDescriptor(t, set), refs)
+	if err != nil {
+		t.Fatalf("Failed to parse descriptor set: %v", err)
+	}
+	assertSameDescriptor(t, want, parsed.(*ParsedProtobuf))
+
+	// Without the file defining the custom option, the option cannot be
+	// written back and is refused rather than dropped.
+	_, err = NewParser().Parse(encodeDescriptor(t, set.File[len(set.File)-1]), refs)
+	if err == nil || !strings.Contains(err.Error(), "custom options") {
+		t.Errorf("Expected a custom options error, got %v", err)
+	}
+
+	// Imports must still be registered references.
+	if _, err := NewParser().Parse(encodeDescriptor(t, set), nil); err == nil {
+		t.Error("Expected an error for an import without a reference")
+	}
+}
+
+func TestDecodeDescriptor_NotADescriptor(t *testing.T) {
+	for _, s := range []string{
+		`syntax = "proto3"; message A { string a = 1; }`,
+		"abcd",
+		base64.StdEncoding.EncodeToString([]byte("not a descriptor")),
+		"",
+	} {
+		if _, ok := decodeDescriptor(s); ok {
+			t.Errorf("decodeDescriptor(%q) decoded a descriptor", s)
+		}
+	}
+}
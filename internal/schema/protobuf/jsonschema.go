@@ -0,0 +1,176 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+)
+
+// ToJSONSchema returns a JSON Schema (draft 2020-12) describing the messages
+// of the Protobuf schema in the canonical proto3 JSON mapping: fields are
+// named by their JSON name, 64-bit integers may be strings, bytes are base64
+// strings, enums are their value names and well-known types take their
+// special JSON forms. The description is not exact; for instance no field is
+// required and oneof exclusivity is not expressed. The first message is the
+// document itself and is referenced as "#"; every other message and enum is
+// emitted once under $defs, by full name. A schema without messages converts
+// to an empty object schema.
+func (p *ParsedProtobuf) ToJSONSchema() (string, error) {
+	c := &jsonSchemaConverter{defs: make(map[string]any)}
+	doc := map[string]any{"type": "object"}
+	if msgs := p.descriptor.Messages(); msgs.Len() > 0 {
+		c.root = msgs.Get(0).FullName()
+		doc = c.message(msgs.Get(0))
+	}
+	doc["$schema"] = schema.JSONSchemaDraft
+	if len(c.defs) > 0 {
+		doc["$defs"] = c.defs
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON Schema: %w", err)
+	}
+	return string(out), nil
+}
+
+// jsonSchemaConverter converts Protobuf descriptors to JSON Schema.
+type jsonSchemaConverter struct {
+	root protoreflect.FullName // the first message, converted as the document
+	defs map[string]any        // other messages and enums, by full name
+}
+
+// wellKnownJSONSchemas are the JSON forms of the well-known types that the
+// proto3 JSON mapping does not encode as objects of their fields.
+var wellKnownJSONSchemas = map[protoreflect.FullName]map[string]any{
+	"google.protobuf.Timestamp":   {"type": "string", "format": "date-time"},
+	"google.protobuf.Duration":    {"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?s$`},
+	"google.protobuf.FieldMask":   {"type": "string"},
+	"google.protobuf.Struct":      {"type": "object"},
+	"google.protobuf.Value":       {},
+	"google.protobuf.ListValue":   {"type": "array"},
+	"google.protobuf.Any":         {"type": "object", "properties": map[string]any{"@type": map[string]any{"type": "string"}}},
+	"google.protobuf.Empty":       {"type": "object"},
+	"google.protobuf.DoubleValue": {"type": "number"},
+	"google.protobuf.FloatValue":  {"type": "number"},
+	"google.protobuf.Int64Value":  {"type": []any{"integer", "string"}},
+	"google.protobuf.UInt64Value": {"type": []any{"integer", "string"}},
+	"google.protobuf.Int32Value":  {"type": "integer"},
+	"google.protobuf.UInt32Value": {"type": "integer"},
+	"google.protobuf.BoolValue":   {"type": "boolean"},
+	"google.protobuf.StringValue": {"type": "string"},
+	"google.protobuf.BytesValue":  {"type": "string", "contentEncoding": "base64"},
+}
+
+// message returns the JSON Schema of a message.
+func (c *jsonSchemaConverter) message(md protoreflect.MessageDescriptor) map[string]any {
+	out := map[string]any{"type": "object", "title": string(md.FullName())}
+	if doc := comment(md); doc != "" {
+		out["description"] = doc
+	}
+	fields := md.Fields()
+	properties := make(map[string]any, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		prop := c.field(fd)
+		if doc := comment(fd); doc != "" {
+			prop["description"] = doc
+		}
+		properties[fd.JSONName()] = prop
+	}
+	out["properties"] = properties
+	return out
+}
+
+// field returns the JSON Schema of a field, including its cardinality.
+func (c *jsonSchemaConverter) field(fd protoreflect.FieldDescriptor) map[string]any {
+	switch {
+	case fd.IsMap():
+		return map[string]any{"type": "object", "additionalProperties": c.singular(fd.MapValue())}
+	case fd.IsList():
+		return map[string]any{"type": "array", "items": c.singular(fd)}
+	}
+	return c.singular(fd)
+}
+
+// singular returns the JSON Schema of one value of a field.
+func (c *jsonSchemaConverter) singular(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": []any{"integer", "string"}}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]any{"type": "number"}
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.EnumKind:
+		return c.enumRef(fd.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return c.messageRef(fd.Message())
+	}
+	return map[string]any{}
+}
+
+// messageRef returns a reference to a message, converting it into defs the
+// first time it is referenced. Well-known types are inlined.
+func (c *jsonSchemaConverter) messageRef(md protoreflect.MessageDescriptor) map[string]any {
+	if wk, ok := wellKnownJSONSchemas[md.FullName()]; ok {
+		out := make(map[string]any, len(wk))
+		for k, v := range wk {
+			out[k] = v
+		}
+		return out
+	}
+	if md.FullName() == c.root {
+		return map[string]any{"$ref": "#"}
+	}
+	name := string(md.FullName())
+	if _, ok := c.defs[name]; !ok {
+		// Placeholder so that recursive messages reference it.
+		c.defs[name] = nil
+		c.defs[name] = c.message(md)
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// enumRef returns a reference to an enum, converting it into defs the first
+// time it is referenced. google.protobuf.NullValue is null.
+func (c *jsonSchemaConverter) enumRef(ed protoreflect.EnumDescriptor) map[string]any {
+	if ed.FullName() == "google.protobuf.NullValue" {
+		return map[string]any{"type": "null"}
+	}
+	name := string(ed.FullName())
+	if _, ok := c.defs[name]; !ok {
+		values := ed.Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		def := map[string]any{"type": "string", "title": name, "enum": names}
+		if doc := comment(ed); doc != "" {
+			def["description"] = doc
+		}
+		c.defs[name] = def
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// comment returns the leading comment of a descriptor as plain text.
+func comment(d protoreflect.Descriptor) string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	lines := strings.Split(strings.TrimSpace(loc.LeadingComments), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
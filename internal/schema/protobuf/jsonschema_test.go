@@ -0,0 +1,81 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
+)
+
+func TestParsedProtobuf_ToJSONSchema(t *testing.T) {
+	parsed, err := NewParser().Parse(`
+syntax = "proto3";
+package shop;
+
+import "google/protobuf/timestamp.proto";
+
+// An order.
+message Order {
+  int64 order_id = 1;
+  repeated Line lines = 2;
+  Status status = 3;
+  google.protobuf.Timestamp placed_at = 4;
+  map<string, string> tags = 5;
+  bytes payload = 6;
+  Order parent = 7;
+}
+
+message Line {
+  string sku = 1;
+  int32 quantity = 2;
+}
+
+enum Status {
+  STATUS_UNKNOWN = 0;
+  STATUS_PLACED = 1;
+}
+`, nil)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	out, err := parsed.(schema.JSONSchemaConverter).ToJSONSchema()
+	if err != nil {
+		t.Fatalf("ToJSONSchema: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("expected a JSON document, got %q: %v", out, err)
+	}
+
+	want := map[string]any{
+		"$schema":     schema.JSONSchemaDraft,
+		"type":        "object",
+		"title":       "shop.Order",
+		"description": "An order.",
+		"properties": map[string]any{
+			"orderId":  map[string]any{"type": []any{"integer", "string"}},
+			"lines":    map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/shop.Line"}},
+			"status":   map[string]any{"$ref": "#/$defs/shop.Status"},
+			"placedAt": map[string]any{"type": "string", "format": "date-time"},
+			"tags":     map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"payload":  map[string]any{"type": "string", "contentEncoding": "base64"},
+			"parent":   map[string]any{"$ref": "#"},
+		},
+		"$defs": map[string]any{
+			"shop.Line": map[string]any{
+				"type":  "object",
+				"title": "shop.Line",
+				"properties": map[string]any{
+					"sku":      map[string]any{"type": "string"},
+					"quantity": map[string]any{"type": "integer"},
+				},
+			},
+			"shop.Status": map[string]any{"type": "string", "title": "shop.Status", "enum": []any{"STATUS_UNKNOWN", "STATUS_PLACED"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected JSON Schema:\n got %s", out)
+	}
+}
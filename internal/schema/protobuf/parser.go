@@ -7,11 +7,14 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/bufbuild/protocompile"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
 
@@ -36,20 +39,31 @@ func (p *Parser) Type() storage.SchemaType {
 	return storage.SchemaTypeProtobuf
 }
 
-// Parse parses and validates a Protobuf schema.
+// Parse parses and validates a Protobuf schema, given as .proto source or as
+// a base64-encoded FileDescriptorSet or FileDescriptorProto.
 func (p *Parser) Parse(schemaStr string, refs []storage.Reference) (schema.ParsedSchema, error) {
+	// A descriptor is compiled, stored and returned as the .proto source it
+	// describes.
+	source, ok, err := sourceFromDescriptor(schemaStr)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		schemaStr = source
+	}
+
 	// Create a resolver with references and the schema content
 	resolver := p.resolver.withReferencesAndSchema(schemaStr, refs)
 
-	// Create compiler with standard imports taking priority over hand-written
-	// stubs. This ensures the real descriptor.proto (with full Options messages)
-	// is used, enabling support for options like allow_alias and packed.
-	// The standard imports resolver is checked first; for non-standard files
-	// (user schemas, references), it returns not-found and the custom resolver
+	// Create compiler with standard and bundled imports taking priority over
+	// hand-written stubs. This ensures the real descriptor.proto (with full
+	// Options messages) is used, enabling support for options like allow_alias
+	// and packed. The bundled resolver is checked first; for other files (user
+	// schemas, references), it returns not-found and the custom resolver
 	// handles them.
 	compiler := protocompile.Compiler{
 		Resolver: protocompile.CompositeResolver{
-			protocompile.WithStandardImports(notFoundResolver{}),
+			BundledImports(),
 			resolver,
 		},
 		SourceInfoMode: protocompile.SourceInfoStandard,
@@ -77,9 +91,10 @@ func (p *Parser) Parse(schemaStr string, refs []storage.Reference) (schema.Parse
 
 // ParsedProtobuf represents a parsed Protobuf schema.
 type ParsedProtobuf struct {
-	raw        string
-	descriptor protoreflect.FileDescriptor
-	references []storage.Reference
+	raw          string
+	descriptor   protoreflect.FileDescriptor
+	references   []storage.Reference
+	keepComments bool // include leading comments in the normalized form
 }
 
 // Type returns the schema type.
@@ -105,7 +120,8 @@ func (p *ParsedProtobuf) RawSchema() interface{} {
 	return p.descriptor
 }
 
-// Raw returns the original schema string.
+// Raw returns the schema as .proto source: the original schema string, or
+// the source reconstructed from a descriptor.
 func (p *ParsedProtobuf) Raw() string {
 	return p.raw
 }
@@ -117,11 +133,20 @@ func (p *ParsedProtobuf) Descriptor() protoreflect.FileDescriptor {
 
 // Normalize returns a normalized copy of this schema.
 func (p *ParsedProtobuf) Normalize() schema.ParsedSchema {
-	return &ParsedProtobuf{
-		raw:        p.normalize(),
-		descriptor: p.descriptor,
-		references: p.references,
-	}
+	return p.NormalizeWithProfile(schema.DefaultNormalizationProfile)
+}
+
+// NormalizeWithProfile returns a normalized copy of this schema. When the
+// profile enables ProtobufKeepComments, leading comments are kept in the
+// normalized form and therefore contribute to the fingerprint.
+func (p *ParsedProtobuf) NormalizeWithProfile(profile schema.NormalizationProfile) schema.ParsedSchema {
+	normalized := &ParsedProtobuf{
+		descriptor:   p.descriptor,
+		references:   p.references,
+		keepComments: profile.ProtobufKeepComments,
+	}
+	normalized.raw = normalized.normalize()
+	return normalized
 }
 
 // HasTopLevelField reports whether any top-level message in the Protobuf
@@ -142,8 +167,19 @@ func (p *ParsedProtobuf) HasTopLevelField(field string) bool {
 	return false
 }
 
+// RecordName returns the full name of the first message in the Protobuf
+// schema. Returns "" when the schema declares no messages.
+func (p *ParsedProtobuf) RecordName() string {
+	if p.descriptor == nil || p.descriptor.Messages().Len() == 0 {
+		return ""
+	}
+	return string(p.descriptor.Messages().Get(0).FullName())
+}
+
 // FormattedString returns the schema in the requested format.
-// Supported formats: "serialized" (base64-encoded FileDescriptorProto), "default" (canonical).
+// Supported formats: "serialized" (base64-encoded FileDescriptorProto),
+// "resolved" (base64-encoded FileDescriptorSet of the schema and every file
+// it imports), "default" (canonical).
 func (p *ParsedProtobuf) FormattedString(format string) string {
 	switch strings.ToLower(strings.TrimSpace(format)) {
 	case "serialized":
@@ -153,13 +189,54 @@ func (p *ParsedProtobuf) FormattedString(format string) string {
 			return p.normalize()
 		}
 		return base64.StdEncoding.EncodeToString(data)
+	case "resolved":
+		data, err := proto.Marshal(p.fileDescriptorSet())
+		if err != nil {
+			return p.normalize()
+		}
+		return base64.StdEncoding.EncodeToString(data)
 	default:
 		return p.normalize()
 	}
 }
 
+// fileDescriptorSet returns the schema with its transitive imports as a
+// self-contained FileDescriptorSet. Every file comes after the files it
+// imports, as protoc orders them, and the schema's own file is last.
+func (p *ParsedProtobuf) fileDescriptorSet() *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		if fd == p.descriptor {
+			set.File = append(set.File, toFileDescriptorProto(fd))
+			return
+		}
+		fdp := protodesc.ToFileDescriptorProto(fd)
+		fdp.SourceCodeInfo = nil
+		set.File = append(set.File, fdp)
+	}
+	add(p.descriptor)
+	return set
+}
+
 // toFileDescriptorProto converts a protoreflect.FileDescriptor to a descriptorpb.FileDescriptorProto.
 func toFileDescriptorProto(fd protoreflect.FileDescriptor) *descriptorpb.FileDescriptorProto {
+	if fd.Syntax() == protoreflect.Editions {
+		// Editions semantics live in resolved features, which the hand-built
+		// descriptor below cannot express, so use the full conversion.
+		fdp := protodesc.ToFileDescriptorProto(fd)
+		fdp.SourceCodeInfo = nil
+		return fdp
+	}
 	fdp := &descriptorpb.FileDescriptorProto{}
 	name := fd.Path()
 	fdp.Name = &name
@@ -228,12 +305,20 @@ func fieldToProto(fd protoreflect.FieldDescriptor) *descriptorpb.FieldDescriptor
 	fp.Type = &fdType
 	label := descriptorpb.FieldDescriptorProto_Label(fd.Cardinality())
 	fp.Label = &label
-	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.EnumKind {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
 		tn := string(fd.Message().FullName())
-		if fd.Kind() == protoreflect.EnumKind {
-			tn = string(fd.Enum().FullName())
-		}
 		fp.TypeName = &tn
+	case protoreflect.EnumKind:
+		tn := string(fd.Enum().FullName())
+		fp.TypeName = &tn
+	}
+	if fd.HasDefault() {
+		def := defaultValueString(fd)
+		if fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BytesKind {
+			def, _ = strconv.Unquote(def)
+		}
+		fp.DefaultValue = &def
 	}
 	if fd.ContainingOneof() != nil {
 		idx := int32(fd.ContainingOneof().Index()) // #nosec G115 -- oneof index is always small
@@ -289,9 +374,12 @@ func (p *ParsedProtobuf) normalize() string {
 	}
 
 	// Syntax
-	if fd.Syntax() == protoreflect.Proto3 {
+	switch fd.Syntax() {
+	case protoreflect.Proto3:
 		sb.WriteString("syntax = \"proto3\";\n")
-	} else {
+	case protoreflect.Editions:
+		sb.WriteString(fmt.Sprintf("edition = %q;\n", editionName(fd)))
+	default:
 		sb.WriteString("syntax = \"proto2\";\n")
 	}
 
@@ -299,7 +387,7 @@ func (p *ParsedProtobuf) normalize() string {
 	messages := make([]string, 0, fd.Messages().Len())
 	for i := 0; i < fd.Messages().Len(); i++ {
 		msg := fd.Messages().Get(i)
-		messages = append(messages, normalizeMessage(msg, 0))
+		messages = append(messages, normalizeMessage(msg, 0, p.keepComments))
 	}
 	sort.Strings(messages)
 	for _, m := range messages {
@@ -310,7 +398,7 @@ func (p *ParsedProtobuf) normalize() string {
 	enums := make([]string, 0, fd.Enums().Len())
 	for i := 0; i < fd.Enums().Len(); i++ {
 		enum := fd.Enums().Get(i)
-		enums = append(enums, normalizeEnum(enum, 0))
+		enums = append(enums, normalizeEnum(enum, 0, p.keepComments))
 	}
 	sort.Strings(enums)
 	for _, e := range enums {
@@ -321,21 +409,67 @@ func (p *ParsedProtobuf) normalize() string {
 	services := make([]string, 0, fd.Services().Len())
 	for i := 0; i < fd.Services().Len(); i++ {
 		svc := fd.Services().Get(i)
-		services = append(services, normalizeService(svc))
+		services = append(services, normalizeService(svc, p.keepComments))
 	}
 	sort.Strings(services)
 	for _, s := range services {
 		sb.WriteString(s)
 	}
 
+	// Extensions (proto2 and editions)
+	sb.WriteString(normalizeExtensions(fd.Extensions(), 0, p.keepComments))
+
+	return sb.String()
+}
+
+// editionName returns the edition of an editions file, e.g. "2023".
+func editionName(fd protoreflect.FileDescriptor) string {
+	edition := protodesc.ToFileDescriptorProto(fd).GetEdition()
+	return strings.TrimPrefix(edition.String(), "EDITION_")
+}
+
+// normalizeExtensions renders extension fields grouped into one extend
+// block per extended message. Extensions of descriptor options only carry
+// custom options, which do not affect the data, and are skipped; this keeps
+// proto3 files, which may only extend options, unaffected.
+func normalizeExtensions(exts protoreflect.ExtensionDescriptors, indent int, keepComments bool) string {
+	byExtendee := make(map[string][]protoreflect.FieldDescriptor)
+	for i := 0; i < exts.Len(); i++ {
+		x := exts.Get(i)
+		extendee := x.ContainingMessage().FullName()
+		if extendee.Parent() == "google.protobuf" && strings.HasSuffix(string(extendee.Name()), "Options") {
+			continue
+		}
+		byExtendee[string(extendee)] = append(byExtendee[string(extendee)], x)
+	}
+	extendees := make([]string, 0, len(byExtendee))
+	for name := range byExtendee {
+		extendees = append(extendees, name)
+	}
+	sort.Strings(extendees)
+
+	var sb strings.Builder
+	prefix := strings.Repeat("  ", indent)
+	for _, name := range extendees {
+		fields := byExtendee[name]
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Number() < fields[j].Number() })
+		sb.WriteString(fmt.Sprintf("%sextend %s {\n", prefix, name))
+		for _, f := range fields {
+			sb.WriteString(normalizeField(f, indent+1, keepComments))
+		}
+		sb.WriteString(fmt.Sprintf("%s}\n", prefix))
+	}
 	return sb.String()
 }
 
 // normalizeMessage normalizes a message descriptor.
-func normalizeMessage(msg protoreflect.MessageDescriptor, indent int) string {
+func normalizeMessage(msg protoreflect.MessageDescriptor, indent int, keepComments bool) string {
 	var sb strings.Builder
 	prefix := strings.Repeat("  ", indent)
 
+	if keepComments {
+		sb.WriteString(leadingComment(msg, prefix))
+	}
 	sb.WriteString(fmt.Sprintf("%smessage %s {\n", prefix, msg.Name()))
 
 	// Fields (sorted by number)
@@ -348,7 +482,7 @@ func normalizeMessage(msg protoreflect.MessageDescriptor, indent int) string {
 		f := msg.Fields().Get(i)
 		fields = append(fields, fieldInfo{
 			number: int(f.Number()),
-			text:   normalizeField(f, indent+1),
+			text:   normalizeField(f, indent+1, keepComments),
 		})
 	}
 	sort.Slice(fields, func(i, j int) bool {
@@ -358,13 +492,21 @@ func normalizeMessage(msg protoreflect.MessageDescriptor, indent int) string {
 		sb.WriteString(f.text)
 	}
 
+	// Extension ranges (proto2 and editions)
+	ranges := msg.ExtensionRanges()
+	for i := 0; i < ranges.Len(); i++ {
+		r := ranges.Get(i)
+		// Ranges are stored with an exclusive end.
+		sb.WriteString(fmt.Sprintf("%s  extensions %d to %d;\n", prefix, r[0], r[1]-1))
+	}
+
 	// Nested messages
 	nested := make([]string, 0, msg.Messages().Len())
 	for i := 0; i < msg.Messages().Len(); i++ {
 		nm := msg.Messages().Get(i)
 		// Skip map entry types
 		if !nm.IsMapEntry() {
-			nested = append(nested, normalizeMessage(nm, indent+1))
+			nested = append(nested, normalizeMessage(nm, indent+1, keepComments))
 		}
 	}
 	sort.Strings(nested)
@@ -376,7 +518,7 @@ func normalizeMessage(msg protoreflect.MessageDescriptor, indent int) string {
 	enums := make([]string, 0, msg.Enums().Len())
 	for i := 0; i < msg.Enums().Len(); i++ {
 		e := msg.Enums().Get(i)
-		enums = append(enums, normalizeEnum(e, indent+1))
+		enums = append(enums, normalizeEnum(e, indent+1, keepComments))
 	}
 	sort.Strings(enums)
 	for _, e := range enums {
@@ -389,7 +531,7 @@ func normalizeMessage(msg protoreflect.MessageDescriptor, indent int) string {
 		o := msg.Oneofs().Get(i)
 		// Skip synthetic oneofs (for optional fields in proto3)
 		if !o.IsSynthetic() {
-			oneofs = append(oneofs, normalizeOneof(o, indent+1))
+			oneofs = append(oneofs, normalizeOneof(o, indent+1, keepComments))
 		}
 	}
 	sort.Strings(oneofs)
@@ -397,13 +539,20 @@ func normalizeMessage(msg protoreflect.MessageDescriptor, indent int) string {
 		sb.WriteString(o)
 	}
 
+	// Nested extensions
+	sb.WriteString(normalizeExtensions(msg.Extensions(), indent+1, keepComments))
+
 	sb.WriteString(fmt.Sprintf("%s}\n", prefix))
 	return sb.String()
 }
 
 // normalizeField normalizes a field descriptor.
-func normalizeField(f protoreflect.FieldDescriptor, indent int) string {
+func normalizeField(f protoreflect.FieldDescriptor, indent int, keepComments bool) string {
 	prefix := strings.Repeat("  ", indent)
+	comment := ""
+	if keepComments {
+		comment = leadingComment(f, prefix)
+	}
 
 	var label string
 	if f.Cardinality() == protoreflect.Repeated {
@@ -411,18 +560,95 @@ func normalizeField(f protoreflect.FieldDescriptor, indent int) string {
 			// Map field
 			keyType := protoTypeName(f.MapKey())
 			valueType := protoTypeName(f.MapValue())
-			return fmt.Sprintf("%smap<%s, %s> %s = %d;\n", prefix, keyType, valueType, f.Name(), f.Number())
+			return fmt.Sprintf("%s%smap<%s, %s> %s = %d;\n", comment, prefix, keyType, valueType, f.Name(), f.Number())
 		}
 		label = "repeated "
+	} else if f.ParentFile().Syntax() == protoreflect.Editions {
+		// Editions have no optional or required labels; presence is a
+		// feature, rendered by fieldOptions.
 	} else if f.Cardinality() == protoreflect.Optional && f.ParentFile().Syntax() == protoreflect.Proto2 {
 		label = "optional "
 	} else if f.Cardinality() == protoreflect.Required {
 		label = "required "
 	}
 
-	typeName := protoTypeName(f)
+	return fmt.Sprintf("%s%s%s%s = %d%s;\n", comment, prefix, label, fieldTypeAndName(f), f.Number(), fieldOptions(f))
+}
 
-	return fmt.Sprintf("%s%s%s %s = %d;\n", prefix, label, typeName, f.Name(), f.Number())
+// fieldTypeAndName returns the type and name of a field declaration. A
+// proto2 group is declared by its message name; the group's message itself
+// is rendered with the nested messages.
+func fieldTypeAndName(f protoreflect.FieldDescriptor) string {
+	if f.Kind() == protoreflect.GroupKind && f.ParentFile().Syntax() == protoreflect.Proto2 {
+		return "group " + string(f.Message().Name())
+	}
+	return protoTypeName(f) + " " + string(f.Name())
+}
+
+// fieldOptions returns the options of a field that change its meaning or
+// encoding, as " [...]", or "" when there are none. Only proto2 and
+// editions fields can have any, so proto3 fields are unaffected.
+func fieldOptions(f protoreflect.FieldDescriptor) string {
+	var opts []string
+	if f.HasDefault() {
+		opts = append(opts, "default = "+defaultValueString(f))
+	}
+	if f.ParentFile().Syntax() == protoreflect.Editions {
+		// Only features that differ from the edition 2023 defaults are
+		// rendered, resolved from the file, message and field options.
+		switch {
+		case f.Cardinality() == protoreflect.Required:
+			opts = append(opts, "features.field_presence = LEGACY_REQUIRED")
+		case f.Cardinality() == protoreflect.Optional && !f.HasPresence():
+			opts = append(opts, "features.field_presence = IMPLICIT")
+		}
+		if f.Kind() == protoreflect.GroupKind {
+			opts = append(opts, "features.message_encoding = DELIMITED")
+		}
+		if f.Cardinality() == protoreflect.Repeated && !f.IsMap() && isPackable(f.Kind()) && !f.IsPacked() {
+			opts = append(opts, "features.repeated_field_encoding = EXPANDED")
+		}
+	}
+	if len(opts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(opts, ", ") + "]"
+}
+
+// isPackable reports whether repeated fields of the kind can use packed
+// encoding: every scalar kind except strings and bytes.
+func isPackable(k protoreflect.Kind) bool {
+	switch k {
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		return false
+	}
+	return true
+}
+
+// defaultValueString renders the explicit default of a proto2 or editions
+// field as it would appear in a default option.
+func defaultValueString(f protoreflect.FieldDescriptor) string {
+	switch f.Kind() {
+	case protoreflect.EnumKind:
+		return string(f.DefaultEnumValue().Name())
+	case protoreflect.StringKind:
+		return strconv.Quote(f.Default().String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(f.Default().Bytes()))
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		v := f.Default().Float()
+		switch {
+		case math.IsInf(v, 1):
+			return "inf"
+		case math.IsInf(v, -1):
+			return "-inf"
+		case math.IsNaN(v):
+			return "nan"
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(f.Default().Interface())
+	}
 }
 
 // protoTypeName returns the type name for a field.
@@ -458,22 +684,23 @@ func protoTypeName(f protoreflect.FieldDescriptor) string {
 		return "string"
 	case protoreflect.BytesKind:
 		return "bytes"
-	case protoreflect.MessageKind:
-		return string(f.Message().FullName())
 	case protoreflect.EnumKind:
 		return string(f.Enum().FullName())
-	case protoreflect.GroupKind:
-		return "group"
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return string(f.Message().FullName())
 	default:
 		return "unknown"
 	}
 }
 
 // normalizeEnum normalizes an enum descriptor.
-func normalizeEnum(e protoreflect.EnumDescriptor, indent int) string {
+func normalizeEnum(e protoreflect.EnumDescriptor, indent int, keepComments bool) string {
 	var sb strings.Builder
 	prefix := strings.Repeat("  ", indent)
 
+	if keepComments {
+		sb.WriteString(leadingComment(e, prefix))
+	}
 	sb.WriteString(fmt.Sprintf("%senum %s {\n", prefix, e.Name()))
 
 	// Values (sorted by number)
@@ -484,9 +711,13 @@ func normalizeEnum(e protoreflect.EnumDescriptor, indent int) string {
 	values := make([]valueInfo, 0, e.Values().Len())
 	for i := 0; i < e.Values().Len(); i++ {
 		v := e.Values().Get(i)
+		text := fmt.Sprintf("%s  %s = %d;\n", prefix, v.Name(), v.Number())
+		if keepComments {
+			text = leadingComment(v, prefix+"  ") + text
+		}
 		values = append(values, valueInfo{
 			number: int(v.Number()),
-			text:   fmt.Sprintf("%s  %s = %d;\n", prefix, v.Name(), v.Number()),
+			text:   text,
 		})
 	}
 	sort.Slice(values, func(i, j int) bool {
@@ -501,10 +732,13 @@ func normalizeEnum(e protoreflect.EnumDescriptor, indent int) string {
 }
 
 // normalizeOneof normalizes a oneof descriptor.
-func normalizeOneof(o protoreflect.OneofDescriptor, indent int) string {
+func normalizeOneof(o protoreflect.OneofDescriptor, indent int, keepComments bool) string {
 	var sb strings.Builder
 	prefix := strings.Repeat("  ", indent)
 
+	if keepComments {
+		sb.WriteString(leadingComment(o, prefix))
+	}
 	sb.WriteString(fmt.Sprintf("%soneof %s {\n", prefix, o.Name()))
 
 	// Fields (sorted by number)
@@ -515,10 +749,13 @@ func normalizeOneof(o protoreflect.OneofDescriptor, indent int) string {
 	fields := make([]fieldInfo, 0, o.Fields().Len())
 	for i := 0; i < o.Fields().Len(); i++ {
 		f := o.Fields().Get(i)
-		typeName := protoTypeName(f)
+		text := fmt.Sprintf("%s  %s = %d%s;\n", prefix, fieldTypeAndName(f), f.Number(), fieldOptions(f))
+		if keepComments {
+			text = leadingComment(f, prefix+"  ") + text
+		}
 		fields = append(fields, fieldInfo{
 			number: int(f.Number()),
-			text:   fmt.Sprintf("%s  %s %s = %d;\n", prefix, typeName, f.Name(), f.Number()),
+			text:   text,
 		})
 	}
 	sort.Slice(fields, func(i, j int) bool {
@@ -533,9 +770,12 @@ func normalizeOneof(o protoreflect.OneofDescriptor, indent int) string {
 }
 
 // normalizeService normalizes a service descriptor.
-func normalizeService(s protoreflect.ServiceDescriptor) string {
+func normalizeService(s protoreflect.ServiceDescriptor, keepComments bool) string {
 	var sb strings.Builder
 
+	if keepComments {
+		sb.WriteString(leadingComment(s, ""))
+	}
 	sb.WriteString(fmt.Sprintf("service %s {\n", s.Name()))
 
 	// Methods (sorted by name)
@@ -564,3 +804,18 @@ func normalizeService(s protoreflect.ServiceDescriptor) string {
 
 // Ensure Parser implements schema.Parser
 var _ schema.Parser = (*Parser)(nil)
+
+// leadingComment returns the leading comment attached to a descriptor,
+// rendered as "//" lines with the given indentation, or "" if there is none.
+func leadingComment(d protoreflect.Descriptor, prefix string) string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	comment := strings.TrimSpace(loc.LeadingComments)
+	if comment == "" {
+		return ""
+	}
+	var sb strings.Builder
+	for _, line := range strings.Split(comment, "\n") {
+		sb.WriteString(prefix + "// " + strings.TrimSpace(line) + "\n")
+	}
+	return sb.String()
+}
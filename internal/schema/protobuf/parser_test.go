@@ -1,9 +1,15 @@
 package protobuf
 
 import (
+	"encoding/base64"
 	"strings"
 	"testing"
 
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/axonops/axonops-schema-registry/internal/schema"
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
@@ -273,6 +279,113 @@ message LegacyMessage {
 	}
 }
 
+func TestParser_Parse_Proto2GroupsDefaultsExtensions(t *testing.T) {
+	parser := NewParser()
+
+	schema := `
+syntax = "proto2";
+package legacy;
+
+message Order {
+  required string id = 1;
+  optional int32 priority = 2 [default = 5];
+  repeated group Item = 3 {
+    optional string sku = 1;
+  }
+  extensions 100 to 199;
+}
+
+extend Order {
+  optional string channel = 100;
+}
+`
+
+	parsed, err := parser.Parse(schema, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	canonical := parsed.CanonicalString()
+	for _, want := range []string{
+		"optional int32 priority = 2 [default = 5];",
+		"repeated group Item = 3;",
+		"message Item {",
+		"extensions 100 to 199;",
+		"extend legacy.Order {\n  optional string channel = 100;\n}",
+	} {
+		if !strings.Contains(canonical, want) {
+			t.Errorf("Canonical form should contain %q: %s", want, canonical)
+		}
+	}
+
+	// A changed default must change the fingerprint.
+	changed, err := parser.Parse(strings.Replace(schema, "default = 5", "default = 6", 1), nil)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	if changed.Fingerprint() == parsed.Fingerprint() {
+		t.Error("Changing a default value should change the fingerprint")
+	}
+}
+
+func TestParser_Parse_Editions(t *testing.T) {
+	parser := NewParser()
+
+	schema := `
+edition = "2023";
+package orders;
+
+message Order {
+  string id = 1 [features.field_presence = LEGACY_REQUIRED];
+  int32 quantity = 2 [features.field_presence = IMPLICIT];
+  string note = 3;
+  Order parent = 4 [features.message_encoding = DELIMITED];
+  repeated int32 codes = 5 [features.repeated_field_encoding = EXPANDED];
+}
+`
+
+	parsed, err := parser.Parse(schema, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	canonical := parsed.CanonicalString()
+	for _, want := range []string{
+		`edition = "2023";`,
+		"string id = 1 [features.field_presence = LEGACY_REQUIRED];",
+		"int32 quantity = 2 [features.field_presence = IMPLICIT];",
+		"string note = 3;",
+		"orders.Order parent = 4 [features.message_encoding = DELIMITED];",
+		"repeated int32 codes = 5 [features.repeated_field_encoding = EXPANDED];",
+	} {
+		if !strings.Contains(canonical, want) {
+			t.Errorf("Canonical form should contain %q: %s", want, canonical)
+		}
+	}
+
+	// The same fields under proto2 are a different schema.
+	proto2, err := parser.Parse(`
+syntax = "proto2";
+package orders;
+
+message Order {
+  required string id = 1;
+  optional int32 quantity = 2;
+  optional string note = 3;
+}
+`, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	if proto2.Fingerprint() == parsed.Fingerprint() {
+		t.Error("Editions and proto2 schemas should not share a fingerprint")
+	}
+
+	if parsed.(*ParsedProtobuf).FormattedString("serialized") == "" {
+		t.Error("Expected a serialized descriptor for an editions schema")
+	}
+}
+
 func TestParser_Parse_WellKnownTypes(t *testing.T) {
 	parser := NewParser()
 
@@ -993,3 +1106,135 @@ func TestResolver_WithReferencesAndSchema_EmptyContentRef(t *testing.T) {
 		t.Error("Expected error for reference with empty content")
 	}
 }
+
+func TestParsedProtobuf_NormalizeWithProfile_KeepComments(t *testing.T) {
+	parser := NewParser()
+
+	schemaStr := `syntax = "proto3";
+package test;
+
+// A user of the system.
+message User {
+  // Unique identifier.
+  int64 id = 1;
+}`
+
+	parsed, err := parser.Parse(schemaStr, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if strings.Contains(parsed.Normalize().CanonicalString(), "Unique identifier") {
+		t.Error("Expected default profile to strip comments")
+	}
+
+	keep := schema.NormalizationProfile{Name: "keep", ProtobufKeepComments: true}
+	canonical := parsed.NormalizeWithProfile(keep).CanonicalString()
+	for _, comment := range []string{"// A user of the system.", "// Unique identifier."} {
+		if !strings.Contains(canonical, comment) {
+			t.Errorf("Expected %q to be kept, got:\n%s", comment, canonical)
+		}
+	}
+}
+
+func TestParser_Parse_BundledWellKnownImports(t *testing.T) {
+	parser := NewParser()
+
+	schemaStr := `syntax = "proto3";
+package test;
+
+import "google/type/date.proto";
+import "google/type/money.proto";
+import "confluent/meta.proto";
+import "confluent/type/decimal.proto";
+
+message Invoice {
+  google.type.Date due = 1;
+  google.type.Money total = 2 [(confluent.field_meta).doc = "Invoice total"];
+  confluent.type.Decimal tax_rate = 3;
+}`
+
+	parsed, err := parser.Parse(schemaStr, nil)
+	if err != nil {
+		t.Fatalf("Parse failed for bundled imports: %v", err)
+	}
+	if parsed.Type() != storage.SchemaTypeProtobuf {
+		t.Errorf("Expected PROTOBUF type, got %s", parsed.Type())
+	}
+}
+
+func TestParsedProtobuf_RecordName(t *testing.T) {
+	parser := NewParser()
+
+	parsed, err := parser.Parse(`
+syntax = "proto3";
+package com.example;
+
+message Order {
+  string id = 1;
+}
+
+message Item {
+  string sku = 1;
+}
+`, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	if got := parsed.RecordName(); got != "com.example.Order" {
+		t.Errorf("RecordName() = %q, want com.example.Order", got)
+	}
+}
+
+func TestParsedProtobuf_FormattedString_Resolved(t *testing.T) {
+	parser := NewParser()
+	addressSchema := `
+syntax = "proto3";
+package types;
+import "google/protobuf/timestamp.proto";
+message Address {
+  string street = 1;
+  google.protobuf.Timestamp verified_at = 2;
+}
+`
+	mainSchema := `
+syntax = "proto3";
+package orders;
+import "address.proto";
+message Order {
+  string id = 1;
+  types.Address shipping = 2;
+}
+`
+	parsed, err := parser.Parse(mainSchema, []storage.Reference{
+		{Name: "address.proto", Subject: "address-value", Version: 1, Schema: addressSchema},
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(parsed.FormattedString("resolved"))
+	if err != nil {
+		t.Fatalf("expected base64 output: %v", err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		t.Fatalf("expected a FileDescriptorSet: %v", err)
+	}
+	var names []string
+	for _, f := range set.File {
+		names = append(names, f.GetName())
+	}
+	if want := []string{"google/protobuf/timestamp.proto", "address.proto", "schema.proto"}; strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+
+	// The set is self-contained: it links without any other files.
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		t.Fatalf("resolved set does not link: %v", err)
+	}
+	if _, err := files.FindDescriptorByName("orders.Order"); err != nil {
+		t.Errorf("orders.Order not found: %v", err)
+	}
+}
@@ -153,15 +153,6 @@ message FieldMask {
 	}
 }
 
-// notFoundResolver is a resolver that always returns not-found. It is used as
-// the inner resolver for protocompile.WithStandardImports so that standard
-// imports are always provided from the real protobuf definitions.
-type notFoundResolver struct{}
-
-func (notFoundResolver) FindFileByPath(path string) (protocompile.SearchResult, error) {
-	return protocompile.SearchResult{}, &fileNotFoundError{path: path}
-}
-
 // Ensure referenceResolver implements the required interface
 var _ protocompile.Resolver = (*referenceResolver)(nil)
 
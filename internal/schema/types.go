@@ -26,15 +26,97 @@ type ParsedSchema interface {
 
 	// Normalize returns a normalized copy of this schema with deterministic
 	// representation for deduplication and comparison purposes.
+	// It is equivalent to NormalizeWithProfile(DefaultNormalizationProfile).
 	Normalize() ParsedSchema
 
+	// NormalizeWithProfile returns a normalized copy of this schema using the
+	// given profile. The CanonicalString and Fingerprint of the returned schema
+	// reflect the profile. Options that do not apply to the schema type are ignored.
+	NormalizeWithProfile(profile NormalizationProfile) ParsedSchema
+
 	// HasTopLevelField reports whether the schema contains a top-level field
 	// with the given name. For Avro records this checks record fields, for
 	// Protobuf it checks fields across all top-level messages, and for JSON
 	// Schema it checks the "properties" object.
 	HasTopLevelField(field string) bool
+
+	// RecordName returns the fully-qualified name of the schema's top-level
+	// record, as used by the RecordNameStrategy and TopicRecordNameStrategy
+	// subject naming strategies. For Avro this is the full name of a named
+	// schema, for Protobuf the full name of the first message, and for JSON
+	// Schema the "title" keyword. Returns "" when the schema has no name.
+	RecordName() string
+}
+
+// PayloadValidator is implemented by parsed schemas that can check a
+// document against themselves. Only JSON Schema implements it.
+type PayloadValidator interface {
+	// ValidatePayload returns every problem found in doc, a value decoded
+	// from JSON, or nil if doc is valid.
+	ValidatePayload(doc interface{}) []PayloadError
+}
+
+// SourceProvider is implemented by parsed schemas that return the schema
+// string to store, which the parser may have rewritten from the one it was
+// given. Protobuf returns a schema given as a descriptor as .proto source.
+type SourceProvider interface {
+	// Raw returns the schema string to store.
+	Raw() string
+}
+
+// ParsingFingerprinter is implemented by parsed schemas that have an Avro
+// Parsing Canonical Form. Only Avro implements it.
+type ParsingFingerprinter interface {
+	// ParsingFingerprint64 returns the CRC-64-AVRO (Rabin) fingerprint of
+	// the Parsing Canonical Form, the one Avro single-object encoding
+	// identifies the writer schema by.
+	ParsingFingerprint64() uint64
+}
+
+// JSONSchemaConverter is implemented by parsed schemas that can describe
+// their values as a JSON Schema. Avro and Protobuf implement it.
+type JSONSchemaConverter interface {
+	// ToJSONSchema returns a JSON Schema document, of draft JSONSchemaDraft,
+	// describing the JSON form of the schema's values.
+	ToJSONSchema() (string, error)
 }
 
+// JSONSchemaDraft is the $schema of JSON Schemas converted from other
+// schema types.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// PayloadError is a problem found when validating a document against a schema.
+type PayloadError struct {
+	// InstanceLocation is the JSON pointer of the offending value in the
+	// document; "" is the document itself.
+	InstanceLocation string
+	// KeywordLocation is the path to the keyword the value failed, from the
+	// schema's root and through any $ref followed on the way.
+	KeywordLocation string
+	// Message describes the problem.
+	Message string
+}
+
+// NormalizationProfile selects how schemas are canonicalized when normalization
+// is enabled. Ecosystems disagree about what "canonical" means, so the choices
+// that affect deduplication are configurable. The zero value is the default
+// profile and matches the registry's built-in normalization.
+type NormalizationProfile struct {
+	// Name identifies the profile in configuration and logs.
+	Name string
+
+	// JSONStripAnnotations removes the annotation keywords "title",
+	// "description", "examples" and "$comment" from JSON Schemas.
+	JSONStripAnnotations bool
+
+	// ProtobufKeepComments keeps leading comments on messages, fields, enums,
+	// enum values and services instead of stripping them.
+	ProtobufKeepComments bool
+}
+
+// DefaultNormalizationProfile is the profile used when none is configured.
+var DefaultNormalizationProfile = NormalizationProfile{Name: "default"}
+
 // Parser is the interface for schema parsers.
 type Parser interface {
 	// Parse parses a schema string.
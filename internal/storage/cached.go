@@ -0,0 +1,327 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CacheMetricsRecorder is the interface that the cached storage wrapper uses
+// to record cache hits, misses and size.
+type CacheMetricsRecorder interface {
+	RecordCacheAccess(cache string, hit bool)
+	UpdateCacheSize(cache string, size float64)
+}
+
+// Cache names reported to the CacheMetricsRecorder.
+const (
+	cacheSchemaByID      = "schema_by_id"
+	cacheSchemaByVersion = "schema_by_version"
+	cacheLatestSchema    = "latest_schema"
+	cacheConfig          = "config"
+)
+
+// cacheKey identifies a cached read. Only the fields relevant to kind are set.
+type cacheKey struct {
+	kind        string
+	registryCtx string
+	subject     string
+	id          int64
+	version     int
+}
+
+// cacheEntry is a cached read result. err is only set for cached ErrNotFound
+// config lookups, and stamp only for latest schemas.
+type cacheEntry struct {
+	key        cacheKey
+	value      any
+	err        error
+	stamp      string
+	generation uint64
+	expires    time.Time
+}
+
+// generationKey groups the entries invalidated together by a write: the
+// schemas or the configs of one registry context.
+type generationKey struct {
+	registryCtx string
+	configs     bool
+}
+
+// CachedStorage wraps a Storage implementation with an in-process LRU cache
+// for the hottest reads: schemas by ID, schemas by subject and version, the
+// latest schema of each subject, and subject configs. Entries expire after a
+// TTL and the cache holds at most maxEntries entries.
+//
+// Writes made through the wrapper invalidate the affected entries at once:
+// any schema write invalidates the cached schemas of its context, and any
+// config write the cached configs of its context. Writes made by other
+// instances are only seen when entries expire, so the TTL bounds how stale a
+// read can be in a cluster.
+//
+// Latest schemas are the exception: serializers that auto-register break on
+// a stale "latest", so each cached latest schema is checked against the
+// backend's GetLatestSchemaStamp before it is served. The stamp is written
+// with the version itself, so a read never returns an older latest version
+// than the backend holds, whichever instance registered it.
+type CachedStorage struct {
+	Storage
+	maxEntries int
+	ttl        time.Duration
+	recorder   CacheMetricsRecorder
+	now        func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[cacheKey]*list.Element
+	sizes   map[string]int // entries per cache kind
+	// generations is bumped by every write; entries stored under an older
+	// generation are stale. Reads capture the generation before querying
+	// storage so a result racing with a write is never served.
+	generations map[generationKey]uint64
+}
+
+// NewCachedStorage creates a new CachedStorage that caches up to maxEntries
+// reads from store for ttl. recorder may be nil.
+func NewCachedStorage(store Storage, maxEntries int, ttl time.Duration, recorder CacheMetricsRecorder) *CachedStorage {
+	return &CachedStorage{
+		Storage:     store,
+		maxEntries:  maxEntries,
+		ttl:         ttl,
+		recorder:    recorder,
+		now:         time.Now,
+		lru:         list.New(),
+		entries:     make(map[cacheKey]*list.Element),
+		sizes:       make(map[string]int),
+		generations: make(map[generationKey]uint64),
+	}
+}
+
+// get returns the cached entry for key stored with stamp, or false with the
+// generation to pass to put after reading from storage.
+func (s *CachedStorage) get(key cacheKey, gen generationKey, stamp string) (*cacheEntry, uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.generations[gen]
+	if el, ok := s.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		if entry.generation == current && entry.stamp == stamp && s.now().Before(entry.expires) {
+			s.lru.MoveToFront(el)
+			s.recordAccess(key.kind, true)
+			return entry, current, true
+		}
+		s.remove(el)
+	}
+	s.recordAccess(key.kind, false)
+	return nil, current, false
+}
+
+// put stores a read result unless a write invalidated it in the meantime,
+// evicting the least recently used entry if the cache is full.
+func (s *CachedStorage) put(key cacheKey, gen generationKey, generation uint64, value any, err error, stamp string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.generations[gen] != generation {
+		return
+	}
+	entry := &cacheEntry{key: key, value: value, err: err, stamp: stamp, generation: generation, expires: s.now().Add(s.ttl)}
+	if el, ok := s.entries[key]; ok {
+		el.Value = entry
+		s.lru.MoveToFront(el)
+		return
+	}
+	s.entries[key] = s.lru.PushFront(entry)
+	s.resize(key.kind, 1)
+	for s.lru.Len() > s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+}
+
+// remove drops a cached entry. Callers must hold mu.
+func (s *CachedStorage) remove(el *list.Element) {
+	key := el.Value.(*cacheEntry).key
+	s.lru.Remove(el)
+	delete(s.entries, key)
+	s.resize(key.kind, -1)
+}
+
+// resize adjusts and reports the number of entries in a cache. Callers must
+// hold mu.
+func (s *CachedStorage) resize(cache string, delta int) {
+	s.sizes[cache] += delta
+	if s.recorder != nil {
+		s.recorder.UpdateCacheSize(cache, float64(s.sizes[cache]))
+	}
+}
+
+// invalidate drops the cached schemas or configs of a registry context.
+func (s *CachedStorage) invalidate(registryCtx string, configs bool) {
+	s.mu.Lock()
+	s.generations[generationKey{registryCtx, configs}]++
+	s.mu.Unlock()
+}
+
+// invalidateContext drops everything cached for a registry context.
+func (s *CachedStorage) invalidateContext(registryCtx string) {
+	s.invalidate(registryCtx, false)
+	s.invalidate(registryCtx, true)
+}
+
+func (s *CachedStorage) recordAccess(cache string, hit bool) {
+	if s.recorder != nil {
+		s.recorder.RecordCacheAccess(cache, hit)
+	}
+}
+
+// --- Cached reads ---
+
+func (s *CachedStorage) GetSchemaByID(ctx context.Context, registryCtx string, id int64) (*SchemaRecord, error) {
+	key := cacheKey{kind: cacheSchemaByID, registryCtx: registryCtx, id: id}
+	gen := generationKey{registryCtx: registryCtx}
+	entry, generation, ok := s.get(key, gen, "")
+	if ok {
+		rec := *entry.value.(*SchemaRecord)
+		return &rec, nil
+	}
+	rec, err := s.Storage.GetSchemaByID(ctx, registryCtx, id)
+	if err == nil {
+		cached := *rec
+		s.put(key, gen, generation, &cached, nil, "")
+	}
+	return rec, err
+}
+
+// GetSchemaBySubjectVersion serves "latest" (-1) through GetLatestSchema.
+func (s *CachedStorage) GetSchemaBySubjectVersion(ctx context.Context, registryCtx string, subject string, version int) (*SchemaRecord, error) {
+	if version == -1 {
+		return s.GetLatestSchema(ctx, registryCtx, subject)
+	}
+	if version <= 0 {
+		return s.Storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	}
+	key := cacheKey{kind: cacheSchemaByVersion, registryCtx: registryCtx, subject: subject, version: version}
+	gen := generationKey{registryCtx: registryCtx}
+	entry, generation, ok := s.get(key, gen, "")
+	if ok {
+		rec := *entry.value.(*SchemaRecord)
+		return &rec, nil
+	}
+	rec, err := s.Storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err == nil {
+		cached := *rec
+		s.put(key, gen, generation, &cached, nil, "")
+	}
+	return rec, err
+}
+
+// GetLatestSchema reads the subject's latest stamp from storage on every call
+// and serves the cached schema only if it was stored under the same stamp.
+// The stamp read replaces loading the schema, its ID and its references.
+func (s *CachedStorage) GetLatestSchema(ctx context.Context, registryCtx string, subject string) (*SchemaRecord, error) {
+	stamp, err := s.Storage.GetLatestSchemaStamp(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey{kind: cacheLatestSchema, registryCtx: registryCtx, subject: subject}
+	gen := generationKey{registryCtx: registryCtx}
+	entry, generation, ok := s.get(key, gen, stamp)
+	if ok {
+		rec := *entry.value.(*SchemaRecord)
+		return &rec, nil
+	}
+	rec, err := s.Storage.GetLatestSchema(ctx, registryCtx, subject)
+	if err == nil {
+		// A registration between the two reads may make rec newer than
+		// stamp. The entry is then never served, since the next stamp read
+		// returns the newer stamp.
+		cached := *rec
+		s.put(key, gen, generation, &cached, nil, stamp)
+	}
+	return rec, err
+}
+
+// GetConfig also caches ErrNotFound, since most subjects have no config of
+// their own and fall back to the global config on every lookup.
+func (s *CachedStorage) GetConfig(ctx context.Context, registryCtx string, subject string) (*ConfigRecord, error) {
+	key := cacheKey{kind: cacheConfig, registryCtx: registryCtx, subject: subject}
+	gen := generationKey{registryCtx: registryCtx, configs: true}
+	entry, generation, ok := s.get(key, gen, "")
+	if ok {
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		cfg := *entry.value.(*ConfigRecord)
+		return &cfg, nil
+	}
+	cfg, err := s.Storage.GetConfig(ctx, registryCtx, subject)
+	switch {
+	case err == nil:
+		cached := *cfg
+		s.put(key, gen, generation, &cached, nil, "")
+	case errors.Is(err, ErrNotFound):
+		s.put(key, gen, generation, nil, err, "")
+	}
+	return cfg, err
+}
+
+// --- Schema writes ---
+
+func (s *CachedStorage) CreateSchema(ctx context.Context, registryCtx string, record *SchemaRecord) error {
+	defer s.invalidate(registryCtx, false)
+	return s.Storage.CreateSchema(ctx, registryCtx, record)
+}
+
+func (s *CachedStorage) DeleteSchema(ctx context.Context, registryCtx string, subject string, version int, permanent bool) error {
+	defer s.invalidate(registryCtx, false)
+	return s.Storage.DeleteSchema(ctx, registryCtx, subject, version, permanent)
+}
+
+func (s *CachedStorage) RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error {
+	defer s.invalidate(registryCtx, false)
+	return s.Storage.RewriteSchemaText(ctx, registryCtx, id, schemaText)
+}
+
+// DeleteSubject also invalidates configs, since a permanent delete removes
+// the subject's config.
+func (s *CachedStorage) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
+	defer s.invalidateContext(registryCtx)
+	return s.Storage.DeleteSubject(ctx, registryCtx, subject, permanent)
+}
+
+func (s *CachedStorage) ImportSchema(ctx context.Context, registryCtx string, record *SchemaRecord) error {
+	defer s.invalidate(registryCtx, false)
+	return s.Storage.ImportSchema(ctx, registryCtx, record)
+}
+
+// --- Config writes ---
+
+func (s *CachedStorage) SetConfig(ctx context.Context, registryCtx string, subject string, config *ConfigRecord) error {
+	defer s.invalidate(registryCtx, true)
+	return s.Storage.SetConfig(ctx, registryCtx, subject, config)
+}
+
+func (s *CachedStorage) DeleteConfig(ctx context.Context, registryCtx string, subject string) error {
+	defer s.invalidate(registryCtx, true)
+	return s.Storage.DeleteConfig(ctx, registryCtx, subject)
+}
+
+func (s *CachedStorage) SetGlobalConfig(ctx context.Context, registryCtx string, config *ConfigRecord) error {
+	defer s.invalidate(registryCtx, true)
+	return s.Storage.SetGlobalConfig(ctx, registryCtx, config)
+}
+
+func (s *CachedStorage) DeleteGlobalConfig(ctx context.Context, registryCtx string) error {
+	defer s.invalidate(registryCtx, true)
+	return s.Storage.DeleteGlobalConfig(ctx, registryCtx)
+}
+
+// --- Context writes ---
+
+func (s *CachedStorage) DeleteContext(ctx context.Context, name string) error {
+	defer s.invalidateContext(name)
+	return s.Storage.DeleteContext(ctx, name)
+}
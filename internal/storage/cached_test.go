@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// countingStorage serves canned schemas and configs and counts reads.
+type countingStorage struct {
+	Storage
+	reads   map[string]int
+	configs map[string]*ConfigRecord
+	latest  int
+}
+
+func newCountingStorage() *countingStorage {
+	return &countingStorage{reads: make(map[string]int), configs: make(map[string]*ConfigRecord)}
+}
+
+func (s *countingStorage) GetSchemaByID(ctx context.Context, registryCtx string, id int64) (*SchemaRecord, error) {
+	s.reads["id"]++
+	if id == 404 {
+		return nil, ErrSchemaNotFound
+	}
+	return &SchemaRecord{ID: id, Schema: `"string"`}, nil
+}
+
+func (s *countingStorage) GetSchemaBySubjectVersion(ctx context.Context, registryCtx string, subject string, version int) (*SchemaRecord, error) {
+	s.reads["version"]++
+	return &SchemaRecord{ID: 1, Subject: subject, Version: version}, nil
+}
+
+func (s *countingStorage) GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error) {
+	s.reads["stamp"]++
+	if s.latest == 0 {
+		return "", ErrSubjectNotFound
+	}
+	return fmt.Sprint(s.latest), nil
+}
+
+func (s *countingStorage) GetLatestSchema(ctx context.Context, registryCtx string, subject string) (*SchemaRecord, error) {
+	s.reads["latest"]++
+	return &SchemaRecord{ID: 1, Subject: subject, Version: s.latest}, nil
+}
+
+func (s *countingStorage) CreateSchema(ctx context.Context, registryCtx string, record *SchemaRecord) error {
+	return nil
+}
+
+func (s *countingStorage) GetConfig(ctx context.Context, registryCtx string, subject string) (*ConfigRecord, error) {
+	s.reads["config"]++
+	if cfg, ok := s.configs[subject]; ok {
+		return cfg, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *countingStorage) SetConfig(ctx context.Context, registryCtx string, subject string, config *ConfigRecord) error {
+	s.configs[subject] = config
+	return nil
+}
+
+type cacheAccess struct {
+	hits, misses int
+	sizes        map[string]float64
+}
+
+func (r *cacheAccess) RecordCacheAccess(cache string, hit bool) {
+	if hit {
+		r.hits++
+	} else {
+		r.misses++
+	}
+}
+
+func (r *cacheAccess) UpdateCacheSize(cache string, size float64) {
+	r.sizes[cache] = size
+}
+
+func TestCachedStorage_ServesRepeatedReads(t *testing.T) {
+	backend := newCountingStorage()
+	metrics := &cacheAccess{sizes: make(map[string]float64)}
+	store := NewCachedStorage(backend, 100, time.Minute, metrics)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		rec, err := store.GetSchemaByID(ctx, ".", 1)
+		if err != nil || rec.ID != 1 {
+			t.Fatalf("GetSchemaByID = %+v, %v", rec, err)
+		}
+		// Callers may modify the returned record without affecting the cache.
+		rec.Schema = "modified"
+	}
+	if rec, _ := store.GetSchemaByID(ctx, ".", 1); rec.Schema != `"string"` {
+		t.Errorf("cached record was modified: %q", rec.Schema)
+	}
+	if backend.reads["id"] != 1 {
+		t.Errorf("backend reads = %d, want 1", backend.reads["id"])
+	}
+	// The same ID in another context is a different schema.
+	store.GetSchemaByID(ctx, ".team", 1)
+	if backend.reads["id"] != 2 {
+		t.Errorf("backend reads = %d, want 2", backend.reads["id"])
+	}
+	if metrics.hits != 3 || metrics.misses != 2 {
+		t.Errorf("hits/misses = %d/%d, want 3/2", metrics.hits, metrics.misses)
+	}
+	if metrics.sizes[cacheSchemaByID] != 2 {
+		t.Errorf("size = %v, want 2", metrics.sizes[cacheSchemaByID])
+	}
+}
+
+func TestCachedStorage_DoesNotCacheMisses(t *testing.T) {
+	backend := newCountingStorage()
+	store := NewCachedStorage(backend, 100, time.Minute, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := store.GetSchemaByID(ctx, ".", 404); !errors.Is(err, ErrSchemaNotFound) {
+			t.Fatalf("err = %v, want ErrSchemaNotFound", err)
+		}
+		if _, err := store.GetLatestSchema(ctx, ".", "orders"); !errors.Is(err, ErrSubjectNotFound) {
+			t.Fatalf("err = %v, want ErrSubjectNotFound", err)
+		}
+	}
+	if backend.reads["id"] != 2 || backend.reads["stamp"] != 2 {
+		t.Errorf("backend reads = %v, want 2 each", backend.reads)
+	}
+}
+
+func TestCachedStorage_LatestValidatesStamp(t *testing.T) {
+	backend := newCountingStorage()
+	backend.latest = 1
+	store := NewCachedStorage(backend, 100, time.Minute, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		rec, err := store.GetSchemaBySubjectVersion(ctx, ".", "orders", -1)
+		if err != nil || rec.Version != 1 {
+			t.Fatalf("latest = %+v, %v", rec, err)
+		}
+	}
+	if backend.reads["latest"] != 1 || backend.reads["stamp"] != 3 {
+		t.Errorf("backend reads = %v, want 1 latest and 3 stamp reads", backend.reads)
+	}
+
+	// A registration that bypasses this cache changes the stamp.
+	backend.latest = 2
+	if rec, _ := store.GetLatestSchema(ctx, ".", "orders"); rec.Version != 2 {
+		t.Errorf("stale latest served: version %d, want 2", rec.Version)
+	}
+	if backend.reads["latest"] != 2 {
+		t.Errorf("latest reads = %d, want 2", backend.reads["latest"])
+	}
+}
+
+func TestCachedStorage_TTLAndEviction(t *testing.T) {
+	backend := newCountingStorage()
+	store := NewCachedStorage(backend, 2, time.Minute, nil)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.GetSchemaBySubjectVersion(ctx, ".", "orders", 1)
+	store.GetSchemaBySubjectVersion(ctx, ".", "orders", 2)
+	store.GetSchemaBySubjectVersion(ctx, ".", "orders", 1) // hit; version 2 is now least recently used
+	store.GetSchemaBySubjectVersion(ctx, ".", "orders", 3) // evicts version 2
+	store.GetSchemaBySubjectVersion(ctx, ".", "orders", 1) // hit
+	if backend.reads["version"] != 3 {
+		t.Fatalf("backend reads = %d, want 3", backend.reads["version"])
+	}
+	store.GetSchemaBySubjectVersion(ctx, ".", "orders", 2)
+	if backend.reads["version"] != 4 {
+		t.Errorf("evicted entry served from cache: reads = %d, want 4", backend.reads["version"])
+	}
+
+	now = now.Add(2 * time.Minute)
+	store.GetSchemaBySubjectVersion(ctx, ".", "orders", 2)
+	if backend.reads["version"] != 5 {
+		t.Errorf("expired entry served from cache: reads = %d, want 5", backend.reads["version"])
+	}
+}
+
+func TestCachedStorage_WritesInvalidate(t *testing.T) {
+	backend := newCountingStorage()
+	store := NewCachedStorage(backend, 100, time.Minute, nil)
+	ctx := context.Background()
+
+	// A missing subject config is cached too.
+	for i := 0; i < 2; i++ {
+		if _, err := store.GetConfig(ctx, ".", "orders"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+	}
+	if backend.reads["config"] != 1 {
+		t.Fatalf("backend reads = %d, want 1", backend.reads["config"])
+	}
+	if err := store.SetConfig(ctx, ".", "orders", &ConfigRecord{Subject: "orders", CompatibilityLevel: "FULL"}); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	cfg, err := store.GetConfig(ctx, ".", "orders")
+	if err != nil || cfg.CompatibilityLevel != "FULL" {
+		t.Errorf("GetConfig after SetConfig = %+v, %v", cfg, err)
+	}
+
+	store.GetSchemaByID(ctx, ".", 1)
+	store.GetSchemaByID(ctx, ".team", 1)
+	if err := store.CreateSchema(ctx, ".", &SchemaRecord{}); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+	store.GetSchemaByID(ctx, ".", 1)
+	store.GetSchemaByID(ctx, ".team", 1)
+	if backend.reads["id"] != 3 {
+		t.Errorf("backend reads = %d, want 3 (only the written context is invalidated)", backend.reads["id"])
+	}
+	// Schema writes leave cached configs alone.
+	store.GetConfig(ctx, ".", "orders")
+	if backend.reads["config"] != 2 {
+		t.Errorf("config reads = %d, want 2", backend.reads["config"])
+	}
+}
@@ -0,0 +1,160 @@
+package cassandra
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	gocql "github.com/apache/cassandra-gocql-driver/v2"
+)
+
+// Host selection policies.
+const (
+	// HostSelectionTokenAware sends each query to a replica of its
+	// partition, picked among the hosts of the dc_aware policy when LocalDC
+	// is set and of the round_robin policy otherwise.
+	HostSelectionTokenAware = "token_aware"
+	// HostSelectionDCAware prefers the hosts of LocalDC and fails over to
+	// the other datacenters when none is up.
+	HostSelectionDCAware = "dc_aware"
+	// HostSelectionRoundRobin spreads queries over every host.
+	HostSelectionRoundRobin = "round_robin"
+)
+
+// Query retry policies.
+const (
+	// RetrySimple retries a failed query right away on the next host.
+	RetrySimple = "simple"
+	// RetryExponential retries a failed query after a backoff that doubles
+	// from MinBackoff up to MaxBackoff.
+	RetryExponential = "exponential"
+)
+
+// TLS configures encrypted connections to the Cassandra nodes.
+type TLS struct {
+	// Enabled turns TLS on. It is implied by any of the files.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// CAFile verifies the nodes' certificates. Default: the system roots.
+	CAFile string `json:"ca_file" yaml:"ca_file"`
+	// CertFile and KeyFile are the client certificate, for clusters that
+	// require client authentication.
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+	// ServerName is the name the nodes' certificates are verified against.
+	// Default: the host each connection is made to.
+	ServerName string `json:"server_name" yaml:"server_name"`
+	// InsecureSkipVerify skips the verification of the nodes' certificates.
+	InsecureSkipVerify bool `json:"insecure_skip_verify" yaml:"insecure_skip_verify"`
+}
+
+func (t TLS) enabled() bool {
+	return t.Enabled || t.CAFile != "" || t.CertFile != "" || t.KeyFile != ""
+}
+
+// Retry configures how queries that fail on one host are retried on others.
+type Retry struct {
+	// Policy is RetrySimple or RetryExponential. Default: no retries.
+	Policy string `json:"policy" yaml:"policy"`
+	// NumRetries is the number of retries of a query. Default: 3.
+	NumRetries int `json:"num_retries" yaml:"num_retries"`
+	// MinBackoff and MaxBackoff bound the backoff of RetryExponential.
+	// Defaults: 100ms and 10s.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff"`
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff"`
+}
+
+// hostSelectionPolicy returns the host selection policy of the cluster, nil
+// for the driver's default.
+func hostSelectionPolicy(policy, localDC string) (gocql.HostSelectionPolicy, error) {
+	fallback := func() gocql.HostSelectionPolicy {
+		if localDC != "" {
+			return gocql.DCAwareRoundRobinPolicy(localDC)
+		}
+		return gocql.RoundRobinHostPolicy()
+	}
+	switch policy {
+	case "":
+		if localDC != "" {
+			return gocql.DCAwareRoundRobinPolicy(localDC), nil
+		}
+		return nil, nil
+	case HostSelectionTokenAware:
+		return gocql.TokenAwareHostPolicy(fallback(), gocql.ShuffleReplicas()), nil
+	case HostSelectionDCAware:
+		if localDC == "" {
+			return nil, fmt.Errorf("cassandra host selection policy %s requires a local datacenter", HostSelectionDCAware)
+		}
+		return gocql.DCAwareRoundRobinPolicy(localDC), nil
+	case HostSelectionRoundRobin:
+		return gocql.RoundRobinHostPolicy(), nil
+	}
+	return nil, fmt.Errorf("invalid cassandra host selection policy: %q (must be %s, %s or %s)",
+		policy, HostSelectionTokenAware, HostSelectionDCAware, HostSelectionRoundRobin)
+}
+
+// retryPolicy returns the query retry policy of the cluster, nil for none.
+func retryPolicy(r Retry) (gocql.RetryPolicy, error) {
+	if r.NumRetries < 0 {
+		return nil, fmt.Errorf("invalid cassandra query retries: %d", r.NumRetries)
+	}
+	numRetries := r.NumRetries
+	if numRetries == 0 {
+		numRetries = 3
+	}
+	switch r.Policy {
+	case "":
+		return nil, nil
+	case RetrySimple:
+		return &gocql.SimpleRetryPolicy{NumRetries: numRetries}, nil
+	case RetryExponential:
+		minBackoff, maxBackoff := r.MinBackoff, r.MaxBackoff
+		if minBackoff <= 0 {
+			minBackoff = 100 * time.Millisecond
+		}
+		if maxBackoff <= 0 {
+			maxBackoff = 10 * time.Second
+		}
+		if minBackoff > maxBackoff {
+			return nil, fmt.Errorf("cassandra retry min backoff %s exceeds max backoff %s", minBackoff, maxBackoff)
+		}
+		return &gocql.ExponentialBackoffRetryPolicy{NumRetries: numRetries, Min: minBackoff, Max: maxBackoff}, nil
+	}
+	return nil, fmt.Errorf("invalid cassandra retry policy: %q (must be %s or %s)", r.Policy, RetrySimple, RetryExponential)
+}
+
+// sslOptions returns the TLS options of the cluster, nil when TLS is off.
+func sslOptions(t TLS) (*gocql.SslOptions, error) {
+	if !t.enabled() {
+		return nil, nil
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, fmt.Errorf("cassandra TLS client certificate and key must be set together")
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, // #nosec G402 -- opt-in via configuration
+	}
+	if t.CAFile != "" {
+		// #nosec G304 -- CAFile is from trusted configuration
+		caCert, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassandra TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in cassandra TLS CA file %s", t.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cassandra TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &gocql.SslOptions{Config: tlsConfig, EnableHostVerification: !t.InsecureSkipVerify}, nil
+}
@@ -3,11 +3,86 @@ package cassandra
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	gocql "github.com/apache/cassandra-gocql-driver/v2"
 )
 
+// Replication strategies for the keyspace created by Migrate.
+const (
+	SimpleStrategy          = "SimpleStrategy"
+	NetworkTopologyStrategy = "NetworkTopologyStrategy"
+)
+
+// Replication configures the replication of the keyspace created by Migrate.
+// An existing keyspace is not altered.
+type Replication struct {
+	// Strategy is SimpleStrategy (default) or NetworkTopologyStrategy.
+	Strategy string `json:"strategy" yaml:"strategy"`
+	// Factor is the replication factor of SimpleStrategy. Default: 1.
+	Factor int `json:"factor" yaml:"factor"`
+	// Datacenters is the number of replicas in each datacenter with
+	// NetworkTopologyStrategy.
+	Datacenters map[string]int `json:"datacenters" yaml:"datacenters"`
+}
+
+// datacenterName matches the datacenter names allowed in the replication map.
+var datacenterName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Validate checks that the replication settings are complete and consistent.
+func (r Replication) Validate() error {
+	switch r.Strategy {
+	case "", SimpleStrategy:
+		if r.Factor < 0 {
+			return fmt.Errorf("invalid cassandra replication factor: %d", r.Factor)
+		}
+		if len(r.Datacenters) > 0 {
+			return fmt.Errorf("cassandra replication datacenters require %s", NetworkTopologyStrategy)
+		}
+	case NetworkTopologyStrategy:
+		if len(r.Datacenters) == 0 {
+			return fmt.Errorf("cassandra %s requires the replicas of at least one datacenter", NetworkTopologyStrategy)
+		}
+		for dc, n := range r.Datacenters {
+			if !datacenterName.MatchString(dc) {
+				return fmt.Errorf("invalid cassandra datacenter name: %q", dc)
+			}
+			if n <= 0 {
+				return fmt.Errorf("invalid cassandra replication factor %d for datacenter %q", n, dc)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid cassandra replication strategy: %q (must be %s or %s)", r.Strategy, SimpleStrategy, NetworkTopologyStrategy)
+	}
+	return nil
+}
+
+// cql returns the replication map of a CREATE KEYSPACE statement.
+func (r Replication) cql() (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+	if r.Strategy != NetworkTopologyStrategy {
+		factor := r.Factor
+		if factor == 0 {
+			factor = 1
+		}
+		return fmt.Sprintf(`{'class': '%s', 'replication_factor': %d}`, SimpleStrategy, factor), nil
+	}
+	dcs := make([]string, 0, len(r.Datacenters))
+	for dc := range r.Datacenters {
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+	parts := []string{fmt.Sprintf(`'class': '%s'`, NetworkTopologyStrategy)}
+	for _, dc := range dcs {
+		parts = append(parts, fmt.Sprintf(`'%s': %d`, dc, r.Datacenters[dc]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}", nil
+}
+
 // Migrate creates/updates the Cassandra schema needed by the registry.
 // This is intentionally idempotent (IF NOT EXISTS everywhere).
 //
@@ -29,12 +104,16 @@ import (
 //
 //   - All data tables include registry_ctx for multi-tenant context support.
 //     registry_ctx is part of the partition key so that queries are scoped to a single context.
-func Migrate(session *gocql.Session, keyspace string) error {
+func Migrate(session *gocql.Session, keyspace string, replication Replication) error {
+	replicationMap, err := replication.cql()
+	if err != nil {
+		return err
+	}
 	stmts := []string{
 		// Keyspace creation
 		fmt.Sprintf(`CREATE KEYSPACE IF NOT EXISTS %s
-			WITH REPLICATION = {'class': 'SimpleStrategy', 'replication_factor': 1}
-			AND durable_writes = true`, qident(keyspace)),
+			WITH REPLICATION = %s
+			AND durable_writes = true`, qident(keyspace), replicationMap),
 
 		// Table 1: schemas_by_id - lookup by context-scoped schema ID
 		// Primary lookup table for deserialization.
@@ -230,8 +309,13 @@ func Migrate(session *gocql.Session, keyspace string) error {
 
 		// Table 16: contexts - tracks all registry contexts
 		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.contexts (
-			registry_ctx text PRIMARY KEY,
-			created_at   timeuuid
+			registry_ctx    text PRIMARY KEY,
+			created_at      timeuuid,
+			description     text,
+			owner           text,
+			max_subjects    int,
+			max_schema_size int,
+			updated_at      timestamp
 		)`, qident(keyspace)),
 
 		// Table 17: keks - Key Encryption Keys for CSFLE (global, not per-context)
@@ -287,6 +371,214 @@ func Migrate(session *gocql.Session, keyspace string) error {
 			ts         bigint,
 			trace      text
 		)`, qident(keyspace)),
+
+		// Table 22: role_grants - scoped role bindings (global, not per-context)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.role_grants (
+			grant_id       bigint PRIMARY KEY,
+			username       text,
+			api_key_id     bigint,
+			role           text,
+			registry_ctx   text,
+			subject_prefix text,
+			created_by     text,
+			created_at     timestamp
+		)`, qident(keyspace)),
+
+		// Table 23: jobs - async jobs for long-running operations (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.jobs (
+			job_id           text PRIMARY KEY,
+			type             text,
+			state            text,
+			registry_ctx     text,
+			params           text,
+			result           text,
+			error            text,
+			message          text,
+			done             bigint,
+			total            bigint,
+			cancel_requested boolean,
+			created_by       text,
+			created_at       timestamp,
+			updated_at       timestamp,
+			started_at       timestamp,
+			finished_at      timestamp
+		)`, qident(keyspace)),
+
+		// Table 24: schema_usage - schema fetch counts per day, partitioned by
+		// context so a context's usage since a given day is one range read
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.schema_usage (
+			registry_ctx text,
+			usage_day    date,
+			schema_id    bigint,
+			subject      text,
+			version      int,
+			fetch_count  counter,
+			PRIMARY KEY ((registry_ctx), usage_day, schema_id, subject, version)
+		)`, qident(keyspace)),
+
+		// Table 25: share_tokens_by_id - read-only share tokens (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.share_tokens_by_id (
+			token_id     bigint PRIMARY KEY,
+			token_hash   text,
+			token_prefix text,
+			name         text,
+			registry_ctx text,
+			subject      text,
+			created_by   text,
+			created_at   timestamp,
+			expires_at   timestamp
+		)`, qident(keyspace)),
+
+		// Table 26: share_tokens_by_hash - lookup by hash for authentication (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.share_tokens_by_hash (
+			token_hash text PRIMARY KEY,
+			token_id   bigint
+		)`, qident(keyspace)),
+
+		// Table 27: tenants - tenants owning contexts and users (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.tenants (
+			name          text PRIMARY KEY,
+			description   text,
+			encrypted_key text,
+			created_at    timestamp,
+			updated_at    timestamp
+		)`, qident(keyspace)),
+
+		// Table 28: schema_tags - tags and labels on subjects (version 0) and
+		// versions, partitioned by context so search reads one partition
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.schema_tags (
+			registry_ctx text,
+			subject      text,
+			version      int,
+			tags         list<text>,
+			labels       map<text, text>,
+			updated_at   timestamp,
+			PRIMARY KEY ((registry_ctx), subject, version)
+		)`, qident(keyspace)),
+
+		// Table 29: import_sessions - imports staged until commit (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.import_sessions (
+			session_id   text PRIMARY KEY,
+			registry_ctx text,
+			subjects     list<text>,
+			state        text,
+			modes        map<text, text>,
+			schemas      text,
+			staged       int,
+			created_by   text,
+			created_at   timestamp,
+			updated_at   timestamp
+		)`, qident(keyspace)),
+
+		// Table 30: maintenance_windows - scheduled READONLY_OVERRIDE periods (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.maintenance_windows (
+			window_id  text PRIMARY KEY,
+			starts_at  timestamp,
+			ends_at    timestamp,
+			reason     text,
+			cancelled  boolean,
+			created_by text,
+			created_at timestamp,
+			updated_at timestamp
+		)`, qident(keyspace)),
+
+		// Table 31: schema_id_aliases - source registries' schema IDs mapped
+		// to local IDs, partitioned by context
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.schema_id_aliases (
+			registry_ctx text,
+			source       text,
+			source_id    bigint,
+			schema_id    bigint,
+			updated_at   timestamp,
+			PRIMARY KEY ((registry_ctx), source, source_id)
+		)`, qident(keyspace)),
+
+		// Table 32: frozen_versions - versions protected from deletion,
+		// partitioned by context
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.frozen_versions (
+			registry_ctx text,
+			subject      text,
+			version      int,
+			frozen_by    text,
+			reason       text,
+			frozen_at    timestamp,
+			PRIMARY KEY ((registry_ctx), subject, version)
+		)`, qident(keyspace)),
+
+		// Table 33: sessions_by_id - login sessions (global). Rows are
+		// written with a TTL that ends when the session expires.
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.sessions_by_id (
+			session_id   text PRIMARY KEY,
+			refresh_hash text,
+			user_id      bigint,
+			api_key_id   bigint,
+			username     text,
+			role         text,
+			tenant       text,
+			auth_method  text,
+			created_at   timestamp,
+			refreshed_at timestamp,
+			expires_at   timestamp
+		)`, qident(keyspace)),
+
+		// Table 34: sessions_by_user - sessions of each database user, to
+		// revoke them together (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.sessions_by_user (
+			user_id    bigint,
+			session_id text,
+			PRIMARY KEY ((user_id), session_id)
+		)`, qident(keyspace)),
+
+		// Table 35: pending_schemas - registrations awaiting approval (global)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.pending_schemas (
+			pending_id   text PRIMARY KEY,
+			registry_ctx text,
+			subject      text,
+			state        text,
+			request      text,
+			submitted_by text,
+			reviewed_by  text,
+			reason       text,
+			schema_id    bigint,
+			version      int,
+			created_at   timestamp,
+			updated_at   timestamp
+		)`, qident(keyspace)),
+
+		// Table 36: reader_assertions - reader schemas that consumers declare
+		// for the subjects they read (per-context)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.reader_assertions (
+			registry_ctx   text,
+			subject        text,
+			consumer       text,
+			reader_subject text,
+			reader_version int,
+			updated_at     timestamp,
+			PRIMARY KEY ((registry_ctx), subject, consumer)
+		)`, qident(keyspace)),
+
+		// Table 37: subject_meta - owner, description, contact and
+		// documentation links of subjects (per-context)
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.subject_meta (
+			registry_ctx text,
+			subject      text,
+			owner        text,
+			description  text,
+			contact      text,
+			links        text,
+			updated_at   timestamp,
+			PRIMARY KEY ((registry_ctx), subject)
+		)`, qident(keyspace)),
+
+		// Table 38: api_key_usage - API key request counts per day,
+		// partitioned by key so a key's usage since a given day is one
+		// range read
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.api_key_usage (
+			api_key_id    bigint,
+			usage_day     date,
+			request_count counter,
+			PRIMARY KEY ((api_key_id), usage_day)
+		)`, qident(keyspace)),
 	}
 
 	for _, stmt := range stmts {
@@ -310,6 +602,9 @@ func Migrate(session *gocql.Session, keyspace string) error {
 		fmt.Sprintf(`ALTER TABLE %s.subject_versions ADD metadata text`, qident(keyspace)),
 		fmt.Sprintf(`ALTER TABLE %s.subject_versions ADD ruleset text`, qident(keyspace)),
 
+		// subject_versions: soft-delete time, read by the retention purge worker
+		fmt.Sprintf(`ALTER TABLE %s.subject_versions ADD deleted_at timestamp`, qident(keyspace)),
+
 		// subject_configs: alias, normalize, and metadata/ruleset config fields
 		fmt.Sprintf(`ALTER TABLE %s.subject_configs ADD alias text`, qident(keyspace)),
 		fmt.Sprintf(`ALTER TABLE %s.subject_configs ADD normalize boolean`, qident(keyspace)),
@@ -342,6 +637,31 @@ func Migrate(session *gocql.Session, keyspace string) error {
 		fmt.Sprintf(`ALTER TABLE %s.modes ADD registry_ctx text`, qident(keyspace)),
 		fmt.Sprintf(`ALTER TABLE %s.id_alloc ADD registry_ctx text`, qident(keyspace)),
 		fmt.Sprintf(`ALTER TABLE %s.schema_fingerprints ADD registry_ctx text`, qident(keyspace)),
+
+		// contexts: administrative metadata and per-context limits
+		fmt.Sprintf(`ALTER TABLE %s.contexts ADD description text`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.contexts ADD owner text`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.contexts ADD max_subjects int`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.contexts ADD max_schema_size int`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.contexts ADD updated_at timestamp`, qident(keyspace)),
+
+		// tenancy: owning tenant of contexts and users
+		fmt.Sprintf(`ALTER TABLE %s.contexts ADD tenant text`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.users_by_id ADD tenant text`, qident(keyspace)),
+
+		// password policy state of users
+		fmt.Sprintf(`ALTER TABLE %s.users_by_id ADD password_changed_at timestamp`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.users_by_id ADD must_change_password boolean`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.users_by_id ADD password_history list<text>`, qident(keyspace)),
+
+		// resources API keys are restricted to, read when authenticating by
+		// hash and when loading a key by ID
+		fmt.Sprintf(`ALTER TABLE %s.api_keys_by_id ADD scopes text`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.api_keys_by_hash ADD scopes text`, qident(keyspace)),
+
+		// reviewers declared by subjects and assigned to pending schemas
+		fmt.Sprintf(`ALTER TABLE %s.subject_meta ADD reviewers list<text>`, qident(keyspace)),
+		fmt.Sprintf(`ALTER TABLE %s.pending_schemas ADD reviewers list<text>`, qident(keyspace)),
 	}
 	for _, stmt := range alterStmts {
 		if err := session.Query(stmt).Exec(); err != nil {
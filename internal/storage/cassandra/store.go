@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"reflect"
 	"sort"
 	"strings"
@@ -48,6 +49,25 @@ type Config struct {
 	// IDBlockSize is the number of IDs to reserve per LWT call.
 	// Higher values reduce LWT frequency but may leave gaps on crash. Default: 50.
 	IDBlockSize int `json:"id_block_size" yaml:"id_block_size"`
+
+	// GlobalIDs makes every context allocate schema IDs from the default
+	// context's sequence, so that a schema ID is unique across contexts.
+	GlobalIDs bool `json:"global_ids" yaml:"global_ids"`
+
+	// Replication is used when the keyspace is created by Migrate.
+	Replication Replication `json:"replication" yaml:"replication"`
+
+	// HostSelectionPolicy is HostSelectionTokenAware, HostSelectionDCAware or
+	// HostSelectionRoundRobin. Default: dc_aware with LocalDC, otherwise
+	// round_robin.
+	HostSelectionPolicy string `json:"host_selection_policy" yaml:"host_selection_policy"`
+
+	// TLS encrypts the connections to the nodes.
+	TLS TLS `json:"tls" yaml:"tls"`
+
+	// Retry retries queries that fail on one host on others. Default: no
+	// retries.
+	Retry Retry `json:"retry" yaml:"retry"`
 }
 
 // idAllocator reserves blocks of sequential IDs via a single LWT, then hands
@@ -117,12 +137,13 @@ func (s *Store) ResetIDCache() {
 
 // Store implements storage.Storage on Cassandra.
 type Store struct {
-	cfg              Config
-	cluster          *gocql.ClusterConfig
-	session          *gocql.Session
-	readConsistency  gocql.Consistency
-	writeConsistency gocql.Consistency
-	idAlloc          *idAllocator
+	cfg               Config
+	cluster           *gocql.ClusterConfig
+	session           *gocql.Session
+	readConsistency   gocql.Consistency
+	writeConsistency  gocql.Consistency
+	serialConsistency gocql.Consistency
+	idAlloc           *idAllocator
 }
 
 // NewStore connects to Cassandra and optionally runs migrations.
@@ -149,6 +170,9 @@ func NewStore(ctx context.Context, cfg Config) (*Store, error) {
 	if cfg.IDBlockSize <= 0 {
 		cfg.IDBlockSize = 50
 	}
+	if err := cfg.Replication.Validate(); err != nil {
+		return nil, err
+	}
 
 	cluster := gocql.NewCluster(cfg.Hosts...)
 	cluster.Port = cfg.Port
@@ -156,8 +180,22 @@ func NewStore(ctx context.Context, cfg Config) (*Store, error) {
 	cluster.Timeout = cfg.Timeout
 	cluster.ConnectTimeout = cfg.ConnectTimeout
 
-	if cfg.LocalDC != "" {
-		cluster.PoolConfig.HostSelectionPolicy = gocql.DCAwareRoundRobinPolicy(cfg.LocalDC)
+	hostPolicy, err := hostSelectionPolicy(cfg.HostSelectionPolicy, cfg.LocalDC)
+	if err != nil {
+		return nil, err
+	}
+	if hostPolicy != nil {
+		cluster.PoolConfig.HostSelectionPolicy = hostPolicy
+	}
+	retry, err := retryPolicy(cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
+	if retry != nil {
+		cluster.RetryPolicy = retry
+	}
+	if cluster.SslOpts, err = sslOptions(cfg.TLS); err != nil {
+		return nil, err
 	}
 	if cfg.Username != "" {
 		cluster.Authenticator = gocql.PasswordAuthenticator{Username: cfg.Username, Password: cfg.Password}
@@ -208,16 +246,24 @@ func NewStore(ctx context.Context, cfg Config) (*Store, error) {
 	}
 
 	s := &Store{
-		cfg:              cfg,
-		cluster:          cluster,
-		session:          session,
-		readConsistency:  readConsistency,
-		writeConsistency: writeConsistency,
-		idAlloc:          newIDAllocator(int64(cfg.IDBlockSize)),
+		cfg:               cfg,
+		cluster:           cluster,
+		session:           session,
+		readConsistency:   readConsistency,
+		writeConsistency:  writeConsistency,
+		serialConsistency: serialConsistency,
+		idAlloc:           newIDAllocator(int64(cfg.IDBlockSize)),
 	}
 
 	if cfg.Migrate {
-		if err := Migrate(session, cfg.Keyspace); err != nil {
+		if err := Migrate(session, cfg.Keyspace, cfg.Replication); err != nil {
+			session.Close()
+			return nil, err
+		}
+	}
+
+	if cfg.GlobalIDs {
+		if err := s.seedGlobalIDSequence(ctx); err != nil {
 			session.Close()
 			return nil, err
 		}
@@ -241,6 +287,18 @@ func (s *Store) IsHealthy(ctx context.Context) bool {
 	return err == nil
 }
 
+// CoordinatorLatency returns how long a query to the coordinator takes to
+// answer. A slow coordinator is an early sign of an overloaded or
+// partitioned cluster.
+func (s *Store) CoordinatorLatency(ctx context.Context) (time.Duration, error) {
+	var now time.Time
+	start := time.Now()
+	if err := s.session.Query(`SELECT now() FROM system.local`).WithContext(ctx).Scan(&now); err != nil {
+		return 0, fmt.Errorf("failed to query the coordinator: %w", err)
+	}
+	return time.Since(start), nil
+}
+
 // readQuery creates a query with read consistency.
 func (s *Store) readQuery(stmt string, values ...interface{}) *gocql.Query {
 	return s.session.Query(stmt, values...).Consistency(s.readConsistency)
@@ -251,6 +309,19 @@ func (s *Store) writeQuery(stmt string, values ...interface{}) *gocql.Query {
 	return s.session.Query(stmt, values...).Consistency(s.writeConsistency)
 }
 
+// serialQuery creates a read at the serial consistency level. Rows that are
+// only changed by LWTs, such as the ID sequence, must be read this way:
+// a serial read completes any LWT in progress on the partition, while a read
+// at read consistency may return a value an LWT has already replaced.
+func (s *Store) serialQuery(stmt string, values ...interface{}) *gocql.Query {
+	return s.session.Query(stmt, values...).Consistency(s.serialConsistency)
+}
+
+// writeBatch creates a batch with write consistency.
+func (s *Store) writeBatch(ctx context.Context, typ gocql.BatchType) *gocql.Batch {
+	return s.session.NewBatch(typ).WithContext(ctx).Consistency(s.writeConsistency)
+}
+
 // ---------- Context Operations ----------
 
 // ensureContext ensures a context exists in the contexts tracking table.
@@ -279,69 +350,245 @@ func (s *Store) ListContexts(ctx context.Context) ([]string, error) {
 	return contexts, nil
 }
 
+// CreateContext explicitly creates a registry context with its metadata.
+func (s *Store) CreateContext(ctx context.Context, record *storage.ContextRecord) error {
+	if record == nil {
+		return errors.New("context is nil")
+	}
+
+	now := time.Now()
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.contexts (registry_ctx, created_at, description, owner, max_subjects, max_schema_size, tenant, updated_at)
+			VALUES (?, now(), ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+		record.Name, record.Description, record.Owner, record.MaxSubjects, record.MaxSchemaSize, record.Tenant, now,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to create context: %w", err)
+	}
+	if !applied {
+		return storage.ErrContextExists
+	}
+
+	record.CreatedAt = now
+	record.UpdatedAt = now
+	return nil
+}
+
+// GetContext retrieves a registry context and its metadata.
+func (s *Store) GetContext(ctx context.Context, name string) (*storage.ContextRecord, error) {
+	rec := &storage.ContextRecord{Name: name}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT toTimestamp(created_at), description, owner, max_subjects, max_schema_size, tenant, updated_at
+			FROM %s.contexts WHERE registry_ctx = ?`, qident(s.cfg.Keyspace)),
+		name,
+	).WithContext(ctx).Scan(&rec.CreatedAt, &rec.Description, &rec.Owner, &rec.MaxSubjects, &rec.MaxSchemaSize, &rec.Tenant, &rec.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrContextNotFound
+		}
+		return nil, fmt.Errorf("failed to get context: %w", err)
+	}
+	return rec, nil
+}
+
+// UpdateContext updates the metadata and limits of an existing context.
+func (s *Store) UpdateContext(ctx context.Context, record *storage.ContextRecord) error {
+	if record == nil {
+		return errors.New("context is nil")
+	}
+
+	now := time.Now()
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.contexts SET description = ?, owner = ?, max_subjects = ?, max_schema_size = ?, updated_at = ?
+			WHERE registry_ctx = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		record.Description, record.Owner, record.MaxSubjects, record.MaxSchemaSize, now, record.Name,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to update context: %w", err)
+	}
+	if !applied {
+		return storage.ErrContextNotFound
+	}
+
+	existing, err := s.GetContext(ctx, record.Name)
+	if err != nil {
+		return err
+	}
+	record.Tenant = existing.Tenant
+	record.CreatedAt = existing.CreatedAt
+	record.UpdatedAt = now
+	return nil
+}
+
+// DeleteContext removes a context together with its context-level config,
+// mode and ID allocation state. Subject data is partitioned by subject and is
+// expected to have been permanently deleted by the caller beforehand.
+func (s *Store) DeleteContext(ctx context.Context, name string) error {
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.contexts WHERE registry_ctx = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		name,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to delete context: %w", err)
+	}
+	if !applied {
+		return storage.ErrContextNotFound
+	}
+
+	for _, stmt := range []struct {
+		table, key, value string
+	}{
+		{"global_config", "key", "global"},
+		{"modes", "key", "global"},
+		{"id_alloc", "name", "schema_id"},
+	} {
+		if err := s.writeQuery(
+			fmt.Sprintf(`DELETE FROM %s.%s WHERE registry_ctx = ? AND %s = ?`, qident(s.cfg.Keyspace), stmt.table, stmt.key),
+			name, stmt.value,
+		).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to delete context data from %s: %w", stmt.table, err)
+		}
+	}
+	for _, table := range []string{"schema_tags", "subject_meta", "schema_id_aliases", "frozen_versions", "reader_assertions"} {
+		if err := s.writeQuery(
+			fmt.Sprintf(`DELETE FROM %s.%s WHERE registry_ctx = ?`, qident(s.cfg.Keyspace), table),
+			name,
+		).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to delete context data from %s: %w", table, err)
+		}
+	}
+	s.idAlloc.reset(name)
+
+	return nil
+}
+
 // ---------- ID Allocation (Block-Based, Per-Context) ----------
 
+// idSequence is the name of the schema ID sequence in id_alloc.
+const idSequence = "schema_id"
+
+// idSequenceContext returns the context whose id_alloc sequence allocates
+// the schema IDs of registryCtx.
+func (s *Store) idSequenceContext(registryCtx string) string {
+	if s.cfg.GlobalIDs {
+		return "."
+	}
+	return registryCtx
+}
+
+// seedGlobalIDSequence advances the shared ID sequence past every ID already
+// assigned in any context, so that switching to global IDs never reuses one.
+func (s *Store) seedGlobalIDSequence(ctx context.Context) error {
+	maxID, err := s.GetMaxSchemaID(ctx, ".")
+	if err != nil {
+		return err
+	}
+	if err := s.SetNextID(ctx, ".", maxID+1); err != nil {
+		return fmt.Errorf("failed to seed global ID sequence: %w", err)
+	}
+	return nil
+}
+
 // NextID returns a new per-context schema ID using block-based allocation.
-// Reserves IDs in blocks via a single LWT, then hands out locally.
+// Reserves IDs in blocks via a single LWT, then hands out locally. With
+// GlobalIDs every context draws from the default context's sequence.
 func (s *Store) NextID(ctx context.Context, registryCtx string) (int64, error) {
-	return s.idAlloc.next(ctx, s, registryCtx)
+	return s.idAlloc.next(ctx, s, s.idSequenceContext(registryCtx))
 }
 
 // reserveIDBlock atomically reserves a block of IDs via LWT for a specific context.
 // Returns the base ID of the reserved block.
 func (s *Store) reserveIDBlock(ctx context.Context, registryCtx string, blockSize int64) (int64, error) {
+	current, found, err := s.readIDSequence(ctx, registryCtx)
+	if err != nil {
+		return 0, err
+	}
 	for attempt := 0; attempt < s.cfg.MaxRetries; attempt++ {
-		var current int
-		if err := s.session.Query(
-			fmt.Sprintf(`SELECT next_id FROM %s.id_alloc WHERE registry_ctx = ? AND name = ?`, qident(s.cfg.Keyspace)),
-			registryCtx, "schema_id",
-		).WithContext(ctx).Scan(&current); err != nil {
-			if errors.Is(err, gocql.ErrNotFound) {
-				// Initialize if not exists
-				applied, err := casApplied(
-					s.session.Query(
-						fmt.Sprintf(`INSERT INTO %s.id_alloc (registry_ctx, name, next_id) VALUES (?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
-						registryCtx, "schema_id", int(blockSize)+1,
-					).WithContext(ctx),
-				)
-				if err != nil {
-					return 0, err
-				}
-				if applied {
-					return 1, nil // Base = 1
-				}
-				continue // Someone else initialized, retry
-			}
-			return 0, err
+		base := current
+		if !found {
+			base = 1
 		}
-
-		// Atomically reserve block: SET next_id = current + blockSize IF next_id = current
-		applied, err := casApplied(
-			s.session.Query(
-				fmt.Sprintf(`UPDATE %s.id_alloc SET next_id = ? WHERE registry_ctx = ? AND name = ? IF next_id = ?`, qident(s.cfg.Keyspace)),
-				current+int(blockSize), registryCtx, "schema_id", current,
-			).WithContext(ctx),
-		)
+		applied, actual, actualFound, err := s.advanceIDSequence(ctx, registryCtx, current, found, base+blockSize)
 		if err != nil {
 			return 0, err
 		}
-		if !applied {
-			continue // Contention, retry
+		if applied {
+			return base, nil
+		}
+		// Another writer reserved a block first; retry from where it left the sequence.
+		current, found = actual, actualFound
+		if err := casBackoff(ctx, attempt); err != nil {
+			return 0, err
 		}
-		return int64(current), nil
 	}
 	return 0, errors.New("failed to allocate schema ID block: too much contention")
 }
 
-// GetMaxSchemaID returns the highest per-context schema ID currently assigned.
+// readIDSequence returns the next unreserved ID of a context's sequence, and
+// false if the sequence has not been initialized.
+func (s *Store) readIDSequence(ctx context.Context, registryCtx string) (int64, bool, error) {
+	var next int
+	err := s.serialQuery(
+		fmt.Sprintf(`SELECT next_id FROM %s.id_alloc WHERE registry_ctx = ? AND name = ?`, qident(s.cfg.Keyspace)),
+		registryCtx, idSequence,
+	).WithContext(ctx).Scan(&next)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return int64(next), true, nil
+}
+
+// advanceIDSequence moves a context's sequence from current to next with an
+// LWT, creating it if found is false. When another writer changed the
+// sequence first, it returns false with the sequence's actual value, taken
+// from the LWT result, so that the caller can retry without reading it again.
+func (s *Store) advanceIDSequence(ctx context.Context, registryCtx string, current int64, found bool, next int64) (applied bool, actual int64, actualFound bool, err error) {
+	var q *gocql.Query
+	if found {
+		q = s.writeQuery(
+			fmt.Sprintf(`UPDATE %s.id_alloc SET next_id = ? WHERE registry_ctx = ? AND name = ? IF next_id = ?`, qident(s.cfg.Keyspace)),
+			int(next), registryCtx, idSequence, int(current),
+		)
+	} else {
+		q = s.writeQuery(
+			fmt.Sprintf(`INSERT INTO %s.id_alloc (registry_ctx, name, next_id) VALUES (?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+			registryCtx, idSequence, int(next),
+		)
+	}
+	m := map[string]interface{}{}
+	applied, err = q.WithContext(ctx).MapScanCAS(m)
+	if err != nil {
+		return false, 0, false, fmt.Errorf("ID sequence LWT failed: %w", err)
+	}
+	if applied {
+		return true, next, true, nil
+	}
+	// A conditional UPDATE of a missing row returns no next_id, e.g. after
+	// the context was deleted.
+	v, ok := m["next_id"].(int)
+	if !ok {
+		return false, 0, false, nil
+	}
+	return false, int64(v), true, nil
+}
+
+// GetMaxSchemaID returns the highest per-context schema ID currently assigned,
+// or the highest in any context when GlobalIDs is set.
 // Scans actual schema data to find the true maximum, rather than reading the
 // block allocator's next_id which may be much higher due to pre-allocation.
 func (s *Store) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64, error) {
 	var maxID int
-	err := s.readQuery(
+	q := s.readQuery(
 		fmt.Sprintf(`SELECT MAX(schema_id) FROM %s.schemas_by_id WHERE registry_ctx = ? ALLOW FILTERING`, qident(s.cfg.Keyspace)),
 		registryCtx,
-	).WithContext(ctx).Scan(&maxID)
+	)
+	if s.cfg.GlobalIDs {
+		q = s.readQuery(fmt.Sprintf(`SELECT MAX(schema_id) FROM %s.schemas_by_id`, qident(s.cfg.Keyspace)))
+	}
+	err := q.WithContext(ctx).Scan(&maxID)
 	if err != nil {
 		if errors.Is(err, gocql.ErrNotFound) {
 			return 0, nil
@@ -351,49 +598,38 @@ func (s *Store) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64,
 	return int64(maxID), nil
 }
 
+// PeekNextID returns the first ID past the blocks reserved from the
+// context's sequence, or from the shared sequence when GlobalIDs is set,
+// without reserving anything.
+func (s *Store) PeekNextID(ctx context.Context, registryCtx string) (int64, error) {
+	next, found, err := s.readIDSequence(ctx, s.idSequenceContext(registryCtx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read next ID: %w", err)
+	}
+	if !found {
+		return 1, nil
+	}
+	return next, nil
+}
+
 // SetNextID sets the per-context ID sequence to start from the given value.
 // Used after import to prevent ID conflicts.
 // Guards against rewinding: if the current value is already >= id, this is a no-op.
+//
+// Other instances keep handing out the IDs of blocks they reserved before;
+// createSchemaWithNewID skips those that an import has already used.
 func (s *Store) SetNextID(ctx context.Context, registryCtx string, id int64) error {
-	// Use LWT to set the next_id value
+	registryCtx = s.idSequenceContext(registryCtx)
+	current, found, err := s.readIDSequence(ctx, registryCtx)
+	if err != nil {
+		return err
+	}
 	for attempt := 0; attempt < s.cfg.MaxRetries; attempt++ {
-		var current int
-		err := s.session.Query(
-			fmt.Sprintf(`SELECT next_id FROM %s.id_alloc WHERE registry_ctx = ? AND name = ?`, qident(s.cfg.Keyspace)),
-			registryCtx, "schema_id",
-		).WithContext(ctx).Scan(&current)
-
-		if errors.Is(err, gocql.ErrNotFound) {
-			applied, err := casApplied(
-				s.session.Query(
-					fmt.Sprintf(`INSERT INTO %s.id_alloc (registry_ctx, name, next_id) VALUES (?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
-					registryCtx, "schema_id", int(id),
-				).WithContext(ctx),
-			)
-			if err != nil {
-				return err
-			}
-			if applied {
-				s.idAlloc.reset(registryCtx)
-				return nil
-			}
-			continue
-		}
-		if err != nil {
-			return err
-		}
-
 		// Guard against rewinding: only advance, never go backward
-		if int64(current) >= id {
+		if found && current >= id {
 			return nil
 		}
-
-		applied, err := casApplied(
-			s.session.Query(
-				fmt.Sprintf(`UPDATE %s.id_alloc SET next_id = ? WHERE registry_ctx = ? AND name = ? IF next_id = ?`, qident(s.cfg.Keyspace)),
-				int(id), registryCtx, "schema_id", current,
-			).WithContext(ctx),
-		)
+		applied, actual, actualFound, err := s.advanceIDSequence(ctx, registryCtx, current, found, id)
 		if err != nil {
 			return err
 		}
@@ -401,6 +637,10 @@ func (s *Store) SetNextID(ctx context.Context, registryCtx string, id int64) err
 			s.idAlloc.reset(registryCtx)
 			return nil
 		}
+		current, found = actual, actualFound
+		if err := casBackoff(ctx, attempt); err != nil {
+			return err
+		}
 	}
 	return errors.New("failed to set next ID: too much contention")
 }
@@ -488,7 +728,7 @@ func (s *Store) ImportSchema(ctx context.Context, registryCtx string, record *st
 	}
 
 	// Batch: subject_versions + subject_latest + references (logged batch for atomicity)
-	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
 
 	batch.Query(
 		fmt.Sprintf(`INSERT INTO %s.subject_versions (registry_ctx, subject, version, schema_id, deleted, created_at, metadata, ruleset)
@@ -608,7 +848,7 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 
 		// Step 1: Write subject_versions FIRST (idempotent with IF NOT EXISTS)
 		applied, err := casApplied(
-			s.session.Query(
+			s.writeQuery(
 				fmt.Sprintf(`INSERT INTO %s.subject_versions (registry_ctx, subject, version, schema_id, deleted, created_at, metadata, ruleset)
 					VALUES (?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
 				registryCtx, record.Subject, newVersion, int(schemaID), false, createdUUID, metadataStr, rulesetStr,
@@ -620,7 +860,7 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 		if !applied {
 			// Version already exists — check if it has our schema_id (retry case) or different (contention)
 			var existingSchemaID int
-			err := s.session.Query(
+			err := s.serialQuery(
 				fmt.Sprintf(`SELECT schema_id FROM %s.subject_versions WHERE registry_ctx = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
 				registryCtx, record.Subject, newVersion,
 			).WithContext(ctx).Scan(&existingSchemaID)
@@ -647,7 +887,7 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 		// Step 2: CAS update subject_latest to "publish" this version
 		if !exists {
 			applied, err = casApplied(
-				s.session.Query(
+				s.writeQuery(
 					fmt.Sprintf(`INSERT INTO %s.subject_latest (registry_ctx, subject, latest_version, latest_schema_id, updated_at)
 						VALUES (?, ?, ?, ?, now()) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
 					registryCtx, record.Subject, newVersion, int(schemaID),
@@ -655,7 +895,7 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 			)
 		} else {
 			applied, err = casApplied(
-				s.session.Query(
+				s.writeQuery(
 					fmt.Sprintf(`UPDATE %s.subject_latest SET latest_version = ?, latest_schema_id = ?, updated_at = now()
 						WHERE registry_ctx = ? AND subject = ? IF latest_version = ? AND latest_schema_id = ?`, qident(s.cfg.Keyspace)),
 					newVersion, int(schemaID), registryCtx, record.Subject, latestVersion, latestSchemaID,
@@ -671,7 +911,7 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 
 		// Step 3: Write schema references (logged batch for atomicity)
 		if len(record.References) > 0 {
-			batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+			batch := s.writeBatch(ctx, gocql.LoggedBatch)
 			for _, ref := range record.References {
 				batch.Query(
 					fmt.Sprintf(`INSERT INTO %s.schema_references (registry_ctx, schema_id, name, ref_subject, ref_version) VALUES (?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
@@ -721,8 +961,8 @@ func (s *Store) ensureGlobalSchema(ctx context.Context, registryCtx string, sche
 		return 0, time.Time{}, err
 	}
 
-	// Slow path: allocate new ID and claim fingerprint via LWT
-	newID, err := s.NextID(ctx, registryCtx)
+	// Slow path: write the schema under a new ID, then claim the fingerprint via LWT
+	newID, createdAt, err := s.createSchemaWithNewID(ctx, registryCtx, schemaType, schemaText, canonical, fp)
 	if err != nil {
 		return 0, time.Time{}, err
 	}
@@ -732,12 +972,45 @@ func (s *Store) ensureGlobalSchema(ctx context.Context, registryCtx string, sche
 		return 0, time.Time{}, err
 	}
 	if !applied {
-		// Another writer claimed this fingerprint first — use their schema_id
+		// Another writer claimed this fingerprint first — drop our copy and use their schema_id.
+		// A failed delete only leaves an unreferenced row behind.
+		_ = s.writeQuery(
+			fmt.Sprintf(`DELETE FROM %s.schemas_by_id WHERE registry_ctx = ? AND schema_id = ?`, qident(s.cfg.Keyspace)),
+			registryCtx, int(newID),
+		).WithContext(ctx).Exec()
 		return s.ensureSchemaData(ctx, registryCtx, winnerID, schemaType, schemaText, canonical, fp)
 	}
+	return newID, createdAt, nil
+}
 
-	// We won the CAS — insert full schema data into schemas_by_id
-	return s.ensureSchemaData(ctx, registryCtx, newID, schemaType, schemaText, canonical, fp)
+// createSchemaWithNewID allocates a schema ID and writes the schema under it
+// with INSERT IF NOT EXISTS, so that an ID can never be given to two schemas.
+// Instances hand out IDs from blocks they reserved earlier, and after an
+// import has moved the sequence past IDs of such a block (see SetNextID) the
+// block may contain IDs that are already taken; those are skipped.
+func (s *Store) createSchemaWithNewID(ctx context.Context, registryCtx string, schemaType, schemaText, canonical, fp string) (int64, time.Time, error) {
+	for attempt := 0; attempt < s.cfg.MaxRetries; attempt++ {
+		id, err := s.NextID(ctx, registryCtx)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		createdUUID := gocql.TimeUUID()
+		applied, err := casApplied(
+			s.writeQuery(
+				fmt.Sprintf(`INSERT INTO %s.schemas_by_id (registry_ctx, schema_id, schema_type, fingerprint, schema_text, canonical_text, created_at)
+					VALUES (?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+				registryCtx, int(id), schemaType, fp, schemaText, canonical, createdUUID,
+			).WithContext(ctx),
+		)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("schema ID LWT failed: %w", err)
+		}
+		if applied {
+			return id, createdUUID.Time(), nil
+		}
+		slog.Warn("skipping schema ID already in use", "registry_ctx", registryCtx, "schema_id", id)
+	}
+	return 0, time.Time{}, errors.New("failed to allocate schema ID: every allocated ID was already in use")
 }
 
 // claimFingerprint atomically associates a fingerprint with a schema_id using LWT within a context.
@@ -745,7 +1018,7 @@ func (s *Store) ensureGlobalSchema(ctx context.Context, registryCtx string, sche
 // Returns (false, existingID, nil) if the fingerprint was already claimed by existingID.
 func (s *Store) claimFingerprint(ctx context.Context, registryCtx, fp string, schemaID int64) (applied bool, existingID int64, err error) {
 	m := map[string]interface{}{}
-	applied, err = s.session.Query(
+	applied, err = s.writeQuery(
 		fmt.Sprintf(`INSERT INTO %s.schema_fingerprints (registry_ctx, fingerprint, schema_id) VALUES (?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
 		registryCtx, fp, int(schemaID),
 	).WithContext(ctx).MapScanCAS(m)
@@ -1198,6 +1471,32 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	return s.GetSchemaBySubjectVersion(ctx, registryCtx, subject, latestVersion)
 }
 
+// GetLatestSchemaStamp returns the version and creation time of a subject's
+// latest non-deleted version. A re-registered version gets a new creation
+// time.
+func (s *Store) GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT version, created_at FROM %s.subject_versions WHERE registry_ctx = ? AND subject = ? AND deleted = false`, qident(s.cfg.Keyspace)),
+		registryCtx, subject,
+	).WithContext(ctx).Iter()
+	latestVersion := 0
+	var latestCreated gocql.UUID
+	var version int
+	var created gocql.UUID
+	for iter.Scan(&version, &created) {
+		if version > latestVersion {
+			latestVersion, latestCreated = version, created
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return "", err
+	}
+	if latestVersion == 0 {
+		return "", storage.ErrSubjectNotFound
+	}
+	return fmt.Sprintf("%d:%s", latestVersion, latestCreated), nil
+}
+
 func (s *Store) getSubjectLatest(ctx context.Context, registryCtx string, subject string) (latestVersion int, latestSchemaID int, exists bool, err error) {
 	var v, sid int
 	var updated gocql.UUID
@@ -1214,6 +1513,33 @@ func (s *Store) getSubjectLatest(ctx context.Context, registryCtx string, subjec
 	return v, sid, true, nil
 }
 
+// RewriteSchemaText replaces the stored text of a schema within a context.
+func (s *Store) RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error {
+	var schemaType string
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT schema_type FROM %s.schemas_by_id WHERE registry_ctx = ? AND schema_id = ?`, qident(s.cfg.Keyspace)),
+		registryCtx, int(id),
+	).WithContext(ctx).Scan(&schemaType)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return storage.ErrSchemaNotFound
+		}
+		return err
+	}
+
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.schemas_by_id SET schema_text = ?, canonical_text = ? WHERE registry_ctx = ? AND schema_id = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		schemaText, canonicalize(schemaType, schemaText), registryCtx, int(id),
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to rewrite schema: %w", err)
+	}
+	if !applied {
+		return storage.ErrSchemaNotFound
+	}
+	return nil
+}
+
 // DeleteSchema soft-deletes or permanently deletes a schema version within a context.
 func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject string, version int, permanent bool) error {
 	if subject == "" || version <= 0 {
@@ -1257,8 +1583,8 @@ func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject st
 		return nil
 	}
 	return s.writeQuery(
-		fmt.Sprintf(`UPDATE %s.subject_versions SET deleted = true WHERE registry_ctx = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
-		registryCtx, subject, version,
+		fmt.Sprintf(`UPDATE %s.subject_versions SET deleted = true, deleted_at = ? WHERE registry_ctx = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
+		time.Now(), registryCtx, subject, version,
 	).WithContext(ctx).Exec()
 }
 
@@ -1376,44 +1702,169 @@ func (s *Store) ListSubjects(ctx context.Context, registryCtx string, includeDel
 	return subjects, nil
 }
 
-// DeleteSubject soft-deletes or permanently deletes all versions of a subject within a context.
-func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
-	if subject == "" {
-		return nil, nil
+// ListSubjectsPage returns one page of subject names within a context.
+// Subjects are filtered by prefix and sorted before their versions are
+// probed, and probing stops once the page is full.
+func (s *Store) ListSubjectsPage(ctx context.Context, registryCtx string, params *storage.ListSubjectsParams) ([]string, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT subject FROM %s.subject_latest WHERE registry_ctx = ?`, qident(s.cfg.Keyspace)),
+		registryCtx,
+	).WithContext(ctx).Iter()
+
+	var candidates []string
+	var subject string
+	for iter.Scan(&subject) {
+		if strings.HasPrefix(subject, params.Prefix) {
+			candidates = append(candidates, subject)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Strings(candidates)
+
+	if params.Deleted && !params.DeletedOnly {
+		return params.Page(candidates), nil
+	}
+
+	subjects := make([]string, 0)
+	for _, subj := range candidates {
+		if params.Limit > 0 && len(subjects) >= params.Offset+params.Limit {
+			break
+		}
+		var v int
+		err := s.readQuery(
+			fmt.Sprintf(`SELECT version FROM %s.subject_versions WHERE registry_ctx = ? AND subject = ? AND deleted = false LIMIT 1`, qident(s.cfg.Keyspace)),
+			registryCtx, subj,
+		).WithContext(ctx).Scan(&v)
+		switch {
+		case err == nil:
+			if !params.DeletedOnly {
+				subjects = append(subjects, subj)
+			}
+		case errors.Is(err, gocql.ErrNotFound):
+			if params.DeletedOnly {
+				subjects = append(subjects, subj)
+			}
+		default:
+			return nil, err
+		}
 	}
+	return params.Page(subjects), nil
+}
 
-	iter := s.session.Query(
+// ListVersions returns the version numbers of a subject within a context.
+// Only the subject_versions partition is read; schema content is not loaded.
+func (s *Store) ListVersions(ctx context.Context, registryCtx string, subject string, params *storage.ListVersionsParams) ([]int, error) {
+	iter := s.readQuery(
 		fmt.Sprintf(`SELECT version, deleted FROM %s.subject_versions WHERE registry_ctx = ? AND subject = ?`, qident(s.cfg.Keyspace)),
 		registryCtx, subject,
 	).WithContext(ctx).Iter()
 
-	type versionInfo struct {
-		version int
-		deleted bool
-	}
-	var allVersions []versionInfo
+	var versions []int
 	var version int
 	var deleted bool
 	for iter.Scan(&version, &deleted) {
-		allVersions = append(allVersions, versionInfo{version, deleted})
+		if params.Deleted || !deleted {
+			versions = append(versions, version)
+		}
 	}
 	if err := iter.Close(); err != nil {
 		return nil, err
 	}
-	if len(allVersions) == 0 {
+	if len(versions) == 0 {
 		return nil, storage.ErrSubjectNotFound
 	}
 
-	var deletedVersions []int
-	if permanent {
-		for _, vi := range allVersions {
-			if !vi.deleted {
-				return nil, storage.ErrSubjectNotSoftDeleted
-			}
-			deletedVersions = append(deletedVersions, vi.version)
-		}
-	} else {
-		allSoftDeleted := true
+	// The partition is clustered by version ASC, but sort defensively.
+	sort.Ints(versions)
+	return params.Page(versions), nil
+}
+
+// ListSoftDeletedVersions returns soft-deleted versions deleted before the cutoff.
+// Cassandra migrations cannot backfill rows, so versions soft-deleted before
+// deleted_at existed are stamped with the current time when first seen here.
+func (s *Store) ListSoftDeletedVersions(ctx context.Context, registryCtx string, deletedBefore time.Time) ([]storage.SubjectVersion, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT subject, version, deleted_at FROM %s.subject_versions WHERE registry_ctx = ? AND deleted = true`, qident(s.cfg.Keyspace)),
+		registryCtx,
+	).WithContext(ctx).Iter()
+
+	result := []storage.SubjectVersion{}
+	var unstamped []storage.SubjectVersion
+	var subject string
+	var version int
+	var deletedAt time.Time
+	for iter.Scan(&subject, &version, &deletedAt) {
+		sv := storage.SubjectVersion{Subject: subject, Version: version}
+		switch {
+		case deletedAt.IsZero():
+			unstamped = append(unstamped, sv)
+		case deletedAt.Before(deletedBefore):
+			result = append(result, sv)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, sv := range unstamped {
+		if err := s.writeQuery(
+			fmt.Sprintf(`UPDATE %s.subject_versions SET deleted_at = ? WHERE registry_ctx = ? AND subject = ? AND version = ? IF deleted = true`, qident(s.cfg.Keyspace)),
+			now, registryCtx, sv.Subject, sv.Version,
+		).WithContext(ctx).Exec(); err != nil {
+			slog.Warn("failed to stamp soft-deleted version", "subject", sv.Subject, "version", sv.Version, "error", err)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Subject != result[j].Subject {
+			return result[i].Subject < result[j].Subject
+		}
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// DeleteSubject soft-deletes or permanently deletes all versions of a subject within a context.
+func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
+	if subject == "" {
+		return nil, nil
+	}
+
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT version, deleted FROM %s.subject_versions WHERE registry_ctx = ? AND subject = ?`, qident(s.cfg.Keyspace)),
+		registryCtx, subject,
+	).WithContext(ctx).Iter()
+
+	type versionInfo struct {
+		version int
+		deleted bool
+	}
+	var allVersions []versionInfo
+	var version int
+	var deleted bool
+	for iter.Scan(&version, &deleted) {
+		allVersions = append(allVersions, versionInfo{version, deleted})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	if len(allVersions) == 0 {
+		return nil, storage.ErrSubjectNotFound
+	}
+
+	var deletedVersions []int
+	if permanent {
+		for _, vi := range allVersions {
+			if !vi.deleted {
+				return nil, storage.ErrSubjectNotSoftDeleted
+			}
+			deletedVersions = append(deletedVersions, vi.version)
+		}
+	} else {
+		allSoftDeleted := true
 		for _, vi := range allVersions {
 			if !vi.deleted {
 				allSoftDeleted = false
@@ -1456,13 +1907,26 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 		).WithContext(ctx).Exec(); err != nil {
 			slog.Warn("failed to delete subject mode", "subject", subject, "error", err)
 		}
+		if err := s.writeQuery(
+			fmt.Sprintf(`DELETE FROM %s.schema_tags WHERE registry_ctx = ? AND subject = ?`, qident(s.cfg.Keyspace)),
+			registryCtx, subject,
+		).WithContext(ctx).Exec(); err != nil {
+			slog.Warn("failed to delete subject tags", "subject", subject, "error", err)
+		}
+		if err := s.writeQuery(
+			fmt.Sprintf(`DELETE FROM %s.subject_meta WHERE registry_ctx = ? AND subject = ?`, qident(s.cfg.Keyspace)),
+			registryCtx, subject,
+		).WithContext(ctx).Exec(); err != nil {
+			slog.Warn("failed to delete subject meta", "subject", subject, "error", err)
+		}
 	} else {
 		// Soft delete: batch all version updates (same partition = unlogged batch is atomic)
-		batch := s.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+		batch := s.writeBatch(ctx, gocql.UnloggedBatch)
+		now := time.Now()
 		for _, v := range deletedVersions {
 			batch.Query(
-				fmt.Sprintf(`UPDATE %s.subject_versions SET deleted = true WHERE registry_ctx = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
-				registryCtx, subject, v,
+				fmt.Sprintf(`UPDATE %s.subject_versions SET deleted = true, deleted_at = ? WHERE registry_ctx = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
+				now, registryCtx, subject, v,
 			)
 		}
 		if err := s.session.ExecuteBatch(batch); err != nil {
@@ -1594,11 +2058,41 @@ func (s *Store) GetVersionsBySchemaID(ctx context.Context, registryCtx string, i
 	return results, nil
 }
 
+// subjectAliases returns the target of every subject of a context whose
+// config sets an alias.
+func (s *Store) subjectAliases(ctx context.Context, registryCtx string) (map[string]string, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT subject, alias FROM %s.subject_configs WHERE registry_ctx = ? ALLOW FILTERING`, qident(s.cfg.Keyspace)),
+		registryCtx,
+	).WithContext(ctx).Iter()
+
+	aliases := make(map[string]string)
+	var subject, alias string
+	for iter.Scan(&subject, &alias) {
+		if subject != "" && alias != "" {
+			aliases[subject] = alias
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
 // ListSchemas lists schemas with optional filtering within a context.
 func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
 	if params == nil {
 		params = &storage.ListSchemasParams{}
 	}
+	if params.Aliases {
+		aliases, err := s.subjectAliases(ctx, registryCtx)
+		if err != nil {
+			return nil, err
+		}
+		return params.WithAliases(aliases, func(p *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
+			return s.ListSchemas(ctx, registryCtx, p)
+		})
+	}
 
 	subjects, err := s.ListSubjects(ctx, registryCtx, params.Deleted)
 	if err != nil {
@@ -1611,19 +2105,22 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 			continue
 		}
 
-		if params.LatestOnly {
-			rec, err := s.GetLatestSchema(ctx, registryCtx, subject)
-			if err == nil {
-				results = append(results, rec)
-			}
-		} else {
-			recs, err := s.GetSchemasBySubject(ctx, registryCtx, subject, params.Deleted)
-			if err == nil {
-				results = append(results, recs...)
-			}
+		recs, err := s.GetSchemasBySubject(ctx, registryCtx, subject, params.Deleted)
+		if err != nil {
+			continue
 		}
+		if params.LatestOnly && len(recs) > 0 {
+			// Versions are sorted, so the last is the latest.
+			recs = recs[len(recs)-1:]
+		}
+		results = append(results, recs...)
 	}
 
+	// Order by ID, as the other backends do.
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+
 	// Apply offset and limit
 	if params.Offset > 0 {
 		if params.Offset >= len(results) {
@@ -1638,6 +2135,31 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 	return results, nil
 }
 
+// IterateSchemas calls fn with every schema version of a context, reading
+// one subject's versions at a time.
+func (s *Store) IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*storage.SchemaRecord) error) error {
+	subjects, err := s.ListSubjects(ctx, registryCtx, includeDeleted)
+	if err != nil {
+		return err
+	}
+	for _, subject := range subjects {
+		versions, err := s.GetSchemasBySubject(ctx, registryCtx, subject, includeDeleted)
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, record := range versions {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ---------- Config Operations ----------
 
 // GetConfig retrieves the compatibility config for a subject within a context.
@@ -1862,138 +2384,588 @@ func (s *Store) DeleteGlobalMode(ctx context.Context, registryCtx string) error
 	).WithContext(ctx).Exec()
 }
 
-// ---------- User Operations ----------
+// ---------- Tenant Operations ----------
 
-// CreateUser creates a new user.
-func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
-	if user == nil {
-		return errors.New("user is nil")
-	}
-	if user.Username == "" {
-		return errors.New("username is required")
+// SetTags replaces the tags and labels of a subject or subject version.
+func (s *Store) SetTags(ctx context.Context, registryCtx string, record *storage.TagsRecord) error {
+	if record == nil {
+		return errors.New("tags record is nil")
 	}
-
-	existing, err := s.GetUserByUsername(ctx, user.Username)
-	if err == nil && existing != nil {
-		return storage.ErrUserExists
+	now := time.Now()
+	if err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.schema_tags (registry_ctx, subject, version, tags, labels, updated_at) VALUES (?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		registryCtx, record.Subject, record.Version, record.Tags, record.Labels, now,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to set tags: %w", err)
 	}
+	record.UpdatedAt = now
+	return nil
+}
 
-	if user.ID == 0 {
-		id, err := s.NextID(ctx, ".")
-		if err != nil {
-			return err
+// GetTags retrieves the tags and labels of a subject or subject version.
+func (s *Store) GetTags(ctx context.Context, registryCtx string, subject string, version int) (*storage.TagsRecord, error) {
+	rec := &storage.TagsRecord{Subject: subject, Version: version}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT tags, labels, updated_at FROM %s.schema_tags WHERE registry_ctx = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
+		registryCtx, subject, version,
+	).WithContext(ctx).Scan(&rec.Tags, &rec.Labels, &rec.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrTagsNotFound
 		}
-		user.ID = id
+		return nil, fmt.Errorf("failed to get tags: %w", err)
 	}
+	return rec, nil
+}
 
-	nowUUID := gocql.TimeUUID()
-	now := nowUUID.Time()
-	if user.CreatedAt.IsZero() {
-		user.CreatedAt = now
+// DeleteTags removes the tags and labels of a subject or subject version.
+func (s *Store) DeleteTags(ctx context.Context, registryCtx string, subject string, version int) error {
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.schema_tags WHERE registry_ctx = ? AND subject = ? AND version = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		registryCtx, subject, version,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
 	}
-	if user.UpdatedAt.IsZero() {
-		user.UpdatedAt = now
+	if !applied {
+		return storage.ErrTagsNotFound
 	}
+	return nil
+}
 
-	createdUUID := gocql.UUIDFromTime(user.CreatedAt)
-	updatedUUID := gocql.UUIDFromTime(user.UpdatedAt)
+// ListTags returns every tags record in a context, ordered by subject and
+// version (the clustering order of the partition).
+func (s *Store) ListTags(ctx context.Context, registryCtx string) ([]*storage.TagsRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT subject, version, tags, labels, updated_at FROM %s.schema_tags WHERE registry_ctx = ?`, qident(s.cfg.Keyspace)),
+		registryCtx,
+	).WithContext(ctx).Iter()
 
-	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
-	batch.Query(
-		fmt.Sprintf(`INSERT INTO %s.users_by_id (user_id, email, name, password_hash, roles, enabled, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
-		user.ID, user.Email, user.Username, user.PasswordHash, []string{user.Role}, user.Enabled, createdUUID, updatedUUID,
-	)
-	batch.Query(
-		fmt.Sprintf(`INSERT INTO %s.users_by_email (email, user_id, name, password_hash, roles, enabled, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
-		user.Username, user.ID, user.Username, user.PasswordHash, []string{user.Role}, user.Enabled, createdUUID, updatedUUID,
-	)
-	return s.session.ExecuteBatch(batch)
+	records := make([]*storage.TagsRecord, 0)
+	for {
+		rec := &storage.TagsRecord{}
+		if !iter.Scan(&rec.Subject, &rec.Version, &rec.Tags, &rec.Labels, &rec.UpdatedAt) {
+			break
+		}
+		records = append(records, rec)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return records, nil
 }
 
-// GetUserByID retrieves a user by ID.
-func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
-	var email, name, pw string
-	var roles []string
-	var enabled bool
-	var createdUUID, updatedUUID gocql.UUID
+// SetSubjectMeta creates or replaces the meta of a subject.
+func (s *Store) SetSubjectMeta(ctx context.Context, registryCtx string, record *storage.SubjectMetaRecord) error {
+	if record == nil {
+		return errors.New("subject meta record is nil")
+	}
+	links := record.Links
+	if links == nil {
+		links = []storage.SubjectLink{}
+	}
+	linksJSON, err := json.Marshal(links)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subject links: %w", err)
+	}
+	now := time.Now()
+	if err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.subject_meta (registry_ctx, subject, owner, description, contact, links, reviewers, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		registryCtx, record.Subject, record.Owner, record.Description, record.Contact, string(linksJSON), record.Reviewers, now,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to set subject meta: %w", err)
+	}
+	record.UpdatedAt = now
+	return nil
+}
+
+// GetSubjectMeta retrieves the meta of a subject.
+func (s *Store) GetSubjectMeta(ctx context.Context, registryCtx string, subject string) (*storage.SubjectMetaRecord, error) {
+	rec := &storage.SubjectMetaRecord{Subject: subject}
+	var linksJSON string
 	err := s.readQuery(
-		fmt.Sprintf(`SELECT email, name, password_hash, roles, enabled, created_at, updated_at FROM %s.users_by_id WHERE user_id = ?`, qident(s.cfg.Keyspace)),
-		id,
-	).WithContext(ctx).Scan(&email, &name, &pw, &roles, &enabled, &createdUUID, &updatedUUID)
+		fmt.Sprintf(`SELECT owner, description, contact, links, reviewers, updated_at FROM %s.subject_meta WHERE registry_ctx = ? AND subject = ?`, qident(s.cfg.Keyspace)),
+		registryCtx, subject,
+	).WithContext(ctx).Scan(&rec.Owner, &rec.Description, &rec.Contact, &linksJSON, &rec.Reviewers, &rec.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, gocql.ErrNotFound) {
-			return nil, storage.ErrUserNotFound
+			return nil, storage.ErrSubjectMetaNotFound
 		}
-		return nil, err
+		return nil, fmt.Errorf("failed to get subject meta: %w", err)
 	}
-
-	role := ""
-	if len(roles) > 0 {
-		role = roles[0]
+	if linksJSON != "" {
+		if err := json.Unmarshal([]byte(linksJSON), &rec.Links); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal subject links: %w", err)
+		}
 	}
+	return rec, nil
+}
 
-	return &storage.UserRecord{
-		ID:           id,
-		Username:     name,
-		Email:        email,
-		PasswordHash: pw,
-		Role:         role,
-		Enabled:      enabled,
-		CreatedAt:    createdUUID.Time(),
-		UpdatedAt:    updatedUUID.Time(),
-	}, nil
+// DeleteSubjectMeta removes the meta of a subject.
+func (s *Store) DeleteSubjectMeta(ctx context.Context, registryCtx string, subject string) error {
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.subject_meta WHERE registry_ctx = ? AND subject = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		registryCtx, subject,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to delete subject meta: %w", err)
+	}
+	if !applied {
+		return storage.ErrSubjectMetaNotFound
+	}
+	return nil
 }
 
-// GetUserByUsername retrieves a user by username.
-func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
-	if username == "" {
-		return nil, storage.ErrUserNotFound
+// SetSchemaIDAlias creates or replaces the alias of a source registry's schema ID.
+func (s *Store) SetSchemaIDAlias(ctx context.Context, registryCtx string, alias *storage.SchemaIDAliasRecord) error {
+	if alias == nil {
+		return errors.New("schema ID alias is nil")
 	}
-	var userID int64
+	now := time.Now()
+	if err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.schema_id_aliases (registry_ctx, source, source_id, schema_id, updated_at) VALUES (?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		registryCtx, alias.Source, alias.SourceID, alias.SchemaID, now,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to set schema ID alias: %w", err)
+	}
+	alias.UpdatedAt = now
+	return nil
+}
+
+// GetSchemaIDAlias retrieves the alias of a source registry's schema ID.
+func (s *Store) GetSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) (*storage.SchemaIDAliasRecord, error) {
+	alias := &storage.SchemaIDAliasRecord{Source: source, SourceID: sourceID}
 	err := s.readQuery(
-		fmt.Sprintf(`SELECT user_id FROM %s.users_by_email WHERE email = ?`, qident(s.cfg.Keyspace)),
-		username,
-	).WithContext(ctx).Scan(&userID)
+		fmt.Sprintf(`SELECT schema_id, updated_at FROM %s.schema_id_aliases WHERE registry_ctx = ? AND source = ? AND source_id = ?`, qident(s.cfg.Keyspace)),
+		registryCtx, source, sourceID,
+	).WithContext(ctx).Scan(&alias.SchemaID, &alias.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, gocql.ErrNotFound) {
-			return nil, storage.ErrUserNotFound
+			return nil, storage.ErrIDAliasNotFound
 		}
-		return nil, err
+		return nil, fmt.Errorf("failed to get schema ID alias: %w", err)
 	}
-	return s.GetUserByID(ctx, userID)
+	return alias, nil
 }
 
-// UpdateUser updates a user.
-func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
-	if user == nil {
-		return errors.New("user is nil")
-	}
-	if user.ID == 0 {
-		return errors.New("user id is required")
-	}
-
-	existing, err := s.GetUserByID(ctx, user.ID)
+// DeleteSchemaIDAlias removes the alias of a source registry's schema ID.
+func (s *Store) DeleteSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) error {
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.schema_id_aliases WHERE registry_ctx = ? AND source = ? AND source_id = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		registryCtx, source, sourceID,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to delete schema ID alias: %w", err)
+	}
+	if !applied {
+		return storage.ErrIDAliasNotFound
 	}
+	return nil
+}
 
-	if existing.Username != user.Username {
-		existingByName, err := s.GetUserByUsername(ctx, user.Username)
-		if err == nil && existingByName.ID != user.ID {
-			return storage.ErrUserExists
+// ListSchemaIDAliases returns the aliases in a context, ordered by source and
+// source ID (the clustering order of the partition).
+func (s *Store) ListSchemaIDAliases(ctx context.Context, registryCtx string, source string) ([]*storage.SchemaIDAliasRecord, error) {
+	query := fmt.Sprintf(`SELECT source, source_id, schema_id, updated_at FROM %s.schema_id_aliases WHERE registry_ctx = ?`, qident(s.cfg.Keyspace))
+	args := []interface{}{registryCtx}
+	if source != "" {
+		query += ` AND source = ?`
+		args = append(args, source)
+	}
+	iter := s.readQuery(query, args...).WithContext(ctx).Iter()
+
+	aliases := make([]*storage.SchemaIDAliasRecord, 0)
+	for {
+		alias := &storage.SchemaIDAliasRecord{}
+		if !iter.Scan(&alias.Source, &alias.SourceID, &alias.SchemaID, &alias.UpdatedAt) {
+			break
 		}
+		aliases = append(aliases, alias)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list schema ID aliases: %w", err)
 	}
+	return aliases, nil
+}
 
-	user.UpdatedAt = time.Now()
-	updatedUUID := gocql.UUIDFromTime(user.UpdatedAt)
-	createdUUID := gocql.UUIDFromTime(user.CreatedAt)
+// FreezeVersion creates or replaces the freeze of a subject version.
+func (s *Store) FreezeVersion(ctx context.Context, registryCtx string, record *storage.FrozenVersionRecord) error {
+	if record == nil {
+		return errors.New("frozen version is nil")
+	}
+	now := time.Now()
+	if err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.frozen_versions (registry_ctx, subject, version, frozen_by, reason, frozen_at) VALUES (?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		registryCtx, record.Subject, record.Version, record.FrozenBy, record.Reason, now,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to freeze version: %w", err)
+	}
+	record.FrozenAt = now
+	return nil
+}
 
-	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+// GetFrozenVersion retrieves the freeze of a subject version.
+func (s *Store) GetFrozenVersion(ctx context.Context, registryCtx string, subject string, version int) (*storage.FrozenVersionRecord, error) {
+	record := &storage.FrozenVersionRecord{Subject: subject, Version: version}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT frozen_by, reason, frozen_at FROM %s.frozen_versions WHERE registry_ctx = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
+		registryCtx, subject, version,
+	).WithContext(ctx).Scan(&record.FrozenBy, &record.Reason, &record.FrozenAt)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrFrozenVersionNotFound
+		}
+		return nil, fmt.Errorf("failed to get frozen version: %w", err)
+	}
+	return record, nil
+}
+
+// UnfreezeVersion removes the freeze of a subject version.
+func (s *Store) UnfreezeVersion(ctx context.Context, registryCtx string, subject string, version int) error {
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.frozen_versions WHERE registry_ctx = ? AND subject = ? AND version = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		registryCtx, subject, version,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to unfreeze version: %w", err)
+	}
+	if !applied {
+		return storage.ErrFrozenVersionNotFound
+	}
+	return nil
+}
+
+// ListFrozenVersions returns the frozen versions in a context, ordered by
+// subject and version (the clustering order of the partition).
+func (s *Store) ListFrozenVersions(ctx context.Context, registryCtx string, subject string) ([]*storage.FrozenVersionRecord, error) {
+	query := fmt.Sprintf(`SELECT subject, version, frozen_by, reason, frozen_at FROM %s.frozen_versions WHERE registry_ctx = ?`, qident(s.cfg.Keyspace))
+	args := []interface{}{registryCtx}
+	if subject != "" {
+		query += ` AND subject = ?`
+		args = append(args, subject)
+	}
+	iter := s.readQuery(query, args...).WithContext(ctx).Iter()
+
+	records := make([]*storage.FrozenVersionRecord, 0)
+	for {
+		record := &storage.FrozenVersionRecord{}
+		if !iter.Scan(&record.Subject, &record.Version, &record.FrozenBy, &record.Reason, &record.FrozenAt) {
+			break
+		}
+		records = append(records, record)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list frozen versions: %w", err)
+	}
+	return records, nil
+}
+
+// SetReaderAssertion creates or replaces a consumer's assertion for a subject.
+func (s *Store) SetReaderAssertion(ctx context.Context, registryCtx string, record *storage.ReaderAssertionRecord) error {
+	if record == nil {
+		return errors.New("reader assertion is nil")
+	}
+	now := time.Now()
+	if err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.reader_assertions (registry_ctx, subject, consumer, reader_subject, reader_version, updated_at) VALUES (?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		registryCtx, record.Subject, record.Consumer, record.ReaderSubject, record.ReaderVersion, now,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to set reader assertion: %w", err)
+	}
+	record.UpdatedAt = now
+	return nil
+}
+
+// DeleteReaderAssertion removes a consumer's assertion for a subject.
+func (s *Store) DeleteReaderAssertion(ctx context.Context, registryCtx string, subject string, consumer string) error {
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.reader_assertions WHERE registry_ctx = ? AND subject = ? AND consumer = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		registryCtx, subject, consumer,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to delete reader assertion: %w", err)
+	}
+	if !applied {
+		return storage.ErrReaderAssertionNotFound
+	}
+	return nil
+}
+
+// ListReaderAssertions returns the reader assertions in a context, ordered
+// by subject and consumer (the clustering order of the partition).
+func (s *Store) ListReaderAssertions(ctx context.Context, registryCtx string, subject string) ([]*storage.ReaderAssertionRecord, error) {
+	query := fmt.Sprintf(`SELECT subject, consumer, reader_subject, reader_version, updated_at FROM %s.reader_assertions WHERE registry_ctx = ?`, qident(s.cfg.Keyspace))
+	args := []interface{}{registryCtx}
+	if subject != "" {
+		query += ` AND subject = ?`
+		args = append(args, subject)
+	}
+	iter := s.readQuery(query, args...).WithContext(ctx).Iter()
+
+	records := make([]*storage.ReaderAssertionRecord, 0)
+	for {
+		record := &storage.ReaderAssertionRecord{}
+		if !iter.Scan(&record.Subject, &record.Consumer, &record.ReaderSubject, &record.ReaderVersion, &record.UpdatedAt) {
+			break
+		}
+		records = append(records, record)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list reader assertions: %w", err)
+	}
+	return records, nil
+}
+
+// CreateTenant creates a new tenant.
+func (s *Store) CreateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
+	if tenant == nil {
+		return errors.New("tenant is nil")
+	}
+
+	now := time.Now()
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.tenants (name, description, encrypted_key, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+		tenant.Name, tenant.Description, tenant.EncryptedKey, now, now,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+	if !applied {
+		return storage.ErrTenantExists
+	}
+
+	tenant.CreatedAt = now
+	tenant.UpdatedAt = now
+	return nil
+}
+
+// GetTenant retrieves a tenant by name.
+func (s *Store) GetTenant(ctx context.Context, name string) (*storage.TenantRecord, error) {
+	tenant := &storage.TenantRecord{Name: name}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT description, encrypted_key, created_at, updated_at FROM %s.tenants WHERE name = ?`, qident(s.cfg.Keyspace)),
+		name,
+	).WithContext(ctx).Scan(&tenant.Description, &tenant.EncryptedKey, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return tenant, nil
+}
+
+// UpdateTenant updates the description of an existing tenant.
+func (s *Store) UpdateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
+	if tenant == nil {
+		return errors.New("tenant is nil")
+	}
+
+	now := time.Now()
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.tenants SET description = ?, updated_at = ? WHERE name = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		tenant.Description, now, tenant.Name,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+	if !applied {
+		return storage.ErrTenantNotFound
+	}
+
+	existing, err := s.GetTenant(ctx, tenant.Name)
+	if err != nil {
+		return err
+	}
+	*tenant = *existing
+	return nil
+}
+
+// DeleteTenant deletes a tenant by name.
+func (s *Store) DeleteTenant(ctx context.Context, name string) error {
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.tenants WHERE name = ? IF EXISTS`, qident(s.cfg.Keyspace)),
+		name,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	if !applied {
+		return storage.ErrTenantNotFound
+	}
+	return nil
+}
+
+// ListTenants returns all tenants ordered by name.
+func (s *Store) ListTenants(ctx context.Context) ([]*storage.TenantRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT name, description, encrypted_key, created_at, updated_at FROM %s.tenants`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	tenants := make([]*storage.TenantRecord, 0)
+	var name, description, key string
+	var createdAt, updatedAt time.Time
+	for iter.Scan(&name, &description, &key, &createdAt, &updatedAt) {
+		tenants = append(tenants, &storage.TenantRecord{
+			Name:         name,
+			Description:  description,
+			EncryptedKey: key,
+			CreatedAt:    createdAt,
+			UpdatedAt:    updatedAt,
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants, nil
+}
+
+// ---------- User Operations ----------
+
+// CreateUser creates a new user.
+func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
+	if user == nil {
+		return errors.New("user is nil")
+	}
+	if user.Username == "" {
+		return errors.New("username is required")
+	}
+
+	existing, err := s.GetUserByUsername(ctx, user.Username)
+	if err == nil && existing != nil {
+		return storage.ErrUserExists
+	}
+
+	if user.ID == 0 {
+		id, err := s.NextID(ctx, ".")
+		if err != nil {
+			return err
+		}
+		user.ID = id
+	}
+
+	nowUUID := gocql.TimeUUID()
+	now := nowUUID.Time()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+
+	createdUUID := gocql.UUIDFromTime(user.CreatedAt)
+	updatedUUID := gocql.UUIDFromTime(user.UpdatedAt)
+
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
+	batch.Query(
+		fmt.Sprintf(`INSERT INTO %s.users_by_id (user_id, email, name, password_hash, roles, enabled, tenant, created_at, updated_at,
+			password_changed_at, must_change_password, password_history)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		user.ID, user.Email, user.Username, user.PasswordHash, []string{user.Role}, user.Enabled, user.Tenant, createdUUID, updatedUUID,
+		user.PasswordChangedAt, user.MustChangePassword, user.PasswordHistory,
+	)
+	batch.Query(
+		fmt.Sprintf(`INSERT INTO %s.users_by_email (email, user_id, name, password_hash, roles, enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		user.Username, user.ID, user.Username, user.PasswordHash, []string{user.Role}, user.Enabled, createdUUID, updatedUUID,
+	)
+	return s.session.ExecuteBatch(batch)
+}
+
+// GetUserByID retrieves a user by ID.
+func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
+	var email, name, pw, tenant string
+	var roles, history []string
+	var enabled, mustChange bool
+	var createdUUID, updatedUUID gocql.UUID
+	var changedAt time.Time
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT email, name, password_hash, roles, enabled, tenant, created_at, updated_at,
+			password_changed_at, must_change_password, password_history FROM %s.users_by_id WHERE user_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(&email, &name, &pw, &roles, &enabled, &tenant, &createdUUID, &updatedUUID,
+		&changedAt, &mustChange, &history)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	role := ""
+	if len(roles) > 0 {
+		role = roles[0]
+	}
+
+	return &storage.UserRecord{
+		ID:           id,
+		Username:     name,
+		Email:        email,
+		PasswordHash: pw,
+		Role:         role,
+		Enabled:      enabled,
+		Tenant:       tenant,
+		CreatedAt:    createdUUID.Time(),
+		UpdatedAt:    updatedUUID.Time(),
+
+		PasswordChangedAt:  changedAt,
+		MustChangePassword: mustChange,
+		PasswordHistory:    history,
+	}, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
+	if username == "" {
+		return nil, storage.ErrUserNotFound
+	}
+	var userID int64
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT user_id FROM %s.users_by_email WHERE email = ?`, qident(s.cfg.Keyspace)),
+		username,
+	).WithContext(ctx).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return s.GetUserByID(ctx, userID)
+}
+
+// UpdateUser updates a user.
+func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
+	if user == nil {
+		return errors.New("user is nil")
+	}
+	if user.ID == 0 {
+		return errors.New("user id is required")
+	}
+
+	existing, err := s.GetUserByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing.Username != user.Username {
+		existingByName, err := s.GetUserByUsername(ctx, user.Username)
+		if err == nil && existingByName.ID != user.ID {
+			return storage.ErrUserExists
+		}
+	}
+
+	user.UpdatedAt = time.Now()
+	updatedUUID := gocql.UUIDFromTime(user.UpdatedAt)
+	createdUUID := gocql.UUIDFromTime(user.CreatedAt)
+
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
 	batch.Query(
-		fmt.Sprintf(`UPDATE %s.users_by_id SET email = ?, name = ?, password_hash = ?, roles = ?, enabled = ?, updated_at = ? WHERE user_id = ?`, qident(s.cfg.Keyspace)),
-		user.Email, user.Username, user.PasswordHash, []string{user.Role}, user.Enabled, updatedUUID, user.ID,
+		fmt.Sprintf(`UPDATE %s.users_by_id SET email = ?, name = ?, password_hash = ?, roles = ?, enabled = ?, updated_at = ?,
+			password_changed_at = ?, must_change_password = ?, password_history = ? WHERE user_id = ?`, qident(s.cfg.Keyspace)),
+		user.Email, user.Username, user.PasswordHash, []string{user.Role}, user.Enabled, updatedUUID,
+		user.PasswordChangedAt, user.MustChangePassword, user.PasswordHistory, user.ID,
 	)
 
 	if existing.Username != user.Username {
@@ -2009,35 +2981,894 @@ func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error
 		user.Username, user.ID, user.Username, user.PasswordHash, []string{user.Role}, user.Enabled, createdUUID, updatedUUID,
 	)
 
-	return s.session.ExecuteBatch(batch)
+	return s.session.ExecuteBatch(batch)
+}
+
+// DeleteUser deletes a user.
+func (s *Store) DeleteUser(ctx context.Context, id int64) error {
+	u, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
+	batch.Query(fmt.Sprintf(`DELETE FROM %s.users_by_id WHERE user_id = ?`, qident(s.cfg.Keyspace)), id)
+	batch.Query(fmt.Sprintf(`DELETE FROM %s.users_by_email WHERE email = ?`, qident(s.cfg.Keyspace)), u.Username)
+	return s.session.ExecuteBatch(batch)
+}
+
+// ListUsers retrieves all users.
+func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT user_id FROM %s.users_by_id`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	var userID int64
+	var out []*storage.UserRecord
+	for iter.Scan(&userID) {
+		u, err := s.GetUserByID(ctx, userID)
+		if err == nil {
+			out = append(out, u)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// ---------- API Key Operations ----------
+
+// CreateAPIKey creates a new API key.
+func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	if key == nil {
+		return errors.New("api key is nil")
+	}
+	if key.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if key.KeyHash == "" {
+		return errors.New("key_hash is required")
+	}
+
+	if key.ID == 0 {
+		id, err := s.NextID(ctx, ".")
+		if err != nil {
+			return err
+		}
+		key.ID = id
+	}
+
+	createdUUID := gocql.TimeUUID()
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = createdUUID.Time()
+	} else {
+		createdUUID = gocql.UUIDFromTime(key.CreatedAt)
+	}
+
+	if !key.Enabled {
+		key.Enabled = true
+	}
+
+	if _, err := s.GetAPIKeyByHash(ctx, key.KeyHash); err == nil {
+		return storage.ErrAPIKeyExists
+	}
+	scopes, err := marshalAPIKeyScopes(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
+	batch.Query(
+		fmt.Sprintf(`INSERT INTO %s.api_keys_by_id (api_key_id, user_id, name, api_key_hash, key_prefix, role, enabled, created_at, expires_at, scopes)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		key.ID, key.UserID, key.Name, key.KeyHash, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt, scopes,
+	)
+	batch.Query(
+		fmt.Sprintf(`INSERT INTO %s.api_keys_by_user (user_id, api_key_id, name, api_key_hash, key_prefix, role, enabled, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		key.UserID, key.ID, key.Name, key.KeyHash, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt,
+	)
+	batch.Query(
+		fmt.Sprintf(`INSERT INTO %s.api_keys_by_hash (api_key_hash, api_key_id, user_id, name, key_prefix, role, enabled, created_at, expires_at, scopes)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		key.KeyHash, key.ID, key.UserID, key.Name, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt, scopes,
+	)
+	return s.session.ExecuteBatch(batch)
+}
+
+// GetAPIKeyByID retrieves an API key by ID.
+func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
+	var userID int64
+	var name, hash, keyPrefix, role, scopes string
+	var enabled bool
+	var createdUUID gocql.UUID
+	var expiresAt, lastUsed time.Time
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT user_id, name, api_key_hash, key_prefix, role, enabled, created_at, expires_at, last_used, scopes FROM %s.api_keys_by_id WHERE api_key_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(&userID, &name, &hash, &keyPrefix, &role, &enabled, &createdUUID, &expiresAt, &lastUsed, &scopes)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	rec := &storage.APIKeyRecord{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		KeyHash:   hash,
+		KeyPrefix: keyPrefix,
+		Role:      role,
+		Enabled:   enabled,
+		CreatedAt: createdUUID.Time(),
+		ExpiresAt: expiresAt,
+	}
+	if !lastUsed.IsZero() {
+		rec.LastUsed = &lastUsed
+	}
+	if err := unmarshalAPIKeyScopes(scopes, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// GetAPIKeyByHash retrieves an API key by its hash.
+func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
+	var keyID, userID int64
+	var name, keyPrefix, role, scopes string
+	var enabled bool
+	var createdUUID gocql.UUID
+	var expiresAt, lastUsed time.Time
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT api_key_id, user_id, name, key_prefix, role, enabled, created_at, expires_at, last_used, scopes FROM %s.api_keys_by_hash WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)),
+		keyHash,
+	).WithContext(ctx).Scan(&keyID, &userID, &name, &keyPrefix, &role, &enabled, &createdUUID, &expiresAt, &lastUsed, &scopes)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	rec := &storage.APIKeyRecord{
+		ID:        keyID,
+		UserID:    userID,
+		Name:      name,
+		KeyHash:   keyHash,
+		KeyPrefix: keyPrefix,
+		Role:      role,
+		Enabled:   enabled,
+		CreatedAt: createdUUID.Time(),
+		ExpiresAt: expiresAt,
+	}
+	if !lastUsed.IsZero() {
+		rec.LastUsed = &lastUsed
+	}
+	if err := unmarshalAPIKeyScopes(scopes, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
+func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
+	keys, err := s.ListAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	return nil, storage.ErrAPIKeyNotFound
+}
+
+// UpdateAPIKey updates an API key.
+func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	if key == nil {
+		return errors.New("api key is nil")
+	}
+	if key.ID == 0 {
+		return errors.New("api key id is required")
+	}
+
+	existing, err := s.GetAPIKeyByID(ctx, key.ID)
+	if err != nil {
+		return err
+	}
+
+	createdUUID := gocql.UUIDFromTime(key.CreatedAt)
+	scopes, err := marshalAPIKeyScopes(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
+	batch.Query(
+		fmt.Sprintf(`UPDATE %s.api_keys_by_id SET name = ?, key_prefix = ?, role = ?, enabled = ?, expires_at = ?, scopes = ? WHERE api_key_id = ?`, qident(s.cfg.Keyspace)),
+		key.Name, key.KeyPrefix, key.Role, key.Enabled, key.ExpiresAt, scopes, key.ID,
+	)
+	batch.Query(
+		fmt.Sprintf(`UPDATE %s.api_keys_by_user SET name = ?, key_prefix = ?, role = ?, enabled = ?, expires_at = ? WHERE user_id = ? AND api_key_id = ?`, qident(s.cfg.Keyspace)),
+		key.Name, key.KeyPrefix, key.Role, key.Enabled, key.ExpiresAt, key.UserID, key.ID,
+	)
+
+	if existing.KeyHash != key.KeyHash {
+		batch.Query(
+			fmt.Sprintf(`DELETE FROM %s.api_keys_by_hash WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)),
+			existing.KeyHash,
+		)
+		batch.Query(
+			fmt.Sprintf(`INSERT INTO %s.api_keys_by_hash (api_key_hash, api_key_id, user_id, name, key_prefix, role, enabled, created_at, expires_at, scopes)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+			key.KeyHash, key.ID, key.UserID, key.Name, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt, scopes,
+		)
+	} else {
+		batch.Query(
+			fmt.Sprintf(`UPDATE %s.api_keys_by_hash SET name = ?, key_prefix = ?, role = ?, enabled = ?, expires_at = ?, scopes = ? WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)),
+			key.Name, key.KeyPrefix, key.Role, key.Enabled, key.ExpiresAt, scopes, key.KeyHash,
+		)
+	}
+
+	return s.session.ExecuteBatch(batch)
+}
+
+// marshalAPIKeyScopes encodes the scopes of an API key for the scopes
+// column, which is empty for a key without scopes.
+func marshalAPIKeyScopes(scopes []storage.APIKeyScope) (string, error) {
+	if len(scopes) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(scopes)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal API key scopes: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalAPIKeyScopes sets the scopes of an API key from its scopes
+// column.
+func unmarshalAPIKeyScopes(scopes string, key *storage.APIKeyRecord) error {
+	if scopes == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return fmt.Errorf("failed to unmarshal API key scopes: %w", err)
+	}
+	return nil
+}
+
+// DeleteAPIKey deletes an API key.
+func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
+	rec, err := s.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
+	batch.Query(fmt.Sprintf(`DELETE FROM %s.api_keys_by_id WHERE api_key_id = ?`, qident(s.cfg.Keyspace)), id)
+	batch.Query(fmt.Sprintf(`DELETE FROM %s.api_keys_by_user WHERE user_id = ? AND api_key_id = ?`, qident(s.cfg.Keyspace)), rec.UserID, id)
+	batch.Query(fmt.Sprintf(`DELETE FROM %s.api_keys_by_hash WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)), rec.KeyHash)
+	return s.session.ExecuteBatch(batch)
+}
+
+// ---------- Role Grant Operations ----------
+
+// CreateGrant creates a new role grant.
+func (s *Store) CreateGrant(ctx context.Context, grant *storage.GrantRecord) error {
+	if grant == nil {
+		return errors.New("grant is nil")
+	}
+	if grant.ID == 0 {
+		id, err := s.NextID(ctx, ".")
+		if err != nil {
+			return err
+		}
+		grant.ID = id
+	}
+	grant.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+
+	return s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.role_grants (grant_id, username, api_key_id, role, registry_ctx, subject_prefix, created_by, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		grant.ID, grant.Username, grant.APIKeyID, grant.Role, grant.Context, grant.SubjectPrefix, grant.CreatedBy, grant.CreatedAt,
+	).WithContext(ctx).Exec()
+}
+
+// GetGrantByID retrieves a role grant by ID.
+func (s *Store) GetGrantByID(ctx context.Context, id int64) (*storage.GrantRecord, error) {
+	grant := &storage.GrantRecord{ID: id}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT username, api_key_id, role, registry_ctx, subject_prefix, created_by, created_at FROM %s.role_grants WHERE grant_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(&grant.Username, &grant.APIKeyID, &grant.Role, &grant.Context, &grant.SubjectPrefix, &grant.CreatedBy, &grant.CreatedAt)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrGrantNotFound
+		}
+		return nil, err
+	}
+	return grant, nil
+}
+
+// DeleteGrant deletes a role grant.
+func (s *Store) DeleteGrant(ctx context.Context, id int64) error {
+	if _, err := s.GetGrantByID(ctx, id); err != nil {
+		return err
+	}
+	return s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.role_grants WHERE grant_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Exec()
+}
+
+// ListGrants retrieves all role grants.
+func (s *Store) ListGrants(ctx context.Context) ([]*storage.GrantRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT grant_id, username, api_key_id, role, registry_ctx, subject_prefix, created_by, created_at FROM %s.role_grants`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	out := []*storage.GrantRecord{}
+	for {
+		grant := &storage.GrantRecord{}
+		if !iter.Scan(&grant.ID, &grant.Username, &grant.APIKeyID, &grant.Role, &grant.Context, &grant.SubjectPrefix, &grant.CreatedBy, &grant.CreatedAt) {
+			break
+		}
+		out = append(out, grant)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// ---------- Async Job Operations ----------
+
+// AddSchemaUsage adds fetch counts to the schema usage buckets.
+func (s *Store) AddSchemaUsage(ctx context.Context, records []*storage.SchemaUsageRecord) error {
+	for _, rec := range records {
+		if err := s.writeQuery(
+			fmt.Sprintf(`UPDATE %s.schema_usage SET fetch_count = fetch_count + ?
+				WHERE registry_ctx = ? AND usage_day = ? AND schema_id = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
+			rec.Count, rec.Context, usageDay(rec.Day), rec.SchemaID, rec.Subject, rec.Version,
+		).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to add schema usage: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListSchemaUsage returns the schema usage buckets for days on or after since.
+func (s *Store) ListSchemaUsage(ctx context.Context, since time.Time) ([]*storage.SchemaUsageRecord, error) {
+	contexts, err := s.ListContexts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []*storage.SchemaUsageRecord{}
+	for _, registryCtx := range contexts {
+		iter := s.readQuery(
+			fmt.Sprintf(`SELECT usage_day, schema_id, subject, version, fetch_count
+				FROM %s.schema_usage WHERE registry_ctx = ? AND usage_day >= ?`, qident(s.cfg.Keyspace)),
+			registryCtx, usageDay(since),
+		).WithContext(ctx).Iter()
+		for {
+			rec := &storage.SchemaUsageRecord{Context: registryCtx}
+			if !iter.Scan(&rec.Day, &rec.SchemaID, &rec.Subject, &rec.Version, &rec.Count) {
+				break
+			}
+			rec.Day = usageDay(rec.Day)
+			out = append(out, rec)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to list schema usage: %w", err)
+		}
+	}
+	return out, nil
+}
+
+// usageDay returns midnight UTC of the day containing t.
+func usageDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+const jobColumns = `job_id, type, state, registry_ctx, params, result, error, message, done, total, cancel_requested, created_by, created_at, updated_at, started_at, finished_at`
+
+// CreateJob creates a new async job.
+func (s *Store) CreateJob(ctx context.Context, job *storage.JobRecord) error {
+	if job == nil {
+		return errors.New("job is nil")
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.jobs (`+jobColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+		job.ID, job.Type, job.State, job.Context, job.Params, job.Result, job.Error, job.Message,
+		job.Done, job.Total, false, job.CreatedBy, now, now, job.StartedAt, job.FinishedAt,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	if !applied {
+		return storage.ErrJobExists
+	}
+
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	return nil
+}
+
+// GetJob retrieves an async job by ID.
+func (s *Store) GetJob(ctx context.Context, id string) (*storage.JobRecord, error) {
+	job := &storage.JobRecord{}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT `+jobColumns+` FROM %s.jobs WHERE job_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(jobScanDest(job)...)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateJob updates an async job's state, progress and result. Only the
+// mutable columns are written so a concurrent cancellation request survives.
+func (s *Store) UpdateJob(ctx context.Context, job *storage.JobRecord) error {
+	if _, err := s.GetJob(ctx, job.ID); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.jobs SET state = ?, result = ?, error = ?, message = ?, done = ?, total = ?,
+			updated_at = ?, started_at = ?, finished_at = ? WHERE job_id = ?`, qident(s.cfg.Keyspace)),
+		job.State, job.Result, job.Error, job.Message, job.Done, job.Total,
+		now, job.StartedAt, job.FinishedAt, job.ID,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	job.UpdatedAt = now
+	return nil
+}
+
+// RequestJobCancel marks an async job for cancellation.
+func (s *Store) RequestJobCancel(ctx context.Context, id string) error {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return err
+	}
+	return s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.jobs SET cancel_requested = true WHERE job_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Exec()
+}
+
+// ListJobs returns all async jobs, newest first.
+func (s *Store) ListJobs(ctx context.Context) ([]*storage.JobRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT `+jobColumns+` FROM %s.jobs`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	out := []*storage.JobRecord{}
+	for {
+		job := &storage.JobRecord{}
+		if !iter.Scan(jobScanDest(job)...) {
+			break
+		}
+		out = append(out, job)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	return out, nil
+}
+
+// DeleteJob deletes an async job.
+func (s *Store) DeleteJob(ctx context.Context, id string) error {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return err
+	}
+	return s.writeQuery(
+		fmt.Sprintf(`DELETE FROM %s.jobs WHERE job_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Exec()
+}
+
+const importSessionColumns = `session_id, registry_ctx, subjects, state, modes, schemas, staged, created_by, created_at, updated_at`
+
+// CreateImportSession creates a new import session.
+func (s *Store) CreateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
+	if session == nil {
+		return errors.New("import session is nil")
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.import_sessions (`+importSessionColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+		session.ID, session.Context, session.Subjects, session.State, session.Modes,
+		session.Schemas, session.Staged, session.CreatedBy, now, now,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to create import session: %w", err)
+	}
+	if !applied {
+		return storage.ErrImportSessionExists
+	}
+
+	session.CreatedAt = now
+	session.UpdatedAt = now
+	return nil
+}
+
+// GetImportSession retrieves an import session by ID.
+func (s *Store) GetImportSession(ctx context.Context, id string) (*storage.ImportSessionRecord, error) {
+	session := &storage.ImportSessionRecord{}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT `+importSessionColumns+` FROM %s.import_sessions WHERE session_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(importSessionScanDest(session)...)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrImportSessionNotFound
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// UpdateImportSession updates an import session's state and staged schemas.
+func (s *Store) UpdateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
+	if _, err := s.GetImportSession(ctx, session.ID); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.import_sessions SET state = ?, schemas = ?, staged = ?, updated_at = ?
+			WHERE session_id = ?`, qident(s.cfg.Keyspace)),
+		session.State, session.Schemas, session.Staged, now, session.ID,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to update import session: %w", err)
+	}
+	session.UpdatedAt = now
+	return nil
+}
+
+// ListImportSessions returns all import sessions, newest first.
+func (s *Store) ListImportSessions(ctx context.Context) ([]*storage.ImportSessionRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT `+importSessionColumns+` FROM %s.import_sessions`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	out := []*storage.ImportSessionRecord{}
+	for {
+		session := &storage.ImportSessionRecord{}
+		if !iter.Scan(importSessionScanDest(session)...) {
+			break
+		}
+		out = append(out, session)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	return out, nil
+}
+
+const maintenanceColumns = `window_id, starts_at, ends_at, reason, cancelled, created_by, created_at, updated_at`
+
+// CreateMaintenanceWindow creates a new maintenance window.
+func (s *Store) CreateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	if window == nil {
+		return errors.New("maintenance window is nil")
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.maintenance_windows (`+maintenanceColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+		window.ID, window.StartsAt, window.EndsAt, window.Reason, window.Cancelled,
+		window.CreatedBy, now, now,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	if !applied {
+		return storage.ErrMaintenanceExists
+	}
+
+	window.CreatedAt = now
+	window.UpdatedAt = now
+	return nil
+}
+
+// GetMaintenanceWindow retrieves a maintenance window by ID.
+func (s *Store) GetMaintenanceWindow(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error) {
+	window := &storage.MaintenanceWindowRecord{}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT `+maintenanceColumns+` FROM %s.maintenance_windows WHERE window_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(maintenanceScanDest(window)...)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrMaintenanceNotFound
+		}
+		return nil, err
+	}
+	return window, nil
+}
+
+// UpdateMaintenanceWindow updates a maintenance window's end time and cancellation.
+func (s *Store) UpdateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	if _, err := s.GetMaintenanceWindow(ctx, window.ID); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.maintenance_windows SET ends_at = ?, cancelled = ?, updated_at = ?
+			WHERE window_id = ?`, qident(s.cfg.Keyspace)),
+		window.EndsAt, window.Cancelled, now, window.ID,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+	window.UpdatedAt = now
+	return nil
+}
+
+// ListMaintenanceWindows returns all maintenance windows ordered by start time.
+func (s *Store) ListMaintenanceWindows(ctx context.Context) ([]*storage.MaintenanceWindowRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT `+maintenanceColumns+` FROM %s.maintenance_windows`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	out := []*storage.MaintenanceWindowRecord{}
+	for {
+		window := &storage.MaintenanceWindowRecord{}
+		if !iter.Scan(maintenanceScanDest(window)...) {
+			break
+		}
+		out = append(out, window)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartsAt.Equal(out[j].StartsAt) {
+			return out[i].StartsAt.Before(out[j].StartsAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+const pendingSchemaColumns = `pending_id, registry_ctx, subject, state, request, submitted_by, reviewers, reviewed_by, reason, schema_id, version, created_at, updated_at`
+
+// CreatePendingSchema creates a new pending schema.
+func (s *Store) CreatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
+	if pending == nil {
+		return errors.New("pending schema is nil")
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`INSERT INTO %s.pending_schemas (`+pendingSchemaColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
+		pending.ID, pending.Context, pending.Subject, pending.State, pending.Request, pending.SubmittedBy, pending.Reviewers,
+		pending.ReviewedBy, pending.Reason, pending.SchemaID, pending.Version, now, now,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to create pending schema: %w", err)
+	}
+	if !applied {
+		return storage.ErrPendingSchemaExists
+	}
+
+	pending.CreatedAt = now
+	pending.UpdatedAt = now
+	return nil
+}
+
+// GetPendingSchema retrieves a pending schema by ID.
+func (s *Store) GetPendingSchema(ctx context.Context, id string) (*storage.PendingSchemaRecord, error) {
+	pending := &storage.PendingSchemaRecord{}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT `+pendingSchemaColumns+` FROM %s.pending_schemas WHERE pending_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(pendingSchemaScanDest(pending)...)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrPendingSchemaNotFound
+		}
+		return nil, err
+	}
+	return pending, nil
+}
+
+// UpdatePendingSchema updates a pending schema's state, review and registration.
+func (s *Store) UpdatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
+	if _, err := s.GetPendingSchema(ctx, pending.ID); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.pending_schemas SET state = ?, reviewed_by = ?, reason = ?, schema_id = ?, version = ?, updated_at = ?
+			WHERE pending_id = ?`, qident(s.cfg.Keyspace)),
+		pending.State, pending.ReviewedBy, pending.Reason, pending.SchemaID, pending.Version, now, pending.ID,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to update pending schema: %w", err)
+	}
+	pending.UpdatedAt = now
+	return nil
+}
+
+// ListPendingSchemas returns all pending schemas, newest first.
+func (s *Store) ListPendingSchemas(ctx context.Context) ([]*storage.PendingSchemaRecord, error) {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT `+pendingSchemaColumns+` FROM %s.pending_schemas`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	out := []*storage.PendingSchemaRecord{}
+	for {
+		pending := &storage.PendingSchemaRecord{}
+		if !iter.Scan(pendingSchemaScanDest(pending)...) {
+			break
+		}
+		out = append(out, pending)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	return out, nil
+}
+
+// pendingSchemaScanDest returns the scan destinations for pendingSchemaColumns.
+func pendingSchemaScanDest(pending *storage.PendingSchemaRecord) []interface{} {
+	return []interface{}{&pending.ID, &pending.Context, &pending.Subject, &pending.State, &pending.Request,
+		&pending.SubmittedBy, &pending.Reviewers, &pending.ReviewedBy, &pending.Reason, &pending.SchemaID, &pending.Version,
+		&pending.CreatedAt, &pending.UpdatedAt}
+}
+
+// maintenanceScanDest returns the scan destinations for maintenanceColumns.
+func maintenanceScanDest(window *storage.MaintenanceWindowRecord) []interface{} {
+	return []interface{}{&window.ID, &window.StartsAt, &window.EndsAt, &window.Reason, &window.Cancelled,
+		&window.CreatedBy, &window.CreatedAt, &window.UpdatedAt}
+}
+
+// importSessionScanDest returns the scan destinations for importSessionColumns.
+func importSessionScanDest(session *storage.ImportSessionRecord) []interface{} {
+	return []interface{}{&session.ID, &session.Context, &session.Subjects, &session.State, &session.Modes,
+		&session.Schemas, &session.Staged, &session.CreatedBy, &session.CreatedAt, &session.UpdatedAt}
+}
+
+// jobScanDest returns the scan destinations for jobColumns.
+func jobScanDest(job *storage.JobRecord) []interface{} {
+	return []interface{}{&job.ID, &job.Type, &job.State, &job.Context, &job.Params, &job.Result,
+		&job.Error, &job.Message, &job.Done, &job.Total, &job.CancelRequested, &job.CreatedBy,
+		&job.CreatedAt, &job.UpdatedAt, &job.StartedAt, &job.FinishedAt}
+}
+
+// ---------- Share Token Operations ----------
+
+// CreateShareToken creates a new share token.
+func (s *Store) CreateShareToken(ctx context.Context, token *storage.ShareTokenRecord) error {
+	if token == nil {
+		return errors.New("share token is nil")
+	}
+	if token.TokenHash == "" {
+		return errors.New("token_hash is required")
+	}
+	if token.ID == 0 {
+		id, err := s.NextID(ctx, ".")
+		if err != nil {
+			return err
+		}
+		token.ID = id
+	}
+	token.CreatedAt = token.CreatedAt.UTC().Truncate(time.Millisecond)
+	token.ExpiresAt = token.ExpiresAt.UTC().Truncate(time.Millisecond)
+
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
+	batch.Query(
+		fmt.Sprintf(`INSERT INTO %s.share_tokens_by_id (token_id, token_hash, token_prefix, name, registry_ctx, subject, created_by, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
+		token.ID, token.TokenHash, token.TokenPrefix, token.Name, token.Context, token.Subject, token.CreatedBy, token.CreatedAt, token.ExpiresAt,
+	)
+	batch.Query(
+		fmt.Sprintf(`INSERT INTO %s.share_tokens_by_hash (token_hash, token_id) VALUES (?, ?)`, qident(s.cfg.Keyspace)),
+		token.TokenHash, token.ID,
+	)
+	return s.session.ExecuteBatch(batch)
+}
+
+// GetShareTokenByID retrieves a share token by ID.
+func (s *Store) GetShareTokenByID(ctx context.Context, id int64) (*storage.ShareTokenRecord, error) {
+	token := &storage.ShareTokenRecord{ID: id}
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT token_hash, token_prefix, name, registry_ctx, subject, created_by, created_at, expires_at FROM %s.share_tokens_by_id WHERE token_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	).WithContext(ctx).Scan(&token.TokenHash, &token.TokenPrefix, &token.Name, &token.Context, &token.Subject, &token.CreatedBy, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrShareTokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetShareTokenByHash retrieves a share token by the hash of its value.
+func (s *Store) GetShareTokenByHash(ctx context.Context, tokenHash string) (*storage.ShareTokenRecord, error) {
+	var id int64
+	err := s.readQuery(
+		fmt.Sprintf(`SELECT token_id FROM %s.share_tokens_by_hash WHERE token_hash = ?`, qident(s.cfg.Keyspace)),
+		tokenHash,
+	).WithContext(ctx).Scan(&id)
+	if err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, storage.ErrShareTokenNotFound
+		}
+		return nil, err
+	}
+	return s.GetShareTokenByID(ctx, id)
 }
 
-// DeleteUser deletes a user.
-func (s *Store) DeleteUser(ctx context.Context, id int64) error {
-	u, err := s.GetUserByID(ctx, id)
+// DeleteShareToken deletes a share token.
+func (s *Store) DeleteShareToken(ctx context.Context, id int64) error {
+	token, err := s.GetShareTokenByID(ctx, id)
 	if err != nil {
 		return err
 	}
-
-	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
-	batch.Query(fmt.Sprintf(`DELETE FROM %s.users_by_id WHERE user_id = ?`, qident(s.cfg.Keyspace)), id)
-	batch.Query(fmt.Sprintf(`DELETE FROM %s.users_by_email WHERE email = ?`, qident(s.cfg.Keyspace)), u.Username)
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.share_tokens_by_id WHERE token_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	)
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.share_tokens_by_hash WHERE token_hash = ?`, qident(s.cfg.Keyspace)),
+		token.TokenHash,
+	)
 	return s.session.ExecuteBatch(batch)
 }
 
-// ListUsers retrieves all users.
-func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
+// ListShareTokens retrieves all share tokens.
+func (s *Store) ListShareTokens(ctx context.Context) ([]*storage.ShareTokenRecord, error) {
 	iter := s.readQuery(
-		fmt.Sprintf(`SELECT user_id FROM %s.users_by_id`, qident(s.cfg.Keyspace)),
+		fmt.Sprintf(`SELECT token_id, token_hash, token_prefix, name, registry_ctx, subject, created_by, created_at, expires_at FROM %s.share_tokens_by_id`, qident(s.cfg.Keyspace)),
 	).WithContext(ctx).Iter()
 
-	var userID int64
-	var out []*storage.UserRecord
-	for iter.Scan(&userID) {
-		u, err := s.GetUserByID(ctx, userID)
-		if err == nil {
-			out = append(out, u)
+	out := []*storage.ShareTokenRecord{}
+	for {
+		token := &storage.ShareTokenRecord{}
+		if !iter.Scan(&token.ID, &token.TokenHash, &token.TokenPrefix, &token.Name, &token.Context, &token.Subject, &token.CreatedBy, &token.CreatedAt, &token.ExpiresAt) {
+			break
 		}
+		out = append(out, token)
 	}
 	if err := iter.Close(); err != nil {
 		return nil, err
@@ -2046,204 +3877,187 @@ func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
 	return out, nil
 }
 
-// ---------- API Key Operations ----------
-
-// CreateAPIKey creates a new API key.
-func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
-	if key == nil {
-		return errors.New("api key is nil")
-	}
-	if key.UserID == 0 {
-		return errors.New("user_id is required")
-	}
-	if key.KeyHash == "" {
-		return errors.New("key_hash is required")
-	}
-
-	if key.ID == 0 {
-		id, err := s.NextID(ctx, ".")
-		if err != nil {
-			return err
-		}
-		key.ID = id
-	}
+// ---------- Session Operations ----------
 
-	createdUUID := gocql.TimeUUID()
-	if key.CreatedAt.IsZero() {
-		key.CreatedAt = createdUUID.Time()
-	} else {
-		createdUUID = gocql.UUIDFromTime(key.CreatedAt)
+// sessionTTL returns the TTL, in seconds, of the rows of a session that
+// expires at expiresAt.
+func sessionTTL(expiresAt time.Time) int {
+	ttl := int(time.Until(expiresAt).Seconds()) + 1
+	if ttl < 1 {
+		ttl = 1
 	}
+	return ttl
+}
 
-	if !key.Enabled {
-		key.Enabled = true
+// CreateSession creates a new login session.
+func (s *Store) CreateSession(ctx context.Context, session *storage.SessionRecord) error {
+	if session == nil {
+		return errors.New("session is nil")
 	}
-
-	if _, err := s.GetAPIKeyByHash(ctx, key.KeyHash); err == nil {
-		return storage.ErrAPIKeyExists
+	if session.ID == "" {
+		return errors.New("session_id is required")
 	}
+	session.CreatedAt = session.CreatedAt.UTC().Truncate(time.Millisecond)
+	session.RefreshedAt = session.RefreshedAt.UTC().Truncate(time.Millisecond)
+	session.ExpiresAt = session.ExpiresAt.UTC().Truncate(time.Millisecond)
+	ttl := sessionTTL(session.ExpiresAt)
 
 	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
 	batch.Query(
-		fmt.Sprintf(`INSERT INTO %s.api_keys_by_id (api_key_id, user_id, name, api_key_hash, key_prefix, role, enabled, created_at, expires_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
-		key.ID, key.UserID, key.Name, key.KeyHash, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt,
-	)
-	batch.Query(
-		fmt.Sprintf(`INSERT INTO %s.api_keys_by_user (user_id, api_key_id, name, api_key_hash, key_prefix, role, enabled, created_at, expires_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
-		key.UserID, key.ID, key.Name, key.KeyHash, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt,
-	)
-	batch.Query(
-		fmt.Sprintf(`INSERT INTO %s.api_keys_by_hash (api_key_hash, api_key_id, user_id, name, key_prefix, role, enabled, created_at, expires_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
-		key.KeyHash, key.ID, key.UserID, key.Name, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt,
+		fmt.Sprintf(`INSERT INTO %s.sessions_by_id (session_id, refresh_hash, user_id, api_key_id, username, role, tenant, auth_method, created_at, refreshed_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?`, qident(s.cfg.Keyspace)),
+		session.ID, session.RefreshHash, session.UserID, session.APIKeyID, session.Username, session.Role,
+		session.Tenant, session.AuthMethod, session.CreatedAt, session.RefreshedAt, session.ExpiresAt, ttl,
 	)
+	if session.UserID > 0 {
+		batch.Query(
+			fmt.Sprintf(`INSERT INTO %s.sessions_by_user (user_id, session_id) VALUES (?, ?) USING TTL ?`, qident(s.cfg.Keyspace)),
+			session.UserID, session.ID, ttl,
+		)
+	}
 	return s.session.ExecuteBatch(batch)
 }
 
-// GetAPIKeyByID retrieves an API key by ID.
-func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
-	var userID int64
-	var name, hash, keyPrefix, role string
-	var enabled bool
-	var createdUUID gocql.UUID
-	var expiresAt, lastUsed time.Time
+// GetSession retrieves a login session by ID.
+func (s *Store) GetSession(ctx context.Context, id string) (*storage.SessionRecord, error) {
+	session := &storage.SessionRecord{ID: id}
 	err := s.readQuery(
-		fmt.Sprintf(`SELECT user_id, name, api_key_hash, key_prefix, role, enabled, created_at, expires_at, last_used FROM %s.api_keys_by_id WHERE api_key_id = ?`, qident(s.cfg.Keyspace)),
+		fmt.Sprintf(`SELECT refresh_hash, user_id, api_key_id, username, role, tenant, auth_method, created_at, refreshed_at, expires_at FROM %s.sessions_by_id WHERE session_id = ?`, qident(s.cfg.Keyspace)),
 		id,
-	).WithContext(ctx).Scan(&userID, &name, &hash, &keyPrefix, &role, &enabled, &createdUUID, &expiresAt, &lastUsed)
+	).WithContext(ctx).Scan(&session.RefreshHash, &session.UserID, &session.APIKeyID, &session.Username, &session.Role,
+		&session.Tenant, &session.AuthMethod, &session.CreatedAt, &session.RefreshedAt, &session.ExpiresAt)
 	if err != nil {
 		if errors.Is(err, gocql.ErrNotFound) {
-			return nil, storage.ErrAPIKeyNotFound
+			return nil, storage.ErrSessionNotFound
 		}
 		return nil, err
 	}
-
-	rec := &storage.APIKeyRecord{
-		ID:        id,
-		UserID:    userID,
-		Name:      name,
-		KeyHash:   hash,
-		KeyPrefix: keyPrefix,
-		Role:      role,
-		Enabled:   enabled,
-		CreatedAt: createdUUID.Time(),
-		ExpiresAt: expiresAt,
-	}
-	if !lastUsed.IsZero() {
-		rec.LastUsed = &lastUsed
-	}
-	return rec, nil
+	return session, nil
 }
 
-// GetAPIKeyByHash retrieves an API key by its hash.
-func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
-	var keyID, userID int64
-	var name, keyPrefix, role string
-	var enabled bool
-	var createdUUID gocql.UUID
-	var expiresAt, lastUsed time.Time
-	err := s.readQuery(
-		fmt.Sprintf(`SELECT api_key_id, user_id, name, key_prefix, role, enabled, created_at, expires_at, last_used FROM %s.api_keys_by_hash WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)),
-		keyHash,
-	).WithContext(ctx).Scan(&keyID, &userID, &name, &keyPrefix, &role, &enabled, &createdUUID, &expiresAt, &lastUsed)
+// RefreshSession stores a session's new refresh token hash if its current
+// one is oldRefreshHash. Every column is rewritten so that the whole row
+// gets the TTL of the new expiry.
+func (s *Store) RefreshSession(ctx context.Context, session *storage.SessionRecord, oldRefreshHash string) error {
+	session.RefreshedAt = session.RefreshedAt.UTC().Truncate(time.Millisecond)
+	session.ExpiresAt = session.ExpiresAt.UTC().Truncate(time.Millisecond)
+	ttl := sessionTTL(session.ExpiresAt)
+
+	applied, err := s.writeQuery(
+		fmt.Sprintf(`UPDATE %s.sessions_by_id USING TTL ? SET refresh_hash = ?, user_id = ?, api_key_id = ?, username = ?, role = ?, tenant = ?, auth_method = ?, created_at = ?, refreshed_at = ?, expires_at = ?
+			WHERE session_id = ? IF refresh_hash = ?`, qident(s.cfg.Keyspace)),
+		ttl, session.RefreshHash, session.UserID, session.APIKeyID, session.Username, session.Role, session.Tenant,
+		session.AuthMethod, session.CreatedAt, session.RefreshedAt, session.ExpiresAt, session.ID, oldRefreshHash,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
 	if err != nil {
-		if errors.Is(err, gocql.ErrNotFound) {
-			return nil, storage.ErrAPIKeyNotFound
-		}
-		return nil, err
+		return fmt.Errorf("failed to refresh session: %w", err)
 	}
-
-	rec := &storage.APIKeyRecord{
-		ID:        keyID,
-		UserID:    userID,
-		Name:      name,
-		KeyHash:   keyHash,
-		KeyPrefix: keyPrefix,
-		Role:      role,
-		Enabled:   enabled,
-		CreatedAt: createdUUID.Time(),
-		ExpiresAt: expiresAt,
+	if !applied {
+		return storage.ErrSessionNotFound
 	}
-	if !lastUsed.IsZero() {
-		rec.LastUsed = &lastUsed
+	if session.UserID > 0 {
+		return s.writeQuery(
+			fmt.Sprintf(`INSERT INTO %s.sessions_by_user (user_id, session_id) VALUES (?, ?) USING TTL ?`, qident(s.cfg.Keyspace)),
+			session.UserID, session.ID, ttl,
+		).WithContext(ctx).Exec()
 	}
-	return rec, nil
+	return nil
 }
 
-// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
-func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
-	keys, err := s.ListAPIKeysByUserID(ctx, userID)
+// DeleteSession deletes a login session by ID.
+func (s *Store) DeleteSession(ctx context.Context, id string) error {
+	session, err := s.GetSession(ctx, id)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for _, k := range keys {
-		if k.Name == name {
-			return k, nil
-		}
+	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.sessions_by_id WHERE session_id = ?`, qident(s.cfg.Keyspace)),
+		id,
+	)
+	if session.UserID > 0 {
+		batch.Query(
+			fmt.Sprintf(`DELETE FROM %s.sessions_by_user WHERE user_id = ? AND session_id = ?`, qident(s.cfg.Keyspace)),
+			session.UserID, id,
+		)
 	}
-	return nil, storage.ErrAPIKeyNotFound
+	return s.session.ExecuteBatch(batch)
 }
 
-// UpdateAPIKey updates an API key.
-func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
-	if key == nil {
-		return errors.New("api key is nil")
-	}
-	if key.ID == 0 {
-		return errors.New("api key id is required")
-	}
+// DeleteUserSessions deletes every login session of a user.
+func (s *Store) DeleteUserSessions(ctx context.Context, userID int64) error {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT session_id FROM %s.sessions_by_user WHERE user_id = ?`, qident(s.cfg.Keyspace)),
+		userID,
+	).WithContext(ctx).Iter()
 
-	existing, err := s.GetAPIKeyByID(ctx, key.ID)
-	if err != nil {
+	var ids []string
+	var id string
+	for iter.Scan(&id) {
+		ids = append(ids, id)
+	}
+	if err := iter.Close(); err != nil {
 		return err
 	}
 
-	createdUUID := gocql.UUIDFromTime(key.CreatedAt)
-
 	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
-	batch.Query(
-		fmt.Sprintf(`UPDATE %s.api_keys_by_id SET name = ?, key_prefix = ?, role = ?, enabled = ?, expires_at = ? WHERE api_key_id = ?`, qident(s.cfg.Keyspace)),
-		key.Name, key.KeyPrefix, key.Role, key.Enabled, key.ExpiresAt, key.ID,
-	)
-	batch.Query(
-		fmt.Sprintf(`UPDATE %s.api_keys_by_user SET name = ?, key_prefix = ?, role = ?, enabled = ?, expires_at = ? WHERE user_id = ? AND api_key_id = ?`, qident(s.cfg.Keyspace)),
-		key.Name, key.KeyPrefix, key.Role, key.Enabled, key.ExpiresAt, key.UserID, key.ID,
-	)
-
-	if existing.KeyHash != key.KeyHash {
-		batch.Query(
-			fmt.Sprintf(`DELETE FROM %s.api_keys_by_hash WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)),
-			existing.KeyHash,
-		)
-		batch.Query(
-			fmt.Sprintf(`INSERT INTO %s.api_keys_by_hash (api_key_hash, api_key_id, user_id, name, key_prefix, role, enabled, created_at, expires_at)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, qident(s.cfg.Keyspace)),
-			key.KeyHash, key.ID, key.UserID, key.Name, key.KeyPrefix, key.Role, key.Enabled, createdUUID, key.ExpiresAt,
-		)
-	} else {
+	for _, id := range ids {
 		batch.Query(
-			fmt.Sprintf(`UPDATE %s.api_keys_by_hash SET name = ?, key_prefix = ?, role = ?, enabled = ?, expires_at = ? WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)),
-			key.Name, key.KeyPrefix, key.Role, key.Enabled, key.ExpiresAt, key.KeyHash,
+			fmt.Sprintf(`DELETE FROM %s.sessions_by_id WHERE session_id = ?`, qident(s.cfg.Keyspace)),
+			id,
 		)
 	}
-
+	batch.Query(
+		fmt.Sprintf(`DELETE FROM %s.sessions_by_user WHERE user_id = ?`, qident(s.cfg.Keyspace)),
+		userID,
+	)
 	return s.session.ExecuteBatch(batch)
 }
 
-// DeleteAPIKey deletes an API key.
-func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
-	rec, err := s.GetAPIKeyByID(ctx, id)
-	if err != nil {
+// DeleteExpiredSessions deletes the login sessions that expired before the
+// given time. Session rows also carry a TTL, so Cassandra drops expired
+// sessions itself when this is not called.
+func (s *Store) DeleteExpiredSessions(ctx context.Context, before time.Time) error {
+	iter := s.readQuery(
+		fmt.Sprintf(`SELECT session_id, user_id, expires_at FROM %s.sessions_by_id`, qident(s.cfg.Keyspace)),
+	).WithContext(ctx).Iter()
+
+	type expiredSession struct {
+		id     string
+		userID int64
+	}
+	var expired []expiredSession
+	var (
+		id        string
+		userID    int64
+		expiresAt time.Time
+	)
+	for iter.Scan(&id, &userID, &expiresAt) {
+		if expiresAt.Before(before) {
+			expired = append(expired, expiredSession{id: id, userID: userID})
+		}
+	}
+	if err := iter.Close(); err != nil {
 		return err
 	}
 
-	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
-	batch.Query(fmt.Sprintf(`DELETE FROM %s.api_keys_by_id WHERE api_key_id = ?`, qident(s.cfg.Keyspace)), id)
-	batch.Query(fmt.Sprintf(`DELETE FROM %s.api_keys_by_user WHERE user_id = ? AND api_key_id = ?`, qident(s.cfg.Keyspace)), rec.UserID, id)
-	batch.Query(fmt.Sprintf(`DELETE FROM %s.api_keys_by_hash WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)), rec.KeyHash)
-	return s.session.ExecuteBatch(batch)
+	for _, session := range expired {
+		batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		batch.Query(
+			fmt.Sprintf(`DELETE FROM %s.sessions_by_id WHERE session_id = ?`, qident(s.cfg.Keyspace)),
+			session.id,
+		)
+		if session.userID > 0 {
+			batch.Query(
+				fmt.Sprintf(`DELETE FROM %s.sessions_by_user WHERE user_id = ? AND session_id = ?`, qident(s.cfg.Keyspace)),
+				session.userID, session.id,
+			)
+		}
+		if err := s.session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ListAPIKeys retrieves all API keys.
@@ -2295,7 +4109,7 @@ func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
 	}
 
 	now := time.Now()
-	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
 	batch.Query(
 		fmt.Sprintf(`UPDATE %s.api_keys_by_id SET last_used = ? WHERE api_key_id = ?`, qident(s.cfg.Keyspace)),
 		now, id,
@@ -2311,6 +4125,77 @@ func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
 	return s.session.ExecuteBatch(batch)
 }
 
+// AddAPIKeyUsage adds request counts to the API key usage buckets and moves
+// the keys' last-used times forward.
+func (s *Store) AddAPIKeyUsage(ctx context.Context, records []*storage.APIKeyUsageRecord) error {
+	for _, rec := range records {
+		key, err := s.GetAPIKeyByID(ctx, rec.APIKeyID)
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.writeQuery(
+			fmt.Sprintf(`UPDATE %s.api_key_usage SET request_count = request_count + ?
+				WHERE api_key_id = ? AND usage_day = ?`, qident(s.cfg.Keyspace)),
+			rec.Requests, rec.APIKeyID, usageDay(rec.Day),
+		).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to add API key usage: %w", err)
+		}
+		if rec.LastUsed.IsZero() || (key.LastUsed != nil && !rec.LastUsed.After(*key.LastUsed)) {
+			continue
+		}
+		batch := s.writeBatch(ctx, gocql.LoggedBatch)
+		batch.Query(
+			fmt.Sprintf(`UPDATE %s.api_keys_by_id SET last_used = ? WHERE api_key_id = ?`, qident(s.cfg.Keyspace)),
+			rec.LastUsed, key.ID,
+		)
+		batch.Query(
+			fmt.Sprintf(`UPDATE %s.api_keys_by_user SET last_used = ? WHERE user_id = ? AND api_key_id = ?`, qident(s.cfg.Keyspace)),
+			rec.LastUsed, key.UserID, key.ID,
+		)
+		batch.Query(
+			fmt.Sprintf(`UPDATE %s.api_keys_by_hash SET last_used = ? WHERE api_key_hash = ?`, qident(s.cfg.Keyspace)),
+			rec.LastUsed, key.KeyHash,
+		)
+		if err := s.session.ExecuteBatch(batch); err != nil {
+			return fmt.Errorf("failed to update API key last used: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListAPIKeyUsage returns the API key usage buckets for days on or after
+// since.
+func (s *Store) ListAPIKeyUsage(ctx context.Context, since time.Time) ([]*storage.APIKeyUsageRecord, error) {
+	keys, err := s.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []*storage.APIKeyUsageRecord{}
+	for _, key := range keys {
+		iter := s.readQuery(
+			fmt.Sprintf(`SELECT usage_day, request_count FROM %s.api_key_usage
+				WHERE api_key_id = ? AND usage_day >= ?`, qident(s.cfg.Keyspace)),
+			key.ID, usageDay(since),
+		).WithContext(ctx).Iter()
+		for {
+			rec := &storage.APIKeyUsageRecord{APIKeyID: key.ID}
+			if !iter.Scan(&rec.Day, &rec.Requests) {
+				break
+			}
+			rec.Day = usageDay(rec.Day)
+			out = append(out, rec)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to list API key usage: %w", err)
+		}
+	}
+	return out, nil
+}
+
 // ---------- Helpers ----------
 
 func casApplied(q *gocql.Query) (bool, error) {
@@ -2318,6 +4203,21 @@ func casApplied(q *gocql.Query) (bool, error) {
 	return q.MapScanCAS(m)
 }
 
+// casBackoff waits before retrying an LWT that lost to a concurrent writer.
+// The wait is random and grows with attempt, up to 64ms, so that writers
+// contending for the same partition stop colliding.
+func casBackoff(ctx context.Context, attempt int) error {
+	limit := time.Millisecond << min(attempt, 6)
+	timer := time.NewTimer(time.Duration(rand.Int64N(int64(limit))) + 1)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func parseConsistency(v string) (gocql.Consistency, error) {
 	switch strings.ToUpper(strings.TrimSpace(v)) {
 	case "ANY":
@@ -2540,7 +4440,7 @@ func (s *Store) DeleteExporter(ctx context.Context, name string) error {
 		return err
 	}
 
-	batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch := s.writeBatch(ctx, gocql.LoggedBatch)
 	batch.Query(fmt.Sprintf(`DELETE FROM %s.exporters WHERE name = ?`, qident(s.cfg.Keyspace)), name)
 	batch.Query(fmt.Sprintf(`DELETE FROM %s.exporter_statuses WHERE name = ?`, qident(s.cfg.Keyspace)), name)
 
@@ -2677,7 +4577,7 @@ func (s *Store) CreateKEK(ctx context.Context, kek *storage.KEKRecord) error {
 	propsJSON := marshalJSONText(kek.KmsProps)
 
 	applied, err := casApplied(
-		s.session.Query(
+		s.writeQuery(
 			fmt.Sprintf(`INSERT INTO %s.keks (name, kms_type, kms_key_id, kms_props, doc, shared, deleted, ts, created_at, updated_at)
 				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
 			kek.Name, kek.KmsType, kek.KmsKeyID, propsJSON, kek.Doc, kek.Shared, false,
@@ -2775,7 +4675,7 @@ func (s *Store) DeleteKEK(ctx context.Context, name string, permanent bool) erro
 	}
 
 	if permanent {
-		batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		batch := s.writeBatch(ctx, gocql.LoggedBatch)
 		batch.Query(fmt.Sprintf(`DELETE FROM %s.keks WHERE name = ?`, qident(s.cfg.Keyspace)), name)
 
 		// Find all subjects under this KEK via deks_by_kek, then delete DEKs and the denorm table
@@ -2921,7 +4821,7 @@ func (s *Store) CreateDEK(ctx context.Context, dek *storage.DEKRecord) error {
 	//  3. The reverse order (LWT first, denorm second) risks a DEK existing in the
 	//     deks table but missing from deks_by_kek, which causes ListDEKs to miss it
 	//     and DeleteKEK (permanent) to leave orphaned deks rows.
-	if err := s.session.Query(
+	if err := s.writeQuery(
 		fmt.Sprintf(`INSERT INTO %s.deks_by_kek (kek_name, subject) VALUES (?, ?)`, qident(s.cfg.Keyspace)),
 		dek.KEKName, dek.Subject,
 	).WithContext(ctx).Exec(); err != nil {
@@ -2931,7 +4831,7 @@ func (s *Store) CreateDEK(ctx context.Context, dek *storage.DEKRecord) error {
 	// Use INSERT IF NOT EXISTS (LWT) to atomically check-and-insert the DEK,
 	// avoiding the TOCTOU race of a separate SELECT + INSERT.
 	applied, err := casApplied(
-		s.session.Query(
+		s.writeQuery(
 			fmt.Sprintf(`INSERT INTO %s.deks (kek_name, subject, version, algorithm, encrypted_key_material, deleted, ts)
 				VALUES (?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`, qident(s.cfg.Keyspace)),
 			dek.KEKName, dek.Subject, dek.Version, dek.Algorithm, dek.EncryptedKeyMaterial, false, dek.Ts,
@@ -3141,7 +5041,7 @@ func (s *Store) DeleteDEK(ctx context.Context, kekName, subject string, version
 		}
 
 		if permanent {
-			batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+			batch := s.writeBatch(ctx, gocql.LoggedBatch)
 			for _, ver := range toDelete {
 				batch.Query(
 					fmt.Sprintf(`DELETE FROM %s.deks WHERE kek_name = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
@@ -3192,7 +5092,7 @@ func (s *Store) DeleteDEK(ctx context.Context, kekName, subject string, version
 			}
 		} else {
 			ts := time.Now().UnixMilli()
-			batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+			batch := s.writeBatch(ctx, gocql.LoggedBatch)
 			for _, ver := range toDelete {
 				batch.Query(
 					fmt.Sprintf(`UPDATE %s.deks SET deleted = ?, ts = ? WHERE kek_name = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
@@ -3225,7 +5125,7 @@ func (s *Store) DeleteDEK(ctx context.Context, kekName, subject string, version
 	}
 
 	if permanent {
-		batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		batch := s.writeBatch(ctx, gocql.LoggedBatch)
 		batch.Query(
 			fmt.Sprintf(`DELETE FROM %s.deks WHERE kek_name = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
 			kekName, subject, version,
@@ -3302,7 +5202,7 @@ func (s *Store) UndeleteDEK(ctx context.Context, kekName, subject string, versio
 		}
 
 		ts := time.Now().UnixMilli()
-		batch := s.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		batch := s.writeBatch(ctx, gocql.LoggedBatch)
 		for _, ver := range toUndelete {
 			batch.Query(
 				fmt.Sprintf(`UPDATE %s.deks SET deleted = ?, ts = ? WHERE kek_name = ? AND subject = ? AND version = ?`, qident(s.cfg.Keyspace)),
@@ -3354,7 +5254,7 @@ func (s *Store) UpdateDEK(ctx context.Context, dek *storage.DEKRecord) error {
 	// Cassandra UPDATEs are upserts — verify the DEK exists first.
 	var count int
 	countQuery := `SELECT COUNT(*) FROM deks WHERE kek_name = ? AND subject = ? AND version = ?`
-	if err := s.session.Query(countQuery, dek.KEKName, dek.Subject, dek.Version).WithContext(ctx).Scan(&count); err != nil {
+	if err := s.readQuery(countQuery, dek.KEKName, dek.Subject, dek.Version).WithContext(ctx).Scan(&count); err != nil {
 		return err
 	}
 	if count == 0 {
@@ -3362,7 +5262,7 @@ func (s *Store) UpdateDEK(ctx context.Context, dek *storage.DEKRecord) error {
 	}
 	// algorithm is not part of the primary key, so we can't filter by it in WHERE.
 	query := `UPDATE deks SET encrypted_key_material = ?, ts = ? WHERE kek_name = ? AND subject = ? AND version = ?`
-	if err := s.session.Query(query, dek.EncryptedKeyMaterial, dek.Ts, dek.KEKName, dek.Subject, dek.Version).WithContext(ctx).Exec(); err != nil {
+	if err := s.writeQuery(query, dek.EncryptedKeyMaterial, dek.Ts, dek.KEKName, dek.Subject, dek.Version).WithContext(ctx).Exec(); err != nil {
 		return err
 	}
 	return nil
@@ -469,6 +469,153 @@ func TestMigrate_StatementsAreGenerated(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Replication — keyspace replication map
+// ---------------------------------------------------------------------------
+
+func TestReplication_CQL(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Replication
+		want string
+	}{
+		{"default", Replication{}, `{'class': 'SimpleStrategy', 'replication_factor': 1}`},
+		{"simple", Replication{Strategy: SimpleStrategy, Factor: 3}, `{'class': 'SimpleStrategy', 'replication_factor': 3}`},
+		{"network topology", Replication{Strategy: NetworkTopologyStrategy, Datacenters: map[string]int{"eu-west": 3, "dc1": 2}},
+			`{'class': 'NetworkTopologyStrategy', 'dc1': 2, 'eu-west': 3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.cql()
+			if err != nil {
+				t.Fatalf("cql: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("cql = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplication_Invalid(t *testing.T) {
+	invalid := []Replication{
+		{Strategy: "LocalStrategy"},
+		{Factor: -1},
+		{Datacenters: map[string]int{"dc1": 3}},
+		{Strategy: NetworkTopologyStrategy},
+		{Strategy: NetworkTopologyStrategy, Datacenters: map[string]int{"dc1": 0}},
+		{Strategy: NetworkTopologyStrategy, Datacenters: map[string]int{"dc1': 3, 'x": 1}},
+	}
+	for _, r := range invalid {
+		if _, err := r.cql(); err == nil {
+			t.Errorf("expected an error for %+v", r)
+		}
+	}
+}
+
+func TestNewStore_InvalidReplication(t *testing.T) {
+	cfg := Config{
+		Hosts:       []string{"127.0.0.1"},
+		Replication: Replication{Strategy: NetworkTopologyStrategy},
+	}
+
+	_, err := NewStore(t.Context(), cfg)
+	if err == nil || !strings.Contains(err.Error(), NetworkTopologyStrategy) {
+		t.Errorf("expected replication error, got: %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Driver policies
+// ---------------------------------------------------------------------------
+
+func TestHostSelectionPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		policy, localDC string
+		wantNil         bool
+	}{
+		{"", "", true},
+		{"", "dc1", false},
+		{HostSelectionTokenAware, "", false},
+		{HostSelectionTokenAware, "dc1", false},
+		{HostSelectionDCAware, "dc1", false},
+		{HostSelectionRoundRobin, "", false},
+	} {
+		policy, err := hostSelectionPolicy(tt.policy, tt.localDC)
+		if err != nil {
+			t.Errorf("hostSelectionPolicy(%q, %q): %v", tt.policy, tt.localDC, err)
+			continue
+		}
+		if (policy == nil) != tt.wantNil {
+			t.Errorf("hostSelectionPolicy(%q, %q) = %v, want nil %v", tt.policy, tt.localDC, policy, tt.wantNil)
+		}
+	}
+
+	if _, err := hostSelectionPolicy(HostSelectionDCAware, ""); err == nil {
+		t.Error("expected an error for dc_aware without a local datacenter")
+	}
+	if _, err := hostSelectionPolicy("nearest", ""); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	if policy, err := retryPolicy(Retry{}); err != nil || policy != nil {
+		t.Errorf("expected no retry policy by default, got %v, %v", policy, err)
+	}
+
+	policy, err := retryPolicy(Retry{Policy: RetrySimple})
+	if err != nil {
+		t.Fatalf("retryPolicy: %v", err)
+	}
+	if simple, ok := policy.(*gocql.SimpleRetryPolicy); !ok || simple.NumRetries != 3 {
+		t.Errorf("expected a simple policy with 3 retries, got %#v", policy)
+	}
+
+	policy, err = retryPolicy(Retry{Policy: RetryExponential, NumRetries: 5, MaxBackoff: time.Second})
+	if err != nil {
+		t.Fatalf("retryPolicy: %v", err)
+	}
+	exp, ok := policy.(*gocql.ExponentialBackoffRetryPolicy)
+	if !ok || exp.NumRetries != 5 || exp.Min != 100*time.Millisecond || exp.Max != time.Second {
+		t.Errorf("unexpected exponential policy: %#v", policy)
+	}
+
+	for _, r := range []Retry{
+		{Policy: "forever"},
+		{Policy: RetrySimple, NumRetries: -1},
+		{Policy: RetryExponential, MinBackoff: time.Minute, MaxBackoff: time.Second},
+	} {
+		if _, err := retryPolicy(r); err == nil {
+			t.Errorf("expected an error for %+v", r)
+		}
+	}
+}
+
+func TestSSLOptions(t *testing.T) {
+	if opts, err := sslOptions(TLS{}); err != nil || opts != nil {
+		t.Errorf("expected TLS to be off by default, got %v, %v", opts, err)
+	}
+
+	opts, err := sslOptions(TLS{Enabled: true, ServerName: "cassandra.internal"})
+	if err != nil {
+		t.Fatalf("sslOptions: %v", err)
+	}
+	if !opts.EnableHostVerification || opts.Config.ServerName != "cassandra.internal" || opts.Config.InsecureSkipVerify {
+		t.Errorf("unexpected TLS options: %+v", opts.Config)
+	}
+
+	if opts, err := sslOptions(TLS{Enabled: true, InsecureSkipVerify: true}); err != nil || opts.EnableHostVerification {
+		t.Errorf("expected host verification to be off, got %+v, %v", opts, err)
+	}
+	if _, err := sslOptions(TLS{CertFile: "client.pem"}); err == nil {
+		t.Error("expected an error for a client certificate without a key")
+	}
+	if _, err := sslOptions(TLS{CAFile: "/nonexistent/ca.pem"}); err == nil {
+		t.Error("expected an error for a missing CA file")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Compile-time interface check
 // ---------------------------------------------------------------------------
@@ -0,0 +1,141 @@
+// Package delta encodes a text as a compact delta against a similar base text.
+//
+// A delta is a sequence of operations that copy a range of the base or insert
+// literal text. It is itself valid UTF-8 whenever the target is, so it can be
+// stored in a text column. Storage backends use it to store a schema version
+// as a delta against the previous version of its subject.
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// header prefixes every delta, so the format can evolve.
+const header = "d1;"
+
+// blockSize is the shortest run of the base that is copied rather than
+// inserted. Shorter copies cost more to encode than the text they replace.
+const blockSize = 16
+
+// ErrCorrupt is returned when a delta cannot be applied to its base.
+var ErrCorrupt = errors.New("corrupt delta")
+
+// Encode returns target as a delta against base. ok is false when the delta
+// would not be less than half the size of target, in which case target
+// should be stored in full.
+func Encode(base, target string) (delta string, ok bool) {
+	if len(base) < blockSize || len(target) < blockSize {
+		return "", false
+	}
+
+	// Index the base by its aligned blocks, keeping the first occurrence.
+	index := make(map[string]int, len(base)/blockSize)
+	for off := 0; off+blockSize <= len(base); off += blockSize {
+		if _, exists := index[base[off:off+blockSize]]; !exists {
+			index[base[off:off+blockSize]] = off
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	pending := 0 // start of target text not yet copied or inserted
+	for i := 0; i+blockSize <= len(target); {
+		off, found := index[target[i:i+blockSize]]
+		if !found {
+			i++
+			continue
+		}
+		// Extend the match in both directions, but not back into text
+		// already encoded.
+		ts, bs := i, off
+		for ts > pending && bs > 0 && target[ts-1] == base[bs-1] {
+			ts--
+			bs--
+		}
+		te, be := i+blockSize, off+blockSize
+		for te < len(target) && be < len(base) && target[te] == base[be] {
+			te++
+			be++
+		}
+		// Keep inserted text valid UTF-8 by only splitting between runes.
+		for ts < te && !runeBoundary(target, ts) {
+			ts++
+			bs++
+		}
+		for te > ts && !runeBoundary(target, te) {
+			te--
+		}
+		if te-ts < blockSize {
+			i++
+			continue
+		}
+		writeInsert(&b, target[pending:ts])
+		fmt.Fprintf(&b, "c%d,%d;", bs, te-ts)
+		pending, i = te, te
+	}
+	writeInsert(&b, target[pending:])
+
+	if b.Len() > len(target)/2 {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// Apply reconstructs the target text from base and a delta created by Encode.
+func Apply(base, delta string) (string, error) {
+	if !strings.HasPrefix(delta, header) {
+		return "", fmt.Errorf("%w: missing header", ErrCorrupt)
+	}
+	rest := delta[len(header):]
+
+	var b strings.Builder
+	for rest != "" {
+		op := rest[0]
+		rest = rest[1:]
+		switch op {
+		case 'c':
+			comma := strings.IndexByte(rest, ',')
+			semi := strings.IndexByte(rest, ';')
+			if comma < 0 || semi < comma {
+				return "", fmt.Errorf("%w: malformed copy", ErrCorrupt)
+			}
+			off, err1 := strconv.Atoi(rest[:comma])
+			n, err2 := strconv.Atoi(rest[comma+1 : semi])
+			if err1 != nil || err2 != nil || off < 0 || n < 0 || off+n > len(base) {
+				return "", fmt.Errorf("%w: copy out of range", ErrCorrupt)
+			}
+			b.WriteString(base[off : off+n])
+			rest = rest[semi+1:]
+		case 'i':
+			colon := strings.IndexByte(rest, ':')
+			if colon < 0 {
+				return "", fmt.Errorf("%w: malformed insert", ErrCorrupt)
+			}
+			n, err := strconv.Atoi(rest[:colon])
+			if err != nil || n < 0 || colon+1+n > len(rest) {
+				return "", fmt.Errorf("%w: insert out of range", ErrCorrupt)
+			}
+			b.WriteString(rest[colon+1 : colon+1+n])
+			rest = rest[colon+1+n:]
+		default:
+			return "", fmt.Errorf("%w: unknown operation %q", ErrCorrupt, op)
+		}
+	}
+	return b.String(), nil
+}
+
+func writeInsert(b *strings.Builder, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(b, "i%d:", len(text))
+	b.WriteString(text)
+}
+
+func runeBoundary(s string, i int) bool {
+	return i == 0 || i == len(s) || utf8.RuneStart(s[i])
+}
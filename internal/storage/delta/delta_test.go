@@ -0,0 +1,71 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func generatedSchema(fields int, extra string) string {
+	var b strings.Builder
+	b.WriteString(`{"type":"record","name":"Order","namespace":"com.example.généré","fields":[`)
+	for i := 0; i < fields; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"name":"field_%d","type":"string","doc":"Field number %d of the order"}`, i, i)
+	}
+	b.WriteString(extra)
+	b.WriteString("]}")
+	return b.String()
+}
+
+func TestEncodeApply(t *testing.T) {
+	base := generatedSchema(50, "")
+	tests := []struct {
+		name   string
+		target string
+		ok     bool
+	}{
+		{"added field", generatedSchema(50, `,{"name":"note","type":["null","string"],"default":null}`), true},
+		{"removed field", generatedSchema(49, ""), true},
+		{"changed doc", strings.Replace(base, "Field number 7 of", "Champ numéro 7 de", 1), true},
+		{"scattered changes", strings.NewReplacer("field_3\"", "field_three\"", "field_40\"", "field_forty\"").Replace(base), true},
+		{"unrelated", strings.Repeat("x", len(base)), false},
+		{"short", `"string"`, false},
+	}
+	for _, tt := range tests {
+		d, ok := Encode(base, tt.target)
+		if ok != tt.ok {
+			t.Errorf("%s: Encode ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !utf8.ValidString(d) {
+			t.Errorf("%s: delta is not valid UTF-8", tt.name)
+		}
+		got, err := Apply(base, d)
+		if err != nil {
+			t.Fatalf("%s: Apply: %v", tt.name, err)
+		}
+		if got != tt.target {
+			t.Errorf("%s: Apply did not reconstruct the target", tt.name)
+		}
+		if len(d) > len(tt.target)/10 {
+			t.Errorf("%s: delta is %d bytes for a %d byte target", tt.name, len(d), len(tt.target))
+		}
+	}
+}
+
+func TestApply_Corrupt(t *testing.T) {
+	base := generatedSchema(5, "")
+	for _, d := range []string{"", "c0,4;", header + "c0,99999;", header + "i5:abc", header + "x", header + "c1;"} {
+		if _, err := Apply(base, d); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Apply(%q): expected ErrCorrupt, got %v", d, err)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mirroredCredential replaces password and API key hashes in the mirror. It
+// can never match a bcrypt hash or an HMAC digest, so mirrored records
+// cannot be used to authenticate.
+const mirroredCredential = "!vault"
+
+// mirrorKeyHash returns the placeholder hash of the mirror copy of the
+// primary API key id. It is unique per key, as the backends require, and
+// identifies the mirror copy during reconciliation.
+func mirrorKeyHash(id int64) string {
+	return mirroredCredential + ":" + strconv.FormatInt(id, 10)
+}
+
+// AuthMirrorReport summarizes a reconciliation of the mirror with the
+// primary auth store.
+type AuthMirrorReport struct {
+	Created int
+	Updated int
+	Deleted int
+}
+
+// mirrorAction is what syncing one record did to the mirror.
+type mirrorAction int
+
+const (
+	mirrorUnchanged mirrorAction = iota
+	mirrorCreated
+	mirrorUpdated
+	mirrorDeleted
+)
+
+func (r *AuthMirrorReport) add(action mirrorAction) {
+	switch action {
+	case mirrorCreated:
+		r.Created++
+	case mirrorUpdated:
+		r.Updated++
+	case mirrorDeleted:
+		r.Deleted++
+	}
+}
+
+// DualWriteAuthStorage wraps the primary AuthStorage, such as Vault, and
+// mirrors users and API keys into a second store, such as the SQL database
+// that holds the schemas. Every read is served by the primary. The mirror
+// receives the same users and keys with their password and key hashes
+// replaced by a placeholder, so credentials only ever live in the primary
+// while SQL tooling and reports can still see who has access.
+//
+// Mirror writes follow a successful primary write. A failed mirror write
+// does not fail the operation; it is logged and repaired by the next
+// Reconcile. Role grants, share tokens and login sessions are not mirrored,
+// nor are API key last-used times.
+type DualWriteAuthStorage struct {
+	AuthStorage
+	mirror AuthStorage
+	logger *slog.Logger
+}
+
+// NewDualWriteAuthStorage creates a new DualWriteAuthStorage that serves
+// auth data from primary and mirrors users and API keys into mirror.
+func NewDualWriteAuthStorage(primary, mirror AuthStorage, logger *slog.Logger) *DualWriteAuthStorage {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DualWriteAuthStorage{AuthStorage: primary, mirror: mirror, logger: logger}
+}
+
+// mirrorFailed logs a mirror write that failed after the primary write
+// succeeded.
+func (s *DualWriteAuthStorage) mirrorFailed(op string, err error) {
+	s.logger.Warn("auth mirror write failed, the consistency check will repair it",
+		slog.String("operation", op),
+		slog.String("error", err.Error()),
+	)
+}
+
+// CreateUser creates the user in the primary and mirrors it.
+func (s *DualWriteAuthStorage) CreateUser(ctx context.Context, user *UserRecord) error {
+	if err := s.AuthStorage.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	if _, _, err := s.syncUser(ctx, user.Username, user); err != nil {
+		s.mirrorFailed("create_user", err)
+	}
+	return nil
+}
+
+// UpdateUser updates the user in the primary and mirrors it, following a
+// change of username.
+func (s *DualWriteAuthStorage) UpdateUser(ctx context.Context, user *UserRecord) error {
+	username := user.Username
+	if old, err := s.AuthStorage.GetUserByID(ctx, user.ID); err == nil {
+		username = old.Username
+	}
+	if err := s.AuthStorage.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	if _, _, err := s.syncUser(ctx, username, user); err != nil {
+		s.mirrorFailed("update_user", err)
+	}
+	return nil
+}
+
+// DeleteUser deletes the user from the primary and the mirror.
+func (s *DualWriteAuthStorage) DeleteUser(ctx context.Context, id int64) error {
+	user, err := s.AuthStorage.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.AuthStorage.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	if _, _, err := s.syncUser(ctx, user.Username, nil); err != nil {
+		s.mirrorFailed("delete_user", err)
+	}
+	return nil
+}
+
+// CreateAPIKey creates the key in the primary and mirrors it without its
+// hash.
+func (s *DualWriteAuthStorage) CreateAPIKey(ctx context.Context, key *APIKeyRecord) error {
+	if err := s.AuthStorage.CreateAPIKey(ctx, key); err != nil {
+		return err
+	}
+	if _, err := s.syncAPIKey(ctx, key.ID, key); err != nil {
+		s.mirrorFailed("create_api_key", err)
+	}
+	return nil
+}
+
+// UpdateAPIKey updates the key in the primary and mirrors it.
+func (s *DualWriteAuthStorage) UpdateAPIKey(ctx context.Context, key *APIKeyRecord) error {
+	if err := s.AuthStorage.UpdateAPIKey(ctx, key); err != nil {
+		return err
+	}
+	if _, err := s.syncAPIKey(ctx, key.ID, key); err != nil {
+		s.mirrorFailed("update_api_key", err)
+	}
+	return nil
+}
+
+// DeleteAPIKey deletes the key from the primary and the mirror.
+func (s *DualWriteAuthStorage) DeleteAPIKey(ctx context.Context, id int64) error {
+	if err := s.AuthStorage.DeleteAPIKey(ctx, id); err != nil {
+		return err
+	}
+	if _, err := s.syncAPIKey(ctx, id, nil); err != nil {
+		s.mirrorFailed("delete_api_key", err)
+	}
+	return nil
+}
+
+// syncUser makes the mirror copy of the user, found by username, match
+// user, or deletes it when user is nil. It returns the mirror copy.
+func (s *DualWriteAuthStorage) syncUser(ctx context.Context, username string, user *UserRecord) (*UserRecord, mirrorAction, error) {
+	existing, err := s.mirror.GetUserByUsername(ctx, username)
+	if errors.Is(err, ErrUserNotFound) {
+		existing = nil
+	} else if err != nil {
+		return nil, mirrorUnchanged, err
+	}
+
+	if user == nil {
+		if existing == nil {
+			return nil, mirrorUnchanged, nil
+		}
+		if err := s.mirror.DeleteUser(ctx, existing.ID); err != nil && !errors.Is(err, ErrUserNotFound) {
+			return nil, mirrorUnchanged, err
+		}
+		return nil, mirrorDeleted, nil
+	}
+
+	want := *user
+	want.ID = 0
+	want.PasswordHash = mirroredCredential
+	want.PasswordHistory = nil
+	if existing == nil {
+		if err := s.mirror.CreateUser(ctx, &want); err != nil {
+			return nil, mirrorUnchanged, err
+		}
+		return &want, mirrorCreated, nil
+	}
+	if existing.Username == want.Username && existing.Email == want.Email &&
+		existing.PasswordHash == want.PasswordHash && existing.Role == want.Role &&
+		existing.Enabled == want.Enabled && existing.Tenant == want.Tenant {
+		return existing, mirrorUnchanged, nil
+	}
+	want.ID = existing.ID
+	if err := s.mirror.UpdateUser(ctx, &want); err != nil {
+		return nil, mirrorUnchanged, err
+	}
+	return &want, mirrorUpdated, nil
+}
+
+// syncAPIKey makes the mirror copy of the primary API key id match key, or
+// deletes it when key is nil. The copy belongs to the mirror copy of the
+// key's owner, which is created first if it is missing.
+func (s *DualWriteAuthStorage) syncAPIKey(ctx context.Context, id int64, key *APIKeyRecord) (mirrorAction, error) {
+	existing, err := s.mirror.GetAPIKeyByHash(ctx, mirrorKeyHash(id))
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		existing = nil
+	} else if err != nil {
+		return mirrorUnchanged, err
+	}
+
+	if key == nil {
+		if existing == nil {
+			return mirrorUnchanged, nil
+		}
+		if err := s.mirror.DeleteAPIKey(ctx, existing.ID); err != nil && !errors.Is(err, ErrAPIKeyNotFound) {
+			return mirrorUnchanged, err
+		}
+		return mirrorDeleted, nil
+	}
+
+	owner, err := s.AuthStorage.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return mirrorUnchanged, fmt.Errorf("owner of API key %d: %w", id, err)
+	}
+	mirrorOwner, _, err := s.syncUser(ctx, owner.Username, owner)
+	if err != nil {
+		return mirrorUnchanged, fmt.Errorf("owner of API key %d: %w", id, err)
+	}
+
+	want := *key
+	want.ID = 0
+	want.UserID = mirrorOwner.ID
+	want.KeyHash = mirrorKeyHash(id)
+	want.LastUsed = nil
+	if existing == nil {
+		if err := s.mirror.CreateAPIKey(ctx, &want); err != nil {
+			return mirrorUnchanged, err
+		}
+		return mirrorCreated, nil
+	}
+	// The key prefix and last-used time are not compared: the prefix never
+	// changes after creation and last-used times are not mirrored.
+	if existing.UserID == want.UserID && existing.Name == want.Name && existing.Role == want.Role &&
+		existing.Enabled == want.Enabled && existing.ExpiresAt.Equal(want.ExpiresAt) &&
+		EqualAPIKeyScopes(existing.Scopes, want.Scopes) {
+		return mirrorUnchanged, nil
+	}
+	want.ID = existing.ID
+	if err := s.mirror.UpdateAPIKey(ctx, &want); err != nil {
+		return mirrorUnchanged, err
+	}
+	return mirrorUpdated, nil
+}
+
+// Reconcile makes the mirror match the primary: it creates missing users
+// and API keys, updates those that have drifted, and deletes the ones the
+// primary does not have. Keys and users stored in the mirror with real
+// credentials, for example from before auth moved to Vault, are replaced
+// by redacted copies or deleted. Reconcile carries on past failed records
+// and returns their errors joined.
+func (s *DualWriteAuthStorage) Reconcile(ctx context.Context) (AuthMirrorReport, error) {
+	var report AuthMirrorReport
+
+	users, err := s.AuthStorage.ListUsers(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list users: %w", err)
+	}
+	keys, err := s.AuthStorage.ListAPIKeys(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	mirrorUsers, err := s.mirror.ListUsers(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list mirrored users: %w", err)
+	}
+	mirrorKeys, err := s.mirror.ListAPIKeys(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list mirrored API keys: %w", err)
+	}
+
+	var errs []error
+	keyIDs := make(map[int64]bool, len(keys))
+	for _, key := range keys {
+		keyIDs[key.ID] = true
+	}
+	usernames := make(map[string]bool, len(users))
+	for _, user := range users {
+		usernames[user.Username] = true
+	}
+
+	// Delete first, so that stale keys and users cannot collide with the
+	// names of the ones created below.
+	for _, key := range mirrorKeys {
+		if id, ok := strings.CutPrefix(key.KeyHash, mirroredCredential+":"); ok {
+			if n, err := strconv.ParseInt(id, 10, 64); err == nil && keyIDs[n] {
+				continue
+			}
+		}
+		if err := s.mirror.DeleteAPIKey(ctx, key.ID); err != nil && !errors.Is(err, ErrAPIKeyNotFound) {
+			errs = append(errs, fmt.Errorf("mirrored API key %d: %w", key.ID, err))
+			continue
+		}
+		report.Deleted++
+	}
+	for _, user := range mirrorUsers {
+		if usernames[user.Username] {
+			continue
+		}
+		if err := s.mirror.DeleteUser(ctx, user.ID); err != nil && !errors.Is(err, ErrUserNotFound) {
+			errs = append(errs, fmt.Errorf("mirrored user %s: %w", user.Username, err))
+			continue
+		}
+		report.Deleted++
+	}
+
+	for _, user := range users {
+		_, action, err := s.syncUser(ctx, user.Username, user)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", user.Username, err))
+			continue
+		}
+		report.add(action)
+	}
+	for _, key := range keys {
+		action, err := s.syncAPIKey(ctx, key.ID, key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("API key %d: %w", key.ID, err))
+			continue
+		}
+		report.add(action)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// Start reconciles the mirror every interval until stop is closed.
+func (s *DualWriteAuthStorage) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce(context.Background())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// RunOnce reconciles the mirror once and logs the outcome.
+func (s *DualWriteAuthStorage) RunOnce(ctx context.Context) {
+	report, err := s.Reconcile(ctx)
+	if err != nil {
+		s.logger.Error("auth mirror consistency check failed", slog.String("error", err.Error()))
+	}
+	if report != (AuthMirrorReport{}) {
+		s.logger.Warn("auth mirror consistency check repaired drift",
+			slog.Int("created", report.Created),
+			slog.Int("updated", report.Updated),
+			slog.Int("deleted", report.Deleted),
+		)
+	}
+}
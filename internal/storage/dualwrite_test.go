@@ -0,0 +1,152 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func newDualWrite(t *testing.T) (*storage.DualWriteAuthStorage, *memory.Store, *memory.Store) {
+	t.Helper()
+	primary := memory.NewStore()
+	mirror := memory.NewStore()
+	return storage.NewDualWriteAuthStorage(primary, mirror, nil), primary, mirror
+}
+
+func TestDualWriteAuthStorage_MirrorsWithoutCredentials(t *testing.T) {
+	ctx := context.Background()
+	s, _, mirror := newDualWrite(t)
+
+	user := &storage.UserRecord{Username: "alice", PasswordHash: "$2a$10$secret", Role: "developer", Enabled: true}
+	if err := s.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	key := &storage.APIKeyRecord{UserID: user.ID, KeyHash: "deadbeef", KeyPrefix: "sr_", Name: "ci", Role: "readonly", Enabled: true, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.CreateAPIKey(ctx, key); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	// Reads come from the primary, with the real credentials.
+	got, err := s.GetAPIKeyByHash(ctx, "deadbeef")
+	if err != nil || got.Name != "ci" {
+		t.Fatalf("GetAPIKeyByHash = %+v, %v", got, err)
+	}
+
+	mirrorUser, err := mirror.GetUserByUsername(ctx, "alice")
+	if err != nil {
+		t.Fatalf("mirrored user: %v", err)
+	}
+	if mirrorUser.PasswordHash == user.PasswordHash || mirrorUser.Role != "developer" || !mirrorUser.Enabled {
+		t.Errorf("mirrored user = %+v", mirrorUser)
+	}
+	mirrorKeys, err := mirror.ListAPIKeys(ctx)
+	if err != nil || len(mirrorKeys) != 1 {
+		t.Fatalf("mirrored keys = %v, %v", mirrorKeys, err)
+	}
+	if mirrorKeys[0].KeyHash == "deadbeef" || mirrorKeys[0].Name != "ci" || mirrorKeys[0].UserID != mirrorUser.ID {
+		t.Errorf("mirrored key = %+v", mirrorKeys[0])
+	}
+	if _, err := mirror.GetAPIKeyByHash(ctx, "deadbeef"); !errors.Is(err, storage.ErrAPIKeyNotFound) {
+		t.Errorf("mirror holds the key hash: %v", err)
+	}
+}
+
+func TestDualWriteAuthStorage_UpdatesAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	s, _, mirror := newDualWrite(t)
+
+	user := &storage.UserRecord{Username: "bob", PasswordHash: "hash", Role: "readonly", Enabled: true}
+	if err := s.CreateUser(ctx, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	key := &storage.APIKeyRecord{UserID: user.ID, KeyHash: "h1", Name: "k", Role: "readonly", Enabled: true, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.CreateAPIKey(ctx, key); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	renamed := *user
+	renamed.Username = "robert"
+	renamed.Role = "admin"
+	if err := s.UpdateUser(ctx, &renamed); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if _, err := mirror.GetUserByUsername(ctx, "bob"); !errors.Is(err, storage.ErrUserNotFound) {
+		t.Errorf("old username still mirrored: %v", err)
+	}
+	if u, err := mirror.GetUserByUsername(ctx, "robert"); err != nil || u.Role != "admin" {
+		t.Errorf("renamed user = %+v, %v", u, err)
+	}
+
+	disabled := *key
+	disabled.Enabled = false
+	if err := s.UpdateAPIKey(ctx, &disabled); err != nil {
+		t.Fatalf("UpdateAPIKey: %v", err)
+	}
+	if keys, _ := mirror.ListAPIKeys(ctx); len(keys) != 1 || keys[0].Enabled {
+		t.Errorf("mirrored keys after update = %+v", keys)
+	}
+
+	if err := s.DeleteAPIKey(ctx, key.ID); err != nil {
+		t.Fatalf("DeleteAPIKey: %v", err)
+	}
+	if keys, _ := mirror.ListAPIKeys(ctx); len(keys) != 0 {
+		t.Errorf("mirrored keys after delete = %+v", keys)
+	}
+	if err := s.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if users, _ := mirror.ListUsers(ctx); len(users) != 0 {
+		t.Errorf("mirrored users after delete = %+v", users)
+	}
+}
+
+func TestDualWriteAuthStorage_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	s, primary, mirror := newDualWrite(t)
+
+	// Written to the primary behind the wrapper's back: missing from the mirror.
+	carol := &storage.UserRecord{Username: "carol", PasswordHash: "hash", Role: "admin", Enabled: true}
+	if err := primary.CreateUser(ctx, carol); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if err := primary.CreateAPIKey(ctx, &storage.APIKeyRecord{UserID: carol.ID, KeyHash: "h1", Name: "k", Role: "admin", Enabled: true, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	// Left in the mirror with real credentials from before auth moved.
+	if err := mirror.CreateUser(ctx, &storage.UserRecord{Username: "carol", PasswordHash: "hash", Role: "admin", Enabled: true}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	stale := &storage.UserRecord{Username: "dave", PasswordHash: "hash", Role: "readonly", Enabled: true}
+	if err := mirror.CreateUser(ctx, stale); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if err := mirror.CreateAPIKey(ctx, &storage.APIKeyRecord{UserID: stale.ID, KeyHash: "real", Name: "old", Role: "readonly", Enabled: true, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	report, err := s.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	want := storage.AuthMirrorReport{Created: 1, Updated: 1, Deleted: 2}
+	if report != want {
+		t.Errorf("report = %+v, want %+v", report, want)
+	}
+
+	users, _ := mirror.ListUsers(ctx)
+	if len(users) != 1 || users[0].Username != "carol" || users[0].PasswordHash == "hash" {
+		t.Errorf("mirrored users = %+v", users)
+	}
+	if _, err := mirror.GetAPIKeyByHash(ctx, "real"); !errors.Is(err, storage.ErrAPIKeyNotFound) {
+		t.Errorf("stale key still mirrored: %v", err)
+	}
+
+	report, err = s.Reconcile(ctx)
+	if err != nil || report != (storage.AuthMirrorReport{}) {
+		t.Errorf("second Reconcile = %+v, %v, want no changes", report, err)
+	}
+}
@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix marks schema text sealed by EncryptedStorage. It is
+// followed by the name of the key, a colon, and base64(nonce || ciphertext).
+const encryptedPrefix = "enc:v1:"
+
+// ErrSchemaKeyUnavailable is returned when stored schema text is encrypted
+// with a key the keyring cannot provide.
+var ErrSchemaKeyUnavailable = errors.New("schema encryption key unavailable")
+
+// SchemaKeyring supplies the keys EncryptedStorage seals schema text with.
+type SchemaKeyring interface {
+	// KeyFor returns the name and AEAD of the key schemas in registryCtx are
+	// sealed with, or a nil AEAD when they are stored in plaintext.
+	KeyFor(ctx context.Context, registryCtx string) (string, cipher.AEAD, error)
+	// Key returns the AEAD of the named key.
+	Key(ctx context.Context, name string) (cipher.AEAD, error)
+	// Forget drops anything cached about registryCtx.
+	Forget(registryCtx string)
+}
+
+// EncryptedStorage wraps a Storage implementation and encrypts schema text
+// before it reaches the backend. Each sealed value records the name of its
+// key and is bound to its registry context, so a value copied into another
+// context, or read with another tenant's key, fails to open. Schema text
+// written before encryption was enabled is returned unchanged.
+//
+// Only schema text is encrypted: subjects, fingerprints and references stay
+// in plaintext because the backends query by them.
+type EncryptedStorage struct {
+	Storage
+	keyring SchemaKeyring
+}
+
+// NewEncryptedStorage creates a new EncryptedStorage that seals the schemas
+// written to store with keys from keyring.
+func NewEncryptedStorage(store Storage, keyring SchemaKeyring) *EncryptedStorage {
+	return &EncryptedStorage{Storage: store, keyring: keyring}
+}
+
+// seal returns the stored form of schema for registryCtx.
+func (s *EncryptedStorage) seal(ctx context.Context, registryCtx, schema string) (string, error) {
+	name, aead, err := s.keyring.KeyFor(ctx, registryCtx)
+	if err != nil {
+		return "", err
+	}
+	if aead == nil {
+		return schema, nil
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(schema)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(schema), []byte(registryCtx))
+	return encryptedPrefix + name + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open returns the plaintext of a stored schema read from registryCtx.
+func (s *EncryptedStorage) open(ctx context.Context, registryCtx, stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+	name, encoded, ok := strings.Cut(strings.TrimPrefix(stored, encryptedPrefix), ":")
+	if !ok {
+		return "", fmt.Errorf("malformed encrypted schema in context %s", registryCtx)
+	}
+	aead, err := s.keyring.Key(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("key %s: %w", name, err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted schema in context %s", registryCtx)
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(registryCtx))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt schema in context %s with key %s: %w", registryCtx, name, ErrSchemaKeyUnavailable)
+	}
+	return string(plain), nil
+}
+
+// openRecord returns a copy of record with its schema text decrypted. The
+// record itself is left alone because some backends return their own copy.
+func (s *EncryptedStorage) openRecord(ctx context.Context, registryCtx string, record *SchemaRecord) (*SchemaRecord, error) {
+	if record == nil || !strings.HasPrefix(record.Schema, encryptedPrefix) {
+		return record, nil
+	}
+	plain, err := s.open(ctx, registryCtx, record.Schema)
+	if err != nil {
+		return nil, err
+	}
+	opened := *record
+	opened.Schema = plain
+	return &opened, nil
+}
+
+// openRecords returns records with every schema text decrypted.
+func (s *EncryptedStorage) openRecords(ctx context.Context, registryCtx string, records []*SchemaRecord) ([]*SchemaRecord, error) {
+	opened := make([]*SchemaRecord, len(records))
+	for i, record := range records {
+		rec, err := s.openRecord(ctx, registryCtx, record)
+		if err != nil {
+			return nil, err
+		}
+		opened[i] = rec
+	}
+	return opened, nil
+}
+
+// writeSealed stores record through write with its schema text sealed. The
+// caller's record keeps its plaintext and receives the assigned ID, version
+// and timestamps.
+func (s *EncryptedStorage) writeSealed(ctx context.Context, registryCtx string, record *SchemaRecord, write func(*SchemaRecord) error) error {
+	sealed, err := s.seal(ctx, registryCtx, record.Schema)
+	if err != nil {
+		return err
+	}
+	plain := record.Schema
+	record.Schema = sealed
+	err = write(record)
+	record.Schema = plain
+	return err
+}
+
+// --- Schema operations ---
+
+func (s *EncryptedStorage) CreateSchema(ctx context.Context, registryCtx string, record *SchemaRecord) error {
+	return s.writeSealed(ctx, registryCtx, record, func(r *SchemaRecord) error {
+		return s.Storage.CreateSchema(ctx, registryCtx, r)
+	})
+}
+
+func (s *EncryptedStorage) ImportSchema(ctx context.Context, registryCtx string, record *SchemaRecord) error {
+	return s.writeSealed(ctx, registryCtx, record, func(r *SchemaRecord) error {
+		return s.Storage.ImportSchema(ctx, registryCtx, r)
+	})
+}
+
+// RewriteSchemaText seals schemaText with the current key of registryCtx,
+// so rewriting every schema with its own plaintext re-encrypts a context
+// after a key rotation, or encrypts schemas written before encryption was
+// enabled.
+func (s *EncryptedStorage) RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error {
+	sealed, err := s.seal(ctx, registryCtx, schemaText)
+	if err != nil {
+		return err
+	}
+	return s.Storage.RewriteSchemaText(ctx, registryCtx, id, sealed)
+}
+
+func (s *EncryptedStorage) GetSchemaByID(ctx context.Context, registryCtx string, id int64) (*SchemaRecord, error) {
+	rec, err := s.Storage.GetSchemaByID(ctx, registryCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.openRecord(ctx, registryCtx, rec)
+}
+
+func (s *EncryptedStorage) GetSchemaBySubjectVersion(ctx context.Context, registryCtx string, subject string, version int) (*SchemaRecord, error) {
+	rec, err := s.Storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.openRecord(ctx, registryCtx, rec)
+}
+
+func (s *EncryptedStorage) GetSchemasBySubject(ctx context.Context, registryCtx string, subject string, includeDeleted bool) ([]*SchemaRecord, error) {
+	recs, err := s.Storage.GetSchemasBySubject(ctx, registryCtx, subject, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	return s.openRecords(ctx, registryCtx, recs)
+}
+
+func (s *EncryptedStorage) GetSchemaByFingerprint(ctx context.Context, registryCtx string, subject, fingerprint string, includeDeleted bool) (*SchemaRecord, error) {
+	rec, err := s.Storage.GetSchemaByFingerprint(ctx, registryCtx, subject, fingerprint, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	return s.openRecord(ctx, registryCtx, rec)
+}
+
+func (s *EncryptedStorage) GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx string, fingerprint string) (*SchemaRecord, error) {
+	rec, err := s.Storage.GetSchemaByGlobalFingerprint(ctx, registryCtx, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	return s.openRecord(ctx, registryCtx, rec)
+}
+
+func (s *EncryptedStorage) GetLatestSchema(ctx context.Context, registryCtx string, subject string) (*SchemaRecord, error) {
+	rec, err := s.Storage.GetLatestSchema(ctx, registryCtx, subject)
+	if err != nil {
+		return nil, err
+	}
+	return s.openRecord(ctx, registryCtx, rec)
+}
+
+func (s *EncryptedStorage) ListSchemas(ctx context.Context, registryCtx string, params *ListSchemasParams) ([]*SchemaRecord, error) {
+	recs, err := s.Storage.ListSchemas(ctx, registryCtx, params)
+	if err != nil {
+		return nil, err
+	}
+	return s.openRecords(ctx, registryCtx, recs)
+}
+
+func (s *EncryptedStorage) IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*SchemaRecord) error) error {
+	return s.Storage.IterateSchemas(ctx, registryCtx, includeDeleted, func(record *SchemaRecord) error {
+		opened, err := s.openRecord(ctx, registryCtx, record)
+		if err != nil {
+			return err
+		}
+		return fn(opened)
+	})
+}
+
+// --- Context operations ---
+
+func (s *EncryptedStorage) CreateContext(ctx context.Context, record *ContextRecord) error {
+	s.keyring.Forget(record.Name)
+	return s.Storage.CreateContext(ctx, record)
+}
+
+func (s *EncryptedStorage) DeleteContext(ctx context.Context, name string) error {
+	err := s.Storage.DeleteContext(ctx, name)
+	s.keyring.Forget(name)
+	return err
+}
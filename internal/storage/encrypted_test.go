@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// staticKeyring seals every context listed in contexts with the named key.
+type staticKeyring struct {
+	keys     map[string]cipher.AEAD
+	contexts map[string]string
+}
+
+func newStaticKeyring(t *testing.T, names ...string) *staticKeyring {
+	t.Helper()
+	k := &staticKeyring{keys: make(map[string]cipher.AEAD), contexts: make(map[string]string)}
+	for i, name := range names {
+		block, err := aes.NewCipher([]byte(strings.Repeat(string(rune('a'+i)), 32)))
+		if err != nil {
+			t.Fatalf("NewCipher: %v", err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			t.Fatalf("NewGCM: %v", err)
+		}
+		k.keys[name] = aead
+	}
+	return k
+}
+
+func (k *staticKeyring) KeyFor(_ context.Context, registryCtx string) (string, cipher.AEAD, error) {
+	name, ok := k.contexts[registryCtx]
+	if !ok {
+		return "", nil, nil
+	}
+	return name, k.keys[name], nil
+}
+
+func (k *staticKeyring) Key(_ context.Context, name string) (cipher.AEAD, error) {
+	aead, ok := k.keys[name]
+	if !ok {
+		return nil, ErrSchemaKeyUnavailable
+	}
+	return aead, nil
+}
+
+func (k *staticKeyring) Forget(string) {}
+
+// recordStorage keeps schemas by ID and returns its own pointers, like the
+// memory store does.
+type recordStorage struct {
+	Storage
+	schemas map[int64]*SchemaRecord
+}
+
+func (s *recordStorage) CreateSchema(_ context.Context, _ string, record *SchemaRecord) error {
+	record.ID = int64(len(s.schemas) + 1)
+	stored := *record
+	s.schemas[record.ID] = &stored
+	return nil
+}
+
+func (s *recordStorage) GetSchemaByID(_ context.Context, _ string, id int64) (*SchemaRecord, error) {
+	rec, ok := s.schemas[id]
+	if !ok {
+		return nil, ErrSchemaNotFound
+	}
+	return rec, nil
+}
+
+func TestEncryptedStorage(t *testing.T) {
+	ctx := context.Background()
+	keyring := newStaticKeyring(t, "acme", "globex")
+	keyring.contexts[".acme"] = "acme"
+	keyring.contexts[".globex"] = "globex"
+	backend := &recordStorage{schemas: make(map[int64]*SchemaRecord)}
+	store := NewEncryptedStorage(backend, keyring)
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		record := &SchemaRecord{Subject: "orders", Schema: `"string"`}
+		if err := store.CreateSchema(ctx, ".acme", record); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if record.Schema != `"string"` {
+			t.Errorf("caller's record lost its plaintext: %q", record.Schema)
+		}
+		stored := backend.schemas[record.ID].Schema
+		if !strings.HasPrefix(stored, encryptedPrefix+"acme:") || strings.Contains(stored, "string") {
+			t.Errorf("expected sealed schema text, got %q", stored)
+		}
+
+		got, err := store.GetSchemaByID(ctx, ".acme", record.ID)
+		if err != nil {
+			t.Fatalf("GetSchemaByID: %v", err)
+		}
+		if got.Schema != `"string"` {
+			t.Errorf("expected plaintext schema, got %q", got.Schema)
+		}
+		if backend.schemas[record.ID].Schema != stored {
+			t.Error("reading mutated the backend's record")
+		}
+	})
+
+	t.Run("PlaintextContext", func(t *testing.T) {
+		record := &SchemaRecord{Subject: "orders", Schema: `"int"`}
+		if err := store.CreateSchema(ctx, ".", record); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if backend.schemas[record.ID].Schema != `"int"` {
+			t.Errorf("expected plaintext in untenanted context, got %q", backend.schemas[record.ID].Schema)
+		}
+		got, err := store.GetSchemaByID(ctx, ".", record.ID)
+		if err != nil || got.Schema != `"int"` {
+			t.Errorf("GetSchemaByID = %v, %v", got, err)
+		}
+	})
+
+	t.Run("BoundToContext", func(t *testing.T) {
+		record := &SchemaRecord{Subject: "orders", Schema: `"long"`}
+		if err := store.CreateSchema(ctx, ".acme", record); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if _, err := store.GetSchemaByID(ctx, ".globex", record.ID); !errors.Is(err, ErrSchemaKeyUnavailable) {
+			t.Errorf("expected ErrSchemaKeyUnavailable reading from another context, got %v", err)
+		}
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		backend.schemas[99] = &SchemaRecord{ID: 99, Schema: encryptedPrefix + "initech:AAAA"}
+		if _, err := store.GetSchemaByID(ctx, ".initech", 99); !errors.Is(err, ErrSchemaKeyUnavailable) {
+			t.Errorf("expected ErrSchemaKeyUnavailable for unknown key, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,690 @@
+// Package fallback keeps the registry readable while its storage backend is
+// down. It wraps the backend, keeps a local snapshot of the schemas,
+// configs, modes, contexts and tenants it holds, and serves reads from the
+// snapshot while the backend is unavailable. Serializers and deserializers
+// then keep resolving schemas during a database outage; writes are refused
+// with the registry in READONLY mode until the backend returns.
+package fallback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	registrycontext "github.com/axonops/axonops-schema-registry/internal/context"
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+// snapshotVersion is the version of the snapshot file format.
+const snapshotVersion = 1
+
+// modeReadOnly is the mode of every subject and context while the backend
+// is unavailable.
+const modeReadOnly = "READONLY"
+
+// Store wraps a storage backend and serves reads from a snapshot of it while
+// it is unavailable. The backend is considered unavailable when it reports
+// itself unhealthy, either to the periodic check or after a read fails with
+// an error other than a record not being found. While it is, reads are
+// answered from the snapshot and every subject and context reports the
+// READONLY mode, so the registry refuses writes instead of failing them
+// half-way. The next successful check returns to the backend.
+//
+// Only the schema data the registry reads is in the snapshot. Users, API
+// keys, jobs and the other administrative records are read from the backend
+// at all times, as are the configs and modes of subjects without versions,
+// such as subject aliases.
+type Store struct {
+	storage.Storage
+	path   string
+	logger *slog.Logger
+
+	degraded atomic.Bool
+
+	mu       sync.RWMutex
+	snapshot storage.Storage // nil until a snapshot is loaded or taken
+	takenAt  time.Time
+}
+
+// New wraps primary, loading the snapshot kept at path if there is one, so
+// that an instance started during an outage can serve it.
+func New(primary storage.Storage, path string, logger *slog.Logger) *Store {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s := &Store{Storage: primary, path: path, logger: logger}
+	if err := s.load(context.Background()); err != nil {
+		logger.Warn("failed to load the storage snapshot, reads cannot fall back until it is refreshed",
+			slog.String("path", path),
+			slog.String("error", err.Error()),
+		)
+	}
+	return s
+}
+
+// Degraded reports whether the backend is unavailable and reads are served
+// from the snapshot.
+func (s *Store) Degraded() bool {
+	return s.degraded.Load()
+}
+
+// SnapshotTakenAt returns when the current snapshot was taken, or the zero
+// time if there is none.
+func (s *Store) SnapshotTakenAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.takenAt
+}
+
+// serving returns the snapshot reads are served from, or nil if the backend
+// is available or there is no snapshot.
+func (s *Store) serving() storage.Storage {
+	if !s.degraded.Load() {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snapshot
+}
+
+// setDegraded records whether the backend is available, logging changes.
+func (s *Store) setDegraded(degraded bool) {
+	if !s.degraded.CompareAndSwap(!degraded, degraded) {
+		return
+	}
+	if !degraded {
+		s.logger.Info("storage backend is available again, leaving read-only mode")
+		return
+	}
+	takenAt := s.SnapshotTakenAt()
+	if takenAt.IsZero() {
+		s.logger.Error("storage backend is unavailable and there is no snapshot to serve reads from")
+		return
+	}
+	s.logger.Warn("storage backend is unavailable, serving reads from the snapshot in read-only mode",
+		slog.Time("snapshot_taken_at", takenAt),
+	)
+}
+
+// failed reports whether err from a read means the backend is unavailable,
+// marking it so. Records that do not exist are answers, not failures, and
+// are never checked.
+func (s *Store) failed(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	for _, expected := range []error{
+		storage.ErrNotFound, storage.ErrSubjectNotFound, storage.ErrSchemaNotFound,
+		storage.ErrVersionNotFound, storage.ErrInvalidVersion, storage.ErrSubjectDeleted,
+		storage.ErrContextNotFound, storage.ErrTenantNotFound, storage.ErrSchemaKeyUnavailable,
+	} {
+		if errors.Is(err, expected) {
+			return false
+		}
+	}
+	if s.Storage.IsHealthy(ctx) {
+		return false
+	}
+	s.setDegraded(true)
+	return true
+}
+
+// read runs op against the backend, or against the snapshot if the backend
+// is unavailable or becomes so.
+func read[T any](ctx context.Context, s *Store, op func(storage.Storage) (T, error)) (T, error) {
+	if snapshot := s.serving(); snapshot != nil {
+		return op(snapshot)
+	}
+	v, err := op(s.Storage)
+	if err == nil || !s.failed(ctx, err) {
+		return v, err
+	}
+	if snapshot := s.serving(); snapshot != nil {
+		return op(snapshot)
+	}
+	return v, err
+}
+
+// Start checks the backend every checkInterval and refreshes the snapshot
+// every refreshInterval while it is available, until stop is closed. The
+// first snapshot is taken at once if the backend is available.
+func (s *Store) Start(refreshInterval, checkInterval time.Duration, stop <-chan struct{}) {
+	go func() {
+		s.Check(context.Background())
+		if !s.Degraded() {
+			s.refreshOnce(context.Background())
+		}
+
+		refresh := time.NewTicker(refreshInterval)
+		defer refresh.Stop()
+		check := time.NewTicker(checkInterval)
+		defer check.Stop()
+		for {
+			select {
+			case <-check.C:
+				s.Check(context.Background())
+			case <-refresh.C:
+				if !s.Degraded() {
+					s.refreshOnce(context.Background())
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Check checks whether the backend is available. Returning to a backend
+// that was unavailable refreshes the snapshot.
+func (s *Store) Check(ctx context.Context) {
+	healthy := s.Storage.IsHealthy(ctx)
+	wasDegraded := s.Degraded()
+	s.setDegraded(!healthy)
+	if healthy && wasDegraded {
+		s.refreshOnce(ctx)
+	}
+}
+
+// refreshOnce refreshes the snapshot and logs a failure.
+func (s *Store) refreshOnce(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Error("failed to refresh the storage snapshot",
+			slog.String("path", s.path),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// Refresh takes a snapshot of the backend, writes it to the snapshot file
+// and serves reads from it from then on. A failed refresh keeps the
+// previous snapshot.
+func (s *Store) Refresh(ctx context.Context) error {
+	snap, err := take(ctx, s.Storage)
+	if err != nil {
+		return err
+	}
+	snapshot, err := snap.restore(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := writeFile(s.path, data); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.snapshot = snapshot
+	s.takenAt = snap.TakenAt
+	s.mu.Unlock()
+	return nil
+}
+
+// load serves reads from the snapshot file, if there is one.
+func (s *Store) load(ctx context.Context) error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	snapshot, err := snap.restore(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.snapshot = snapshot
+	s.takenAt = snap.TakenAt
+	s.mu.Unlock()
+	return nil
+}
+
+// writeFile replaces the file at path with data, so that a crash never
+// leaves a partly written snapshot behind.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// IsHealthy reports the backend as healthy while reads are served from the
+// snapshot, so that readiness checks keep the instance in service.
+func (s *Store) IsHealthy(ctx context.Context) bool {
+	if s.serving() != nil {
+		return true
+	}
+	return s.Storage.IsHealthy(ctx)
+}
+
+// GetSchemaByID reads through to the snapshot while the backend is down.
+func (s *Store) GetSchemaByID(ctx context.Context, registryCtx string, id int64) (*storage.SchemaRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.SchemaRecord, error) {
+		return st.GetSchemaByID(ctx, registryCtx, id)
+	})
+}
+
+// GetSchemaBySubjectVersion reads through to the snapshot while the backend is down.
+func (s *Store) GetSchemaBySubjectVersion(ctx context.Context, registryCtx string, subject string, version int) (*storage.SchemaRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.SchemaRecord, error) {
+		return st.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
+	})
+}
+
+// GetSchemasBySubject reads through to the snapshot while the backend is down.
+func (s *Store) GetSchemasBySubject(ctx context.Context, registryCtx string, subject string, includeDeleted bool) ([]*storage.SchemaRecord, error) {
+	return read(ctx, s, func(st storage.Storage) ([]*storage.SchemaRecord, error) {
+		return st.GetSchemasBySubject(ctx, registryCtx, subject, includeDeleted)
+	})
+}
+
+// GetSchemaByFingerprint reads through to the snapshot while the backend is down.
+func (s *Store) GetSchemaByFingerprint(ctx context.Context, registryCtx string, subject, fingerprint string, includeDeleted bool) (*storage.SchemaRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.SchemaRecord, error) {
+		return st.GetSchemaByFingerprint(ctx, registryCtx, subject, fingerprint, includeDeleted)
+	})
+}
+
+// GetSchemaByGlobalFingerprint reads through to the snapshot while the backend is down.
+func (s *Store) GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx string, fingerprint string) (*storage.SchemaRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.SchemaRecord, error) {
+		return st.GetSchemaByGlobalFingerprint(ctx, registryCtx, fingerprint)
+	})
+}
+
+// GetLatestSchema reads through to the snapshot while the backend is down.
+func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject string) (*storage.SchemaRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.SchemaRecord, error) {
+		return st.GetLatestSchema(ctx, registryCtx, subject)
+	})
+}
+
+// GetLatestSchemaStamp reads through to the snapshot while the backend is down.
+func (s *Store) GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error) {
+	return read(ctx, s, func(st storage.Storage) (string, error) {
+		return st.GetLatestSchemaStamp(ctx, registryCtx, subject)
+	})
+}
+
+// ListSubjects reads through to the snapshot while the backend is down.
+func (s *Store) ListSubjects(ctx context.Context, registryCtx string, includeDeleted bool) ([]string, error) {
+	return read(ctx, s, func(st storage.Storage) ([]string, error) {
+		return st.ListSubjects(ctx, registryCtx, includeDeleted)
+	})
+}
+
+// ListSubjectsPage reads through to the snapshot while the backend is down.
+func (s *Store) ListSubjectsPage(ctx context.Context, registryCtx string, params *storage.ListSubjectsParams) ([]string, error) {
+	return read(ctx, s, func(st storage.Storage) ([]string, error) {
+		return st.ListSubjectsPage(ctx, registryCtx, params)
+	})
+}
+
+// ListVersions reads through to the snapshot while the backend is down.
+func (s *Store) ListVersions(ctx context.Context, registryCtx string, subject string, params *storage.ListVersionsParams) ([]int, error) {
+	return read(ctx, s, func(st storage.Storage) ([]int, error) {
+		return st.ListVersions(ctx, registryCtx, subject, params)
+	})
+}
+
+// SubjectExists reads through to the snapshot while the backend is down.
+func (s *Store) SubjectExists(ctx context.Context, registryCtx string, subject string) (bool, error) {
+	return read(ctx, s, func(st storage.Storage) (bool, error) {
+		return st.SubjectExists(ctx, registryCtx, subject)
+	})
+}
+
+// GetConfig reads through to the snapshot while the backend is down.
+func (s *Store) GetConfig(ctx context.Context, registryCtx string, subject string) (*storage.ConfigRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.ConfigRecord, error) {
+		return st.GetConfig(ctx, registryCtx, subject)
+	})
+}
+
+// GetGlobalConfig reads through to the snapshot while the backend is down.
+func (s *Store) GetGlobalConfig(ctx context.Context, registryCtx string) (*storage.ConfigRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.ConfigRecord, error) {
+		return st.GetGlobalConfig(ctx, registryCtx)
+	})
+}
+
+// GetMode returns READONLY while the backend is down.
+func (s *Store) GetMode(ctx context.Context, registryCtx string, subject string) (*storage.ModeRecord, error) {
+	rec, err := read(ctx, s, func(st storage.Storage) (*storage.ModeRecord, error) {
+		return st.GetMode(ctx, registryCtx, subject)
+	})
+	if s.Degraded() {
+		return &storage.ModeRecord{Subject: subject, Mode: modeReadOnly}, nil
+	}
+	return rec, err
+}
+
+// GetGlobalMode returns READONLY while the backend is down.
+func (s *Store) GetGlobalMode(ctx context.Context, registryCtx string) (*storage.ModeRecord, error) {
+	rec, err := read(ctx, s, func(st storage.Storage) (*storage.ModeRecord, error) {
+		return st.GetGlobalMode(ctx, registryCtx)
+	})
+	if s.Degraded() {
+		return &storage.ModeRecord{Mode: modeReadOnly}, nil
+	}
+	return rec, err
+}
+
+// GetReferencedBy reads through to the snapshot while the backend is down.
+func (s *Store) GetReferencedBy(ctx context.Context, registryCtx string, subject string, version int) ([]storage.SubjectVersion, error) {
+	return read(ctx, s, func(st storage.Storage) ([]storage.SubjectVersion, error) {
+		return st.GetReferencedBy(ctx, registryCtx, subject, version)
+	})
+}
+
+// GetSubjectsBySchemaID reads through to the snapshot while the backend is down.
+func (s *Store) GetSubjectsBySchemaID(ctx context.Context, registryCtx string, id int64, includeDeleted bool) ([]string, error) {
+	return read(ctx, s, func(st storage.Storage) ([]string, error) {
+		return st.GetSubjectsBySchemaID(ctx, registryCtx, id, includeDeleted)
+	})
+}
+
+// GetVersionsBySchemaID reads through to the snapshot while the backend is down.
+func (s *Store) GetVersionsBySchemaID(ctx context.Context, registryCtx string, id int64, includeDeleted bool) ([]storage.SubjectVersion, error) {
+	return read(ctx, s, func(st storage.Storage) ([]storage.SubjectVersion, error) {
+		return st.GetVersionsBySchemaID(ctx, registryCtx, id, includeDeleted)
+	})
+}
+
+// ListSchemas reads through to the snapshot while the backend is down.
+func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
+	return read(ctx, s, func(st storage.Storage) ([]*storage.SchemaRecord, error) {
+		return st.ListSchemas(ctx, registryCtx, params)
+	})
+}
+
+// IterateSchemas reads through to the snapshot while the backend is down. A
+// backend failure part way through is returned rather than resumed from the
+// snapshot, so fn never sees a version twice.
+func (s *Store) IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*storage.SchemaRecord) error) error {
+	if snapshot := s.serving(); snapshot != nil {
+		return snapshot.IterateSchemas(ctx, registryCtx, includeDeleted, fn)
+	}
+	started := false
+	var fnErr error
+	err := s.Storage.IterateSchemas(ctx, registryCtx, includeDeleted, func(record *storage.SchemaRecord) error {
+		started = true
+		fnErr = fn(record)
+		return fnErr
+	})
+	if err == nil || fnErr != nil || !s.failed(ctx, err) || started {
+		return err
+	}
+	if snapshot := s.serving(); snapshot != nil {
+		return snapshot.IterateSchemas(ctx, registryCtx, includeDeleted, fn)
+	}
+	return err
+}
+
+// ListContexts reads through to the snapshot while the backend is down.
+func (s *Store) ListContexts(ctx context.Context) ([]string, error) {
+	return read(ctx, s, func(st storage.Storage) ([]string, error) {
+		return st.ListContexts(ctx)
+	})
+}
+
+// GetContext reads through to the snapshot while the backend is down.
+func (s *Store) GetContext(ctx context.Context, name string) (*storage.ContextRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.ContextRecord, error) {
+		return st.GetContext(ctx, name)
+	})
+}
+
+// GetTenant reads through to the snapshot while the backend is down.
+func (s *Store) GetTenant(ctx context.Context, name string) (*storage.TenantRecord, error) {
+	return read(ctx, s, func(st storage.Storage) (*storage.TenantRecord, error) {
+		return st.GetTenant(ctx, name)
+	})
+}
+
+// ListTenants reads through to the snapshot while the backend is down.
+func (s *Store) ListTenants(ctx context.Context) ([]*storage.TenantRecord, error) {
+	return read(ctx, s, func(st storage.Storage) ([]*storage.TenantRecord, error) {
+		return st.ListTenants(ctx)
+	})
+}
+
+// snapshot is the content of the snapshot file. Schema text is kept as the
+// backend stores it, so schemas encrypted at rest stay encrypted.
+type snapshot struct {
+	Version  int               `json:"version"`
+	TakenAt  time.Time         `json:"taken_at"`
+	Contexts []snapshotContext `json:"contexts"`
+	Tenants  []snapshotTenant  `json:"tenants,omitempty"`
+}
+
+type snapshotContext struct {
+	Name         string                  `json:"name"`
+	Record       *storage.ContextRecord  `json:"record,omitempty"`
+	Schemas      []snapshotSchema        `json:"schemas,omitempty"`
+	GlobalConfig *storage.ConfigRecord   `json:"global_config,omitempty"`
+	Configs      []*storage.ConfigRecord `json:"configs,omitempty"`
+	GlobalMode   *storage.ModeRecord     `json:"global_mode,omitempty"`
+	Modes        []*storage.ModeRecord   `json:"modes,omitempty"`
+}
+
+type snapshotSchema struct {
+	ID          int64               `json:"id"`
+	Subject     string              `json:"subject"`
+	Version     int                 `json:"version"`
+	SchemaType  storage.SchemaType  `json:"schema_type"`
+	Schema      string              `json:"schema"`
+	References  []storage.Reference `json:"references,omitempty"`
+	Metadata    *storage.Metadata   `json:"metadata,omitempty"`
+	RuleSet     *storage.RuleSet    `json:"rule_set,omitempty"`
+	Fingerprint string              `json:"fingerprint"`
+	Deleted     bool                `json:"deleted,omitempty"`
+}
+
+type snapshotTenant struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	EncryptedKey string `json:"encrypted_key"`
+}
+
+// take reads a snapshot of every context of the backend, the default and
+// __GLOBAL contexts included.
+func take(ctx context.Context, st storage.Storage) (*snapshot, error) {
+	snap := &snapshot{Version: snapshotVersion, TakenAt: time.Now().UTC()}
+
+	names, err := st.ListContexts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contexts: %w", err)
+	}
+	seen := make(map[string]bool, len(names)+2)
+	for _, name := range append([]string{registrycontext.DefaultContext, registrycontext.GlobalContext}, names...) {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		sc, err := takeContext(ctx, st, name)
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", name, err)
+		}
+		snap.Contexts = append(snap.Contexts, *sc)
+	}
+
+	tenants, err := st.ListTenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	for _, t := range tenants {
+		snap.Tenants = append(snap.Tenants, snapshotTenant{Name: t.Name, Description: t.Description, EncryptedKey: t.EncryptedKey})
+	}
+	return snap, nil
+}
+
+// takeContext reads the snapshot of one context.
+func takeContext(ctx context.Context, st storage.Storage, name string) (*snapshotContext, error) {
+	sc := &snapshotContext{Name: name}
+
+	rec, err := st.GetContext(ctx, name)
+	if err != nil && !errors.Is(err, storage.ErrContextNotFound) {
+		return nil, err
+	}
+	sc.Record = rec
+	if sc.GlobalConfig, err = st.GetGlobalConfig(ctx, name); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	if sc.GlobalMode, err = st.GetGlobalMode(ctx, name); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	subjects, err := st.ListSubjects(ctx, name, true)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(subjects)
+	for _, subject := range subjects {
+		versions, err := st.GetSchemasBySubject(ctx, name, subject, true)
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			sc.Schemas = append(sc.Schemas, snapshotSchema{
+				ID:          v.ID,
+				Subject:     v.Subject,
+				Version:     v.Version,
+				SchemaType:  v.SchemaType,
+				Schema:      v.Schema,
+				References:  v.References,
+				Metadata:    v.Metadata,
+				RuleSet:     v.RuleSet,
+				Fingerprint: v.Fingerprint,
+				Deleted:     v.Deleted,
+			})
+		}
+
+		config, err := st.GetConfig(ctx, name, subject)
+		if err == nil {
+			config.Subject = subject
+			sc.Configs = append(sc.Configs, config)
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		mode, err := st.GetMode(ctx, name, subject)
+		if err == nil {
+			mode.Subject = subject
+			sc.Modes = append(sc.Modes, mode)
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return sc, nil
+}
+
+// restore loads the snapshot into an in-memory store.
+func (snap *snapshot) restore(ctx context.Context) (storage.Storage, error) {
+	st := memory.NewStore()
+	for _, t := range snap.Tenants {
+		if err := st.CreateTenant(ctx, &storage.TenantRecord{Name: t.Name, Description: t.Description, EncryptedKey: t.EncryptedKey}); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", t.Name, err)
+		}
+	}
+	for _, sc := range snap.Contexts {
+		if err := sc.restore(ctx, st); err != nil {
+			return nil, fmt.Errorf("context %s: %w", sc.Name, err)
+		}
+	}
+	return st, nil
+}
+
+// restore loads one context of the snapshot into st.
+func (sc *snapshotContext) restore(ctx context.Context, st *memory.Store) error {
+	if sc.Record != nil {
+		rec := *sc.Record
+		if err := st.CreateContext(ctx, &rec); err != nil && !errors.Is(err, storage.ErrContextExists) {
+			return err
+		}
+	}
+	if sc.GlobalConfig != nil {
+		if err := st.SetGlobalConfig(ctx, sc.Name, sc.GlobalConfig); err != nil {
+			return err
+		}
+	}
+	if sc.GlobalMode != nil {
+		if err := st.SetGlobalMode(ctx, sc.Name, sc.GlobalMode); err != nil {
+			return err
+		}
+	}
+	for _, v := range sc.Schemas {
+		rec := &storage.SchemaRecord{
+			ID:          v.ID,
+			Subject:     v.Subject,
+			Version:     v.Version,
+			SchemaType:  v.SchemaType,
+			Schema:      v.Schema,
+			References:  v.References,
+			Metadata:    v.Metadata,
+			RuleSet:     v.RuleSet,
+			Fingerprint: v.Fingerprint,
+		}
+		if err := st.ImportSchema(ctx, sc.Name, rec); err != nil {
+			return fmt.Errorf("schema %s version %d: %w", v.Subject, v.Version, err)
+		}
+	}
+	for _, v := range sc.Schemas {
+		if !v.Deleted {
+			continue
+		}
+		if err := st.DeleteSchema(ctx, sc.Name, v.Subject, v.Version, false); err != nil {
+			return fmt.Errorf("schema %s version %d: %w", v.Subject, v.Version, err)
+		}
+	}
+	for _, config := range sc.Configs {
+		if err := st.SetConfig(ctx, sc.Name, config.Subject, config); err != nil {
+			return err
+		}
+	}
+	for _, mode := range sc.Modes {
+		if err := st.SetMode(ctx, sc.Name, mode.Subject, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
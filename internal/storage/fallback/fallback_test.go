@@ -0,0 +1,157 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+var errConnRefused = errors.New("connection refused")
+
+// outage is a backend whose reads fail while it is down.
+type outage struct {
+	storage.Storage
+	down atomic.Bool
+}
+
+func (o *outage) IsHealthy(ctx context.Context) bool {
+	return !o.down.Load()
+}
+
+func (o *outage) GetSchemaByID(ctx context.Context, registryCtx string, id int64) (*storage.SchemaRecord, error) {
+	if o.down.Load() {
+		return nil, errConnRefused
+	}
+	return o.Storage.GetSchemaByID(ctx, registryCtx, id)
+}
+
+func (o *outage) GetSchemasBySubject(ctx context.Context, registryCtx string, subject string, includeDeleted bool) ([]*storage.SchemaRecord, error) {
+	if o.down.Load() {
+		return nil, errConnRefused
+	}
+	return o.Storage.GetSchemasBySubject(ctx, registryCtx, subject, includeDeleted)
+}
+
+func (o *outage) GetGlobalMode(ctx context.Context, registryCtx string) (*storage.ModeRecord, error) {
+	if o.down.Load() {
+		return nil, errConnRefused
+	}
+	return o.Storage.GetGlobalMode(ctx, registryCtx)
+}
+
+// newPrimary returns a backend holding two versions of subject "orders",
+// the first soft-deleted, a subject config and a context with limits.
+func newPrimary(t *testing.T) (*outage, int64) {
+	t.Helper()
+	ctx := context.Background()
+	st := memory.NewStore()
+	var id int64
+	for i, schema := range []string{`"string"`, `"int"`} {
+		rec := &storage.SchemaRecord{Subject: "orders", SchemaType: storage.SchemaTypeAvro, Schema: schema, Fingerprint: "fp" + schema}
+		if err := st.CreateSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if i == 0 {
+			if err := st.DeleteSchema(ctx, ".", "orders", rec.Version, false); err != nil {
+				t.Fatalf("DeleteSchema: %v", err)
+			}
+		}
+		id = rec.ID
+	}
+	if err := st.SetConfig(ctx, ".", "orders", &storage.ConfigRecord{CompatibilityLevel: "FULL"}); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	if err := st.CreateContext(ctx, &storage.ContextRecord{Name: ".team", MaxSubjects: 5}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	return &outage{Storage: st}, id
+}
+
+func TestStore_ServesSnapshotWhileDown(t *testing.T) {
+	ctx := context.Background()
+	primary, id := newPrimary(t)
+	s := New(primary, filepath.Join(t.TempDir(), "snapshot.json"), nil)
+	if err := s.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	primary.down.Store(true)
+	rec, err := s.GetSchemaByID(ctx, ".", id)
+	if err != nil || rec.Schema != `"int"` {
+		t.Fatalf("expected the schema from the snapshot, got %+v, %v", rec, err)
+	}
+	if !s.Degraded() {
+		t.Error("expected a failed read to mark the backend unavailable")
+	}
+	if !s.IsHealthy(ctx) {
+		t.Error("expected the store to stay healthy while it serves the snapshot")
+	}
+	if mode, err := s.GetGlobalMode(ctx, "."); err != nil || mode.Mode != "READONLY" {
+		t.Errorf("expected READONLY while the backend is down, got %+v, %v", mode, err)
+	}
+	if live, err := s.GetSchemasBySubject(ctx, ".", "orders", false); err != nil || len(live) != 1 {
+		t.Errorf("expected the soft-deleted version to stay deleted, got %d versions, %v", len(live), err)
+	}
+	var iterated int
+	if err := s.IterateSchemas(ctx, ".", true, func(*storage.SchemaRecord) error { iterated++; return nil }); err != nil || iterated != 2 {
+		t.Errorf("expected both versions from the snapshot, got %d, %v", iterated, err)
+	}
+	if config, err := s.GetConfig(ctx, ".", "orders"); err != nil || config.CompatibilityLevel != "FULL" {
+		t.Errorf("expected the subject config from the snapshot, got %+v, %v", config, err)
+	}
+	if rec, err := s.GetContext(ctx, ".team"); err != nil || rec.MaxSubjects != 5 {
+		t.Errorf("expected the context from the snapshot, got %+v, %v", rec, err)
+	}
+
+	primary.down.Store(false)
+	s.Check(ctx)
+	if s.Degraded() {
+		t.Fatal("expected the backend to be available again")
+	}
+	if _, err := s.GetGlobalMode(ctx, "."); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected the backend's mode once it is back, got %v", err)
+	}
+}
+
+func TestStore_LoadsSnapshotFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	primary, id := newPrimary(t)
+	if err := New(primary, path, nil).Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	// An instance started during an outage serves the snapshot file.
+	primary.down.Store(true)
+	s := New(primary, path, nil)
+	if s.SnapshotTakenAt().IsZero() {
+		t.Fatal("expected the snapshot file to be loaded")
+	}
+	if rec, err := s.GetSchemaByID(ctx, ".", id); err != nil || rec.Schema != `"int"` {
+		t.Errorf("expected the schema from the snapshot file, got %+v, %v", rec, err)
+	}
+}
+
+func TestStore_NotFoundIsNotAnOutage(t *testing.T) {
+	ctx := context.Background()
+	primary, _ := newPrimary(t)
+	s := New(primary, filepath.Join(t.TempDir(), "snapshot.json"), nil)
+
+	if _, err := s.GetSchemaByID(ctx, ".", 999); !errors.Is(err, storage.ErrSchemaNotFound) {
+		t.Errorf("expected ErrSchemaNotFound, got %v", err)
+	}
+	if s.Degraded() {
+		t.Error("expected a missing schema not to mark the backend unavailable")
+	}
+
+	// Without a snapshot the backend's error is returned.
+	primary.down.Store(true)
+	if _, err := s.GetSchemaByID(ctx, ".", 1); !errors.Is(err, errConnRefused) {
+		t.Errorf("expected the backend error without a snapshot, got %v", err)
+	}
+}
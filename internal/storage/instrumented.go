@@ -3,8 +3,16 @@ package storage
 import (
 	"context"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracerName names the instrumentation scope of storage spans.
+const tracerName = "github.com/axonops/axonops-schema-registry/internal/storage"
+
 // MetricsRecorder is the interface that the instrumented storage wrapper uses
 // to record storage operation metrics. This avoids a circular import between
 // the storage and metrics packages.
@@ -13,7 +21,8 @@ type MetricsRecorder interface {
 }
 
 // InstrumentedStorage wraps a Storage implementation and records metrics
-// for each storage operation using the provided MetricsRecorder.
+// for each storage operation using the provided MetricsRecorder. Each
+// operation is also traced as a child span of the span in its context.
 type InstrumentedStorage struct {
 	Storage
 	backend  string
@@ -30,158 +39,375 @@ func NewInstrumentedStorage(store Storage, backend string, recorder MetricsRecor
 	}
 }
 
-// record is a helper that records a storage operation's duration and error.
-func (s *InstrumentedStorage) record(operation string, start time.Time, err error) {
+// begin starts the span for a storage operation and returns its context and
+// start time.
+func (s *InstrumentedStorage) begin(ctx context.Context, operation string) (context.Context, time.Time) {
+	ctx, _ = otel.Tracer(tracerName).Start(ctx, "storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", s.backend),
+			attribute.String("db.operation", operation),
+		),
+	)
+	return ctx, time.Now()
+}
+
+// record is a helper that records a storage operation's duration and error
+// and ends the span begin started.
+func (s *InstrumentedStorage) record(ctx context.Context, operation string, start time.Time, err error) {
 	s.recorder.RecordStorageOperation(s.backend, operation, time.Since(start), err)
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
 
 // --- Schema operations ---
 
 func (s *InstrumentedStorage) CreateSchema(ctx context.Context, registryCtx string, record *SchemaRecord) error {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "create_schema")
 	err := s.Storage.CreateSchema(ctx, registryCtx, record)
-	s.record("create_schema", start, err)
+	s.record(ctx, "create_schema", start, err)
 	return err
 }
 
 func (s *InstrumentedStorage) GetSchemaByID(ctx context.Context, registryCtx string, id int64) (*SchemaRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_schema_by_id")
 	rec, err := s.Storage.GetSchemaByID(ctx, registryCtx, id)
-	s.record("get_schema_by_id", start, err)
+	s.record(ctx, "get_schema_by_id", start, err)
 	return rec, err
 }
 
 func (s *InstrumentedStorage) GetSchemaBySubjectVersion(ctx context.Context, registryCtx string, subject string, version int) (*SchemaRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_schema_by_subject_version")
 	rec, err := s.Storage.GetSchemaBySubjectVersion(ctx, registryCtx, subject, version)
-	s.record("get_schema_by_subject_version", start, err)
+	s.record(ctx, "get_schema_by_subject_version", start, err)
 	return rec, err
 }
 
 func (s *InstrumentedStorage) GetSchemasBySubject(ctx context.Context, registryCtx string, subject string, includeDeleted bool) ([]*SchemaRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_schemas_by_subject")
 	recs, err := s.Storage.GetSchemasBySubject(ctx, registryCtx, subject, includeDeleted)
-	s.record("get_schemas_by_subject", start, err)
+	s.record(ctx, "get_schemas_by_subject", start, err)
 	return recs, err
 }
 
 func (s *InstrumentedStorage) GetSchemaByFingerprint(ctx context.Context, registryCtx string, subject, fingerprint string, includeDeleted bool) (*SchemaRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_schema_by_fingerprint")
 	rec, err := s.Storage.GetSchemaByFingerprint(ctx, registryCtx, subject, fingerprint, includeDeleted)
-	s.record("get_schema_by_fingerprint", start, err)
+	s.record(ctx, "get_schema_by_fingerprint", start, err)
 	return rec, err
 }
 
 func (s *InstrumentedStorage) GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx string, fingerprint string) (*SchemaRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_schema_by_global_fingerprint")
 	rec, err := s.Storage.GetSchemaByGlobalFingerprint(ctx, registryCtx, fingerprint)
-	s.record("get_schema_by_global_fingerprint", start, err)
+	s.record(ctx, "get_schema_by_global_fingerprint", start, err)
 	return rec, err
 }
 
 func (s *InstrumentedStorage) GetLatestSchema(ctx context.Context, registryCtx string, subject string) (*SchemaRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_latest_schema")
 	rec, err := s.Storage.GetLatestSchema(ctx, registryCtx, subject)
-	s.record("get_latest_schema", start, err)
+	s.record(ctx, "get_latest_schema", start, err)
 	return rec, err
 }
 
+func (s *InstrumentedStorage) GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error) {
+	ctx, start := s.begin(ctx, "get_latest_schema_stamp")
+	stamp, err := s.Storage.GetLatestSchemaStamp(ctx, registryCtx, subject)
+	s.record(ctx, "get_latest_schema_stamp", start, err)
+	return stamp, err
+}
+
 func (s *InstrumentedStorage) DeleteSchema(ctx context.Context, registryCtx string, subject string, version int, permanent bool) error {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "delete_schema")
 	err := s.Storage.DeleteSchema(ctx, registryCtx, subject, version, permanent)
-	s.record("delete_schema", start, err)
+	s.record(ctx, "delete_schema", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error {
+	ctx, start := s.begin(ctx, "rewrite_schema_text")
+	err := s.Storage.RewriteSchemaText(ctx, registryCtx, id, schemaText)
+	s.record(ctx, "rewrite_schema_text", start, err)
 	return err
 }
 
 // --- Subject operations ---
 
 func (s *InstrumentedStorage) ListSubjects(ctx context.Context, registryCtx string, includeDeleted bool) ([]string, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "list_subjects")
 	subjects, err := s.Storage.ListSubjects(ctx, registryCtx, includeDeleted)
-	s.record("list_subjects", start, err)
+	s.record(ctx, "list_subjects", start, err)
 	return subjects, err
 }
 
+func (s *InstrumentedStorage) ListSubjectsPage(ctx context.Context, registryCtx string, params *ListSubjectsParams) ([]string, error) {
+	ctx, start := s.begin(ctx, "list_subjects")
+	subjects, err := s.Storage.ListSubjectsPage(ctx, registryCtx, params)
+	s.record(ctx, "list_subjects", start, err)
+	return subjects, err
+}
+
+func (s *InstrumentedStorage) ListVersions(ctx context.Context, registryCtx string, subject string, params *ListVersionsParams) ([]int, error) {
+	ctx, start := s.begin(ctx, "list_versions")
+	versions, err := s.Storage.ListVersions(ctx, registryCtx, subject, params)
+	s.record(ctx, "list_versions", start, err)
+	return versions, err
+}
+
+func (s *InstrumentedStorage) ListSoftDeletedVersions(ctx context.Context, registryCtx string, deletedBefore time.Time) ([]SubjectVersion, error) {
+	ctx, start := s.begin(ctx, "list_soft_deleted_versions")
+	versions, err := s.Storage.ListSoftDeletedVersions(ctx, registryCtx, deletedBefore)
+	s.record(ctx, "list_soft_deleted_versions", start, err)
+	return versions, err
+}
+
 func (s *InstrumentedStorage) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "delete_subject")
 	versions, err := s.Storage.DeleteSubject(ctx, registryCtx, subject, permanent)
-	s.record("delete_subject", start, err)
+	s.record(ctx, "delete_subject", start, err)
 	return versions, err
 }
 
 func (s *InstrumentedStorage) SubjectExists(ctx context.Context, registryCtx string, subject string) (bool, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "subject_exists")
 	exists, err := s.Storage.SubjectExists(ctx, registryCtx, subject)
-	s.record("subject_exists", start, err)
+	s.record(ctx, "subject_exists", start, err)
 	return exists, err
 }
 
 // --- Config operations ---
 
 func (s *InstrumentedStorage) GetConfig(ctx context.Context, registryCtx string, subject string) (*ConfigRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_config")
 	cfg, err := s.Storage.GetConfig(ctx, registryCtx, subject)
-	s.record("get_config", start, err)
+	s.record(ctx, "get_config", start, err)
 	return cfg, err
 }
 
 func (s *InstrumentedStorage) SetConfig(ctx context.Context, registryCtx string, subject string, config *ConfigRecord) error {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "set_config")
 	err := s.Storage.SetConfig(ctx, registryCtx, subject, config)
-	s.record("set_config", start, err)
+	s.record(ctx, "set_config", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) DeleteConfig(ctx context.Context, registryCtx string, subject string) error {
+	ctx, start := s.begin(ctx, "delete_config")
+	err := s.Storage.DeleteConfig(ctx, registryCtx, subject)
+	s.record(ctx, "delete_config", start, err)
 	return err
 }
 
 func (s *InstrumentedStorage) GetGlobalConfig(ctx context.Context, registryCtx string) (*ConfigRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "get_global_config")
 	cfg, err := s.Storage.GetGlobalConfig(ctx, registryCtx)
-	s.record("get_global_config", start, err)
+	s.record(ctx, "get_global_config", start, err)
 	return cfg, err
 }
 
 func (s *InstrumentedStorage) SetGlobalConfig(ctx context.Context, registryCtx string, config *ConfigRecord) error {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "set_global_config")
 	err := s.Storage.SetGlobalConfig(ctx, registryCtx, config)
-	s.record("set_global_config", start, err)
+	s.record(ctx, "set_global_config", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) DeleteGlobalConfig(ctx context.Context, registryCtx string) error {
+	ctx, start := s.begin(ctx, "delete_global_config")
+	err := s.Storage.DeleteGlobalConfig(ctx, registryCtx)
+	s.record(ctx, "delete_global_config", start, err)
+	return err
+}
+
+// --- Mode operations ---
+
+func (s *InstrumentedStorage) GetMode(ctx context.Context, registryCtx string, subject string) (*ModeRecord, error) {
+	ctx, start := s.begin(ctx, "get_mode")
+	mode, err := s.Storage.GetMode(ctx, registryCtx, subject)
+	s.record(ctx, "get_mode", start, err)
+	return mode, err
+}
+
+func (s *InstrumentedStorage) SetMode(ctx context.Context, registryCtx string, subject string, mode *ModeRecord) error {
+	ctx, start := s.begin(ctx, "set_mode")
+	err := s.Storage.SetMode(ctx, registryCtx, subject, mode)
+	s.record(ctx, "set_mode", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) DeleteMode(ctx context.Context, registryCtx string, subject string) error {
+	ctx, start := s.begin(ctx, "delete_mode")
+	err := s.Storage.DeleteMode(ctx, registryCtx, subject)
+	s.record(ctx, "delete_mode", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) GetGlobalMode(ctx context.Context, registryCtx string) (*ModeRecord, error) {
+	ctx, start := s.begin(ctx, "get_global_mode")
+	mode, err := s.Storage.GetGlobalMode(ctx, registryCtx)
+	s.record(ctx, "get_global_mode", start, err)
+	return mode, err
+}
+
+func (s *InstrumentedStorage) SetGlobalMode(ctx context.Context, registryCtx string, mode *ModeRecord) error {
+	ctx, start := s.begin(ctx, "set_global_mode")
+	err := s.Storage.SetGlobalMode(ctx, registryCtx, mode)
+	s.record(ctx, "set_global_mode", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) DeleteGlobalMode(ctx context.Context, registryCtx string) error {
+	ctx, start := s.begin(ctx, "delete_global_mode")
+	err := s.Storage.DeleteGlobalMode(ctx, registryCtx)
+	s.record(ctx, "delete_global_mode", start, err)
 	return err
 }
 
 // --- ID operations ---
 
 func (s *InstrumentedStorage) NextID(ctx context.Context, registryCtx string) (int64, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "next_id")
 	id, err := s.Storage.NextID(ctx, registryCtx)
-	s.record("next_id", start, err)
+	s.record(ctx, "next_id", start, err)
+	return id, err
+}
+
+func (s *InstrumentedStorage) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64, error) {
+	ctx, start := s.begin(ctx, "get_max_schema_id")
+	id, err := s.Storage.GetMaxSchemaID(ctx, registryCtx)
+	s.record(ctx, "get_max_schema_id", start, err)
 	return id, err
 }
 
 // --- Import operations ---
 
 func (s *InstrumentedStorage) ImportSchema(ctx context.Context, registryCtx string, record *SchemaRecord) error {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "import_schema")
 	err := s.Storage.ImportSchema(ctx, registryCtx, record)
-	s.record("import_schema", start, err)
+	s.record(ctx, "import_schema", start, err)
 	return err
 }
 
+func (s *InstrumentedStorage) PeekNextID(ctx context.Context, registryCtx string) (int64, error) {
+	ctx, start := s.begin(ctx, "peek_next_id")
+	id, err := s.Storage.PeekNextID(ctx, registryCtx)
+	s.record(ctx, "peek_next_id", start, err)
+	return id, err
+}
+
+func (s *InstrumentedStorage) SetNextID(ctx context.Context, registryCtx string, id int64) error {
+	ctx, start := s.begin(ctx, "set_next_id")
+	err := s.Storage.SetNextID(ctx, registryCtx, id)
+	s.record(ctx, "set_next_id", start, err)
+	return err
+}
+
+// --- Reference and schema ID lookups ---
+
+func (s *InstrumentedStorage) GetReferencedBy(ctx context.Context, registryCtx string, subject string, version int) ([]SubjectVersion, error) {
+	ctx, start := s.begin(ctx, "get_referenced_by")
+	refs, err := s.Storage.GetReferencedBy(ctx, registryCtx, subject, version)
+	s.record(ctx, "get_referenced_by", start, err)
+	return refs, err
+}
+
+func (s *InstrumentedStorage) GetSubjectsBySchemaID(ctx context.Context, registryCtx string, id int64, includeDeleted bool) ([]string, error) {
+	ctx, start := s.begin(ctx, "get_subjects_by_schema_id")
+	subjects, err := s.Storage.GetSubjectsBySchemaID(ctx, registryCtx, id, includeDeleted)
+	s.record(ctx, "get_subjects_by_schema_id", start, err)
+	return subjects, err
+}
+
+func (s *InstrumentedStorage) GetVersionsBySchemaID(ctx context.Context, registryCtx string, id int64, includeDeleted bool) ([]SubjectVersion, error) {
+	ctx, start := s.begin(ctx, "get_versions_by_schema_id")
+	versions, err := s.Storage.GetVersionsBySchemaID(ctx, registryCtx, id, includeDeleted)
+	s.record(ctx, "get_versions_by_schema_id", start, err)
+	return versions, err
+}
+
 // --- Schema listing ---
 
 func (s *InstrumentedStorage) ListSchemas(ctx context.Context, registryCtx string, params *ListSchemasParams) ([]*SchemaRecord, error) {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "list_schemas")
 	recs, err := s.Storage.ListSchemas(ctx, registryCtx, params)
-	s.record("list_schemas", start, err)
+	s.record(ctx, "list_schemas", start, err)
 	return recs, err
 }
 
+func (s *InstrumentedStorage) IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*SchemaRecord) error) error {
+	ctx, start := s.begin(ctx, "iterate_schemas")
+	err := s.Storage.IterateSchemas(ctx, registryCtx, includeDeleted, fn)
+	s.record(ctx, "iterate_schemas", start, err)
+	return err
+}
+
+// --- Context operations ---
+
+func (s *InstrumentedStorage) ListContexts(ctx context.Context) ([]string, error) {
+	ctx, start := s.begin(ctx, "list_contexts")
+	contexts, err := s.Storage.ListContexts(ctx)
+	s.record(ctx, "list_contexts", start, err)
+	return contexts, err
+}
+
+func (s *InstrumentedStorage) CreateContext(ctx context.Context, record *ContextRecord) error {
+	ctx, start := s.begin(ctx, "create_context")
+	err := s.Storage.CreateContext(ctx, record)
+	s.record(ctx, "create_context", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) GetContext(ctx context.Context, name string) (*ContextRecord, error) {
+	ctx, start := s.begin(ctx, "get_context")
+	rec, err := s.Storage.GetContext(ctx, name)
+	s.record(ctx, "get_context", start, err)
+	return rec, err
+}
+
+func (s *InstrumentedStorage) UpdateContext(ctx context.Context, record *ContextRecord) error {
+	ctx, start := s.begin(ctx, "update_context")
+	err := s.Storage.UpdateContext(ctx, record)
+	s.record(ctx, "update_context", start, err)
+	return err
+}
+
+func (s *InstrumentedStorage) DeleteContext(ctx context.Context, name string) error {
+	ctx, start := s.begin(ctx, "delete_context")
+	err := s.Storage.DeleteContext(ctx, name)
+	s.record(ctx, "delete_context", start, err)
+	return err
+}
+
+// --- Exporter operations ---
+
+func (s *InstrumentedStorage) GetExporterStatus(ctx context.Context, name string) (*ExporterStatusRecord, error) {
+	ctx, start := s.begin(ctx, "get_exporter_status")
+	status, err := s.Storage.GetExporterStatus(ctx, name)
+	s.record(ctx, "get_exporter_status", start, err)
+	return status, err
+}
+
+func (s *InstrumentedStorage) SetExporterStatus(ctx context.Context, name string, status *ExporterStatusRecord) error {
+	ctx, start := s.begin(ctx, "set_exporter_status")
+	err := s.Storage.SetExporterStatus(ctx, name, status)
+	s.record(ctx, "set_exporter_status", start, err)
+	return err
+}
+
 // --- Lifecycle ---
 
 func (s *InstrumentedStorage) IsHealthy(ctx context.Context) bool {
-	start := time.Now()
+	ctx, start := s.begin(ctx, "health_check")
 	healthy := s.Storage.IsHealthy(ctx)
 	var err error
 	if !healthy {
 		err = ErrNotFound // Use a sentinel to signal unhealthy
 	}
-	s.record("health_check", start, err)
+	s.record(ctx, "health_check", start, err)
 	return healthy
 }
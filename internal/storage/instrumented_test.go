@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type recordedOp struct {
+	backend   string
+	operation string
+	err       error
+}
+
+type fakeRecorder struct {
+	ops []recordedOp
+}
+
+func (r *fakeRecorder) RecordStorageOperation(backend, operation string, _ time.Duration, err error) {
+	r.ops = append(r.ops, recordedOp{backend: backend, operation: operation, err: err})
+}
+
+// stubStorage implements only the methods exercised by the tests below; any
+// other call panics on the nil embedded interface.
+type stubStorage struct {
+	Storage
+	modeErr error
+}
+
+func (s *stubStorage) GetMode(ctx context.Context, registryCtx string, subject string) (*ModeRecord, error) {
+	if s.modeErr != nil {
+		return nil, s.modeErr
+	}
+	return &ModeRecord{Subject: subject, Mode: "READWRITE"}, nil
+}
+
+func (s *stubStorage) ListContexts(ctx context.Context) ([]string, error) {
+	return []string{"."}, nil
+}
+
+func TestInstrumentedStorage_RecordsOperation(t *testing.T) {
+	rec := &fakeRecorder{}
+	store := NewInstrumentedStorage(&stubStorage{}, "cassandra", rec)
+
+	mode, err := store.GetMode(context.Background(), ".", "orders-value")
+	if err != nil || mode.Mode != "READWRITE" {
+		t.Fatalf("GetMode = %v, %v", mode, err)
+	}
+	if _, err := store.ListContexts(context.Background()); err != nil {
+		t.Fatalf("ListContexts: %v", err)
+	}
+
+	want := []recordedOp{
+		{backend: "cassandra", operation: "get_mode"},
+		{backend: "cassandra", operation: "list_contexts"},
+	}
+	if len(rec.ops) != len(want) {
+		t.Fatalf("expected %d recorded operations, got %d: %+v", len(want), len(rec.ops), rec.ops)
+	}
+	for i, w := range want {
+		if rec.ops[i] != w {
+			t.Errorf("op %d = %+v, want %+v", i, rec.ops[i], w)
+		}
+	}
+}
+
+func TestInstrumentedStorage_RecordsError(t *testing.T) {
+	rec := &fakeRecorder{}
+	backendErr := errors.New("connection refused")
+	store := NewInstrumentedStorage(&stubStorage{modeErr: backendErr}, "postgresql", rec)
+
+	if _, err := store.GetMode(context.Background(), ".", "orders-value"); !errors.Is(err, backendErr) {
+		t.Fatalf("expected backend error to be returned unchanged, got %v", err)
+	}
+	if len(rec.ops) != 1 || !errors.Is(rec.ops[0].err, backendErr) {
+		t.Errorf("expected recorded error, got %+v", rec.ops)
+	}
+}
+
+func TestInstrumentedStorage_TracesOperation(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	backendErr := errors.New("connection refused")
+	store := NewInstrumentedStorage(&stubStorage{modeErr: backendErr}, "postgresql", &fakeRecorder{})
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	_, _ = store.GetMode(ctx, ".", "orders-value")
+	parent.End()
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "storage.get_mode" {
+		t.Errorf("span name = %q, want storage.get_mode", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected the storage span to be a child of the request span")
+	}
+	if span.Status().Code != codes.Error || span.Status().Description != backendErr.Error() {
+		t.Errorf("expected an error status, got %+v", span.Status())
+	}
+	found := false
+	for _, kv := range span.Attributes() {
+		if kv.Key == "db.system" && kv.Value.AsString() == "postgresql" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected db.system=postgresql, got %v", span.Attributes())
+	}
+}
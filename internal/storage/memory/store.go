@@ -3,8 +3,11 @@ package memory
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -20,6 +23,7 @@ type subjectVersionInfo struct {
 	schemaID  int64
 	version   int
 	deleted   bool
+	deletedAt time.Time
 	createdAt time.Time
 	metadata  *storage.Metadata
 	ruleSet   *storage.RuleSet
@@ -49,6 +53,21 @@ type contextStore struct {
 	// modes stores mode configurations by subject
 	modes map[string]*storage.ModeRecord
 
+	// tags stores tags and labels by subject and version (0 = the subject itself)
+	tags map[string]map[int]*storage.TagsRecord
+
+	// subjectMeta stores subject meta by subject
+	subjectMeta map[string]*storage.SubjectMetaRecord
+
+	// idAliases maps a source registry and its schema ID to a local schema ID
+	idAliases map[idAliasKey]*storage.SchemaIDAliasRecord
+
+	// frozen stores frozen versions by subject and version
+	frozen map[string]map[int]*storage.FrozenVersionRecord
+
+	// readers stores reader assertions by subject and consumer
+	readers map[string]map[string]*storage.ReaderAssertionRecord
+
 	// globalConfig is the context-level compatibility configuration (applies to all subjects in context)
 	globalConfig *storage.ConfigRecord
 
@@ -57,6 +76,9 @@ type contextStore struct {
 
 	// nextID is the next schema ID to assign within this context
 	nextID int64
+
+	// meta holds the administrative metadata and limits for this context
+	meta storage.ContextRecord
 }
 
 // newContextStore creates a new initialized context store.
@@ -69,9 +91,15 @@ func newContextStore() *contextStore {
 		idToSubjectVersions: make(map[int64][]storage.SubjectVersion),
 		configs:             make(map[string]*storage.ConfigRecord),
 		modes:               make(map[string]*storage.ModeRecord),
+		tags:                make(map[string]map[int]*storage.TagsRecord),
+		subjectMeta:         make(map[string]*storage.SubjectMetaRecord),
+		idAliases:           make(map[idAliasKey]*storage.SchemaIDAliasRecord),
+		frozen:              make(map[string]map[int]*storage.FrozenVersionRecord),
+		readers:             make(map[string]map[string]*storage.ReaderAssertionRecord),
 		globalConfig:        nil,
 		globalMode:          nil,
 		nextID:              1,
+		meta:                storage.ContextRecord{CreatedAt: time.Now()},
 	}
 }
 
@@ -101,6 +129,42 @@ type Store struct {
 	// nextAPIKeyID is the next API key ID to assign (global)
 	nextAPIKeyID int64
 
+	// grants stores role grant records by ID (global)
+	grants map[int64]*storage.GrantRecord
+
+	// nextGrantID is the next grant ID to assign (global)
+	nextGrantID int64
+
+	// shareTokens stores share token records by ID (global)
+	shareTokens map[int64]*storage.ShareTokenRecord
+
+	// nextShareTokenID is the next share token ID to assign (global)
+	nextShareTokenID int64
+
+	// sessions stores login session records by ID (global)
+	sessions map[string]*storage.SessionRecord
+
+	// tenants stores tenant records by name (global, not per-context)
+	tenants map[string]*storage.TenantRecord
+
+	// jobs stores async job records by ID (global, not per-context)
+	jobs map[string]*storage.JobRecord
+
+	// importSessions stores import session records by ID (global, not per-context)
+	importSessions map[string]*storage.ImportSessionRecord
+
+	// maintenance stores maintenance window records by ID (global, not per-context)
+	maintenance map[string]*storage.MaintenanceWindowRecord
+
+	// pendingSchemas stores pending schema records by ID (global, not per-context)
+	pendingSchemas map[string]*storage.PendingSchemaRecord
+
+	// Schema usage buckets, keyed by context, ID, subject, version and day
+	schemaUsage map[schemaUsageKey]int64
+
+	// API key usage buckets, keyed by API key ID and day
+	apiKeyUsage map[apiKeyUsageKey]int64
+
 	// exporters stores exporter records by name (global, not per-context)
 	exporters map[string]*storage.ExporterRecord
 
@@ -112,10 +176,25 @@ type Store struct {
 
 	// deks stores DEK records by kekName → subject → version (global, not per-context)
 	deks map[string]map[string]map[int]*storage.DEKRecord
+
+	// globalIDs makes every context allocate schema IDs from the default
+	// context's sequence
+	globalIDs bool
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithGlobalIDs makes every context allocate schema IDs from the sequence of
+// the default context, so that a schema ID is unique across all contexts.
+func WithGlobalIDs() Option {
+	return func(s *Store) {
+		s.globalIDs = true
+	}
 }
 
 // NewStore creates a new in-memory store with the default context initialized.
-func NewStore() *Store {
+func NewStore(opts ...Option) *Store {
 	s := &Store{
 		contexts:         make(map[string]*contextStore),
 		users:            make(map[int64]*storage.UserRecord),
@@ -124,6 +203,18 @@ func NewStore() *Store {
 		apiKeysByHash:    make(map[string]int64),
 		nextUserID:       1,
 		nextAPIKeyID:     1,
+		grants:           make(map[int64]*storage.GrantRecord),
+		nextGrantID:      1,
+		shareTokens:      make(map[int64]*storage.ShareTokenRecord),
+		nextShareTokenID: 1,
+		sessions:         make(map[string]*storage.SessionRecord),
+		tenants:          make(map[string]*storage.TenantRecord),
+		jobs:             make(map[string]*storage.JobRecord),
+		importSessions:   make(map[string]*storage.ImportSessionRecord),
+		maintenance:      make(map[string]*storage.MaintenanceWindowRecord),
+		pendingSchemas:   make(map[string]*storage.PendingSchemaRecord),
+		schemaUsage:      make(map[schemaUsageKey]int64),
+		apiKeyUsage:      make(map[apiKeyUsageKey]int64),
 		exporters:        make(map[string]*storage.ExporterRecord),
 		exporterStatuses: make(map[string]*storage.ExporterStatusRecord),
 		keks:             make(map[string]*storage.KEKRecord),
@@ -131,9 +222,21 @@ func NewStore() *Store {
 	}
 	// Default context is always present
 	s.contexts[DefaultContext] = newContextStore()
+	for _, opt := range opts {
+		opt(s)
+	}
 	return s
 }
 
+// idSequence returns the context store whose nextID allocates the schema IDs
+// of a context. Must be called with s.mu held (write lock).
+func (s *Store) idSequence(registryCtx string) *contextStore {
+	if s.globalIDs {
+		return s.contexts[DefaultContext]
+	}
+	return s.getOrCreateContext(registryCtx)
+}
+
 // getOrCreateContext returns the context store, creating it if it doesn't exist.
 // Must be called with s.mu held (write lock).
 func (s *Store) getOrCreateContext(registryCtx string) *contextStore {
@@ -210,9 +313,10 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 		// Reuse the existing schema ID (per-context deduplication)
 		schemaID = existingID
 	} else {
-		// New schema, assign new ID within this context
-		schemaID = cs.nextID
-		cs.nextID++
+		// New schema, assign a new ID from the context's sequence
+		seq := s.idSequence(registryCtx)
+		schemaID = seq.nextID
+		seq.nextID++
 		cs.fingerprints[record.Fingerprint] = schemaID
 
 		// Store the schema content (first time seeing this fingerprint in this context)
@@ -509,6 +613,49 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	}, nil
 }
 
+// GetLatestSchemaStamp returns the version, schema ID and creation time of a
+// subject's latest non-deleted version.
+func (s *Store) GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return "", storage.ErrSubjectNotFound
+	}
+
+	latestVersion := 0
+	var latest *subjectVersionInfo
+	for v, info := range cs.subjectVersions[subject] {
+		if !info.deleted && v > latestVersion {
+			latestVersion, latest = v, info
+		}
+	}
+	if latest == nil {
+		return "", storage.ErrSubjectNotFound
+	}
+	return fmt.Sprintf("%d:%d:%d", latestVersion, latest.schemaID, latest.createdAt.UnixNano()), nil
+}
+
+// RewriteSchemaText replaces the stored text of a schema within a context.
+func (s *Store) RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return storage.ErrSchemaNotFound
+	}
+	schema, ok := cs.schemas[id]
+	if !ok {
+		return storage.ErrSchemaNotFound
+	}
+	rewritten := *schema
+	rewritten.Schema = schemaText
+	cs.schemas[id] = &rewritten
+	return nil
+}
+
 // DeleteSchema soft-deletes or permanently deletes a schema version within a context.
 func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject string, version int, permanent bool) error {
 	s.mu.Lock()
@@ -558,6 +705,7 @@ func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject st
 		}
 	} else {
 		info.deleted = true
+		info.deletedAt = time.Now()
 	}
 
 	return nil
@@ -593,6 +741,91 @@ func (s *Store) ListSubjects(ctx context.Context, registryCtx string, includeDel
 	return subjects, nil
 }
 
+// ListSubjectsPage returns one page of subject names within a context.
+func (s *Store) ListSubjectsPage(ctx context.Context, registryCtx string, params *storage.ListSubjectsParams) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return []string{}, nil
+	}
+
+	subjects := []string{}
+	for subject, versionMap := range cs.subjectVersions {
+		if !strings.HasPrefix(subject, params.Prefix) || len(versionMap) == 0 {
+			continue
+		}
+		active := false
+		for _, info := range versionMap {
+			if !info.deleted {
+				active = true
+				break
+			}
+		}
+		if (active && !params.DeletedOnly) || (!active && (params.Deleted || params.DeletedOnly)) {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	sort.Strings(subjects)
+	return params.Page(subjects), nil
+}
+
+// ListVersions returns the version numbers of a subject within a context.
+func (s *Store) ListVersions(ctx context.Context, registryCtx string, subject string, params *storage.ListVersionsParams) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return nil, storage.ErrSubjectNotFound
+	}
+
+	var versions []int
+	for version, info := range cs.subjectVersions[subject] {
+		if !params.Deleted && info.deleted {
+			continue
+		}
+		if cs.schemas[info.schemaID] != nil {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, storage.ErrSubjectNotFound
+	}
+
+	sort.Ints(versions)
+	return params.Page(versions), nil
+}
+
+// ListSoftDeletedVersions returns soft-deleted versions deleted before the cutoff.
+func (s *Store) ListSoftDeletedVersions(ctx context.Context, registryCtx string, deletedBefore time.Time) ([]storage.SubjectVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return []storage.SubjectVersion{}, nil
+	}
+
+	result := []storage.SubjectVersion{}
+	for subject, versions := range cs.subjectVersions {
+		for version, info := range versions {
+			if info.deleted && info.deletedAt.Before(deletedBefore) {
+				result = append(result, storage.SubjectVersion{Subject: subject, Version: version})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Subject != result[j].Subject {
+			return result[i].Subject < result[j].Subject
+		}
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
 // DeleteSubject deletes all versions of a subject within a context.
 func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
 	s.mu.Lock()
@@ -659,6 +892,7 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 			}
 		} else {
 			info.deleted = true
+			info.deletedAt = time.Now()
 		}
 	}
 
@@ -670,6 +904,8 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 		delete(cs.nextSubjectVersion, subject)
 		delete(cs.configs, subject)
 		delete(cs.modes, subject)
+		delete(cs.tags, subject)
+		delete(cs.subjectMeta, subject)
 	}
 
 	return deletedVersions, nil
@@ -857,18 +1093,21 @@ func (s *Store) DeleteGlobalMode(ctx context.Context, registryCtx string) error
 	return nil
 }
 
-// NextID returns the next available schema ID for a context.
+// NextID returns the next available schema ID for a context, from the shared
+// sequence when the store uses global IDs.
 func (s *Store) NextID(ctx context.Context, registryCtx string) (int64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	cs := s.getOrCreateContext(registryCtx)
-	id := cs.nextID
-	cs.nextID++
+	s.getOrCreateContext(registryCtx)
+	seq := s.idSequence(registryCtx)
+	id := seq.nextID
+	seq.nextID++
 	return id, nil
 }
 
-// GetMaxSchemaID returns the highest schema ID currently assigned in a context.
+// GetMaxSchemaID returns the highest schema ID currently assigned in a context,
+// or in any context when the store uses global IDs.
 func (s *Store) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -877,10 +1116,29 @@ func (s *Store) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64,
 	if cs == nil {
 		return 0, nil
 	}
+	if s.globalIDs {
+		cs = s.contexts[DefaultContext]
+	}
 
 	return cs.nextID - 1, nil
 }
 
+// PeekNextID returns the next ID of the context's sequence, or of the shared
+// sequence when the store uses global IDs, without allocating it.
+func (s *Store) PeekNextID(ctx context.Context, registryCtx string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if s.globalIDs {
+		cs = s.contexts[DefaultContext]
+	}
+	if cs == nil {
+		return 1, nil
+	}
+	return cs.nextID, nil
+}
+
 // ImportSchema inserts a schema with a specified ID (for migration) within a context.
 // Returns ErrSchemaIDConflict if the ID already exists with different content.
 func (s *Store) ImportSchema(ctx context.Context, registryCtx string, record *storage.SchemaRecord) error {
@@ -956,8 +1214,8 @@ func (s *Store) SetNextID(ctx context.Context, registryCtx string, id int64) err
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	cs := s.getOrCreateContext(registryCtx)
-	cs.nextID = id
+	s.getOrCreateContext(registryCtx)
+	s.idSequence(registryCtx).nextID = id
 	return nil
 }
 
@@ -1074,6 +1332,12 @@ func (s *Store) GetVersionsBySchemaID(ctx context.Context, registryCtx string, i
 
 // ListSchemas returns schemas matching the given filters within a context.
 func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
+	if params.Aliases {
+		return params.WithAliases(s.subjectAliases(registryCtx), func(p *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
+			return s.ListSchemas(ctx, registryCtx, p)
+		})
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -1168,6 +1432,48 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 	return results, nil
 }
 
+// subjectAliases returns the target of every subject of a context whose
+// config sets an alias.
+func (s *Store) subjectAliases(registryCtx string) map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	aliases := make(map[string]string)
+	if cs := s.getContext(registryCtx); cs != nil {
+		for subject, config := range cs.configs {
+			if subject != "" && config.Alias != "" {
+				aliases[subject] = config.Alias
+			}
+		}
+	}
+	return aliases
+}
+
+// IterateSchemas calls fn with every schema version of a context, one
+// subject at a time, so the lock is not held while fn runs.
+func (s *Store) IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*storage.SchemaRecord) error) error {
+	subjects, err := s.ListSubjects(ctx, registryCtx, includeDeleted)
+	if err != nil {
+		return err
+	}
+	for _, subject := range subjects {
+		versions, err := s.GetSchemasBySubject(ctx, registryCtx, subject, includeDeleted)
+		if errors.Is(err, storage.ErrSubjectNotFound) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, record := range versions {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ListContexts returns all registry context names, sorted alphabetically.
 func (s *Store) ListContexts(ctx context.Context) ([]string, error) {
 	s.mu.RLock()
@@ -1181,307 +1487,1418 @@ func (s *Store) ListContexts(ctx context.Context) ([]string, error) {
 	return contexts, nil
 }
 
-// DeleteGlobalConfig resets the global config to default for a context.
-func (s *Store) DeleteGlobalConfig(ctx context.Context, registryCtx string) error {
+// CreateContext explicitly creates a registry context with its metadata.
+func (s *Store) CreateContext(ctx context.Context, record *storage.ContextRecord) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	cs := s.getContext(registryCtx)
-	if cs == nil {
-		return nil
+	if _, exists := s.contexts[record.Name]; exists {
+		return storage.ErrContextExists
 	}
 
-	cs.globalConfig = nil
+	cs := newContextStore()
+	record.CreatedAt = cs.meta.CreatedAt
+	record.UpdatedAt = cs.meta.CreatedAt
+	cs.meta = *record
+	s.contexts[record.Name] = cs
 	return nil
 }
 
-// Close closes the store.
-func (s *Store) Close() error {
-	return nil
-}
+// GetContext retrieves a registry context and its metadata.
+func (s *Store) GetContext(ctx context.Context, name string) (*storage.ContextRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-// IsHealthy returns true if the store is healthy.
-func (s *Store) IsHealthy(ctx context.Context) bool {
-	return true
+	cs := s.getContext(name)
+	if cs == nil {
+		return nil, storage.ErrContextNotFound
+	}
+
+	rec := cs.meta
+	rec.Name = name
+	return &rec, nil
 }
 
-// CreateUser creates a new user.
-func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
+// UpdateContext updates the metadata and limits of an existing context.
+func (s *Store) UpdateContext(ctx context.Context, record *storage.ContextRecord) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Check for existing username
-	if _, exists := s.usersByUsername[user.Username]; exists {
-		return storage.ErrUserExists
+	cs := s.getContext(record.Name)
+	if cs == nil {
+		return storage.ErrContextNotFound
 	}
 
-	// Assign ID if not set
-	if user.ID == 0 {
-		user.ID = atomic.AddInt64(&s.nextUserID, 1) - 1
-	}
+	// Preserve original creation time and owning tenant
+	record.CreatedAt = cs.meta.CreatedAt
+	record.Tenant = cs.meta.Tenant
+	record.UpdatedAt = time.Now()
+	cs.meta = *record
+	return nil
+}
 
-	now := time.Now()
-	user.CreatedAt = now
-	user.UpdatedAt = now
+// DeleteContext removes a context and all of its per-context data.
+func (s *Store) DeleteContext(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Store the user
-	s.users[user.ID] = user
-	s.usersByUsername[user.Username] = user.ID
+	if _, exists := s.contexts[name]; !exists {
+		return storage.ErrContextNotFound
+	}
 
+	delete(s.contexts, name)
 	return nil
 }
 
-// GetUserByID retrieves a user by ID.
-func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// CreateTenant creates a new tenant.
+func (s *Store) CreateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	user, exists := s.users[id]
-	if !exists {
-		return nil, storage.ErrUserNotFound
+	if _, exists := s.tenants[tenant.Name]; exists {
+		return storage.ErrTenantExists
 	}
-
-	return user, nil
+	now := time.Now()
+	tenant.CreatedAt = now
+	tenant.UpdatedAt = now
+	rec := *tenant
+	s.tenants[tenant.Name] = &rec
+	return nil
 }
 
-// GetUserByUsername retrieves a user by username.
-func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
+// GetTenant retrieves a tenant by name.
+func (s *Store) GetTenant(ctx context.Context, name string) (*storage.TenantRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	id, exists := s.usersByUsername[username]
+	tenant, exists := s.tenants[name]
 	if !exists {
-		return nil, storage.ErrUserNotFound
-	}
-
-	user := s.users[id]
-	if user == nil {
-		return nil, storage.ErrUserNotFound
+		return nil, storage.ErrTenantNotFound
 	}
-
-	return user, nil
+	rec := *tenant
+	return &rec, nil
 }
 
-// UpdateUser updates an existing user.
-func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
+// UpdateTenant updates the description of an existing tenant.
+func (s *Store) UpdateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	existing, exists := s.users[user.ID]
+	existing, exists := s.tenants[tenant.Name]
 	if !exists {
-		return storage.ErrUserNotFound
+		return storage.ErrTenantNotFound
 	}
-
-	// If username changed, update lookup map
-	if existing.Username != user.Username {
-		// Check if new username is taken
-		if _, taken := s.usersByUsername[user.Username]; taken {
-			return storage.ErrUserExists
-		}
-		delete(s.usersByUsername, existing.Username)
-		s.usersByUsername[user.Username] = user.ID
-	}
-
-	user.UpdatedAt = time.Now()
-	s.users[user.ID] = user
-
+	existing.Description = tenant.Description
+	existing.UpdatedAt = time.Now()
+	*tenant = *existing
 	return nil
 }
 
-// DeleteUser deletes a user by ID.
-func (s *Store) DeleteUser(ctx context.Context, id int64) error {
+// DeleteTenant deletes a tenant by name.
+func (s *Store) DeleteTenant(ctx context.Context, name string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	user, exists := s.users[id]
-	if !exists {
-		return storage.ErrUserNotFound
+	if _, exists := s.tenants[name]; !exists {
+		return storage.ErrTenantNotFound
 	}
-
-	delete(s.usersByUsername, user.Username)
-	delete(s.users, id)
-
+	delete(s.tenants, name)
 	return nil
 }
 
-// ListUsers returns all users.
-func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
+// ListTenants returns all tenants sorted by name.
+func (s *Store) ListTenants(ctx context.Context) ([]*storage.TenantRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	users := make([]*storage.UserRecord, 0, len(s.users))
-	for _, user := range s.users {
-		users = append(users, user)
+	tenants := make([]*storage.TenantRecord, 0, len(s.tenants))
+	for _, tenant := range s.tenants {
+		rec := *tenant
+		tenants = append(tenants, &rec)
 	}
-
-	// Sort by ID for consistent ordering
-	sort.Slice(users, func(i, j int) bool {
-		return users[i].ID < users[j].ID
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].Name < tenants[j].Name
 	})
+	return tenants, nil
+}
 
-	return users, nil
+// copyTags returns a deep copy of a tags record.
+func copyTags(rec *storage.TagsRecord) *storage.TagsRecord {
+	cp := *rec
+	cp.Tags = append([]string{}, rec.Tags...)
+	cp.Labels = copyStringMap(rec.Labels)
+	return &cp
 }
 
-// CreateAPIKey creates a new API key.
-func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+// SetTags replaces the tags and labels of a subject or subject version.
+func (s *Store) SetTags(ctx context.Context, registryCtx string, record *storage.TagsRecord) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Check for existing key hash
-	if _, exists := s.apiKeysByHash[key.KeyHash]; exists {
-		return storage.ErrAPIKeyExists
-	}
-
-	// Assign ID if not set
-	if key.ID == 0 {
-		key.ID = atomic.AddInt64(&s.nextAPIKeyID, 1) - 1
+	cs := s.getOrCreateContext(registryCtx)
+	if cs.tags[record.Subject] == nil {
+		cs.tags[record.Subject] = make(map[int]*storage.TagsRecord)
+	}
+	record.UpdatedAt = time.Now()
+	cs.tags[record.Subject][record.Version] = copyTags(record)
+	return nil
+}
+
+// GetTags retrieves the tags and labels of a subject or subject version.
+func (s *Store) GetTags(ctx context.Context, registryCtx string, subject string, version int) (*storage.TagsRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return nil, storage.ErrTagsNotFound
+	}
+	rec, exists := cs.tags[subject][version]
+	if !exists {
+		return nil, storage.ErrTagsNotFound
+	}
+	return copyTags(rec), nil
+}
+
+// DeleteTags removes the tags and labels of a subject or subject version.
+func (s *Store) DeleteTags(ctx context.Context, registryCtx string, subject string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return storage.ErrTagsNotFound
+	}
+	if _, exists := cs.tags[subject][version]; !exists {
+		return storage.ErrTagsNotFound
+	}
+	delete(cs.tags[subject], version)
+	if len(cs.tags[subject]) == 0 {
+		delete(cs.tags, subject)
+	}
+	return nil
+}
+
+// ListTags returns every tags record in a context, ordered by subject and version.
+func (s *Store) ListTags(ctx context.Context, registryCtx string) ([]*storage.TagsRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]*storage.TagsRecord, 0)
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return records, nil
+	}
+	for _, versions := range cs.tags {
+		for _, rec := range versions {
+			records = append(records, copyTags(rec))
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Subject != records[j].Subject {
+			return records[i].Subject < records[j].Subject
+		}
+		return records[i].Version < records[j].Version
+	})
+	return records, nil
+}
+
+// copySubjectMeta returns a deep copy of a subject meta record.
+func copySubjectMeta(rec *storage.SubjectMetaRecord) *storage.SubjectMetaRecord {
+	cp := *rec
+	cp.Links = append([]storage.SubjectLink(nil), rec.Links...)
+	cp.Reviewers = append([]string(nil), rec.Reviewers...)
+	return &cp
+}
+
+// SetSubjectMeta creates or replaces the meta of a subject.
+func (s *Store) SetSubjectMeta(ctx context.Context, registryCtx string, record *storage.SubjectMetaRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getOrCreateContext(registryCtx)
+	record.UpdatedAt = time.Now()
+	cs.subjectMeta[record.Subject] = copySubjectMeta(record)
+	return nil
+}
+
+// GetSubjectMeta retrieves the meta of a subject.
+func (s *Store) GetSubjectMeta(ctx context.Context, registryCtx string, subject string) (*storage.SubjectMetaRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return nil, storage.ErrSubjectMetaNotFound
+	}
+	rec, exists := cs.subjectMeta[subject]
+	if !exists {
+		return nil, storage.ErrSubjectMetaNotFound
+	}
+	return copySubjectMeta(rec), nil
+}
+
+// DeleteSubjectMeta removes the meta of a subject.
+func (s *Store) DeleteSubjectMeta(ctx context.Context, registryCtx string, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return storage.ErrSubjectMetaNotFound
+	}
+	if _, exists := cs.subjectMeta[subject]; !exists {
+		return storage.ErrSubjectMetaNotFound
+	}
+	delete(cs.subjectMeta, subject)
+	return nil
+}
+
+// idAliasKey identifies the alias of a source registry's schema ID.
+type idAliasKey struct {
+	source   string
+	sourceID int64
+}
+
+// SetSchemaIDAlias creates or replaces the alias of a source registry's schema ID.
+func (s *Store) SetSchemaIDAlias(ctx context.Context, registryCtx string, alias *storage.SchemaIDAliasRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getOrCreateContext(registryCtx)
+	alias.UpdatedAt = time.Now()
+	cp := *alias
+	cs.idAliases[idAliasKey{alias.Source, alias.SourceID}] = &cp
+	return nil
+}
+
+// GetSchemaIDAlias retrieves the alias of a source registry's schema ID.
+func (s *Store) GetSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) (*storage.SchemaIDAliasRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return nil, storage.ErrIDAliasNotFound
+	}
+	alias, exists := cs.idAliases[idAliasKey{source, sourceID}]
+	if !exists {
+		return nil, storage.ErrIDAliasNotFound
+	}
+	cp := *alias
+	return &cp, nil
+}
+
+// DeleteSchemaIDAlias removes the alias of a source registry's schema ID.
+func (s *Store) DeleteSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return storage.ErrIDAliasNotFound
+	}
+	key := idAliasKey{source, sourceID}
+	if _, exists := cs.idAliases[key]; !exists {
+		return storage.ErrIDAliasNotFound
+	}
+	delete(cs.idAliases, key)
+	return nil
+}
+
+// ListSchemaIDAliases returns the aliases in a context, ordered by source and source ID.
+func (s *Store) ListSchemaIDAliases(ctx context.Context, registryCtx string, source string) ([]*storage.SchemaIDAliasRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	aliases := make([]*storage.SchemaIDAliasRecord, 0)
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return aliases, nil
+	}
+	for key, alias := range cs.idAliases {
+		if source != "" && key.source != source {
+			continue
+		}
+		cp := *alias
+		aliases = append(aliases, &cp)
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		if aliases[i].Source != aliases[j].Source {
+			return aliases[i].Source < aliases[j].Source
+		}
+		return aliases[i].SourceID < aliases[j].SourceID
+	})
+	return aliases, nil
+}
+
+// FreezeVersion creates or replaces the freeze of a subject version.
+func (s *Store) FreezeVersion(ctx context.Context, registryCtx string, record *storage.FrozenVersionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getOrCreateContext(registryCtx)
+	record.FrozenAt = time.Now()
+	if cs.frozen[record.Subject] == nil {
+		cs.frozen[record.Subject] = make(map[int]*storage.FrozenVersionRecord)
+	}
+	cp := *record
+	cs.frozen[record.Subject][record.Version] = &cp
+	return nil
+}
+
+// GetFrozenVersion retrieves the freeze of a subject version.
+func (s *Store) GetFrozenVersion(ctx context.Context, registryCtx string, subject string, version int) (*storage.FrozenVersionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return nil, storage.ErrFrozenVersionNotFound
+	}
+	record, exists := cs.frozen[subject][version]
+	if !exists {
+		return nil, storage.ErrFrozenVersionNotFound
+	}
+	cp := *record
+	return &cp, nil
+}
+
+// UnfreezeVersion removes the freeze of a subject version.
+func (s *Store) UnfreezeVersion(ctx context.Context, registryCtx string, subject string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return storage.ErrFrozenVersionNotFound
+	}
+	if _, exists := cs.frozen[subject][version]; !exists {
+		return storage.ErrFrozenVersionNotFound
+	}
+	delete(cs.frozen[subject], version)
+	if len(cs.frozen[subject]) == 0 {
+		delete(cs.frozen, subject)
+	}
+	return nil
+}
+
+// ListFrozenVersions returns the frozen versions in a context, ordered by subject and version.
+func (s *Store) ListFrozenVersions(ctx context.Context, registryCtx string, subject string) ([]*storage.FrozenVersionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]*storage.FrozenVersionRecord, 0)
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return records, nil
+	}
+	for subj, versions := range cs.frozen {
+		if subject != "" && subj != subject {
+			continue
+		}
+		for _, record := range versions {
+			cp := *record
+			records = append(records, &cp)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Subject != records[j].Subject {
+			return records[i].Subject < records[j].Subject
+		}
+		return records[i].Version < records[j].Version
+	})
+	return records, nil
+}
+
+// SetReaderAssertion creates or replaces a consumer's assertion for a subject.
+func (s *Store) SetReaderAssertion(ctx context.Context, registryCtx string, record *storage.ReaderAssertionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getOrCreateContext(registryCtx)
+	record.UpdatedAt = time.Now()
+	if cs.readers[record.Subject] == nil {
+		cs.readers[record.Subject] = make(map[string]*storage.ReaderAssertionRecord)
+	}
+	cp := *record
+	cs.readers[record.Subject][record.Consumer] = &cp
+	return nil
+}
+
+// DeleteReaderAssertion removes a consumer's assertion for a subject.
+func (s *Store) DeleteReaderAssertion(ctx context.Context, registryCtx string, subject string, consumer string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return storage.ErrReaderAssertionNotFound
+	}
+	if _, exists := cs.readers[subject][consumer]; !exists {
+		return storage.ErrReaderAssertionNotFound
+	}
+	delete(cs.readers[subject], consumer)
+	if len(cs.readers[subject]) == 0 {
+		delete(cs.readers, subject)
+	}
+	return nil
+}
+
+// ListReaderAssertions returns the reader assertions in a context, ordered by subject and consumer.
+func (s *Store) ListReaderAssertions(ctx context.Context, registryCtx string, subject string) ([]*storage.ReaderAssertionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]*storage.ReaderAssertionRecord, 0)
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return records, nil
+	}
+	for subj, consumers := range cs.readers {
+		if subject != "" && subj != subject {
+			continue
+		}
+		for _, record := range consumers {
+			cp := *record
+			records = append(records, &cp)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Subject != records[j].Subject {
+			return records[i].Subject < records[j].Subject
+		}
+		return records[i].Consumer < records[j].Consumer
+	})
+	return records, nil
+}
+
+// DeleteGlobalConfig resets the global config to default for a context.
+func (s *Store) DeleteGlobalConfig(ctx context.Context, registryCtx string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := s.getContext(registryCtx)
+	if cs == nil {
+		return nil
+	}
+
+	cs.globalConfig = nil
+	return nil
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	return nil
+}
+
+// IsHealthy returns true if the store is healthy.
+func (s *Store) IsHealthy(ctx context.Context) bool {
+	return true
+}
+
+// CreateUser creates a new user.
+func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check for existing username
+	if _, exists := s.usersByUsername[user.Username]; exists {
+		return storage.ErrUserExists
+	}
+
+	// Assign ID if not set
+	if user.ID == 0 {
+		user.ID = atomic.AddInt64(&s.nextUserID, 1) - 1
+	}
+
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	// Store the user
+	s.users[user.ID] = user
+	s.usersByUsername[user.Username] = user.ID
+
+	return nil
+}
+
+// GetUserByID retrieves a user by ID.
+func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, exists := s.usersByUsername[username]
+	if !exists {
+		return nil, storage.ErrUserNotFound
+	}
+
+	user := s.users[id]
+	if user == nil {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// UpdateUser updates an existing user.
+func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.users[user.ID]
+	if !exists {
+		return storage.ErrUserNotFound
+	}
+
+	// If username changed, update lookup map
+	if existing.Username != user.Username {
+		// Check if new username is taken
+		if _, taken := s.usersByUsername[user.Username]; taken {
+			return storage.ErrUserExists
+		}
+		delete(s.usersByUsername, existing.Username)
+		s.usersByUsername[user.Username] = user.ID
+	}
+
+	user.Tenant = existing.Tenant
+	user.UpdatedAt = time.Now()
+	s.users[user.ID] = user
+
+	return nil
+}
+
+// DeleteUser deletes a user by ID.
+func (s *Store) DeleteUser(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return storage.ErrUserNotFound
+	}
+
+	delete(s.usersByUsername, user.Username)
+	delete(s.users, id)
+
+	return nil
+}
+
+// ListUsers returns all users.
+func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*storage.UserRecord, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+
+	// Sort by ID for consistent ordering
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	return users, nil
+}
+
+// CreateAPIKey creates a new API key.
+func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check for existing key hash
+	if _, exists := s.apiKeysByHash[key.KeyHash]; exists {
+		return storage.ErrAPIKeyExists
+	}
+
+	// Assign ID if not set
+	if key.ID == 0 {
+		key.ID = atomic.AddInt64(&s.nextAPIKeyID, 1) - 1
 	}
 
 	key.CreatedAt = time.Now()
 
-	// Store the key
-	s.apiKeys[key.ID] = key
-	s.apiKeysByHash[key.KeyHash] = key.ID
+	// Store the key
+	s.apiKeys[key.ID] = key
+	s.apiKeysByHash[key.KeyHash] = key.ID
+
+	return nil
+}
+
+// GetAPIKeyByID retrieves an API key by ID.
+func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, exists := s.apiKeys[id]
+	if !exists {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByHash retrieves an API key by key hash.
+func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, exists := s.apiKeysByHash[keyHash]
+	if !exists {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+
+	key := s.apiKeys[id]
+	if key == nil {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
+
+// UpdateAPIKey updates an existing API key.
+func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.apiKeys[key.ID]
+	if !exists {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	// If key hash changed, update lookup map
+	if existing.KeyHash != key.KeyHash {
+		// Check if new hash is taken
+		if _, taken := s.apiKeysByHash[key.KeyHash]; taken {
+			return storage.ErrAPIKeyExists
+		}
+		delete(s.apiKeysByHash, existing.KeyHash)
+		s.apiKeysByHash[key.KeyHash] = key.ID
+	}
+
+	s.apiKeys[key.ID] = key
+
+	return nil
+}
+
+// DeleteAPIKey deletes an API key by ID.
+func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, exists := s.apiKeys[id]
+	if !exists {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	delete(s.apiKeysByHash, key.KeyHash)
+	delete(s.apiKeys, id)
+
+	return nil
+}
+
+// ListAPIKeys returns all API keys.
+func (s *Store) ListAPIKeys(ctx context.Context) ([]*storage.APIKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*storage.APIKeyRecord, 0, len(s.apiKeys))
+	for _, key := range s.apiKeys {
+		keys = append(keys, key)
+	}
+
+	// Sort by ID for consistent ordering
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys, nil
+}
+
+// ListAPIKeysByUserID returns all API keys for a user.
+func (s *Store) ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*storage.APIKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []*storage.APIKeyRecord
+	for _, key := range s.apiKeys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+
+	// Sort by ID for consistent ordering
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys, nil
+}
+
+// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
+func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.apiKeys {
+		if key.UserID == userID && key.Name == name {
+			return key, nil
+		}
+	}
+
+	return nil, storage.ErrAPIKeyNotFound
+}
+
+// UpdateAPIKeyLastUsed updates the last_used timestamp for an API key.
+func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, exists := s.apiKeys[id]
+	if !exists {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	now := time.Now()
+	key.LastUsed = &now
+
+	return nil
+}
+
+// apiKeyUsageKey identifies one API key usage bucket.
+type apiKeyUsageKey struct {
+	apiKeyID int64
+	day      time.Time
+}
+
+// AddAPIKeyUsage adds request counts to the API key usage buckets and moves
+// the keys' last-used times forward.
+func (s *Store) AddAPIKeyUsage(ctx context.Context, records []*storage.APIKeyUsageRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rec := range records {
+		key, exists := s.apiKeys[rec.APIKeyID]
+		if !exists {
+			continue
+		}
+		s.apiKeyUsage[apiKeyUsageKey{rec.APIKeyID, rec.Day.UTC().Truncate(24 * time.Hour)}] += rec.Requests
+		if !rec.LastUsed.IsZero() && (key.LastUsed == nil || rec.LastUsed.After(*key.LastUsed)) {
+			lastUsed := rec.LastUsed
+			key.LastUsed = &lastUsed
+		}
+	}
+
+	return nil
+}
+
+// ListAPIKeyUsage returns the API key usage buckets for days on or after
+// since.
+func (s *Store) ListAPIKeyUsage(ctx context.Context, since time.Time) ([]*storage.APIKeyUsageRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	since = since.UTC().Truncate(24 * time.Hour)
+	records := []*storage.APIKeyUsageRecord{}
+	for key, requests := range s.apiKeyUsage {
+		if key.day.Before(since) {
+			continue
+		}
+		records = append(records, &storage.APIKeyUsageRecord{APIKeyID: key.apiKeyID, Day: key.day, Requests: requests})
+	}
+
+	return records, nil
+}
+
+// CreateGrant creates a new role grant.
+func (s *Store) CreateGrant(ctx context.Context, grant *storage.GrantRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if grant.ID == 0 {
+		grant.ID = atomic.AddInt64(&s.nextGrantID, 1) - 1
+	}
+	grant.CreatedAt = time.Now()
+	s.grants[grant.ID] = grant
+
+	return nil
+}
+
+// GetGrantByID retrieves a role grant by ID.
+func (s *Store) GetGrantByID(ctx context.Context, id int64) (*storage.GrantRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	grant, exists := s.grants[id]
+	if !exists {
+		return nil, storage.ErrGrantNotFound
+	}
+
+	return grant, nil
+}
+
+// DeleteGrant deletes a role grant by ID.
+func (s *Store) DeleteGrant(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.grants[id]; !exists {
+		return storage.ErrGrantNotFound
+	}
+	delete(s.grants, id)
+
+	return nil
+}
+
+// ListGrants returns all role grants.
+func (s *Store) ListGrants(ctx context.Context) ([]*storage.GrantRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	grants := make([]*storage.GrantRecord, 0, len(s.grants))
+	for _, grant := range s.grants {
+		grants = append(grants, grant)
+	}
+
+	// Sort by ID for consistent ordering
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].ID < grants[j].ID
+	})
+
+	return grants, nil
+}
+
+// CreateJob creates a new async job.
+func (s *Store) CreateJob(ctx context.Context, job *storage.JobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.ID]; exists {
+		return storage.ErrJobExists
+	}
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	stored := *job
+	s.jobs[job.ID] = &stored
 
 	return nil
 }
 
-// GetAPIKeyByID retrieves an API key by ID.
-func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
+// GetJob retrieves an async job by ID.
+func (s *Store) GetJob(ctx context.Context, id string) (*storage.JobRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	key, exists := s.apiKeys[id]
+	job, exists := s.jobs[id]
 	if !exists {
-		return nil, storage.ErrAPIKeyNotFound
+		return nil, storage.ErrJobNotFound
 	}
+	out := *job
+	return &out, nil
+}
 
-	return key, nil
+// UpdateJob updates an async job's state, progress and result.
+func (s *Store) UpdateJob(ctx context.Context, job *storage.JobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.jobs[job.ID]
+	if !exists {
+		return storage.ErrJobNotFound
+	}
+	job.UpdatedAt = time.Now()
+	existing.State = job.State
+	existing.Result = job.Result
+	existing.Error = job.Error
+	existing.Message = job.Message
+	existing.Done = job.Done
+	existing.Total = job.Total
+	existing.UpdatedAt = job.UpdatedAt
+	existing.StartedAt = job.StartedAt
+	existing.FinishedAt = job.FinishedAt
+
+	return nil
 }
 
-// GetAPIKeyByHash retrieves an API key by key hash.
-func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
+// RequestJobCancel marks an async job for cancellation.
+func (s *Store) RequestJobCancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return storage.ErrJobNotFound
+	}
+	job.CancelRequested = true
+
+	return nil
+}
+
+// ListJobs returns all async jobs, newest first.
+func (s *Store) ListJobs(ctx context.Context) ([]*storage.JobRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	id, exists := s.apiKeysByHash[keyHash]
-	if !exists {
-		return nil, storage.ErrAPIKeyNotFound
+	jobs := make([]*storage.JobRecord, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		out := *job
+		jobs = append(jobs, &out)
 	}
 
-	key := s.apiKeys[id]
-	if key == nil {
-		return nil, storage.ErrAPIKeyNotFound
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID > jobs[j].ID
+	})
+
+	return jobs, nil
+}
+
+// DeleteJob deletes an async job.
+func (s *Store) DeleteJob(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[id]; !exists {
+		return storage.ErrJobNotFound
 	}
+	delete(s.jobs, id)
 
-	return key, nil
+	return nil
 }
 
-// UpdateAPIKey updates an existing API key.
-func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+// copyImportSession returns a deep copy of an import session record.
+func copyImportSession(session *storage.ImportSessionRecord) *storage.ImportSessionRecord {
+	cp := *session
+	cp.Subjects = append([]string{}, session.Subjects...)
+	cp.Modes = make(map[string]string, len(session.Modes))
+	for k, v := range session.Modes {
+		cp.Modes[k] = v
+	}
+	return &cp
+}
+
+// CreateImportSession creates a new import session.
+func (s *Store) CreateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	existing, exists := s.apiKeys[key.ID]
+	if _, exists := s.importSessions[session.ID]; exists {
+		return storage.ErrImportSessionExists
+	}
+	now := time.Now()
+	session.CreatedAt = now
+	session.UpdatedAt = now
+	s.importSessions[session.ID] = copyImportSession(session)
+
+	return nil
+}
+
+// GetImportSession retrieves an import session by ID.
+func (s *Store) GetImportSession(ctx context.Context, id string) (*storage.ImportSessionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, exists := s.importSessions[id]
 	if !exists {
-		return storage.ErrAPIKeyNotFound
+		return nil, storage.ErrImportSessionNotFound
+	}
+	return copyImportSession(session), nil
+}
+
+// UpdateImportSession updates an import session's state and staged schemas.
+func (s *Store) UpdateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.importSessions[session.ID]
+	if !exists {
+		return storage.ErrImportSessionNotFound
 	}
+	session.UpdatedAt = time.Now()
+	existing.State = session.State
+	existing.Schemas = session.Schemas
+	existing.Staged = session.Staged
+	existing.UpdatedAt = session.UpdatedAt
 
-	// If key hash changed, update lookup map
-	if existing.KeyHash != key.KeyHash {
-		// Check if new hash is taken
-		if _, taken := s.apiKeysByHash[key.KeyHash]; taken {
-			return storage.ErrAPIKeyExists
+	return nil
+}
+
+// ListImportSessions returns all import sessions, newest first.
+func (s *Store) ListImportSessions(ctx context.Context) ([]*storage.ImportSessionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions := make([]*storage.ImportSessionRecord, 0, len(s.importSessions))
+	for _, session := range s.importSessions {
+		sessions = append(sessions, copyImportSession(session))
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
 		}
-		delete(s.apiKeysByHash, existing.KeyHash)
-		s.apiKeysByHash[key.KeyHash] = key.ID
+		return sessions[i].ID > sessions[j].ID
+	})
+
+	return sessions, nil
+}
+
+// CreateMaintenanceWindow creates a new maintenance window.
+func (s *Store) CreateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.maintenance[window.ID]; exists {
+		return storage.ErrMaintenanceExists
 	}
+	now := time.Now()
+	window.CreatedAt = now
+	window.UpdatedAt = now
+	cp := *window
+	s.maintenance[window.ID] = &cp
 
-	s.apiKeys[key.ID] = key
+	return nil
+}
+
+// GetMaintenanceWindow retrieves a maintenance window by ID.
+func (s *Store) GetMaintenanceWindow(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	window, exists := s.maintenance[id]
+	if !exists {
+		return nil, storage.ErrMaintenanceNotFound
+	}
+	cp := *window
+	return &cp, nil
+}
+
+// UpdateMaintenanceWindow updates a maintenance window's end time and cancellation.
+func (s *Store) UpdateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.maintenance[window.ID]
+	if !exists {
+		return storage.ErrMaintenanceNotFound
+	}
+	window.UpdatedAt = time.Now()
+	existing.EndsAt = window.EndsAt
+	existing.Cancelled = window.Cancelled
+	existing.UpdatedAt = window.UpdatedAt
 
 	return nil
 }
 
-// DeleteAPIKey deletes an API key by ID.
-func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
+// ListMaintenanceWindows returns all maintenance windows ordered by start time.
+func (s *Store) ListMaintenanceWindows(ctx context.Context) ([]*storage.MaintenanceWindowRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	windows := make([]*storage.MaintenanceWindowRecord, 0, len(s.maintenance))
+	for _, window := range s.maintenance {
+		cp := *window
+		windows = append(windows, &cp)
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		if !windows[i].StartsAt.Equal(windows[j].StartsAt) {
+			return windows[i].StartsAt.Before(windows[j].StartsAt)
+		}
+		return windows[i].ID < windows[j].ID
+	})
+
+	return windows, nil
+}
+
+// CreatePendingSchema creates a new pending schema.
+func (s *Store) CreatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	key, exists := s.apiKeys[id]
+	if _, exists := s.pendingSchemas[pending.ID]; exists {
+		return storage.ErrPendingSchemaExists
+	}
+	now := time.Now()
+	pending.CreatedAt = now
+	pending.UpdatedAt = now
+	cp := *pending
+	cp.Reviewers = append([]string(nil), pending.Reviewers...)
+	s.pendingSchemas[pending.ID] = &cp
+
+	return nil
+}
+
+// GetPendingSchema retrieves a pending schema by ID.
+func (s *Store) GetPendingSchema(ctx context.Context, id string) (*storage.PendingSchemaRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pending, exists := s.pendingSchemas[id]
 	if !exists {
-		return storage.ErrAPIKeyNotFound
+		return nil, storage.ErrPendingSchemaNotFound
 	}
+	cp := *pending
+	return &cp, nil
+}
 
-	delete(s.apiKeysByHash, key.KeyHash)
-	delete(s.apiKeys, id)
+// UpdatePendingSchema updates a pending schema's state, review and registration.
+func (s *Store) UpdatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.pendingSchemas[pending.ID]
+	if !exists {
+		return storage.ErrPendingSchemaNotFound
+	}
+	pending.UpdatedAt = time.Now()
+	existing.State = pending.State
+	existing.ReviewedBy = pending.ReviewedBy
+	existing.Reason = pending.Reason
+	existing.SchemaID = pending.SchemaID
+	existing.Version = pending.Version
+	existing.UpdatedAt = pending.UpdatedAt
 
 	return nil
 }
 
-// ListAPIKeys returns all API keys.
-func (s *Store) ListAPIKeys(ctx context.Context) ([]*storage.APIKeyRecord, error) {
+// ListPendingSchemas returns all pending schemas, newest first.
+func (s *Store) ListPendingSchemas(ctx context.Context) ([]*storage.PendingSchemaRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	keys := make([]*storage.APIKeyRecord, 0, len(s.apiKeys))
-	for _, key := range s.apiKeys {
-		keys = append(keys, key)
+	out := make([]*storage.PendingSchemaRecord, 0, len(s.pendingSchemas))
+	for _, pending := range s.pendingSchemas {
+		cp := *pending
+		out = append(out, &cp)
 	}
 
-	// Sort by ID for consistent ordering
-	sort.Slice(keys, func(i, j int) bool {
-		return keys[i].ID < keys[j].ID
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID > out[j].ID
 	})
 
-	return keys, nil
+	return out, nil
 }
 
-// ListAPIKeysByUserID returns all API keys for a user.
-func (s *Store) ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*storage.APIKeyRecord, error) {
+// schemaUsageKey identifies one schema usage bucket.
+type schemaUsageKey struct {
+	context  string
+	schemaID int64
+	subject  string
+	version  int
+	day      time.Time
+}
+
+// AddSchemaUsage adds fetch counts to the schema usage buckets.
+func (s *Store) AddSchemaUsage(ctx context.Context, records []*storage.SchemaUsageRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rec := range records {
+		key := schemaUsageKey{rec.Context, rec.SchemaID, rec.Subject, rec.Version, rec.Day.UTC().Truncate(24 * time.Hour)}
+		s.schemaUsage[key] += rec.Count
+	}
+
+	return nil
+}
+
+// ListSchemaUsage returns the schema usage buckets for days on or after since.
+func (s *Store) ListSchemaUsage(ctx context.Context, since time.Time) ([]*storage.SchemaUsageRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var keys []*storage.APIKeyRecord
-	for _, key := range s.apiKeys {
-		if key.UserID == userID {
-			keys = append(keys, key)
+	since = since.UTC().Truncate(24 * time.Hour)
+	records := []*storage.SchemaUsageRecord{}
+	for key, count := range s.schemaUsage {
+		if key.day.Before(since) {
+			continue
 		}
+		records = append(records, &storage.SchemaUsageRecord{
+			Context:  key.context,
+			SchemaID: key.schemaID,
+			Subject:  key.subject,
+			Version:  key.version,
+			Day:      key.day,
+			Count:    count,
+		})
 	}
 
-	// Sort by ID for consistent ordering
-	sort.Slice(keys, func(i, j int) bool {
-		return keys[i].ID < keys[j].ID
-	})
+	return records, nil
+}
 
-	return keys, nil
+// CreateShareToken creates a new share token.
+func (s *Store) CreateShareToken(ctx context.Context, token *storage.ShareTokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token.ID == 0 {
+		token.ID = atomic.AddInt64(&s.nextShareTokenID, 1) - 1
+	}
+	s.shareTokens[token.ID] = token
+
+	return nil
 }
 
-// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
-func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
+// GetShareTokenByID retrieves a share token by ID.
+func (s *Store) GetShareTokenByID(ctx context.Context, id int64) (*storage.ShareTokenRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	for _, key := range s.apiKeys {
-		if key.UserID == userID && key.Name == name {
-			return key, nil
+	token, exists := s.shareTokens[id]
+	if !exists {
+		return nil, storage.ErrShareTokenNotFound
+	}
+
+	return token, nil
+}
+
+// GetShareTokenByHash retrieves a share token by the hash of its value.
+func (s *Store) GetShareTokenByHash(ctx context.Context, tokenHash string) (*storage.ShareTokenRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, token := range s.shareTokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
 		}
 	}
 
-	return nil, storage.ErrAPIKeyNotFound
+	return nil, storage.ErrShareTokenNotFound
 }
 
-// UpdateAPIKeyLastUsed updates the last_used timestamp for an API key.
-func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
+// DeleteShareToken deletes a share token by ID.
+func (s *Store) DeleteShareToken(ctx context.Context, id int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	key, exists := s.apiKeys[id]
+	if _, exists := s.shareTokens[id]; !exists {
+		return storage.ErrShareTokenNotFound
+	}
+	delete(s.shareTokens, id)
+
+	return nil
+}
+
+// ListShareTokens returns all share tokens.
+func (s *Store) ListShareTokens(ctx context.Context) ([]*storage.ShareTokenRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := make([]*storage.ShareTokenRecord, 0, len(s.shareTokens))
+	for _, token := range s.shareTokens {
+		tokens = append(tokens, token)
+	}
+
+	// Sort by ID for consistent ordering
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].ID < tokens[j].ID
+	})
+
+	return tokens, nil
+}
+
+// CreateSession creates a new login session.
+func (s *Store) CreateSession(ctx context.Context, session *storage.SessionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *session
+	s.sessions[session.ID] = &stored
+
+	return nil
+}
+
+// GetSession retrieves a login session by ID.
+func (s *Store) GetSession(ctx context.Context, id string) (*storage.SessionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, exists := s.sessions[id]
 	if !exists {
-		return storage.ErrAPIKeyNotFound
+		return nil, storage.ErrSessionNotFound
 	}
 
-	now := time.Now()
-	key.LastUsed = &now
+	result := *session
+	return &result, nil
+}
+
+// RefreshSession stores a session's new refresh token hash if its current
+// one is oldRefreshHash.
+func (s *Store) RefreshSession(ctx context.Context, session *storage.SessionRecord, oldRefreshHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.sessions[session.ID]
+	if !exists || existing.RefreshHash != oldRefreshHash {
+		return storage.ErrSessionNotFound
+	}
+	existing.RefreshHash = session.RefreshHash
+	existing.Role = session.Role
+	existing.Tenant = session.Tenant
+	existing.RefreshedAt = session.RefreshedAt
+	existing.ExpiresAt = session.ExpiresAt
+
+	return nil
+}
+
+// DeleteSession deletes a login session by ID.
+func (s *Store) DeleteSession(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.sessions[id]; !exists {
+		return storage.ErrSessionNotFound
+	}
+	delete(s.sessions, id)
+
+	return nil
+}
+
+// DeleteUserSessions deletes every login session of a user.
+func (s *Store) DeleteUserSessions(ctx context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, session := range s.sessions {
+		if session.UserID == userID {
+			delete(s.sessions, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessions deletes the login sessions that expired before the
+// given time.
+func (s *Store) DeleteExpiredSessions(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, session := range s.sessions {
+		if session.ExpiresAt.Before(before) {
+			delete(s.sessions, id)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// driverConfig returns the driver configuration for the store: the DSN
+// settings, plus the custom TLS configuration when CA or client certificate
+// files or a server name are set, and the password provider.
+func (c Config) driverConfig() (*mysql.Config, error) {
+	cfg, err := mysql.ParseDSN(c.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("invalid MySQL connection settings: %w", err)
+	}
+
+	tlsCfg, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		cfg.TLS = tlsCfg
+		cfg.AllowFallbackToPlaintext = c.TLS == "preferred"
+	}
+
+	if c.PasswordProvider != nil {
+		if c.TLS == "" || c.TLS == "false" || c.TLS == "preferred" {
+			return nil, fmt.Errorf("MySQL password provider requires TLS (tls: true, skip-verify or a custom config)")
+		}
+		// Tokens are sent with the cleartext authentication plugin, which
+		// the driver refuses unless allowed.
+		cfg.AllowCleartextPasswords = true
+		provider := c.PasswordProvider
+		if err := cfg.Apply(mysql.BeforeConnect(func(ctx context.Context, cfg *mysql.Config) error {
+			password, err := provider(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get MySQL password: %w", err)
+			}
+			cfg.Passwd = password
+			return nil
+		})); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// tlsConfig builds the TLS configuration from the CA and client certificate
+// files and the server name. It returns nil when none of them is set, leaving
+// the TLS mode to the driver.
+func (c Config) tlsConfig() (*tls.Config, error) {
+	if c.TLSCAFile == "" && c.TLSCertFile == "" && c.TLSKeyFile == "" && c.TLSServerName == "" {
+		return nil, nil
+	}
+	switch c.TLS {
+	case "true", "skip-verify", "preferred":
+	default:
+		return nil, fmt.Errorf("MySQL TLS files and server name require tls to be true, skip-verify or preferred, got %q", c.TLS)
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return nil, fmt.Errorf("MySQL tls_cert_file and tls_key_file must be set together")
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.TLSServerName,
+		InsecureSkipVerify: c.TLS != "true", // #nosec G402 -- skip-verify and preferred are explicit opt-outs
+	}
+	if tlsCfg.ServerName == "" {
+		tlsCfg.ServerName = c.Host
+	}
+	if c.TLSCAFile != "" {
+		pem, err := os.ReadFile(c.TLSCAFile) // #nosec G304 -- TLSCAFile is from trusted configuration
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MySQL TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in MySQL TLS CA file %s", c.TLSCAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if c.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MySQL TLS client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
@@ -0,0 +1,144 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage/delta"
+)
+
+// maxDeltaChain bounds the number of rows read to reconstruct one schema, so a
+// corrupt chain fails instead of looping. Chains written by this store are
+// never longer than the snapshot interval.
+const maxDeltaChain = 1000
+
+// rowQuerier is implemented by *sql.DB and *sql.Tx.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// expandSchemaText returns the full text of a schema row. Rows stored as a
+// delta (base is set) are reconstructed from their chain of base rows.
+// known caches full texts by row ID across calls and may be nil.
+func expandSchemaText(ctx context.Context, q rowQuerier, rowID int64, text string, base sql.NullInt64, known map[int64]string) (string, error) {
+	if !base.Valid {
+		return text, nil
+	}
+	full, _, err := resolveDeltaChain(ctx, q, text, base, known)
+	if err != nil {
+		return "", fmt.Errorf("failed to reconstruct schema %d: %w", rowID, err)
+	}
+	if known != nil {
+		known[rowID] = full
+	}
+	return full, nil
+}
+
+// resolveDeltaChain follows delta bases back to a full snapshot and applies
+// the deltas forward. It also returns the number of deltas applied.
+func resolveDeltaChain(ctx context.Context, q rowQuerier, text string, base sql.NullInt64, known map[int64]string) (string, int, error) {
+	var deltas []string
+	for base.Valid {
+		if len(deltas) >= maxDeltaChain {
+			return "", 0, fmt.Errorf("delta chain longer than %d rows", maxDeltaChain)
+		}
+		deltas = append(deltas, text)
+		id := base.Int64
+		if full, ok := known[id]; ok {
+			text, base = full, sql.NullInt64{}
+			break
+		}
+		if err := q.QueryRowContext(ctx,
+			"SELECT schema_text, delta_base_id FROM `schemas` WHERE id = ?", id).Scan(&text, &base); err != nil {
+			return "", 0, fmt.Errorf("failed to load delta base %d: %w", id, err)
+		}
+	}
+	for i := len(deltas) - 1; i >= 0; i-- {
+		var err error
+		if text, err = delta.Apply(text, deltas[i]); err != nil {
+			return "", 0, err
+		}
+	}
+	return text, len(deltas), nil
+}
+
+// encodeSchemaText returns what to store for a new version of a subject: a
+// delta against the subject's current highest version when delta encoding is
+// enabled and the delta is worthwhile, otherwise the full text. Every
+// DeltaSnapshotInterval-th version in a chain is stored in full.
+func (s *Store) encodeSchemaText(ctx context.Context, tx *sql.Tx, registryCtx, subject, text string) (string, sql.NullInt64, error) {
+	if !s.config.DeltaEncoding {
+		return text, sql.NullInt64{}, nil
+	}
+
+	var prevID int64
+	var prevText string
+	var prevBase sql.NullInt64
+	err := tx.QueryRowContext(ctx,
+		"SELECT id, schema_text, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND subject = ? ORDER BY version DESC LIMIT 1",
+		registryCtx, subject).Scan(&prevID, &prevText, &prevBase)
+	if err == sql.ErrNoRows {
+		return text, sql.NullInt64{}, nil
+	}
+	if err != nil {
+		return "", sql.NullInt64{}, fmt.Errorf("failed to load previous version: %w", err)
+	}
+
+	prevFull, depth, err := resolveDeltaChain(ctx, tx, prevText, prevBase, nil)
+	if err != nil {
+		return "", sql.NullInt64{}, fmt.Errorf("failed to reconstruct previous version: %w", err)
+	}
+	if depth+1 >= s.config.DeltaSnapshotInterval {
+		return text, sql.NullInt64{}, nil
+	}
+	d, ok := delta.Encode(prevFull, text)
+	if !ok {
+		return text, sql.NullInt64{}, nil
+	}
+	return d, sql.NullInt64{Int64: prevID, Valid: true}, nil
+}
+
+// detachDeltaDependents rewrites in full every row stored as a delta against a
+// row matched by cond, unless it is matched by cond itself. It must run in the
+// transaction that then deletes the rows matched by cond. cond uses ?
+// placeholders bound to args.
+func detachDeltaDependents(ctx context.Context, tx *sql.Tx, cond string, args ...any) error {
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id, schema_text, delta_base_id FROM `schemas` "+
+			"WHERE delta_base_id IN (SELECT id FROM `schemas` WHERE "+cond+") AND NOT ("+cond+")",
+		append(append([]any{}, args...), args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to find delta dependents: %w", err)
+	}
+	type dependent struct {
+		id   int64
+		text string
+		base sql.NullInt64
+	}
+	var dependents []dependent
+	for rows.Next() {
+		var d dependent
+		if err := rows.Scan(&d.id, &d.text, &d.base); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan delta dependent: %w", err)
+		}
+		dependents = append(dependents, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to find delta dependents: %w", err)
+	}
+
+	for _, d := range dependents {
+		full, err := expandSchemaText(ctx, tx, d.id, d.text, d.base, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE `schemas` SET schema_text = ?, delta_base_id = NULL WHERE id = ?", full, d.id); err != nil {
+			return fmt.Errorf("failed to detach delta dependent %d: %w", d.id, err)
+		}
+	}
+	return nil
+}
@@ -267,4 +267,253 @@ var migrations = []string{
 		"trace TEXT," +
 		"FOREIGN KEY (name) REFERENCES exporters(name) ON DELETE CASCADE" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+
+	// Migration 47: Context metadata and per-context limits.
+	"ALTER TABLE contexts " +
+		"ADD COLUMN description TEXT," +
+		"ADD COLUMN owner VARCHAR(255)," +
+		"ADD COLUMN max_subjects INT NOT NULL DEFAULT 0," +
+		"ADD COLUMN max_schema_size INT NOT NULL DEFAULT 0," +
+		"ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP",
+
+	// Migration 48: Record when a version was soft-deleted so the retention
+	// purge worker can expire it. Rows soft-deleted before this column existed
+	// are backfilled with the migration time. Times are stored as UTC to match
+	// the driver's default loc.
+	"ALTER TABLE `schemas` ADD COLUMN deleted_at TIMESTAMP NULL",
+	"UPDATE `schemas` SET deleted_at = UTC_TIMESTAMP() WHERE deleted = TRUE AND deleted_at IS NULL",
+
+	// Migration 49: Role grants binding a user or API key to a role within a
+	// context and/or subject prefix.
+	"CREATE TABLE IF NOT EXISTS role_grants (" +
+		"id BIGINT AUTO_INCREMENT PRIMARY KEY," +
+		"username VARCHAR(255) NULL," +
+		"api_key_id BIGINT NULL," +
+		"role VARCHAR(50) NOT NULL," +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT ''," +
+		"subject_prefix VARCHAR(255) NOT NULL DEFAULT ''," +
+		"created_by VARCHAR(255) NULL," +
+		"created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+
+	// Migration 50: Async jobs for long-running operations.
+	"CREATE TABLE IF NOT EXISTS jobs (" +
+		"id VARCHAR(64) PRIMARY KEY," +
+		"type VARCHAR(100) NOT NULL," +
+		"state VARCHAR(20) NOT NULL," +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT ''," +
+		"params MEDIUMTEXT NOT NULL," +
+		"result MEDIUMTEXT NOT NULL," +
+		"error TEXT NOT NULL," +
+		"message TEXT NOT NULL," +
+		"done BIGINT NOT NULL DEFAULT 0," +
+		"total BIGINT NOT NULL DEFAULT 0," +
+		"cancel_requested BOOLEAN NOT NULL DEFAULT FALSE," +
+		"created_by VARCHAR(255) NOT NULL DEFAULT ''," +
+		"created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"started_at TIMESTAMP(3) NULL," +
+		"finished_at TIMESTAMP(3) NULL" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+
+	// Migration 51: Schema usage analytics (fetch counts per day).
+	"CREATE TABLE IF NOT EXISTS schema_usage (" +
+		"usage_day DATE NOT NULL," +
+		"registry_ctx VARCHAR(255) NOT NULL," +
+		"schema_id BIGINT NOT NULL," +
+		"subject VARCHAR(255) NOT NULL DEFAULT ''," +
+		"version INT NOT NULL DEFAULT 0," +
+		"fetch_count BIGINT NOT NULL DEFAULT 0," +
+		"PRIMARY KEY (usage_day, registry_ctx, schema_id, subject, version)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 52: Read-only share tokens scoped to a context or subject.
+	"CREATE TABLE IF NOT EXISTS share_tokens (" +
+		"id BIGINT AUTO_INCREMENT PRIMARY KEY," +
+		"token_hash VARCHAR(64) NOT NULL," +
+		"token_prefix VARCHAR(16) NOT NULL," +
+		"name VARCHAR(255) NOT NULL," +
+		"registry_ctx VARCHAR(255) NOT NULL," +
+		"subject VARCHAR(255) NOT NULL DEFAULT ''," +
+		"created_by VARCHAR(255) NOT NULL DEFAULT ''," +
+		"created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"expires_at TIMESTAMP(3) NOT NULL," +
+		"UNIQUE KEY idx_share_tokens_hash (token_hash)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 53: Delta-encoded schema versions. A row with delta_base_id
+	// stores schema_text as a delta against that row.
+	"ALTER TABLE `schemas` ADD COLUMN delta_base_id BIGINT NULL",
+
+	// Migration 54: Tenants, which own contexts and users and hold the key
+	// their schemas are encrypted with.
+	"CREATE TABLE IF NOT EXISTS tenants (" +
+		"name VARCHAR(255) NOT NULL PRIMARY KEY," +
+		"description TEXT," +
+		"encrypted_key TEXT," +
+		"created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+	"ALTER TABLE contexts ADD COLUMN tenant VARCHAR(255) NOT NULL DEFAULT ''",
+	"ALTER TABLE users ADD COLUMN tenant VARCHAR(255) NOT NULL DEFAULT ''",
+
+	// Migration 55: Tags and labels on subjects (version 0) and versions.
+	"CREATE TABLE IF NOT EXISTS schema_tags (" +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT '.'," +
+		"subject VARCHAR(255) NOT NULL," +
+		"version INT NOT NULL DEFAULT 0," +
+		"tags JSON NOT NULL," +
+		"labels JSON NOT NULL," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"PRIMARY KEY (registry_ctx, subject, version)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 56: Import sessions staging schemas until they are committed.
+	"CREATE TABLE IF NOT EXISTS import_sessions (" +
+		"id VARCHAR(64) PRIMARY KEY," +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT '.'," +
+		"subjects JSON NOT NULL," +
+		"state VARCHAR(20) NOT NULL," +
+		"modes JSON NOT NULL," +
+		"`schemas` LONGTEXT NOT NULL," +
+		"staged INT NOT NULL DEFAULT 0," +
+		"created_by VARCHAR(255) NOT NULL DEFAULT ''," +
+		"created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 57: Maintenance windows putting the registry into READONLY_OVERRIDE.
+	"CREATE TABLE IF NOT EXISTS maintenance_windows (" +
+		"id VARCHAR(64) PRIMARY KEY," +
+		"starts_at TIMESTAMP(3) NOT NULL," +
+		"ends_at TIMESTAMP(3) NOT NULL," +
+		"reason TEXT NOT NULL," +
+		"cancelled BOOLEAN NOT NULL DEFAULT FALSE," +
+		"created_by VARCHAR(255) NOT NULL DEFAULT ''," +
+		"created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 58: Aliases mapping source registries' schema IDs to local IDs.
+	"CREATE TABLE IF NOT EXISTS schema_id_aliases (" +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT '.'," +
+		"source VARCHAR(255) NOT NULL," +
+		"source_id BIGINT NOT NULL," +
+		"schema_id BIGINT NOT NULL," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"PRIMARY KEY (registry_ctx, source, source_id)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 59: Frozen versions, protected from deletion.
+	"CREATE TABLE IF NOT EXISTS frozen_versions (" +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT '.'," +
+		"subject VARCHAR(255) NOT NULL," +
+		"version INT NOT NULL," +
+		"frozen_by VARCHAR(255) NOT NULL DEFAULT ''," +
+		"reason TEXT NOT NULL," +
+		"frozen_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"PRIMARY KEY (registry_ctx, subject, version)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 60: Password policy state of users. Existing passwords are
+	// dated from the user's last update, which the backfill must not bump.
+	"ALTER TABLE users ADD COLUMN password_changed_at TIMESTAMP(3) NULL",
+	"ALTER TABLE users ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE",
+	"ALTER TABLE users ADD COLUMN password_history TEXT",
+	"UPDATE users SET password_changed_at = updated_at, updated_at = updated_at WHERE password_changed_at IS NULL",
+
+	// Migration 61: Login sessions started with POST /auth/login.
+	"CREATE TABLE IF NOT EXISTS sessions (" +
+		"id VARCHAR(64) PRIMARY KEY," +
+		"refresh_hash VARCHAR(64) NOT NULL," +
+		"user_id BIGINT NOT NULL DEFAULT 0," +
+		"api_key_id BIGINT NOT NULL DEFAULT 0," +
+		"username VARCHAR(255) NOT NULL," +
+		"role VARCHAR(50) NOT NULL," +
+		"tenant VARCHAR(255) NOT NULL DEFAULT ''," +
+		"auth_method VARCHAR(50) NOT NULL," +
+		"created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"refreshed_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"expires_at TIMESTAMP(3) NOT NULL," +
+		"INDEX idx_sessions_user_id (user_id)," +
+		"INDEX idx_sessions_expires_at (expires_at)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 62: Registrations pending approval.
+	"CREATE TABLE IF NOT EXISTS pending_schemas (" +
+		"id VARCHAR(64) PRIMARY KEY," +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT '.'," +
+		"subject VARCHAR(255) NOT NULL," +
+		"state VARCHAR(20) NOT NULL," +
+		"request LONGTEXT NOT NULL," +
+		"submitted_by VARCHAR(255) NOT NULL DEFAULT ''," +
+		"reviewed_by VARCHAR(255) NOT NULL DEFAULT ''," +
+		"reason TEXT NOT NULL," +
+		"schema_id BIGINT NOT NULL DEFAULT 0," +
+		"version INT NOT NULL DEFAULT 0," +
+		"created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 63: Resources an API key is restricted to.
+	"ALTER TABLE api_keys ADD COLUMN scopes TEXT",
+
+	// Migration 64: Per-subject rows that registrations lock so that they
+	// allocate versions one at a time.
+	"CREATE TABLE IF NOT EXISTS subject_versions (" +
+		"registry_ctx VARCHAR(255) NOT NULL," +
+		"subject VARCHAR(255) NOT NULL," +
+		"PRIMARY KEY (registry_ctx, subject)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 65: Reader schemas that consumers declare for the subjects
+	// they read.
+	"CREATE TABLE IF NOT EXISTS reader_assertions (" +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT '.'," +
+		"subject VARCHAR(255) NOT NULL," +
+		"consumer VARCHAR(255) NOT NULL," +
+		"reader_subject VARCHAR(255) NOT NULL," +
+		"reader_version INT NOT NULL," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"PRIMARY KEY (registry_ctx, subject, consumer)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 66: Owner, description, contact and documentation links of
+	// subjects.
+	"CREATE TABLE IF NOT EXISTS subject_meta (" +
+		"registry_ctx VARCHAR(255) NOT NULL DEFAULT '.'," +
+		"subject VARCHAR(255) NOT NULL," +
+		"owner VARCHAR(255) NOT NULL DEFAULT ''," +
+		"description TEXT NOT NULL," +
+		"contact VARCHAR(255) NOT NULL DEFAULT ''," +
+		"links JSON NOT NULL," +
+		"updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)," +
+		"PRIMARY KEY (registry_ctx, subject)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 67: Reviewers declared by subjects and assigned to pending
+	// schemas.
+	"ALTER TABLE subject_meta ADD COLUMN reviewers TEXT",
+	"ALTER TABLE pending_schemas ADD COLUMN reviewers TEXT",
+
+	// Migration 68: API key usage analytics (request counts per day).
+	"CREATE TABLE IF NOT EXISTS api_key_usage (" +
+		"usage_day DATE NOT NULL," +
+		"api_key_id BIGINT NOT NULL," +
+		"request_count BIGINT NOT NULL DEFAULT 0," +
+		"PRIMARY KEY (usage_day, api_key_id)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+
+	// Migration 69: Make subjects and fingerprints case-sensitive in the
+	// tables created with utf8mb4_unicode_ci, like registry_ctx in migration
+	// 42. Otherwise subjects differing only in case, such as Orders-value and
+	// orders-value, share a row.
+	"ALTER TABLE `schemas` MODIFY COLUMN subject VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL",
+	"ALTER TABLE `schemas` MODIFY COLUMN fingerprint VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL",
+	"ALTER TABLE schema_references MODIFY COLUMN ref_subject VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL",
+	"ALTER TABLE configs MODIFY COLUMN subject VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL",
+	"ALTER TABLE modes MODIFY COLUMN subject VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL",
+	"ALTER TABLE schema_fingerprints MODIFY COLUMN fingerprint VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL",
+	"ALTER TABLE deks MODIFY COLUMN subject VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL",
+	"ALTER TABLE role_grants MODIFY COLUMN subject_prefix VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT ''",
 }
@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
@@ -42,7 +44,11 @@ type Config struct {
 	Database           string        `json:"database" yaml:"database"`
 	Username           string        `json:"username" yaml:"username"`
 	Password           string        `json:"password" yaml:"password"`
-	TLS                string        `json:"tls" yaml:"tls"` // true, false, skip-verify, preferred, or custom config name
+	TLS                string        `json:"tls" yaml:"tls"`                         // true, false, skip-verify, preferred, or custom config name
+	TLSCAFile          string        `json:"tls_ca_file" yaml:"tls_ca_file"`         // CA bundle the server certificate is verified against (default: system roots)
+	TLSCertFile        string        `json:"tls_cert_file" yaml:"tls_cert_file"`     // Client certificate, for servers requiring X509 authentication
+	TLSKeyFile         string        `json:"tls_key_file" yaml:"tls_key_file"`       // Client certificate key
+	TLSServerName      string        `json:"tls_server_name" yaml:"tls_server_name"` // Name the server certificate must be valid for (default: Host)
 	MaxOpenConns       int           `json:"max_open_conns" yaml:"max_open_conns"`
 	MaxIdleConns       int           `json:"max_idle_conns" yaml:"max_idle_conns"`
 	ConnMaxLifetime    time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime"`
@@ -50,6 +56,23 @@ type Config struct {
 	ConnectTimeout     time.Duration `json:"connect_timeout" yaml:"connect_timeout"`           // Initial connection ping timeout (default: 5s)
 	HealthCheckTimeout time.Duration `json:"health_check_timeout" yaml:"health_check_timeout"` // Health check timeout (default: 2s)
 	SchemaMaxRetries   int           `json:"schema_max_retries" yaml:"schema_max_retries"`     // Max retries for schema creation (default: 15)
+
+	// DeltaEncoding stores each new schema version as a delta against the
+	// previous version of its subject when that saves at least half the space.
+	// Reads reconstruct the full text transparently. Existing rows are not
+	// rewritten when this is turned on or off.
+	DeltaEncoding         bool `json:"delta_encoding" yaml:"delta_encoding"`
+	DeltaSnapshotInterval int  `json:"delta_snapshot_interval" yaml:"delta_snapshot_interval"` // Store every Nth version of a chain in full (default: 10)
+
+	// GlobalIDs makes every context allocate schema IDs from the default
+	// context's sequence, so that a schema ID is unique across contexts.
+	GlobalIDs bool `json:"global_ids" yaml:"global_ids"`
+
+	// PasswordProvider, when set, is called for the password of each new
+	// connection instead of using Password, for credentials that expire
+	// such as AWS RDS IAM authentication tokens. The password is sent in
+	// cleartext, so TLS is required.
+	PasswordProvider func(ctx context.Context) (string, error) `json:"-" yaml:"-"`
 }
 
 // DefaultConfig returns a default configuration.
@@ -68,6 +91,8 @@ func DefaultConfig() Config {
 		ConnectTimeout:     5 * time.Second,
 		HealthCheckTimeout: 2 * time.Second,
 		SchemaMaxRetries:   15,
+
+		DeltaSnapshotInterval: 10,
 	}
 }
 
@@ -97,6 +122,7 @@ type preparedStatements struct {
 	getSchemaBySubjectVer  *sql.Stmt
 	getSchemaByFingerprint *sql.Stmt
 	getLatestSchema        *sql.Stmt
+	getLatestSchemaStamp   *sql.Stmt
 	softDeleteSchema       *sql.Stmt
 	hardDeleteSchema       *sql.Stmt
 	countSchemasBySubject  *sql.Stmt
@@ -153,11 +179,19 @@ func NewStore(config Config) (*Store, error) {
 	if config.SchemaMaxRetries == 0 {
 		config.SchemaMaxRetries = defaults.SchemaMaxRetries
 	}
+	if config.DeltaSnapshotInterval == 0 {
+		config.DeltaSnapshotInterval = defaults.DeltaSnapshotInterval
+	}
 
-	db, err := sql.Open("mysql", config.DSN())
+	driverCfg, err := config.driverConfig()
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(driverCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db := sql.OpenDB(connector)
 
 	// Configure connection pool
 	db.SetMaxOpenConns(config.MaxOpenConns)
@@ -191,6 +225,13 @@ func NewStore(config Config) (*Store, error) {
 	db.SetMaxIdleConns(0)
 	db.SetMaxIdleConns(config.MaxIdleConns)
 
+	if config.GlobalIDs {
+		if err := store.seedGlobalIDSequence(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
 	// Prepare statements
 	if err := store.prepareStatements(); err != nil {
 		db.Close()
@@ -200,6 +241,26 @@ func NewStore(config Config) (*Store, error) {
 	return store, nil
 }
 
+// seedGlobalIDSequence advances the shared ID sequence past every ID already
+// assigned in any context, so that switching to global IDs never reuses one.
+func (s *Store) seedGlobalIDSequence(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO ctx_id_alloc (registry_ctx, next_id) SELECT '.', COALESCE(MAX(schema_id), 0) + 1 FROM schema_fingerprints ON DUPLICATE KEY UPDATE next_id = GREATEST(ctx_id_alloc.next_id, VALUES(next_id))")
+	if err != nil {
+		return fmt.Errorf("failed to seed global ID sequence: %w", err)
+	}
+	return nil
+}
+
+// idSequence returns the ctx_id_alloc row that allocates the schema IDs of a
+// context.
+func (s *Store) idSequence(registryCtx string) string {
+	if s.config.GlobalIDs {
+		return "."
+	}
+	return registryCtx
+}
+
 // prepareStatements prepares all SQL statements for better performance.
 func (s *Store) prepareStatements() error {
 	var err error
@@ -207,31 +268,37 @@ func (s *Store) prepareStatements() error {
 
 	// Schema statements — all scoped by registry_ctx
 	stmts.getSchemaByID, err = s.db.Prepare(
-		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ? AND id = ?")
+		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND id = ?")
 	if err != nil {
 		return fmt.Errorf("prepare getSchemaByID: %w", err)
 	}
 
 	stmts.getSchemaBySubjectVer, err = s.db.Prepare(
-		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND version = ?")
+		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND version = ?")
 	if err != nil {
 		return fmt.Errorf("prepare getSchemaBySubjectVer: %w", err)
 	}
 
 	stmts.getSchemaByFingerprint, err = s.db.Prepare(
-		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND fingerprint = ? AND deleted = FALSE")
+		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND fingerprint = ? AND deleted = FALSE")
 	if err != nil {
 		return fmt.Errorf("prepare getSchemaByFingerprint: %w", err)
 	}
 
 	stmts.getLatestSchema, err = s.db.Prepare(
-		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND deleted = FALSE ORDER BY version DESC LIMIT 1")
+		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND deleted = FALSE ORDER BY version DESC LIMIT 1")
 	if err != nil {
 		return fmt.Errorf("prepare getLatestSchema: %w", err)
 	}
 
+	stmts.getLatestSchemaStamp, err = s.db.Prepare(
+		"SELECT id FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND deleted = FALSE ORDER BY version DESC LIMIT 1")
+	if err != nil {
+		return fmt.Errorf("prepare getLatestSchemaStamp: %w", err)
+	}
+
 	stmts.softDeleteSchema, err = s.db.Prepare(
-		"UPDATE `schemas` SET deleted = TRUE WHERE registry_ctx = ? AND subject = ? AND version = ?")
+		"UPDATE `schemas` SET deleted = TRUE, deleted_at = COALESCE(deleted_at, UTC_TIMESTAMP()) WHERE registry_ctx = ? AND subject = ? AND version = ?")
 	if err != nil {
 		return fmt.Errorf("prepare softDeleteSchema: %w", err)
 	}
@@ -280,25 +347,27 @@ func (s *Store) prepareStatements() error {
 
 	// User statements (global scope)
 	stmts.createUser, err = s.db.Prepare(
-		"INSERT INTO users (username, email, password_hash, role, enabled, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)")
+		"INSERT INTO users (username, email, password_hash, role, enabled, created_at, updated_at, tenant, " +
+			"password_changed_at, must_change_password, password_history) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return fmt.Errorf("prepare createUser: %w", err)
 	}
 
 	stmts.getUserByID, err = s.db.Prepare(
-		"SELECT id, username, email, password_hash, role, enabled, created_at, updated_at FROM users WHERE id = ?")
+		"SELECT id, username, email, password_hash, role, enabled, created_at, updated_at, tenant, password_changed_at, must_change_password, password_history FROM users WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("prepare getUserByID: %w", err)
 	}
 
 	stmts.getUserByUsername, err = s.db.Prepare(
-		"SELECT id, username, email, password_hash, role, enabled, created_at, updated_at FROM users WHERE username = ?")
+		"SELECT id, username, email, password_hash, role, enabled, created_at, updated_at, tenant, password_changed_at, must_change_password, password_history FROM users WHERE username = ?")
 	if err != nil {
 		return fmt.Errorf("prepare getUserByUsername: %w", err)
 	}
 
 	stmts.updateUser, err = s.db.Prepare(
-		"UPDATE users SET username = ?, email = ?, password_hash = ?, role = ?, enabled = ?, updated_at = ? WHERE id = ?")
+		"UPDATE users SET username = ?, email = ?, password_hash = ?, role = ?, enabled = ?, updated_at = ?, " +
+			"password_changed_at = ?, must_change_password = ?, password_history = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("prepare updateUser: %w", err)
 	}
@@ -310,32 +379,32 @@ func (s *Store) prepareStatements() error {
 	}
 
 	stmts.listUsers, err = s.db.Prepare(
-		"SELECT id, username, email, password_hash, role, enabled, created_at, updated_at FROM users ORDER BY username")
+		"SELECT id, username, email, password_hash, role, enabled, created_at, updated_at, tenant, password_changed_at, must_change_password, password_history FROM users ORDER BY username")
 	if err != nil {
 		return fmt.Errorf("prepare listUsers: %w", err)
 	}
 
 	// API Key statements (global scope)
 	stmts.createAPIKey, err = s.db.Prepare(
-		"INSERT INTO api_keys (user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+		"INSERT INTO api_keys (user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, scopes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return fmt.Errorf("prepare createAPIKey: %w", err)
 	}
 
 	stmts.getAPIKeyByID, err = s.db.Prepare(
-		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used FROM api_keys WHERE id = ?")
+		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes FROM api_keys WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("prepare getAPIKeyByID: %w", err)
 	}
 
 	stmts.getAPIKeyByHash, err = s.db.Prepare(
-		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used FROM api_keys WHERE key_hash = ?")
+		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes FROM api_keys WHERE key_hash = ?")
 	if err != nil {
 		return fmt.Errorf("prepare getAPIKeyByHash: %w", err)
 	}
 
 	stmts.updateAPIKey, err = s.db.Prepare(
-		"UPDATE api_keys SET user_id = ?, key_hash = ?, name = ?, role = ?, enabled = ?, expires_at = ? WHERE id = ?")
+		"UPDATE api_keys SET user_id = ?, key_hash = ?, name = ?, role = ?, enabled = ?, expires_at = ?, scopes = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("prepare updateAPIKey: %w", err)
 	}
@@ -347,19 +416,19 @@ func (s *Store) prepareStatements() error {
 	}
 
 	stmts.listAPIKeys, err = s.db.Prepare(
-		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used FROM api_keys ORDER BY created_at DESC")
+		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes FROM api_keys ORDER BY created_at DESC")
 	if err != nil {
 		return fmt.Errorf("prepare listAPIKeys: %w", err)
 	}
 
 	stmts.listAPIKeysByUserID, err = s.db.Prepare(
-		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used FROM api_keys WHERE user_id = ? ORDER BY created_at DESC")
+		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes FROM api_keys WHERE user_id = ? ORDER BY created_at DESC")
 	if err != nil {
 		return fmt.Errorf("prepare listAPIKeysByUserID: %w", err)
 	}
 
 	stmts.getAPIKeyByUserAndName, err = s.db.Prepare(
-		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used FROM api_keys WHERE user_id = ? AND name = ?")
+		"SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes FROM api_keys WHERE user_id = ? AND name = ?")
 	if err != nil {
 		return fmt.Errorf("prepare getAPIKeyByUserAndName: %w", err)
 	}
@@ -382,7 +451,7 @@ func (s *Store) closeStatements() {
 
 	stmts := []*sql.Stmt{
 		s.stmts.getSchemaByID, s.stmts.getSchemaBySubjectVer, s.stmts.getSchemaByFingerprint,
-		s.stmts.getLatestSchema, s.stmts.softDeleteSchema, s.stmts.hardDeleteSchema,
+		s.stmts.getLatestSchema, s.stmts.getLatestSchemaStamp, s.stmts.softDeleteSchema, s.stmts.hardDeleteSchema,
 		s.stmts.countSchemasBySubject, s.stmts.getSubjectsBySchemaID,
 		s.stmts.getVersionsBySchemaID, s.stmts.getReferencedBy,
 		s.stmts.createUser, s.stmts.getUserByID, s.stmts.getUserByUsername,
@@ -426,6 +495,9 @@ func (s *Store) migrate(ctx context.Context) error {
 	// Remove ON DELETE CASCADE from schema_references FK (ignore error if already dropped)
 	_, _ = s.db.ExecContext(ctx, "ALTER TABLE schema_references DROP FOREIGN KEY schema_references_ibfk_1")
 
+	// Keep delta bases from being deleted while versions depend on them (ignore error if already added)
+	_, _ = s.db.ExecContext(ctx, "ALTER TABLE `schemas` ADD CONSTRAINT fk_schemas_delta_base FOREIGN KEY (delta_base_id) REFERENCES `schemas` (id)")
+
 	// Backfill schema_fingerprints from existing data (first ID per fingerprint wins)
 	_, _ = s.db.ExecContext(ctx, "INSERT IGNORE INTO schema_fingerprints (registry_ctx, fingerprint, schema_id) SELECT registry_ctx, fingerprint, MIN(id) FROM `schemas` GROUP BY registry_ctx, fingerprint")
 
@@ -495,8 +567,11 @@ func (s *Store) ensureContext(ctx context.Context, registryCtx string) {
 }
 
 // CreateSchema stores a new schema record.
-// This implementation handles concurrent insertions by retrying on conflicts.
-// MySQL deadlocks are handled as retriable errors.
+// Registrations to the same subject are serialized by a lock on the
+// subject's subject_versions row, so each is given the next version in
+// turn. Conflicts that remain, such as a duplicate key or a deadlock, are
+// retried up to SchemaMaxRetries times with a backoff that gives up when
+// ctx is done.
 func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *storage.SchemaRecord) error {
 	s.ensureContext(ctx, registryCtx)
 	var lastErr error
@@ -509,21 +584,13 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 		if err == storage.ErrSchemaExists {
 			return err
 		}
-		// On duplicate key error or deadlock, retry with exponential backoff + jitter
-		if isMySQLDuplicateError(err) || isMySQLDeadlock(err) {
-			lastErr = err
-			// Exponential backoff: 5ms, 10ms, 20ms, 40ms, ... capped at 500ms
-			// Plus jitter of 0-50% to prevent thundering herd
-			backoff := time.Duration(5<<attempt) * time.Millisecond
-			if backoff > 500*time.Millisecond {
-				backoff = 500 * time.Millisecond
-			}
-			// Add jitter: 0-50% of backoff
-			jitter := time.Duration(float64(backoff) * (0.5 * float64(time.Now().UnixNano()%100) / 100))
-			time.Sleep(backoff + jitter)
-			continue
+		if !isMySQLDuplicateError(err) && !isMySQLDeadlock(err) {
+			return err
+		}
+		lastErr = err
+		if err := retryBackoff(ctx, attempt); err != nil {
+			return err
 		}
-		return err
 	}
 
 	return fmt.Errorf("failed to create schema after %d retries: %w", s.config.SchemaMaxRetries, lastErr)
@@ -539,13 +606,14 @@ func (s *Store) allocateSchemaID(ctx context.Context, registryCtx string) (int64
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	_, _ = tx.ExecContext(ctx, "INSERT IGNORE INTO ctx_id_alloc (registry_ctx, next_id) VALUES (?, 1)", registryCtx)
+	seq := s.idSequence(registryCtx)
+	_, _ = tx.ExecContext(ctx, "INSERT IGNORE INTO ctx_id_alloc (registry_ctx, next_id) VALUES (?, 1)", seq)
 	var id int64
-	err = tx.QueryRowContext(ctx, "SELECT next_id FROM ctx_id_alloc WHERE registry_ctx = ? FOR UPDATE", registryCtx).Scan(&id)
+	err = tx.QueryRowContext(ctx, "SELECT next_id FROM ctx_id_alloc WHERE registry_ctx = ? FOR UPDATE", seq).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get next ID: %w", err)
 	}
-	_, err = tx.ExecContext(ctx, "UPDATE ctx_id_alloc SET next_id = next_id + 1 WHERE registry_ctx = ?", registryCtx)
+	_, err = tx.ExecContext(ctx, "UPDATE ctx_id_alloc SET next_id = next_id + 1 WHERE registry_ctx = ?", seq)
 	if err != nil {
 		return 0, fmt.Errorf("failed to increment next ID: %w", err)
 	}
@@ -563,6 +631,18 @@ func (s *Store) createSchemaAttempt(ctx context.Context, registryCtx string, rec
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	// Lock the subject before reading its versions. A concurrent registration
+	// to the same subject waits here until this transaction ends. This is the
+	// transaction's first statement, so its snapshot is taken after the lock
+	// is held and includes the version the other registration inserted.
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO subject_versions (registry_ctx, subject) VALUES (?, ?) ON DUPLICATE KEY UPDATE subject = subject",
+		registryCtx, record.Subject,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to lock subject: %w", err)
+	}
+
 	// Get next version for this subject FIRST.
 	// In READ COMMITTED (MySQL REPEATABLE READ default uses consistent snapshot,
 	// but within a transaction each statement still sees committed data after locks).
@@ -635,16 +715,23 @@ func (s *Store) createSchemaAttempt(ctx context.Context, registryCtx string, rec
 
 	// If a soft-deleted row with the same fingerprint exists, remove it first.
 	if existingDeleted {
-		_, _ = tx.ExecContext(ctx,
-			"DELETE FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND fingerprint = ? AND deleted = TRUE",
-			registryCtx, record.Subject, record.Fingerprint,
-		)
+		const cond = "registry_ctx = ? AND subject = ? AND fingerprint = ? AND deleted = TRUE"
+		if err := detachDeltaDependents(ctx, tx, cond, registryCtx, record.Subject, record.Fingerprint); err != nil {
+			return err
+		}
+		_, _ = tx.ExecContext(ctx, "DELETE FROM `schemas` WHERE "+cond+" ORDER BY version DESC",
+			registryCtx, record.Subject, record.Fingerprint)
+	}
+
+	schemaText, deltaBase, err := s.encodeSchemaText(ctx, tx, registryCtx, record.Subject, record.Schema)
+	if err != nil {
+		return err
 	}
 
 	// Insert schema - unique constraint on (registry_ctx, subject, version) prevents duplicates
 	_, err = tx.ExecContext(ctx,
-		"INSERT INTO `schemas` (registry_ctx, subject, version, schema_type, schema_text, fingerprint, created_at, metadata, ruleset) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		registryCtx, record.Subject, nextVersion, record.SchemaType, record.Schema, record.Fingerprint, time.Now(), metadataJSON, rulesetJSON,
+		"INSERT INTO `schemas` (registry_ctx, subject, version, schema_type, schema_text, fingerprint, created_at, metadata, ruleset, delta_base_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		registryCtx, record.Subject, nextVersion, record.SchemaType, schemaText, record.Fingerprint, time.Now(), metadataJSON, rulesetJSON, deltaBase,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert schema: %w", err)
@@ -654,13 +741,14 @@ func (s *Store) createSchemaAttempt(ctx context.Context, registryCtx string, rec
 	// the INSERT IGNORE into schema_fingerprints and the subsequent SELECT are
 	// in the same transaction, preventing a TOCTOU race where a concurrent
 	// transaction's INSERT is not yet visible to our SELECT.
-	_, _ = tx.ExecContext(ctx, "INSERT IGNORE INTO ctx_id_alloc (registry_ctx, next_id) VALUES (?, 1)", registryCtx)
+	seq := s.idSequence(registryCtx)
+	_, _ = tx.ExecContext(ctx, "INSERT IGNORE INTO ctx_id_alloc (registry_ctx, next_id) VALUES (?, 1)", seq)
 	var nextCtxID int64
-	err = tx.QueryRowContext(ctx, "SELECT next_id FROM ctx_id_alloc WHERE registry_ctx = ? FOR UPDATE", registryCtx).Scan(&nextCtxID)
+	err = tx.QueryRowContext(ctx, "SELECT next_id FROM ctx_id_alloc WHERE registry_ctx = ? FOR UPDATE", seq).Scan(&nextCtxID)
 	if err != nil {
 		return fmt.Errorf("failed to get next ID: %w", err)
 	}
-	_, err = tx.ExecContext(ctx, "UPDATE ctx_id_alloc SET next_id = next_id + 1 WHERE registry_ctx = ?", registryCtx)
+	_, err = tx.ExecContext(ctx, "UPDATE ctx_id_alloc SET next_id = next_id + 1 WHERE registry_ctx = ?", seq)
 	if err != nil {
 		return fmt.Errorf("failed to increment next ID: %w", err)
 	}
@@ -714,6 +802,7 @@ func (s *Store) GetSchemaByID(ctx context.Context, registryCtx string, id int64)
 	record := &storage.SchemaRecord{}
 	var schemaType string
 	var metadataBytes, rulesetBytes []byte
+	var deltaBase sql.NullInt64
 
 	// Look up the per-context schema ID via schema_fingerprints first.
 	var fingerprint string
@@ -727,12 +816,12 @@ func (s *Store) GetSchemaByID(ctx context.Context, registryCtx string, id int64)
 	// Prefer non-deleted rows but fall back to deleted ones — schema content
 	// must remain accessible by ID even after all subjects are soft-deleted.
 	err := s.db.QueryRowContext(ctx,
-		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset"+
+		"SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id"+
 			" FROM `schemas` WHERE registry_ctx = ? AND fingerprint = ? ORDER BY deleted ASC, id ASC LIMIT 1",
 		registryCtx, fingerprint).Scan(
 		&record.ID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataBytes, &rulesetBytes)
+		&metadataBytes, &rulesetBytes, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		return nil, storage.ErrSchemaNotFound
@@ -741,6 +830,10 @@ func (s *Store) GetSchemaByID(ctx context.Context, registryCtx string, id int64)
 		return nil, fmt.Errorf("failed to get schema: %w", err)
 	}
 
+	if record.Schema, err = expandSchemaText(ctx, s.db, record.ID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
+
 	// Use the per-context schema ID, not the row's auto-generated ID
 	record.ID = id
 
@@ -771,11 +864,12 @@ func (s *Store) GetSchemaBySubjectVersion(ctx context.Context, registryCtx strin
 	var schemaType string
 	var rowID int64
 	var metadataBytes, rulesetBytes []byte
+	var deltaBase sql.NullInt64
 
 	err := s.stmts.getSchemaBySubjectVer.QueryRowContext(ctx, registryCtx, subject, version).Scan(
 		&rowID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataBytes, &rulesetBytes)
+		&metadataBytes, &rulesetBytes, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		// Check if subject exists
@@ -795,6 +889,9 @@ func (s *Store) GetSchemaBySubjectVersion(ctx context.Context, registryCtx strin
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	if err := scanSchemaMetadata(record, metadataBytes, rulesetBytes); err != nil {
 		return nil, err
@@ -820,7 +917,7 @@ func (s *Store) GetSchemaBySubjectVersion(ctx context.Context, registryCtx strin
 
 // GetSchemasBySubject retrieves all schemas for a subject.
 func (s *Store) GetSchemasBySubject(ctx context.Context, registryCtx string, subject string, includeDeleted bool) ([]*storage.SchemaRecord, error) {
-	query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ? AND subject = ?"
+	query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND subject = ?"
 	if !includeDeleted {
 		query += " AND deleted = FALSE"
 	}
@@ -832,18 +929,23 @@ func (s *Store) GetSchemasBySubject(ctx context.Context, registryCtx string, sub
 	}
 	defer rows.Close()
 
+	known := make(map[int64]string)
 	var schemas []*storage.SchemaRecord
 	for rows.Next() {
 		record := &storage.SchemaRecord{}
 		var schemaType string
 		var rowID int64
 		var metadataBytes, rulesetBytes []byte
+		var deltaBase sql.NullInt64
 		if err := rows.Scan(&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataBytes, &rulesetBytes); err != nil {
+			&metadataBytes, &rulesetBytes, &deltaBase); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		record.SchemaType = storage.SchemaType(schemaType)
+		if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, known); err != nil {
+			return nil, err
+		}
 
 		if err := scanSchemaMetadata(record, metadataBytes, rulesetBytes); err != nil {
 			return nil, err
@@ -881,25 +983,115 @@ func (s *Store) GetSchemasBySubject(ctx context.Context, registryCtx string, sub
 	return schemas, nil
 }
 
+// iterateBatchSize is the number of schema versions IterateSchemas reads per
+// query.
+const iterateBatchSize = 500
+
+// IterateSchemas calls fn with every schema version of a context, ordered by
+// subject and version. Versions are read in batches, each batch starting
+// after the last version of the previous one, and no query is open while fn
+// runs.
+func (s *Store) IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*storage.SchemaRecord) error) error {
+	query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND (subject > ? OR (subject = ? AND version > ?))"
+	if !includeDeleted {
+		query += " AND deleted = FALSE"
+	}
+	query += " ORDER BY subject, version LIMIT ?"
+
+	lastSubject, lastVersion := "", 0
+	for {
+		batch, err := s.schemaBatch(ctx, registryCtx, query, lastSubject, lastVersion)
+		if err != nil {
+			return err
+		}
+		for _, record := range batch {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if len(batch) < iterateBatchSize {
+			return nil
+		}
+		lastSubject, lastVersion = batch[len(batch)-1].Subject, batch[len(batch)-1].Version
+	}
+}
+
+// schemaBatch reads one batch of IterateSchemas, the versions after
+// lastSubject and lastVersion. The rows are closed before delta texts,
+// global IDs and references are resolved.
+func (s *Store) schemaBatch(ctx context.Context, registryCtx, query, lastSubject string, lastVersion int) ([]*storage.SchemaRecord, error) {
+	type schemaRow struct {
+		record                      *storage.SchemaRecord
+		rowID                       int64
+		metadataBytes, rulesetBytes []byte
+		deltaBase                   sql.NullInt64
+	}
+	rows, err := s.db.QueryContext(ctx, query, registryCtx, lastSubject, lastSubject, lastVersion, iterateBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	var scanned []schemaRow
+	for rows.Next() {
+		row := schemaRow{record: &storage.SchemaRecord{}}
+		var schemaType string
+		if err := rows.Scan(&row.rowID, &row.record.Subject, &row.record.Version, &schemaType,
+			&row.record.Schema, &row.record.Fingerprint, &row.record.Deleted, &row.record.CreatedAt,
+			&row.metadataBytes, &row.rulesetBytes, &row.deltaBase); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		row.record.SchemaType = storage.SchemaType(schemaType)
+		scanned = append(scanned, row)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate schemas: %w", err)
+	}
+
+	known := make(map[int64]string)
+	batch := make([]*storage.SchemaRecord, 0, len(scanned))
+	for _, row := range scanned {
+		record := row.record
+		if record.Schema, err = expandSchemaText(ctx, s.db, row.rowID, record.Schema, row.deltaBase, known); err != nil {
+			return nil, err
+		}
+		if err := scanSchemaMetadata(record, row.metadataBytes, row.rulesetBytes); err != nil {
+			return nil, err
+		}
+		if globalID, gErr := s.globalSchemaID(ctx, registryCtx, record.Fingerprint); gErr == nil {
+			record.ID = globalID
+		} else {
+			record.ID = row.rowID
+		}
+		if record.References, err = s.loadReferences(ctx, registryCtx, record.ID); err != nil {
+			return nil, err
+		}
+		batch = append(batch, record)
+	}
+	return batch, nil
+}
+
 // GetSchemaByFingerprint retrieves a schema by subject and fingerprint.
 func (s *Store) GetSchemaByFingerprint(ctx context.Context, registryCtx string, subject, fingerprint string, includeDeleted bool) (*storage.SchemaRecord, error) {
 	record := &storage.SchemaRecord{}
 	var schemaType string
 	var rowID int64
 	var metadataBytes, rulesetBytes []byte
+	var deltaBase sql.NullInt64
 	var err error
 
 	if includeDeleted {
-		query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND fingerprint = ?"
+		query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND subject = ? AND fingerprint = ?"
 		err = s.db.QueryRowContext(ctx, query, registryCtx, subject, fingerprint).Scan(
 			&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataBytes, &rulesetBytes)
+			&metadataBytes, &rulesetBytes, &deltaBase)
 	} else {
 		err = s.stmts.getSchemaByFingerprint.QueryRowContext(ctx, registryCtx, subject, fingerprint).Scan(
 			&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataBytes, &rulesetBytes)
+			&metadataBytes, &rulesetBytes, &deltaBase)
 	}
 
 	if err == sql.ErrNoRows {
@@ -917,6 +1109,9 @@ func (s *Store) GetSchemaByFingerprint(ctx context.Context, registryCtx string,
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	if err := scanSchemaMetadata(record, metadataBytes, rulesetBytes); err != nil {
 		return nil, err
@@ -946,13 +1141,14 @@ func (s *Store) GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx st
 	var schemaType string
 	var rowID int64
 	var metadataBytes, rulesetBytes []byte
+	var deltaBase sql.NullInt64
 
 	// Query for any schema with this fingerprint within this context
-	query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ? AND fingerprint = ? AND deleted = false LIMIT 1"
+	query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ? AND fingerprint = ? AND deleted = false LIMIT 1"
 	err := s.db.QueryRowContext(ctx, query, registryCtx, fingerprint).Scan(
 		&rowID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataBytes, &rulesetBytes)
+		&metadataBytes, &rulesetBytes, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		return nil, storage.ErrSchemaNotFound
@@ -962,6 +1158,9 @@ func (s *Store) GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx st
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	if err := scanSchemaMetadata(record, metadataBytes, rulesetBytes); err != nil {
 		return nil, err
@@ -990,11 +1189,12 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	var schemaType string
 	var rowID int64
 	var metadataBytes, rulesetBytes []byte
+	var deltaBase sql.NullInt64
 
 	err := s.stmts.getLatestSchema.QueryRowContext(ctx, registryCtx, subject).Scan(
 		&rowID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataBytes, &rulesetBytes)
+		&metadataBytes, &rulesetBytes, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		return nil, storage.ErrSubjectNotFound
@@ -1004,6 +1204,9 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	if err := scanSchemaMetadata(record, metadataBytes, rulesetBytes); err != nil {
 		return nil, err
@@ -1026,6 +1229,20 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	return record, nil
 }
 
+// GetLatestSchemaStamp returns the row ID of a subject's latest non-deleted
+// version. Row IDs are never reused, so a re-registered version gets a new one.
+func (s *Store) GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error) {
+	var rowID int64
+	err := s.stmts.getLatestSchemaStamp.QueryRowContext(ctx, registryCtx, subject).Scan(&rowID)
+	if err == sql.ErrNoRows {
+		return "", storage.ErrSubjectNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest schema stamp: %w", err)
+	}
+	return strconv.FormatInt(rowID, 10), nil
+}
+
 // DeleteSchema soft-deletes or permanently deletes a schema version.
 func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject string, version int, permanent bool) error {
 	if permanent {
@@ -1051,8 +1268,18 @@ func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject st
 		if !deleted {
 			return storage.ErrVersionNotSoftDeleted
 		}
-		_, err = s.stmts.hardDeleteSchema.ExecContext(ctx, registryCtx, subject, version)
+		tx, err := s.db.BeginTx(ctx, nil)
 		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		if err := detachDeltaDependents(ctx, tx, "registry_ctx = ? AND subject = ? AND version = ?", registryCtx, subject, version); err != nil {
+			return err
+		}
+		if _, err := tx.StmtContext(ctx, s.stmts.hardDeleteSchema).ExecContext(ctx, registryCtx, subject, version); err != nil {
+			return fmt.Errorf("failed to delete schema: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
 			return fmt.Errorf("failed to delete schema: %w", err)
 		}
 
@@ -1063,7 +1290,7 @@ func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject st
 	}
 
 	var result sql.Result
-	if err := s.withDeadlockRetry(func() error {
+	if err := s.withDeadlockRetry(ctx, func() error {
 		var err error
 		result, err = s.stmts.softDeleteSchema.ExecContext(ctx, registryCtx, subject, version)
 		return err
@@ -1085,6 +1312,43 @@ func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject st
 	return nil
 }
 
+// RewriteSchemaText replaces the stored text of every row of the schema with
+// the given ID. The rows are stored in full afterwards, so rows stored as a
+// delta against them are first rewritten in full too.
+func (s *Store) RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var fingerprint string
+	err = tx.QueryRowContext(ctx,
+		"SELECT fingerprint FROM schema_fingerprints WHERE registry_ctx = ? AND schema_id = ?", registryCtx, id).Scan(&fingerprint)
+	if err == sql.ErrNoRows {
+		return storage.ErrSchemaNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get schema: %w", err)
+	}
+	if err := detachDeltaDependents(ctx, tx, "registry_ctx = ? AND fingerprint = ?", registryCtx, fingerprint); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx,
+		"UPDATE `schemas` SET schema_text = ?, delta_base_id = NULL WHERE registry_ctx = ? AND fingerprint = ?",
+		schemaText, registryCtx, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite schema: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrSchemaNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to rewrite schema: %w", err)
+	}
+	return nil
+}
+
 // ListSubjects returns all subject names.
 func (s *Store) ListSubjects(ctx context.Context, registryCtx string, includeDeleted bool) ([]string, error) {
 	query := "SELECT DISTINCT subject FROM `schemas` WHERE registry_ctx = ?"
@@ -1111,6 +1375,118 @@ func (s *Store) ListSubjects(ctx context.Context, registryCtx string, includeDel
 	return subjects, nil
 }
 
+// ListSubjectsPage returns one page of subject names, applying the filters
+// and LIMIT/OFFSET in the database.
+func (s *Store) ListSubjectsPage(ctx context.Context, registryCtx string, params *storage.ListSubjectsParams) ([]string, error) {
+	query := "SELECT subject FROM `schemas` WHERE registry_ctx = ?"
+	args := []interface{}{registryCtx}
+	if params.Prefix != "" {
+		query += " AND subject LIKE ?"
+		args = append(args, likePrefix(params.Prefix))
+	}
+	query += " GROUP BY subject"
+	switch {
+	case params.DeletedOnly:
+		query += " HAVING MIN(deleted) = 1"
+	case !params.Deleted:
+		query += " HAVING MIN(deleted) = 0"
+	}
+	query += " ORDER BY subject"
+	// MySQL requires LIMIT before OFFSET; add a large default LIMIT when only OFFSET is specified
+	if params.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, params.Limit)
+	} else if params.Offset > 0 {
+		query += " LIMIT ?"
+		args = append(args, int64(math.MaxInt64))
+	}
+	if params.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, params.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subjects: %w", err)
+	}
+	defer rows.Close()
+
+	subjects := []string{}
+	for rows.Next() {
+		var subject string
+		if err := rows.Scan(&subject); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		subjects = append(subjects, subject)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate subjects: %w", err)
+	}
+	return subjects, nil
+}
+
+// ListVersions returns the version numbers of a subject, applying LIMIT/OFFSET in the database.
+func (s *Store) ListVersions(ctx context.Context, registryCtx string, subject string, params *storage.ListVersionsParams) ([]int, error) {
+	query := "SELECT version FROM `schemas` WHERE registry_ctx = ? AND subject = ?"
+	if !params.Deleted {
+		query += " AND deleted = FALSE"
+	}
+	args := []interface{}{registryCtx, subject}
+
+	if params.LatestOnly {
+		query += " ORDER BY version DESC LIMIT 1"
+	} else {
+		query += " ORDER BY version"
+		// MySQL requires LIMIT before OFFSET; add a large default LIMIT when only OFFSET is specified
+		if params.Limit > 0 {
+			query += " LIMIT ?"
+			args = append(args, params.Limit)
+		} else if params.Offset > 0 {
+			query += " LIMIT ?"
+			args = append(args, int64(math.MaxInt64))
+		}
+		if params.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, params.Offset)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []int{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate versions: %w", err)
+	}
+
+	if len(versions) == 0 {
+		// An empty page past the end is not the same as a missing subject.
+		countQuery := "SELECT COUNT(*) FROM `schemas` WHERE registry_ctx = ? AND subject = ?"
+		if !params.Deleted {
+			countQuery += " AND deleted = FALSE"
+		}
+		var count int
+		if err := s.db.QueryRowContext(ctx, countQuery, registryCtx, subject).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count versions: %w", err)
+		}
+		if count == 0 {
+			return nil, storage.ErrSubjectNotFound
+		}
+	}
+
+	return versions, nil
+}
+
 // DeleteSubject deletes all versions of a subject.
 func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
 	if permanent {
@@ -1149,12 +1525,16 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 		}
 		rows.Close()
 
-		_, err = s.db.ExecContext(ctx, "DELETE FROM `schemas` WHERE registry_ctx = ? AND subject = ?", registryCtx, subject)
+		// Newest first, so no version is deleted before the versions stored as
+		// deltas against it.
+		_, err = s.db.ExecContext(ctx, "DELETE FROM `schemas` WHERE registry_ctx = ? AND subject = ? ORDER BY version DESC", registryCtx, subject)
 		if err != nil {
 			return nil, fmt.Errorf("failed to delete schemas: %w", err)
 		}
 		_, _ = s.db.ExecContext(ctx, "DELETE FROM configs WHERE registry_ctx = ? AND subject = ?", registryCtx, subject)
 		_, _ = s.db.ExecContext(ctx, "DELETE FROM modes WHERE registry_ctx = ? AND subject = ?", registryCtx, subject)
+		_, _ = s.db.ExecContext(ctx, "DELETE FROM schema_tags WHERE registry_ctx = ? AND subject = ?", registryCtx, subject)
+		_, _ = s.db.ExecContext(ctx, "DELETE FROM subject_meta WHERE registry_ctx = ? AND subject = ?", registryCtx, subject)
 
 		// Clean up orphaned schema_fingerprints and schema_references
 		for fp := range fingerprintSet {
@@ -1193,9 +1573,9 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 		return nil, storage.ErrSubjectNotFound
 	}
 
-	if err := s.withDeadlockRetry(func() error {
+	if err := s.withDeadlockRetry(ctx, func() error {
 		_, err := s.db.ExecContext(ctx,
-			"UPDATE `schemas` SET deleted = TRUE WHERE registry_ctx = ? AND subject = ?",
+			"UPDATE `schemas` SET deleted = TRUE, deleted_at = UTC_TIMESTAMP() WHERE registry_ctx = ? AND subject = ? AND deleted = FALSE",
 			registryCtx, subject,
 		)
 		return err
@@ -1206,6 +1586,33 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 	return versions, nil
 }
 
+// ListSoftDeletedVersions returns soft-deleted versions deleted before the cutoff.
+func (s *Store) ListSoftDeletedVersions(ctx context.Context, registryCtx string, deletedBefore time.Time) ([]storage.SubjectVersion, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT subject, version FROM `schemas` "+
+			"WHERE registry_ctx = ? AND deleted = TRUE AND deleted_at < ? "+
+			"ORDER BY subject, version",
+		registryCtx, deletedBefore.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query soft-deleted versions: %w", err)
+	}
+	defer rows.Close()
+
+	result := []storage.SubjectVersion{}
+	for rows.Next() {
+		var sv storage.SubjectVersion
+		if err := rows.Scan(&sv.Subject, &sv.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result = append(result, sv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate soft-deleted versions: %w", err)
+	}
+	return result, nil
+}
+
 // SubjectExists checks if a subject exists.
 func (s *Store) SubjectExists(ctx context.Context, registryCtx string, subject string) (bool, error) {
 	var count int
@@ -1473,19 +1880,25 @@ func (s *Store) SetGlobalMode(ctx context.Context, registryCtx string, mode *sto
 }
 
 // NextID returns the next available per-context schema ID.
-// Uses the ctx_id_alloc table for per-context ID allocation.
+// Uses the ctx_id_alloc table for per-context ID allocation, or its default
+// context row when GlobalIDs is set.
 func (s *Store) NextID(ctx context.Context, registryCtx string) (int64, error) {
 	return s.allocateSchemaID(ctx, registryCtx)
 }
 
-// GetMaxSchemaID returns the highest per-context schema ID currently assigned.
+// GetMaxSchemaID returns the highest per-context schema ID currently assigned,
+// or the highest in any context when GlobalIDs is set.
 func (s *Store) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64, error) {
+	query := "SELECT COALESCE(MAX(schema_id), 0) FROM schema_fingerprints WHERE registry_ctx = ?"
+	args := []interface{}{registryCtx}
+	if s.config.GlobalIDs {
+		query = "SELECT COALESCE(MAX(schema_id), 0) FROM schema_fingerprints"
+		args = nil
+	}
 	var lastErr error
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		var maxID int64
-		err := s.db.QueryRowContext(ctx,
-			"SELECT COALESCE(MAX(schema_id), 0) FROM schema_fingerprints WHERE registry_ctx = ?",
-			registryCtx).Scan(&maxID)
+		err := s.db.QueryRowContext(ctx, query, args...).Scan(&maxID)
 		if err == nil {
 			return maxID, nil
 		}
@@ -1497,6 +1910,21 @@ func (s *Store) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64,
 	return 0, fmt.Errorf("failed to get max schema ID: %w", lastErr)
 }
 
+// PeekNextID returns the next ID of the context's sequence, or of the shared
+// sequence when GlobalIDs is set, without allocating it.
+func (s *Store) PeekNextID(ctx context.Context, registryCtx string) (int64, error) {
+	var next int64
+	err := s.db.QueryRowContext(ctx,
+		"SELECT next_id FROM ctx_id_alloc WHERE registry_ctx = ?", s.idSequence(registryCtx)).Scan(&next)
+	if err == sql.ErrNoRows {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read next ID: %w", err)
+	}
+	return next, nil
+}
+
 // ImportSchema inserts a schema with a specified ID (for migration).
 // Returns ErrSchemaIDConflict if the ID already exists with different content.
 func (s *Store) ImportSchema(ctx context.Context, registryCtx string, record *storage.SchemaRecord) error {
@@ -1569,7 +1997,7 @@ func (s *Store) ImportSchema(ctx context.Context, registryCtx string, record *st
 		// Advance ctx_id_alloc past the imported ID if needed
 		_, _ = tx.ExecContext(ctx,
 			"INSERT INTO ctx_id_alloc (registry_ctx, next_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE next_id = GREATEST(next_id, VALUES(next_id))",
-			registryCtx, record.ID+1,
+			s.idSequence(registryCtx), record.ID+1,
 		)
 	}
 
@@ -1601,7 +2029,7 @@ func (s *Store) ImportSchema(ctx context.Context, registryCtx string, record *st
 func (s *Store) SetNextID(ctx context.Context, registryCtx string, id int64) error {
 	_, err := s.db.ExecContext(ctx,
 		"INSERT INTO ctx_id_alloc (registry_ctx, next_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE next_id = VALUES(next_id)",
-		registryCtx, id)
+		s.idSequence(registryCtx), id)
 	if err != nil {
 		return fmt.Errorf("failed to set next ID: %w", err)
 	}
@@ -1747,9 +2175,48 @@ func (s *Store) GetVersionsBySchemaID(ctx context.Context, registryCtx string, i
 	return versions, nil
 }
 
+// subjectAliases returns the target of every subject of a context whose
+// config sets an alias.
+func (s *Store) subjectAliases(ctx context.Context, registryCtx string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT subject, alias FROM configs WHERE registry_ctx = ? AND subject <> '' AND alias IS NOT NULL AND alias <> ''`,
+		registryCtx,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subject aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make(map[string]string)
+	for rows.Next() {
+		var subject, alias string
+		if err := rows.Scan(&subject, &alias); err != nil {
+			return nil, fmt.Errorf("failed to scan subject alias: %w", err)
+		}
+		aliases[subject] = alias
+	}
+	return aliases, rows.Err()
+}
+
+// likePrefix returns the LIKE pattern matching strings that start with
+// prefix, with the wildcards in prefix escaped.
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+}
+
 // ListSchemas returns schemas matching the given filters, scoped to a context.
 func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
-	query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM `schemas` WHERE registry_ctx = ?"
+	if params.Aliases {
+		aliases, err := s.subjectAliases(ctx, registryCtx)
+		if err != nil {
+			return nil, err
+		}
+		return params.WithAliases(aliases, func(p *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
+			return s.ListSchemas(ctx, registryCtx, p)
+		})
+	}
+
+	query := "SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM `schemas` WHERE registry_ctx = ?"
 	args := []interface{}{registryCtx}
 
 	if !params.Deleted {
@@ -1759,18 +2226,18 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 
 	if params.SubjectPrefix != "" {
 		query += " AND subject LIKE ?"
-		args = append(args, params.SubjectPrefix+"%")
+		args = append(args, likePrefix(params.SubjectPrefix))
 	}
 
 	if params.LatestOnly {
 		args = []interface{}{registryCtx}
-		query = "SELECT s.id, s.subject, s.version, s.schema_type, s.schema_text, s.fingerprint, s.deleted, s.created_at, s.metadata, s.ruleset FROM `schemas` s INNER JOIN (SELECT subject, MAX(version) as max_version FROM `schemas` WHERE registry_ctx = ?"
+		query = "SELECT s.id, s.subject, s.version, s.schema_type, s.schema_text, s.fingerprint, s.deleted, s.created_at, s.metadata, s.ruleset, s.delta_base_id FROM `schemas` s INNER JOIN (SELECT subject, MAX(version) as max_version FROM `schemas` WHERE registry_ctx = ?"
 		if !params.Deleted {
 			query += " AND deleted = FALSE"
 		}
 		if params.SubjectPrefix != "" {
 			query += " AND subject LIKE ?"
-			args = append(args, params.SubjectPrefix+"%")
+			args = append(args, likePrefix(params.SubjectPrefix))
 		}
 		query += " GROUP BY subject) latest ON s.subject = latest.subject AND s.version = latest.max_version"
 		query += " WHERE s.registry_ctx = ?"
@@ -1802,18 +2269,23 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 	}
 	defer rows.Close()
 
+	known := make(map[int64]string)
 	var schemas []*storage.SchemaRecord
 	for rows.Next() {
 		record := &storage.SchemaRecord{}
 		var schemaType string
 		var rowID int64
 		var metadataBytes, rulesetBytes []byte
+		var deltaBase sql.NullInt64
 		if err := rows.Scan(&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataBytes, &rulesetBytes); err != nil {
+			&metadataBytes, &rulesetBytes, &deltaBase); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		record.SchemaType = storage.SchemaType(schemaType)
+		if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, known); err != nil {
+			return nil, err
+		}
 
 		if err := scanSchemaMetadata(record, metadataBytes, rulesetBytes); err != nil {
 			return nil, err
@@ -1889,329 +2361,1794 @@ func (s *Store) ListContexts(ctx context.Context) ([]string, error) {
 	return contexts, nil
 }
 
-// CreateUser creates a new user record.
-func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
+// CreateContext explicitly creates a registry context with its metadata.
+func (s *Store) CreateContext(ctx context.Context, record *storage.ContextRecord) error {
 	now := time.Now()
-	user.CreatedAt = now
-	user.UpdatedAt = now
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO contexts (registry_ctx, description, owner, max_subjects, max_schema_size, tenant, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		record.Name, record.Description, record.Owner, record.MaxSubjects, record.MaxSchemaSize, record.Tenant, now, now)
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrContextExists
+		}
+		return fmt.Errorf("failed to create context: %w", err)
+	}
 
-	var email sql.NullString
-	if user.Email != "" {
-		email = sql.NullString{String: user.Email, Valid: true}
+	record.CreatedAt = now
+	record.UpdatedAt = now
+	return nil
+}
+
+// GetContext retrieves a registry context and its metadata.
+func (s *Store) GetContext(ctx context.Context, name string) (*storage.ContextRecord, error) {
+	rec := &storage.ContextRecord{}
+	var description, owner sql.NullString
+
+	err := s.db.QueryRowContext(ctx,
+		"SELECT registry_ctx, description, owner, max_subjects, max_schema_size, tenant, created_at, updated_at FROM contexts WHERE registry_ctx = ?",
+		name).Scan(&rec.Name, &description, &owner, &rec.MaxSubjects, &rec.MaxSchemaSize, &rec.Tenant, &rec.CreatedAt, &rec.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrContextNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get context: %w", err)
 	}
 
-	result, err := s.stmts.createUser.ExecContext(ctx,
-		user.Username, email, user.PasswordHash, user.Role, user.Enabled, user.CreatedAt, user.UpdatedAt)
+	rec.Description = description.String
+	rec.Owner = owner.String
+	return rec, nil
+}
 
+// UpdateContext updates the metadata and limits of an existing context.
+func (s *Store) UpdateContext(ctx context.Context, record *storage.ContextRecord) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE contexts SET description = ?, owner = ?, max_subjects = ?, max_schema_size = ?, updated_at = ? WHERE registry_ctx = ?",
+		record.Description, record.Owner, record.MaxSubjects, record.MaxSchemaSize, time.Now(), record.Name)
 	if err != nil {
-		if isMySQLDuplicateError(err) {
-			return storage.ErrUserExists
+		return fmt.Errorf("failed to update context: %w", err)
+	}
+
+	// MySQL reports zero affected rows when nothing changed, so read the row
+	// back to distinguish a no-op update from a missing context.
+	existing, err := s.GetContext(ctx, record.Name)
+	if err != nil {
+		return err
+	}
+	record.Tenant = existing.Tenant
+	record.CreatedAt = existing.CreatedAt
+	record.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+// DeleteContext removes a context and all of its per-context data.
+func (s *Store) DeleteContext(ctx context.Context, name string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM contexts WHERE registry_ctx = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete context: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return storage.ErrContextNotFound
+	}
+
+	for _, table := range []string{"schema_references", "`schemas`", "schema_fingerprints", "configs", "modes", "schema_tags", "subject_meta", "schema_id_aliases", "frozen_versions", "reader_assertions", "ctx_id_alloc"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE registry_ctx = ?", name); err != nil {
+			return fmt.Errorf("failed to delete context data from %s: %w", table, err)
 		}
-		return fmt.Errorf("failed to create user: %w", err)
 	}
 
-	id, err := result.LastInsertId()
+	return tx.Commit()
+}
+
+// SetTags replaces the tags and labels of a subject or subject version.
+func (s *Store) SetTags(ctx context.Context, registryCtx string, record *storage.TagsRecord) error {
+	tagsJSON, labelsJSON, err := marshalTags(record)
 	if err != nil {
-		return fmt.Errorf("failed to get last insert ID: %w", err)
+		return err
 	}
-	user.ID = id
+	now := time.Now()
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO schema_tags (registry_ctx, subject, version, tags, labels, updated_at) VALUES (?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE tags = VALUES(tags), labels = VALUES(labels), updated_at = VALUES(updated_at)",
+		registryCtx, record.Subject, record.Version, tagsJSON, labelsJSON, now)
+	if err != nil {
+		return fmt.Errorf("failed to set tags: %w", err)
+	}
+	record.UpdatedAt = now
+	return nil
+}
+
+// GetTags retrieves the tags and labels of a subject or subject version.
+func (s *Store) GetTags(ctx context.Context, registryCtx string, subject string, version int) (*storage.TagsRecord, error) {
+	rec := &storage.TagsRecord{Subject: subject, Version: version}
+	var tagsJSON, labelsJSON []byte
+	err := s.db.QueryRowContext(ctx,
+		"SELECT tags, labels, updated_at FROM schema_tags WHERE registry_ctx = ? AND subject = ? AND version = ?",
+		registryCtx, subject, version).Scan(&tagsJSON, &labelsJSON, &rec.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrTagsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	if err := unmarshalTags(rec, tagsJSON, labelsJSON); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
 
+// DeleteTags removes the tags and labels of a subject or subject version.
+func (s *Store) DeleteTags(ctx context.Context, registryCtx string, subject string, version int) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM schema_tags WHERE registry_ctx = ? AND subject = ? AND version = ?",
+		registryCtx, subject, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrTagsNotFound
+	}
 	return nil
 }
 
-// GetUserByID retrieves a user by ID.
-func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
-	user := &storage.UserRecord{}
-	var email sql.NullString
+// ListTags returns every tags record in a context, ordered by subject and version.
+func (s *Store) ListTags(ctx context.Context, registryCtx string) ([]*storage.TagsRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT subject, version, tags, labels, updated_at FROM schema_tags WHERE registry_ctx = ? ORDER BY subject, version",
+		registryCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
 
-	err := s.stmts.getUserByID.QueryRowContext(ctx, id).Scan(
-		&user.ID, &user.Username, &email, &user.PasswordHash,
-		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt)
+	records := make([]*storage.TagsRecord, 0)
+	for rows.Next() {
+		rec := &storage.TagsRecord{}
+		var tagsJSON, labelsJSON []byte
+		if err := rows.Scan(&rec.Subject, &rec.Version, &tagsJSON, &labelsJSON, &rec.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := unmarshalTags(rec, tagsJSON, labelsJSON); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// marshalTags encodes a record's tags and labels for the JSON columns.
+func marshalTags(record *storage.TagsRecord) (tagsJSON, labelsJSON string, err error) {
+	tags := record.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	labels := record.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	t, err := json.Marshal(tags)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	l, err := json.Marshal(labels)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	return string(t), string(l), nil
+}
+
+// unmarshalTags decodes the JSON columns into a record.
+func unmarshalTags(rec *storage.TagsRecord, tagsJSON, labelsJSON []byte) error {
+	if err := json.Unmarshal(tagsJSON, &rec.Tags); err != nil {
+		return fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if err := json.Unmarshal(labelsJSON, &rec.Labels); err != nil {
+		return fmt.Errorf("failed to unmarshal labels: %w", err)
+	}
+	return nil
+}
+
+// SetSubjectMeta creates or replaces the meta of a subject.
+func (s *Store) SetSubjectMeta(ctx context.Context, registryCtx string, record *storage.SubjectMetaRecord) error {
+	linksJSON, err := marshalSubjectLinks(record.Links)
+	if err != nil {
+		return err
+	}
+	reviewers, err := marshalReviewers(record.Reviewers)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO subject_meta (registry_ctx, subject, owner, description, contact, links, reviewers, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE owner = VALUES(owner), description = VALUES(description), contact = VALUES(contact), "+
+			"links = VALUES(links), reviewers = VALUES(reviewers), updated_at = VALUES(updated_at)",
+		registryCtx, record.Subject, record.Owner, record.Description, record.Contact, linksJSON, reviewers, now)
+	if err != nil {
+		return fmt.Errorf("failed to set subject meta: %w", err)
+	}
+	record.UpdatedAt = now
+	return nil
+}
 
+// GetSubjectMeta retrieves the meta of a subject.
+func (s *Store) GetSubjectMeta(ctx context.Context, registryCtx string, subject string) (*storage.SubjectMetaRecord, error) {
+	rec := &storage.SubjectMetaRecord{Subject: subject}
+	var linksJSON []byte
+	var reviewers sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		"SELECT owner, description, contact, links, reviewers, updated_at FROM subject_meta WHERE registry_ctx = ? AND subject = ?",
+		registryCtx, subject).Scan(&rec.Owner, &rec.Description, &rec.Contact, &linksJSON, &reviewers, &rec.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, storage.ErrUserNotFound
+		return nil, storage.ErrSubjectMetaNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get subject meta: %w", err)
+	}
+	if err := json.Unmarshal(linksJSON, &rec.Links); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subject links: %w", err)
+	}
+	if rec.Reviewers, err = unmarshalReviewers(reviewers); err != nil {
+		return nil, err
 	}
+	return rec, nil
+}
 
-	if email.Valid {
-		user.Email = email.String
+// DeleteSubjectMeta removes the meta of a subject.
+func (s *Store) DeleteSubjectMeta(ctx context.Context, registryCtx string, subject string) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM subject_meta WHERE registry_ctx = ? AND subject = ?", registryCtx, subject)
+	if err != nil {
+		return fmt.Errorf("failed to delete subject meta: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrSubjectMetaNotFound
+	}
+	return nil
+}
+
+// marshalSubjectLinks encodes a subject's links for the JSON column.
+func marshalSubjectLinks(links []storage.SubjectLink) (string, error) {
+	if links == nil {
+		links = []storage.SubjectLink{}
+	}
+	b, err := json.Marshal(links)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal subject links: %w", err)
 	}
+	return string(b), nil
+}
 
-	return user, nil
+// SetSchemaIDAlias creates or replaces the alias of a source registry's schema ID.
+func (s *Store) SetSchemaIDAlias(ctx context.Context, registryCtx string, alias *storage.SchemaIDAliasRecord) error {
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO schema_id_aliases (registry_ctx, source, source_id, schema_id, updated_at) VALUES (?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE schema_id = VALUES(schema_id), updated_at = VALUES(updated_at)",
+		registryCtx, alias.Source, alias.SourceID, alias.SchemaID, now)
+	if err != nil {
+		return fmt.Errorf("failed to set schema ID alias: %w", err)
+	}
+	alias.UpdatedAt = now
+	return nil
 }
 
-// GetUserByUsername retrieves a user by username.
-func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
-	user := &storage.UserRecord{}
-	var email sql.NullString
+// GetSchemaIDAlias retrieves the alias of a source registry's schema ID.
+func (s *Store) GetSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) (*storage.SchemaIDAliasRecord, error) {
+	alias := &storage.SchemaIDAliasRecord{Source: source, SourceID: sourceID}
+	err := s.db.QueryRowContext(ctx,
+		"SELECT schema_id, updated_at FROM schema_id_aliases WHERE registry_ctx = ? AND source = ? AND source_id = ?",
+		registryCtx, source, sourceID).Scan(&alias.SchemaID, &alias.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrIDAliasNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema ID alias: %w", err)
+	}
+	return alias, nil
+}
 
-	err := s.stmts.getUserByUsername.QueryRowContext(ctx, username).Scan(
-		&user.ID, &user.Username, &email, &user.PasswordHash,
-		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt)
+// DeleteSchemaIDAlias removes the alias of a source registry's schema ID.
+func (s *Store) DeleteSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM schema_id_aliases WHERE registry_ctx = ? AND source = ? AND source_id = ?",
+		registryCtx, source, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete schema ID alias: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrIDAliasNotFound
+	}
+	return nil
+}
+
+// ListSchemaIDAliases returns the aliases in a context, ordered by source and source ID.
+func (s *Store) ListSchemaIDAliases(ctx context.Context, registryCtx string, source string) ([]*storage.SchemaIDAliasRecord, error) {
+	query := "SELECT source, source_id, schema_id, updated_at FROM schema_id_aliases WHERE registry_ctx = ?"
+	args := []interface{}{registryCtx}
+	if source != "" {
+		query += " AND source = ?"
+		args = append(args, source)
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY source, source_id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema ID aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make([]*storage.SchemaIDAliasRecord, 0)
+	for rows.Next() {
+		alias := &storage.SchemaIDAliasRecord{}
+		if err := rows.Scan(&alias.Source, &alias.SourceID, &alias.SchemaID, &alias.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+// FreezeVersion creates or replaces the freeze of a subject version.
+func (s *Store) FreezeVersion(ctx context.Context, registryCtx string, record *storage.FrozenVersionRecord) error {
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO frozen_versions (registry_ctx, subject, version, frozen_by, reason, frozen_at) VALUES (?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE frozen_by = VALUES(frozen_by), reason = VALUES(reason), frozen_at = VALUES(frozen_at)",
+		registryCtx, record.Subject, record.Version, record.FrozenBy, record.Reason, now)
+	if err != nil {
+		return fmt.Errorf("failed to freeze version: %w", err)
+	}
+	record.FrozenAt = now
+	return nil
+}
 
+// GetFrozenVersion retrieves the freeze of a subject version.
+func (s *Store) GetFrozenVersion(ctx context.Context, registryCtx string, subject string, version int) (*storage.FrozenVersionRecord, error) {
+	record := &storage.FrozenVersionRecord{Subject: subject, Version: version}
+	err := s.db.QueryRowContext(ctx,
+		"SELECT frozen_by, reason, frozen_at FROM frozen_versions WHERE registry_ctx = ? AND subject = ? AND version = ?",
+		registryCtx, subject, version).Scan(&record.FrozenBy, &record.Reason, &record.FrozenAt)
 	if err == sql.ErrNoRows {
-		return nil, storage.ErrUserNotFound
+		return nil, storage.ErrFrozenVersionNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get frozen version: %w", err)
 	}
+	return record, nil
+}
 
-	if email.Valid {
-		user.Email = email.String
+// UnfreezeVersion removes the freeze of a subject version.
+func (s *Store) UnfreezeVersion(ctx context.Context, registryCtx string, subject string, version int) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM frozen_versions WHERE registry_ctx = ? AND subject = ? AND version = ?",
+		registryCtx, subject, version)
+	if err != nil {
+		return fmt.Errorf("failed to unfreeze version: %w", err)
 	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrFrozenVersionNotFound
+	}
+	return nil
+}
 
-	return user, nil
+// ListFrozenVersions returns the frozen versions in a context, ordered by subject and version.
+func (s *Store) ListFrozenVersions(ctx context.Context, registryCtx string, subject string) ([]*storage.FrozenVersionRecord, error) {
+	query := "SELECT subject, version, frozen_by, reason, frozen_at FROM frozen_versions WHERE registry_ctx = ?"
+	args := []interface{}{registryCtx}
+	if subject != "" {
+		query += " AND subject = ?"
+		args = append(args, subject)
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY subject, version", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query frozen versions: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*storage.FrozenVersionRecord, 0)
+	for rows.Next() {
+		record := &storage.FrozenVersionRecord{}
+		if err := rows.Scan(&record.Subject, &record.Version, &record.FrozenBy, &record.Reason, &record.FrozenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
 }
 
-// UpdateUser updates an existing user record.
-func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
-	user.UpdatedAt = time.Now()
+// SetReaderAssertion creates or replaces a consumer's assertion for a subject.
+func (s *Store) SetReaderAssertion(ctx context.Context, registryCtx string, record *storage.ReaderAssertionRecord) error {
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO reader_assertions (registry_ctx, subject, consumer, reader_subject, reader_version, updated_at) VALUES (?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE reader_subject = VALUES(reader_subject), reader_version = VALUES(reader_version), updated_at = VALUES(updated_at)",
+		registryCtx, record.Subject, record.Consumer, record.ReaderSubject, record.ReaderVersion, now)
+	if err != nil {
+		return fmt.Errorf("failed to set reader assertion: %w", err)
+	}
+	record.UpdatedAt = now
+	return nil
+}
+
+// DeleteReaderAssertion removes a consumer's assertion for a subject.
+func (s *Store) DeleteReaderAssertion(ctx context.Context, registryCtx string, subject string, consumer string) error {
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM reader_assertions WHERE registry_ctx = ? AND subject = ? AND consumer = ?",
+		registryCtx, subject, consumer)
+	if err != nil {
+		return fmt.Errorf("failed to delete reader assertion: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrReaderAssertionNotFound
+	}
+	return nil
+}
+
+// ListReaderAssertions returns the reader assertions in a context, ordered by subject and consumer.
+func (s *Store) ListReaderAssertions(ctx context.Context, registryCtx string, subject string) ([]*storage.ReaderAssertionRecord, error) {
+	query := "SELECT subject, consumer, reader_subject, reader_version, updated_at FROM reader_assertions WHERE registry_ctx = ?"
+	args := []interface{}{registryCtx}
+	if subject != "" {
+		query += " AND subject = ?"
+		args = append(args, subject)
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY subject, consumer", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reader assertions: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*storage.ReaderAssertionRecord, 0)
+	for rows.Next() {
+		record := &storage.ReaderAssertionRecord{}
+		if err := rows.Scan(&record.Subject, &record.Consumer, &record.ReaderSubject, &record.ReaderVersion, &record.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// CreateTenant creates a new tenant.
+func (s *Store) CreateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO tenants (name, description, encrypted_key, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		tenant.Name, tenant.Description, tenant.EncryptedKey, now, now)
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrTenantExists
+		}
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+	tenant.CreatedAt = now
+	tenant.UpdatedAt = now
+	return nil
+}
+
+// GetTenant retrieves a tenant by name.
+func (s *Store) GetTenant(ctx context.Context, name string) (*storage.TenantRecord, error) {
+	tenant := &storage.TenantRecord{}
+	var description, key sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		"SELECT name, description, encrypted_key, created_at, updated_at FROM tenants WHERE name = ?", name).Scan(
+		&tenant.Name, &description, &key, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	tenant.Description = description.String
+	tenant.EncryptedKey = key.String
+	return tenant, nil
+}
+
+// UpdateTenant updates the description of an existing tenant.
+func (s *Store) UpdateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE tenants SET description = ?, updated_at = ? WHERE name = ?",
+		tenant.Description, time.Now(), tenant.Name)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+
+	// Read the row back: MySQL reports zero affected rows for a no-op update.
+	existing, err := s.GetTenant(ctx, tenant.Name)
+	if err != nil {
+		return err
+	}
+	*tenant = *existing
+	return nil
+}
+
+// DeleteTenant deletes a tenant by name.
+func (s *Store) DeleteTenant(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM tenants WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return storage.ErrTenantNotFound
+	}
+	return nil
+}
+
+// ListTenants returns all tenants ordered by name.
+func (s *Store) ListTenants(ctx context.Context) ([]*storage.TenantRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT name, description, encrypted_key, created_at, updated_at FROM tenants ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := make([]*storage.TenantRecord, 0)
+	for rows.Next() {
+		tenant := &storage.TenantRecord{}
+		var description, key sql.NullString
+		if err := rows.Scan(&tenant.Name, &description, &key, &tenant.CreatedAt, &tenant.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tenant.Description = description.String
+		tenant.EncryptedKey = key.String
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+// CreateUser creates a new user record.
+func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	var email sql.NullString
+	if user.Email != "" {
+		email = sql.NullString{String: user.Email, Valid: true}
+	}
+
+	history, err := marshalPasswordHistory(user.PasswordHistory)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.stmts.createUser.ExecContext(ctx,
+		user.Username, email, user.PasswordHash, user.Role, user.Enabled, user.CreatedAt, user.UpdatedAt, user.Tenant,
+		nullTime(user.PasswordChangedAt), user.MustChangePassword, history)
+
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrUserExists
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	user.ID = id
+
+	return nil
+}
+
+// GetUserByID retrieves a user by ID.
+func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
+	user := &storage.UserRecord{}
+	var email sql.NullString
+	var changedAt sql.NullTime
+	var history sql.NullString
+
+	err := s.stmts.getUserByID.QueryRowContext(ctx, id).Scan(
+		&user.ID, &user.Username, &email, &user.PasswordHash,
+		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt, &user.Tenant,
+		&changedAt, &user.MustChangePassword, &history)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if email.Valid {
+		user.Email = email.String
+	}
+	if err := setPasswordState(user, changedAt, history); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
+	user := &storage.UserRecord{}
+	var email sql.NullString
+	var changedAt sql.NullTime
+	var history sql.NullString
+
+	err := s.stmts.getUserByUsername.QueryRowContext(ctx, username).Scan(
+		&user.ID, &user.Username, &email, &user.PasswordHash,
+		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt, &user.Tenant,
+		&changedAt, &user.MustChangePassword, &history)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if email.Valid {
+		user.Email = email.String
+	}
+	if err := setPasswordState(user, changedAt, history); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUser updates an existing user record.
+func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
+	user.UpdatedAt = time.Now()
+
+	var email sql.NullString
+	if user.Email != "" {
+		email = sql.NullString{String: user.Email, Valid: true}
+	}
+
+	history, err := marshalPasswordHistory(user.PasswordHistory)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.stmts.updateUser.ExecContext(ctx,
+		user.Username, email, user.PasswordHash, user.Role, user.Enabled, user.UpdatedAt,
+		nullTime(user.PasswordChangedAt), user.MustChangePassword, history, user.ID)
+
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrUserExists
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a user by ID.
+func (s *Store) DeleteUser(ctx context.Context, id int64) error {
+	result, err := s.stmts.deleteUser.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ListUsers returns all users.
+func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
+	rows, err := s.stmts.listUsers.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*storage.UserRecord
+	for rows.Next() {
+		user := &storage.UserRecord{}
+		var email sql.NullString
+		var changedAt sql.NullTime
+		var history sql.NullString
+		if err := rows.Scan(&user.ID, &user.Username, &email, &user.PasswordHash,
+			&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt, &user.Tenant,
+			&changedAt, &user.MustChangePassword, &history); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if email.Valid {
+			user.Email = email.String
+		}
+		if err := setPasswordState(user, changedAt, history); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// marshalPasswordHistory encodes the password hashes of a user's history
+// for the password_history column.
+func marshalPasswordHistory(history []string) (string, error) {
+	if history == nil {
+		history = []string{}
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal password history: %w", err)
+	}
+	return string(data), nil
+}
+
+// marshalAPIKeyScopes encodes the scopes of an API key for the scopes
+// column.
+func marshalAPIKeyScopes(scopes []storage.APIKeyScope) (string, error) {
+	if scopes == nil {
+		scopes = []storage.APIKeyScope{}
+	}
+	data, err := json.Marshal(scopes)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal API key scopes: %w", err)
+	}
+	return string(data), nil
+}
+
+// marshalReviewers encodes the reviewers of a subject or pending schema for
+// their reviewers column.
+func marshalReviewers(reviewers []string) (string, error) {
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	data, err := json.Marshal(reviewers)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal reviewers: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalReviewers decodes a nullable reviewers column. No reviewers
+// decode to nil.
+func unmarshalReviewers(data sql.NullString) ([]string, error) {
+	if !data.Valid || data.String == "" || data.String == "[]" {
+		return nil, nil
+	}
+	var reviewers []string
+	if err := json.Unmarshal([]byte(data.String), &reviewers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reviewers: %w", err)
+	}
+	return reviewers, nil
+}
+
+// setAPIKeyScopes sets the scopes of an API key from its nullable column.
+func setAPIKeyScopes(key *storage.APIKeyRecord, scopes sql.NullString) error {
+	if !scopes.Valid || scopes.String == "" || scopes.String == "[]" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(scopes.String), &key.Scopes); err != nil {
+		return fmt.Errorf("failed to unmarshal API key scopes: %w", err)
+	}
+	return nil
+}
+
+// setPasswordState sets the password policy state of a user from its
+// nullable columns.
+func setPasswordState(user *storage.UserRecord, changedAt sql.NullTime, history sql.NullString) error {
+	if changedAt.Valid {
+		user.PasswordChangedAt = changedAt.Time
+	}
+	if history.Valid && history.String != "" {
+		if err := json.Unmarshal([]byte(history.String), &user.PasswordHistory); err != nil {
+			return fmt.Errorf("failed to unmarshal password history: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateAPIKey creates a new API key record.
+func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	key.CreatedAt = time.Now()
+	scopes, err := marshalAPIKeyScopes(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.stmts.createAPIKey.ExecContext(ctx,
+		key.UserID, key.KeyHash, key.KeyPrefix, key.Name, key.Role, key.Enabled, key.CreatedAt, key.ExpiresAt, scopes)
+
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrAPIKeyExists
+		}
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	key.ID = id
+
+	return nil
+}
+
+// GetAPIKeyByID retrieves an API key by ID.
+func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
+	key := &storage.APIKeyRecord{}
+	var userID sql.NullInt64
+	var expiresAt, lastUsed sql.NullTime
+	var scopes sql.NullString
+
+	err := s.stmts.getAPIKeyByID.QueryRowContext(ctx, id).Scan(
+		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
+		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if userID.Valid {
+		key.UserID = userID.Int64
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = expiresAt.Time
+	}
+	if lastUsed.Valid {
+		key.LastUsed = &lastUsed.Time
+	}
+	if err := setAPIKeyScopes(key, scopes); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByHash retrieves an API key by its hash.
+func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
+	key := &storage.APIKeyRecord{}
+	var userID sql.NullInt64
+	var expiresAt, lastUsed sql.NullTime
+	var scopes sql.NullString
+
+	err := s.stmts.getAPIKeyByHash.QueryRowContext(ctx, keyHash).Scan(
+		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
+		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if userID.Valid {
+		key.UserID = userID.Int64
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = expiresAt.Time
+	}
+	if lastUsed.Valid {
+		key.LastUsed = &lastUsed.Time
+	}
+	if err := setAPIKeyScopes(key, scopes); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// UpdateAPIKey updates an existing API key record.
+func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	scopes, err := marshalAPIKeyScopes(key.Scopes)
+	if err != nil {
+		return err
+	}
+	result, err := s.stmts.updateAPIKey.ExecContext(ctx,
+		key.UserID, key.KeyHash, key.Name, key.Role, key.Enabled, key.ExpiresAt, scopes, key.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// DeleteAPIKey deletes an API key by ID.
+func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
+	result, err := s.stmts.deleteAPIKey.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// ListAPIKeys returns all API keys.
+func (s *Store) ListAPIKeys(ctx context.Context) ([]*storage.APIKeyRecord, error) {
+	rows, err := s.stmts.listAPIKeys.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanAPIKeys(rows)
+}
+
+// ListAPIKeysByUserID returns all API keys for a user.
+func (s *Store) ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*storage.APIKeyRecord, error) {
+	rows, err := s.stmts.listAPIKeysByUserID.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanAPIKeys(rows)
+}
+
+// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
+func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
+	key := &storage.APIKeyRecord{}
+	var keyUserID sql.NullInt64
+	var expiresAt, lastUsed sql.NullTime
+	var scopes sql.NullString
+
+	err := s.stmts.getAPIKeyByUserAndName.QueryRowContext(ctx, userID, name).Scan(
+		&key.ID, &keyUserID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
+		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if keyUserID.Valid {
+		key.UserID = keyUserID.Int64
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = expiresAt.Time
+	}
+	if lastUsed.Valid {
+		key.LastUsed = &lastUsed.Time
+	}
+	if err := setAPIKeyScopes(key, scopes); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// UpdateAPIKeyLastUsed updates the last_used timestamp for an API key.
+func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
+	result, err := s.stmts.updateAPIKeyLastUsed.ExecContext(ctx, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update API key last used: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// AddAPIKeyUsage adds request counts to the API key usage buckets and moves
+// the keys' last-used times forward.
+func (s *Store) AddAPIKeyUsage(ctx context.Context, records []*storage.APIKeyUsageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, rec := range records {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO api_key_usage (usage_day, api_key_id, request_count) "+
+				"SELECT ?, id, ? FROM api_keys WHERE id = ? "+
+				"ON DUPLICATE KEY UPDATE request_count = request_count + VALUES(request_count)",
+			usageDay(rec.Day).Format("2006-01-02"), rec.Requests, rec.APIKeyID); err != nil {
+			return fmt.Errorf("failed to add API key usage: %w", err)
+		}
+		if rec.LastUsed.IsZero() {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE api_keys SET last_used = ? WHERE id = ? AND (last_used IS NULL OR last_used < ?)",
+			rec.LastUsed, rec.APIKeyID, rec.LastUsed); err != nil {
+			return fmt.Errorf("failed to update API key last used: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListAPIKeyUsage returns the API key usage buckets for days on or after
+// since.
+func (s *Store) ListAPIKeyUsage(ctx context.Context, since time.Time) ([]*storage.APIKeyUsageRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT usage_day, api_key_id, request_count FROM api_key_usage WHERE usage_day >= ?",
+		usageDay(since).Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API key usage: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*storage.APIKeyUsageRecord{}
+	for rows.Next() {
+		rec := &storage.APIKeyUsageRecord{}
+		if err := rows.Scan(&rec.Day, &rec.APIKeyID, &rec.Requests); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rec.Day = usageDay(rec.Day)
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// CreateGrant creates a new role grant.
+func (s *Store) CreateGrant(ctx context.Context, grant *storage.GrantRecord) error {
+	grant.CreatedAt = time.Now()
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO role_grants (username, api_key_id, role, registry_ctx, subject_prefix, created_by, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		sql.NullString{String: grant.Username, Valid: grant.Username != ""},
+		sql.NullInt64{Int64: grant.APIKeyID, Valid: grant.APIKeyID != 0},
+		grant.Role, grant.Context, grant.SubjectPrefix,
+		sql.NullString{String: grant.CreatedBy, Valid: grant.CreatedBy != ""},
+		grant.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create grant: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	grant.ID = id
+
+	return nil
+}
+
+// GetGrantByID retrieves a role grant by ID.
+func (s *Store) GetGrantByID(ctx context.Context, id int64) (*storage.GrantRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+grantColumns+` FROM role_grants WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grant: %w", err)
+	}
+	defer rows.Close()
+
+	grants, err := scanGrants(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return nil, storage.ErrGrantNotFound
+	}
+	return grants[0], nil
+}
+
+// DeleteGrant deletes a role grant by ID.
+func (s *Store) DeleteGrant(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM role_grants WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete grant: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrGrantNotFound
+	}
+
+	return nil
+}
+
+// ListGrants returns all role grants.
+func (s *Store) ListGrants(ctx context.Context) ([]*storage.GrantRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+grantColumns+` FROM role_grants ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query grants: %w", err)
+	}
+	defer rows.Close()
+
+	return scanGrants(rows)
+}
+
+const grantColumns = `id, username, api_key_id, role, registry_ctx, subject_prefix, created_by, created_at`
+
+// scanGrants scans rows into grant records.
+func scanGrants(rows *sql.Rows) ([]*storage.GrantRecord, error) {
+	grants := []*storage.GrantRecord{}
+	for rows.Next() {
+		grant := &storage.GrantRecord{}
+		var username, createdBy sql.NullString
+		var apiKeyID sql.NullInt64
+		if err := rows.Scan(&grant.ID, &username, &apiKeyID, &grant.Role, &grant.Context,
+			&grant.SubjectPrefix, &createdBy, &grant.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		grant.Username = username.String
+		grant.APIKeyID = apiKeyID.Int64
+		grant.CreatedBy = createdBy.String
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate grants: %w", err)
+	}
+	return grants, nil
+}
+
+// CreateShareToken creates a new share token.
+func (s *Store) CreateShareToken(ctx context.Context, token *storage.ShareTokenRecord) error {
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO share_tokens (token_hash, token_prefix, name, registry_ctx, subject, created_by, created_at, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		token.TokenHash, token.TokenPrefix, token.Name, token.Context, token.Subject,
+		token.CreatedBy, token.CreatedAt, token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create share token: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	token.ID = id
+
+	return nil
+}
+
+// GetShareTokenByID retrieves a share token by ID.
+func (s *Store) GetShareTokenByID(ctx context.Context, id int64) (*storage.ShareTokenRecord, error) {
+	return s.getShareToken(ctx, `SELECT `+shareTokenColumns+` FROM share_tokens WHERE id = ?`, id)
+}
+
+// GetShareTokenByHash retrieves a share token by the hash of its value.
+func (s *Store) GetShareTokenByHash(ctx context.Context, tokenHash string) (*storage.ShareTokenRecord, error) {
+	return s.getShareToken(ctx, `SELECT `+shareTokenColumns+` FROM share_tokens WHERE token_hash = ?`, tokenHash)
+}
+
+func (s *Store) getShareToken(ctx context.Context, query string, arg any) (*storage.ShareTokenRecord, error) {
+	rows, err := s.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share token: %w", err)
+	}
+	defer rows.Close()
+
+	tokens, err := scanShareTokens(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, storage.ErrShareTokenNotFound
+	}
+	return tokens[0], nil
+}
+
+// DeleteShareToken deletes a share token by ID.
+func (s *Store) DeleteShareToken(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM share_tokens WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete share token: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrShareTokenNotFound
+	}
+
+	return nil
+}
+
+// ListShareTokens returns all share tokens.
+func (s *Store) ListShareTokens(ctx context.Context) ([]*storage.ShareTokenRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+shareTokenColumns+` FROM share_tokens ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query share tokens: %w", err)
+	}
+	defer rows.Close()
+
+	return scanShareTokens(rows)
+}
+
+const shareTokenColumns = `id, token_hash, token_prefix, name, registry_ctx, subject, created_by, created_at, expires_at`
+
+// scanShareTokens scans rows into share token records.
+func scanShareTokens(rows *sql.Rows) ([]*storage.ShareTokenRecord, error) {
+	tokens := []*storage.ShareTokenRecord{}
+	for rows.Next() {
+		token := &storage.ShareTokenRecord{}
+		if err := rows.Scan(&token.ID, &token.TokenHash, &token.TokenPrefix, &token.Name, &token.Context,
+			&token.Subject, &token.CreatedBy, &token.CreatedAt, &token.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate share tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// CreateSession creates a new login session.
+func (s *Store) CreateSession(ctx context.Context, session *storage.SessionRecord) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO sessions (id, refresh_hash, user_id, api_key_id, username, role, tenant, auth_method, created_at, refreshed_at, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.ID, session.RefreshHash, session.UserID, session.APIKeyID, session.Username, session.Role,
+		session.Tenant, session.AuthMethod, session.CreatedAt, session.RefreshedAt, session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetSession retrieves a login session by ID.
+func (s *Store) GetSession(ctx context.Context, id string) (*storage.SessionRecord, error) {
+	session := &storage.SessionRecord{}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, refresh_hash, user_id, api_key_id, username, role, tenant, auth_method, created_at, refreshed_at, expires_at
+		 FROM sessions WHERE id = ?`, id,
+	).Scan(&session.ID, &session.RefreshHash, &session.UserID, &session.APIKeyID, &session.Username, &session.Role,
+		&session.Tenant, &session.AuthMethod, &session.CreatedAt, &session.RefreshedAt, &session.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// RefreshSession stores a session's new refresh token hash if its current
+// one is oldRefreshHash.
+func (s *Store) RefreshSession(ctx context.Context, session *storage.SessionRecord, oldRefreshHash string) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE sessions SET refresh_hash = ?, role = ?, tenant = ?, refreshed_at = ?, expires_at = ?
+		 WHERE id = ? AND refresh_hash = ?`,
+		session.RefreshHash, session.Role, session.Tenant, session.RefreshedAt, session.ExpiresAt, session.ID, oldRefreshHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to refresh session: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// DeleteSession deletes a login session by ID.
+func (s *Store) DeleteSession(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// DeleteUserSessions deletes every login session of a user.
+func (s *Store) DeleteUserSessions(ctx context.Context, userID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions deletes the login sessions that expired before the
+// given time.
+func (s *Store) DeleteExpiredSessions(ctx context.Context, before time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < ?`, before); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return nil
+}
+
+// CreateJob creates a new async job.
+func (s *Store) CreateJob(ctx context.Context, job *storage.JobRecord) error {
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO jobs (id, type, state, registry_ctx, params, result, error, message, done, total, created_by, created_at, updated_at, started_at, finished_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		job.ID, job.Type, job.State, job.Context, job.Params, job.Result, job.Error, job.Message,
+		job.Done, job.Total, job.CreatedBy, now, now, nullTime(job.StartedAt), nullTime(job.FinishedAt))
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrJobExists
+		}
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	return nil
+}
+
+// GetJob retrieves an async job by ID.
+func (s *Store) GetJob(ctx context.Context, id string) (*storage.JobRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	defer rows.Close()
+
+	jobs, err := scanJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, storage.ErrJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// UpdateJob updates an async job's state, progress and result.
+func (s *Store) UpdateJob(ctx context.Context, job *storage.JobRecord) error {
+	if _, err := s.GetJob(ctx, job.ID); err != nil {
+		// RowsAffected is 0 for unchanged rows in MySQL, so check existence first.
+		return err
+	}
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE jobs SET state = ?, result = ?, error = ?, message = ?, done = ?, total = ?, updated_at = ?, started_at = ?, finished_at = ? WHERE id = ?",
+		job.State, job.Result, job.Error, job.Message, job.Done, job.Total,
+		now, nullTime(job.StartedAt), nullTime(job.FinishedAt), job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+
+	job.UpdatedAt = now
+	return nil
+}
+
+// RequestJobCancel marks an async job for cancellation.
+func (s *Store) RequestJobCancel(ctx context.Context, id string) error {
+	if _, err := s.GetJob(ctx, id); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, "UPDATE jobs SET cancel_requested = TRUE WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+	return nil
+}
 
-	var email sql.NullString
-	if user.Email != "" {
-		email = sql.NullString{String: user.Email, Valid: true}
+// ListJobs returns all async jobs, newest first.
+func (s *Store) ListJobs(ctx context.Context) ([]*storage.JobRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+jobColumns+" FROM jobs ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
 	}
+	defer rows.Close()
 
-	result, err := s.stmts.updateUser.ExecContext(ctx,
-		user.Username, email, user.PasswordHash, user.Role, user.Enabled, user.UpdatedAt, user.ID)
+	return scanJobs(rows)
+}
 
+// DeleteJob deletes an async job.
+func (s *Store) DeleteJob(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM jobs WHERE id = ?", id)
 	if err != nil {
-		if isMySQLDuplicateError(err) {
-			return storage.ErrUserExists
-		}
-		return fmt.Errorf("failed to update user: %w", err)
+		return fmt.Errorf("failed to delete job: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return storage.ErrUserNotFound
+		return storage.ErrJobNotFound
+	}
+	return nil
+}
+
+// CreateImportSession creates a new import session.
+func (s *Store) CreateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
+	subjectsJSON, modesJSON, err := marshalImportSession(session)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO import_sessions (id, registry_ctx, subjects, state, modes, `schemas`, staged, created_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		session.ID, session.Context, subjectsJSON, session.State, modesJSON, session.Schemas, session.Staged, session.CreatedBy, now, now)
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrImportSessionExists
+		}
+		return fmt.Errorf("failed to create import session: %w", err)
 	}
 
+	session.CreatedAt = now
+	session.UpdatedAt = now
 	return nil
 }
 
-// DeleteUser deletes a user by ID.
-func (s *Store) DeleteUser(ctx context.Context, id int64) error {
-	result, err := s.stmts.deleteUser.ExecContext(ctx, id)
+// GetImportSession retrieves an import session by ID.
+func (s *Store) GetImportSession(ctx context.Context, id string) (*storage.ImportSessionRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+importSessionColumns+" FROM import_sessions WHERE id = ?", id)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return nil, fmt.Errorf("failed to get import session: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrUserNotFound
+	sessions, err := scanImportSessions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, storage.ErrImportSessionNotFound
+	}
+	return sessions[0], nil
+}
+
+// UpdateImportSession updates an import session's state and staged schemas.
+func (s *Store) UpdateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
+	if _, err := s.GetImportSession(ctx, session.ID); err != nil {
+		// RowsAffected is 0 for unchanged rows in MySQL, so check existence first.
+		return err
+	}
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE import_sessions SET state = ?, `schemas` = ?, staged = ?, updated_at = ? WHERE id = ?",
+		session.State, session.Schemas, session.Staged, now, session.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update import session: %w", err)
 	}
 
+	session.UpdatedAt = now
 	return nil
 }
 
-// ListUsers returns all users.
-func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
-	rows, err := s.stmts.listUsers.QueryContext(ctx)
+// ListImportSessions returns all import sessions, newest first.
+func (s *Store) ListImportSessions(ctx context.Context) ([]*storage.ImportSessionRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+importSessionColumns+" FROM import_sessions ORDER BY created_at DESC, id DESC")
 	if err != nil {
-		return nil, fmt.Errorf("failed to query users: %w", err)
+		return nil, fmt.Errorf("failed to query import sessions: %w", err)
 	}
 	defer rows.Close()
 
-	var users []*storage.UserRecord
+	return scanImportSessions(rows)
+}
+
+const importSessionColumns = "id, registry_ctx, subjects, state, modes, `schemas`, staged, created_by, created_at, updated_at"
+
+// scanImportSessions scans rows into import session records.
+func scanImportSessions(rows *sql.Rows) ([]*storage.ImportSessionRecord, error) {
+	sessions := []*storage.ImportSessionRecord{}
 	for rows.Next() {
-		user := &storage.UserRecord{}
-		var email sql.NullString
-		if err := rows.Scan(&user.ID, &user.Username, &email, &user.PasswordHash,
-			&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		session := &storage.ImportSessionRecord{}
+		var subjectsJSON, modesJSON []byte
+		if err := rows.Scan(&session.ID, &session.Context, &subjectsJSON, &session.State, &modesJSON,
+			&session.Schemas, &session.Staged, &session.CreatedBy, &session.CreatedAt, &session.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-		if email.Valid {
-			user.Email = email.String
+		if err := unmarshalImportSession(session, subjectsJSON, modesJSON); err != nil {
+			return nil, err
 		}
-		users = append(users, user)
+		sessions = append(sessions, session)
 	}
-
-	return users, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate import sessions: %w", err)
+	}
+	return sessions, nil
 }
 
-// CreateAPIKey creates a new API key record.
-func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
-	key.CreatedAt = time.Now()
-
-	result, err := s.stmts.createAPIKey.ExecContext(ctx,
-		key.UserID, key.KeyHash, key.KeyPrefix, key.Name, key.Role, key.Enabled, key.CreatedAt, key.ExpiresAt)
-
+// marshalImportSession encodes a session's subjects and modes for the JSON columns.
+func marshalImportSession(session *storage.ImportSessionRecord) (subjectsJSON, modesJSON string, err error) {
+	subjects := session.Subjects
+	if subjects == nil {
+		subjects = []string{}
+	}
+	modes := session.Modes
+	if modes == nil {
+		modes = map[string]string{}
+	}
+	sj, err := json.Marshal(subjects)
 	if err != nil {
-		if isMySQLDuplicateError(err) {
-			return storage.ErrAPIKeyExists
-		}
-		return fmt.Errorf("failed to create API key: %w", err)
+		return "", "", fmt.Errorf("failed to marshal import session subjects: %w", err)
 	}
-
-	id, err := result.LastInsertId()
+	mj, err := json.Marshal(modes)
 	if err != nil {
-		return fmt.Errorf("failed to get last insert ID: %w", err)
+		return "", "", fmt.Errorf("failed to marshal import session modes: %w", err)
 	}
-	key.ID = id
+	return string(sj), string(mj), nil
+}
 
+// unmarshalImportSession decodes the JSON columns into a session.
+func unmarshalImportSession(session *storage.ImportSessionRecord, subjectsJSON, modesJSON []byte) error {
+	if err := json.Unmarshal(subjectsJSON, &session.Subjects); err != nil {
+		return fmt.Errorf("failed to unmarshal import session subjects: %w", err)
+	}
+	if err := json.Unmarshal(modesJSON, &session.Modes); err != nil {
+		return fmt.Errorf("failed to unmarshal import session modes: %w", err)
+	}
 	return nil
 }
 
-// GetAPIKeyByID retrieves an API key by ID.
-func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
-	key := &storage.APIKeyRecord{}
-	var userID sql.NullInt64
-	var expiresAt, lastUsed sql.NullTime
+// CreateMaintenanceWindow creates a new maintenance window.
+func (s *Store) CreateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO maintenance_windows (id, starts_at, ends_at, reason, cancelled, created_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		window.ID, window.StartsAt, window.EndsAt, window.Reason, window.Cancelled, window.CreatedBy, now, now)
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrMaintenanceExists
+		}
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
 
-	err := s.stmts.getAPIKeyByID.QueryRowContext(ctx, id).Scan(
-		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
-		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed)
+	window.CreatedAt = now
+	window.UpdatedAt = now
+	return nil
+}
 
-	if err == sql.ErrNoRows {
-		return nil, storage.ErrAPIKeyNotFound
-	}
+// GetMaintenanceWindow retrieves a maintenance window by ID.
+func (s *Store) GetMaintenanceWindow(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+maintenanceColumns+" FROM maintenance_windows WHERE id = ?", id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get API key: %w", err)
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
 	}
+	defer rows.Close()
 
-	if userID.Valid {
-		key.UserID = userID.Int64
-	}
-	if expiresAt.Valid {
-		key.ExpiresAt = expiresAt.Time
+	windows, err := scanMaintenanceWindows(rows)
+	if err != nil {
+		return nil, err
 	}
-	if lastUsed.Valid {
-		key.LastUsed = &lastUsed.Time
+	if len(windows) == 0 {
+		return nil, storage.ErrMaintenanceNotFound
 	}
-
-	return key, nil
+	return windows[0], nil
 }
 
-// GetAPIKeyByHash retrieves an API key by its hash.
-func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
-	key := &storage.APIKeyRecord{}
-	var userID sql.NullInt64
-	var expiresAt, lastUsed sql.NullTime
-
-	err := s.stmts.getAPIKeyByHash.QueryRowContext(ctx, keyHash).Scan(
-		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
-		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed)
-
-	if err == sql.ErrNoRows {
-		return nil, storage.ErrAPIKeyNotFound
+// UpdateMaintenanceWindow updates a maintenance window's end time and cancellation.
+func (s *Store) UpdateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	if _, err := s.GetMaintenanceWindow(ctx, window.ID); err != nil {
+		// RowsAffected is 0 for unchanged rows in MySQL, so check existence first.
+		return err
 	}
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE maintenance_windows SET ends_at = ?, cancelled = ?, updated_at = ? WHERE id = ?",
+		window.EndsAt, window.Cancelled, now, window.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get API key: %w", err)
+		return fmt.Errorf("failed to update maintenance window: %w", err)
 	}
 
-	if userID.Valid {
-		key.UserID = userID.Int64
-	}
-	if expiresAt.Valid {
-		key.ExpiresAt = expiresAt.Time
-	}
-	if lastUsed.Valid {
-		key.LastUsed = &lastUsed.Time
+	window.UpdatedAt = now
+	return nil
+}
+
+// ListMaintenanceWindows returns all maintenance windows ordered by start time.
+func (s *Store) ListMaintenanceWindows(ctx context.Context) ([]*storage.MaintenanceWindowRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+maintenanceColumns+" FROM maintenance_windows ORDER BY starts_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
 	}
+	defer rows.Close()
 
-	return key, nil
+	return scanMaintenanceWindows(rows)
 }
 
-// UpdateAPIKey updates an existing API key record.
-func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
-	result, err := s.stmts.updateAPIKey.ExecContext(ctx,
-		key.UserID, key.KeyHash, key.Name, key.Role, key.Enabled, key.ExpiresAt, key.ID)
+const maintenanceColumns = "id, starts_at, ends_at, reason, cancelled, created_by, created_at, updated_at"
 
-	if err != nil {
-		return fmt.Errorf("failed to update API key: %w", err)
+// scanMaintenanceWindows scans rows into maintenance window records.
+func scanMaintenanceWindows(rows *sql.Rows) ([]*storage.MaintenanceWindowRecord, error) {
+	windows := []*storage.MaintenanceWindowRecord{}
+	for rows.Next() {
+		w := &storage.MaintenanceWindowRecord{}
+		if err := rows.Scan(&w.ID, &w.StartsAt, &w.EndsAt, &w.Reason, &w.Cancelled,
+			&w.CreatedBy, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		windows = append(windows, w)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate maintenance windows: %w", err)
+	}
+	return windows, nil
+}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrAPIKeyNotFound
+// CreatePendingSchema creates a new pending schema.
+func (s *Store) CreatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
+	reviewers, err := marshalReviewers(pending.Reviewers)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO pending_schemas ("+pendingSchemaColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		pending.ID, pending.Context, pending.Subject, pending.State, pending.Request, pending.SubmittedBy, reviewers,
+		pending.ReviewedBy, pending.Reason, pending.SchemaID, pending.Version, now, now)
+	if err != nil {
+		if isMySQLDuplicateError(err) {
+			return storage.ErrPendingSchemaExists
+		}
+		return fmt.Errorf("failed to create pending schema: %w", err)
 	}
 
+	pending.CreatedAt = now
+	pending.UpdatedAt = now
 	return nil
 }
 
-// DeleteAPIKey deletes an API key by ID.
-func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
-	result, err := s.stmts.deleteAPIKey.ExecContext(ctx, id)
+// GetPendingSchema retrieves a pending schema by ID.
+func (s *Store) GetPendingSchema(ctx context.Context, id string) (*storage.PendingSchemaRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+pendingSchemaColumns+" FROM pending_schemas WHERE id = ?", id)
 	if err != nil {
-		return fmt.Errorf("failed to delete API key: %w", err)
+		return nil, fmt.Errorf("failed to get pending schema: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrAPIKeyNotFound
+	pending, err := scanPendingSchemas(rows)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	if len(pending) == 0 {
+		return nil, storage.ErrPendingSchemaNotFound
+	}
+	return pending[0], nil
 }
 
-// ListAPIKeys returns all API keys.
-func (s *Store) ListAPIKeys(ctx context.Context) ([]*storage.APIKeyRecord, error) {
-	rows, err := s.stmts.listAPIKeys.QueryContext(ctx)
+// UpdatePendingSchema updates a pending schema's state, review and registration.
+func (s *Store) UpdatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
+	if _, err := s.GetPendingSchema(ctx, pending.ID); err != nil {
+		// RowsAffected is 0 for unchanged rows in MySQL, so check existence first.
+		return err
+	}
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE pending_schemas SET state = ?, reviewed_by = ?, reason = ?, schema_id = ?, version = ?, updated_at = ? WHERE id = ?",
+		pending.State, pending.ReviewedBy, pending.Reason, pending.SchemaID, pending.Version, now, pending.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query API keys: %w", err)
+		return fmt.Errorf("failed to update pending schema: %w", err)
 	}
-	defer rows.Close()
 
-	return s.scanAPIKeys(rows)
+	pending.UpdatedAt = now
+	return nil
 }
 
-// ListAPIKeysByUserID returns all API keys for a user.
-func (s *Store) ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*storage.APIKeyRecord, error) {
-	rows, err := s.stmts.listAPIKeysByUserID.QueryContext(ctx, userID)
+// ListPendingSchemas returns all pending schemas, newest first.
+func (s *Store) ListPendingSchemas(ctx context.Context) ([]*storage.PendingSchemaRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+pendingSchemaColumns+" FROM pending_schemas ORDER BY created_at DESC, id DESC")
 	if err != nil {
-		return nil, fmt.Errorf("failed to query API keys: %w", err)
+		return nil, fmt.Errorf("failed to query pending schemas: %w", err)
 	}
 	defer rows.Close()
 
-	return s.scanAPIKeys(rows)
+	return scanPendingSchemas(rows)
 }
 
-// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
-func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
-	key := &storage.APIKeyRecord{}
-	var keyUserID sql.NullInt64
-	var expiresAt, lastUsed sql.NullTime
+const pendingSchemaColumns = "id, registry_ctx, subject, state, request, submitted_by, reviewers, reviewed_by, reason, schema_id, version, created_at, updated_at"
 
-	err := s.stmts.getAPIKeyByUserAndName.QueryRowContext(ctx, userID, name).Scan(
-		&key.ID, &keyUserID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
-		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed)
+// scanPendingSchemas scans rows into pending schema records.
+func scanPendingSchemas(rows *sql.Rows) ([]*storage.PendingSchemaRecord, error) {
+	out := []*storage.PendingSchemaRecord{}
+	for rows.Next() {
+		p := &storage.PendingSchemaRecord{}
+		var reviewers sql.NullString
+		if err := rows.Scan(&p.ID, &p.Context, &p.Subject, &p.State, &p.Request, &p.SubmittedBy, &reviewers,
+			&p.ReviewedBy, &p.Reason, &p.SchemaID, &p.Version, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		var err error
+		if p.Reviewers, err = unmarshalReviewers(reviewers); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pending schemas: %w", err)
+	}
+	return out, nil
+}
 
-	if err == sql.ErrNoRows {
-		return nil, storage.ErrAPIKeyNotFound
+// AddSchemaUsage adds fetch counts to the schema usage buckets.
+func (s *Store) AddSchemaUsage(ctx context.Context, records []*storage.SchemaUsageRecord) error {
+	if len(records) == 0 {
+		return nil
 	}
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get API key: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	if keyUserID.Valid {
-		key.UserID = keyUserID.Int64
+	for _, rec := range records {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_usage (usage_day, registry_ctx, schema_id, subject, version, fetch_count) "+
+				"VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE fetch_count = fetch_count + VALUES(fetch_count)",
+			usageDay(rec.Day).Format("2006-01-02"), rec.Context, rec.SchemaID, rec.Subject, rec.Version, rec.Count); err != nil {
+			return fmt.Errorf("failed to add schema usage: %w", err)
+		}
 	}
-	if expiresAt.Valid {
-		key.ExpiresAt = expiresAt.Time
+	return tx.Commit()
+}
+
+// ListSchemaUsage returns the schema usage buckets for days on or after since.
+func (s *Store) ListSchemaUsage(ctx context.Context, since time.Time) ([]*storage.SchemaUsageRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT usage_day, registry_ctx, schema_id, subject, version, fetch_count "+
+			"FROM schema_usage WHERE usage_day >= ?", usageDay(since).Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema usage: %w", err)
 	}
-	if lastUsed.Valid {
-		key.LastUsed = &lastUsed.Time
+	defer rows.Close()
+
+	records := []*storage.SchemaUsageRecord{}
+	for rows.Next() {
+		rec := &storage.SchemaUsageRecord{}
+		if err := rows.Scan(&rec.Day, &rec.Context, &rec.SchemaID, &rec.Subject, &rec.Version, &rec.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rec.Day = usageDay(rec.Day)
+		records = append(records, rec)
 	}
+	return records, rows.Err()
+}
 
-	return key, nil
+// usageDay returns midnight UTC of the day containing t.
+func usageDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
-// UpdateAPIKeyLastUsed updates the last_used timestamp for an API key.
-func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
-	result, err := s.stmts.updateAPIKeyLastUsed.ExecContext(ctx, time.Now(), id)
-	if err != nil {
-		return fmt.Errorf("failed to update API key last used: %w", err)
-	}
+const jobColumns = "id, type, state, registry_ctx, params, result, error, message, done, total, cancel_requested, created_by, created_at, updated_at, started_at, finished_at"
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrAPIKeyNotFound
+// scanJobs scans rows into job records.
+func scanJobs(rows *sql.Rows) ([]*storage.JobRecord, error) {
+	jobs := []*storage.JobRecord{}
+	for rows.Next() {
+		job := &storage.JobRecord{}
+		var startedAt, finishedAt sql.NullTime
+		if err := rows.Scan(&job.ID, &job.Type, &job.State, &job.Context, &job.Params, &job.Result,
+			&job.Error, &job.Message, &job.Done, &job.Total, &job.CancelRequested, &job.CreatedBy,
+			&job.CreatedAt, &job.UpdatedAt, &startedAt, &finishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		job.StartedAt = startedAt.Time
+		job.FinishedAt = finishedAt.Time
+		jobs = append(jobs, job)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+	return jobs, nil
+}
 
-	return nil
+// nullTime maps a zero time to SQL NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
 }
 
 // scanAPIKeys scans rows into API key records.
@@ -2221,8 +4158,9 @@ func (s *Store) scanAPIKeys(rows *sql.Rows) ([]*storage.APIKeyRecord, error) {
 		key := &storage.APIKeyRecord{}
 		var userID sql.NullInt64
 		var expiresAt, lastUsed sql.NullTime
+		var scopes sql.NullString
 		if err := rows.Scan(&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name,
-			&key.Role, &key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed); err != nil {
+			&key.Role, &key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		if userID.Valid {
@@ -2234,6 +4172,9 @@ func (s *Store) scanAPIKeys(rows *sql.Rows) ([]*storage.APIKeyRecord, error) {
 		if lastUsed.Valid {
 			key.LastUsed = &lastUsed.Time
 		}
+		if err := setAPIKeyScopes(key, scopes); err != nil {
+			return nil, err
+		}
 		keys = append(keys, key)
 	}
 	return keys, nil
@@ -2334,8 +4275,8 @@ func isMySQLDeadlock(err error) bool {
 }
 
 // withDeadlockRetry retries an operation on MySQL deadlock (error 1213) with
-// exponential backoff + jitter. Uses the same retry parameters as CreateSchema.
-func (s *Store) withDeadlockRetry(fn func() error) error {
+// the same backoff and number of retries as CreateSchema.
+func (s *Store) withDeadlockRetry(ctx context.Context, fn func() error) error {
 	var lastErr error
 	for attempt := 0; attempt < s.config.SchemaMaxRetries; attempt++ {
 		err := fn()
@@ -2346,16 +4287,29 @@ func (s *Store) withDeadlockRetry(fn func() error) error {
 			return err
 		}
 		lastErr = err
-		backoff := time.Duration(5<<attempt) * time.Millisecond
-		if backoff > 500*time.Millisecond {
-			backoff = 500 * time.Millisecond
+		if err := retryBackoff(ctx, attempt); err != nil {
+			return err
 		}
-		jitter := time.Duration(float64(backoff) * (0.5 * float64(time.Now().UnixNano()%100) / 100))
-		time.Sleep(backoff + jitter)
 	}
 	return fmt.Errorf("operation failed after %d retries due to deadlock: %w", s.config.SchemaMaxRetries, lastErr)
 }
 
+// retryBackoff waits before retrying a write that conflicted with a
+// concurrent one: 5ms, 10ms, 20ms, ... up to 500ms, plus up to 50% random
+// jitter so that retrying writers spread out. It returns ctx's error if ctx
+// is done first.
+func retryBackoff(ctx context.Context, attempt int) error {
+	backoff := min(5*time.Millisecond<<min(attempt, 7), 500*time.Millisecond)
+	timer := time.NewTimer(backoff + time.Duration(rand.Int64N(int64(backoff/2)+1)))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // contains checks if s contains substr.
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
@@ -1,8 +1,18 @@
 package mysql
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -137,6 +147,128 @@ func TestConfig_DSN_SpecialCharsInPassword(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// driverConfig
+// ---------------------------------------------------------------------------
+
+// writeTestCert writes a self-signed certificate and its key to dir.
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mysql-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestConfig_DriverConfig_DSNOnly(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TLS = "true"
+	driverCfg, err := cfg.driverConfig()
+	if err != nil {
+		t.Fatalf("driverConfig: %v", err)
+	}
+	if driverCfg.TLSConfig != "true" || driverCfg.AllowCleartextPasswords {
+		t.Errorf("expected the DSN settings only, got TLSConfig=%q AllowCleartextPasswords=%v",
+			driverCfg.TLSConfig, driverCfg.AllowCleartextPasswords)
+	}
+}
+
+func TestConfig_DriverConfig_TLSFiles(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+	cfg := DefaultConfig()
+	cfg.Host = "10.0.0.5"
+	cfg.TLS = "true"
+	cfg.TLSCAFile = certFile
+	cfg.TLSCertFile = certFile
+	cfg.TLSKeyFile = keyFile
+	cfg.TLSServerName = "db.internal"
+
+	driverCfg, err := cfg.driverConfig()
+	if err != nil {
+		t.Fatalf("driverConfig: %v", err)
+	}
+	tlsCfg := driverCfg.TLS
+	if tlsCfg == nil {
+		t.Fatal("expected a custom TLS config")
+	}
+	if tlsCfg.ServerName != "db.internal" {
+		t.Errorf("expected ServerName db.internal, got %q", tlsCfg.ServerName)
+	}
+	if tlsCfg.RootCAs == nil || len(tlsCfg.Certificates) != 1 {
+		t.Errorf("expected the CA pool and client certificate to be loaded")
+	}
+	if tlsCfg.InsecureSkipVerify {
+		t.Error("expected the server certificate to be verified with tls=true")
+	}
+}
+
+func TestConfig_DriverConfig_Errors(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+	provider := func(context.Context) (string, error) { return "token", nil }
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"files without tls", Config{TLS: "false", TLSCAFile: certFile}, "require tls"},
+		{"cert without key", Config{TLS: "true", TLSCertFile: certFile}, "set together"},
+		{"key without cert", Config{TLS: "true", TLSKeyFile: keyFile}, "set together"},
+		{"missing CA file", Config{TLS: "true", TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")}, "CA file"},
+		{"CA file without certificates", Config{TLS: "true", TLSCAFile: keyFile}, "no certificates"},
+		{"password provider without tls", Config{TLS: "false", PasswordProvider: provider}, "requires TLS"},
+		{"password provider with preferred tls", Config{TLS: "preferred", PasswordProvider: provider}, "requires TLS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Host, tt.cfg.Port, tt.cfg.Database = "localhost", 3306, "db"
+			_, err := tt.cfg.driverConfig()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestConfig_DriverConfig_PasswordProvider(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TLS = "true"
+	cfg.PasswordProvider = func(context.Context) (string, error) { return "token", nil }
+
+	driverCfg, err := cfg.driverConfig()
+	if err != nil {
+		t.Fatalf("driverConfig: %v", err)
+	}
+	if !driverCfg.AllowCleartextPasswords {
+		t.Error("expected cleartext passwords to be allowed for tokens")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // isInvalidConnErr
 // ---------------------------------------------------------------------------
@@ -294,6 +426,31 @@ func TestMigrations_CaseSensitiveContextCollation(t *testing.T) {
 	}
 }
 
+func TestMigrations_CaseSensitiveSubjectCollation(t *testing.T) {
+	// Subjects such as Orders-value and orders-value must not share a row.
+	for _, column := range []string{
+		"`schemas` MODIFY COLUMN subject",
+		"`schemas` MODIFY COLUMN fingerprint",
+		"schema_references MODIFY COLUMN ref_subject",
+		"configs MODIFY COLUMN subject",
+		"modes MODIFY COLUMN subject",
+		"schema_fingerprints MODIFY COLUMN fingerprint",
+		"deks MODIFY COLUMN subject",
+		"role_grants MODIFY COLUMN subject_prefix",
+	} {
+		found := false
+		for _, m := range migrations {
+			if strings.Contains(m, column) && strings.Contains(m, "COLLATE utf8mb4_bin") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("migrations must make %s use utf8mb4_bin collation", column)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Migrations count — guard against accidental deletion
 // ---------------------------------------------------------------------------
@@ -288,4 +288,239 @@ var migrations = []string{
 		ts BIGINT NOT NULL DEFAULT 0,
 		trace TEXT
 	)`,
+
+	// Migration 46: Context metadata and per-context limits.
+	`ALTER TABLE contexts
+		ADD COLUMN IF NOT EXISTS description TEXT,
+		ADD COLUMN IF NOT EXISTS owner VARCHAR(255),
+		ADD COLUMN IF NOT EXISTS max_subjects INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS max_schema_size INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()`,
+
+	// Migration 47: Record when a version was soft-deleted so the retention
+	// purge worker can expire it. Rows soft-deleted before this column existed
+	// are backfilled with the migration time.
+	`ALTER TABLE schemas ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE`,
+	`UPDATE schemas SET deleted_at = NOW() WHERE deleted = TRUE AND deleted_at IS NULL`,
+
+	// Migration 48: Role grants binding a user or API key to a role within a
+	// context and/or subject prefix.
+	`CREATE TABLE IF NOT EXISTS role_grants (
+		id BIGSERIAL PRIMARY KEY,
+		username VARCHAR(255),
+		api_key_id BIGINT,
+		role VARCHAR(50) NOT NULL,
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '',
+		subject_prefix VARCHAR(255) NOT NULL DEFAULT '',
+		created_by VARCHAR(255),
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	)`,
+
+	// Migration 49: Async jobs for long-running operations.
+	`CREATE TABLE IF NOT EXISTS jobs (
+		id VARCHAR(64) PRIMARY KEY,
+		type VARCHAR(100) NOT NULL,
+		state VARCHAR(20) NOT NULL,
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '',
+		params TEXT NOT NULL DEFAULT '',
+		result TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT '',
+		message TEXT NOT NULL DEFAULT '',
+		done BIGINT NOT NULL DEFAULT 0,
+		total BIGINT NOT NULL DEFAULT 0,
+		cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+		created_by VARCHAR(255) NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		started_at TIMESTAMP WITH TIME ZONE,
+		finished_at TIMESTAMP WITH TIME ZONE
+	)`,
+
+	// Migration 50: Schema usage analytics (fetch counts per day).
+	`CREATE TABLE IF NOT EXISTS schema_usage (
+		usage_day DATE NOT NULL,
+		registry_ctx VARCHAR(255) NOT NULL,
+		schema_id BIGINT NOT NULL,
+		subject VARCHAR(255) NOT NULL DEFAULT '',
+		version INT NOT NULL DEFAULT 0,
+		fetch_count BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (usage_day, registry_ctx, schema_id, subject, version)
+	)`,
+
+	// Migration 51: Read-only share tokens scoped to a context or subject.
+	`CREATE TABLE IF NOT EXISTS share_tokens (
+		id BIGSERIAL PRIMARY KEY,
+		token_hash VARCHAR(64) NOT NULL UNIQUE,
+		token_prefix VARCHAR(16) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		registry_ctx VARCHAR(255) NOT NULL,
+		subject VARCHAR(255) NOT NULL DEFAULT '',
+		created_by VARCHAR(255) NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+
+	// Migration 52: Delta-encoded schema versions. A row with delta_base_id
+	// stores schema_text as a delta against that row.
+	`ALTER TABLE schemas ADD COLUMN IF NOT EXISTS delta_base_id BIGINT REFERENCES schemas(id)`,
+	`CREATE INDEX IF NOT EXISTS idx_schemas_delta_base ON schemas(delta_base_id)`,
+
+	// Migration 53: Tenants, which own contexts and users and hold the key
+	// their schemas are encrypted with.
+	`CREATE TABLE IF NOT EXISTS tenants (
+		name VARCHAR(255) PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		encrypted_key TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	)`,
+	`ALTER TABLE contexts ADD COLUMN IF NOT EXISTS tenant VARCHAR(255) NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant VARCHAR(255) NOT NULL DEFAULT ''`,
+
+	// Migration 54: Tags and labels on subjects (version 0) and versions.
+	`CREATE TABLE IF NOT EXISTS schema_tags (
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '.',
+		subject VARCHAR(255) NOT NULL,
+		version INT NOT NULL DEFAULT 0,
+		tags JSONB NOT NULL DEFAULT '[]',
+		labels JSONB NOT NULL DEFAULT '{}',
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (registry_ctx, subject, version)
+	)`,
+
+	// Migration 55: Import sessions staging schemas until they are committed.
+	`CREATE TABLE IF NOT EXISTS import_sessions (
+		id VARCHAR(64) PRIMARY KEY,
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '.',
+		subjects JSONB NOT NULL DEFAULT '[]',
+		state VARCHAR(20) NOT NULL,
+		modes JSONB NOT NULL DEFAULT '{}',
+		schemas TEXT NOT NULL DEFAULT '',
+		staged INT NOT NULL DEFAULT 0,
+		created_by VARCHAR(255) NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	)`,
+
+	// Migration 56: Maintenance windows putting the registry into READONLY_OVERRIDE.
+	`CREATE TABLE IF NOT EXISTS maintenance_windows (
+		id VARCHAR(64) PRIMARY KEY,
+		starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+		created_by VARCHAR(255) NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	)`,
+
+	// Migration 57: Aliases mapping source registries' schema IDs to local IDs.
+	`CREATE TABLE IF NOT EXISTS schema_id_aliases (
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '.',
+		source VARCHAR(255) NOT NULL,
+		source_id BIGINT NOT NULL,
+		schema_id BIGINT NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (registry_ctx, source, source_id)
+	)`,
+
+	// Migration 58: Frozen versions, protected from deletion.
+	`CREATE TABLE IF NOT EXISTS frozen_versions (
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '.',
+		subject VARCHAR(255) NOT NULL,
+		version INT NOT NULL,
+		frozen_by VARCHAR(255) NOT NULL DEFAULT '',
+		reason TEXT NOT NULL DEFAULT '',
+		frozen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (registry_ctx, subject, version)
+	)`,
+
+	// Migration 59: Password policy state of users. Existing passwords are
+	// dated from the user's last update.
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS password_history TEXT NOT NULL DEFAULT '[]'`,
+	`UPDATE users SET password_changed_at = updated_at WHERE password_changed_at IS NULL`,
+
+	// Migration 60: Login sessions started with POST /auth/login.
+	`CREATE TABLE IF NOT EXISTS sessions (
+		id VARCHAR(64) PRIMARY KEY,
+		refresh_hash VARCHAR(64) NOT NULL,
+		user_id BIGINT NOT NULL DEFAULT 0,
+		api_key_id BIGINT NOT NULL DEFAULT 0,
+		username VARCHAR(255) NOT NULL,
+		role VARCHAR(50) NOT NULL,
+		tenant VARCHAR(255) NOT NULL DEFAULT '',
+		auth_method VARCHAR(50) NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at)`,
+
+	// Migration 61: Registrations pending approval.
+	`CREATE TABLE IF NOT EXISTS pending_schemas (
+		id VARCHAR(64) PRIMARY KEY,
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '.',
+		subject VARCHAR(255) NOT NULL,
+		state VARCHAR(20) NOT NULL,
+		request TEXT NOT NULL,
+		submitted_by VARCHAR(255) NOT NULL DEFAULT '',
+		reviewed_by VARCHAR(255) NOT NULL DEFAULT '',
+		reason TEXT NOT NULL DEFAULT '',
+		schema_id BIGINT NOT NULL DEFAULT 0,
+		version INT NOT NULL DEFAULT 0,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	)`,
+
+	// Migration 62: Resources an API key is restricted to.
+	`ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT NOT NULL DEFAULT '[]'`,
+
+	// Migration 63: Per-subject rows that registrations lock so that they
+	// allocate versions one at a time.
+	`CREATE TABLE IF NOT EXISTS subject_versions (
+		registry_ctx VARCHAR(255) NOT NULL,
+		subject VARCHAR(255) NOT NULL,
+		PRIMARY KEY (registry_ctx, subject)
+	)`,
+
+	// Migration 64: Reader schemas that consumers declare for the subjects
+	// they read.
+	`CREATE TABLE IF NOT EXISTS reader_assertions (
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '.',
+		subject VARCHAR(255) NOT NULL,
+		consumer VARCHAR(255) NOT NULL,
+		reader_subject VARCHAR(255) NOT NULL,
+		reader_version INT NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (registry_ctx, subject, consumer)
+	)`,
+
+	// Migration 65: Owner, description, contact and documentation links of
+	// subjects.
+	`CREATE TABLE IF NOT EXISTS subject_meta (
+		registry_ctx VARCHAR(255) NOT NULL DEFAULT '.',
+		subject VARCHAR(255) NOT NULL,
+		owner VARCHAR(255) NOT NULL DEFAULT '',
+		description TEXT NOT NULL DEFAULT '',
+		contact VARCHAR(255) NOT NULL DEFAULT '',
+		links JSONB NOT NULL DEFAULT '[]',
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (registry_ctx, subject)
+	)`,
+
+	// Migration 66: Reviewers declared by subjects and assigned to pending
+	// schemas.
+	`ALTER TABLE subject_meta ADD COLUMN IF NOT EXISTS reviewers JSONB NOT NULL DEFAULT '[]'`,
+	`ALTER TABLE pending_schemas ADD COLUMN IF NOT EXISTS reviewers TEXT NOT NULL DEFAULT '[]'`,
+
+	// Migration 67: API key usage analytics (request counts per day).
+	`CREATE TABLE IF NOT EXISTS api_key_usage (
+		usage_day DATE NOT NULL,
+		api_key_id BIGINT NOT NULL,
+		request_count BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (usage_day, api_key_id)
+	)`,
 }
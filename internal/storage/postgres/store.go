@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +31,17 @@ type Config struct {
 	ConnectTimeout     time.Duration `json:"connect_timeout" yaml:"connect_timeout"`           // Initial connection ping timeout (default: 5s)
 	HealthCheckTimeout time.Duration `json:"health_check_timeout" yaml:"health_check_timeout"` // Health check timeout (default: 2s)
 	SchemaMaxRetries   int           `json:"schema_max_retries" yaml:"schema_max_retries"`     // Max retries for schema creation (default: 15)
+
+	// DeltaEncoding stores each new schema version as a delta against the
+	// previous version of its subject when that saves at least half the space.
+	// Reads reconstruct the full text transparently. Existing rows are not
+	// rewritten when this is turned on or off.
+	DeltaEncoding         bool `json:"delta_encoding" yaml:"delta_encoding"`
+	DeltaSnapshotInterval int  `json:"delta_snapshot_interval" yaml:"delta_snapshot_interval"` // Store every Nth version of a chain in full (default: 10)
+
+	// GlobalIDs makes every context allocate schema IDs from the default
+	// context's sequence, so that a schema ID is unique across contexts.
+	GlobalIDs bool `json:"global_ids" yaml:"global_ids"`
 }
 
 // DefaultConfig returns a default configuration.
@@ -47,6 +60,8 @@ func DefaultConfig() Config {
 		ConnectTimeout:     5 * time.Second,
 		HealthCheckTimeout: 2 * time.Second,
 		SchemaMaxRetries:   15,
+
+		DeltaSnapshotInterval: 10,
 	}
 }
 
@@ -74,6 +89,7 @@ type preparedStatements struct {
 	getSchemaBySubjectVer  *sql.Stmt
 	getSchemaByFingerprint *sql.Stmt
 	getLatestSchema        *sql.Stmt
+	getLatestSchemaStamp   *sql.Stmt
 	softDeleteSchema       *sql.Stmt
 	hardDeleteSchema       *sql.Stmt
 	countSchemasBySubject  *sql.Stmt
@@ -138,6 +154,9 @@ func NewStore(config Config) (*Store, error) {
 	if config.SchemaMaxRetries == 0 {
 		config.SchemaMaxRetries = defaults.SchemaMaxRetries
 	}
+	if config.DeltaSnapshotInterval == 0 {
+		config.DeltaSnapshotInterval = defaults.DeltaSnapshotInterval
+	}
 
 	db, err := sql.Open("postgres", config.DSN())
 	if err != nil {
@@ -170,6 +189,13 @@ func NewStore(config Config) (*Store, error) {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	if config.GlobalIDs {
+		if err := store.seedGlobalIDSequence(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
 	// Prepare statements
 	if err := store.prepareStatements(); err != nil {
 		db.Close()
@@ -179,6 +205,28 @@ func NewStore(config Config) (*Store, error) {
 	return store, nil
 }
 
+// seedGlobalIDSequence advances the shared ID sequence past every ID already
+// assigned in any context, so that switching to global IDs never reuses one.
+func (s *Store) seedGlobalIDSequence(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO ctx_id_alloc (registry_ctx, next_id)
+		 SELECT '.', COALESCE(MAX(schema_id), 0) + 1 FROM schema_fingerprints
+		 ON CONFLICT (registry_ctx) DO UPDATE SET next_id = GREATEST(ctx_id_alloc.next_id, EXCLUDED.next_id)`)
+	if err != nil {
+		return fmt.Errorf("failed to seed global ID sequence: %w", err)
+	}
+	return nil
+}
+
+// idSequence returns the ctx_id_alloc row that allocates the schema IDs of a
+// context.
+func (s *Store) idSequence(registryCtx string) string {
+	if s.config.GlobalIDs {
+		return "."
+	}
+	return registryCtx
+}
+
 // prepareStatements prepares all SQL statements for better performance.
 func (s *Store) prepareStatements() error {
 	var err error
@@ -186,36 +234,43 @@ func (s *Store) prepareStatements() error {
 
 	// Schema statements — all scoped by registry_ctx
 	stmts.getSchemaByID, err = s.db.Prepare(
-		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 		 FROM schemas WHERE registry_ctx = $1 AND id = $2`)
 	if err != nil {
 		return fmt.Errorf("prepare getSchemaByID: %w", err)
 	}
 
 	stmts.getSchemaBySubjectVer, err = s.db.Prepare(
-		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 		 FROM schemas WHERE registry_ctx = $1 AND subject = $2 AND version = $3`)
 	if err != nil {
 		return fmt.Errorf("prepare getSchemaBySubjectVer: %w", err)
 	}
 
 	stmts.getSchemaByFingerprint, err = s.db.Prepare(
-		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 		 FROM schemas WHERE registry_ctx = $1 AND subject = $2 AND fingerprint = $3 AND deleted = FALSE`)
 	if err != nil {
 		return fmt.Errorf("prepare getSchemaByFingerprint: %w", err)
 	}
 
 	stmts.getLatestSchema, err = s.db.Prepare(
-		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 		 FROM schemas WHERE registry_ctx = $1 AND subject = $2 AND deleted = FALSE
 		 ORDER BY version DESC LIMIT 1`)
 	if err != nil {
 		return fmt.Errorf("prepare getLatestSchema: %w", err)
 	}
 
+	stmts.getLatestSchemaStamp, err = s.db.Prepare(
+		`SELECT id FROM schemas WHERE registry_ctx = $1 AND subject = $2 AND deleted = FALSE
+		 ORDER BY version DESC LIMIT 1`)
+	if err != nil {
+		return fmt.Errorf("prepare getLatestSchemaStamp: %w", err)
+	}
+
 	stmts.softDeleteSchema, err = s.db.Prepare(
-		`UPDATE schemas SET deleted = TRUE WHERE registry_ctx = $1 AND subject = $2 AND version = $3`)
+		`UPDATE schemas SET deleted = TRUE, deleted_at = COALESCE(deleted_at, NOW()) WHERE registry_ctx = $1 AND subject = $2 AND version = $3`)
 	if err != nil {
 		return fmt.Errorf("prepare softDeleteSchema: %w", err)
 	}
@@ -317,22 +372,23 @@ func (s *Store) prepareStatements() error {
 
 	// User statements
 	stmts.createUser, err = s.db.Prepare(
-		`INSERT INTO users (username, email, password_hash, role, enabled, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`INSERT INTO users (username, email, password_hash, role, enabled, created_at, updated_at, tenant,
+		   password_changed_at, must_change_password, password_history)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		 RETURNING id`)
 	if err != nil {
 		return fmt.Errorf("prepare createUser: %w", err)
 	}
 
 	stmts.getUserByID, err = s.db.Prepare(
-		`SELECT id, username, email, password_hash, role, enabled, created_at, updated_at
+		`SELECT id, username, email, password_hash, role, enabled, created_at, updated_at, tenant, password_changed_at, must_change_password, password_history
 		 FROM users WHERE id = $1`)
 	if err != nil {
 		return fmt.Errorf("prepare getUserByID: %w", err)
 	}
 
 	stmts.getUserByUsername, err = s.db.Prepare(
-		`SELECT id, username, email, password_hash, role, enabled, created_at, updated_at
+		`SELECT id, username, email, password_hash, role, enabled, created_at, updated_at, tenant, password_changed_at, must_change_password, password_history
 		 FROM users WHERE username = $1`)
 	if err != nil {
 		return fmt.Errorf("prepare getUserByUsername: %w", err)
@@ -340,7 +396,8 @@ func (s *Store) prepareStatements() error {
 
 	stmts.updateUser, err = s.db.Prepare(
 		`UPDATE users SET username = $1, email = $2, password_hash = $3, role = $4,
-		 enabled = $5, updated_at = $6 WHERE id = $7`)
+		 enabled = $5, updated_at = $6, password_changed_at = $7, must_change_password = $8,
+		 password_history = $9 WHERE id = $10`)
 	if err != nil {
 		return fmt.Errorf("prepare updateUser: %w", err)
 	}
@@ -352,7 +409,7 @@ func (s *Store) prepareStatements() error {
 	}
 
 	stmts.listUsers, err = s.db.Prepare(
-		`SELECT id, username, email, password_hash, role, enabled, created_at, updated_at
+		`SELECT id, username, email, password_hash, role, enabled, created_at, updated_at, tenant, password_changed_at, must_change_password, password_history
 		 FROM users ORDER BY username`)
 	if err != nil {
 		return fmt.Errorf("prepare listUsers: %w", err)
@@ -360,30 +417,30 @@ func (s *Store) prepareStatements() error {
 
 	// API Key statements
 	stmts.createAPIKey, err = s.db.Prepare(
-		`INSERT INTO api_keys (user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`INSERT INTO api_keys (user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, scopes)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		 RETURNING id`)
 	if err != nil {
 		return fmt.Errorf("prepare createAPIKey: %w", err)
 	}
 
 	stmts.getAPIKeyByID, err = s.db.Prepare(
-		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used
+		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes
 		 FROM api_keys WHERE id = $1`)
 	if err != nil {
 		return fmt.Errorf("prepare getAPIKeyByID: %w", err)
 	}
 
 	stmts.getAPIKeyByHash, err = s.db.Prepare(
-		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used
+		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes
 		 FROM api_keys WHERE key_hash = $1`)
 	if err != nil {
 		return fmt.Errorf("prepare getAPIKeyByHash: %w", err)
 	}
 
 	stmts.updateAPIKey, err = s.db.Prepare(
-		`UPDATE api_keys SET user_id = $1, key_hash = $2, name = $3, role = $4, enabled = $5, expires_at = $6
-		 WHERE id = $7`)
+		`UPDATE api_keys SET user_id = $1, key_hash = $2, name = $3, role = $4, enabled = $5, expires_at = $6, scopes = $7
+		 WHERE id = $8`)
 	if err != nil {
 		return fmt.Errorf("prepare updateAPIKey: %w", err)
 	}
@@ -395,21 +452,21 @@ func (s *Store) prepareStatements() error {
 	}
 
 	stmts.listAPIKeys, err = s.db.Prepare(
-		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used
+		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes
 		 FROM api_keys ORDER BY created_at DESC`)
 	if err != nil {
 		return fmt.Errorf("prepare listAPIKeys: %w", err)
 	}
 
 	stmts.listAPIKeysByUserID, err = s.db.Prepare(
-		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used
+		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes
 		 FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`)
 	if err != nil {
 		return fmt.Errorf("prepare listAPIKeysByUserID: %w", err)
 	}
 
 	stmts.getAPIKeyByUserAndName, err = s.db.Prepare(
-		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used
+		`SELECT id, user_id, key_hash, key_prefix, name, role, enabled, created_at, expires_at, last_used, scopes
 		 FROM api_keys WHERE user_id = $1 AND name = $2`)
 	if err != nil {
 		return fmt.Errorf("prepare getAPIKeyByUserAndName: %w", err)
@@ -434,7 +491,7 @@ func (s *Store) closeStatements() {
 	// Close all statements (ignore errors on close)
 	stmts := []*sql.Stmt{
 		s.stmts.getSchemaByID, s.stmts.getSchemaBySubjectVer, s.stmts.getSchemaByFingerprint,
-		s.stmts.getLatestSchema, s.stmts.softDeleteSchema, s.stmts.hardDeleteSchema,
+		s.stmts.getLatestSchema, s.stmts.getLatestSchemaStamp, s.stmts.softDeleteSchema, s.stmts.hardDeleteSchema,
 		s.stmts.countSchemasBySubject, s.stmts.loadReferences, s.stmts.getSubjectsBySchemaID,
 		s.stmts.getVersionsBySchemaID, s.stmts.getReferencedBy,
 		s.stmts.getConfig, s.stmts.setConfig, s.stmts.deleteConfig,
@@ -503,8 +560,11 @@ func (s *Store) ensureContext(ctx context.Context, registryCtx string) {
 }
 
 // CreateSchema stores a new schema record.
-// This implementation handles concurrent insertions by retrying on conflicts.
-// PostgreSQL serialization errors are handled as retriable errors.
+// Registrations to the same subject are serialized by a lock on the
+// subject's subject_versions row, so each is given the next version in
+// turn. Conflicts that remain, such as two subjects claiming the same
+// fingerprint or a serialization failure, are retried up to
+// SchemaMaxRetries times with a backoff that gives up when ctx is done.
 func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *storage.SchemaRecord) error {
 	s.ensureContext(ctx, registryCtx)
 	var lastErr error
@@ -517,26 +577,34 @@ func (s *Store) CreateSchema(ctx context.Context, registryCtx string, record *st
 		if err == storage.ErrSchemaExists {
 			return err
 		}
-		// On unique violation or serialization error, retry with exponential backoff + jitter
-		if isUniqueViolation(err) || isSerializationError(err) {
-			lastErr = err
-			// Exponential backoff: 5ms, 10ms, 20ms, 40ms, ... capped at 500ms
-			// Plus jitter of 0-50% to prevent thundering herd
-			backoff := time.Duration(5<<attempt) * time.Millisecond
-			if backoff > 500*time.Millisecond {
-				backoff = 500 * time.Millisecond
-			}
-			// Add jitter: 0-50% of backoff
-			jitter := time.Duration(float64(backoff) * (0.5 * float64(time.Now().UnixNano()%100) / 100))
-			time.Sleep(backoff + jitter)
-			continue
+		if !isUniqueViolation(err) && !isSerializationError(err) {
+			return err
+		}
+		lastErr = err
+		if err := retryBackoff(ctx, attempt); err != nil {
+			return err
 		}
-		return err
 	}
 
 	return fmt.Errorf("failed to create schema after %d retries: %w", s.config.SchemaMaxRetries, lastErr)
 }
 
+// retryBackoff waits before retrying a write that conflicted with a
+// concurrent one: 5ms, 10ms, 20ms, ... up to 500ms, plus up to 50% random
+// jitter so that retrying writers spread out. It returns ctx's error if ctx
+// is done first.
+func retryBackoff(ctx context.Context, attempt int) error {
+	backoff := min(5*time.Millisecond<<min(attempt, 7), 500*time.Millisecond)
+	timer := time.NewTimer(backoff + time.Duration(rand.Int64N(int64(backoff/2)+1)))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // createSchemaAttempt performs a single attempt to create a schema.
 func (s *Store) createSchemaAttempt(ctx context.Context, registryCtx string, record *storage.SchemaRecord) error {
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -545,6 +613,19 @@ func (s *Store) createSchemaAttempt(ctx context.Context, registryCtx string, rec
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	// Lock the subject before reading its versions. A concurrent registration
+	// to the same subject waits here until this transaction ends, and its
+	// statements then see the version this one inserted, so the two cannot
+	// pick the same version number.
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO subject_versions (registry_ctx, subject) VALUES ($1, $2)
+		 ON CONFLICT (registry_ctx, subject) DO UPDATE SET subject = EXCLUDED.subject`,
+		registryCtx, record.Subject,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to lock subject: %w", err)
+	}
+
 	// Get next version for this subject FIRST.
 	// In READ COMMITTED, each statement gets its own snapshot. By running this
 	// before the fingerprint check, we ensure the fingerprint check's snapshot
@@ -618,17 +699,23 @@ func (s *Store) createSchemaAttempt(ctx context.Context, registryCtx string, rec
 	// the table tidy. Only delete soft-deleted rows — non-deleted rows with the
 	// same fingerprint but different metadata/ruleSet must be preserved.
 	if existingDeleted {
-		_, _ = tx.ExecContext(ctx,
-			`DELETE FROM schemas WHERE registry_ctx = $1 AND subject = $2 AND fingerprint = $3 AND deleted = TRUE`,
-			registryCtx, record.Subject, record.Fingerprint,
-		)
+		const cond = `registry_ctx = $1 AND subject = $2 AND fingerprint = $3 AND deleted = TRUE`
+		if err := detachDeltaDependents(ctx, tx, cond, registryCtx, record.Subject, record.Fingerprint); err != nil {
+			return err
+		}
+		_, _ = tx.ExecContext(ctx, `DELETE FROM schemas WHERE `+cond, registryCtx, record.Subject, record.Fingerprint)
+	}
+
+	schemaText, deltaBase, err := s.encodeSchemaText(ctx, tx, registryCtx, record.Subject, record.Schema)
+	if err != nil {
+		return err
 	}
 
 	// Insert schema - unique constraint on (registry_ctx, subject, version) prevents duplicates
 	_, err = tx.ExecContext(ctx,
-		`INSERT INTO schemas (registry_ctx, subject, version, schema_type, schema_text, fingerprint, created_at, metadata, ruleset)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
-		registryCtx, record.Subject, nextVersion, record.SchemaType, record.Schema, record.Fingerprint, time.Now(), metadataJSON, rulesetJSON,
+		`INSERT INTO schemas (registry_ctx, subject, version, schema_type, schema_text, fingerprint, created_at, metadata, ruleset, delta_base_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		registryCtx, record.Subject, nextVersion, record.SchemaType, schemaText, record.Fingerprint, time.Now(), metadataJSON, rulesetJSON, deltaBase,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert schema: %w", err)
@@ -640,7 +727,7 @@ func (s *Store) createSchemaAttempt(ctx context.Context, registryCtx string, rec
 		`INSERT INTO ctx_id_alloc (registry_ctx, next_id)
 		 VALUES ($1, 2)
 		 ON CONFLICT (registry_ctx) DO UPDATE SET next_id = ctx_id_alloc.next_id + 1
-		 RETURNING next_id - 1`, registryCtx).Scan(&nextCtxID)
+		 RETURNING next_id - 1`, s.idSequence(registryCtx)).Scan(&nextCtxID)
 	if err != nil {
 		return fmt.Errorf("failed to allocate schema ID: %w", err)
 	}
@@ -693,6 +780,7 @@ func (s *Store) GetSchemaByID(ctx context.Context, registryCtx string, id int64)
 	record := &storage.SchemaRecord{}
 	var schemaType string
 	var metadataJSON, rulesetJSON []byte
+	var deltaBase sql.NullInt64
 
 	// Look up the per-context schema ID via schema_fingerprints first.
 	var fingerprint string
@@ -706,12 +794,12 @@ func (s *Store) GetSchemaByID(ctx context.Context, registryCtx string, id int64)
 	// Prefer non-deleted rows but fall back to deleted ones — schema content
 	// must remain accessible by ID even after all subjects are soft-deleted.
 	err := s.db.QueryRowContext(ctx,
-		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+		`SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 		 FROM schemas WHERE registry_ctx = $1 AND fingerprint = $2 ORDER BY deleted ASC, id ASC LIMIT 1`,
 		registryCtx, fingerprint).Scan(
 		&record.ID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataJSON, &rulesetJSON)
+		&metadataJSON, &rulesetJSON, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		return nil, storage.ErrSchemaNotFound
@@ -720,6 +808,10 @@ func (s *Store) GetSchemaByID(ctx context.Context, registryCtx string, id int64)
 		return nil, fmt.Errorf("failed to get schema: %w", err)
 	}
 
+	if record.Schema, err = expandSchemaText(ctx, s.db, record.ID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
+
 	// Use the per-context schema ID, not the row's auto-generated ID
 	record.ID = id
 
@@ -755,11 +847,12 @@ func (s *Store) GetSchemaBySubjectVersion(ctx context.Context, registryCtx strin
 	var schemaType string
 	var rowID int64
 	var metadataJSON, rulesetJSON []byte
+	var deltaBase sql.NullInt64
 
 	err := s.stmts.getSchemaBySubjectVer.QueryRowContext(ctx, registryCtx, subject, version).Scan(
 		&rowID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataJSON, &rulesetJSON)
+		&metadataJSON, &rulesetJSON, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		// Check if subject exists
@@ -779,6 +872,9 @@ func (s *Store) GetSchemaBySubjectVersion(ctx context.Context, registryCtx strin
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	record.Metadata, err = unmarshalMetadata(metadataJSON)
 	if err != nil {
@@ -809,7 +905,7 @@ func (s *Store) GetSchemaBySubjectVersion(ctx context.Context, registryCtx strin
 
 // GetSchemasBySubject retrieves all schemas for a subject.
 func (s *Store) GetSchemasBySubject(ctx context.Context, registryCtx string, subject string, includeDeleted bool) ([]*storage.SchemaRecord, error) {
-	query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+	query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 		      FROM schemas WHERE registry_ctx = $1 AND subject = $2`
 	if !includeDeleted {
 		query += ` AND deleted = FALSE`
@@ -822,18 +918,23 @@ func (s *Store) GetSchemasBySubject(ctx context.Context, registryCtx string, sub
 	}
 	defer rows.Close()
 
+	known := make(map[int64]string)
 	var schemas []*storage.SchemaRecord
 	for rows.Next() {
 		record := &storage.SchemaRecord{}
 		var schemaType string
 		var rowID int64
 		var metadataJSON, rulesetJSON []byte
+		var deltaBase sql.NullInt64
 		if err := rows.Scan(&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataJSON, &rulesetJSON); err != nil {
+			&metadataJSON, &rulesetJSON, &deltaBase); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		record.SchemaType = storage.SchemaType(schemaType)
+		if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, known); err != nil {
+			return nil, err
+		}
 
 		record.Metadata, _ = unmarshalMetadata(metadataJSON)
 		record.RuleSet, _ = unmarshalRuleSet(rulesetJSON)
@@ -870,26 +971,116 @@ func (s *Store) GetSchemasBySubject(ctx context.Context, registryCtx string, sub
 	return schemas, nil
 }
 
+// iterateBatchSize is the number of schema versions IterateSchemas reads per
+// query.
+const iterateBatchSize = 500
+
+// IterateSchemas calls fn with every schema version of a context, ordered by
+// subject and version. Versions are read in batches, each batch starting
+// after the last version of the previous one, and no query is open while fn
+// runs.
+func (s *Store) IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*storage.SchemaRecord) error) error {
+	query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
+		      FROM schemas WHERE registry_ctx = $1 AND (subject > $2 OR (subject = $2 AND version > $3))`
+	if !includeDeleted {
+		query += ` AND deleted = FALSE`
+	}
+	query += ` ORDER BY subject, version LIMIT $4`
+
+	lastSubject, lastVersion := "", 0
+	for {
+		batch, err := s.schemaBatch(ctx, registryCtx, query, lastSubject, lastVersion)
+		if err != nil {
+			return err
+		}
+		for _, record := range batch {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if len(batch) < iterateBatchSize {
+			return nil
+		}
+		lastSubject, lastVersion = batch[len(batch)-1].Subject, batch[len(batch)-1].Version
+	}
+}
+
+// schemaBatch reads one batch of IterateSchemas, the versions after
+// lastSubject and lastVersion. The rows are closed before delta texts,
+// global IDs and references are resolved.
+func (s *Store) schemaBatch(ctx context.Context, registryCtx, query, lastSubject string, lastVersion int) ([]*storage.SchemaRecord, error) {
+	type schemaRow struct {
+		record                    *storage.SchemaRecord
+		rowID                     int64
+		metadataJSON, rulesetJSON []byte
+		deltaBase                 sql.NullInt64
+	}
+	rows, err := s.db.QueryContext(ctx, query, registryCtx, lastSubject, lastVersion, iterateBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	var scanned []schemaRow
+	for rows.Next() {
+		row := schemaRow{record: &storage.SchemaRecord{}}
+		var schemaType string
+		if err := rows.Scan(&row.rowID, &row.record.Subject, &row.record.Version, &schemaType,
+			&row.record.Schema, &row.record.Fingerprint, &row.record.Deleted, &row.record.CreatedAt,
+			&row.metadataJSON, &row.rulesetJSON, &row.deltaBase); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		row.record.SchemaType = storage.SchemaType(schemaType)
+		scanned = append(scanned, row)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate schemas: %w", err)
+	}
+
+	known := make(map[int64]string)
+	batch := make([]*storage.SchemaRecord, 0, len(scanned))
+	for _, row := range scanned {
+		record := row.record
+		if record.Schema, err = expandSchemaText(ctx, s.db, row.rowID, record.Schema, row.deltaBase, known); err != nil {
+			return nil, err
+		}
+		record.Metadata, _ = unmarshalMetadata(row.metadataJSON)
+		record.RuleSet, _ = unmarshalRuleSet(row.rulesetJSON)
+		if globalID, gErr := s.globalSchemaID(ctx, registryCtx, record.Fingerprint); gErr == nil {
+			record.ID = globalID
+		} else {
+			record.ID = row.rowID
+		}
+		if record.References, err = s.loadReferences(ctx, registryCtx, record.ID); err != nil {
+			return nil, err
+		}
+		batch = append(batch, record)
+	}
+	return batch, nil
+}
+
 // GetSchemaByFingerprint retrieves a schema by subject and fingerprint.
 func (s *Store) GetSchemaByFingerprint(ctx context.Context, registryCtx string, subject, fingerprint string, includeDeleted bool) (*storage.SchemaRecord, error) {
 	record := &storage.SchemaRecord{}
 	var schemaType string
 	var rowID int64
 	var metadataJSON, rulesetJSON []byte
+	var deltaBase sql.NullInt64
 	var err error
 
 	if includeDeleted {
-		query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+		query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 		          FROM schemas WHERE registry_ctx = $1 AND subject = $2 AND fingerprint = $3`
 		err = s.db.QueryRowContext(ctx, query, registryCtx, subject, fingerprint).Scan(
 			&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataJSON, &rulesetJSON)
+			&metadataJSON, &rulesetJSON, &deltaBase)
 	} else {
 		err = s.stmts.getSchemaByFingerprint.QueryRowContext(ctx, registryCtx, subject, fingerprint).Scan(
 			&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataJSON, &rulesetJSON)
+			&metadataJSON, &rulesetJSON, &deltaBase)
 	}
 
 	if err == sql.ErrNoRows {
@@ -907,6 +1098,9 @@ func (s *Store) GetSchemaByFingerprint(ctx context.Context, registryCtx string,
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	record.Metadata, _ = unmarshalMetadata(metadataJSON)
 	record.RuleSet, _ = unmarshalRuleSet(rulesetJSON)
@@ -935,14 +1129,15 @@ func (s *Store) GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx st
 	var schemaType string
 	var rowID int64
 	var metadataJSON, rulesetJSON []byte
+	var deltaBase sql.NullInt64
 
 	// Query for any schema with this fingerprint within this context
-	query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset
+	query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id
 	          FROM schemas WHERE registry_ctx = $1 AND fingerprint = $2 AND deleted = false LIMIT 1`
 	err := s.db.QueryRowContext(ctx, query, registryCtx, fingerprint).Scan(
 		&rowID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataJSON, &rulesetJSON)
+		&metadataJSON, &rulesetJSON, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		return nil, storage.ErrSchemaNotFound
@@ -952,6 +1147,9 @@ func (s *Store) GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx st
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	record.Metadata, _ = unmarshalMetadata(metadataJSON)
 	record.RuleSet, _ = unmarshalRuleSet(rulesetJSON)
@@ -979,11 +1177,12 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	var schemaType string
 	var rowID int64
 	var metadataJSON, rulesetJSON []byte
+	var deltaBase sql.NullInt64
 
 	err := s.stmts.getLatestSchema.QueryRowContext(ctx, registryCtx, subject).Scan(
 		&rowID, &record.Subject, &record.Version, &schemaType,
 		&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-		&metadataJSON, &rulesetJSON)
+		&metadataJSON, &rulesetJSON, &deltaBase)
 
 	if err == sql.ErrNoRows {
 		return nil, storage.ErrSubjectNotFound
@@ -993,6 +1192,9 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	}
 
 	record.SchemaType = storage.SchemaType(schemaType)
+	if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, nil); err != nil {
+		return nil, err
+	}
 
 	record.Metadata, _ = unmarshalMetadata(metadataJSON)
 	record.RuleSet, _ = unmarshalRuleSet(rulesetJSON)
@@ -1014,6 +1216,20 @@ func (s *Store) GetLatestSchema(ctx context.Context, registryCtx string, subject
 	return record, nil
 }
 
+// GetLatestSchemaStamp returns the row ID of a subject's latest non-deleted
+// version. Row IDs are never reused, so a re-registered version gets a new one.
+func (s *Store) GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error) {
+	var rowID int64
+	err := s.stmts.getLatestSchemaStamp.QueryRowContext(ctx, registryCtx, subject).Scan(&rowID)
+	if err == sql.ErrNoRows {
+		return "", storage.ErrSubjectNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest schema stamp: %w", err)
+	}
+	return strconv.FormatInt(rowID, 10), nil
+}
+
 // DeleteSchema soft-deletes or permanently deletes a schema version.
 func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject string, version int, permanent bool) error {
 	if permanent {
@@ -1039,8 +1255,18 @@ func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject st
 		if !deleted {
 			return storage.ErrVersionNotSoftDeleted
 		}
-		_, err = s.stmts.hardDeleteSchema.ExecContext(ctx, registryCtx, subject, version)
+		tx, err := s.db.BeginTx(ctx, nil)
 		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		if err := detachDeltaDependents(ctx, tx, `registry_ctx = $1 AND subject = $2 AND version = $3`, registryCtx, subject, version); err != nil {
+			return err
+		}
+		if _, err := tx.StmtContext(ctx, s.stmts.hardDeleteSchema).ExecContext(ctx, registryCtx, subject, version); err != nil {
+			return fmt.Errorf("failed to delete schema: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
 			return fmt.Errorf("failed to delete schema: %w", err)
 		}
 
@@ -1070,6 +1296,43 @@ func (s *Store) DeleteSchema(ctx context.Context, registryCtx string, subject st
 	return nil
 }
 
+// RewriteSchemaText replaces the stored text of every row of the schema with
+// the given ID. The rows are stored in full afterwards, so rows stored as a
+// delta against them are first rewritten in full too.
+func (s *Store) RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var fingerprint string
+	err = tx.QueryRowContext(ctx,
+		`SELECT fingerprint FROM schema_fingerprints WHERE registry_ctx = $1 AND schema_id = $2`, registryCtx, id).Scan(&fingerprint)
+	if err == sql.ErrNoRows {
+		return storage.ErrSchemaNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get schema: %w", err)
+	}
+	if err := detachDeltaDependents(ctx, tx, `registry_ctx = $1 AND fingerprint = $2`, registryCtx, fingerprint); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx,
+		`UPDATE schemas SET schema_text = $3, delta_base_id = NULL WHERE registry_ctx = $1 AND fingerprint = $2`,
+		registryCtx, fingerprint, schemaText)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite schema: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrSchemaNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to rewrite schema: %w", err)
+	}
+	return nil
+}
+
 // ListSubjects returns all subject names.
 func (s *Store) ListSubjects(ctx context.Context, registryCtx string, includeDeleted bool) ([]string, error) {
 	query := `SELECT DISTINCT subject FROM schemas WHERE registry_ctx = $1`
@@ -1096,6 +1359,115 @@ func (s *Store) ListSubjects(ctx context.Context, registryCtx string, includeDel
 	return subjects, nil
 }
 
+// ListSubjectsPage returns one page of subject names, applying the filters
+// and LIMIT/OFFSET in the database.
+func (s *Store) ListSubjectsPage(ctx context.Context, registryCtx string, params *storage.ListSubjectsParams) ([]string, error) {
+	query := `SELECT subject FROM schemas WHERE registry_ctx = $1`
+	args := []interface{}{registryCtx}
+	argNum := 2
+	if params.Prefix != "" {
+		query += fmt.Sprintf(` AND subject LIKE $%d`, argNum)
+		args = append(args, likePrefix(params.Prefix))
+		argNum++
+	}
+	query += ` GROUP BY subject`
+	switch {
+	case params.DeletedOnly:
+		query += ` HAVING BOOL_AND(deleted)`
+	case !params.Deleted:
+		query += ` HAVING NOT BOOL_AND(deleted)`
+	}
+	query += ` ORDER BY subject`
+	if params.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT $%d`, argNum)
+		args = append(args, params.Limit)
+		argNum++
+	}
+	if params.Offset > 0 {
+		query += fmt.Sprintf(` OFFSET $%d`, argNum)
+		args = append(args, params.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subjects: %w", err)
+	}
+	defer rows.Close()
+
+	subjects := []string{}
+	for rows.Next() {
+		var subject string
+		if err := rows.Scan(&subject); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		subjects = append(subjects, subject)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate subjects: %w", err)
+	}
+	return subjects, nil
+}
+
+// ListVersions returns the version numbers of a subject, applying LIMIT/OFFSET in the database.
+func (s *Store) ListVersions(ctx context.Context, registryCtx string, subject string, params *storage.ListVersionsParams) ([]int, error) {
+	query := `SELECT version FROM schemas WHERE registry_ctx = $1 AND subject = $2`
+	if !params.Deleted {
+		query += ` AND deleted = FALSE`
+	}
+	args := []interface{}{registryCtx, subject}
+
+	if params.LatestOnly {
+		query += ` ORDER BY version DESC LIMIT 1`
+	} else {
+		query += ` ORDER BY version`
+		argNum := 3
+		if params.Limit > 0 {
+			query += fmt.Sprintf(` LIMIT $%d`, argNum)
+			args = append(args, params.Limit)
+			argNum++
+		}
+		if params.Offset > 0 {
+			query += fmt.Sprintf(` OFFSET $%d`, argNum)
+			args = append(args, params.Offset)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []int{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate versions: %w", err)
+	}
+
+	if len(versions) == 0 {
+		// An empty page past the end is not the same as a missing subject.
+		countQuery := `SELECT COUNT(*) FROM schemas WHERE registry_ctx = $1 AND subject = $2`
+		if !params.Deleted {
+			countQuery += ` AND deleted = FALSE`
+		}
+		var count int
+		if err := s.db.QueryRowContext(ctx, countQuery, registryCtx, subject).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count versions: %w", err)
+		}
+		if count == 0 {
+			return nil, storage.ErrSubjectNotFound
+		}
+	}
+
+	return versions, nil
+}
+
 // DeleteSubject deletes all versions of a subject.
 func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error) {
 	if permanent {
@@ -1140,6 +1512,8 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 		}
 		_, _ = s.db.ExecContext(ctx, `DELETE FROM configs WHERE registry_ctx = $1 AND subject = $2`, registryCtx, subject)
 		_, _ = s.db.ExecContext(ctx, `DELETE FROM modes WHERE registry_ctx = $1 AND subject = $2`, registryCtx, subject)
+		_, _ = s.db.ExecContext(ctx, `DELETE FROM schema_tags WHERE registry_ctx = $1 AND subject = $2`, registryCtx, subject)
+		_, _ = s.db.ExecContext(ctx, `DELETE FROM subject_meta WHERE registry_ctx = $1 AND subject = $2`, registryCtx, subject)
 
 		// Clean up orphaned schema_fingerprints and schema_references
 		for fp := range fingerprintSet {
@@ -1179,7 +1553,7 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 	}
 
 	_, err = s.db.ExecContext(ctx,
-		`UPDATE schemas SET deleted = TRUE WHERE registry_ctx = $1 AND subject = $2`,
+		`UPDATE schemas SET deleted = TRUE, deleted_at = NOW() WHERE registry_ctx = $1 AND subject = $2 AND deleted = FALSE`,
 		registryCtx, subject,
 	)
 	if err != nil {
@@ -1189,6 +1563,33 @@ func (s *Store) DeleteSubject(ctx context.Context, registryCtx string, subject s
 	return versions, nil
 }
 
+// ListSoftDeletedVersions returns soft-deleted versions deleted before the cutoff.
+func (s *Store) ListSoftDeletedVersions(ctx context.Context, registryCtx string, deletedBefore time.Time) ([]storage.SubjectVersion, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT subject, version FROM schemas
+		 WHERE registry_ctx = $1 AND deleted = TRUE AND deleted_at < $2
+		 ORDER BY subject, version`,
+		registryCtx, deletedBefore,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query soft-deleted versions: %w", err)
+	}
+	defer rows.Close()
+
+	result := []storage.SubjectVersion{}
+	for rows.Next() {
+		var sv storage.SubjectVersion
+		if err := rows.Scan(&sv.Subject, &sv.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result = append(result, sv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate soft-deleted versions: %w", err)
+	}
+	return result, nil
+}
+
 // SubjectExists checks if a subject exists.
 func (s *Store) SubjectExists(ctx context.Context, registryCtx string, subject string) (bool, error) {
 	var count int
@@ -1361,32 +1762,55 @@ func (s *Store) SetGlobalMode(ctx context.Context, registryCtx string, mode *sto
 }
 
 // NextID returns the next available per-context schema ID.
-// Uses the ctx_id_alloc table for per-context ID allocation.
+// Uses the ctx_id_alloc table for per-context ID allocation, or its default
+// context row when GlobalIDs is set.
 func (s *Store) NextID(ctx context.Context, registryCtx string) (int64, error) {
 	var id int64
 	err := s.db.QueryRowContext(ctx,
 		`INSERT INTO ctx_id_alloc (registry_ctx, next_id)
 		 VALUES ($1, 2)
 		 ON CONFLICT (registry_ctx) DO UPDATE SET next_id = ctx_id_alloc.next_id + 1
-		 RETURNING next_id - 1`, registryCtx).Scan(&id)
+		 RETURNING next_id - 1`, s.idSequence(registryCtx)).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get next ID: %w", err)
 	}
 	return id, nil
 }
 
-// GetMaxSchemaID returns the highest per-context schema ID currently assigned.
+// GetMaxSchemaID returns the highest per-context schema ID currently assigned,
+// or the highest in any context when GlobalIDs is set.
 func (s *Store) GetMaxSchemaID(ctx context.Context, registryCtx string) (int64, error) {
 	var maxID int64
-	err := s.db.QueryRowContext(ctx,
-		`SELECT COALESCE(MAX(schema_id), 0) FROM schema_fingerprints WHERE registry_ctx = $1`,
-		registryCtx).Scan(&maxID)
+	var err error
+	if s.config.GlobalIDs {
+		err = s.db.QueryRowContext(ctx,
+			`SELECT COALESCE(MAX(schema_id), 0) FROM schema_fingerprints`).Scan(&maxID)
+	} else {
+		err = s.db.QueryRowContext(ctx,
+			`SELECT COALESCE(MAX(schema_id), 0) FROM schema_fingerprints WHERE registry_ctx = $1`,
+			registryCtx).Scan(&maxID)
+	}
 	if err != nil {
 		return 0, fmt.Errorf("failed to get max schema ID: %w", err)
 	}
 	return maxID, nil
 }
 
+// PeekNextID returns the next ID of the context's sequence, or of the shared
+// sequence when GlobalIDs is set, without allocating it.
+func (s *Store) PeekNextID(ctx context.Context, registryCtx string) (int64, error) {
+	var next int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT next_id FROM ctx_id_alloc WHERE registry_ctx = $1`, s.idSequence(registryCtx)).Scan(&next)
+	if err == sql.ErrNoRows {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read next ID: %w", err)
+	}
+	return next, nil
+}
+
 // ImportSchema inserts a schema with a specified ID (for migration).
 // Returns ErrSchemaIDConflict if the ID already exists with different content.
 func (s *Store) ImportSchema(ctx context.Context, registryCtx string, record *storage.SchemaRecord) error {
@@ -1462,7 +1886,7 @@ func (s *Store) ImportSchema(ctx context.Context, registryCtx string, record *st
 			`INSERT INTO ctx_id_alloc (registry_ctx, next_id)
 			 VALUES ($1, $2)
 			 ON CONFLICT (registry_ctx) DO UPDATE SET next_id = GREATEST(ctx_id_alloc.next_id, $2)`,
-			registryCtx, record.ID+1,
+			s.idSequence(registryCtx), record.ID+1,
 		)
 	}
 
@@ -1496,7 +1920,7 @@ func (s *Store) SetNextID(ctx context.Context, registryCtx string, id int64) err
 	_, err := s.db.ExecContext(ctx,
 		`INSERT INTO ctx_id_alloc (registry_ctx, next_id) VALUES ($1, $2)
 		 ON CONFLICT (registry_ctx) DO UPDATE SET next_id = $2`,
-		registryCtx, id)
+		s.idSequence(registryCtx), id)
 	if err != nil {
 		return fmt.Errorf("failed to set next ID: %w", err)
 	}
@@ -1632,10 +2056,49 @@ func (s *Store) GetVersionsBySchemaID(ctx context.Context, registryCtx string, i
 	return versions, nil
 }
 
+// subjectAliases returns the target of every subject of a context whose
+// config sets an alias.
+func (s *Store) subjectAliases(ctx context.Context, registryCtx string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT subject, alias FROM configs WHERE registry_ctx = $1 AND subject <> '' AND alias IS NOT NULL AND alias <> ''`,
+		registryCtx,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subject aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make(map[string]string)
+	for rows.Next() {
+		var subject, alias string
+		if err := rows.Scan(&subject, &alias); err != nil {
+			return nil, fmt.Errorf("failed to scan subject alias: %w", err)
+		}
+		aliases[subject] = alias
+	}
+	return aliases, rows.Err()
+}
+
+// likePrefix returns the LIKE pattern matching strings that start with
+// prefix, with the wildcards in prefix escaped.
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+}
+
 // ListSchemas returns schemas matching the given filters, scoped to a context.
 func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
+	if params.Aliases {
+		aliases, err := s.subjectAliases(ctx, registryCtx)
+		if err != nil {
+			return nil, err
+		}
+		return params.WithAliases(aliases, func(p *storage.ListSchemasParams) ([]*storage.SchemaRecord, error) {
+			return s.ListSchemas(ctx, registryCtx, p)
+		})
+	}
+
 	// Always start with registry_ctx filter
-	query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset FROM schemas WHERE registry_ctx = $1`
+	query := `SELECT id, subject, version, schema_type, schema_text, fingerprint, deleted, created_at, metadata, ruleset, delta_base_id FROM schemas WHERE registry_ctx = $1`
 	args := []interface{}{registryCtx}
 	argNum := 2
 
@@ -1647,14 +2110,14 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 
 	if params.SubjectPrefix != "" {
 		query += fmt.Sprintf(` AND subject LIKE $%d`, argNum)
-		args = append(args, params.SubjectPrefix+"%")
+		args = append(args, likePrefix(params.SubjectPrefix))
 		argNum++
 	}
 
 	if params.LatestOnly {
 		args = []interface{}{registryCtx}
 		argNum = 2
-		query = `SELECT s.id, s.subject, s.version, s.schema_type, s.schema_text, s.fingerprint, s.deleted, s.created_at, s.metadata, s.ruleset
+		query = `SELECT s.id, s.subject, s.version, s.schema_type, s.schema_text, s.fingerprint, s.deleted, s.created_at, s.metadata, s.ruleset, s.delta_base_id
 		         FROM schemas s
 		         INNER JOIN (
 		             SELECT subject, MAX(version) as max_version
@@ -1665,7 +2128,7 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 		}
 		if params.SubjectPrefix != "" {
 			query += fmt.Sprintf(` AND subject LIKE $%d`, argNum)
-			args = append(args, params.SubjectPrefix+"%")
+			args = append(args, likePrefix(params.SubjectPrefix))
 			argNum++
 		}
 		query += ` GROUP BY subject
@@ -1695,18 +2158,23 @@ func (s *Store) ListSchemas(ctx context.Context, registryCtx string, params *sto
 	}
 	defer rows.Close()
 
+	known := make(map[int64]string)
 	var schemas []*storage.SchemaRecord
 	for rows.Next() {
 		record := &storage.SchemaRecord{}
 		var schemaType string
 		var rowID int64
 		var metadataJSON, rulesetJSON []byte
+		var deltaBase sql.NullInt64
 		if err := rows.Scan(&rowID, &record.Subject, &record.Version, &schemaType,
 			&record.Schema, &record.Fingerprint, &record.Deleted, &record.CreatedAt,
-			&metadataJSON, &rulesetJSON); err != nil {
+			&metadataJSON, &rulesetJSON, &deltaBase); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		record.SchemaType = storage.SchemaType(schemaType)
+		if record.Schema, err = expandSchemaText(ctx, s.db, rowID, record.Schema, deltaBase, known); err != nil {
+			return nil, err
+		}
 
 		record.Metadata, _ = unmarshalMetadata(metadataJSON)
 		record.RuleSet, _ = unmarshalRuleSet(rulesetJSON)
@@ -1766,314 +2234,1776 @@ func (s *Store) ListContexts(ctx context.Context) ([]string, error) {
 	return contexts, nil
 }
 
-// CreateUser creates a new user record.
-func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
-	now := time.Now()
-	user.CreatedAt = now
-	user.UpdatedAt = now
+// CreateContext explicitly creates a registry context with its metadata.
+func (s *Store) CreateContext(ctx context.Context, record *storage.ContextRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO contexts (registry_ctx, description, owner, max_subjects, max_schema_size, tenant, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		 RETURNING created_at, updated_at`,
+		record.Name,
+		sql.NullString{String: record.Description, Valid: record.Description != ""},
+		sql.NullString{String: record.Owner, Valid: record.Owner != ""},
+		record.MaxSubjects, record.MaxSchemaSize, record.Tenant,
+	).Scan(&record.CreatedAt, &record.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrContextExists
+		}
+		return fmt.Errorf("failed to create context: %w", err)
+	}
+	return nil
+}
 
-	err := s.stmts.createUser.QueryRowContext(ctx,
-		user.Username, sql.NullString{String: user.Email, Valid: user.Email != ""},
-		user.PasswordHash, user.Role, user.Enabled, user.CreatedAt, user.UpdatedAt,
-	).Scan(&user.ID)
+// GetContext retrieves a registry context and its metadata.
+func (s *Store) GetContext(ctx context.Context, name string) (*storage.ContextRecord, error) {
+	rec := &storage.ContextRecord{}
+	var description, owner sql.NullString
 
+	err := s.db.QueryRowContext(ctx,
+		`SELECT registry_ctx, description, owner, max_subjects, max_schema_size, tenant, created_at, updated_at
+		 FROM contexts WHERE registry_ctx = $1`, name).Scan(
+		&rec.Name, &description, &owner, &rec.MaxSubjects, &rec.MaxSchemaSize, &rec.Tenant, &rec.CreatedAt, &rec.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrContextNotFound
+	}
 	if err != nil {
-		// Check for unique constraint violation
-		if isUniqueViolation(err) {
-			return storage.ErrUserExists
+		return nil, fmt.Errorf("failed to get context: %w", err)
+	}
+
+	rec.Description = description.String
+	rec.Owner = owner.String
+	return rec, nil
+}
+
+// UpdateContext updates the metadata and limits of an existing context.
+func (s *Store) UpdateContext(ctx context.Context, record *storage.ContextRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE contexts SET description = $2, owner = $3, max_subjects = $4, max_schema_size = $5, updated_at = NOW()
+		 WHERE registry_ctx = $1
+		 RETURNING tenant, created_at, updated_at`,
+		record.Name,
+		sql.NullString{String: record.Description, Valid: record.Description != ""},
+		sql.NullString{String: record.Owner, Valid: record.Owner != ""},
+		record.MaxSubjects, record.MaxSchemaSize,
+	).Scan(&record.Tenant, &record.CreatedAt, &record.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return storage.ErrContextNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update context: %w", err)
+	}
+	return nil
+}
+
+// DeleteContext removes a context and all of its per-context data.
+func (s *Store) DeleteContext(ctx context.Context, name string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM contexts WHERE registry_ctx = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete context: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrContextNotFound
+	}
+
+	for _, table := range []string{"schema_references", "schemas", "schema_fingerprints", "configs", "modes", "schema_tags", "subject_meta", "schema_id_aliases", "frozen_versions", "reader_assertions", "ctx_id_alloc"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE registry_ctx = $1`, name); err != nil {
+			return fmt.Errorf("failed to delete context data from %s: %w", table, err)
 		}
-		return fmt.Errorf("failed to create user: %w", err)
 	}
 
+	return tx.Commit()
+}
+
+// SetTags replaces the tags and labels of a subject or subject version.
+func (s *Store) SetTags(ctx context.Context, registryCtx string, record *storage.TagsRecord) error {
+	tagsJSON, labelsJSON, err := marshalTags(record)
+	if err != nil {
+		return err
+	}
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO schema_tags (registry_ctx, subject, version, tags, labels, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, NOW())
+		 ON CONFLICT (registry_ctx, subject, version)
+		 DO UPDATE SET tags = EXCLUDED.tags, labels = EXCLUDED.labels, updated_at = EXCLUDED.updated_at
+		 RETURNING updated_at`,
+		registryCtx, record.Subject, record.Version, tagsJSON, labelsJSON,
+	).Scan(&record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set tags: %w", err)
+	}
 	return nil
 }
 
-// GetUserByID retrieves a user by ID.
-func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
-	user := &storage.UserRecord{}
-	var email sql.NullString
+// GetTags retrieves the tags and labels of a subject or subject version.
+func (s *Store) GetTags(ctx context.Context, registryCtx string, subject string, version int) (*storage.TagsRecord, error) {
+	rec := &storage.TagsRecord{Subject: subject, Version: version}
+	var tagsJSON, labelsJSON []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT tags, labels, updated_at FROM schema_tags WHERE registry_ctx = $1 AND subject = $2 AND version = $3`,
+		registryCtx, subject, version).Scan(&tagsJSON, &labelsJSON, &rec.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrTagsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	if err := unmarshalTags(rec, tagsJSON, labelsJSON); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
 
-	err := s.stmts.getUserByID.QueryRowContext(ctx, id).Scan(
-		&user.ID, &user.Username, &email, &user.PasswordHash,
-		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt)
+// DeleteTags removes the tags and labels of a subject or subject version.
+func (s *Store) DeleteTags(ctx context.Context, registryCtx string, subject string, version int) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM schema_tags WHERE registry_ctx = $1 AND subject = $2 AND version = $3`,
+		registryCtx, subject, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrTagsNotFound
+	}
+	return nil
+}
+
+// ListTags returns every tags record in a context, ordered by subject and version.
+func (s *Store) ListTags(ctx context.Context, registryCtx string) ([]*storage.TagsRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT subject, version, tags, labels, updated_at FROM schema_tags
+		 WHERE registry_ctx = $1 ORDER BY subject, version`, registryCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*storage.TagsRecord, 0)
+	for rows.Next() {
+		rec := &storage.TagsRecord{}
+		var tagsJSON, labelsJSON []byte
+		if err := rows.Scan(&rec.Subject, &rec.Version, &tagsJSON, &labelsJSON, &rec.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := unmarshalTags(rec, tagsJSON, labelsJSON); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// marshalTags encodes a record's tags and labels for the JSON columns.
+func marshalTags(record *storage.TagsRecord) (tagsJSON, labelsJSON string, err error) {
+	tags := record.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	labels := record.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	t, err := json.Marshal(tags)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	l, err := json.Marshal(labels)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	return string(t), string(l), nil
+}
+
+// unmarshalTags decodes the JSON columns into a record.
+func unmarshalTags(rec *storage.TagsRecord, tagsJSON, labelsJSON []byte) error {
+	if err := json.Unmarshal(tagsJSON, &rec.Tags); err != nil {
+		return fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if err := json.Unmarshal(labelsJSON, &rec.Labels); err != nil {
+		return fmt.Errorf("failed to unmarshal labels: %w", err)
+	}
+	return nil
+}
+
+// SetSubjectMeta creates or replaces the meta of a subject.
+func (s *Store) SetSubjectMeta(ctx context.Context, registryCtx string, record *storage.SubjectMetaRecord) error {
+	linksJSON, err := marshalSubjectLinks(record.Links)
+	if err != nil {
+		return err
+	}
+	reviewersJSON, err := marshalReviewers(record.Reviewers)
+	if err != nil {
+		return err
+	}
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO subject_meta (registry_ctx, subject, owner, description, contact, links, reviewers, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		 ON CONFLICT (registry_ctx, subject)
+		 DO UPDATE SET owner = EXCLUDED.owner, description = EXCLUDED.description, contact = EXCLUDED.contact,
+		   links = EXCLUDED.links, reviewers = EXCLUDED.reviewers, updated_at = EXCLUDED.updated_at
+		 RETURNING updated_at`,
+		registryCtx, record.Subject, record.Owner, record.Description, record.Contact, linksJSON, reviewersJSON,
+	).Scan(&record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set subject meta: %w", err)
+	}
+	return nil
+}
 
+// GetSubjectMeta retrieves the meta of a subject.
+func (s *Store) GetSubjectMeta(ctx context.Context, registryCtx string, subject string) (*storage.SubjectMetaRecord, error) {
+	rec := &storage.SubjectMetaRecord{Subject: subject}
+	var linksJSON, reviewersJSON []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT owner, description, contact, links, reviewers, updated_at FROM subject_meta WHERE registry_ctx = $1 AND subject = $2`,
+		registryCtx, subject).Scan(&rec.Owner, &rec.Description, &rec.Contact, &linksJSON, &reviewersJSON, &rec.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, storage.ErrUserNotFound
+		return nil, storage.ErrSubjectMetaNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get subject meta: %w", err)
+	}
+	if err := json.Unmarshal(linksJSON, &rec.Links); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subject links: %w", err)
+	}
+	if rec.Reviewers, err = unmarshalReviewers(string(reviewersJSON)); err != nil {
+		return nil, err
 	}
+	return rec, nil
+}
 
-	if email.Valid {
-		user.Email = email.String
+// DeleteSubjectMeta removes the meta of a subject.
+func (s *Store) DeleteSubjectMeta(ctx context.Context, registryCtx string, subject string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM subject_meta WHERE registry_ctx = $1 AND subject = $2`, registryCtx, subject)
+	if err != nil {
+		return fmt.Errorf("failed to delete subject meta: %w", err)
 	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrSubjectMetaNotFound
+	}
+	return nil
+}
 
-	return user, nil
+// marshalSubjectLinks encodes a subject's links for the JSON column.
+func marshalSubjectLinks(links []storage.SubjectLink) (string, error) {
+	if links == nil {
+		links = []storage.SubjectLink{}
+	}
+	b, err := json.Marshal(links)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal subject links: %w", err)
+	}
+	return string(b), nil
 }
 
-// GetUserByUsername retrieves a user by username.
-func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
-	user := &storage.UserRecord{}
-	var email sql.NullString
+// marshalReviewers encodes the reviewers of a subject or pending schema for
+// their JSON column.
+func marshalReviewers(reviewers []string) (string, error) {
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	b, err := json.Marshal(reviewers)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal reviewers: %w", err)
+	}
+	return string(b), nil
+}
 
-	err := s.stmts.getUserByUsername.QueryRowContext(ctx, username).Scan(
-		&user.ID, &user.Username, &email, &user.PasswordHash,
-		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt)
+// unmarshalReviewers decodes a reviewers column. No reviewers decode to nil.
+func unmarshalReviewers(data string) ([]string, error) {
+	if data == "" || data == "[]" {
+		return nil, nil
+	}
+	var reviewers []string
+	if err := json.Unmarshal([]byte(data), &reviewers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reviewers: %w", err)
+	}
+	return reviewers, nil
+}
+
+// SetSchemaIDAlias creates or replaces the alias of a source registry's schema ID.
+func (s *Store) SetSchemaIDAlias(ctx context.Context, registryCtx string, alias *storage.SchemaIDAliasRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO schema_id_aliases (registry_ctx, source, source_id, schema_id, updated_at)
+		 VALUES ($1, $2, $3, $4, NOW())
+		 ON CONFLICT (registry_ctx, source, source_id)
+		 DO UPDATE SET schema_id = EXCLUDED.schema_id, updated_at = EXCLUDED.updated_at
+		 RETURNING updated_at`,
+		registryCtx, alias.Source, alias.SourceID, alias.SchemaID,
+	).Scan(&alias.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set schema ID alias: %w", err)
+	}
+	return nil
+}
 
+// GetSchemaIDAlias retrieves the alias of a source registry's schema ID.
+func (s *Store) GetSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) (*storage.SchemaIDAliasRecord, error) {
+	alias := &storage.SchemaIDAliasRecord{Source: source, SourceID: sourceID}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT schema_id, updated_at FROM schema_id_aliases WHERE registry_ctx = $1 AND source = $2 AND source_id = $3`,
+		registryCtx, source, sourceID).Scan(&alias.SchemaID, &alias.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, storage.ErrUserNotFound
+		return nil, storage.ErrIDAliasNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get schema ID alias: %w", err)
 	}
+	return alias, nil
+}
 
-	if email.Valid {
-		user.Email = email.String
+// DeleteSchemaIDAlias removes the alias of a source registry's schema ID.
+func (s *Store) DeleteSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM schema_id_aliases WHERE registry_ctx = $1 AND source = $2 AND source_id = $3`,
+		registryCtx, source, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete schema ID alias: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrIDAliasNotFound
+	}
+	return nil
+}
+
+// ListSchemaIDAliases returns the aliases in a context, ordered by source and source ID.
+func (s *Store) ListSchemaIDAliases(ctx context.Context, registryCtx string, source string) ([]*storage.SchemaIDAliasRecord, error) {
+	query := `SELECT source, source_id, schema_id, updated_at FROM schema_id_aliases WHERE registry_ctx = $1`
+	args := []interface{}{registryCtx}
+	if source != "" {
+		query += ` AND source = $2`
+		args = append(args, source)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY source, source_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema ID aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make([]*storage.SchemaIDAliasRecord, 0)
+	for rows.Next() {
+		alias := &storage.SchemaIDAliasRecord{}
+		if err := rows.Scan(&alias.Source, &alias.SourceID, &alias.SchemaID, &alias.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+// FreezeVersion creates or replaces the freeze of a subject version.
+func (s *Store) FreezeVersion(ctx context.Context, registryCtx string, record *storage.FrozenVersionRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO frozen_versions (registry_ctx, subject, version, frozen_by, reason, frozen_at)
+		 VALUES ($1, $2, $3, $4, $5, NOW())
+		 ON CONFLICT (registry_ctx, subject, version)
+		 DO UPDATE SET frozen_by = EXCLUDED.frozen_by, reason = EXCLUDED.reason, frozen_at = EXCLUDED.frozen_at
+		 RETURNING frozen_at`,
+		registryCtx, record.Subject, record.Version, record.FrozenBy, record.Reason,
+	).Scan(&record.FrozenAt)
+	if err != nil {
+		return fmt.Errorf("failed to freeze version: %w", err)
+	}
+	return nil
+}
+
+// GetFrozenVersion retrieves the freeze of a subject version.
+func (s *Store) GetFrozenVersion(ctx context.Context, registryCtx string, subject string, version int) (*storage.FrozenVersionRecord, error) {
+	record := &storage.FrozenVersionRecord{Subject: subject, Version: version}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT frozen_by, reason, frozen_at FROM frozen_versions WHERE registry_ctx = $1 AND subject = $2 AND version = $3`,
+		registryCtx, subject, version).Scan(&record.FrozenBy, &record.Reason, &record.FrozenAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrFrozenVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frozen version: %w", err)
+	}
+	return record, nil
+}
+
+// UnfreezeVersion removes the freeze of a subject version.
+func (s *Store) UnfreezeVersion(ctx context.Context, registryCtx string, subject string, version int) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM frozen_versions WHERE registry_ctx = $1 AND subject = $2 AND version = $3`,
+		registryCtx, subject, version)
+	if err != nil {
+		return fmt.Errorf("failed to unfreeze version: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrFrozenVersionNotFound
+	}
+	return nil
+}
+
+// ListFrozenVersions returns the frozen versions in a context, ordered by subject and version.
+func (s *Store) ListFrozenVersions(ctx context.Context, registryCtx string, subject string) ([]*storage.FrozenVersionRecord, error) {
+	query := `SELECT subject, version, frozen_by, reason, frozen_at FROM frozen_versions WHERE registry_ctx = $1`
+	args := []interface{}{registryCtx}
+	if subject != "" {
+		query += ` AND subject = $2`
+		args = append(args, subject)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY subject, version`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query frozen versions: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*storage.FrozenVersionRecord, 0)
+	for rows.Next() {
+		record := &storage.FrozenVersionRecord{}
+		if err := rows.Scan(&record.Subject, &record.Version, &record.FrozenBy, &record.Reason, &record.FrozenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// SetReaderAssertion creates or replaces a consumer's assertion for a subject.
+func (s *Store) SetReaderAssertion(ctx context.Context, registryCtx string, record *storage.ReaderAssertionRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO reader_assertions (registry_ctx, subject, consumer, reader_subject, reader_version, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, NOW())
+		 ON CONFLICT (registry_ctx, subject, consumer)
+		 DO UPDATE SET reader_subject = EXCLUDED.reader_subject, reader_version = EXCLUDED.reader_version, updated_at = EXCLUDED.updated_at
+		 RETURNING updated_at`,
+		registryCtx, record.Subject, record.Consumer, record.ReaderSubject, record.ReaderVersion,
+	).Scan(&record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set reader assertion: %w", err)
+	}
+	return nil
+}
+
+// DeleteReaderAssertion removes a consumer's assertion for a subject.
+func (s *Store) DeleteReaderAssertion(ctx context.Context, registryCtx string, subject string, consumer string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM reader_assertions WHERE registry_ctx = $1 AND subject = $2 AND consumer = $3`,
+		registryCtx, subject, consumer)
+	if err != nil {
+		return fmt.Errorf("failed to delete reader assertion: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrReaderAssertionNotFound
+	}
+	return nil
+}
+
+// ListReaderAssertions returns the reader assertions in a context, ordered by subject and consumer.
+func (s *Store) ListReaderAssertions(ctx context.Context, registryCtx string, subject string) ([]*storage.ReaderAssertionRecord, error) {
+	query := `SELECT subject, consumer, reader_subject, reader_version, updated_at FROM reader_assertions WHERE registry_ctx = $1`
+	args := []interface{}{registryCtx}
+	if subject != "" {
+		query += ` AND subject = $2`
+		args = append(args, subject)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY subject, consumer`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reader assertions: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*storage.ReaderAssertionRecord, 0)
+	for rows.Next() {
+		record := &storage.ReaderAssertionRecord{}
+		if err := rows.Scan(&record.Subject, &record.Consumer, &record.ReaderSubject, &record.ReaderVersion, &record.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// CreateTenant creates a new tenant.
+func (s *Store) CreateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO tenants (name, description, encrypted_key, created_at, updated_at)
+		 VALUES ($1, $2, $3, NOW(), NOW())
+		 RETURNING created_at, updated_at`,
+		tenant.Name, tenant.Description, tenant.EncryptedKey,
+	).Scan(&tenant.CreatedAt, &tenant.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrTenantExists
+		}
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+	return nil
+}
+
+// GetTenant retrieves a tenant by name.
+func (s *Store) GetTenant(ctx context.Context, name string) (*storage.TenantRecord, error) {
+	tenant := &storage.TenantRecord{}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT name, description, encrypted_key, created_at, updated_at FROM tenants WHERE name = $1`, name).Scan(
+		&tenant.Name, &tenant.Description, &tenant.EncryptedKey, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return tenant, nil
+}
+
+// UpdateTenant updates the description of an existing tenant.
+func (s *Store) UpdateTenant(ctx context.Context, tenant *storage.TenantRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE tenants SET description = $2, updated_at = NOW() WHERE name = $1
+		 RETURNING encrypted_key, created_at, updated_at`,
+		tenant.Name, tenant.Description,
+	).Scan(&tenant.EncryptedKey, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return storage.ErrTenantNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+	return nil
+}
+
+// DeleteTenant deletes a tenant by name.
+func (s *Store) DeleteTenant(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM tenants WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrTenantNotFound
+	}
+	return nil
+}
+
+// ListTenants returns all tenants ordered by name.
+func (s *Store) ListTenants(ctx context.Context) ([]*storage.TenantRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT name, description, encrypted_key, created_at, updated_at FROM tenants ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := make([]*storage.TenantRecord, 0)
+	for rows.Next() {
+		tenant := &storage.TenantRecord{}
+		if err := rows.Scan(&tenant.Name, &tenant.Description, &tenant.EncryptedKey, &tenant.CreatedAt, &tenant.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+// CreateUser creates a new user record.
+func (s *Store) CreateUser(ctx context.Context, user *storage.UserRecord) error {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	history, err := marshalPasswordHistory(user.PasswordHistory)
+	if err != nil {
+		return err
+	}
+
+	err = s.stmts.createUser.QueryRowContext(ctx,
+		user.Username, sql.NullString{String: user.Email, Valid: user.Email != ""},
+		user.PasswordHash, user.Role, user.Enabled, user.CreatedAt, user.UpdatedAt, user.Tenant,
+		nullTime(user.PasswordChangedAt), user.MustChangePassword, history,
+	).Scan(&user.ID)
+
+	if err != nil {
+		// Check for unique constraint violation
+		if isUniqueViolation(err) {
+			return storage.ErrUserExists
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserByID retrieves a user by ID.
+func (s *Store) GetUserByID(ctx context.Context, id int64) (*storage.UserRecord, error) {
+	user := &storage.UserRecord{}
+	var email sql.NullString
+	var changedAt sql.NullTime
+	var history sql.NullString
+
+	err := s.stmts.getUserByID.QueryRowContext(ctx, id).Scan(
+		&user.ID, &user.Username, &email, &user.PasswordHash,
+		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt, &user.Tenant,
+		&changedAt, &user.MustChangePassword, &history)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if email.Valid {
+		user.Email = email.String
+	}
+	if err := setPasswordState(user, changedAt, history); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (*storage.UserRecord, error) {
+	user := &storage.UserRecord{}
+	var email sql.NullString
+	var changedAt sql.NullTime
+	var history sql.NullString
+
+	err := s.stmts.getUserByUsername.QueryRowContext(ctx, username).Scan(
+		&user.ID, &user.Username, &email, &user.PasswordHash,
+		&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt, &user.Tenant,
+		&changedAt, &user.MustChangePassword, &history)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if email.Valid {
+		user.Email = email.String
+	}
+	if err := setPasswordState(user, changedAt, history); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUser updates an existing user record.
+func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
+	user.UpdatedAt = time.Now()
+	history, err := marshalPasswordHistory(user.PasswordHistory)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.stmts.updateUser.ExecContext(ctx,
+		user.Username, sql.NullString{String: user.Email, Valid: user.Email != ""},
+		user.PasswordHash, user.Role, user.Enabled, user.UpdatedAt,
+		nullTime(user.PasswordChangedAt), user.MustChangePassword, history, user.ID,
+	)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrUserExists
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a user by ID.
+func (s *Store) DeleteUser(ctx context.Context, id int64) error {
+	result, err := s.stmts.deleteUser.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ListUsers returns all users.
+func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
+	rows, err := s.stmts.listUsers.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*storage.UserRecord
+	for rows.Next() {
+		user := &storage.UserRecord{}
+		var email sql.NullString
+		var changedAt sql.NullTime
+		var history sql.NullString
+		if err := rows.Scan(&user.ID, &user.Username, &email, &user.PasswordHash,
+			&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt, &user.Tenant,
+			&changedAt, &user.MustChangePassword, &history); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if email.Valid {
+			user.Email = email.String
+		}
+		if err := setPasswordState(user, changedAt, history); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// marshalPasswordHistory encodes the password hashes of a user's history
+// for the password_history column.
+func marshalPasswordHistory(history []string) (string, error) {
+	if history == nil {
+		history = []string{}
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal password history: %w", err)
+	}
+	return string(data), nil
+}
+
+// marshalAPIKeyScopes encodes the scopes of an API key for the scopes
+// column.
+func marshalAPIKeyScopes(scopes []storage.APIKeyScope) (string, error) {
+	if scopes == nil {
+		scopes = []storage.APIKeyScope{}
+	}
+	data, err := json.Marshal(scopes)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal API key scopes: %w", err)
+	}
+	return string(data), nil
+}
+
+// setAPIKeyScopes sets the scopes of an API key from its nullable column.
+func setAPIKeyScopes(key *storage.APIKeyRecord, scopes sql.NullString) error {
+	if !scopes.Valid || scopes.String == "" || scopes.String == "[]" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(scopes.String), &key.Scopes); err != nil {
+		return fmt.Errorf("failed to unmarshal API key scopes: %w", err)
+	}
+	return nil
+}
+
+// setPasswordState sets the password policy state of a user from its
+// nullable columns.
+func setPasswordState(user *storage.UserRecord, changedAt sql.NullTime, history sql.NullString) error {
+	if changedAt.Valid {
+		user.PasswordChangedAt = changedAt.Time
+	}
+	if history.Valid && history.String != "" {
+		if err := json.Unmarshal([]byte(history.String), &user.PasswordHistory); err != nil {
+			return fmt.Errorf("failed to unmarshal password history: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateAPIKey creates a new API key record.
+func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	key.CreatedAt = time.Now()
+	scopes, err := marshalAPIKeyScopes(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	err = s.stmts.createAPIKey.QueryRowContext(ctx,
+		key.UserID, key.KeyHash, key.KeyPrefix, key.Name, key.Role, key.Enabled, key.CreatedAt, key.ExpiresAt, scopes,
+	).Scan(&key.ID)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrAPIKeyExists
+		}
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetAPIKeyByID retrieves an API key by ID.
+func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
+	key := &storage.APIKeyRecord{}
+	var userID sql.NullInt64
+	var expiresAt, lastUsed sql.NullTime
+	var scopes sql.NullString
+
+	err := s.stmts.getAPIKeyByID.QueryRowContext(ctx, id).Scan(
+		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
+		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if userID.Valid {
+		key.UserID = userID.Int64
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = expiresAt.Time
+	}
+	if lastUsed.Valid {
+		key.LastUsed = &lastUsed.Time
+	}
+	if err := setAPIKeyScopes(key, scopes); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByHash retrieves an API key by its hash.
+func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
+	key := &storage.APIKeyRecord{}
+	var userID sql.NullInt64
+	var expiresAt, lastUsed sql.NullTime
+	var scopes sql.NullString
+
+	err := s.stmts.getAPIKeyByHash.QueryRowContext(ctx, keyHash).Scan(
+		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
+		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if userID.Valid {
+		key.UserID = userID.Int64
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = expiresAt.Time
+	}
+	if lastUsed.Valid {
+		key.LastUsed = &lastUsed.Time
+	}
+	if err := setAPIKeyScopes(key, scopes); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// UpdateAPIKey updates an existing API key record.
+func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
+	scopes, err := marshalAPIKeyScopes(key.Scopes)
+	if err != nil {
+		return err
+	}
+	result, err := s.stmts.updateAPIKey.ExecContext(ctx,
+		key.UserID, key.KeyHash, key.Name, key.Role, key.Enabled, key.ExpiresAt, scopes, key.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// DeleteAPIKey deletes an API key by ID.
+func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
+	result, err := s.stmts.deleteAPIKey.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// ListAPIKeys returns all API keys.
+func (s *Store) ListAPIKeys(ctx context.Context) ([]*storage.APIKeyRecord, error) {
+	rows, err := s.stmts.listAPIKeys.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanAPIKeys(rows)
+}
+
+// ListAPIKeysByUserID returns all API keys for a user.
+func (s *Store) ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*storage.APIKeyRecord, error) {
+	rows, err := s.stmts.listAPIKeysByUserID.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanAPIKeys(rows)
+}
+
+// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
+func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
+	key := &storage.APIKeyRecord{}
+	var keyUserID sql.NullInt64
+	var expiresAt, lastUsed sql.NullTime
+	var scopes sql.NullString
+
+	err := s.stmts.getAPIKeyByUserAndName.QueryRowContext(ctx, userID, name).Scan(
+		&key.ID, &keyUserID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
+		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes)
+
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if keyUserID.Valid {
+		key.UserID = keyUserID.Int64
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = expiresAt.Time
+	}
+	if lastUsed.Valid {
+		key.LastUsed = &lastUsed.Time
+	}
+	if err := setAPIKeyScopes(key, scopes); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// UpdateAPIKeyLastUsed updates the last_used timestamp for an API key.
+func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
+	result, err := s.stmts.updateAPIKeyLastUsed.ExecContext(ctx, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update API key last used: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// AddAPIKeyUsage adds request counts to the API key usage buckets and moves
+// the keys' last-used times forward.
+func (s *Store) AddAPIKeyUsage(ctx context.Context, records []*storage.APIKeyUsageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, rec := range records {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO api_key_usage (usage_day, api_key_id, request_count)
+			 SELECT $1::date, id, $3::bigint FROM api_keys WHERE id = $2
+			 ON CONFLICT (usage_day, api_key_id)
+			 DO UPDATE SET request_count = api_key_usage.request_count + EXCLUDED.request_count`,
+			usageDay(rec.Day), rec.APIKeyID, rec.Requests); err != nil {
+			return fmt.Errorf("failed to add API key usage: %w", err)
+		}
+		if rec.LastUsed.IsZero() {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE api_keys SET last_used = $1 WHERE id = $2 AND (last_used IS NULL OR last_used < $1)`,
+			rec.LastUsed, rec.APIKeyID); err != nil {
+			return fmt.Errorf("failed to update API key last used: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListAPIKeyUsage returns the API key usage buckets for days on or after
+// since.
+func (s *Store) ListAPIKeyUsage(ctx context.Context, since time.Time) ([]*storage.APIKeyUsageRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT usage_day, api_key_id, request_count FROM api_key_usage WHERE usage_day >= $1`, usageDay(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API key usage: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*storage.APIKeyUsageRecord{}
+	for rows.Next() {
+		rec := &storage.APIKeyUsageRecord{}
+		if err := rows.Scan(&rec.Day, &rec.APIKeyID, &rec.Requests); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rec.Day = usageDay(rec.Day)
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// CreateGrant creates a new role grant.
+func (s *Store) CreateGrant(ctx context.Context, grant *storage.GrantRecord) error {
+	grant.CreatedAt = time.Now()
+
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO role_grants (username, api_key_id, role, registry_ctx, subject_prefix, created_by, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		sql.NullString{String: grant.Username, Valid: grant.Username != ""},
+		sql.NullInt64{Int64: grant.APIKeyID, Valid: grant.APIKeyID != 0},
+		grant.Role, grant.Context, grant.SubjectPrefix,
+		sql.NullString{String: grant.CreatedBy, Valid: grant.CreatedBy != ""},
+		grant.CreatedAt,
+	).Scan(&grant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create grant: %w", err)
+	}
+
+	return nil
+}
+
+// GetGrantByID retrieves a role grant by ID.
+func (s *Store) GetGrantByID(ctx context.Context, id int64) (*storage.GrantRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+grantColumns+` FROM role_grants WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grant: %w", err)
+	}
+	defer rows.Close()
+
+	grants, err := scanGrants(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return nil, storage.ErrGrantNotFound
+	}
+	return grants[0], nil
+}
+
+// DeleteGrant deletes a role grant by ID.
+func (s *Store) DeleteGrant(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM role_grants WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete grant: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrGrantNotFound
+	}
+
+	return nil
+}
+
+// ListGrants returns all role grants.
+func (s *Store) ListGrants(ctx context.Context) ([]*storage.GrantRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+grantColumns+` FROM role_grants ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query grants: %w", err)
+	}
+	defer rows.Close()
+
+	return scanGrants(rows)
+}
+
+const grantColumns = `id, username, api_key_id, role, registry_ctx, subject_prefix, created_by, created_at`
+
+// scanGrants scans rows into grant records.
+func scanGrants(rows *sql.Rows) ([]*storage.GrantRecord, error) {
+	grants := []*storage.GrantRecord{}
+	for rows.Next() {
+		grant := &storage.GrantRecord{}
+		var username, createdBy sql.NullString
+		var apiKeyID sql.NullInt64
+		if err := rows.Scan(&grant.ID, &username, &apiKeyID, &grant.Role, &grant.Context,
+			&grant.SubjectPrefix, &createdBy, &grant.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		grant.Username = username.String
+		grant.APIKeyID = apiKeyID.Int64
+		grant.CreatedBy = createdBy.String
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate grants: %w", err)
+	}
+	return grants, nil
+}
+
+// CreateShareToken creates a new share token.
+func (s *Store) CreateShareToken(ctx context.Context, token *storage.ShareTokenRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO share_tokens (token_hash, token_prefix, name, registry_ctx, subject, created_by, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		token.TokenHash, token.TokenPrefix, token.Name, token.Context, token.Subject,
+		token.CreatedBy, token.CreatedAt, token.ExpiresAt,
+	).Scan(&token.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create share token: %w", err)
+	}
+
+	return nil
+}
+
+// GetShareTokenByID retrieves a share token by ID.
+func (s *Store) GetShareTokenByID(ctx context.Context, id int64) (*storage.ShareTokenRecord, error) {
+	return s.getShareToken(ctx, `SELECT `+shareTokenColumns+` FROM share_tokens WHERE id = $1`, id)
+}
+
+// GetShareTokenByHash retrieves a share token by the hash of its value.
+func (s *Store) GetShareTokenByHash(ctx context.Context, tokenHash string) (*storage.ShareTokenRecord, error) {
+	return s.getShareToken(ctx, `SELECT `+shareTokenColumns+` FROM share_tokens WHERE token_hash = $1`, tokenHash)
+}
+
+func (s *Store) getShareToken(ctx context.Context, query string, arg any) (*storage.ShareTokenRecord, error) {
+	rows, err := s.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share token: %w", err)
+	}
+	defer rows.Close()
+
+	tokens, err := scanShareTokens(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, storage.ErrShareTokenNotFound
+	}
+	return tokens[0], nil
+}
+
+// DeleteShareToken deletes a share token by ID.
+func (s *Store) DeleteShareToken(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM share_tokens WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete share token: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrShareTokenNotFound
+	}
+
+	return nil
+}
+
+// ListShareTokens returns all share tokens.
+func (s *Store) ListShareTokens(ctx context.Context) ([]*storage.ShareTokenRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+shareTokenColumns+` FROM share_tokens ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query share tokens: %w", err)
+	}
+	defer rows.Close()
+
+	return scanShareTokens(rows)
+}
+
+const shareTokenColumns = `id, token_hash, token_prefix, name, registry_ctx, subject, created_by, created_at, expires_at`
+
+// scanShareTokens scans rows into share token records.
+func scanShareTokens(rows *sql.Rows) ([]*storage.ShareTokenRecord, error) {
+	tokens := []*storage.ShareTokenRecord{}
+	for rows.Next() {
+		token := &storage.ShareTokenRecord{}
+		if err := rows.Scan(&token.ID, &token.TokenHash, &token.TokenPrefix, &token.Name, &token.Context,
+			&token.Subject, &token.CreatedBy, &token.CreatedAt, &token.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate share tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// CreateSession creates a new login session.
+func (s *Store) CreateSession(ctx context.Context, session *storage.SessionRecord) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO sessions (id, refresh_hash, user_id, api_key_id, username, role, tenant, auth_method, created_at, refreshed_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		session.ID, session.RefreshHash, session.UserID, session.APIKeyID, session.Username, session.Role,
+		session.Tenant, session.AuthMethod, session.CreatedAt, session.RefreshedAt, session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetSession retrieves a login session by ID.
+func (s *Store) GetSession(ctx context.Context, id string) (*storage.SessionRecord, error) {
+	session := &storage.SessionRecord{}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, refresh_hash, user_id, api_key_id, username, role, tenant, auth_method, created_at, refreshed_at, expires_at
+		 FROM sessions WHERE id = $1`, id,
+	).Scan(&session.ID, &session.RefreshHash, &session.UserID, &session.APIKeyID, &session.Username, &session.Role,
+		&session.Tenant, &session.AuthMethod, &session.CreatedAt, &session.RefreshedAt, &session.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// RefreshSession stores a session's new refresh token hash if its current
+// one is oldRefreshHash.
+func (s *Store) RefreshSession(ctx context.Context, session *storage.SessionRecord, oldRefreshHash string) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE sessions SET refresh_hash = $1, role = $2, tenant = $3, refreshed_at = $4, expires_at = $5
+		 WHERE id = $6 AND refresh_hash = $7`,
+		session.RefreshHash, session.Role, session.Tenant, session.RefreshedAt, session.ExpiresAt, session.ID, oldRefreshHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to refresh session: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// DeleteSession deletes a login session by ID.
+func (s *Store) DeleteSession(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// DeleteUserSessions deletes every login session of a user.
+func (s *Store) DeleteUserSessions(ctx context.Context, userID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions deletes the login sessions that expired before the
+// given time.
+func (s *Store) DeleteExpiredSessions(ctx context.Context, before time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < $1`, before); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return nil
+}
+
+// CreateJob creates a new async job.
+func (s *Store) CreateJob(ctx context.Context, job *storage.JobRecord) error {
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO jobs (id, type, state, registry_ctx, params, result, error, message, done, total, created_by, created_at, updated_at, started_at, finished_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12, $13, $14)`,
+		job.ID, job.Type, job.State, job.Context, job.Params, job.Result, job.Error, job.Message,
+		job.Done, job.Total, job.CreatedBy, now, nullTime(job.StartedAt), nullTime(job.FinishedAt),
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrJobExists
+		}
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	return nil
+}
+
+// GetJob retrieves an async job by ID.
+func (s *Store) GetJob(ctx context.Context, id string) (*storage.JobRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	defer rows.Close()
+
+	jobs, err := scanJobs(rows)
+	if err != nil {
+		return nil, err
 	}
-
-	return user, nil
+	if len(jobs) == 0 {
+		return nil, storage.ErrJobNotFound
+	}
+	return jobs[0], nil
 }
 
-// UpdateUser updates an existing user record.
-func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error {
-	user.UpdatedAt = time.Now()
-
-	result, err := s.stmts.updateUser.ExecContext(ctx,
-		user.Username, sql.NullString{String: user.Email, Valid: user.Email != ""},
-		user.PasswordHash, user.Role, user.Enabled, user.UpdatedAt, user.ID,
+// UpdateJob updates an async job's state, progress and result.
+func (s *Store) UpdateJob(ctx context.Context, job *storage.JobRecord) error {
+	now := time.Now()
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET state = $2, result = $3, error = $4, message = $5, done = $6, total = $7,
+		 updated_at = $8, started_at = $9, finished_at = $10 WHERE id = $1`,
+		job.ID, job.State, job.Result, job.Error, job.Message, job.Done, job.Total,
+		now, nullTime(job.StartedAt), nullTime(job.FinishedAt),
 	)
-
 	if err != nil {
-		if isUniqueViolation(err) {
-			return storage.ErrUserExists
-		}
-		return fmt.Errorf("failed to update user: %w", err)
+		return fmt.Errorf("failed to update job: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return storage.ErrUserNotFound
+		return storage.ErrJobNotFound
 	}
-
+	job.UpdatedAt = now
 	return nil
 }
 
-// DeleteUser deletes a user by ID.
-func (s *Store) DeleteUser(ctx context.Context, id int64) error {
-	result, err := s.stmts.deleteUser.ExecContext(ctx, id)
+// RequestJobCancel marks an async job for cancellation.
+func (s *Store) RequestJobCancel(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE jobs SET cancel_requested = TRUE WHERE id = $1`, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return fmt.Errorf("failed to cancel job: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return storage.ErrUserNotFound
+		return storage.ErrJobNotFound
 	}
-
 	return nil
 }
 
-// ListUsers returns all users.
-func (s *Store) ListUsers(ctx context.Context) ([]*storage.UserRecord, error) {
-	rows, err := s.stmts.listUsers.QueryContext(ctx)
+// ListJobs returns all async jobs, newest first.
+func (s *Store) ListJobs(ctx context.Context) ([]*storage.JobRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+jobColumns+` FROM jobs ORDER BY created_at DESC, id DESC`)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query users: %w", err)
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
 	}
 	defer rows.Close()
 
-	var users []*storage.UserRecord
-	for rows.Next() {
-		user := &storage.UserRecord{}
-		var email sql.NullString
-		if err := rows.Scan(&user.ID, &user.Username, &email, &user.PasswordHash,
-			&user.Role, &user.Enabled, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-		if email.Valid {
-			user.Email = email.String
-		}
-		users = append(users, user)
-	}
-
-	return users, nil
+	return scanJobs(rows)
 }
 
-// CreateAPIKey creates a new API key record.
-func (s *Store) CreateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
-	key.CreatedAt = time.Now()
+// DeleteJob deletes an async job.
+func (s *Store) DeleteJob(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
 
-	err := s.stmts.createAPIKey.QueryRowContext(ctx,
-		key.UserID, key.KeyHash, key.KeyPrefix, key.Name, key.Role, key.Enabled, key.CreatedAt, key.ExpiresAt,
-	).Scan(&key.ID)
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrJobNotFound
+	}
+	return nil
+}
 
+// CreateImportSession creates a new import session.
+func (s *Store) CreateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
+	subjectsJSON, modesJSON, err := marshalImportSession(session)
+	if err != nil {
+		return err
+	}
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO import_sessions (id, registry_ctx, subjects, state, modes, schemas, staged, created_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		 RETURNING created_at, updated_at`,
+		session.ID, session.Context, subjectsJSON, session.State, modesJSON, session.Schemas, session.Staged, session.CreatedBy,
+	).Scan(&session.CreatedAt, &session.UpdatedAt)
 	if err != nil {
 		if isUniqueViolation(err) {
-			return storage.ErrAPIKeyExists
+			return storage.ErrImportSessionExists
 		}
-		return fmt.Errorf("failed to create API key: %w", err)
+		return fmt.Errorf("failed to create import session: %w", err)
 	}
-
 	return nil
 }
 
-// GetAPIKeyByID retrieves an API key by ID.
-func (s *Store) GetAPIKeyByID(ctx context.Context, id int64) (*storage.APIKeyRecord, error) {
-	key := &storage.APIKeyRecord{}
-	var userID sql.NullInt64
-	var expiresAt, lastUsed sql.NullTime
+// GetImportSession retrieves an import session by ID.
+func (s *Store) GetImportSession(ctx context.Context, id string) (*storage.ImportSessionRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+importSessionColumns+` FROM import_sessions WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import session: %w", err)
+	}
+	defer rows.Close()
 
-	err := s.stmts.getAPIKeyByID.QueryRowContext(ctx, id).Scan(
-		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
-		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed)
+	sessions, err := scanImportSessions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, storage.ErrImportSessionNotFound
+	}
+	return sessions[0], nil
+}
 
+// UpdateImportSession updates an import session's state and staged schemas.
+func (s *Store) UpdateImportSession(ctx context.Context, session *storage.ImportSessionRecord) error {
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE import_sessions SET state = $2, schemas = $3, staged = $4, updated_at = NOW()
+		 WHERE id = $1 RETURNING updated_at`,
+		session.ID, session.State, session.Schemas, session.Staged,
+	).Scan(&updatedAt)
 	if err == sql.ErrNoRows {
-		return nil, storage.ErrAPIKeyNotFound
+		return storage.ErrImportSessionNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get API key: %w", err)
+		return fmt.Errorf("failed to update import session: %w", err)
 	}
+	session.UpdatedAt = updatedAt
+	return nil
+}
 
-	if userID.Valid {
-		key.UserID = userID.Int64
-	}
-	if expiresAt.Valid {
-		key.ExpiresAt = expiresAt.Time
-	}
-	if lastUsed.Valid {
-		key.LastUsed = &lastUsed.Time
+// ListImportSessions returns all import sessions, newest first.
+func (s *Store) ListImportSessions(ctx context.Context) ([]*storage.ImportSessionRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+importSessionColumns+` FROM import_sessions ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query import sessions: %w", err)
 	}
+	defer rows.Close()
 
-	return key, nil
+	return scanImportSessions(rows)
 }
 
-// GetAPIKeyByHash retrieves an API key by its hash.
-func (s *Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKeyRecord, error) {
-	key := &storage.APIKeyRecord{}
-	var userID sql.NullInt64
-	var expiresAt, lastUsed sql.NullTime
-
-	err := s.stmts.getAPIKeyByHash.QueryRowContext(ctx, keyHash).Scan(
-		&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
-		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed)
+const importSessionColumns = `id, registry_ctx, subjects, state, modes, schemas, staged, created_by, created_at, updated_at`
 
-	if err == sql.ErrNoRows {
-		return nil, storage.ErrAPIKeyNotFound
+// scanImportSessions scans rows into import session records.
+func scanImportSessions(rows *sql.Rows) ([]*storage.ImportSessionRecord, error) {
+	sessions := []*storage.ImportSessionRecord{}
+	for rows.Next() {
+		session := &storage.ImportSessionRecord{}
+		var subjectsJSON, modesJSON []byte
+		if err := rows.Scan(&session.ID, &session.Context, &subjectsJSON, &session.State, &modesJSON,
+			&session.Schemas, &session.Staged, &session.CreatedBy, &session.CreatedAt, &session.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := unmarshalImportSession(session, subjectsJSON, modesJSON); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key: %w", err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate import sessions: %w", err)
 	}
+	return sessions, nil
+}
 
-	if userID.Valid {
-		key.UserID = userID.Int64
+// marshalImportSession encodes a session's subjects and modes for the JSON columns.
+func marshalImportSession(session *storage.ImportSessionRecord) (subjectsJSON, modesJSON string, err error) {
+	subjects := session.Subjects
+	if subjects == nil {
+		subjects = []string{}
 	}
-	if expiresAt.Valid {
-		key.ExpiresAt = expiresAt.Time
+	modes := session.Modes
+	if modes == nil {
+		modes = map[string]string{}
 	}
-	if lastUsed.Valid {
-		key.LastUsed = &lastUsed.Time
+	sj, err := json.Marshal(subjects)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal import session subjects: %w", err)
 	}
+	mj, err := json.Marshal(modes)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal import session modes: %w", err)
+	}
+	return string(sj), string(mj), nil
+}
 
-	return key, nil
+// unmarshalImportSession decodes the JSON columns into a session.
+func unmarshalImportSession(session *storage.ImportSessionRecord, subjectsJSON, modesJSON []byte) error {
+	if err := json.Unmarshal(subjectsJSON, &session.Subjects); err != nil {
+		return fmt.Errorf("failed to unmarshal import session subjects: %w", err)
+	}
+	if err := json.Unmarshal(modesJSON, &session.Modes); err != nil {
+		return fmt.Errorf("failed to unmarshal import session modes: %w", err)
+	}
+	return nil
 }
 
-// UpdateAPIKey updates an existing API key record.
-func (s *Store) UpdateAPIKey(ctx context.Context, key *storage.APIKeyRecord) error {
-	result, err := s.stmts.updateAPIKey.ExecContext(ctx,
-		key.UserID, key.KeyHash, key.Name, key.Role, key.Enabled, key.ExpiresAt, key.ID,
-	)
+// CreateMaintenanceWindow creates a new maintenance window.
+func (s *Store) CreateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO maintenance_windows (id, starts_at, ends_at, reason, cancelled, created_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		 RETURNING created_at, updated_at`,
+		window.ID, window.StartsAt, window.EndsAt, window.Reason, window.Cancelled, window.CreatedBy,
+	).Scan(&window.CreatedAt, &window.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrMaintenanceExists
+		}
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	return nil
+}
 
+// GetMaintenanceWindow retrieves a maintenance window by ID.
+func (s *Store) GetMaintenanceWindow(ctx context.Context, id string) (*storage.MaintenanceWindowRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+maintenanceColumns+` FROM maintenance_windows WHERE id = $1`, id)
 	if err != nil {
-		return fmt.Errorf("failed to update API key: %w", err)
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrAPIKeyNotFound
+	windows, err := scanMaintenanceWindows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, storage.ErrMaintenanceNotFound
 	}
+	return windows[0], nil
+}
 
+// UpdateMaintenanceWindow updates a maintenance window's end time and cancellation.
+func (s *Store) UpdateMaintenanceWindow(ctx context.Context, window *storage.MaintenanceWindowRecord) error {
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE maintenance_windows SET ends_at = $2, cancelled = $3, updated_at = NOW()
+		 WHERE id = $1 RETURNING updated_at`,
+		window.ID, window.EndsAt, window.Cancelled,
+	).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return storage.ErrMaintenanceNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+	window.UpdatedAt = updatedAt
 	return nil
 }
 
-// DeleteAPIKey deletes an API key by ID.
-func (s *Store) DeleteAPIKey(ctx context.Context, id int64) error {
-	result, err := s.stmts.deleteAPIKey.ExecContext(ctx, id)
+// ListMaintenanceWindows returns all maintenance windows ordered by start time.
+func (s *Store) ListMaintenanceWindows(ctx context.Context) ([]*storage.MaintenanceWindowRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+maintenanceColumns+` FROM maintenance_windows ORDER BY starts_at, id`)
 	if err != nil {
-		return fmt.Errorf("failed to delete API key: %w", err)
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrAPIKeyNotFound
+	return scanMaintenanceWindows(rows)
+}
+
+const maintenanceColumns = `id, starts_at, ends_at, reason, cancelled, created_by, created_at, updated_at`
+
+// scanMaintenanceWindows scans rows into maintenance window records.
+func scanMaintenanceWindows(rows *sql.Rows) ([]*storage.MaintenanceWindowRecord, error) {
+	windows := []*storage.MaintenanceWindowRecord{}
+	for rows.Next() {
+		w := &storage.MaintenanceWindowRecord{}
+		if err := rows.Scan(&w.ID, &w.StartsAt, &w.EndsAt, &w.Reason, &w.Cancelled,
+			&w.CreatedBy, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		windows = append(windows, w)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate maintenance windows: %w", err)
+	}
+	return windows, nil
+}
 
+// CreatePendingSchema creates a new pending schema.
+func (s *Store) CreatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
+	reviewers, err := marshalReviewers(pending.Reviewers)
+	if err != nil {
+		return err
+	}
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO pending_schemas (id, registry_ctx, subject, state, request, submitted_by, reviewers, reviewed_by, reason, schema_id, version, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+		 RETURNING created_at, updated_at`,
+		pending.ID, pending.Context, pending.Subject, pending.State, pending.Request, pending.SubmittedBy,
+		reviewers, pending.ReviewedBy, pending.Reason, pending.SchemaID, pending.Version,
+	).Scan(&pending.CreatedAt, &pending.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrPendingSchemaExists
+		}
+		return fmt.Errorf("failed to create pending schema: %w", err)
+	}
 	return nil
 }
 
-// ListAPIKeys returns all API keys.
-func (s *Store) ListAPIKeys(ctx context.Context) ([]*storage.APIKeyRecord, error) {
-	rows, err := s.stmts.listAPIKeys.QueryContext(ctx)
+// GetPendingSchema retrieves a pending schema by ID.
+func (s *Store) GetPendingSchema(ctx context.Context, id string) (*storage.PendingSchemaRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+pendingSchemaColumns+` FROM pending_schemas WHERE id = $1`, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query API keys: %w", err)
+		return nil, fmt.Errorf("failed to get pending schema: %w", err)
 	}
 	defer rows.Close()
 
-	return s.scanAPIKeys(rows)
+	pending, err := scanPendingSchemas(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, storage.ErrPendingSchemaNotFound
+	}
+	return pending[0], nil
 }
 
-// ListAPIKeysByUserID returns all API keys for a user.
-func (s *Store) ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*storage.APIKeyRecord, error) {
-	rows, err := s.stmts.listAPIKeysByUserID.QueryContext(ctx, userID)
+// UpdatePendingSchema updates a pending schema's state, review and registration.
+func (s *Store) UpdatePendingSchema(ctx context.Context, pending *storage.PendingSchemaRecord) error {
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE pending_schemas SET state = $2, reviewed_by = $3, reason = $4, schema_id = $5, version = $6, updated_at = NOW()
+		 WHERE id = $1 RETURNING updated_at`,
+		pending.ID, pending.State, pending.ReviewedBy, pending.Reason, pending.SchemaID, pending.Version,
+	).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return storage.ErrPendingSchemaNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query API keys: %w", err)
+		return fmt.Errorf("failed to update pending schema: %w", err)
+	}
+	pending.UpdatedAt = updatedAt
+	return nil
+}
+
+// ListPendingSchemas returns all pending schemas, newest first.
+func (s *Store) ListPendingSchemas(ctx context.Context) ([]*storage.PendingSchemaRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+pendingSchemaColumns+` FROM pending_schemas ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending schemas: %w", err)
 	}
 	defer rows.Close()
 
-	return s.scanAPIKeys(rows)
+	return scanPendingSchemas(rows)
 }
 
-// GetAPIKeyByUserAndName retrieves an API key by user ID and name.
-func (s *Store) GetAPIKeyByUserAndName(ctx context.Context, userID int64, name string) (*storage.APIKeyRecord, error) {
-	key := &storage.APIKeyRecord{}
-	var keyUserID sql.NullInt64
-	var expiresAt, lastUsed sql.NullTime
+const pendingSchemaColumns = `id, registry_ctx, subject, state, request, submitted_by, reviewers, reviewed_by, reason, schema_id, version, created_at, updated_at`
 
-	err := s.stmts.getAPIKeyByUserAndName.QueryRowContext(ctx, userID, name).Scan(
-		&key.ID, &keyUserID, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Role,
-		&key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed)
+// scanPendingSchemas scans rows into pending schema records.
+func scanPendingSchemas(rows *sql.Rows) ([]*storage.PendingSchemaRecord, error) {
+	out := []*storage.PendingSchemaRecord{}
+	for rows.Next() {
+		p := &storage.PendingSchemaRecord{}
+		var reviewers string
+		if err := rows.Scan(&p.ID, &p.Context, &p.Subject, &p.State, &p.Request, &p.SubmittedBy, &reviewers,
+			&p.ReviewedBy, &p.Reason, &p.SchemaID, &p.Version, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		var err error
+		if p.Reviewers, err = unmarshalReviewers(reviewers); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pending schemas: %w", err)
+	}
+	return out, nil
+}
 
-	if err == sql.ErrNoRows {
-		return nil, storage.ErrAPIKeyNotFound
+// AddSchemaUsage adds fetch counts to the schema usage buckets.
+func (s *Store) AddSchemaUsage(ctx context.Context, records []*storage.SchemaUsageRecord) error {
+	if len(records) == 0 {
+		return nil
 	}
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get API key: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	if keyUserID.Valid {
-		key.UserID = keyUserID.Int64
+	for _, rec := range records {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_usage (usage_day, registry_ctx, schema_id, subject, version, fetch_count)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 ON CONFLICT (usage_day, registry_ctx, schema_id, subject, version)
+			 DO UPDATE SET fetch_count = schema_usage.fetch_count + EXCLUDED.fetch_count`,
+			usageDay(rec.Day), rec.Context, rec.SchemaID, rec.Subject, rec.Version, rec.Count); err != nil {
+			return fmt.Errorf("failed to add schema usage: %w", err)
+		}
 	}
-	if expiresAt.Valid {
-		key.ExpiresAt = expiresAt.Time
+	return tx.Commit()
+}
+
+// ListSchemaUsage returns the schema usage buckets for days on or after since.
+func (s *Store) ListSchemaUsage(ctx context.Context, since time.Time) ([]*storage.SchemaUsageRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT usage_day, registry_ctx, schema_id, subject, version, fetch_count
+		 FROM schema_usage WHERE usage_day >= $1`, usageDay(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema usage: %w", err)
 	}
-	if lastUsed.Valid {
-		key.LastUsed = &lastUsed.Time
+	defer rows.Close()
+
+	records := []*storage.SchemaUsageRecord{}
+	for rows.Next() {
+		rec := &storage.SchemaUsageRecord{}
+		if err := rows.Scan(&rec.Day, &rec.Context, &rec.SchemaID, &rec.Subject, &rec.Version, &rec.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rec.Day = usageDay(rec.Day)
+		records = append(records, rec)
 	}
+	return records, rows.Err()
+}
 
-	return key, nil
+// usageDay returns midnight UTC of the day containing t.
+func usageDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
-// UpdateAPIKeyLastUsed updates the last_used timestamp for an API key.
-func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
-	result, err := s.stmts.updateAPIKeyLastUsed.ExecContext(ctx, time.Now(), id)
-	if err != nil {
-		return fmt.Errorf("failed to update API key last used: %w", err)
-	}
+const jobColumns = `id, type, state, registry_ctx, params, result, error, message, done, total, cancel_requested, created_by, created_at, updated_at, started_at, finished_at`
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrAPIKeyNotFound
+// scanJobs scans rows into job records.
+func scanJobs(rows *sql.Rows) ([]*storage.JobRecord, error) {
+	jobs := []*storage.JobRecord{}
+	for rows.Next() {
+		job := &storage.JobRecord{}
+		var startedAt, finishedAt sql.NullTime
+		if err := rows.Scan(&job.ID, &job.Type, &job.State, &job.Context, &job.Params, &job.Result,
+			&job.Error, &job.Message, &job.Done, &job.Total, &job.CancelRequested, &job.CreatedBy,
+			&job.CreatedAt, &job.UpdatedAt, &startedAt, &finishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		job.StartedAt = startedAt.Time
+		job.FinishedAt = finishedAt.Time
+		jobs = append(jobs, job)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+	return jobs, nil
+}
 
-	return nil
+// nullTime maps a zero time to SQL NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
 }
 
 // scanAPIKeys scans rows into API key records.
@@ -2083,8 +4013,9 @@ func (s *Store) scanAPIKeys(rows *sql.Rows) ([]*storage.APIKeyRecord, error) {
 		key := &storage.APIKeyRecord{}
 		var userID sql.NullInt64
 		var expiresAt, lastUsed sql.NullTime
+		var scopes sql.NullString
 		if err := rows.Scan(&key.ID, &userID, &key.KeyHash, &key.KeyPrefix, &key.Name,
-			&key.Role, &key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed); err != nil {
+			&key.Role, &key.Enabled, &key.CreatedAt, &expiresAt, &lastUsed, &scopes); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		if userID.Valid {
@@ -2096,6 +4027,9 @@ func (s *Store) scanAPIKeys(rows *sql.Rows) ([]*storage.APIKeyRecord, error) {
 		if lastUsed.Valid {
 			key.LastUsed = &lastUsed.Time
 		}
+		if err := setAPIKeyScopes(key, scopes); err != nil {
+			return nil, err
+		}
 		keys = append(keys, key)
 	}
 	return keys, nil
@@ -2208,6 +4142,20 @@ func (s *Store) IsHealthy(ctx context.Context) bool {
 	return s.db.PingContext(ctx) == nil
 }
 
+// ReplicationLag returns the replay lag of the standby furthest behind this
+// server, or 0 when no standby is streaming from it. A write acknowledged
+// within this lag could be lost if a standby is promoted.
+func (s *Store) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	var seconds float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(EXTRACT(EPOCH FROM MAX(replay_lag)), 0) FROM pg_stat_replication`,
+	).Scan(&seconds)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query replication lag: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
 // Stats returns connection pool statistics.
 func (s *Store) Stats() sql.DBStats {
 	return s.db.Stats()
@@ -0,0 +1,44 @@
+package rdsiam
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestTokenProvider_Token(t *testing.T) {
+	p := newTokenProvider("db.example.eu-west-1.rds.amazonaws.com", 3306, "registry", "eu-west-1",
+		credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""))
+	p.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	token, err := p.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	host, rawQuery, ok := strings.Cut(token, "/?")
+	if !ok || host != "db.example.eu-west-1.rds.amazonaws.com:3306" {
+		t.Fatalf("expected the token to start with the endpoint, got %q", token)
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		t.Fatalf("failed to parse token query: %v", err)
+	}
+	for key, want := range map[string]string{
+		"Action":           "connect",
+		"DBUser":           "registry",
+		"X-Amz-Expires":    "900",
+		"X-Amz-Date":       "20260102T030405Z",
+		"X-Amz-Credential": "AKIDEXAMPLE/20260102/eu-west-1/rds-db/aws4_request",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s: expected %q, got %q", key, want, got)
+		}
+	}
+	if query.Get("X-Amz-Signature") == "" {
+		t.Error("expected a signature")
+	}
+}
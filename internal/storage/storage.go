@@ -4,41 +4,64 @@ package storage
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
 // Common errors
 var (
-	ErrNotFound              = errors.New("not found")
-	ErrSubjectNotFound       = errors.New("subject not found")
-	ErrSchemaNotFound        = errors.New("schema not found")
-	ErrVersionNotFound       = errors.New("version not found")
-	ErrInvalidVersion        = errors.New("invalid version")
-	ErrSubjectDeleted        = errors.New("subject has been deleted")
-	ErrSubjectNotSoftDeleted = errors.New("subject must be soft-deleted before being permanently deleted")
-	ErrVersionNotSoftDeleted = errors.New("version must be soft-deleted before being permanently deleted")
-	ErrSchemaExists          = errors.New("schema already exists")
-	ErrUserNotFound          = errors.New("user not found")
-	ErrUserExists            = errors.New("user already exists")
-	ErrAPIKeyNotFound        = errors.New("API key not found")
-	ErrAPIKeyExists          = errors.New("API key already exists")
-	ErrAPIKeyNameExists      = errors.New("API key name already exists for this user")
-	ErrInvalidAPIKey         = errors.New("invalid API key")
-	ErrAPIKeyExpired         = errors.New("API key has expired")
-	ErrAPIKeyDisabled        = errors.New("API key is disabled")
-	ErrUserDisabled          = errors.New("user is disabled")
-	ErrInvalidRole           = errors.New("invalid role")
-	ErrPermissionDenied      = errors.New("permission denied")
-	ErrSchemaIDConflict      = errors.New("schema ID already exists")
-	ErrOperationNotPermitted = errors.New("Cannot import since found existing subjects")
-	ErrExporterNotFound      = errors.New("exporter not found")
-	ErrExporterExists        = errors.New("exporter already exists")
-	ErrKEKNotFound           = errors.New("key encryption key not found")
-	ErrKEKExists             = errors.New("key encryption key already exists")
-	ErrKEKSoftDeleted        = errors.New("key encryption key is soft-deleted")
-	ErrDEKNotFound           = errors.New("data encryption key not found")
-	ErrDEKExists             = errors.New("data encryption key already exists")
-	ErrDEKSoftDeleted        = errors.New("data encryption key is soft-deleted")
+	ErrNotFound                = errors.New("not found")
+	ErrSubjectNotFound         = errors.New("subject not found")
+	ErrSchemaNotFound          = errors.New("schema not found")
+	ErrVersionNotFound         = errors.New("version not found")
+	ErrInvalidVersion          = errors.New("invalid version")
+	ErrSubjectDeleted          = errors.New("subject has been deleted")
+	ErrSubjectNotSoftDeleted   = errors.New("subject must be soft-deleted before being permanently deleted")
+	ErrVersionNotSoftDeleted   = errors.New("version must be soft-deleted before being permanently deleted")
+	ErrSchemaExists            = errors.New("schema already exists")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrUserExists              = errors.New("user already exists")
+	ErrAPIKeyNotFound          = errors.New("API key not found")
+	ErrAPIKeyExists            = errors.New("API key already exists")
+	ErrAPIKeyNameExists        = errors.New("API key name already exists for this user")
+	ErrInvalidAPIKey           = errors.New("invalid API key")
+	ErrAPIKeyExpired           = errors.New("API key has expired")
+	ErrAPIKeyDisabled          = errors.New("API key is disabled")
+	ErrUserDisabled            = errors.New("user is disabled")
+	ErrInvalidRole             = errors.New("invalid role")
+	ErrPermissionDenied        = errors.New("permission denied")
+	ErrSchemaIDConflict        = errors.New("schema ID already exists")
+	ErrOperationNotPermitted   = errors.New("Cannot import since found existing subjects")
+	ErrExporterNotFound        = errors.New("exporter not found")
+	ErrExporterExists          = errors.New("exporter already exists")
+	ErrKEKNotFound             = errors.New("key encryption key not found")
+	ErrKEKExists               = errors.New("key encryption key already exists")
+	ErrKEKSoftDeleted          = errors.New("key encryption key is soft-deleted")
+	ErrDEKNotFound             = errors.New("data encryption key not found")
+	ErrDEKExists               = errors.New("data encryption key already exists")
+	ErrDEKSoftDeleted          = errors.New("data encryption key is soft-deleted")
+	ErrContextNotFound         = errors.New("context not found")
+	ErrContextExists           = errors.New("context already exists")
+	ErrGrantNotFound           = errors.New("grant not found")
+	ErrShareTokenNotFound      = errors.New("share token not found")
+	ErrSessionNotFound         = errors.New("session not found")
+	ErrJobNotFound             = errors.New("job not found")
+	ErrJobExists               = errors.New("job already exists")
+	ErrTenantNotFound          = errors.New("tenant not found")
+	ErrTenantExists            = errors.New("tenant already exists")
+	ErrTagsNotFound            = errors.New("tags not found")
+	ErrSubjectMetaNotFound     = errors.New("subject meta not found")
+	ErrImportSessionNotFound   = errors.New("import session not found")
+	ErrImportSessionExists     = errors.New("import session already exists")
+	ErrIDAliasNotFound         = errors.New("schema ID alias not found")
+	ErrFrozenVersionNotFound   = errors.New("frozen version not found")
+	ErrReaderAssertionNotFound = errors.New("reader assertion not found")
+	ErrMaintenanceNotFound     = errors.New("maintenance window not found")
+	ErrMaintenanceExists       = errors.New("maintenance window already exists")
+	ErrPendingSchemaNotFound   = errors.New("pending schema not found")
+	ErrPendingSchemaExists     = errors.New("pending schema already exists")
 )
 
 // SchemaType represents the type of schema.
@@ -115,7 +138,11 @@ type Reference struct {
 	Name    string `json:"name"`
 	Subject string `json:"subject"`
 	Version int    `json:"version"`
-	Schema  string `json:"-"` // Resolved schema content; not serialized to API responses
+	// ID names the referenced schema by ID instead of by subject and
+	// version. The registry replaces it with a subject version before the
+	// reference is used, so stored references never carry it.
+	ID     int64  `json:"id,omitempty"`
+	Schema string `json:"-"` // Resolved schema content; not serialized to API responses
 }
 
 // SubjectVersion represents a subject-version pair.
@@ -154,8 +181,19 @@ type UserRecord struct {
 	PasswordHash string    `json:"-"` // Never exposed in JSON
 	Role         string    `json:"role"`
 	Enabled      bool      `json:"enabled"`
+	Tenant       string    `json:"tenant,omitempty"` // Tenant the user belongs to; empty for instance-wide users
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// PasswordChangedAt is when the password was last set; zero for users
+	// created before password policies were tracked.
+	PasswordChangedAt time.Time `json:"password_changed_at,omitempty"`
+	// MustChangePassword requires the user to change their password before
+	// doing anything else, e.g. for a bootstrapped admin.
+	MustChangePassword bool `json:"must_change_password,omitempty"`
+	// PasswordHistory holds the hashes of previous passwords, most recent
+	// first, so that they are not reused.
+	PasswordHistory []string `json:"-"`
 }
 
 // APIKeyRecord represents a stored API key.
@@ -170,6 +208,86 @@ type APIKeyRecord struct {
 	CreatedAt time.Time  `json:"created_at"`
 	ExpiresAt time.Time  `json:"expires_at"` // Required expiration time
 	LastUsed  *time.Time `json:"last_used,omitempty"`
+
+	// Scopes restricts the key to the listed resources. A key without
+	// scopes can do whatever its role allows.
+	Scopes []APIKeyScope `json:"scopes,omitempty"`
+}
+
+// APIKeyUsageRecord counts the requests an API key authenticated on one UTC
+// day. LastUsed is when the last of them was made; AddAPIKeyUsage moves the
+// key's last-used time forward to it, and ListAPIKeyUsage leaves it zero.
+type APIKeyUsageRecord struct {
+	APIKeyID int64     `json:"api_key_id"`
+	Day      time.Time `json:"day"` // Midnight UTC of the day the requests were made
+	Requests int64     `json:"requests"`
+	LastUsed time.Time `json:"last_used,omitempty"`
+}
+
+// APIKeyScope is a resource an API key is restricted to. Context is a
+// registry context name, or empty for every context; SubjectPrefix limits
+// the scope to subjects starting with the prefix, or is empty for the whole
+// context. Operations lists the permissions the scope allows, such as
+// "schema:read", or is empty for every permission of the key's role.
+type APIKeyScope struct {
+	Context       string   `json:"context,omitempty"`
+	SubjectPrefix string   `json:"subject_prefix,omitempty"`
+	Operations    []string `json:"operations,omitempty"`
+}
+
+// EqualAPIKeyScopes reports whether two API keys have the same scopes.
+func EqualAPIKeyScopes(a, b []APIKeyScope) bool {
+	return slices.EqualFunc(a, b, func(x, y APIKeyScope) bool {
+		return x.Context == y.Context && x.SubjectPrefix == y.SubjectPrefix && slices.Equal(x.Operations, y.Operations)
+	})
+}
+
+// GrantRecord binds a user or an API key to a role within a scope. A grant
+// names exactly one principal: a username, or the ID of a database API key.
+// Context is a registry context name, or empty for every context;
+// SubjectPrefix limits the grant to subjects starting with the prefix, or is
+// empty for the whole context.
+type GrantRecord struct {
+	ID            int64     `json:"id"`
+	Username      string    `json:"username,omitempty"`
+	APIKeyID      int64     `json:"api_key_id,omitempty"`
+	Role          string    `json:"role"`
+	Context       string    `json:"context,omitempty"`
+	SubjectPrefix string    `json:"subject_prefix,omitempty"`
+	CreatedBy     string    `json:"created_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ShareTokenRecord is a time-limited, read-only bearer token scoped to one
+// registry context, or to one subject within it when Subject is set.
+type ShareTokenRecord struct {
+	ID          int64     `json:"id"`
+	TokenHash   string    `json:"-"`            // Hash of the token, never exposed
+	TokenPrefix string    `json:"token_prefix"` // First 8 chars for display/identification
+	Name        string    `json:"name"`
+	Context     string    `json:"context"`
+	Subject     string    `json:"subject,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// SessionRecord is a login session started with POST /auth/login. Its
+// access tokens are signed JWTs naming the session, so revoking the session
+// revokes them; its refresh token is stored hashed and replaced on every
+// refresh.
+type SessionRecord struct {
+	ID          string    `json:"id"`
+	RefreshHash string    `json:"-"`                    // Hash of the current refresh token, never exposed
+	UserID      int64     `json:"user_id,omitempty"`    // Database user, or 0 for config, htpasswd and LDAP users
+	APIKeyID    int64     `json:"api_key_id,omitempty"` // Database API key the session was started with, or 0
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	Tenant      string    `json:"tenant,omitempty"`
+	AuthMethod  string    `json:"auth_method"` // Method used to log in
+	CreatedAt   time.Time `json:"created_at"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	ExpiresAt   time.Time `json:"expires_at"` // When the refresh token, and so the session, expires
 }
 
 // ExporterRecord represents a stored exporter (Confluent Schema Linking compatible).
@@ -193,6 +311,217 @@ type ExporterStatusRecord struct {
 	Trace  string `json:"trace,omitempty"`  // Error trace if state is ERROR
 }
 
+// ContextRecord represents a registry context with its administrative metadata
+// and per-context limits. A zero limit means unlimited. Tenant is set when
+// the context belongs to a tenant; it is fixed when the context is created
+// and UpdateContext leaves it unchanged.
+type ContextRecord struct {
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	Owner         string    `json:"owner,omitempty"`
+	Tenant        string    `json:"tenant,omitempty"`
+	MaxSubjects   int       `json:"maxSubjects"`   // Maximum number of live subjects (0 = unlimited)
+	MaxSchemaSize int       `json:"maxSchemaSize"` // Maximum schema size in bytes (0 = unlimited)
+	CreatedAt     time.Time `json:"-"`
+	UpdatedAt     time.Time `json:"-"`
+}
+
+// TenantRecord represents a tenant: a group of contexts and users isolated
+// from every other tenant. EncryptedKey is the tenant's data encryption key,
+// wrapped by the instance master key; schemas in the tenant's contexts are
+// encrypted with it before they are stored.
+type TenantRecord struct {
+	Name         string    `json:"name"`
+	Description  string    `json:"description,omitempty"`
+	EncryptedKey string    `json:"-"`
+	CreatedAt    time.Time `json:"-"`
+	UpdatedAt    time.Time `json:"-"`
+}
+
+// TagsRecord holds the free-form tags and key/value labels attached to a
+// subject, or to one version of it when Version is non-zero. A version's
+// effective tags are its own combined with the subject's.
+type TagsRecord struct {
+	Subject   string            `json:"subject"`
+	Version   int               `json:"version,omitempty"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	UpdatedAt time.Time         `json:"-"`
+}
+
+// SubjectMetaRecord is the catalog information of a subject: the team that
+// owns it, what it holds, whom to contact, where it is documented and who
+// reviews the schemas submitted to it for approval. Reviewers are usernames
+// or roles written as role:<name>.
+type SubjectMetaRecord struct {
+	Subject     string        `json:"subject"`
+	Owner       string        `json:"owner,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Contact     string        `json:"contact,omitempty"`
+	Links       []SubjectLink `json:"links,omitempty"`
+	Reviewers   []string      `json:"reviewers,omitempty"`
+	UpdatedAt   time.Time     `json:"-"`
+}
+
+// SubjectLink is a named link to documentation about a subject.
+type SubjectLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// SchemaIDAliasRecord maps the ID a schema had in a source registry to its
+// ID in this registry, so that messages serialized against the source
+// registry can still be read after a migration that could not preserve IDs.
+// Aliases are per-context; Source names the source registry.
+type SchemaIDAliasRecord struct {
+	Source    string    `json:"source"`
+	SourceID  int64     `json:"source_id"`
+	SchemaID  int64     `json:"schema_id"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// FrozenVersionRecord marks a subject version as frozen: it cannot be
+// soft- or permanently deleted until it is unfrozen. Frozen versions are
+// per-context.
+type FrozenVersionRecord struct {
+	Subject  string    `json:"subject"`
+	Version  int       `json:"version"`
+	FrozenBy string    `json:"frozen_by,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	FrozenAt time.Time `json:"frozen_at"`
+}
+
+// ReaderAssertionRecord records that a consumer reads a subject's data with
+// a reader schema: one version of ReaderSubject, which may be a different
+// subject. Assertions are per-context, one per subject and consumer.
+type ReaderAssertionRecord struct {
+	Subject       string    `json:"subject"`
+	Consumer      string    `json:"consumer"`
+	ReaderSubject string    `json:"reader_subject"`
+	ReaderVersion int       `json:"reader_version"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Job states stored in JobRecord.State.
+const (
+	JobStatePending   = "PENDING"
+	JobStateRunning   = "RUNNING"
+	JobStateSucceeded = "SUCCEEDED"
+	JobStateFailed    = "FAILED"
+	JobStateCancelled = "CANCELLED"
+)
+
+// JobRecord represents a long-running operation run by the async job
+// framework. Jobs are global, not per-context; Context records the registry
+// context the job operates on, if any. Params and Result are JSON documents
+// whose shape is owned by the job type.
+type JobRecord struct {
+	ID              string    `json:"id"`
+	Type            string    `json:"type"`
+	State           string    `json:"state"`
+	Context         string    `json:"context,omitempty"`
+	Params          string    `json:"-"`
+	Result          string    `json:"-"`
+	Error           string    `json:"error,omitempty"`
+	Message         string    `json:"message,omitempty"` // Human-readable progress detail
+	Done            int64     `json:"done"`              // Units of work completed
+	Total           int64     `json:"total"`             // Units of work in total (0 = unknown)
+	CancelRequested bool      `json:"cancel_requested"`
+	CreatedBy       string    `json:"created_by,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`  // Also refreshed as a heartbeat while the job is active
+	StartedAt       time.Time `json:"started_at"`  // Zero until the job starts
+	FinishedAt      time.Time `json:"finished_at"` // Zero until the job finishes
+}
+
+// Finished reports whether the job has reached a terminal state.
+func (j *JobRecord) Finished() bool {
+	return j.State == JobStateSucceeded || j.State == JobStateFailed || j.State == JobStateCancelled
+}
+
+// Import session states stored in ImportSessionRecord.State.
+const (
+	ImportSessionOpen      = "OPEN"
+	ImportSessionCommitted = "COMMITTED"
+	ImportSessionAborted   = "ABORTED"
+)
+
+// ImportSessionRecord is an import into a set of subjects that is staged
+// until it is committed or aborted. Sessions are global, not per-context;
+// Context records the registry context the subjects belong to. Modes holds
+// each subject's own mode from before the session, empty for a subject that
+// had none. Schemas is a JSON document of the staged schemas whose shape is
+// owned by the registry.
+type ImportSessionRecord struct {
+	ID        string            `json:"id"`
+	Context   string            `json:"context"`
+	Subjects  []string          `json:"subjects"`
+	State     string            `json:"state"`
+	Modes     map[string]string `json:"-"`
+	Schemas   string            `json:"-"`
+	Staged    int               `json:"staged"` // Number of staged schemas
+	CreatedBy string            `json:"created_by,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// MaintenanceWindowRecord is a period during which the registry is put into
+// READONLY_OVERRIDE. Windows are global. A cancelled window no longer
+// applies; cancelling a window that has started also moves EndsAt to the
+// time of cancellation.
+type MaintenanceWindowRecord struct {
+	ID        string    `json:"id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	Cancelled bool      `json:"cancelled"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Pending schema states stored in PendingSchemaRecord.State.
+const (
+	PendingSchemaPending  = "PENDING"
+	PendingSchemaApproved = "APPROVED"
+	PendingSchemaRejected = "REJECTED"
+)
+
+// PendingSchemaRecord is a registration held until it is approved or
+// rejected. Pending schemas are global, not per-context; Context records the
+// registry context of the subject. Request is a JSON document of the
+// registration request whose shape is owned by the registry. Reviewers are
+// the reviewers assigned when it was submitted; when there are any, only
+// they may review it. SchemaID and Version are set when the registration is
+// approved.
+type PendingSchemaRecord struct {
+	ID          string    `json:"id"`
+	Context     string    `json:"context"`
+	Subject     string    `json:"subject"`
+	State       string    `json:"state"`
+	Request     string    `json:"-"`
+	SubmittedBy string    `json:"submitted_by,omitempty"`
+	Reviewers   []string  `json:"reviewers,omitempty"`
+	ReviewedBy  string    `json:"reviewed_by,omitempty"`
+	Reason      string    `json:"reason,omitempty"` // Reviewer's reason, required for rejections
+	SchemaID    int64     `json:"schema_id,omitempty"`
+	Version     int       `json:"version,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SchemaUsageRecord counts how often a schema was fetched on one UTC day.
+// Fetches by schema ID alone have an empty Subject and a zero Version;
+// fetches by subject and version record both along with the schema ID.
+type SchemaUsageRecord struct {
+	Context  string    `json:"context"`
+	SchemaID int64     `json:"id"`
+	Subject  string    `json:"subject,omitempty"`
+	Version  int       `json:"version,omitempty"`
+	Day      time.Time `json:"day"` // Midnight UTC of the day the fetches happened
+	Count    int64     `json:"count"`
+}
+
 // KEKRecord represents a Key Encryption Key for CSFLE (Client-Side Field Level Encryption).
 type KEKRecord struct {
 	Name      string            `json:"name"`
@@ -240,6 +569,43 @@ type AuthStorage interface {
 	ListAPIKeys(ctx context.Context) ([]*APIKeyRecord, error)
 	ListAPIKeysByUserID(ctx context.Context, userID int64) ([]*APIKeyRecord, error)
 	UpdateAPIKeyLastUsed(ctx context.Context, id int64) error
+	// AddAPIKeyUsage adds each record's Requests to the stored count for the
+	// same key and day, and moves the key's last-used time forward to the
+	// record's LastUsed. Keys that no longer exist are skipped.
+	AddAPIKeyUsage(ctx context.Context, records []*APIKeyUsageRecord) error
+	// ListAPIKeyUsage returns the API key usage buckets for days on or
+	// after since.
+	ListAPIKeyUsage(ctx context.Context, since time.Time) ([]*APIKeyUsageRecord, error)
+
+	// Role grant management (scoped RBAC bindings)
+	CreateGrant(ctx context.Context, grant *GrantRecord) error
+	GetGrantByID(ctx context.Context, id int64) (*GrantRecord, error)
+	DeleteGrant(ctx context.Context, id int64) error
+	// ListGrants returns all grants ordered by ID.
+	ListGrants(ctx context.Context) ([]*GrantRecord, error)
+
+	// Share token management (read-only scoped bearer tokens)
+	CreateShareToken(ctx context.Context, token *ShareTokenRecord) error
+	GetShareTokenByID(ctx context.Context, id int64) (*ShareTokenRecord, error)
+	GetShareTokenByHash(ctx context.Context, tokenHash string) (*ShareTokenRecord, error)
+	DeleteShareToken(ctx context.Context, id int64) error
+	// ListShareTokens returns all share tokens ordered by ID.
+	ListShareTokens(ctx context.Context) ([]*ShareTokenRecord, error)
+
+	// Login session management
+	CreateSession(ctx context.Context, session *SessionRecord) error
+	GetSession(ctx context.Context, id string) (*SessionRecord, error)
+	// RefreshSession stores the session's new refresh hash, role, tenant,
+	// refreshed and expiry times, provided its stored refresh hash is still
+	// oldRefreshHash. Otherwise, as when the session does not exist, it
+	// returns ErrSessionNotFound, so a refresh token can only be used once.
+	RefreshSession(ctx context.Context, session *SessionRecord, oldRefreshHash string) error
+	DeleteSession(ctx context.Context, id string) error
+	// DeleteUserSessions deletes every session of a database user.
+	DeleteUserSessions(ctx context.Context, userID int64) error
+	// DeleteExpiredSessions deletes the sessions that expired before the
+	// given time.
+	DeleteExpiredSessions(ctx context.Context, before time.Time) error
 }
 
 // Storage defines the interface for schema storage backends.
@@ -260,10 +626,34 @@ type Storage interface {
 	GetSchemaByFingerprint(ctx context.Context, registryCtx string, subject, fingerprint string, includeDeleted bool) (*SchemaRecord, error)
 	GetSchemaByGlobalFingerprint(ctx context.Context, registryCtx string, fingerprint string) (*SchemaRecord, error)
 	GetLatestSchema(ctx context.Context, registryCtx string, subject string) (*SchemaRecord, error)
+	// GetLatestSchemaStamp returns an opaque value identifying the stored
+	// version GetLatestSchema would return, without loading the schema. The
+	// stamp changes whenever that version changes, including when a subject
+	// is permanently deleted and registered again. Returns ErrSubjectNotFound
+	// if the subject has no non-deleted versions.
+	GetLatestSchemaStamp(ctx context.Context, registryCtx string, subject string) (string, error)
 	DeleteSchema(ctx context.Context, registryCtx string, subject string, version int, permanent bool) error
+	// RewriteSchemaText replaces the stored text of the schema with the given
+	// ID, in every subject version that uses it, without changing its
+	// fingerprint, metadata or references. It re-encrypts schemas at rest, so
+	// the new text must be the same schema. Returns ErrSchemaNotFound if no
+	// schema has the ID.
+	RewriteSchemaText(ctx context.Context, registryCtx string, id int64, schemaText string) error
 
 	// Subject operations
 	ListSubjects(ctx context.Context, registryCtx string, includeDeleted bool) ([]string, error)
+	// ListSubjectsPage returns one page of subject names in ascending order,
+	// applying the filters and LIMIT/OFFSET in the backend where it can.
+	ListSubjectsPage(ctx context.Context, registryCtx string, params *ListSubjectsParams) ([]string, error)
+	// ListVersions returns a subject's version numbers in ascending order
+	// without loading schema content. Returns ErrSubjectNotFound if the subject
+	// has no versions matching params.Deleted.
+	ListVersions(ctx context.Context, registryCtx string, subject string, params *ListVersionsParams) ([]int, error)
+	// ListSoftDeletedVersions returns the soft-deleted versions in a context
+	// that were deleted before deletedBefore, ordered by subject and version.
+	// Versions soft-deleted before the backend recorded deletion times are
+	// treated as deleted when the backend was upgraded.
+	ListSoftDeletedVersions(ctx context.Context, registryCtx string, deletedBefore time.Time) ([]SubjectVersion, error)
 	DeleteSubject(ctx context.Context, registryCtx string, subject string, permanent bool) ([]int, error)
 	SubjectExists(ctx context.Context, registryCtx string, subject string) (bool, error)
 
@@ -282,9 +672,17 @@ type Storage interface {
 	SetGlobalMode(ctx context.Context, registryCtx string, mode *ModeRecord) error
 	DeleteGlobalMode(ctx context.Context, registryCtx string) error
 
-	// ID generation (per-context: each context has its own ID sequence)
+	// ID generation (per-context: each context has its own ID sequence,
+	// unless the backend is configured with global IDs, in which case all
+	// contexts share the default context's sequence and GetMaxSchemaID
+	// covers every context)
 	NextID(ctx context.Context, registryCtx string) (int64, error)
 	GetMaxSchemaID(ctx context.Context, registryCtx string) (int64, error)
+	// PeekNextID returns the next ID the sequence has not handed out,
+	// without allocating it; 1 for a sequence that was never used. Backends
+	// that reserve IDs in blocks return the first ID past the reserved
+	// blocks, so every ID allocated so far is lower.
+	PeekNextID(ctx context.Context, registryCtx string) (int64, error)
 
 	// Import operations (for migration from other schema registries)
 	// ImportSchema inserts a schema with a specified ID (for migration).
@@ -303,9 +701,88 @@ type Storage interface {
 
 	// Schema listing
 	ListSchemas(ctx context.Context, registryCtx string, params *ListSchemasParams) ([]*SchemaRecord, error)
+	// IterateSchemas calls fn with every schema version of a context, ordered
+	// by subject and version, without loading them all at once; use it over
+	// ListSchemas to walk a whole context. Soft-deleted versions are included
+	// with includeDeleted. Iteration stops at the first error from fn, which
+	// is returned.
+	IterateSchemas(ctx context.Context, registryCtx string, includeDeleted bool, fn func(*SchemaRecord) error) error
 
 	// Context operations
 	ListContexts(ctx context.Context) ([]string, error)
+	// CreateContext registers a context explicitly. Returns ErrContextExists if
+	// the context is already tracked (including contexts created implicitly by
+	// schema registration).
+	CreateContext(ctx context.Context, record *ContextRecord) error
+	GetContext(ctx context.Context, name string) (*ContextRecord, error)
+	UpdateContext(ctx context.Context, record *ContextRecord) error
+	// DeleteContext removes a context together with its context-level config,
+	// mode and ID sequence. Callers must permanently delete the context's
+	// subjects first; backends are not required to cascade subject data.
+	DeleteContext(ctx context.Context, name string) error
+
+	// Tenant operations (global, not per-context)
+	// CreateTenant returns ErrTenantExists if the name is taken.
+	CreateTenant(ctx context.Context, tenant *TenantRecord) error
+	GetTenant(ctx context.Context, name string) (*TenantRecord, error)
+	// UpdateTenant updates a tenant's description. The key is never changed.
+	UpdateTenant(ctx context.Context, tenant *TenantRecord) error
+	DeleteTenant(ctx context.Context, name string) error
+	// ListTenants returns all tenants ordered by name.
+	ListTenants(ctx context.Context) ([]*TenantRecord, error)
+
+	// Tag operations (per-context). Version 0 addresses the subject itself.
+	// SetTags replaces the tags and labels stored for the subject or version.
+	SetTags(ctx context.Context, registryCtx string, record *TagsRecord) error
+	// GetTags returns ErrTagsNotFound if nothing is stored.
+	GetTags(ctx context.Context, registryCtx string, subject string, version int) (*TagsRecord, error)
+	// DeleteTags returns ErrTagsNotFound if nothing is stored.
+	DeleteTags(ctx context.Context, registryCtx string, subject string, version int) error
+	// ListTags returns every tags record in a context, ordered by subject
+	// and version. Permanently deleting a subject removes its records.
+	ListTags(ctx context.Context, registryCtx string) ([]*TagsRecord, error)
+
+	// Subject meta operations (per-context)
+	// SetSubjectMeta creates or replaces the meta of a subject.
+	SetSubjectMeta(ctx context.Context, registryCtx string, record *SubjectMetaRecord) error
+	// GetSubjectMeta returns ErrSubjectMetaNotFound if nothing is stored.
+	GetSubjectMeta(ctx context.Context, registryCtx string, subject string) (*SubjectMetaRecord, error)
+	// DeleteSubjectMeta returns ErrSubjectMetaNotFound if nothing is stored.
+	// Permanently deleting a subject removes its meta.
+	DeleteSubjectMeta(ctx context.Context, registryCtx string, subject string) error
+
+	// Schema ID alias operations (per-context)
+	// SetSchemaIDAlias creates or replaces the alias of a source registry's
+	// schema ID.
+	SetSchemaIDAlias(ctx context.Context, registryCtx string, alias *SchemaIDAliasRecord) error
+	// GetSchemaIDAlias returns ErrIDAliasNotFound if the source ID has no alias.
+	GetSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) (*SchemaIDAliasRecord, error)
+	// DeleteSchemaIDAlias returns ErrIDAliasNotFound if the source ID has no alias.
+	DeleteSchemaIDAlias(ctx context.Context, registryCtx string, source string, sourceID int64) error
+	// ListSchemaIDAliases returns the aliases in a context from source, or
+	// from every source when source is empty, ordered by source and source ID.
+	ListSchemaIDAliases(ctx context.Context, registryCtx string, source string) ([]*SchemaIDAliasRecord, error)
+
+	// Frozen version operations (per-context)
+	// FreezeVersion creates or replaces the freeze of a subject version.
+	FreezeVersion(ctx context.Context, registryCtx string, record *FrozenVersionRecord) error
+	// GetFrozenVersion returns ErrFrozenVersionNotFound if the version is not frozen.
+	GetFrozenVersion(ctx context.Context, registryCtx string, subject string, version int) (*FrozenVersionRecord, error)
+	// UnfreezeVersion returns ErrFrozenVersionNotFound if the version is not frozen.
+	UnfreezeVersion(ctx context.Context, registryCtx string, subject string, version int) error
+	// ListFrozenVersions returns the frozen versions of subject, or of every
+	// subject when subject is empty, ordered by subject and version.
+	ListFrozenVersions(ctx context.Context, registryCtx string, subject string) ([]*FrozenVersionRecord, error)
+
+	// Reader assertion operations (per-context)
+	// SetReaderAssertion creates or replaces a consumer's assertion for a subject.
+	SetReaderAssertion(ctx context.Context, registryCtx string, record *ReaderAssertionRecord) error
+	// DeleteReaderAssertion returns ErrReaderAssertionNotFound if the consumer has no
+	// assertion for the subject.
+	DeleteReaderAssertion(ctx context.Context, registryCtx string, subject string, consumer string) error
+	// ListReaderAssertions returns the assertions for subject, or for every
+	// subject when subject is empty, ordered by subject and consumer.
+	ListReaderAssertions(ctx context.Context, registryCtx string, subject string) ([]*ReaderAssertionRecord, error)
 
 	// Global config delete
 	DeleteGlobalConfig(ctx context.Context, registryCtx string) error
@@ -346,16 +823,182 @@ type Storage interface {
 	GetExporterConfig(ctx context.Context, name string) (map[string]string, error)
 	UpdateExporterConfig(ctx context.Context, name string, config map[string]string) error
 
+	// Job operations (async job framework; global, not per-context)
+	// CreateJob stores a new job. Returns ErrJobExists if the ID is taken.
+	CreateJob(ctx context.Context, job *JobRecord) error
+	GetJob(ctx context.Context, id string) (*JobRecord, error)
+	// UpdateJob stores a job's state, progress, result and timestamps. It
+	// never changes CancelRequested, so a cancellation requested by another
+	// instance is not lost when the running instance reports progress.
+	UpdateJob(ctx context.Context, job *JobRecord) error
+	// RequestJobCancel sets CancelRequested on a job.
+	RequestJobCancel(ctx context.Context, id string) error
+	// ListJobs returns all jobs, newest first.
+	ListJobs(ctx context.Context) ([]*JobRecord, error)
+	DeleteJob(ctx context.Context, id string) error
+
+	// Import session operations (global, not per-context)
+	// CreateImportSession returns ErrImportSessionExists if the ID is taken.
+	CreateImportSession(ctx context.Context, session *ImportSessionRecord) error
+	GetImportSession(ctx context.Context, id string) (*ImportSessionRecord, error)
+	// UpdateImportSession stores a session's state and staged schemas. Its
+	// context, subjects and modes never change.
+	UpdateImportSession(ctx context.Context, session *ImportSessionRecord) error
+	// ListImportSessions returns all import sessions, newest first.
+	ListImportSessions(ctx context.Context) ([]*ImportSessionRecord, error)
+
+	// Maintenance window operations (global, not per-context)
+	// CreateMaintenanceWindow returns ErrMaintenanceExists if the ID is taken.
+	CreateMaintenanceWindow(ctx context.Context, window *MaintenanceWindowRecord) error
+	GetMaintenanceWindow(ctx context.Context, id string) (*MaintenanceWindowRecord, error)
+	// UpdateMaintenanceWindow stores a window's end time and cancellation.
+	UpdateMaintenanceWindow(ctx context.Context, window *MaintenanceWindowRecord) error
+	// ListMaintenanceWindows returns all maintenance windows ordered by start
+	// time.
+	ListMaintenanceWindows(ctx context.Context) ([]*MaintenanceWindowRecord, error)
+
+	// Pending schema operations (global, not per-context)
+	// CreatePendingSchema returns ErrPendingSchemaExists if the ID is taken.
+	CreatePendingSchema(ctx context.Context, pending *PendingSchemaRecord) error
+	GetPendingSchema(ctx context.Context, id string) (*PendingSchemaRecord, error)
+	// UpdatePendingSchema stores a pending schema's state, review and the
+	// schema ID and version it was registered as. Its context, subject,
+	// request, submitter and reviewers never change.
+	UpdatePendingSchema(ctx context.Context, pending *PendingSchemaRecord) error
+	// ListPendingSchemas returns all pending schemas, newest first.
+	ListPendingSchemas(ctx context.Context) ([]*PendingSchemaRecord, error)
+
+	// Schema usage operations (fetch counts in daily buckets; global, not per-context)
+	// AddSchemaUsage adds each record's Count to the stored count for the
+	// same context, schema ID, subject, version and day.
+	AddSchemaUsage(ctx context.Context, records []*SchemaUsageRecord) error
+	// ListSchemaUsage returns the usage buckets for days on or after since.
+	ListSchemaUsage(ctx context.Context, since time.Time) ([]*SchemaUsageRecord, error)
+
 	// Lifecycle
 	Close() error
 	IsHealthy(ctx context.Context) bool
 }
 
+// ListVersionsParams contains parameters for listing the versions of a subject.
+type ListVersionsParams struct {
+	Deleted    bool // Include soft-deleted versions
+	LatestOnly bool // Return only the highest matching version
+	Offset     int
+	Limit      int // 0 means no limit
+}
+
+// Page applies the params to a sorted, already-filtered list of versions.
+// Backends that cannot push LIMIT/OFFSET down to the database use it to
+// produce the same result as the SQL backends.
+func (p *ListVersionsParams) Page(versions []int) []int {
+	if p.LatestOnly {
+		if len(versions) == 0 {
+			return []int{}
+		}
+		return versions[len(versions)-1:]
+	}
+	start := p.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > len(versions) {
+		start = len(versions)
+	}
+	end := len(versions)
+	if p.Limit > 0 && start+p.Limit < end {
+		end = start + p.Limit
+	}
+	return versions[start:end]
+}
+
+// ListSubjectsParams contains parameters for listing subject names.
+type ListSubjectsParams struct {
+	Prefix      string // Only subjects starting with Prefix
+	Deleted     bool   // Include subjects whose versions are all soft-deleted
+	DeletedOnly bool   // Only subjects whose versions are all soft-deleted
+	Offset      int
+	Limit       int // 0 means no limit
+}
+
+// Page applies offset and limit to a sorted, already-filtered list of
+// subjects, for backends that cannot push them down to the database.
+func (p *ListSubjectsParams) Page(subjects []string) []string {
+	start := p.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(subjects) {
+		return []string{}
+	}
+	end := len(subjects)
+	if p.Limit > 0 && start+p.Limit < end {
+		end = start + p.Limit
+	}
+	return subjects[start:end]
+}
+
 // ListSchemasParams contains parameters for listing schemas.
 type ListSchemasParams struct {
-	SubjectPrefix string
-	Deleted       bool
-	LatestOnly    bool
+	SubjectPrefix string // Only schemas of subjects starting with SubjectPrefix
+	Deleted       bool   // Include soft-deleted versions
+	LatestOnly    bool   // Only the latest version of each subject
+	Aliases       bool   // Also list the schemas of an alias target under each alias subject
 	Offset        int
-	Limit         int
+	Limit         int // 0 means no limit
+}
+
+// WithAliases lists schemas for params.Aliases. aliases maps each subject
+// whose config sets an alias to its target, and list lists schemas without
+// aliases. The schemas of each target are listed again under every alias
+// subject that matches SubjectPrefix, and offset and limit are applied to
+// the combined list, ordered by ID and subject.
+func (p *ListSchemasParams) WithAliases(aliases map[string]string, list func(*ListSchemasParams) ([]*SchemaRecord, error)) ([]*SchemaRecord, error) {
+	all := *p
+	all.SubjectPrefix, all.Aliases, all.Offset, all.Limit = "", false, 0, 0
+	records, err := list(&all)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SchemaRecord, 0, len(records))
+	bySubject := make(map[string][]*SchemaRecord)
+	for _, rec := range records {
+		bySubject[rec.Subject] = append(bySubject[rec.Subject], rec)
+		if strings.HasPrefix(rec.Subject, p.SubjectPrefix) {
+			results = append(results, rec)
+		}
+	}
+	for alias, target := range aliases {
+		if !strings.HasPrefix(alias, p.SubjectPrefix) {
+			continue
+		}
+		for _, rec := range bySubject[target] {
+			aliased := *rec
+			aliased.Subject = alias
+			results = append(results, &aliased)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].ID != results[j].ID {
+			return results[i].ID < results[j].ID
+		}
+		if results[i].Subject != results[j].Subject {
+			return results[i].Subject < results[j].Subject
+		}
+		return results[i].Version < results[j].Version
+	})
+
+	start := p.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > len(results) {
+		start = len(results)
+	}
+	end := len(results)
+	if p.Limit > 0 && start+p.Limit < end {
+		end = start + p.Limit
+	}
+	return results[start:end], nil
 }
@@ -6,9 +6,11 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -44,11 +46,13 @@ func DefaultConfig() Config {
 
 // Store implements storage.AuthStorage using HashiCorp Vault.
 type Store struct {
-	client    *api.Client
-	config    Config
-	mu        sync.RWMutex
-	userIDSeq int64
-	keyIDSeq  int64
+	client     *api.Client
+	config     Config
+	mu         sync.RWMutex
+	userIDSeq  int64
+	keyIDSeq   int64
+	grantIDSeq int64
+	shareIDSeq int64
 }
 
 // NewStore creates a new Vault auth store.
@@ -147,6 +151,20 @@ func (s *Store) initSequences(ctx context.Context) error {
 	}
 	s.keyIDSeq = keySeq
 
+	// Read grant ID sequence
+	grantSeq, err := s.readSequence(ctx, "grant_id_seq")
+	if err != nil {
+		return err
+	}
+	s.grantIDSeq = grantSeq
+
+	// Read share token ID sequence
+	shareSeq, err := s.readSequence(ctx, "share_token_id_seq")
+	if err != nil {
+		return err
+	}
+	s.shareIDSeq = shareSeq
+
 	return nil
 }
 
@@ -203,6 +221,22 @@ func (s *Store) nextAPIKeyID(ctx context.Context) (int64, error) {
 	return id, nil
 }
 
+func (s *Store) nextGrantID(ctx context.Context) (int64, error) {
+	id := atomic.AddInt64(&s.grantIDSeq, 1)
+	if err := s.writeSequence(ctx, "grant_id_seq", id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *Store) nextShareTokenID(ctx context.Context) (int64, error) {
+	id := atomic.AddInt64(&s.shareIDSeq, 1)
+	if err := s.writeSequence(ctx, "share_token_id_seq", id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
 // kvPath returns the full path for a key in KV v2.
 func (s *Store) kvPath(key string) string {
 	return s.config.BasePath + "/" + key
@@ -258,8 +292,15 @@ func (s *Store) writeUser(ctx context.Context, user *storage.UserRecord) error {
 		"password_hash": user.PasswordHash,
 		"role":          user.Role,
 		"enabled":       user.Enabled,
+		"tenant":        user.Tenant,
 		"created_at":    user.CreatedAt.Format(time.RFC3339),
 		"updated_at":    user.UpdatedAt.Format(time.RFC3339),
+
+		"must_change_password": user.MustChangePassword,
+		"password_history":     user.PasswordHistory,
+	}
+	if !user.PasswordChangedAt.IsZero() {
+		data["password_changed_at"] = user.PasswordChangedAt.Format(time.RFC3339)
 	}
 	_, err := s.client.KVv2(s.config.MountPath).Put(ctx, path, data)
 	return err
@@ -340,6 +381,7 @@ func (s *Store) UpdateUser(ctx context.Context, user *storage.UserRecord) error
 		}
 	}
 
+	user.Tenant = current.Tenant
 	user.UpdatedAt = time.Now().UTC()
 
 	// Update user record
@@ -464,6 +506,9 @@ func (s *Store) writeAPIKey(ctx context.Context, key *storage.APIKeyRecord) erro
 	if key.LastUsed != nil {
 		data["last_used"] = key.LastUsed.Format(time.RFC3339)
 	}
+	if len(key.Scopes) > 0 {
+		data["scopes"] = key.Scopes
+	}
 	_, err := s.client.KVv2(s.config.MountPath).Put(ctx, path, data)
 	return err
 }
@@ -644,6 +689,459 @@ func (s *Store) UpdateAPIKeyLastUsed(ctx context.Context, id int64) error {
 	return s.writeAPIKey(ctx, key)
 }
 
+// AddAPIKeyUsage adds request counts to the API key usage buckets and moves
+// the keys' last-used times forward. Each key's daily counts are kept in one
+// secret, keyed by day.
+func (s *Store) AddAPIKeyUsage(ctx context.Context, records []*storage.APIKeyUsageRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rec := range records {
+		key, err := s.GetAPIKeyByID(ctx, rec.APIKeyID)
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		days, err := s.readAPIKeyUsage(ctx, rec.APIKeyID)
+		if err != nil {
+			return err
+		}
+		day := rec.Day.UTC().Format("2006-01-02")
+		days[day] += rec.Requests
+		data := make(map[string]interface{}, len(days))
+		for d, requests := range days {
+			data[d] = requests
+		}
+		if _, err := s.client.KVv2(s.config.MountPath).Put(ctx, s.kvPath(fmt.Sprintf("apikey-usage/%d", rec.APIKeyID)), data); err != nil {
+			return fmt.Errorf("failed to write API key usage: %w", err)
+		}
+		if !rec.LastUsed.IsZero() && (key.LastUsed == nil || rec.LastUsed.After(*key.LastUsed)) {
+			lastUsed := rec.LastUsed.UTC()
+			key.LastUsed = &lastUsed
+			if err := s.writeAPIKey(ctx, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ListAPIKeyUsage returns the API key usage buckets for days on or after
+// since.
+func (s *Store) ListAPIKeyUsage(ctx context.Context, since time.Time) ([]*storage.APIKeyUsageRecord, error) {
+	keys, err := s.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	y, m, d := since.UTC().Date()
+	since = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	records := []*storage.APIKeyUsageRecord{}
+	for _, key := range keys {
+		days, err := s.readAPIKeyUsage(ctx, key.ID)
+		if err != nil {
+			return nil, err
+		}
+		for d, requests := range days {
+			day, err := time.Parse("2006-01-02", d)
+			if err != nil || day.Before(since) {
+				continue
+			}
+			records = append(records, &storage.APIKeyUsageRecord{APIKeyID: key.ID, Day: day, Requests: requests})
+		}
+	}
+	return records, nil
+}
+
+// readAPIKeyUsage returns an API key's request counts by day.
+func (s *Store) readAPIKeyUsage(ctx context.Context, id int64) (map[string]int64, error) {
+	days := make(map[string]int64)
+	secret, err := s.client.KVv2(s.config.MountPath).Get(ctx, s.kvPath(fmt.Sprintf("apikey-usage/%d", id)))
+	if err != nil {
+		if isNotFoundError(err) {
+			return days, nil
+		}
+		return nil, fmt.Errorf("failed to get API key usage: %w", err)
+	}
+	if secret == nil {
+		return days, nil
+	}
+	for d := range secret.Data {
+		if requests, err := parseID(secret.Data, d); err == nil {
+			days[d] = requests
+		}
+	}
+	return days, nil
+}
+
+// CreateGrant creates a new role grant.
+func (s *Store) CreateGrant(ctx context.Context, grant *storage.GrantRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.nextGrantID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to generate grant ID: %w", err)
+	}
+	grant.ID = id
+	grant.CreatedAt = time.Now().UTC()
+
+	path := s.kvPath(fmt.Sprintf("grants/%d", grant.ID))
+	data := map[string]interface{}{
+		"id":             grant.ID,
+		"username":       grant.Username,
+		"api_key_id":     grant.APIKeyID,
+		"role":           grant.Role,
+		"context":        grant.Context,
+		"subject_prefix": grant.SubjectPrefix,
+		"created_by":     grant.CreatedBy,
+		"created_at":     grant.CreatedAt.Format(time.RFC3339),
+	}
+	if _, err := s.client.KVv2(s.config.MountPath).Put(ctx, path, data); err != nil {
+		return fmt.Errorf("failed to write grant: %w", err)
+	}
+	return nil
+}
+
+// GetGrantByID retrieves a role grant by ID.
+func (s *Store) GetGrantByID(ctx context.Context, id int64) (*storage.GrantRecord, error) {
+	path := s.kvPath(fmt.Sprintf("grants/%d", id))
+	secret, err := s.client.KVv2(s.config.MountPath).Get(ctx, path)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, storage.ErrGrantNotFound
+		}
+		return nil, fmt.Errorf("failed to get grant: %w", err)
+	}
+
+	// Check for deleted or empty secret
+	if secret == nil || secret.Data == nil || len(secret.Data) == 0 {
+		return nil, storage.ErrGrantNotFound
+	}
+
+	return parseGrantRecord(secret.Data)
+}
+
+// DeleteGrant deletes a role grant by ID.
+func (s *Store) DeleteGrant(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.GetGrantByID(ctx, id); err != nil {
+		return err
+	}
+
+	path := s.kvPath(fmt.Sprintf("grants/%d", id))
+	return s.client.KVv2(s.config.MountPath).Delete(ctx, path)
+}
+
+// ListGrants returns all role grants.
+func (s *Store) ListGrants(ctx context.Context) ([]*storage.GrantRecord, error) {
+	path := s.config.BasePath + "/grants"
+	secret, err := s.client.Logical().ListWithContext(ctx, s.config.MountPath+"/metadata/"+path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grants: %w", err)
+	}
+
+	grants := []*storage.GrantRecord{}
+	if secret == nil || secret.Data == nil {
+		return grants, nil
+	}
+
+	keys, ok := secret.Data["keys"].([]interface{})
+	if !ok {
+		return grants, nil
+	}
+
+	for _, key := range keys {
+		idStr, ok := key.(string)
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		grant, err := s.GetGrantByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		grants = append(grants, grant)
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].ID < grants[j].ID
+	})
+
+	return grants, nil
+}
+
+// CreateShareToken creates a new share token.
+func (s *Store) CreateShareToken(ctx context.Context, token *storage.ShareTokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.nextShareTokenID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to generate share token ID: %w", err)
+	}
+	token.ID = id
+
+	path := s.kvPath(fmt.Sprintf("sharetokens/%d", token.ID))
+	data := map[string]interface{}{
+		"id":           token.ID,
+		"token_hash":   token.TokenHash,
+		"token_prefix": token.TokenPrefix,
+		"name":         token.Name,
+		"context":      token.Context,
+		"subject":      token.Subject,
+		"created_by":   token.CreatedBy,
+		"created_at":   token.CreatedAt.Format(time.RFC3339),
+		"expires_at":   token.ExpiresAt.Format(time.RFC3339),
+	}
+	if _, err := s.client.KVv2(s.config.MountPath).Put(ctx, path, data); err != nil {
+		return fmt.Errorf("failed to write share token: %w", err)
+	}
+
+	indexPath := s.kvPath("indexes/sharetokens/hash/" + token.TokenHash)
+	if _, err := s.client.KVv2(s.config.MountPath).Put(ctx, indexPath, map[string]interface{}{"id": token.ID}); err != nil {
+		return fmt.Errorf("failed to write share token index: %w", err)
+	}
+	return nil
+}
+
+// GetShareTokenByID retrieves a share token by ID.
+func (s *Store) GetShareTokenByID(ctx context.Context, id int64) (*storage.ShareTokenRecord, error) {
+	path := s.kvPath(fmt.Sprintf("sharetokens/%d", id))
+	secret, err := s.client.KVv2(s.config.MountPath).Get(ctx, path)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, storage.ErrShareTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get share token: %w", err)
+	}
+
+	// Check for deleted or empty secret
+	if secret == nil || secret.Data == nil || len(secret.Data) == 0 {
+		return nil, storage.ErrShareTokenNotFound
+	}
+
+	return parseShareTokenRecord(secret.Data)
+}
+
+// GetShareTokenByHash retrieves a share token by the hash of its value.
+func (s *Store) GetShareTokenByHash(ctx context.Context, tokenHash string) (*storage.ShareTokenRecord, error) {
+	path := s.kvPath("indexes/sharetokens/hash/" + tokenHash)
+	secret, err := s.client.KVv2(s.config.MountPath).Get(ctx, path)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, storage.ErrShareTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to lookup share token: %w", err)
+	}
+	if secret == nil || secret.Data == nil || len(secret.Data) == 0 {
+		return nil, storage.ErrShareTokenNotFound
+	}
+
+	id, err := parseID(secret.Data, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetShareTokenByID(ctx, id)
+}
+
+// DeleteShareToken deletes a share token by ID.
+func (s *Store) DeleteShareToken(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := s.GetShareTokenByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	indexPath := s.kvPath("indexes/sharetokens/hash/" + token.TokenHash)
+	if err := s.client.KVv2(s.config.MountPath).Delete(ctx, indexPath); err != nil {
+		return fmt.Errorf("failed to delete share token index: %w", err)
+	}
+	path := s.kvPath(fmt.Sprintf("sharetokens/%d", id))
+	return s.client.KVv2(s.config.MountPath).Delete(ctx, path)
+}
+
+// ListShareTokens returns all share tokens.
+func (s *Store) ListShareTokens(ctx context.Context) ([]*storage.ShareTokenRecord, error) {
+	path := s.config.BasePath + "/sharetokens"
+	secret, err := s.client.Logical().ListWithContext(ctx, s.config.MountPath+"/metadata/"+path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share tokens: %w", err)
+	}
+
+	tokens := []*storage.ShareTokenRecord{}
+	if secret == nil || secret.Data == nil {
+		return tokens, nil
+	}
+
+	keys, ok := secret.Data["keys"].([]interface{})
+	if !ok {
+		return tokens, nil
+	}
+
+	for _, key := range keys {
+		idStr, ok := key.(string)
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		token, err := s.GetShareTokenByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].ID < tokens[j].ID
+	})
+
+	return tokens, nil
+}
+
+// CreateSession creates a new login session.
+func (s *Store) CreateSession(ctx context.Context, session *storage.SessionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.writeSession(ctx, session)
+}
+
+func (s *Store) writeSession(ctx context.Context, session *storage.SessionRecord) error {
+	path := s.kvPath("sessions/" + session.ID)
+	data := map[string]interface{}{
+		"id":           session.ID,
+		"refresh_hash": session.RefreshHash,
+		"user_id":      session.UserID,
+		"api_key_id":   session.APIKeyID,
+		"username":     session.Username,
+		"role":         session.Role,
+		"tenant":       session.Tenant,
+		"auth_method":  session.AuthMethod,
+		"created_at":   session.CreatedAt.Format(time.RFC3339),
+		"refreshed_at": session.RefreshedAt.Format(time.RFC3339),
+		"expires_at":   session.ExpiresAt.Format(time.RFC3339),
+	}
+	if _, err := s.client.KVv2(s.config.MountPath).Put(ctx, path, data); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// GetSession retrieves a login session by ID.
+func (s *Store) GetSession(ctx context.Context, id string) (*storage.SessionRecord, error) {
+	path := s.kvPath("sessions/" + id)
+	secret, err := s.client.KVv2(s.config.MountPath).Get(ctx, path)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, storage.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	// Check for deleted or empty secret
+	if secret == nil || secret.Data == nil || len(secret.Data) == 0 {
+		return nil, storage.ErrSessionNotFound
+	}
+
+	return parseSessionRecord(secret.Data)
+}
+
+// RefreshSession stores a session's new refresh token hash if its current
+// one is oldRefreshHash.
+func (s *Store) RefreshSession(ctx context.Context, session *storage.SessionRecord, oldRefreshHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.GetSession(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+	if existing.RefreshHash != oldRefreshHash {
+		return storage.ErrSessionNotFound
+	}
+	existing.RefreshHash = session.RefreshHash
+	existing.Role = session.Role
+	existing.Tenant = session.Tenant
+	existing.RefreshedAt = session.RefreshedAt
+	existing.ExpiresAt = session.ExpiresAt
+	return s.writeSession(ctx, existing)
+}
+
+// DeleteSession deletes a login session by ID. Its metadata is deleted too,
+// so revoked sessions do not accumulate in Vault.
+func (s *Store) DeleteSession(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.GetSession(ctx, id); err != nil {
+		return err
+	}
+	return s.client.KVv2(s.config.MountPath).DeleteMetadata(ctx, s.kvPath("sessions/"+id))
+}
+
+// DeleteUserSessions deletes every login session of a user.
+func (s *Store) DeleteUserSessions(ctx context.Context, userID int64) error {
+	return s.deleteSessionsWhere(ctx, func(session *storage.SessionRecord) bool {
+		return session.UserID == userID
+	})
+}
+
+// DeleteExpiredSessions deletes the login sessions that expired before the
+// given time.
+func (s *Store) DeleteExpiredSessions(ctx context.Context, before time.Time) error {
+	return s.deleteSessionsWhere(ctx, func(session *storage.SessionRecord) bool {
+		return session.ExpiresAt.Before(before)
+	})
+}
+
+// deleteSessionsWhere deletes the login sessions that match.
+func (s *Store) deleteSessionsWhere(ctx context.Context, match func(*storage.SessionRecord) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.config.BasePath + "/sessions"
+	secret, err := s.client.Logical().ListWithContext(ctx, s.config.MountPath+"/metadata/"+path)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil
+	}
+	keys, ok := secret.Data["keys"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, key := range keys {
+		id, ok := key.(string)
+		if !ok {
+			continue
+		}
+		session, err := s.GetSession(ctx, id)
+		if err != nil || !match(session) {
+			continue
+		}
+		if err := s.client.KVv2(s.config.MountPath).DeleteMetadata(ctx, s.kvPath("sessions/"+id)); err != nil {
+			return fmt.Errorf("failed to delete session: %w", err)
+		}
+	}
+	return nil
+}
+
 // Close closes the Vault client connection.
 func (s *Store) Close() error {
 	// Vault client doesn't need explicit closing
@@ -719,6 +1217,9 @@ func parseUserRecord(data map[string]interface{}) (*storage.UserRecord, error) {
 	if v, ok := data["enabled"].(bool); ok {
 		user.Enabled = v
 	}
+	if v, ok := data["tenant"].(string); ok {
+		user.Tenant = v
+	}
 	if v, ok := data["created_at"].(string); ok {
 		if t, err := time.Parse(time.RFC3339, v); err == nil {
 			user.CreatedAt = t
@@ -729,6 +1230,21 @@ func parseUserRecord(data map[string]interface{}) (*storage.UserRecord, error) {
 			user.UpdatedAt = t
 		}
 	}
+	if v, ok := data["password_changed_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			user.PasswordChangedAt = t
+		}
+	}
+	if v, ok := data["must_change_password"].(bool); ok {
+		user.MustChangePassword = v
+	}
+	if v, ok := data["password_history"].([]interface{}); ok {
+		for _, h := range v {
+			if hash, ok := h.(string); ok {
+				user.PasswordHistory = append(user.PasswordHistory, hash)
+			}
+		}
+	}
 
 	return user, nil
 }
@@ -775,9 +1291,161 @@ func parseAPIKeyRecord(data map[string]interface{}) (*storage.APIKeyRecord, erro
 			key.LastUsed = &t
 		}
 	}
+	if v, ok := data["scopes"].([]interface{}); ok {
+		key.Scopes = parseAPIKeyScopes(v)
+	}
 
 	return key, nil
 }
 
+// parseAPIKeyScopes converts the scopes of an API key as read back from KV.
+func parseAPIKeyScopes(values []interface{}) []storage.APIKeyScope {
+	var scopes []storage.APIKeyScope
+	for _, v := range values {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var scope storage.APIKeyScope
+		scope.Context, _ = m["context"].(string)
+		scope.SubjectPrefix, _ = m["subject_prefix"].(string)
+		if ops, ok := m["operations"].([]interface{}); ok {
+			for _, op := range ops {
+				if name, ok := op.(string); ok {
+					scope.Operations = append(scope.Operations, name)
+				}
+			}
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+func parseGrantRecord(data map[string]interface{}) (*storage.GrantRecord, error) {
+	grant := &storage.GrantRecord{}
+
+	id, err := parseID(data, "id")
+	if err != nil {
+		return nil, err
+	}
+	grant.ID = id
+
+	if apiKeyID, err := parseID(data, "api_key_id"); err == nil {
+		grant.APIKeyID = apiKeyID
+	}
+	if v, ok := data["username"].(string); ok {
+		grant.Username = v
+	}
+	if v, ok := data["role"].(string); ok {
+		grant.Role = v
+	}
+	if v, ok := data["context"].(string); ok {
+		grant.Context = v
+	}
+	if v, ok := data["subject_prefix"].(string); ok {
+		grant.SubjectPrefix = v
+	}
+	if v, ok := data["created_by"].(string); ok {
+		grant.CreatedBy = v
+	}
+	if v, ok := data["created_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			grant.CreatedAt = t
+		}
+	}
+
+	return grant, nil
+}
+
+func parseShareTokenRecord(data map[string]interface{}) (*storage.ShareTokenRecord, error) {
+	token := &storage.ShareTokenRecord{}
+
+	id, err := parseID(data, "id")
+	if err != nil {
+		return nil, err
+	}
+	token.ID = id
+
+	if v, ok := data["token_hash"].(string); ok {
+		token.TokenHash = v
+	}
+	if v, ok := data["token_prefix"].(string); ok {
+		token.TokenPrefix = v
+	}
+	if v, ok := data["name"].(string); ok {
+		token.Name = v
+	}
+	if v, ok := data["context"].(string); ok {
+		token.Context = v
+	}
+	if v, ok := data["subject"].(string); ok {
+		token.Subject = v
+	}
+	if v, ok := data["created_by"].(string); ok {
+		token.CreatedBy = v
+	}
+	if v, ok := data["created_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			token.CreatedAt = t
+		}
+	}
+	if v, ok := data["expires_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			token.ExpiresAt = t
+		}
+	}
+
+	return token, nil
+}
+
+func parseSessionRecord(data map[string]interface{}) (*storage.SessionRecord, error) {
+	session := &storage.SessionRecord{}
+
+	id, ok := data["id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing id")
+	}
+	session.ID = id
+
+	if v, ok := data["refresh_hash"].(string); ok {
+		session.RefreshHash = v
+	}
+	if userID, err := parseID(data, "user_id"); err == nil {
+		session.UserID = userID
+	}
+	if keyID, err := parseID(data, "api_key_id"); err == nil {
+		session.APIKeyID = keyID
+	}
+	if v, ok := data["username"].(string); ok {
+		session.Username = v
+	}
+	if v, ok := data["role"].(string); ok {
+		session.Role = v
+	}
+	if v, ok := data["tenant"].(string); ok {
+		session.Tenant = v
+	}
+	if v, ok := data["auth_method"].(string); ok {
+		session.AuthMethod = v
+	}
+	if v, ok := data["created_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			session.CreatedAt = t
+		}
+	}
+	if v, ok := data["refreshed_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			session.RefreshedAt = t
+		}
+	}
+	if v, ok := data["expires_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			session.ExpiresAt = t
+		}
+	}
+
+	return session, nil
+}
+
 // Ensure Store implements storage.AuthStorage
 var _ storage.AuthStorage = (*Store)(nil)
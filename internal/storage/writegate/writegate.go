@@ -0,0 +1,141 @@
+// Package writegate refuses writes while the storage backend could lose
+// them. It measures health signals of the backend, such as how far its
+// replicas lag behind, and closes while any of them is above its threshold.
+// While the gate is closed the registry refuses writes as if it were
+// READONLY, so that a registration fails instead of being acknowledged and
+// then lost in a failover. It opens again by itself once every signal is
+// back under its threshold.
+package writegate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Signal measures one health signal of the backend, such as a replication
+// lag or a query latency.
+type Signal func(ctx context.Context) (time.Duration, error)
+
+type signal struct {
+	name      string
+	threshold time.Duration
+	measure   Signal
+}
+
+// Gate closes while a registered signal is above its threshold or cannot be
+// measured. Signals are measured by Check, which Start runs periodically.
+type Gate struct {
+	timeout time.Duration
+	logger  *slog.Logger
+
+	mu      sync.RWMutex
+	signals []signal
+	reason  string // empty while the gate is open
+}
+
+// New creates an open gate without signals. Each measurement of a signal
+// may take up to timeout.
+func New(timeout time.Duration, logger *slog.Logger) *Gate {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Gate{timeout: timeout, logger: logger}
+}
+
+// Register adds a signal that closes the gate while it is above threshold.
+func (g *Gate) Register(name string, threshold time.Duration, measure Signal) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.signals = append(g.signals, signal{name: name, threshold: threshold, measure: measure})
+}
+
+// Signals returns the names of the registered signals.
+func (g *Gate) Signals() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	names := make([]string, len(g.signals))
+	for i, s := range g.signals {
+		names[i] = s.name
+	}
+	return names
+}
+
+// Reason describes why the gate is closed, or returns "" while it is open.
+func (g *Gate) Reason() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.reason
+}
+
+// Closed reports whether the gate is closed.
+func (g *Gate) Closed() bool {
+	return g.Reason() != ""
+}
+
+// Start runs Check at once and then every interval until stop is closed.
+func (g *Gate) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		g.Check(context.Background())
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				g.Check(context.Background())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Check measures every signal and closes the gate if any of them is above
+// its threshold or fails to be measured, or opens it otherwise. Changes are
+// logged.
+func (g *Gate) Check(ctx context.Context) {
+	g.mu.RLock()
+	signals := g.signals
+	g.mu.RUnlock()
+
+	var problems []string
+	for _, s := range signals {
+		if problem := g.measure(ctx, s); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	g.setReason(strings.Join(problems, "; "))
+}
+
+// measure measures one signal and describes the problem with it, if any.
+func (g *Gate) measure(ctx context.Context, s signal) string {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+	value, err := s.measure(ctx)
+	if err != nil {
+		return fmt.Sprintf("%s could not be measured: %v", s.name, err)
+	}
+	if value > s.threshold {
+		return fmt.Sprintf("%s is %s, above the %s threshold", s.name, value.Round(time.Millisecond), s.threshold)
+	}
+	return ""
+}
+
+// setReason records why the gate is closed, logging when it closes and
+// opens.
+func (g *Gate) setReason(reason string) {
+	g.mu.Lock()
+	previous := g.reason
+	g.reason = reason
+	g.mu.Unlock()
+
+	switch {
+	case reason != "" && previous == "":
+		g.logger.Warn("storage write gate closed, refusing writes", slog.String("reason", reason))
+	case reason == "" && previous != "":
+		g.logger.Info("storage write gate open again, accepting writes")
+	}
+}
@@ -0,0 +1,70 @@
+package writegate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGate_ClosesAndRecovers(t *testing.T) {
+	var lag atomic.Int64
+	g := New(time.Second, nil)
+	g.Register("postgresql_replication_lag", 5*time.Second, func(context.Context) (time.Duration, error) {
+		return time.Duration(lag.Load()), nil
+	})
+
+	g.Check(context.Background())
+	if g.Closed() {
+		t.Fatalf("gate closed without lag: %s", g.Reason())
+	}
+
+	lag.Store(int64(12 * time.Second))
+	g.Check(context.Background())
+	if !g.Closed() {
+		t.Fatal("gate open with lag above the threshold")
+	}
+	if reason := g.Reason(); !strings.Contains(reason, "postgresql_replication_lag is 12s, above the 5s threshold") {
+		t.Errorf("Reason() = %q", reason)
+	}
+
+	lag.Store(int64(time.Second))
+	g.Check(context.Background())
+	if g.Closed() {
+		t.Errorf("gate still closed after the lag recovered: %s", g.Reason())
+	}
+}
+
+func TestGate_ClosesWhenSignalFails(t *testing.T) {
+	g := New(time.Second, nil)
+	g.Register("cassandra_coordinator_latency", time.Second, func(context.Context) (time.Duration, error) {
+		return 0, errors.New("no hosts available")
+	})
+	g.Register("postgresql_replication_lag", time.Second, func(context.Context) (time.Duration, error) {
+		return 0, nil
+	})
+
+	g.Check(context.Background())
+	want := "cassandra_coordinator_latency could not be measured: no hosts available"
+	if reason := g.Reason(); reason != want {
+		t.Errorf("Reason() = %q, want %q", reason, want)
+	}
+	if names := g.Signals(); len(names) != 2 {
+		t.Errorf("Signals() = %v", names)
+	}
+}
+
+func TestGate_MeasurementTimesOut(t *testing.T) {
+	g := New(10*time.Millisecond, nil)
+	g.Register("slow", time.Second, func(ctx context.Context) (time.Duration, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	g.Check(context.Background())
+	if !strings.Contains(g.Reason(), "context deadline exceeded") {
+		t.Errorf("Reason() = %q", g.Reason())
+	}
+}
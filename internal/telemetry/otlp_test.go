@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestOTLPExporter_ExportSpans(t *testing.T) {
+	var got struct {
+		path, contentType, apiKey string
+		body                      map[string]any
+	}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.contentType = r.Header.Get("Content-Type")
+		got.apiKey = r.Header.Get("X-Api-Key")
+		b, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &got.body); err != nil {
+			t.Errorf("collector received invalid JSON: %v", err)
+		}
+	}))
+	defer collector.Close()
+
+	exporter, err := NewOTLPExporter(collector.URL, map[string]string{"X-Api-Key": "secret"})
+	if err != nil {
+		t.Fatalf("NewOTLPExporter: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "schema-registry"))),
+	)
+	ctx, parent := tp.Tracer("registry").Start(context.Background(), "registry.RegisterSchema")
+	_, child := tp.Tracer("registry").Start(ctx, "storage.create_schema")
+	child.SetAttributes(attribute.Int64("schema_registry.schema_id", 42), attribute.Bool("cached", false))
+	child.RecordError(errors.New("connection refused"))
+	child.SetStatus(codes.Error, "connection refused")
+	child.End()
+	parent.End()
+	_ = tp.Shutdown(context.Background())
+
+	if got.path != "/v1/traces" || got.contentType != "application/json" || got.apiKey != "secret" {
+		t.Fatalf("unexpected request: path=%q content-type=%q api-key=%q", got.path, got.contentType, got.apiKey)
+	}
+	resourceSpans := got.body["resourceSpans"].([]any)[0].(map[string]any)
+	attrs := resourceSpans["resource"].(map[string]any)["attributes"].([]any)
+	if kv := attrs[0].(map[string]any); kv["key"] != "service.name" || kv["value"].(map[string]any)["stringValue"] != "schema-registry" {
+		t.Errorf("unexpected resource attributes: %v", attrs)
+	}
+	span := resourceSpans["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	if span["name"] != "storage.create_schema" || span["traceId"] != parent.SpanContext().TraceID().String() ||
+		span["parentSpanId"] != parent.SpanContext().SpanID().String() {
+		t.Errorf("unexpected span: %v", span)
+	}
+	if _, ok := span["startTimeUnixNano"].(string); !ok {
+		t.Errorf("expected timestamps as strings, got %v", span["startTimeUnixNano"])
+	}
+	if status := span["status"].(map[string]any); status["code"] != float64(otlpStatusError) || status["message"] != "connection refused" {
+		t.Errorf("unexpected status: %v", status)
+	}
+	if kv := span["attributes"].([]any)[0].(map[string]any); kv["value"].(map[string]any)["intValue"] != "42" {
+		t.Errorf("expected an integer attribute as a string, got %v", kv)
+	}
+	if events := span["events"].([]any); len(events) != 1 || events[0].(map[string]any)["name"] != "exception" {
+		t.Errorf("expected the recorded error as an event, got %v", events)
+	}
+}
+
+func TestOTLPExporter_CollectorError(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	exporter, err := NewOTLPExporter(collector.URL+"/v1/traces", nil)
+	if err != nil {
+		t.Fatalf("NewOTLPExporter: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider()
+	_, span := tp.Tracer("test").Start(context.Background(), "request")
+	span.End()
+	ro := span.(sdktrace.ReadOnlySpan)
+	if err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{ro}); err == nil {
+		t.Error("expected an error when the collector rejects spans")
+	}
+}
+
+func TestNewOTLPExporter_InvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "localhost:4318", "ftp://collector"} {
+		if _, err := NewOTLPExporter(endpoint, nil); err == nil {
+			t.Errorf("expected an error for endpoint %q", endpoint)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Package telemetry sets up OpenTelemetry tracing. HTTP requests, registry
+// operations and storage calls record spans through the global tracer
+// provider; this package installs the provider that samples them and sends
+// them to an OTLP/HTTP collector, and the W3C trace context propagator that
+// continues the traces of callers sending a traceparent header.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+)
+
+// Setup installs the W3C trace context propagator and, when tracing is
+// enabled, a tracer provider exporting to the configured collector. The
+// returned function flushes pending spans and stops the provider; it does
+// nothing when tracing is disabled.
+func Setup(cfg config.TracingConfig, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := NewOTLPExporter(cfg.Endpoint, cfg.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.version", version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,35 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axonops/axonops-schema-registry/internal/config"
+)
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(config.TracingConfig{}, "test")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	defer func() { _ = shutdown(context.Background()) }()
+
+	// The caller's trace context is still picked up for log correlation.
+	header := http.Header{}
+	header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	if got := trace.SpanContextFromContext(ctx).TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("extracted trace ID = %q", got)
+	}
+}
+
+func TestSetup_InvalidEndpoint(t *testing.T) {
+	if _, err := Setup(config.TracingConfig{Enabled: true, Endpoint: "collector:4318"}, "test"); err == nil {
+		t.Error("expected an error for an invalid endpoint")
+	}
+}
@@ -0,0 +1,172 @@
+// Package tenant provides the per-tenant data encryption keys used to
+// encrypt schemas at rest.
+package tenant
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// MasterKeySize is the size in bytes of the instance master key.
+const MasterKeySize = 32
+
+// Keyring generates tenant keys, wraps them with the instance master key for
+// storage, and resolves the key for a registry context from the tenant that
+// owns it. Unwrapped keys and context owners are cached; both are immutable
+// once created.
+type Keyring struct {
+	store  storage.Storage
+	master cipher.AEAD
+
+	mu       sync.RWMutex
+	keys     map[string]cipher.AEAD // tenant name -> unwrapped key
+	contexts map[string]string      // registry context -> owning tenant
+}
+
+// NewKeyring creates a Keyring that reads tenants and contexts from store
+// and wraps tenant keys with masterKey, which must be MasterKeySize bytes.
+func NewKeyring(store storage.Storage, masterKey []byte) (*Keyring, error) {
+	if len(masterKey) != MasterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", MasterKeySize, len(masterKey))
+	}
+	master, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Keyring{
+		store:    store,
+		master:   master,
+		keys:     make(map[string]cipher.AEAD),
+		contexts: make(map[string]string),
+	}, nil
+}
+
+// DecodeMasterKey decodes a base64 master key and checks its size.
+func DecodeMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("master key is not valid base64: %w", err)
+	}
+	if len(key) != MasterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", MasterKeySize, len(key))
+	}
+	return key, nil
+}
+
+// NewTenantKey generates a key for tenant and returns it wrapped with the
+// master key, ready to be stored in the tenant record. The wrapped key is
+// bound to the tenant name and cannot be moved to another tenant.
+func (k *Keyring) NewTenantKey(tenant string) (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate tenant key: %w", err)
+	}
+	nonce := make([]byte, k.master.NonceSize(), k.master.NonceSize()+len(key)+k.master.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	wrapped := k.master.Seal(nonce, nonce, key, []byte(tenant))
+	return base64.StdEncoding.EncodeToString(wrapped), nil
+}
+
+// KeyFor returns the key schemas in registryCtx are encrypted with, named
+// after the tenant that owns the context. Contexts without a tenant, and
+// contexts that do not exist yet, return a nil AEAD.
+func (k *Keyring) KeyFor(ctx context.Context, registryCtx string) (string, cipher.AEAD, error) {
+	k.mu.RLock()
+	tenant, ok := k.contexts[registryCtx]
+	k.mu.RUnlock()
+
+	if !ok {
+		record, err := k.store.GetContext(ctx, registryCtx)
+		if errors.Is(err, storage.ErrContextNotFound) {
+			return "", nil, nil
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to resolve tenant of context %s: %w", registryCtx, err)
+		}
+		tenant = record.Tenant
+		k.mu.Lock()
+		k.contexts[registryCtx] = tenant
+		k.mu.Unlock()
+	}
+
+	if tenant == "" {
+		return "", nil, nil
+	}
+	aead, err := k.Key(ctx, tenant)
+	if err != nil {
+		return "", nil, err
+	}
+	return tenant, aead, nil
+}
+
+// Key returns the unwrapped key of the named tenant.
+func (k *Keyring) Key(ctx context.Context, tenant string) (cipher.AEAD, error) {
+	k.mu.RLock()
+	aead, ok := k.keys[tenant]
+	k.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	record, err := k.store.GetTenant(ctx, tenant)
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		return nil, fmt.Errorf("tenant %s: %w", tenant, storage.ErrSchemaKeyUnavailable)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.EncryptedKey == "" {
+		return nil, fmt.Errorf("tenant %s has no key: %w", tenant, storage.ErrSchemaKeyUnavailable)
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(record.EncryptedKey)
+	if err != nil || len(wrapped) < k.master.NonceSize() {
+		return nil, fmt.Errorf("tenant %s has a malformed key: %w", tenant, storage.ErrSchemaKeyUnavailable)
+	}
+	key, err := k.master.Open(nil, wrapped[:k.master.NonceSize()], wrapped[k.master.NonceSize():], []byte(tenant))
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s key cannot be unwrapped with the master key: %w", tenant, storage.ErrSchemaKeyUnavailable)
+	}
+	aead, err = newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.keys[tenant] = aead
+	k.mu.Unlock()
+	return aead, nil
+}
+
+// Forget drops the cached owner of registryCtx.
+func (k *Keyring) Forget(registryCtx string) {
+	k.mu.Lock()
+	delete(k.contexts, registryCtx)
+	k.mu.Unlock()
+}
+
+// ForgetTenant drops the cached key of tenant.
+func (k *Keyring) ForgetTenant(tenant string) {
+	k.mu.Lock()
+	delete(k.keys, tenant)
+	k.mu.Unlock()
+}
+
+// newAEAD returns an AES-GCM AEAD for key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,96 @@
+package tenant
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func newTestKeyring(t *testing.T, store storage.Storage, fill byte) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(store, bytes.Repeat([]byte{fill}, MasterKeySize))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return k
+}
+
+func TestKeyring_KeyFor(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	k := newTestKeyring(t, store, 1)
+
+	key, err := k.NewTenantKey("acme")
+	if err != nil {
+		t.Fatalf("NewTenantKey: %v", err)
+	}
+	if err := store.CreateTenant(ctx, &storage.TenantRecord{Name: "acme", EncryptedKey: key}); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".acme-orders", Tenant: "acme"}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+	if err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".shared"}); err != nil {
+		t.Fatalf("CreateContext: %v", err)
+	}
+
+	name, aead, err := k.KeyFor(ctx, ".acme-orders")
+	if err != nil || aead == nil || name != "acme" {
+		t.Fatalf("KeyFor(.acme-orders) = %q, %v, %v; want the acme key", name, aead, err)
+	}
+	for _, registryCtx := range []string{".shared", ".missing", "."} {
+		if _, aead, err := k.KeyFor(ctx, registryCtx); err != nil || aead != nil {
+			t.Errorf("KeyFor(%s) = %v, %v; want plaintext", registryCtx, aead, err)
+		}
+	}
+}
+
+func TestKeyring_WrongMasterKey(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	key, err := newTestKeyring(t, store, 1).NewTenantKey("acme")
+	if err != nil {
+		t.Fatalf("NewTenantKey: %v", err)
+	}
+	if err := store.CreateTenant(ctx, &storage.TenantRecord{Name: "acme", EncryptedKey: key}); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+
+	if _, err := newTestKeyring(t, store, 2).Key(ctx, "acme"); !errors.Is(err, storage.ErrSchemaKeyUnavailable) {
+		t.Errorf("expected ErrSchemaKeyUnavailable with another master key, got %v", err)
+	}
+}
+
+func TestKeyring_KeyBoundToTenant(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	k := newTestKeyring(t, store, 1)
+	key, err := k.NewTenantKey("acme")
+	if err != nil {
+		t.Fatalf("NewTenantKey: %v", err)
+	}
+	// A wrapped key copied to another tenant must not unwrap.
+	if err := store.CreateTenant(ctx, &storage.TenantRecord{Name: "globex", EncryptedKey: key}); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if _, err := k.Key(ctx, "globex"); !errors.Is(err, storage.ErrSchemaKeyUnavailable) {
+		t.Errorf("expected ErrSchemaKeyUnavailable, got %v", err)
+	}
+}
+
+func TestDecodeMasterKey(t *testing.T) {
+	if _, err := DecodeMasterKey(base64.StdEncoding.EncodeToString(make([]byte, MasterKeySize))); err != nil {
+		t.Errorf("expected valid key, got %v", err)
+	}
+	if _, err := DecodeMasterKey(base64.StdEncoding.EncodeToString(make([]byte, 16))); err == nil {
+		t.Error("expected error for a 16-byte key")
+	}
+	if _, err := DecodeMasterKey("not base64!"); err == nil {
+		t.Error("expected error for invalid base64")
+	}
+}
@@ -0,0 +1,158 @@
+package usage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxClientEntries is the number of distinct client, endpoint and
+// principal combinations a ClientTracker keeps by default.
+const DefaultMaxClientEntries = 10000
+
+// OtherClient is the client name that requests are counted under once the
+// tracker holds its maximum number of entries.
+const OtherClient = "other"
+
+// UnknownClient is the client name of requests without a User-Agent.
+const UnknownClient = "unknown"
+
+// maxClientTokenLength bounds the client name and version taken from a
+// User-Agent, since the header is chosen by the caller.
+const maxClientTokenLength = 64
+
+// ClientUsage is the number of requests a client library version made to an
+// endpoint as a principal.
+type ClientUsage struct {
+	Client    string
+	Version   string
+	Method    string
+	Endpoint  string // Route pattern, e.g. /subjects/{subject}/versions
+	Principal string // Empty when authentication is disabled
+	Requests  int64
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+type clientKey struct {
+	client, version, method, endpoint, principal string
+}
+
+// ClientTracker counts requests by client library and version, parsed from
+// the User-Agent header, so operators can see which SDKs still call the
+// registry before they rely on newer features or retire old behaviour.
+//
+// Counts are kept in memory since the process started and are not shared
+// between instances. A nil *ClientTracker is valid and records nothing.
+type ClientTracker struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[clientKey]*ClientUsage
+}
+
+// NewClientTracker creates a new client tracker that keeps at most
+// maxEntries distinct combinations; further ones are counted under
+// OtherClient.
+func NewClientTracker(maxEntries int) *ClientTracker {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxClientEntries
+	}
+	return &ClientTracker{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[clientKey]*ClientUsage),
+	}
+}
+
+// Record counts a request.
+func (t *ClientTracker) Record(userAgent, method, endpoint, principal string) {
+	if t == nil {
+		return
+	}
+	client, version := ParseUserAgent(userAgent)
+	key := clientKey{client, version, method, endpoint, principal}
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u := t.entries[key]
+	if u == nil {
+		if len(t.entries) >= t.maxEntries {
+			key = clientKey{client: OtherClient}
+			u = t.entries[key]
+		}
+		if u == nil {
+			u = &ClientUsage{
+				Client:    key.client,
+				Version:   key.version,
+				Method:    key.method,
+				Endpoint:  key.endpoint,
+				Principal: key.principal,
+				FirstSeen: now,
+			}
+			t.entries[key] = u
+		}
+	}
+	u.Requests++
+	u.LastSeen = now
+}
+
+// Query returns the counts of requests seen since the given time, optionally
+// only for one client, ordered by client, version, endpoint, method and
+// principal. Entries are filtered by their most recent request, so the
+// counts of returned entries still cover all requests since startup.
+func (t *ClientTracker) Query(client string, since time.Time) []ClientUsage {
+	t.mu.Lock()
+	out := make([]ClientUsage, 0, len(t.entries))
+	for _, u := range t.entries {
+		if client != "" && !strings.EqualFold(u.Client, client) {
+			continue
+		}
+		if u.LastSeen.Before(since) {
+			continue
+		}
+		out = append(out, *u)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Client != b.Client {
+			return a.Client < b.Client
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Principal < b.Principal
+	})
+	return out
+}
+
+// ParseUserAgent returns the client name and version of a User-Agent header,
+// taken from its first product token ("name/version"), for example
+// confluent-kafka-python/2.3.0 or Java/17.0.2. A header without a version
+// yields an empty version, and an empty header UnknownClient.
+func ParseUserAgent(userAgent string) (client, version string) {
+	fields := strings.Fields(userAgent)
+	if len(fields) == 0 {
+		return UnknownClient, ""
+	}
+	client, version, _ = strings.Cut(fields[0], "/")
+	return truncate(client), truncate(version)
+}
+
+func truncate(s string) string {
+	if len(s) > maxClientTokenLength {
+		return s[:maxClientTokenLength]
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package usage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent       string
+		client, version string
+	}{
+		{"confluent-kafka-python/2.3.0", "confluent-kafka-python", "2.3.0"},
+		{"Java/17.0.2", "Java", "17.0.2"},
+		{"Go-http-client/1.1", "Go-http-client", "1.1"},
+		{"curl/8.4.0 (x86_64-pc-linux-gnu)", "curl", "8.4.0"},
+		{"sarama", "sarama", ""},
+		{"", UnknownClient, ""},
+		{"   ", UnknownClient, ""},
+	}
+	for _, tt := range tests {
+		client, version := ParseUserAgent(tt.userAgent)
+		if client != tt.client || version != tt.version {
+			t.Errorf("ParseUserAgent(%q) = %q, %q; want %q, %q", tt.userAgent, client, version, tt.client, tt.version)
+		}
+	}
+}
+
+func TestClientTracker_Query(t *testing.T) {
+	tr := NewClientTracker(0)
+	start := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return start }
+	tr.Record("Java/11.0.1", "GET", "/schemas/ids/{id}", "legacy-app")
+
+	tr.now = func() time.Time { return start.Add(time.Hour) }
+	tr.Record("confluent-kafka-python/2.3.0", "POST", "/subjects/{subject}/versions", "orders-svc")
+	tr.Record("confluent-kafka-python/2.3.0", "POST", "/subjects/{subject}/versions", "orders-svc")
+	tr.Record("confluent-kafka-python/2.3.0", "GET", "/schemas/ids/{id}", "orders-svc")
+
+	all := tr.Query("", time.Time{})
+	if len(all) != 3 {
+		t.Fatalf("Query returned %d entries, want 3: %+v", len(all), all)
+	}
+	if all[0].Client != "Java" || all[1].Endpoint != "/schemas/ids/{id}" || all[2].Requests != 2 {
+		t.Errorf("unexpected order or counts: %+v", all)
+	}
+	if !all[2].FirstSeen.Equal(start.Add(time.Hour)) {
+		t.Errorf("FirstSeen = %v", all[2].FirstSeen)
+	}
+
+	if got := tr.Query("java", time.Time{}); len(got) != 1 || got[0].Principal != "legacy-app" {
+		t.Errorf("Query(java) = %+v", got)
+	}
+	if got := tr.Query("", start.Add(time.Minute)); len(got) != 2 {
+		t.Errorf("Query(since) returned %d entries, want 2", len(got))
+	}
+}
+
+func TestClientTracker_CapsEntries(t *testing.T) {
+	tr := NewClientTracker(2)
+	for i := 0; i < 5; i++ {
+		tr.Record(fmt.Sprintf("client%d/1.0", i), "GET", "/subjects", "")
+	}
+	got := tr.Query("", time.Time{})
+	if len(got) != 3 {
+		t.Fatalf("Query returned %d entries, want 3: %+v", len(got), got)
+	}
+	other := tr.Query(OtherClient, time.Time{})
+	if len(other) != 1 || other[0].Requests != 3 {
+		t.Errorf("other = %+v, want 3 requests", other)
+	}
+}
+
+func TestClientTracker_Nil(t *testing.T) {
+	var tr *ClientTracker
+	tr.Record("Java/17", "GET", "/subjects", "")
+}
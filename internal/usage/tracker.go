@@ -0,0 +1,243 @@
+// Package usage counts how often schemas are fetched, and by which client
+// libraries, so operators can see which schemas and SDK versions consumers
+// actually use before deleting or deprecating anything.
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// SchemaUsage is the number of fetches of a schema since a point in time.
+// Fetches by schema ID alone have an empty Subject and a zero Version.
+type SchemaUsage struct {
+	Context     string
+	SchemaID    int64
+	Subject     string
+	Version     int
+	Fetches     int64
+	LastFetched time.Time // Day of the most recent fetch; zero if never fetched
+}
+
+// bucket identifies one counter: a schema fetched in a given way on a day.
+type bucket struct {
+	context  string
+	schemaID int64
+	subject  string
+	version  int
+	day      time.Time
+}
+
+// Tracker counts schema fetches in memory and periodically adds them to
+// storage in daily buckets. Counting is cheap and never blocks a request on
+// storage; counts not yet flushed are lost if the process is killed.
+//
+// A nil *Tracker is valid and records nothing, so handlers need not check
+// whether usage tracking is enabled.
+type Tracker struct {
+	store  storage.Storage
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[bucket]int64
+}
+
+// NewTracker creates a new usage tracker.
+func NewTracker(store storage.Storage, logger *slog.Logger) *Tracker {
+	return &Tracker{
+		store:   store,
+		logger:  logger,
+		now:     time.Now,
+		pending: make(map[bucket]int64),
+	}
+}
+
+// RecordID records a fetch of a schema by its ID.
+func (t *Tracker) RecordID(registryCtx string, id int64) {
+	t.record(registryCtx, id, "", 0)
+}
+
+// RecordVersion records a fetch of a schema by subject and version.
+func (t *Tracker) RecordVersion(registryCtx, subject string, version int, id int64) {
+	t.record(registryCtx, id, subject, version)
+}
+
+func (t *Tracker) record(registryCtx string, id int64, subject string, version int) {
+	if t == nil {
+		return
+	}
+	b := bucket{registryCtx, id, subject, version, day(t.now())}
+	t.mu.Lock()
+	t.pending[b]++
+	t.mu.Unlock()
+}
+
+// Start starts a background goroutine that flushes counts to storage every
+// interval. When the stop channel is closed, remaining counts are flushed
+// once more before the goroutine exits.
+func (t *Tracker) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.Flush(context.Background())
+			case <-stop:
+				t.Flush(context.Background())
+				return
+			}
+		}
+	}()
+}
+
+// Flush adds the counts recorded since the last flush to storage. If the
+// write fails, the counts are kept and retried on the next flush.
+func (t *Tracker) Flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[bucket]int64)
+	t.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	records := make([]*storage.SchemaUsageRecord, 0, len(pending))
+	for b, count := range pending {
+		records = append(records, &storage.SchemaUsageRecord{
+			Context:  b.context,
+			SchemaID: b.schemaID,
+			Subject:  b.subject,
+			Version:  b.version,
+			Day:      b.day,
+			Count:    count,
+		})
+	}
+	if err := t.store.AddSchemaUsage(ctx, records); err != nil {
+		t.logger.Error("schema usage: failed to flush counts",
+			slog.Int("buckets", len(records)),
+			slog.String("error", err.Error()),
+		)
+		t.mu.Lock()
+		for b, count := range pending {
+			t.pending[b] += count
+		}
+		t.mu.Unlock()
+	}
+}
+
+// Query returns fetch counts since the given time, including counts not yet
+// flushed, ordered by context, schema ID, subject and version. Counts are
+// kept per day, so since is rounded down to the start of its UTC day.
+func (t *Tracker) Query(ctx context.Context, since time.Time) ([]SchemaUsage, error) {
+	stored, err := t.store.ListSchemaUsage(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	since = day(since)
+	totals := make(map[bucket]*SchemaUsage)
+	add := func(b bucket, count int64) {
+		if b.day.Before(since) {
+			return
+		}
+		key := bucket{b.context, b.schemaID, b.subject, b.version, time.Time{}}
+		u := totals[key]
+		if u == nil {
+			u = &SchemaUsage{Context: b.context, SchemaID: b.schemaID, Subject: b.subject, Version: b.version}
+			totals[key] = u
+		}
+		u.Fetches += count
+		if b.day.After(u.LastFetched) {
+			u.LastFetched = b.day
+		}
+	}
+	for _, rec := range stored {
+		add(bucket{rec.Context, rec.SchemaID, rec.Subject, rec.Version, day(rec.Day)}, rec.Count)
+	}
+	t.mu.Lock()
+	for b, count := range t.pending {
+		add(b, count)
+	}
+	t.mu.Unlock()
+
+	out := make([]SchemaUsage, 0, len(totals))
+	for _, u := range totals {
+		out = append(out, *u)
+	}
+	sortUsage(out)
+	return out, nil
+}
+
+// Unused returns the live subject versions that were not fetched since the
+// given time, neither by subject and version nor by their schema ID.
+func (t *Tracker) Unused(ctx context.Context, since time.Time) ([]SchemaUsage, error) {
+	used, err := t.Query(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	type idKey struct {
+		context string
+		id      int64
+	}
+	type versionKey struct {
+		context, subject string
+		version          int
+	}
+	usedIDs := make(map[idKey]bool)
+	usedVersions := make(map[versionKey]bool)
+	for _, u := range used {
+		if u.Subject == "" {
+			usedIDs[idKey{u.Context, u.SchemaID}] = true
+		} else {
+			usedVersions[versionKey{u.Context, u.Subject, u.Version}] = true
+		}
+	}
+
+	contexts, err := t.store.ListContexts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := []SchemaUsage{}
+	for _, registryCtx := range contexts {
+		err := t.store.IterateSchemas(ctx, registryCtx, false, func(s *storage.SchemaRecord) error {
+			if !usedIDs[idKey{registryCtx, s.ID}] && !usedVersions[versionKey{registryCtx, s.Subject, s.Version}] {
+				out = append(out, SchemaUsage{Context: registryCtx, SchemaID: s.ID, Subject: s.Subject, Version: s.Version})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sortUsage(out)
+	return out, nil
+}
+
+func sortUsage(u []SchemaUsage) {
+	sort.Slice(u, func(i, j int) bool {
+		a, b := u[i], u[j]
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.SchemaID != b.SchemaID {
+			return a.SchemaID < b.SchemaID
+		}
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		return a.Version < b.Version
+	})
+}
+
+// day returns midnight UTC of the day containing t.
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,137 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+	"github.com/axonops/axonops-schema-registry/internal/storage/memory"
+)
+
+func newTestTracker(store storage.Storage) *Tracker {
+	return NewTracker(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func createSchema(t *testing.T, store storage.Storage, registryCtx, subject, schema string) *storage.SchemaRecord {
+	t.Helper()
+	rec := &storage.SchemaRecord{
+		Subject:     subject,
+		SchemaType:  storage.SchemaTypeAvro,
+		Schema:      schema,
+		Fingerprint: registryCtx + subject + schema,
+	}
+	if err := store.CreateSchema(context.Background(), registryCtx, rec); err != nil {
+		t.Fatalf("CreateSchema: %v", err)
+	}
+	return rec
+}
+
+func TestTracker_QueryMergesStoredAndPending(t *testing.T) {
+	store := memory.NewStore()
+	tr := newTestTracker(store)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return now.AddDate(0, 0, -3) }
+	tr.RecordID(".", 1)
+	tr.RecordVersion(".", "orders", 1, 1)
+	tr.Flush(ctx)
+
+	tr.now = func() time.Time { return now }
+	tr.RecordID(".", 1)
+	tr.RecordID(".", 1)
+	tr.RecordID(".team", 1)
+
+	usage, err := tr.Query(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	want := []SchemaUsage{
+		{Context: ".", SchemaID: 1, Fetches: 3, LastFetched: today},
+		{Context: ".", SchemaID: 1, Subject: "orders", Version: 1, Fetches: 1, LastFetched: today.AddDate(0, 0, -3)},
+		{Context: ".team", SchemaID: 1, Fetches: 1, LastFetched: today},
+	}
+	if len(usage) != len(want) {
+		t.Fatalf("Query returned %d entries, want %d: %+v", len(usage), len(want), usage)
+	}
+	for i := range want {
+		if usage[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, usage[i], want[i])
+		}
+	}
+
+	// since is rounded down to its day, so the flushed counts from three
+	// days ago drop out while today's pending counts remain.
+	recent, err := tr.Query(ctx, now.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Fetches != 2 || recent[1].Context != ".team" {
+		t.Errorf("recent usage = %+v", recent)
+	}
+}
+
+func TestTracker_Unused(t *testing.T) {
+	store := memory.NewStore()
+	tr := newTestTracker(store)
+	ctx := context.Background()
+
+	byID := createSchema(t, store, ".", "orders", `"string"`)
+	byVersion := createSchema(t, store, ".", "payments", `"int"`)
+	unused := createSchema(t, store, ".", "legacy", `"long"`)
+
+	tr.RecordID(".", byID.ID)
+	tr.RecordVersion(".", "payments", byVersion.Version, byVersion.ID)
+
+	got, err := tr.Unused(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("Unused: %v", err)
+	}
+	if len(got) != 1 || got[0].Subject != "legacy" || got[0].SchemaID != unused.ID || got[0].Fetches != 0 {
+		t.Errorf("Unused = %+v, want only legacy", got)
+	}
+}
+
+type failingUsageStore struct {
+	storage.Storage
+	fail bool
+}
+
+func (s *failingUsageStore) AddSchemaUsage(ctx context.Context, records []*storage.SchemaUsageRecord) error {
+	if s.fail {
+		return errors.New("storage unavailable")
+	}
+	return s.Storage.AddSchemaUsage(ctx, records)
+}
+
+func TestTracker_FlushFailureKeepsCounts(t *testing.T) {
+	store := &failingUsageStore{Storage: memory.NewStore(), fail: true}
+	tr := newTestTracker(store)
+	ctx := context.Background()
+
+	tr.RecordID(".", 7)
+	tr.Flush(ctx)
+	tr.RecordID(".", 7)
+
+	store.fail = false
+	tr.Flush(ctx)
+
+	stored, err := store.ListSchemaUsage(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("ListSchemaUsage: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Count != 2 {
+		t.Errorf("stored usage = %+v, want one bucket with 2 fetches", stored)
+	}
+}
+
+func TestTracker_NilIsNoop(t *testing.T) {
+	var tr *Tracker
+	tr.RecordID(".", 1)
+	tr.RecordVersion(".", "orders", 1, 1)
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// cache holds schemas the client has fetched or registered. Schema IDs and
+// registered versions never change meaning, so entries are never stale; a
+// deleted schema stays cached until ClearCache.
+type cache struct {
+	mu       sync.RWMutex
+	byID     map[int64]*Schema
+	ids      map[string]int64           // subject + schema key -> ID
+	versions map[string]*SubjectVersion // subject + schema key -> lookup result
+}
+
+func newCache() *cache {
+	c := &cache{}
+	c.clear()
+	return c
+}
+
+func (c *cache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byID = make(map[int64]*Schema)
+	c.ids = make(map[string]int64)
+	c.versions = make(map[string]*SubjectVersion)
+}
+
+func (c *cache) schema(id int64) (*Schema, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.byID[id]
+	return s, ok
+}
+
+func (c *cache) putSchema(id int64, s *Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byID[id] = s
+}
+
+func (c *cache) id(key string) (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.ids[key]
+	return id, ok
+}
+
+func (c *cache) putID(key string, id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[key] = id
+}
+
+func (c *cache) version(key string) (*SubjectVersion, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.versions[key]
+	return v, ok
+}
+
+func (c *cache) putVersion(key string, v *SubjectVersion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions[key] = v
+	c.ids[key] = v.ID
+}
+
+// schemaKey identifies a schema registered under a subject. The whole
+// schema is part of the key, so two schemas never share an entry.
+func schemaKey(subject string, s Schema, normalize bool) string {
+	data, _ := json.Marshal(struct {
+		Subject   string `json:"s"`
+		Schema    Schema `json:"d"`
+		Normalize bool   `json:"n"`
+	}{subject, s, normalize})
+	return string(data)
+}
@@ -0,0 +1,417 @@
+// Package client is a Go client for the AxonOps Schema Registry REST API.
+//
+// It covers the Confluent-compatible registry endpoints — schemas, subjects,
+// compatibility, config, mode and contexts — with typed requests and
+// responses, supports basic, API key, bearer token and mutual TLS
+// authentication, retries transient failures with exponential backoff, and
+// caches schemas by ID and by subject so that serializers and deserializers
+// using the Confluent wire format (see EncodeWireFormat) only call the
+// registry once per schema.
+//
+//	c, err := client.New("https://registry:8081", client.WithBasicAuth("app", "secret"))
+//	if err != nil {
+//		return err
+//	}
+//	id, err := c.Register(ctx, "orders-value", client.Schema{Schema: avroSchema})
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// contentType is the media type of registry requests and responses.
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// Retry defaults, used unless overridden with WithRetry.
+const (
+	DefaultMaxRetries     = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+)
+
+// Client calls a schema registry over HTTP. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	tlsConfig  *tls.Config
+	headers    http.Header
+	auth       string
+	userAgent  string
+
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	// registryContext prefixes every path with /contexts/{name} when set.
+	registryContext string
+
+	cache *cache
+
+	// optErr records an option that failed, returned by New.
+	optErr error
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. WithTLSConfig and
+// WithClientCertificate replace its transport.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// WithBasicAuth authenticates with a username and password.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		c.auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+}
+
+// WithBearerToken authenticates with a JWT, OIDC or share token.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.auth = "Bearer " + token
+	}
+}
+
+// WithAPIKey authenticates with an API key sent in header, which must match
+// the registry's security.auth.api_key.header setting. An empty header
+// defaults to X-API-Key.
+func WithAPIKey(header, key string) Option {
+	return func(c *Client) {
+		if header == "" {
+			header = "X-API-Key"
+		}
+		c.headers.Set(header, key)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration for HTTPS connections, such as
+// a custom root CA pool or client certificates for mutual TLS.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithClientCertificate authenticates with a client certificate (mutual
+// TLS), loaded from PEM-encoded certificate and key files. Errors loading
+// the files are returned by New.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *Client) {
+		if c.tlsConfig == nil {
+			c.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		} else {
+			c.tlsConfig = c.tlsConfig.Clone()
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.optErr = fmt.Errorf("load client certificate: %w", err)
+			return
+		}
+		c.tlsConfig.Certificates = append(c.tlsConfig.Certificates, cert)
+	}
+}
+
+// WithHeader sets an extra header on every request, such as the tenant
+// override header of a super admin.
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(name, value)
+	}
+}
+
+// WithUserAgent sets the User-Agent header, which the registry uses to count
+// requests per client library.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithRetry sets how often failed requests are retried and the bounds of
+// the exponential backoff between attempts. maxRetries of zero disables
+// retries.
+func WithRetry(maxRetries int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.initialBackoff = initialBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithRegistryContext sends every request to the named registry context
+// instead of the default context.
+func WithRegistryContext(name string) Option {
+	return func(c *Client) {
+		c.registryContext = name
+	}
+}
+
+// WithoutCache disables the schema cache, so every lookup calls the registry.
+func WithoutCache() Option {
+	return func(c *Client) {
+		c.cache = nil
+	}
+}
+
+// New creates a client for the registry at baseURL. A comma-separated list
+// of URLs, as accepted by Confluent clients, uses the first URL.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	if idx := strings.Index(baseURL, ","); idx >= 0 {
+		baseURL = baseURL[:idx]
+	}
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid registry URL %q: must be an http or https URL", baseURL)
+	}
+
+	c := &Client{
+		baseURL:        baseURL,
+		headers:        make(http.Header),
+		maxRetries:     DefaultMaxRetries,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		cache:          newCache(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.optErr != nil {
+		return nil, c.optErr
+	}
+	if c.maxRetries < 0 {
+		return nil, errors.New("max retries must not be negative")
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if c.tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = c.tlsConfig
+		hc := *c.httpClient
+		hc.Transport = transport
+		c.httpClient = &hc
+	}
+	return c, nil
+}
+
+// BaseURL returns the registry URL requests are sent to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// ClearCache drops every cached schema.
+func (c *Client) ClearCache() {
+	if c.cache != nil {
+		c.cache.clear()
+	}
+}
+
+// do sends a request to path within the client's registry context and
+// decodes a successful JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	_, err := c.send(ctx, method, c.scoped(path), query, in, out)
+	return err
+}
+
+// scoped prefixes path with the client's registry context, if any.
+func (c *Client) scoped(path string) string {
+	if c.registryContext == "" {
+		return path
+	}
+	return "/contexts/" + url.PathEscape(c.registryContext) + path
+}
+
+// send sends a request and decodes a successful JSON response into out,
+// returning the response headers. Network errors, 429 and 5xx responses
+// are retried with backoff; other error responses are returned as *Error
+// without retrying.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, in, out interface{}) (http.Header, error) {
+	var body []byte
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		body = data
+	}
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		header, retryAfter, err := c.attempt(ctx, method, target, body, out)
+		if err == nil {
+			return header, nil
+		}
+		if !retryable(err) || attempt >= c.maxRetries {
+			return nil, err
+		}
+
+		wait := c.backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends one request. It returns the Retry-After delay of a
+// throttled response alongside the error.
+func (c *Client) attempt(ctx context.Context, method, target string, body []byte, out interface{}) (http.Header, time.Duration, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", contentType)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.auth != "" {
+		req.Header.Set("Authorization", c.auth)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, &networkError{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var retryAfter time.Duration
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return nil, retryAfter, newError(resp)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.Header, 0, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, 0, fmt.Errorf("decode response: %w", err)
+	}
+	return resp.Header, 0, nil
+}
+
+// list fetches every page of a paginated list endpoint, following the
+// registry's next-page Link headers.
+func list[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	var all []T
+	for {
+		var page []T
+		header, err := c.send(ctx, http.MethodGet, c.scoped(path), query, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) == 0 || !strings.Contains(header.Get("Link"), `rel="next"`) {
+			return all, nil
+		}
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		query.Set("offset", strconv.Itoa(offset+len(page)))
+	}
+}
+
+// backoff returns the delay before retry attempt+1: exponential from the
+// initial backoff, capped at the maximum, with up to 20% jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.initialBackoff
+	for i := 0; i < attempt && wait < c.maxBackoff; i++ {
+		wait *= 2
+	}
+	if c.maxBackoff > 0 && wait > c.maxBackoff {
+		wait = c.maxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait - time.Duration(rand.Int64N(int64(wait)/5+1))
+}
+
+// networkError marks a request that failed before a response was received.
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string { return e.err.Error() }
+func (e *networkError) Unwrap() error { return e.err }
+
+// retryable reports whether a failed request may succeed if sent again.
+func retryable(err error) bool {
+	var netErr *networkError
+	if errors.As(err, &netErr) {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return false
+}
+
+// subjectPath returns the path of a subject with optional suffix elements.
+func subjectPath(subject string, elems ...string) string {
+	path := "/subjects/" + url.PathEscape(subject)
+	for _, e := range elems {
+		path += "/" + e
+	}
+	return path
+}
+
+// versionString formats a version for a path; zero or negative means latest.
+func versionString(version int) string {
+	if version <= 0 {
+		return "latest"
+	}
+	return strconv.Itoa(version)
+}
+
+// boolQuery adds name=true to q when set.
+func boolQuery(q url.Values, name string, set bool) {
+	if set {
+		q.Set(name, "true")
+	}
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient starts a server with handler and returns a client for it
+// that retries without waiting.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	opts = append([]Option{WithRetry(2, time.Millisecond, time.Millisecond)}, opts...)
+	c, err := New(srv.URL, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestNew_InvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "registry:8081", "ftp://registry"} {
+		if _, err := New(raw); err == nil {
+			t.Errorf("New(%q) succeeded", raw)
+		}
+	}
+	c, err := New("http://a:8081/, http://b:8081")
+	if err != nil || c.BaseURL() != "http://a:8081" {
+		t.Errorf("New with URL list = %v, %v", c, err)
+	}
+}
+
+func TestNew_ClientCertificateError(t *testing.T) {
+	if _, err := New("https://registry", WithClientCertificate("missing.crt", "missing.key")); err == nil {
+		t.Error("expected an error for a missing client certificate")
+	}
+}
+
+func TestClient_Auth(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    Option
+		header string
+		want   string
+	}{
+		{"basic", WithBasicAuth("alice", "secret"), "Authorization", "Basic YWxpY2U6c2VjcmV0"},
+		{"bearer", WithBearerToken("tok"), "Authorization", "Bearer tok"},
+		{"api key", WithAPIKey("", "sr_key"), "X-API-Key", "sr_key"},
+		{"custom api key header", WithAPIKey("X-Registry-Key", "sr_key"), "X-Registry-Key", "sr_key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(tt.header)
+				w.Write([]byte(`["AVRO"]`))
+			}, tt.opt)
+			if _, err := c.GetSchemaTypes(context.Background()); err != nil {
+				t.Fatalf("GetSchemaTypes: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, `{"error_code":50001,"message":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[1,2]`))
+	})
+	versions, err := c.ListVersions(context.Background(), "orders-value", false)
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("ListVersions = %v, %v", versions, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+
+	// Retries give up after the configured number of attempts.
+	calls.Store(0)
+	down := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"error_code":50001,"message":"unavailable"}`, http.StatusInternalServerError)
+	})
+	if _, err := down.ListVersions(context.Background(), "orders-value", false); ErrorCode(err) != 50001 {
+		t.Errorf("err = %v, want error code 50001", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestClient_ErrorsAreNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_code":40401,"message":"Subject 'x' not found."}`))
+	})
+	_, err := c.GetLatestVersion(context.Background(), "x")
+	if !IsNotFound(err) || ErrorCode(err) != ErrorCodeSubjectNotFound {
+		t.Fatalf("err = %v, want subject not found", err)
+	}
+	if !strings.Contains(err.Error(), "Subject 'x' not found.") {
+		t.Errorf("err = %q", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestClient_RegisterCachesIDs(t *testing.T) {
+	var registers, fetches atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/subjects/orders-value/versions":
+			registers.Add(1)
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["schemaType"] != "JSON" || r.URL.Query().Get("normalize") != "true" {
+				t.Errorf("register body = %v, query = %s", body, r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"id":7}`))
+		case r.URL.Path == "/schemas/ids/7":
+			fetches.Add(1)
+			w.Write([]byte(`{"schema":"{}","schemaType":"JSON"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+	schema := Schema{Schema: "{}", SchemaType: SchemaTypeJSON}
+
+	for i := 0; i < 3; i++ {
+		id, err := c.Register(ctx, "orders-value", schema, true)
+		if err != nil || id != 7 {
+			t.Fatalf("Register = %d, %v", id, err)
+		}
+	}
+	if registers.Load() != 1 {
+		t.Errorf("registers = %d, want 1", registers.Load())
+	}
+
+	for i := 0; i < 3; i++ {
+		if s, err := c.GetSchemaByID(ctx, 7); err != nil || s.SchemaType != SchemaTypeJSON {
+			t.Fatalf("GetSchemaByID = %+v, %v", s, err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("fetches = %d, want 1", fetches.Load())
+	}
+
+	c.ClearCache()
+	if _, err := c.Register(ctx, "orders-value", schema, true); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if registers.Load() != 2 {
+		t.Errorf("registers after ClearCache = %d, want 2", registers.Load())
+	}
+}
+
+func TestClient_SchemaForMessage(t *testing.T) {
+	var fetches atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.URL.Path != "/schemas/ids/42" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"schema":"\"string\"","schemaType":"AVRO"}`))
+	})
+
+	msg, err := EncodeWireFormat(42, []byte("payload"))
+	if err != nil {
+		t.Fatalf("EncodeWireFormat: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		schema, payload, err := c.SchemaForMessage(context.Background(), msg)
+		if err != nil || schema.Schema != `"string"` || string(payload) != "payload" {
+			t.Fatalf("SchemaForMessage = %+v, %q, %v", schema, payload, err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("fetches = %d, want 1", fetches.Load())
+	}
+}
+
+func TestClient_RegistryContext(t *testing.T) {
+	var paths []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/contexts":
+			w.Write([]byte(`[".",".staging"]`))
+		default:
+			w.Write([]byte(`{"compatibilityLevel":"FULL"}`))
+		}
+	}, WithRegistryContext(".staging"))
+
+	ctx := context.Background()
+	cfg, err := c.GetConfig(ctx, "orders-value", true)
+	if err != nil || cfg.Level() != CompatibilityFull {
+		t.Fatalf("GetConfig = %+v, %v", cfg, err)
+	}
+	if _, err := c.ListContexts(ctx); err != nil {
+		t.Fatalf("ListContexts: %v", err)
+	}
+	want := []string{"/contexts/.staging/config/orders-value", "/contexts"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestClient_ListSubjectsFollowsPages(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "":
+			w.Header().Set("Link", `</subjects?limit=2&offset=2>; rel="next"`)
+			w.Write([]byte(`["a","b"]`))
+		case "2":
+			w.Write([]byte(`["c"]`))
+		default:
+			t.Errorf("unexpected offset %q", r.URL.Query().Get("offset"))
+		}
+	})
+	subjects, err := c.ListSubjects(context.Background(), ListSubjectsOptions{})
+	if err != nil || strings.Join(subjects, ",") != "a,b,c" {
+		t.Errorf("ListSubjects = %v, %v", subjects, err)
+	}
+}
+
+func TestClient_ConfigAndMode(t *testing.T) {
+	var got []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, r.Method+" "+r.URL.String())
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/config"):
+			if r.Method == http.MethodPut && (body["compatibility"] != "FULL" || body["compatibilityLevel"] != nil) {
+				t.Errorf("config body = %v", body)
+			}
+			w.Write([]byte(`{"compatibility":"FULL"}`))
+		default:
+			w.Write([]byte(`{"mode":"IMPORT"}`))
+		}
+	})
+	ctx := context.Background()
+
+	if _, err := c.SetConfig(ctx, "", Config{CompatibilityLevel: CompatibilityFull}); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	mode, err := c.SetMode(ctx, "orders-value", ModeImport, true)
+	if err != nil || mode != ModeImport {
+		t.Fatalf("SetMode = %q, %v", mode, err)
+	}
+	if err := c.DeleteMode(ctx, ""); err != nil {
+		t.Fatalf("DeleteMode: %v", err)
+	}
+	want := []string{"PUT /config", "PUT /mode/orders-value?force=true", "DELETE /mode"}
+	if strings.Join(got, " | ") != strings.Join(want, " | ") {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+}
+
+func TestClient_TestCompatibility(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/compatibility/subjects/orders-value/versions/latest" || r.URL.Query().Get("verbose") != "true" {
+			t.Errorf("request = %s", r.URL)
+		}
+		w.Write([]byte(`{"is_compatible":false,"messages":["m"],"incompatibilities":[{"code":"TYPE_MISMATCH","path":"/fields/0","message":"m"}]}`))
+	})
+	res, err := c.TestCompatibility(context.Background(), "orders-value", 0, Schema{Schema: `"int"`}, true)
+	if err != nil {
+		t.Fatalf("TestCompatibility: %v", err)
+	}
+	if res.IsCompatible || len(res.Incompatibilities) != 1 || res.Incompatibilities[0].Path != "/fields/0" {
+		t.Errorf("result = %+v", res)
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// configPath returns the config or mode path of subject, or of the
+// registry context when subject is empty.
+func configPath(resource, subject string) string {
+	if subject == "" {
+		return "/" + resource
+	}
+	return "/" + resource + "/" + url.PathEscape(subject)
+}
+
+// GetConfig returns the config of subject, or the global config when
+// subject is empty. With defaultToGlobal, a subject without its own config
+// returns the config in effect for it.
+func (c *Client) GetConfig(ctx context.Context, subject string, defaultToGlobal bool) (*Config, error) {
+	q := url.Values{}
+	boolQuery(q, "defaultToGlobal", defaultToGlobal)
+	var cfg Config
+	if err := c.do(ctx, http.MethodGet, configPath("config", subject), q, nil, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SetConfig sets the config of subject, or the global config when subject
+// is empty, and returns the config as stored.
+func (c *Client) SetConfig(ctx context.Context, subject string, cfg Config) (*Config, error) {
+	cfg.Compatibility = cfg.Level()
+	cfg.CompatibilityLevel = ""
+	var out Config
+	if err := c.do(ctx, http.MethodPut, configPath("config", subject), nil, cfg, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetCompatibility sets the compatibility level of subject, or the global
+// level when subject is empty.
+func (c *Client) SetCompatibility(ctx context.Context, subject, level string) error {
+	_, err := c.SetConfig(ctx, subject, Config{Compatibility: level})
+	return err
+}
+
+// DeleteConfig deletes the config of subject, or resets the global config
+// when subject is empty.
+func (c *Client) DeleteConfig(ctx context.Context, subject string) error {
+	return c.do(ctx, http.MethodDelete, configPath("config", subject), nil, nil, nil)
+}
+
+// GetMode returns the mode of subject, or the global mode when subject is
+// empty. With defaultToGlobal, a subject without its own mode returns the
+// mode in effect for it.
+func (c *Client) GetMode(ctx context.Context, subject string, defaultToGlobal bool) (string, error) {
+	q := url.Values{}
+	boolQuery(q, "defaultToGlobal", defaultToGlobal)
+	var out struct {
+		Mode string `json:"mode"`
+	}
+	err := c.do(ctx, http.MethodGet, configPath("mode", subject), q, nil, &out)
+	return out.Mode, err
+}
+
+// SetMode sets the mode of subject, or the global mode when subject is
+// empty. force allows switching to IMPORT mode while schemas exist.
+func (c *Client) SetMode(ctx context.Context, subject, mode string, force bool) (string, error) {
+	q := url.Values{}
+	boolQuery(q, "force", force)
+	in := struct {
+		Mode string `json:"mode"`
+	}{mode}
+	var out struct {
+		Mode string `json:"mode"`
+	}
+	err := c.do(ctx, http.MethodPut, configPath("mode", subject), q, in, &out)
+	return out.Mode, err
+}
+
+// DeleteMode deletes the mode of subject, or resets the global mode when
+// subject is empty.
+func (c *Client) DeleteMode(ctx context.Context, subject string) error {
+	return c.do(ctx, http.MethodDelete, configPath("mode", subject), nil, nil, nil)
+}
+
+// ListContexts returns the registry contexts visible to the caller. It
+// ignores WithRegistryContext.
+func (c *Client) ListContexts(ctx context.Context) ([]string, error) {
+	var contexts []string
+	_, err := c.send(ctx, http.MethodGet, "/contexts", nil, nil, &contexts)
+	return contexts, err
+}
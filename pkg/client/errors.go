@@ -0,0 +1,87 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Registry error codes, as returned in the error_code field of error
+// responses. See the Error Code Reference in docs/troubleshooting.md for the
+// full list.
+const (
+	ErrorCodeSubjectNotFound           = 40401
+	ErrorCodeVersionNotFound           = 40402
+	ErrorCodeSchemaNotFound            = 40403
+	ErrorCodeSubjectCompatNotFound     = 40408
+	ErrorCodeSubjectModeNotFound       = 40409
+	ErrorCodeContextNotFound           = 40460
+	ErrorCodeIncompatibleSchema        = 409
+	ErrorCodeInvalidSchema             = 42201
+	ErrorCodeInvalidVersion            = 42202
+	ErrorCodeInvalidCompatibilityLevel = 42203
+	ErrorCodeInvalidMode               = 42204
+	ErrorCodeOperationNotPermitted     = 42205
+	ErrorCodeReferenceExists           = 42206
+	ErrorCodeUnauthorized              = 40101
+	ErrorCodeForbidden                 = 40301
+)
+
+// Error is an error response from the registry.
+type Error struct {
+	StatusCode int    // HTTP status code
+	Code       int    // Registry error code; the status code if the body had none
+	Message    string // Error message from the registry
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("schema registry error %d (HTTP %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// newError reads an error response into an *Error.
+func newError(resp *http.Response) *Error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &Error{StatusCode: resp.StatusCode, Code: resp.StatusCode}
+
+	var body struct {
+		ErrorCode int    `json:"error_code"`
+		Message   string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil && body.ErrorCode != 0 {
+		apiErr.Code = body.ErrorCode
+		apiErr.Message = body.Message
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// ErrorCode returns the registry error code of err, or 0 if err is not an
+// error response from the registry.
+func ErrorCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404 response from the registry, such
+// as an unknown subject, version or schema ID.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsIncompatible reports whether err rejects a schema as incompatible with
+// earlier versions of its subject.
+func IsIncompatible(err error) bool {
+	return ErrorCode(err) == ErrorCodeIncompatibleSchema
+}
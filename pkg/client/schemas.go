@@ -0,0 +1,256 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// RegisterRequest registers a schema with options beyond Register.
+type RegisterRequest struct {
+	Schema
+
+	// ID and Version preserve a schema's ID and version; the subject must
+	// be in IMPORT mode.
+	ID      int64
+	Version int
+
+	// Normalize normalizes the schema before registering it.
+	Normalize bool
+}
+
+// ListSchemasOptions filters ListSchemas.
+type ListSchemasOptions struct {
+	SubjectPrefix string
+	Deleted       bool // Include soft-deleted versions
+	LatestOnly    bool // Only the latest version of each subject
+}
+
+// ListSubjectsOptions filters ListSubjects.
+type ListSubjectsOptions struct {
+	SubjectPrefix string
+	Deleted       bool // Include soft-deleted subjects
+	DeletedOnly   bool // Only soft-deleted subjects
+}
+
+// GetSchemaTypes returns the schema types the registry supports.
+func (c *Client) GetSchemaTypes(ctx context.Context) ([]string, error) {
+	var types []string
+	err := c.do(ctx, http.MethodGet, "/schemas/types", nil, nil, &types)
+	return types, err
+}
+
+// GetSchemaByID returns the schema with the given ID. Schemas are cached,
+// so deserializers may call it for every message.
+func (c *Client) GetSchemaByID(ctx context.Context, id int64) (*Schema, error) {
+	if c.cache != nil {
+		if s, ok := c.cache.schema(id); ok {
+			return s, nil
+		}
+	}
+	var s Schema
+	if err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.FormatInt(id, 10), nil, nil, &s); err != nil {
+		return nil, err
+	}
+	if c.cache != nil {
+		c.cache.putSchema(id, &s)
+	}
+	return &s, nil
+}
+
+// GetSubjectsBySchemaID returns the subjects a schema is registered under.
+func (c *Client) GetSubjectsBySchemaID(ctx context.Context, id int64, deleted bool) ([]string, error) {
+	q := url.Values{}
+	boolQuery(q, "deleted", deleted)
+	var subjects []string
+	err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.FormatInt(id, 10)+"/subjects", q, nil, &subjects)
+	return subjects, err
+}
+
+// GetVersionsBySchemaID returns the subject versions a schema is registered as.
+func (c *Client) GetVersionsBySchemaID(ctx context.Context, id int64, deleted bool) ([]SubjectVersionPair, error) {
+	q := url.Values{}
+	boolQuery(q, "deleted", deleted)
+	var pairs []SubjectVersionPair
+	err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.FormatInt(id, 10)+"/versions", q, nil, &pairs)
+	return pairs, err
+}
+
+// ListSchemas returns the registered schema versions matching opts,
+// fetching every page.
+func (c *Client) ListSchemas(ctx context.Context, opts ListSchemasOptions) ([]SubjectVersion, error) {
+	q := url.Values{}
+	if opts.SubjectPrefix != "" {
+		q.Set("subjectPrefix", opts.SubjectPrefix)
+	}
+	boolQuery(q, "deleted", opts.Deleted)
+	boolQuery(q, "latestOnly", opts.LatestOnly)
+	return list[SubjectVersion](ctx, c, "/schemas", q)
+}
+
+// ListSubjects returns the subjects matching opts, fetching every page.
+func (c *Client) ListSubjects(ctx context.Context, opts ListSubjectsOptions) ([]string, error) {
+	q := url.Values{}
+	if opts.SubjectPrefix != "" {
+		q.Set("subjectPrefix", opts.SubjectPrefix)
+	}
+	boolQuery(q, "deleted", opts.Deleted)
+	boolQuery(q, "deletedOnly", opts.DeletedOnly)
+	return list[string](ctx, c, "/subjects", q)
+}
+
+// ListVersions returns the versions registered under subject.
+func (c *Client) ListVersions(ctx context.Context, subject string, deleted bool) ([]int, error) {
+	q := url.Values{}
+	boolQuery(q, "deleted", deleted)
+	var versions []int
+	err := c.do(ctx, http.MethodGet, subjectPath(subject, "versions"), q, nil, &versions)
+	return versions, err
+}
+
+// GetVersion returns a version of subject; a version of zero or less
+// returns the latest version.
+func (c *Client) GetVersion(ctx context.Context, subject string, version int) (*SubjectVersion, error) {
+	var sv SubjectVersion
+	if err := c.do(ctx, http.MethodGet, subjectPath(subject, "versions", versionString(version)), nil, nil, &sv); err != nil {
+		return nil, err
+	}
+	if c.cache != nil && sv.ID > 0 {
+		c.cache.putSchema(sv.ID, sv.schema())
+	}
+	return &sv, nil
+}
+
+// GetLatestVersion returns the latest version of subject.
+func (c *Client) GetLatestVersion(ctx context.Context, subject string) (*SubjectVersion, error) {
+	return c.GetVersion(ctx, subject, 0)
+}
+
+// GetReferencedBy returns the IDs of the schemas that reference a version
+// of subject.
+func (c *Client) GetReferencedBy(ctx context.Context, subject string, version int) ([]int64, error) {
+	var ids []int64
+	err := c.do(ctx, http.MethodGet, subjectPath(subject, "versions", versionString(version), "referencedby"), nil, nil, &ids)
+	return ids, err
+}
+
+// Register registers schema under subject, or returns the ID it is already
+// registered with. IDs are cached, so serializers may call it for every
+// message.
+func (c *Client) Register(ctx context.Context, subject string, schema Schema, normalize bool) (int64, error) {
+	key := schemaKey(subject, schema, normalize)
+	if c.cache != nil {
+		if id, ok := c.cache.id(key); ok {
+			return id, nil
+		}
+	}
+	res, err := c.RegisterSchema(ctx, subject, RegisterRequest{Schema: schema, Normalize: normalize})
+	if err != nil {
+		return 0, err
+	}
+	if c.cache != nil {
+		c.cache.putID(key, res.ID)
+	}
+	return res.ID, nil
+}
+
+// RegisterSchema registers a schema under subject without consulting the
+// cache, returning its ID and any lint warnings.
+func (c *Client) RegisterSchema(ctx context.Context, subject string, req RegisterRequest) (*RegisterResult, error) {
+	body := struct {
+		Schema
+		ID      int64 `json:"id,omitempty"`
+		Version int   `json:"version,omitempty"`
+	}{req.Schema, req.ID, req.Version}
+	q := url.Values{}
+	boolQuery(q, "normalize", req.Normalize)
+
+	var res RegisterResult
+	if err := c.do(ctx, http.MethodPost, subjectPath(subject, "versions"), q, body, &res); err != nil {
+		return nil, err
+	}
+	// A normalized schema is stored in its normalized form, which only the
+	// registry knows.
+	if c.cache != nil && !req.Normalize {
+		c.cache.putSchema(res.ID, &req.Schema)
+	}
+	return &res, nil
+}
+
+// Lookup returns the version of subject that schema is registered as.
+// Results are cached.
+func (c *Client) Lookup(ctx context.Context, subject string, schema Schema, normalize, deleted bool) (*SubjectVersion, error) {
+	key := schemaKey(subject, schema, normalize)
+	if c.cache != nil && !deleted {
+		if sv, ok := c.cache.version(key); ok {
+			return sv, nil
+		}
+	}
+	q := url.Values{}
+	boolQuery(q, "normalize", normalize)
+	boolQuery(q, "deleted", deleted)
+
+	var sv SubjectVersion
+	if err := c.do(ctx, http.MethodPost, subjectPath(subject), q, schema, &sv); err != nil {
+		return nil, err
+	}
+	if c.cache != nil && !deleted {
+		c.cache.putVersion(key, &sv)
+		c.cache.putSchema(sv.ID, sv.schema())
+	}
+	return &sv, nil
+}
+
+// DeleteSubject deletes every version of subject, returning the deleted
+// versions. A permanent delete requires a prior soft delete.
+func (c *Client) DeleteSubject(ctx context.Context, subject string, permanent bool) ([]int, error) {
+	q := url.Values{}
+	boolQuery(q, "permanent", permanent)
+	var versions []int
+	err := c.do(ctx, http.MethodDelete, subjectPath(subject), q, nil, &versions)
+	return versions, err
+}
+
+// DeleteVersion deletes a version of subject, returning the deleted version.
+func (c *Client) DeleteVersion(ctx context.Context, subject string, version int, permanent bool) (int, error) {
+	q := url.Values{}
+	boolQuery(q, "permanent", permanent)
+	var deleted int
+	err := c.do(ctx, http.MethodDelete, subjectPath(subject, "versions", versionString(version)), q, nil, &deleted)
+	return deleted, err
+}
+
+// TestCompatibility checks schema against a version of subject; a version of
+// zero or less checks against the latest version. Verbose results explain
+// each incompatibility.
+func (c *Client) TestCompatibility(ctx context.Context, subject string, version int, schema Schema, verbose bool) (*CompatibilityResult, error) {
+	return c.testCompatibility(ctx, "/compatibility"+subjectPath(subject, "versions", versionString(version)), schema, verbose)
+}
+
+// TestCompatibilityAll checks schema against every version of subject that
+// the subject's compatibility level applies to.
+func (c *Client) TestCompatibilityAll(ctx context.Context, subject string, schema Schema, verbose bool) (*CompatibilityResult, error) {
+	return c.testCompatibility(ctx, "/compatibility"+subjectPath(subject, "versions"), schema, verbose)
+}
+
+func (c *Client) testCompatibility(ctx context.Context, path string, schema Schema, verbose bool) (*CompatibilityResult, error) {
+	q := url.Values{}
+	boolQuery(q, "verbose", verbose)
+	var res CompatibilityResult
+	if err := c.do(ctx, http.MethodPost, path, q, schema, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// schema returns the schema definition of a subject version.
+func (sv *SubjectVersion) schema() *Schema {
+	return &Schema{
+		Schema:     sv.Schema,
+		SchemaType: sv.SchemaType,
+		References: sv.References,
+		Metadata:   sv.Metadata,
+		RuleSet:    sv.RuleSet,
+	}
+}
@@ -0,0 +1,145 @@
+package client
+
+// Schema types accepted by the registry.
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+	SchemaTypeJSON     = "JSON"
+)
+
+// Compatibility levels.
+const (
+	CompatibilityNone               = "NONE"
+	CompatibilityBackward           = "BACKWARD"
+	CompatibilityBackwardTransitive = "BACKWARD_TRANSITIVE"
+	CompatibilityForward            = "FORWARD"
+	CompatibilityForwardTransitive  = "FORWARD_TRANSITIVE"
+	CompatibilityFull               = "FULL"
+	CompatibilityFullTransitive     = "FULL_TRANSITIVE"
+)
+
+// Modes.
+const (
+	ModeReadWrite        = "READWRITE"
+	ModeReadOnly         = "READONLY"
+	ModeReadOnlyOverride = "READONLY_OVERRIDE"
+	ModeImport           = "IMPORT"
+)
+
+// Reference is a reference from a schema to a schema registered under
+// another subject.
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Metadata is the data contract metadata of a schema.
+type Metadata struct {
+	Tags       map[string][]string `json:"tags,omitempty"`
+	Properties map[string]string   `json:"properties,omitempty"`
+	Sensitive  []string            `json:"sensitive,omitempty"`
+}
+
+// RuleSet is the set of data contract rules of a schema.
+type RuleSet struct {
+	MigrationRules []Rule `json:"migrationRules,omitempty"`
+	DomainRules    []Rule `json:"domainRules,omitempty"`
+	EncodingRules  []Rule `json:"encodingRules,omitempty"`
+}
+
+// Rule is a single data contract rule.
+type Rule struct {
+	Name      string            `json:"name"`
+	Doc       string            `json:"doc,omitempty"`
+	Kind      string            `json:"kind"`
+	Mode      string            `json:"mode"`
+	Type      string            `json:"type,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	Expr      string            `json:"expr,omitempty"`
+	OnSuccess string            `json:"onSuccess,omitempty"`
+	OnFailure string            `json:"onFailure,omitempty"`
+	Disabled  bool              `json:"disabled,omitempty"`
+	EnableAt  *int64            `json:"enableAt,omitempty"`
+}
+
+// Schema is a schema definition, as registered or fetched by ID. An empty
+// SchemaType means Avro.
+type Schema struct {
+	Schema     string      `json:"schema"`
+	SchemaType string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+	Metadata   *Metadata   `json:"metadata,omitempty"`
+	RuleSet    *RuleSet    `json:"ruleSet,omitempty"`
+}
+
+// SubjectVersion is a schema registered under a subject.
+type SubjectVersion struct {
+	Subject    string      `json:"subject"`
+	ID         int64       `json:"id"`
+	Version    int         `json:"version"`
+	SchemaType string      `json:"schemaType"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references,omitempty"`
+	Metadata   *Metadata   `json:"metadata,omitempty"`
+	RuleSet    *RuleSet    `json:"ruleSet,omitempty"`
+}
+
+// SubjectVersionPair identifies a version of a subject.
+type SubjectVersionPair struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// RegisterResult is the result of registering a schema.
+type RegisterResult struct {
+	ID       int64    `json:"id"`
+	Warnings []string `json:"warnings,omitempty"` // Warn-severity lint violations
+}
+
+// Incompatibility is one reason a schema failed a compatibility check.
+type Incompatibility struct {
+	Code      string `json:"code"`
+	Path      string `json:"path,omitempty"`
+	Message   string `json:"message"`
+	Direction string `json:"direction,omitempty"`
+	Version   int    `json:"version,omitempty"`
+	Old       string `json:"old,omitempty"`
+	New       string `json:"new,omitempty"`
+}
+
+// CompatibilityResult is the result of a compatibility check. Messages and
+// Incompatibilities are only set by verbose checks.
+type CompatibilityResult struct {
+	IsCompatible      bool              `json:"is_compatible"`
+	Messages          []string          `json:"messages,omitempty"`
+	Warnings          []string          `json:"warnings,omitempty"`
+	Incompatibilities []Incompatibility `json:"incompatibilities,omitempty"`
+}
+
+// Config is the compatibility configuration of a subject, context or the
+// registry. Compatibility is set when writing it; CompatibilityLevel is
+// returned when reading it.
+type Config struct {
+	Compatibility       string    `json:"compatibility,omitempty"`
+	CompatibilityLevel  string    `json:"compatibilityLevel,omitempty"`
+	Normalize           *bool     `json:"normalize,omitempty"`
+	ValidateFields      *bool     `json:"validateFields,omitempty"`
+	Alias               string    `json:"alias,omitempty"`
+	CompatibilityGroup  string    `json:"compatibilityGroup,omitempty"`
+	DefaultMetadata     *Metadata `json:"defaultMetadata,omitempty"`
+	OverrideMetadata    *Metadata `json:"overrideMetadata,omitempty"`
+	DefaultRuleSet      *RuleSet  `json:"defaultRuleSet,omitempty"`
+	OverrideRuleSet     *RuleSet  `json:"overrideRuleSet,omitempty"`
+	AliasForDeks        string    `json:"aliasForDeks,omitempty"`
+	CompatibilityPolicy string    `json:"compatibilityPolicy,omitempty"`
+}
+
+// Level returns the compatibility level of a config, read or written.
+func (c *Config) Level() string {
+	if c.CompatibilityLevel != "" {
+		return c.CompatibilityLevel
+	}
+	return c.Compatibility
+}
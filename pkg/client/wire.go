@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MagicByte is the first byte of a message in the Confluent wire format,
+// followed by the 4-byte big-endian schema ID and the serialized payload.
+const MagicByte byte = 0
+
+// wireHeaderSize is the size of the magic byte and schema ID.
+const wireHeaderSize = 5
+
+// ErrInvalidWireFormat is returned for data that does not start with a
+// Confluent wire format header.
+var ErrInvalidWireFormat = errors.New("invalid wire format")
+
+// EncodeWireFormat prefixes payload with the wire format header for schema id.
+func EncodeWireFormat(id int64, payload []byte) ([]byte, error) {
+	if id < 0 || id > math.MaxInt32 {
+		return nil, fmt.Errorf("schema ID %d does not fit the wire format", id)
+	}
+	out := make([]byte, wireHeaderSize, wireHeaderSize+len(payload))
+	out[0] = MagicByte
+	binary.BigEndian.PutUint32(out[1:], uint32(id))
+	return append(out, payload...), nil
+}
+
+// DecodeWireFormat splits a message in the wire format into its schema ID
+// and payload. The payload shares data's backing array.
+func DecodeWireFormat(data []byte) (int64, []byte, error) {
+	if len(data) < wireHeaderSize {
+		return 0, nil, fmt.Errorf("%w: message is %d bytes, shorter than the header", ErrInvalidWireFormat, len(data))
+	}
+	if data[0] != MagicByte {
+		return 0, nil, fmt.Errorf("%w: unknown magic byte %d", ErrInvalidWireFormat, data[0])
+	}
+	return int64(binary.BigEndian.Uint32(data[1:wireHeaderSize])), data[wireHeaderSize:], nil
+}
+
+// SchemaForMessage returns the schema a wire format message was written
+// with, and the payload after the header. The schema comes from the cache
+// when possible.
+func (c *Client) SchemaForMessage(ctx context.Context, data []byte) (*Schema, []byte, error) {
+	id, payload, err := DecodeWireFormat(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	schema, err := c.GetSchemaByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, payload, nil
+}
+
+// EncodeMessageIndexes encodes the message indexes that follow the header of
+// a Protobuf message, locating its message type within the schema: the
+// first top-level message is [0], the second nested message of the first
+// top-level message is [0, 1]. [0] is encoded as a single zero byte.
+func EncodeMessageIndexes(indexes []int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		return []byte{0}
+	}
+	out := binary.AppendVarint(nil, int64(len(indexes)))
+	for _, idx := range indexes {
+		out = binary.AppendVarint(out, int64(idx))
+	}
+	return out
+}
+
+// DecodeMessageIndexes decodes the message indexes at the start of a
+// Protobuf payload, returning them and the rest of the payload.
+func DecodeMessageIndexes(payload []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 || count < 0 || count > int64(len(payload)) {
+		return nil, nil, fmt.Errorf("%w: bad message index count", ErrInvalidWireFormat)
+	}
+	payload = payload[n:]
+	if count == 0 {
+		return []int{0}, payload, nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		idx, n := binary.Varint(payload)
+		if n <= 0 || idx < 0 {
+			return nil, nil, fmt.Errorf("%w: bad message index", ErrInvalidWireFormat)
+		}
+		indexes[i] = int(idx)
+		payload = payload[n:]
+	}
+	return indexes, payload, nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWireFormat_RoundTrip(t *testing.T) {
+	msg, err := EncodeWireFormat(258, []byte{0xAA})
+	if err != nil {
+		t.Fatalf("EncodeWireFormat: %v", err)
+	}
+	if !bytes.Equal(msg, []byte{0, 0, 0, 1, 2, 0xAA}) {
+		t.Errorf("encoded = %v", msg)
+	}
+	id, payload, err := DecodeWireFormat(msg)
+	if err != nil || id != 258 || !bytes.Equal(payload, []byte{0xAA}) {
+		t.Errorf("DecodeWireFormat = %d, %v, %v", id, payload, err)
+	}
+}
+
+func TestWireFormat_Invalid(t *testing.T) {
+	if _, err := EncodeWireFormat(1<<32, nil); err == nil {
+		t.Error("expected an error for an ID beyond 32 bits")
+	}
+	for _, data := range [][]byte{nil, {0, 0, 0}, {1, 0, 0, 0, 1}} {
+		if _, _, err := DecodeWireFormat(data); !errors.Is(err, ErrInvalidWireFormat) {
+			t.Errorf("DecodeWireFormat(%v) err = %v", data, err)
+		}
+	}
+}
+
+func TestMessageIndexes(t *testing.T) {
+	tests := []struct {
+		indexes []int
+		encoded []byte
+	}{
+		{[]int{0}, []byte{0}},
+		{[]int{1}, []byte{2, 2}},
+		{[]int{0, 2}, []byte{4, 0, 4}},
+	}
+	for _, tt := range tests {
+		got := EncodeMessageIndexes(tt.indexes)
+		if !bytes.Equal(got, tt.encoded) {
+			t.Errorf("EncodeMessageIndexes(%v) = %v, want %v", tt.indexes, got, tt.encoded)
+		}
+		indexes, rest, err := DecodeMessageIndexes(append(got, 0xFF))
+		if err != nil || !reflect.DeepEqual(indexes, tt.indexes) || !bytes.Equal(rest, []byte{0xFF}) {
+			t.Errorf("DecodeMessageIndexes(%v) = %v, %v, %v", got, indexes, rest, err)
+		}
+	}
+
+	if _, _, err := DecodeMessageIndexes([]byte{4, 0}); !errors.Is(err, ErrInvalidWireFormat) {
+		t.Errorf("truncated indexes err = %v", err)
+	}
+}
@@ -95,8 +95,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Admin can register a schema
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-s-admin" password "pass" role "admin"
-    When I authenticate as "ba-s-admin" with password "pass"
+    And I create a user with username "ba-s-admin" password "pass-1234" role "admin"
+    When I authenticate as "ba-s-admin" with password "pass-1234"
     And I register a "AVRO" schema under subject "ba-admin-subject":
       """
       {"type":"record","name":"BaAdmin","fields":[{"name":"id","type":"int"}]}
@@ -132,8 +132,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Developer can register a schema
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-s-dev" password "pass" role "developer"
-    When I authenticate as "ba-s-dev" with password "pass"
+    And I create a user with username "ba-s-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-s-dev" with password "pass-1234"
     And I register a "AVRO" schema under subject "ba-dev-subject":
       """
       {"type":"record","name":"BaDev","fields":[{"name":"id","type":"int"}]}
@@ -146,8 +146,8 @@ Feature: Internal User Authentication and RBAC
       """
       {"type":"record","name":"BaDevRead","fields":[{"name":"id","type":"int"}]}
       """
-    And I create a user with username "ba-s-devr" password "pass" role "developer"
-    When I authenticate as "ba-s-devr" with password "pass"
+    And I create a user with username "ba-s-devr" password "pass-1234" role "developer"
+    When I authenticate as "ba-s-devr" with password "pass-1234"
     And I GET "/subjects/ba-dev-read/versions/1"
     Then the response status should be 200
 
@@ -157,8 +157,8 @@ Feature: Internal User Authentication and RBAC
       """
       {"type":"record","name":"BaDevNoDel","fields":[{"name":"id","type":"int"}]}
       """
-    And I create a user with username "ba-s-devd" password "pass" role "developer"
-    When I authenticate as "ba-s-devd" with password "pass"
+    And I create a user with username "ba-s-devd" password "pass-1234" role "developer"
+    When I authenticate as "ba-s-devd" with password "pass-1234"
     And I DELETE "/subjects/ba-dev-nodel/versions/1"
     Then the response status should be 403
 
@@ -168,8 +168,8 @@ Feature: Internal User Authentication and RBAC
       """
       {"type":"record","name":"BaDevNoDelSubj","fields":[{"name":"id","type":"int"}]}
       """
-    And I create a user with username "ba-s-devs" password "pass" role "developer"
-    When I authenticate as "ba-s-devs" with password "pass"
+    And I create a user with username "ba-s-devs" password "pass-1234" role "developer"
+    When I authenticate as "ba-s-devs" with password "pass-1234"
     And I DELETE "/subjects/ba-dev-nodelsubj"
     Then the response status should be 403
 
@@ -179,15 +179,15 @@ Feature: Internal User Authentication and RBAC
       """
       {"type":"record","name":"BaRORead","fields":[{"name":"id","type":"int"}]}
       """
-    And I create a user with username "ba-s-ror" password "pass" role "readonly"
-    When I authenticate as "ba-s-ror" with password "pass"
+    And I create a user with username "ba-s-ror" password "pass-1234" role "readonly"
+    When I authenticate as "ba-s-ror" with password "pass-1234"
     And I GET "/subjects/ba-ro-read/versions/1"
     Then the response status should be 200
 
   Scenario: Readonly cannot register a schema
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-s-rowr" password "pass" role "readonly"
-    When I authenticate as "ba-s-rowr" with password "pass"
+    And I create a user with username "ba-s-rowr" password "pass-1234" role "readonly"
+    When I authenticate as "ba-s-rowr" with password "pass-1234"
     And I register a "AVRO" schema under subject "ba-ro-write":
       """
       {"type":"record","name":"BaROWrite","fields":[{"name":"id","type":"int"}]}
@@ -200,8 +200,8 @@ Feature: Internal User Authentication and RBAC
       """
       {"type":"record","name":"BaRONoDel","fields":[{"name":"id","type":"int"}]}
       """
-    And I create a user with username "ba-s-rod" password "pass" role "readonly"
-    When I authenticate as "ba-s-rod" with password "pass"
+    And I create a user with username "ba-s-rod" password "pass-1234" role "readonly"
+    When I authenticate as "ba-s-rod" with password "pass-1234"
     And I DELETE "/subjects/ba-ro-nodel/versions/1"
     Then the response status should be 403
 
@@ -243,15 +243,15 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Developer can read config
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-c-dev" password "pass" role "developer"
-    When I authenticate as "ba-c-dev" with password "pass"
+    And I create a user with username "ba-c-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-c-dev" with password "pass-1234"
     And I GET "/config"
     Then the response status should be 200
 
   Scenario: Developer cannot update config
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-c-devw" password "pass" role "developer"
-    When I authenticate as "ba-c-devw" with password "pass"
+    And I create a user with username "ba-c-devw" password "pass-1234" role "developer"
+    When I authenticate as "ba-c-devw" with password "pass-1234"
     And I PUT "/config" with body:
       """
       {"compatibility":"NONE"}
@@ -260,15 +260,15 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Readonly can read config
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-c-ro" password "pass" role "readonly"
-    When I authenticate as "ba-c-ro" with password "pass"
+    And I create a user with username "ba-c-ro" password "pass-1234" role "readonly"
+    When I authenticate as "ba-c-ro" with password "pass-1234"
     And I GET "/config"
     Then the response status should be 200
 
   Scenario: Readonly cannot update config
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-c-row" password "pass" role "readonly"
-    When I authenticate as "ba-c-row" with password "pass"
+    And I create a user with username "ba-c-row" password "pass-1234" role "readonly"
+    When I authenticate as "ba-c-row" with password "pass-1234"
     And I PUT "/config" with body:
       """
       {"compatibility":"NONE"}
@@ -307,15 +307,15 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Developer can read mode
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-m-dev" password "pass" role "developer"
-    When I authenticate as "ba-m-dev" with password "pass"
+    And I create a user with username "ba-m-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-m-dev" with password "pass-1234"
     And I GET "/mode"
     Then the response status should be 200
 
   Scenario: Developer cannot update mode
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-m-devw" password "pass" role "developer"
-    When I authenticate as "ba-m-devw" with password "pass"
+    And I create a user with username "ba-m-devw" password "pass-1234" role "developer"
+    When I authenticate as "ba-m-devw" with password "pass-1234"
     And I PUT "/mode" with body:
       """
       {"mode":"READONLY"}
@@ -324,15 +324,15 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Readonly can read mode
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-m-ro" password "pass" role "readonly"
-    When I authenticate as "ba-m-ro" with password "pass"
+    And I create a user with username "ba-m-ro" password "pass-1234" role "readonly"
+    When I authenticate as "ba-m-ro" with password "pass-1234"
     And I GET "/mode"
     Then the response status should be 200
 
   Scenario: Readonly cannot update mode
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-m-row" password "pass" role "readonly"
-    When I authenticate as "ba-m-row" with password "pass"
+    And I create a user with username "ba-m-row" password "pass-1234" role "readonly"
+    When I authenticate as "ba-m-row" with password "pass-1234"
     And I PUT "/mode" with body:
       """
       {"mode":"READONLY"}
@@ -390,8 +390,8 @@ Feature: Internal User Authentication and RBAC
       """
       {"type":"record","name":"BaCompatDev","fields":[{"name":"id","type":"int"}]}
       """
-    And I create a user with username "ba-cp-dev" password "pass" role "developer"
-    When I authenticate as "ba-cp-dev" with password "pass"
+    And I create a user with username "ba-cp-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-cp-dev" with password "pass-1234"
     And I check compatibility of schema against subject "ba-compat-dev":
       """
       {"type":"record","name":"BaCompatDev","fields":[{"name":"id","type":"int"},{"name":"name","type":["null","string"],"default":null}]}
@@ -404,8 +404,8 @@ Feature: Internal User Authentication and RBAC
       """
       {"type":"record","name":"BaCompatRO","fields":[{"name":"id","type":"int"}]}
       """
-    And I create a user with username "ba-cp-ro" password "pass" role "readonly"
-    When I authenticate as "ba-cp-ro" with password "pass"
+    And I create a user with username "ba-cp-ro" password "pass-1234" role "readonly"
+    When I authenticate as "ba-cp-ro" with password "pass-1234"
     And I check compatibility of schema against subject "ba-compat-ro":
       """
       {"type":"record","name":"BaCompatRO","fields":[{"name":"id","type":"int"},{"name":"name","type":["null","string"],"default":null}]}
@@ -418,8 +418,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Admin can create and read a KEK
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-k-admin" password "pass" role "admin"
-    When I authenticate as "ba-k-admin" with password "pass"
+    And I create a user with username "ba-k-admin" password "pass-1234" role "admin"
+    When I authenticate as "ba-k-admin" with password "pass-1234"
     And I POST "/dek-registry/v1/keks" with body:
       """
       {"name":"ba-test-kek","kmsType":"test-kms","kmsKeyId":"test-key-id","shared":false}
@@ -430,8 +430,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Developer cannot create a KEK
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-k-dev" password "pass" role "developer"
-    When I authenticate as "ba-k-dev" with password "pass"
+    And I create a user with username "ba-k-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-k-dev" with password "pass-1234"
     And I POST "/dek-registry/v1/keks" with body:
       """
       {"name":"ba-dev-kek","kmsType":"test-kms","kmsKeyId":"test-key-id","shared":false}
@@ -440,8 +440,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Readonly cannot create a KEK
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-k-ro" password "pass" role "readonly"
-    When I authenticate as "ba-k-ro" with password "pass"
+    And I create a user with username "ba-k-ro" password "pass-1234" role "readonly"
+    When I authenticate as "ba-k-ro" with password "pass-1234"
     And I POST "/dek-registry/v1/keks" with body:
       """
       {"name":"ba-ro-kek","kmsType":"test-kms","kmsKeyId":"test-key-id","shared":false}
@@ -454,8 +454,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Admin can create and read an exporter
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-e-admin" password "pass" role "admin"
-    When I authenticate as "ba-e-admin" with password "pass"
+    And I create a user with username "ba-e-admin" password "pass-1234" role "admin"
+    When I authenticate as "ba-e-admin" with password "pass-1234"
     And I POST "/exporters" with body:
       """
       {"name":"ba-exporter","subjects":["*"],"contextType":"CUSTOM","context":"ba-ctx","config":{"schema.registry.url":"http://localhost:8081"}}
@@ -466,8 +466,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Developer cannot create an exporter
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-e-dev" password "pass" role "developer"
-    When I authenticate as "ba-e-dev" with password "pass"
+    And I create a user with username "ba-e-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-e-dev" with password "pass-1234"
     And I POST "/exporters" with body:
       """
       {"name":"ba-dev-exporter","subjects":["*"],"contextType":"CUSTOM","context":"ba-ctx","config":{"schema.registry.url":"http://localhost:8081"}}
@@ -476,8 +476,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Readonly cannot create an exporter
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-e-ro" password "pass" role "readonly"
-    When I authenticate as "ba-e-ro" with password "pass"
+    And I create a user with username "ba-e-ro" password "pass-1234" role "readonly"
+    When I authenticate as "ba-e-ro" with password "pass-1234"
     And I POST "/exporters" with body:
       """
       {"name":"ba-ro-exporter","subjects":["*"],"contextType":"CUSTOM","context":"ba-ctx","config":{"schema.registry.url":"http://localhost:8081"}}
@@ -492,13 +492,13 @@ Feature: Internal User Authentication and RBAC
     Given I authenticate as "admin" with password "admin-password"
     When I GET "/admin/users"
     Then the response status should be 200
-    When I create a user with username "ba-sa-test" password "pass" role "readonly"
+    When I create a user with username "ba-sa-test" password "pass-1234" role "readonly"
     Then the response status should be 201
 
   Scenario: Admin role can read admin endpoints but cannot write
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-a-test" password "pass" role "admin"
-    When I authenticate as "ba-a-test" with password "pass"
+    And I create a user with username "ba-a-test" password "pass-1234" role "admin"
+    When I authenticate as "ba-a-test" with password "pass-1234"
     And I GET "/admin/users"
     Then the response status should be 200
     When I create a user with username "ba-a-should-fail" password "nope" role "readonly"
@@ -506,15 +506,15 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Developer cannot access admin endpoints
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-a-dev" password "pass" role "developer"
-    When I authenticate as "ba-a-dev" with password "pass"
+    And I create a user with username "ba-a-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-a-dev" with password "pass-1234"
     And I GET "/admin/users"
     Then the response status should be 403
 
   Scenario: Readonly cannot access admin endpoints
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-a-ro" password "pass" role "readonly"
-    When I authenticate as "ba-a-ro" with password "pass"
+    And I create a user with username "ba-a-ro" password "pass-1234" role "readonly"
+    When I authenticate as "ba-a-ro" with password "pass-1234"
     And I GET "/admin/users"
     Then the response status should be 403
 
@@ -593,8 +593,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Developer schema register produces audit event with correct actor and role
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-aud-dev" password "pass" role "developer"
-    When I authenticate as "ba-aud-dev" with password "pass"
+    And I create a user with username "ba-aud-dev" password "pass-1234" role "developer"
+    When I authenticate as "ba-aud-dev" with password "pass-1234"
     And I register a "AVRO" schema under subject "ba-audit-dev":
       """
       {"type":"record","name":"BaAuditDev","fields":[{"name":"id","type":"int"}]}
@@ -631,8 +631,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Forbidden action produces auth_forbidden event
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-aud-ro" password "pass" role "readonly"
-    When I authenticate as "ba-aud-ro" with password "pass"
+    And I create a user with username "ba-aud-ro" password "pass-1234" role "readonly"
+    When I authenticate as "ba-aud-ro" with password "pass-1234"
     And I register a "AVRO" schema under subject "ba-audit-forbidden":
       """
       {"type":"record","name":"BaAuditForbidden","fields":[{"name":"id","type":"int"}]}
@@ -799,8 +799,8 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: Self-service /me returns current user info
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-me-test" password "me-pass" role "developer"
-    When I authenticate as "ba-me-test" with password "me-pass"
+    And I create a user with username "ba-me-test" password "me-pass-1234" role "developer"
+    When I authenticate as "ba-me-test" with password "me-pass-1234"
     And I GET "/me"
     Then the response status should be 200
     And the response field "username" should be "ba-me-test"
@@ -808,7 +808,7 @@ Feature: Internal User Authentication and RBAC
 
   Scenario: API key inherits role and enforces RBAC
     Given I authenticate as "admin" with password "admin-password"
-    And I create a user with username "ba-apikey-user" password "pass" role "readonly"
+    And I create a user with username "ba-apikey-user" password "pass-1234" role "readonly"
     Then the response status should be 201
     # Create API key with developer role for the user
     And I create an API key with name "ba-dev-key" role "developer" expires_in 3600
@@ -865,10 +865,10 @@ Feature: Internal User Authentication and RBAC
   Scenario: Super admin can create users but admin role cannot
     Given I authenticate as "admin" with password "admin-password"
     # Super admin creates an admin user
-    And I create a user with username "ba-admin-test" password "pass" role "admin"
+    And I create a user with username "ba-admin-test" password "pass-1234" role "admin"
     Then the response status should be 201
     # The admin user tries to create another user (admin:write required)
-    When I authenticate as "ba-admin-test" with password "pass"
+    When I authenticate as "ba-admin-test" with password "pass-1234"
     And I create a user with username "ba-should-fail" password "fail" role "readonly"
     Then the response status should be 403
     And the audit log should contain an event:
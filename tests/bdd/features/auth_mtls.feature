@@ -159,7 +159,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Valid cert + valid admin credentials succeeds
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I GET "/subjects"
     Then the response status should be 200
 
@@ -235,14 +235,14 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: No client certificate is refused even with valid auth
     Given I connect without a client certificate
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I attempt a GET request to "/subjects"
     Then the connection should be refused
 
   @mtls-auth
   Scenario: Expired client certificate is refused even with valid auth
     Given I connect with mTLS certificate "client-expired"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I attempt a GET request to "/subjects"
     Then the connection should be refused
 
@@ -251,7 +251,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Admin can register a schema over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I POST "/subjects/mtls-rbac-test/versions" with body:
       """
       {"schema": "{\"type\":\"record\",\"name\":\"MtlsRbac\",\"fields\":[{\"name\":\"id\",\"type\":\"int\"}]}"}
@@ -289,7 +289,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Admin can read a schema over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     And I POST "/subjects/mtls-read-test/versions" with body:
       """
       {"schema": "{\"type\":\"record\",\"name\":\"MtlsRead\",\"fields\":[{\"name\":\"id\",\"type\":\"int\"}]}"}
@@ -301,7 +301,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Admin can delete a schema version over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     And I POST "/subjects/mtls-delvs-test/versions" with body:
       """
       {"schema": "{\"type\":\"record\",\"name\":\"MtlsDelVs\",\"fields\":[{\"name\":\"id\",\"type\":\"int\"}]}"}
@@ -341,7 +341,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Admin can delete a subject over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     And I POST "/subjects/mtls-delsub-test/versions" with body:
       """
       {"schema": "{\"type\":\"record\",\"name\":\"MtlsDelSub\",\"fields\":[{\"name\":\"id\",\"type\":\"int\"}]}"}
@@ -381,7 +381,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Readonly user can read schemas but not write over mTLS
     Given I connect with mTLS certificate "client-readonly"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     And I POST "/subjects/mtls-ro-test/versions" with body:
       """
       {"schema": "{\"type\":\"record\",\"name\":\"MtlsRo\",\"fields\":[{\"name\":\"id\",\"type\":\"int\"}]}"}
@@ -462,7 +462,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Readonly user cannot delete schemas over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     And I POST "/subjects/mtls-ro-del/versions" with body:
       """
       {"schema": "{\"type\":\"record\",\"name\":\"MtlsRoDel\",\"fields\":[{\"name\":\"id\",\"type\":\"int\"}]}"}
@@ -544,7 +544,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Admin can update global config over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I PUT "/config" with body:
       """
       {"compatibility": "FULL"}
@@ -582,14 +582,14 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Admin can read global config over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I GET "/config"
     Then the response status should be 200
 
   @mtls-auth
   Scenario: Readonly user cannot update config over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I POST "/admin/users" with body:
       """
       {"username": "configro", "password": "configro-pass", "role": "readonly"}
@@ -635,7 +635,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Admin can update mode over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I PUT "/mode" with body:
       """
       {"mode": "READONLY"}
@@ -679,7 +679,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Readonly user cannot update mode over mTLS
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I POST "/admin/users" with body:
       """
       {"username": "modero", "password": "modero-pass", "role": "readonly"}
@@ -725,7 +725,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: Schema register audit event has full transport_security context
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I POST "/subjects/mtls-audit-test/versions" with body:
       """
       {"schema": "{\"type\":\"record\",\"name\":\"MtlsAudit\",\"fields\":[{\"name\":\"id\",\"type\":\"int\"}]}"}
@@ -798,7 +798,7 @@ Feature: mTLS Transport Security
   @mtls-auth
   Scenario: RBAC forbidden audit event has full transport_security context
     Given I connect with mTLS certificate "client-admin"
-    And I authenticate as "admin" with password "admin"
+    And I authenticate as "admin" with password "admin-password"
     When I POST "/admin/users" with body:
       """
       {"username": "auditro", "password": "auditro-pass", "role": "readonly"}
@@ -2644,6 +2644,90 @@ Feature: Protobuf Schema Compatibility
       | duration_ms          | *                                |
       | request_id           | *                                |
 
+  # ==========================================================================
+  # Field Reuse (3 scenarios)
+  # Reserved and removed field numbers are checked against every version
+  # ==========================================================================
+
+  Scenario: Field reuse - reserved field number is incompatible
+    Given the global compatibility level is "BACKWARD"
+    And subject "proto-reuse-1" has "PROTOBUF" schema:
+      """
+      syntax = "proto3";
+      message User {
+        reserved 2;
+        reserved "email";
+        string id = 1;
+      }
+      """
+    When I register a "PROTOBUF" schema under subject "proto-reuse-1":
+      """
+      syntax = "proto3";
+      message User {
+        string id = 1;
+        int64 created_at = 2;
+      }
+      """
+    Then the response status should be 409
+    And the response should contain "which version 1 reserved"
+
+  Scenario: Field reuse - removed field number is incompatible under non-transitive BACKWARD
+    Given the global compatibility level is "NONE"
+    And subject "proto-reuse-2" has "PROTOBUF" schema:
+      """
+      syntax = "proto3";
+      message User {
+        string id = 1;
+        string email = 2;
+      }
+      """
+    And subject "proto-reuse-2" has "PROTOBUF" schema:
+      """
+      syntax = "proto3";
+      message User {
+        string id = 1;
+      }
+      """
+    And the global compatibility level is "BACKWARD"
+    When I register a "PROTOBUF" schema under subject "proto-reuse-2":
+      """
+      syntax = "proto3";
+      message User {
+        string id = 1;
+        int64 created_at = 2;
+      }
+      """
+    Then the response status should be 409
+    And the response should contain "reuses number 2"
+
+  Scenario: Field reuse - restoring a removed field unchanged is compatible
+    Given the global compatibility level is "NONE"
+    And subject "proto-reuse-3" has "PROTOBUF" schema:
+      """
+      syntax = "proto3";
+      message User {
+        string id = 1;
+        string email = 2;
+      }
+      """
+    And subject "proto-reuse-3" has "PROTOBUF" schema:
+      """
+      syntax = "proto3";
+      message User {
+        string id = 1;
+      }
+      """
+    And the global compatibility level is "BACKWARD"
+    When I register a "PROTOBUF" schema under subject "proto-reuse-3":
+      """
+      syntax = "proto3";
+      message User {
+        string id = 1;
+        string email = 2;
+      }
+      """
+    Then the response status should be 200
+
   # ==========================================================================
   # Error Validation (5 scenarios)
   # Verify error responses, check endpoint, and per-subject overrides
@@ -1152,18 +1152,21 @@ Feature: Transitive Compatibility - Multi-Version Chains
       | request_id           | *                                     |
 
   Scenario: Protobuf BACKWARD vs BACKWARD_TRANSITIVE differentiator
-    # v1={id, code:string}, v2={id} (removes code). Under BACKWARD, the checker
-    # flags "field removed" for code. So we register v1, v2 under NONE.
-    # v3={id, code:int32}: BACKWARD vs v2 (latest). v2 has {id}. v3 has {id, code}.
-    # code not in v2 = "new field". id matches. PASS.
-    # BACKWARD_TRANSITIVE vs v1: code type changed string->int32. FAIL.
+    # v1={Event, Extra}, v2={Event} (removes Extra). Under BACKWARD, the checker
+    # flags the removed message. So we register v1, v2 under NONE.
+    # v3={Event with source}: BACKWARD vs v2 (latest). Adds a field. PASS.
+    # BACKWARD_TRANSITIVE vs v1: message Extra removed. FAIL.
+    # (Reusing a removed field number would fail in both modes, since field
+    # reuse is checked against every version.)
     Given the global compatibility level is "NONE"
     And subject "proto-bt-vs-b" has "PROTOBUF" schema:
       """
       syntax = "proto3";
       message Event {
         int32 id = 1;
-        string code = 2;
+      }
+      message Extra {
+        string note = 1;
       }
       """
     And subject "proto-bt-vs-b" has "PROTOBUF" schema:
@@ -1179,7 +1182,7 @@ Feature: Transitive Compatibility - Multi-Version Chains
       syntax = "proto3";
       message Event {
         int32 id = 1;
-        int32 code = 2;
+        string source = 2;
       }
       """
     Then the response status should be 200
@@ -1213,7 +1216,7 @@ Feature: Transitive Compatibility - Multi-Version Chains
       | request_id           | *                                     |
 
   Scenario: Protobuf BACKWARD_TRANSITIVE catches field number type change
-    # Same v1, v2 as above but registered under NONE. Switch to BACKWARD_TRANSITIVE.
+    # v1={id, code:string}, v2={id} registered under NONE. Switch to BACKWARD_TRANSITIVE.
     # v3={id, code:int32}: checked against ALL versions.
     # vs v2={id}: code not in v2, "new field". PASS.
     # vs v1={id, code:string}: code type changed string->int32. FAIL.
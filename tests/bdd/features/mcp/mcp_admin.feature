@@ -503,7 +503,7 @@ Feature: MCP Admin Tools
   Scenario: Change user password
     When I call MCP tool "create_user" with JSON input:
       """
-      {"username": "pwuser", "password": "oldpass", "role": "developer"}
+      {"username": "pwuser", "password": "oldpass123", "role": "developer"}
       """
     Then the MCP result should contain "pwuser"
     And I store the MCP result field "id" as "user_id"
@@ -537,7 +537,7 @@ Feature: MCP Admin Tools
       | metadata           |                |
     When I call MCP tool "change_password" with JSON input:
       """
-      {"id": $user_id, "old_password": "oldpass", "new_password": "newpass"}
+      {"id": $user_id, "old_password": "oldpass123", "new_password": "newpass123"}
       """
     Then the MCP result should contain "true"
     And the audit log should contain an event:
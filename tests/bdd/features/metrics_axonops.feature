@@ -321,6 +321,21 @@ Feature: AxonOps-Native Metrics
     Then the response status should be 422
     And the Prometheus metric "schema_registry_compatibility_errors_total" should exist
 
+  Scenario: compatibility_rejections_total counter tracks rejections by reason
+    When I register a schema under subject "metrics-compat-reject-test":
+      """
+      {"type":"record","name":"MetricsCompatReject","fields":[{"name":"id","type":"int"}]}
+      """
+    Then the response status should be 200
+    And subject "metrics-compat-reject-test" has compatibility level "BACKWARD"
+    When I register a schema under subject "metrics-compat-reject-test":
+      """
+      {"type":"record","name":"MetricsCompatReject","fields":[{"name":"id","type":"string"}]}
+      """
+    Then the response status should be 409
+    And the Prometheus metric "schema_registry_compatibility_rejections_total" with labels "reason=\"type_changed\",type=\"AVRO\"" should exist
+    And the Prometheus metric "schema_registry_registrations_total" with labels "status=\"failure\"" should exist
+
   Scenario: storage_errors_total counter tracks storage errors
     When I GET "/subjects/nonexistent-subject-for-metrics/versions"
     Then the response status should be 404
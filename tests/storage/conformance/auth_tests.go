@@ -8,7 +8,8 @@ import (
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
 
-// RunAuthTests tests all user and API key CRUD operations.
+// RunAuthTests tests all user, API key, role grant, share token, and login
+// session CRUD operations.
 func RunAuthTests(t *testing.T, newStore StoreFactory) {
 	t.Helper()
 
@@ -94,6 +95,46 @@ func RunAuthTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("UpdateUser_PasswordState", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		user := &storage.UserRecord{Username: "dora", PasswordHash: "hash-2", Role: "reader", Enabled: true, MustChangePassword: true}
+		if err := store.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		got, _ := store.GetUserByID(ctx, user.ID)
+		if !got.MustChangePassword {
+			t.Error("expected the user to have to change the password")
+		}
+
+		changedAt := time.Now().UTC().Truncate(time.Second)
+		updated := &storage.UserRecord{
+			ID:                user.ID,
+			Username:          user.Username,
+			PasswordHash:      "hash-3",
+			Role:              user.Role,
+			Enabled:           user.Enabled,
+			CreatedAt:         user.CreatedAt,
+			PasswordChangedAt: changedAt,
+			PasswordHistory:   []string{"hash-2", "hash-1"},
+		}
+		if err := store.UpdateUser(ctx, updated); err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+		got, _ = store.GetUserByUsername(ctx, "dora")
+		if got.MustChangePassword {
+			t.Error("expected the requirement to be lifted")
+		}
+		if !got.PasswordChangedAt.Equal(changedAt) {
+			t.Errorf("expected the password to be changed at %v, got %v", changedAt, got.PasswordChangedAt)
+		}
+		if len(got.PasswordHistory) != 2 || got.PasswordHistory[0] != "hash-2" {
+			t.Errorf("expected the password history to be kept, got %v", got.PasswordHistory)
+		}
+	})
+
 	t.Run("UpdateUser_ChangeUsername", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -285,6 +326,47 @@ func RunAuthTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("APIKeyScopes", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		user := &storage.UserRecord{Username: "u-scopes", PasswordHash: "h", Role: "admin", Enabled: true}
+		if err := store.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		scopes := []storage.APIKeyScope{
+			{Context: ".ci", SubjectPrefix: "orders-", Operations: []string{"schema:read", "schema:write"}},
+			{Context: ".ci"},
+		}
+		key := &storage.APIKeyRecord{UserID: user.ID, KeyHash: "hash-scopes", KeyPrefix: "ak_", Name: "ci", Role: "developer", Enabled: true, ExpiresAt: time.Now().Add(time.Hour), Scopes: scopes}
+		if err := store.CreateAPIKey(ctx, key); err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+
+		got, err := store.GetAPIKeyByHash(ctx, "hash-scopes")
+		if err != nil {
+			t.Fatalf("GetAPIKeyByHash: %v", err)
+		}
+		if !storage.EqualAPIKeyScopes(got.Scopes, scopes) {
+			t.Errorf("expected scopes %+v, got %+v", scopes, got.Scopes)
+		}
+
+		updated := *got
+		updated.Scopes = scopes[:1]
+		if err := store.UpdateAPIKey(ctx, &updated); err != nil {
+			t.Fatalf("UpdateAPIKey: %v", err)
+		}
+		got, err = store.GetAPIKeyByID(ctx, key.ID)
+		if err != nil {
+			t.Fatalf("GetAPIKeyByID: %v", err)
+		}
+		if !storage.EqualAPIKeyScopes(got.Scopes, scopes[:1]) {
+			t.Errorf("expected scopes %+v after update, got %+v", scopes[:1], got.Scopes)
+		}
+	})
+
 	t.Run("GetAPIKeyByUserAndName", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -492,6 +574,71 @@ func RunAuthTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("AddAndListAPIKeyUsage", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		user := &storage.UserRecord{Username: "u-usage", PasswordHash: "h", Role: "admin", Enabled: true}
+		if err := store.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		key := &storage.APIKeyRecord{UserID: user.ID, KeyHash: "hash-usage", KeyPrefix: "ak_", Name: "k-usage", Role: "reader", Enabled: true, ExpiresAt: time.Now().Add(time.Hour)}
+		if err := store.CreateAPIKey(ctx, key); err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		weekAgo := today.AddDate(0, 0, -7)
+		lastUsed := today.Add(90 * time.Minute)
+		batch := []*storage.APIKeyUsageRecord{
+			{APIKeyID: key.ID, Day: today, Requests: 3, LastUsed: lastUsed},
+			{APIKeyID: key.ID, Day: weekAgo, Requests: 10, LastUsed: weekAgo.Add(time.Hour)},
+			{APIKeyID: key.ID + 1000, Day: today, Requests: 5, LastUsed: lastUsed}, // no such key
+		}
+		if err := store.AddAPIKeyUsage(ctx, batch); err != nil {
+			t.Fatalf("AddAPIKeyUsage: %v", err)
+		}
+		if err := store.AddAPIKeyUsage(ctx, []*storage.APIKeyUsageRecord{
+			{APIKeyID: key.ID, Day: today, Requests: 2, LastUsed: today.Add(time.Minute)},
+		}); err != nil {
+			t.Fatalf("AddAPIKeyUsage: %v", err)
+		}
+
+		all, err := store.ListAPIKeyUsage(ctx, time.Time{})
+		if err != nil {
+			t.Fatalf("ListAPIKeyUsage: %v", err)
+		}
+		counts := make(map[time.Time]int64)
+		for _, rec := range all {
+			if rec.APIKeyID != key.ID {
+				t.Errorf("usage recorded for unknown key %d", rec.APIKeyID)
+				continue
+			}
+			counts[rec.Day.UTC()] += rec.Requests
+		}
+		if counts[today] != 5 || counts[weekAgo] != 10 {
+			t.Errorf("expected 5 requests today and 10 a week ago, got %v", counts)
+		}
+
+		recent, err := store.ListAPIKeyUsage(ctx, today)
+		if err != nil {
+			t.Fatalf("ListAPIKeyUsage since today: %v", err)
+		}
+		if len(recent) != 1 || recent[0].Requests != 5 {
+			t.Errorf("expected only today's 5 requests, got %d records", len(recent))
+		}
+
+		// last_used only moves forward.
+		got, err := store.GetAPIKeyByID(ctx, key.ID)
+		if err != nil {
+			t.Fatalf("GetAPIKeyByID: %v", err)
+		}
+		if got.LastUsed == nil || !got.LastUsed.Equal(lastUsed) {
+			t.Errorf("expected LastUsed %v, got %v", lastUsed, got.LastUsed)
+		}
+	})
+
 	t.Run("CreateAPIKey_DuplicateHash", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -515,4 +662,302 @@ func RunAuthTests(t *testing.T, newStore StoreFactory) {
 			t.Errorf("expected ErrAPIKeyExists for duplicate hash, got %v", err)
 		}
 	})
+
+	// --- Role Grant Tests ---
+
+	t.Run("CreateGrant_AssignsID", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		grant := &storage.GrantRecord{Username: "dev-user", Role: "developer", Context: ".dev", CreatedBy: "admin"}
+		if err := store.CreateGrant(ctx, grant); err != nil {
+			t.Fatalf("CreateGrant: %v", err)
+		}
+		if grant.ID == 0 {
+			t.Error("expected non-zero ID")
+		}
+		if grant.CreatedAt.IsZero() {
+			t.Error("expected CreatedAt to be set")
+		}
+	})
+
+	t.Run("GetGrantByID", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		grant := &storage.GrantRecord{APIKeyID: 7, Role: "readonly", SubjectPrefix: "orders-"}
+		if err := store.CreateGrant(ctx, grant); err != nil {
+			t.Fatalf("CreateGrant: %v", err)
+		}
+
+		got, err := store.GetGrantByID(ctx, grant.ID)
+		if err != nil {
+			t.Fatalf("GetGrantByID: %v", err)
+		}
+		if got.APIKeyID != 7 || got.Username != "" {
+			t.Errorf("expected API key 7 and no username, got %d %q", got.APIKeyID, got.Username)
+		}
+		if got.Role != "readonly" || got.Context != "" || got.SubjectPrefix != "orders-" {
+			t.Errorf("unexpected grant scope: %+v", got)
+		}
+
+		if _, err := store.GetGrantByID(ctx, 99999); err != storage.ErrGrantNotFound {
+			t.Errorf("expected ErrGrantNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteGrant", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		grant := &storage.GrantRecord{Username: "gina", Role: "admin", Context: ".prod"}
+		store.CreateGrant(ctx, grant)
+
+		if err := store.DeleteGrant(ctx, grant.ID); err != nil {
+			t.Fatalf("DeleteGrant: %v", err)
+		}
+		if _, err := store.GetGrantByID(ctx, grant.ID); err != storage.ErrGrantNotFound {
+			t.Errorf("expected ErrGrantNotFound after delete, got %v", err)
+		}
+		if err := store.DeleteGrant(ctx, grant.ID); err != storage.ErrGrantNotFound {
+			t.Errorf("expected ErrGrantNotFound for second delete, got %v", err)
+		}
+	})
+
+	t.Run("ListGrants", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, name := range []string{"g1", "g2", "g3"} {
+			if err := store.CreateGrant(ctx, &storage.GrantRecord{Username: name, Role: "developer"}); err != nil {
+				t.Fatalf("CreateGrant %s: %v", name, err)
+			}
+		}
+
+		grants, err := store.ListGrants(ctx)
+		if err != nil {
+			t.Fatalf("ListGrants: %v", err)
+		}
+		if len(grants) != 3 {
+			t.Fatalf("expected 3 grants, got %d", len(grants))
+		}
+		for i := 1; i < len(grants); i++ {
+			if grants[i-1].ID >= grants[i].ID {
+				t.Errorf("expected grants ordered by ID, got %d before %d", grants[i-1].ID, grants[i].ID)
+			}
+		}
+	})
+
+	// --- Share Token Tests ---
+
+	t.Run("ShareToken_CreateAndGet", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		now := time.Now().UTC().Truncate(time.Second)
+		token := &storage.ShareTokenRecord{
+			TokenHash:   "share-hash-1",
+			TokenPrefix: "abcd1234",
+			Name:        "partner-acme",
+			Context:     ".partners",
+			Subject:     "orders-value",
+			CreatedBy:   "admin",
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(24 * time.Hour),
+		}
+		if err := store.CreateShareToken(ctx, token); err != nil {
+			t.Fatalf("CreateShareToken: %v", err)
+		}
+		if token.ID == 0 {
+			t.Fatal("expected non-zero ID")
+		}
+
+		for name, get := range map[string]func() (*storage.ShareTokenRecord, error){
+			"ByID":   func() (*storage.ShareTokenRecord, error) { return store.GetShareTokenByID(ctx, token.ID) },
+			"ByHash": func() (*storage.ShareTokenRecord, error) { return store.GetShareTokenByHash(ctx, "share-hash-1") },
+		} {
+			got, err := get()
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if got.ID != token.ID || got.Name != "partner-acme" || got.Context != ".partners" || got.Subject != "orders-value" {
+				t.Errorf("%s: unexpected token %+v", name, got)
+			}
+			if !got.ExpiresAt.Equal(token.ExpiresAt) {
+				t.Errorf("%s: expires_at = %v, want %v", name, got.ExpiresAt, token.ExpiresAt)
+			}
+		}
+
+		if _, err := store.GetShareTokenByID(ctx, 99999); err != storage.ErrShareTokenNotFound {
+			t.Errorf("expected ErrShareTokenNotFound, got %v", err)
+		}
+		if _, err := store.GetShareTokenByHash(ctx, "unknown"); err != storage.ErrShareTokenNotFound {
+			t.Errorf("expected ErrShareTokenNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ShareToken_DeleteAndList", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		expires := time.Now().Add(time.Hour)
+		var ids []int64
+		for _, name := range []string{"t1", "t2", "t3"} {
+			token := &storage.ShareTokenRecord{TokenHash: "hash-" + name, TokenPrefix: name, Name: name, Context: ".", CreatedAt: time.Now(), ExpiresAt: expires}
+			if err := store.CreateShareToken(ctx, token); err != nil {
+				t.Fatalf("CreateShareToken %s: %v", name, err)
+			}
+			ids = append(ids, token.ID)
+		}
+
+		if err := store.DeleteShareToken(ctx, ids[1]); err != nil {
+			t.Fatalf("DeleteShareToken: %v", err)
+		}
+		if _, err := store.GetShareTokenByHash(ctx, "hash-t2"); err != storage.ErrShareTokenNotFound {
+			t.Errorf("expected ErrShareTokenNotFound after delete, got %v", err)
+		}
+		if err := store.DeleteShareToken(ctx, ids[1]); err != storage.ErrShareTokenNotFound {
+			t.Errorf("expected ErrShareTokenNotFound for second delete, got %v", err)
+		}
+
+		tokens, err := store.ListShareTokens(ctx)
+		if err != nil {
+			t.Fatalf("ListShareTokens: %v", err)
+		}
+		if len(tokens) != 2 || tokens[0].ID != ids[0] || tokens[1].ID != ids[2] {
+			t.Errorf("expected tokens %d and %d, got %+v", ids[0], ids[2], tokens)
+		}
+	})
+
+	// --- Session Tests ---
+
+	t.Run("Session_CreateGetRefresh", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		now := time.Now().UTC().Truncate(time.Second)
+		session := &storage.SessionRecord{
+			ID:          "session-1",
+			RefreshHash: "refresh-hash-1",
+			UserID:      7,
+			Username:    "alice",
+			Role:        "developer",
+			Tenant:      "acme",
+			AuthMethod:  "basic",
+			CreatedAt:   now,
+			RefreshedAt: now,
+			ExpiresAt:   now.Add(24 * time.Hour),
+		}
+		if err := store.CreateSession(ctx, session); err != nil {
+			t.Fatalf("CreateSession: %v", err)
+		}
+
+		got, err := store.GetSession(ctx, "session-1")
+		if err != nil {
+			t.Fatalf("GetSession: %v", err)
+		}
+		if got.RefreshHash != "refresh-hash-1" || got.UserID != 7 || got.Username != "alice" ||
+			got.Role != "developer" || got.Tenant != "acme" || got.AuthMethod != "basic" {
+			t.Errorf("unexpected session %+v", got)
+		}
+		if !got.ExpiresAt.Equal(session.ExpiresAt) {
+			t.Errorf("expires_at = %v, want %v", got.ExpiresAt, session.ExpiresAt)
+		}
+		if _, err := store.GetSession(ctx, "unknown"); err != storage.ErrSessionNotFound {
+			t.Errorf("expected ErrSessionNotFound, got %v", err)
+		}
+
+		refreshed := *got
+		refreshed.RefreshHash = "refresh-hash-2"
+		refreshed.Role = "readonly"
+		refreshed.RefreshedAt = now.Add(time.Hour)
+		refreshed.ExpiresAt = now.Add(25 * time.Hour)
+		if err := store.RefreshSession(ctx, &refreshed, "refresh-hash-1"); err != nil {
+			t.Fatalf("RefreshSession: %v", err)
+		}
+		got, err = store.GetSession(ctx, "session-1")
+		if err != nil {
+			t.Fatalf("GetSession after refresh: %v", err)
+		}
+		if got.RefreshHash != "refresh-hash-2" || got.Role != "readonly" || got.Username != "alice" {
+			t.Errorf("unexpected refreshed session %+v", got)
+		}
+		if !got.ExpiresAt.Equal(refreshed.ExpiresAt) {
+			t.Errorf("expires_at = %v, want %v", got.ExpiresAt, refreshed.ExpiresAt)
+		}
+
+		// The replaced refresh token cannot be used again.
+		refreshed.RefreshHash = "refresh-hash-3"
+		if err := store.RefreshSession(ctx, &refreshed, "refresh-hash-1"); err != storage.ErrSessionNotFound {
+			t.Errorf("expected ErrSessionNotFound for a stale refresh hash, got %v", err)
+		}
+		missing := &storage.SessionRecord{ID: "unknown", RefreshHash: "x", ExpiresAt: now.Add(time.Hour)}
+		if err := store.RefreshSession(ctx, missing, "refresh-hash-1"); err != storage.ErrSessionNotFound {
+			t.Errorf("expected ErrSessionNotFound for a missing session, got %v", err)
+		}
+	})
+
+	t.Run("Session_Delete", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		now := time.Now().UTC().Truncate(time.Second)
+		for _, s := range []struct {
+			id      string
+			userID  int64
+			expires time.Duration
+		}{
+			{"s-alice-1", 1, time.Hour},
+			{"s-alice-2", 1, 3 * time.Hour},
+			{"s-bob", 2, time.Hour},
+			{"s-config-user", 0, 3 * time.Hour},
+		} {
+			session := &storage.SessionRecord{
+				ID: s.id, RefreshHash: "hash-" + s.id, UserID: s.userID, Username: s.id, Role: "readonly",
+				AuthMethod: "basic", CreatedAt: now, RefreshedAt: now, ExpiresAt: now.Add(s.expires),
+			}
+			if err := store.CreateSession(ctx, session); err != nil {
+				t.Fatalf("CreateSession %s: %v", s.id, err)
+			}
+		}
+
+		if err := store.DeleteSession(ctx, "s-bob"); err != nil {
+			t.Fatalf("DeleteSession: %v", err)
+		}
+		if _, err := store.GetSession(ctx, "s-bob"); err != storage.ErrSessionNotFound {
+			t.Errorf("expected ErrSessionNotFound after delete, got %v", err)
+		}
+		if err := store.DeleteSession(ctx, "s-bob"); err != storage.ErrSessionNotFound {
+			t.Errorf("expected ErrSessionNotFound for second delete, got %v", err)
+		}
+
+		// Sessions expiring within two hours are expired by then.
+		if err := store.DeleteExpiredSessions(ctx, now.Add(2*time.Hour)); err != nil {
+			t.Fatalf("DeleteExpiredSessions: %v", err)
+		}
+		if _, err := store.GetSession(ctx, "s-alice-1"); err != storage.ErrSessionNotFound {
+			t.Errorf("expected the expired session to be deleted, got %v", err)
+		}
+		if _, err := store.GetSession(ctx, "s-alice-2"); err != nil {
+			t.Errorf("expected the unexpired session to remain: %v", err)
+		}
+
+		if err := store.DeleteUserSessions(ctx, 1); err != nil {
+			t.Fatalf("DeleteUserSessions: %v", err)
+		}
+		if _, err := store.GetSession(ctx, "s-alice-2"); err != storage.ErrSessionNotFound {
+			t.Errorf("expected the user's sessions to be deleted, got %v", err)
+		}
+		if _, err := store.GetSession(ctx, "s-config-user"); err != nil {
+			t.Errorf("expected other sessions to remain: %v", err)
+		}
+	})
 }
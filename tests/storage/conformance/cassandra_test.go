@@ -35,6 +35,21 @@ func TestCassandraBackend(t *testing.T) {
 		store.ResetIDCache()
 		return &noCloseStore{store}
 	})
+
+	cfg.GlobalIDs = true
+	globalStore, err := cassandra.NewStore(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create Cassandra store with global IDs: %v", err)
+	}
+	defer globalStore.Close()
+
+	t.Run("GlobalIDs", func(t *testing.T) {
+		RunGlobalIDTests(t, func() storage.Storage {
+			truncateCassandra(t, cfg)
+			globalStore.ResetIDCache()
+			return &noCloseStore{globalStore}
+		})
+	})
 }
 
 func truncateCassandra(t *testing.T, cfg cassandra.Config) {
@@ -53,7 +68,7 @@ func truncateCassandra(t *testing.T, cfg cassandra.Config) {
 	defer session.Close()
 
 	tables := []string{
-		"exporter_statuses", "exporters", "deks", "deks_by_kek", "keks",
+		"subject_meta", "reader_assertions", "pending_schemas", "sessions_by_id", "sessions_by_user", "frozen_versions", "schema_id_aliases", "maintenance_windows", "import_sessions", "schema_tags", "share_tokens_by_id", "share_tokens_by_hash", "schema_usage", "jobs", "role_grants", "exporter_statuses", "exporters", "deks", "deks_by_kek", "keks",
 		"api_keys_by_hash", "api_keys_by_user", "api_keys_by_id",
 		"users_by_email", "users_by_id",
 		"id_alloc", "modes", "global_config", "subject_configs",
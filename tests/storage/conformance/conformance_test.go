@@ -12,3 +12,9 @@ func TestMemoryBackend(t *testing.T) {
 		return memory.NewStore()
 	})
 }
+
+func TestMemoryBackendGlobalIDs(t *testing.T) {
+	RunGlobalIDTests(t, func() storage.Storage {
+		return memory.NewStore(memory.WithGlobalIDs())
+	})
+}
@@ -738,6 +738,48 @@ func RunContextTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("PeekNextID_DoesNotAllocate", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		next, err := store.PeekNextID(ctx, ".ctx-a")
+		if err != nil {
+			t.Fatalf("PeekNextID: %v", err)
+		}
+		if next != 1 {
+			t.Errorf("expected 1 for fresh context, got %d", next)
+		}
+
+		if err := store.SetNextID(ctx, ".ctx-a", 100); err != nil {
+			t.Fatalf("SetNextID: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			next, err := store.PeekNextID(ctx, ".ctx-a")
+			if err != nil {
+				t.Fatalf("PeekNextID: %v", err)
+			}
+			if next != 100 {
+				t.Errorf("peek %d: expected 100, got %d", i, next)
+			}
+		}
+
+		id, err := store.NextID(ctx, ".ctx-a")
+		if err != nil {
+			t.Fatalf("NextID: %v", err)
+		}
+		if id != 100 {
+			t.Errorf("expected NextID 100, got %d", id)
+		}
+		next, err = store.PeekNextID(ctx, ".ctx-a")
+		if err != nil {
+			t.Fatalf("PeekNextID: %v", err)
+		}
+		if next <= id {
+			t.Errorf("expected the peeked ID to be past %d, got %d", id, next)
+		}
+	})
+
 	t.Run("GetSchemaByFingerprint_CrossContext", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -768,4 +810,148 @@ func RunContextTests(t *testing.T, newStore StoreFactory) {
 			t.Error("GetSchemaByFingerprint .ctx-b: expected error, got nil")
 		}
 	})
+
+	// --- Context CRUD ---
+
+	t.Run("CreateContext_GetContext_RoundTrip", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		rec := &storage.ContextRecord{
+			Name:          ".team-a",
+			Description:   "Team A schemas",
+			Owner:         "team-a@example.com",
+			MaxSubjects:   10,
+			MaxSchemaSize: 4096,
+		}
+		if err := store.CreateContext(ctx, rec); err != nil {
+			t.Fatalf("CreateContext: %v", err)
+		}
+
+		got, err := store.GetContext(ctx, ".team-a")
+		if err != nil {
+			t.Fatalf("GetContext: %v", err)
+		}
+		if got.Name != ".team-a" || got.Description != "Team A schemas" || got.Owner != "team-a@example.com" {
+			t.Errorf("unexpected context metadata: %+v", got)
+		}
+		if got.MaxSubjects != 10 || got.MaxSchemaSize != 4096 {
+			t.Errorf("unexpected context limits: %+v", got)
+		}
+
+		contexts, err := store.ListContexts(ctx)
+		if err != nil {
+			t.Fatalf("ListContexts: %v", err)
+		}
+		found := false
+		for _, c := range contexts {
+			if c == ".team-a" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("created context missing from ListContexts: %v", contexts)
+		}
+	})
+
+	t.Run("CreateContext_Duplicate", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".dup"}); err != nil {
+			t.Fatalf("CreateContext: %v", err)
+		}
+		err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".dup"})
+		if !errors.Is(err, storage.ErrContextExists) {
+			t.Errorf("expected ErrContextExists, got %v", err)
+		}
+	})
+
+	t.Run("CreateContext_ImplicitContextExists", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		rec := &storage.SchemaRecord{
+			Subject:     "subj",
+			SchemaType:  storage.SchemaTypeAvro,
+			Schema:      `{"type":"string"}`,
+			Fingerprint: "fp-implicit-ctx",
+		}
+		if err := store.CreateSchema(ctx, ".implicit", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+
+		if _, err := store.GetContext(ctx, ".implicit"); err != nil {
+			t.Errorf("GetContext on implicitly created context: %v", err)
+		}
+		err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".implicit"})
+		if !errors.Is(err, storage.ErrContextExists) {
+			t.Errorf("expected ErrContextExists, got %v", err)
+		}
+	})
+
+	t.Run("GetContext_NotFound", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+
+		_, err := store.GetContext(context.Background(), ".missing")
+		if !errors.Is(err, storage.ErrContextNotFound) {
+			t.Errorf("expected ErrContextNotFound, got %v", err)
+		}
+	})
+
+	t.Run("UpdateContext", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".upd", Owner: "old"}); err != nil {
+			t.Fatalf("CreateContext: %v", err)
+		}
+		if err := store.UpdateContext(ctx, &storage.ContextRecord{Name: ".upd", Owner: "new", MaxSubjects: 3}); err != nil {
+			t.Fatalf("UpdateContext: %v", err)
+		}
+
+		got, err := store.GetContext(ctx, ".upd")
+		if err != nil {
+			t.Fatalf("GetContext: %v", err)
+		}
+		if got.Owner != "new" || got.MaxSubjects != 3 {
+			t.Errorf("update not applied: %+v", got)
+		}
+
+		err = store.UpdateContext(ctx, &storage.ContextRecord{Name: ".missing"})
+		if !errors.Is(err, storage.ErrContextNotFound) {
+			t.Errorf("expected ErrContextNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteContext", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".gone"}); err != nil {
+			t.Fatalf("CreateContext: %v", err)
+		}
+		if err := store.SetGlobalConfig(ctx, ".gone", &storage.ConfigRecord{CompatibilityLevel: "NONE"}); err != nil {
+			t.Fatalf("SetGlobalConfig: %v", err)
+		}
+		if err := store.DeleteContext(ctx, ".gone"); err != nil {
+			t.Fatalf("DeleteContext: %v", err)
+		}
+
+		if _, err := store.GetContext(ctx, ".gone"); !errors.Is(err, storage.ErrContextNotFound) {
+			t.Errorf("expected ErrContextNotFound after delete, got %v", err)
+		}
+		if _, err := store.GetGlobalConfig(ctx, ".gone"); err == nil {
+			t.Error("expected context config to be removed with the context")
+		}
+		if err := store.DeleteContext(ctx, ".gone"); !errors.Is(err, storage.ErrContextNotFound) {
+			t.Errorf("expected ErrContextNotFound on second delete, got %v", err)
+		}
+	})
 }
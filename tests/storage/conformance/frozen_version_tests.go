@@ -0,0 +1,99 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunFrozenVersionTests tests the frozen versions protected from deletion.
+func RunFrozenVersionTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("FreezeGetUnfreeze", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetFrozenVersion(ctx, ".", "orders-value", 1); !errors.Is(err, storage.ErrFrozenVersionNotFound) {
+			t.Fatalf("expected ErrFrozenVersionNotFound, got %v", err)
+		}
+
+		record := &storage.FrozenVersionRecord{Subject: "orders-value", Version: 1, FrozenBy: "alice", Reason: "production"}
+		if err := store.FreezeVersion(ctx, ".", record); err != nil {
+			t.Fatalf("FreezeVersion: %v", err)
+		}
+		if record.FrozenAt.IsZero() {
+			t.Error("expected FrozenAt to be set")
+		}
+
+		// Freezing again replaces the record.
+		if err := store.FreezeVersion(ctx, ".", &storage.FrozenVersionRecord{Subject: "orders-value", Version: 1, FrozenBy: "bob"}); err != nil {
+			t.Fatalf("FreezeVersion replace: %v", err)
+		}
+		got, err := store.GetFrozenVersion(ctx, ".", "orders-value", 1)
+		if err != nil {
+			t.Fatalf("GetFrozenVersion: %v", err)
+		}
+		if got.Subject != "orders-value" || got.Version != 1 || got.FrozenBy != "bob" || got.Reason != "" {
+			t.Errorf("unexpected frozen version: %+v", got)
+		}
+
+		if err := store.UnfreezeVersion(ctx, ".", "orders-value", 1); err != nil {
+			t.Fatalf("UnfreezeVersion: %v", err)
+		}
+		if err := store.UnfreezeVersion(ctx, ".", "orders-value", 1); !errors.Is(err, storage.ErrFrozenVersionNotFound) {
+			t.Errorf("expected ErrFrozenVersionNotFound on second unfreeze, got %v", err)
+		}
+	})
+
+	t.Run("ListBySubjectAndContext", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, f := range []struct {
+			ctx     string
+			subject string
+			version int
+		}{
+			{".", "users-value", 1},
+			{".", "orders-value", 2},
+			{".", "orders-value", 1},
+			{".staging", "orders-value", 3},
+		} {
+			if err := store.FreezeVersion(ctx, f.ctx, &storage.FrozenVersionRecord{Subject: f.subject, Version: f.version}); err != nil {
+				t.Fatalf("FreezeVersion: %v", err)
+			}
+		}
+
+		all, err := store.ListFrozenVersions(ctx, ".", "")
+		if err != nil {
+			t.Fatalf("ListFrozenVersions: %v", err)
+		}
+		if len(all) != 3 {
+			t.Fatalf("expected 3 frozen versions, got %d", len(all))
+		}
+		if all[0].Subject != "orders-value" || all[0].Version != 1 || all[1].Version != 2 || all[2].Subject != "users-value" {
+			t.Errorf("unexpected order: %+v %+v %+v", all[0], all[1], all[2])
+		}
+
+		orders, err := store.ListFrozenVersions(ctx, ".", "orders-value")
+		if err != nil || len(orders) != 2 {
+			t.Errorf("expected 2 orders-value frozen versions, got %d, %v", len(orders), err)
+		}
+
+		// Frozen versions are per-context.
+		if _, err := store.GetFrozenVersion(ctx, ".staging", "orders-value", 3); err != nil {
+			t.Errorf("expected the .staging frozen version, got %v", err)
+		}
+		if _, err := store.GetFrozenVersion(ctx, ".", "orders-value", 3); !errors.Is(err, storage.ErrFrozenVersionNotFound) {
+			t.Errorf("expected ErrFrozenVersionNotFound in the default context, got %v", err)
+		}
+		if empty, err := store.ListFrozenVersions(ctx, ".other", ""); err != nil || len(empty) != 0 {
+			t.Errorf("expected no frozen versions in .other, got %v, %v", empty, err)
+		}
+	})
+}
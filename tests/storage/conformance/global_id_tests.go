@@ -0,0 +1,90 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunGlobalIDTests tests schema ID allocation of a store configured with
+// global IDs, where every context allocates from one sequence. It is not
+// part of RunAll because the factory must create stores in that mode.
+func RunGlobalIDTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("SharedSequence", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		seen := make(map[int64]string)
+		for i, c := range []struct {
+			registryCtx string
+			fingerprint string
+		}{
+			{".", "fp-global-1"},
+			{".staging", "fp-global-2"},
+			{".dev", "fp-global-3"},
+			{".staging", "fp-global-4"},
+		} {
+			rec := &storage.SchemaRecord{
+				Subject:     "orders-value",
+				SchemaType:  storage.SchemaTypeAvro,
+				Schema:      `{"type":"string"}`,
+				Fingerprint: c.fingerprint,
+			}
+			if err := store.CreateSchema(ctx, c.registryCtx, rec); err != nil {
+				t.Fatalf("CreateSchema %d in %s: %v", i, c.registryCtx, err)
+			}
+			if other, ok := seen[rec.ID]; ok {
+				t.Fatalf("ID %d was given to schemas in %s and %s", rec.ID, other, c.registryCtx)
+			}
+			seen[rec.ID] = c.registryCtx
+		}
+
+		// The same schema in another context still gets its own ID.
+		dup := &storage.SchemaRecord{
+			Subject:     "orders-value",
+			SchemaType:  storage.SchemaTypeAvro,
+			Schema:      `{"type":"string"}`,
+			Fingerprint: "fp-global-1",
+		}
+		if err := store.CreateSchema(ctx, ".dev", dup); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if _, ok := seen[dup.ID]; ok {
+			t.Errorf("expected a new ID for the schema in .dev, got %d", dup.ID)
+		}
+
+		maxID, err := store.GetMaxSchemaID(ctx, ".staging")
+		if err != nil {
+			t.Fatalf("GetMaxSchemaID: %v", err)
+		}
+		if maxID < dup.ID {
+			t.Errorf("expected the max ID to cover every context, got %d < %d", maxID, dup.ID)
+		}
+	})
+
+	t.Run("SetNextIDAdvancesSharedSequence", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if err := store.SetNextID(ctx, ".staging", 500); err != nil {
+			t.Fatalf("SetNextID: %v", err)
+		}
+		rec := &storage.SchemaRecord{
+			Subject:     "orders-value",
+			SchemaType:  storage.SchemaTypeAvro,
+			Schema:      `{"type":"string"}`,
+			Fingerprint: "fp-global-next",
+		}
+		if err := store.CreateSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if rec.ID < 500 {
+			t.Errorf("expected an ID of at least 500 in the default context, got %d", rec.ID)
+		}
+	})
+}
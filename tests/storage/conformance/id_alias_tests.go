@@ -0,0 +1,98 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunIDAliasTests tests the aliases of source registries' schema IDs.
+func RunIDAliasTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("SetGetDelete", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetSchemaIDAlias(ctx, ".", "us-east", 1); !errors.Is(err, storage.ErrIDAliasNotFound) {
+			t.Fatalf("expected ErrIDAliasNotFound, got %v", err)
+		}
+
+		alias := &storage.SchemaIDAliasRecord{Source: "us-east", SourceID: 1, SchemaID: 101}
+		if err := store.SetSchemaIDAlias(ctx, ".", alias); err != nil {
+			t.Fatalf("SetSchemaIDAlias: %v", err)
+		}
+		if alias.UpdatedAt.IsZero() {
+			t.Error("expected UpdatedAt to be set")
+		}
+
+		// Replacing points the source ID at another schema.
+		if err := store.SetSchemaIDAlias(ctx, ".", &storage.SchemaIDAliasRecord{Source: "us-east", SourceID: 1, SchemaID: 102}); err != nil {
+			t.Fatalf("SetSchemaIDAlias replace: %v", err)
+		}
+		got, err := store.GetSchemaIDAlias(ctx, ".", "us-east", 1)
+		if err != nil {
+			t.Fatalf("GetSchemaIDAlias: %v", err)
+		}
+		if got.Source != "us-east" || got.SourceID != 1 || got.SchemaID != 102 {
+			t.Errorf("unexpected alias: %+v", got)
+		}
+
+		if err := store.DeleteSchemaIDAlias(ctx, ".", "us-east", 1); err != nil {
+			t.Fatalf("DeleteSchemaIDAlias: %v", err)
+		}
+		if err := store.DeleteSchemaIDAlias(ctx, ".", "us-east", 1); !errors.Is(err, storage.ErrIDAliasNotFound) {
+			t.Errorf("expected ErrIDAliasNotFound on second delete, got %v", err)
+		}
+	})
+
+	t.Run("ListBySourceAndContext", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, a := range []struct {
+			ctx      string
+			source   string
+			sourceID int64
+			schemaID int64
+		}{
+			{".", "us-west", 1, 201},
+			{".", "us-east", 2, 102},
+			{".", "us-east", 1, 101},
+			{".staging", "us-east", 1, 301},
+		} {
+			if err := store.SetSchemaIDAlias(ctx, a.ctx, &storage.SchemaIDAliasRecord{Source: a.source, SourceID: a.sourceID, SchemaID: a.schemaID}); err != nil {
+				t.Fatalf("SetSchemaIDAlias: %v", err)
+			}
+		}
+
+		all, err := store.ListSchemaIDAliases(ctx, ".", "")
+		if err != nil {
+			t.Fatalf("ListSchemaIDAliases: %v", err)
+		}
+		if len(all) != 3 {
+			t.Fatalf("expected 3 aliases, got %d", len(all))
+		}
+		if all[0].Source != "us-east" || all[0].SourceID != 1 || all[1].SourceID != 2 || all[2].Source != "us-west" {
+			t.Errorf("unexpected order: %+v %+v %+v", all[0], all[1], all[2])
+		}
+
+		east, err := store.ListSchemaIDAliases(ctx, ".", "us-east")
+		if err != nil || len(east) != 2 {
+			t.Errorf("expected 2 us-east aliases, got %d, %v", len(east), err)
+		}
+
+		// Aliases are per-context.
+		got, err := store.GetSchemaIDAlias(ctx, ".staging", "us-east", 1)
+		if err != nil || got.SchemaID != 301 {
+			t.Errorf("expected the .staging alias, got %+v, %v", got, err)
+		}
+		if empty, err := store.ListSchemaIDAliases(ctx, ".other", ""); err != nil || len(empty) != 0 {
+			t.Errorf("expected no aliases in .other, got %v, %v", empty, err)
+		}
+	})
+}
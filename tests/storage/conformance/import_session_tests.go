@@ -0,0 +1,96 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunImportSessionTests tests storage of staged import sessions.
+func RunImportSessionTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("CreateGetUpdate", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetImportSession(ctx, "missing"); !errors.Is(err, storage.ErrImportSessionNotFound) {
+			t.Fatalf("expected ErrImportSessionNotFound, got %v", err)
+		}
+
+		session := &storage.ImportSessionRecord{
+			ID:        "s1",
+			Context:   ".",
+			Subjects:  []string{"orders-value", "payments-value"},
+			State:     storage.ImportSessionOpen,
+			Modes:     map[string]string{"orders-value": "READONLY", "payments-value": ""},
+			CreatedBy: "admin",
+		}
+		if err := store.CreateImportSession(ctx, session); err != nil {
+			t.Fatalf("CreateImportSession: %v", err)
+		}
+		if session.CreatedAt.IsZero() || session.UpdatedAt.IsZero() {
+			t.Error("expected timestamps to be set")
+		}
+		if err := store.CreateImportSession(ctx, &storage.ImportSessionRecord{ID: "s1", State: storage.ImportSessionOpen}); !errors.Is(err, storage.ErrImportSessionExists) {
+			t.Errorf("expected ErrImportSessionExists, got %v", err)
+		}
+
+		session.Schemas = `[{"id":1}]`
+		session.Staged = 1
+		session.State = storage.ImportSessionCommitted
+		if err := store.UpdateImportSession(ctx, session); err != nil {
+			t.Fatalf("UpdateImportSession: %v", err)
+		}
+
+		got, err := store.GetImportSession(ctx, "s1")
+		if err != nil {
+			t.Fatalf("GetImportSession: %v", err)
+		}
+		if got.Context != "." || got.State != storage.ImportSessionCommitted || got.CreatedBy != "admin" {
+			t.Errorf("unexpected session: %+v", got)
+		}
+		if got.Schemas != `[{"id":1}]` || got.Staged != 1 {
+			t.Errorf("unexpected staged schemas: %q (%d)", got.Schemas, got.Staged)
+		}
+		if len(got.Subjects) != 2 || got.Subjects[0] != "orders-value" || got.Subjects[1] != "payments-value" {
+			t.Errorf("unexpected subjects: %v", got.Subjects)
+		}
+		if got.Modes["orders-value"] != "READONLY" || got.Modes["payments-value"] != "" {
+			t.Errorf("unexpected modes: %v", got.Modes)
+		}
+
+		if err := store.UpdateImportSession(ctx, &storage.ImportSessionRecord{ID: "missing"}); !errors.Is(err, storage.ErrImportSessionNotFound) {
+			t.Errorf("expected ErrImportSessionNotFound on update, got %v", err)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		sessions, err := store.ListImportSessions(ctx)
+		if err != nil {
+			t.Fatalf("ListImportSessions: %v", err)
+		}
+		if len(sessions) != 0 {
+			t.Fatalf("expected no sessions, got %d", len(sessions))
+		}
+		for _, id := range []string{"a", "b"} {
+			if err := store.CreateImportSession(ctx, &storage.ImportSessionRecord{ID: id, Context: ".", Subjects: []string{id}, State: storage.ImportSessionOpen}); err != nil {
+				t.Fatalf("CreateImportSession %s: %v", id, err)
+			}
+		}
+		sessions, err = store.ListImportSessions(ctx)
+		if err != nil {
+			t.Fatalf("ListImportSessions: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Fatalf("expected 2 sessions, got %d", len(sessions))
+		}
+	})
+}
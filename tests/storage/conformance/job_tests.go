@@ -0,0 +1,147 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunJobTests tests the async job store operations.
+func RunJobTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("CreateAndGetJob", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		job := &storage.JobRecord{
+			ID:        "job-1",
+			Type:      "test",
+			State:     storage.JobStatePending,
+			Context:   ".team-a",
+			Params:    `{"subject":"orders"}`,
+			CreatedBy: "alice",
+		}
+		if err := store.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		if job.CreatedAt.IsZero() || job.UpdatedAt.IsZero() {
+			t.Error("expected CreatedAt and UpdatedAt to be set")
+		}
+
+		got, err := store.GetJob(ctx, "job-1")
+		if err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if got.Type != "test" || got.State != storage.JobStatePending || got.Context != ".team-a" ||
+			got.Params != `{"subject":"orders"}` || got.CreatedBy != "alice" {
+			t.Errorf("unexpected job: %+v", got)
+		}
+		if !got.StartedAt.IsZero() || !got.FinishedAt.IsZero() || got.CancelRequested {
+			t.Errorf("expected a fresh job, got %+v", got)
+		}
+	})
+
+	t.Run("CreateJob_Duplicate", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if err := store.CreateJob(ctx, &storage.JobRecord{ID: "dup", Type: "test", State: storage.JobStatePending}); err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		err := store.CreateJob(ctx, &storage.JobRecord{ID: "dup", Type: "test", State: storage.JobStatePending})
+		if !errors.Is(err, storage.ErrJobExists) {
+			t.Errorf("expected ErrJobExists, got %v", err)
+		}
+	})
+
+	t.Run("GetJob_NotFound", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+
+		if _, err := store.GetJob(context.Background(), "missing"); !errors.Is(err, storage.ErrJobNotFound) {
+			t.Errorf("expected ErrJobNotFound, got %v", err)
+		}
+	})
+
+	t.Run("UpdateJob_KeepsCancelRequest", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		job := &storage.JobRecord{ID: "job-u", Type: "test", State: storage.JobStatePending}
+		if err := store.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob: %v", err)
+		}
+		if err := store.RequestJobCancel(ctx, "job-u"); err != nil {
+			t.Fatalf("RequestJobCancel: %v", err)
+		}
+
+		started := time.Now().UTC().Truncate(time.Millisecond)
+		job.State = storage.JobStateRunning
+		job.Done, job.Total = 3, 10
+		job.Message = "working"
+		job.StartedAt = started
+		if err := store.UpdateJob(ctx, job); err != nil {
+			t.Fatalf("UpdateJob: %v", err)
+		}
+
+		got, err := store.GetJob(ctx, "job-u")
+		if err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if got.State != storage.JobStateRunning || got.Done != 3 || got.Total != 10 || got.Message != "working" {
+			t.Errorf("unexpected job after update: %+v", got)
+		}
+		if !got.StartedAt.Equal(started) {
+			t.Errorf("expected StartedAt %v, got %v", started, got.StartedAt)
+		}
+		if !got.CancelRequested {
+			t.Error("expected UpdateJob to preserve CancelRequested")
+		}
+
+		if err := store.UpdateJob(ctx, &storage.JobRecord{ID: "missing", State: storage.JobStateFailed}); !errors.Is(err, storage.ErrJobNotFound) {
+			t.Errorf("expected ErrJobNotFound, got %v", err)
+		}
+		if err := store.RequestJobCancel(ctx, "missing"); !errors.Is(err, storage.ErrJobNotFound) {
+			t.Errorf("expected ErrJobNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ListAndDeleteJobs", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, id := range []string{"a", "b", "c"} {
+			if err := store.CreateJob(ctx, &storage.JobRecord{ID: id, Type: "test", State: storage.JobStatePending}); err != nil {
+				t.Fatalf("CreateJob %s: %v", id, err)
+			}
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		jobs, err := store.ListJobs(ctx)
+		if err != nil {
+			t.Fatalf("ListJobs: %v", err)
+		}
+		if len(jobs) != 3 || jobs[0].ID != "c" || jobs[2].ID != "a" {
+			t.Fatalf("expected jobs newest first, got %d jobs", len(jobs))
+		}
+
+		if err := store.DeleteJob(ctx, "b"); err != nil {
+			t.Fatalf("DeleteJob: %v", err)
+		}
+		if err := store.DeleteJob(ctx, "b"); !errors.Is(err, storage.ErrJobNotFound) {
+			t.Errorf("expected ErrJobNotFound, got %v", err)
+		}
+		jobs, _ = store.ListJobs(ctx)
+		if len(jobs) != 2 {
+			t.Errorf("expected 2 jobs after delete, got %d", len(jobs))
+		}
+	})
+}
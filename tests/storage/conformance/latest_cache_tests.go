@@ -0,0 +1,146 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunLatestCacheTests checks that latest schemas cached by storage.CachedStorage
+// are never stale. Each test puts two caches over one store to stand in for
+// two registry instances: a write through one must be visible to the other at
+// once, long before the cache TTL expires.
+func RunLatestCacheTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	newNodes := func(store storage.Storage) (*storage.CachedStorage, *storage.CachedStorage) {
+		return storage.NewCachedStorage(store, 100, time.Hour, nil), storage.NewCachedStorage(store, 100, time.Hour, nil)
+	}
+	register := func(t *testing.T, node storage.Storage, subject, schema string) *storage.SchemaRecord {
+		t.Helper()
+		rec := &storage.SchemaRecord{Subject: subject, SchemaType: storage.SchemaTypeAvro, Schema: schema, Fingerprint: "fp-" + schema}
+		if err := node.CreateSchema(context.Background(), ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		return rec
+	}
+	expectLatest := func(t *testing.T, node storage.Storage, subject string, version int, schema string) {
+		t.Helper()
+		for _, get := range []func() (*storage.SchemaRecord, error){
+			func() (*storage.SchemaRecord, error) { return node.GetLatestSchema(context.Background(), ".", subject) },
+			func() (*storage.SchemaRecord, error) {
+				return node.GetSchemaBySubjectVersion(context.Background(), ".", subject, -1)
+			},
+		} {
+			latest, err := get()
+			if err != nil {
+				t.Fatalf("latest %s: %v", subject, err)
+			}
+			if latest.Version != version || latest.Schema != schema {
+				t.Errorf("latest %s = v%d %s, want v%d %s", subject, latest.Version, latest.Schema, version, schema)
+			}
+		}
+	}
+
+	t.Run("RegisterOnOtherNode", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		a, b := newNodes(store)
+
+		register(t, a, "s", `"string"`)
+		expectLatest(t, b, "s", 1, `"string"`)
+		expectLatest(t, b, "s", 1, `"string"`) // served from b's cache
+
+		register(t, a, "s", `"int"`)
+		expectLatest(t, b, "s", 2, `"int"`)
+	})
+
+	t.Run("SoftDeleteOnOtherNode", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+		a, b := newNodes(store)
+
+		register(t, a, "s", `"string"`)
+		register(t, a, "s", `"int"`)
+		expectLatest(t, b, "s", 2, `"int"`)
+
+		if err := a.DeleteSchema(ctx, ".", "s", 2, false); err != nil {
+			t.Fatalf("DeleteSchema: %v", err)
+		}
+		expectLatest(t, b, "s", 1, `"string"`)
+
+		if err := a.DeleteSchema(ctx, ".", "s", 1, false); err != nil {
+			t.Fatalf("DeleteSchema: %v", err)
+		}
+		if _, err := b.GetLatestSchema(ctx, ".", "s"); !errors.Is(err, storage.ErrSubjectNotFound) {
+			t.Errorf("expected ErrSubjectNotFound after deleting every version, got %v", err)
+		}
+	})
+
+	t.Run("HardDeleteAndReregisterOnOtherNode", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+		a, b := newNodes(store)
+
+		register(t, a, "s", `"string"`)
+		expectLatest(t, b, "s", 1, `"string"`)
+
+		if _, err := a.DeleteSubject(ctx, ".", "s", false); err != nil {
+			t.Fatalf("DeleteSubject: %v", err)
+		}
+		if _, err := a.DeleteSubject(ctx, ".", "s", true); err != nil {
+			t.Fatalf("DeleteSubject(permanent): %v", err)
+		}
+		rec := register(t, a, "s", `"long"`)
+		expectLatest(t, b, "s", rec.Version, `"long"`)
+	})
+
+	t.Run("ConcurrentRegistrations", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+		a, b := newNodes(store)
+		register(t, a, "s", `"v0"`)
+
+		// A reader on b must never see the latest version go backwards while
+		// a writes, and must see the final version once a is done.
+		const versions = 20
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seen := 0
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				latest, err := b.GetLatestSchema(ctx, ".", "s")
+				if err != nil {
+					t.Errorf("GetLatestSchema: %v", err)
+					return
+				}
+				if latest.Version < seen {
+					t.Errorf("latest went from v%d back to v%d", seen, latest.Version)
+					return
+				}
+				seen = latest.Version
+			}
+		}()
+		for i := 1; i <= versions; i++ {
+			register(t, a, "s", fmt.Sprintf(`"v%d"`, i))
+		}
+		close(done)
+		wg.Wait()
+		expectLatest(t, b, "s", versions+1, fmt.Sprintf(`"v%d"`, versions))
+	})
+}
@@ -0,0 +1,80 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunMaintenanceTests tests storage of maintenance windows.
+func RunMaintenanceTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("CreateGetUpdateList", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetMaintenanceWindow(ctx, "missing"); !errors.Is(err, storage.ErrMaintenanceNotFound) {
+			t.Fatalf("expected ErrMaintenanceNotFound, got %v", err)
+		}
+
+		// Millisecond precision is what every backend stores.
+		start := time.Now().UTC().Truncate(time.Millisecond).Add(time.Hour)
+		late := &storage.MaintenanceWindowRecord{
+			ID:        "m2",
+			StartsAt:  start.Add(24 * time.Hour),
+			EndsAt:    start.Add(25 * time.Hour),
+			Reason:    "storage upgrade",
+			CreatedBy: "admin",
+		}
+		early := &storage.MaintenanceWindowRecord{
+			ID:       "m1",
+			StartsAt: start,
+			EndsAt:   start.Add(30 * time.Minute),
+			Reason:   "patching",
+		}
+		for _, w := range []*storage.MaintenanceWindowRecord{late, early} {
+			if err := store.CreateMaintenanceWindow(ctx, w); err != nil {
+				t.Fatalf("CreateMaintenanceWindow: %v", err)
+			}
+		}
+		if late.CreatedAt.IsZero() || late.UpdatedAt.IsZero() {
+			t.Error("expected timestamps to be set")
+		}
+		if err := store.CreateMaintenanceWindow(ctx, &storage.MaintenanceWindowRecord{ID: "m1", StartsAt: start, EndsAt: start}); !errors.Is(err, storage.ErrMaintenanceExists) {
+			t.Errorf("expected ErrMaintenanceExists, got %v", err)
+		}
+
+		late.Cancelled = true
+		late.EndsAt = start.Add(26 * time.Hour)
+		if err := store.UpdateMaintenanceWindow(ctx, late); err != nil {
+			t.Fatalf("UpdateMaintenanceWindow: %v", err)
+		}
+		got, err := store.GetMaintenanceWindow(ctx, "m2")
+		if err != nil {
+			t.Fatalf("GetMaintenanceWindow: %v", err)
+		}
+		if !got.Cancelled || !got.EndsAt.Equal(late.EndsAt) || !got.StartsAt.Equal(late.StartsAt) {
+			t.Errorf("unexpected window after update: %+v", got)
+		}
+		if got.Reason != "storage upgrade" || got.CreatedBy != "admin" {
+			t.Errorf("unexpected reason or creator: %+v", got)
+		}
+
+		if err := store.UpdateMaintenanceWindow(ctx, &storage.MaintenanceWindowRecord{ID: "missing"}); !errors.Is(err, storage.ErrMaintenanceNotFound) {
+			t.Errorf("expected ErrMaintenanceNotFound, got %v", err)
+		}
+
+		windows, err := store.ListMaintenanceWindows(ctx)
+		if err != nil {
+			t.Fatalf("ListMaintenanceWindows: %v", err)
+		}
+		if len(windows) != 2 || windows[0].ID != "m1" || windows[1].ID != "m2" {
+			t.Errorf("expected windows ordered by start time, got %+v", windows)
+		}
+	})
+}
@@ -31,6 +31,20 @@ func TestMySQLBackend(t *testing.T) {
 		truncateMySQL(t, cfg)
 		return &noCloseStore{store}
 	})
+
+	cfg.GlobalIDs = true
+	globalStore, err := mysql.NewStore(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create MySQL store with global IDs: %v", err)
+	}
+	defer globalStore.Close()
+
+	t.Run("GlobalIDs", func(t *testing.T) {
+		RunGlobalIDTests(t, func() storage.Storage {
+			truncateMySQL(t, cfg)
+			return &noCloseStore{globalStore}
+		})
+	})
 }
 
 func truncateMySQL(t *testing.T, cfg mysql.Config) {
@@ -46,7 +60,7 @@ func truncateMySQL(t *testing.T, cfg mysql.Config) {
 		t.Fatalf("Failed to disable FK checks: %v", err)
 	}
 
-	tables := []string{"exporter_statuses", "exporters", "deks", "keks", "api_keys", "users", "schema_references", "schema_fingerprints", "schemas", "modes", "configs", "id_alloc", "ctx_id_alloc", "contexts"}
+	tables := []string{"subject_versions", "subject_meta", "reader_assertions", "pending_schemas", "sessions", "frozen_versions", "schema_id_aliases", "maintenance_windows", "import_sessions", "schema_tags", "share_tokens", "schema_usage", "jobs", "role_grants", "exporter_statuses", "exporters", "deks", "keks", "api_keys", "users", "schema_references", "schema_fingerprints", "schemas", "modes", "configs", "id_alloc", "ctx_id_alloc", "contexts"}
 	for _, table := range tables {
 		if _, err := db.Exec("TRUNCATE TABLE `" + table + "`"); err != nil {
 			t.Fatalf("Failed to truncate MySQL table %s: %v", table, err)
@@ -0,0 +1,94 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunPendingSchemaTests tests storage of registrations pending approval.
+func RunPendingSchemaTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("CreateGetUpdateList", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetPendingSchema(ctx, "missing"); !errors.Is(err, storage.ErrPendingSchemaNotFound) {
+			t.Fatalf("expected ErrPendingSchemaNotFound, got %v", err)
+		}
+
+		first := &storage.PendingSchemaRecord{
+			ID:          "p1",
+			Context:     ".",
+			Subject:     "orders-value",
+			State:       storage.PendingSchemaPending,
+			Request:     `{"schema":"\"string\""}`,
+			SubmittedBy: "dev",
+			Reviewers:   []string{"alice", "role:admin"},
+		}
+		if err := store.CreatePendingSchema(ctx, first); err != nil {
+			t.Fatalf("CreatePendingSchema: %v", err)
+		}
+		if first.CreatedAt.IsZero() || first.UpdatedAt.IsZero() {
+			t.Error("expected timestamps to be set")
+		}
+		// Created timestamps are compared at millisecond precision.
+		time.Sleep(5 * time.Millisecond)
+		second := &storage.PendingSchemaRecord{
+			ID:      "p2",
+			Context: ".team-a",
+			Subject: "users-value",
+			State:   storage.PendingSchemaPending,
+			Request: `{"schema":"\"int\""}`,
+		}
+		if err := store.CreatePendingSchema(ctx, second); err != nil {
+			t.Fatalf("CreatePendingSchema: %v", err)
+		}
+		if err := store.CreatePendingSchema(ctx, &storage.PendingSchemaRecord{ID: "p1", State: storage.PendingSchemaPending}); !errors.Is(err, storage.ErrPendingSchemaExists) {
+			t.Errorf("expected ErrPendingSchemaExists, got %v", err)
+		}
+
+		first.State = storage.PendingSchemaApproved
+		first.ReviewedBy = "admin"
+		first.Reason = "looks good"
+		first.SchemaID = 7
+		first.Version = 2
+		if err := store.UpdatePendingSchema(ctx, first); err != nil {
+			t.Fatalf("UpdatePendingSchema: %v", err)
+		}
+		got, err := store.GetPendingSchema(ctx, "p1")
+		if err != nil {
+			t.Fatalf("GetPendingSchema: %v", err)
+		}
+		if got.State != storage.PendingSchemaApproved || got.ReviewedBy != "admin" || got.Reason != "looks good" ||
+			got.SchemaID != 7 || got.Version != 2 {
+			t.Errorf("unexpected pending schema after update: %+v", got)
+		}
+		if got.Context != "." || got.Subject != "orders-value" || got.Request != first.Request || got.SubmittedBy != "dev" {
+			t.Errorf("unexpected submission fields: %+v", got)
+		}
+		if len(got.Reviewers) != 2 || got.Reviewers[0] != "alice" || got.Reviewers[1] != "role:admin" {
+			t.Errorf("unexpected reviewers: %v", got.Reviewers)
+		}
+
+		if err := store.UpdatePendingSchema(ctx, &storage.PendingSchemaRecord{ID: "missing"}); !errors.Is(err, storage.ErrPendingSchemaNotFound) {
+			t.Errorf("expected ErrPendingSchemaNotFound, got %v", err)
+		}
+
+		list, err := store.ListPendingSchemas(ctx)
+		if err != nil {
+			t.Fatalf("ListPendingSchemas: %v", err)
+		}
+		if len(list) != 2 || list[0].ID != "p2" || list[1].ID != "p1" {
+			t.Errorf("expected pending schemas newest first, got %+v", list)
+		}
+		if len(list[0].Reviewers) != 0 || len(list[1].Reviewers) != 2 {
+			t.Errorf("unexpected reviewers in list: %v, %v", list[0].Reviewers, list[1].Reviewers)
+		}
+	})
+}
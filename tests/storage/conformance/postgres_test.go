@@ -32,6 +32,20 @@ func TestPostgresBackend(t *testing.T) {
 		truncatePostgres(t, cfg)
 		return &noCloseStore{store}
 	})
+
+	cfg.GlobalIDs = true
+	globalStore, err := postgres.NewStore(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create PostgreSQL store with global IDs: %v", err)
+	}
+	defer globalStore.Close()
+
+	t.Run("GlobalIDs", func(t *testing.T) {
+		RunGlobalIDTests(t, func() storage.Storage {
+			truncatePostgres(t, cfg)
+			return &noCloseStore{globalStore}
+		})
+	})
 }
 
 func truncatePostgres(t *testing.T, cfg postgres.Config) {
@@ -44,7 +58,7 @@ func truncatePostgres(t *testing.T, cfg postgres.Config) {
 	defer db.Close()
 
 	stmts := []string{
-		"TRUNCATE TABLE exporter_statuses, exporters, deks, keks, api_keys, users, schema_references, schema_fingerprints, schemas, modes, configs, ctx_id_alloc, contexts CASCADE",
+		"TRUNCATE TABLE subject_versions, subject_meta, reader_assertions, pending_schemas, sessions, frozen_versions, schema_id_aliases, maintenance_windows, import_sessions, schema_tags, share_tokens, schema_usage, jobs, role_grants, exporter_statuses, exporters, deks, keks, api_keys, users, schema_references, schema_fingerprints, schemas, modes, configs, ctx_id_alloc, contexts CASCADE",
 		"ALTER SEQUENCE schemas_id_seq RESTART WITH 1",
 		// Re-seed context and ID allocation but NOT global config/mode — the
 		// conformance tests start from a clean state and set their own.
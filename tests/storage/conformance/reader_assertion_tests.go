@@ -0,0 +1,93 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunReaderAssertionTests tests the reader schemas consumers declare for subjects.
+func RunReaderAssertionTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("SetReplaceDelete", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		record := &storage.ReaderAssertionRecord{Subject: "orders-value", Consumer: "billing", ReaderSubject: "orders-value", ReaderVersion: 1}
+		if err := store.SetReaderAssertion(ctx, ".", record); err != nil {
+			t.Fatalf("SetReaderAssertion: %v", err)
+		}
+		if record.UpdatedAt.IsZero() {
+			t.Error("expected UpdatedAt to be set")
+		}
+
+		// Setting again replaces the record.
+		if err := store.SetReaderAssertion(ctx, ".", &storage.ReaderAssertionRecord{Subject: "orders-value", Consumer: "billing", ReaderSubject: "orders-billing", ReaderVersion: 3}); err != nil {
+			t.Fatalf("SetReaderAssertion replace: %v", err)
+		}
+		got, err := store.ListReaderAssertions(ctx, ".", "orders-value")
+		if err != nil {
+			t.Fatalf("ListReaderAssertions: %v", err)
+		}
+		if len(got) != 1 || got[0].Consumer != "billing" || got[0].ReaderSubject != "orders-billing" || got[0].ReaderVersion != 3 {
+			t.Errorf("unexpected reader assertions: %+v", got)
+		}
+
+		if err := store.DeleteReaderAssertion(ctx, ".", "orders-value", "billing"); err != nil {
+			t.Fatalf("DeleteReaderAssertion: %v", err)
+		}
+		if err := store.DeleteReaderAssertion(ctx, ".", "orders-value", "billing"); !errors.Is(err, storage.ErrReaderAssertionNotFound) {
+			t.Errorf("expected ErrReaderAssertionNotFound on second delete, got %v", err)
+		}
+	})
+
+	t.Run("ListBySubjectAndContext", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, r := range []struct {
+			ctx      string
+			subject  string
+			consumer string
+		}{
+			{".", "users-value", "crm"},
+			{".", "orders-value", "shipping"},
+			{".", "orders-value", "billing"},
+			{".staging", "orders-value", "billing"},
+		} {
+			if err := store.SetReaderAssertion(ctx, r.ctx, &storage.ReaderAssertionRecord{Subject: r.subject, Consumer: r.consumer, ReaderSubject: r.subject, ReaderVersion: 1}); err != nil {
+				t.Fatalf("SetReaderAssertion: %v", err)
+			}
+		}
+
+		all, err := store.ListReaderAssertions(ctx, ".", "")
+		if err != nil {
+			t.Fatalf("ListReaderAssertions: %v", err)
+		}
+		if len(all) != 3 {
+			t.Fatalf("expected 3 reader assertions, got %d", len(all))
+		}
+		if all[0].Consumer != "billing" || all[1].Consumer != "shipping" || all[2].Subject != "users-value" {
+			t.Errorf("unexpected order: %+v %+v %+v", all[0], all[1], all[2])
+		}
+
+		orders, err := store.ListReaderAssertions(ctx, ".", "orders-value")
+		if err != nil || len(orders) != 2 {
+			t.Errorf("expected 2 orders-value reader assertions, got %d, %v", len(orders), err)
+		}
+
+		// Reader assertions are per-context.
+		staging, err := store.ListReaderAssertions(ctx, ".staging", "")
+		if err != nil || len(staging) != 1 {
+			t.Errorf("expected 1 .staging reader assertion, got %d, %v", len(staging), err)
+		}
+		if err := store.DeleteReaderAssertion(ctx, ".other", "orders-value", "billing"); !errors.Is(err, storage.ErrReaderAssertionNotFound) {
+			t.Errorf("expected ErrReaderAssertionNotFound in .other, got %v", err)
+		}
+	})
+}
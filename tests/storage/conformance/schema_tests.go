@@ -2,8 +2,11 @@ package conformance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/axonops/axonops-schema-registry/internal/storage"
 )
@@ -52,6 +55,59 @@ func RunSchemaTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("CreateSchema_ConcurrentRegistrations", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		// Writers registering different schemas to one subject at the same
+		// time must each get their own version, with no gaps.
+		const writers, perWriter = 8, 5
+		var wg sync.WaitGroup
+		versions := make(chan int, writers*perWriter)
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < perWriter; i++ {
+					rec := &storage.SchemaRecord{
+						Subject:     "concurrent",
+						SchemaType:  storage.SchemaTypeAvro,
+						Schema:      fmt.Sprintf(`{"type":"record","name":"R%d_%d","fields":[]}`, w, i),
+						Fingerprint: fmt.Sprintf("fp-concurrent-%d-%d", w, i),
+					}
+					if err := store.CreateSchema(ctx, ".", rec); err != nil {
+						t.Errorf("CreateSchema writer %d #%d: %v", w, i, err)
+						return
+					}
+					versions <- rec.Version
+				}
+			}(w)
+		}
+		wg.Wait()
+		close(versions)
+
+		seen := map[int]bool{}
+		for v := range versions {
+			if seen[v] {
+				t.Errorf("version %d was assigned twice", v)
+			}
+			seen[v] = true
+		}
+		for v := 1; v <= writers*perWriter; v++ {
+			if !seen[v] {
+				t.Errorf("version %d was not assigned", v)
+			}
+		}
+		schemas, err := store.GetSchemasBySubject(ctx, ".", "concurrent", false)
+		if err != nil {
+			t.Fatalf("GetSchemasBySubject: %v", err)
+		}
+		if len(schemas) != writers*perWriter {
+			t.Errorf("expected %d versions, got %d", writers*perWriter, len(schemas))
+		}
+	})
+
 	t.Run("CreateSchema_GlobalDeduplication", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -198,6 +254,147 @@ func RunSchemaTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("ListVersions_PaginatesAndFilters", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for i := 0; i < 5; i++ {
+			rec := &storage.SchemaRecord{
+				Subject:     "s",
+				SchemaType:  storage.SchemaTypeAvro,
+				Schema:      fmt.Sprintf(`{"type":"string","doc":"%d"}`, i),
+				Fingerprint: fmt.Sprintf("fp-lv-%d", i),
+			}
+			if err := store.CreateSchema(ctx, ".", rec); err != nil {
+				t.Fatalf("CreateSchema: %v", err)
+			}
+		}
+		store.DeleteSchema(ctx, ".", "s", 5, false) // soft delete the latest
+
+		tests := []struct {
+			name   string
+			params storage.ListVersionsParams
+			want   []int
+		}{
+			{"all active", storage.ListVersionsParams{}, []int{1, 2, 3, 4}},
+			{"include deleted", storage.ListVersionsParams{Deleted: true}, []int{1, 2, 3, 4, 5}},
+			{"offset and limit", storage.ListVersionsParams{Offset: 1, Limit: 2}, []int{2, 3}},
+			{"offset only", storage.ListVersionsParams{Offset: 3}, []int{4}},
+			{"offset past end", storage.ListVersionsParams{Offset: 10}, []int{}},
+			{"latest only", storage.ListVersionsParams{LatestOnly: true}, []int{4}},
+			{"latest only with deleted", storage.ListVersionsParams{LatestOnly: true, Deleted: true}, []int{5}},
+		}
+		for _, tt := range tests {
+			got, err := store.ListVersions(ctx, ".", "s", &tt.params)
+			if err != nil {
+				t.Fatalf("%s: ListVersions: %v", tt.name, err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("ListSubjectsPage_PaginatesAndFilters", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for i, subject := range []string{"orders-b", "orders-a", "payments", "orders-c", "orders-d"} {
+			rec := &storage.SchemaRecord{
+				Subject:     subject,
+				SchemaType:  storage.SchemaTypeAvro,
+				Schema:      fmt.Sprintf(`{"type":"string","doc":"%d"}`, i),
+				Fingerprint: fmt.Sprintf("fp-lsp-%d", i),
+			}
+			if err := store.CreateSchema(ctx, ".", rec); err != nil {
+				t.Fatalf("CreateSchema: %v", err)
+			}
+		}
+		if _, err := store.DeleteSubject(ctx, ".", "orders-c", false); err != nil {
+			t.Fatalf("DeleteSubject: %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			params storage.ListSubjectsParams
+			want   []string
+		}{
+			{"all active", storage.ListSubjectsParams{}, []string{"orders-a", "orders-b", "orders-d", "payments"}},
+			{"include deleted", storage.ListSubjectsParams{Deleted: true}, []string{"orders-a", "orders-b", "orders-c", "orders-d", "payments"}},
+			{"deleted only", storage.ListSubjectsParams{DeletedOnly: true}, []string{"orders-c"}},
+			{"prefix", storage.ListSubjectsParams{Prefix: "orders-"}, []string{"orders-a", "orders-b", "orders-d"}},
+			{"offset and limit", storage.ListSubjectsParams{Offset: 1, Limit: 2}, []string{"orders-b", "orders-d"}},
+			{"prefix with offset", storage.ListSubjectsParams{Prefix: "orders-", Deleted: true, Offset: 2}, []string{"orders-c", "orders-d"}},
+			{"offset past end", storage.ListSubjectsParams{Offset: 10}, []string{}},
+		}
+		for _, tt := range tests {
+			got, err := store.ListSubjectsPage(ctx, ".", &tt.params)
+			if err != nil {
+				t.Fatalf("%s: ListSubjectsPage: %v", tt.name, err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("ListSoftDeletedVersions_FiltersByDeletionTime", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, subject := range []string{"b", "a"} {
+			for i := 0; i < 2; i++ {
+				rec := &storage.SchemaRecord{
+					Subject:     subject,
+					SchemaType:  storage.SchemaTypeAvro,
+					Schema:      fmt.Sprintf(`{"type":"string","doc":"%s%d"}`, subject, i),
+					Fingerprint: fmt.Sprintf("fp-sdv-%s-%d", subject, i),
+				}
+				if err := store.CreateSchema(ctx, ".", rec); err != nil {
+					t.Fatalf("CreateSchema: %v", err)
+				}
+			}
+		}
+		if err := store.DeleteSchema(ctx, ".", "b", 1, false); err != nil {
+			t.Fatalf("DeleteSchema: %v", err)
+		}
+		if _, err := store.DeleteSubject(ctx, ".", "a", false); err != nil {
+			t.Fatalf("DeleteSubject: %v", err)
+		}
+
+		// Generous margins keep the test independent of database clock skew.
+		got, err := store.ListSoftDeletedVersions(ctx, ".", time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("ListSoftDeletedVersions: %v", err)
+		}
+		want := []storage.SubjectVersion{{Subject: "a", Version: 1}, {Subject: "a", Version: 2}, {Subject: "b", Version: 1}}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		got, err = store.ListSoftDeletedVersions(ctx, ".", time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("ListSoftDeletedVersions: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("expected no versions deleted an hour ago, got %v", got)
+		}
+	})
+
+	t.Run("ListVersions_SubjectNotFound", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		_, err := store.ListVersions(ctx, ".", "missing", &storage.ListVersionsParams{})
+		if !errors.Is(err, storage.ErrSubjectNotFound) {
+			t.Errorf("expected ErrSubjectNotFound, got %v", err)
+		}
+	})
+
 	t.Run("GetSchemaByFingerprint", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -294,6 +491,53 @@ func RunSchemaTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("GetLatestSchemaStamp", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetLatestSchemaStamp(ctx, ".", "s"); !errors.Is(err, storage.ErrSubjectNotFound) {
+			t.Fatalf("expected ErrSubjectNotFound, got %v", err)
+		}
+		r1 := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-stamp-1"}
+		store.CreateSchema(ctx, ".", r1)
+		stamp1, err := store.GetLatestSchemaStamp(ctx, ".", "s")
+		if err != nil {
+			t.Fatalf("GetLatestSchemaStamp: %v", err)
+		}
+		if again, _ := store.GetLatestSchemaStamp(ctx, ".", "s"); again != stamp1 {
+			t.Errorf("stamp changed without a write: %q, then %q", stamp1, again)
+		}
+
+		r2 := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"int"}`, Fingerprint: "fp-stamp-2"}
+		store.CreateSchema(ctx, ".", r2)
+		stamp2, _ := store.GetLatestSchemaStamp(ctx, ".", "s")
+		if stamp2 == stamp1 {
+			t.Error("expected a new stamp after registering version 2")
+		}
+
+		store.DeleteSchema(ctx, ".", "s", 2, false)
+		if stamp, _ := store.GetLatestSchemaStamp(ctx, ".", "s"); stamp != stamp1 {
+			t.Errorf("expected the version 1 stamp after soft-deleting version 2, got %q", stamp)
+		}
+
+		// Hard-deleting the subject and registering the same schema again
+		// must still produce a new stamp.
+		store.DeleteSchema(ctx, ".", "s", 1, false)
+		store.DeleteSchema(ctx, ".", "s", 1, true)
+		store.DeleteSchema(ctx, ".", "s", 2, true)
+		if _, err := store.GetLatestSchemaStamp(ctx, ".", "s"); !errors.Is(err, storage.ErrSubjectNotFound) {
+			t.Errorf("expected ErrSubjectNotFound after deleting every version, got %v", err)
+		}
+		r3 := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-stamp-1"}
+		if err := store.CreateSchema(ctx, ".", r3); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if stamp, _ := store.GetLatestSchemaStamp(ctx, ".", "s"); stamp == stamp1 || stamp == stamp2 {
+			t.Errorf("expected a new stamp after re-registering, got %q", stamp)
+		}
+	})
+
 	t.Run("DeleteSchema_Soft", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -554,6 +798,155 @@ func RunSchemaTests(t *testing.T, newStore StoreFactory) {
 		}
 	})
 
+	t.Run("ListSchemas_SubjectPrefixWildcards", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		r1 := &storage.SchemaRecord{Subject: "my_topic-value", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-wc-1"}
+		r2 := &storage.SchemaRecord{Subject: "myXtopic-value", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"int"}`, Fingerprint: "fp-wc-2"}
+		store.CreateSchema(ctx, ".", r1)
+		store.CreateSchema(ctx, ".", r2)
+
+		schemas, err := store.ListSchemas(ctx, ".", &storage.ListSchemasParams{SubjectPrefix: "my_"})
+		if err != nil {
+			t.Fatalf("ListSchemas: %v", err)
+		}
+		if len(schemas) != 1 || schemas[0].Subject != "my_topic-value" {
+			t.Errorf("expected only my_topic-value for prefix 'my_', got %d schemas", len(schemas))
+		}
+	})
+
+	t.Run("ListSchemas_DeletedLatestOnly", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		r1 := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-dl-1"}
+		r2 := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"int"}`, Fingerprint: "fp-dl-2"}
+		store.CreateSchema(ctx, ".", r1)
+		store.CreateSchema(ctx, ".", r2)
+		if err := store.DeleteSchema(ctx, ".", "s", 2, false); err != nil {
+			t.Fatalf("DeleteSchema: %v", err)
+		}
+
+		schemas, err := store.ListSchemas(ctx, ".", &storage.ListSchemasParams{LatestOnly: true})
+		if err != nil {
+			t.Fatalf("ListSchemas: %v", err)
+		}
+		if len(schemas) != 1 || schemas[0].Version != 1 {
+			t.Errorf("expected version 1 as the latest non-deleted version, got %+v", schemas)
+		}
+		schemas, err = store.ListSchemas(ctx, ".", &storage.ListSchemasParams{LatestOnly: true, Deleted: true})
+		if err != nil {
+			t.Fatalf("ListSchemas: %v", err)
+		}
+		if len(schemas) != 1 || schemas[0].Version != 2 || !schemas[0].Deleted {
+			t.Errorf("expected deleted version 2 as the latest version, got %+v", schemas)
+		}
+	})
+
+	t.Run("ListSchemas_Aliases", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		r1 := &storage.SchemaRecord{Subject: "orders-value", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-al-1"}
+		r2 := &storage.SchemaRecord{Subject: "orders-value", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"int"}`, Fingerprint: "fp-al-2"}
+		r3 := &storage.SchemaRecord{Subject: "users-value", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"long"}`, Fingerprint: "fp-al-3"}
+		store.CreateSchema(ctx, ".", r1)
+		store.CreateSchema(ctx, ".", r2)
+		store.CreateSchema(ctx, ".", r3)
+		if err := store.SetConfig(ctx, ".", "legacy-orders", &storage.ConfigRecord{Alias: "orders-value"}); err != nil {
+			t.Fatalf("SetConfig: %v", err)
+		}
+
+		schemas, err := store.ListSchemas(ctx, ".", &storage.ListSchemasParams{SubjectPrefix: "legacy"})
+		if err != nil {
+			t.Fatalf("ListSchemas: %v", err)
+		}
+		if len(schemas) != 0 {
+			t.Errorf("expected no schemas without aliases, got %d", len(schemas))
+		}
+
+		schemas, err = store.ListSchemas(ctx, ".", &storage.ListSchemasParams{SubjectPrefix: "legacy", Aliases: true})
+		if err != nil {
+			t.Fatalf("ListSchemas: %v", err)
+		}
+		if len(schemas) != 2 || schemas[0].Subject != "legacy-orders" || schemas[0].ID != r1.ID || schemas[1].Version != 2 {
+			t.Errorf("expected the versions of orders-value under legacy-orders, got %+v", schemas)
+		}
+
+		schemas, err = store.ListSchemas(ctx, ".", &storage.ListSchemasParams{Aliases: true, LatestOnly: true, Offset: 1, Limit: 1})
+		if err != nil {
+			t.Fatalf("ListSchemas: %v", err)
+		}
+		// Latest versions ordered by ID: orders-value v2 and legacy-orders v2
+		// share r2's ID, then users-value.
+		if len(schemas) != 1 || schemas[0].Subject != "orders-value" || schemas[0].Version != 2 {
+			t.Errorf("unexpected page: %+v", schemas)
+		}
+	})
+
+	t.Run("IterateSchemas_OrderedBySubjectAndVersion", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for i, subject := range []string{"b", "a", "b", "a"} {
+			rec := &storage.SchemaRecord{Subject: subject, SchemaType: storage.SchemaTypeAvro, Schema: fmt.Sprintf(`{"type":"fixed","name":"f","size":%d}`, i+1), Fingerprint: fmt.Sprintf("fp-it-%d", i)}
+			if err := store.CreateSchema(ctx, ".", rec); err != nil {
+				t.Fatalf("CreateSchema: %v", err)
+			}
+		}
+		store.DeleteSchema(ctx, ".", "b", 1, false)
+
+		var got []string
+		err := store.IterateSchemas(ctx, ".", false, func(rec *storage.SchemaRecord) error {
+			got = append(got, fmt.Sprintf("%s-%d", rec.Subject, rec.Version))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("IterateSchemas: %v", err)
+		}
+		if fmt.Sprint(got) != "[a-1 a-2 b-2]" {
+			t.Errorf("expected [a-1 a-2 b-2], got %v", got)
+		}
+
+		got = nil
+		store.IterateSchemas(ctx, ".", true, func(rec *storage.SchemaRecord) error {
+			got = append(got, fmt.Sprintf("%s-%d", rec.Subject, rec.Version))
+			return nil
+		})
+		if fmt.Sprint(got) != "[a-1 a-2 b-1 b-2]" {
+			t.Errorf("expected soft-deleted b-1 with includeDeleted, got %v", got)
+		}
+	})
+
+	t.Run("IterateSchemas_StopsOnError", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			rec := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: fmt.Sprintf(`{"type":"fixed","name":"f","size":%d}`, i+1), Fingerprint: fmt.Sprintf("fp-its-%d", i)}
+			store.CreateSchema(ctx, ".", rec)
+		}
+
+		errStop := errors.New("stop")
+		calls := 0
+		err := store.IterateSchemas(ctx, ".", false, func(rec *storage.SchemaRecord) error {
+			calls++
+			return errStop
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("expected the callback's error, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected iteration to stop after 1 call, got %d", calls)
+		}
+	})
+
 	t.Run("ListSchemas_ExcludesDeleted", func(t *testing.T) {
 		store := newStore()
 		defer store.Close()
@@ -579,4 +972,57 @@ func RunSchemaTests(t *testing.T, newStore StoreFactory) {
 			t.Errorf("expected 1 schema (deleted included), got %d", len(schemas))
 		}
 	})
+
+	t.Run("RewriteSchemaText", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		// The same schema in two subjects, and a second version of one of them.
+		shared := &storage.SchemaRecord{Subject: "a", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-rewrite-1"}
+		if err := store.CreateSchema(ctx, ".", shared); err != nil {
+			t.Fatalf("CreateSchema a: %v", err)
+		}
+		if err := store.CreateSchema(ctx, ".", &storage.SchemaRecord{Subject: "b", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-rewrite-1"}); err != nil {
+			t.Fatalf("CreateSchema b: %v", err)
+		}
+		other := &storage.SchemaRecord{Subject: "a", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"int"}`, Fingerprint: "fp-rewrite-2"}
+		if err := store.CreateSchema(ctx, ".", other); err != nil {
+			t.Fatalf("CreateSchema a v2: %v", err)
+		}
+
+		if err := store.RewriteSchemaText(ctx, ".", shared.ID, `{"type": "string"}`); err != nil {
+			t.Fatalf("RewriteSchemaText: %v", err)
+		}
+		for _, subject := range []string{"a", "b"} {
+			got, err := store.GetSchemaBySubjectVersion(ctx, ".", subject, 1)
+			if err != nil {
+				t.Fatalf("GetSchemaBySubjectVersion %s: %v", subject, err)
+			}
+			if got.Schema != `{"type": "string"}` || got.Fingerprint != "fp-rewrite-1" {
+				t.Errorf("%s v1: expected the rewritten text with its fingerprint, got %q (%s)", subject, got.Schema, got.Fingerprint)
+			}
+		}
+		got, err := store.GetSchemaByID(ctx, ".", shared.ID)
+		if err != nil {
+			t.Fatalf("GetSchemaByID: %v", err)
+		}
+		if got.Schema != `{"type": "string"}` {
+			t.Errorf("expected the rewritten text by ID, got %q", got.Schema)
+		}
+		got, err = store.GetSchemaBySubjectVersion(ctx, ".", "a", 2)
+		if err != nil {
+			t.Fatalf("GetSchemaBySubjectVersion a v2: %v", err)
+		}
+		if got.Schema != `{"type":"int"}` {
+			t.Errorf("expected other schemas to be left alone, got %q", got.Schema)
+		}
+
+		if err := store.RewriteSchemaText(ctx, ".", 99999, `"string"`); !errors.Is(err, storage.ErrSchemaNotFound) {
+			t.Errorf("expected ErrSchemaNotFound, got %v", err)
+		}
+		if err := store.RewriteSchemaText(ctx, ".other", shared.ID, `"string"`); !errors.Is(err, storage.ErrSchemaNotFound) {
+			t.Errorf("expected ErrSchemaNotFound in another context, got %v", err)
+		}
+	})
 }
@@ -0,0 +1,103 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunSubjectMetaTests tests the owner, description, contact and links of subjects.
+func RunSubjectMetaTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("SetGetDelete", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetSubjectMeta(ctx, ".", "orders-value"); !errors.Is(err, storage.ErrSubjectMetaNotFound) {
+			t.Errorf("expected ErrSubjectMetaNotFound before set, got %v", err)
+		}
+
+		record := &storage.SubjectMetaRecord{
+			Subject:     "orders-value",
+			Owner:       "payments",
+			Description: "Orders placed in the web shop",
+			Contact:     "payments@example.com",
+			Links:       []storage.SubjectLink{{Name: "runbook", URL: "https://wiki.example.com/orders"}},
+			Reviewers:   []string{"alice", "role:admin"},
+		}
+		if err := store.SetSubjectMeta(ctx, ".", record); err != nil {
+			t.Fatalf("SetSubjectMeta: %v", err)
+		}
+		if record.UpdatedAt.IsZero() {
+			t.Error("expected UpdatedAt to be set")
+		}
+		got, err := store.GetSubjectMeta(ctx, ".", "orders-value")
+		if err != nil {
+			t.Fatalf("GetSubjectMeta: %v", err)
+		}
+		if got.Owner != "payments" || got.Description != "Orders placed in the web shop" || got.Contact != "payments@example.com" {
+			t.Errorf("unexpected subject meta: %+v", got)
+		}
+		if len(got.Links) != 1 || got.Links[0].Name != "runbook" || got.Links[0].URL != "https://wiki.example.com/orders" {
+			t.Errorf("unexpected links: %+v", got.Links)
+		}
+		if len(got.Reviewers) != 2 || got.Reviewers[0] != "alice" || got.Reviewers[1] != "role:admin" {
+			t.Errorf("unexpected reviewers: %v", got.Reviewers)
+		}
+
+		// Setting again replaces the record, links included.
+		if err := store.SetSubjectMeta(ctx, ".", &storage.SubjectMetaRecord{Subject: "orders-value", Owner: "fulfilment"}); err != nil {
+			t.Fatalf("SetSubjectMeta replace: %v", err)
+		}
+		got, err = store.GetSubjectMeta(ctx, ".", "orders-value")
+		if err != nil {
+			t.Fatalf("GetSubjectMeta: %v", err)
+		}
+		if got.Owner != "fulfilment" || got.Description != "" || len(got.Links) != 0 || len(got.Reviewers) != 0 {
+			t.Errorf("expected the record to be replaced, got %+v", got)
+		}
+
+		// Meta is per-context.
+		if _, err := store.GetSubjectMeta(ctx, ".staging", "orders-value"); !errors.Is(err, storage.ErrSubjectMetaNotFound) {
+			t.Errorf("expected ErrSubjectMetaNotFound in another context, got %v", err)
+		}
+
+		if err := store.DeleteSubjectMeta(ctx, ".", "orders-value"); err != nil {
+			t.Fatalf("DeleteSubjectMeta: %v", err)
+		}
+		if err := store.DeleteSubjectMeta(ctx, ".", "orders-value"); !errors.Is(err, storage.ErrSubjectMetaNotFound) {
+			t.Errorf("expected ErrSubjectMetaNotFound on second delete, got %v", err)
+		}
+	})
+
+	t.Run("RemovedWithSubject", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		rec := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-meta"}
+		if err := store.CreateSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if err := store.SetSubjectMeta(ctx, ".", &storage.SubjectMetaRecord{Subject: "s", Owner: "data"}); err != nil {
+			t.Fatalf("SetSubjectMeta: %v", err)
+		}
+
+		if _, err := store.DeleteSubject(ctx, ".", "s", false); err != nil {
+			t.Fatalf("DeleteSubject(soft): %v", err)
+		}
+		if _, err := store.GetSubjectMeta(ctx, ".", "s"); err != nil {
+			t.Errorf("expected meta to survive a soft delete, got %v", err)
+		}
+		if _, err := store.DeleteSubject(ctx, ".", "s", true); err != nil {
+			t.Fatalf("DeleteSubject(permanent): %v", err)
+		}
+		if _, err := store.GetSubjectMeta(ctx, ".", "s"); !errors.Is(err, storage.ErrSubjectMetaNotFound) {
+			t.Errorf("expected meta to be removed with the subject, got %v", err)
+		}
+	})
+}
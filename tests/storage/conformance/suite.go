@@ -25,4 +25,16 @@ func RunAll(t *testing.T, newStore StoreFactory) {
 	t.Run("DEK", func(t *testing.T) { RunDEKTests(t, newStore) })
 	t.Run("Exporter", func(t *testing.T) { RunExporterTests(t, newStore) })
 	t.Run("Context", func(t *testing.T) { RunContextTests(t, newStore) })
+	t.Run("Job", func(t *testing.T) { RunJobTests(t, newStore) })
+	t.Run("SchemaUsage", func(t *testing.T) { RunSchemaUsageTests(t, newStore) })
+	t.Run("LatestCache", func(t *testing.T) { RunLatestCacheTests(t, newStore) })
+	t.Run("Tenant", func(t *testing.T) { RunTenantTests(t, newStore) })
+	t.Run("Tags", func(t *testing.T) { RunTagsTests(t, newStore) })
+	t.Run("SubjectMeta", func(t *testing.T) { RunSubjectMetaTests(t, newStore) })
+	t.Run("ImportSession", func(t *testing.T) { RunImportSessionTests(t, newStore) })
+	t.Run("IDAlias", func(t *testing.T) { RunIDAliasTests(t, newStore) })
+	t.Run("FrozenVersion", func(t *testing.T) { RunFrozenVersionTests(t, newStore) })
+	t.Run("ReaderAssertion", func(t *testing.T) { RunReaderAssertionTests(t, newStore) })
+	t.Run("Maintenance", func(t *testing.T) { RunMaintenanceTests(t, newStore) })
+	t.Run("PendingSchema", func(t *testing.T) { RunPendingSchemaTests(t, newStore) })
 }
@@ -0,0 +1,130 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunTagsTests tests tags and labels on subjects and subject versions.
+func RunTagsTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("SetGetDelete", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if _, err := store.GetTags(ctx, ".", "orders-value", 0); !errors.Is(err, storage.ErrTagsNotFound) {
+			t.Fatalf("expected ErrTagsNotFound, got %v", err)
+		}
+
+		rec := &storage.TagsRecord{Subject: "orders-value", Tags: []string{"pii"}, Labels: map[string]string{"owner": "sales"}}
+		if err := store.SetTags(ctx, ".", rec); err != nil {
+			t.Fatalf("SetTags: %v", err)
+		}
+		if rec.UpdatedAt.IsZero() {
+			t.Error("expected UpdatedAt to be set")
+		}
+		if err := store.SetTags(ctx, ".", &storage.TagsRecord{Subject: "orders-value", Version: 2, Tags: []string{"golden"}}); err != nil {
+			t.Fatalf("SetTags on version: %v", err)
+		}
+
+		// Replacing overwrites both tags and labels.
+		if err := store.SetTags(ctx, ".", &storage.TagsRecord{Subject: "orders-value", Tags: []string{"gdpr", "pii"}, Labels: map[string]string{"tier": "1"}}); err != nil {
+			t.Fatalf("SetTags replace: %v", err)
+		}
+		got, err := store.GetTags(ctx, ".", "orders-value", 0)
+		if err != nil {
+			t.Fatalf("GetTags: %v", err)
+		}
+		if len(got.Tags) != 2 || got.Tags[0] != "gdpr" || got.Tags[1] != "pii" {
+			t.Errorf("unexpected tags: %v", got.Tags)
+		}
+		if len(got.Labels) != 1 || got.Labels["tier"] != "1" {
+			t.Errorf("unexpected labels: %v", got.Labels)
+		}
+
+		if err := store.DeleteTags(ctx, ".", "orders-value", 0); err != nil {
+			t.Fatalf("DeleteTags: %v", err)
+		}
+		if err := store.DeleteTags(ctx, ".", "orders-value", 0); !errors.Is(err, storage.ErrTagsNotFound) {
+			t.Errorf("expected ErrTagsNotFound on second delete, got %v", err)
+		}
+		if got, err := store.GetTags(ctx, ".", "orders-value", 2); err != nil || len(got.Tags) != 1 {
+			t.Errorf("expected version tags to survive, got %+v, %v", got, err)
+		}
+	})
+
+	t.Run("ListPerContext", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, rec := range []*storage.TagsRecord{
+			{Subject: "b-value", Version: 1, Tags: []string{"x"}},
+			{Subject: "a-value", Tags: []string{"y"}},
+			{Subject: "b-value", Tags: []string{"z"}},
+		} {
+			if err := store.SetTags(ctx, ".", rec); err != nil {
+				t.Fatalf("SetTags: %v", err)
+			}
+		}
+		if err := store.SetTags(ctx, ".staging", &storage.TagsRecord{Subject: "a-value", Tags: []string{"other"}}); err != nil {
+			t.Fatalf("SetTags: %v", err)
+		}
+
+		records, err := store.ListTags(ctx, ".")
+		if err != nil {
+			t.Fatalf("ListTags: %v", err)
+		}
+		if len(records) != 3 {
+			t.Fatalf("expected 3 records in the default context, got %d", len(records))
+		}
+		order := []struct {
+			subject string
+			version int
+		}{{"a-value", 0}, {"b-value", 0}, {"b-value", 1}}
+		for i, want := range order {
+			if records[i].Subject != want.subject || records[i].Version != want.version {
+				t.Errorf("record %d: expected %s v%d, got %s v%d", i, want.subject, want.version, records[i].Subject, records[i].Version)
+			}
+		}
+	})
+
+	t.Run("RemovedWithSubject", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		rec := &storage.SchemaRecord{Subject: "s", SchemaType: storage.SchemaTypeAvro, Schema: `{"type":"string"}`, Fingerprint: "fp-tags"}
+		if err := store.CreateSchema(ctx, ".", rec); err != nil {
+			t.Fatalf("CreateSchema: %v", err)
+		}
+		if err := store.SetTags(ctx, ".", &storage.TagsRecord{Subject: "s", Tags: []string{"pii"}}); err != nil {
+			t.Fatalf("SetTags: %v", err)
+		}
+		if err := store.SetTags(ctx, ".", &storage.TagsRecord{Subject: "s", Version: rec.Version, Tags: []string{"golden"}}); err != nil {
+			t.Fatalf("SetTags: %v", err)
+		}
+
+		if _, err := store.DeleteSubject(ctx, ".", "s", false); err != nil {
+			t.Fatalf("DeleteSubject(soft): %v", err)
+		}
+		if _, err := store.GetTags(ctx, ".", "s", 0); err != nil {
+			t.Errorf("expected tags to survive a soft delete, got %v", err)
+		}
+		if _, err := store.DeleteSubject(ctx, ".", "s", true); err != nil {
+			t.Fatalf("DeleteSubject(permanent): %v", err)
+		}
+		records, err := store.ListTags(ctx, ".")
+		if err != nil {
+			t.Fatalf("ListTags: %v", err)
+		}
+		if len(records) != 0 {
+			t.Errorf("expected tags to be removed with the subject, got %d records", len(records))
+		}
+	})
+}
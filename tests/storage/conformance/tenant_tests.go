@@ -0,0 +1,161 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunTenantTests tests tenant records and the tenant ownership of contexts and users.
+func RunTenantTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("CreateGetUpdateDelete", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		tenant := &storage.TenantRecord{Name: "acme", Description: "Acme Corp", EncryptedKey: "wrapped-key"}
+		if err := store.CreateTenant(ctx, tenant); err != nil {
+			t.Fatalf("CreateTenant: %v", err)
+		}
+		if tenant.CreatedAt.IsZero() {
+			t.Error("expected CreatedAt to be set")
+		}
+
+		got, err := store.GetTenant(ctx, "acme")
+		if err != nil {
+			t.Fatalf("GetTenant: %v", err)
+		}
+		if got.Description != "Acme Corp" || got.EncryptedKey != "wrapped-key" {
+			t.Errorf("unexpected tenant: %+v", got)
+		}
+
+		update := &storage.TenantRecord{Name: "acme", Description: "Acme Inc"}
+		if err := store.UpdateTenant(ctx, update); err != nil {
+			t.Fatalf("UpdateTenant: %v", err)
+		}
+		got, err = store.GetTenant(ctx, "acme")
+		if err != nil {
+			t.Fatalf("GetTenant after update: %v", err)
+		}
+		if got.Description != "Acme Inc" {
+			t.Errorf("expected updated description, got %q", got.Description)
+		}
+		if got.EncryptedKey != "wrapped-key" {
+			t.Errorf("UpdateTenant must not change the key, got %q", got.EncryptedKey)
+		}
+
+		if err := store.DeleteTenant(ctx, "acme"); err != nil {
+			t.Fatalf("DeleteTenant: %v", err)
+		}
+		if _, err := store.GetTenant(ctx, "acme"); !errors.Is(err, storage.ErrTenantNotFound) {
+			t.Errorf("expected ErrTenantNotFound after delete, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if err := store.CreateTenant(ctx, &storage.TenantRecord{Name: "dup"}); err != nil {
+			t.Fatalf("CreateTenant: %v", err)
+		}
+		if err := store.CreateTenant(ctx, &storage.TenantRecord{Name: "dup"}); !errors.Is(err, storage.ErrTenantExists) {
+			t.Errorf("expected ErrTenantExists, got %v", err)
+		}
+		if _, err := store.GetTenant(ctx, "missing"); !errors.Is(err, storage.ErrTenantNotFound) {
+			t.Errorf("GetTenant: expected ErrTenantNotFound, got %v", err)
+		}
+		if err := store.UpdateTenant(ctx, &storage.TenantRecord{Name: "missing"}); !errors.Is(err, storage.ErrTenantNotFound) {
+			t.Errorf("UpdateTenant: expected ErrTenantNotFound, got %v", err)
+		}
+		if err := store.DeleteTenant(ctx, "missing"); !errors.Is(err, storage.ErrTenantNotFound) {
+			t.Errorf("DeleteTenant: expected ErrTenantNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ListOrderedByName", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		for _, name := range []string{"zeta", "alpha", "mid"} {
+			if err := store.CreateTenant(ctx, &storage.TenantRecord{Name: name}); err != nil {
+				t.Fatalf("CreateTenant(%s): %v", name, err)
+			}
+		}
+		tenants, err := store.ListTenants(ctx)
+		if err != nil {
+			t.Fatalf("ListTenants: %v", err)
+		}
+		var names []string
+		for _, tn := range tenants {
+			names = append(names, tn.Name)
+		}
+		want := []string{"alpha", "mid", "zeta"}
+		if len(names) != len(want) {
+			t.Fatalf("expected %v, got %v", want, names)
+		}
+		for i := range want {
+			if names[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, names)
+			}
+		}
+	})
+
+	t.Run("ContextTenantIsImmutable", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		if err := store.CreateContext(ctx, &storage.ContextRecord{Name: ".acme-orders", Tenant: "acme"}); err != nil {
+			t.Fatalf("CreateContext: %v", err)
+		}
+		update := &storage.ContextRecord{Name: ".acme-orders", Description: "orders", Tenant: "other"}
+		if err := store.UpdateContext(ctx, update); err != nil {
+			t.Fatalf("UpdateContext: %v", err)
+		}
+		if update.Tenant != "acme" {
+			t.Errorf("UpdateContext should report the stored tenant, got %q", update.Tenant)
+		}
+		got, err := store.GetContext(ctx, ".acme-orders")
+		if err != nil {
+			t.Fatalf("GetContext: %v", err)
+		}
+		if got.Tenant != "acme" {
+			t.Errorf("expected tenant acme, got %q", got.Tenant)
+		}
+		if got.Description != "orders" {
+			t.Errorf("expected description to be updated, got %q", got.Description)
+		}
+	})
+
+	t.Run("UserTenant", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		user := &storage.UserRecord{Username: "acme-dev", PasswordHash: "hash", Role: "developer", Enabled: true, Tenant: "acme"}
+		if err := store.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		got, err := store.GetUserByUsername(ctx, "acme-dev")
+		if err != nil {
+			t.Fatalf("GetUserByUsername: %v", err)
+		}
+		if got.Tenant != "acme" {
+			t.Errorf("expected tenant acme, got %q", got.Tenant)
+		}
+		got, err = store.GetUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if got.Tenant != "acme" {
+			t.Errorf("expected tenant acme by ID, got %q", got.Tenant)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package conformance
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/axonops/axonops-schema-registry/internal/storage"
+)
+
+// RunSchemaUsageTests tests the schema usage analytics operations.
+func RunSchemaUsageTests(t *testing.T, newStore StoreFactory) {
+	t.Helper()
+
+	t.Run("AddAndListSchemaUsage", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+		ctx := context.Background()
+
+		// Usage is only recorded for schemas that exist, so create the
+		// contexts the usage refers to.
+		for _, registryCtx := range []string{".", ".team-a"} {
+			rec := &storage.SchemaRecord{
+				Subject:     "orders",
+				SchemaType:  storage.SchemaTypeAvro,
+				Schema:      `{"type":"string"}`,
+				Fingerprint: "fp-usage" + registryCtx,
+			}
+			if err := store.CreateSchema(ctx, registryCtx, rec); err != nil {
+				t.Fatalf("CreateSchema in %s: %v", registryCtx, err)
+			}
+		}
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		weekAgo := today.AddDate(0, 0, -7)
+		batch := []*storage.SchemaUsageRecord{
+			{Context: ".", SchemaID: 1, Day: today, Count: 3},
+			{Context: ".", SchemaID: 1, Subject: "orders", Version: 1, Day: today.Add(5 * time.Hour), Count: 2},
+			{Context: ".", SchemaID: 1, Day: weekAgo, Count: 10},
+			{Context: ".team-a", SchemaID: 1, Day: today, Count: 1},
+		}
+		if err := store.AddSchemaUsage(ctx, batch); err != nil {
+			t.Fatalf("AddSchemaUsage: %v", err)
+		}
+		// Counts for the same bucket accumulate.
+		if err := store.AddSchemaUsage(ctx, batch[:1]); err != nil {
+			t.Fatalf("AddSchemaUsage: %v", err)
+		}
+
+		all, err := store.ListSchemaUsage(ctx, time.Time{})
+		if err != nil {
+			t.Fatalf("ListSchemaUsage: %v", err)
+		}
+		if len(all) != 4 {
+			t.Fatalf("expected 4 usage buckets, got %d", len(all))
+		}
+
+		recent, err := store.ListSchemaUsage(ctx, today.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("ListSchemaUsage: %v", err)
+		}
+		sort.Slice(recent, func(i, j int) bool {
+			if recent[i].Context != recent[j].Context {
+				return recent[i].Context < recent[j].Context
+			}
+			return recent[i].Subject < recent[j].Subject
+		})
+		want := []storage.SchemaUsageRecord{
+			{Context: ".", SchemaID: 1, Day: today, Count: 6},
+			{Context: ".", SchemaID: 1, Subject: "orders", Version: 1, Day: today, Count: 2},
+			{Context: ".team-a", SchemaID: 1, Day: today, Count: 1},
+		}
+		if len(recent) != len(want) {
+			t.Fatalf("expected %d recent buckets, got %d", len(want), len(recent))
+		}
+		for i, w := range want {
+			got := *recent[i]
+			if !got.Day.Equal(w.Day) {
+				t.Errorf("bucket %d: day %v, want %v", i, got.Day, w.Day)
+			}
+			got.Day = w.Day
+			if got != w {
+				t.Errorf("bucket %d = %+v, want %+v", i, got, w)
+			}
+		}
+	})
+
+	t.Run("ListSchemaUsageEmpty", func(t *testing.T) {
+		store := newStore()
+		defer store.Close()
+
+		usage, err := store.ListSchemaUsage(context.Background(), time.Time{})
+		if err != nil {
+			t.Fatalf("ListSchemaUsage: %v", err)
+		}
+		if len(usage) != 0 {
+			t.Errorf("expected no usage, got %d buckets", len(usage))
+		}
+	})
+}